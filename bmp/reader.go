@@ -9,15 +9,55 @@ package bmp // import "golang.org/x/image/bmp"
 
 import (
 	"errors"
+	"fmt"
 	"image"
 	"image/color"
 	"io"
+	"math"
+	"runtime"
+	"sync"
 )
 
 // ErrUnsupported means that the input BMP image uses a valid but unsupported
 // feature.
 var ErrUnsupported = errors.New("bmp: unsupported BMP image")
 
+// A FormatError reports that the input is not a valid BMP image.
+type FormatError string
+
+func (e FormatError) Error() string {
+	return "bmp: invalid format: " + string(e)
+}
+
+// A LimitError reports that the input BMP image exceeds one of the limits
+// passed to DecodeWithLimits.
+type LimitError struct {
+	// Field is the header field that exceeded its limit: "width", "height"
+	// or "palette length".
+	Field string
+	// Value is the value given in the BMP header.
+	Value int
+	// Limit is the caller-provided limit.
+	Limit int
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("bmp: %s %d exceeds limit %d", e.Field, e.Value, e.Limit)
+}
+
+// limits holds the maximum width, height and palette length to accept when
+// decoding. A zero value means no limit.
+type limits struct {
+	maxW, maxH, maxPaletteLen int
+}
+
+func (l *limits) check(field string, value, limit int) error {
+	if limit > 0 && value > limit {
+		return &LimitError{Field: field, Value: value, Limit: limit}
+	}
+	return nil
+}
+
 func readUint16(b []byte) uint16 {
 	return uint16(b[0]) | uint16(b[1])<<8
 }
@@ -26,86 +66,222 @@ func readUint32(b []byte) uint32 {
 	return uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16 | uint32(b[3])<<24
 }
 
+// rowBufferSize is the size of the buffers that decodeRows reads rows into.
+// It reads as many whole rows as fit, or one row if a row is larger.
+const rowBufferSize = 64 << 10
+
+// parallelRowBufferSize is like rowBufferSize, when converting rows
+// concurrently. Larger blocks amortize the cost of handing them over to the
+// goroutines.
+const parallelRowBufferSize = 1 << 20
+
+// decodeRows reads the rows of an image from r, each of which is rowLen
+// bytes, including its padding to a multiple of 4 bytes, and calls convert
+// with each row and its y coordinate. If topDown is false, the rows are read
+// bottom-up.
+//
+// Reading several rows at once saves many small reads from r for narrow
+// images. If workers is more than one, up to that many goroutines call
+// convert concurrently, each for a different block of rows, while r is read
+// sequentially.
+func decodeRows(r io.Reader, c image.Config, topDown bool, rowLen, workers int, convert func(y int, row []byte) error) error {
+	bufSize := rowBufferSize
+	if workers > 1 {
+		bufSize = parallelRowBufferSize
+	}
+	n := bufSize / rowLen
+	if n < 1 {
+		n = 1
+	} else if n > c.Height {
+		n = c.Height
+	}
+	y, yDelta := c.Height-1, -1
+	if topDown {
+		y, yDelta = 0, +1
+	}
+	convertBlock := func(y int, rows []byte) error {
+		for ; len(rows) > 0; rows = rows[rowLen:] {
+			if err := convert(y, rows[:rowLen]); err != nil {
+				return err
+			}
+			y += yDelta
+		}
+		return nil
+	}
+
+	if workers <= 1 || n == c.Height {
+		b := make([]byte, n*rowLen)
+		for remaining := c.Height; remaining > 0; remaining -= n {
+			if n > remaining {
+				n = remaining
+			}
+			rows := b[:n*rowLen]
+			if _, err := io.ReadFull(r, rows); err != nil {
+				return err
+			}
+			if err := convertBlock(y, rows); err != nil {
+				return err
+			}
+			y += n * yDelta
+		}
+		return nil
+	}
+
+	type block struct {
+		y    int
+		rows []byte
+	}
+	var (
+		blocks = make(chan block)
+		// free holds the buffers that are not being read into or
+		// converted. One more buffer than workers lets r be read while
+		// every worker is busy.
+		free       = make(chan []byte, workers+1)
+		wg         sync.WaitGroup
+		mu         sync.Mutex
+		convertErr error
+	)
+	for i := 0; i < workers+1; i++ {
+		free <- nil
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for b := range blocks {
+				if err := convertBlock(b.y, b.rows); err != nil {
+					mu.Lock()
+					if convertErr == nil {
+						convertErr = err
+					}
+					mu.Unlock()
+				}
+				free <- b.rows[:cap(b.rows)]
+			}
+		}()
+	}
+	var readErr error
+	for remaining := c.Height; remaining > 0; remaining -= n {
+		mu.Lock()
+		failed := convertErr != nil
+		mu.Unlock()
+		if failed {
+			break
+		}
+		if n > remaining {
+			n = remaining
+		}
+		buf := <-free
+		if buf == nil {
+			buf = make([]byte, n*rowLen)
+		}
+		rows := buf[:n*rowLen]
+		if _, err := io.ReadFull(r, rows); err != nil {
+			readErr = err
+			break
+		}
+		blocks <- block{y, rows}
+		y += n * yDelta
+	}
+	close(blocks)
+	wg.Wait()
+	// An error converting a row comes before any error reading a later row,
+	// as it would when converting the rows sequentially.
+	if convertErr != nil {
+		return convertErr
+	}
+	return readErr
+}
+
 // decodePaletted reads an 8 bit-per-pixel BMP image from r.
 // If topDown is false, the image rows will be read bottom-up.
-func decodePaletted(r io.Reader, c image.Config, topDown bool) (image.Image, error) {
+func decodePaletted(r io.Reader, c image.Config, topDown bool, workers int) (image.Image, error) {
 	paletted := image.NewPaletted(image.Rect(0, 0, c.Width, c.Height), c.ColorModel.(color.Palette))
 	if c.Width == 0 || c.Height == 0 {
 		return paletted, nil
 	}
-	var tmp [4]byte
-	y0, y1, yDelta := c.Height-1, -1, -1
-	if topDown {
-		y0, y1, yDelta = 0, c.Height, +1
-	}
-	for y := y0; y != y1; y += yDelta {
-		p := paletted.Pix[y*paletted.Stride : y*paletted.Stride+c.Width]
-		if _, err := io.ReadFull(r, p); err != nil {
-			return nil, err
-		}
-		// Each row is 4-byte aligned.
-		if c.Width%4 != 0 {
-			_, err := io.ReadFull(r, tmp[:4-c.Width%4])
-			if err != nil {
-				return nil, err
+	paletteLen := len(paletted.Palette)
+	// Each row is 4-byte aligned.
+	rowLen := (c.Width + 3) &^ 3
+	err := decodeRows(r, c, topDown, rowLen, workers, func(y int, row []byte) error {
+		p := row[:c.Width]
+		// An index beyond the palette would make the image's At method
+		// panic.
+		if paletteLen < 256 {
+			for _, i := range p {
+				if int(i) >= paletteLen {
+					return FormatError("invalid color index")
+				}
 			}
 		}
+		copy(paletted.Pix[y*paletted.Stride:], p)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 	return paletted, nil
 }
 
 // decodeRGB reads a 24 bit-per-pixel BMP image from r.
 // If topDown is false, the image rows will be read bottom-up.
-func decodeRGB(r io.Reader, c image.Config, topDown bool) (image.Image, error) {
+func decodeRGB(r io.Reader, c image.Config, topDown bool, workers int) (image.Image, error) {
 	rgba := image.NewRGBA(image.Rect(0, 0, c.Width, c.Height))
 	if c.Width == 0 || c.Height == 0 {
 		return rgba, nil
 	}
 	// There are 3 bytes per pixel, and each row is 4-byte aligned.
-	b := make([]byte, (3*c.Width+3)&^3)
-	y0, y1, yDelta := c.Height-1, -1, -1
-	if topDown {
-		y0, y1, yDelta = 0, c.Height, +1
-	}
-	for y := y0; y != y1; y += yDelta {
-		if _, err := io.ReadFull(r, b); err != nil {
-			return nil, err
-		}
-		p := rgba.Pix[y*rgba.Stride : y*rgba.Stride+c.Width*4]
-		for i, j := 0, 0; i < len(p); i, j = i+4, j+3 {
-			// BMP images are stored in BGR order rather than RGB order.
-			p[i+0] = b[j+2]
-			p[i+1] = b[j+1]
-			p[i+2] = b[j+0]
-			p[i+3] = 0xFF
-		}
+	rowLen := (3*c.Width + 3) &^ 3
+	err := decodeRows(r, c, topDown, rowLen, workers, func(y int, row []byte) error {
+		bgrToRGBA(rgba.Pix[y*rgba.Stride:y*rgba.Stride+c.Width*4], row)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 	return rgba, nil
 }
 
+// bgrToRGBA converts the BGR pixels in src, as stored in 24 bit-per-pixel BMP
+// images, to the opaque RGBA pixels in dst. The loop is unrolled to convert
+// four pixels at a time.
+func bgrToRGBA(dst, src []byte) {
+	for len(dst) >= 16 && len(src) >= 12 {
+		d, s := dst[:16:16], src[:12:12]
+		d[0], d[1], d[2], d[3] = s[2], s[1], s[0], 0xff
+		d[4], d[5], d[6], d[7] = s[5], s[4], s[3], 0xff
+		d[8], d[9], d[10], d[11] = s[8], s[7], s[6], 0xff
+		d[12], d[13], d[14], d[15] = s[11], s[10], s[9], 0xff
+		dst, src = dst[16:], src[12:]
+	}
+	for len(dst) >= 4 && len(src) >= 3 {
+		d, s := dst[:4:4], src[:3:3]
+		d[0], d[1], d[2], d[3] = s[2], s[1], s[0], 0xff
+		dst, src = dst[4:], src[3:]
+	}
+}
+
 // decodeNRGBA reads a 32 bit-per-pixel BMP image from r.
 // If topDown is false, the image rows will be read bottom-up.
-func decodeNRGBA(r io.Reader, c image.Config, topDown, allowAlpha bool) (image.Image, error) {
+func decodeNRGBA(r io.Reader, c image.Config, topDown, allowAlpha bool, workers int) (image.Image, error) {
 	rgba := image.NewNRGBA(image.Rect(0, 0, c.Width, c.Height))
 	if c.Width == 0 || c.Height == 0 {
 		return rgba, nil
 	}
-	y0, y1, yDelta := c.Height-1, -1, -1
-	if topDown {
-		y0, y1, yDelta = 0, c.Height, +1
-	}
-	for y := y0; y != y1; y += yDelta {
+	err := decodeRows(r, c, topDown, c.Width*4, workers, func(y int, row []byte) error {
 		p := rgba.Pix[y*rgba.Stride : y*rgba.Stride+c.Width*4]
-		if _, err := io.ReadFull(r, p); err != nil {
-			return nil, err
-		}
 		for i := 0; i < len(p); i += 4 {
 			// BMP images are stored in BGRA order rather than RGBA order.
-			p[i+0], p[i+2] = p[i+2], p[i+0]
+			s := row[i : i+4 : i+4]
+			p[i+0], p[i+1], p[i+2], p[i+3] = s[2], s[1], s[0], s[3]
 			if !allowAlpha {
 				p[i+3] = 0xFF
 			}
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 	return rgba, nil
 }
@@ -113,17 +289,73 @@ func decodeNRGBA(r io.Reader, c image.Config, topDown, allowAlpha bool) (image.I
 // Decode reads a BMP image from r and returns it as an image.Image.
 // Limitation: The file must be 8, 24 or 32 bits per pixel.
 func Decode(r io.Reader) (image.Image, error) {
-	c, bpp, topDown, allowAlpha, err := decodeConfig(r)
+	return decode(r, limits{}, 0, 1)
+}
+
+// DecodeWithLimits is like Decode but returns a *LimitError, before
+// allocating any pixel or palette memory, if the image is wider than maxW,
+// taller than maxH or has more than maxPaletteLen palette entries. A zero
+// limit means no limit.
+//
+// It is intended for decoding untrusted input, such as user uploads, where
+// the header alone could otherwise cause a very large allocation.
+func DecodeWithLimits(r io.Reader, maxW, maxH, maxPaletteLen int) (image.Image, error) {
+	if maxW < 0 || maxH < 0 || maxPaletteLen < 0 {
+		return nil, errors.New("bmp: negative limit")
+	}
+	return decode(r, limits{maxW: maxW, maxH: maxH, maxPaletteLen: maxPaletteLen}, 0, 1)
+}
+
+// DecodeOptions are the decoding parameters.
+type DecodeOptions struct {
+	// ZeroHeight is the image height to use if the BMP header's height is
+	// zero, as written by some streaming encoders that do not know the
+	// number of rows in advance and instead provide it out-of-band. As for
+	// the BMP header, a negative value means that the image rows are stored
+	// top-down. It is ignored if the BMP header's height is non-zero.
+	ZeroHeight int
+
+	// Concurrency is the number of goroutines that convert the image's rows
+	// to pixels, while the rows are read from the io.Reader in turn. That
+	// speeds up decoding very large images, of hundreds of megabytes, whose
+	// rows are independent. Zero or one means to convert the rows on the
+	// calling goroutine, and a negative value means runtime.GOMAXPROCS(0)
+	// goroutines.
+	Concurrency int
+}
+
+// DecodeWithOptions is like Decode but with decoding parameters. A nil opts
+// means to use the default parameters.
+func DecodeWithOptions(r io.Reader, opts *DecodeOptions) (image.Image, error) {
+	zeroHeight, workers := 0, 1
+	if opts != nil {
+		zeroHeight = opts.ZeroHeight
+		workers = opts.Concurrency
+		if workers < 0 {
+			workers = runtime.GOMAXPROCS(0)
+		}
+	}
+	if zeroHeight < -math.MaxInt32 || zeroHeight > math.MaxInt32 {
+		return nil, errors.New("bmp: ZeroHeight out of range")
+	}
+	return decode(r, limits{}, zeroHeight, workers)
+}
+
+// decode decodes a BMP image. If the header's height is zero, zeroHeight is
+// used instead, as per DecodeOptions.ZeroHeight. Up to workers goroutines
+// convert the rows, as per DecodeOptions.Concurrency.
+func decode(r io.Reader, l limits, zeroHeight, workers int) (image.Image, error) {
+	c, bpp, topDown, allowAlpha, err := decodeConfig(r, l, zeroHeight)
 	if err != nil {
 		return nil, err
 	}
 	switch bpp {
 	case 8:
-		return decodePaletted(r, c, topDown)
+		return decodePaletted(r, c, topDown, workers)
 	case 24:
-		return decodeRGB(r, c, topDown)
+		return decodeRGB(r, c, topDown, workers)
 	case 32:
-		return decodeNRGBA(r, c, topDown, allowAlpha)
+		return decodeNRGBA(r, c, topDown, allowAlpha, workers)
 	}
 	panic("unreachable")
 }
@@ -132,11 +364,11 @@ func Decode(r io.Reader) (image.Image, error) {
 // decoding the entire image.
 // Limitation: The file must be 8, 24 or 32 bits per pixel.
 func DecodeConfig(r io.Reader) (image.Config, error) {
-	config, _, _, _, err := decodeConfig(r)
+	config, _, _, _, err := decodeConfig(r, limits{}, 0)
 	return config, err
 }
 
-func decodeConfig(r io.Reader) (config image.Config, bitsPerPixel int, topDown bool, allowAlpha bool, err error) {
+func decodeConfig(r io.Reader, l limits, zeroHeight int) (config image.Config, bitsPerPixel int, topDown bool, allowAlpha bool, err error) {
 	// We only support those BMP images with one of the following DIB headers:
 	// - BITMAPINFOHEADER (40 bytes)
 	// - BITMAPV4HEADER (108 bytes)
@@ -155,7 +387,7 @@ func decodeConfig(r io.Reader) (config image.Config, bitsPerPixel int, topDown b
 		return image.Config{}, 0, false, false, err
 	}
 	if string(b[:2]) != "BM" {
-		return image.Config{}, 0, false, false, errors.New("bmp: invalid format")
+		return image.Config{}, 0, false, false, FormatError("missing BM signature")
 	}
 	offset := readUint32(b[10:14])
 	infoLen := readUint32(b[14:18])
@@ -168,13 +400,32 @@ func decodeConfig(r io.Reader) (config image.Config, bitsPerPixel int, topDown b
 		}
 		return image.Config{}, 0, false, false, err
 	}
-	width := int(int32(readUint32(b[18:22])))
-	height := int(int32(readUint32(b[22:26])))
+	width := int64(int32(readUint32(b[18:22])))
+	height := int64(int32(readUint32(b[22:26])))
+	if height == 0 {
+		height = int64(zeroHeight)
+	}
 	if height < 0 {
 		height, topDown = -height, true
 	}
-	if width < 0 || height < 0 {
-		return image.Config{}, 0, false, false, ErrUnsupported
+	if width < 0 {
+		return image.Config{}, 0, false, false, FormatError("negative width")
+	}
+	// A height of -(1<<31) negates to 1<<31, which does not fit in an int32
+	// and so cannot be a valid top-down height.
+	if height > math.MaxInt32 {
+		return image.Config{}, 0, false, false, FormatError("height out of range")
+	}
+	if err := l.check("width", int(width), l.maxW); err != nil {
+		return image.Config{}, 0, false, false, err
+	}
+	if err := l.check("height", int(height), l.maxH); err != nil {
+		return image.Config{}, 0, false, false, err
+	}
+	// Reject images whose pixel buffer size would overflow an int, as
+	// image.NewRGBA et al would otherwise panic.
+	if width != 0 && height > math.MaxInt/4/width {
+		return image.Config{}, 0, false, false, FormatError("image too large")
 	}
 	// We only support 1 plane and 8, 24 or 32 bits per pixel and no
 	// compression.
@@ -186,7 +437,10 @@ func decodeConfig(r io.Reader) (config image.Config, bitsPerPixel int, topDown b
 		readUint32(b[62:66]) == 0xff && readUint32(b[66:70]) == 0xff000000 {
 		compression = 0
 	}
-	if planes != 1 || compression != 0 {
+	if planes != 1 {
+		return image.Config{}, 0, false, false, FormatError("number of planes is not 1")
+	}
+	if compression != 0 {
 		return image.Config{}, 0, false, false, ErrUnsupported
 	}
 	switch bpp {
@@ -196,7 +450,11 @@ func decodeConfig(r io.Reader) (config image.Config, bitsPerPixel int, topDown b
 		if colorUsed == 0 {
 			colorUsed = 256
 		} else if colorUsed > 256 {
-			return image.Config{}, 0, false, false, ErrUnsupported
+			// An 8 bit-per-pixel image cannot index more than 256 colors.
+			return image.Config{}, 0, false, false, FormatError("too many palette colors for bit depth")
+		}
+		if err := l.check("palette length", int(colorUsed), l.maxPaletteLen); err != nil {
+			return image.Config{}, 0, false, false, err
 		}
 
 		if offset != fileHeaderLen+infoLen+colorUsed*4 {
@@ -212,12 +470,12 @@ func decodeConfig(r io.Reader) (config image.Config, bitsPerPixel int, topDown b
 			// Every 4th byte is padding.
 			pcm[i] = color.RGBA{b[4*i+2], b[4*i+1], b[4*i+0], 0xFF}
 		}
-		return image.Config{ColorModel: pcm, Width: width, Height: height}, 8, topDown, false, nil
+		return image.Config{ColorModel: pcm, Width: int(width), Height: int(height)}, 8, topDown, false, nil
 	case 24:
 		if offset != fileHeaderLen+infoLen {
 			return image.Config{}, 0, false, false, ErrUnsupported
 		}
-		return image.Config{ColorModel: color.RGBAModel, Width: width, Height: height}, 24, topDown, false, nil
+		return image.Config{ColorModel: color.RGBAModel, Width: int(width), Height: int(height)}, 24, topDown, false, nil
 	case 32:
 		if offset != fileHeaderLen+infoLen {
 			return image.Config{}, 0, false, false, ErrUnsupported
@@ -243,7 +501,7 @@ func decodeConfig(r io.Reader) (config image.Config, bitsPerPixel int, topDown b
 		// infoHeaderLen) condition distinguishes BITMAPINFOHEADER (40 bytes)
 		// vs later (larger) headers.
 		allowAlpha = infoLen > infoHeaderLen
-		return image.Config{ColorModel: color.RGBAModel, Width: width, Height: height}, 32, topDown, allowAlpha, nil
+		return image.Config{ColorModel: color.RGBAModel, Width: int(width), Height: int(height)}, 32, topDown, allowAlpha, nil
 	}
 	return image.Config{}, 0, false, false, ErrUnsupported
 }
@@ -6,8 +6,11 @@ package bmp
 
 import (
 	"bytes"
+	"encoding/binary"
+	"errors"
 	"fmt"
 	"image"
+	"image/color"
 	"io"
 	"os"
 	"testing"
@@ -88,3 +91,261 @@ func TestEOF(t *testing.T) {
 		t.Errorf("Error should be io.ErrUnexpectedEOF on nil but got %v", err)
 	}
 }
+
+// bmpHeader returns a BITMAPINFOHEADER-based BMP file header, followed by
+// numColors palette entries.
+func bmpHeader(width, height int32, planes, bpp uint16, numColors uint32) []byte {
+	b := make([]byte, 54+4*numColors)
+	copy(b, "BM")
+	binary.LittleEndian.PutUint32(b[10:], uint32(len(b)))
+	binary.LittleEndian.PutUint32(b[14:], 40)
+	binary.LittleEndian.PutUint32(b[18:], uint32(width))
+	binary.LittleEndian.PutUint32(b[22:], uint32(height))
+	binary.LittleEndian.PutUint16(b[26:], planes)
+	binary.LittleEndian.PutUint16(b[28:], bpp)
+	binary.LittleEndian.PutUint32(b[46:], numColors)
+	return b
+}
+
+func TestDecodeWithLimits(t *testing.T) {
+	testCases := []struct {
+		desc                   string
+		data                   []byte
+		maxW, maxH, maxPalette int
+		wantField              string
+	}{
+		{"within limits", bmpHeader(4, 2, 1, 24, 0), 4, 2, 0, ""},
+		{"no limits", bmpHeader(4, 2, 1, 24, 0), 0, 0, 0, ""},
+		{"too wide", bmpHeader(5, 2, 1, 24, 0), 4, 2, 0, "width"},
+		{"too tall", bmpHeader(4, 3, 1, 24, 0), 4, 2, 0, "height"},
+		{"too tall top-down", bmpHeader(4, -3, 1, 24, 0), 4, 2, 0, "height"},
+		{"palette within limit", bmpHeader(4, 2, 1, 8, 16), 0, 0, 16, ""},
+		{"palette too long", bmpHeader(4, 2, 1, 8, 17), 0, 0, 16, "palette length"},
+		{"implicit palette too long", bmpHeader(4, 2, 1, 8, 0), 0, 0, 16, "palette length"},
+	}
+
+	for _, tc := range testCases {
+		data := tc.data
+		if tc.wantField == "" {
+			// Append enough zero-valued pixel data for 24 bits per pixel.
+			data = append(data, make([]byte, 4*3*4)...)
+		}
+		_, err := DecodeWithLimits(bytes.NewReader(data), tc.maxW, tc.maxH, tc.maxPalette)
+		if tc.wantField == "" {
+			if err != nil {
+				t.Errorf("%s: got %v, want nil", tc.desc, err)
+			}
+			continue
+		}
+		var limitErr *LimitError
+		if !errors.As(err, &limitErr) {
+			t.Errorf("%s: got %v, want a *LimitError", tc.desc, err)
+			continue
+		}
+		if limitErr.Field != tc.wantField {
+			t.Errorf("%s: got field %q, want %q", tc.desc, limitErr.Field, tc.wantField)
+		}
+	}
+}
+
+func TestDecodeInvalidHeader(t *testing.T) {
+	badPixel := append(bmpHeader(1, 1, 1, 8, 2), 2, 0, 0, 0)
+	testCases := []struct {
+		desc string
+		data []byte
+	}{
+		{"negative width", bmpHeader(-1, 1, 1, 24, 0)},
+		{"minimum int32 height", bmpHeader(1, -1<<31, 1, 24, 0)},
+		{"two planes", bmpHeader(1, 1, 2, 24, 0)},
+		{"too many colors", bmpHeader(1, 1, 1, 8, 257)},
+		{"color index out of range", badPixel},
+	}
+
+	for _, tc := range testCases {
+		_, err := Decode(bytes.NewReader(tc.data))
+		var formatErr FormatError
+		if !errors.As(err, &formatErr) {
+			t.Errorf("%s: got %v, want a FormatError", tc.desc, err)
+		}
+	}
+}
+
+func TestDecodeZeroHeight(t *testing.T) {
+	src, err := openImage("yellow_rose-small.bmp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := src.Bounds().Dy()
+
+	for _, topDown := range []bool{false, true} {
+		var buf bytes.Buffer
+		if err := EncodeWithOptions(&buf, src, &EncodeOptions{TopDown: topDown}); err != nil {
+			t.Fatal(err)
+		}
+		data := buf.Bytes()
+		binary.LittleEndian.PutUint32(data[22:26], 0)
+
+		// Without a ZeroHeight, the image is empty.
+		m, err := Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Errorf("topDown=%t: Decode: %v", topDown, err)
+		} else if got := m.Bounds().Dy(); got != 0 {
+			t.Errorf("topDown=%t: Decode height: got %d, want 0", topDown, got)
+		}
+
+		zeroHeight := h
+		if topDown {
+			zeroHeight = -h
+		}
+		m, err = DecodeWithOptions(bytes.NewReader(data), &DecodeOptions{ZeroHeight: zeroHeight})
+		if err != nil {
+			t.Errorf("topDown=%t: DecodeWithOptions: %v", topDown, err)
+			continue
+		}
+		if err := compare(src, m); err != nil {
+			t.Errorf("topDown=%t: %v", topDown, err)
+		}
+	}
+
+	// ZeroHeight is ignored if the header's height is non-zero.
+	data := append(bmpHeader(1, 1, 1, 24, 0), 0, 0, 0, 0)
+	m, err := DecodeWithOptions(bytes.NewReader(data), &DecodeOptions{ZeroHeight: 100})
+	if err != nil {
+		t.Fatalf("DecodeWithOptions: %v", err)
+	}
+	if got := m.Bounds().Dy(); got != 1 {
+		t.Errorf("non-zero header height: got %d, want 1", got)
+	}
+}
+
+// TestDecodeRGBRows tests decoding 24 bit-per-pixel images whose rows are
+// read in several batches, including a final partial batch.
+func TestDecodeRGBRows(t *testing.T) {
+	for _, size := range []image.Point{{5, 10000}, {1, 3}, {30000, 2}} {
+		src := benchmarkImage(size.X, size.Y)
+		for _, topDown := range []bool{false, true} {
+			var buf bytes.Buffer
+			if err := EncodeWithOptions(&buf, src, &EncodeOptions{TopDown: topDown}); err != nil {
+				t.Fatal(err)
+			}
+			data := buf.Bytes()
+			m, err := Decode(bytes.NewReader(data))
+			if err != nil {
+				t.Errorf("%v, topDown=%t: %v", size, topDown, err)
+				continue
+			}
+			if err := compare(src, m); err != nil {
+				t.Errorf("%v, topDown=%t: %v", size, topDown, err)
+			}
+
+			// Truncated pixel data is an error.
+			if _, err := Decode(bytes.NewReader(data[:len(data)-1])); err != io.ErrUnexpectedEOF {
+				t.Errorf("%v, topDown=%t: truncated: got %v, want %v", size, topDown, err, io.ErrUnexpectedEOF)
+			}
+		}
+	}
+}
+
+// TestDecodeConcurrency tests that converting rows concurrently decodes the
+// same images, and reports the same errors, as converting them sequentially.
+func TestDecodeConcurrency(t *testing.T) {
+	const w, h = 601, 2000
+	paletted := image.NewPaletted(image.Rect(0, 0, w, h), color.Palette{
+		color.Black, color.White, color.RGBA{0xff, 0, 0, 0xff},
+	})
+	nrgba := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for i := range paletted.Pix {
+		paletted.Pix[i] = uint8(i % 3)
+	}
+	for i := range nrgba.Pix {
+		nrgba.Pix[i] = uint8(i * 7)
+	}
+	for _, src := range []image.Image{paletted, benchmarkImage(w, h), nrgba} {
+		for _, topDown := range []bool{false, true} {
+			var buf bytes.Buffer
+			if err := EncodeWithOptions(&buf, src, &EncodeOptions{TopDown: topDown}); err != nil {
+				t.Fatal(err)
+			}
+			data := buf.Bytes()
+			desc := fmt.Sprintf("%T, topDown=%t", src, topDown)
+			want, err := Decode(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("%s: Decode: %v", desc, err)
+			}
+			for _, concurrency := range []int{-1, 2, 5} {
+				m, err := DecodeWithOptions(bytes.NewReader(data), &DecodeOptions{Concurrency: concurrency})
+				if err != nil {
+					t.Errorf("%s, concurrency=%d: %v", desc, concurrency, err)
+					continue
+				}
+				if err := compare(want, m); err != nil {
+					t.Errorf("%s, concurrency=%d: %v", desc, concurrency, err)
+				}
+				_, err = DecodeWithOptions(bytes.NewReader(data[:len(data)-1]), &DecodeOptions{Concurrency: concurrency})
+				if err != io.ErrUnexpectedEOF {
+					t.Errorf("%s, concurrency=%d: truncated: got %v, want %v", desc, concurrency, err, io.ErrUnexpectedEOF)
+				}
+			}
+		}
+	}
+
+	// An invalid color index, in the middle of the image, is reported.
+	data := append(bmpHeader(w, h, 1, 8, 3), make([]byte, (w+3)&^3*h)...)
+	data[len(data)/2] = 3
+	_, err := DecodeWithOptions(bytes.NewReader(data), &DecodeOptions{Concurrency: 4})
+	var formatErr FormatError
+	if !errors.As(err, &formatErr) {
+		t.Errorf("invalid color index: got %v, want a FormatError", err)
+	}
+}
+
+// benchmarkDecode benchmarks decoding the BMP encoding of m.
+func benchmarkDecode(b *testing.B, m image.Image, topDown bool) {
+	var buf bytes.Buffer
+	if err := EncodeWithOptions(&buf, m, &EncodeOptions{TopDown: topDown}); err != nil {
+		b.Fatal(err)
+	}
+	data := buf.Bytes()
+	s := m.Bounds().Size()
+	b.SetBytes(int64(s.X * s.Y * 4))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Decode(bytes.NewReader(data)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// benchmarkImage returns an opaque image, which encodes as 24 bits per pixel,
+// of the given size.
+func benchmarkImage(width, height int) image.Image {
+	m := image.NewRGBA(image.Rect(0, 0, width, height))
+	for i := range m.Pix {
+		m.Pix[i] = uint8(i * 7)
+		if i%4 == 3 {
+			m.Pix[i] = 0xff
+		}
+	}
+	return m
+}
+
+func BenchmarkDecodeRGB(b *testing.B) {
+	m, err := openImage("video-001.bmp")
+	if err != nil {
+		b.Fatal(err)
+	}
+	benchmarkDecode(b, m, false)
+}
+
+func BenchmarkDecodeRGBLarge(b *testing.B) {
+	benchmarkDecode(b, benchmarkImage(1921, 1080), false)
+}
+
+func BenchmarkDecodeRGBLargeTopDown(b *testing.B) {
+	benchmarkDecode(b, benchmarkImage(1921, 1080), true)
+}
+
+func BenchmarkDecodeRGBNarrow(b *testing.B) {
+	benchmarkDecode(b, benchmarkImage(3, 100000), false)
+}
@@ -8,9 +8,14 @@ import (
 	"encoding/binary"
 	"errors"
 	"image"
+	"image/color"
 	"io"
 )
 
+// ErrLossy means that EncodeOptions.Lossless is set but the image cannot be
+// encoded without loss.
+var ErrLossy = errors.New("bmp: image cannot be encoded without loss")
+
 type header struct {
 	sigBM           [2]byte
 	fileSize        uint32
@@ -29,12 +34,23 @@ type header struct {
 	colorImportant  uint32
 }
 
-func encodePaletted(w io.Writer, pix []uint8, dx, dy, stride, step int) error {
+// rowOrder returns the first row to write, the row after the last row to
+// write and the difference between consecutive rows, for an image with dy
+// rows. If topDown is false, the image rows will be written bottom-up.
+func rowOrder(dy int, topDown bool) (y0, y1, yDelta int) {
+	if topDown {
+		return 0, dy, +1
+	}
+	return dy - 1, -1, -1
+}
+
+func encodePaletted(w io.Writer, pix []uint8, dx, dy, stride, step int, topDown bool) error {
+	y0, y1, yDelta := rowOrder(dy, topDown)
 	var padding []byte
 	if dx < step {
 		padding = make([]byte, step-dx)
 	}
-	for y := dy - 1; y >= 0; y-- {
+	for y := y0; y != y1; y += yDelta {
 		min := y*stride + 0
 		max := y*stride + dx
 		if _, err := w.Write(pix[min:max]); err != nil {
@@ -49,10 +65,11 @@ func encodePaletted(w io.Writer, pix []uint8, dx, dy, stride, step int) error {
 	return nil
 }
 
-func encodeRGBA(w io.Writer, pix []uint8, dx, dy, stride, step int, opaque bool) error {
+func encodeRGBA(w io.Writer, pix []uint8, dx, dy, stride, step int, opaque bool, topDown bool) error {
+	y0, y1, yDelta := rowOrder(dy, topDown)
 	buf := make([]byte, step)
 	if opaque {
-		for y := dy - 1; y >= 0; y-- {
+		for y := y0; y != y1; y += yDelta {
 			min := y*stride + 0
 			max := y*stride + dx*4
 			off := 0
@@ -67,7 +84,7 @@ func encodeRGBA(w io.Writer, pix []uint8, dx, dy, stride, step int, opaque bool)
 			}
 		}
 	} else {
-		for y := dy - 1; y >= 0; y-- {
+		for y := y0; y != y1; y += yDelta {
 			min := y*stride + 0
 			max := y*stride + dx*4
 			off := 0
@@ -102,10 +119,11 @@ func encodeRGBA(w io.Writer, pix []uint8, dx, dy, stride, step int, opaque bool)
 	return nil
 }
 
-func encodeNRGBA(w io.Writer, pix []uint8, dx, dy, stride, step int, opaque bool) error {
+func encodeNRGBA(w io.Writer, pix []uint8, dx, dy, stride, step int, opaque bool, topDown bool) error {
+	y0, y1, yDelta := rowOrder(dy, topDown)
 	buf := make([]byte, step)
 	if opaque {
-		for y := dy - 1; y >= 0; y-- {
+		for y := y0; y != y1; y += yDelta {
 			min := y*stride + 0
 			max := y*stride + dx*4
 			off := 0
@@ -120,7 +138,7 @@ func encodeNRGBA(w io.Writer, pix []uint8, dx, dy, stride, step int, opaque bool
 			}
 		}
 	} else {
-		for y := dy - 1; y >= 0; y-- {
+		for y := y0; y != y1; y += yDelta {
 			min := y*stride + 0
 			max := y*stride + dx*4
 			off := 0
@@ -139,13 +157,112 @@ func encodeNRGBA(w io.Writer, pix []uint8, dx, dy, stride, step int, opaque bool
 	return nil
 }
 
-func encode(w io.Writer, m image.Image, step int) error {
+// to8 returns the 8-bit value nearest to the 16-bit value v.
+func to8(v uint32) uint8 {
+	return uint8((v*0xff + 0x7fff) / 0xffff)
+}
+
+func encodeGray16(w io.Writer, pix []uint8, dx, dy, stride, step int, topDown bool) error {
+	y0, y1, yDelta := rowOrder(dy, topDown)
+	buf := make([]byte, step)
+	for y := y0; y != y1; y += yDelta {
+		min := y*stride + 0
+		max := y*stride + dx*2
+		off := 0
+		for i := min; i < max; i += 2 {
+			buf[off] = to8(uint32(pix[i+0])<<8 | uint32(pix[i+1]))
+			off++
+		}
+		if _, err := w.Write(buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodeRGBA64 encodes the pixels of an *image.RGBA64, if premultiplied is
+// true, or an *image.NRGBA64, rounding each sample to 8 bits.
+func encodeRGBA64(w io.Writer, pix []uint8, dx, dy, stride, step int, premultiplied, opaque bool, topDown bool) error {
+	y0, y1, yDelta := rowOrder(dy, topDown)
+	buf := make([]byte, step)
+	for y := y0; y != y1; y += yDelta {
+		min := y*stride + 0
+		max := y*stride + dx*8
+		off := 0
+		for i := min; i < max; i += 8 {
+			r := uint32(pix[i+0])<<8 | uint32(pix[i+1])
+			g := uint32(pix[i+2])<<8 | uint32(pix[i+3])
+			b := uint32(pix[i+4])<<8 | uint32(pix[i+5])
+			if opaque {
+				buf[off+2] = to8(r)
+				buf[off+1] = to8(g)
+				buf[off+0] = to8(b)
+				off += 3
+				continue
+			}
+			a := uint32(pix[i+6])<<8 | uint32(pix[i+7])
+			if premultiplied && a != 0 && a != 0xffff {
+				r = r * 0xffff / a
+				g = g * 0xffff / a
+				b = b * 0xffff / a
+			}
+			buf[off+2] = to8(r)
+			buf[off+1] = to8(g)
+			buf[off+0] = to8(b)
+			buf[off+3] = to8(a)
+			off += 4
+		}
+		if _, err := w.Write(buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func encodeCMYK(w io.Writer, pix []uint8, dx, dy, stride, step int, topDown bool) error {
+	y0, y1, yDelta := rowOrder(dy, topDown)
+	buf := make([]byte, step)
+	for y := y0; y != y1; y += yDelta {
+		min := y*stride + 0
+		max := y*stride + dx*4
+		off := 0
+		for i := min; i < max; i += 4 {
+			r, g, b := color.CMYKToRGB(pix[i+0], pix[i+1], pix[i+2], pix[i+3])
+			buf[off+2] = r
+			buf[off+1] = g
+			buf[off+0] = b
+			off += 3
+		}
+		if _, err := w.Write(buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fits8 reports whether all of the samples of the first dx pixels of each of
+// the dy rows of pix, with 2 bytes per sample and n samples per pixel, can be
+// represented with 8 bits.
+func fits8(pix []uint8, dx, dy, stride, n int) bool {
+	for y := 0; y < dy; y++ {
+		row := pix[y*stride : y*stride+dx*n*2]
+		for i := 0; i < len(row); i += 2 {
+			if row[i] != row[i+1] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func encode(w io.Writer, m image.Image, step int, topDown bool) error {
 	b := m.Bounds()
 	buf := make([]byte, step)
-	for y := b.Max.Y - 1; y >= b.Min.Y; y-- {
+	y0, y1, yDelta := rowOrder(b.Dy(), topDown)
+	for y := y0; y != y1; y += yDelta {
 		off := 0
 		for x := b.Min.X; x < b.Max.X; x++ {
-			r, g, b, _ := m.At(x, y).RGBA()
+			r, g, b, _ := m.At(x, b.Min.Y+y).RGBA()
 			buf[off+2] = byte(r >> 8)
 			buf[off+1] = byte(g >> 8)
 			buf[off+0] = byte(b >> 8)
@@ -158,19 +275,62 @@ func encode(w io.Writer, m image.Image, step int) error {
 	return nil
 }
 
+// EncodeOptions are the encoding parameters.
+type EncodeOptions struct {
+	// TopDown means to store the image rows top-down, recorded in the file
+	// as a negative height, instead of the usual bottom-up order. Some
+	// software, such as video pipelines, requires top-down images.
+	TopDown bool
+
+	// Lossless means to return ErrLossy, instead of converting the image, if
+	// the image cannot be encoded exactly. Encoding converts an *image.CMYK
+	// to RGB using the naive conversion of color.CMYKToRGB, and rounds the
+	// samples of an *image.Gray16, *image.RGBA64 or *image.NRGBA64 to 8
+	// bits. Lossless rejects the former, and the latter when any of their
+	// samples are not multiples of 0x101.
+	Lossless bool
+}
+
 // Encode writes the image m to w in BMP format.
 func Encode(w io.Writer, m image.Image) error {
+	return EncodeWithOptions(w, m, nil)
+}
+
+// EncodeWithOptions is like Encode but with encoding parameters. A nil opts
+// means to use the default parameters.
+func EncodeWithOptions(w io.Writer, m image.Image, opts *EncodeOptions) error {
 	d := m.Bounds().Size()
 	if d.X < 0 || d.Y < 0 {
 		return errors.New("bmp: negative bounds")
 	}
+	topDown := opts != nil && opts.TopDown
+	if opts != nil && opts.Lossless {
+		lossy := false
+		switch m := m.(type) {
+		case *image.CMYK:
+			lossy = true
+		case *image.Gray16:
+			lossy = !fits8(m.Pix, d.X, d.Y, m.Stride, 1)
+		case *image.RGBA64:
+			lossy = !fits8(m.Pix, d.X, d.Y, m.Stride, 4)
+		case *image.NRGBA64:
+			lossy = !fits8(m.Pix, d.X, d.Y, m.Stride, 4)
+		}
+		if lossy {
+			return ErrLossy
+		}
+	}
+	height := uint32(d.Y)
+	if topDown {
+		height = uint32(-int32(d.Y))
+	}
 	h := &header{
 		sigBM:         [2]byte{'B', 'M'},
 		fileSize:      14 + 40,
 		pixOffset:     14 + 40,
 		dibHeaderSize: 40,
 		width:         uint32(d.X),
-		height:        uint32(d.Y),
+		height:        height,
 		colorPlane:    1,
 	}
 
@@ -178,7 +338,7 @@ func Encode(w io.Writer, m image.Image) error {
 	var palette []byte
 	var opaque bool
 	switch m := m.(type) {
-	case *image.Gray:
+	case *image.Gray, *image.Gray16:
 		step = (d.X + 3) &^ 3
 		palette = make([]byte, 1024)
 		for i := 0; i < 256; i++ {
@@ -228,6 +388,28 @@ func Encode(w io.Writer, m image.Image) error {
 		}
 		h.imageSize = uint32(d.Y * step)
 		h.fileSize += h.imageSize
+	case *image.RGBA64:
+		opaque = m.Opaque()
+		if opaque {
+			step = (3*d.X + 3) &^ 3
+			h.bpp = 24
+		} else {
+			step = 4 * d.X
+			h.bpp = 32
+		}
+		h.imageSize = uint32(d.Y * step)
+		h.fileSize += h.imageSize
+	case *image.NRGBA64:
+		opaque = m.Opaque()
+		if opaque {
+			step = (3*d.X + 3) &^ 3
+			h.bpp = 24
+		} else {
+			step = 4 * d.X
+			h.bpp = 32
+		}
+		h.imageSize = uint32(d.Y * step)
+		h.fileSize += h.imageSize
 	default:
 		step = (3*d.X + 3) &^ 3
 		h.imageSize = uint32(d.Y * step)
@@ -250,13 +432,21 @@ func Encode(w io.Writer, m image.Image) error {
 
 	switch m := m.(type) {
 	case *image.Gray:
-		return encodePaletted(w, m.Pix, d.X, d.Y, m.Stride, step)
+		return encodePaletted(w, m.Pix, d.X, d.Y, m.Stride, step, topDown)
 	case *image.Paletted:
-		return encodePaletted(w, m.Pix, d.X, d.Y, m.Stride, step)
+		return encodePaletted(w, m.Pix, d.X, d.Y, m.Stride, step, topDown)
 	case *image.RGBA:
-		return encodeRGBA(w, m.Pix, d.X, d.Y, m.Stride, step, opaque)
+		return encodeRGBA(w, m.Pix, d.X, d.Y, m.Stride, step, opaque, topDown)
 	case *image.NRGBA:
-		return encodeNRGBA(w, m.Pix, d.X, d.Y, m.Stride, step, opaque)
+		return encodeNRGBA(w, m.Pix, d.X, d.Y, m.Stride, step, opaque, topDown)
+	case *image.Gray16:
+		return encodeGray16(w, m.Pix, d.X, d.Y, m.Stride, step, topDown)
+	case *image.RGBA64:
+		return encodeRGBA64(w, m.Pix, d.X, d.Y, m.Stride, step, true, opaque, topDown)
+	case *image.NRGBA64:
+		return encodeRGBA64(w, m.Pix, d.X, d.Y, m.Stride, step, false, opaque, topDown)
+	case *image.CMYK:
+		return encodeCMYK(w, m.Pix, d.X, d.Y, m.Stride, step, topDown)
 	}
-	return encode(w, m, step)
+	return encode(w, m, step, topDown)
 }
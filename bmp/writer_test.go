@@ -6,8 +6,11 @@ package bmp
 
 import (
 	"bytes"
+	"encoding/binary"
 	"fmt"
 	"image"
+	"image/color"
+	"image/color/palette"
 	"image/draw"
 	"io/ioutil"
 	"os"
@@ -95,6 +98,143 @@ func TestEncode(t *testing.T) {
 	}
 }
 
+func TestEncodeTopDown(t *testing.T) {
+	src, err := openImage("yellow_rose-small.bmp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := src.Bounds()
+	gray := image.NewGray(b)
+	draw.Draw(gray, b, src, b.Min, draw.Src)
+	paletted := image.NewPaletted(b, palette.Plan9)
+	draw.Draw(paletted, b, src, b.Min, draw.Src)
+	nrgba := image.NewNRGBA(b)
+	draw.Draw(nrgba, b, src, b.Min, draw.Src)
+	for i := 3; i < len(nrgba.Pix); i += 4 {
+		nrgba.Pix[i] = uint8(i)
+	}
+	rgba64 := image.NewRGBA64(b)
+	draw.Draw(rgba64, b, src, b.Min, draw.Src)
+	nrgba64 := image.NewNRGBA64(b)
+	draw.Draw(nrgba64, b, nrgba, b.Min, draw.Src)
+	gray16 := image.NewGray16(b)
+	draw.Draw(gray16, b, src, b.Min, draw.Src)
+	cmyk := image.NewCMYK(b)
+	draw.Draw(cmyk, b, src, b.Min, draw.Src)
+
+	testCases := []image.Image{
+		gray,
+		paletted,
+		convertToRGBA(src),
+		convertToRGBA(nrgba),
+		convertToNRGBA(src),
+		nrgba,
+		rgba64,
+		nrgba64,
+		gray16,
+		cmyk,
+	}
+
+	for _, m := range testCases {
+		var bottomUp, topDown bytes.Buffer
+		if err := Encode(&bottomUp, m); err != nil {
+			t.Errorf("%T: Encode: %v", m, err)
+			continue
+		}
+		if err := EncodeWithOptions(&topDown, m, &EncodeOptions{TopDown: true}); err != nil {
+			t.Errorf("%T: EncodeWithOptions: %v", m, err)
+			continue
+		}
+		if got, want := int32(binary.LittleEndian.Uint32(topDown.Bytes()[22:26])), -int32(b.Dy()); got != want {
+			t.Errorf("%T: header height: got %d, want %d", m, got, want)
+		}
+		if topDown.Len() != bottomUp.Len() {
+			t.Errorf("%T: lengths differ: top-down %d, bottom-up %d", m, topDown.Len(), bottomUp.Len())
+			continue
+		}
+
+		want, err := Decode(&bottomUp)
+		if err != nil {
+			t.Errorf("%T: Decode bottom-up: %v", m, err)
+			continue
+		}
+		got, err := Decode(&topDown)
+		if err != nil {
+			t.Errorf("%T: Decode top-down: %v", m, err)
+			continue
+		}
+		if err := compare(want, got); err != nil {
+			t.Errorf("%T: %v", m, err)
+		}
+	}
+}
+
+func TestEncodeWide(t *testing.T) {
+	r := image.Rect(0, 0, 3, 1)
+	gray16 := image.NewGray16(r)
+	gray16.SetGray16(0, 0, color.Gray16{0x0000})
+	gray16.SetGray16(1, 0, color.Gray16{0x807f})
+	gray16.SetGray16(2, 0, color.Gray16{0xffff})
+	rgba64 := image.NewRGBA64(r)
+	rgba64.SetRGBA64(0, 0, color.RGBA64{0x1234, 0x5678, 0x9abc, 0xffff})
+	rgba64.SetRGBA64(1, 0, color.RGBA64{0x4000, 0x2000, 0x0000, 0x8000})
+	nrgba64 := image.NewNRGBA64(r)
+	nrgba64.SetNRGBA64(0, 0, color.NRGBA64{0x1212, 0x3434, 0x5656, 0xffff})
+	nrgba64.SetNRGBA64(1, 0, color.NRGBA64{0x8080, 0x4040, 0x0000, 0x8080})
+	cmyk := image.NewCMYK(r)
+	cmyk.SetCMYK(0, 0, color.CMYK{0x00, 0x80, 0xff, 0x20})
+	cmyk.SetCMYK(1, 0, color.CMYK{0x10, 0x20, 0x30, 0x00})
+
+	// The decoder ignores the alpha channel of the encoded images, so the
+	// wanted colors are opaque.
+	testCases := []struct {
+		m         image.Image
+		want      []color.NRGBA
+		wantLossy bool
+	}{{
+		gray16,
+		[]color.NRGBA{{0x00, 0x00, 0x00, 0xff}, {0x80, 0x80, 0x80, 0xff}, {0xff, 0xff, 0xff, 0xff}},
+		true,
+	}, {
+		rgba64,
+		[]color.NRGBA{{0x12, 0x56, 0x9a, 0xff}, {0x7f, 0x40, 0x00, 0xff}, {0x00, 0x00, 0x00, 0xff}},
+		true,
+	}, {
+		nrgba64,
+		[]color.NRGBA{{0x12, 0x34, 0x56, 0xff}, {0x80, 0x40, 0x00, 0xff}, {0x00, 0x00, 0x00, 0xff}},
+		false,
+	}, {
+		cmyk,
+		[]color.NRGBA{{0xdf, 0x6f, 0x00, 0xff}, {0xef, 0xdf, 0xcf, 0xff}, {0xff, 0xff, 0xff, 0xff}},
+		true,
+	}}
+
+	for _, tc := range testCases {
+		var buf bytes.Buffer
+		if err := Encode(&buf, tc.m); err != nil {
+			t.Errorf("%T: Encode: %v", tc.m, err)
+			continue
+		}
+		m, err := Decode(&buf)
+		if err != nil {
+			t.Errorf("%T: Decode: %v", tc.m, err)
+			continue
+		}
+		for x, want := range tc.want {
+			if got := color.NRGBAModel.Convert(m.At(x, 0)); got != want {
+				t.Errorf("%T: pixel %d: got %v, want %v", tc.m, x, got, want)
+			}
+		}
+
+		err = EncodeWithOptions(ioutil.Discard, tc.m, &EncodeOptions{Lossless: true})
+		if tc.wantLossy && err != ErrLossy {
+			t.Errorf("%T: Lossless: got %v, want %v", tc.m, err, ErrLossy)
+		} else if !tc.wantLossy && err != nil {
+			t.Errorf("%T: Lossless: %v", tc.m, err)
+		}
+	}
+}
+
 // TestZeroWidthVeryLargeHeight tests that encoding and decoding a degenerate
 // image with zero width but over one billion pixels in height is faster than
 // naively calling an io.Reader or io.Writer method once per row.
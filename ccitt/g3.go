@@ -0,0 +1,170 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ccitt
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"io"
+)
+
+var errShortDigiFAXHeader = errors.New("ccitt: short DigiFAX header")
+
+// digiFAXMagic starts the 64 byte header of a DigiFAX file, as written by
+// Ghostscript's dfaxlow and dfaxhigh devices.
+const (
+	digiFAXMagic     = "\x00PC Research, Inc\x00\x00\x00\x00\x00\x00"
+	digiFAXHeaderLen = 64
+)
+
+// faxWidths are the standard Group 3 fax page widths, in pixels: ISO A4, ISO
+// B4 and ISO A3 at 8 pixels per millimeter.
+var faxWidths = [...]int{1728, 2048, 2432}
+
+func init() {
+	// Only DigiFAX files are registered. Raw files start with just an EOL,
+	// which is too short a magic number to tell them from other formats, so
+	// they must be decoded by calling Decode explicitly.
+	image.RegisterFormat("g3", digiFAXMagic, Decode, DecodeConfig)
+}
+
+// decodeG3File decodes a Group 3 fax file, returning one bit per pixel in the
+// same layout as NewReader's output.
+func decodeG3File(r io.Reader) (pix []byte, width int, height int, err error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if bytes.HasPrefix(data, []byte(digiFAXMagic)) {
+		if len(data) < digiFAXHeaderLen {
+			return nil, 0, 0, errShortDigiFAXHeader
+		}
+		data = data[digiFAXHeaderLen:]
+	}
+
+	// Neither the bit order, the coding nor the width is recorded in the
+	// file. Try each possibility in turn: with the wrong one, decoding fails
+	// almost immediately, as rows don't end with an EOL. The first row is
+	// always one-dimensionally coded, so measuring it gives the width, but
+	// fall back to the standard widths in case it is malformed.
+	firstErr := error(nil)
+	for _, order := range [...]Order{LSB, MSB} {
+		for _, sf := range [...]SubFormat{Group3, Group3TwoD} {
+			widths := faxWidths[:]
+			if w := measureG3Width(data, order, sf); w > 0 {
+				widths = append([]int{w}, widths...)
+			}
+			for i, w := range widths {
+				if (i > 0) && (w == widths[0]) {
+					continue
+				}
+				z := &reader{
+					br:            bitReader{r: bytes.NewReader(data), order: order},
+					subFormat:     sf,
+					fillBits:      true,
+					width:         w,
+					rowsRemaining: AutoDetectHeight,
+				}
+				pix, err := io.ReadAll(z)
+				if err == nil {
+					return pix, w, len(pix) / ((w + 7) / 8), nil
+				}
+				if firstErr == nil {
+					firstErr = err
+				}
+			}
+		}
+	}
+	return nil, 0, 0, firstErr
+}
+
+// measureG3Width returns the width of the first row of Group 3 data, as the
+// sum of its run lengths, or 0 if that row cannot be decoded.
+func measureG3Width(data []byte, order Order, sf SubFormat) int {
+	br := bitReader{r: bytes.NewReader(data), order: order}
+	if decodeFillBitsEOL(&br) != nil {
+		return 0
+	}
+	if sf == Group3TwoD {
+		if tag, err := br.nextBit(); (err != nil) || (tag == 0) {
+			return 0
+		}
+	}
+	total, white := 0, true
+	for {
+		table := blackDecodeTable[:]
+		if white {
+			table = whiteDecodeTable[:]
+		}
+		n, err := decode(&br, table)
+		if err != nil {
+			// The run codes stop at the EOL code (or fill bits) that ends the
+			// row. Unlike a run code, it cannot be decoded.
+			if (err != errInvalidCode) || (total > maxWidth) {
+				return 0
+			}
+			return total
+		}
+		total += int(n)
+		if total > maxWidth {
+			return 0
+		}
+		// Anything 0x3F or below is a terminal code, ending a run.
+		if n <= 0x3F {
+			white = !white
+		}
+	}
+}
+
+// Decode reads a Group 3 fax file from r and returns it as an *image.Gray,
+// with black pixels 0x00 and white pixels 0xFF.
+//
+// The file holds one-dimensional (Modified Huffman) or two-dimensional
+// (Modified READ) Group 3 data, optionally preceded by a DigiFAX header. Raw
+// files like this are written by fax software such as mgetty and HylaFAX. The
+// bit order, the coding and the page width are detected automatically. The
+// width is measured from the first row, or failing that, is one of the
+// standard fax widths. Fill bits before each EOL are allowed. The data must
+// start with an EOL and end with an RTC (Return To Control) of 6 consecutive
+// EOL's.
+//
+// Only files with a DigiFAX header are registered with the image package, so
+// that image.Decode can decode them. Raw files must be decoded by calling
+// Decode.
+func Decode(r io.Reader) (image.Image, error) {
+	pix, width, height, err := decodeG3File(r)
+	if err != nil {
+		return nil, err
+	}
+	m := image.NewGray(image.Rect(0, 0, width, height))
+	rowBytes := (width + 7) / 8
+	for y := 0; y < height; y++ {
+		src := pix[y*rowBytes : (y+1)*rowBytes]
+		dst := m.Pix[y*m.Stride : y*m.Stride+width]
+		for x := range dst {
+			if src[x>>3]&(0x80>>uint(x&7)) != 0 {
+				dst[x] = 0xFF
+			}
+		}
+	}
+	return m, nil
+}
+
+// DecodeConfig returns the color model and dimensions of a Group 3 fax file
+// without returning the image. As the height is not recorded in the file, it
+// still has to decode all of the image data.
+func DecodeConfig(r io.Reader) (image.Config, error) {
+	_, width, height, err := decodeG3File(r)
+	if err != nil {
+		return image.Config{}, err
+	}
+	return image.Config{
+		ColorModel: color.GrayModel,
+		Width:      width,
+		Height:     height,
+	}, nil
+}
@@ -0,0 +1,220 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ccitt
+
+import (
+	"bytes"
+	"image"
+	"io"
+	"strings"
+	"testing"
+)
+
+// encodeG3File encodes src, a black and white image, as Group 3 data,
+// starting with an EOL and ending with an RTC. If fill is true, 0 bits are
+// inserted so that each EOL ends on a byte boundary.
+//
+// If twoD is true, it encodes Group3TwoD data, where every other row is coded
+// two-dimensionally, using only the horizontal mode.
+func encodeG3File(t *testing.T, src *image.Gray, order Order, fill, twoD bool) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := &bitWriter{w: &buf, order: order}
+	write := func(bs bitString) {
+		if bs.nBits == 0 {
+			return
+		}
+		if err := w.writeCode(bs); err != nil {
+			t.Fatalf("writeCode: %v", err)
+		}
+	}
+	eol := func(tag uint32) {
+		if fill {
+			write(bitString{0, (12 - w.nBits%8) % 8})
+		}
+		write(bitString{0x001, 12})
+		if twoD {
+			write(bitString{tag, 1})
+		}
+	}
+	run := func(n int, white bool) {
+		table2, table3 := blackEncodeTable2[:], blackEncodeTable3[:]
+		if white {
+			table2, table3 = whiteEncodeTable2[:], whiteEncodeTable3[:]
+		}
+		for ; n >= 2560; n -= 2560 {
+			write(table3[2560/64-1])
+		}
+		if n >= 64 {
+			write(table3[n/64-1])
+		}
+		write(table2[n%64])
+	}
+
+	b := src.Bounds()
+	eol(1)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		// runs are the row's alternating white and black run lengths.
+		white, runs := true, []int{0}
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if (src.GrayAt(x, y).Y != 0) != white {
+				white = !white
+				runs = append(runs, 0)
+			}
+			runs[len(runs)-1]++
+		}
+		if twoD && ((y-b.Min.Y)%2 == 1) {
+			// Code each pair of runs in the horizontal mode.
+			if len(runs)%2 == 1 {
+				runs = append(runs, 0)
+			}
+			for i := 0; i < len(runs); i += 2 {
+				write(modeEncodeTable[modeH])
+				run(runs[i], true)
+				run(runs[i+1], false)
+			}
+		} else {
+			for i, n := range runs {
+				run(n, i%2 == 0)
+			}
+		}
+		// The next row is coded two-dimensionally if it is odd.
+		eol(uint32(y-b.Min.Y) & 1)
+	}
+	for i := 0; i < 5; i++ {
+		eol(1)
+	}
+	if err := w.close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestDecodeG3File(t *testing.T) {
+	gopher, err := decodePNG("testdata/bw-gopher.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	digiFAXHeader := make([]byte, digiFAXHeaderLen)
+	copy(digiFAXHeader, digiFAXMagic)
+
+	for _, tc := range []struct {
+		name   string
+		width  int
+		order  Order
+		fill   bool
+		twoD   bool
+		header []byte
+	}{
+		{"lsb", 1728, LSB, true, false, nil},
+		{"msb", 1728, MSB, true, false, nil},
+		{"lsb-b4", 2048, LSB, true, false, nil},
+		{"msb-a3", 2432, MSB, true, false, nil},
+		{"digifax-lsb", 1728, LSB, true, false, digiFAXHeader},
+		{"digifax-no-fill", 1728, MSB, false, false, digiFAXHeader},
+		{"msb-non-standard-width", 200, MSB, true, false, nil},
+		{"lsb-2d", 1728, LSB, true, true, nil},
+		{"digifax-2d-no-fill", 2048, MSB, false, true, digiFAXHeader},
+		{"lsb-2d-non-standard-width", 160, LSB, true, true, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			want := image.NewGray(image.Rect(0, 0, tc.width, 60))
+			for i := range want.Pix {
+				want.Pix[i] = 0xFF
+			}
+			b := gopher.Bounds()
+			for y := b.Min.Y; y < b.Max.Y; y++ {
+				for x := b.Min.X; x < b.Max.X; x++ {
+					want.Set(x-b.Min.X, y-b.Min.Y, gopher.At(x, y))
+				}
+			}
+			data := append(tc.header, encodeG3File(t, want, tc.order, tc.fill, tc.twoD)...)
+
+			cfg, err := DecodeConfig(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("DecodeConfig: %v", err)
+			}
+			if cfg.Width != tc.width || cfg.Height != 60 {
+				t.Errorf("DecodeConfig: got %dx%d, want %dx%d", cfg.Width, cfg.Height, tc.width, 60)
+			}
+
+			got, err := Decode(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+
+			// Only DigiFAX files are registered with the image package.
+			_, format, err := image.DecodeConfig(bytes.NewReader(data))
+			if tc.header == nil {
+				if err != image.ErrFormat {
+					t.Errorf("image.DecodeConfig: got %q, %v, want %v", format, err, image.ErrFormat)
+				}
+			} else if err != nil || format != "g3" {
+				t.Errorf("image.DecodeConfig: got %q, %v, want %q, nil", format, err, "g3")
+			}
+			compareImages(t, got, want)
+		})
+	}
+}
+
+func TestDecodeG3FileInvalid(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		data string
+	}{
+		{"empty", ""},
+		{"short-digifax-header", digiFAXMagic + "\x00"},
+		{"no-eol", "\xff\xff\xff\xff"},
+		{"no-rtc", "\x00\x80\x00\x80"},
+		{"wrong-width", strings.Repeat("\x00\x80\x1b\x00\x80", 6)},
+	} {
+		if _, err := Decode(strings.NewReader(tc.data)); err == nil {
+			t.Errorf("%s: got nil error, want non-nil", tc.name)
+		}
+	}
+}
+
+func TestReadGroup3TwoD(t *testing.T) {
+	img, err := decodePNG("testdata/bw-gopher.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gopher := img.(*image.Gray)
+	w, h := gopher.Bounds().Dx(), gopher.Bounds().Dy()
+	oneD := encodeG3File(t, gopher, MSB, false, false)
+	twoD := encodeG3File(t, gopher, MSB, false, true)
+
+	want, err := io.ReadAll(NewReader(bytes.NewReader(oneD), MSB, Group3, w, h, nil))
+	if err != nil {
+		t.Fatalf("Group3: %v", err)
+	}
+	for _, tc := range []struct {
+		name   string
+		data   []byte
+		sf     SubFormat
+		height int
+	}{
+		{"2d", twoD, Group3TwoD, h},
+		{"2d-auto-detect-height", twoD, Group3TwoD, AutoDetectHeight},
+		{"2d-auto-detect-sub-format", twoD, AutoDetectSubFormat, h},
+		{"2d-auto-detect-both", twoD, AutoDetectSubFormat, AutoDetectHeight},
+		{"1d-auto-detect-sub-format", oneD, AutoDetectSubFormat, AutoDetectHeight},
+	} {
+		got, err := io.ReadAll(NewReader(bytes.NewReader(tc.data), MSB, tc.sf, w, tc.height, nil))
+		if err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+			continue
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s: got and want differ", tc.name)
+		}
+	}
+
+	if _, err := io.ReadAll(NewReader(strings.NewReader("\xff\xff\xff\xff"), MSB, AutoDetectSubFormat, w, h, nil)); err == nil {
+		t.Errorf("invalid data: got nil error, want non-nil")
+	}
+}
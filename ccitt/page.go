@@ -0,0 +1,118 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ccitt
+
+import (
+	"io"
+)
+
+// PageReader decodes a data stream of consecutive CCITT-formatted pages, as
+// found in fax spool files, where each page ends with the RTC (Return To
+// Control) of 6 consecutive EOL's (for Group3 and Group3TwoD) or the EOFB
+// (End Of Facsimile Block) of 2 consecutive EOL's (for Group4). All of the
+// pages have the same width, the same sub-format and the same options, but
+// each page's height is detected from where it ends.
+//
+// Each page after the first must start on a byte boundary, as it does when
+// each page's data is padded to a whole number of bytes. Those padding bits
+// are ignored.
+//
+// Like an archive/tar.Reader, a PageReader is an io.Reader for the current
+// page, and the NextPage method advances to the next page. Its byte stream is
+// the same as that of NewReader, for that page, passed AutoDetectHeight.
+type PageReader struct {
+	z reader
+
+	// started is whether NextPage has been called.
+	started bool
+
+	// err is a sticky error for the NextPage method.
+	err error
+}
+
+// NewPageReader returns a PageReader that decodes the pages of
+// CCITT-formatted data in r, which all have the given width.
+//
+// For the AutoDetectSubFormat sub-format, the sub-format is detected from the
+// start of the first page, and used for every page.
+func NewPageReader(r io.Reader, order Order, sf SubFormat, width int, opts *Options) *PageReader {
+	p := &PageReader{
+		z: reader{
+			br:            bitReader{r: r, order: order},
+			subFormat:     sf,
+			align:         (opts != nil) && opts.Align,
+			invert:        (opts != nil) && opts.Invert,
+			width:         width,
+			rowsRemaining: AutoDetectHeight,
+
+			// There is no current page until NextPage is called.
+			readErr: io.EOF,
+		},
+	}
+	if width < 0 {
+		p.err = errInvalidBounds
+	} else if width > maxWidth {
+		p.err = errUnsupportedWidth
+	} else if sf == ModifiedHuffman {
+		p.err = errUnsupportedSubFormat
+	}
+	return p
+}
+
+// NextPage advances to the next page. Any unread rows of the current page are
+// decoded and discarded. It returns io.EOF, without advancing, if there are no
+// more pages.
+//
+// NextPage must be called before reading the first page.
+func (p *PageReader) NextPage() error {
+	if p.err != nil {
+		return p.err
+	}
+	z := &p.z
+
+	if !p.started {
+		p.started = true
+		// Detecting the sub-format re-reads the start of the data stream, so
+		// it must happen before looking for the end of that stream below.
+		if z.subFormat == AutoDetectSubFormat {
+			if p.err = z.detectSubFormat(); p.err != nil {
+				return p.err
+			}
+		}
+	} else {
+		var buf [256]byte
+		for z.readErr == nil {
+			z.Read(buf[:])
+		}
+		if z.readErr != io.EOF {
+			p.err = z.readErr
+			return p.err
+		}
+		z.br.alignToByteBoundary()
+	}
+
+	if atEOF, err := z.br.atEOF(); err != nil {
+		p.err = err
+		return p.err
+	} else if atEOF {
+		p.err = io.EOF
+		return p.err
+	}
+
+	*z = reader{
+		br:            z.br,
+		subFormat:     z.subFormat,
+		align:         z.align,
+		invert:        z.invert,
+		width:         z.width,
+		rowsRemaining: AutoDetectHeight,
+	}
+	return nil
+}
+
+// Read reads from the current page. It returns io.EOF at the end of that page.
+func (p *PageReader) Read(b []byte) (int, error) {
+	return p.z.Read(b)
+}
@@ -0,0 +1,160 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ccitt
+
+import (
+	"bytes"
+	"image"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestPageReader(t *testing.T) {
+	img, err := decodePNG("testdata/bw-gopher.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gopher := img.(*image.Gray)
+	w := gopher.Bounds().Dx()
+	// top is a shorter page, so that the pages' heights differ.
+	top := gopher.SubImage(image.Rect(0, 0, w, 20)).(*image.Gray)
+
+	readFile := func(fileName string) []byte {
+		data, err := os.ReadFile(fileName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+	g3, g3Aligned := readFile("testdata/bw-gopher.ccitt_group3"), readFile("testdata/bw-gopher-aligned.ccitt_group3")
+	g4, g4Aligned := readFile("testdata/bw-gopher.ccitt_group4"), readFile("testdata/bw-gopher-aligned.ccitt_group4")
+	g4Inverted := readFile("testdata/bw-gopher-inverted.ccitt_group4")
+
+	for _, tc := range []struct {
+		name  string
+		pages [][]byte
+		sf    SubFormat
+		opts  *Options
+	}{
+		{"group3", [][]byte{g3, g3, g3}, Group3, nil},
+		{"group3-aligned", [][]byte{g3Aligned, g3Aligned}, Group3, &Options{Align: true}},
+		{"group3-encoded", [][]byte{
+			encodeG3File(t, gopher, MSB, false, false),
+			encodeG3File(t, top, MSB, false, false),
+			encodeG3File(t, gopher, MSB, false, false),
+		}, Group3, nil},
+		{"group3-2d", [][]byte{
+			encodeG3File(t, top, MSB, false, true),
+			encodeG3File(t, gopher, MSB, false, true),
+		}, Group3TwoD, nil},
+		{"group4", [][]byte{g4, g4, g4}, Group4, nil},
+		{"group4-single", [][]byte{g4}, Group4, nil},
+		{"group4-aligned", [][]byte{g4Aligned, g4Aligned}, Group4, &Options{Align: true}},
+		{"group4-inverted", [][]byte{g4Inverted, g4Inverted}, Group4, &Options{Invert: true}},
+	} {
+		for _, sf := range []SubFormat{tc.sf, AutoDetectSubFormat} {
+			var all []byte
+			for _, page := range tc.pages {
+				all = append(all, page...)
+			}
+			p := NewPageReader(bytes.NewReader(all), MSB, sf, w, tc.opts)
+			for i, page := range tc.pages {
+				want, err := io.ReadAll(NewReader(bytes.NewReader(page), MSB, tc.sf, w, AutoDetectHeight, tc.opts))
+				if err != nil {
+					t.Fatalf("%s, sf=%d, page %d: NewReader: %v", tc.name, sf, i, err)
+				}
+				if err := p.NextPage(); err != nil {
+					t.Fatalf("%s, sf=%d, page %d: NextPage: %v", tc.name, sf, i, err)
+				}
+				got, err := io.ReadAll(p)
+				if err != nil {
+					t.Fatalf("%s, sf=%d, page %d: ReadAll: %v", tc.name, sf, i, err)
+				}
+				if !bytes.Equal(got, want) {
+					t.Fatalf("%s, sf=%d, page %d: PageReader and NewReader differ", tc.name, sf, i)
+				}
+			}
+			for i := 0; i < 2; i++ {
+				if err := p.NextPage(); err != io.EOF {
+					t.Fatalf("%s, sf=%d: NextPage after the last page: got %v, want %v", tc.name, sf, err, io.EOF)
+				}
+			}
+		}
+	}
+}
+
+func TestPageReaderSkip(t *testing.T) {
+	g4, err := os.ReadFile("testdata/bw-gopher.ccitt_group4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const width, height = 153, 55
+	const rowLen = (width + 7) / 8
+
+	// Read only part of the first page, and none of the second page.
+	p := NewPageReader(bytes.NewReader(bytes.Repeat(g4, 3)), MSB, Group4, width, nil)
+	if err := p.NextPage(); err != nil {
+		t.Fatalf("NextPage #0: %v", err)
+	}
+	if _, err := p.Read(make([]byte, 3*rowLen)); err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if err := p.NextPage(); err != nil {
+		t.Fatalf("NextPage #1: %v", err)
+	}
+	if err := p.NextPage(); err != nil {
+		t.Fatalf("NextPage #2: %v", err)
+	}
+	got, err := io.ReadAll(p)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if len(got) != height*rowLen {
+		t.Fatalf("third page: got %d bytes, want %d", len(got), height*rowLen)
+	}
+	if err := p.NextPage(); err != io.EOF {
+		t.Fatalf("NextPage #3: got %v, want %v", err, io.EOF)
+	}
+}
+
+func TestPageReaderInvalid(t *testing.T) {
+	// There are no pages in an empty data stream.
+	p := NewPageReader(strings.NewReader(""), MSB, Group4, 153, nil)
+	if err := p.NextPage(); err != io.EOF {
+		t.Errorf("empty: got %v, want %v", err, io.EOF)
+	}
+
+	// Reading before the first NextPage call returns io.EOF.
+	p = NewPageReader(strings.NewReader("\x00"), MSB, Group4, 153, nil)
+	if n, err := p.Read(make([]byte, 1)); n != 0 || err != io.EOF {
+		t.Errorf("Read before NextPage: got %d, %v, want 0, %v", n, err, io.EOF)
+	}
+
+	// A page that doesn't end makes the next NextPage call fail.
+	g4, err := os.ReadFile("testdata/bw-gopher-truncated0.ccitt_group4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p = NewPageReader(bytes.NewReader(g4), MSB, Group4, 153, nil)
+	if err := p.NextPage(); err != nil {
+		t.Fatalf("truncated: NextPage #0: %v", err)
+	}
+	if err := p.NextPage(); err == nil || err == io.EOF {
+		t.Errorf("truncated: NextPage #1: got %v, want a decoding error", err)
+	}
+
+	p = NewPageReader(strings.NewReader(""), MSB, Group4, -1, nil)
+	if err := p.NextPage(); err != errInvalidBounds {
+		t.Errorf("negative width: got %v, want %v", err, errInvalidBounds)
+	}
+
+	// ModifiedHuffman data does not mark where each page ends.
+	p = NewPageReader(strings.NewReader("\x00"), MSB, ModifiedHuffman, 153, nil)
+	if err := p.NextPage(); err != errUnsupportedSubFormat {
+		t.Errorf("ModifiedHuffman: got %v, want %v", err, errUnsupportedSubFormat)
+	}
+}
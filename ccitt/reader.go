@@ -4,10 +4,11 @@
 
 //go:generate go run gen.go
 
-// Package ccitt implements a CCITT (fax) image decoder.
+// Package ccitt implements a CCITT (fax) image decoder and encoder.
 package ccitt
 
 import (
+	"bytes"
 	"encoding/binary"
 	"errors"
 	"image"
@@ -17,6 +18,7 @@ import (
 
 var (
 	errIncompleteCode          = errors.New("ccitt: incomplete code")
+	errIncompleteRow           = errors.New("ccitt: incomplete row")
 	errInvalidBounds           = errors.New("ccitt: invalid bounds")
 	errInvalidCode             = errors.New("ccitt: invalid code")
 	errInvalidMode             = errors.New("ccitt: invalid mode")
@@ -27,6 +29,8 @@ var (
 	errUnsupportedMode         = errors.New("ccitt: unsupported mode")
 	errUnsupportedSubFormat    = errors.New("ccitt: unsupported sub-format")
 	errUnsupportedWidth        = errors.New("ccitt: unsupported width")
+	errUnknownSubFormat        = errors.New("ccitt: cannot detect sub-format")
+	errWriterClosed            = errors.New("ccitt: write to a closed writer")
 )
 
 // Order specifies the bit ordering in a CCITT data stream.
@@ -47,6 +51,26 @@ type SubFormat uint32
 const (
 	Group3 SubFormat = iota
 	Group4
+
+	// Group3TwoD is Group 3 with two-dimensional coding. Each row is coded
+	// either like a Group3 row or, relative to the row above, like a Group4
+	// row, as given by a tag bit after each End-of-Line. In TIFF, this is
+	// Group 3 compression with bit 0 of the T4Options field set.
+	Group3TwoD
+
+	// AutoDetectSubFormat means that the sub-format is not known in advance,
+	// as is often the case for raw fax data, and should be inferred from the
+	// start of the data stream. Group3 and Group3TwoD streams start with an
+	// End-of-Line code, and Group4 streams do not. Group3 and Group3TwoD are
+	// told apart by decoding the first few rows both ways.
+	AutoDetectSubFormat
+
+	// ModifiedHuffman is Group3 one-dimensional coding without any EOL codes,
+	// with each row starting on a byte boundary. In TIFF, this is CCITT
+	// Modified Huffman RLE compression (Compression=2). Its data stream does
+	// not mark where it ends, so AutoDetectSubFormat never detects it and a
+	// PageReader does not support it.
+	ModifiedHuffman
 )
 
 // AutoDetectHeight is passed as the height argument to NewReader to indicate
@@ -57,7 +81,10 @@ const AutoDetectHeight = -1
 type Options struct {
 	// Align means that some variable-bit-width codes are byte-aligned.
 	Align bool
-	// Invert means that black is the 1 bit or 0xFF byte, and white is 0.
+	// Invert means that black is the 1 bit or 0xFF byte, and white is 0. By
+	// default, as for a TIFF PhotometricInterpretation of BlackIsZero
+	// (MinIsBlack), black is 0. Invert gives WhiteIsZero (MinIsWhite)
+	// output.
 	Invert bool
 }
 
@@ -155,6 +182,21 @@ func (b *bitReader) alignToByteBoundary() {
 	b.nBits -= n
 }
 
+// atEOF returns whether there are no more bits to read. It does not consume
+// any bits.
+func (b *bitReader) atEOF() (bool, error) {
+	bit, err := b.nextBit()
+	if err == io.EOF {
+		return true, nil
+	} else if err != nil {
+		return false, err
+	}
+	// Unread the bit.
+	b.bits = (b.bits >> 1) | (bit << 63)
+	b.nBits++
+	return false, nil
+}
+
 // nextBitMaxNBits is the maximum possible value of bitReader.nBits after a
 // bitReader.nextBit call, provided that bitReader.nBits was not more than this
 // value before that call.
@@ -260,6 +302,39 @@ func decodeEOL(b *bitReader) error {
 	}
 }
 
+// decodeFillBitsEOL is like decodeEOL but also accepts extra 0 bits before
+// the EOL code.
+func decodeFillBitsEOL(b *bitReader) error {
+	nBitsRead, bitsRead := uint32(0), uint64(0)
+	for {
+		bit, err := b.nextBit()
+		if err != nil {
+			if err == io.EOF {
+				err = errMissingEOL
+			}
+			return err
+		}
+
+		if nBitsRead >= 11 {
+			if bit&1 != 0 {
+				return nil
+			}
+			continue
+		}
+
+		bitsRead |= bit << (63 - nBitsRead)
+		nBitsRead++
+		if bit&1 == 0 {
+			continue
+		}
+
+		// Unread the bits we've read, then return errMissingEOL.
+		b.bits = (b.bits >> nBitsRead) | bitsRead
+		b.nBits += nBitsRead
+		return errMissingEOL
+	}
+}
+
 type reader struct {
 	br        bitReader
 	subFormat SubFormat
@@ -272,7 +347,8 @@ type reader struct {
 	// are decoded. Alternatively, it may be negative if the image height is
 	// not known in advance at the time of the NewReader call.
 	//
-	// When driven through DecodeIntoGray, this field is unused.
+	// When driven through DecodeIntoGray, this field is the image height, and
+	// is only used by detectSubFormat.
 	rowsRemaining int
 
 	// curr and prev hold the current and previous rows. Each element is either
@@ -300,6 +376,15 @@ type reader struct {
 	align  bool
 	invert bool
 
+	// twoD is whether, for the Group3TwoD subFormat, the next row is coded
+	// two-dimensionally, as given by the tag bit after the most recent EOL.
+	twoD bool
+
+	// fillBits is whether an EOL may be preceded by any number of extra 0
+	// bits, such as those that fax software inserts so that each EOL ends on
+	// a byte boundary.
+	fillBits bool
+
 	// atStartOfRow is whether we have just started the row. Some parts of the
 	// spec say to treat this situation as if "wi = -1".
 	atStartOfRow bool
@@ -363,7 +448,18 @@ func (z *reader) Read(p []byte) (int, error) {
 					z.br.alignToByteBoundary()
 				}
 
-				if err := z.decodeEOL(); err == errMissingEOL {
+				// For the ModifiedHuffman subFormat, there is no EOL, and
+				// the image ends with the data stream.
+				if z.subFormat == ModifiedHuffman {
+					z.br.alignToByteBoundary()
+					if atEOF, err := z.br.atEOF(); err != nil {
+						z.readErr = err
+						break
+					} else if atEOF {
+						z.readErr = io.EOF
+						break
+					}
+				} else if err := z.decodeEOL(); err == errMissingEOL {
 					// No-op. It's another row of pixel data.
 				} else if err != nil {
 					z.readErr = err
@@ -421,13 +517,19 @@ func (z *reader) penColor() byte {
 }
 
 func (z *reader) startDecode() error {
+	if z.subFormat == AutoDetectSubFormat {
+		if err := z.detectSubFormat(); err != nil {
+			return err
+		}
+	}
+
 	switch z.subFormat {
-	case Group3:
+	case Group3, Group3TwoD:
 		if err := z.decodeEOL(); err != nil {
 			return err
 		}
 
-	case Group4:
+	case Group4, ModifiedHuffman:
 		// No-op.
 
 	default:
@@ -441,7 +543,7 @@ func (z *reader) startDecode() error {
 func (z *reader) finishDecode(alreadySeenEOL bool) error {
 	numberOfEOLs := 0
 	switch z.subFormat {
-	case Group3:
+	case Group3, Group3TwoD:
 		if z.truncated {
 			return nil
 		}
@@ -469,6 +571,10 @@ func (z *reader) finishDecode(alreadySeenEOL bool) error {
 		}
 		numberOfEOLs = 1
 
+	case ModifiedHuffman:
+		// There is no trailer.
+		return nil
+
 	default:
 		return errUnsupportedSubFormat
 	}
@@ -485,7 +591,28 @@ func (z *reader) finishDecode(alreadySeenEOL bool) error {
 }
 
 func (z *reader) decodeEOL() error {
-	return decodeEOL(&z.br)
+	err := error(nil)
+	if z.fillBits {
+		err = decodeFillBitsEOL(&z.br)
+	} else {
+		err = decodeEOL(&z.br)
+	}
+	if (err != nil) || (z.subFormat != Group3TwoD) {
+		return err
+	}
+
+	// For the Group3TwoD subFormat, each EOL (including those of the RTC) is
+	// followed by a tag bit: 1 for one-dimensional coding of the next row, 0
+	// for two-dimensional coding.
+	tag, err := z.br.nextBit()
+	if err != nil {
+		if err == io.EOF {
+			err = errIncompleteCode
+		}
+		return err
+	}
+	z.twoD = tag == 0
+	return nil
 }
 
 func (z *reader) decodeRow(finalRow bool) error {
@@ -498,11 +625,17 @@ func (z *reader) decodeRow(finalRow bool) error {
 	}
 
 	switch z.subFormat {
-	case Group3:
-		for ; z.wi < len(z.curr); z.atStartOfRow = false {
-			if err := z.decodeRun(); err != nil {
+	case Group3, Group3TwoD:
+		if z.twoD {
+			if err := z.decodeModes(); err != nil {
 				return err
 			}
+		} else {
+			for ; z.wi < len(z.curr); z.atStartOfRow = false {
+				if err := z.decodeRun(); err != nil {
+					return err
+				}
+			}
 		}
 		err := z.decodeEOL()
 		if finalRow && (err == errMissingEOL) {
@@ -512,19 +645,12 @@ func (z *reader) decodeRow(finalRow bool) error {
 		return err
 
 	case Group4:
+		return z.decodeModes()
+
+	case ModifiedHuffman:
+		z.br.alignToByteBoundary()
 		for ; z.wi < len(z.curr); z.atStartOfRow = false {
-			mode, err := decode(&z.br, modeDecodeTable[:])
-			if err != nil {
-				return err
-			}
-			rm := readerMode{}
-			if mode < uint32(len(readerModes)) {
-				rm = readerModes[mode]
-			}
-			if rm.function == nil {
-				return errInvalidMode
-			}
-			if err := rm.function(z, rm.arg); err != nil {
+			if err := z.decodeRun(); err != nil {
 				return err
 			}
 		}
@@ -534,6 +660,88 @@ func (z *reader) decodeRow(finalRow bool) error {
 	return errUnsupportedSubFormat
 }
 
+// decodeModes decodes a two-dimensionally coded row.
+func (z *reader) decodeModes() error {
+	for ; z.wi < len(z.curr); z.atStartOfRow = false {
+		mode, err := decode(&z.br, modeDecodeTable[:])
+		if err != nil {
+			return err
+		}
+		rm := readerMode{}
+		if mode < uint32(len(readerModes)) {
+			rm = readerModes[mode]
+		}
+		if rm.function == nil {
+			return errInvalidMode
+		}
+		if err := rm.function(z, rm.arg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const (
+	// sniffLen is the maximum number of bytes that detectSubFormat reads
+	// ahead.
+	sniffLen = 16 << 10
+	// sniffRows is the maximum number of rows that detectSubFormat decodes
+	// for each candidate sub-format.
+	sniffRows = 16
+)
+
+// detectSubFormat sets z.subFormat, for the AutoDetectSubFormat sub-format,
+// by trying to decode the start of the data stream as each of Group3,
+// Group3TwoD and Group4, and picking the one that decodes the most rows. Ties
+// are broken in that order, as a Group3 stream with a single row is also a
+// valid Group3TwoD stream when that row's first code starts with a 1 bit.
+//
+// It must be called before any bits are read from z.br, and re-reads the
+// bytes that it reads ahead.
+func (z *reader) detectSubFormat() error {
+	prefix := make([]byte, sniffLen)
+	n, err := io.ReadFull(z.br.r, prefix)
+	if (err != nil) && (err != io.EOF) && (err != io.ErrUnexpectedEOF) {
+		return err
+	}
+	prefix = prefix[:n]
+	z.br.r = io.MultiReader(bytes.NewReader(prefix), z.br.r)
+
+	maxRows := sniffRows
+	if (z.rowsRemaining >= 0) && (z.rowsRemaining < maxRows) {
+		maxRows = z.rowsRemaining
+	}
+	best, bestRows := AutoDetectSubFormat, 0
+	for _, sf := range [...]SubFormat{Group3, Group3TwoD, Group4} {
+		trial := reader{
+			br:        bitReader{r: bytes.NewReader(prefix), order: z.br.order},
+			subFormat: sf,
+			width:     z.width,
+			align:     z.align,
+			fillBits:  z.fillBits,
+		}
+		if trial.startDecode() != nil {
+			continue
+		}
+		rows := 0
+		for ; rows < maxRows; rows++ {
+			trial.curr = make([]byte, z.width)
+			if trial.decodeRow(false) != nil {
+				break
+			}
+			trial.curr, trial.prev = nil, trial.curr
+		}
+		if (best == AutoDetectSubFormat) || (rows > bestRows) {
+			best, bestRows = sf, rows
+		}
+	}
+	if best == AutoDetectSubFormat {
+		return errUnknownSubFormat
+	}
+	z.subFormat = best
+	return nil
+}
+
 func (z *reader) decodeRun() error {
 	table := blackDecodeTable[:]
 	if z.penColorIsWhite {
@@ -740,6 +948,8 @@ func DecodeIntoGray(dst *image.Gray, r io.Reader, order Order, sf SubFormat, opt
 		align:     (opts != nil) && opts.Align,
 		invert:    (opts != nil) && opts.Invert,
 		width:     bounds.Dx(),
+
+		rowsRemaining: bounds.Dy(),
 	}
 	if err := z.startDecode(); err != nil {
 		return err
@@ -769,6 +979,61 @@ func DecodeIntoGray(dst *image.Gray, r io.Reader, order Order, sf SubFormat, opt
 	return nil
 }
 
+// DecodeIntoPacked decodes the CCITT-formatted data in r, an image of the
+// given width and height, into dst, at one bit per pixel, most significant bit
+// first, with 1 meaning white and 0 meaning black unless opts.Invert is set.
+// That is the same as the byte stream of NewReader, but with the start of each
+// row stride bytes after the start of the previous row. With a stride of
+// (width+7)/8, dst holds the same packed rows as TIFF and PDF images with
+// one bit per sample, and needs an eighth of the memory of an *image.Gray.
+//
+// The bits that pad each row to a whole byte are 0. Any other bytes between
+// rows are left unchanged. It returns an error if stride is less than
+// (width+7)/8 or if dst is too short to hold height rows.
+func DecodeIntoPacked(dst []byte, stride, width, height int, r io.Reader, order Order, sf SubFormat, opts *Options) error {
+	if (width < 0) || (height < 0) {
+		return errInvalidBounds
+	}
+	if width > maxWidth {
+		return errUnsupportedWidth
+	}
+	rowLen := (width + 7) / 8
+	if (stride < rowLen) || ((height > 0) && (len(dst) < (height-1)*stride+rowLen)) {
+		return errInvalidBounds
+	}
+
+	z := reader{
+		br:        bitReader{r: r, order: order},
+		subFormat: sf,
+		align:     (opts != nil) && opts.Align,
+		invert:    (opts != nil) && opts.Invert,
+		width:     width,
+
+		rowsRemaining: height,
+	}
+	if err := z.startDecode(); err != nil {
+		return err
+	}
+
+	// Decode each row, at 1 byte per pixel, then pack it into dst.
+	curr, prev := make([]byte, width), make([]byte, width)
+	for y := 0; y < height; y++ {
+		z.curr = curr
+		if err := z.decodeRow(y+1 == height); err != nil {
+			return err
+		}
+		row := dst[y*stride : y*stride+rowLen]
+		highBits(row, curr, z.invert)
+		if z.invert {
+			invertBytes(row)
+		}
+		z.prev = curr
+		curr, prev = prev, curr
+	}
+
+	return z.finishDecode(false)
+}
+
 // NewReader returns an io.Reader that decodes the CCITT-formatted data in r.
 // The resultant byte stream is one bit per pixel (MSB first), with 1 meaning
 // white and 0 meaning black. Each row in the result is byte-aligned.
@@ -417,6 +417,7 @@ func TestRead(t *testing.T) {
 		invert := strings.Contains(fileName, "inverted")
 		truncated := strings.Contains(fileName, "truncated")
 		testRead(t, fileName, subFormat, align, invert, truncated)
+		testRead(t, fileName, AutoDetectSubFormat, align, invert, truncated)
 	}
 }
 
@@ -432,6 +433,8 @@ func TestDecodeIntoGray(t *testing.T) {
 		{"testdata/bw-gopher-truncated0.ccitt_group4", Group4, 153, 55},
 		{"testdata/bw-gopher-truncated1.ccitt_group3", Group3, 153, 55},
 		{"testdata/bw-gopher-truncated1.ccitt_group4", Group4, 153, 55},
+		{"testdata/bw-gopher.ccitt_group3", AutoDetectSubFormat, 153, 55},
+		{"testdata/bw-gopher.ccitt_group4", AutoDetectSubFormat, 153, 55},
 	} {
 		t.Run(tt.fileName, func(t *testing.T) {
 			testDecodeIntoGray(t, tt.fileName, MSB, tt.sf, tt.w, tt.h, nil)
@@ -439,6 +442,67 @@ func TestDecodeIntoGray(t *testing.T) {
 	}
 }
 
+func TestDecodeIntoPacked(t *testing.T) {
+	const width, height = 153, 55
+	for _, fileName := range []string{
+		"testdata/bw-gopher.ccitt_group3",
+		"testdata/bw-gopher-inverted.ccitt_group4",
+		"testdata/bw-gopher-truncated0.ccitt_group3",
+	} {
+		sf := Group3
+		if strings.HasSuffix(fileName, "group4") {
+			sf = Group4
+		}
+		for _, invert := range []bool{false, true} {
+			opts := &Options{Invert: invert}
+			data, err := ioutil.ReadFile(filepath.FromSlash(fileName))
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := ioutil.ReadAll(NewReader(bytes.NewReader(data), MSB, sf, width, height, opts))
+			if err != nil {
+				t.Fatalf("%s: ReadAll: %v", fileName, err)
+			}
+
+			// A stride of 23 leaves 3 bytes, which must be unchanged, after
+			// each 20 byte row.
+			for _, stride := range []int{20, 23} {
+				got := bytes.Repeat([]byte{0xA5}, (height-1)*stride+20)
+				if err := DecodeIntoPacked(got, stride, width, height, bytes.NewReader(data), MSB, sf, opts); err != nil {
+					t.Errorf("%s, invert=%t, stride=%d: %v", fileName, invert, stride, err)
+					continue
+				}
+				for y := 0; y < height; y++ {
+					row := got[y*stride:]
+					if !bytes.Equal(row[:20], want[y*20:(y+1)*20]) {
+						t.Errorf("%s, invert=%t, stride=%d: row %d: got %02X, want %02X",
+							fileName, invert, stride, y, row[:20], want[y*20:(y+1)*20])
+						break
+					}
+					if y+1 < height && !bytes.Equal(row[20:stride], bytes.Repeat([]byte{0xA5}, stride-20)) {
+						t.Errorf("%s, invert=%t, stride=%d: row %d: padding changed", fileName, invert, stride, y)
+						break
+					}
+				}
+			}
+		}
+	}
+
+	for _, tc := range []struct {
+		desc                 string
+		n, stride, w, height int
+	}{
+		{"short stride", 20 * 55, 19, 153, 55},
+		{"short dst", 20*55 - 1, 20, 153, 55},
+		{"negative width", 0, 0, -1, 0},
+	} {
+		err := DecodeIntoPacked(make([]byte, tc.n), tc.stride, tc.w, tc.height, bytes.NewReader(nil), MSB, Group3, nil)
+		if err == nil {
+			t.Errorf("%s: got nil error, want non-nil", tc.desc)
+		}
+	}
+}
+
 func testDecodeIntoGray(t *testing.T, fileName string, order Order, sf SubFormat, width int, height int, opts *Options) {
 	t.Helper()
 
@@ -100,3 +100,325 @@ func (b *bitWriter) writeCode(bs bitString) error {
 	b.nBits = nBits
 	return nil
 }
+
+// eolCode is the End-of-Line code, "000000000001".
+var eolCode = bitString{0x0001, 12}
+
+// verticalModes are the modeV* modes for a1-b1 from -3 to +3.
+var verticalModes = [...]int{modeVL3, modeVL2, modeVL1, modeV0, modeVR1, modeVR2, modeVR3}
+
+// group3TwoDK is the K parameter of Group3TwoD encoding: one of every
+// group3TwoDK rows is coded one-dimensionally, so that a corrupted row does not
+// corrupt every row below it. 4 is the value that the T.4 spec recommends for
+// fax machines' higher vertical resolution.
+const group3TwoDK = 4
+
+type writer struct {
+	bw        bitWriter
+	subFormat SubFormat
+	width     int
+
+	// These fields are copied from the *Options (which may be nil).
+	align  bool
+	invert bool
+
+	// row holds the packed bytes of the current row, of which row[:n] have
+	// been written.
+	row []byte
+	n   int
+
+	// curr and prev hold the current and previous rows, unpacked to 1 byte per
+	// pixel. Each element is either 0x00 (black) or 0xFF (white). Before the
+	// first row, prev is all white, as the row above the first row is
+	// implicitly all white.
+	curr []byte
+	prev []byte
+
+	// y is the number of rows encoded so far.
+	y int
+
+	// err is a sticky error for the Write and Close methods.
+	err error
+}
+
+// NewWriter returns an io.WriteCloser that encodes, as CCITT-formatted data
+// written to w, the rows of an image of the given width. The bytes written to
+// it are in the same format as the byte stream of NewReader: one bit per pixel
+// (MSB first), with 1 meaning white and 0 meaning black unless opts.Invert is
+// set, and with each row padded to a whole byte. The image height is the
+// number of rows written.
+//
+// Group3TwoD data codes one row in four one-dimensionally, and the rows in
+// between relative to the row above them. If opts.Align is set, each row
+// starts on a byte boundary. AutoDetectSubFormat is not a valid sub-format for
+// encoding.
+//
+// Data is buffered, and Close must be called to write the end of the data
+// stream: the RTC (Return To Control) for Group3 and Group3TwoD or the EOFB
+// (End Of Facsimile Block) for Group4, padded to a whole byte. Close does not
+// close w.
+func NewWriter(w io.Writer, order Order, sf SubFormat, width int, opts *Options) io.WriteCloser {
+	z := &writer{
+		bw:        bitWriter{w: w, order: order},
+		subFormat: sf,
+		width:     width,
+		align:     (opts != nil) && opts.Align,
+		invert:    (opts != nil) && opts.Invert,
+	}
+	if width < 0 {
+		z.err = errInvalidBounds
+		return z
+	} else if width > maxWidth {
+		z.err = errUnsupportedWidth
+		return z
+	}
+
+	switch sf {
+	case Group3, Group3TwoD:
+		// The data stream starts with an EOL. The bitWriter buffers it, so
+		// that there is no error yet.
+		z.err = z.writeEOL(true)
+	case Group4, ModifiedHuffman:
+		// No-op.
+	default:
+		z.err = errUnsupportedSubFormat
+		return z
+	}
+
+	z.row = make([]byte, (width+7)/8)
+	z.curr = make([]byte, width)
+	z.prev = make([]byte, width)
+	for i := range z.prev {
+		z.prev[i] = 0xFF
+	}
+	return z
+}
+
+func (z *writer) Write(p []byte) (int, error) {
+	if z.err != nil {
+		return 0, z.err
+	}
+	if (len(p) > 0) && (len(z.row) == 0) {
+		// Rows of a zero-width image have no bytes.
+		z.err = errInvalidBounds
+		return 0, z.err
+	}
+
+	n := 0
+	for len(p) > 0 {
+		c := copy(z.row[z.n:], p)
+		p = p[c:]
+		n += c
+		if z.n += c; z.n < len(z.row) {
+			break
+		}
+		z.n = 0
+		if z.err = z.encodeRow(); z.err != nil {
+			return n, z.err
+		}
+	}
+	return n, nil
+}
+
+func (z *writer) Close() error {
+	if z.err != nil {
+		return z.err
+	}
+	if z.n != 0 {
+		z.err = errIncompleteRow
+		return z.err
+	}
+
+	switch z.subFormat {
+	case Group3, Group3TwoD:
+		// The RTC is 6 consecutive EOL's, the first of which ended the final
+		// row (or started a zero-height image).
+		for i := 0; i < 5; i++ {
+			if z.err = z.writeEOL(true); z.err != nil {
+				return z.err
+			}
+		}
+
+	case Group4:
+		if z.align {
+			if z.err = z.bw.alignToByteBoundary(); z.err != nil {
+				return z.err
+			}
+		}
+		// The EOFB is 2 consecutive EOL's.
+		for i := 0; i < 2; i++ {
+			if z.err = z.bw.writeCode(eolCode); z.err != nil {
+				return z.err
+			}
+		}
+	}
+
+	if z.err = z.bw.close(); z.err != nil {
+		return z.err
+	}
+	z.err = errWriterClosed
+	return nil
+}
+
+// writeEOL writes an EOL. For the Group3TwoD subFormat, it is followed by a
+// tag bit for whether the next row is coded one-dimensionally.
+func (z *writer) writeEOL(oneD bool) error {
+	if err := z.bw.writeCode(eolCode); err != nil {
+		return err
+	}
+	if z.subFormat != Group3TwoD {
+		return nil
+	}
+	tag := bitString{0, 1}
+	if oneD {
+		tag.bits = 1
+	}
+	return z.bw.writeCode(tag)
+}
+
+// encodeRow encodes the packed row in z.row.
+func (z *writer) encodeRow() error {
+	for i := range z.curr {
+		bit := z.row[i/8] >> (7 - uint(i%8)) & 1
+		if z.invert {
+			bit ^= 1
+		}
+		z.curr[i] = 0xFF * bit
+	}
+
+	if z.align || (z.subFormat == ModifiedHuffman) {
+		if err := z.bw.alignToByteBoundary(); err != nil {
+			return err
+		}
+	}
+
+	err := error(nil)
+	switch z.subFormat {
+	case Group3:
+		if err = z.encodeRuns(); err == nil {
+			err = z.writeEOL(true)
+		}
+	case Group3TwoD:
+		if z.y%group3TwoDK == 0 {
+			err = z.encodeRuns()
+		} else {
+			err = z.encodeModes()
+		}
+		if err == nil {
+			err = z.writeEOL((z.y+1)%group3TwoDK == 0)
+		}
+	case Group4:
+		err = z.encodeModes()
+	case ModifiedHuffman:
+		err = z.encodeRuns()
+	}
+	if err != nil {
+		return err
+	}
+
+	z.y++
+	z.curr, z.prev = z.prev, z.curr
+	return nil
+}
+
+// findChange returns the index of the first element of row, at or to the
+// right of x, that is not the given color, or len(row) if there is no such
+// element.
+func findChange(row []byte, x int, color byte) int {
+	for ; x < len(row); x++ {
+		if row[x] != color {
+			return x
+		}
+	}
+	return len(row)
+}
+
+// encodeRuns codes z.curr one-dimensionally, as alternating white and black
+// runs, starting with a (possibly empty) white run.
+func (z *writer) encodeRuns() error {
+	for a0, color := 0, byte(0xFF); a0 < len(z.curr); color = ^color {
+		a1 := findChange(z.curr, a0, color)
+		if err := z.writeRun(a1-a0, color == 0xFF); err != nil {
+			return err
+		}
+		a0 = a1
+	}
+	return nil
+}
+
+// encodeModes codes z.curr two-dimensionally, relative to z.prev. See the
+// reader's findB method for the meaning of the a0, a1, a2, b1 and b2 changing
+// elements.
+func (z *writer) encodeModes() error {
+	// a0 starts at the imaginary white pixel to the left of the row.
+	a0, color := -1, byte(0xFF)
+	for a0 < len(z.curr) {
+		a1 := findChange(z.curr, a0+1, color)
+		b1 := z.findB1(a0, color)
+		b2 := findChange(z.prev, b1, ^color)
+
+		if b2 < a1 {
+			if err := z.bw.writeCode(modeEncodeTable[modePass]); err != nil {
+				return err
+			}
+			a0 = b2
+		} else if d := a1 - b1; (-3 <= d) && (d <= 3) {
+			if err := z.bw.writeCode(modeEncodeTable[verticalModes[d+3]]); err != nil {
+				return err
+			}
+			a0, color = a1, ^color
+		} else {
+			a2 := findChange(z.curr, a1, ^color)
+			if a0 < 0 {
+				a0 = 0
+			}
+			if err := z.bw.writeCode(modeEncodeTable[modeH]); err != nil {
+				return err
+			}
+			if err := z.writeRun(a1-a0, color == 0xFF); err != nil {
+				return err
+			}
+			if err := z.writeRun(a2-a1, color != 0xFF); err != nil {
+				return err
+			}
+			a0 = a2
+		}
+	}
+	return nil
+}
+
+// findB1 returns b1, the first changing element of z.prev to the right of a0
+// whose color is the opposite of color, the color of a0, or len(z.prev) if
+// there is no such element.
+func (z *writer) findB1(a0 int, color byte) int {
+	for i := a0 + 1; i < len(z.prev); i++ {
+		left := byte(0xFF)
+		if i > 0 {
+			left = z.prev[i-1]
+		}
+		if (z.prev[i] != color) && (left == color) {
+			return i
+		}
+	}
+	return len(z.prev)
+}
+
+// writeRun writes a white or black run of n pixels, as zero or more makeup
+// codes followed by a terminating code.
+func (z *writer) writeRun(n int, white bool) error {
+	table2, table3 := blackEncodeTable2[:], blackEncodeTable3[:]
+	if white {
+		table2, table3 = whiteEncodeTable2[:], whiteEncodeTable3[:]
+	}
+	for ; n >= 2560; n -= 2560 {
+		if err := z.bw.writeCode(table3[2560/64-1]); err != nil {
+			return err
+		}
+	}
+	if n >= 64 {
+		if err := z.bw.writeCode(table3[n/64-1]); err != nil {
+			return err
+		}
+	}
+	return z.bw.writeCode(table2[n%64])
+}
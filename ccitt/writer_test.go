@@ -6,6 +6,9 @@ package ccitt
 
 import (
 	"bytes"
+	"io"
+	"math/rand"
+	"os"
 	"reflect"
 	"testing"
 )
@@ -65,3 +68,142 @@ func testEncode(t *testing.T, o Order) {
 
 func TestEncodeLSB(t *testing.T) { testEncode(t, LSB) }
 func TestEncodeMSB(t *testing.T) { testEncode(t, MSB) }
+
+// encodeAll encodes the packed rows of pix with a NewWriter.
+func encodeAll(t *testing.T, pix []byte, order Order, sf SubFormat, width int, opts *Options) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := NewWriter(&buf, order, sf, width, opts)
+	if _, err := w.Write(pix); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestWriterTestdata(t *testing.T) {
+	const width, height = 153, 55
+	for _, tc := range []struct {
+		fileName string
+		sf       SubFormat
+		opts     *Options
+	}{
+		{"testdata/bw-gopher.ccitt_group3", Group3, nil},
+		{"testdata/bw-gopher-aligned.ccitt_group3", Group3, &Options{Align: true}},
+		{"testdata/bw-gopher-inverted.ccitt_group3", Group3, &Options{Invert: true}},
+		{"testdata/bw-gopher.ccitt_group4", Group4, nil},
+		{"testdata/bw-gopher-aligned.ccitt_group4", Group4, &Options{Align: true}},
+		{"testdata/bw-gopher-inverted.ccitt_group4", Group4, &Options{Invert: true}},
+		{"testdata/bw-gopher-inverted-aligned.ccitt_group4", Group4, &Options{Align: true, Invert: true}},
+	} {
+		want, err := os.ReadFile(tc.fileName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pix, err := io.ReadAll(NewReader(bytes.NewReader(want), MSB, tc.sf, width, height, tc.opts))
+		if err != nil {
+			t.Fatalf("%s: ReadAll: %v", tc.fileName, err)
+		}
+		if got := encodeAll(t, pix, MSB, tc.sf, width, tc.opts); !bytes.Equal(got, want) {
+			t.Errorf("%s: encoded data differs", tc.fileName)
+		}
+	}
+}
+
+func TestWriterRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	gopher, err := os.ReadFile("testdata/bw-gopher.ccitt_group4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gopherPix, err := io.ReadAll(NewReader(bytes.NewReader(gopher), MSB, Group4, 153, 55, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The wide image has runs of more than 2560 pixels, and rows that differ
+	// from the row above by more than 3 pixels.
+	const wideWidth, wideHeight = 6000, 9
+	wideRowLen := (wideWidth + 7) / 8
+	widePix := make([]byte, wideRowLen*wideHeight)
+	for y := 0; y < wideHeight; y++ {
+		row := widePix[y*wideRowLen : (y+1)*wideRowLen]
+		for x, white := rng.Intn(3000), false; x < wideWidth; x += 1 + rng.Intn(100*y+1) {
+			white = !white
+			for i := x; white && (i < wideWidth) && (i < x+64); i++ {
+				row[i/8] |= 0x80 >> uint(i%8)
+			}
+		}
+	}
+
+	for _, img := range []struct {
+		name          string
+		pix           []byte
+		width, height int
+	}{
+		{"gopher", gopherPix, 153, 55},
+		{"wide", widePix, wideWidth, wideHeight},
+		{"empty", nil, 10, 0},
+	} {
+		for _, sf := range []SubFormat{Group3, Group3TwoD, Group4, ModifiedHuffman} {
+			for _, order := range []Order{LSB, MSB} {
+				for _, opts := range []*Options{nil, {Align: true}, {Invert: true}} {
+					data := encodeAll(t, img.pix, order, sf, img.width, opts)
+					for _, height := range []int{img.height, AutoDetectHeight} {
+						// When reading aligned Group 3 rows of an unknown height,
+						// the padding bits before a row can be mistaken for the
+						// start of an EOL.
+						if (height < 0) && (opts != nil) && opts.Align && ((sf == Group3) || (sf == Group3TwoD)) {
+							continue
+						}
+						got, err := io.ReadAll(NewReader(bytes.NewReader(data), order, sf, img.width, height, opts))
+						if err != nil {
+							t.Fatalf("%s, sf=%d, order=%d, opts=%v, height=%d: ReadAll: %v",
+								img.name, sf, order, opts, height, err)
+						}
+						if !bytes.Equal(got, img.pix) {
+							t.Fatalf("%s, sf=%d, order=%d, opts=%v, height=%d: round trip differs",
+								img.name, sf, order, opts, height)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestWriterErrors(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, MSB, Group4, 9, nil)
+	if _, err := w.Write(make([]byte, 3)); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := w.Close(); err != errIncompleteRow {
+		t.Errorf("Close after a partial row: got %v, want %v", err, errIncompleteRow)
+	}
+
+	w = NewWriter(&buf, MSB, Group4, 9, nil)
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if _, err := w.Write(make([]byte, 2)); err != errWriterClosed {
+		t.Errorf("Write after Close: got %v, want %v", err, errWriterClosed)
+	}
+
+	for _, tc := range []struct {
+		sf    SubFormat
+		width int
+		want  error
+	}{
+		{Group4, -1, errInvalidBounds},
+		{Group4, maxWidth + 1, errUnsupportedWidth},
+		{Group4, 0, errInvalidBounds},
+		{AutoDetectSubFormat, 9, errUnsupportedSubFormat},
+	} {
+		if _, err := NewWriter(&buf, MSB, tc.sf, tc.width, nil).Write(make([]byte, 2)); err != tc.want {
+			t.Errorf("sf=%d, width=%d: got %v, want %v", tc.sf, tc.width, err, tc.want)
+		}
+	}
+}
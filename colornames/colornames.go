@@ -7,4 +7,6 @@
 // Package colornames provides named colors as defined in the SVG 1.1 spec.
 //
 // See https://www.w3.org/TR/SVG11/types.html#ColorKeywords
+//
+// The css4 and material subpackages provide other sets of named colors.
 package colornames
@@ -0,0 +1,11 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:generate go run ../gen.go -set=css4
+
+// Package css4 provides named colors as defined in the CSS Color Module
+// Level 4 spec. They are the colors of package colornames, and rebeccapurple.
+//
+// See https://www.w3.org/TR/css-color-4/#named-colors
+package css4
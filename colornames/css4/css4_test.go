@@ -0,0 +1,32 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package css4
+
+import (
+	"image/color"
+	"testing"
+
+	"golang.org/x/image/colornames"
+)
+
+func TestCSS4(t *testing.T) {
+	if len(Map) != len(Names) {
+		t.Fatalf("Map and Names have different length: %d vs %d", len(Map), len(Names))
+	}
+	if len(Map) != len(colornames.Map)+1 {
+		t.Fatalf("got %d colors, want %d", len(Map), len(colornames.Map)+1)
+	}
+
+	for name, want := range colornames.Map {
+		if got, ok := Map[name]; !ok {
+			t.Errorf("Did not find %s", name)
+		} else if got != want {
+			t.Errorf("%s:\ngot  %v\nwant %v", name, got, want)
+		}
+	}
+	if got, want := Rebeccapurple, (color.RGBA{102, 51, 153, 255}); got != want || Map["rebeccapurple"] != want {
+		t.Errorf("rebeccapurple:\ngot  %v\nwant %v", got, want)
+	}
+}
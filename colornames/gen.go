@@ -6,10 +6,18 @@
 
 // This program generates table.go from
 // https://www.w3.org/TR/SVG11/types.html#ColorKeywords
+//
+// With the -set flag, it instead generates the table.go of one of the
+// subpackages that provide other sets of named colors, when run in that
+// subpackage's directory:
+//
+//	-set=css4      https://www.w3.org/TR/css-color-4/#named-colors
+//	-set=material  the Material Design color palette
 package main
 
 import (
 	"bytes"
+	"flag"
 	"fmt"
 	"go/format"
 	"image/color"
@@ -123,24 +131,180 @@ func extractSVGColors(tree *html.Node) (map[string]color.RGBA, error) {
 	return ret, nil
 }
 
+// parseHex parses a color from a string like "#00df80". It sets the alpha
+// value of the color to full opacity.
+func parseHex(s string) (color.RGBA, error) {
+	s = strings.TrimSpace(s)
+	if len(s) != 7 || s[0] != '#' {
+		return color.RGBA{}, fmt.Errorf("malformed color: %q", s)
+	}
+	num, err := strconv.ParseUint(s[1:], 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("malformed color: %q: %s", s, err)
+	}
+	return color.RGBA{uint8(num >> 16), uint8(num >> 8), uint8(num), 0xFF}, nil
+}
+
+// textContent returns the concatenated text of the TextNodes under n.
+func textContent(n *html.Node) string {
+	var b strings.Builder
+	for _, t := range appendAll(nil, n, func(n *html.Node) bool { return n.Type == html.TextNode }) {
+		b.WriteString(t.Data)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// extractCSS4Colors extracts named colors from the parse tree of the CSS
+// Color Module Level 4 spec HTML document.
+func extractCSS4Colors(tree *html.Node) (map[string]color.RGBA, error) {
+	ret := make(map[string]color.RGBA)
+
+	// Find the table which stores the named colors in the parse tree.
+	colorTables := appendAll(nil, tree, func(n *html.Node) bool {
+		if n.DataAtom != atom.Table {
+			return false
+		}
+		for _, class := range strings.Fields(getAttr(n, "", "class")) {
+			if class == "named-color-table" {
+				return true
+			}
+		}
+		return false
+	})
+
+	for _, table := range colorTables {
+		// Each row has the color's name in a dfn element, and its value in
+		// a td element like "#f0f8ff". The header row has neither.
+		for _, tr := range appendAll(nil, table, matchAtom(atom.Tr)) {
+			dfns := appendAll(nil, tr, matchAtom(atom.Dfn))
+			if len(dfns) != 1 {
+				continue
+			}
+			name, value := textContent(dfns[0]), ""
+			for _, td := range appendAll(nil, tr, matchAtom(atom.Td)) {
+				if v := textContent(td); strings.HasPrefix(v, "#") {
+					value = v
+					break
+				}
+			}
+			val, err := parseHex(value)
+			if err != nil {
+				return nil, fmt.Errorf("extractCSS4Colors: couldn't parse name/value %q/%q: %s", name, value, err)
+			}
+			ret[name] = val
+		}
+	}
+	if len(ret) == 0 {
+		return nil, fmt.Errorf("extractCSS4Colors: couldn't find the named color table")
+	}
+	return ret, nil
+}
+
+// materialPalette is the Material Design color palette, from
+// https://m2.material.io/design/color/the-color-system.html#tools-for-picking-colors
+//
+// Each line holds a hue's name and its shades' values, from 50 to 900 and,
+// for most hues, then from A100 to A700.
+const materialPalette = `
+red         #ffebee #ffcdd2 #ef9a9a #e57373 #ef5350 #f44336 #e53935 #d32f2f #c62828 #b71c1c #ff8a80 #ff5252 #ff1744 #d50000
+pink        #fce4ec #f8bbd0 #f48fb1 #f06292 #ec407a #e91e63 #d81b60 #c2185b #ad1457 #880e4f #ff80ab #ff4081 #f50057 #c51162
+purple      #f3e5f5 #e1bee7 #ce93d8 #ba68c8 #ab47bc #9c27b0 #8e24aa #7b1fa2 #6a1b9a #4a148c #ea80fc #e040fb #d500f9 #aa00ff
+deep-purple #ede7f6 #d1c4e9 #b39ddb #9575cd #7e57c2 #673ab7 #5e35b1 #512da8 #4527a0 #311b92 #b388ff #7c4dff #651fff #6200ea
+indigo      #e8eaf6 #c5cae9 #9fa8da #7986cb #5c6bc0 #3f51b5 #3949ab #303f9f #283593 #1a237e #8c9eff #536dfe #3d5afe #304ffe
+blue        #e3f2fd #bbdefb #90caf9 #64b5f6 #42a5f5 #2196f3 #1e88e5 #1976d2 #1565c0 #0d47a1 #82b1ff #448aff #2979ff #2962ff
+light-blue  #e1f5fe #b3e5fc #81d4fa #4fc3f7 #29b6f6 #03a9f4 #039be5 #0288d1 #0277bd #01579b #80d8ff #40c4ff #00b0ff #0091ea
+cyan        #e0f7fa #b2ebf2 #80deea #4dd0e1 #26c6da #00bcd4 #00acc1 #0097a7 #00838f #006064 #84ffff #18ffff #00e5ff #00b8d4
+teal        #e0f2f1 #b2dfdb #80cbc4 #4db6ac #26a69a #009688 #00897b #00796b #00695c #004d40 #a7ffeb #64ffda #1de9b6 #00bfa5
+green       #e8f5e9 #c8e6c9 #a5d6a7 #81c784 #66bb6a #4caf50 #43a047 #388e3c #2e7d32 #1b5e20 #b9f6ca #69f0ae #00e676 #00c853
+light-green #f1f8e9 #dcedc8 #c5e1a5 #aed581 #9ccc65 #8bc34a #7cb342 #689f38 #558b2f #33691e #ccff90 #b2ff59 #76ff03 #64dd17
+lime        #f9fbe7 #f0f4c3 #e6ee9c #dce775 #d4e157 #cddc39 #c0ca33 #afb42b #9e9d24 #827717 #f4ff81 #eeff41 #c6ff00 #aeea00
+yellow      #fffde7 #fff9c4 #fff59d #fff176 #ffee58 #ffeb3b #fdd835 #fbc02d #f9a825 #f57f17 #ffff8d #ffff00 #ffea00 #ffd600
+amber       #fff8e1 #ffecb3 #ffe082 #ffd54f #ffca28 #ffc107 #ffb300 #ffa000 #ff8f00 #ff6f00 #ffe57f #ffd740 #ffc400 #ffab00
+orange      #fff3e0 #ffe0b2 #ffcc80 #ffb74d #ffa726 #ff9800 #fb8c00 #f57c00 #ef6c00 #e65100 #ffd180 #ffab40 #ff9100 #ff6d00
+deep-orange #fbe9e7 #ffccbc #ffab91 #ff8a65 #ff7043 #ff5722 #f4511e #e64a19 #d84315 #bf360c #ff9e80 #ff6e40 #ff3d00 #dd2c00
+brown       #efebe9 #d7ccc8 #bcaaa4 #a1887f #8d6e63 #795548 #6d4c41 #5d4037 #4e342e #3e2723
+grey        #fafafa #f5f5f5 #eeeeee #e0e0e0 #bdbdbd #9e9e9e #757575 #616161 #424242 #212121
+blue-grey   #eceff1 #cfd8dc #b0bec5 #90a4ae #78909c #607d8b #546e7a #455a64 #37474f #263238
+black       #000000
+white       #ffffff
+`
+
+// materialShades are the names of the shades in each line of
+// materialPalette.
+var materialShades = []string{"50", "100", "200", "300", "400", "500", "600", "700", "800", "900", "a100", "a200", "a400", "a700"}
+
+// materialColors returns the named colors of the Material Design color
+// palette, with names like "deep-purple-500" and "deep-purple-a100", and the
+// unshaded "black" and "white".
+func materialColors() (map[string]color.RGBA, error) {
+	ret := make(map[string]color.RGBA)
+	for _, line := range strings.Split(strings.TrimSpace(materialPalette), "\n") {
+		f := strings.Fields(line)
+		if len(f) > 1+len(materialShades) {
+			return nil, fmt.Errorf("materialColors: too many shades of %s", f[0])
+		}
+		for i, v := range f[1:] {
+			val, err := parseHex(v)
+			if err != nil {
+				return nil, fmt.Errorf("materialColors: %s: %s", f[0], err)
+			}
+			name := f[0]
+			if len(f) > 2 {
+				name += "-" + materialShades[i]
+			}
+			ret[name] = val
+		}
+	}
+	return ret, nil
+}
+
+// fetchColors returns the named colors extracted from the HTML document at
+// url.
+func fetchColors(url string, extract func(*html.Node) (map[string]color.RGBA, error)) (map[string]color.RGBA, error) {
+	res, err := http.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read from %s: %s", url, err)
+	}
+	defer res.Body.Close()
+
+	tree, err := html.Parse(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse %s: %s", url, err)
+	}
+	return extract(tree)
+}
+
 const preamble = `// generated by go generate; DO NOT EDIT.
 
-package colornames
+package %s
 
 import "image/color"
 
 `
 
-// WriteColorNames writes table.go.
-func writeColorNames(w io.Writer, m map[string]color.RGBA) {
+// goName returns the exported Go name of the color named k: "Darkred" for
+// "darkred", "Red50" for "red-50" and "DeepPurpleA100" for
+// "deep-purple-a100".
+func goName(k string) string {
+	parts := strings.Split(k, "-")
+	for i, p := range parts {
+		if 'a' <= p[0] && p[0] <= 'z' {
+			parts[i] = string(p[0]-0x20) + p[1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// WriteColorNames writes table.go, for the named colors m defined in spec.
+func writeColorNames(w io.Writer, pkg, spec string, m map[string]color.RGBA) {
 	keys := make([]string, 0, len(m))
 	for k := range m {
 		keys = append(keys, k)
 	}
 	sort.Strings(keys)
 
-	fmt.Fprintln(w, preamble)
-	fmt.Fprintln(w, "// Map contains named colors defined in the SVG 1.1 spec.")
+	fmt.Fprintf(w, preamble, pkg)
+	fmt.Fprintf(w, "// Map contains named colors defined in %s.\n", spec)
 	fmt.Fprintln(w, "var Map = map[string]color.RGBA{")
 	for _, k := range keys {
 		c := m[k]
@@ -148,7 +312,7 @@ func writeColorNames(w io.Writer, m map[string]color.RGBA) {
 			k, c.R, c.G, c.B, c.A, c.R, c.G, c.B)
 	}
 	fmt.Fprintln(w, "}\n")
-	fmt.Fprintln(w, "// Names contains the color names defined in the SVG 1.1 spec.")
+	fmt.Fprintf(w, "// Names contains the color names defined in %s.\n", spec)
 	fmt.Fprintln(w, "var Names = []string{")
 	for _, k := range keys {
 		fmt.Fprintf(w, "%q,\n", k)
@@ -157,35 +321,44 @@ func writeColorNames(w io.Writer, m map[string]color.RGBA) {
 	fmt.Fprintln(w, "var (")
 	for _, k := range keys {
 		c := m[k]
-		// Make the upper case version of k: "Darkred" instead of "darkred".
-		k = string(k[0]-0x20) + k[1:]
 		fmt.Fprintf(w, "%s=color.RGBA{%#02x, %#02x, %#02x, %#02x} // rgb(%d, %d, %d)\n",
-			k, c.R, c.G, c.B, c.A, c.R, c.G, c.B)
+			goName(k), c.R, c.G, c.B, c.A, c.R, c.G, c.B)
 	}
 	fmt.Fprintln(w, ")")
 }
 
-const url = "https://www.w3.org/TR/SVG11/types.html"
+// colorSets are the sets of named colors that the -set flag selects, and the
+// packages that provide them.
+var colorSets = map[string]struct {
+	pkg  string
+	spec string
+	load func() (map[string]color.RGBA, error)
+}{
+	"svg": {"colornames", "the SVG 1.1 spec", func() (map[string]color.RGBA, error) {
+		return fetchColors("https://www.w3.org/TR/SVG11/types.html", extractSVGColors)
+	}},
+	"css4": {"css4", "the CSS Color Module Level 4 spec", func() (map[string]color.RGBA, error) {
+		return fetchColors("https://www.w3.org/TR/css-color-4/", extractCSS4Colors)
+	}},
+	"material": {"material", "the Material Design color palette", materialColors},
+}
+
+var set = flag.String("set", "svg", `the set of named colors to generate: "svg", "css4" or "material"`)
 
 func main() {
-	res, err := http.Get(url)
-	if err != nil {
-		log.Fatalf("Couldn't read from %s: %s\n", url, err)
-	}
-	defer res.Body.Close()
-
-	tree, err := html.Parse(res.Body)
-	if err != nil {
-		log.Fatalf("Couldn't parse %s: %s\n", url, err)
+	flag.Parse()
+	cs, ok := colorSets[*set]
+	if !ok {
+		log.Fatalf("Unknown set of named colors %q\n", *set)
 	}
 
-	colors, err := extractSVGColors(tree)
+	colors, err := cs.load()
 	if err != nil {
 		log.Fatalf("Couldn't extract colors: %s\n", err)
 	}
 
 	buf := &bytes.Buffer{}
-	writeColorNames(buf, colors)
+	writeColorNames(buf, cs.pkg, cs.spec, colors)
 	fmted, err := format.Source(buf.Bytes())
 	if err != nil {
 		log.Fatalf("Error while formatting code: %s\n", err)
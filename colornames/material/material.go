@@ -0,0 +1,13 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:generate go run ../gen.go -set=material
+
+// Package material provides the named colors of the Material Design color
+// palette. Each hue has shades named like "deep-purple-500", from 50 to 900,
+// and most hues also have accent shades named like "deep-purple-a100", from
+// A100 to A700. The palette also has black and white.
+//
+// See https://m2.material.io/design/color/the-color-system.html#tools-for-picking-colors
+package material
@@ -0,0 +1,51 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package material
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestMaterial(t *testing.T) {
+	if len(Map) != len(Names) {
+		t.Fatalf("Map and Names have different length: %d vs %d", len(Map), len(Names))
+	}
+	// 16 hues have 14 shades, 3 hues have 10 shades, and there is black and
+	// white.
+	if got, want := len(Map), 16*14+3*10+2; got != want {
+		t.Fatalf("got %d colors, want %d", got, want)
+	}
+
+	for name, want := range testCases {
+		got, ok := Map[name]
+		if !ok {
+			t.Errorf("Did not find %s", name)
+			continue
+		}
+		if got != want {
+			t.Errorf("%s:\ngot  %v\nwant %v", name, got, want)
+		}
+	}
+	if DeepPurpleA100 != Map["deep-purple-a100"] || BlueGrey900 != Map["blue-grey-900"] {
+		t.Errorf("variables and Map differ")
+	}
+}
+
+var testCases = map[string]color.RGBA{
+	"red-50":           color.RGBA{0xff, 0xeb, 0xee, 0xff},
+	"red-500":          color.RGBA{0xf4, 0x43, 0x36, 0xff},
+	"red-a700":         color.RGBA{0xd5, 0x00, 0x00, 0xff},
+	"deep-purple-a100": color.RGBA{0xb3, 0x88, 0xff, 0xff},
+	"indigo-500":       color.RGBA{0x3f, 0x51, 0xb5, 0xff},
+	"blue-500":         color.RGBA{0x21, 0x96, 0xf3, 0xff},
+	"teal-900":         color.RGBA{0x00, 0x4d, 0x40, 0xff},
+	"amber-500":        color.RGBA{0xff, 0xc1, 0x07, 0xff},
+	"brown-500":        color.RGBA{0x79, 0x55, 0x48, 0xff},
+	"grey-900":         color.RGBA{0x21, 0x21, 0x21, 0xff},
+	"blue-grey-500":    color.RGBA{0x60, 0x7d, 0x8b, 0xff},
+	"black":            color.RGBA{0x00, 0x00, 0x00, 0xff},
+	"white":            color.RGBA{0xff, 0xff, 0xff, 0xff},
+}
@@ -0,0 +1,784 @@
+// generated by go generate; DO NOT EDIT.
+
+package material
+
+import "image/color"
+
+// Map contains named colors defined in the Material Design color palette.
+var Map = map[string]color.RGBA{
+	"amber-100":        color.RGBA{0xff, 0xec, 0xb3, 0xff}, // rgb(255, 236, 179)
+	"amber-200":        color.RGBA{0xff, 0xe0, 0x82, 0xff}, // rgb(255, 224, 130)
+	"amber-300":        color.RGBA{0xff, 0xd5, 0x4f, 0xff}, // rgb(255, 213, 79)
+	"amber-400":        color.RGBA{0xff, 0xca, 0x28, 0xff}, // rgb(255, 202, 40)
+	"amber-50":         color.RGBA{0xff, 0xf8, 0xe1, 0xff}, // rgb(255, 248, 225)
+	"amber-500":        color.RGBA{0xff, 0xc1, 0x07, 0xff}, // rgb(255, 193, 7)
+	"amber-600":        color.RGBA{0xff, 0xb3, 0x00, 0xff}, // rgb(255, 179, 0)
+	"amber-700":        color.RGBA{0xff, 0xa0, 0x00, 0xff}, // rgb(255, 160, 0)
+	"amber-800":        color.RGBA{0xff, 0x8f, 0x00, 0xff}, // rgb(255, 143, 0)
+	"amber-900":        color.RGBA{0xff, 0x6f, 0x00, 0xff}, // rgb(255, 111, 0)
+	"amber-a100":       color.RGBA{0xff, 0xe5, 0x7f, 0xff}, // rgb(255, 229, 127)
+	"amber-a200":       color.RGBA{0xff, 0xd7, 0x40, 0xff}, // rgb(255, 215, 64)
+	"amber-a400":       color.RGBA{0xff, 0xc4, 0x00, 0xff}, // rgb(255, 196, 0)
+	"amber-a700":       color.RGBA{0xff, 0xab, 0x00, 0xff}, // rgb(255, 171, 0)
+	"black":            color.RGBA{0x00, 0x00, 0x00, 0xff}, // rgb(0, 0, 0)
+	"blue-100":         color.RGBA{0xbb, 0xde, 0xfb, 0xff}, // rgb(187, 222, 251)
+	"blue-200":         color.RGBA{0x90, 0xca, 0xf9, 0xff}, // rgb(144, 202, 249)
+	"blue-300":         color.RGBA{0x64, 0xb5, 0xf6, 0xff}, // rgb(100, 181, 246)
+	"blue-400":         color.RGBA{0x42, 0xa5, 0xf5, 0xff}, // rgb(66, 165, 245)
+	"blue-50":          color.RGBA{0xe3, 0xf2, 0xfd, 0xff}, // rgb(227, 242, 253)
+	"blue-500":         color.RGBA{0x21, 0x96, 0xf3, 0xff}, // rgb(33, 150, 243)
+	"blue-600":         color.RGBA{0x1e, 0x88, 0xe5, 0xff}, // rgb(30, 136, 229)
+	"blue-700":         color.RGBA{0x19, 0x76, 0xd2, 0xff}, // rgb(25, 118, 210)
+	"blue-800":         color.RGBA{0x15, 0x65, 0xc0, 0xff}, // rgb(21, 101, 192)
+	"blue-900":         color.RGBA{0x0d, 0x47, 0xa1, 0xff}, // rgb(13, 71, 161)
+	"blue-a100":        color.RGBA{0x82, 0xb1, 0xff, 0xff}, // rgb(130, 177, 255)
+	"blue-a200":        color.RGBA{0x44, 0x8a, 0xff, 0xff}, // rgb(68, 138, 255)
+	"blue-a400":        color.RGBA{0x29, 0x79, 0xff, 0xff}, // rgb(41, 121, 255)
+	"blue-a700":        color.RGBA{0x29, 0x62, 0xff, 0xff}, // rgb(41, 98, 255)
+	"blue-grey-100":    color.RGBA{0xcf, 0xd8, 0xdc, 0xff}, // rgb(207, 216, 220)
+	"blue-grey-200":    color.RGBA{0xb0, 0xbe, 0xc5, 0xff}, // rgb(176, 190, 197)
+	"blue-grey-300":    color.RGBA{0x90, 0xa4, 0xae, 0xff}, // rgb(144, 164, 174)
+	"blue-grey-400":    color.RGBA{0x78, 0x90, 0x9c, 0xff}, // rgb(120, 144, 156)
+	"blue-grey-50":     color.RGBA{0xec, 0xef, 0xf1, 0xff}, // rgb(236, 239, 241)
+	"blue-grey-500":    color.RGBA{0x60, 0x7d, 0x8b, 0xff}, // rgb(96, 125, 139)
+	"blue-grey-600":    color.RGBA{0x54, 0x6e, 0x7a, 0xff}, // rgb(84, 110, 122)
+	"blue-grey-700":    color.RGBA{0x45, 0x5a, 0x64, 0xff}, // rgb(69, 90, 100)
+	"blue-grey-800":    color.RGBA{0x37, 0x47, 0x4f, 0xff}, // rgb(55, 71, 79)
+	"blue-grey-900":    color.RGBA{0x26, 0x32, 0x38, 0xff}, // rgb(38, 50, 56)
+	"brown-100":        color.RGBA{0xd7, 0xcc, 0xc8, 0xff}, // rgb(215, 204, 200)
+	"brown-200":        color.RGBA{0xbc, 0xaa, 0xa4, 0xff}, // rgb(188, 170, 164)
+	"brown-300":        color.RGBA{0xa1, 0x88, 0x7f, 0xff}, // rgb(161, 136, 127)
+	"brown-400":        color.RGBA{0x8d, 0x6e, 0x63, 0xff}, // rgb(141, 110, 99)
+	"brown-50":         color.RGBA{0xef, 0xeb, 0xe9, 0xff}, // rgb(239, 235, 233)
+	"brown-500":        color.RGBA{0x79, 0x55, 0x48, 0xff}, // rgb(121, 85, 72)
+	"brown-600":        color.RGBA{0x6d, 0x4c, 0x41, 0xff}, // rgb(109, 76, 65)
+	"brown-700":        color.RGBA{0x5d, 0x40, 0x37, 0xff}, // rgb(93, 64, 55)
+	"brown-800":        color.RGBA{0x4e, 0x34, 0x2e, 0xff}, // rgb(78, 52, 46)
+	"brown-900":        color.RGBA{0x3e, 0x27, 0x23, 0xff}, // rgb(62, 39, 35)
+	"cyan-100":         color.RGBA{0xb2, 0xeb, 0xf2, 0xff}, // rgb(178, 235, 242)
+	"cyan-200":         color.RGBA{0x80, 0xde, 0xea, 0xff}, // rgb(128, 222, 234)
+	"cyan-300":         color.RGBA{0x4d, 0xd0, 0xe1, 0xff}, // rgb(77, 208, 225)
+	"cyan-400":         color.RGBA{0x26, 0xc6, 0xda, 0xff}, // rgb(38, 198, 218)
+	"cyan-50":          color.RGBA{0xe0, 0xf7, 0xfa, 0xff}, // rgb(224, 247, 250)
+	"cyan-500":         color.RGBA{0x00, 0xbc, 0xd4, 0xff}, // rgb(0, 188, 212)
+	"cyan-600":         color.RGBA{0x00, 0xac, 0xc1, 0xff}, // rgb(0, 172, 193)
+	"cyan-700":         color.RGBA{0x00, 0x97, 0xa7, 0xff}, // rgb(0, 151, 167)
+	"cyan-800":         color.RGBA{0x00, 0x83, 0x8f, 0xff}, // rgb(0, 131, 143)
+	"cyan-900":         color.RGBA{0x00, 0x60, 0x64, 0xff}, // rgb(0, 96, 100)
+	"cyan-a100":        color.RGBA{0x84, 0xff, 0xff, 0xff}, // rgb(132, 255, 255)
+	"cyan-a200":        color.RGBA{0x18, 0xff, 0xff, 0xff}, // rgb(24, 255, 255)
+	"cyan-a400":        color.RGBA{0x00, 0xe5, 0xff, 0xff}, // rgb(0, 229, 255)
+	"cyan-a700":        color.RGBA{0x00, 0xb8, 0xd4, 0xff}, // rgb(0, 184, 212)
+	"deep-orange-100":  color.RGBA{0xff, 0xcc, 0xbc, 0xff}, // rgb(255, 204, 188)
+	"deep-orange-200":  color.RGBA{0xff, 0xab, 0x91, 0xff}, // rgb(255, 171, 145)
+	"deep-orange-300":  color.RGBA{0xff, 0x8a, 0x65, 0xff}, // rgb(255, 138, 101)
+	"deep-orange-400":  color.RGBA{0xff, 0x70, 0x43, 0xff}, // rgb(255, 112, 67)
+	"deep-orange-50":   color.RGBA{0xfb, 0xe9, 0xe7, 0xff}, // rgb(251, 233, 231)
+	"deep-orange-500":  color.RGBA{0xff, 0x57, 0x22, 0xff}, // rgb(255, 87, 34)
+	"deep-orange-600":  color.RGBA{0xf4, 0x51, 0x1e, 0xff}, // rgb(244, 81, 30)
+	"deep-orange-700":  color.RGBA{0xe6, 0x4a, 0x19, 0xff}, // rgb(230, 74, 25)
+	"deep-orange-800":  color.RGBA{0xd8, 0x43, 0x15, 0xff}, // rgb(216, 67, 21)
+	"deep-orange-900":  color.RGBA{0xbf, 0x36, 0x0c, 0xff}, // rgb(191, 54, 12)
+	"deep-orange-a100": color.RGBA{0xff, 0x9e, 0x80, 0xff}, // rgb(255, 158, 128)
+	"deep-orange-a200": color.RGBA{0xff, 0x6e, 0x40, 0xff}, // rgb(255, 110, 64)
+	"deep-orange-a400": color.RGBA{0xff, 0x3d, 0x00, 0xff}, // rgb(255, 61, 0)
+	"deep-orange-a700": color.RGBA{0xdd, 0x2c, 0x00, 0xff}, // rgb(221, 44, 0)
+	"deep-purple-100":  color.RGBA{0xd1, 0xc4, 0xe9, 0xff}, // rgb(209, 196, 233)
+	"deep-purple-200":  color.RGBA{0xb3, 0x9d, 0xdb, 0xff}, // rgb(179, 157, 219)
+	"deep-purple-300":  color.RGBA{0x95, 0x75, 0xcd, 0xff}, // rgb(149, 117, 205)
+	"deep-purple-400":  color.RGBA{0x7e, 0x57, 0xc2, 0xff}, // rgb(126, 87, 194)
+	"deep-purple-50":   color.RGBA{0xed, 0xe7, 0xf6, 0xff}, // rgb(237, 231, 246)
+	"deep-purple-500":  color.RGBA{0x67, 0x3a, 0xb7, 0xff}, // rgb(103, 58, 183)
+	"deep-purple-600":  color.RGBA{0x5e, 0x35, 0xb1, 0xff}, // rgb(94, 53, 177)
+	"deep-purple-700":  color.RGBA{0x51, 0x2d, 0xa8, 0xff}, // rgb(81, 45, 168)
+	"deep-purple-800":  color.RGBA{0x45, 0x27, 0xa0, 0xff}, // rgb(69, 39, 160)
+	"deep-purple-900":  color.RGBA{0x31, 0x1b, 0x92, 0xff}, // rgb(49, 27, 146)
+	"deep-purple-a100": color.RGBA{0xb3, 0x88, 0xff, 0xff}, // rgb(179, 136, 255)
+	"deep-purple-a200": color.RGBA{0x7c, 0x4d, 0xff, 0xff}, // rgb(124, 77, 255)
+	"deep-purple-a400": color.RGBA{0x65, 0x1f, 0xff, 0xff}, // rgb(101, 31, 255)
+	"deep-purple-a700": color.RGBA{0x62, 0x00, 0xea, 0xff}, // rgb(98, 0, 234)
+	"green-100":        color.RGBA{0xc8, 0xe6, 0xc9, 0xff}, // rgb(200, 230, 201)
+	"green-200":        color.RGBA{0xa5, 0xd6, 0xa7, 0xff}, // rgb(165, 214, 167)
+	"green-300":        color.RGBA{0x81, 0xc7, 0x84, 0xff}, // rgb(129, 199, 132)
+	"green-400":        color.RGBA{0x66, 0xbb, 0x6a, 0xff}, // rgb(102, 187, 106)
+	"green-50":         color.RGBA{0xe8, 0xf5, 0xe9, 0xff}, // rgb(232, 245, 233)
+	"green-500":        color.RGBA{0x4c, 0xaf, 0x50, 0xff}, // rgb(76, 175, 80)
+	"green-600":        color.RGBA{0x43, 0xa0, 0x47, 0xff}, // rgb(67, 160, 71)
+	"green-700":        color.RGBA{0x38, 0x8e, 0x3c, 0xff}, // rgb(56, 142, 60)
+	"green-800":        color.RGBA{0x2e, 0x7d, 0x32, 0xff}, // rgb(46, 125, 50)
+	"green-900":        color.RGBA{0x1b, 0x5e, 0x20, 0xff}, // rgb(27, 94, 32)
+	"green-a100":       color.RGBA{0xb9, 0xf6, 0xca, 0xff}, // rgb(185, 246, 202)
+	"green-a200":       color.RGBA{0x69, 0xf0, 0xae, 0xff}, // rgb(105, 240, 174)
+	"green-a400":       color.RGBA{0x00, 0xe6, 0x76, 0xff}, // rgb(0, 230, 118)
+	"green-a700":       color.RGBA{0x00, 0xc8, 0x53, 0xff}, // rgb(0, 200, 83)
+	"grey-100":         color.RGBA{0xf5, 0xf5, 0xf5, 0xff}, // rgb(245, 245, 245)
+	"grey-200":         color.RGBA{0xee, 0xee, 0xee, 0xff}, // rgb(238, 238, 238)
+	"grey-300":         color.RGBA{0xe0, 0xe0, 0xe0, 0xff}, // rgb(224, 224, 224)
+	"grey-400":         color.RGBA{0xbd, 0xbd, 0xbd, 0xff}, // rgb(189, 189, 189)
+	"grey-50":          color.RGBA{0xfa, 0xfa, 0xfa, 0xff}, // rgb(250, 250, 250)
+	"grey-500":         color.RGBA{0x9e, 0x9e, 0x9e, 0xff}, // rgb(158, 158, 158)
+	"grey-600":         color.RGBA{0x75, 0x75, 0x75, 0xff}, // rgb(117, 117, 117)
+	"grey-700":         color.RGBA{0x61, 0x61, 0x61, 0xff}, // rgb(97, 97, 97)
+	"grey-800":         color.RGBA{0x42, 0x42, 0x42, 0xff}, // rgb(66, 66, 66)
+	"grey-900":         color.RGBA{0x21, 0x21, 0x21, 0xff}, // rgb(33, 33, 33)
+	"indigo-100":       color.RGBA{0xc5, 0xca, 0xe9, 0xff}, // rgb(197, 202, 233)
+	"indigo-200":       color.RGBA{0x9f, 0xa8, 0xda, 0xff}, // rgb(159, 168, 218)
+	"indigo-300":       color.RGBA{0x79, 0x86, 0xcb, 0xff}, // rgb(121, 134, 203)
+	"indigo-400":       color.RGBA{0x5c, 0x6b, 0xc0, 0xff}, // rgb(92, 107, 192)
+	"indigo-50":        color.RGBA{0xe8, 0xea, 0xf6, 0xff}, // rgb(232, 234, 246)
+	"indigo-500":       color.RGBA{0x3f, 0x51, 0xb5, 0xff}, // rgb(63, 81, 181)
+	"indigo-600":       color.RGBA{0x39, 0x49, 0xab, 0xff}, // rgb(57, 73, 171)
+	"indigo-700":       color.RGBA{0x30, 0x3f, 0x9f, 0xff}, // rgb(48, 63, 159)
+	"indigo-800":       color.RGBA{0x28, 0x35, 0x93, 0xff}, // rgb(40, 53, 147)
+	"indigo-900":       color.RGBA{0x1a, 0x23, 0x7e, 0xff}, // rgb(26, 35, 126)
+	"indigo-a100":      color.RGBA{0x8c, 0x9e, 0xff, 0xff}, // rgb(140, 158, 255)
+	"indigo-a200":      color.RGBA{0x53, 0x6d, 0xfe, 0xff}, // rgb(83, 109, 254)
+	"indigo-a400":      color.RGBA{0x3d, 0x5a, 0xfe, 0xff}, // rgb(61, 90, 254)
+	"indigo-a700":      color.RGBA{0x30, 0x4f, 0xfe, 0xff}, // rgb(48, 79, 254)
+	"light-blue-100":   color.RGBA{0xb3, 0xe5, 0xfc, 0xff}, // rgb(179, 229, 252)
+	"light-blue-200":   color.RGBA{0x81, 0xd4, 0xfa, 0xff}, // rgb(129, 212, 250)
+	"light-blue-300":   color.RGBA{0x4f, 0xc3, 0xf7, 0xff}, // rgb(79, 195, 247)
+	"light-blue-400":   color.RGBA{0x29, 0xb6, 0xf6, 0xff}, // rgb(41, 182, 246)
+	"light-blue-50":    color.RGBA{0xe1, 0xf5, 0xfe, 0xff}, // rgb(225, 245, 254)
+	"light-blue-500":   color.RGBA{0x03, 0xa9, 0xf4, 0xff}, // rgb(3, 169, 244)
+	"light-blue-600":   color.RGBA{0x03, 0x9b, 0xe5, 0xff}, // rgb(3, 155, 229)
+	"light-blue-700":   color.RGBA{0x02, 0x88, 0xd1, 0xff}, // rgb(2, 136, 209)
+	"light-blue-800":   color.RGBA{0x02, 0x77, 0xbd, 0xff}, // rgb(2, 119, 189)
+	"light-blue-900":   color.RGBA{0x01, 0x57, 0x9b, 0xff}, // rgb(1, 87, 155)
+	"light-blue-a100":  color.RGBA{0x80, 0xd8, 0xff, 0xff}, // rgb(128, 216, 255)
+	"light-blue-a200":  color.RGBA{0x40, 0xc4, 0xff, 0xff}, // rgb(64, 196, 255)
+	"light-blue-a400":  color.RGBA{0x00, 0xb0, 0xff, 0xff}, // rgb(0, 176, 255)
+	"light-blue-a700":  color.RGBA{0x00, 0x91, 0xea, 0xff}, // rgb(0, 145, 234)
+	"light-green-100":  color.RGBA{0xdc, 0xed, 0xc8, 0xff}, // rgb(220, 237, 200)
+	"light-green-200":  color.RGBA{0xc5, 0xe1, 0xa5, 0xff}, // rgb(197, 225, 165)
+	"light-green-300":  color.RGBA{0xae, 0xd5, 0x81, 0xff}, // rgb(174, 213, 129)
+	"light-green-400":  color.RGBA{0x9c, 0xcc, 0x65, 0xff}, // rgb(156, 204, 101)
+	"light-green-50":   color.RGBA{0xf1, 0xf8, 0xe9, 0xff}, // rgb(241, 248, 233)
+	"light-green-500":  color.RGBA{0x8b, 0xc3, 0x4a, 0xff}, // rgb(139, 195, 74)
+	"light-green-600":  color.RGBA{0x7c, 0xb3, 0x42, 0xff}, // rgb(124, 179, 66)
+	"light-green-700":  color.RGBA{0x68, 0x9f, 0x38, 0xff}, // rgb(104, 159, 56)
+	"light-green-800":  color.RGBA{0x55, 0x8b, 0x2f, 0xff}, // rgb(85, 139, 47)
+	"light-green-900":  color.RGBA{0x33, 0x69, 0x1e, 0xff}, // rgb(51, 105, 30)
+	"light-green-a100": color.RGBA{0xcc, 0xff, 0x90, 0xff}, // rgb(204, 255, 144)
+	"light-green-a200": color.RGBA{0xb2, 0xff, 0x59, 0xff}, // rgb(178, 255, 89)
+	"light-green-a400": color.RGBA{0x76, 0xff, 0x03, 0xff}, // rgb(118, 255, 3)
+	"light-green-a700": color.RGBA{0x64, 0xdd, 0x17, 0xff}, // rgb(100, 221, 23)
+	"lime-100":         color.RGBA{0xf0, 0xf4, 0xc3, 0xff}, // rgb(240, 244, 195)
+	"lime-200":         color.RGBA{0xe6, 0xee, 0x9c, 0xff}, // rgb(230, 238, 156)
+	"lime-300":         color.RGBA{0xdc, 0xe7, 0x75, 0xff}, // rgb(220, 231, 117)
+	"lime-400":         color.RGBA{0xd4, 0xe1, 0x57, 0xff}, // rgb(212, 225, 87)
+	"lime-50":          color.RGBA{0xf9, 0xfb, 0xe7, 0xff}, // rgb(249, 251, 231)
+	"lime-500":         color.RGBA{0xcd, 0xdc, 0x39, 0xff}, // rgb(205, 220, 57)
+	"lime-600":         color.RGBA{0xc0, 0xca, 0x33, 0xff}, // rgb(192, 202, 51)
+	"lime-700":         color.RGBA{0xaf, 0xb4, 0x2b, 0xff}, // rgb(175, 180, 43)
+	"lime-800":         color.RGBA{0x9e, 0x9d, 0x24, 0xff}, // rgb(158, 157, 36)
+	"lime-900":         color.RGBA{0x82, 0x77, 0x17, 0xff}, // rgb(130, 119, 23)
+	"lime-a100":        color.RGBA{0xf4, 0xff, 0x81, 0xff}, // rgb(244, 255, 129)
+	"lime-a200":        color.RGBA{0xee, 0xff, 0x41, 0xff}, // rgb(238, 255, 65)
+	"lime-a400":        color.RGBA{0xc6, 0xff, 0x00, 0xff}, // rgb(198, 255, 0)
+	"lime-a700":        color.RGBA{0xae, 0xea, 0x00, 0xff}, // rgb(174, 234, 0)
+	"orange-100":       color.RGBA{0xff, 0xe0, 0xb2, 0xff}, // rgb(255, 224, 178)
+	"orange-200":       color.RGBA{0xff, 0xcc, 0x80, 0xff}, // rgb(255, 204, 128)
+	"orange-300":       color.RGBA{0xff, 0xb7, 0x4d, 0xff}, // rgb(255, 183, 77)
+	"orange-400":       color.RGBA{0xff, 0xa7, 0x26, 0xff}, // rgb(255, 167, 38)
+	"orange-50":        color.RGBA{0xff, 0xf3, 0xe0, 0xff}, // rgb(255, 243, 224)
+	"orange-500":       color.RGBA{0xff, 0x98, 0x00, 0xff}, // rgb(255, 152, 0)
+	"orange-600":       color.RGBA{0xfb, 0x8c, 0x00, 0xff}, // rgb(251, 140, 0)
+	"orange-700":       color.RGBA{0xf5, 0x7c, 0x00, 0xff}, // rgb(245, 124, 0)
+	"orange-800":       color.RGBA{0xef, 0x6c, 0x00, 0xff}, // rgb(239, 108, 0)
+	"orange-900":       color.RGBA{0xe6, 0x51, 0x00, 0xff}, // rgb(230, 81, 0)
+	"orange-a100":      color.RGBA{0xff, 0xd1, 0x80, 0xff}, // rgb(255, 209, 128)
+	"orange-a200":      color.RGBA{0xff, 0xab, 0x40, 0xff}, // rgb(255, 171, 64)
+	"orange-a400":      color.RGBA{0xff, 0x91, 0x00, 0xff}, // rgb(255, 145, 0)
+	"orange-a700":      color.RGBA{0xff, 0x6d, 0x00, 0xff}, // rgb(255, 109, 0)
+	"pink-100":         color.RGBA{0xf8, 0xbb, 0xd0, 0xff}, // rgb(248, 187, 208)
+	"pink-200":         color.RGBA{0xf4, 0x8f, 0xb1, 0xff}, // rgb(244, 143, 177)
+	"pink-300":         color.RGBA{0xf0, 0x62, 0x92, 0xff}, // rgb(240, 98, 146)
+	"pink-400":         color.RGBA{0xec, 0x40, 0x7a, 0xff}, // rgb(236, 64, 122)
+	"pink-50":          color.RGBA{0xfc, 0xe4, 0xec, 0xff}, // rgb(252, 228, 236)
+	"pink-500":         color.RGBA{0xe9, 0x1e, 0x63, 0xff}, // rgb(233, 30, 99)
+	"pink-600":         color.RGBA{0xd8, 0x1b, 0x60, 0xff}, // rgb(216, 27, 96)
+	"pink-700":         color.RGBA{0xc2, 0x18, 0x5b, 0xff}, // rgb(194, 24, 91)
+	"pink-800":         color.RGBA{0xad, 0x14, 0x57, 0xff}, // rgb(173, 20, 87)
+	"pink-900":         color.RGBA{0x88, 0x0e, 0x4f, 0xff}, // rgb(136, 14, 79)
+	"pink-a100":        color.RGBA{0xff, 0x80, 0xab, 0xff}, // rgb(255, 128, 171)
+	"pink-a200":        color.RGBA{0xff, 0x40, 0x81, 0xff}, // rgb(255, 64, 129)
+	"pink-a400":        color.RGBA{0xf5, 0x00, 0x57, 0xff}, // rgb(245, 0, 87)
+	"pink-a700":        color.RGBA{0xc5, 0x11, 0x62, 0xff}, // rgb(197, 17, 98)
+	"purple-100":       color.RGBA{0xe1, 0xbe, 0xe7, 0xff}, // rgb(225, 190, 231)
+	"purple-200":       color.RGBA{0xce, 0x93, 0xd8, 0xff}, // rgb(206, 147, 216)
+	"purple-300":       color.RGBA{0xba, 0x68, 0xc8, 0xff}, // rgb(186, 104, 200)
+	"purple-400":       color.RGBA{0xab, 0x47, 0xbc, 0xff}, // rgb(171, 71, 188)
+	"purple-50":        color.RGBA{0xf3, 0xe5, 0xf5, 0xff}, // rgb(243, 229, 245)
+	"purple-500":       color.RGBA{0x9c, 0x27, 0xb0, 0xff}, // rgb(156, 39, 176)
+	"purple-600":       color.RGBA{0x8e, 0x24, 0xaa, 0xff}, // rgb(142, 36, 170)
+	"purple-700":       color.RGBA{0x7b, 0x1f, 0xa2, 0xff}, // rgb(123, 31, 162)
+	"purple-800":       color.RGBA{0x6a, 0x1b, 0x9a, 0xff}, // rgb(106, 27, 154)
+	"purple-900":       color.RGBA{0x4a, 0x14, 0x8c, 0xff}, // rgb(74, 20, 140)
+	"purple-a100":      color.RGBA{0xea, 0x80, 0xfc, 0xff}, // rgb(234, 128, 252)
+	"purple-a200":      color.RGBA{0xe0, 0x40, 0xfb, 0xff}, // rgb(224, 64, 251)
+	"purple-a400":      color.RGBA{0xd5, 0x00, 0xf9, 0xff}, // rgb(213, 0, 249)
+	"purple-a700":      color.RGBA{0xaa, 0x00, 0xff, 0xff}, // rgb(170, 0, 255)
+	"red-100":          color.RGBA{0xff, 0xcd, 0xd2, 0xff}, // rgb(255, 205, 210)
+	"red-200":          color.RGBA{0xef, 0x9a, 0x9a, 0xff}, // rgb(239, 154, 154)
+	"red-300":          color.RGBA{0xe5, 0x73, 0x73, 0xff}, // rgb(229, 115, 115)
+	"red-400":          color.RGBA{0xef, 0x53, 0x50, 0xff}, // rgb(239, 83, 80)
+	"red-50":           color.RGBA{0xff, 0xeb, 0xee, 0xff}, // rgb(255, 235, 238)
+	"red-500":          color.RGBA{0xf4, 0x43, 0x36, 0xff}, // rgb(244, 67, 54)
+	"red-600":          color.RGBA{0xe5, 0x39, 0x35, 0xff}, // rgb(229, 57, 53)
+	"red-700":          color.RGBA{0xd3, 0x2f, 0x2f, 0xff}, // rgb(211, 47, 47)
+	"red-800":          color.RGBA{0xc6, 0x28, 0x28, 0xff}, // rgb(198, 40, 40)
+	"red-900":          color.RGBA{0xb7, 0x1c, 0x1c, 0xff}, // rgb(183, 28, 28)
+	"red-a100":         color.RGBA{0xff, 0x8a, 0x80, 0xff}, // rgb(255, 138, 128)
+	"red-a200":         color.RGBA{0xff, 0x52, 0x52, 0xff}, // rgb(255, 82, 82)
+	"red-a400":         color.RGBA{0xff, 0x17, 0x44, 0xff}, // rgb(255, 23, 68)
+	"red-a700":         color.RGBA{0xd5, 0x00, 0x00, 0xff}, // rgb(213, 0, 0)
+	"teal-100":         color.RGBA{0xb2, 0xdf, 0xdb, 0xff}, // rgb(178, 223, 219)
+	"teal-200":         color.RGBA{0x80, 0xcb, 0xc4, 0xff}, // rgb(128, 203, 196)
+	"teal-300":         color.RGBA{0x4d, 0xb6, 0xac, 0xff}, // rgb(77, 182, 172)
+	"teal-400":         color.RGBA{0x26, 0xa6, 0x9a, 0xff}, // rgb(38, 166, 154)
+	"teal-50":          color.RGBA{0xe0, 0xf2, 0xf1, 0xff}, // rgb(224, 242, 241)
+	"teal-500":         color.RGBA{0x00, 0x96, 0x88, 0xff}, // rgb(0, 150, 136)
+	"teal-600":         color.RGBA{0x00, 0x89, 0x7b, 0xff}, // rgb(0, 137, 123)
+	"teal-700":         color.RGBA{0x00, 0x79, 0x6b, 0xff}, // rgb(0, 121, 107)
+	"teal-800":         color.RGBA{0x00, 0x69, 0x5c, 0xff}, // rgb(0, 105, 92)
+	"teal-900":         color.RGBA{0x00, 0x4d, 0x40, 0xff}, // rgb(0, 77, 64)
+	"teal-a100":        color.RGBA{0xa7, 0xff, 0xeb, 0xff}, // rgb(167, 255, 235)
+	"teal-a200":        color.RGBA{0x64, 0xff, 0xda, 0xff}, // rgb(100, 255, 218)
+	"teal-a400":        color.RGBA{0x1d, 0xe9, 0xb6, 0xff}, // rgb(29, 233, 182)
+	"teal-a700":        color.RGBA{0x00, 0xbf, 0xa5, 0xff}, // rgb(0, 191, 165)
+	"white":            color.RGBA{0xff, 0xff, 0xff, 0xff}, // rgb(255, 255, 255)
+	"yellow-100":       color.RGBA{0xff, 0xf9, 0xc4, 0xff}, // rgb(255, 249, 196)
+	"yellow-200":       color.RGBA{0xff, 0xf5, 0x9d, 0xff}, // rgb(255, 245, 157)
+	"yellow-300":       color.RGBA{0xff, 0xf1, 0x76, 0xff}, // rgb(255, 241, 118)
+	"yellow-400":       color.RGBA{0xff, 0xee, 0x58, 0xff}, // rgb(255, 238, 88)
+	"yellow-50":        color.RGBA{0xff, 0xfd, 0xe7, 0xff}, // rgb(255, 253, 231)
+	"yellow-500":       color.RGBA{0xff, 0xeb, 0x3b, 0xff}, // rgb(255, 235, 59)
+	"yellow-600":       color.RGBA{0xfd, 0xd8, 0x35, 0xff}, // rgb(253, 216, 53)
+	"yellow-700":       color.RGBA{0xfb, 0xc0, 0x2d, 0xff}, // rgb(251, 192, 45)
+	"yellow-800":       color.RGBA{0xf9, 0xa8, 0x25, 0xff}, // rgb(249, 168, 37)
+	"yellow-900":       color.RGBA{0xf5, 0x7f, 0x17, 0xff}, // rgb(245, 127, 23)
+	"yellow-a100":      color.RGBA{0xff, 0xff, 0x8d, 0xff}, // rgb(255, 255, 141)
+	"yellow-a200":      color.RGBA{0xff, 0xff, 0x00, 0xff}, // rgb(255, 255, 0)
+	"yellow-a400":      color.RGBA{0xff, 0xea, 0x00, 0xff}, // rgb(255, 234, 0)
+	"yellow-a700":      color.RGBA{0xff, 0xd6, 0x00, 0xff}, // rgb(255, 214, 0)
+}
+
+// Names contains the color names defined in the Material Design color palette.
+var Names = []string{
+	"amber-100",
+	"amber-200",
+	"amber-300",
+	"amber-400",
+	"amber-50",
+	"amber-500",
+	"amber-600",
+	"amber-700",
+	"amber-800",
+	"amber-900",
+	"amber-a100",
+	"amber-a200",
+	"amber-a400",
+	"amber-a700",
+	"black",
+	"blue-100",
+	"blue-200",
+	"blue-300",
+	"blue-400",
+	"blue-50",
+	"blue-500",
+	"blue-600",
+	"blue-700",
+	"blue-800",
+	"blue-900",
+	"blue-a100",
+	"blue-a200",
+	"blue-a400",
+	"blue-a700",
+	"blue-grey-100",
+	"blue-grey-200",
+	"blue-grey-300",
+	"blue-grey-400",
+	"blue-grey-50",
+	"blue-grey-500",
+	"blue-grey-600",
+	"blue-grey-700",
+	"blue-grey-800",
+	"blue-grey-900",
+	"brown-100",
+	"brown-200",
+	"brown-300",
+	"brown-400",
+	"brown-50",
+	"brown-500",
+	"brown-600",
+	"brown-700",
+	"brown-800",
+	"brown-900",
+	"cyan-100",
+	"cyan-200",
+	"cyan-300",
+	"cyan-400",
+	"cyan-50",
+	"cyan-500",
+	"cyan-600",
+	"cyan-700",
+	"cyan-800",
+	"cyan-900",
+	"cyan-a100",
+	"cyan-a200",
+	"cyan-a400",
+	"cyan-a700",
+	"deep-orange-100",
+	"deep-orange-200",
+	"deep-orange-300",
+	"deep-orange-400",
+	"deep-orange-50",
+	"deep-orange-500",
+	"deep-orange-600",
+	"deep-orange-700",
+	"deep-orange-800",
+	"deep-orange-900",
+	"deep-orange-a100",
+	"deep-orange-a200",
+	"deep-orange-a400",
+	"deep-orange-a700",
+	"deep-purple-100",
+	"deep-purple-200",
+	"deep-purple-300",
+	"deep-purple-400",
+	"deep-purple-50",
+	"deep-purple-500",
+	"deep-purple-600",
+	"deep-purple-700",
+	"deep-purple-800",
+	"deep-purple-900",
+	"deep-purple-a100",
+	"deep-purple-a200",
+	"deep-purple-a400",
+	"deep-purple-a700",
+	"green-100",
+	"green-200",
+	"green-300",
+	"green-400",
+	"green-50",
+	"green-500",
+	"green-600",
+	"green-700",
+	"green-800",
+	"green-900",
+	"green-a100",
+	"green-a200",
+	"green-a400",
+	"green-a700",
+	"grey-100",
+	"grey-200",
+	"grey-300",
+	"grey-400",
+	"grey-50",
+	"grey-500",
+	"grey-600",
+	"grey-700",
+	"grey-800",
+	"grey-900",
+	"indigo-100",
+	"indigo-200",
+	"indigo-300",
+	"indigo-400",
+	"indigo-50",
+	"indigo-500",
+	"indigo-600",
+	"indigo-700",
+	"indigo-800",
+	"indigo-900",
+	"indigo-a100",
+	"indigo-a200",
+	"indigo-a400",
+	"indigo-a700",
+	"light-blue-100",
+	"light-blue-200",
+	"light-blue-300",
+	"light-blue-400",
+	"light-blue-50",
+	"light-blue-500",
+	"light-blue-600",
+	"light-blue-700",
+	"light-blue-800",
+	"light-blue-900",
+	"light-blue-a100",
+	"light-blue-a200",
+	"light-blue-a400",
+	"light-blue-a700",
+	"light-green-100",
+	"light-green-200",
+	"light-green-300",
+	"light-green-400",
+	"light-green-50",
+	"light-green-500",
+	"light-green-600",
+	"light-green-700",
+	"light-green-800",
+	"light-green-900",
+	"light-green-a100",
+	"light-green-a200",
+	"light-green-a400",
+	"light-green-a700",
+	"lime-100",
+	"lime-200",
+	"lime-300",
+	"lime-400",
+	"lime-50",
+	"lime-500",
+	"lime-600",
+	"lime-700",
+	"lime-800",
+	"lime-900",
+	"lime-a100",
+	"lime-a200",
+	"lime-a400",
+	"lime-a700",
+	"orange-100",
+	"orange-200",
+	"orange-300",
+	"orange-400",
+	"orange-50",
+	"orange-500",
+	"orange-600",
+	"orange-700",
+	"orange-800",
+	"orange-900",
+	"orange-a100",
+	"orange-a200",
+	"orange-a400",
+	"orange-a700",
+	"pink-100",
+	"pink-200",
+	"pink-300",
+	"pink-400",
+	"pink-50",
+	"pink-500",
+	"pink-600",
+	"pink-700",
+	"pink-800",
+	"pink-900",
+	"pink-a100",
+	"pink-a200",
+	"pink-a400",
+	"pink-a700",
+	"purple-100",
+	"purple-200",
+	"purple-300",
+	"purple-400",
+	"purple-50",
+	"purple-500",
+	"purple-600",
+	"purple-700",
+	"purple-800",
+	"purple-900",
+	"purple-a100",
+	"purple-a200",
+	"purple-a400",
+	"purple-a700",
+	"red-100",
+	"red-200",
+	"red-300",
+	"red-400",
+	"red-50",
+	"red-500",
+	"red-600",
+	"red-700",
+	"red-800",
+	"red-900",
+	"red-a100",
+	"red-a200",
+	"red-a400",
+	"red-a700",
+	"teal-100",
+	"teal-200",
+	"teal-300",
+	"teal-400",
+	"teal-50",
+	"teal-500",
+	"teal-600",
+	"teal-700",
+	"teal-800",
+	"teal-900",
+	"teal-a100",
+	"teal-a200",
+	"teal-a400",
+	"teal-a700",
+	"white",
+	"yellow-100",
+	"yellow-200",
+	"yellow-300",
+	"yellow-400",
+	"yellow-50",
+	"yellow-500",
+	"yellow-600",
+	"yellow-700",
+	"yellow-800",
+	"yellow-900",
+	"yellow-a100",
+	"yellow-a200",
+	"yellow-a400",
+	"yellow-a700",
+}
+
+var (
+	Amber100       = color.RGBA{0xff, 0xec, 0xb3, 0xff} // rgb(255, 236, 179)
+	Amber200       = color.RGBA{0xff, 0xe0, 0x82, 0xff} // rgb(255, 224, 130)
+	Amber300       = color.RGBA{0xff, 0xd5, 0x4f, 0xff} // rgb(255, 213, 79)
+	Amber400       = color.RGBA{0xff, 0xca, 0x28, 0xff} // rgb(255, 202, 40)
+	Amber50        = color.RGBA{0xff, 0xf8, 0xe1, 0xff} // rgb(255, 248, 225)
+	Amber500       = color.RGBA{0xff, 0xc1, 0x07, 0xff} // rgb(255, 193, 7)
+	Amber600       = color.RGBA{0xff, 0xb3, 0x00, 0xff} // rgb(255, 179, 0)
+	Amber700       = color.RGBA{0xff, 0xa0, 0x00, 0xff} // rgb(255, 160, 0)
+	Amber800       = color.RGBA{0xff, 0x8f, 0x00, 0xff} // rgb(255, 143, 0)
+	Amber900       = color.RGBA{0xff, 0x6f, 0x00, 0xff} // rgb(255, 111, 0)
+	AmberA100      = color.RGBA{0xff, 0xe5, 0x7f, 0xff} // rgb(255, 229, 127)
+	AmberA200      = color.RGBA{0xff, 0xd7, 0x40, 0xff} // rgb(255, 215, 64)
+	AmberA400      = color.RGBA{0xff, 0xc4, 0x00, 0xff} // rgb(255, 196, 0)
+	AmberA700      = color.RGBA{0xff, 0xab, 0x00, 0xff} // rgb(255, 171, 0)
+	Black          = color.RGBA{0x00, 0x00, 0x00, 0xff} // rgb(0, 0, 0)
+	Blue100        = color.RGBA{0xbb, 0xde, 0xfb, 0xff} // rgb(187, 222, 251)
+	Blue200        = color.RGBA{0x90, 0xca, 0xf9, 0xff} // rgb(144, 202, 249)
+	Blue300        = color.RGBA{0x64, 0xb5, 0xf6, 0xff} // rgb(100, 181, 246)
+	Blue400        = color.RGBA{0x42, 0xa5, 0xf5, 0xff} // rgb(66, 165, 245)
+	Blue50         = color.RGBA{0xe3, 0xf2, 0xfd, 0xff} // rgb(227, 242, 253)
+	Blue500        = color.RGBA{0x21, 0x96, 0xf3, 0xff} // rgb(33, 150, 243)
+	Blue600        = color.RGBA{0x1e, 0x88, 0xe5, 0xff} // rgb(30, 136, 229)
+	Blue700        = color.RGBA{0x19, 0x76, 0xd2, 0xff} // rgb(25, 118, 210)
+	Blue800        = color.RGBA{0x15, 0x65, 0xc0, 0xff} // rgb(21, 101, 192)
+	Blue900        = color.RGBA{0x0d, 0x47, 0xa1, 0xff} // rgb(13, 71, 161)
+	BlueA100       = color.RGBA{0x82, 0xb1, 0xff, 0xff} // rgb(130, 177, 255)
+	BlueA200       = color.RGBA{0x44, 0x8a, 0xff, 0xff} // rgb(68, 138, 255)
+	BlueA400       = color.RGBA{0x29, 0x79, 0xff, 0xff} // rgb(41, 121, 255)
+	BlueA700       = color.RGBA{0x29, 0x62, 0xff, 0xff} // rgb(41, 98, 255)
+	BlueGrey100    = color.RGBA{0xcf, 0xd8, 0xdc, 0xff} // rgb(207, 216, 220)
+	BlueGrey200    = color.RGBA{0xb0, 0xbe, 0xc5, 0xff} // rgb(176, 190, 197)
+	BlueGrey300    = color.RGBA{0x90, 0xa4, 0xae, 0xff} // rgb(144, 164, 174)
+	BlueGrey400    = color.RGBA{0x78, 0x90, 0x9c, 0xff} // rgb(120, 144, 156)
+	BlueGrey50     = color.RGBA{0xec, 0xef, 0xf1, 0xff} // rgb(236, 239, 241)
+	BlueGrey500    = color.RGBA{0x60, 0x7d, 0x8b, 0xff} // rgb(96, 125, 139)
+	BlueGrey600    = color.RGBA{0x54, 0x6e, 0x7a, 0xff} // rgb(84, 110, 122)
+	BlueGrey700    = color.RGBA{0x45, 0x5a, 0x64, 0xff} // rgb(69, 90, 100)
+	BlueGrey800    = color.RGBA{0x37, 0x47, 0x4f, 0xff} // rgb(55, 71, 79)
+	BlueGrey900    = color.RGBA{0x26, 0x32, 0x38, 0xff} // rgb(38, 50, 56)
+	Brown100       = color.RGBA{0xd7, 0xcc, 0xc8, 0xff} // rgb(215, 204, 200)
+	Brown200       = color.RGBA{0xbc, 0xaa, 0xa4, 0xff} // rgb(188, 170, 164)
+	Brown300       = color.RGBA{0xa1, 0x88, 0x7f, 0xff} // rgb(161, 136, 127)
+	Brown400       = color.RGBA{0x8d, 0x6e, 0x63, 0xff} // rgb(141, 110, 99)
+	Brown50        = color.RGBA{0xef, 0xeb, 0xe9, 0xff} // rgb(239, 235, 233)
+	Brown500       = color.RGBA{0x79, 0x55, 0x48, 0xff} // rgb(121, 85, 72)
+	Brown600       = color.RGBA{0x6d, 0x4c, 0x41, 0xff} // rgb(109, 76, 65)
+	Brown700       = color.RGBA{0x5d, 0x40, 0x37, 0xff} // rgb(93, 64, 55)
+	Brown800       = color.RGBA{0x4e, 0x34, 0x2e, 0xff} // rgb(78, 52, 46)
+	Brown900       = color.RGBA{0x3e, 0x27, 0x23, 0xff} // rgb(62, 39, 35)
+	Cyan100        = color.RGBA{0xb2, 0xeb, 0xf2, 0xff} // rgb(178, 235, 242)
+	Cyan200        = color.RGBA{0x80, 0xde, 0xea, 0xff} // rgb(128, 222, 234)
+	Cyan300        = color.RGBA{0x4d, 0xd0, 0xe1, 0xff} // rgb(77, 208, 225)
+	Cyan400        = color.RGBA{0x26, 0xc6, 0xda, 0xff} // rgb(38, 198, 218)
+	Cyan50         = color.RGBA{0xe0, 0xf7, 0xfa, 0xff} // rgb(224, 247, 250)
+	Cyan500        = color.RGBA{0x00, 0xbc, 0xd4, 0xff} // rgb(0, 188, 212)
+	Cyan600        = color.RGBA{0x00, 0xac, 0xc1, 0xff} // rgb(0, 172, 193)
+	Cyan700        = color.RGBA{0x00, 0x97, 0xa7, 0xff} // rgb(0, 151, 167)
+	Cyan800        = color.RGBA{0x00, 0x83, 0x8f, 0xff} // rgb(0, 131, 143)
+	Cyan900        = color.RGBA{0x00, 0x60, 0x64, 0xff} // rgb(0, 96, 100)
+	CyanA100       = color.RGBA{0x84, 0xff, 0xff, 0xff} // rgb(132, 255, 255)
+	CyanA200       = color.RGBA{0x18, 0xff, 0xff, 0xff} // rgb(24, 255, 255)
+	CyanA400       = color.RGBA{0x00, 0xe5, 0xff, 0xff} // rgb(0, 229, 255)
+	CyanA700       = color.RGBA{0x00, 0xb8, 0xd4, 0xff} // rgb(0, 184, 212)
+	DeepOrange100  = color.RGBA{0xff, 0xcc, 0xbc, 0xff} // rgb(255, 204, 188)
+	DeepOrange200  = color.RGBA{0xff, 0xab, 0x91, 0xff} // rgb(255, 171, 145)
+	DeepOrange300  = color.RGBA{0xff, 0x8a, 0x65, 0xff} // rgb(255, 138, 101)
+	DeepOrange400  = color.RGBA{0xff, 0x70, 0x43, 0xff} // rgb(255, 112, 67)
+	DeepOrange50   = color.RGBA{0xfb, 0xe9, 0xe7, 0xff} // rgb(251, 233, 231)
+	DeepOrange500  = color.RGBA{0xff, 0x57, 0x22, 0xff} // rgb(255, 87, 34)
+	DeepOrange600  = color.RGBA{0xf4, 0x51, 0x1e, 0xff} // rgb(244, 81, 30)
+	DeepOrange700  = color.RGBA{0xe6, 0x4a, 0x19, 0xff} // rgb(230, 74, 25)
+	DeepOrange800  = color.RGBA{0xd8, 0x43, 0x15, 0xff} // rgb(216, 67, 21)
+	DeepOrange900  = color.RGBA{0xbf, 0x36, 0x0c, 0xff} // rgb(191, 54, 12)
+	DeepOrangeA100 = color.RGBA{0xff, 0x9e, 0x80, 0xff} // rgb(255, 158, 128)
+	DeepOrangeA200 = color.RGBA{0xff, 0x6e, 0x40, 0xff} // rgb(255, 110, 64)
+	DeepOrangeA400 = color.RGBA{0xff, 0x3d, 0x00, 0xff} // rgb(255, 61, 0)
+	DeepOrangeA700 = color.RGBA{0xdd, 0x2c, 0x00, 0xff} // rgb(221, 44, 0)
+	DeepPurple100  = color.RGBA{0xd1, 0xc4, 0xe9, 0xff} // rgb(209, 196, 233)
+	DeepPurple200  = color.RGBA{0xb3, 0x9d, 0xdb, 0xff} // rgb(179, 157, 219)
+	DeepPurple300  = color.RGBA{0x95, 0x75, 0xcd, 0xff} // rgb(149, 117, 205)
+	DeepPurple400  = color.RGBA{0x7e, 0x57, 0xc2, 0xff} // rgb(126, 87, 194)
+	DeepPurple50   = color.RGBA{0xed, 0xe7, 0xf6, 0xff} // rgb(237, 231, 246)
+	DeepPurple500  = color.RGBA{0x67, 0x3a, 0xb7, 0xff} // rgb(103, 58, 183)
+	DeepPurple600  = color.RGBA{0x5e, 0x35, 0xb1, 0xff} // rgb(94, 53, 177)
+	DeepPurple700  = color.RGBA{0x51, 0x2d, 0xa8, 0xff} // rgb(81, 45, 168)
+	DeepPurple800  = color.RGBA{0x45, 0x27, 0xa0, 0xff} // rgb(69, 39, 160)
+	DeepPurple900  = color.RGBA{0x31, 0x1b, 0x92, 0xff} // rgb(49, 27, 146)
+	DeepPurpleA100 = color.RGBA{0xb3, 0x88, 0xff, 0xff} // rgb(179, 136, 255)
+	DeepPurpleA200 = color.RGBA{0x7c, 0x4d, 0xff, 0xff} // rgb(124, 77, 255)
+	DeepPurpleA400 = color.RGBA{0x65, 0x1f, 0xff, 0xff} // rgb(101, 31, 255)
+	DeepPurpleA700 = color.RGBA{0x62, 0x00, 0xea, 0xff} // rgb(98, 0, 234)
+	Green100       = color.RGBA{0xc8, 0xe6, 0xc9, 0xff} // rgb(200, 230, 201)
+	Green200       = color.RGBA{0xa5, 0xd6, 0xa7, 0xff} // rgb(165, 214, 167)
+	Green300       = color.RGBA{0x81, 0xc7, 0x84, 0xff} // rgb(129, 199, 132)
+	Green400       = color.RGBA{0x66, 0xbb, 0x6a, 0xff} // rgb(102, 187, 106)
+	Green50        = color.RGBA{0xe8, 0xf5, 0xe9, 0xff} // rgb(232, 245, 233)
+	Green500       = color.RGBA{0x4c, 0xaf, 0x50, 0xff} // rgb(76, 175, 80)
+	Green600       = color.RGBA{0x43, 0xa0, 0x47, 0xff} // rgb(67, 160, 71)
+	Green700       = color.RGBA{0x38, 0x8e, 0x3c, 0xff} // rgb(56, 142, 60)
+	Green800       = color.RGBA{0x2e, 0x7d, 0x32, 0xff} // rgb(46, 125, 50)
+	Green900       = color.RGBA{0x1b, 0x5e, 0x20, 0xff} // rgb(27, 94, 32)
+	GreenA100      = color.RGBA{0xb9, 0xf6, 0xca, 0xff} // rgb(185, 246, 202)
+	GreenA200      = color.RGBA{0x69, 0xf0, 0xae, 0xff} // rgb(105, 240, 174)
+	GreenA400      = color.RGBA{0x00, 0xe6, 0x76, 0xff} // rgb(0, 230, 118)
+	GreenA700      = color.RGBA{0x00, 0xc8, 0x53, 0xff} // rgb(0, 200, 83)
+	Grey100        = color.RGBA{0xf5, 0xf5, 0xf5, 0xff} // rgb(245, 245, 245)
+	Grey200        = color.RGBA{0xee, 0xee, 0xee, 0xff} // rgb(238, 238, 238)
+	Grey300        = color.RGBA{0xe0, 0xe0, 0xe0, 0xff} // rgb(224, 224, 224)
+	Grey400        = color.RGBA{0xbd, 0xbd, 0xbd, 0xff} // rgb(189, 189, 189)
+	Grey50         = color.RGBA{0xfa, 0xfa, 0xfa, 0xff} // rgb(250, 250, 250)
+	Grey500        = color.RGBA{0x9e, 0x9e, 0x9e, 0xff} // rgb(158, 158, 158)
+	Grey600        = color.RGBA{0x75, 0x75, 0x75, 0xff} // rgb(117, 117, 117)
+	Grey700        = color.RGBA{0x61, 0x61, 0x61, 0xff} // rgb(97, 97, 97)
+	Grey800        = color.RGBA{0x42, 0x42, 0x42, 0xff} // rgb(66, 66, 66)
+	Grey900        = color.RGBA{0x21, 0x21, 0x21, 0xff} // rgb(33, 33, 33)
+	Indigo100      = color.RGBA{0xc5, 0xca, 0xe9, 0xff} // rgb(197, 202, 233)
+	Indigo200      = color.RGBA{0x9f, 0xa8, 0xda, 0xff} // rgb(159, 168, 218)
+	Indigo300      = color.RGBA{0x79, 0x86, 0xcb, 0xff} // rgb(121, 134, 203)
+	Indigo400      = color.RGBA{0x5c, 0x6b, 0xc0, 0xff} // rgb(92, 107, 192)
+	Indigo50       = color.RGBA{0xe8, 0xea, 0xf6, 0xff} // rgb(232, 234, 246)
+	Indigo500      = color.RGBA{0x3f, 0x51, 0xb5, 0xff} // rgb(63, 81, 181)
+	Indigo600      = color.RGBA{0x39, 0x49, 0xab, 0xff} // rgb(57, 73, 171)
+	Indigo700      = color.RGBA{0x30, 0x3f, 0x9f, 0xff} // rgb(48, 63, 159)
+	Indigo800      = color.RGBA{0x28, 0x35, 0x93, 0xff} // rgb(40, 53, 147)
+	Indigo900      = color.RGBA{0x1a, 0x23, 0x7e, 0xff} // rgb(26, 35, 126)
+	IndigoA100     = color.RGBA{0x8c, 0x9e, 0xff, 0xff} // rgb(140, 158, 255)
+	IndigoA200     = color.RGBA{0x53, 0x6d, 0xfe, 0xff} // rgb(83, 109, 254)
+	IndigoA400     = color.RGBA{0x3d, 0x5a, 0xfe, 0xff} // rgb(61, 90, 254)
+	IndigoA700     = color.RGBA{0x30, 0x4f, 0xfe, 0xff} // rgb(48, 79, 254)
+	LightBlue100   = color.RGBA{0xb3, 0xe5, 0xfc, 0xff} // rgb(179, 229, 252)
+	LightBlue200   = color.RGBA{0x81, 0xd4, 0xfa, 0xff} // rgb(129, 212, 250)
+	LightBlue300   = color.RGBA{0x4f, 0xc3, 0xf7, 0xff} // rgb(79, 195, 247)
+	LightBlue400   = color.RGBA{0x29, 0xb6, 0xf6, 0xff} // rgb(41, 182, 246)
+	LightBlue50    = color.RGBA{0xe1, 0xf5, 0xfe, 0xff} // rgb(225, 245, 254)
+	LightBlue500   = color.RGBA{0x03, 0xa9, 0xf4, 0xff} // rgb(3, 169, 244)
+	LightBlue600   = color.RGBA{0x03, 0x9b, 0xe5, 0xff} // rgb(3, 155, 229)
+	LightBlue700   = color.RGBA{0x02, 0x88, 0xd1, 0xff} // rgb(2, 136, 209)
+	LightBlue800   = color.RGBA{0x02, 0x77, 0xbd, 0xff} // rgb(2, 119, 189)
+	LightBlue900   = color.RGBA{0x01, 0x57, 0x9b, 0xff} // rgb(1, 87, 155)
+	LightBlueA100  = color.RGBA{0x80, 0xd8, 0xff, 0xff} // rgb(128, 216, 255)
+	LightBlueA200  = color.RGBA{0x40, 0xc4, 0xff, 0xff} // rgb(64, 196, 255)
+	LightBlueA400  = color.RGBA{0x00, 0xb0, 0xff, 0xff} // rgb(0, 176, 255)
+	LightBlueA700  = color.RGBA{0x00, 0x91, 0xea, 0xff} // rgb(0, 145, 234)
+	LightGreen100  = color.RGBA{0xdc, 0xed, 0xc8, 0xff} // rgb(220, 237, 200)
+	LightGreen200  = color.RGBA{0xc5, 0xe1, 0xa5, 0xff} // rgb(197, 225, 165)
+	LightGreen300  = color.RGBA{0xae, 0xd5, 0x81, 0xff} // rgb(174, 213, 129)
+	LightGreen400  = color.RGBA{0x9c, 0xcc, 0x65, 0xff} // rgb(156, 204, 101)
+	LightGreen50   = color.RGBA{0xf1, 0xf8, 0xe9, 0xff} // rgb(241, 248, 233)
+	LightGreen500  = color.RGBA{0x8b, 0xc3, 0x4a, 0xff} // rgb(139, 195, 74)
+	LightGreen600  = color.RGBA{0x7c, 0xb3, 0x42, 0xff} // rgb(124, 179, 66)
+	LightGreen700  = color.RGBA{0x68, 0x9f, 0x38, 0xff} // rgb(104, 159, 56)
+	LightGreen800  = color.RGBA{0x55, 0x8b, 0x2f, 0xff} // rgb(85, 139, 47)
+	LightGreen900  = color.RGBA{0x33, 0x69, 0x1e, 0xff} // rgb(51, 105, 30)
+	LightGreenA100 = color.RGBA{0xcc, 0xff, 0x90, 0xff} // rgb(204, 255, 144)
+	LightGreenA200 = color.RGBA{0xb2, 0xff, 0x59, 0xff} // rgb(178, 255, 89)
+	LightGreenA400 = color.RGBA{0x76, 0xff, 0x03, 0xff} // rgb(118, 255, 3)
+	LightGreenA700 = color.RGBA{0x64, 0xdd, 0x17, 0xff} // rgb(100, 221, 23)
+	Lime100        = color.RGBA{0xf0, 0xf4, 0xc3, 0xff} // rgb(240, 244, 195)
+	Lime200        = color.RGBA{0xe6, 0xee, 0x9c, 0xff} // rgb(230, 238, 156)
+	Lime300        = color.RGBA{0xdc, 0xe7, 0x75, 0xff} // rgb(220, 231, 117)
+	Lime400        = color.RGBA{0xd4, 0xe1, 0x57, 0xff} // rgb(212, 225, 87)
+	Lime50         = color.RGBA{0xf9, 0xfb, 0xe7, 0xff} // rgb(249, 251, 231)
+	Lime500        = color.RGBA{0xcd, 0xdc, 0x39, 0xff} // rgb(205, 220, 57)
+	Lime600        = color.RGBA{0xc0, 0xca, 0x33, 0xff} // rgb(192, 202, 51)
+	Lime700        = color.RGBA{0xaf, 0xb4, 0x2b, 0xff} // rgb(175, 180, 43)
+	Lime800        = color.RGBA{0x9e, 0x9d, 0x24, 0xff} // rgb(158, 157, 36)
+	Lime900        = color.RGBA{0x82, 0x77, 0x17, 0xff} // rgb(130, 119, 23)
+	LimeA100       = color.RGBA{0xf4, 0xff, 0x81, 0xff} // rgb(244, 255, 129)
+	LimeA200       = color.RGBA{0xee, 0xff, 0x41, 0xff} // rgb(238, 255, 65)
+	LimeA400       = color.RGBA{0xc6, 0xff, 0x00, 0xff} // rgb(198, 255, 0)
+	LimeA700       = color.RGBA{0xae, 0xea, 0x00, 0xff} // rgb(174, 234, 0)
+	Orange100      = color.RGBA{0xff, 0xe0, 0xb2, 0xff} // rgb(255, 224, 178)
+	Orange200      = color.RGBA{0xff, 0xcc, 0x80, 0xff} // rgb(255, 204, 128)
+	Orange300      = color.RGBA{0xff, 0xb7, 0x4d, 0xff} // rgb(255, 183, 77)
+	Orange400      = color.RGBA{0xff, 0xa7, 0x26, 0xff} // rgb(255, 167, 38)
+	Orange50       = color.RGBA{0xff, 0xf3, 0xe0, 0xff} // rgb(255, 243, 224)
+	Orange500      = color.RGBA{0xff, 0x98, 0x00, 0xff} // rgb(255, 152, 0)
+	Orange600      = color.RGBA{0xfb, 0x8c, 0x00, 0xff} // rgb(251, 140, 0)
+	Orange700      = color.RGBA{0xf5, 0x7c, 0x00, 0xff} // rgb(245, 124, 0)
+	Orange800      = color.RGBA{0xef, 0x6c, 0x00, 0xff} // rgb(239, 108, 0)
+	Orange900      = color.RGBA{0xe6, 0x51, 0x00, 0xff} // rgb(230, 81, 0)
+	OrangeA100     = color.RGBA{0xff, 0xd1, 0x80, 0xff} // rgb(255, 209, 128)
+	OrangeA200     = color.RGBA{0xff, 0xab, 0x40, 0xff} // rgb(255, 171, 64)
+	OrangeA400     = color.RGBA{0xff, 0x91, 0x00, 0xff} // rgb(255, 145, 0)
+	OrangeA700     = color.RGBA{0xff, 0x6d, 0x00, 0xff} // rgb(255, 109, 0)
+	Pink100        = color.RGBA{0xf8, 0xbb, 0xd0, 0xff} // rgb(248, 187, 208)
+	Pink200        = color.RGBA{0xf4, 0x8f, 0xb1, 0xff} // rgb(244, 143, 177)
+	Pink300        = color.RGBA{0xf0, 0x62, 0x92, 0xff} // rgb(240, 98, 146)
+	Pink400        = color.RGBA{0xec, 0x40, 0x7a, 0xff} // rgb(236, 64, 122)
+	Pink50         = color.RGBA{0xfc, 0xe4, 0xec, 0xff} // rgb(252, 228, 236)
+	Pink500        = color.RGBA{0xe9, 0x1e, 0x63, 0xff} // rgb(233, 30, 99)
+	Pink600        = color.RGBA{0xd8, 0x1b, 0x60, 0xff} // rgb(216, 27, 96)
+	Pink700        = color.RGBA{0xc2, 0x18, 0x5b, 0xff} // rgb(194, 24, 91)
+	Pink800        = color.RGBA{0xad, 0x14, 0x57, 0xff} // rgb(173, 20, 87)
+	Pink900        = color.RGBA{0x88, 0x0e, 0x4f, 0xff} // rgb(136, 14, 79)
+	PinkA100       = color.RGBA{0xff, 0x80, 0xab, 0xff} // rgb(255, 128, 171)
+	PinkA200       = color.RGBA{0xff, 0x40, 0x81, 0xff} // rgb(255, 64, 129)
+	PinkA400       = color.RGBA{0xf5, 0x00, 0x57, 0xff} // rgb(245, 0, 87)
+	PinkA700       = color.RGBA{0xc5, 0x11, 0x62, 0xff} // rgb(197, 17, 98)
+	Purple100      = color.RGBA{0xe1, 0xbe, 0xe7, 0xff} // rgb(225, 190, 231)
+	Purple200      = color.RGBA{0xce, 0x93, 0xd8, 0xff} // rgb(206, 147, 216)
+	Purple300      = color.RGBA{0xba, 0x68, 0xc8, 0xff} // rgb(186, 104, 200)
+	Purple400      = color.RGBA{0xab, 0x47, 0xbc, 0xff} // rgb(171, 71, 188)
+	Purple50       = color.RGBA{0xf3, 0xe5, 0xf5, 0xff} // rgb(243, 229, 245)
+	Purple500      = color.RGBA{0x9c, 0x27, 0xb0, 0xff} // rgb(156, 39, 176)
+	Purple600      = color.RGBA{0x8e, 0x24, 0xaa, 0xff} // rgb(142, 36, 170)
+	Purple700      = color.RGBA{0x7b, 0x1f, 0xa2, 0xff} // rgb(123, 31, 162)
+	Purple800      = color.RGBA{0x6a, 0x1b, 0x9a, 0xff} // rgb(106, 27, 154)
+	Purple900      = color.RGBA{0x4a, 0x14, 0x8c, 0xff} // rgb(74, 20, 140)
+	PurpleA100     = color.RGBA{0xea, 0x80, 0xfc, 0xff} // rgb(234, 128, 252)
+	PurpleA200     = color.RGBA{0xe0, 0x40, 0xfb, 0xff} // rgb(224, 64, 251)
+	PurpleA400     = color.RGBA{0xd5, 0x00, 0xf9, 0xff} // rgb(213, 0, 249)
+	PurpleA700     = color.RGBA{0xaa, 0x00, 0xff, 0xff} // rgb(170, 0, 255)
+	Red100         = color.RGBA{0xff, 0xcd, 0xd2, 0xff} // rgb(255, 205, 210)
+	Red200         = color.RGBA{0xef, 0x9a, 0x9a, 0xff} // rgb(239, 154, 154)
+	Red300         = color.RGBA{0xe5, 0x73, 0x73, 0xff} // rgb(229, 115, 115)
+	Red400         = color.RGBA{0xef, 0x53, 0x50, 0xff} // rgb(239, 83, 80)
+	Red50          = color.RGBA{0xff, 0xeb, 0xee, 0xff} // rgb(255, 235, 238)
+	Red500         = color.RGBA{0xf4, 0x43, 0x36, 0xff} // rgb(244, 67, 54)
+	Red600         = color.RGBA{0xe5, 0x39, 0x35, 0xff} // rgb(229, 57, 53)
+	Red700         = color.RGBA{0xd3, 0x2f, 0x2f, 0xff} // rgb(211, 47, 47)
+	Red800         = color.RGBA{0xc6, 0x28, 0x28, 0xff} // rgb(198, 40, 40)
+	Red900         = color.RGBA{0xb7, 0x1c, 0x1c, 0xff} // rgb(183, 28, 28)
+	RedA100        = color.RGBA{0xff, 0x8a, 0x80, 0xff} // rgb(255, 138, 128)
+	RedA200        = color.RGBA{0xff, 0x52, 0x52, 0xff} // rgb(255, 82, 82)
+	RedA400        = color.RGBA{0xff, 0x17, 0x44, 0xff} // rgb(255, 23, 68)
+	RedA700        = color.RGBA{0xd5, 0x00, 0x00, 0xff} // rgb(213, 0, 0)
+	Teal100        = color.RGBA{0xb2, 0xdf, 0xdb, 0xff} // rgb(178, 223, 219)
+	Teal200        = color.RGBA{0x80, 0xcb, 0xc4, 0xff} // rgb(128, 203, 196)
+	Teal300        = color.RGBA{0x4d, 0xb6, 0xac, 0xff} // rgb(77, 182, 172)
+	Teal400        = color.RGBA{0x26, 0xa6, 0x9a, 0xff} // rgb(38, 166, 154)
+	Teal50         = color.RGBA{0xe0, 0xf2, 0xf1, 0xff} // rgb(224, 242, 241)
+	Teal500        = color.RGBA{0x00, 0x96, 0x88, 0xff} // rgb(0, 150, 136)
+	Teal600        = color.RGBA{0x00, 0x89, 0x7b, 0xff} // rgb(0, 137, 123)
+	Teal700        = color.RGBA{0x00, 0x79, 0x6b, 0xff} // rgb(0, 121, 107)
+	Teal800        = color.RGBA{0x00, 0x69, 0x5c, 0xff} // rgb(0, 105, 92)
+	Teal900        = color.RGBA{0x00, 0x4d, 0x40, 0xff} // rgb(0, 77, 64)
+	TealA100       = color.RGBA{0xa7, 0xff, 0xeb, 0xff} // rgb(167, 255, 235)
+	TealA200       = color.RGBA{0x64, 0xff, 0xda, 0xff} // rgb(100, 255, 218)
+	TealA400       = color.RGBA{0x1d, 0xe9, 0xb6, 0xff} // rgb(29, 233, 182)
+	TealA700       = color.RGBA{0x00, 0xbf, 0xa5, 0xff} // rgb(0, 191, 165)
+	White          = color.RGBA{0xff, 0xff, 0xff, 0xff} // rgb(255, 255, 255)
+	Yellow100      = color.RGBA{0xff, 0xf9, 0xc4, 0xff} // rgb(255, 249, 196)
+	Yellow200      = color.RGBA{0xff, 0xf5, 0x9d, 0xff} // rgb(255, 245, 157)
+	Yellow300      = color.RGBA{0xff, 0xf1, 0x76, 0xff} // rgb(255, 241, 118)
+	Yellow400      = color.RGBA{0xff, 0xee, 0x58, 0xff} // rgb(255, 238, 88)
+	Yellow50       = color.RGBA{0xff, 0xfd, 0xe7, 0xff} // rgb(255, 253, 231)
+	Yellow500      = color.RGBA{0xff, 0xeb, 0x3b, 0xff} // rgb(255, 235, 59)
+	Yellow600      = color.RGBA{0xfd, 0xd8, 0x35, 0xff} // rgb(253, 216, 53)
+	Yellow700      = color.RGBA{0xfb, 0xc0, 0x2d, 0xff} // rgb(251, 192, 45)
+	Yellow800      = color.RGBA{0xf9, 0xa8, 0x25, 0xff} // rgb(249, 168, 37)
+	Yellow900      = color.RGBA{0xf5, 0x7f, 0x17, 0xff} // rgb(245, 127, 23)
+	YellowA100     = color.RGBA{0xff, 0xff, 0x8d, 0xff} // rgb(255, 255, 141)
+	YellowA200     = color.RGBA{0xff, 0xff, 0x00, 0xff} // rgb(255, 255, 0)
+	YellowA400     = color.RGBA{0xff, 0xea, 0x00, 0xff} // rgb(255, 234, 0)
+	YellowA700     = color.RGBA{0xff, 0xd6, 0x00, 0xff} // rgb(255, 214, 0)
+)
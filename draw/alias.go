@@ -0,0 +1,175 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package draw
+
+import (
+	"image"
+	"reflect"
+)
+
+// unalias returns src, or a copy of the sr part of src if writing to the r
+// part of dst could change the src pixels that are read, such as when dst and
+// src are the same image or sub-images of the same image. The copy has the
+// same bounds and coordinates, so that sr still applies to it.
+//
+// Scaling and transforming can read each src pixel more than once, and not in
+// the order that dst pixels are written, so unlike drawing an image onto
+// itself, processing the pixels in a different order would not be enough.
+func unalias(dst Image, r image.Rectangle, src image.Image, sr image.Rectangle) image.Image {
+	sr = sr.Intersect(src.Bounds())
+	r = r.Intersect(dst.Bounds())
+	if sr.Empty() || r.Empty() {
+		return src
+	}
+	if d, ok := pixSpan(dst, r); ok {
+		if s, ok := pixSpan(src, sr); !ok || !d.overlaps(s) {
+			return src
+		}
+	} else if !sameImage(dst, src) || !r.Overlaps(sr) {
+		return src
+	}
+	return cloneRect(src, sr)
+}
+
+// sameImage reports whether dst and src are the same image, when their
+// pixels cannot be inspected directly.
+func sameImage(dst Image, src image.Image) bool {
+	t := reflect.TypeOf(src)
+	// Comparing the interface values panics unless their dynamic type is
+	// comparable.
+	return reflect.TypeOf(dst) == t && t.Comparable() && image.Image(dst) == src
+}
+
+// pixels returns the Pix, Stride and bytes per pixel of m, for the image
+// types whose pixels are in a single slice.
+func pixels(m image.Image) (pix []uint8, stride, bpp int, ok bool) {
+	switch m := m.(type) {
+	case *image.Alpha:
+		return m.Pix, m.Stride, 1, true
+	case *image.Alpha16:
+		return m.Pix, m.Stride, 2, true
+	case *image.CMYK:
+		return m.Pix, m.Stride, 4, true
+	case *image.Gray:
+		return m.Pix, m.Stride, 1, true
+	case *image.Gray16:
+		return m.Pix, m.Stride, 2, true
+	case *image.NRGBA:
+		return m.Pix, m.Stride, 4, true
+	case *image.NRGBA64:
+		return m.Pix, m.Stride, 8, true
+	case *image.Paletted:
+		return m.Pix, m.Stride, 1, true
+	case *image.RGBA:
+		return m.Pix, m.Stride, 4, true
+	case *image.RGBA64:
+		return m.Pix, m.Stride, 8, true
+	}
+	return nil, 0, 0, false
+}
+
+// span is the part of a Pix slice that holds a rectangle's pixels. Its rows
+// start at lo, lo+stride, lo+2*stride and so on, and are w elements long.
+type span struct {
+	pix    []uint8
+	lo     int
+	stride int
+	w, h   int
+}
+
+// pixSpan returns the span of m's Pix that holds the pixels of r, which must
+// be a non-empty rectangle within m's bounds.
+func pixSpan(m image.Image, r image.Rectangle) (span, bool) {
+	pix, stride, bpp, ok := pixels(m)
+	if !ok {
+		return span{}, false
+	}
+	b := m.Bounds()
+	return span{
+		pix:    pix,
+		lo:     (r.Min.Y-b.Min.Y)*stride + (r.Min.X-b.Min.X)*bpp,
+		stride: stride,
+		w:      r.Dx() * bpp,
+		h:      r.Dy(),
+	}, true
+}
+
+// overlaps reports whether a and b share any elements.
+//
+// Sub-images share the end of their parent's backing array, as image.SubImage
+// reslices Pix from the start, so a and b share a backing array if and only
+// if the last elements of their capacities are the same. Measuring offsets
+// from that end gives them a common origin.
+func (a span) overlaps(b span) bool {
+	if cap(a.pix) == 0 || cap(b.pix) == 0 || &a.pix[:cap(a.pix)][cap(a.pix)-1] != &b.pix[:cap(b.pix)][cap(b.pix)-1] {
+		return false
+	}
+	// off is the offset of b's first element from a's first element.
+	off := (b.lo - cap(b.pix)) - (a.lo - cap(a.pix))
+	if a.stride != b.stride {
+		// Compare the ranges from the start of the first row to the end of
+		// the last row.
+		aEnd := (a.h-1)*a.stride + a.w
+		bEnd := off + (b.h-1)*b.stride + b.w
+		return off < aEnd && 0 < bEnd
+	}
+	// Row j of b overlaps row i of a if and only if, for k = j-i, the
+	// offset between them, off + k*stride, is in the range (-b.w, a.w). That
+	// offset increases with k, so it is enough to check the smallest k in
+	// the range [1-a.h, b.h-1] for which it is greater than -b.w.
+	k := floorDiv(-b.w-off, a.stride) + 1
+	if k < 1-a.h {
+		k = 1 - a.h
+	}
+	return k <= b.h-1 && off+k*a.stride < a.w
+}
+
+// floorDiv returns x/y rounded down, for y > 0.
+func floorDiv(x, y int) int {
+	q := x / y
+	if x%y < 0 {
+		q--
+	}
+	return q
+}
+
+// cloneRect returns a copy of the r part of m, with the same type as m when
+// that is one of the types that pixels accepts.
+func cloneRect(m image.Image, r image.Rectangle) image.Image {
+	var c image.Image
+	switch m := m.(type) {
+	case *image.Alpha:
+		c = image.NewAlpha(r)
+	case *image.Alpha16:
+		c = image.NewAlpha16(r)
+	case *image.CMYK:
+		c = image.NewCMYK(r)
+	case *image.Gray:
+		c = image.NewGray(r)
+	case *image.Gray16:
+		c = image.NewGray16(r)
+	case *image.NRGBA:
+		c = image.NewNRGBA(r)
+	case *image.NRGBA64:
+		c = image.NewNRGBA64(r)
+	case *image.Paletted:
+		c = image.NewPaletted(r, m.Palette)
+	case *image.RGBA:
+		c = image.NewRGBA(r)
+	case *image.RGBA64:
+		c = image.NewRGBA64(r)
+	default:
+		// An RGBA64 image holds any color's RGBA values exactly.
+		c := image.NewRGBA64(r)
+		Draw(c, r, m, r.Min, Src)
+		return c
+	}
+	cpix, cstride, _, _ := pixels(c)
+	s, _ := pixSpan(m, r)
+	for y := 0; y < s.h; y++ {
+		copy(cpix[y*cstride:y*cstride+s.w], s.pix[s.lo+y*s.stride:])
+	}
+	return c
+}
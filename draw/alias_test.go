@@ -0,0 +1,108 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package draw
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+
+	"golang.org/x/image/math/f64"
+)
+
+// TestOverlap tests that scaling and transforming part of an image onto an
+// overlapping part of the same image gives the same result as reading from a
+// separate copy of it.
+func TestOverlap(t *testing.T) {
+	newSrc := func() *image.RGBA {
+		m := image.NewRGBA(image.Rect(0, 0, 40, 30))
+		for y := 0; y < 30; y++ {
+			for x := 0; x < 40; x++ {
+				m.SetRGBA(x, y, color.RGBA{uint8(7 * x), uint8(9 * y), uint8(x * y), 0xff})
+			}
+		}
+		return m
+	}
+	sr := image.Rect(5, 5, 25, 20)
+	dr := image.Rect(10, 8, 38, 29)
+	s2d := f64.Aff3{1.3, 0.2, 4, -0.1, 1.2, 3}
+
+	testCases := []struct {
+		name string
+		// draw draws the sr part of src onto dst, which share pixels.
+		draw func(q Interpolator, dst Image, src image.Image)
+	}{
+		{"Scale", func(q Interpolator, dst Image, src image.Image) {
+			q.Scale(dst, dr, src, sr, Src, nil)
+		}},
+		{"Scale 1:1", func(q Interpolator, dst Image, src image.Image) {
+			q.Scale(dst, sr.Add(image.Point{3, 2}), src, sr, Src, nil)
+		}},
+		{"Transform", func(q Interpolator, dst Image, src image.Image) {
+			q.Transform(dst, s2d, src, sr, Over, nil)
+		}},
+	}
+	qs := map[string]Interpolator{
+		"NearestNeighbor": NearestNeighbor,
+		"ApproxBiLinear":  ApproxBiLinear,
+		"CatmullRom":      CatmullRom,
+	}
+	for _, tc := range testCases {
+		for qName, q := range qs {
+			want := newSrc()
+			tc.draw(q, want, newSrc())
+
+			got := newSrc()
+			tc.draw(q, got, got)
+			if !bytes.Equal(got.Pix, want.Pix) {
+				t.Errorf("%s, %s: same image: pixels differ", tc.name, qName)
+			}
+
+			// Use separate, overlapping sub-images of the same image.
+			got = newSrc()
+			tc.draw(q, got.SubImage(image.Rect(8, 0, 40, 30)).(*image.RGBA), got.SubImage(sr).(*image.RGBA))
+			if !bytes.Equal(got.Pix, want.Pix) {
+				t.Errorf("%s, %s: sub-images: pixels differ", tc.name, qName)
+			}
+		}
+	}
+}
+
+func TestOverlaps(t *testing.T) {
+	m := image.NewGray(image.Rect(0, 0, 10, 10))
+	left := m.SubImage(image.Rect(0, 0, 5, 10))
+	right := m.SubImage(image.Rect(5, 0, 10, 10))
+	other := image.NewGray(image.Rect(0, 0, 10, 10))
+
+	testCases := []struct {
+		dst  Image
+		r    image.Rectangle
+		src  image.Image
+		sr   image.Rectangle
+		want bool
+	}{
+		{m, image.Rect(0, 0, 5, 5), m, image.Rect(4, 4, 9, 9), true},
+		{m, image.Rect(0, 0, 5, 5), m, image.Rect(5, 0, 9, 5), false},
+		{m, image.Rect(0, 0, 5, 5), other, image.Rect(0, 0, 5, 5), false},
+		{left.(Image), left.Bounds(), right, right.Bounds(), false},
+		{left.(Image), image.Rect(0, 0, 5, 1), right, image.Rect(5, 0, 6, 1), false},
+		{left.(Image), image.Rect(0, 1, 5, 2), right, image.Rect(5, 0, 6, 1), false},
+		{left.(Image), image.Rect(0, 1, 1, 2), right, image.Rect(9, 0, 10, 1), false},
+		{left.(Image), image.Rect(0, 1, 1, 2), right, image.Rect(5, 0, 10, 3), false},
+		{left.(Image), image.Rect(0, 1, 5, 2), m, image.Rect(4, 0, 10, 3), true},
+		{right.(Image), image.Rect(5, 2, 6, 3), left, image.Rect(0, 0, 5, 10), false},
+		{right.(Image), image.Rect(5, 2, 6, 3), m, image.Rect(0, 0, 6, 10), true},
+		// Without direct access to their pixels, only the same image is
+		// detected.
+		{srcWrapper{m}.Image.(Image), image.Rect(0, 0, 5, 5), srcWrapper{m}, image.Rect(0, 0, 5, 5), false},
+	}
+	for i, tc := range testCases {
+		got := unalias(tc.dst, tc.r, tc.src, tc.sr) != tc.src
+		if got != tc.want {
+			t.Errorf("%d: got %t, want %t", i, got, tc.want)
+		}
+	}
+}
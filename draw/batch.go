@@ -0,0 +1,167 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package draw
+
+import (
+	"image"
+)
+
+// BatchItem is a single operation within a Batch call: the part of the source
+// image defined by Src and SR is drawn to the part of the destination image
+// defined by DR.
+type BatchItem struct {
+	DR  image.Rectangle
+	Src image.Image
+	SR  image.Rectangle
+}
+
+// Batch draws each of items onto dst, in order, with the Porter-Duff operator
+// op. It is equivalent to, but typically faster than, calling Copy for every
+// item whose DR and SR have the same size, and NearestNeighbor.Scale for every
+// other item.
+//
+// Batch is intended for workloads, such as tile maps and sprite atlases, that
+// draw many small images onto the same destination. The destination's type
+// and bounds are examined once per call, not once per item, and common
+// destination and source type combinations are drawn without going through
+// the image/draw package.
+//
+// If opts is non-nil and has a DstMask or SrcMask, Batch falls back to calling
+// Copy or NearestNeighbor.Scale for each item.
+func Batch(dst Image, items []BatchItem, op Op, opts *Options) {
+	if opts != nil && (opts.DstMask != nil || opts.SrcMask != nil) {
+		for i := range items {
+			it := &items[i]
+			if it.DR.Size() == it.SR.Size() {
+				Copy(dst, it.DR.Min, it.Src, it.SR, op, opts)
+			} else {
+				NearestNeighbor.Scale(dst, it.DR, it.Src, it.SR, op, opts)
+			}
+		}
+		return
+	}
+
+	db := dst.Bounds()
+	dstRGBA, _ := dst.(*image.RGBA)
+	for i := range items {
+		it := &items[i]
+		if it.DR.Size() != it.SR.Size() {
+			NearestNeighbor.Scale(dst, it.DR, it.Src, it.SR, op, nil)
+			continue
+		}
+
+		// Clip to the dst bounds, the src bounds and SR, all in dst space.
+		delta := it.DR.Min.Sub(it.SR.Min)
+		dr := it.DR.Intersect(db).Intersect(it.SR.Intersect(it.Src.Bounds()).Add(delta))
+		if dr.Empty() {
+			continue
+		}
+		sp := dr.Min.Sub(delta)
+
+		if dstRGBA != nil {
+			switch src := it.Src.(type) {
+			case *image.RGBA:
+				if src != dstRGBA {
+					if op == Over {
+						batchRGBAOverRGBA(dstRGBA, dr, src, sp)
+					} else {
+						batchRGBASrcRGBA(dstRGBA, dr, src, sp)
+					}
+					continue
+				}
+			case *image.NRGBA:
+				if op == Over {
+					batchRGBAOverNRGBA(dstRGBA, dr, src, sp)
+				} else {
+					batchRGBASrcNRGBA(dstRGBA, dr, src, sp)
+				}
+				continue
+			}
+		}
+		DrawMask(dst, dr, it.Src, sp, nil, image.Point{}, op)
+	}
+}
+
+// The batchXxx functions below assume that dr and the corresponding src
+// rectangle have already been clipped, and that dst and src do not overlap.
+// Their formulae match those of the image/draw package.
+
+func batchRGBASrcRGBA(dst *image.RGBA, dr image.Rectangle, src *image.RGBA, sp image.Point) {
+	n := 4 * dr.Dx()
+	d := dst.PixOffset(dr.Min.X, dr.Min.Y)
+	s := src.PixOffset(sp.X, sp.Y)
+	for y := dr.Min.Y; y < dr.Max.Y; y, d, s = y+1, d+dst.Stride, s+src.Stride {
+		copy(dst.Pix[d:d+n], src.Pix[s:s+n])
+	}
+}
+
+func batchRGBAOverRGBA(dst *image.RGBA, dr image.Rectangle, src *image.RGBA, sp image.Point) {
+	n := 4 * dr.Dx()
+	d := dst.PixOffset(dr.Min.X, dr.Min.Y)
+	s := src.PixOffset(sp.X, sp.Y)
+	for y := dr.Min.Y; y < dr.Max.Y; y, d, s = y+1, d+dst.Stride, s+src.Stride {
+		dpix := dst.Pix[d : d+n]
+		spix := src.Pix[s : s+n]
+		for i := 0; i < n; i += 4 {
+			sa := uint32(spix[i+3]) * 0x101
+			if sa == 0xffff {
+				copy(dpix[i:i+4], spix[i:i+4])
+				continue
+			}
+			sr := uint32(spix[i+0]) * 0x101
+			sg := uint32(spix[i+1]) * 0x101
+			sb := uint32(spix[i+2]) * 0x101
+			a := (0xffff - sa) * 0x101
+			dpix[i+0] = uint8((uint32(dpix[i+0])*a/0xffff + sr) >> 8)
+			dpix[i+1] = uint8((uint32(dpix[i+1])*a/0xffff + sg) >> 8)
+			dpix[i+2] = uint8((uint32(dpix[i+2])*a/0xffff + sb) >> 8)
+			dpix[i+3] = uint8((uint32(dpix[i+3])*a/0xffff + sa) >> 8)
+		}
+	}
+}
+
+func batchRGBASrcNRGBA(dst *image.RGBA, dr image.Rectangle, src *image.NRGBA, sp image.Point) {
+	n := 4 * dr.Dx()
+	d := dst.PixOffset(dr.Min.X, dr.Min.Y)
+	s := src.PixOffset(sp.X, sp.Y)
+	for y := dr.Min.Y; y < dr.Max.Y; y, d, s = y+1, d+dst.Stride, s+src.Stride {
+		dpix := dst.Pix[d : d+n]
+		spix := src.Pix[s : s+n]
+		for i := 0; i < n; i += 4 {
+			sa := uint32(spix[i+3]) * 0x101
+			sr := uint32(spix[i+0]) * sa / 0xff
+			sg := uint32(spix[i+1]) * sa / 0xff
+			sb := uint32(spix[i+2]) * sa / 0xff
+			dpix[i+0] = uint8(sr >> 8)
+			dpix[i+1] = uint8(sg >> 8)
+			dpix[i+2] = uint8(sb >> 8)
+			dpix[i+3] = uint8(sa >> 8)
+		}
+	}
+}
+
+func batchRGBAOverNRGBA(dst *image.RGBA, dr image.Rectangle, src *image.NRGBA, sp image.Point) {
+	n := 4 * dr.Dx()
+	d := dst.PixOffset(dr.Min.X, dr.Min.Y)
+	s := src.PixOffset(sp.X, sp.Y)
+	for y := dr.Min.Y; y < dr.Max.Y; y, d, s = y+1, d+dst.Stride, s+src.Stride {
+		dpix := dst.Pix[d : d+n]
+		spix := src.Pix[s : s+n]
+		for i := 0; i < n; i += 4 {
+			sa := uint32(spix[i+3]) * 0x101
+			if sa == 0 {
+				continue
+			}
+			sr := uint32(spix[i+0]) * sa / 0xff
+			sg := uint32(spix[i+1]) * sa / 0xff
+			sb := uint32(spix[i+2]) * sa / 0xff
+			a := (0xffff - sa) * 0x101
+			dpix[i+0] = uint8((uint32(dpix[i+0])*a/0xffff + sr) >> 8)
+			dpix[i+1] = uint8((uint32(dpix[i+1])*a/0xffff + sg) >> 8)
+			dpix[i+2] = uint8((uint32(dpix[i+2])*a/0xffff + sb) >> 8)
+			dpix[i+3] = uint8((uint32(dpix[i+3])*a/0xffff + sa) >> 8)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package draw
+
+import (
+	"bytes"
+	"image"
+	"math/rand"
+	"testing"
+)
+
+func TestBatch(t *testing.T) {
+	srcfs := []func(image.Rectangle) (image.Image, error){
+		srcGray,
+		srcNRGBA,
+		srcRGBA,
+		srcUnif,
+	}
+	var srcs []image.Image
+	for _, srcf := range srcfs {
+		src, err := srcf(image.Rect(0, 0, 12, 9))
+		if err != nil {
+			t.Fatal(err)
+		}
+		srcs = append(srcs, src)
+	}
+	items := []BatchItem{
+		{DR: image.Rect(0, 0, 4, 4), SR: image.Rect(0, 0, 4, 4)},     // Same size.
+		{DR: image.Rect(3, 4, 8, 6), SR: image.Rect(2, 2, 7, 4)},     // Translated.
+		{DR: image.Rect(-3, -5, 2, 4), SR: image.Rect(1, 0, 6, 9)},   // Partial dst out-of-bounds.
+		{DR: image.Rect(6, 6, 16, 12), SR: image.Rect(8, 4, 18, 10)}, // Partial src out-of-bounds.
+		{DR: image.Rect(20, 20, 24, 24), SR: image.Rect(0, 0, 4, 4)}, // Complete out-of-bounds.
+		{DR: image.Rect(1, 2, 9, 8), SR: image.Rect(0, 0, 4, 3)},     // Scaled.
+		{DR: image.Rect(5, 5, 5, 5), SR: image.Rect(5, 5, 5, 5)},     // Empty.
+	}
+
+	for _, op := range []Op{Over, Src} {
+		for _, src := range srcs {
+			for i := range items {
+				items[i].Src = src
+			}
+
+			want := image.NewRGBA(image.Rect(0, 0, 10, 10))
+			fillPix(rand.New(rand.NewSource(0)), want.Pix)
+			got := image.NewRGBA(want.Rect)
+			copy(got.Pix, want.Pix)
+
+			for _, it := range items {
+				if it.DR.Size() == it.SR.Size() {
+					Copy(want, it.DR.Min, it.Src, it.SR, op, nil)
+				} else {
+					NearestNeighbor.Scale(want, it.DR, it.Src, it.SR, op, nil)
+				}
+			}
+			Batch(got, items, op, nil)
+
+			if !bytes.Equal(got.Pix, want.Pix) {
+				t.Errorf("op=%v, src=%T:\ngot  %v\nwant %v", op, src, got.Pix, want.Pix)
+			}
+		}
+	}
+}
+
+func BenchmarkBatch(b *testing.B) {
+	dst := image.NewRGBA(image.Rect(0, 0, 640, 480))
+	atlas, err := srcNRGBA(image.Rect(0, 0, 256, 256))
+	if err != nil {
+		b.Fatal(err)
+	}
+	items := make([]BatchItem, 0, (640/16)*(480/16))
+	for y := 0; y < 480; y += 16 {
+		for x := 0; x < 640; x += 16 {
+			sx, sy := (x*7)%256&^15, (y*3)%256&^15
+			items = append(items, BatchItem{
+				DR:  image.Rect(x, y, x+16, y+16),
+				Src: atlas,
+				SR:  image.Rect(sx, sy, sx+16, sy+16),
+			})
+		}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Batch(dst, items, Over, nil)
+	}
+}
@@ -2,38 +2,46 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-//go:build ignore
-
+// Drawgen generates the fast paths of the golang.org/x/image/draw package's
+// interpolators.
+//
+// Run without flags, in the draw package's directory, it generates that
+// package's impl.go. With a -config flag, it generates a standalone file, for
+// another package, with fast paths for that package's image types. See the
+// config type for details.
 package main
 
 import (
 	"bytes"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"go/format"
 	"log"
 	"os"
+	"path/filepath"
 	"strings"
 )
 
-var debug = flag.Bool("debug", false, "")
+var (
+	debug      = flag.Bool("debug", false, "")
+	configFile = flag.String("config", "", "generate a standalone file for the image types in this JSON config file")
+	outFile    = flag.String("o", "impl.go", "output file")
+)
 
 func main() {
 	flag.Parse()
 
 	w := new(bytes.Buffer)
-	w.WriteString("// generated by \"go run gen.go\". DO NOT EDIT.\n\n" +
-		"package draw\n\nimport (\n" +
-		"\"image\"\n" +
-		"\"image/color\"\n" +
-		"\"math\"\n" +
-		"\n" +
-		"\"golang.org/x/image/math/f64\"\n" +
-		")\n")
-
-	gen(w, "nnInterpolator", codeNNScaleLeaf, codeNNTransformLeaf)
-	gen(w, "ablInterpolator", codeABLScaleLeaf, codeABLTransformLeaf)
-	genKernel(w)
+	if *configFile != "" {
+		c, err := readConfig(*configFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		genStandalone(w, c)
+	} else {
+		genPackage(w)
+	}
 
 	if *debug {
 		os.Stdout.Write(w.Bytes())
@@ -43,11 +51,27 @@ func main() {
 	if err != nil {
 		log.Fatal(err)
 	}
-	if err := os.WriteFile("impl.go", out, 0660); err != nil {
+	if err := os.WriteFile(*outFile, out, 0660); err != nil {
 		log.Fatal(err)
 	}
 }
 
+// genPackage generates the draw package's impl.go.
+func genPackage(w *bytes.Buffer) {
+	w.WriteString("// generated by \"go run ./cmd/drawgen\". DO NOT EDIT.\n\n" +
+		"package draw\n\nimport (\n" +
+		"\"image\"\n" +
+		"\"image/color\"\n" +
+		"\"math\"\n" +
+		"\n" +
+		"\"golang.org/x/image/math/f64\"\n" +
+		")\n")
+
+	gen(w, "nnInterpolator", codeNNScaleLeaf, codeNNTransformLeaf)
+	gen(w, "ablInterpolator", codeABLScaleLeaf, codeABLTransformLeaf)
+	genKernel(w)
+}
+
 var (
 	// dsTypes are the (dst image type, src image type) pairs to generate
 	// scale_DType_SType implementations for. The last element in the slice
@@ -56,17 +80,32 @@ var (
 	// TODO: add *image.CMYK src type after Go 1.5 is released.
 	// An *image.CMYK is also alwaysOpaque.
 	dsTypes = []struct{ dType, sType string }{
+		{"*image.Alpha", "*image.Alpha"},
+		{"*image.Alpha", "image.RGBA64Image"},
+		{"*image.Alpha", "image.Image"},
+		{"*image.Gray", "*image.Gray"},
+		{"*image.Gray", "image.RGBA64Image"},
+		{"*image.Gray", "image.Image"},
 		{"*image.RGBA", "*image.Gray"},
 		{"*image.RGBA", "*image.NRGBA"},
 		{"*image.RGBA", "*image.RGBA"},
 		{"*image.RGBA", "*image.YCbCr"},
+		{"*image.RGBA", "*image.NYCbCrA"},
 		{"*image.RGBA", "image.RGBA64Image"},
 		{"*image.RGBA", "image.Image"},
+		{"*image.RGBA64", "*image.NRGBA64"},
+		{"*image.RGBA64", "*image.RGBA64"},
+		{"*image.RGBA64", "image.RGBA64Image"},
+		{"*image.RGBA64", "image.Image"},
+		{"*image.NRGBA64", "*image.NRGBA64"},
+		{"*image.NRGBA64", "*image.RGBA64"},
+		{"*image.NRGBA64", "image.RGBA64Image"},
+		{"*image.NRGBA64", "image.Image"},
 		{"RGBA64Image", "image.RGBA64Image"},
 		{"Image", "image.Image"},
 	}
 	dTypes, sTypes  []string
-	sTypesForDType  = map[string][]string{}
+	sTypesForDType  map[string][]string
 	subsampleRatios = []string{
 		"444",
 		"422",
@@ -81,9 +120,19 @@ var (
 		"*image.Gray":  true,
 		"*image.YCbCr": true,
 	}
+	// optionsType is how the generated code refers to the Options type.
+	optionsType = "Options"
 )
 
 func init() {
+	setDSTypes(dsTypes)
+}
+
+// setDSTypes sets dsTypes, and the dTypes, sTypes and sTypesForDType derived
+// from it.
+func setDSTypes(ts []struct{ dType, sType string }) {
+	dsTypes, dTypes, sTypes = ts, nil, nil
+	sTypesForDType = map[string][]string{}
 	dTypesSeen := map[string]bool{}
 	sTypesSeen := map[string]bool{}
 	for _, t := range dsTypes {
@@ -110,6 +159,10 @@ type data struct {
 
 func gen(w *bytes.Buffer, receiver string, codes ...string) {
 	expn(w, codeRoot, &data{receiver: receiver})
+	genLeaves(w, receiver, codes...)
+}
+
+func genLeaves(w *bytes.Buffer, receiver string, codes ...string) {
 	for _, code := range codes {
 		for _, t := range dsTypes {
 			for _, op := range ops {
@@ -142,6 +195,10 @@ func genKernel(w *bytes.Buffer) {
 			})
 		}
 	}
+	genKernelTransformLeaves(w)
+}
+
+func genKernelTransformLeaves(w *bytes.Buffer) {
 	for _, t := range dsTypes {
 		for _, op := range ops {
 			if op == "Over" && alwaysOpaque[t.sType] {
@@ -156,8 +213,224 @@ func genKernel(w *bytes.Buffer) {
 	}
 }
 
+// config is a JSON config file for generating a standalone file, in another
+// package, with fast paths for the image types that an application uses. For
+// example, for a BGRA image type defined in the example.com/pix package:
+//
+//	{
+//		"Package": "pixdraw",
+//		"Imports": ["example.com/pix"],
+//		"Types": [
+//			{"Type": "*pix.BGRA", "Channels": "BGRA", "Premultiplied": true}
+//		],
+//		"Funcs": [
+//			{"Dst": "*pix.BGRA", "Src": "*pix.BGRA", "Interpolator": "ApproxBiLinear"},
+//			{"Dst": "*pix.BGRA", "Src": "*image.RGBA", "Interpolator": "ApproxBiLinear"},
+//			{"Dst": "*image.RGBA", "Src": "*pix.BGRA", "Interpolator": "Kernel"}
+//		]
+//	}
+//
+// generated by running, in the pixdraw package's directory:
+//
+//	go run golang.org/x/image/draw/cmd/drawgen -config config.json -o impl.go
+//
+// The generated package has NearestNeighbor, ApproxBiLinear and Kernel
+// interpolators, for those used in Funcs, that are like this package's, but
+// with fast paths for the Funcs' image types. Other image types, and non-nil
+// Options, fall back to this package's interpolators.
+type config struct {
+	// Package is the generated file's package name.
+	Package string
+	// Imports are the import paths of the packages that define Types, if
+	// they are not defined in the generated package.
+	Imports []string
+	// Types are the image types that are not in the image package.
+	Types []struct {
+		// Type is the Go type, a pointer to a struct that has Pix, Stride
+		// and Rect fields like an image.RGBA's, and that implements
+		// draw.Image.
+		Type string
+		// Channels has one of 'R', 'G', 'B', 'A' or 'X' for each byte of a
+		// pixel, where 'X' is a byte that is not used, such as "BGRA".
+		Channels string
+		// Premultiplied is whether the color channels are
+		// alpha-premultiplied.
+		Premultiplied bool
+	}
+	// Funcs are the (dst, src, interpolator) triples to generate fast paths
+	// for. Dst is *image.Alpha, *image.Gray, *image.RGBA or one of Types,
+	// which must be premultiplied or have no alpha channel. Src is
+	// *image.Alpha, *image.Gray, *image.NRGBA, *image.RGBA or one of Types.
+	// Interpolator is NearestNeighbor, ApproxBiLinear or Kernel.
+	Funcs []struct {
+		Dst, Src, Interpolator string
+	}
+}
+
+// interpolators are the receivers and leaf templates for each interpolator
+// that a config can use, in the order that they are generated.
+var interpolators = []struct {
+	name, receiver string
+	codes          []string
+}{
+	{"NearestNeighbor", "nnInterpolator", []string{codeNNScaleLeaf, codeNNTransformLeaf}},
+	{"ApproxBiLinear", "ablInterpolator", []string{codeABLScaleLeaf, codeABLTransformLeaf}},
+	{"Kernel", "*Kernel", nil},
+}
+
+func readConfig(filename string) (*config, error) {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	c := &config{}
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	if c.Package == "" {
+		return nil, fmt.Errorf("%s: no Package", filename)
+	}
+
+	names := map[string]string{}
+	for t := range layouts {
+		names[relName(t)] = t
+	}
+	for _, t := range c.Types {
+		if !strings.HasPrefix(t.Type, "*") {
+			return nil, fmt.Errorf("%s: type %q is not a pointer type", filename, t.Type)
+		}
+		if other, ok := names[relName(t.Type)]; ok {
+			return nil, fmt.Errorf("%s: types %q and %q have the same name", filename, other, t.Type)
+		}
+		names[relName(t.Type)] = t.Type
+		if !validChannels(t.Channels) {
+			return nil, fmt.Errorf("%s: type %q has invalid channels %q", filename, t.Type, t.Channels)
+		}
+		l := layout{channels: t.Channels, premul: t.Premultiplied}
+		layouts[t.Type] = l
+		if !l.hasAlpha() {
+			alwaysOpaque[t.Type] = true
+		}
+	}
+
+	for _, f := range c.Funcs {
+		l, ok := layouts[f.Dst]
+		if !ok {
+			return nil, fmt.Errorf("%s: unknown Dst type %q", filename, f.Dst)
+		} else if l.hasAlpha() && !l.premul {
+			return nil, fmt.Errorf("%s: Dst type %q is not premultiplied", filename, f.Dst)
+		}
+		if _, ok := layouts[f.Src]; !ok {
+			return nil, fmt.Errorf("%s: unknown Src type %q", filename, f.Src)
+		}
+		if layouts[f.Src].alphaOnly() && !l.alphaOnly() {
+			return nil, fmt.Errorf("%s: Src type %q is alpha-only but Dst type %q is not", filename, f.Src, f.Dst)
+		} else if l.alphaOnly() && alwaysOpaque[f.Src] {
+			return nil, fmt.Errorf("%s: Dst type %q is alpha-only but Src type %q is opaque", filename, f.Dst, f.Src)
+		}
+		found := false
+		for _, in := range interpolators {
+			found = found || in.name == f.Interpolator
+		}
+		if !found {
+			return nil, fmt.Errorf("%s: unknown Interpolator %q", filename, f.Interpolator)
+		}
+	}
+	return c, nil
+}
+
+// validChannels returns whether channels has each of 'R', 'G' and 'B' once,
+// at most one 'A', and no other bytes except for 'X'.
+func validChannels(channels string) bool {
+	n := map[rune]int{}
+	for _, c := range channels {
+		n[c]++
+	}
+	return n['R'] == 1 && n['G'] == 1 && n['B'] == 1 && n['A'] <= 1 &&
+		len(channels) == 3+n['A']+n['X']
+}
+
+// genStandalone generates a file, for the package named by c, that has the
+// interpolators used by c's Funcs.
+func genStandalone(w *bytes.Buffer, c *config) {
+	optionsType = "draw.Options"
+	fmt.Fprintf(w, "// generated by \"go run golang.org/x/image/draw/cmd/drawgen -config %s\". DO NOT EDIT.\n\n"+
+		"package %s\n\nimport (\n"+
+		"\"image\"\n"+
+		"\"math\"\n"+
+		"\n"+
+		"\"golang.org/x/image/draw\"\n"+
+		"\"golang.org/x/image/math/f64\"\n",
+		filepath.Base(*configFile), c.Package)
+	for _, imp := range c.Imports {
+		fmt.Fprintf(w, "%q\n", imp)
+	}
+	w.WriteString(")\n")
+
+	for _, in := range interpolators {
+		var ts []struct{ dType, sType string }
+		seen := map[[2]string]bool{}
+		for _, f := range c.Funcs {
+			if f.Interpolator == in.name && !seen[[2]string{f.Dst, f.Src}] {
+				seen[[2]string{f.Dst, f.Src}] = true
+				ts = append(ts, struct{ dType, sType string }{f.Dst, f.Src})
+			}
+		}
+		if len(ts) == 0 {
+			continue
+		}
+		setDSTypes(ts)
+		if in.name == "Kernel" {
+			expn(w, codeStandaloneKernelRoot, &data{})
+			genKernelTransformLeaves(w)
+		} else {
+			fmt.Fprintf(w, "// %[1]s is like draw.%[1]s, with fast paths for more image types.\n"+
+				"var %[1]s = draw.Interpolator(%[2]s{})\n\n"+
+				"type %[2]s struct{}\n",
+				in.name, in.receiver)
+			expn(w, codeStandaloneRoot, &data{receiver: in.receiver})
+			genLeaves(w, in.receiver, in.codes...)
+		}
+	}
+	w.WriteString(codeStandaloneHelpers)
+}
+
+// expnStandaloneSwitch expands a "$standaloneSwitch" template to a type
+// switch on dst and src, for the standalone roots. Unlike expnSwitch, it has
+// no fallback cases. Instead, each case that has a fast path ends with the
+// template's own return statement, and the others fall through to after the
+// switch.
+func expnStandaloneSwitch(template string) string {
+	lines := []string{"switch dst := dst.(type) {"}
+	for _, dType := range dTypes {
+		lines = append(lines, fmt.Sprintf("case %s:", dType), "switch src := src.(type) {")
+		for _, sType := range sTypesForDType[dType] {
+			// The fast paths do not support dst and src sharing pixels.
+			lines = append(lines,
+				fmt.Sprintf("case %s:", sType),
+				"if sharePix(dst.Pix, src.Pix) {",
+				"break",
+				"}",
+			)
+			if alwaysOpaque[sType] {
+				lines = append(lines, expnLine(template, &data{dType: dType, sType: sType, op: "Src"}))
+				continue
+			}
+			lines = append(lines,
+				"if op == draw.Over {",
+				expnLine(template, &data{dType: dType, sType: sType, op: "Over"}),
+				"}",
+				expnLine(template, &data{dType: dType, sType: sType, op: "Src"}),
+			)
+		}
+		lines = append(lines, "}")
+	}
+	lines = append(lines, "}")
+	return strings.Join(lines, "\n")
+}
+
 func expn(w *bytes.Buffer, code string, d *data) {
-	if d.sType == "*image.YCbCr" && d.sratio == "" {
+	if isYCbCr(d.sType) && d.sratio == "" {
 		for _, sratio := range subsampleRatios {
 			e := *d
 			e.sratio = sratio
@@ -220,6 +493,8 @@ func expnDollar(prefix, dollar, suffix string, d *data) string {
 		return prefix + d.receiver + suffix
 	case "op":
 		return prefix + d.op + suffix
+	case "Options":
+		return prefix + optionsType + suffix
 
 	case "switch":
 		return expnSwitch("", "", true, suffix)
@@ -227,6 +502,16 @@ func expnDollar(prefix, dollar, suffix string, d *data) string {
 		return expnSwitch("", "", false, suffix)
 	case "switchS":
 		return expnSwitch("", "anyDType", false, suffix)
+	case "standaloneSwitch":
+		return expnStandaloneSwitch(suffix)
+	case "fallback":
+		switch d.receiver {
+		case "nnInterpolator":
+			return prefix + "draw.NearestNeighbor" + suffix
+		case "ablInterpolator":
+			return prefix + "draw.ApproxBiLinear" + suffix
+		}
+		return ""
 
 	case "preOuter":
 		switch d.dType {
@@ -252,12 +537,10 @@ func expnDollar(prefix, dollar, suffix string, d *data) string {
 		}
 
 	case "preInner":
-		switch d.dType {
-		default:
-			return ";"
-		case "*image.RGBA":
-			return "d := " + pixOffset("dst", "dr.Min.X+adr.Min.X", "dr.Min.Y+int(dy)", "*4", "*dst.Stride")
+		if l, ok := layouts[d.dType]; ok {
+			return "d := " + pixOffset("dst", "dr.Min.X+adr.Min.X", "dr.Min.Y+int(dy)", l.xstride(), "*dst.Stride")
 		}
+		return ";"
 
 	case "preKernelOuter":
 		switch d.sType {
@@ -268,40 +551,24 @@ func expnDollar(prefix, dollar, suffix string, d *data) string {
 		}
 
 	case "preKernelInner":
-		switch d.dType {
-		default:
-			return ";"
-		case "*image.RGBA":
-			return "d := " + pixOffset("dst", "dr.Min.X+int(dx)", "dr.Min.Y+adr.Min.Y", "*4", "*dst.Stride")
+		if l, ok := layouts[d.dType]; ok {
+			return "d := " + pixOffset("dst", "dr.Min.X+int(dx)", "dr.Min.Y+adr.Min.Y", l.xstride(), "*dst.Stride")
 		}
+		return ";"
 
 	case "blend":
 		args, _ := splitArgs(suffix)
 		if len(args) != 4 {
 			return ""
 		}
-		switch d.sType {
-		default:
-			return argf(args, ""+
-				"$3r = float64($0*$1r) + float64($2*$3r)\n"+
-				"$3g = float64($0*$1g) + float64($2*$3g)\n"+
-				"$3b = float64($0*$1b) + float64($2*$3b)\n"+
-				"$3a = float64($0*$1a) + float64($2*$3a)",
-			)
-		case "*image.Gray":
-			return argf(args, ""+
-				"$3r = float64($0*$1r) + float64($2*$3r)",
-			)
-		case "*image.YCbCr":
-			return argf(args, ""+
-				"$3r = float64($0*$1r) + float64($2*$3r)\n"+
-				"$3g = float64($0*$1g) + float64($2*$3g)\n"+
-				"$3b = float64($0*$1b) + float64($2*$3b)",
-			)
+		var lines []string
+		for _, c := range leafChannels(d) {
+			lines = append(lines, fmt.Sprintf("$3%[1]c = float64($0*$1%[1]c) + float64($2*$3%[1]c)", c))
 		}
+		return argf(args, strings.Join(lines, "\n"))
 
 	case "clampToAlpha":
-		if alwaysOpaque[d.sType] {
+		if alwaysOpaque[d.sType] || leafChannels(d) == "a" {
 			return ";"
 		}
 		// Go uses alpha-premultiplied color. The naive computation can lead to
@@ -324,35 +591,32 @@ func expnDollar(prefix, dollar, suffix string, d *data) string {
 			return ""
 		}
 
-		switch d.sType {
-		default:
-			return argf(args, ""+
-				"$0r := uint32($1r)\n"+
-				"$0g := uint32($1g)\n"+
-				"$0b := uint32($1b)\n"+
-				"$0a := uint32($1a)",
-			)
-		case "*image.Gray":
-			return argf(args, ""+
-				"$0r := uint32($1r)",
-			)
-		case "*image.YCbCr":
-			return argf(args, ""+
-				"$0r := uint32($1r)\n"+
-				"$0g := uint32($1g)\n"+
-				"$0b := uint32($1b)",
-			)
-		case "image.RGBA64Image":
+		channels := leafChannels(d)
+		if d.sType == "image.RGBA64Image" {
+			if channels == "a" {
+				return argf(args, "$0 := color.RGBA64{A: uint16($1a)}")
+			}
 			return argf(args, ""+
 				"$0 := color.RGBA64{uint16($1r), uint16($1g), uint16($1b), uint16($1a)}",
 			)
 		}
+		if dropsAlpha(d) {
+			channels = strings.TrimSuffix(channels, "a")
+		}
+		var lines []string
+		for _, c := range channels {
+			lines = append(lines, fmt.Sprintf("$0%[1]c := uint32($1%[1]c)", c))
+		}
+		return argf(args, strings.Join(lines, "\n"))
 
 	case "outputu":
 		args, _ := splitArgs(suffix)
 		if len(args) != 3 {
 			return ""
 		}
+		if _, ok := layouts[d.dType]; ok {
+			return argf(args, layoutOutputu(d))
+		}
 
 		switch d.op {
 		case "Over":
@@ -393,7 +657,7 @@ func expnDollar(prefix, dollar, suffix string, d *data) string {
 						"dstColorRGBA64.G = uint16(uint32(q.G)*$2a1/0xffff + $2g)\n"+
 						"dstColorRGBA64.B = uint16(uint32(q.B)*$2a1/0xffff + $2b)\n"+
 						"dstColorRGBA64.A = uint16(uint32(q.A)*$2a1/0xffff + $2a)\n"+
-						"dst.Set($0, $1, dstColorRGBA64)",
+						"dst.SetRGBA64($0, $1, dstColorRGBA64)",
 					)
 				case "image.RGBA64Image":
 					return argf(args, ""+
@@ -410,26 +674,7 @@ func expnDollar(prefix, dollar, suffix string, d *data) string {
 						"dstColorRGBA64.G = uint16(uint32(q.G)*$2a1/0xffff + uint32($2.G))\n"+
 						"dstColorRGBA64.B = uint16(uint32(q.B)*$2a1/0xffff + uint32($2.B))\n"+
 						"dstColorRGBA64.A = uint16(uint32(q.A)*$2a1/0xffff + uint32($2.A))\n"+
-						"dst.Set($0, $1, dstColorRGBA64)",
-					)
-				}
-			case "*image.RGBA":
-				switch d.sType {
-				default:
-					return argf(args, ""+
-						"$2a1 := (0xffff - $2a) * 0x101\n"+
-						"dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*$2a1/0xffff + $2r) >> 8)\n"+
-						"dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*$2a1/0xffff + $2g) >> 8)\n"+
-						"dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*$2a1/0xffff + $2b) >> 8)\n"+
-						"dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*$2a1/0xffff + $2a) >> 8)",
-					)
-				case "image.RGBA64Image":
-					return argf(args, ""+
-						"$2a1 := (0xffff - uint32($2.A)) * 0x101\n"+
-						"dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*$2a1/0xffff + uint32($2.R)) >> 8)\n"+
-						"dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*$2a1/0xffff + uint32($2.G)) >> 8)\n"+
-						"dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*$2a1/0xffff + uint32($2.B)) >> 8)\n"+
-						"dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*$2a1/0xffff + uint32($2.A)) >> 8)",
+						"dst.SetRGBA64($0, $1, dstColorRGBA64)",
 					)
 				}
 			}
@@ -477,13 +722,13 @@ func expnDollar(prefix, dollar, suffix string, d *data) string {
 						"	dstColorRGBA64.G = uint16(uint32(q.G)*$2a1/0xffff + $2g)\n"+
 						"	dstColorRGBA64.B = uint16(uint32(q.B)*$2a1/0xffff + $2b)\n"+
 						"	dstColorRGBA64.A = uint16(uint32(q.A)*$2a1/0xffff + $2a)\n"+
-						"	dst.Set($0, $1, dstColorRGBA64)\n"+
+						"	dst.SetRGBA64($0, $1, dstColorRGBA64)\n"+
 						"} else {\n"+
 						"	dstColorRGBA64.R = uint16($2r)\n"+
 						"	dstColorRGBA64.G = uint16($2g)\n"+
 						"	dstColorRGBA64.B = uint16($2b)\n"+
 						"	dstColorRGBA64.A = uint16($2a)\n"+
-						"	dst.Set($0, $1, dstColorRGBA64)\n"+
+						"	dst.SetRGBA64($0, $1, dstColorRGBA64)\n"+
 						"}",
 					)
 				case "image.RGBA64Image":
@@ -500,44 +745,12 @@ func expnDollar(prefix, dollar, suffix string, d *data) string {
 						"	dstColorRGBA64.G = uint16(uint32(q.G)*$2a1/0xffff + uint32($2.G))\n"+
 						"	dstColorRGBA64.B = uint16(uint32(q.B)*$2a1/0xffff + uint32($2.B))\n"+
 						"	dstColorRGBA64.A = uint16(uint32(q.A)*$2a1/0xffff + uint32($2.A))\n"+
-						"	dst.Set($0, $1, dstColorRGBA64)\n"+
+						"	dst.SetRGBA64($0, $1, dstColorRGBA64)\n"+
 						"} else {\n"+
-						"	dst.Set($0, $1, $2)\n"+
+						"	dst.SetRGBA64($0, $1, $2)\n"+
 						"}",
 					)
 				}
-			case "*image.RGBA":
-				switch d.sType {
-				default:
-					return argf(args, ""+
-						"dst.Pix[d+0] = uint8($2r >> 8)\n"+
-						"dst.Pix[d+1] = uint8($2g >> 8)\n"+
-						"dst.Pix[d+2] = uint8($2b >> 8)\n"+
-						"dst.Pix[d+3] = uint8($2a >> 8)",
-					)
-				case "*image.Gray":
-					return argf(args, ""+
-						"out := uint8($2r >> 8)\n"+
-						"dst.Pix[d+0] = out\n"+
-						"dst.Pix[d+1] = out\n"+
-						"dst.Pix[d+2] = out\n"+
-						"dst.Pix[d+3] = 0xff",
-					)
-				case "*image.YCbCr":
-					return argf(args, ""+
-						"dst.Pix[d+0] = uint8($2r >> 8)\n"+
-						"dst.Pix[d+1] = uint8($2g >> 8)\n"+
-						"dst.Pix[d+2] = uint8($2b >> 8)\n"+
-						"dst.Pix[d+3] = 0xff",
-					)
-				case "image.RGBA64Image":
-					return argf(args, ""+
-						"dst.Pix[d+0] = uint8($2.R >> 8)\n"+
-						"dst.Pix[d+1] = uint8($2.G >> 8)\n"+
-						"dst.Pix[d+2] = uint8($2.B >> 8)\n"+
-						"dst.Pix[d+3] = uint8($2.A >> 8)",
-					)
-				}
 			}
 		}
 
@@ -548,6 +761,11 @@ func expnDollar(prefix, dollar, suffix string, d *data) string {
 		}
 		ret := ""
 
+		if _, ok := layouts[d.dType]; ok {
+			ret = argf(args, layoutOutputf(d))
+			return strings.Replace(ret, " * 1)", ")", -1)
+		}
+
 		switch d.op {
 		case "Over":
 			switch d.dType {
@@ -595,18 +813,6 @@ func expnDollar(prefix, dollar, suffix string, d *data) string {
 					"dstColorRGBA64.A = uint16(uint32(q.A)*$3a1/0xffff + $3a0)\n"+
 					"dst.SetRGBA64($0, $1, dstColorRGBA64)",
 				)
-			case "*image.RGBA":
-				ret = argf(args, ""+
-					"$3r0 := uint32($2($3r * $4))\n"+
-					"$3g0 := uint32($2($3g * $4))\n"+
-					"$3b0 := uint32($2($3b * $4))\n"+
-					"$3a0 := uint32($2($3a * $4))\n"+
-					"$3a1 := (0xffff - uint32($3a0)) * 0x101\n"+
-					"dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*$3a1/0xffff + $3r0) >> 8)\n"+
-					"dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*$3a1/0xffff + $3g0) >> 8)\n"+
-					"dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*$3a1/0xffff + $3b0) >> 8)\n"+
-					"dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*$3a1/0xffff + $3a0) >> 8)",
-				)
 			}
 
 		case "Src":
@@ -659,31 +865,6 @@ func expnDollar(prefix, dollar, suffix string, d *data) string {
 					"	dst.SetRGBA64($0, $1, dstColorRGBA64)\n"+
 					"}",
 				)
-			case "*image.RGBA":
-				switch d.sType {
-				default:
-					ret = argf(args, ""+
-						"dst.Pix[d+0] = uint8($2($3r * $4) >> 8)\n"+
-						"dst.Pix[d+1] = uint8($2($3g * $4) >> 8)\n"+
-						"dst.Pix[d+2] = uint8($2($3b * $4) >> 8)\n"+
-						"dst.Pix[d+3] = uint8($2($3a * $4) >> 8)",
-					)
-				case "*image.Gray":
-					ret = argf(args, ""+
-						"out := uint8($2($3r * $4) >> 8)\n"+
-						"dst.Pix[d+0] = out\n"+
-						"dst.Pix[d+1] = out\n"+
-						"dst.Pix[d+2] = out\n"+
-						"dst.Pix[d+3] = 0xff",
-					)
-				case "*image.YCbCr":
-					ret = argf(args, ""+
-						"dst.Pix[d+0] = uint8($2($3r * $4) >> 8)\n"+
-						"dst.Pix[d+1] = uint8($2($3g * $4) >> 8)\n"+
-						"dst.Pix[d+2] = uint8($2($3b * $4) >> 8)\n"+
-						"dst.Pix[d+3] = 0xff",
-					)
-				}
 			}
 		}
 
@@ -710,11 +891,32 @@ func expnDollar(prefix, dollar, suffix string, d *data) string {
 		buf := new(bytes.Buffer)
 		switch d.sType {
 		default:
-			log.Fatalf("bad sType %q", d.sType)
+			l, ok := layouts[d.sType]
+			if !ok {
+				log.Fatalf("bad sType %q", d.sType)
+			}
+			fmt.Fprintf(buf, "%si := %s\n", lhs, pixOffset("src", args[0], args[1], l.xstride(), "*src.Stride"))
+			// Integer alpha is not needed when writing to an opaque dst,
+			// unless for premultiplying the other channels.
+			buf.WriteString(l.srcu(lhs, tmp, dollar == "srcf" || !dropsAlpha(d)))
 		case "image.Image":
+			// Blank the variables for the channels that the leaf does not
+			// use.
+			channels := leafChannels(d)
+			if dollar == "srcu" && dropsAlpha(d) {
+				channels = strings.TrimSuffix(channels, "a")
+			}
+			var names []string
+			for _, c := range []byte("rgba") {
+				if hasChannel(channels, c) {
+					names = append(names, fmt.Sprintf("%s%c%s", lhs, c, tmp))
+				} else {
+					names = append(names, "_")
+				}
+			}
 			fmt.Fprintf(buf, ""+
-				"%sr%s, %sg%s, %sb%s, %sa%s := src.At(%s, %s).RGBA()\n",
-				lhs, tmp, lhs, tmp, lhs, tmp, lhs, tmp, args[0], args[1],
+				"%s := src.At(%s, %s).RGBA()\n",
+				strings.Join(names, ", "), args[0], args[1],
 			)
 			if d.dType == "" || d.dType == "Image" || d.dType == "RGBA64Image" {
 				fmt.Fprintf(buf, ""+
@@ -753,24 +955,6 @@ func expnDollar(prefix, dollar, suffix string, d *data) string {
 				"%[1]sr%[2]s := uint32(src.Pix[%[1]si]) * 0x101\n",
 				lhs, tmp, pixOffset("src", args[0], args[1], "", "*src.Stride"),
 			)
-		case "*image.NRGBA":
-			fmt.Fprintf(buf, ""+
-				"%[1]si := %[3]s\n"+
-				"%[1]sa%[2]s := uint32(src.Pix[%[1]si+3]) * 0x101\n"+
-				"%[1]sr%[2]s := uint32(src.Pix[%[1]si+0]) * %[1]sa%s / 0xff\n"+
-				"%[1]sg%[2]s := uint32(src.Pix[%[1]si+1]) * %[1]sa%s / 0xff\n"+
-				"%[1]sb%[2]s := uint32(src.Pix[%[1]si+2]) * %[1]sa%s / 0xff\n",
-				lhs, tmp, pixOffset("src", args[0], args[1], "*4", "*src.Stride"),
-			)
-		case "*image.RGBA":
-			fmt.Fprintf(buf, ""+
-				"%[1]si := %[3]s\n"+
-				"%[1]sr%[2]s := uint32(src.Pix[%[1]si+0]) * 0x101\n"+
-				"%[1]sg%[2]s := uint32(src.Pix[%[1]si+1]) * 0x101\n"+
-				"%[1]sb%[2]s := uint32(src.Pix[%[1]si+2]) * 0x101\n"+
-				"%[1]sa%[2]s := uint32(src.Pix[%[1]si+3]) * 0x101\n",
-				lhs, tmp, pixOffset("src", args[0], args[1], "*4", "*src.Stride"),
-			)
 		case "*image.YCbCr":
 			fmt.Fprintf(buf, ""+
 				"%[1]si := %[2]s\n"+
@@ -780,6 +964,21 @@ func expnDollar(prefix, dollar, suffix string, d *data) string {
 				cOffset(args[0], args[1], d.sratio),
 				ycbcrToRGB(lhs, tmp),
 			)
+		case "*image.NYCbCrA":
+			fmt.Fprintf(buf, ""+
+				"%[1]si := %[3]s\n"+
+				"%[1]sj := %[4]s\n"+
+				"%[1]sk := %[5]s\n"+
+				"%[6]s\n"+
+				"%[1]sa%[2]s := uint32(src.A[%[1]sk]) * 0x101\n"+
+				"%[1]sr%[2]s := uint32(%[1]sr%[2]s0) * %[1]sa%[2]s / 0xffff\n"+
+				"%[1]sg%[2]s := uint32(%[1]sg%[2]s0) * %[1]sa%[2]s / 0xffff\n"+
+				"%[1]sb%[2]s := uint32(%[1]sb%[2]s0) * %[1]sa%[2]s / 0xffff\n",
+				lhs, tmp, pixOffset("src", args[0], args[1], "", "*src.YStride"),
+				cOffset(args[0], args[1], d.sratio),
+				pixOffset("src", args[0], args[1], "", "*src.AStride"),
+				ycbcrToRGB(lhs, tmp+"0"),
+			)
 		}
 
 		if dollar == "srcf" {
@@ -788,34 +987,15 @@ func expnDollar(prefix, dollar, suffix string, d *data) string {
 				avoidFMA0, avoidFMA1 = "float64(", ")"
 			}
 
-			switch d.sType {
-			default:
-				fmt.Fprintf(buf, ""+
-					"%[1]sr %[2]s %[4]sfloat64(%[1]sru)%[3]s%[5]s\n"+
-					"%[1]sg %[2]s %[4]sfloat64(%[1]sgu)%[3]s%[5]s\n"+
-					"%[1]sb %[2]s %[4]sfloat64(%[1]sbu)%[3]s%[5]s\n"+
-					"%[1]sa %[2]s %[4]sfloat64(%[1]sau)%[3]s%[5]s\n",
-					lhs, eqOp, extra, avoidFMA0, avoidFMA1,
-				)
-			case "*image.Gray":
-				fmt.Fprintf(buf, ""+
-					"%[1]sr %[2]s %[4]sfloat64(%[1]sru)%[3]s%[5]s\n",
-					lhs, eqOp, extra, avoidFMA0, avoidFMA1,
-				)
-			case "*image.YCbCr":
-				fmt.Fprintf(buf, ""+
-					"%[1]sr %[2]s %[4]sfloat64(%[1]sru)%[3]s%[5]s\n"+
-					"%[1]sg %[2]s %[4]sfloat64(%[1]sgu)%[3]s%[5]s\n"+
-					"%[1]sb %[2]s %[4]sfloat64(%[1]sbu)%[3]s%[5]s\n",
-					lhs, eqOp, extra, avoidFMA0, avoidFMA1,
-				)
-			case "image.RGBA64Image":
+			for _, c := range leafChannels(d) {
+				// u is the integer variable for the channel c.
+				u := fmt.Sprintf("%s%cu", lhs, c)
+				if d.sType == "image.RGBA64Image" {
+					u = fmt.Sprintf("%su.%c", lhs, c+'A'-'a')
+				}
 				fmt.Fprintf(buf, ""+
-					"%[1]sr %[2]s %[4]sfloat64(%[1]su.R)%[3]s%[5]s\n"+
-					"%[1]sg %[2]s %[4]sfloat64(%[1]su.G)%[3]s%[5]s\n"+
-					"%[1]sb %[2]s %[4]sfloat64(%[1]su.B)%[3]s%[5]s\n"+
-					"%[1]sa %[2]s %[4]sfloat64(%[1]su.A)%[3]s%[5]s\n",
-					lhs, eqOp, extra, avoidFMA0, avoidFMA1,
+					"%[1]s%[6]c %[2]s %[4]sfloat64(%[7]s)%[3]s%[5]s\n",
+					lhs, eqOp, extra, avoidFMA0, avoidFMA1, c, u,
 				)
 			}
 		}
@@ -823,49 +1003,63 @@ func expnDollar(prefix, dollar, suffix string, d *data) string {
 		return strings.TrimSpace(buf.String())
 
 	case "tweakD":
-		if d.dType == "*image.RGBA" {
+		if _, ok := layouts[d.dType]; ok {
 			return "d += dst.Stride"
 		}
 		return ";"
 
 	case "tweakDx":
-		if d.dType == "*image.RGBA" {
-			return strings.Replace(prefix, "dx++", "dx, d = dx+1, d+4", 1)
+		if l, ok := layouts[d.dType]; ok {
+			return strings.Replace(prefix, "dx++", fmt.Sprintf("dx, d = dx+1, d+%d", l.pixelSize()), 1)
 		}
 		return prefix
 
 	case "tweakDy":
-		if d.dType == "*image.RGBA" {
+		if _, ok := layouts[d.dType]; ok {
 			return strings.Replace(prefix, "for dy, s", "for _, s", 1)
 		}
 		return prefix
 
 	case "tweakP":
-		switch d.sType {
-		case "*image.Gray":
-			if strings.HasPrefix(strings.TrimSpace(prefix), "pa * ") {
-				return "1,"
-			}
+		// The prefix is the tmp element for the channel c of the variables
+		// set by the srcf expansion. Gray sets only pr, already scaled by
+		// tweakPr, which is also the green and blue. Opaque colors have an
+		// alpha of 1. Alpha-only colors, like color.Alpha, are premultiplied
+		// white, so their color channels are the alpha.
+		channels := leafChannels(d)
+		c := strings.TrimSpace(prefix)[1]
+		switch {
+		case c == 'a' && !hasChannel(channels, c):
+			return "1,"
+		case channels == "r":
 			return "pr,"
-		case "*image.YCbCr":
-			if strings.HasPrefix(strings.TrimSpace(prefix), "pa * ") {
-				return "1,"
-			}
+		case channels == "a":
+			return strings.Replace(prefix, "p"+string(c), "pa", 1)
 		}
 		return prefix
 
 	case "tweakPr":
-		if d.sType == "*image.Gray" {
+		if leafChannels(d) == "r" {
 			return "pr *= s.invTotalWeightFFFF"
 		}
 		return ";"
 
 	case "tweakVarP":
-		switch d.sType {
-		case "*image.Gray":
-			return strings.Replace(prefix, "var pr, pg, pb, pa", "var pr", 1)
-		case "*image.YCbCr":
-			return strings.Replace(prefix, "var pr, pg, pb, pa", "var pr, pg, pb", 1)
+		channels := leafChannels(d)
+		if channels == "rgba" {
+			return prefix
+		}
+		var vars []string
+		for _, c := range channels {
+			vars = append(vars, "p"+string(c))
+		}
+		return strings.Replace(prefix, "var pr, pg, pb, pa", "var "+strings.Join(vars, ", "), 1)
+
+	case "tweakChannel":
+		// The prefix sets the variable for the channel c, which is dropped
+		// if the leaf does not use it.
+		if !hasChannel(leafChannels(d), strings.TrimSpace(prefix)[1]) {
+			return ";"
 		}
 		return prefix
 	}
@@ -911,8 +1105,8 @@ func expnSwitch(op, dType string, expandBoth bool, template string) string {
 		}
 
 		if dType != "" {
-			if v == "*image.YCbCr" {
-				lines = append(lines, expnSwitchYCbCr(op, dType, template))
+			if isYCbCr(v) {
+				lines = append(lines, expnSwitchYCbCr(op, dType, v, template))
 			} else {
 				lines = append(lines, expnLine(template, &data{dType: dType, sType: v, op: op}))
 			}
@@ -927,7 +1121,13 @@ func expnSwitch(op, dType string, expandBoth bool, template string) string {
 	return strings.Join(lines, "\n")
 }
 
-func expnSwitchYCbCr(op, dType, template string) string {
+// isYCbCr returns whether sType is *image.YCbCr or *image.NYCbCrA, whose
+// leaves are generated for each of the subsampleRatios.
+func isYCbCr(sType string) bool {
+	return sType == "*image.YCbCr" || sType == "*image.NYCbCrA"
+}
+
+func expnSwitchYCbCr(op, dType, sType, template string) string {
 	lines := []string{
 		"switch src.SubsampleRatio {",
 		"default:",
@@ -936,7 +1136,7 @@ func expnSwitchYCbCr(op, dType, template string) string {
 	for _, sratio := range subsampleRatios {
 		lines = append(lines,
 			fmt.Sprintf("case image.YCbCrSubsampleRatio%s:", sratio),
-			expnLine(template, &data{dType: dType, sType: "*image.YCbCr", sratio: sratio, op: op}),
+			expnLine(template, &data{dType: dType, sType: sType, sratio: sratio, op: op}),
 		)
 	}
 	lines = append(lines, "}")
@@ -958,6 +1158,382 @@ func pixOffset(m, x, y, xstride, ystride string) string {
 	return fmt.Sprintf("(%s-%s.Rect.Min.Y)%s + (%s-%s.Rect.Min.X)%s", y, m, ystride, x, m, xstride)
 }
 
+// layout describes the pixels of an image type that, like *image.RGBA or
+// *image.RGBA64, has Pix, Stride and Rect fields and one or two bytes per
+// channel.
+type layout struct {
+	// channels has one of 'R', 'G', 'B', 'A' or 'X' for each channel of a
+	// pixel, where 'X' is a channel that is not used, or is "Y" for gray.
+	channels string
+	// premul is whether the color channels are alpha-premultiplied.
+	premul bool
+	// wide is whether each channel is a big-endian 16-bit value, as for
+	// *image.RGBA64, instead of a byte.
+	wide bool
+}
+
+// layouts are the image types whose pixels are read and written through
+// their layout.
+var layouts = map[string]layout{
+	"*image.Alpha":   {"A", true, false},
+	"*image.Gray":    {"Y", false, false},
+	"*image.NRGBA":   {"RGBA", false, false},
+	"*image.NRGBA64": {"RGBA", false, true},
+	"*image.RGBA":    {"RGBA", true, false},
+	"*image.RGBA64":  {"RGBA", true, true},
+}
+
+// pixelSize returns the number of bytes per pixel.
+func (l layout) pixelSize() int {
+	if l.wide {
+		return 2 * len(l.channels)
+	}
+	return len(l.channels)
+}
+
+func (l layout) xstride() string {
+	if l.pixelSize() == 1 {
+		return ""
+	}
+	return fmt.Sprintf("*%d", l.pixelSize())
+}
+
+func (l layout) hasAlpha() bool {
+	return strings.IndexByte(l.channels, 'A') >= 0
+}
+
+// alphaOnly returns whether l has an alpha channel and no color channels,
+// like *image.Alpha.
+func (l layout) alphaOnly() bool {
+	return strings.Trim(l.channels, "AX") == ""
+}
+
+// srcu returns the lines that set the lhs+"r"+tmp, etc. variables to the
+// 16-bit alpha-premultiplied color of the src pixel at lhs+"i". If alpha is
+// false, the alpha variable is only set if it is needed for the others.
+func (l layout) srcu(lhs, tmp string, alpha bool) string {
+	if l.wide {
+		return l.srcuWide(lhs, tmp, alpha)
+	}
+	buf := new(bytes.Buffer)
+	if l.hasAlpha() && !l.premul {
+		fmt.Fprintf(buf, "%[1]sa%[2]s := uint32(src.Pix[%[1]si+%[3]d]) * 0x101\n", lhs, tmp, strings.IndexByte(l.channels, 'A'))
+	}
+	for _, c := range "RGBA" {
+		i := strings.IndexRune(l.channels, c)
+		switch {
+		case i < 0:
+			// No-op.
+		case c != 'A' && l.hasAlpha() && !l.premul:
+			fmt.Fprintf(buf, "%[1]s%[4]c%[2]s := uint32(src.Pix[%[1]si+%[3]d]) * %[1]sa%[2]s / 0xff\n", lhs, tmp, i, c+'a'-'A')
+		case c != 'A' || l.premul && alpha:
+			fmt.Fprintf(buf, "%[1]s%[4]c%[2]s := uint32(src.Pix[%[1]si+%[3]d]) * 0x101\n", lhs, tmp, i, c+'a'-'A')
+		}
+	}
+	return buf.String()
+}
+
+// srcuWide is like srcu, for a layout with 16-bit channels.
+func (l layout) srcuWide(lhs, tmp string, alpha bool) string {
+	// v is the 16-bit value of the i'th channel.
+	v := func(i int) string {
+		return fmt.Sprintf("(uint32(src.Pix[%[1]si+%[2]d])<<8 | uint32(src.Pix[%[1]si+%[3]d]))", lhs, 2*i, 2*i+1)
+	}
+	buf := new(bytes.Buffer)
+	if l.hasAlpha() && !l.premul {
+		fmt.Fprintf(buf, "%sa%s := %s\n", lhs, tmp, v(strings.IndexByte(l.channels, 'A')))
+	}
+	for _, c := range "RGBA" {
+		i := strings.IndexRune(l.channels, c)
+		switch {
+		case i < 0:
+			// No-op.
+		case c != 'A' && l.hasAlpha() && !l.premul:
+			fmt.Fprintf(buf, "%[1]s%[3]c%[2]s := %[4]s * %[1]sa%[2]s / 0xffff\n", lhs, tmp, c+'a'-'A', v(i))
+		case c != 'A' || l.premul && alpha:
+			fmt.Fprintf(buf, "%[1]s%[3]c%[2]s := %[4]s\n", lhs, tmp, c+'a'-'A', v(i))
+		}
+	}
+	return buf.String()
+}
+
+// dropsAlpha returns whether d's leaf writes to a dst without alpha, and so
+// does not use the src alpha after premultiplying the other channels.
+func dropsAlpha(d *data) bool {
+	l, ok := layouts[d.dType]
+	return ok && !l.hasAlpha() && d.op == "Src"
+}
+
+// srcChannels returns the color variables, from "rgba", that an sType has:
+// "r" for gray, "rgb" for opaque color, "a" for alpha only and "rgba" for
+// color with alpha.
+func srcChannels(sType string) string {
+	switch sType {
+	case "*image.Gray":
+		return "r"
+	case "*image.YCbCr":
+		return "rgb"
+	}
+	if l, ok := layouts[sType]; ok {
+		if l.alphaOnly() {
+			return "a"
+		} else if !l.hasAlpha() {
+			return "rgb"
+		}
+	}
+	return "rgba"
+}
+
+// leafChannels returns the color variables, from "rgba", that d's leaf
+// computes: those of the src that the dst uses. An alpha-only dst uses only
+// the alpha.
+func leafChannels(d *data) string {
+	c := srcChannels(d.sType)
+	if l, ok := layouts[d.dType]; ok && l.alphaOnly() {
+		return strings.Trim(c, "rgb")
+	}
+	return c
+}
+
+// hasChannel returns whether channels, from "rgba", has the channel c.
+func hasChannel(channels string, c byte) bool {
+	return strings.IndexByte(channels, c) >= 0
+}
+
+// dstPix returns the lines that set each used byte of the dst pixel at d to
+// the value that f returns for that byte's channel, 'r', 'g', 'b' or 'a', and
+// offset.
+func dstPix(dType string, f func(c byte, i int) string) string {
+	var lines []string
+	for i, c := range []byte(layouts[dType].channels) {
+		if c == 'X' {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("dst.Pix[d+%d] = %s", i, f(c+'a'-'A', i)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// layoutOutputu is the outputu expansion template for a dType in layouts.
+func layoutOutputu(d *data) string {
+	// p is how the template refers to the src color's channel c.
+	p := func(c byte) string { return "$2" + string(c) }
+	if d.sType == "image.RGBA64Image" {
+		p = func(c byte) string { return "$2." + string(c+'A'-'a') }
+	}
+	if layouts[d.dType].channels == "Y" {
+		return grayOutputu(d)
+	}
+	if layouts[d.dType].wide {
+		return wideOutput(d, "$2", func(c byte) string {
+			switch {
+			case !hasChannel(srcChannels(d.sType), c):
+				if c == 'a' {
+					return "0xffff"
+				}
+				return p('r')
+			case d.sType == "image.RGBA64Image":
+				return "uint32(" + p(c) + ")"
+			}
+			return p(c)
+		})
+	}
+	if d.op == "Over" {
+		a1 := "$2a1 := (0xffff - $2a) * 0x101\n"
+		if d.sType == "image.RGBA64Image" {
+			a1 = "$2a1 := (0xffff - uint32($2.A)) * 0x101\n"
+			p = func(c byte) string { return "uint32($2." + string(c+'A'-'a') + ")" }
+		}
+		return a1 + dstPix(d.dType, func(c byte, i int) string {
+			return fmt.Sprintf("uint8((uint32(dst.Pix[d+%d])*$2a1/0xffff + %s) >> 8)", i, p(c))
+		})
+	}
+	switch srcChannels(d.sType) {
+	case "r":
+		return "out := uint8($2r >> 8)\n" + dstPix(d.dType, func(c byte, i int) string {
+			if c == 'a' {
+				return "0xff"
+			}
+			return "out"
+		})
+	case "rgb":
+		return dstPix(d.dType, func(c byte, i int) string {
+			if c == 'a' {
+				return "0xff"
+			}
+			return "uint8(" + p(c) + " >> 8)"
+		})
+	}
+	return dstPix(d.dType, func(c byte, i int) string {
+		return "uint8(" + p(c) + " >> 8)"
+	})
+}
+
+// layoutOutputf is the outputf expansion template for a dType in layouts.
+func layoutOutputf(d *data) string {
+	if layouts[d.dType].channels == "Y" {
+		return grayOutputf(d)
+	}
+	if layouts[d.dType].wide {
+		return wideOutput(d, "$3", func(c byte) string {
+			switch {
+			case !hasChannel(srcChannels(d.sType), c):
+				if c == 'a' {
+					return "0xffff"
+				}
+				return "uint32($2($3r * $4))"
+			}
+			return fmt.Sprintf("uint32($2($3%c * $4))", c)
+		})
+	}
+	if d.op == "Over" {
+		var lines []string
+		for _, c := range leafChannels(d) {
+			lines = append(lines, fmt.Sprintf("$3%[1]c0 := uint32($2($3%[1]c * $4))", c))
+		}
+		return strings.Join(lines, "\n") + "\n" +
+			"$3a1 := (0xffff - uint32($3a0)) * 0x101\n" +
+			dstPix(d.dType, func(c byte, i int) string {
+				return fmt.Sprintf("uint8((uint32(dst.Pix[d+%d])*$3a1/0xffff + $3%c0) >> 8)", i, c)
+			})
+	}
+	switch srcChannels(d.sType) {
+	case "r":
+		return "out := uint8($2($3r * $4) >> 8)\n" + dstPix(d.dType, func(c byte, i int) string {
+			if c == 'a' {
+				return "0xff"
+			}
+			return "out"
+		})
+	case "rgb":
+		return dstPix(d.dType, func(c byte, i int) string {
+			if c == 'a' {
+				return "0xff"
+			}
+			return fmt.Sprintf("uint8($2($3%c * $4) >> 8)", c)
+		})
+	}
+	return dstPix(d.dType, func(c byte, i int) string {
+		return fmt.Sprintf("uint8($2($3%c * $4) >> 8)", c)
+	})
+}
+
+// wideOutput is the outputu or outputf expansion template for a dType in
+// layouts with 16-bit channels. It sets the v+"r0", etc. variables to the
+// 16-bit alpha-premultiplied dst color, where src returns the expression for
+// the src color's channel c, and writes them to the dst pixel at d. As with
+// the RGBA64Image implementation, Over blends with the dst color, and a dst
+// that is not premultiplied has the result converted as by its SetRGBA64
+// method.
+func wideOutput(d *data, v string, src func(c byte) string) string {
+	l := layouts[d.dType]
+	// dstu returns the expression for the 16-bit value of the dst pixel's
+	// i'th channel.
+	dstu := func(i int) string {
+		return fmt.Sprintf("(uint32(dst.Pix[d+%d])<<8 | uint32(dst.Pix[d+%d]))", 2*i, 2*i+1)
+	}
+	opaque := src('a') == "0xffff"
+	unpremul := l.hasAlpha() && !l.premul && !(opaque && d.op == "Src")
+
+	var lines []string
+	if d.op == "Over" {
+		lines = append(lines, fmt.Sprintf("%sa1 := 0xffff - %s", v, src('a')))
+		if ai := strings.IndexByte(l.channels, 'A'); ai >= 0 && !l.premul {
+			lines = append(lines, fmt.Sprintf("%sqa := %s", v, dstu(ai)))
+		}
+	}
+	for i, c := range []byte(l.channels) {
+		if c == 'X' {
+			continue
+		}
+		c += 'a' - 'A'
+		value := src(c)
+		if d.op == "Over" {
+			q := dstu(i)
+			if !l.premul && c != 'a' {
+				q = fmt.Sprintf("(%s * %sqa / 0xffff)", q, v)
+			} else if !l.premul {
+				q = v + "qa"
+			}
+			value = fmt.Sprintf("%s*%sa1/0xffff + %s", q, v, value)
+		} else if value == "0xffff" {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s%c0 := %s", v, c, value))
+	}
+	if unpremul {
+		lines = append(lines, fmt.Sprintf("if %[1]sa0 != 0 && %[1]sa0 != 0xffff {", v))
+		for _, c := range []byte(l.channels) {
+			if c != 'A' && c != 'X' {
+				lines = append(lines, fmt.Sprintf("	%[1]s%[2]c0 = %[1]s%[2]c0 * 0xffff / %[1]sa0", v, c+'a'-'A'))
+			}
+		}
+		lines = append(lines, "}")
+	}
+	for i, c := range []byte(l.channels) {
+		if c == 'X' {
+			continue
+		}
+		c += 'a' - 'A'
+		if d.op == "Src" && src(c) == "0xffff" {
+			lines = append(lines,
+				fmt.Sprintf("dst.Pix[d+%d] = 0xff", 2*i),
+				fmt.Sprintf("dst.Pix[d+%d] = 0xff", 2*i+1),
+			)
+			continue
+		}
+		lines = append(lines,
+			fmt.Sprintf("dst.Pix[d+%d] = uint8(%s%c0 >> 8)", 2*i, v, c),
+			fmt.Sprintf("dst.Pix[d+%d] = uint8(%s%c0)", 2*i+1, v, c),
+		)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// luma returns the expression for the 8-bit gray value of the 16-bit color
+// values r, g and b, as computed by color.GrayModel.
+func luma(r, g, b string) string {
+	return fmt.Sprintf("uint8((19595*%s + 38470*%s + 7471*%s + 1<<15) >> 24)", r, g, b)
+}
+
+// grayOutputu is the outputu expansion template for an *image.Gray dType.
+// For Over, the src color is blended with the dst gray as with the generic
+// implementation, which then converts the result to gray.
+func grayOutputu(d *data) string {
+	if srcChannels(d.sType) == "r" {
+		return "dst.Pix[d] = uint8($2r >> 8)"
+	}
+	p := func(c byte) string { return "$2" + string(c) }
+	if d.sType == "image.RGBA64Image" {
+		p = func(c byte) string { return "uint32($2." + string(c+'A'-'a') + ")" }
+	}
+	if d.op == "Over" {
+		return "" +
+			"$2a1 := 0xffff - " + p('a') + "\n" +
+			"$2q := uint32(dst.Pix[d]) * 0x101 * $2a1 / 0xffff\n" +
+			"dst.Pix[d] = " + luma("($2q+"+p('r')+")", "($2q+"+p('g')+")", "($2q+"+p('b')+")")
+	}
+	return "dst.Pix[d] = " + luma(p('r'), p('g'), p('b'))
+}
+
+// grayOutputf is the outputf expansion template for an *image.Gray dType.
+func grayOutputf(d *data) string {
+	if srcChannels(d.sType) == "r" {
+		return "dst.Pix[d] = uint8($2($3r * $4) >> 8)"
+	}
+	s := "" +
+		"$3r0 := uint32($2($3r * $4))\n" +
+		"$3g0 := uint32($2($3g * $4))\n" +
+		"$3b0 := uint32($2($3b * $4))\n"
+	if d.op == "Over" {
+		return s +
+			"$3a0 := uint32($2($3a * $4))\n" +
+			"$3q := uint32(dst.Pix[d]) * 0x101 * (0xffff - $3a0) / 0xffff\n" +
+			"dst.Pix[d] = " + luma("($3q+$3r0)", "($3q+$3g0)", "($3q+$3b0)")
+	}
+	return s + "dst.Pix[d] = " + luma("$3r0", "$3g0", "$3b0")
+}
+
 func cOffset(x, y, sratio string) string {
 	switch sratio {
 	case "444":
@@ -972,6 +1548,9 @@ func cOffset(x, y, sratio string) string {
 	return fmt.Sprintf("unsupported sratio %q", sratio)
 }
 
+// ycbcrToRGB returns the lines that set the lhs+"r"+tmp, etc. variables to
+// the 16-bit color of the src pixel whose luma is at lhs+"i" and chroma is at
+// lhs+"j".
 func ycbcrToRGB(lhs, tmp string) string {
 	s := `
 		// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
@@ -1042,16 +1621,18 @@ func relName(s string) string {
 	if i := strings.LastIndex(s, "."); i >= 0 {
 		return s[i+1:]
 	}
-	return s
+	return strings.TrimPrefix(s, "*")
 }
 
 const (
 	codeRoot = `
 		func (z $receiver) Scale(dst Image, dr image.Rectangle, src image.Image, sr image.Rectangle, op Op, opts *Options) {
+			src, sr = untile(src, sr, opts)
+
 			// Try to simplify a Scale to a Copy when DstMask is not specified.
 			// If DstMask is not nil, Copy will call Scale back with same dr and sr, and cause stack overflow.
 			if dr.Size() == sr.Size() && (opts == nil || opts.DstMask == nil) {
-				Copy(dst, dr.Min, src, sr, op, opts)
+				Copy(dst, dr.Min, unalias(dst, dr, src, sr), sr, op, opts)
 				return
 			}
 
@@ -1066,6 +1647,7 @@ const (
 			if adr.Empty() || sr.Empty() {
 				return
 			}
+			src = unalias(dst, adr, src, sr)
 			// Make adr relative to dr.Min.
 			adr = adr.Sub(dr.Min)
 			if op == Over && o.SrcMask == nil && opaque(src) {
@@ -1085,8 +1667,12 @@ const (
 					z.scale_Image_Image_Src(dst, dr, adr, src, sr, &o)
 				}
 			} else if _, ok := src.(*image.Uniform); ok {
-				Draw(dst, dr, src, src.Bounds().Min, op)
+				// Scaling a uniform source is a fill of the affected
+				// destination pixels, which may have been clipped by a
+				// rectangular DstMask.
+				Draw(palettedDst(dst), adr.Add(dr.Min), src, src.Bounds().Min, op)
 			} else {
+				dst = palettedDst(dst)
 				$switch z.scale_$dTypeRN_$sTypeRN$sratio_$op(dst, dr, adr, src, sr, &o)
 			}
 		}
@@ -1097,7 +1683,8 @@ const (
 				dx := int(s2d[2])
 				dy := int(s2d[5])
 				if float64(dx) == s2d[2] && float64(dy) == s2d[5] {
-					Copy(dst, image.Point{X: sr.Min.X + dx, Y: sr.Min.X + dy}, src, sr, op, opts)
+					dp := image.Point{X: sr.Min.X + dx, Y: sr.Min.Y + dy}
+					Copy(dst, dp, unalias(dst, sr.Add(dp.Sub(sr.Min)), src, sr), sr, op, opts)
 					return
 				}
 			}
@@ -1114,6 +1701,7 @@ const (
 			if adr.Empty() || sr.Empty() {
 				return
 			}
+			src = unalias(dst, adr, src, sr)
 			if op == Over && o.SrcMask == nil && opaque(src) {
 				op = Src
 			}
@@ -1148,13 +1736,14 @@ const (
 			} else if u, ok := src.(*image.Uniform); ok {
 				transform_Uniform(dst, dr, adr, &d2s, u, sr, bias, op)
 			} else {
+				dst = palettedDst(dst)
 				$switch z.transform_$dTypeRN_$sTypeRN$sratio_$op(dst, dr, adr, &d2s, src, sr, bias, &o)
 			}
 		}
 	`
 
 	codeNNScaleLeaf = `
-		func (nnInterpolator) scale_$dTypeRN_$sTypeRN$sratio_$op(dst $dType, dr, adr image.Rectangle, src $sType, sr image.Rectangle, opts *Options) {
+		func (nnInterpolator) scale_$dTypeRN_$sTypeRN$sratio_$op(dst $dType, dr, adr image.Rectangle, src $sType, sr image.Rectangle, opts *$Options) {
 			dw2 := uint64(dr.Dx()) * 2
 			dh2 := uint64(dr.Dy()) * 2
 			sw := uint64(sr.Dx())
@@ -1173,7 +1762,7 @@ const (
 	`
 
 	codeNNTransformLeaf = `
-		func (nnInterpolator) transform_$dTypeRN_$sTypeRN$sratio_$op(dst $dType, dr, adr image.Rectangle, d2s *f64.Aff3, src $sType, sr image.Rectangle, bias image.Point, opts *Options) {
+		func (nnInterpolator) transform_$dTypeRN_$sTypeRN$sratio_$op(dst $dType, dr, adr image.Rectangle, d2s *f64.Aff3, src $sType, sr image.Rectangle, bias image.Point, opts *$Options) {
 			$preOuter
 			for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
 				dyf := float64(dr.Min.Y + int(dy)) + 0.5
@@ -1193,7 +1782,7 @@ const (
 	`
 
 	codeABLScaleLeaf = `
-		func (ablInterpolator) scale_$dTypeRN_$sTypeRN$sratio_$op(dst $dType, dr, adr image.Rectangle, src $sType, sr image.Rectangle, opts *Options) {
+		func (ablInterpolator) scale_$dTypeRN_$sTypeRN$sratio_$op(dst $dType, dr, adr image.Rectangle, src $sType, sr image.Rectangle, opts *$Options) {
 			sw := int32(sr.Dx())
 			sh := int32(sr.Dy())
 			yscale := float64(sh) / float64(dr.Dy())
@@ -1248,7 +1837,7 @@ const (
 	`
 
 	codeABLTransformLeaf = `
-		func (ablInterpolator) transform_$dTypeRN_$sTypeRN$sratio_$op(dst $dType, dr, adr image.Rectangle, d2s *f64.Aff3, src $sType, sr image.Rectangle, bias image.Point, opts *Options) {
+		func (ablInterpolator) transform_$dTypeRN_$sTypeRN$sratio_$op(dst $dType, dr, adr image.Rectangle, d2s *f64.Aff3, src $sType, sr image.Rectangle, bias image.Point, opts *$Options) {
 			$preOuter
 			for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
 				dyf := float64(dr.Min.Y + int(dy)) + 0.5
@@ -1306,7 +1895,16 @@ const (
 	codeKernelRoot = `
 		func (z *kernelScaler) Scale(dst Image, dr image.Rectangle, src image.Image, sr image.Rectangle, op Op, opts *Options) {
 			if z.dw != int32(dr.Dx()) || z.dh != int32(dr.Dy()) || z.sw != int32(sr.Dx()) || z.sh != int32(sr.Dy()) {
-				z.kernel.Scale(dst, dr, src, sr, op, opts)
+				newKernelScaler(z.kx, z.ky, dr.Dx(), dr.Dy(), sr.Dx(), sr.Dy(), false).Scale(dst, dr, src, sr, op, opts)
+				return
+			}
+			src, sr = untile(src, sr, opts)
+
+			// Try to simplify a Scale to a Copy, as for the non-kernel
+			// interpolators, when each dst pixel is a copy of one src pixel.
+			// Copy has no fast path when DstMask is not nil.
+			if z.identity && (opts == nil || opts.DstMask == nil) {
+				Copy(dst, dr.Min, unalias(dst, dr, src, sr), sr, op, opts)
 				return
 			}
 
@@ -1327,8 +1925,10 @@ const (
 				op = Src
 			}
 
+			// Scaling a uniform source is a fill. The kernel's weights sum
+			// to one, so there is no need to accumulate them.
 			if _, ok := src.(*image.Uniform); ok && o.DstMask == nil && o.SrcMask == nil && sr.In(src.Bounds()) {
-				Draw(dst, dr, src, src.Bounds().Min, op)
+				Draw(palettedDst(dst), adr.Add(dr.Min), src, src.Bounds().Min, op)
 				return
 			}
 
@@ -1363,6 +1963,7 @@ const (
 					z.scaleY_Image_Src(dst, dr, adr, tmp, &o)
 				}
 			} else {
+				dst = palettedDst(dst)
 				$switchD z.scaleY_$dTypeRN_$op(dst, dr, adr, tmp, &o)
 			}
 		}
@@ -1380,6 +1981,7 @@ const (
 			if adr.Empty() || sr.Empty() {
 				return
 			}
+			src = unalias(dst, adr, src, sr)
 			if op == Over && o.SrcMask == nil && opaque(src) {
 				op = Src
 			}
@@ -1399,7 +2001,9 @@ const (
 			// Make adr relative to dr.Min.
 			adr = adr.Sub(dr.Min)
 
-			if u, ok := src.(*image.Uniform); ok && o.DstMask != nil && o.SrcMask != nil && sr.In(src.Bounds()) {
+			// Likewise, transforming a uniform source fills those
+			// destination pixels that map to inside sr.
+			if u, ok := src.(*image.Uniform); ok && o.DstMask == nil && o.SrcMask == nil && sr.In(src.Bounds()) {
 				transform_Uniform(dst, dr, adr, &d2s, u, sr, bias, op)
 				return
 			}
@@ -1426,13 +2030,14 @@ const (
 					q.transform_Image_Image_Src(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
 				}
 			} else {
+				dst = palettedDst(dst)
 				$switch q.transform_$dTypeRN_$sTypeRN$sratio_$op(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
 			}
 		}
 	`
 
 	codeKernelScaleLeafX = `
-		func (z *kernelScaler) scaleX_$sTypeRN$sratio(tmp [][4]float64, src $sType, sr image.Rectangle, opts *Options) {
+		func (z *kernelScaler) scaleX_$sTypeRN$sratio(tmp [][4]float64, src $sType, sr image.Rectangle, opts *$Options) {
 			t := 0
 			$preKernelOuter
 			for y := int32(0); y < z.sh; y++ {
@@ -1460,13 +2065,13 @@ const (
 			for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
 				$preKernelInner
 				for dy, s := range z.vertical.sources[adr.Min.Y:adr.Max.Y] { $tweakDy
-					var pr, pg, pb, pa float64
+					var pr, pg, pb, pa float64 $tweakVarP
 					for _, c := range z.vertical.contribs[s.i:s.j] {
 						p := &tmp[c.coord*z.dw+dx]
-						pr += float64(p[0] * c.weight)
-						pg += float64(p[1] * c.weight)
-						pb += float64(p[2] * c.weight)
-						pa += float64(p[3] * c.weight)
+						pr += float64(p[0] * c.weight) $tweakChannel
+						pg += float64(p[1] * c.weight) $tweakChannel
+						pb += float64(p[2] * c.weight) $tweakChannel
+						pa += float64(p[3] * c.weight) $tweakChannel
 					}
 					$clampToAlpha
 					$outputf[dr.Min.X + int(dx), dr.Min.Y + int(adr.Min.Y + dy), ftou, p, s.invTotalWeight]
@@ -1477,7 +2082,7 @@ const (
 	`
 
 	codeKernelTransformLeaf = `
-		func (q *Kernel) transform_$dTypeRN_$sTypeRN$sratio_$op(dst $dType, dr, adr image.Rectangle, d2s *f64.Aff3, src $sType, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+		func (q *Kernel) transform_$dTypeRN_$sTypeRN$sratio_$op(dst $dType, dr, adr image.Rectangle, d2s *f64.Aff3, src $sType, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *$Options) {
 			// When shrinking, broaden the effective kernel support so that we still
 			// visit every source pixel.
 			xHalfWidth, xKernelArgScale := q.Support, 1.0
@@ -1491,8 +2096,10 @@ const (
 				yKernelArgScale = 1 / yscale
 			}
 
-			xWeights := make([]float64, 1 + 2*int(math.Ceil(xHalfWidth)))
-			yWeights := make([]float64, 1 + 2*int(math.Ceil(yHalfWidth)))
+			// The weights of small kernels fit in arrays on the stack.
+			var xWeightsBuf, yWeightsBuf [32]float64
+			xWeights := makeWeights(xWeightsBuf[:], 1 + 2*int(math.Ceil(xHalfWidth)))
+			yWeights := makeWeights(yWeightsBuf[:], 1 + 2*int(math.Ceil(yHalfWidth)))
 
 			$preOuter
 			for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
@@ -1572,4 +2179,236 @@ const (
 			}
 		}
 	`
+
+	codeStandaloneRoot = `
+		// Scale implements the draw.Scaler interface.
+		func (z $receiver) Scale(dst draw.Image, dr image.Rectangle, src image.Image, sr image.Rectangle, op draw.Op, opts *draw.Options) {
+			// The fast paths assume that there are no options, and, as they
+			// access the Pix fields directly without bounds checking, that sr
+			// is within the src bounds.
+			if opts == nil && sr.In(src.Bounds()) {
+				// adr is the affected destination pixels.
+				adr := dst.Bounds().Intersect(dr)
+				if adr.Empty() || sr.Empty() {
+					return
+				}
+				// Make adr relative to dr.Min.
+				adr = adr.Sub(dr.Min)
+				$standaloneSwitch z.scale_$dTypeRN_$sTypeRN_$op(dst, dr, adr, src, sr, nil); return
+			}
+			$fallback.Scale(dst, dr, src, sr, op, opts)
+		}
+
+		// Transform implements the draw.Transformer interface.
+		func (z $receiver) Transform(dst draw.Image, s2d f64.Aff3, src image.Image, sr image.Rectangle, op draw.Op, opts *draw.Options) {
+			if opts == nil && sr.In(src.Bounds()) {
+				dr := transformRect(&s2d, &sr)
+				// adr is the affected destination pixels.
+				adr := dst.Bounds().Intersect(dr)
+				if adr.Empty() || sr.Empty() {
+					return
+				}
+				d2s, bias := invertBiased(&s2d, &adr)
+				// Make adr relative to dr.Min.
+				adr = adr.Sub(dr.Min)
+				$standaloneSwitch z.transform_$dTypeRN_$sTypeRN_$op(dst, dr, adr, &d2s, src, sr, bias, nil); return
+			}
+			$fallback.Transform(dst, s2d, src, sr, op, opts)
+		}
+	`
+
+	codeStandaloneKernelRoot = `
+		// Kernel is like draw.Kernel, with fast paths for more image types.
+		// Convert a *draw.Kernel, such as draw.CatmullRom, to a *Kernel to use
+		// them.
+		//
+		// The Scale fast paths compute each dst pixel from a two-dimensional
+		// window of src pixels, as the Transform ones do. They can be slower
+		// than a draw.Kernel's Scale, which makes separate horizontal and
+		// vertical passes, when scaling down by a large factor.
+		type Kernel draw.Kernel
+
+		var (
+			// BiLinear is draw.BiLinear, with fast paths for more image types.
+			BiLinear = (*Kernel)(draw.BiLinear)
+
+			// CatmullRom is draw.CatmullRom, with fast paths for more image
+			// types.
+			CatmullRom = (*Kernel)(draw.CatmullRom)
+		)
+
+		// Scale implements the draw.Scaler interface.
+		func (q *Kernel) Scale(dst draw.Image, dr image.Rectangle, src image.Image, sr image.Rectangle, op draw.Op, opts *draw.Options) {
+			if opts == nil && sr.In(src.Bounds()) && !dr.Empty() && !sr.Empty() {
+				// s2d maps sr to dr.
+				xs := float64(dr.Dx()) / float64(sr.Dx())
+				ys := float64(dr.Dy()) / float64(sr.Dy())
+				s2d := f64.Aff3{
+					xs, 0, float64(dr.Min.X) - float64(sr.Min.X)*xs,
+					0, ys, float64(dr.Min.Y) - float64(sr.Min.Y)*ys,
+				}
+				if q.transform(dst, dr, &s2d, src, sr, op) {
+					return
+				}
+			}
+			(*draw.Kernel)(q).Scale(dst, dr, src, sr, op, opts)
+		}
+
+		// Transform implements the draw.Transformer interface.
+		func (q *Kernel) Transform(dst draw.Image, s2d f64.Aff3, src image.Image, sr image.Rectangle, op draw.Op, opts *draw.Options) {
+			if opts == nil && sr.In(src.Bounds()) && q.transform(dst, transformRect(&s2d, &sr), &s2d, src, sr, op) {
+				return
+			}
+			(*draw.Kernel)(q).Transform(dst, s2d, src, sr, op, opts)
+		}
+
+		// transform transforms src by s2d to the dr part of dst, and returns
+		// whether there is a fast path for dst and src.
+		func (q *Kernel) transform(dst draw.Image, dr image.Rectangle, s2d *f64.Aff3, src image.Image, sr image.Rectangle, op draw.Op) bool {
+			// adr is the affected destination pixels.
+			adr := dst.Bounds().Intersect(dr)
+			if adr.Empty() || sr.Empty() {
+				return true
+			}
+			d2s, bias := invertBiased(s2d, &adr)
+			// Make adr relative to dr.Min.
+			adr = adr.Sub(dr.Min)
+
+			xscale := abs(d2s[0])
+			if s := abs(d2s[1]); xscale < s {
+				xscale = s
+			}
+			yscale := abs(d2s[3])
+			if s := abs(d2s[4]); yscale < s {
+				yscale = s
+			}
+
+			$standaloneSwitch q.transform_$dTypeRN_$sTypeRN_$op(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, nil); return true
+			return false
+		}
+	`
+
+	// codeStandaloneHelpers are copies of the unexported draw package
+	// functions that the standalone roots and leaves use.
+	codeStandaloneHelpers = `
+		// sharePix reports whether a and b have the same backing array, as an
+		// image and its sub-images do.
+		func sharePix(a, b []uint8) bool {
+			return cap(a) > 0 && cap(b) > 0 && &a[:cap(a)][cap(a)-1] == &b[:cap(b)][cap(b)-1]
+		}
+
+		// invertBiased returns the inverse of s2d, translated by bias so that
+		// it maps adr to non-negative src coordinates, as the transform leaves
+		// expect.
+		func invertBiased(s2d *f64.Aff3, adr *image.Rectangle) (d2s f64.Aff3, bias image.Point) {
+			d2s = invert(s2d)
+			// bias is a translation of the mapping from dst coordinates to src
+			// coordinates such that the latter temporarily have non-negative X
+			// and Y coordinates. This allows us to write int(f) instead of
+			// int(math.Floor(f)), since "round to zero" and "round down" are
+			// equivalent when f >= 0, but the former is much cheaper. The X--
+			// and Y-- are because the transform leaves have a "sx -= 0.5"
+			// adjustment.
+			bias = transformRect(&d2s, adr).Min
+			bias.X--
+			bias.Y--
+			d2s[2] -= float64(bias.X)
+			d2s[5] -= float64(bias.Y)
+			return d2s, bias
+		}
+
+		// makeWeights returns a slice of n kernel weights, using buf if it is
+		// large enough.
+		func makeWeights(buf []float64, n int) []float64 {
+			if n <= len(buf) {
+				return buf[:n]
+			}
+			return make([]float64, n)
+		}
+
+		func abs(f float64) float64 {
+			if f < 0 {
+				f = -f
+			}
+			return f
+		}
+
+		// fffftou converts the range [0.0, 65535.0] to [0, 0xffff].
+		func fffftou(f float64) uint16 {
+			i := int32(f + 0.5)
+			if i > 0xffff {
+				return 0xffff
+			}
+			if i > 0 {
+				return uint16(i)
+			}
+			return 0
+		}
+
+		// invert returns the inverse of m.
+		func invert(m *f64.Aff3) f64.Aff3 {
+			m00 := +m[3*1+1]
+			m01 := -m[3*0+1]
+			m02 := +float64(m[3*1+2]*m[3*0+1]) - float64(m[3*1+1]*m[3*0+2])
+			m10 := -m[3*1+0]
+			m11 := +m[3*0+0]
+			m12 := +float64(m[3*1+0]*m[3*0+2]) - float64(m[3*1+2]*m[3*0+0])
+
+			det := float64(m00*m11) - float64(m10*m01)
+
+			return f64.Aff3{
+				m00 / det,
+				m01 / det,
+				m02 / det,
+				m10 / det,
+				m11 / det,
+				m12 / det,
+			}
+		}
+
+		// transformRect returns a rectangle dr that contains sr transformed by
+		// s2d.
+		func transformRect(s2d *f64.Aff3, sr *image.Rectangle) (dr image.Rectangle) {
+			ps := [...]image.Point{
+				{sr.Min.X, sr.Min.Y},
+				{sr.Max.X, sr.Min.Y},
+				{sr.Min.X, sr.Max.Y},
+				{sr.Max.X, sr.Max.Y},
+			}
+			for i, p := range ps {
+				sxf := float64(p.X)
+				syf := float64(p.Y)
+				dx := int(math.Floor(float64(s2d[0]*sxf) + float64(s2d[1]*syf) + s2d[2]))
+				dy := int(math.Floor(float64(s2d[3]*sxf) + float64(s2d[4]*syf) + s2d[5]))
+
+				// The +1 adjustments below are because an image.Rectangle is
+				// inclusive on the low end but exclusive on the high end.
+
+				if i == 0 {
+					dr = image.Rectangle{
+						Min: image.Point{dx + 0, dy + 0},
+						Max: image.Point{dx + 1, dy + 1},
+					}
+					continue
+				}
+
+				if dr.Min.X > dx {
+					dr.Min.X = dx
+				}
+				dx++
+				if dr.Max.X < dx {
+					dr.Max.X = dx
+				}
+
+				if dr.Min.Y > dy {
+					dr.Min.Y = dy
+				}
+				dy++
+				if dr.Max.Y < dy {
+					dr.Max.Y = dy
+				}
+			}
+			return dr
+		}
+	`
 )
@@ -0,0 +1,212 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package draw
+
+import (
+	"image"
+	"image/color"
+)
+
+// OrderedDither is a Drawer that is the Src Op with ordered dithering, for
+// destination images whose color model is a color.Palette, such as
+// *image.Paletted images. For other destination images, it is the Src Op
+// without dithering.
+//
+// Unlike FloydSteinberg, whose error diffusion depends on the rectangle being
+// drawn, the color of each dithered pixel depends only on its source color,
+// the palette and its position in dst. Drawing an image one tile at a time, in
+// any order, gives the same pixels as drawing it all at once. The dithering
+// uses only integer arithmetic, so that it also gives the same pixels on every
+// machine.
+type OrderedDither struct {
+	// Matrix is the threshold matrix, tiled over dst so that the pixel at
+	// (x, y) uses the threshold Matrix[y%n][x%m], for an n×m matrix, after
+	// making x and y non-negative. Its values range from 0 to n×m-1. A nil
+	// Matrix means BayerMatrix(8).
+	Matrix [][]int
+}
+
+// Draw implements the Drawer interface.
+func (d OrderedDither) Draw(dst Image, r image.Rectangle, src image.Image, sp image.Point) {
+	m := d.Matrix
+	if m == nil {
+		m = bayer8
+	}
+	n := 0
+	for _, row := range m {
+		n += len(row)
+	}
+	if n == 0 {
+		Draw(dst, r, src, sp, Src)
+		return
+	}
+	ditherDraw(dst, r, src, sp, func(x, y int) uint32 {
+		row := m[mod(y, len(m))]
+		if len(row) == 0 {
+			return 0x8000
+		}
+		// Center each threshold within its 1/n of the range.
+		return uint32((2*row[mod(x, len(row))] + 1) * 0x8000 / n)
+	})
+}
+
+// NoiseDither is a Drawer that is the Src Op with random dithering, for
+// destination images whose color model is a color.Palette. For other
+// destination images, it is the Src Op without dithering.
+//
+// The thresholds are pseudo-random, but they are a function only of Seed and
+// of each pixel's position in dst. As for OrderedDither, drawing an image one
+// tile at a time gives the same pixels as drawing it all at once, on every
+// machine, and drawing it again with the same Seed reproduces it exactly.
+type NoiseDither struct {
+	Seed uint64
+}
+
+// Draw implements the Drawer interface.
+func (d NoiseDither) Draw(dst Image, r image.Rectangle, src image.Image, sp image.Point) {
+	ditherDraw(dst, r, src, sp, func(x, y int) uint32 {
+		// This is the SplitMix64 finalizer, mixing the seed and the position.
+		h := d.Seed ^ uint64(uint32(x)) ^ uint64(uint32(y))<<32
+		h = (h ^ h>>30) * 0xbf58476d1ce4e5b9
+		h = (h ^ h>>27) * 0x94d049bb133111eb
+		h ^= h >> 31
+		return uint32(h >> 48)
+	})
+}
+
+// BayerMatrix returns the n×n Bayer threshold matrix, also known as an
+// index matrix, for use as an OrderedDither's Matrix. n must be a power of 2,
+// from 1 to 256.
+func BayerMatrix(n int) [][]int {
+	if n < 1 || n > 256 || n&(n-1) != 0 {
+		panic("draw: invalid Bayer matrix size")
+	}
+	m := [][]int{{0}}
+	for len(m) < n {
+		k := len(m)
+		next := make([][]int, 2*k)
+		for y := range next {
+			next[y] = make([]int, 2*k)
+			for x := range next[y] {
+				v := 4 * m[y%k][x%k]
+				switch {
+				case y < k && x >= k:
+					v += 2
+				case y >= k && x < k:
+					v += 3
+				case y >= k && x >= k:
+					v += 1
+				}
+				next[y][x] = v
+			}
+		}
+		m = next
+	}
+	return m
+}
+
+var bayer8 = BayerMatrix(8)
+
+// mod returns x modulo n, which is non-negative even when x is negative.
+func mod(x, n int) int {
+	x %= n
+	if x < 0 {
+		x += n
+	}
+	return x
+}
+
+// ditherDraw is the Src Op with dithering. threshold returns the threshold
+// for the dst pixel at (x, y), ranging from 0 to 0xffff. Each of the pixel's
+// red, green and blue values is offset by that threshold, less 0x8000, scaled
+// by the spread of that channel's values in the palette, and the pixel is set
+// to the palette color nearest to the result.
+func ditherDraw(dst Image, r image.Rectangle, src image.Image, sp image.Point, threshold func(x, y int) uint32) {
+	p, ok := dst.ColorModel().(color.Palette)
+	if !ok || len(p) == 0 {
+		Draw(dst, r, src, sp, Src)
+		return
+	}
+	orig := r.Min
+	r = r.Intersect(dst.Bounds())
+	r = r.Intersect(src.Bounds().Add(orig.Sub(sp)))
+	if r.Empty() {
+		return
+	}
+	sp = sp.Add(r.Min.Sub(orig))
+
+	spread := paletteSpread(p)
+	paletted, _ := dst.(*image.Paletted)
+	rgba64, _ := src.(image.RGBA64Image)
+	// cache maps dithered colors to palette indexes, as many pixels of a
+	// typical image share their dithered colors.
+	cache := map[color.RGBA64]int{}
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		sy := sp.Y + y - r.Min.Y
+		for x := r.Min.X; x < r.Max.X; x++ {
+			sx := sp.X + x - r.Min.X
+			var c color.RGBA64
+			if rgba64 != nil {
+				c = rgba64.RGBA64At(sx, sy)
+			} else {
+				sr, sg, sb, sa := src.At(sx, sy).RGBA()
+				c = color.RGBA64{uint16(sr), uint16(sg), uint16(sb), uint16(sa)}
+			}
+			t := int32(threshold(x, y)) - 0x8000
+			c.R = ditherChannel(c.R, c.A, t, spread[0])
+			c.G = ditherChannel(c.G, c.A, t, spread[1])
+			c.B = ditherChannel(c.B, c.A, t, spread[2])
+			i, ok := cache[c]
+			if !ok {
+				i = p.Index(c)
+				cache[c] = i
+			}
+			if paletted != nil {
+				paletted.Pix[paletted.PixOffset(x, y)] = uint8(i)
+			} else {
+				dst.Set(x, y, p[i])
+			}
+		}
+	}
+}
+
+// ditherChannel returns the premultiplied channel value v, offset by t, which
+// ranges from -0x8000 to 0x7fff, scaled by spread/0x10000, and clamped to the
+// alpha value a.
+func ditherChannel(v, a uint16, t int32, spread uint32) uint16 {
+	w := int32(v) + int32(int64(t)*int64(spread)>>16)
+	if w < 0 {
+		return 0
+	} else if w > int32(a) {
+		return a
+	}
+	return uint16(w)
+}
+
+// paletteSpread returns, for each of the red, green and blue channels, the
+// largest gap between consecutive distinct values of that channel among the
+// colors of p. That is the range over which dithering needs to spread a
+// channel's values for them to reach a neighboring palette color.
+func paletteSpread(p color.Palette) (spread [3]uint32) {
+	for ch := range spread {
+		var seen [0x10000 / 64]uint64
+		for _, c := range p {
+			r, g, b, _ := c.RGBA()
+			v := [3]uint32{r, g, b}[ch]
+			seen[v/64] |= 1 << (v % 64)
+		}
+		prev := -1
+		for v := 0; v < 0x10000; v++ {
+			if seen[v/64]&(1<<(v%64)) == 0 {
+				continue
+			}
+			if prev >= 0 && uint32(v-prev) > spread[ch] {
+				spread[ch] = uint32(v - prev)
+			}
+			prev = v
+		}
+	}
+	return spread
+}
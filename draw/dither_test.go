@@ -0,0 +1,103 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package draw
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"reflect"
+	"testing"
+)
+
+func TestBayerMatrix(t *testing.T) {
+	if got, want := BayerMatrix(2), [][]int{{0, 2}, {3, 1}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("BayerMatrix(2): got %v, want %v", got, want)
+	}
+	for _, n := range []int{1, 4, 16} {
+		m := BayerMatrix(n)
+		seen := make([]bool, n*n)
+		for _, row := range m {
+			for _, v := range row {
+				if v < 0 || v >= n*n || seen[v] {
+					t.Fatalf("BayerMatrix(%d): invalid or repeated value %d", n, v)
+				}
+				seen[v] = true
+			}
+		}
+	}
+}
+
+// ditherSrc returns a horizontal gradient, from black to white.
+func ditherSrc() *image.RGBA {
+	src := image.NewRGBA(image.Rect(-10, 5, 54, 37))
+	for y := src.Rect.Min.Y; y < src.Rect.Max.Y; y++ {
+		for x := src.Rect.Min.X; x < src.Rect.Max.X; x++ {
+			v := uint8((x - src.Rect.Min.X) * 4)
+			src.SetRGBA(x, y, color.RGBA{v, v, v, 0xff})
+		}
+	}
+	return src
+}
+
+func TestDitherTiles(t *testing.T) {
+	src := ditherSrc()
+	bw := color.Palette{color.Black, color.White}
+	for _, d := range []Drawer{OrderedDither{}, OrderedDither{Matrix: BayerMatrix(4)}, NoiseDither{Seed: 42}} {
+		whole := image.NewPaletted(image.Rect(-3, -3, 61, 29), bw)
+		d.Draw(whole, whole.Rect, src, src.Rect.Min)
+
+		// Drawing in tiles, in a different order, gives the same pixels.
+		tiled := image.NewPaletted(whole.Rect, bw)
+		for ty := 1; ty >= 0; ty-- {
+			for tx := 1; tx >= 0; tx-- {
+				r := image.Rect(-3+32*tx, -3+16*ty, -3+32*(tx+1), -3+16*(ty+1))
+				d.Draw(tiled, r, src, src.Rect.Min.Add(r.Min.Sub(whole.Rect.Min)))
+			}
+		}
+		if !bytes.Equal(whole.Pix, tiled.Pix) {
+			t.Errorf("%#v: tiles differ from the whole image", d)
+		}
+
+		// The proportion of white pixels follows the gradient.
+		for _, x := range []int{-3, 29, 60} {
+			n := 0
+			for y := whole.Rect.Min.Y; y < whole.Rect.Max.Y; y++ {
+				n += int(whole.ColorIndexAt(x, y))
+			}
+			want := (x - whole.Rect.Min.X) * whole.Rect.Dy() / whole.Rect.Dx()
+			if n < want-8 || n > want+8 {
+				t.Errorf("%#v: x=%d: got %d white pixels, want about %d", d, x, n, want)
+			}
+		}
+	}
+}
+
+func TestNoiseDitherSeed(t *testing.T) {
+	src := ditherSrc()
+	draw := func(seed uint64) []byte {
+		dst := image.NewPaletted(src.Rect, palette.WebSafe)
+		NoiseDither{Seed: seed}.Draw(dst, dst.Rect, src, src.Rect.Min)
+		return dst.Pix
+	}
+	if !bytes.Equal(draw(1), draw(1)) {
+		t.Error("same seed: got different pixels")
+	}
+	if bytes.Equal(draw(1), draw(2)) {
+		t.Error("different seeds: got the same pixels")
+	}
+}
+
+func TestDitherNonPaletted(t *testing.T) {
+	src := ditherSrc()
+	want := image.NewRGBA(src.Rect)
+	Draw(want, want.Rect, src, src.Rect.Min, Src)
+	got := image.NewRGBA(src.Rect)
+	OrderedDither{}.Draw(got, got.Rect, src, src.Rect.Min)
+	if !bytes.Equal(got.Pix, want.Pix) {
+		t.Error("got dithered pixels, want the Src Op's")
+	}
+}
@@ -9,6 +9,16 @@
 //
 // This package is a superset of and a drop-in replacement for the image/draw
 // package in the standard library.
+//
+// The interpolators have fast paths for the image package's RGBA, NRGBA,
+// RGBA64, NRGBA64, Gray, YCbCr and NYCbCrA types. Those for RGBA64 and NRGBA64
+// images keep the full 16 bits of precision of each channel. Applications
+// with other image types, such as BGRA images, can generate fast paths for
+// them, in their own package, by running
+//
+//	go run golang.org/x/image/draw/cmd/drawgen -config config.json -o impl.go
+//
+// See the config type in cmd/drawgen for details.
 package draw
 
 // This file just contains the API exported by the image/draw package in the
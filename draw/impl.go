@@ -1,4 +1,4 @@
-// generated by "go run gen.go". DO NOT EDIT.
+// generated by "go run ./cmd/drawgen". DO NOT EDIT.
 
 package draw
 
@@ -11,10 +11,12 @@ import (
 )
 
 func (z nnInterpolator) Scale(dst Image, dr image.Rectangle, src image.Image, sr image.Rectangle, op Op, opts *Options) {
+	src, sr = untile(src, sr, opts)
+
 	// Try to simplify a Scale to a Copy when DstMask is not specified.
 	// If DstMask is not nil, Copy will call Scale back with same dr and sr, and cause stack overflow.
 	if dr.Size() == sr.Size() && (opts == nil || opts.DstMask == nil) {
-		Copy(dst, dr.Min, src, sr, op, opts)
+		Copy(dst, dr.Min, unalias(dst, dr, src, sr), sr, op, opts)
 		return
 	}
 
@@ -29,6 +31,7 @@ func (z nnInterpolator) Scale(dst Image, dr image.Rectangle, src image.Image, sr
 	if adr.Empty() || sr.Empty() {
 		return
 	}
+	src = unalias(dst, adr, src, sr)
 	// Make adr relative to dr.Min.
 	adr = adr.Sub(dr.Min)
 	if op == Over && o.SrcMask == nil && opaque(src) {
@@ -48,22 +51,77 @@ func (z nnInterpolator) Scale(dst Image, dr image.Rectangle, src image.Image, sr
 			z.scale_Image_Image_Src(dst, dr, adr, src, sr, &o)
 		}
 	} else if _, ok := src.(*image.Uniform); ok {
-		Draw(dst, dr, src, src.Bounds().Min, op)
+		// Scaling a uniform source is a fill of the affected
+		// destination pixels, which may have been clipped by a
+		// rectangular DstMask.
+		Draw(palettedDst(dst), adr.Add(dr.Min), src, src.Bounds().Min, op)
 	} else {
+		dst = palettedDst(dst)
 		switch op {
 		case Over:
 			switch dst := dst.(type) {
+			case *image.Alpha:
+				switch src := src.(type) {
+				case *image.Alpha:
+					z.scale_Alpha_Alpha_Over(dst, dr, adr, src, sr, &o)
+				case image.RGBA64Image:
+					z.scale_Alpha_RGBA64Image_Over(dst, dr, adr, src, sr, &o)
+				default:
+					z.scale_Alpha_Image_Over(dst, dr, adr, src, sr, &o)
+				}
+			case *image.Gray:
+				switch src := src.(type) {
+				case image.RGBA64Image:
+					z.scale_Gray_RGBA64Image_Over(dst, dr, adr, src, sr, &o)
+				default:
+					z.scale_Gray_Image_Over(dst, dr, adr, src, sr, &o)
+				}
 			case *image.RGBA:
 				switch src := src.(type) {
 				case *image.NRGBA:
 					z.scale_RGBA_NRGBA_Over(dst, dr, adr, src, sr, &o)
 				case *image.RGBA:
 					z.scale_RGBA_RGBA_Over(dst, dr, adr, src, sr, &o)
+				case *image.NYCbCrA:
+					switch src.SubsampleRatio {
+					default:
+						z.scale_RGBA_Image_Over(dst, dr, adr, src, sr, &o)
+					case image.YCbCrSubsampleRatio444:
+						z.scale_RGBA_NYCbCrA444_Over(dst, dr, adr, src, sr, &o)
+					case image.YCbCrSubsampleRatio422:
+						z.scale_RGBA_NYCbCrA422_Over(dst, dr, adr, src, sr, &o)
+					case image.YCbCrSubsampleRatio420:
+						z.scale_RGBA_NYCbCrA420_Over(dst, dr, adr, src, sr, &o)
+					case image.YCbCrSubsampleRatio440:
+						z.scale_RGBA_NYCbCrA440_Over(dst, dr, adr, src, sr, &o)
+					}
 				case image.RGBA64Image:
 					z.scale_RGBA_RGBA64Image_Over(dst, dr, adr, src, sr, &o)
 				default:
 					z.scale_RGBA_Image_Over(dst, dr, adr, src, sr, &o)
 				}
+			case *image.RGBA64:
+				switch src := src.(type) {
+				case *image.NRGBA64:
+					z.scale_RGBA64_NRGBA64_Over(dst, dr, adr, src, sr, &o)
+				case *image.RGBA64:
+					z.scale_RGBA64_RGBA64_Over(dst, dr, adr, src, sr, &o)
+				case image.RGBA64Image:
+					z.scale_RGBA64_RGBA64Image_Over(dst, dr, adr, src, sr, &o)
+				default:
+					z.scale_RGBA64_Image_Over(dst, dr, adr, src, sr, &o)
+				}
+			case *image.NRGBA64:
+				switch src := src.(type) {
+				case *image.NRGBA64:
+					z.scale_NRGBA64_NRGBA64_Over(dst, dr, adr, src, sr, &o)
+				case *image.RGBA64:
+					z.scale_NRGBA64_RGBA64_Over(dst, dr, adr, src, sr, &o)
+				case image.RGBA64Image:
+					z.scale_NRGBA64_RGBA64Image_Over(dst, dr, adr, src, sr, &o)
+				default:
+					z.scale_NRGBA64_Image_Over(dst, dr, adr, src, sr, &o)
+				}
 			case RGBA64Image:
 				switch src := src.(type) {
 				case image.RGBA64Image:
@@ -77,6 +135,24 @@ func (z nnInterpolator) Scale(dst Image, dr image.Rectangle, src image.Image, sr
 			}
 		case Src:
 			switch dst := dst.(type) {
+			case *image.Alpha:
+				switch src := src.(type) {
+				case *image.Alpha:
+					z.scale_Alpha_Alpha_Src(dst, dr, adr, src, sr, &o)
+				case image.RGBA64Image:
+					z.scale_Alpha_RGBA64Image_Src(dst, dr, adr, src, sr, &o)
+				default:
+					z.scale_Alpha_Image_Src(dst, dr, adr, src, sr, &o)
+				}
+			case *image.Gray:
+				switch src := src.(type) {
+				case *image.Gray:
+					z.scale_Gray_Gray_Src(dst, dr, adr, src, sr, &o)
+				case image.RGBA64Image:
+					z.scale_Gray_RGBA64Image_Src(dst, dr, adr, src, sr, &o)
+				default:
+					z.scale_Gray_Image_Src(dst, dr, adr, src, sr, &o)
+				}
 			case *image.RGBA:
 				switch src := src.(type) {
 				case *image.Gray:
@@ -98,11 +174,46 @@ func (z nnInterpolator) Scale(dst Image, dr image.Rectangle, src image.Image, sr
 					case image.YCbCrSubsampleRatio440:
 						z.scale_RGBA_YCbCr440_Src(dst, dr, adr, src, sr, &o)
 					}
+				case *image.NYCbCrA:
+					switch src.SubsampleRatio {
+					default:
+						z.scale_RGBA_Image_Src(dst, dr, adr, src, sr, &o)
+					case image.YCbCrSubsampleRatio444:
+						z.scale_RGBA_NYCbCrA444_Src(dst, dr, adr, src, sr, &o)
+					case image.YCbCrSubsampleRatio422:
+						z.scale_RGBA_NYCbCrA422_Src(dst, dr, adr, src, sr, &o)
+					case image.YCbCrSubsampleRatio420:
+						z.scale_RGBA_NYCbCrA420_Src(dst, dr, adr, src, sr, &o)
+					case image.YCbCrSubsampleRatio440:
+						z.scale_RGBA_NYCbCrA440_Src(dst, dr, adr, src, sr, &o)
+					}
 				case image.RGBA64Image:
 					z.scale_RGBA_RGBA64Image_Src(dst, dr, adr, src, sr, &o)
 				default:
 					z.scale_RGBA_Image_Src(dst, dr, adr, src, sr, &o)
 				}
+			case *image.RGBA64:
+				switch src := src.(type) {
+				case *image.NRGBA64:
+					z.scale_RGBA64_NRGBA64_Src(dst, dr, adr, src, sr, &o)
+				case *image.RGBA64:
+					z.scale_RGBA64_RGBA64_Src(dst, dr, adr, src, sr, &o)
+				case image.RGBA64Image:
+					z.scale_RGBA64_RGBA64Image_Src(dst, dr, adr, src, sr, &o)
+				default:
+					z.scale_RGBA64_Image_Src(dst, dr, adr, src, sr, &o)
+				}
+			case *image.NRGBA64:
+				switch src := src.(type) {
+				case *image.NRGBA64:
+					z.scale_NRGBA64_NRGBA64_Src(dst, dr, adr, src, sr, &o)
+				case *image.RGBA64:
+					z.scale_NRGBA64_RGBA64_Src(dst, dr, adr, src, sr, &o)
+				case image.RGBA64Image:
+					z.scale_NRGBA64_RGBA64Image_Src(dst, dr, adr, src, sr, &o)
+				default:
+					z.scale_NRGBA64_Image_Src(dst, dr, adr, src, sr, &o)
+				}
 			case RGBA64Image:
 				switch src := src.(type) {
 				case image.RGBA64Image:
@@ -124,7 +235,8 @@ func (z nnInterpolator) Transform(dst Image, s2d f64.Aff3, src image.Image, sr i
 		dx := int(s2d[2])
 		dy := int(s2d[5])
 		if float64(dx) == s2d[2] && float64(dy) == s2d[5] {
-			Copy(dst, image.Point{X: sr.Min.X + dx, Y: sr.Min.X + dy}, src, sr, op, opts)
+			dp := image.Point{X: sr.Min.X + dx, Y: sr.Min.Y + dy}
+			Copy(dst, dp, unalias(dst, sr.Add(dp.Sub(sr.Min)), src, sr), sr, op, opts)
 			return
 		}
 	}
@@ -141,6 +253,7 @@ func (z nnInterpolator) Transform(dst Image, s2d f64.Aff3, src image.Image, sr i
 	if adr.Empty() || sr.Empty() {
 		return
 	}
+	src = unalias(dst, adr, src, sr)
 	if op == Over && o.SrcMask == nil && opaque(src) {
 		op = Src
 	}
@@ -175,20 +288,72 @@ func (z nnInterpolator) Transform(dst Image, s2d f64.Aff3, src image.Image, sr i
 	} else if u, ok := src.(*image.Uniform); ok {
 		transform_Uniform(dst, dr, adr, &d2s, u, sr, bias, op)
 	} else {
+		dst = palettedDst(dst)
 		switch op {
 		case Over:
 			switch dst := dst.(type) {
+			case *image.Alpha:
+				switch src := src.(type) {
+				case *image.Alpha:
+					z.transform_Alpha_Alpha_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
+				case image.RGBA64Image:
+					z.transform_Alpha_RGBA64Image_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
+				default:
+					z.transform_Alpha_Image_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
+				}
+			case *image.Gray:
+				switch src := src.(type) {
+				case image.RGBA64Image:
+					z.transform_Gray_RGBA64Image_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
+				default:
+					z.transform_Gray_Image_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
+				}
 			case *image.RGBA:
 				switch src := src.(type) {
 				case *image.NRGBA:
 					z.transform_RGBA_NRGBA_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
 				case *image.RGBA:
 					z.transform_RGBA_RGBA_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
+				case *image.NYCbCrA:
+					switch src.SubsampleRatio {
+					default:
+						z.transform_RGBA_Image_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
+					case image.YCbCrSubsampleRatio444:
+						z.transform_RGBA_NYCbCrA444_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
+					case image.YCbCrSubsampleRatio422:
+						z.transform_RGBA_NYCbCrA422_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
+					case image.YCbCrSubsampleRatio420:
+						z.transform_RGBA_NYCbCrA420_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
+					case image.YCbCrSubsampleRatio440:
+						z.transform_RGBA_NYCbCrA440_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
+					}
 				case image.RGBA64Image:
 					z.transform_RGBA_RGBA64Image_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
 				default:
 					z.transform_RGBA_Image_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
 				}
+			case *image.RGBA64:
+				switch src := src.(type) {
+				case *image.NRGBA64:
+					z.transform_RGBA64_NRGBA64_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
+				case *image.RGBA64:
+					z.transform_RGBA64_RGBA64_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
+				case image.RGBA64Image:
+					z.transform_RGBA64_RGBA64Image_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
+				default:
+					z.transform_RGBA64_Image_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
+				}
+			case *image.NRGBA64:
+				switch src := src.(type) {
+				case *image.NRGBA64:
+					z.transform_NRGBA64_NRGBA64_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
+				case *image.RGBA64:
+					z.transform_NRGBA64_RGBA64_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
+				case image.RGBA64Image:
+					z.transform_NRGBA64_RGBA64Image_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
+				default:
+					z.transform_NRGBA64_Image_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
+				}
 			case RGBA64Image:
 				switch src := src.(type) {
 				case image.RGBA64Image:
@@ -202,6 +367,24 @@ func (z nnInterpolator) Transform(dst Image, s2d f64.Aff3, src image.Image, sr i
 			}
 		case Src:
 			switch dst := dst.(type) {
+			case *image.Alpha:
+				switch src := src.(type) {
+				case *image.Alpha:
+					z.transform_Alpha_Alpha_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+				case image.RGBA64Image:
+					z.transform_Alpha_RGBA64Image_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+				default:
+					z.transform_Alpha_Image_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+				}
+			case *image.Gray:
+				switch src := src.(type) {
+				case *image.Gray:
+					z.transform_Gray_Gray_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+				case image.RGBA64Image:
+					z.transform_Gray_RGBA64Image_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+				default:
+					z.transform_Gray_Image_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+				}
 			case *image.RGBA:
 				switch src := src.(type) {
 				case *image.Gray:
@@ -223,11 +406,46 @@ func (z nnInterpolator) Transform(dst Image, s2d f64.Aff3, src image.Image, sr i
 					case image.YCbCrSubsampleRatio440:
 						z.transform_RGBA_YCbCr440_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
 					}
+				case *image.NYCbCrA:
+					switch src.SubsampleRatio {
+					default:
+						z.transform_RGBA_Image_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+					case image.YCbCrSubsampleRatio444:
+						z.transform_RGBA_NYCbCrA444_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+					case image.YCbCrSubsampleRatio422:
+						z.transform_RGBA_NYCbCrA422_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+					case image.YCbCrSubsampleRatio420:
+						z.transform_RGBA_NYCbCrA420_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+					case image.YCbCrSubsampleRatio440:
+						z.transform_RGBA_NYCbCrA440_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+					}
 				case image.RGBA64Image:
 					z.transform_RGBA_RGBA64Image_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
 				default:
 					z.transform_RGBA_Image_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
 				}
+			case *image.RGBA64:
+				switch src := src.(type) {
+				case *image.NRGBA64:
+					z.transform_RGBA64_NRGBA64_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+				case *image.RGBA64:
+					z.transform_RGBA64_RGBA64_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+				case image.RGBA64Image:
+					z.transform_RGBA64_RGBA64Image_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+				default:
+					z.transform_RGBA64_Image_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+				}
+			case *image.NRGBA64:
+				switch src := src.(type) {
+				case *image.NRGBA64:
+					z.transform_NRGBA64_NRGBA64_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+				case *image.RGBA64:
+					z.transform_NRGBA64_RGBA64_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+				case image.RGBA64Image:
+					z.transform_NRGBA64_RGBA64Image_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+				default:
+					z.transform_NRGBA64_Image_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+				}
 			case RGBA64Image:
 				switch src := src.(type) {
 				case image.RGBA64Image:
@@ -243,6 +461,192 @@ func (z nnInterpolator) Transform(dst Image, s2d f64.Aff3, src image.Image, sr i
 	}
 }
 
+func (nnInterpolator) scale_Alpha_Alpha_Over(dst *image.Alpha, dr, adr image.Rectangle, src *image.Alpha, sr image.Rectangle, opts *Options) {
+	dw2 := uint64(dr.Dx()) * 2
+	dh2 := uint64(dr.Dy()) * 2
+	sw := uint64(sr.Dx())
+	sh := uint64(sr.Dy())
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := (2*uint64(dy) + 1) * sh / dh2
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			sx := (2*uint64(dx) + 1) * sw / dw2
+			pi := (sr.Min.Y+int(sy)-src.Rect.Min.Y)*src.Stride + (sr.Min.X + int(sx) - src.Rect.Min.X)
+			pa := uint32(src.Pix[pi+0]) * 0x101
+			pa1 := (0xffff - pa) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pa) >> 8)
+		}
+	}
+}
+
+func (nnInterpolator) scale_Alpha_Alpha_Src(dst *image.Alpha, dr, adr image.Rectangle, src *image.Alpha, sr image.Rectangle, opts *Options) {
+	dw2 := uint64(dr.Dx()) * 2
+	dh2 := uint64(dr.Dy()) * 2
+	sw := uint64(sr.Dx())
+	sh := uint64(sr.Dy())
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := (2*uint64(dy) + 1) * sh / dh2
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			sx := (2*uint64(dx) + 1) * sw / dw2
+			pi := (sr.Min.Y+int(sy)-src.Rect.Min.Y)*src.Stride + (sr.Min.X + int(sx) - src.Rect.Min.X)
+			pa := uint32(src.Pix[pi+0]) * 0x101
+			dst.Pix[d+0] = uint8(pa >> 8)
+		}
+	}
+}
+
+func (nnInterpolator) scale_Alpha_RGBA64Image_Over(dst *image.Alpha, dr, adr image.Rectangle, src image.RGBA64Image, sr image.Rectangle, opts *Options) {
+	dw2 := uint64(dr.Dx()) * 2
+	dh2 := uint64(dr.Dy()) * 2
+	sw := uint64(sr.Dx())
+	sh := uint64(sr.Dy())
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := (2*uint64(dy) + 1) * sh / dh2
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			sx := (2*uint64(dx) + 1) * sw / dw2
+			p := src.RGBA64At(sr.Min.X+int(sx), sr.Min.Y+int(sy))
+			pa1 := (0xffff - uint32(p.A)) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + uint32(p.A)) >> 8)
+		}
+	}
+}
+
+func (nnInterpolator) scale_Alpha_RGBA64Image_Src(dst *image.Alpha, dr, adr image.Rectangle, src image.RGBA64Image, sr image.Rectangle, opts *Options) {
+	dw2 := uint64(dr.Dx()) * 2
+	dh2 := uint64(dr.Dy()) * 2
+	sw := uint64(sr.Dx())
+	sh := uint64(sr.Dy())
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := (2*uint64(dy) + 1) * sh / dh2
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			sx := (2*uint64(dx) + 1) * sw / dw2
+			p := src.RGBA64At(sr.Min.X+int(sx), sr.Min.Y+int(sy))
+			dst.Pix[d+0] = uint8(p.A >> 8)
+		}
+	}
+}
+
+func (nnInterpolator) scale_Alpha_Image_Over(dst *image.Alpha, dr, adr image.Rectangle, src image.Image, sr image.Rectangle, opts *Options) {
+	dw2 := uint64(dr.Dx()) * 2
+	dh2 := uint64(dr.Dy()) * 2
+	sw := uint64(sr.Dx())
+	sh := uint64(sr.Dy())
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := (2*uint64(dy) + 1) * sh / dh2
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			sx := (2*uint64(dx) + 1) * sw / dw2
+			_, _, _, pa := src.At(sr.Min.X+int(sx), sr.Min.Y+int(sy)).RGBA()
+			pa1 := (0xffff - pa) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pa) >> 8)
+		}
+	}
+}
+
+func (nnInterpolator) scale_Alpha_Image_Src(dst *image.Alpha, dr, adr image.Rectangle, src image.Image, sr image.Rectangle, opts *Options) {
+	dw2 := uint64(dr.Dx()) * 2
+	dh2 := uint64(dr.Dy()) * 2
+	sw := uint64(sr.Dx())
+	sh := uint64(sr.Dy())
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := (2*uint64(dy) + 1) * sh / dh2
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			sx := (2*uint64(dx) + 1) * sw / dw2
+			_, _, _, pa := src.At(sr.Min.X+int(sx), sr.Min.Y+int(sy)).RGBA()
+			dst.Pix[d+0] = uint8(pa >> 8)
+		}
+	}
+}
+
+func (nnInterpolator) scale_Gray_Gray_Src(dst *image.Gray, dr, adr image.Rectangle, src *image.Gray, sr image.Rectangle, opts *Options) {
+	dw2 := uint64(dr.Dx()) * 2
+	dh2 := uint64(dr.Dy()) * 2
+	sw := uint64(sr.Dx())
+	sh := uint64(sr.Dy())
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := (2*uint64(dy) + 1) * sh / dh2
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			sx := (2*uint64(dx) + 1) * sw / dw2
+			pi := (sr.Min.Y+int(sy)-src.Rect.Min.Y)*src.Stride + (sr.Min.X + int(sx) - src.Rect.Min.X)
+			pr := uint32(src.Pix[pi]) * 0x101
+			dst.Pix[d] = uint8(pr >> 8)
+		}
+	}
+}
+
+func (nnInterpolator) scale_Gray_RGBA64Image_Over(dst *image.Gray, dr, adr image.Rectangle, src image.RGBA64Image, sr image.Rectangle, opts *Options) {
+	dw2 := uint64(dr.Dx()) * 2
+	dh2 := uint64(dr.Dy()) * 2
+	sw := uint64(sr.Dx())
+	sh := uint64(sr.Dy())
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := (2*uint64(dy) + 1) * sh / dh2
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			sx := (2*uint64(dx) + 1) * sw / dw2
+			p := src.RGBA64At(sr.Min.X+int(sx), sr.Min.Y+int(sy))
+			pa1 := 0xffff - uint32(p.A)
+			pq := uint32(dst.Pix[d]) * 0x101 * pa1 / 0xffff
+			dst.Pix[d] = uint8((19595*(pq+uint32(p.R)) + 38470*(pq+uint32(p.G)) + 7471*(pq+uint32(p.B)) + 1<<15) >> 24)
+		}
+	}
+}
+
+func (nnInterpolator) scale_Gray_RGBA64Image_Src(dst *image.Gray, dr, adr image.Rectangle, src image.RGBA64Image, sr image.Rectangle, opts *Options) {
+	dw2 := uint64(dr.Dx()) * 2
+	dh2 := uint64(dr.Dy()) * 2
+	sw := uint64(sr.Dx())
+	sh := uint64(sr.Dy())
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := (2*uint64(dy) + 1) * sh / dh2
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			sx := (2*uint64(dx) + 1) * sw / dw2
+			p := src.RGBA64At(sr.Min.X+int(sx), sr.Min.Y+int(sy))
+			dst.Pix[d] = uint8((19595*uint32(p.R) + 38470*uint32(p.G) + 7471*uint32(p.B) + 1<<15) >> 24)
+		}
+	}
+}
+
+func (nnInterpolator) scale_Gray_Image_Over(dst *image.Gray, dr, adr image.Rectangle, src image.Image, sr image.Rectangle, opts *Options) {
+	dw2 := uint64(dr.Dx()) * 2
+	dh2 := uint64(dr.Dy()) * 2
+	sw := uint64(sr.Dx())
+	sh := uint64(sr.Dy())
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := (2*uint64(dy) + 1) * sh / dh2
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			sx := (2*uint64(dx) + 1) * sw / dw2
+			pr, pg, pb, pa := src.At(sr.Min.X+int(sx), sr.Min.Y+int(sy)).RGBA()
+			pa1 := 0xffff - pa
+			pq := uint32(dst.Pix[d]) * 0x101 * pa1 / 0xffff
+			dst.Pix[d] = uint8((19595*(pq+pr) + 38470*(pq+pg) + 7471*(pq+pb) + 1<<15) >> 24)
+		}
+	}
+}
+
+func (nnInterpolator) scale_Gray_Image_Src(dst *image.Gray, dr, adr image.Rectangle, src image.Image, sr image.Rectangle, opts *Options) {
+	dw2 := uint64(dr.Dx()) * 2
+	dh2 := uint64(dr.Dy()) * 2
+	sw := uint64(sr.Dx())
+	sh := uint64(sr.Dy())
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := (2*uint64(dy) + 1) * sh / dh2
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			sx := (2*uint64(dx) + 1) * sw / dw2
+			pr, pg, pb, _ := src.At(sr.Min.X+int(sx), sr.Min.Y+int(sy)).RGBA()
+			dst.Pix[d] = uint8((19595*pr + 38470*pg + 7471*pb + 1<<15) >> 24)
+		}
+	}
+}
+
 func (nnInterpolator) scale_RGBA_Gray_Src(dst *image.RGBA, dr, adr image.Rectangle, src *image.Gray, sr image.Rectangle, opts *Options) {
 	dw2 := uint64(dr.Dx()) * 2
 	dh2 := uint64(dr.Dy()) * 2
@@ -530,7 +934,7 @@ func (nnInterpolator) scale_RGBA_YCbCr440_Src(dst *image.RGBA, dr, adr image.Rec
 	}
 }
 
-func (nnInterpolator) scale_RGBA_RGBA64Image_Over(dst *image.RGBA, dr, adr image.Rectangle, src image.RGBA64Image, sr image.Rectangle, opts *Options) {
+func (nnInterpolator) scale_RGBA_NYCbCrA444_Over(dst *image.RGBA, dr, adr image.Rectangle, src *image.NYCbCrA, sr image.Rectangle, opts *Options) {
 	dw2 := uint64(dr.Dx()) * 2
 	dh2 := uint64(dr.Dy()) * 2
 	sw := uint64(sr.Dx())
@@ -540,17 +944,47 @@ func (nnInterpolator) scale_RGBA_RGBA64Image_Over(dst *image.RGBA, dr, adr image
 		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
 		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
 			sx := (2*uint64(dx) + 1) * sw / dw2
-			p := src.RGBA64At(sr.Min.X+int(sx), sr.Min.Y+int(sy))
-			pa1 := (0xffff - uint32(p.A)) * 0x101
-			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + uint32(p.R)) >> 8)
-			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + uint32(p.G)) >> 8)
-			dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*pa1/0xffff + uint32(p.B)) >> 8)
-			dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*pa1/0xffff + uint32(p.A)) >> 8)
+			pi := (sr.Min.Y+int(sy)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx) - src.Rect.Min.X)
+			pj := (sr.Min.Y+int(sy)-src.Rect.Min.Y)*src.CStride + (sr.Min.X + int(sx) - src.Rect.Min.X)
+			pk := (sr.Min.Y+int(sy)-src.Rect.Min.Y)*src.AStride + (sr.Min.X + int(sx) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			pyy1 := int(src.Y[pi]) * 0x10101
+			pcb1 := int(src.Cb[pj]) - 128
+			pcr1 := int(src.Cr[pj]) - 128
+			pr0 := (pyy1 + 91881*pcr1) >> 8
+			pg0 := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
+			pb0 := (pyy1 + 116130*pcb1) >> 8
+			if pr0 < 0 {
+				pr0 = 0
+			} else if pr0 > 0xffff {
+				pr0 = 0xffff
+			}
+			if pg0 < 0 {
+				pg0 = 0
+			} else if pg0 > 0xffff {
+				pg0 = 0xffff
+			}
+			if pb0 < 0 {
+				pb0 = 0
+			} else if pb0 > 0xffff {
+				pb0 = 0xffff
+			}
+
+			pa := uint32(src.A[pk]) * 0x101
+			pr := uint32(pr0) * pa / 0xffff
+			pg := uint32(pg0) * pa / 0xffff
+			pb := uint32(pb0) * pa / 0xffff
+			pa1 := (0xffff - pa) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pr) >> 8)
+			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + pg) >> 8)
+			dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*pa1/0xffff + pb) >> 8)
+			dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*pa1/0xffff + pa) >> 8)
 		}
 	}
 }
 
-func (nnInterpolator) scale_RGBA_RGBA64Image_Src(dst *image.RGBA, dr, adr image.Rectangle, src image.RGBA64Image, sr image.Rectangle, opts *Options) {
+func (nnInterpolator) scale_RGBA_NYCbCrA422_Over(dst *image.RGBA, dr, adr image.Rectangle, src *image.NYCbCrA, sr image.Rectangle, opts *Options) {
 	dw2 := uint64(dr.Dx()) * 2
 	dh2 := uint64(dr.Dy()) * 2
 	sw := uint64(sr.Dx())
@@ -560,16 +994,47 @@ func (nnInterpolator) scale_RGBA_RGBA64Image_Src(dst *image.RGBA, dr, adr image.
 		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
 		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
 			sx := (2*uint64(dx) + 1) * sw / dw2
-			p := src.RGBA64At(sr.Min.X+int(sx), sr.Min.Y+int(sy))
-			dst.Pix[d+0] = uint8(p.R >> 8)
-			dst.Pix[d+1] = uint8(p.G >> 8)
-			dst.Pix[d+2] = uint8(p.B >> 8)
-			dst.Pix[d+3] = uint8(p.A >> 8)
+			pi := (sr.Min.Y+int(sy)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx) - src.Rect.Min.X)
+			pj := (sr.Min.Y+int(sy)-src.Rect.Min.Y)*src.CStride + ((sr.Min.X+int(sx))/2 - src.Rect.Min.X/2)
+			pk := (sr.Min.Y+int(sy)-src.Rect.Min.Y)*src.AStride + (sr.Min.X + int(sx) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			pyy1 := int(src.Y[pi]) * 0x10101
+			pcb1 := int(src.Cb[pj]) - 128
+			pcr1 := int(src.Cr[pj]) - 128
+			pr0 := (pyy1 + 91881*pcr1) >> 8
+			pg0 := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
+			pb0 := (pyy1 + 116130*pcb1) >> 8
+			if pr0 < 0 {
+				pr0 = 0
+			} else if pr0 > 0xffff {
+				pr0 = 0xffff
+			}
+			if pg0 < 0 {
+				pg0 = 0
+			} else if pg0 > 0xffff {
+				pg0 = 0xffff
+			}
+			if pb0 < 0 {
+				pb0 = 0
+			} else if pb0 > 0xffff {
+				pb0 = 0xffff
+			}
+
+			pa := uint32(src.A[pk]) * 0x101
+			pr := uint32(pr0) * pa / 0xffff
+			pg := uint32(pg0) * pa / 0xffff
+			pb := uint32(pb0) * pa / 0xffff
+			pa1 := (0xffff - pa) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pr) >> 8)
+			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + pg) >> 8)
+			dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*pa1/0xffff + pb) >> 8)
+			dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*pa1/0xffff + pa) >> 8)
 		}
 	}
 }
 
-func (nnInterpolator) scale_RGBA_Image_Over(dst *image.RGBA, dr, adr image.Rectangle, src image.Image, sr image.Rectangle, opts *Options) {
+func (nnInterpolator) scale_RGBA_NYCbCrA420_Over(dst *image.RGBA, dr, adr image.Rectangle, src *image.NYCbCrA, sr image.Rectangle, opts *Options) {
 	dw2 := uint64(dr.Dx()) * 2
 	dh2 := uint64(dr.Dy()) * 2
 	sw := uint64(sr.Dx())
@@ -579,7 +1044,37 @@ func (nnInterpolator) scale_RGBA_Image_Over(dst *image.RGBA, dr, adr image.Recta
 		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
 		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
 			sx := (2*uint64(dx) + 1) * sw / dw2
-			pr, pg, pb, pa := src.At(sr.Min.X+int(sx), sr.Min.Y+int(sy)).RGBA()
+			pi := (sr.Min.Y+int(sy)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx) - src.Rect.Min.X)
+			pj := ((sr.Min.Y+int(sy))/2-src.Rect.Min.Y/2)*src.CStride + ((sr.Min.X+int(sx))/2 - src.Rect.Min.X/2)
+			pk := (sr.Min.Y+int(sy)-src.Rect.Min.Y)*src.AStride + (sr.Min.X + int(sx) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			pyy1 := int(src.Y[pi]) * 0x10101
+			pcb1 := int(src.Cb[pj]) - 128
+			pcr1 := int(src.Cr[pj]) - 128
+			pr0 := (pyy1 + 91881*pcr1) >> 8
+			pg0 := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
+			pb0 := (pyy1 + 116130*pcb1) >> 8
+			if pr0 < 0 {
+				pr0 = 0
+			} else if pr0 > 0xffff {
+				pr0 = 0xffff
+			}
+			if pg0 < 0 {
+				pg0 = 0
+			} else if pg0 > 0xffff {
+				pg0 = 0xffff
+			}
+			if pb0 < 0 {
+				pb0 = 0
+			} else if pb0 > 0xffff {
+				pb0 = 0xffff
+			}
+
+			pa := uint32(src.A[pk]) * 0x101
+			pr := uint32(pr0) * pa / 0xffff
+			pg := uint32(pg0) * pa / 0xffff
+			pb := uint32(pb0) * pa / 0xffff
 			pa1 := (0xffff - pa) * 0x101
 			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pr) >> 8)
 			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + pg) >> 8)
@@ -589,7 +1084,7 @@ func (nnInterpolator) scale_RGBA_Image_Over(dst *image.RGBA, dr, adr image.Recta
 	}
 }
 
-func (nnInterpolator) scale_RGBA_Image_Src(dst *image.RGBA, dr, adr image.Rectangle, src image.Image, sr image.Rectangle, opts *Options) {
+func (nnInterpolator) scale_RGBA_NYCbCrA440_Over(dst *image.RGBA, dr, adr image.Rectangle, src *image.NYCbCrA, sr image.Rectangle, opts *Options) {
 	dw2 := uint64(dr.Dx()) * 2
 	dh2 := uint64(dr.Dy()) * 2
 	sw := uint64(sr.Dx())
@@ -599,7 +1094,87 @@ func (nnInterpolator) scale_RGBA_Image_Src(dst *image.RGBA, dr, adr image.Rectan
 		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
 		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
 			sx := (2*uint64(dx) + 1) * sw / dw2
-			pr, pg, pb, pa := src.At(sr.Min.X+int(sx), sr.Min.Y+int(sy)).RGBA()
+			pi := (sr.Min.Y+int(sy)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx) - src.Rect.Min.X)
+			pj := ((sr.Min.Y+int(sy))/2-src.Rect.Min.Y/2)*src.CStride + (sr.Min.X + int(sx) - src.Rect.Min.X)
+			pk := (sr.Min.Y+int(sy)-src.Rect.Min.Y)*src.AStride + (sr.Min.X + int(sx) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			pyy1 := int(src.Y[pi]) * 0x10101
+			pcb1 := int(src.Cb[pj]) - 128
+			pcr1 := int(src.Cr[pj]) - 128
+			pr0 := (pyy1 + 91881*pcr1) >> 8
+			pg0 := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
+			pb0 := (pyy1 + 116130*pcb1) >> 8
+			if pr0 < 0 {
+				pr0 = 0
+			} else if pr0 > 0xffff {
+				pr0 = 0xffff
+			}
+			if pg0 < 0 {
+				pg0 = 0
+			} else if pg0 > 0xffff {
+				pg0 = 0xffff
+			}
+			if pb0 < 0 {
+				pb0 = 0
+			} else if pb0 > 0xffff {
+				pb0 = 0xffff
+			}
+
+			pa := uint32(src.A[pk]) * 0x101
+			pr := uint32(pr0) * pa / 0xffff
+			pg := uint32(pg0) * pa / 0xffff
+			pb := uint32(pb0) * pa / 0xffff
+			pa1 := (0xffff - pa) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pr) >> 8)
+			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + pg) >> 8)
+			dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*pa1/0xffff + pb) >> 8)
+			dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*pa1/0xffff + pa) >> 8)
+		}
+	}
+}
+
+func (nnInterpolator) scale_RGBA_NYCbCrA444_Src(dst *image.RGBA, dr, adr image.Rectangle, src *image.NYCbCrA, sr image.Rectangle, opts *Options) {
+	dw2 := uint64(dr.Dx()) * 2
+	dh2 := uint64(dr.Dy()) * 2
+	sw := uint64(sr.Dx())
+	sh := uint64(sr.Dy())
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := (2*uint64(dy) + 1) * sh / dh2
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			sx := (2*uint64(dx) + 1) * sw / dw2
+			pi := (sr.Min.Y+int(sy)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx) - src.Rect.Min.X)
+			pj := (sr.Min.Y+int(sy)-src.Rect.Min.Y)*src.CStride + (sr.Min.X + int(sx) - src.Rect.Min.X)
+			pk := (sr.Min.Y+int(sy)-src.Rect.Min.Y)*src.AStride + (sr.Min.X + int(sx) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			pyy1 := int(src.Y[pi]) * 0x10101
+			pcb1 := int(src.Cb[pj]) - 128
+			pcr1 := int(src.Cr[pj]) - 128
+			pr0 := (pyy1 + 91881*pcr1) >> 8
+			pg0 := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
+			pb0 := (pyy1 + 116130*pcb1) >> 8
+			if pr0 < 0 {
+				pr0 = 0
+			} else if pr0 > 0xffff {
+				pr0 = 0xffff
+			}
+			if pg0 < 0 {
+				pg0 = 0
+			} else if pg0 > 0xffff {
+				pg0 = 0xffff
+			}
+			if pb0 < 0 {
+				pb0 = 0
+			} else if pb0 > 0xffff {
+				pb0 = 0xffff
+			}
+
+			pa := uint32(src.A[pk]) * 0x101
+			pr := uint32(pr0) * pa / 0xffff
+			pg := uint32(pg0) * pa / 0xffff
+			pb := uint32(pb0) * pa / 0xffff
 			dst.Pix[d+0] = uint8(pr >> 8)
 			dst.Pix[d+1] = uint8(pg >> 8)
 			dst.Pix[d+2] = uint8(pb >> 8)
@@ -608,208 +1183,203 @@ func (nnInterpolator) scale_RGBA_Image_Src(dst *image.RGBA, dr, adr image.Rectan
 	}
 }
 
-func (nnInterpolator) scale_RGBA64Image_RGBA64Image_Over(dst RGBA64Image, dr, adr image.Rectangle, src image.RGBA64Image, sr image.Rectangle, opts *Options) {
+func (nnInterpolator) scale_RGBA_NYCbCrA422_Src(dst *image.RGBA, dr, adr image.Rectangle, src *image.NYCbCrA, sr image.Rectangle, opts *Options) {
 	dw2 := uint64(dr.Dx()) * 2
 	dh2 := uint64(dr.Dy()) * 2
 	sw := uint64(sr.Dx())
 	sh := uint64(sr.Dy())
-	srcMask, smp := opts.SrcMask, opts.SrcMaskP
-	dstMask, dmp := opts.DstMask, opts.DstMaskP
-	dstColorRGBA64 := color.RGBA64{}
-
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
 		sy := (2*uint64(dy) + 1) * sh / dh2
-		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
 			sx := (2*uint64(dx) + 1) * sw / dw2
-			p := src.RGBA64At(sr.Min.X+int(sx), sr.Min.Y+int(sy))
-			if srcMask != nil {
-				_, _, _, ma := srcMask.At(smp.X+sr.Min.X+int(sx), smp.Y+sr.Min.Y+int(sy)).RGBA()
-				p.R = uint16(uint32(p.R) * ma / 0xffff)
-				p.G = uint16(uint32(p.G) * ma / 0xffff)
-				p.B = uint16(uint32(p.B) * ma / 0xffff)
-				p.A = uint16(uint32(p.A) * ma / 0xffff)
-			}
-			q := dst.RGBA64At(dr.Min.X+int(dx), dr.Min.Y+int(dy))
-			if dstMask != nil {
-				_, _, _, ma := dstMask.At(dmp.X+dr.Min.X+int(dx), dmp.Y+dr.Min.Y+int(dy)).RGBA()
-				p.R = uint16(uint32(p.R) * ma / 0xffff)
-				p.G = uint16(uint32(p.G) * ma / 0xffff)
-				p.B = uint16(uint32(p.B) * ma / 0xffff)
-				p.A = uint16(uint32(p.A) * ma / 0xffff)
-			}
-			pa1 := 0xffff - uint32(p.A)
-			dstColorRGBA64.R = uint16(uint32(q.R)*pa1/0xffff + uint32(p.R))
-			dstColorRGBA64.G = uint16(uint32(q.G)*pa1/0xffff + uint32(p.G))
-			dstColorRGBA64.B = uint16(uint32(q.B)*pa1/0xffff + uint32(p.B))
-			dstColorRGBA64.A = uint16(uint32(q.A)*pa1/0xffff + uint32(p.A))
-			dst.Set(dr.Min.X+int(dx), dr.Min.Y+int(dy), dstColorRGBA64)
+			pi := (sr.Min.Y+int(sy)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx) - src.Rect.Min.X)
+			pj := (sr.Min.Y+int(sy)-src.Rect.Min.Y)*src.CStride + ((sr.Min.X+int(sx))/2 - src.Rect.Min.X/2)
+			pk := (sr.Min.Y+int(sy)-src.Rect.Min.Y)*src.AStride + (sr.Min.X + int(sx) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			pyy1 := int(src.Y[pi]) * 0x10101
+			pcb1 := int(src.Cb[pj]) - 128
+			pcr1 := int(src.Cr[pj]) - 128
+			pr0 := (pyy1 + 91881*pcr1) >> 8
+			pg0 := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
+			pb0 := (pyy1 + 116130*pcb1) >> 8
+			if pr0 < 0 {
+				pr0 = 0
+			} else if pr0 > 0xffff {
+				pr0 = 0xffff
+			}
+			if pg0 < 0 {
+				pg0 = 0
+			} else if pg0 > 0xffff {
+				pg0 = 0xffff
+			}
+			if pb0 < 0 {
+				pb0 = 0
+			} else if pb0 > 0xffff {
+				pb0 = 0xffff
+			}
+
+			pa := uint32(src.A[pk]) * 0x101
+			pr := uint32(pr0) * pa / 0xffff
+			pg := uint32(pg0) * pa / 0xffff
+			pb := uint32(pb0) * pa / 0xffff
+			dst.Pix[d+0] = uint8(pr >> 8)
+			dst.Pix[d+1] = uint8(pg >> 8)
+			dst.Pix[d+2] = uint8(pb >> 8)
+			dst.Pix[d+3] = uint8(pa >> 8)
 		}
 	}
 }
 
-func (nnInterpolator) scale_RGBA64Image_RGBA64Image_Src(dst RGBA64Image, dr, adr image.Rectangle, src image.RGBA64Image, sr image.Rectangle, opts *Options) {
+func (nnInterpolator) scale_RGBA_NYCbCrA420_Src(dst *image.RGBA, dr, adr image.Rectangle, src *image.NYCbCrA, sr image.Rectangle, opts *Options) {
 	dw2 := uint64(dr.Dx()) * 2
 	dh2 := uint64(dr.Dy()) * 2
 	sw := uint64(sr.Dx())
 	sh := uint64(sr.Dy())
-	srcMask, smp := opts.SrcMask, opts.SrcMaskP
-	dstMask, dmp := opts.DstMask, opts.DstMaskP
-	dstColorRGBA64 := color.RGBA64{}
-
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
 		sy := (2*uint64(dy) + 1) * sh / dh2
-		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
 			sx := (2*uint64(dx) + 1) * sw / dw2
-			p := src.RGBA64At(sr.Min.X+int(sx), sr.Min.Y+int(sy))
-			if srcMask != nil {
-				_, _, _, ma := srcMask.At(smp.X+sr.Min.X+int(sx), smp.Y+sr.Min.Y+int(sy)).RGBA()
-				p.R = uint16(uint32(p.R) * ma / 0xffff)
-				p.G = uint16(uint32(p.G) * ma / 0xffff)
-				p.B = uint16(uint32(p.B) * ma / 0xffff)
-				p.A = uint16(uint32(p.A) * ma / 0xffff)
-			}
-			if dstMask != nil {
-				q := dst.RGBA64At(dr.Min.X+int(dx), dr.Min.Y+int(dy))
-				_, _, _, ma := dstMask.At(dmp.X+dr.Min.X+int(dx), dmp.Y+dr.Min.Y+int(dy)).RGBA()
-				p.R = uint16(uint32(p.R) * ma / 0xffff)
-				p.G = uint16(uint32(p.G) * ma / 0xffff)
-				p.B = uint16(uint32(p.B) * ma / 0xffff)
-				p.A = uint16(uint32(p.A) * ma / 0xffff)
-				pa1 := 0xffff - ma
-				dstColorRGBA64.R = uint16(uint32(q.R)*pa1/0xffff + uint32(p.R))
-				dstColorRGBA64.G = uint16(uint32(q.G)*pa1/0xffff + uint32(p.G))
-				dstColorRGBA64.B = uint16(uint32(q.B)*pa1/0xffff + uint32(p.B))
-				dstColorRGBA64.A = uint16(uint32(q.A)*pa1/0xffff + uint32(p.A))
-				dst.Set(dr.Min.X+int(dx), dr.Min.Y+int(dy), dstColorRGBA64)
-			} else {
-				dst.Set(dr.Min.X+int(dx), dr.Min.Y+int(dy), p)
-			}
+			pi := (sr.Min.Y+int(sy)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx) - src.Rect.Min.X)
+			pj := ((sr.Min.Y+int(sy))/2-src.Rect.Min.Y/2)*src.CStride + ((sr.Min.X+int(sx))/2 - src.Rect.Min.X/2)
+			pk := (sr.Min.Y+int(sy)-src.Rect.Min.Y)*src.AStride + (sr.Min.X + int(sx) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			pyy1 := int(src.Y[pi]) * 0x10101
+			pcb1 := int(src.Cb[pj]) - 128
+			pcr1 := int(src.Cr[pj]) - 128
+			pr0 := (pyy1 + 91881*pcr1) >> 8
+			pg0 := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
+			pb0 := (pyy1 + 116130*pcb1) >> 8
+			if pr0 < 0 {
+				pr0 = 0
+			} else if pr0 > 0xffff {
+				pr0 = 0xffff
+			}
+			if pg0 < 0 {
+				pg0 = 0
+			} else if pg0 > 0xffff {
+				pg0 = 0xffff
+			}
+			if pb0 < 0 {
+				pb0 = 0
+			} else if pb0 > 0xffff {
+				pb0 = 0xffff
+			}
+
+			pa := uint32(src.A[pk]) * 0x101
+			pr := uint32(pr0) * pa / 0xffff
+			pg := uint32(pg0) * pa / 0xffff
+			pb := uint32(pb0) * pa / 0xffff
+			dst.Pix[d+0] = uint8(pr >> 8)
+			dst.Pix[d+1] = uint8(pg >> 8)
+			dst.Pix[d+2] = uint8(pb >> 8)
+			dst.Pix[d+3] = uint8(pa >> 8)
 		}
 	}
 }
 
-func (nnInterpolator) scale_Image_Image_Over(dst Image, dr, adr image.Rectangle, src image.Image, sr image.Rectangle, opts *Options) {
+func (nnInterpolator) scale_RGBA_NYCbCrA440_Src(dst *image.RGBA, dr, adr image.Rectangle, src *image.NYCbCrA, sr image.Rectangle, opts *Options) {
 	dw2 := uint64(dr.Dx()) * 2
 	dh2 := uint64(dr.Dy()) * 2
 	sw := uint64(sr.Dx())
 	sh := uint64(sr.Dy())
-	srcMask, smp := opts.SrcMask, opts.SrcMaskP
-	dstMask, dmp := opts.DstMask, opts.DstMaskP
-	dstColorRGBA64 := &color.RGBA64{}
-	dstColor := color.Color(dstColorRGBA64)
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
 		sy := (2*uint64(dy) + 1) * sh / dh2
-		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
 			sx := (2*uint64(dx) + 1) * sw / dw2
-			pr, pg, pb, pa := src.At(sr.Min.X+int(sx), sr.Min.Y+int(sy)).RGBA()
-			if srcMask != nil {
-				_, _, _, ma := srcMask.At(smp.X+sr.Min.X+int(sx), smp.Y+sr.Min.Y+int(sy)).RGBA()
-				pr = pr * ma / 0xffff
-				pg = pg * ma / 0xffff
-				pb = pb * ma / 0xffff
-				pa = pa * ma / 0xffff
-			}
-			qr, qg, qb, qa := dst.At(dr.Min.X+int(dx), dr.Min.Y+int(dy)).RGBA()
-			if dstMask != nil {
-				_, _, _, ma := dstMask.At(dmp.X+dr.Min.X+int(dx), dmp.Y+dr.Min.Y+int(dy)).RGBA()
-				pr = pr * ma / 0xffff
-				pg = pg * ma / 0xffff
-				pb = pb * ma / 0xffff
-				pa = pa * ma / 0xffff
-			}
-			pa1 := 0xffff - pa
-			dstColorRGBA64.R = uint16(qr*pa1/0xffff + pr)
-			dstColorRGBA64.G = uint16(qg*pa1/0xffff + pg)
-			dstColorRGBA64.B = uint16(qb*pa1/0xffff + pb)
-			dstColorRGBA64.A = uint16(qa*pa1/0xffff + pa)
-			dst.Set(dr.Min.X+int(dx), dr.Min.Y+int(dy), dstColor)
+			pi := (sr.Min.Y+int(sy)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx) - src.Rect.Min.X)
+			pj := ((sr.Min.Y+int(sy))/2-src.Rect.Min.Y/2)*src.CStride + (sr.Min.X + int(sx) - src.Rect.Min.X)
+			pk := (sr.Min.Y+int(sy)-src.Rect.Min.Y)*src.AStride + (sr.Min.X + int(sx) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			pyy1 := int(src.Y[pi]) * 0x10101
+			pcb1 := int(src.Cb[pj]) - 128
+			pcr1 := int(src.Cr[pj]) - 128
+			pr0 := (pyy1 + 91881*pcr1) >> 8
+			pg0 := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
+			pb0 := (pyy1 + 116130*pcb1) >> 8
+			if pr0 < 0 {
+				pr0 = 0
+			} else if pr0 > 0xffff {
+				pr0 = 0xffff
+			}
+			if pg0 < 0 {
+				pg0 = 0
+			} else if pg0 > 0xffff {
+				pg0 = 0xffff
+			}
+			if pb0 < 0 {
+				pb0 = 0
+			} else if pb0 > 0xffff {
+				pb0 = 0xffff
+			}
+
+			pa := uint32(src.A[pk]) * 0x101
+			pr := uint32(pr0) * pa / 0xffff
+			pg := uint32(pg0) * pa / 0xffff
+			pb := uint32(pb0) * pa / 0xffff
+			dst.Pix[d+0] = uint8(pr >> 8)
+			dst.Pix[d+1] = uint8(pg >> 8)
+			dst.Pix[d+2] = uint8(pb >> 8)
+			dst.Pix[d+3] = uint8(pa >> 8)
 		}
 	}
 }
 
-func (nnInterpolator) scale_Image_Image_Src(dst Image, dr, adr image.Rectangle, src image.Image, sr image.Rectangle, opts *Options) {
+func (nnInterpolator) scale_RGBA_RGBA64Image_Over(dst *image.RGBA, dr, adr image.Rectangle, src image.RGBA64Image, sr image.Rectangle, opts *Options) {
 	dw2 := uint64(dr.Dx()) * 2
 	dh2 := uint64(dr.Dy()) * 2
 	sw := uint64(sr.Dx())
 	sh := uint64(sr.Dy())
-	srcMask, smp := opts.SrcMask, opts.SrcMaskP
-	dstMask, dmp := opts.DstMask, opts.DstMaskP
-	dstColorRGBA64 := &color.RGBA64{}
-	dstColor := color.Color(dstColorRGBA64)
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
 		sy := (2*uint64(dy) + 1) * sh / dh2
-		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
 			sx := (2*uint64(dx) + 1) * sw / dw2
-			pr, pg, pb, pa := src.At(sr.Min.X+int(sx), sr.Min.Y+int(sy)).RGBA()
-			if srcMask != nil {
-				_, _, _, ma := srcMask.At(smp.X+sr.Min.X+int(sx), smp.Y+sr.Min.Y+int(sy)).RGBA()
-				pr = pr * ma / 0xffff
-				pg = pg * ma / 0xffff
-				pb = pb * ma / 0xffff
-				pa = pa * ma / 0xffff
-			}
-			if dstMask != nil {
-				qr, qg, qb, qa := dst.At(dr.Min.X+int(dx), dr.Min.Y+int(dy)).RGBA()
-				_, _, _, ma := dstMask.At(dmp.X+dr.Min.X+int(dx), dmp.Y+dr.Min.Y+int(dy)).RGBA()
-				pr = pr * ma / 0xffff
-				pg = pg * ma / 0xffff
-				pb = pb * ma / 0xffff
-				pa = pa * ma / 0xffff
-				pa1 := 0xffff - ma
-				dstColorRGBA64.R = uint16(qr*pa1/0xffff + pr)
-				dstColorRGBA64.G = uint16(qg*pa1/0xffff + pg)
-				dstColorRGBA64.B = uint16(qb*pa1/0xffff + pb)
-				dstColorRGBA64.A = uint16(qa*pa1/0xffff + pa)
-				dst.Set(dr.Min.X+int(dx), dr.Min.Y+int(dy), dstColor)
-			} else {
-				dstColorRGBA64.R = uint16(pr)
-				dstColorRGBA64.G = uint16(pg)
-				dstColorRGBA64.B = uint16(pb)
-				dstColorRGBA64.A = uint16(pa)
-				dst.Set(dr.Min.X+int(dx), dr.Min.Y+int(dy), dstColor)
-			}
+			p := src.RGBA64At(sr.Min.X+int(sx), sr.Min.Y+int(sy))
+			pa1 := (0xffff - uint32(p.A)) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + uint32(p.R)) >> 8)
+			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + uint32(p.G)) >> 8)
+			dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*pa1/0xffff + uint32(p.B)) >> 8)
+			dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*pa1/0xffff + uint32(p.A)) >> 8)
 		}
 	}
 }
 
-func (nnInterpolator) transform_RGBA_Gray_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.Gray, sr image.Rectangle, bias image.Point, opts *Options) {
+func (nnInterpolator) scale_RGBA_RGBA64Image_Src(dst *image.RGBA, dr, adr image.Rectangle, src image.RGBA64Image, sr image.Rectangle, opts *Options) {
+	dw2 := uint64(dr.Dx()) * 2
+	dh2 := uint64(dr.Dy()) * 2
+	sw := uint64(sr.Dx())
+	sh := uint64(sr.Dy())
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
-		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		sy := (2*uint64(dy) + 1) * sh / dh2
 		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
 		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
-			dxf := float64(dr.Min.X+int(dx)) + 0.5
-			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
-			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
-			if !(image.Point{sx0, sy0}).In(sr) {
-				continue
-			}
-			pi := (sy0-src.Rect.Min.Y)*src.Stride + (sx0 - src.Rect.Min.X)
-			pr := uint32(src.Pix[pi]) * 0x101
-			out := uint8(pr >> 8)
-			dst.Pix[d+0] = out
-			dst.Pix[d+1] = out
-			dst.Pix[d+2] = out
-			dst.Pix[d+3] = 0xff
+			sx := (2*uint64(dx) + 1) * sw / dw2
+			p := src.RGBA64At(sr.Min.X+int(sx), sr.Min.Y+int(sy))
+			dst.Pix[d+0] = uint8(p.R >> 8)
+			dst.Pix[d+1] = uint8(p.G >> 8)
+			dst.Pix[d+2] = uint8(p.B >> 8)
+			dst.Pix[d+3] = uint8(p.A >> 8)
 		}
 	}
 }
 
-func (nnInterpolator) transform_RGBA_NRGBA_Over(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NRGBA, sr image.Rectangle, bias image.Point, opts *Options) {
+func (nnInterpolator) scale_RGBA_Image_Over(dst *image.RGBA, dr, adr image.Rectangle, src image.Image, sr image.Rectangle, opts *Options) {
+	dw2 := uint64(dr.Dx()) * 2
+	dh2 := uint64(dr.Dy()) * 2
+	sw := uint64(sr.Dx())
+	sh := uint64(sr.Dy())
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
-		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		sy := (2*uint64(dy) + 1) * sh / dh2
 		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
 		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
-			dxf := float64(dr.Min.X+int(dx)) + 0.5
-			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
-			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
-			if !(image.Point{sx0, sy0}).In(sr) {
-				continue
-			}
-			pi := (sy0-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*4
-			pa := uint32(src.Pix[pi+3]) * 0x101
-			pr := uint32(src.Pix[pi+0]) * pa / 0xff
-			pg := uint32(src.Pix[pi+1]) * pa / 0xff
-			pb := uint32(src.Pix[pi+2]) * pa / 0xff
+			sx := (2*uint64(dx) + 1) * sw / dw2
+			pr, pg, pb, pa := src.At(sr.Min.X+int(sx), sr.Min.Y+int(sy)).RGBA()
 			pa1 := (0xffff - pa) * 0x101
 			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pr) >> 8)
 			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + pg) >> 8)
@@ -819,22 +1389,17 @@ func (nnInterpolator) transform_RGBA_NRGBA_Over(dst *image.RGBA, dr, adr image.R
 	}
 }
 
-func (nnInterpolator) transform_RGBA_NRGBA_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NRGBA, sr image.Rectangle, bias image.Point, opts *Options) {
+func (nnInterpolator) scale_RGBA_Image_Src(dst *image.RGBA, dr, adr image.Rectangle, src image.Image, sr image.Rectangle, opts *Options) {
+	dw2 := uint64(dr.Dx()) * 2
+	dh2 := uint64(dr.Dy()) * 2
+	sw := uint64(sr.Dx())
+	sh := uint64(sr.Dy())
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
-		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		sy := (2*uint64(dy) + 1) * sh / dh2
 		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
 		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
-			dxf := float64(dr.Min.X+int(dx)) + 0.5
-			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
-			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
-			if !(image.Point{sx0, sy0}).In(sr) {
-				continue
-			}
-			pi := (sy0-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*4
-			pa := uint32(src.Pix[pi+3]) * 0x101
-			pr := uint32(src.Pix[pi+0]) * pa / 0xff
-			pg := uint32(src.Pix[pi+1]) * pa / 0xff
-			pb := uint32(src.Pix[pi+2]) * pa / 0xff
+			sx := (2*uint64(dx) + 1) * sw / dw2
+			pr, pg, pb, pa := src.At(sr.Min.X+int(sx), sr.Min.Y+int(sy)).RGBA()
 			dst.Pix[d+0] = uint8(pr >> 8)
 			dst.Pix[d+1] = uint8(pg >> 8)
 			dst.Pix[d+2] = uint8(pb >> 8)
@@ -843,330 +1408,538 @@ func (nnInterpolator) transform_RGBA_NRGBA_Src(dst *image.RGBA, dr, adr image.Re
 	}
 }
 
-func (nnInterpolator) transform_RGBA_RGBA_Over(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.RGBA, sr image.Rectangle, bias image.Point, opts *Options) {
+func (nnInterpolator) scale_RGBA64_NRGBA64_Over(dst *image.RGBA64, dr, adr image.Rectangle, src *image.NRGBA64, sr image.Rectangle, opts *Options) {
+	dw2 := uint64(dr.Dx()) * 2
+	dh2 := uint64(dr.Dy()) * 2
+	sw := uint64(sr.Dx())
+	sh := uint64(sr.Dy())
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
-		dyf := float64(dr.Min.Y+int(dy)) + 0.5
-		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
-		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
-			dxf := float64(dr.Min.X+int(dx)) + 0.5
-			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
-			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
-			if !(image.Point{sx0, sy0}).In(sr) {
-				continue
-			}
-			pi := (sy0-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*4
-			pr := uint32(src.Pix[pi+0]) * 0x101
-			pg := uint32(src.Pix[pi+1]) * 0x101
-			pb := uint32(src.Pix[pi+2]) * 0x101
-			pa := uint32(src.Pix[pi+3]) * 0x101
-			pa1 := (0xffff - pa) * 0x101
-			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pr) >> 8)
-			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + pg) >> 8)
-			dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*pa1/0xffff + pb) >> 8)
-			dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*pa1/0xffff + pa) >> 8)
+		sy := (2*uint64(dy) + 1) * sh / dh2
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			sx := (2*uint64(dx) + 1) * sw / dw2
+			pi := (sr.Min.Y+int(sy)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx)-src.Rect.Min.X)*8
+			pa := (uint32(src.Pix[pi+6])<<8 | uint32(src.Pix[pi+7]))
+			pr := (uint32(src.Pix[pi+0])<<8 | uint32(src.Pix[pi+1])) * pa / 0xffff
+			pg := (uint32(src.Pix[pi+2])<<8 | uint32(src.Pix[pi+3])) * pa / 0xffff
+			pb := (uint32(src.Pix[pi+4])<<8 | uint32(src.Pix[pi+5])) * pa / 0xffff
+			pa1 := 0xffff - pa
+			pr0 := (uint32(dst.Pix[d+0])<<8|uint32(dst.Pix[d+1]))*pa1/0xffff + pr
+			pg0 := (uint32(dst.Pix[d+2])<<8|uint32(dst.Pix[d+3]))*pa1/0xffff + pg
+			pb0 := (uint32(dst.Pix[d+4])<<8|uint32(dst.Pix[d+5]))*pa1/0xffff + pb
+			pa0 := (uint32(dst.Pix[d+6])<<8|uint32(dst.Pix[d+7]))*pa1/0xffff + pa
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
 		}
 	}
 }
 
-func (nnInterpolator) transform_RGBA_RGBA_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.RGBA, sr image.Rectangle, bias image.Point, opts *Options) {
+func (nnInterpolator) scale_RGBA64_NRGBA64_Src(dst *image.RGBA64, dr, adr image.Rectangle, src *image.NRGBA64, sr image.Rectangle, opts *Options) {
+	dw2 := uint64(dr.Dx()) * 2
+	dh2 := uint64(dr.Dy()) * 2
+	sw := uint64(sr.Dx())
+	sh := uint64(sr.Dy())
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
-		dyf := float64(dr.Min.Y+int(dy)) + 0.5
-		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
-		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
-			dxf := float64(dr.Min.X+int(dx)) + 0.5
-			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
-			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
-			if !(image.Point{sx0, sy0}).In(sr) {
-				continue
-			}
-			pi := (sy0-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*4
-			pr := uint32(src.Pix[pi+0]) * 0x101
-			pg := uint32(src.Pix[pi+1]) * 0x101
-			pb := uint32(src.Pix[pi+2]) * 0x101
-			pa := uint32(src.Pix[pi+3]) * 0x101
-			dst.Pix[d+0] = uint8(pr >> 8)
-			dst.Pix[d+1] = uint8(pg >> 8)
-			dst.Pix[d+2] = uint8(pb >> 8)
-			dst.Pix[d+3] = uint8(pa >> 8)
+		sy := (2*uint64(dy) + 1) * sh / dh2
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			sx := (2*uint64(dx) + 1) * sw / dw2
+			pi := (sr.Min.Y+int(sy)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx)-src.Rect.Min.X)*8
+			pa := (uint32(src.Pix[pi+6])<<8 | uint32(src.Pix[pi+7]))
+			pr := (uint32(src.Pix[pi+0])<<8 | uint32(src.Pix[pi+1])) * pa / 0xffff
+			pg := (uint32(src.Pix[pi+2])<<8 | uint32(src.Pix[pi+3])) * pa / 0xffff
+			pb := (uint32(src.Pix[pi+4])<<8 | uint32(src.Pix[pi+5])) * pa / 0xffff
+			pr0 := pr
+			pg0 := pg
+			pb0 := pb
+			pa0 := pa
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
 		}
 	}
 }
 
-func (nnInterpolator) transform_RGBA_YCbCr444_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.YCbCr, sr image.Rectangle, bias image.Point, opts *Options) {
+func (nnInterpolator) scale_RGBA64_RGBA64_Over(dst *image.RGBA64, dr, adr image.Rectangle, src *image.RGBA64, sr image.Rectangle, opts *Options) {
+	dw2 := uint64(dr.Dx()) * 2
+	dh2 := uint64(dr.Dy()) * 2
+	sw := uint64(sr.Dx())
+	sh := uint64(sr.Dy())
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
-		dyf := float64(dr.Min.Y+int(dy)) + 0.5
-		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
-		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
-			dxf := float64(dr.Min.X+int(dx)) + 0.5
-			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
-			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
-			if !(image.Point{sx0, sy0}).In(sr) {
-				continue
-			}
-			pi := (sy0-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
-			pj := (sy0-src.Rect.Min.Y)*src.CStride + (sx0 - src.Rect.Min.X)
-
-			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
-			pyy1 := int(src.Y[pi]) * 0x10101
-			pcb1 := int(src.Cb[pj]) - 128
-			pcr1 := int(src.Cr[pj]) - 128
-			pr := (pyy1 + 91881*pcr1) >> 8
-			pg := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
-			pb := (pyy1 + 116130*pcb1) >> 8
-			if pr < 0 {
-				pr = 0
-			} else if pr > 0xffff {
-				pr = 0xffff
-			}
-			if pg < 0 {
-				pg = 0
-			} else if pg > 0xffff {
-				pg = 0xffff
-			}
-			if pb < 0 {
-				pb = 0
-			} else if pb > 0xffff {
-				pb = 0xffff
-			}
-			dst.Pix[d+0] = uint8(pr >> 8)
-			dst.Pix[d+1] = uint8(pg >> 8)
-			dst.Pix[d+2] = uint8(pb >> 8)
-			dst.Pix[d+3] = 0xff
+		sy := (2*uint64(dy) + 1) * sh / dh2
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			sx := (2*uint64(dx) + 1) * sw / dw2
+			pi := (sr.Min.Y+int(sy)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx)-src.Rect.Min.X)*8
+			pr := (uint32(src.Pix[pi+0])<<8 | uint32(src.Pix[pi+1]))
+			pg := (uint32(src.Pix[pi+2])<<8 | uint32(src.Pix[pi+3]))
+			pb := (uint32(src.Pix[pi+4])<<8 | uint32(src.Pix[pi+5]))
+			pa := (uint32(src.Pix[pi+6])<<8 | uint32(src.Pix[pi+7]))
+			pa1 := 0xffff - pa
+			pr0 := (uint32(dst.Pix[d+0])<<8|uint32(dst.Pix[d+1]))*pa1/0xffff + pr
+			pg0 := (uint32(dst.Pix[d+2])<<8|uint32(dst.Pix[d+3]))*pa1/0xffff + pg
+			pb0 := (uint32(dst.Pix[d+4])<<8|uint32(dst.Pix[d+5]))*pa1/0xffff + pb
+			pa0 := (uint32(dst.Pix[d+6])<<8|uint32(dst.Pix[d+7]))*pa1/0xffff + pa
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
 		}
 	}
 }
 
-func (nnInterpolator) transform_RGBA_YCbCr422_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.YCbCr, sr image.Rectangle, bias image.Point, opts *Options) {
+func (nnInterpolator) scale_RGBA64_RGBA64_Src(dst *image.RGBA64, dr, adr image.Rectangle, src *image.RGBA64, sr image.Rectangle, opts *Options) {
+	dw2 := uint64(dr.Dx()) * 2
+	dh2 := uint64(dr.Dy()) * 2
+	sw := uint64(sr.Dx())
+	sh := uint64(sr.Dy())
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
-		dyf := float64(dr.Min.Y+int(dy)) + 0.5
-		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
-		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
-			dxf := float64(dr.Min.X+int(dx)) + 0.5
-			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
-			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
-			if !(image.Point{sx0, sy0}).In(sr) {
-				continue
-			}
-			pi := (sy0-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
-			pj := (sy0-src.Rect.Min.Y)*src.CStride + ((sx0)/2 - src.Rect.Min.X/2)
+		sy := (2*uint64(dy) + 1) * sh / dh2
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			sx := (2*uint64(dx) + 1) * sw / dw2
+			pi := (sr.Min.Y+int(sy)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx)-src.Rect.Min.X)*8
+			pr := (uint32(src.Pix[pi+0])<<8 | uint32(src.Pix[pi+1]))
+			pg := (uint32(src.Pix[pi+2])<<8 | uint32(src.Pix[pi+3]))
+			pb := (uint32(src.Pix[pi+4])<<8 | uint32(src.Pix[pi+5]))
+			pa := (uint32(src.Pix[pi+6])<<8 | uint32(src.Pix[pi+7]))
+			pr0 := pr
+			pg0 := pg
+			pb0 := pb
+			pa0 := pa
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
 
-			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
-			pyy1 := int(src.Y[pi]) * 0x10101
-			pcb1 := int(src.Cb[pj]) - 128
-			pcr1 := int(src.Cr[pj]) - 128
-			pr := (pyy1 + 91881*pcr1) >> 8
-			pg := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
-			pb := (pyy1 + 116130*pcb1) >> 8
-			if pr < 0 {
-				pr = 0
-			} else if pr > 0xffff {
-				pr = 0xffff
-			}
-			if pg < 0 {
-				pg = 0
-			} else if pg > 0xffff {
-				pg = 0xffff
-			}
-			if pb < 0 {
-				pb = 0
-			} else if pb > 0xffff {
-				pb = 0xffff
-			}
-			dst.Pix[d+0] = uint8(pr >> 8)
-			dst.Pix[d+1] = uint8(pg >> 8)
-			dst.Pix[d+2] = uint8(pb >> 8)
-			dst.Pix[d+3] = 0xff
+func (nnInterpolator) scale_RGBA64_RGBA64Image_Over(dst *image.RGBA64, dr, adr image.Rectangle, src image.RGBA64Image, sr image.Rectangle, opts *Options) {
+	dw2 := uint64(dr.Dx()) * 2
+	dh2 := uint64(dr.Dy()) * 2
+	sw := uint64(sr.Dx())
+	sh := uint64(sr.Dy())
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := (2*uint64(dy) + 1) * sh / dh2
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			sx := (2*uint64(dx) + 1) * sw / dw2
+			p := src.RGBA64At(sr.Min.X+int(sx), sr.Min.Y+int(sy))
+			pa1 := 0xffff - uint32(p.A)
+			pr0 := (uint32(dst.Pix[d+0])<<8|uint32(dst.Pix[d+1]))*pa1/0xffff + uint32(p.R)
+			pg0 := (uint32(dst.Pix[d+2])<<8|uint32(dst.Pix[d+3]))*pa1/0xffff + uint32(p.G)
+			pb0 := (uint32(dst.Pix[d+4])<<8|uint32(dst.Pix[d+5]))*pa1/0xffff + uint32(p.B)
+			pa0 := (uint32(dst.Pix[d+6])<<8|uint32(dst.Pix[d+7]))*pa1/0xffff + uint32(p.A)
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
 		}
 	}
 }
 
-func (nnInterpolator) transform_RGBA_YCbCr420_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.YCbCr, sr image.Rectangle, bias image.Point, opts *Options) {
+func (nnInterpolator) scale_RGBA64_RGBA64Image_Src(dst *image.RGBA64, dr, adr image.Rectangle, src image.RGBA64Image, sr image.Rectangle, opts *Options) {
+	dw2 := uint64(dr.Dx()) * 2
+	dh2 := uint64(dr.Dy()) * 2
+	sw := uint64(sr.Dx())
+	sh := uint64(sr.Dy())
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
-		dyf := float64(dr.Min.Y+int(dy)) + 0.5
-		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
-		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
-			dxf := float64(dr.Min.X+int(dx)) + 0.5
-			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
-			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
-			if !(image.Point{sx0, sy0}).In(sr) {
-				continue
-			}
-			pi := (sy0-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
-			pj := ((sy0)/2-src.Rect.Min.Y/2)*src.CStride + ((sx0)/2 - src.Rect.Min.X/2)
+		sy := (2*uint64(dy) + 1) * sh / dh2
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			sx := (2*uint64(dx) + 1) * sw / dw2
+			p := src.RGBA64At(sr.Min.X+int(sx), sr.Min.Y+int(sy))
+			pr0 := uint32(p.R)
+			pg0 := uint32(p.G)
+			pb0 := uint32(p.B)
+			pa0 := uint32(p.A)
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
 
-			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
-			pyy1 := int(src.Y[pi]) * 0x10101
-			pcb1 := int(src.Cb[pj]) - 128
-			pcr1 := int(src.Cr[pj]) - 128
-			pr := (pyy1 + 91881*pcr1) >> 8
-			pg := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
-			pb := (pyy1 + 116130*pcb1) >> 8
-			if pr < 0 {
-				pr = 0
-			} else if pr > 0xffff {
-				pr = 0xffff
-			}
-			if pg < 0 {
-				pg = 0
-			} else if pg > 0xffff {
-				pg = 0xffff
-			}
-			if pb < 0 {
-				pb = 0
-			} else if pb > 0xffff {
-				pb = 0xffff
-			}
-			dst.Pix[d+0] = uint8(pr >> 8)
-			dst.Pix[d+1] = uint8(pg >> 8)
-			dst.Pix[d+2] = uint8(pb >> 8)
-			dst.Pix[d+3] = 0xff
+func (nnInterpolator) scale_RGBA64_Image_Over(dst *image.RGBA64, dr, adr image.Rectangle, src image.Image, sr image.Rectangle, opts *Options) {
+	dw2 := uint64(dr.Dx()) * 2
+	dh2 := uint64(dr.Dy()) * 2
+	sw := uint64(sr.Dx())
+	sh := uint64(sr.Dy())
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := (2*uint64(dy) + 1) * sh / dh2
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			sx := (2*uint64(dx) + 1) * sw / dw2
+			pr, pg, pb, pa := src.At(sr.Min.X+int(sx), sr.Min.Y+int(sy)).RGBA()
+			pa1 := 0xffff - pa
+			pr0 := (uint32(dst.Pix[d+0])<<8|uint32(dst.Pix[d+1]))*pa1/0xffff + pr
+			pg0 := (uint32(dst.Pix[d+2])<<8|uint32(dst.Pix[d+3]))*pa1/0xffff + pg
+			pb0 := (uint32(dst.Pix[d+4])<<8|uint32(dst.Pix[d+5]))*pa1/0xffff + pb
+			pa0 := (uint32(dst.Pix[d+6])<<8|uint32(dst.Pix[d+7]))*pa1/0xffff + pa
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
 		}
 	}
 }
 
-func (nnInterpolator) transform_RGBA_YCbCr440_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.YCbCr, sr image.Rectangle, bias image.Point, opts *Options) {
+func (nnInterpolator) scale_RGBA64_Image_Src(dst *image.RGBA64, dr, adr image.Rectangle, src image.Image, sr image.Rectangle, opts *Options) {
+	dw2 := uint64(dr.Dx()) * 2
+	dh2 := uint64(dr.Dy()) * 2
+	sw := uint64(sr.Dx())
+	sh := uint64(sr.Dy())
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
-		dyf := float64(dr.Min.Y+int(dy)) + 0.5
-		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
-		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
-			dxf := float64(dr.Min.X+int(dx)) + 0.5
-			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
-			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
-			if !(image.Point{sx0, sy0}).In(sr) {
-				continue
-			}
-			pi := (sy0-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
-			pj := ((sy0)/2-src.Rect.Min.Y/2)*src.CStride + (sx0 - src.Rect.Min.X)
+		sy := (2*uint64(dy) + 1) * sh / dh2
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			sx := (2*uint64(dx) + 1) * sw / dw2
+			pr, pg, pb, pa := src.At(sr.Min.X+int(sx), sr.Min.Y+int(sy)).RGBA()
+			pr0 := pr
+			pg0 := pg
+			pb0 := pb
+			pa0 := pa
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
 
-			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
-			pyy1 := int(src.Y[pi]) * 0x10101
-			pcb1 := int(src.Cb[pj]) - 128
-			pcr1 := int(src.Cr[pj]) - 128
-			pr := (pyy1 + 91881*pcr1) >> 8
-			pg := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
-			pb := (pyy1 + 116130*pcb1) >> 8
-			if pr < 0 {
-				pr = 0
-			} else if pr > 0xffff {
-				pr = 0xffff
-			}
-			if pg < 0 {
-				pg = 0
-			} else if pg > 0xffff {
-				pg = 0xffff
-			}
-			if pb < 0 {
-				pb = 0
-			} else if pb > 0xffff {
-				pb = 0xffff
-			}
-			dst.Pix[d+0] = uint8(pr >> 8)
-			dst.Pix[d+1] = uint8(pg >> 8)
-			dst.Pix[d+2] = uint8(pb >> 8)
-			dst.Pix[d+3] = 0xff
+func (nnInterpolator) scale_NRGBA64_NRGBA64_Over(dst *image.NRGBA64, dr, adr image.Rectangle, src *image.NRGBA64, sr image.Rectangle, opts *Options) {
+	dw2 := uint64(dr.Dx()) * 2
+	dh2 := uint64(dr.Dy()) * 2
+	sw := uint64(sr.Dx())
+	sh := uint64(sr.Dy())
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := (2*uint64(dy) + 1) * sh / dh2
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			sx := (2*uint64(dx) + 1) * sw / dw2
+			pi := (sr.Min.Y+int(sy)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx)-src.Rect.Min.X)*8
+			pa := (uint32(src.Pix[pi+6])<<8 | uint32(src.Pix[pi+7]))
+			pr := (uint32(src.Pix[pi+0])<<8 | uint32(src.Pix[pi+1])) * pa / 0xffff
+			pg := (uint32(src.Pix[pi+2])<<8 | uint32(src.Pix[pi+3])) * pa / 0xffff
+			pb := (uint32(src.Pix[pi+4])<<8 | uint32(src.Pix[pi+5])) * pa / 0xffff
+			pa1 := 0xffff - pa
+			pqa := (uint32(dst.Pix[d+6])<<8 | uint32(dst.Pix[d+7]))
+			pr0 := ((uint32(dst.Pix[d+0])<<8|uint32(dst.Pix[d+1]))*pqa/0xffff)*pa1/0xffff + pr
+			pg0 := ((uint32(dst.Pix[d+2])<<8|uint32(dst.Pix[d+3]))*pqa/0xffff)*pa1/0xffff + pg
+			pb0 := ((uint32(dst.Pix[d+4])<<8|uint32(dst.Pix[d+5]))*pqa/0xffff)*pa1/0xffff + pb
+			pa0 := pqa*pa1/0xffff + pa
+			if pa0 != 0 && pa0 != 0xffff {
+				pr0 = pr0 * 0xffff / pa0
+				pg0 = pg0 * 0xffff / pa0
+				pb0 = pb0 * 0xffff / pa0
+			}
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
 		}
 	}
 }
 
-func (nnInterpolator) transform_RGBA_RGBA64Image_Over(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src image.RGBA64Image, sr image.Rectangle, bias image.Point, opts *Options) {
+func (nnInterpolator) scale_NRGBA64_NRGBA64_Src(dst *image.NRGBA64, dr, adr image.Rectangle, src *image.NRGBA64, sr image.Rectangle, opts *Options) {
+	dw2 := uint64(dr.Dx()) * 2
+	dh2 := uint64(dr.Dy()) * 2
+	sw := uint64(sr.Dx())
+	sh := uint64(sr.Dy())
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
-		dyf := float64(dr.Min.Y+int(dy)) + 0.5
-		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
-		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
-			dxf := float64(dr.Min.X+int(dx)) + 0.5
-			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
-			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
-			if !(image.Point{sx0, sy0}).In(sr) {
-				continue
-			}
-			p := src.RGBA64At(sx0, sy0)
-			pa1 := (0xffff - uint32(p.A)) * 0x101
-			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + uint32(p.R)) >> 8)
-			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + uint32(p.G)) >> 8)
-			dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*pa1/0xffff + uint32(p.B)) >> 8)
-			dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*pa1/0xffff + uint32(p.A)) >> 8)
+		sy := (2*uint64(dy) + 1) * sh / dh2
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			sx := (2*uint64(dx) + 1) * sw / dw2
+			pi := (sr.Min.Y+int(sy)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx)-src.Rect.Min.X)*8
+			pa := (uint32(src.Pix[pi+6])<<8 | uint32(src.Pix[pi+7]))
+			pr := (uint32(src.Pix[pi+0])<<8 | uint32(src.Pix[pi+1])) * pa / 0xffff
+			pg := (uint32(src.Pix[pi+2])<<8 | uint32(src.Pix[pi+3])) * pa / 0xffff
+			pb := (uint32(src.Pix[pi+4])<<8 | uint32(src.Pix[pi+5])) * pa / 0xffff
+			pr0 := pr
+			pg0 := pg
+			pb0 := pb
+			pa0 := pa
+			if pa0 != 0 && pa0 != 0xffff {
+				pr0 = pr0 * 0xffff / pa0
+				pg0 = pg0 * 0xffff / pa0
+				pb0 = pb0 * 0xffff / pa0
+			}
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
 		}
 	}
 }
 
-func (nnInterpolator) transform_RGBA_RGBA64Image_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src image.RGBA64Image, sr image.Rectangle, bias image.Point, opts *Options) {
+func (nnInterpolator) scale_NRGBA64_RGBA64_Over(dst *image.NRGBA64, dr, adr image.Rectangle, src *image.RGBA64, sr image.Rectangle, opts *Options) {
+	dw2 := uint64(dr.Dx()) * 2
+	dh2 := uint64(dr.Dy()) * 2
+	sw := uint64(sr.Dx())
+	sh := uint64(sr.Dy())
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
-		dyf := float64(dr.Min.Y+int(dy)) + 0.5
-		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
-		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
-			dxf := float64(dr.Min.X+int(dx)) + 0.5
-			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
-			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
-			if !(image.Point{sx0, sy0}).In(sr) {
-				continue
-			}
-			p := src.RGBA64At(sx0, sy0)
-			dst.Pix[d+0] = uint8(p.R >> 8)
-			dst.Pix[d+1] = uint8(p.G >> 8)
-			dst.Pix[d+2] = uint8(p.B >> 8)
-			dst.Pix[d+3] = uint8(p.A >> 8)
+		sy := (2*uint64(dy) + 1) * sh / dh2
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			sx := (2*uint64(dx) + 1) * sw / dw2
+			pi := (sr.Min.Y+int(sy)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx)-src.Rect.Min.X)*8
+			pr := (uint32(src.Pix[pi+0])<<8 | uint32(src.Pix[pi+1]))
+			pg := (uint32(src.Pix[pi+2])<<8 | uint32(src.Pix[pi+3]))
+			pb := (uint32(src.Pix[pi+4])<<8 | uint32(src.Pix[pi+5]))
+			pa := (uint32(src.Pix[pi+6])<<8 | uint32(src.Pix[pi+7]))
+			pa1 := 0xffff - pa
+			pqa := (uint32(dst.Pix[d+6])<<8 | uint32(dst.Pix[d+7]))
+			pr0 := ((uint32(dst.Pix[d+0])<<8|uint32(dst.Pix[d+1]))*pqa/0xffff)*pa1/0xffff + pr
+			pg0 := ((uint32(dst.Pix[d+2])<<8|uint32(dst.Pix[d+3]))*pqa/0xffff)*pa1/0xffff + pg
+			pb0 := ((uint32(dst.Pix[d+4])<<8|uint32(dst.Pix[d+5]))*pqa/0xffff)*pa1/0xffff + pb
+			pa0 := pqa*pa1/0xffff + pa
+			if pa0 != 0 && pa0 != 0xffff {
+				pr0 = pr0 * 0xffff / pa0
+				pg0 = pg0 * 0xffff / pa0
+				pb0 = pb0 * 0xffff / pa0
+			}
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
 		}
 	}
 }
 
-func (nnInterpolator) transform_RGBA_Image_Over(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src image.Image, sr image.Rectangle, bias image.Point, opts *Options) {
+func (nnInterpolator) scale_NRGBA64_RGBA64_Src(dst *image.NRGBA64, dr, adr image.Rectangle, src *image.RGBA64, sr image.Rectangle, opts *Options) {
+	dw2 := uint64(dr.Dx()) * 2
+	dh2 := uint64(dr.Dy()) * 2
+	sw := uint64(sr.Dx())
+	sh := uint64(sr.Dy())
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
-		dyf := float64(dr.Min.Y+int(dy)) + 0.5
-		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
-		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
-			dxf := float64(dr.Min.X+int(dx)) + 0.5
-			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
-			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
-			if !(image.Point{sx0, sy0}).In(sr) {
-				continue
-			}
-			pr, pg, pb, pa := src.At(sx0, sy0).RGBA()
-			pa1 := (0xffff - pa) * 0x101
-			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pr) >> 8)
-			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + pg) >> 8)
-			dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*pa1/0xffff + pb) >> 8)
-			dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*pa1/0xffff + pa) >> 8)
+		sy := (2*uint64(dy) + 1) * sh / dh2
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			sx := (2*uint64(dx) + 1) * sw / dw2
+			pi := (sr.Min.Y+int(sy)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx)-src.Rect.Min.X)*8
+			pr := (uint32(src.Pix[pi+0])<<8 | uint32(src.Pix[pi+1]))
+			pg := (uint32(src.Pix[pi+2])<<8 | uint32(src.Pix[pi+3]))
+			pb := (uint32(src.Pix[pi+4])<<8 | uint32(src.Pix[pi+5]))
+			pa := (uint32(src.Pix[pi+6])<<8 | uint32(src.Pix[pi+7]))
+			pr0 := pr
+			pg0 := pg
+			pb0 := pb
+			pa0 := pa
+			if pa0 != 0 && pa0 != 0xffff {
+				pr0 = pr0 * 0xffff / pa0
+				pg0 = pg0 * 0xffff / pa0
+				pb0 = pb0 * 0xffff / pa0
+			}
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
 		}
 	}
 }
 
-func (nnInterpolator) transform_RGBA_Image_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src image.Image, sr image.Rectangle, bias image.Point, opts *Options) {
+func (nnInterpolator) scale_NRGBA64_RGBA64Image_Over(dst *image.NRGBA64, dr, adr image.Rectangle, src image.RGBA64Image, sr image.Rectangle, opts *Options) {
+	dw2 := uint64(dr.Dx()) * 2
+	dh2 := uint64(dr.Dy()) * 2
+	sw := uint64(sr.Dx())
+	sh := uint64(sr.Dy())
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
-		dyf := float64(dr.Min.Y+int(dy)) + 0.5
-		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
-		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
-			dxf := float64(dr.Min.X+int(dx)) + 0.5
-			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
-			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
-			if !(image.Point{sx0, sy0}).In(sr) {
-				continue
-			}
-			pr, pg, pb, pa := src.At(sx0, sy0).RGBA()
-			dst.Pix[d+0] = uint8(pr >> 8)
-			dst.Pix[d+1] = uint8(pg >> 8)
-			dst.Pix[d+2] = uint8(pb >> 8)
-			dst.Pix[d+3] = uint8(pa >> 8)
+		sy := (2*uint64(dy) + 1) * sh / dh2
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			sx := (2*uint64(dx) + 1) * sw / dw2
+			p := src.RGBA64At(sr.Min.X+int(sx), sr.Min.Y+int(sy))
+			pa1 := 0xffff - uint32(p.A)
+			pqa := (uint32(dst.Pix[d+6])<<8 | uint32(dst.Pix[d+7]))
+			pr0 := ((uint32(dst.Pix[d+0])<<8|uint32(dst.Pix[d+1]))*pqa/0xffff)*pa1/0xffff + uint32(p.R)
+			pg0 := ((uint32(dst.Pix[d+2])<<8|uint32(dst.Pix[d+3]))*pqa/0xffff)*pa1/0xffff + uint32(p.G)
+			pb0 := ((uint32(dst.Pix[d+4])<<8|uint32(dst.Pix[d+5]))*pqa/0xffff)*pa1/0xffff + uint32(p.B)
+			pa0 := pqa*pa1/0xffff + uint32(p.A)
+			if pa0 != 0 && pa0 != 0xffff {
+				pr0 = pr0 * 0xffff / pa0
+				pg0 = pg0 * 0xffff / pa0
+				pb0 = pb0 * 0xffff / pa0
+			}
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
 		}
 	}
 }
 
-func (nnInterpolator) transform_RGBA64Image_RGBA64Image_Over(dst RGBA64Image, dr, adr image.Rectangle, d2s *f64.Aff3, src image.RGBA64Image, sr image.Rectangle, bias image.Point, opts *Options) {
+func (nnInterpolator) scale_NRGBA64_RGBA64Image_Src(dst *image.NRGBA64, dr, adr image.Rectangle, src image.RGBA64Image, sr image.Rectangle, opts *Options) {
+	dw2 := uint64(dr.Dx()) * 2
+	dh2 := uint64(dr.Dy()) * 2
+	sw := uint64(sr.Dx())
+	sh := uint64(sr.Dy())
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := (2*uint64(dy) + 1) * sh / dh2
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			sx := (2*uint64(dx) + 1) * sw / dw2
+			p := src.RGBA64At(sr.Min.X+int(sx), sr.Min.Y+int(sy))
+			pr0 := uint32(p.R)
+			pg0 := uint32(p.G)
+			pb0 := uint32(p.B)
+			pa0 := uint32(p.A)
+			if pa0 != 0 && pa0 != 0xffff {
+				pr0 = pr0 * 0xffff / pa0
+				pg0 = pg0 * 0xffff / pa0
+				pb0 = pb0 * 0xffff / pa0
+			}
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
+
+func (nnInterpolator) scale_NRGBA64_Image_Over(dst *image.NRGBA64, dr, adr image.Rectangle, src image.Image, sr image.Rectangle, opts *Options) {
+	dw2 := uint64(dr.Dx()) * 2
+	dh2 := uint64(dr.Dy()) * 2
+	sw := uint64(sr.Dx())
+	sh := uint64(sr.Dy())
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := (2*uint64(dy) + 1) * sh / dh2
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			sx := (2*uint64(dx) + 1) * sw / dw2
+			pr, pg, pb, pa := src.At(sr.Min.X+int(sx), sr.Min.Y+int(sy)).RGBA()
+			pa1 := 0xffff - pa
+			pqa := (uint32(dst.Pix[d+6])<<8 | uint32(dst.Pix[d+7]))
+			pr0 := ((uint32(dst.Pix[d+0])<<8|uint32(dst.Pix[d+1]))*pqa/0xffff)*pa1/0xffff + pr
+			pg0 := ((uint32(dst.Pix[d+2])<<8|uint32(dst.Pix[d+3]))*pqa/0xffff)*pa1/0xffff + pg
+			pb0 := ((uint32(dst.Pix[d+4])<<8|uint32(dst.Pix[d+5]))*pqa/0xffff)*pa1/0xffff + pb
+			pa0 := pqa*pa1/0xffff + pa
+			if pa0 != 0 && pa0 != 0xffff {
+				pr0 = pr0 * 0xffff / pa0
+				pg0 = pg0 * 0xffff / pa0
+				pb0 = pb0 * 0xffff / pa0
+			}
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
+
+func (nnInterpolator) scale_NRGBA64_Image_Src(dst *image.NRGBA64, dr, adr image.Rectangle, src image.Image, sr image.Rectangle, opts *Options) {
+	dw2 := uint64(dr.Dx()) * 2
+	dh2 := uint64(dr.Dy()) * 2
+	sw := uint64(sr.Dx())
+	sh := uint64(sr.Dy())
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := (2*uint64(dy) + 1) * sh / dh2
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			sx := (2*uint64(dx) + 1) * sw / dw2
+			pr, pg, pb, pa := src.At(sr.Min.X+int(sx), sr.Min.Y+int(sy)).RGBA()
+			pr0 := pr
+			pg0 := pg
+			pb0 := pb
+			pa0 := pa
+			if pa0 != 0 && pa0 != 0xffff {
+				pr0 = pr0 * 0xffff / pa0
+				pg0 = pg0 * 0xffff / pa0
+				pb0 = pb0 * 0xffff / pa0
+			}
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
+
+func (nnInterpolator) scale_RGBA64Image_RGBA64Image_Over(dst RGBA64Image, dr, adr image.Rectangle, src image.RGBA64Image, sr image.Rectangle, opts *Options) {
+	dw2 := uint64(dr.Dx()) * 2
+	dh2 := uint64(dr.Dy()) * 2
+	sw := uint64(sr.Dx())
+	sh := uint64(sr.Dy())
 	srcMask, smp := opts.SrcMask, opts.SrcMaskP
 	dstMask, dmp := opts.DstMask, opts.DstMaskP
 	dstColorRGBA64 := color.RGBA64{}
 
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
-		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		sy := (2*uint64(dy) + 1) * sh / dh2
 		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
-			dxf := float64(dr.Min.X+int(dx)) + 0.5
-			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
-			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
-			if !(image.Point{sx0, sy0}).In(sr) {
-				continue
-			}
-			p := src.RGBA64At(sx0, sy0)
+			sx := (2*uint64(dx) + 1) * sw / dw2
+			p := src.RGBA64At(sr.Min.X+int(sx), sr.Min.Y+int(sy))
 			if srcMask != nil {
-				_, _, _, ma := srcMask.At(smp.X+sx0, smp.Y+sy0).RGBA()
+				_, _, _, ma := srcMask.At(smp.X+sr.Min.X+int(sx), smp.Y+sr.Min.Y+int(sy)).RGBA()
 				p.R = uint16(uint32(p.R) * ma / 0xffff)
 				p.G = uint16(uint32(p.G) * ma / 0xffff)
 				p.B = uint16(uint32(p.B) * ma / 0xffff)
@@ -1185,28 +1958,27 @@ func (nnInterpolator) transform_RGBA64Image_RGBA64Image_Over(dst RGBA64Image, dr
 			dstColorRGBA64.G = uint16(uint32(q.G)*pa1/0xffff + uint32(p.G))
 			dstColorRGBA64.B = uint16(uint32(q.B)*pa1/0xffff + uint32(p.B))
 			dstColorRGBA64.A = uint16(uint32(q.A)*pa1/0xffff + uint32(p.A))
-			dst.Set(dr.Min.X+int(dx), dr.Min.Y+int(dy), dstColorRGBA64)
+			dst.SetRGBA64(dr.Min.X+int(dx), dr.Min.Y+int(dy), dstColorRGBA64)
 		}
 	}
 }
 
-func (nnInterpolator) transform_RGBA64Image_RGBA64Image_Src(dst RGBA64Image, dr, adr image.Rectangle, d2s *f64.Aff3, src image.RGBA64Image, sr image.Rectangle, bias image.Point, opts *Options) {
+func (nnInterpolator) scale_RGBA64Image_RGBA64Image_Src(dst RGBA64Image, dr, adr image.Rectangle, src image.RGBA64Image, sr image.Rectangle, opts *Options) {
+	dw2 := uint64(dr.Dx()) * 2
+	dh2 := uint64(dr.Dy()) * 2
+	sw := uint64(sr.Dx())
+	sh := uint64(sr.Dy())
 	srcMask, smp := opts.SrcMask, opts.SrcMaskP
 	dstMask, dmp := opts.DstMask, opts.DstMaskP
 	dstColorRGBA64 := color.RGBA64{}
 
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
-		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		sy := (2*uint64(dy) + 1) * sh / dh2
 		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
-			dxf := float64(dr.Min.X+int(dx)) + 0.5
-			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
-			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
-			if !(image.Point{sx0, sy0}).In(sr) {
-				continue
-			}
-			p := src.RGBA64At(sx0, sy0)
+			sx := (2*uint64(dx) + 1) * sw / dw2
+			p := src.RGBA64At(sr.Min.X+int(sx), sr.Min.Y+int(sy))
 			if srcMask != nil {
-				_, _, _, ma := srcMask.At(smp.X+sx0, smp.Y+sy0).RGBA()
+				_, _, _, ma := srcMask.At(smp.X+sr.Min.X+int(sx), smp.Y+sr.Min.Y+int(sy)).RGBA()
 				p.R = uint16(uint32(p.R) * ma / 0xffff)
 				p.G = uint16(uint32(p.G) * ma / 0xffff)
 				p.B = uint16(uint32(p.B) * ma / 0xffff)
@@ -1224,31 +1996,30 @@ func (nnInterpolator) transform_RGBA64Image_RGBA64Image_Src(dst RGBA64Image, dr,
 				dstColorRGBA64.G = uint16(uint32(q.G)*pa1/0xffff + uint32(p.G))
 				dstColorRGBA64.B = uint16(uint32(q.B)*pa1/0xffff + uint32(p.B))
 				dstColorRGBA64.A = uint16(uint32(q.A)*pa1/0xffff + uint32(p.A))
-				dst.Set(dr.Min.X+int(dx), dr.Min.Y+int(dy), dstColorRGBA64)
+				dst.SetRGBA64(dr.Min.X+int(dx), dr.Min.Y+int(dy), dstColorRGBA64)
 			} else {
-				dst.Set(dr.Min.X+int(dx), dr.Min.Y+int(dy), p)
+				dst.SetRGBA64(dr.Min.X+int(dx), dr.Min.Y+int(dy), p)
 			}
 		}
 	}
 }
 
-func (nnInterpolator) transform_Image_Image_Over(dst Image, dr, adr image.Rectangle, d2s *f64.Aff3, src image.Image, sr image.Rectangle, bias image.Point, opts *Options) {
+func (nnInterpolator) scale_Image_Image_Over(dst Image, dr, adr image.Rectangle, src image.Image, sr image.Rectangle, opts *Options) {
+	dw2 := uint64(dr.Dx()) * 2
+	dh2 := uint64(dr.Dy()) * 2
+	sw := uint64(sr.Dx())
+	sh := uint64(sr.Dy())
 	srcMask, smp := opts.SrcMask, opts.SrcMaskP
 	dstMask, dmp := opts.DstMask, opts.DstMaskP
 	dstColorRGBA64 := &color.RGBA64{}
 	dstColor := color.Color(dstColorRGBA64)
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
-		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		sy := (2*uint64(dy) + 1) * sh / dh2
 		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
-			dxf := float64(dr.Min.X+int(dx)) + 0.5
-			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
-			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
-			if !(image.Point{sx0, sy0}).In(sr) {
-				continue
-			}
-			pr, pg, pb, pa := src.At(sx0, sy0).RGBA()
+			sx := (2*uint64(dx) + 1) * sw / dw2
+			pr, pg, pb, pa := src.At(sr.Min.X+int(sx), sr.Min.Y+int(sy)).RGBA()
 			if srcMask != nil {
-				_, _, _, ma := srcMask.At(smp.X+sx0, smp.Y+sy0).RGBA()
+				_, _, _, ma := srcMask.At(smp.X+sr.Min.X+int(sx), smp.Y+sr.Min.Y+int(sy)).RGBA()
 				pr = pr * ma / 0xffff
 				pg = pg * ma / 0xffff
 				pb = pb * ma / 0xffff
@@ -1272,23 +2043,22 @@ func (nnInterpolator) transform_Image_Image_Over(dst Image, dr, adr image.Rectan
 	}
 }
 
-func (nnInterpolator) transform_Image_Image_Src(dst Image, dr, adr image.Rectangle, d2s *f64.Aff3, src image.Image, sr image.Rectangle, bias image.Point, opts *Options) {
+func (nnInterpolator) scale_Image_Image_Src(dst Image, dr, adr image.Rectangle, src image.Image, sr image.Rectangle, opts *Options) {
+	dw2 := uint64(dr.Dx()) * 2
+	dh2 := uint64(dr.Dy()) * 2
+	sw := uint64(sr.Dx())
+	sh := uint64(sr.Dy())
 	srcMask, smp := opts.SrcMask, opts.SrcMaskP
 	dstMask, dmp := opts.DstMask, opts.DstMaskP
 	dstColorRGBA64 := &color.RGBA64{}
 	dstColor := color.Color(dstColorRGBA64)
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
-		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		sy := (2*uint64(dy) + 1) * sh / dh2
 		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
-			dxf := float64(dr.Min.X+int(dx)) + 0.5
-			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
-			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
-			if !(image.Point{sx0, sy0}).In(sr) {
-				continue
-			}
-			pr, pg, pb, pa := src.At(sx0, sy0).RGBA()
+			sx := (2*uint64(dx) + 1) * sw / dw2
+			pr, pg, pb, pa := src.At(sr.Min.X+int(sx), sr.Min.Y+int(sy)).RGBA()
 			if srcMask != nil {
-				_, _, _, ma := srcMask.At(smp.X+sx0, smp.Y+sy0).RGBA()
+				_, _, _, ma := srcMask.At(smp.X+sr.Min.X+int(sx), smp.Y+sr.Min.Y+int(sy)).RGBA()
 				pr = pr * ma / 0xffff
 				pg = pg * ma / 0xffff
 				pb = pb * ma / 0xffff
@@ -1318,294 +2088,216 @@ func (nnInterpolator) transform_Image_Image_Src(dst Image, dr, adr image.Rectang
 	}
 }
 
-func (z ablInterpolator) Scale(dst Image, dr image.Rectangle, src image.Image, sr image.Rectangle, op Op, opts *Options) {
-	// Try to simplify a Scale to a Copy when DstMask is not specified.
-	// If DstMask is not nil, Copy will call Scale back with same dr and sr, and cause stack overflow.
-	if dr.Size() == sr.Size() && (opts == nil || opts.DstMask == nil) {
-		Copy(dst, dr.Min, src, sr, op, opts)
-		return
-	}
-
-	var o Options
-	if opts != nil {
-		o = *opts
+func (nnInterpolator) transform_Alpha_Alpha_Over(dst *image.Alpha, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.Alpha, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
+			}
+			pi := (sy0-src.Rect.Min.Y)*src.Stride + (sx0 - src.Rect.Min.X)
+			pa := uint32(src.Pix[pi+0]) * 0x101
+			pa1 := (0xffff - pa) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pa) >> 8)
+		}
 	}
+}
 
-	// adr is the affected destination pixels.
-	adr := dst.Bounds().Intersect(dr)
-	adr, o.DstMask = clipAffectedDestRect(adr, o.DstMask, o.DstMaskP)
-	if adr.Empty() || sr.Empty() {
-		return
+func (nnInterpolator) transform_Alpha_Alpha_Src(dst *image.Alpha, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.Alpha, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
+			}
+			pi := (sy0-src.Rect.Min.Y)*src.Stride + (sx0 - src.Rect.Min.X)
+			pa := uint32(src.Pix[pi+0]) * 0x101
+			dst.Pix[d+0] = uint8(pa >> 8)
+		}
 	}
-	// Make adr relative to dr.Min.
-	adr = adr.Sub(dr.Min)
-	if op == Over && o.SrcMask == nil && opaque(src) {
-		op = Src
+}
+
+func (nnInterpolator) transform_Alpha_RGBA64Image_Over(dst *image.Alpha, dr, adr image.Rectangle, d2s *f64.Aff3, src image.RGBA64Image, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
+			}
+			p := src.RGBA64At(sx0, sy0)
+			pa1 := (0xffff - uint32(p.A)) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + uint32(p.A)) >> 8)
+		}
 	}
+}
 
-	// sr is the source pixels. If it extends beyond the src bounds,
-	// we cannot use the type-specific fast paths, as they access
-	// the Pix fields directly without bounds checking.
-	//
-	// Similarly, the fast paths assume that the masks are nil.
-	if o.DstMask != nil || o.SrcMask != nil || !sr.In(src.Bounds()) {
-		switch op {
-		case Over:
-			z.scale_Image_Image_Over(dst, dr, adr, src, sr, &o)
-		case Src:
-			z.scale_Image_Image_Src(dst, dr, adr, src, sr, &o)
+func (nnInterpolator) transform_Alpha_RGBA64Image_Src(dst *image.Alpha, dr, adr image.Rectangle, d2s *f64.Aff3, src image.RGBA64Image, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
+			}
+			p := src.RGBA64At(sx0, sy0)
+			dst.Pix[d+0] = uint8(p.A >> 8)
 		}
-	} else if _, ok := src.(*image.Uniform); ok {
-		Draw(dst, dr, src, src.Bounds().Min, op)
-	} else {
-		switch op {
-		case Over:
-			switch dst := dst.(type) {
-			case *image.RGBA:
-				switch src := src.(type) {
-				case *image.NRGBA:
-					z.scale_RGBA_NRGBA_Over(dst, dr, adr, src, sr, &o)
-				case *image.RGBA:
-					z.scale_RGBA_RGBA_Over(dst, dr, adr, src, sr, &o)
-				case image.RGBA64Image:
-					z.scale_RGBA_RGBA64Image_Over(dst, dr, adr, src, sr, &o)
-				default:
-					z.scale_RGBA_Image_Over(dst, dr, adr, src, sr, &o)
-				}
-			case RGBA64Image:
-				switch src := src.(type) {
-				case image.RGBA64Image:
-					z.scale_RGBA64Image_RGBA64Image_Over(dst, dr, adr, src, sr, &o)
-				}
-			default:
-				switch src := src.(type) {
-				default:
-					z.scale_Image_Image_Over(dst, dr, adr, src, sr, &o)
-				}
+	}
+}
+
+func (nnInterpolator) transform_Alpha_Image_Over(dst *image.Alpha, dr, adr image.Rectangle, d2s *f64.Aff3, src image.Image, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
 			}
-		case Src:
-			switch dst := dst.(type) {
-			case *image.RGBA:
-				switch src := src.(type) {
-				case *image.Gray:
-					z.scale_RGBA_Gray_Src(dst, dr, adr, src, sr, &o)
-				case *image.NRGBA:
-					z.scale_RGBA_NRGBA_Src(dst, dr, adr, src, sr, &o)
-				case *image.RGBA:
-					z.scale_RGBA_RGBA_Src(dst, dr, adr, src, sr, &o)
-				case *image.YCbCr:
-					switch src.SubsampleRatio {
-					default:
-						z.scale_RGBA_Image_Src(dst, dr, adr, src, sr, &o)
-					case image.YCbCrSubsampleRatio444:
-						z.scale_RGBA_YCbCr444_Src(dst, dr, adr, src, sr, &o)
-					case image.YCbCrSubsampleRatio422:
-						z.scale_RGBA_YCbCr422_Src(dst, dr, adr, src, sr, &o)
-					case image.YCbCrSubsampleRatio420:
-						z.scale_RGBA_YCbCr420_Src(dst, dr, adr, src, sr, &o)
-					case image.YCbCrSubsampleRatio440:
-						z.scale_RGBA_YCbCr440_Src(dst, dr, adr, src, sr, &o)
-					}
-				case image.RGBA64Image:
-					z.scale_RGBA_RGBA64Image_Src(dst, dr, adr, src, sr, &o)
-				default:
-					z.scale_RGBA_Image_Src(dst, dr, adr, src, sr, &o)
-				}
-			case RGBA64Image:
-				switch src := src.(type) {
-				case image.RGBA64Image:
-					z.scale_RGBA64Image_RGBA64Image_Src(dst, dr, adr, src, sr, &o)
-				}
-			default:
-				switch src := src.(type) {
-				default:
-					z.scale_Image_Image_Src(dst, dr, adr, src, sr, &o)
-				}
+			_, _, _, pa := src.At(sx0, sy0).RGBA()
+			pa1 := (0xffff - pa) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pa) >> 8)
+		}
+	}
+}
+
+func (nnInterpolator) transform_Alpha_Image_Src(dst *image.Alpha, dr, adr image.Rectangle, d2s *f64.Aff3, src image.Image, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
 			}
+			_, _, _, pa := src.At(sx0, sy0).RGBA()
+			dst.Pix[d+0] = uint8(pa >> 8)
 		}
 	}
 }
 
-func (z ablInterpolator) Transform(dst Image, s2d f64.Aff3, src image.Image, sr image.Rectangle, op Op, opts *Options) {
-	// Try to simplify a Transform to a Copy.
-	if s2d[0] == 1 && s2d[1] == 0 && s2d[3] == 0 && s2d[4] == 1 {
-		dx := int(s2d[2])
-		dy := int(s2d[5])
-		if float64(dx) == s2d[2] && float64(dy) == s2d[5] {
-			Copy(dst, image.Point{X: sr.Min.X + dx, Y: sr.Min.X + dy}, src, sr, op, opts)
-			return
+func (nnInterpolator) transform_Gray_Gray_Src(dst *image.Gray, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.Gray, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
+			}
+			pi := (sy0-src.Rect.Min.Y)*src.Stride + (sx0 - src.Rect.Min.X)
+			pr := uint32(src.Pix[pi]) * 0x101
+			dst.Pix[d] = uint8(pr >> 8)
 		}
 	}
+}
 
-	var o Options
-	if opts != nil {
-		o = *opts
+func (nnInterpolator) transform_Gray_RGBA64Image_Over(dst *image.Gray, dr, adr image.Rectangle, d2s *f64.Aff3, src image.RGBA64Image, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
+			}
+			p := src.RGBA64At(sx0, sy0)
+			pa1 := 0xffff - uint32(p.A)
+			pq := uint32(dst.Pix[d]) * 0x101 * pa1 / 0xffff
+			dst.Pix[d] = uint8((19595*(pq+uint32(p.R)) + 38470*(pq+uint32(p.G)) + 7471*(pq+uint32(p.B)) + 1<<15) >> 24)
+		}
 	}
+}
 
-	dr := transformRect(&s2d, &sr)
-	// adr is the affected destination pixels.
-	adr := dst.Bounds().Intersect(dr)
-	adr, o.DstMask = clipAffectedDestRect(adr, o.DstMask, o.DstMaskP)
-	if adr.Empty() || sr.Empty() {
-		return
+func (nnInterpolator) transform_Gray_RGBA64Image_Src(dst *image.Gray, dr, adr image.Rectangle, d2s *f64.Aff3, src image.RGBA64Image, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
+			}
+			p := src.RGBA64At(sx0, sy0)
+			dst.Pix[d] = uint8((19595*uint32(p.R) + 38470*uint32(p.G) + 7471*uint32(p.B) + 1<<15) >> 24)
+		}
 	}
-	if op == Over && o.SrcMask == nil && opaque(src) {
-		op = Src
+}
+
+func (nnInterpolator) transform_Gray_Image_Over(dst *image.Gray, dr, adr image.Rectangle, d2s *f64.Aff3, src image.Image, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
+			}
+			pr, pg, pb, pa := src.At(sx0, sy0).RGBA()
+			pa1 := 0xffff - pa
+			pq := uint32(dst.Pix[d]) * 0x101 * pa1 / 0xffff
+			dst.Pix[d] = uint8((19595*(pq+pr) + 38470*(pq+pg) + 7471*(pq+pb) + 1<<15) >> 24)
+		}
 	}
+}
 
-	d2s := invert(&s2d)
-	// bias is a translation of the mapping from dst coordinates to src
-	// coordinates such that the latter temporarily have non-negative X
-	// and Y coordinates. This allows us to write int(f) instead of
-	// int(math.Floor(f)), since "round to zero" and "round down" are
-	// equivalent when f >= 0, but the former is much cheaper. The X--
-	// and Y-- are because the TransformLeaf methods have a "sx -= 0.5"
-	// adjustment.
-	bias := transformRect(&d2s, &adr).Min
-	bias.X--
-	bias.Y--
-	d2s[2] -= float64(bias.X)
-	d2s[5] -= float64(bias.Y)
-	// Make adr relative to dr.Min.
-	adr = adr.Sub(dr.Min)
-	// sr is the source pixels. If it extends beyond the src bounds,
-	// we cannot use the type-specific fast paths, as they access
-	// the Pix fields directly without bounds checking.
-	//
-	// Similarly, the fast paths assume that the masks are nil.
-	if o.DstMask != nil || o.SrcMask != nil || !sr.In(src.Bounds()) {
-		switch op {
-		case Over:
-			z.transform_Image_Image_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
-		case Src:
-			z.transform_Image_Image_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
-		}
-	} else if u, ok := src.(*image.Uniform); ok {
-		transform_Uniform(dst, dr, adr, &d2s, u, sr, bias, op)
-	} else {
-		switch op {
-		case Over:
-			switch dst := dst.(type) {
-			case *image.RGBA:
-				switch src := src.(type) {
-				case *image.NRGBA:
-					z.transform_RGBA_NRGBA_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
-				case *image.RGBA:
-					z.transform_RGBA_RGBA_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
-				case image.RGBA64Image:
-					z.transform_RGBA_RGBA64Image_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
-				default:
-					z.transform_RGBA_Image_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
-				}
-			case RGBA64Image:
-				switch src := src.(type) {
-				case image.RGBA64Image:
-					z.transform_RGBA64Image_RGBA64Image_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
-				}
-			default:
-				switch src := src.(type) {
-				default:
-					z.transform_Image_Image_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
-				}
-			}
-		case Src:
-			switch dst := dst.(type) {
-			case *image.RGBA:
-				switch src := src.(type) {
-				case *image.Gray:
-					z.transform_RGBA_Gray_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
-				case *image.NRGBA:
-					z.transform_RGBA_NRGBA_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
-				case *image.RGBA:
-					z.transform_RGBA_RGBA_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
-				case *image.YCbCr:
-					switch src.SubsampleRatio {
-					default:
-						z.transform_RGBA_Image_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
-					case image.YCbCrSubsampleRatio444:
-						z.transform_RGBA_YCbCr444_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
-					case image.YCbCrSubsampleRatio422:
-						z.transform_RGBA_YCbCr422_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
-					case image.YCbCrSubsampleRatio420:
-						z.transform_RGBA_YCbCr420_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
-					case image.YCbCrSubsampleRatio440:
-						z.transform_RGBA_YCbCr440_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
-					}
-				case image.RGBA64Image:
-					z.transform_RGBA_RGBA64Image_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
-				default:
-					z.transform_RGBA_Image_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
-				}
-			case RGBA64Image:
-				switch src := src.(type) {
-				case image.RGBA64Image:
-					z.transform_RGBA64Image_RGBA64Image_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
-				}
-			default:
-				switch src := src.(type) {
-				default:
-					z.transform_Image_Image_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
-				}
-			}
+func (nnInterpolator) transform_Gray_Image_Src(dst *image.Gray, dr, adr image.Rectangle, d2s *f64.Aff3, src image.Image, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
+			}
+			pr, pg, pb, _ := src.At(sx0, sy0).RGBA()
+			dst.Pix[d] = uint8((19595*pr + 38470*pg + 7471*pb + 1<<15) >> 24)
 		}
 	}
 }
 
-func (ablInterpolator) scale_RGBA_Gray_Src(dst *image.RGBA, dr, adr image.Rectangle, src *image.Gray, sr image.Rectangle, opts *Options) {
-	sw := int32(sr.Dx())
-	sh := int32(sr.Dy())
-	yscale := float64(sh) / float64(dr.Dy())
-	xscale := float64(sw) / float64(dr.Dx())
-	swMinus1, shMinus1 := sw-1, sh-1
-
+func (nnInterpolator) transform_RGBA_Gray_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.Gray, sr image.Rectangle, bias image.Point, opts *Options) {
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
-		sy := float64((float64(dy)+0.5)*yscale) - 0.5
-		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
-		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
-		// sx, below.
-		sy0 := int32(sy)
-		yFrac0 := sy - float64(sy0)
-		yFrac1 := 1 - yFrac0
-		sy1 := sy0 + 1
-		if sy < 0 {
-			sy0, sy1 = 0, 0
-			yFrac0, yFrac1 = 0, 1
-		} else if sy1 > shMinus1 {
-			sy0, sy1 = shMinus1, shMinus1
-			yFrac0, yFrac1 = 1, 0
-		}
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
 		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
-
 		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
-			sx := float64((float64(dx)+0.5)*xscale) - 0.5
-			sx0 := int32(sx)
-			xFrac0 := sx - float64(sx0)
-			xFrac1 := 1 - xFrac0
-			sx1 := sx0 + 1
-			if sx < 0 {
-				sx0, sx1 = 0, 0
-				xFrac0, xFrac1 = 0, 1
-			} else if sx1 > swMinus1 {
-				sx0, sx1 = swMinus1, swMinus1
-				xFrac0, xFrac1 = 1, 0
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
 			}
-
-			s00i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.Stride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
-			s00ru := uint32(src.Pix[s00i]) * 0x101
-			s00r := float64(s00ru)
-			s10i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.Stride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
-			s10ru := uint32(src.Pix[s10i]) * 0x101
-			s10r := float64(s10ru)
-			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
-			s01i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.Stride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
-			s01ru := uint32(src.Pix[s01i]) * 0x101
-			s01r := float64(s01ru)
-			s11i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.Stride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
-			s11ru := uint32(src.Pix[s11i]) * 0x101
-			s11r := float64(s11ru)
-			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
-			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
-			pr := uint32(s11r)
+			pi := (sy0-src.Rect.Min.Y)*src.Stride + (sx0 - src.Rect.Min.X)
+			pr := uint32(src.Pix[pi]) * 0x101
 			out := uint8(pr >> 8)
 			dst.Pix[d+0] = out
 			dst.Pix[d+1] = out
@@ -1615,97 +2307,22 @@ func (ablInterpolator) scale_RGBA_Gray_Src(dst *image.RGBA, dr, adr image.Rectan
 	}
 }
 
-func (ablInterpolator) scale_RGBA_NRGBA_Over(dst *image.RGBA, dr, adr image.Rectangle, src *image.NRGBA, sr image.Rectangle, opts *Options) {
-	sw := int32(sr.Dx())
-	sh := int32(sr.Dy())
-	yscale := float64(sh) / float64(dr.Dy())
-	xscale := float64(sw) / float64(dr.Dx())
-	swMinus1, shMinus1 := sw-1, sh-1
-
+func (nnInterpolator) transform_RGBA_NRGBA_Over(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NRGBA, sr image.Rectangle, bias image.Point, opts *Options) {
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
-		sy := float64((float64(dy)+0.5)*yscale) - 0.5
-		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
-		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
-		// sx, below.
-		sy0 := int32(sy)
-		yFrac0 := sy - float64(sy0)
-		yFrac1 := 1 - yFrac0
-		sy1 := sy0 + 1
-		if sy < 0 {
-			sy0, sy1 = 0, 0
-			yFrac0, yFrac1 = 0, 1
-		} else if sy1 > shMinus1 {
-			sy0, sy1 = shMinus1, shMinus1
-			yFrac0, yFrac1 = 1, 0
-		}
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
 		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
-
 		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
-			sx := float64((float64(dx)+0.5)*xscale) - 0.5
-			sx0 := int32(sx)
-			xFrac0 := sx - float64(sx0)
-			xFrac1 := 1 - xFrac0
-			sx1 := sx0 + 1
-			if sx < 0 {
-				sx0, sx1 = 0, 0
-				xFrac0, xFrac1 = 0, 1
-			} else if sx1 > swMinus1 {
-				sx0, sx1 = swMinus1, swMinus1
-				xFrac0, xFrac1 = 1, 0
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
 			}
-
-			s00i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx0)-src.Rect.Min.X)*4
-			s00au := uint32(src.Pix[s00i+3]) * 0x101
-			s00ru := uint32(src.Pix[s00i+0]) * s00au / 0xff
-			s00gu := uint32(src.Pix[s00i+1]) * s00au / 0xff
-			s00bu := uint32(src.Pix[s00i+2]) * s00au / 0xff
-			s00r := float64(s00ru)
-			s00g := float64(s00gu)
-			s00b := float64(s00bu)
-			s00a := float64(s00au)
-			s10i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx1)-src.Rect.Min.X)*4
-			s10au := uint32(src.Pix[s10i+3]) * 0x101
-			s10ru := uint32(src.Pix[s10i+0]) * s10au / 0xff
-			s10gu := uint32(src.Pix[s10i+1]) * s10au / 0xff
-			s10bu := uint32(src.Pix[s10i+2]) * s10au / 0xff
-			s10r := float64(s10ru)
-			s10g := float64(s10gu)
-			s10b := float64(s10bu)
-			s10a := float64(s10au)
-			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
-			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
-			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
-			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
-			s01i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx0)-src.Rect.Min.X)*4
-			s01au := uint32(src.Pix[s01i+3]) * 0x101
-			s01ru := uint32(src.Pix[s01i+0]) * s01au / 0xff
-			s01gu := uint32(src.Pix[s01i+1]) * s01au / 0xff
-			s01bu := uint32(src.Pix[s01i+2]) * s01au / 0xff
-			s01r := float64(s01ru)
-			s01g := float64(s01gu)
-			s01b := float64(s01bu)
-			s01a := float64(s01au)
-			s11i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx1)-src.Rect.Min.X)*4
-			s11au := uint32(src.Pix[s11i+3]) * 0x101
-			s11ru := uint32(src.Pix[s11i+0]) * s11au / 0xff
-			s11gu := uint32(src.Pix[s11i+1]) * s11au / 0xff
-			s11bu := uint32(src.Pix[s11i+2]) * s11au / 0xff
-			s11r := float64(s11ru)
-			s11g := float64(s11gu)
-			s11b := float64(s11bu)
-			s11a := float64(s11au)
-			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
-			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
-			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
-			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
-			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
-			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
-			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
-			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
-			pr := uint32(s11r)
-			pg := uint32(s11g)
-			pb := uint32(s11b)
-			pa := uint32(s11a)
+			pi := (sy0-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*4
+			pa := uint32(src.Pix[pi+3]) * 0x101
+			pr := uint32(src.Pix[pi+0]) * pa / 0xff
+			pg := uint32(src.Pix[pi+1]) * pa / 0xff
+			pb := uint32(src.Pix[pi+2]) * pa / 0xff
 			pa1 := (0xffff - pa) * 0x101
 			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pr) >> 8)
 			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + pg) >> 8)
@@ -1715,97 +2332,22 @@ func (ablInterpolator) scale_RGBA_NRGBA_Over(dst *image.RGBA, dr, adr image.Rect
 	}
 }
 
-func (ablInterpolator) scale_RGBA_NRGBA_Src(dst *image.RGBA, dr, adr image.Rectangle, src *image.NRGBA, sr image.Rectangle, opts *Options) {
-	sw := int32(sr.Dx())
-	sh := int32(sr.Dy())
-	yscale := float64(sh) / float64(dr.Dy())
-	xscale := float64(sw) / float64(dr.Dx())
-	swMinus1, shMinus1 := sw-1, sh-1
-
+func (nnInterpolator) transform_RGBA_NRGBA_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NRGBA, sr image.Rectangle, bias image.Point, opts *Options) {
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
-		sy := float64((float64(dy)+0.5)*yscale) - 0.5
-		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
-		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
-		// sx, below.
-		sy0 := int32(sy)
-		yFrac0 := sy - float64(sy0)
-		yFrac1 := 1 - yFrac0
-		sy1 := sy0 + 1
-		if sy < 0 {
-			sy0, sy1 = 0, 0
-			yFrac0, yFrac1 = 0, 1
-		} else if sy1 > shMinus1 {
-			sy0, sy1 = shMinus1, shMinus1
-			yFrac0, yFrac1 = 1, 0
-		}
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
 		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
-
 		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
-			sx := float64((float64(dx)+0.5)*xscale) - 0.5
-			sx0 := int32(sx)
-			xFrac0 := sx - float64(sx0)
-			xFrac1 := 1 - xFrac0
-			sx1 := sx0 + 1
-			if sx < 0 {
-				sx0, sx1 = 0, 0
-				xFrac0, xFrac1 = 0, 1
-			} else if sx1 > swMinus1 {
-				sx0, sx1 = swMinus1, swMinus1
-				xFrac0, xFrac1 = 1, 0
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
 			}
-
-			s00i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx0)-src.Rect.Min.X)*4
-			s00au := uint32(src.Pix[s00i+3]) * 0x101
-			s00ru := uint32(src.Pix[s00i+0]) * s00au / 0xff
-			s00gu := uint32(src.Pix[s00i+1]) * s00au / 0xff
-			s00bu := uint32(src.Pix[s00i+2]) * s00au / 0xff
-			s00r := float64(s00ru)
-			s00g := float64(s00gu)
-			s00b := float64(s00bu)
-			s00a := float64(s00au)
-			s10i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx1)-src.Rect.Min.X)*4
-			s10au := uint32(src.Pix[s10i+3]) * 0x101
-			s10ru := uint32(src.Pix[s10i+0]) * s10au / 0xff
-			s10gu := uint32(src.Pix[s10i+1]) * s10au / 0xff
-			s10bu := uint32(src.Pix[s10i+2]) * s10au / 0xff
-			s10r := float64(s10ru)
-			s10g := float64(s10gu)
-			s10b := float64(s10bu)
-			s10a := float64(s10au)
-			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
-			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
-			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
-			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
-			s01i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx0)-src.Rect.Min.X)*4
-			s01au := uint32(src.Pix[s01i+3]) * 0x101
-			s01ru := uint32(src.Pix[s01i+0]) * s01au / 0xff
-			s01gu := uint32(src.Pix[s01i+1]) * s01au / 0xff
-			s01bu := uint32(src.Pix[s01i+2]) * s01au / 0xff
-			s01r := float64(s01ru)
-			s01g := float64(s01gu)
-			s01b := float64(s01bu)
-			s01a := float64(s01au)
-			s11i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx1)-src.Rect.Min.X)*4
-			s11au := uint32(src.Pix[s11i+3]) * 0x101
-			s11ru := uint32(src.Pix[s11i+0]) * s11au / 0xff
-			s11gu := uint32(src.Pix[s11i+1]) * s11au / 0xff
-			s11bu := uint32(src.Pix[s11i+2]) * s11au / 0xff
-			s11r := float64(s11ru)
-			s11g := float64(s11gu)
-			s11b := float64(s11bu)
-			s11a := float64(s11au)
-			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
-			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
-			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
-			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
-			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
-			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
-			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
-			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
-			pr := uint32(s11r)
-			pg := uint32(s11g)
-			pb := uint32(s11b)
-			pa := uint32(s11a)
+			pi := (sy0-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*4
+			pa := uint32(src.Pix[pi+3]) * 0x101
+			pr := uint32(src.Pix[pi+0]) * pa / 0xff
+			pg := uint32(src.Pix[pi+1]) * pa / 0xff
+			pb := uint32(src.Pix[pi+2]) * pa / 0xff
 			dst.Pix[d+0] = uint8(pr >> 8)
 			dst.Pix[d+1] = uint8(pg >> 8)
 			dst.Pix[d+2] = uint8(pb >> 8)
@@ -1814,97 +2356,22 @@ func (ablInterpolator) scale_RGBA_NRGBA_Src(dst *image.RGBA, dr, adr image.Recta
 	}
 }
 
-func (ablInterpolator) scale_RGBA_RGBA_Over(dst *image.RGBA, dr, adr image.Rectangle, src *image.RGBA, sr image.Rectangle, opts *Options) {
-	sw := int32(sr.Dx())
-	sh := int32(sr.Dy())
-	yscale := float64(sh) / float64(dr.Dy())
-	xscale := float64(sw) / float64(dr.Dx())
-	swMinus1, shMinus1 := sw-1, sh-1
-
+func (nnInterpolator) transform_RGBA_RGBA_Over(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.RGBA, sr image.Rectangle, bias image.Point, opts *Options) {
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
-		sy := float64((float64(dy)+0.5)*yscale) - 0.5
-		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
-		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
-		// sx, below.
-		sy0 := int32(sy)
-		yFrac0 := sy - float64(sy0)
-		yFrac1 := 1 - yFrac0
-		sy1 := sy0 + 1
-		if sy < 0 {
-			sy0, sy1 = 0, 0
-			yFrac0, yFrac1 = 0, 1
-		} else if sy1 > shMinus1 {
-			sy0, sy1 = shMinus1, shMinus1
-			yFrac0, yFrac1 = 1, 0
-		}
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
 		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
-
 		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
-			sx := float64((float64(dx)+0.5)*xscale) - 0.5
-			sx0 := int32(sx)
-			xFrac0 := sx - float64(sx0)
-			xFrac1 := 1 - xFrac0
-			sx1 := sx0 + 1
-			if sx < 0 {
-				sx0, sx1 = 0, 0
-				xFrac0, xFrac1 = 0, 1
-			} else if sx1 > swMinus1 {
-				sx0, sx1 = swMinus1, swMinus1
-				xFrac0, xFrac1 = 1, 0
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
 			}
-
-			s00i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx0)-src.Rect.Min.X)*4
-			s00ru := uint32(src.Pix[s00i+0]) * 0x101
-			s00gu := uint32(src.Pix[s00i+1]) * 0x101
-			s00bu := uint32(src.Pix[s00i+2]) * 0x101
-			s00au := uint32(src.Pix[s00i+3]) * 0x101
-			s00r := float64(s00ru)
-			s00g := float64(s00gu)
-			s00b := float64(s00bu)
-			s00a := float64(s00au)
-			s10i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx1)-src.Rect.Min.X)*4
-			s10ru := uint32(src.Pix[s10i+0]) * 0x101
-			s10gu := uint32(src.Pix[s10i+1]) * 0x101
-			s10bu := uint32(src.Pix[s10i+2]) * 0x101
-			s10au := uint32(src.Pix[s10i+3]) * 0x101
-			s10r := float64(s10ru)
-			s10g := float64(s10gu)
-			s10b := float64(s10bu)
-			s10a := float64(s10au)
-			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
-			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
-			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
-			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
-			s01i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx0)-src.Rect.Min.X)*4
-			s01ru := uint32(src.Pix[s01i+0]) * 0x101
-			s01gu := uint32(src.Pix[s01i+1]) * 0x101
-			s01bu := uint32(src.Pix[s01i+2]) * 0x101
-			s01au := uint32(src.Pix[s01i+3]) * 0x101
-			s01r := float64(s01ru)
-			s01g := float64(s01gu)
-			s01b := float64(s01bu)
-			s01a := float64(s01au)
-			s11i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx1)-src.Rect.Min.X)*4
-			s11ru := uint32(src.Pix[s11i+0]) * 0x101
-			s11gu := uint32(src.Pix[s11i+1]) * 0x101
-			s11bu := uint32(src.Pix[s11i+2]) * 0x101
-			s11au := uint32(src.Pix[s11i+3]) * 0x101
-			s11r := float64(s11ru)
-			s11g := float64(s11gu)
-			s11b := float64(s11bu)
-			s11a := float64(s11au)
-			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
-			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
-			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
-			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
-			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
-			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
-			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
-			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
-			pr := uint32(s11r)
-			pg := uint32(s11g)
-			pb := uint32(s11b)
-			pa := uint32(s11a)
+			pi := (sy0-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*4
+			pr := uint32(src.Pix[pi+0]) * 0x101
+			pg := uint32(src.Pix[pi+1]) * 0x101
+			pb := uint32(src.Pix[pi+2]) * 0x101
+			pa := uint32(src.Pix[pi+3]) * 0x101
 			pa1 := (0xffff - pa) * 0x101
 			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pr) >> 8)
 			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + pg) >> 8)
@@ -1914,272 +2381,198 @@ func (ablInterpolator) scale_RGBA_RGBA_Over(dst *image.RGBA, dr, adr image.Recta
 	}
 }
 
-func (ablInterpolator) scale_RGBA_RGBA_Src(dst *image.RGBA, dr, adr image.Rectangle, src *image.RGBA, sr image.Rectangle, opts *Options) {
-	sw := int32(sr.Dx())
-	sh := int32(sr.Dy())
-	yscale := float64(sh) / float64(dr.Dy())
-	xscale := float64(sw) / float64(dr.Dx())
-	swMinus1, shMinus1 := sw-1, sh-1
-
+func (nnInterpolator) transform_RGBA_RGBA_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.RGBA, sr image.Rectangle, bias image.Point, opts *Options) {
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
-		sy := float64((float64(dy)+0.5)*yscale) - 0.5
-		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
-		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
-		// sx, below.
-		sy0 := int32(sy)
-		yFrac0 := sy - float64(sy0)
-		yFrac1 := 1 - yFrac0
-		sy1 := sy0 + 1
-		if sy < 0 {
-			sy0, sy1 = 0, 0
-			yFrac0, yFrac1 = 0, 1
-		} else if sy1 > shMinus1 {
-			sy0, sy1 = shMinus1, shMinus1
-			yFrac0, yFrac1 = 1, 0
-		}
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
 		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
+			}
+			pi := (sy0-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*4
+			pr := uint32(src.Pix[pi+0]) * 0x101
+			pg := uint32(src.Pix[pi+1]) * 0x101
+			pb := uint32(src.Pix[pi+2]) * 0x101
+			pa := uint32(src.Pix[pi+3]) * 0x101
+			dst.Pix[d+0] = uint8(pr >> 8)
+			dst.Pix[d+1] = uint8(pg >> 8)
+			dst.Pix[d+2] = uint8(pb >> 8)
+			dst.Pix[d+3] = uint8(pa >> 8)
+		}
+	}
+}
 
+func (nnInterpolator) transform_RGBA_YCbCr444_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.YCbCr, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
 		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
-			sx := float64((float64(dx)+0.5)*xscale) - 0.5
-			sx0 := int32(sx)
-			xFrac0 := sx - float64(sx0)
-			xFrac1 := 1 - xFrac0
-			sx1 := sx0 + 1
-			if sx < 0 {
-				sx0, sx1 = 0, 0
-				xFrac0, xFrac1 = 0, 1
-			} else if sx1 > swMinus1 {
-				sx0, sx1 = swMinus1, swMinus1
-				xFrac0, xFrac1 = 1, 0
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
 			}
+			pi := (sy0-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
+			pj := (sy0-src.Rect.Min.Y)*src.CStride + (sx0 - src.Rect.Min.X)
 
-			s00i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx0)-src.Rect.Min.X)*4
-			s00ru := uint32(src.Pix[s00i+0]) * 0x101
-			s00gu := uint32(src.Pix[s00i+1]) * 0x101
-			s00bu := uint32(src.Pix[s00i+2]) * 0x101
-			s00au := uint32(src.Pix[s00i+3]) * 0x101
-			s00r := float64(s00ru)
-			s00g := float64(s00gu)
-			s00b := float64(s00bu)
-			s00a := float64(s00au)
-			s10i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx1)-src.Rect.Min.X)*4
-			s10ru := uint32(src.Pix[s10i+0]) * 0x101
-			s10gu := uint32(src.Pix[s10i+1]) * 0x101
-			s10bu := uint32(src.Pix[s10i+2]) * 0x101
-			s10au := uint32(src.Pix[s10i+3]) * 0x101
-			s10r := float64(s10ru)
-			s10g := float64(s10gu)
-			s10b := float64(s10bu)
-			s10a := float64(s10au)
-			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
-			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
-			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
-			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
-			s01i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx0)-src.Rect.Min.X)*4
-			s01ru := uint32(src.Pix[s01i+0]) * 0x101
-			s01gu := uint32(src.Pix[s01i+1]) * 0x101
-			s01bu := uint32(src.Pix[s01i+2]) * 0x101
-			s01au := uint32(src.Pix[s01i+3]) * 0x101
-			s01r := float64(s01ru)
-			s01g := float64(s01gu)
-			s01b := float64(s01bu)
-			s01a := float64(s01au)
-			s11i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx1)-src.Rect.Min.X)*4
-			s11ru := uint32(src.Pix[s11i+0]) * 0x101
-			s11gu := uint32(src.Pix[s11i+1]) * 0x101
-			s11bu := uint32(src.Pix[s11i+2]) * 0x101
-			s11au := uint32(src.Pix[s11i+3]) * 0x101
-			s11r := float64(s11ru)
-			s11g := float64(s11gu)
-			s11b := float64(s11bu)
-			s11a := float64(s11au)
-			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
-			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
-			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
-			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
-			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
-			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
-			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
-			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
-			pr := uint32(s11r)
-			pg := uint32(s11g)
-			pb := uint32(s11b)
-			pa := uint32(s11a)
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			pyy1 := int(src.Y[pi]) * 0x10101
+			pcb1 := int(src.Cb[pj]) - 128
+			pcr1 := int(src.Cr[pj]) - 128
+			pr := (pyy1 + 91881*pcr1) >> 8
+			pg := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
+			pb := (pyy1 + 116130*pcb1) >> 8
+			if pr < 0 {
+				pr = 0
+			} else if pr > 0xffff {
+				pr = 0xffff
+			}
+			if pg < 0 {
+				pg = 0
+			} else if pg > 0xffff {
+				pg = 0xffff
+			}
+			if pb < 0 {
+				pb = 0
+			} else if pb > 0xffff {
+				pb = 0xffff
+			}
 			dst.Pix[d+0] = uint8(pr >> 8)
 			dst.Pix[d+1] = uint8(pg >> 8)
 			dst.Pix[d+2] = uint8(pb >> 8)
-			dst.Pix[d+3] = uint8(pa >> 8)
+			dst.Pix[d+3] = 0xff
 		}
 	}
 }
 
-func (ablInterpolator) scale_RGBA_YCbCr444_Src(dst *image.RGBA, dr, adr image.Rectangle, src *image.YCbCr, sr image.Rectangle, opts *Options) {
-	sw := int32(sr.Dx())
-	sh := int32(sr.Dy())
-	yscale := float64(sh) / float64(dr.Dy())
-	xscale := float64(sw) / float64(dr.Dx())
-	swMinus1, shMinus1 := sw-1, sh-1
-
+func (nnInterpolator) transform_RGBA_YCbCr422_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.YCbCr, sr image.Rectangle, bias image.Point, opts *Options) {
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
-		sy := float64((float64(dy)+0.5)*yscale) - 0.5
-		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
-		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
-		// sx, below.
-		sy0 := int32(sy)
-		yFrac0 := sy - float64(sy0)
-		yFrac1 := 1 - yFrac0
-		sy1 := sy0 + 1
-		if sy < 0 {
-			sy0, sy1 = 0, 0
-			yFrac0, yFrac1 = 0, 1
-		} else if sy1 > shMinus1 {
-			sy0, sy1 = shMinus1, shMinus1
-			yFrac0, yFrac1 = 1, 0
-		}
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
 		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
-
 		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
-			sx := float64((float64(dx)+0.5)*xscale) - 0.5
-			sx0 := int32(sx)
-			xFrac0 := sx - float64(sx0)
-			xFrac1 := 1 - xFrac0
-			sx1 := sx0 + 1
-			if sx < 0 {
-				sx0, sx1 = 0, 0
-				xFrac0, xFrac1 = 0, 1
-			} else if sx1 > swMinus1 {
-				sx0, sx1 = swMinus1, swMinus1
-				xFrac0, xFrac1 = 1, 0
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
 			}
-
-			s00i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
-			s00j := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.CStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+			pi := (sy0-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
+			pj := (sy0-src.Rect.Min.Y)*src.CStride + ((sx0)/2 - src.Rect.Min.X/2)
 
 			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
-			s00yy1 := int(src.Y[s00i]) * 0x10101
-			s00cb1 := int(src.Cb[s00j]) - 128
-			s00cr1 := int(src.Cr[s00j]) - 128
-			s00ru := (s00yy1 + 91881*s00cr1) >> 8
-			s00gu := (s00yy1 - 22554*s00cb1 - 46802*s00cr1) >> 8
-			s00bu := (s00yy1 + 116130*s00cb1) >> 8
-			if s00ru < 0 {
-				s00ru = 0
-			} else if s00ru > 0xffff {
-				s00ru = 0xffff
+			pyy1 := int(src.Y[pi]) * 0x10101
+			pcb1 := int(src.Cb[pj]) - 128
+			pcr1 := int(src.Cr[pj]) - 128
+			pr := (pyy1 + 91881*pcr1) >> 8
+			pg := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
+			pb := (pyy1 + 116130*pcb1) >> 8
+			if pr < 0 {
+				pr = 0
+			} else if pr > 0xffff {
+				pr = 0xffff
 			}
-			if s00gu < 0 {
-				s00gu = 0
-			} else if s00gu > 0xffff {
-				s00gu = 0xffff
+			if pg < 0 {
+				pg = 0
+			} else if pg > 0xffff {
+				pg = 0xffff
 			}
-			if s00bu < 0 {
-				s00bu = 0
-			} else if s00bu > 0xffff {
-				s00bu = 0xffff
+			if pb < 0 {
+				pb = 0
+			} else if pb > 0xffff {
+				pb = 0xffff
 			}
+			dst.Pix[d+0] = uint8(pr >> 8)
+			dst.Pix[d+1] = uint8(pg >> 8)
+			dst.Pix[d+2] = uint8(pb >> 8)
+			dst.Pix[d+3] = 0xff
+		}
+	}
+}
 
-			s00r := float64(s00ru)
-			s00g := float64(s00gu)
-			s00b := float64(s00bu)
-			s10i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
-			s10j := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.CStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+func (nnInterpolator) transform_RGBA_YCbCr420_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.YCbCr, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
+			}
+			pi := (sy0-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
+			pj := ((sy0)/2-src.Rect.Min.Y/2)*src.CStride + ((sx0)/2 - src.Rect.Min.X/2)
 
 			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
-			s10yy1 := int(src.Y[s10i]) * 0x10101
-			s10cb1 := int(src.Cb[s10j]) - 128
-			s10cr1 := int(src.Cr[s10j]) - 128
-			s10ru := (s10yy1 + 91881*s10cr1) >> 8
-			s10gu := (s10yy1 - 22554*s10cb1 - 46802*s10cr1) >> 8
-			s10bu := (s10yy1 + 116130*s10cb1) >> 8
-			if s10ru < 0 {
-				s10ru = 0
-			} else if s10ru > 0xffff {
-				s10ru = 0xffff
+			pyy1 := int(src.Y[pi]) * 0x10101
+			pcb1 := int(src.Cb[pj]) - 128
+			pcr1 := int(src.Cr[pj]) - 128
+			pr := (pyy1 + 91881*pcr1) >> 8
+			pg := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
+			pb := (pyy1 + 116130*pcb1) >> 8
+			if pr < 0 {
+				pr = 0
+			} else if pr > 0xffff {
+				pr = 0xffff
 			}
-			if s10gu < 0 {
-				s10gu = 0
-			} else if s10gu > 0xffff {
-				s10gu = 0xffff
+			if pg < 0 {
+				pg = 0
+			} else if pg > 0xffff {
+				pg = 0xffff
 			}
-			if s10bu < 0 {
-				s10bu = 0
-			} else if s10bu > 0xffff {
-				s10bu = 0xffff
+			if pb < 0 {
+				pb = 0
+			} else if pb > 0xffff {
+				pb = 0xffff
 			}
+			dst.Pix[d+0] = uint8(pr >> 8)
+			dst.Pix[d+1] = uint8(pg >> 8)
+			dst.Pix[d+2] = uint8(pb >> 8)
+			dst.Pix[d+3] = 0xff
+		}
+	}
+}
 
-			s10r := float64(s10ru)
-			s10g := float64(s10gu)
-			s10b := float64(s10bu)
-			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
-			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
-			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
-			s01i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
-			s01j := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.CStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+func (nnInterpolator) transform_RGBA_YCbCr440_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.YCbCr, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
+			}
+			pi := (sy0-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
+			pj := ((sy0)/2-src.Rect.Min.Y/2)*src.CStride + (sx0 - src.Rect.Min.X)
 
 			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
-			s01yy1 := int(src.Y[s01i]) * 0x10101
-			s01cb1 := int(src.Cb[s01j]) - 128
-			s01cr1 := int(src.Cr[s01j]) - 128
-			s01ru := (s01yy1 + 91881*s01cr1) >> 8
-			s01gu := (s01yy1 - 22554*s01cb1 - 46802*s01cr1) >> 8
-			s01bu := (s01yy1 + 116130*s01cb1) >> 8
-			if s01ru < 0 {
-				s01ru = 0
-			} else if s01ru > 0xffff {
-				s01ru = 0xffff
-			}
-			if s01gu < 0 {
-				s01gu = 0
-			} else if s01gu > 0xffff {
-				s01gu = 0xffff
+			pyy1 := int(src.Y[pi]) * 0x10101
+			pcb1 := int(src.Cb[pj]) - 128
+			pcr1 := int(src.Cr[pj]) - 128
+			pr := (pyy1 + 91881*pcr1) >> 8
+			pg := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
+			pb := (pyy1 + 116130*pcb1) >> 8
+			if pr < 0 {
+				pr = 0
+			} else if pr > 0xffff {
+				pr = 0xffff
 			}
-			if s01bu < 0 {
-				s01bu = 0
-			} else if s01bu > 0xffff {
-				s01bu = 0xffff
+			if pg < 0 {
+				pg = 0
+			} else if pg > 0xffff {
+				pg = 0xffff
 			}
-
-			s01r := float64(s01ru)
-			s01g := float64(s01gu)
-			s01b := float64(s01bu)
-			s11i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
-			s11j := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.CStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
-
-			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
-			s11yy1 := int(src.Y[s11i]) * 0x10101
-			s11cb1 := int(src.Cb[s11j]) - 128
-			s11cr1 := int(src.Cr[s11j]) - 128
-			s11ru := (s11yy1 + 91881*s11cr1) >> 8
-			s11gu := (s11yy1 - 22554*s11cb1 - 46802*s11cr1) >> 8
-			s11bu := (s11yy1 + 116130*s11cb1) >> 8
-			if s11ru < 0 {
-				s11ru = 0
-			} else if s11ru > 0xffff {
-				s11ru = 0xffff
-			}
-			if s11gu < 0 {
-				s11gu = 0
-			} else if s11gu > 0xffff {
-				s11gu = 0xffff
-			}
-			if s11bu < 0 {
-				s11bu = 0
-			} else if s11bu > 0xffff {
-				s11bu = 0xffff
+			if pb < 0 {
+				pb = 0
+			} else if pb > 0xffff {
+				pb = 0xffff
 			}
-
-			s11r := float64(s11ru)
-			s11g := float64(s11gu)
-			s11b := float64(s11bu)
-			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
-			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
-			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
-			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
-			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
-			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
-			pr := uint32(s11r)
-			pg := uint32(s11g)
-			pb := uint32(s11b)
 			dst.Pix[d+0] = uint8(pr >> 8)
 			dst.Pix[d+1] = uint8(pg >> 8)
 			dst.Pix[d+2] = uint8(pb >> 8)
@@ -2188,603 +2581,422 @@ func (ablInterpolator) scale_RGBA_YCbCr444_Src(dst *image.RGBA, dr, adr image.Re
 	}
 }
 
-func (ablInterpolator) scale_RGBA_YCbCr422_Src(dst *image.RGBA, dr, adr image.Rectangle, src *image.YCbCr, sr image.Rectangle, opts *Options) {
-	sw := int32(sr.Dx())
-	sh := int32(sr.Dy())
-	yscale := float64(sh) / float64(dr.Dy())
-	xscale := float64(sw) / float64(dr.Dx())
-	swMinus1, shMinus1 := sw-1, sh-1
-
+func (nnInterpolator) transform_RGBA_NYCbCrA444_Over(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NYCbCrA, sr image.Rectangle, bias image.Point, opts *Options) {
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
-		sy := float64((float64(dy)+0.5)*yscale) - 0.5
-		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
-		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
-		// sx, below.
-		sy0 := int32(sy)
-		yFrac0 := sy - float64(sy0)
-		yFrac1 := 1 - yFrac0
-		sy1 := sy0 + 1
-		if sy < 0 {
-			sy0, sy1 = 0, 0
-			yFrac0, yFrac1 = 0, 1
-		} else if sy1 > shMinus1 {
-			sy0, sy1 = shMinus1, shMinus1
-			yFrac0, yFrac1 = 1, 0
-		}
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
 		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
-
 		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
-			sx := float64((float64(dx)+0.5)*xscale) - 0.5
-			sx0 := int32(sx)
-			xFrac0 := sx - float64(sx0)
-			xFrac1 := 1 - xFrac0
-			sx1 := sx0 + 1
-			if sx < 0 {
-				sx0, sx1 = 0, 0
-				xFrac0, xFrac1 = 0, 1
-			} else if sx1 > swMinus1 {
-				sx0, sx1 = swMinus1, swMinus1
-				xFrac0, xFrac1 = 1, 0
-			}
-
-			s00i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
-			s00j := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.CStride + ((sr.Min.X+int(sx0))/2 - src.Rect.Min.X/2)
-
-			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
-			s00yy1 := int(src.Y[s00i]) * 0x10101
-			s00cb1 := int(src.Cb[s00j]) - 128
-			s00cr1 := int(src.Cr[s00j]) - 128
-			s00ru := (s00yy1 + 91881*s00cr1) >> 8
-			s00gu := (s00yy1 - 22554*s00cb1 - 46802*s00cr1) >> 8
-			s00bu := (s00yy1 + 116130*s00cb1) >> 8
-			if s00ru < 0 {
-				s00ru = 0
-			} else if s00ru > 0xffff {
-				s00ru = 0xffff
-			}
-			if s00gu < 0 {
-				s00gu = 0
-			} else if s00gu > 0xffff {
-				s00gu = 0xffff
-			}
-			if s00bu < 0 {
-				s00bu = 0
-			} else if s00bu > 0xffff {
-				s00bu = 0xffff
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
 			}
-
-			s00r := float64(s00ru)
-			s00g := float64(s00gu)
-			s00b := float64(s00bu)
-			s10i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
-			s10j := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.CStride + ((sr.Min.X+int(sx1))/2 - src.Rect.Min.X/2)
+			pi := (sy0-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
+			pj := (sy0-src.Rect.Min.Y)*src.CStride + (sx0 - src.Rect.Min.X)
+			pk := (sy0-src.Rect.Min.Y)*src.AStride + (sx0 - src.Rect.Min.X)
 
 			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
-			s10yy1 := int(src.Y[s10i]) * 0x10101
-			s10cb1 := int(src.Cb[s10j]) - 128
-			s10cr1 := int(src.Cr[s10j]) - 128
-			s10ru := (s10yy1 + 91881*s10cr1) >> 8
-			s10gu := (s10yy1 - 22554*s10cb1 - 46802*s10cr1) >> 8
-			s10bu := (s10yy1 + 116130*s10cb1) >> 8
-			if s10ru < 0 {
-				s10ru = 0
-			} else if s10ru > 0xffff {
-				s10ru = 0xffff
-			}
-			if s10gu < 0 {
-				s10gu = 0
-			} else if s10gu > 0xffff {
-				s10gu = 0xffff
-			}
-			if s10bu < 0 {
-				s10bu = 0
-			} else if s10bu > 0xffff {
-				s10bu = 0xffff
-			}
-
-			s10r := float64(s10ru)
-			s10g := float64(s10gu)
-			s10b := float64(s10bu)
-			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
-			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
-			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
-			s01i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
-			s01j := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.CStride + ((sr.Min.X+int(sx0))/2 - src.Rect.Min.X/2)
+			pyy1 := int(src.Y[pi]) * 0x10101
+			pcb1 := int(src.Cb[pj]) - 128
+			pcr1 := int(src.Cr[pj]) - 128
+			pr0 := (pyy1 + 91881*pcr1) >> 8
+			pg0 := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
+			pb0 := (pyy1 + 116130*pcb1) >> 8
+			if pr0 < 0 {
+				pr0 = 0
+			} else if pr0 > 0xffff {
+				pr0 = 0xffff
+			}
+			if pg0 < 0 {
+				pg0 = 0
+			} else if pg0 > 0xffff {
+				pg0 = 0xffff
+			}
+			if pb0 < 0 {
+				pb0 = 0
+			} else if pb0 > 0xffff {
+				pb0 = 0xffff
+			}
+
+			pa := uint32(src.A[pk]) * 0x101
+			pr := uint32(pr0) * pa / 0xffff
+			pg := uint32(pg0) * pa / 0xffff
+			pb := uint32(pb0) * pa / 0xffff
+			pa1 := (0xffff - pa) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pr) >> 8)
+			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + pg) >> 8)
+			dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*pa1/0xffff + pb) >> 8)
+			dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*pa1/0xffff + pa) >> 8)
+		}
+	}
+}
 
-			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
-			s01yy1 := int(src.Y[s01i]) * 0x10101
-			s01cb1 := int(src.Cb[s01j]) - 128
-			s01cr1 := int(src.Cr[s01j]) - 128
-			s01ru := (s01yy1 + 91881*s01cr1) >> 8
-			s01gu := (s01yy1 - 22554*s01cb1 - 46802*s01cr1) >> 8
-			s01bu := (s01yy1 + 116130*s01cb1) >> 8
-			if s01ru < 0 {
-				s01ru = 0
-			} else if s01ru > 0xffff {
-				s01ru = 0xffff
-			}
-			if s01gu < 0 {
-				s01gu = 0
-			} else if s01gu > 0xffff {
-				s01gu = 0xffff
-			}
-			if s01bu < 0 {
-				s01bu = 0
-			} else if s01bu > 0xffff {
-				s01bu = 0xffff
+func (nnInterpolator) transform_RGBA_NYCbCrA422_Over(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NYCbCrA, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
 			}
-
-			s01r := float64(s01ru)
-			s01g := float64(s01gu)
-			s01b := float64(s01bu)
-			s11i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
-			s11j := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.CStride + ((sr.Min.X+int(sx1))/2 - src.Rect.Min.X/2)
+			pi := (sy0-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
+			pj := (sy0-src.Rect.Min.Y)*src.CStride + ((sx0)/2 - src.Rect.Min.X/2)
+			pk := (sy0-src.Rect.Min.Y)*src.AStride + (sx0 - src.Rect.Min.X)
 
 			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
-			s11yy1 := int(src.Y[s11i]) * 0x10101
-			s11cb1 := int(src.Cb[s11j]) - 128
-			s11cr1 := int(src.Cr[s11j]) - 128
-			s11ru := (s11yy1 + 91881*s11cr1) >> 8
-			s11gu := (s11yy1 - 22554*s11cb1 - 46802*s11cr1) >> 8
-			s11bu := (s11yy1 + 116130*s11cb1) >> 8
-			if s11ru < 0 {
-				s11ru = 0
-			} else if s11ru > 0xffff {
-				s11ru = 0xffff
-			}
-			if s11gu < 0 {
-				s11gu = 0
-			} else if s11gu > 0xffff {
-				s11gu = 0xffff
-			}
-			if s11bu < 0 {
-				s11bu = 0
-			} else if s11bu > 0xffff {
-				s11bu = 0xffff
-			}
-
-			s11r := float64(s11ru)
-			s11g := float64(s11gu)
-			s11b := float64(s11bu)
-			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
-			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
-			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
-			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
-			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
-			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
-			pr := uint32(s11r)
-			pg := uint32(s11g)
-			pb := uint32(s11b)
-			dst.Pix[d+0] = uint8(pr >> 8)
-			dst.Pix[d+1] = uint8(pg >> 8)
-			dst.Pix[d+2] = uint8(pb >> 8)
-			dst.Pix[d+3] = 0xff
+			pyy1 := int(src.Y[pi]) * 0x10101
+			pcb1 := int(src.Cb[pj]) - 128
+			pcr1 := int(src.Cr[pj]) - 128
+			pr0 := (pyy1 + 91881*pcr1) >> 8
+			pg0 := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
+			pb0 := (pyy1 + 116130*pcb1) >> 8
+			if pr0 < 0 {
+				pr0 = 0
+			} else if pr0 > 0xffff {
+				pr0 = 0xffff
+			}
+			if pg0 < 0 {
+				pg0 = 0
+			} else if pg0 > 0xffff {
+				pg0 = 0xffff
+			}
+			if pb0 < 0 {
+				pb0 = 0
+			} else if pb0 > 0xffff {
+				pb0 = 0xffff
+			}
+
+			pa := uint32(src.A[pk]) * 0x101
+			pr := uint32(pr0) * pa / 0xffff
+			pg := uint32(pg0) * pa / 0xffff
+			pb := uint32(pb0) * pa / 0xffff
+			pa1 := (0xffff - pa) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pr) >> 8)
+			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + pg) >> 8)
+			dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*pa1/0xffff + pb) >> 8)
+			dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*pa1/0xffff + pa) >> 8)
 		}
 	}
 }
 
-func (ablInterpolator) scale_RGBA_YCbCr420_Src(dst *image.RGBA, dr, adr image.Rectangle, src *image.YCbCr, sr image.Rectangle, opts *Options) {
-	sw := int32(sr.Dx())
-	sh := int32(sr.Dy())
-	yscale := float64(sh) / float64(dr.Dy())
-	xscale := float64(sw) / float64(dr.Dx())
-	swMinus1, shMinus1 := sw-1, sh-1
-
+func (nnInterpolator) transform_RGBA_NYCbCrA420_Over(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NYCbCrA, sr image.Rectangle, bias image.Point, opts *Options) {
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
-		sy := float64((float64(dy)+0.5)*yscale) - 0.5
-		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
-		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
-		// sx, below.
-		sy0 := int32(sy)
-		yFrac0 := sy - float64(sy0)
-		yFrac1 := 1 - yFrac0
-		sy1 := sy0 + 1
-		if sy < 0 {
-			sy0, sy1 = 0, 0
-			yFrac0, yFrac1 = 0, 1
-		} else if sy1 > shMinus1 {
-			sy0, sy1 = shMinus1, shMinus1
-			yFrac0, yFrac1 = 1, 0
-		}
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
 		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
-
 		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
-			sx := float64((float64(dx)+0.5)*xscale) - 0.5
-			sx0 := int32(sx)
-			xFrac0 := sx - float64(sx0)
-			xFrac1 := 1 - xFrac0
-			sx1 := sx0 + 1
-			if sx < 0 {
-				sx0, sx1 = 0, 0
-				xFrac0, xFrac1 = 0, 1
-			} else if sx1 > swMinus1 {
-				sx0, sx1 = swMinus1, swMinus1
-				xFrac0, xFrac1 = 1, 0
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
 			}
-
-			s00i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
-			s00j := ((sr.Min.Y+int(sy0))/2-src.Rect.Min.Y/2)*src.CStride + ((sr.Min.X+int(sx0))/2 - src.Rect.Min.X/2)
+			pi := (sy0-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
+			pj := ((sy0)/2-src.Rect.Min.Y/2)*src.CStride + ((sx0)/2 - src.Rect.Min.X/2)
+			pk := (sy0-src.Rect.Min.Y)*src.AStride + (sx0 - src.Rect.Min.X)
 
 			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
-			s00yy1 := int(src.Y[s00i]) * 0x10101
-			s00cb1 := int(src.Cb[s00j]) - 128
-			s00cr1 := int(src.Cr[s00j]) - 128
-			s00ru := (s00yy1 + 91881*s00cr1) >> 8
-			s00gu := (s00yy1 - 22554*s00cb1 - 46802*s00cr1) >> 8
-			s00bu := (s00yy1 + 116130*s00cb1) >> 8
-			if s00ru < 0 {
-				s00ru = 0
-			} else if s00ru > 0xffff {
-				s00ru = 0xffff
-			}
-			if s00gu < 0 {
-				s00gu = 0
-			} else if s00gu > 0xffff {
-				s00gu = 0xffff
-			}
-			if s00bu < 0 {
-				s00bu = 0
-			} else if s00bu > 0xffff {
-				s00bu = 0xffff
-			}
-
-			s00r := float64(s00ru)
-			s00g := float64(s00gu)
-			s00b := float64(s00bu)
-			s10i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
-			s10j := ((sr.Min.Y+int(sy0))/2-src.Rect.Min.Y/2)*src.CStride + ((sr.Min.X+int(sx1))/2 - src.Rect.Min.X/2)
+			pyy1 := int(src.Y[pi]) * 0x10101
+			pcb1 := int(src.Cb[pj]) - 128
+			pcr1 := int(src.Cr[pj]) - 128
+			pr0 := (pyy1 + 91881*pcr1) >> 8
+			pg0 := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
+			pb0 := (pyy1 + 116130*pcb1) >> 8
+			if pr0 < 0 {
+				pr0 = 0
+			} else if pr0 > 0xffff {
+				pr0 = 0xffff
+			}
+			if pg0 < 0 {
+				pg0 = 0
+			} else if pg0 > 0xffff {
+				pg0 = 0xffff
+			}
+			if pb0 < 0 {
+				pb0 = 0
+			} else if pb0 > 0xffff {
+				pb0 = 0xffff
+			}
+
+			pa := uint32(src.A[pk]) * 0x101
+			pr := uint32(pr0) * pa / 0xffff
+			pg := uint32(pg0) * pa / 0xffff
+			pb := uint32(pb0) * pa / 0xffff
+			pa1 := (0xffff - pa) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pr) >> 8)
+			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + pg) >> 8)
+			dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*pa1/0xffff + pb) >> 8)
+			dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*pa1/0xffff + pa) >> 8)
+		}
+	}
+}
 
-			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
-			s10yy1 := int(src.Y[s10i]) * 0x10101
-			s10cb1 := int(src.Cb[s10j]) - 128
-			s10cr1 := int(src.Cr[s10j]) - 128
-			s10ru := (s10yy1 + 91881*s10cr1) >> 8
-			s10gu := (s10yy1 - 22554*s10cb1 - 46802*s10cr1) >> 8
-			s10bu := (s10yy1 + 116130*s10cb1) >> 8
-			if s10ru < 0 {
-				s10ru = 0
-			} else if s10ru > 0xffff {
-				s10ru = 0xffff
-			}
-			if s10gu < 0 {
-				s10gu = 0
-			} else if s10gu > 0xffff {
-				s10gu = 0xffff
-			}
-			if s10bu < 0 {
-				s10bu = 0
-			} else if s10bu > 0xffff {
-				s10bu = 0xffff
+func (nnInterpolator) transform_RGBA_NYCbCrA440_Over(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NYCbCrA, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
 			}
-
-			s10r := float64(s10ru)
-			s10g := float64(s10gu)
-			s10b := float64(s10bu)
-			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
-			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
-			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
-			s01i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
-			s01j := ((sr.Min.Y+int(sy1))/2-src.Rect.Min.Y/2)*src.CStride + ((sr.Min.X+int(sx0))/2 - src.Rect.Min.X/2)
+			pi := (sy0-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
+			pj := ((sy0)/2-src.Rect.Min.Y/2)*src.CStride + (sx0 - src.Rect.Min.X)
+			pk := (sy0-src.Rect.Min.Y)*src.AStride + (sx0 - src.Rect.Min.X)
 
 			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
-			s01yy1 := int(src.Y[s01i]) * 0x10101
-			s01cb1 := int(src.Cb[s01j]) - 128
-			s01cr1 := int(src.Cr[s01j]) - 128
-			s01ru := (s01yy1 + 91881*s01cr1) >> 8
-			s01gu := (s01yy1 - 22554*s01cb1 - 46802*s01cr1) >> 8
-			s01bu := (s01yy1 + 116130*s01cb1) >> 8
-			if s01ru < 0 {
-				s01ru = 0
-			} else if s01ru > 0xffff {
-				s01ru = 0xffff
-			}
-			if s01gu < 0 {
-				s01gu = 0
-			} else if s01gu > 0xffff {
-				s01gu = 0xffff
-			}
-			if s01bu < 0 {
-				s01bu = 0
-			} else if s01bu > 0xffff {
-				s01bu = 0xffff
-			}
-
-			s01r := float64(s01ru)
-			s01g := float64(s01gu)
-			s01b := float64(s01bu)
-			s11i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
-			s11j := ((sr.Min.Y+int(sy1))/2-src.Rect.Min.Y/2)*src.CStride + ((sr.Min.X+int(sx1))/2 - src.Rect.Min.X/2)
+			pyy1 := int(src.Y[pi]) * 0x10101
+			pcb1 := int(src.Cb[pj]) - 128
+			pcr1 := int(src.Cr[pj]) - 128
+			pr0 := (pyy1 + 91881*pcr1) >> 8
+			pg0 := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
+			pb0 := (pyy1 + 116130*pcb1) >> 8
+			if pr0 < 0 {
+				pr0 = 0
+			} else if pr0 > 0xffff {
+				pr0 = 0xffff
+			}
+			if pg0 < 0 {
+				pg0 = 0
+			} else if pg0 > 0xffff {
+				pg0 = 0xffff
+			}
+			if pb0 < 0 {
+				pb0 = 0
+			} else if pb0 > 0xffff {
+				pb0 = 0xffff
+			}
+
+			pa := uint32(src.A[pk]) * 0x101
+			pr := uint32(pr0) * pa / 0xffff
+			pg := uint32(pg0) * pa / 0xffff
+			pb := uint32(pb0) * pa / 0xffff
+			pa1 := (0xffff - pa) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pr) >> 8)
+			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + pg) >> 8)
+			dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*pa1/0xffff + pb) >> 8)
+			dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*pa1/0xffff + pa) >> 8)
+		}
+	}
+}
 
-			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
-			s11yy1 := int(src.Y[s11i]) * 0x10101
-			s11cb1 := int(src.Cb[s11j]) - 128
-			s11cr1 := int(src.Cr[s11j]) - 128
-			s11ru := (s11yy1 + 91881*s11cr1) >> 8
-			s11gu := (s11yy1 - 22554*s11cb1 - 46802*s11cr1) >> 8
-			s11bu := (s11yy1 + 116130*s11cb1) >> 8
-			if s11ru < 0 {
-				s11ru = 0
-			} else if s11ru > 0xffff {
-				s11ru = 0xffff
-			}
-			if s11gu < 0 {
-				s11gu = 0
-			} else if s11gu > 0xffff {
-				s11gu = 0xffff
-			}
-			if s11bu < 0 {
-				s11bu = 0
-			} else if s11bu > 0xffff {
-				s11bu = 0xffff
+func (nnInterpolator) transform_RGBA_NYCbCrA444_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NYCbCrA, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
 			}
+			pi := (sy0-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
+			pj := (sy0-src.Rect.Min.Y)*src.CStride + (sx0 - src.Rect.Min.X)
+			pk := (sy0-src.Rect.Min.Y)*src.AStride + (sx0 - src.Rect.Min.X)
 
-			s11r := float64(s11ru)
-			s11g := float64(s11gu)
-			s11b := float64(s11bu)
-			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
-			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
-			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
-			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
-			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
-			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
-			pr := uint32(s11r)
-			pg := uint32(s11g)
-			pb := uint32(s11b)
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			pyy1 := int(src.Y[pi]) * 0x10101
+			pcb1 := int(src.Cb[pj]) - 128
+			pcr1 := int(src.Cr[pj]) - 128
+			pr0 := (pyy1 + 91881*pcr1) >> 8
+			pg0 := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
+			pb0 := (pyy1 + 116130*pcb1) >> 8
+			if pr0 < 0 {
+				pr0 = 0
+			} else if pr0 > 0xffff {
+				pr0 = 0xffff
+			}
+			if pg0 < 0 {
+				pg0 = 0
+			} else if pg0 > 0xffff {
+				pg0 = 0xffff
+			}
+			if pb0 < 0 {
+				pb0 = 0
+			} else if pb0 > 0xffff {
+				pb0 = 0xffff
+			}
+
+			pa := uint32(src.A[pk]) * 0x101
+			pr := uint32(pr0) * pa / 0xffff
+			pg := uint32(pg0) * pa / 0xffff
+			pb := uint32(pb0) * pa / 0xffff
 			dst.Pix[d+0] = uint8(pr >> 8)
 			dst.Pix[d+1] = uint8(pg >> 8)
 			dst.Pix[d+2] = uint8(pb >> 8)
-			dst.Pix[d+3] = 0xff
+			dst.Pix[d+3] = uint8(pa >> 8)
 		}
 	}
 }
 
-func (ablInterpolator) scale_RGBA_YCbCr440_Src(dst *image.RGBA, dr, adr image.Rectangle, src *image.YCbCr, sr image.Rectangle, opts *Options) {
-	sw := int32(sr.Dx())
-	sh := int32(sr.Dy())
-	yscale := float64(sh) / float64(dr.Dy())
-	xscale := float64(sw) / float64(dr.Dx())
-	swMinus1, shMinus1 := sw-1, sh-1
-
+func (nnInterpolator) transform_RGBA_NYCbCrA422_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NYCbCrA, sr image.Rectangle, bias image.Point, opts *Options) {
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
-		sy := float64((float64(dy)+0.5)*yscale) - 0.5
-		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
-		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
-		// sx, below.
-		sy0 := int32(sy)
-		yFrac0 := sy - float64(sy0)
-		yFrac1 := 1 - yFrac0
-		sy1 := sy0 + 1
-		if sy < 0 {
-			sy0, sy1 = 0, 0
-			yFrac0, yFrac1 = 0, 1
-		} else if sy1 > shMinus1 {
-			sy0, sy1 = shMinus1, shMinus1
-			yFrac0, yFrac1 = 1, 0
-		}
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
 		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
-
 		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
-			sx := float64((float64(dx)+0.5)*xscale) - 0.5
-			sx0 := int32(sx)
-			xFrac0 := sx - float64(sx0)
-			xFrac1 := 1 - xFrac0
-			sx1 := sx0 + 1
-			if sx < 0 {
-				sx0, sx1 = 0, 0
-				xFrac0, xFrac1 = 0, 1
-			} else if sx1 > swMinus1 {
-				sx0, sx1 = swMinus1, swMinus1
-				xFrac0, xFrac1 = 1, 0
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
 			}
-
-			s00i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
-			s00j := ((sr.Min.Y+int(sy0))/2-src.Rect.Min.Y/2)*src.CStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+			pi := (sy0-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
+			pj := (sy0-src.Rect.Min.Y)*src.CStride + ((sx0)/2 - src.Rect.Min.X/2)
+			pk := (sy0-src.Rect.Min.Y)*src.AStride + (sx0 - src.Rect.Min.X)
 
 			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
-			s00yy1 := int(src.Y[s00i]) * 0x10101
-			s00cb1 := int(src.Cb[s00j]) - 128
-			s00cr1 := int(src.Cr[s00j]) - 128
-			s00ru := (s00yy1 + 91881*s00cr1) >> 8
-			s00gu := (s00yy1 - 22554*s00cb1 - 46802*s00cr1) >> 8
-			s00bu := (s00yy1 + 116130*s00cb1) >> 8
-			if s00ru < 0 {
-				s00ru = 0
-			} else if s00ru > 0xffff {
-				s00ru = 0xffff
-			}
-			if s00gu < 0 {
-				s00gu = 0
-			} else if s00gu > 0xffff {
-				s00gu = 0xffff
-			}
-			if s00bu < 0 {
-				s00bu = 0
-			} else if s00bu > 0xffff {
-				s00bu = 0xffff
-			}
-
-			s00r := float64(s00ru)
-			s00g := float64(s00gu)
-			s00b := float64(s00bu)
-			s10i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
-			s10j := ((sr.Min.Y+int(sy0))/2-src.Rect.Min.Y/2)*src.CStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+			pyy1 := int(src.Y[pi]) * 0x10101
+			pcb1 := int(src.Cb[pj]) - 128
+			pcr1 := int(src.Cr[pj]) - 128
+			pr0 := (pyy1 + 91881*pcr1) >> 8
+			pg0 := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
+			pb0 := (pyy1 + 116130*pcb1) >> 8
+			if pr0 < 0 {
+				pr0 = 0
+			} else if pr0 > 0xffff {
+				pr0 = 0xffff
+			}
+			if pg0 < 0 {
+				pg0 = 0
+			} else if pg0 > 0xffff {
+				pg0 = 0xffff
+			}
+			if pb0 < 0 {
+				pb0 = 0
+			} else if pb0 > 0xffff {
+				pb0 = 0xffff
+			}
+
+			pa := uint32(src.A[pk]) * 0x101
+			pr := uint32(pr0) * pa / 0xffff
+			pg := uint32(pg0) * pa / 0xffff
+			pb := uint32(pb0) * pa / 0xffff
+			dst.Pix[d+0] = uint8(pr >> 8)
+			dst.Pix[d+1] = uint8(pg >> 8)
+			dst.Pix[d+2] = uint8(pb >> 8)
+			dst.Pix[d+3] = uint8(pa >> 8)
+		}
+	}
+}
 
-			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
-			s10yy1 := int(src.Y[s10i]) * 0x10101
-			s10cb1 := int(src.Cb[s10j]) - 128
-			s10cr1 := int(src.Cr[s10j]) - 128
-			s10ru := (s10yy1 + 91881*s10cr1) >> 8
-			s10gu := (s10yy1 - 22554*s10cb1 - 46802*s10cr1) >> 8
-			s10bu := (s10yy1 + 116130*s10cb1) >> 8
-			if s10ru < 0 {
-				s10ru = 0
-			} else if s10ru > 0xffff {
-				s10ru = 0xffff
-			}
-			if s10gu < 0 {
-				s10gu = 0
-			} else if s10gu > 0xffff {
-				s10gu = 0xffff
-			}
-			if s10bu < 0 {
-				s10bu = 0
-			} else if s10bu > 0xffff {
-				s10bu = 0xffff
+func (nnInterpolator) transform_RGBA_NYCbCrA420_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NYCbCrA, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
 			}
-
-			s10r := float64(s10ru)
-			s10g := float64(s10gu)
-			s10b := float64(s10bu)
-			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
-			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
-			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
-			s01i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
-			s01j := ((sr.Min.Y+int(sy1))/2-src.Rect.Min.Y/2)*src.CStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+			pi := (sy0-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
+			pj := ((sy0)/2-src.Rect.Min.Y/2)*src.CStride + ((sx0)/2 - src.Rect.Min.X/2)
+			pk := (sy0-src.Rect.Min.Y)*src.AStride + (sx0 - src.Rect.Min.X)
 
 			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
-			s01yy1 := int(src.Y[s01i]) * 0x10101
-			s01cb1 := int(src.Cb[s01j]) - 128
-			s01cr1 := int(src.Cr[s01j]) - 128
-			s01ru := (s01yy1 + 91881*s01cr1) >> 8
-			s01gu := (s01yy1 - 22554*s01cb1 - 46802*s01cr1) >> 8
-			s01bu := (s01yy1 + 116130*s01cb1) >> 8
-			if s01ru < 0 {
-				s01ru = 0
-			} else if s01ru > 0xffff {
-				s01ru = 0xffff
-			}
-			if s01gu < 0 {
-				s01gu = 0
-			} else if s01gu > 0xffff {
-				s01gu = 0xffff
-			}
-			if s01bu < 0 {
-				s01bu = 0
-			} else if s01bu > 0xffff {
-				s01bu = 0xffff
-			}
-
-			s01r := float64(s01ru)
-			s01g := float64(s01gu)
-			s01b := float64(s01bu)
-			s11i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
-			s11j := ((sr.Min.Y+int(sy1))/2-src.Rect.Min.Y/2)*src.CStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+			pyy1 := int(src.Y[pi]) * 0x10101
+			pcb1 := int(src.Cb[pj]) - 128
+			pcr1 := int(src.Cr[pj]) - 128
+			pr0 := (pyy1 + 91881*pcr1) >> 8
+			pg0 := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
+			pb0 := (pyy1 + 116130*pcb1) >> 8
+			if pr0 < 0 {
+				pr0 = 0
+			} else if pr0 > 0xffff {
+				pr0 = 0xffff
+			}
+			if pg0 < 0 {
+				pg0 = 0
+			} else if pg0 > 0xffff {
+				pg0 = 0xffff
+			}
+			if pb0 < 0 {
+				pb0 = 0
+			} else if pb0 > 0xffff {
+				pb0 = 0xffff
+			}
+
+			pa := uint32(src.A[pk]) * 0x101
+			pr := uint32(pr0) * pa / 0xffff
+			pg := uint32(pg0) * pa / 0xffff
+			pb := uint32(pb0) * pa / 0xffff
+			dst.Pix[d+0] = uint8(pr >> 8)
+			dst.Pix[d+1] = uint8(pg >> 8)
+			dst.Pix[d+2] = uint8(pb >> 8)
+			dst.Pix[d+3] = uint8(pa >> 8)
+		}
+	}
+}
 
-			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
-			s11yy1 := int(src.Y[s11i]) * 0x10101
-			s11cb1 := int(src.Cb[s11j]) - 128
-			s11cr1 := int(src.Cr[s11j]) - 128
-			s11ru := (s11yy1 + 91881*s11cr1) >> 8
-			s11gu := (s11yy1 - 22554*s11cb1 - 46802*s11cr1) >> 8
-			s11bu := (s11yy1 + 116130*s11cb1) >> 8
-			if s11ru < 0 {
-				s11ru = 0
-			} else if s11ru > 0xffff {
-				s11ru = 0xffff
-			}
-			if s11gu < 0 {
-				s11gu = 0
-			} else if s11gu > 0xffff {
-				s11gu = 0xffff
-			}
-			if s11bu < 0 {
-				s11bu = 0
-			} else if s11bu > 0xffff {
-				s11bu = 0xffff
+func (nnInterpolator) transform_RGBA_NYCbCrA440_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NYCbCrA, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
 			}
+			pi := (sy0-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
+			pj := ((sy0)/2-src.Rect.Min.Y/2)*src.CStride + (sx0 - src.Rect.Min.X)
+			pk := (sy0-src.Rect.Min.Y)*src.AStride + (sx0 - src.Rect.Min.X)
 
-			s11r := float64(s11ru)
-			s11g := float64(s11gu)
-			s11b := float64(s11bu)
-			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
-			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
-			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
-			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
-			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
-			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
-			pr := uint32(s11r)
-			pg := uint32(s11g)
-			pb := uint32(s11b)
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			pyy1 := int(src.Y[pi]) * 0x10101
+			pcb1 := int(src.Cb[pj]) - 128
+			pcr1 := int(src.Cr[pj]) - 128
+			pr0 := (pyy1 + 91881*pcr1) >> 8
+			pg0 := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
+			pb0 := (pyy1 + 116130*pcb1) >> 8
+			if pr0 < 0 {
+				pr0 = 0
+			} else if pr0 > 0xffff {
+				pr0 = 0xffff
+			}
+			if pg0 < 0 {
+				pg0 = 0
+			} else if pg0 > 0xffff {
+				pg0 = 0xffff
+			}
+			if pb0 < 0 {
+				pb0 = 0
+			} else if pb0 > 0xffff {
+				pb0 = 0xffff
+			}
+
+			pa := uint32(src.A[pk]) * 0x101
+			pr := uint32(pr0) * pa / 0xffff
+			pg := uint32(pg0) * pa / 0xffff
+			pb := uint32(pb0) * pa / 0xffff
 			dst.Pix[d+0] = uint8(pr >> 8)
 			dst.Pix[d+1] = uint8(pg >> 8)
 			dst.Pix[d+2] = uint8(pb >> 8)
-			dst.Pix[d+3] = 0xff
+			dst.Pix[d+3] = uint8(pa >> 8)
 		}
 	}
 }
 
-func (ablInterpolator) scale_RGBA_RGBA64Image_Over(dst *image.RGBA, dr, adr image.Rectangle, src image.RGBA64Image, sr image.Rectangle, opts *Options) {
-	sw := int32(sr.Dx())
-	sh := int32(sr.Dy())
-	yscale := float64(sh) / float64(dr.Dy())
-	xscale := float64(sw) / float64(dr.Dx())
-	swMinus1, shMinus1 := sw-1, sh-1
-
+func (nnInterpolator) transform_RGBA_RGBA64Image_Over(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src image.RGBA64Image, sr image.Rectangle, bias image.Point, opts *Options) {
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
-		sy := float64((float64(dy)+0.5)*yscale) - 0.5
-		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
-		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
-		// sx, below.
-		sy0 := int32(sy)
-		yFrac0 := sy - float64(sy0)
-		yFrac1 := 1 - yFrac0
-		sy1 := sy0 + 1
-		if sy < 0 {
-			sy0, sy1 = 0, 0
-			yFrac0, yFrac1 = 0, 1
-		} else if sy1 > shMinus1 {
-			sy0, sy1 = shMinus1, shMinus1
-			yFrac0, yFrac1 = 1, 0
-		}
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
 		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
-
 		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
-			sx := float64((float64(dx)+0.5)*xscale) - 0.5
-			sx0 := int32(sx)
-			xFrac0 := sx - float64(sx0)
-			xFrac1 := 1 - xFrac0
-			sx1 := sx0 + 1
-			if sx < 0 {
-				sx0, sx1 = 0, 0
-				xFrac0, xFrac1 = 0, 1
-			} else if sx1 > swMinus1 {
-				sx0, sx1 = swMinus1, swMinus1
-				xFrac0, xFrac1 = 1, 0
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
 			}
-
-			s00u := src.RGBA64At(sr.Min.X+int(sx0), sr.Min.Y+int(sy0))
-			s00r := float64(s00u.R)
-			s00g := float64(s00u.G)
-			s00b := float64(s00u.B)
-			s00a := float64(s00u.A)
-			s10u := src.RGBA64At(sr.Min.X+int(sx1), sr.Min.Y+int(sy0))
-			s10r := float64(s10u.R)
-			s10g := float64(s10u.G)
-			s10b := float64(s10u.B)
-			s10a := float64(s10u.A)
-			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
-			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
-			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
-			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
-			s01u := src.RGBA64At(sr.Min.X+int(sx0), sr.Min.Y+int(sy1))
-			s01r := float64(s01u.R)
-			s01g := float64(s01u.G)
-			s01b := float64(s01u.B)
-			s01a := float64(s01u.A)
-			s11u := src.RGBA64At(sr.Min.X+int(sx1), sr.Min.Y+int(sy1))
-			s11r := float64(s11u.R)
-			s11g := float64(s11u.G)
-			s11b := float64(s11u.B)
-			s11a := float64(s11u.A)
-			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
-			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
-			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
-			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
-			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
-			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
-			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
-			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
-			p := color.RGBA64{uint16(s11r), uint16(s11g), uint16(s11b), uint16(s11a)}
+			p := src.RGBA64At(sx0, sy0)
 			pa1 := (0xffff - uint32(p.A)) * 0x101
 			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + uint32(p.R)) >> 8)
 			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + uint32(p.G)) >> 8)
@@ -2794,78 +3006,18 @@ func (ablInterpolator) scale_RGBA_RGBA64Image_Over(dst *image.RGBA, dr, adr imag
 	}
 }
 
-func (ablInterpolator) scale_RGBA_RGBA64Image_Src(dst *image.RGBA, dr, adr image.Rectangle, src image.RGBA64Image, sr image.Rectangle, opts *Options) {
-	sw := int32(sr.Dx())
-	sh := int32(sr.Dy())
-	yscale := float64(sh) / float64(dr.Dy())
-	xscale := float64(sw) / float64(dr.Dx())
-	swMinus1, shMinus1 := sw-1, sh-1
-
+func (nnInterpolator) transform_RGBA_RGBA64Image_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src image.RGBA64Image, sr image.Rectangle, bias image.Point, opts *Options) {
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
-		sy := float64((float64(dy)+0.5)*yscale) - 0.5
-		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
-		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
-		// sx, below.
-		sy0 := int32(sy)
-		yFrac0 := sy - float64(sy0)
-		yFrac1 := 1 - yFrac0
-		sy1 := sy0 + 1
-		if sy < 0 {
-			sy0, sy1 = 0, 0
-			yFrac0, yFrac1 = 0, 1
-		} else if sy1 > shMinus1 {
-			sy0, sy1 = shMinus1, shMinus1
-			yFrac0, yFrac1 = 1, 0
-		}
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
 		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
-
 		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
-			sx := float64((float64(dx)+0.5)*xscale) - 0.5
-			sx0 := int32(sx)
-			xFrac0 := sx - float64(sx0)
-			xFrac1 := 1 - xFrac0
-			sx1 := sx0 + 1
-			if sx < 0 {
-				sx0, sx1 = 0, 0
-				xFrac0, xFrac1 = 0, 1
-			} else if sx1 > swMinus1 {
-				sx0, sx1 = swMinus1, swMinus1
-				xFrac0, xFrac1 = 1, 0
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
 			}
-
-			s00u := src.RGBA64At(sr.Min.X+int(sx0), sr.Min.Y+int(sy0))
-			s00r := float64(s00u.R)
-			s00g := float64(s00u.G)
-			s00b := float64(s00u.B)
-			s00a := float64(s00u.A)
-			s10u := src.RGBA64At(sr.Min.X+int(sx1), sr.Min.Y+int(sy0))
-			s10r := float64(s10u.R)
-			s10g := float64(s10u.G)
-			s10b := float64(s10u.B)
-			s10a := float64(s10u.A)
-			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
-			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
-			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
-			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
-			s01u := src.RGBA64At(sr.Min.X+int(sx0), sr.Min.Y+int(sy1))
-			s01r := float64(s01u.R)
-			s01g := float64(s01u.G)
-			s01b := float64(s01u.B)
-			s01a := float64(s01u.A)
-			s11u := src.RGBA64At(sr.Min.X+int(sx1), sr.Min.Y+int(sy1))
-			s11r := float64(s11u.R)
-			s11g := float64(s11u.G)
-			s11b := float64(s11u.B)
-			s11a := float64(s11u.A)
-			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
-			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
-			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
-			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
-			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
-			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
-			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
-			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
-			p := color.RGBA64{uint16(s11r), uint16(s11g), uint16(s11b), uint16(s11a)}
+			p := src.RGBA64At(sx0, sy0)
 			dst.Pix[d+0] = uint8(p.R >> 8)
 			dst.Pix[d+1] = uint8(p.G >> 8)
 			dst.Pix[d+2] = uint8(p.B >> 8)
@@ -2874,81 +3026,18 @@ func (ablInterpolator) scale_RGBA_RGBA64Image_Src(dst *image.RGBA, dr, adr image
 	}
 }
 
-func (ablInterpolator) scale_RGBA_Image_Over(dst *image.RGBA, dr, adr image.Rectangle, src image.Image, sr image.Rectangle, opts *Options) {
-	sw := int32(sr.Dx())
-	sh := int32(sr.Dy())
-	yscale := float64(sh) / float64(dr.Dy())
-	xscale := float64(sw) / float64(dr.Dx())
-	swMinus1, shMinus1 := sw-1, sh-1
-
+func (nnInterpolator) transform_RGBA_Image_Over(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src image.Image, sr image.Rectangle, bias image.Point, opts *Options) {
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
-		sy := float64((float64(dy)+0.5)*yscale) - 0.5
-		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
-		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
-		// sx, below.
-		sy0 := int32(sy)
-		yFrac0 := sy - float64(sy0)
-		yFrac1 := 1 - yFrac0
-		sy1 := sy0 + 1
-		if sy < 0 {
-			sy0, sy1 = 0, 0
-			yFrac0, yFrac1 = 0, 1
-		} else if sy1 > shMinus1 {
-			sy0, sy1 = shMinus1, shMinus1
-			yFrac0, yFrac1 = 1, 0
-		}
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
 		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
-
 		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
-			sx := float64((float64(dx)+0.5)*xscale) - 0.5
-			sx0 := int32(sx)
-			xFrac0 := sx - float64(sx0)
-			xFrac1 := 1 - xFrac0
-			sx1 := sx0 + 1
-			if sx < 0 {
-				sx0, sx1 = 0, 0
-				xFrac0, xFrac1 = 0, 1
-			} else if sx1 > swMinus1 {
-				sx0, sx1 = swMinus1, swMinus1
-				xFrac0, xFrac1 = 1, 0
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
 			}
-
-			s00ru, s00gu, s00bu, s00au := src.At(sr.Min.X+int(sx0), sr.Min.Y+int(sy0)).RGBA()
-			s00r := float64(s00ru)
-			s00g := float64(s00gu)
-			s00b := float64(s00bu)
-			s00a := float64(s00au)
-			s10ru, s10gu, s10bu, s10au := src.At(sr.Min.X+int(sx1), sr.Min.Y+int(sy0)).RGBA()
-			s10r := float64(s10ru)
-			s10g := float64(s10gu)
-			s10b := float64(s10bu)
-			s10a := float64(s10au)
-			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
-			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
-			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
-			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
-			s01ru, s01gu, s01bu, s01au := src.At(sr.Min.X+int(sx0), sr.Min.Y+int(sy1)).RGBA()
-			s01r := float64(s01ru)
-			s01g := float64(s01gu)
-			s01b := float64(s01bu)
-			s01a := float64(s01au)
-			s11ru, s11gu, s11bu, s11au := src.At(sr.Min.X+int(sx1), sr.Min.Y+int(sy1)).RGBA()
-			s11r := float64(s11ru)
-			s11g := float64(s11gu)
-			s11b := float64(s11bu)
-			s11a := float64(s11au)
-			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
-			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
-			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
-			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
-			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
-			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
-			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
-			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
-			pr := uint32(s11r)
-			pg := uint32(s11g)
-			pb := uint32(s11b)
-			pa := uint32(s11a)
+			pr, pg, pb, pa := src.At(sx0, sy0).RGBA()
 			pa1 := (0xffff - pa) * 0x101
 			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pr) >> 8)
 			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + pg) >> 8)
@@ -2958,81 +3047,18 @@ func (ablInterpolator) scale_RGBA_Image_Over(dst *image.RGBA, dr, adr image.Rect
 	}
 }
 
-func (ablInterpolator) scale_RGBA_Image_Src(dst *image.RGBA, dr, adr image.Rectangle, src image.Image, sr image.Rectangle, opts *Options) {
-	sw := int32(sr.Dx())
-	sh := int32(sr.Dy())
-	yscale := float64(sh) / float64(dr.Dy())
-	xscale := float64(sw) / float64(dr.Dx())
-	swMinus1, shMinus1 := sw-1, sh-1
-
+func (nnInterpolator) transform_RGBA_Image_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src image.Image, sr image.Rectangle, bias image.Point, opts *Options) {
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
-		sy := float64((float64(dy)+0.5)*yscale) - 0.5
-		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
-		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
-		// sx, below.
-		sy0 := int32(sy)
-		yFrac0 := sy - float64(sy0)
-		yFrac1 := 1 - yFrac0
-		sy1 := sy0 + 1
-		if sy < 0 {
-			sy0, sy1 = 0, 0
-			yFrac0, yFrac1 = 0, 1
-		} else if sy1 > shMinus1 {
-			sy0, sy1 = shMinus1, shMinus1
-			yFrac0, yFrac1 = 1, 0
-		}
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
 		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
-
 		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
-			sx := float64((float64(dx)+0.5)*xscale) - 0.5
-			sx0 := int32(sx)
-			xFrac0 := sx - float64(sx0)
-			xFrac1 := 1 - xFrac0
-			sx1 := sx0 + 1
-			if sx < 0 {
-				sx0, sx1 = 0, 0
-				xFrac0, xFrac1 = 0, 1
-			} else if sx1 > swMinus1 {
-				sx0, sx1 = swMinus1, swMinus1
-				xFrac0, xFrac1 = 1, 0
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
 			}
-
-			s00ru, s00gu, s00bu, s00au := src.At(sr.Min.X+int(sx0), sr.Min.Y+int(sy0)).RGBA()
-			s00r := float64(s00ru)
-			s00g := float64(s00gu)
-			s00b := float64(s00bu)
-			s00a := float64(s00au)
-			s10ru, s10gu, s10bu, s10au := src.At(sr.Min.X+int(sx1), sr.Min.Y+int(sy0)).RGBA()
-			s10r := float64(s10ru)
-			s10g := float64(s10gu)
-			s10b := float64(s10bu)
-			s10a := float64(s10au)
-			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
-			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
-			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
-			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
-			s01ru, s01gu, s01bu, s01au := src.At(sr.Min.X+int(sx0), sr.Min.Y+int(sy1)).RGBA()
-			s01r := float64(s01ru)
-			s01g := float64(s01gu)
-			s01b := float64(s01bu)
-			s01a := float64(s01au)
-			s11ru, s11gu, s11bu, s11au := src.At(sr.Min.X+int(sx1), sr.Min.Y+int(sy1)).RGBA()
-			s11r := float64(s11ru)
-			s11g := float64(s11gu)
-			s11b := float64(s11bu)
-			s11a := float64(s11au)
-			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
-			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
-			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
-			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
-			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
-			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
-			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
-			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
-			pr := uint32(s11r)
-			pg := uint32(s11g)
-			pb := uint32(s11b)
-			pa := uint32(s11a)
+			pr, pg, pb, pa := src.At(sx0, sy0).RGBA()
 			dst.Pix[d+0] = uint8(pr >> 8)
 			dst.Pix[d+1] = uint8(pg >> 8)
 			dst.Pix[d+2] = uint8(pb >> 8)
@@ -3041,478 +3067,682 @@ func (ablInterpolator) scale_RGBA_Image_Src(dst *image.RGBA, dr, adr image.Recta
 	}
 }
 
-func (ablInterpolator) scale_RGBA64Image_RGBA64Image_Over(dst RGBA64Image, dr, adr image.Rectangle, src image.RGBA64Image, sr image.Rectangle, opts *Options) {
-	sw := int32(sr.Dx())
-	sh := int32(sr.Dy())
-	yscale := float64(sh) / float64(dr.Dy())
-	xscale := float64(sw) / float64(dr.Dx())
-	swMinus1, shMinus1 := sw-1, sh-1
-	srcMask, smp := opts.SrcMask, opts.SrcMaskP
-	dstMask, dmp := opts.DstMask, opts.DstMaskP
-	dstColorRGBA64 := color.RGBA64{}
+func (nnInterpolator) transform_RGBA64_NRGBA64_Over(dst *image.RGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NRGBA64, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
+			}
+			pi := (sy0-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*8
+			pa := (uint32(src.Pix[pi+6])<<8 | uint32(src.Pix[pi+7]))
+			pr := (uint32(src.Pix[pi+0])<<8 | uint32(src.Pix[pi+1])) * pa / 0xffff
+			pg := (uint32(src.Pix[pi+2])<<8 | uint32(src.Pix[pi+3])) * pa / 0xffff
+			pb := (uint32(src.Pix[pi+4])<<8 | uint32(src.Pix[pi+5])) * pa / 0xffff
+			pa1 := 0xffff - pa
+			pr0 := (uint32(dst.Pix[d+0])<<8|uint32(dst.Pix[d+1]))*pa1/0xffff + pr
+			pg0 := (uint32(dst.Pix[d+2])<<8|uint32(dst.Pix[d+3]))*pa1/0xffff + pg
+			pb0 := (uint32(dst.Pix[d+4])<<8|uint32(dst.Pix[d+5]))*pa1/0xffff + pb
+			pa0 := (uint32(dst.Pix[d+6])<<8|uint32(dst.Pix[d+7]))*pa1/0xffff + pa
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
 
+func (nnInterpolator) transform_RGBA64_NRGBA64_Src(dst *image.RGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NRGBA64, sr image.Rectangle, bias image.Point, opts *Options) {
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
-		sy := float64((float64(dy)+0.5)*yscale) - 0.5
-		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
-		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
-		// sx, below.
-		sy0 := int32(sy)
-		yFrac0 := sy - float64(sy0)
-		yFrac1 := 1 - yFrac0
-		sy1 := sy0 + 1
-		if sy < 0 {
-			sy0, sy1 = 0, 0
-			yFrac0, yFrac1 = 0, 1
-		} else if sy1 > shMinus1 {
-			sy0, sy1 = shMinus1, shMinus1
-			yFrac0, yFrac1 = 1, 0
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
+			}
+			pi := (sy0-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*8
+			pa := (uint32(src.Pix[pi+6])<<8 | uint32(src.Pix[pi+7]))
+			pr := (uint32(src.Pix[pi+0])<<8 | uint32(src.Pix[pi+1])) * pa / 0xffff
+			pg := (uint32(src.Pix[pi+2])<<8 | uint32(src.Pix[pi+3])) * pa / 0xffff
+			pb := (uint32(src.Pix[pi+4])<<8 | uint32(src.Pix[pi+5])) * pa / 0xffff
+			pr0 := pr
+			pg0 := pg
+			pb0 := pb
+			pa0 := pa
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
 		}
+	}
+}
 
-		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
-			sx := float64((float64(dx)+0.5)*xscale) - 0.5
-			sx0 := int32(sx)
-			xFrac0 := sx - float64(sx0)
-			xFrac1 := 1 - xFrac0
-			sx1 := sx0 + 1
-			if sx < 0 {
-				sx0, sx1 = 0, 0
-				xFrac0, xFrac1 = 0, 1
-			} else if sx1 > swMinus1 {
-				sx0, sx1 = swMinus1, swMinus1
-				xFrac0, xFrac1 = 1, 0
+func (nnInterpolator) transform_RGBA64_RGBA64_Over(dst *image.RGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.RGBA64, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
 			}
+			pi := (sy0-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*8
+			pr := (uint32(src.Pix[pi+0])<<8 | uint32(src.Pix[pi+1]))
+			pg := (uint32(src.Pix[pi+2])<<8 | uint32(src.Pix[pi+3]))
+			pb := (uint32(src.Pix[pi+4])<<8 | uint32(src.Pix[pi+5]))
+			pa := (uint32(src.Pix[pi+6])<<8 | uint32(src.Pix[pi+7]))
+			pa1 := 0xffff - pa
+			pr0 := (uint32(dst.Pix[d+0])<<8|uint32(dst.Pix[d+1]))*pa1/0xffff + pr
+			pg0 := (uint32(dst.Pix[d+2])<<8|uint32(dst.Pix[d+3]))*pa1/0xffff + pg
+			pb0 := (uint32(dst.Pix[d+4])<<8|uint32(dst.Pix[d+5]))*pa1/0xffff + pb
+			pa0 := (uint32(dst.Pix[d+6])<<8|uint32(dst.Pix[d+7]))*pa1/0xffff + pa
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
 
-			s00u := src.RGBA64At(sr.Min.X+int(sx0), sr.Min.Y+int(sy0))
-			if srcMask != nil {
-				_, _, _, ma := srcMask.At(smp.X+sr.Min.X+int(sx0), smp.Y+sr.Min.Y+int(sy0)).RGBA()
-				s00u.R = uint16(uint32(s00u.R) * ma / 0xffff)
-				s00u.G = uint16(uint32(s00u.G) * ma / 0xffff)
-				s00u.B = uint16(uint32(s00u.B) * ma / 0xffff)
-				s00u.A = uint16(uint32(s00u.A) * ma / 0xffff)
-			}
-			s00r := float64(s00u.R)
-			s00g := float64(s00u.G)
-			s00b := float64(s00u.B)
-			s00a := float64(s00u.A)
-			s10u := src.RGBA64At(sr.Min.X+int(sx1), sr.Min.Y+int(sy0))
-			if srcMask != nil {
-				_, _, _, ma := srcMask.At(smp.X+sr.Min.X+int(sx1), smp.Y+sr.Min.Y+int(sy0)).RGBA()
-				s10u.R = uint16(uint32(s10u.R) * ma / 0xffff)
-				s10u.G = uint16(uint32(s10u.G) * ma / 0xffff)
-				s10u.B = uint16(uint32(s10u.B) * ma / 0xffff)
-				s10u.A = uint16(uint32(s10u.A) * ma / 0xffff)
-			}
-			s10r := float64(s10u.R)
-			s10g := float64(s10u.G)
-			s10b := float64(s10u.B)
-			s10a := float64(s10u.A)
-			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
-			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
-			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
-			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
-			s01u := src.RGBA64At(sr.Min.X+int(sx0), sr.Min.Y+int(sy1))
-			if srcMask != nil {
-				_, _, _, ma := srcMask.At(smp.X+sr.Min.X+int(sx0), smp.Y+sr.Min.Y+int(sy1)).RGBA()
-				s01u.R = uint16(uint32(s01u.R) * ma / 0xffff)
-				s01u.G = uint16(uint32(s01u.G) * ma / 0xffff)
-				s01u.B = uint16(uint32(s01u.B) * ma / 0xffff)
-				s01u.A = uint16(uint32(s01u.A) * ma / 0xffff)
-			}
-			s01r := float64(s01u.R)
-			s01g := float64(s01u.G)
-			s01b := float64(s01u.B)
-			s01a := float64(s01u.A)
-			s11u := src.RGBA64At(sr.Min.X+int(sx1), sr.Min.Y+int(sy1))
-			if srcMask != nil {
-				_, _, _, ma := srcMask.At(smp.X+sr.Min.X+int(sx1), smp.Y+sr.Min.Y+int(sy1)).RGBA()
-				s11u.R = uint16(uint32(s11u.R) * ma / 0xffff)
-				s11u.G = uint16(uint32(s11u.G) * ma / 0xffff)
-				s11u.B = uint16(uint32(s11u.B) * ma / 0xffff)
-				s11u.A = uint16(uint32(s11u.A) * ma / 0xffff)
+func (nnInterpolator) transform_RGBA64_RGBA64_Src(dst *image.RGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.RGBA64, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
 			}
-			s11r := float64(s11u.R)
-			s11g := float64(s11u.G)
-			s11b := float64(s11u.B)
-			s11a := float64(s11u.A)
-			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
-			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
-			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
-			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
-			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
-			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
-			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
-			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
-			p := color.RGBA64{uint16(s11r), uint16(s11g), uint16(s11b), uint16(s11a)}
-			q := dst.RGBA64At(dr.Min.X+int(dx), dr.Min.Y+int(dy))
-			if dstMask != nil {
-				_, _, _, ma := dstMask.At(dmp.X+dr.Min.X+int(dx), dmp.Y+dr.Min.Y+int(dy)).RGBA()
-				p.R = uint16(uint32(p.R) * ma / 0xffff)
-				p.G = uint16(uint32(p.G) * ma / 0xffff)
-				p.B = uint16(uint32(p.B) * ma / 0xffff)
-				p.A = uint16(uint32(p.A) * ma / 0xffff)
+			pi := (sy0-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*8
+			pr := (uint32(src.Pix[pi+0])<<8 | uint32(src.Pix[pi+1]))
+			pg := (uint32(src.Pix[pi+2])<<8 | uint32(src.Pix[pi+3]))
+			pb := (uint32(src.Pix[pi+4])<<8 | uint32(src.Pix[pi+5]))
+			pa := (uint32(src.Pix[pi+6])<<8 | uint32(src.Pix[pi+7]))
+			pr0 := pr
+			pg0 := pg
+			pb0 := pb
+			pa0 := pa
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
+
+func (nnInterpolator) transform_RGBA64_RGBA64Image_Over(dst *image.RGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src image.RGBA64Image, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
 			}
+			p := src.RGBA64At(sx0, sy0)
 			pa1 := 0xffff - uint32(p.A)
-			dstColorRGBA64.R = uint16(uint32(q.R)*pa1/0xffff + uint32(p.R))
-			dstColorRGBA64.G = uint16(uint32(q.G)*pa1/0xffff + uint32(p.G))
-			dstColorRGBA64.B = uint16(uint32(q.B)*pa1/0xffff + uint32(p.B))
-			dstColorRGBA64.A = uint16(uint32(q.A)*pa1/0xffff + uint32(p.A))
-			dst.Set(dr.Min.X+int(dx), dr.Min.Y+int(dy), dstColorRGBA64)
+			pr0 := (uint32(dst.Pix[d+0])<<8|uint32(dst.Pix[d+1]))*pa1/0xffff + uint32(p.R)
+			pg0 := (uint32(dst.Pix[d+2])<<8|uint32(dst.Pix[d+3]))*pa1/0xffff + uint32(p.G)
+			pb0 := (uint32(dst.Pix[d+4])<<8|uint32(dst.Pix[d+5]))*pa1/0xffff + uint32(p.B)
+			pa0 := (uint32(dst.Pix[d+6])<<8|uint32(dst.Pix[d+7]))*pa1/0xffff + uint32(p.A)
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
 		}
 	}
 }
 
-func (ablInterpolator) scale_RGBA64Image_RGBA64Image_Src(dst RGBA64Image, dr, adr image.Rectangle, src image.RGBA64Image, sr image.Rectangle, opts *Options) {
-	sw := int32(sr.Dx())
-	sh := int32(sr.Dy())
-	yscale := float64(sh) / float64(dr.Dy())
-	xscale := float64(sw) / float64(dr.Dx())
-	swMinus1, shMinus1 := sw-1, sh-1
-	srcMask, smp := opts.SrcMask, opts.SrcMaskP
-	dstMask, dmp := opts.DstMask, opts.DstMaskP
-	dstColorRGBA64 := color.RGBA64{}
-
+func (nnInterpolator) transform_RGBA64_RGBA64Image_Src(dst *image.RGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src image.RGBA64Image, sr image.Rectangle, bias image.Point, opts *Options) {
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
-		sy := float64((float64(dy)+0.5)*yscale) - 0.5
-		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
-		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
-		// sx, below.
-		sy0 := int32(sy)
-		yFrac0 := sy - float64(sy0)
-		yFrac1 := 1 - yFrac0
-		sy1 := sy0 + 1
-		if sy < 0 {
-			sy0, sy1 = 0, 0
-			yFrac0, yFrac1 = 0, 1
-		} else if sy1 > shMinus1 {
-			sy0, sy1 = shMinus1, shMinus1
-			yFrac0, yFrac1 = 1, 0
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
+			}
+			p := src.RGBA64At(sx0, sy0)
+			pr0 := uint32(p.R)
+			pg0 := uint32(p.G)
+			pb0 := uint32(p.B)
+			pa0 := uint32(p.A)
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
 		}
+	}
+}
 
-		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
-			sx := float64((float64(dx)+0.5)*xscale) - 0.5
-			sx0 := int32(sx)
-			xFrac0 := sx - float64(sx0)
-			xFrac1 := 1 - xFrac0
-			sx1 := sx0 + 1
-			if sx < 0 {
-				sx0, sx1 = 0, 0
-				xFrac0, xFrac1 = 0, 1
-			} else if sx1 > swMinus1 {
-				sx0, sx1 = swMinus1, swMinus1
-				xFrac0, xFrac1 = 1, 0
+func (nnInterpolator) transform_RGBA64_Image_Over(dst *image.RGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src image.Image, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
 			}
+			pr, pg, pb, pa := src.At(sx0, sy0).RGBA()
+			pa1 := 0xffff - pa
+			pr0 := (uint32(dst.Pix[d+0])<<8|uint32(dst.Pix[d+1]))*pa1/0xffff + pr
+			pg0 := (uint32(dst.Pix[d+2])<<8|uint32(dst.Pix[d+3]))*pa1/0xffff + pg
+			pb0 := (uint32(dst.Pix[d+4])<<8|uint32(dst.Pix[d+5]))*pa1/0xffff + pb
+			pa0 := (uint32(dst.Pix[d+6])<<8|uint32(dst.Pix[d+7]))*pa1/0xffff + pa
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
 
-			s00u := src.RGBA64At(sr.Min.X+int(sx0), sr.Min.Y+int(sy0))
-			if srcMask != nil {
-				_, _, _, ma := srcMask.At(smp.X+sr.Min.X+int(sx0), smp.Y+sr.Min.Y+int(sy0)).RGBA()
-				s00u.R = uint16(uint32(s00u.R) * ma / 0xffff)
-				s00u.G = uint16(uint32(s00u.G) * ma / 0xffff)
-				s00u.B = uint16(uint32(s00u.B) * ma / 0xffff)
-				s00u.A = uint16(uint32(s00u.A) * ma / 0xffff)
-			}
-			s00r := float64(s00u.R)
-			s00g := float64(s00u.G)
-			s00b := float64(s00u.B)
-			s00a := float64(s00u.A)
-			s10u := src.RGBA64At(sr.Min.X+int(sx1), sr.Min.Y+int(sy0))
-			if srcMask != nil {
-				_, _, _, ma := srcMask.At(smp.X+sr.Min.X+int(sx1), smp.Y+sr.Min.Y+int(sy0)).RGBA()
-				s10u.R = uint16(uint32(s10u.R) * ma / 0xffff)
-				s10u.G = uint16(uint32(s10u.G) * ma / 0xffff)
-				s10u.B = uint16(uint32(s10u.B) * ma / 0xffff)
-				s10u.A = uint16(uint32(s10u.A) * ma / 0xffff)
-			}
-			s10r := float64(s10u.R)
-			s10g := float64(s10u.G)
-			s10b := float64(s10u.B)
-			s10a := float64(s10u.A)
-			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
-			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
-			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
-			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
-			s01u := src.RGBA64At(sr.Min.X+int(sx0), sr.Min.Y+int(sy1))
-			if srcMask != nil {
-				_, _, _, ma := srcMask.At(smp.X+sr.Min.X+int(sx0), smp.Y+sr.Min.Y+int(sy1)).RGBA()
-				s01u.R = uint16(uint32(s01u.R) * ma / 0xffff)
-				s01u.G = uint16(uint32(s01u.G) * ma / 0xffff)
-				s01u.B = uint16(uint32(s01u.B) * ma / 0xffff)
-				s01u.A = uint16(uint32(s01u.A) * ma / 0xffff)
-			}
-			s01r := float64(s01u.R)
-			s01g := float64(s01u.G)
-			s01b := float64(s01u.B)
-			s01a := float64(s01u.A)
-			s11u := src.RGBA64At(sr.Min.X+int(sx1), sr.Min.Y+int(sy1))
-			if srcMask != nil {
-				_, _, _, ma := srcMask.At(smp.X+sr.Min.X+int(sx1), smp.Y+sr.Min.Y+int(sy1)).RGBA()
-				s11u.R = uint16(uint32(s11u.R) * ma / 0xffff)
-				s11u.G = uint16(uint32(s11u.G) * ma / 0xffff)
-				s11u.B = uint16(uint32(s11u.B) * ma / 0xffff)
-				s11u.A = uint16(uint32(s11u.A) * ma / 0xffff)
-			}
-			s11r := float64(s11u.R)
-			s11g := float64(s11u.G)
-			s11b := float64(s11u.B)
-			s11a := float64(s11u.A)
-			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
-			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
-			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
-			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
-			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
-			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
-			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
-			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
-			p := color.RGBA64{uint16(s11r), uint16(s11g), uint16(s11b), uint16(s11a)}
-			if dstMask != nil {
-				q := dst.RGBA64At(dr.Min.X+int(dx), dr.Min.Y+int(dy))
-				_, _, _, ma := dstMask.At(dmp.X+dr.Min.X+int(dx), dmp.Y+dr.Min.Y+int(dy)).RGBA()
-				p.R = uint16(uint32(p.R) * ma / 0xffff)
-				p.G = uint16(uint32(p.G) * ma / 0xffff)
-				p.B = uint16(uint32(p.B) * ma / 0xffff)
-				p.A = uint16(uint32(p.A) * ma / 0xffff)
-				pa1 := 0xffff - ma
-				dstColorRGBA64.R = uint16(uint32(q.R)*pa1/0xffff + uint32(p.R))
-				dstColorRGBA64.G = uint16(uint32(q.G)*pa1/0xffff + uint32(p.G))
-				dstColorRGBA64.B = uint16(uint32(q.B)*pa1/0xffff + uint32(p.B))
-				dstColorRGBA64.A = uint16(uint32(q.A)*pa1/0xffff + uint32(p.A))
-				dst.Set(dr.Min.X+int(dx), dr.Min.Y+int(dy), dstColorRGBA64)
-			} else {
-				dst.Set(dr.Min.X+int(dx), dr.Min.Y+int(dy), p)
+func (nnInterpolator) transform_RGBA64_Image_Src(dst *image.RGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src image.Image, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
 			}
+			pr, pg, pb, pa := src.At(sx0, sy0).RGBA()
+			pr0 := pr
+			pg0 := pg
+			pb0 := pb
+			pa0 := pa
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
 		}
 	}
 }
 
-func (ablInterpolator) scale_Image_Image_Over(dst Image, dr, adr image.Rectangle, src image.Image, sr image.Rectangle, opts *Options) {
-	sw := int32(sr.Dx())
-	sh := int32(sr.Dy())
-	yscale := float64(sh) / float64(dr.Dy())
-	xscale := float64(sw) / float64(dr.Dx())
-	swMinus1, shMinus1 := sw-1, sh-1
-	srcMask, smp := opts.SrcMask, opts.SrcMaskP
-	dstMask, dmp := opts.DstMask, opts.DstMaskP
-	dstColorRGBA64 := &color.RGBA64{}
-	dstColor := color.Color(dstColorRGBA64)
-
+func (nnInterpolator) transform_NRGBA64_NRGBA64_Over(dst *image.NRGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NRGBA64, sr image.Rectangle, bias image.Point, opts *Options) {
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
-		sy := float64((float64(dy)+0.5)*yscale) - 0.5
-		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
-		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
-		// sx, below.
-		sy0 := int32(sy)
-		yFrac0 := sy - float64(sy0)
-		yFrac1 := 1 - yFrac0
-		sy1 := sy0 + 1
-		if sy < 0 {
-			sy0, sy1 = 0, 0
-			yFrac0, yFrac1 = 0, 1
-		} else if sy1 > shMinus1 {
-			sy0, sy1 = shMinus1, shMinus1
-			yFrac0, yFrac1 = 1, 0
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
+			}
+			pi := (sy0-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*8
+			pa := (uint32(src.Pix[pi+6])<<8 | uint32(src.Pix[pi+7]))
+			pr := (uint32(src.Pix[pi+0])<<8 | uint32(src.Pix[pi+1])) * pa / 0xffff
+			pg := (uint32(src.Pix[pi+2])<<8 | uint32(src.Pix[pi+3])) * pa / 0xffff
+			pb := (uint32(src.Pix[pi+4])<<8 | uint32(src.Pix[pi+5])) * pa / 0xffff
+			pa1 := 0xffff - pa
+			pqa := (uint32(dst.Pix[d+6])<<8 | uint32(dst.Pix[d+7]))
+			pr0 := ((uint32(dst.Pix[d+0])<<8|uint32(dst.Pix[d+1]))*pqa/0xffff)*pa1/0xffff + pr
+			pg0 := ((uint32(dst.Pix[d+2])<<8|uint32(dst.Pix[d+3]))*pqa/0xffff)*pa1/0xffff + pg
+			pb0 := ((uint32(dst.Pix[d+4])<<8|uint32(dst.Pix[d+5]))*pqa/0xffff)*pa1/0xffff + pb
+			pa0 := pqa*pa1/0xffff + pa
+			if pa0 != 0 && pa0 != 0xffff {
+				pr0 = pr0 * 0xffff / pa0
+				pg0 = pg0 * 0xffff / pa0
+				pb0 = pb0 * 0xffff / pa0
+			}
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
 		}
+	}
+}
 
-		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
-			sx := float64((float64(dx)+0.5)*xscale) - 0.5
-			sx0 := int32(sx)
-			xFrac0 := sx - float64(sx0)
-			xFrac1 := 1 - xFrac0
-			sx1 := sx0 + 1
-			if sx < 0 {
-				sx0, sx1 = 0, 0
-				xFrac0, xFrac1 = 0, 1
-			} else if sx1 > swMinus1 {
-				sx0, sx1 = swMinus1, swMinus1
-				xFrac0, xFrac1 = 1, 0
+func (nnInterpolator) transform_NRGBA64_NRGBA64_Src(dst *image.NRGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NRGBA64, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
 			}
+			pi := (sy0-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*8
+			pa := (uint32(src.Pix[pi+6])<<8 | uint32(src.Pix[pi+7]))
+			pr := (uint32(src.Pix[pi+0])<<8 | uint32(src.Pix[pi+1])) * pa / 0xffff
+			pg := (uint32(src.Pix[pi+2])<<8 | uint32(src.Pix[pi+3])) * pa / 0xffff
+			pb := (uint32(src.Pix[pi+4])<<8 | uint32(src.Pix[pi+5])) * pa / 0xffff
+			pr0 := pr
+			pg0 := pg
+			pb0 := pb
+			pa0 := pa
+			if pa0 != 0 && pa0 != 0xffff {
+				pr0 = pr0 * 0xffff / pa0
+				pg0 = pg0 * 0xffff / pa0
+				pb0 = pb0 * 0xffff / pa0
+			}
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
 
-			s00ru, s00gu, s00bu, s00au := src.At(sr.Min.X+int(sx0), sr.Min.Y+int(sy0)).RGBA()
-			if srcMask != nil {
-				_, _, _, ma := srcMask.At(smp.X+sr.Min.X+int(sx0), smp.Y+sr.Min.Y+int(sy0)).RGBA()
-				s00ru = s00ru * ma / 0xffff
-				s00gu = s00gu * ma / 0xffff
-				s00bu = s00bu * ma / 0xffff
-				s00au = s00au * ma / 0xffff
+func (nnInterpolator) transform_NRGBA64_RGBA64_Over(dst *image.NRGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.RGBA64, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
 			}
-			s00r := float64(s00ru)
-			s00g := float64(s00gu)
-			s00b := float64(s00bu)
-			s00a := float64(s00au)
-			s10ru, s10gu, s10bu, s10au := src.At(sr.Min.X+int(sx1), sr.Min.Y+int(sy0)).RGBA()
-			if srcMask != nil {
-				_, _, _, ma := srcMask.At(smp.X+sr.Min.X+int(sx1), smp.Y+sr.Min.Y+int(sy0)).RGBA()
-				s10ru = s10ru * ma / 0xffff
-				s10gu = s10gu * ma / 0xffff
-				s10bu = s10bu * ma / 0xffff
-				s10au = s10au * ma / 0xffff
+			pi := (sy0-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*8
+			pr := (uint32(src.Pix[pi+0])<<8 | uint32(src.Pix[pi+1]))
+			pg := (uint32(src.Pix[pi+2])<<8 | uint32(src.Pix[pi+3]))
+			pb := (uint32(src.Pix[pi+4])<<8 | uint32(src.Pix[pi+5]))
+			pa := (uint32(src.Pix[pi+6])<<8 | uint32(src.Pix[pi+7]))
+			pa1 := 0xffff - pa
+			pqa := (uint32(dst.Pix[d+6])<<8 | uint32(dst.Pix[d+7]))
+			pr0 := ((uint32(dst.Pix[d+0])<<8|uint32(dst.Pix[d+1]))*pqa/0xffff)*pa1/0xffff + pr
+			pg0 := ((uint32(dst.Pix[d+2])<<8|uint32(dst.Pix[d+3]))*pqa/0xffff)*pa1/0xffff + pg
+			pb0 := ((uint32(dst.Pix[d+4])<<8|uint32(dst.Pix[d+5]))*pqa/0xffff)*pa1/0xffff + pb
+			pa0 := pqa*pa1/0xffff + pa
+			if pa0 != 0 && pa0 != 0xffff {
+				pr0 = pr0 * 0xffff / pa0
+				pg0 = pg0 * 0xffff / pa0
+				pb0 = pb0 * 0xffff / pa0
+			}
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
+
+func (nnInterpolator) transform_NRGBA64_RGBA64_Src(dst *image.NRGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.RGBA64, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
 			}
-			s10r := float64(s10ru)
-			s10g := float64(s10gu)
-			s10b := float64(s10bu)
-			s10a := float64(s10au)
-			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
-			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
-			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
-			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
-			s01ru, s01gu, s01bu, s01au := src.At(sr.Min.X+int(sx0), sr.Min.Y+int(sy1)).RGBA()
-			if srcMask != nil {
-				_, _, _, ma := srcMask.At(smp.X+sr.Min.X+int(sx0), smp.Y+sr.Min.Y+int(sy1)).RGBA()
-				s01ru = s01ru * ma / 0xffff
-				s01gu = s01gu * ma / 0xffff
-				s01bu = s01bu * ma / 0xffff
-				s01au = s01au * ma / 0xffff
+			pi := (sy0-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*8
+			pr := (uint32(src.Pix[pi+0])<<8 | uint32(src.Pix[pi+1]))
+			pg := (uint32(src.Pix[pi+2])<<8 | uint32(src.Pix[pi+3]))
+			pb := (uint32(src.Pix[pi+4])<<8 | uint32(src.Pix[pi+5]))
+			pa := (uint32(src.Pix[pi+6])<<8 | uint32(src.Pix[pi+7]))
+			pr0 := pr
+			pg0 := pg
+			pb0 := pb
+			pa0 := pa
+			if pa0 != 0 && pa0 != 0xffff {
+				pr0 = pr0 * 0xffff / pa0
+				pg0 = pg0 * 0xffff / pa0
+				pb0 = pb0 * 0xffff / pa0
+			}
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
+
+func (nnInterpolator) transform_NRGBA64_RGBA64Image_Over(dst *image.NRGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src image.RGBA64Image, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
 			}
-			s01r := float64(s01ru)
-			s01g := float64(s01gu)
-			s01b := float64(s01bu)
-			s01a := float64(s01au)
-			s11ru, s11gu, s11bu, s11au := src.At(sr.Min.X+int(sx1), sr.Min.Y+int(sy1)).RGBA()
-			if srcMask != nil {
-				_, _, _, ma := srcMask.At(smp.X+sr.Min.X+int(sx1), smp.Y+sr.Min.Y+int(sy1)).RGBA()
-				s11ru = s11ru * ma / 0xffff
-				s11gu = s11gu * ma / 0xffff
-				s11bu = s11bu * ma / 0xffff
-				s11au = s11au * ma / 0xffff
+			p := src.RGBA64At(sx0, sy0)
+			pa1 := 0xffff - uint32(p.A)
+			pqa := (uint32(dst.Pix[d+6])<<8 | uint32(dst.Pix[d+7]))
+			pr0 := ((uint32(dst.Pix[d+0])<<8|uint32(dst.Pix[d+1]))*pqa/0xffff)*pa1/0xffff + uint32(p.R)
+			pg0 := ((uint32(dst.Pix[d+2])<<8|uint32(dst.Pix[d+3]))*pqa/0xffff)*pa1/0xffff + uint32(p.G)
+			pb0 := ((uint32(dst.Pix[d+4])<<8|uint32(dst.Pix[d+5]))*pqa/0xffff)*pa1/0xffff + uint32(p.B)
+			pa0 := pqa*pa1/0xffff + uint32(p.A)
+			if pa0 != 0 && pa0 != 0xffff {
+				pr0 = pr0 * 0xffff / pa0
+				pg0 = pg0 * 0xffff / pa0
+				pb0 = pb0 * 0xffff / pa0
+			}
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
+
+func (nnInterpolator) transform_NRGBA64_RGBA64Image_Src(dst *image.NRGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src image.RGBA64Image, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
 			}
-			s11r := float64(s11ru)
-			s11g := float64(s11gu)
-			s11b := float64(s11bu)
-			s11a := float64(s11au)
-			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
-			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
-			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
-			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
-			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
-			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
-			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
-			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
-			pr := uint32(s11r)
-			pg := uint32(s11g)
-			pb := uint32(s11b)
-			pa := uint32(s11a)
-			qr, qg, qb, qa := dst.At(dr.Min.X+int(dx), dr.Min.Y+int(dy)).RGBA()
-			if dstMask != nil {
-				_, _, _, ma := dstMask.At(dmp.X+dr.Min.X+int(dx), dmp.Y+dr.Min.Y+int(dy)).RGBA()
-				pr = pr * ma / 0xffff
-				pg = pg * ma / 0xffff
-				pb = pb * ma / 0xffff
-				pa = pa * ma / 0xffff
+			p := src.RGBA64At(sx0, sy0)
+			pr0 := uint32(p.R)
+			pg0 := uint32(p.G)
+			pb0 := uint32(p.B)
+			pa0 := uint32(p.A)
+			if pa0 != 0 && pa0 != 0xffff {
+				pr0 = pr0 * 0xffff / pa0
+				pg0 = pg0 * 0xffff / pa0
+				pb0 = pb0 * 0xffff / pa0
+			}
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
+
+func (nnInterpolator) transform_NRGBA64_Image_Over(dst *image.NRGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src image.Image, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
 			}
+			pr, pg, pb, pa := src.At(sx0, sy0).RGBA()
 			pa1 := 0xffff - pa
-			dstColorRGBA64.R = uint16(qr*pa1/0xffff + pr)
-			dstColorRGBA64.G = uint16(qg*pa1/0xffff + pg)
-			dstColorRGBA64.B = uint16(qb*pa1/0xffff + pb)
-			dstColorRGBA64.A = uint16(qa*pa1/0xffff + pa)
-			dst.Set(dr.Min.X+int(dx), dr.Min.Y+int(dy), dstColor)
+			pqa := (uint32(dst.Pix[d+6])<<8 | uint32(dst.Pix[d+7]))
+			pr0 := ((uint32(dst.Pix[d+0])<<8|uint32(dst.Pix[d+1]))*pqa/0xffff)*pa1/0xffff + pr
+			pg0 := ((uint32(dst.Pix[d+2])<<8|uint32(dst.Pix[d+3]))*pqa/0xffff)*pa1/0xffff + pg
+			pb0 := ((uint32(dst.Pix[d+4])<<8|uint32(dst.Pix[d+5]))*pqa/0xffff)*pa1/0xffff + pb
+			pa0 := pqa*pa1/0xffff + pa
+			if pa0 != 0 && pa0 != 0xffff {
+				pr0 = pr0 * 0xffff / pa0
+				pg0 = pg0 * 0xffff / pa0
+				pb0 = pb0 * 0xffff / pa0
+			}
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
 		}
 	}
 }
 
-func (ablInterpolator) scale_Image_Image_Src(dst Image, dr, adr image.Rectangle, src image.Image, sr image.Rectangle, opts *Options) {
-	sw := int32(sr.Dx())
-	sh := int32(sr.Dy())
-	yscale := float64(sh) / float64(dr.Dy())
-	xscale := float64(sw) / float64(dr.Dx())
-	swMinus1, shMinus1 := sw-1, sh-1
+func (nnInterpolator) transform_NRGBA64_Image_Src(dst *image.NRGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src image.Image, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
+			}
+			pr, pg, pb, pa := src.At(sx0, sy0).RGBA()
+			pr0 := pr
+			pg0 := pg
+			pb0 := pb
+			pa0 := pa
+			if pa0 != 0 && pa0 != 0xffff {
+				pr0 = pr0 * 0xffff / pa0
+				pg0 = pg0 * 0xffff / pa0
+				pb0 = pb0 * 0xffff / pa0
+			}
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
+
+func (nnInterpolator) transform_RGBA64Image_RGBA64Image_Over(dst RGBA64Image, dr, adr image.Rectangle, d2s *f64.Aff3, src image.RGBA64Image, sr image.Rectangle, bias image.Point, opts *Options) {
 	srcMask, smp := opts.SrcMask, opts.SrcMaskP
 	dstMask, dmp := opts.DstMask, opts.DstMaskP
-	dstColorRGBA64 := &color.RGBA64{}
-	dstColor := color.Color(dstColorRGBA64)
+	dstColorRGBA64 := color.RGBA64{}
 
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
-		sy := float64((float64(dy)+0.5)*yscale) - 0.5
-		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
-		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
-		// sx, below.
-		sy0 := int32(sy)
-		yFrac0 := sy - float64(sy0)
-		yFrac1 := 1 - yFrac0
-		sy1 := sy0 + 1
-		if sy < 0 {
-			sy0, sy1 = 0, 0
-			yFrac0, yFrac1 = 0, 1
-		} else if sy1 > shMinus1 {
-			sy0, sy1 = shMinus1, shMinus1
-			yFrac0, yFrac1 = 1, 0
-		}
-
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
 		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
-			sx := float64((float64(dx)+0.5)*xscale) - 0.5
-			sx0 := int32(sx)
-			xFrac0 := sx - float64(sx0)
-			xFrac1 := 1 - xFrac0
-			sx1 := sx0 + 1
-			if sx < 0 {
-				sx0, sx1 = 0, 0
-				xFrac0, xFrac1 = 0, 1
-			} else if sx1 > swMinus1 {
-				sx0, sx1 = swMinus1, swMinus1
-				xFrac0, xFrac1 = 1, 0
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
 			}
-
-			s00ru, s00gu, s00bu, s00au := src.At(sr.Min.X+int(sx0), sr.Min.Y+int(sy0)).RGBA()
+			p := src.RGBA64At(sx0, sy0)
 			if srcMask != nil {
-				_, _, _, ma := srcMask.At(smp.X+sr.Min.X+int(sx0), smp.Y+sr.Min.Y+int(sy0)).RGBA()
-				s00ru = s00ru * ma / 0xffff
-				s00gu = s00gu * ma / 0xffff
-				s00bu = s00bu * ma / 0xffff
-				s00au = s00au * ma / 0xffff
+				_, _, _, ma := srcMask.At(smp.X+sx0, smp.Y+sy0).RGBA()
+				p.R = uint16(uint32(p.R) * ma / 0xffff)
+				p.G = uint16(uint32(p.G) * ma / 0xffff)
+				p.B = uint16(uint32(p.B) * ma / 0xffff)
+				p.A = uint16(uint32(p.A) * ma / 0xffff)
 			}
-			s00r := float64(s00ru)
-			s00g := float64(s00gu)
-			s00b := float64(s00bu)
-			s00a := float64(s00au)
-			s10ru, s10gu, s10bu, s10au := src.At(sr.Min.X+int(sx1), sr.Min.Y+int(sy0)).RGBA()
-			if srcMask != nil {
-				_, _, _, ma := srcMask.At(smp.X+sr.Min.X+int(sx1), smp.Y+sr.Min.Y+int(sy0)).RGBA()
-				s10ru = s10ru * ma / 0xffff
-				s10gu = s10gu * ma / 0xffff
-				s10bu = s10bu * ma / 0xffff
-				s10au = s10au * ma / 0xffff
+			q := dst.RGBA64At(dr.Min.X+int(dx), dr.Min.Y+int(dy))
+			if dstMask != nil {
+				_, _, _, ma := dstMask.At(dmp.X+dr.Min.X+int(dx), dmp.Y+dr.Min.Y+int(dy)).RGBA()
+				p.R = uint16(uint32(p.R) * ma / 0xffff)
+				p.G = uint16(uint32(p.G) * ma / 0xffff)
+				p.B = uint16(uint32(p.B) * ma / 0xffff)
+				p.A = uint16(uint32(p.A) * ma / 0xffff)
 			}
-			s10r := float64(s10ru)
-			s10g := float64(s10gu)
-			s10b := float64(s10bu)
-			s10a := float64(s10au)
-			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
-			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
-			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
-			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
-			s01ru, s01gu, s01bu, s01au := src.At(sr.Min.X+int(sx0), sr.Min.Y+int(sy1)).RGBA()
+			pa1 := 0xffff - uint32(p.A)
+			dstColorRGBA64.R = uint16(uint32(q.R)*pa1/0xffff + uint32(p.R))
+			dstColorRGBA64.G = uint16(uint32(q.G)*pa1/0xffff + uint32(p.G))
+			dstColorRGBA64.B = uint16(uint32(q.B)*pa1/0xffff + uint32(p.B))
+			dstColorRGBA64.A = uint16(uint32(q.A)*pa1/0xffff + uint32(p.A))
+			dst.SetRGBA64(dr.Min.X+int(dx), dr.Min.Y+int(dy), dstColorRGBA64)
+		}
+	}
+}
+
+func (nnInterpolator) transform_RGBA64Image_RGBA64Image_Src(dst RGBA64Image, dr, adr image.Rectangle, d2s *f64.Aff3, src image.RGBA64Image, sr image.Rectangle, bias image.Point, opts *Options) {
+	srcMask, smp := opts.SrcMask, opts.SrcMaskP
+	dstMask, dmp := opts.DstMask, opts.DstMaskP
+	dstColorRGBA64 := color.RGBA64{}
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
+			}
+			p := src.RGBA64At(sx0, sy0)
 			if srcMask != nil {
-				_, _, _, ma := srcMask.At(smp.X+sr.Min.X+int(sx0), smp.Y+sr.Min.Y+int(sy1)).RGBA()
-				s01ru = s01ru * ma / 0xffff
-				s01gu = s01gu * ma / 0xffff
-				s01bu = s01bu * ma / 0xffff
-				s01au = s01au * ma / 0xffff
+				_, _, _, ma := srcMask.At(smp.X+sx0, smp.Y+sy0).RGBA()
+				p.R = uint16(uint32(p.R) * ma / 0xffff)
+				p.G = uint16(uint32(p.G) * ma / 0xffff)
+				p.B = uint16(uint32(p.B) * ma / 0xffff)
+				p.A = uint16(uint32(p.A) * ma / 0xffff)
 			}
-			s01r := float64(s01ru)
-			s01g := float64(s01gu)
-			s01b := float64(s01bu)
-			s01a := float64(s01au)
-			s11ru, s11gu, s11bu, s11au := src.At(sr.Min.X+int(sx1), sr.Min.Y+int(sy1)).RGBA()
+			if dstMask != nil {
+				q := dst.RGBA64At(dr.Min.X+int(dx), dr.Min.Y+int(dy))
+				_, _, _, ma := dstMask.At(dmp.X+dr.Min.X+int(dx), dmp.Y+dr.Min.Y+int(dy)).RGBA()
+				p.R = uint16(uint32(p.R) * ma / 0xffff)
+				p.G = uint16(uint32(p.G) * ma / 0xffff)
+				p.B = uint16(uint32(p.B) * ma / 0xffff)
+				p.A = uint16(uint32(p.A) * ma / 0xffff)
+				pa1 := 0xffff - ma
+				dstColorRGBA64.R = uint16(uint32(q.R)*pa1/0xffff + uint32(p.R))
+				dstColorRGBA64.G = uint16(uint32(q.G)*pa1/0xffff + uint32(p.G))
+				dstColorRGBA64.B = uint16(uint32(q.B)*pa1/0xffff + uint32(p.B))
+				dstColorRGBA64.A = uint16(uint32(q.A)*pa1/0xffff + uint32(p.A))
+				dst.SetRGBA64(dr.Min.X+int(dx), dr.Min.Y+int(dy), dstColorRGBA64)
+			} else {
+				dst.SetRGBA64(dr.Min.X+int(dx), dr.Min.Y+int(dy), p)
+			}
+		}
+	}
+}
+
+func (nnInterpolator) transform_Image_Image_Over(dst Image, dr, adr image.Rectangle, d2s *f64.Aff3, src image.Image, sr image.Rectangle, bias image.Point, opts *Options) {
+	srcMask, smp := opts.SrcMask, opts.SrcMaskP
+	dstMask, dmp := opts.DstMask, opts.DstMaskP
+	dstColorRGBA64 := &color.RGBA64{}
+	dstColor := color.Color(dstColorRGBA64)
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
+			}
+			pr, pg, pb, pa := src.At(sx0, sy0).RGBA()
 			if srcMask != nil {
-				_, _, _, ma := srcMask.At(smp.X+sr.Min.X+int(sx1), smp.Y+sr.Min.Y+int(sy1)).RGBA()
-				s11ru = s11ru * ma / 0xffff
-				s11gu = s11gu * ma / 0xffff
-				s11bu = s11bu * ma / 0xffff
-				s11au = s11au * ma / 0xffff
+				_, _, _, ma := srcMask.At(smp.X+sx0, smp.Y+sy0).RGBA()
+				pr = pr * ma / 0xffff
+				pg = pg * ma / 0xffff
+				pb = pb * ma / 0xffff
+				pa = pa * ma / 0xffff
+			}
+			qr, qg, qb, qa := dst.At(dr.Min.X+int(dx), dr.Min.Y+int(dy)).RGBA()
+			if dstMask != nil {
+				_, _, _, ma := dstMask.At(dmp.X+dr.Min.X+int(dx), dmp.Y+dr.Min.Y+int(dy)).RGBA()
+				pr = pr * ma / 0xffff
+				pg = pg * ma / 0xffff
+				pb = pb * ma / 0xffff
+				pa = pa * ma / 0xffff
+			}
+			pa1 := 0xffff - pa
+			dstColorRGBA64.R = uint16(qr*pa1/0xffff + pr)
+			dstColorRGBA64.G = uint16(qg*pa1/0xffff + pg)
+			dstColorRGBA64.B = uint16(qb*pa1/0xffff + pb)
+			dstColorRGBA64.A = uint16(qa*pa1/0xffff + pa)
+			dst.Set(dr.Min.X+int(dx), dr.Min.Y+int(dy), dstColor)
+		}
+	}
+}
+
+func (nnInterpolator) transform_Image_Image_Src(dst Image, dr, adr image.Rectangle, d2s *f64.Aff3, src image.Image, sr image.Rectangle, bias image.Point, opts *Options) {
+	srcMask, smp := opts.SrcMask, opts.SrcMaskP
+	dstMask, dmp := opts.DstMask, opts.DstMaskP
+	dstColorRGBA64 := &color.RGBA64{}
+	dstColor := color.Color(dstColorRGBA64)
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+			sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+			if !(image.Point{sx0, sy0}).In(sr) {
+				continue
+			}
+			pr, pg, pb, pa := src.At(sx0, sy0).RGBA()
+			if srcMask != nil {
+				_, _, _, ma := srcMask.At(smp.X+sx0, smp.Y+sy0).RGBA()
+				pr = pr * ma / 0xffff
+				pg = pg * ma / 0xffff
+				pb = pb * ma / 0xffff
+				pa = pa * ma / 0xffff
 			}
-			s11r := float64(s11ru)
-			s11g := float64(s11gu)
-			s11b := float64(s11bu)
-			s11a := float64(s11au)
-			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
-			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
-			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
-			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
-			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
-			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
-			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
-			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
-			pr := uint32(s11r)
-			pg := uint32(s11g)
-			pb := uint32(s11b)
-			pa := uint32(s11a)
 			if dstMask != nil {
 				qr, qg, qb, qa := dst.At(dr.Min.X+int(dx), dr.Min.Y+int(dy)).RGBA()
 				_, _, _, ma := dstMask.At(dmp.X+dr.Min.X+int(dx), dmp.Y+dr.Min.Y+int(dy)).RGBA()
@@ -3537,90 +3767,12326 @@ func (ablInterpolator) scale_Image_Image_Src(dst Image, dr, adr image.Rectangle,
 	}
 }
 
-func (ablInterpolator) transform_RGBA_Gray_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.Gray, sr image.Rectangle, bias image.Point, opts *Options) {
-	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
-		dyf := float64(dr.Min.Y+int(dy)) + 0.5
-		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
-		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
-			dxf := float64(dr.Min.X+int(dx)) + 0.5
-			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
-			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
-			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
-				continue
-			}
-
-			sx -= 0.5
-			sx0 := int(sx)
-			xFrac0 := sx - float64(sx0)
-			xFrac1 := 1 - xFrac0
-			sx0 += bias.X
-			sx1 := sx0 + 1
-			if sx0 < sr.Min.X {
-				sx0, sx1 = sr.Min.X, sr.Min.X
-				xFrac0, xFrac1 = 0, 1
-			} else if sx1 >= sr.Max.X {
-				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
-				xFrac0, xFrac1 = 1, 0
-			}
+func (z ablInterpolator) Scale(dst Image, dr image.Rectangle, src image.Image, sr image.Rectangle, op Op, opts *Options) {
+	src, sr = untile(src, sr, opts)
 
-			sy -= 0.5
-			sy0 := int(sy)
-			yFrac0 := sy - float64(sy0)
-			yFrac1 := 1 - yFrac0
-			sy0 += bias.Y
-			sy1 := sy0 + 1
-			if sy0 < sr.Min.Y {
-				sy0, sy1 = sr.Min.Y, sr.Min.Y
-				yFrac0, yFrac1 = 0, 1
-			} else if sy1 >= sr.Max.Y {
-				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
-				yFrac0, yFrac1 = 1, 0
-			}
+	// Try to simplify a Scale to a Copy when DstMask is not specified.
+	// If DstMask is not nil, Copy will call Scale back with same dr and sr, and cause stack overflow.
+	if dr.Size() == sr.Size() && (opts == nil || opts.DstMask == nil) {
+		Copy(dst, dr.Min, unalias(dst, dr, src, sr), sr, op, opts)
+		return
+	}
 
-			s00i := (sy0-src.Rect.Min.Y)*src.Stride + (sx0 - src.Rect.Min.X)
-			s00ru := uint32(src.Pix[s00i]) * 0x101
-			s00r := float64(s00ru)
-			s10i := (sy0-src.Rect.Min.Y)*src.Stride + (sx1 - src.Rect.Min.X)
-			s10ru := uint32(src.Pix[s10i]) * 0x101
-			s10r := float64(s10ru)
-			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
-			s01i := (sy1-src.Rect.Min.Y)*src.Stride + (sx0 - src.Rect.Min.X)
-			s01ru := uint32(src.Pix[s01i]) * 0x101
-			s01r := float64(s01ru)
-			s11i := (sy1-src.Rect.Min.Y)*src.Stride + (sx1 - src.Rect.Min.X)
-			s11ru := uint32(src.Pix[s11i]) * 0x101
-			s11r := float64(s11ru)
-			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
-			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
-			pr := uint32(s11r)
-			out := uint8(pr >> 8)
-			dst.Pix[d+0] = out
-			dst.Pix[d+1] = out
-			dst.Pix[d+2] = out
-			dst.Pix[d+3] = 0xff
-		}
+	var o Options
+	if opts != nil {
+		o = *opts
 	}
-}
 
-func (ablInterpolator) transform_RGBA_NRGBA_Over(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NRGBA, sr image.Rectangle, bias image.Point, opts *Options) {
-	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
-		dyf := float64(dr.Min.Y+int(dy)) + 0.5
-		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
-		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
-			dxf := float64(dr.Min.X+int(dx)) + 0.5
-			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
-			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
-			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
-				continue
-			}
+	// adr is the affected destination pixels.
+	adr := dst.Bounds().Intersect(dr)
+	adr, o.DstMask = clipAffectedDestRect(adr, o.DstMask, o.DstMaskP)
+	if adr.Empty() || sr.Empty() {
+		return
+	}
+	src = unalias(dst, adr, src, sr)
+	// Make adr relative to dr.Min.
+	adr = adr.Sub(dr.Min)
+	if op == Over && o.SrcMask == nil && opaque(src) {
+		op = Src
+	}
 
-			sx -= 0.5
-			sx0 := int(sx)
-			xFrac0 := sx - float64(sx0)
-			xFrac1 := 1 - xFrac0
-			sx0 += bias.X
-			sx1 := sx0 + 1
-			if sx0 < sr.Min.X {
+	// sr is the source pixels. If it extends beyond the src bounds,
+	// we cannot use the type-specific fast paths, as they access
+	// the Pix fields directly without bounds checking.
+	//
+	// Similarly, the fast paths assume that the masks are nil.
+	if o.DstMask != nil || o.SrcMask != nil || !sr.In(src.Bounds()) {
+		switch op {
+		case Over:
+			z.scale_Image_Image_Over(dst, dr, adr, src, sr, &o)
+		case Src:
+			z.scale_Image_Image_Src(dst, dr, adr, src, sr, &o)
+		}
+	} else if _, ok := src.(*image.Uniform); ok {
+		// Scaling a uniform source is a fill of the affected
+		// destination pixels, which may have been clipped by a
+		// rectangular DstMask.
+		Draw(palettedDst(dst), adr.Add(dr.Min), src, src.Bounds().Min, op)
+	} else {
+		dst = palettedDst(dst)
+		switch op {
+		case Over:
+			switch dst := dst.(type) {
+			case *image.Alpha:
+				switch src := src.(type) {
+				case *image.Alpha:
+					z.scale_Alpha_Alpha_Over(dst, dr, adr, src, sr, &o)
+				case image.RGBA64Image:
+					z.scale_Alpha_RGBA64Image_Over(dst, dr, adr, src, sr, &o)
+				default:
+					z.scale_Alpha_Image_Over(dst, dr, adr, src, sr, &o)
+				}
+			case *image.Gray:
+				switch src := src.(type) {
+				case image.RGBA64Image:
+					z.scale_Gray_RGBA64Image_Over(dst, dr, adr, src, sr, &o)
+				default:
+					z.scale_Gray_Image_Over(dst, dr, adr, src, sr, &o)
+				}
+			case *image.RGBA:
+				switch src := src.(type) {
+				case *image.NRGBA:
+					z.scale_RGBA_NRGBA_Over(dst, dr, adr, src, sr, &o)
+				case *image.RGBA:
+					z.scale_RGBA_RGBA_Over(dst, dr, adr, src, sr, &o)
+				case *image.NYCbCrA:
+					switch src.SubsampleRatio {
+					default:
+						z.scale_RGBA_Image_Over(dst, dr, adr, src, sr, &o)
+					case image.YCbCrSubsampleRatio444:
+						z.scale_RGBA_NYCbCrA444_Over(dst, dr, adr, src, sr, &o)
+					case image.YCbCrSubsampleRatio422:
+						z.scale_RGBA_NYCbCrA422_Over(dst, dr, adr, src, sr, &o)
+					case image.YCbCrSubsampleRatio420:
+						z.scale_RGBA_NYCbCrA420_Over(dst, dr, adr, src, sr, &o)
+					case image.YCbCrSubsampleRatio440:
+						z.scale_RGBA_NYCbCrA440_Over(dst, dr, adr, src, sr, &o)
+					}
+				case image.RGBA64Image:
+					z.scale_RGBA_RGBA64Image_Over(dst, dr, adr, src, sr, &o)
+				default:
+					z.scale_RGBA_Image_Over(dst, dr, adr, src, sr, &o)
+				}
+			case *image.RGBA64:
+				switch src := src.(type) {
+				case *image.NRGBA64:
+					z.scale_RGBA64_NRGBA64_Over(dst, dr, adr, src, sr, &o)
+				case *image.RGBA64:
+					z.scale_RGBA64_RGBA64_Over(dst, dr, adr, src, sr, &o)
+				case image.RGBA64Image:
+					z.scale_RGBA64_RGBA64Image_Over(dst, dr, adr, src, sr, &o)
+				default:
+					z.scale_RGBA64_Image_Over(dst, dr, adr, src, sr, &o)
+				}
+			case *image.NRGBA64:
+				switch src := src.(type) {
+				case *image.NRGBA64:
+					z.scale_NRGBA64_NRGBA64_Over(dst, dr, adr, src, sr, &o)
+				case *image.RGBA64:
+					z.scale_NRGBA64_RGBA64_Over(dst, dr, adr, src, sr, &o)
+				case image.RGBA64Image:
+					z.scale_NRGBA64_RGBA64Image_Over(dst, dr, adr, src, sr, &o)
+				default:
+					z.scale_NRGBA64_Image_Over(dst, dr, adr, src, sr, &o)
+				}
+			case RGBA64Image:
+				switch src := src.(type) {
+				case image.RGBA64Image:
+					z.scale_RGBA64Image_RGBA64Image_Over(dst, dr, adr, src, sr, &o)
+				}
+			default:
+				switch src := src.(type) {
+				default:
+					z.scale_Image_Image_Over(dst, dr, adr, src, sr, &o)
+				}
+			}
+		case Src:
+			switch dst := dst.(type) {
+			case *image.Alpha:
+				switch src := src.(type) {
+				case *image.Alpha:
+					z.scale_Alpha_Alpha_Src(dst, dr, adr, src, sr, &o)
+				case image.RGBA64Image:
+					z.scale_Alpha_RGBA64Image_Src(dst, dr, adr, src, sr, &o)
+				default:
+					z.scale_Alpha_Image_Src(dst, dr, adr, src, sr, &o)
+				}
+			case *image.Gray:
+				switch src := src.(type) {
+				case *image.Gray:
+					z.scale_Gray_Gray_Src(dst, dr, adr, src, sr, &o)
+				case image.RGBA64Image:
+					z.scale_Gray_RGBA64Image_Src(dst, dr, adr, src, sr, &o)
+				default:
+					z.scale_Gray_Image_Src(dst, dr, adr, src, sr, &o)
+				}
+			case *image.RGBA:
+				switch src := src.(type) {
+				case *image.Gray:
+					z.scale_RGBA_Gray_Src(dst, dr, adr, src, sr, &o)
+				case *image.NRGBA:
+					z.scale_RGBA_NRGBA_Src(dst, dr, adr, src, sr, &o)
+				case *image.RGBA:
+					z.scale_RGBA_RGBA_Src(dst, dr, adr, src, sr, &o)
+				case *image.YCbCr:
+					switch src.SubsampleRatio {
+					default:
+						z.scale_RGBA_Image_Src(dst, dr, adr, src, sr, &o)
+					case image.YCbCrSubsampleRatio444:
+						z.scale_RGBA_YCbCr444_Src(dst, dr, adr, src, sr, &o)
+					case image.YCbCrSubsampleRatio422:
+						z.scale_RGBA_YCbCr422_Src(dst, dr, adr, src, sr, &o)
+					case image.YCbCrSubsampleRatio420:
+						z.scale_RGBA_YCbCr420_Src(dst, dr, adr, src, sr, &o)
+					case image.YCbCrSubsampleRatio440:
+						z.scale_RGBA_YCbCr440_Src(dst, dr, adr, src, sr, &o)
+					}
+				case *image.NYCbCrA:
+					switch src.SubsampleRatio {
+					default:
+						z.scale_RGBA_Image_Src(dst, dr, adr, src, sr, &o)
+					case image.YCbCrSubsampleRatio444:
+						z.scale_RGBA_NYCbCrA444_Src(dst, dr, adr, src, sr, &o)
+					case image.YCbCrSubsampleRatio422:
+						z.scale_RGBA_NYCbCrA422_Src(dst, dr, adr, src, sr, &o)
+					case image.YCbCrSubsampleRatio420:
+						z.scale_RGBA_NYCbCrA420_Src(dst, dr, adr, src, sr, &o)
+					case image.YCbCrSubsampleRatio440:
+						z.scale_RGBA_NYCbCrA440_Src(dst, dr, adr, src, sr, &o)
+					}
+				case image.RGBA64Image:
+					z.scale_RGBA_RGBA64Image_Src(dst, dr, adr, src, sr, &o)
+				default:
+					z.scale_RGBA_Image_Src(dst, dr, adr, src, sr, &o)
+				}
+			case *image.RGBA64:
+				switch src := src.(type) {
+				case *image.NRGBA64:
+					z.scale_RGBA64_NRGBA64_Src(dst, dr, adr, src, sr, &o)
+				case *image.RGBA64:
+					z.scale_RGBA64_RGBA64_Src(dst, dr, adr, src, sr, &o)
+				case image.RGBA64Image:
+					z.scale_RGBA64_RGBA64Image_Src(dst, dr, adr, src, sr, &o)
+				default:
+					z.scale_RGBA64_Image_Src(dst, dr, adr, src, sr, &o)
+				}
+			case *image.NRGBA64:
+				switch src := src.(type) {
+				case *image.NRGBA64:
+					z.scale_NRGBA64_NRGBA64_Src(dst, dr, adr, src, sr, &o)
+				case *image.RGBA64:
+					z.scale_NRGBA64_RGBA64_Src(dst, dr, adr, src, sr, &o)
+				case image.RGBA64Image:
+					z.scale_NRGBA64_RGBA64Image_Src(dst, dr, adr, src, sr, &o)
+				default:
+					z.scale_NRGBA64_Image_Src(dst, dr, adr, src, sr, &o)
+				}
+			case RGBA64Image:
+				switch src := src.(type) {
+				case image.RGBA64Image:
+					z.scale_RGBA64Image_RGBA64Image_Src(dst, dr, adr, src, sr, &o)
+				}
+			default:
+				switch src := src.(type) {
+				default:
+					z.scale_Image_Image_Src(dst, dr, adr, src, sr, &o)
+				}
+			}
+		}
+	}
+}
+
+func (z ablInterpolator) Transform(dst Image, s2d f64.Aff3, src image.Image, sr image.Rectangle, op Op, opts *Options) {
+	// Try to simplify a Transform to a Copy.
+	if s2d[0] == 1 && s2d[1] == 0 && s2d[3] == 0 && s2d[4] == 1 {
+		dx := int(s2d[2])
+		dy := int(s2d[5])
+		if float64(dx) == s2d[2] && float64(dy) == s2d[5] {
+			dp := image.Point{X: sr.Min.X + dx, Y: sr.Min.Y + dy}
+			Copy(dst, dp, unalias(dst, sr.Add(dp.Sub(sr.Min)), src, sr), sr, op, opts)
+			return
+		}
+	}
+
+	var o Options
+	if opts != nil {
+		o = *opts
+	}
+
+	dr := transformRect(&s2d, &sr)
+	// adr is the affected destination pixels.
+	adr := dst.Bounds().Intersect(dr)
+	adr, o.DstMask = clipAffectedDestRect(adr, o.DstMask, o.DstMaskP)
+	if adr.Empty() || sr.Empty() {
+		return
+	}
+	src = unalias(dst, adr, src, sr)
+	if op == Over && o.SrcMask == nil && opaque(src) {
+		op = Src
+	}
+
+	d2s := invert(&s2d)
+	// bias is a translation of the mapping from dst coordinates to src
+	// coordinates such that the latter temporarily have non-negative X
+	// and Y coordinates. This allows us to write int(f) instead of
+	// int(math.Floor(f)), since "round to zero" and "round down" are
+	// equivalent when f >= 0, but the former is much cheaper. The X--
+	// and Y-- are because the TransformLeaf methods have a "sx -= 0.5"
+	// adjustment.
+	bias := transformRect(&d2s, &adr).Min
+	bias.X--
+	bias.Y--
+	d2s[2] -= float64(bias.X)
+	d2s[5] -= float64(bias.Y)
+	// Make adr relative to dr.Min.
+	adr = adr.Sub(dr.Min)
+	// sr is the source pixels. If it extends beyond the src bounds,
+	// we cannot use the type-specific fast paths, as they access
+	// the Pix fields directly without bounds checking.
+	//
+	// Similarly, the fast paths assume that the masks are nil.
+	if o.DstMask != nil || o.SrcMask != nil || !sr.In(src.Bounds()) {
+		switch op {
+		case Over:
+			z.transform_Image_Image_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
+		case Src:
+			z.transform_Image_Image_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+		}
+	} else if u, ok := src.(*image.Uniform); ok {
+		transform_Uniform(dst, dr, adr, &d2s, u, sr, bias, op)
+	} else {
+		dst = palettedDst(dst)
+		switch op {
+		case Over:
+			switch dst := dst.(type) {
+			case *image.Alpha:
+				switch src := src.(type) {
+				case *image.Alpha:
+					z.transform_Alpha_Alpha_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
+				case image.RGBA64Image:
+					z.transform_Alpha_RGBA64Image_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
+				default:
+					z.transform_Alpha_Image_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
+				}
+			case *image.Gray:
+				switch src := src.(type) {
+				case image.RGBA64Image:
+					z.transform_Gray_RGBA64Image_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
+				default:
+					z.transform_Gray_Image_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
+				}
+			case *image.RGBA:
+				switch src := src.(type) {
+				case *image.NRGBA:
+					z.transform_RGBA_NRGBA_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
+				case *image.RGBA:
+					z.transform_RGBA_RGBA_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
+				case *image.NYCbCrA:
+					switch src.SubsampleRatio {
+					default:
+						z.transform_RGBA_Image_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
+					case image.YCbCrSubsampleRatio444:
+						z.transform_RGBA_NYCbCrA444_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
+					case image.YCbCrSubsampleRatio422:
+						z.transform_RGBA_NYCbCrA422_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
+					case image.YCbCrSubsampleRatio420:
+						z.transform_RGBA_NYCbCrA420_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
+					case image.YCbCrSubsampleRatio440:
+						z.transform_RGBA_NYCbCrA440_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
+					}
+				case image.RGBA64Image:
+					z.transform_RGBA_RGBA64Image_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
+				default:
+					z.transform_RGBA_Image_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
+				}
+			case *image.RGBA64:
+				switch src := src.(type) {
+				case *image.NRGBA64:
+					z.transform_RGBA64_NRGBA64_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
+				case *image.RGBA64:
+					z.transform_RGBA64_RGBA64_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
+				case image.RGBA64Image:
+					z.transform_RGBA64_RGBA64Image_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
+				default:
+					z.transform_RGBA64_Image_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
+				}
+			case *image.NRGBA64:
+				switch src := src.(type) {
+				case *image.NRGBA64:
+					z.transform_NRGBA64_NRGBA64_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
+				case *image.RGBA64:
+					z.transform_NRGBA64_RGBA64_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
+				case image.RGBA64Image:
+					z.transform_NRGBA64_RGBA64Image_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
+				default:
+					z.transform_NRGBA64_Image_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
+				}
+			case RGBA64Image:
+				switch src := src.(type) {
+				case image.RGBA64Image:
+					z.transform_RGBA64Image_RGBA64Image_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
+				}
+			default:
+				switch src := src.(type) {
+				default:
+					z.transform_Image_Image_Over(dst, dr, adr, &d2s, src, sr, bias, &o)
+				}
+			}
+		case Src:
+			switch dst := dst.(type) {
+			case *image.Alpha:
+				switch src := src.(type) {
+				case *image.Alpha:
+					z.transform_Alpha_Alpha_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+				case image.RGBA64Image:
+					z.transform_Alpha_RGBA64Image_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+				default:
+					z.transform_Alpha_Image_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+				}
+			case *image.Gray:
+				switch src := src.(type) {
+				case *image.Gray:
+					z.transform_Gray_Gray_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+				case image.RGBA64Image:
+					z.transform_Gray_RGBA64Image_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+				default:
+					z.transform_Gray_Image_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+				}
+			case *image.RGBA:
+				switch src := src.(type) {
+				case *image.Gray:
+					z.transform_RGBA_Gray_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+				case *image.NRGBA:
+					z.transform_RGBA_NRGBA_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+				case *image.RGBA:
+					z.transform_RGBA_RGBA_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+				case *image.YCbCr:
+					switch src.SubsampleRatio {
+					default:
+						z.transform_RGBA_Image_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+					case image.YCbCrSubsampleRatio444:
+						z.transform_RGBA_YCbCr444_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+					case image.YCbCrSubsampleRatio422:
+						z.transform_RGBA_YCbCr422_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+					case image.YCbCrSubsampleRatio420:
+						z.transform_RGBA_YCbCr420_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+					case image.YCbCrSubsampleRatio440:
+						z.transform_RGBA_YCbCr440_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+					}
+				case *image.NYCbCrA:
+					switch src.SubsampleRatio {
+					default:
+						z.transform_RGBA_Image_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+					case image.YCbCrSubsampleRatio444:
+						z.transform_RGBA_NYCbCrA444_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+					case image.YCbCrSubsampleRatio422:
+						z.transform_RGBA_NYCbCrA422_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+					case image.YCbCrSubsampleRatio420:
+						z.transform_RGBA_NYCbCrA420_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+					case image.YCbCrSubsampleRatio440:
+						z.transform_RGBA_NYCbCrA440_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+					}
+				case image.RGBA64Image:
+					z.transform_RGBA_RGBA64Image_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+				default:
+					z.transform_RGBA_Image_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+				}
+			case *image.RGBA64:
+				switch src := src.(type) {
+				case *image.NRGBA64:
+					z.transform_RGBA64_NRGBA64_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+				case *image.RGBA64:
+					z.transform_RGBA64_RGBA64_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+				case image.RGBA64Image:
+					z.transform_RGBA64_RGBA64Image_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+				default:
+					z.transform_RGBA64_Image_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+				}
+			case *image.NRGBA64:
+				switch src := src.(type) {
+				case *image.NRGBA64:
+					z.transform_NRGBA64_NRGBA64_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+				case *image.RGBA64:
+					z.transform_NRGBA64_RGBA64_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+				case image.RGBA64Image:
+					z.transform_NRGBA64_RGBA64Image_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+				default:
+					z.transform_NRGBA64_Image_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+				}
+			case RGBA64Image:
+				switch src := src.(type) {
+				case image.RGBA64Image:
+					z.transform_RGBA64Image_RGBA64Image_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+				}
+			default:
+				switch src := src.(type) {
+				default:
+					z.transform_Image_Image_Src(dst, dr, adr, &d2s, src, sr, bias, &o)
+				}
+			}
+		}
+	}
+}
+
+func (ablInterpolator) scale_Alpha_Alpha_Over(dst *image.Alpha, dr, adr image.Rectangle, src *image.Alpha, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.Stride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+			s00au := uint32(src.Pix[s00i+0]) * 0x101
+			s00a := float64(s00au)
+			s10i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.Stride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+			s10au := uint32(src.Pix[s10i+0]) * 0x101
+			s10a := float64(s10au)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.Stride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+			s01au := uint32(src.Pix[s01i+0]) * 0x101
+			s01a := float64(s01au)
+			s11i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.Stride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+			s11au := uint32(src.Pix[s11i+0]) * 0x101
+			s11a := float64(s11au)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pa := uint32(s11a)
+			pa1 := (0xffff - pa) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pa) >> 8)
+		}
+	}
+}
+
+func (ablInterpolator) scale_Alpha_Alpha_Src(dst *image.Alpha, dr, adr image.Rectangle, src *image.Alpha, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.Stride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+			s00au := uint32(src.Pix[s00i+0]) * 0x101
+			s00a := float64(s00au)
+			s10i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.Stride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+			s10au := uint32(src.Pix[s10i+0]) * 0x101
+			s10a := float64(s10au)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.Stride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+			s01au := uint32(src.Pix[s01i+0]) * 0x101
+			s01a := float64(s01au)
+			s11i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.Stride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+			s11au := uint32(src.Pix[s11i+0]) * 0x101
+			s11a := float64(s11au)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pa := uint32(s11a)
+			dst.Pix[d+0] = uint8(pa >> 8)
+		}
+	}
+}
+
+func (ablInterpolator) scale_Alpha_RGBA64Image_Over(dst *image.Alpha, dr, adr image.Rectangle, src image.RGBA64Image, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00u := src.RGBA64At(sr.Min.X+int(sx0), sr.Min.Y+int(sy0))
+			s00a := float64(s00u.A)
+			s10u := src.RGBA64At(sr.Min.X+int(sx1), sr.Min.Y+int(sy0))
+			s10a := float64(s10u.A)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01u := src.RGBA64At(sr.Min.X+int(sx0), sr.Min.Y+int(sy1))
+			s01a := float64(s01u.A)
+			s11u := src.RGBA64At(sr.Min.X+int(sx1), sr.Min.Y+int(sy1))
+			s11a := float64(s11u.A)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			p := color.RGBA64{A: uint16(s11a)}
+			pa1 := (0xffff - uint32(p.A)) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + uint32(p.A)) >> 8)
+		}
+	}
+}
+
+func (ablInterpolator) scale_Alpha_RGBA64Image_Src(dst *image.Alpha, dr, adr image.Rectangle, src image.RGBA64Image, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00u := src.RGBA64At(sr.Min.X+int(sx0), sr.Min.Y+int(sy0))
+			s00a := float64(s00u.A)
+			s10u := src.RGBA64At(sr.Min.X+int(sx1), sr.Min.Y+int(sy0))
+			s10a := float64(s10u.A)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01u := src.RGBA64At(sr.Min.X+int(sx0), sr.Min.Y+int(sy1))
+			s01a := float64(s01u.A)
+			s11u := src.RGBA64At(sr.Min.X+int(sx1), sr.Min.Y+int(sy1))
+			s11a := float64(s11u.A)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			p := color.RGBA64{A: uint16(s11a)}
+			dst.Pix[d+0] = uint8(p.A >> 8)
+		}
+	}
+}
+
+func (ablInterpolator) scale_Alpha_Image_Over(dst *image.Alpha, dr, adr image.Rectangle, src image.Image, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			_, _, _, s00au := src.At(sr.Min.X+int(sx0), sr.Min.Y+int(sy0)).RGBA()
+			s00a := float64(s00au)
+			_, _, _, s10au := src.At(sr.Min.X+int(sx1), sr.Min.Y+int(sy0)).RGBA()
+			s10a := float64(s10au)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			_, _, _, s01au := src.At(sr.Min.X+int(sx0), sr.Min.Y+int(sy1)).RGBA()
+			s01a := float64(s01au)
+			_, _, _, s11au := src.At(sr.Min.X+int(sx1), sr.Min.Y+int(sy1)).RGBA()
+			s11a := float64(s11au)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pa := uint32(s11a)
+			pa1 := (0xffff - pa) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pa) >> 8)
+		}
+	}
+}
+
+func (ablInterpolator) scale_Alpha_Image_Src(dst *image.Alpha, dr, adr image.Rectangle, src image.Image, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			_, _, _, s00au := src.At(sr.Min.X+int(sx0), sr.Min.Y+int(sy0)).RGBA()
+			s00a := float64(s00au)
+			_, _, _, s10au := src.At(sr.Min.X+int(sx1), sr.Min.Y+int(sy0)).RGBA()
+			s10a := float64(s10au)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			_, _, _, s01au := src.At(sr.Min.X+int(sx0), sr.Min.Y+int(sy1)).RGBA()
+			s01a := float64(s01au)
+			_, _, _, s11au := src.At(sr.Min.X+int(sx1), sr.Min.Y+int(sy1)).RGBA()
+			s11a := float64(s11au)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pa := uint32(s11a)
+			dst.Pix[d+0] = uint8(pa >> 8)
+		}
+	}
+}
+
+func (ablInterpolator) scale_Gray_Gray_Src(dst *image.Gray, dr, adr image.Rectangle, src *image.Gray, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.Stride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+			s00ru := uint32(src.Pix[s00i]) * 0x101
+			s00r := float64(s00ru)
+			s10i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.Stride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+			s10ru := uint32(src.Pix[s10i]) * 0x101
+			s10r := float64(s10ru)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s01i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.Stride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+			s01ru := uint32(src.Pix[s01i]) * 0x101
+			s01r := float64(s01ru)
+			s11i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.Stride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+			s11ru := uint32(src.Pix[s11i]) * 0x101
+			s11r := float64(s11ru)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			pr := uint32(s11r)
+			dst.Pix[d] = uint8(pr >> 8)
+		}
+	}
+}
+
+func (ablInterpolator) scale_Gray_RGBA64Image_Over(dst *image.Gray, dr, adr image.Rectangle, src image.RGBA64Image, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00u := src.RGBA64At(sr.Min.X+int(sx0), sr.Min.Y+int(sy0))
+			s00r := float64(s00u.R)
+			s00g := float64(s00u.G)
+			s00b := float64(s00u.B)
+			s00a := float64(s00u.A)
+			s10u := src.RGBA64At(sr.Min.X+int(sx1), sr.Min.Y+int(sy0))
+			s10r := float64(s10u.R)
+			s10g := float64(s10u.G)
+			s10b := float64(s10u.B)
+			s10a := float64(s10u.A)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01u := src.RGBA64At(sr.Min.X+int(sx0), sr.Min.Y+int(sy1))
+			s01r := float64(s01u.R)
+			s01g := float64(s01u.G)
+			s01b := float64(s01u.B)
+			s01a := float64(s01u.A)
+			s11u := src.RGBA64At(sr.Min.X+int(sx1), sr.Min.Y+int(sy1))
+			s11r := float64(s11u.R)
+			s11g := float64(s11u.G)
+			s11b := float64(s11u.B)
+			s11a := float64(s11u.A)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			p := color.RGBA64{uint16(s11r), uint16(s11g), uint16(s11b), uint16(s11a)}
+			pa1 := 0xffff - uint32(p.A)
+			pq := uint32(dst.Pix[d]) * 0x101 * pa1 / 0xffff
+			dst.Pix[d] = uint8((19595*(pq+uint32(p.R)) + 38470*(pq+uint32(p.G)) + 7471*(pq+uint32(p.B)) + 1<<15) >> 24)
+		}
+	}
+}
+
+func (ablInterpolator) scale_Gray_RGBA64Image_Src(dst *image.Gray, dr, adr image.Rectangle, src image.RGBA64Image, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00u := src.RGBA64At(sr.Min.X+int(sx0), sr.Min.Y+int(sy0))
+			s00r := float64(s00u.R)
+			s00g := float64(s00u.G)
+			s00b := float64(s00u.B)
+			s00a := float64(s00u.A)
+			s10u := src.RGBA64At(sr.Min.X+int(sx1), sr.Min.Y+int(sy0))
+			s10r := float64(s10u.R)
+			s10g := float64(s10u.G)
+			s10b := float64(s10u.B)
+			s10a := float64(s10u.A)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01u := src.RGBA64At(sr.Min.X+int(sx0), sr.Min.Y+int(sy1))
+			s01r := float64(s01u.R)
+			s01g := float64(s01u.G)
+			s01b := float64(s01u.B)
+			s01a := float64(s01u.A)
+			s11u := src.RGBA64At(sr.Min.X+int(sx1), sr.Min.Y+int(sy1))
+			s11r := float64(s11u.R)
+			s11g := float64(s11u.G)
+			s11b := float64(s11u.B)
+			s11a := float64(s11u.A)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			p := color.RGBA64{uint16(s11r), uint16(s11g), uint16(s11b), uint16(s11a)}
+			dst.Pix[d] = uint8((19595*uint32(p.R) + 38470*uint32(p.G) + 7471*uint32(p.B) + 1<<15) >> 24)
+		}
+	}
+}
+
+func (ablInterpolator) scale_Gray_Image_Over(dst *image.Gray, dr, adr image.Rectangle, src image.Image, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00ru, s00gu, s00bu, s00au := src.At(sr.Min.X+int(sx0), sr.Min.Y+int(sy0)).RGBA()
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10ru, s10gu, s10bu, s10au := src.At(sr.Min.X+int(sx1), sr.Min.Y+int(sy0)).RGBA()
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01ru, s01gu, s01bu, s01au := src.At(sr.Min.X+int(sx0), sr.Min.Y+int(sy1)).RGBA()
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11ru, s11gu, s11bu, s11au := src.At(sr.Min.X+int(sx1), sr.Min.Y+int(sy1)).RGBA()
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			pa1 := 0xffff - pa
+			pq := uint32(dst.Pix[d]) * 0x101 * pa1 / 0xffff
+			dst.Pix[d] = uint8((19595*(pq+pr) + 38470*(pq+pg) + 7471*(pq+pb) + 1<<15) >> 24)
+		}
+	}
+}
+
+func (ablInterpolator) scale_Gray_Image_Src(dst *image.Gray, dr, adr image.Rectangle, src image.Image, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00ru, s00gu, s00bu, s00au := src.At(sr.Min.X+int(sx0), sr.Min.Y+int(sy0)).RGBA()
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10ru, s10gu, s10bu, s10au := src.At(sr.Min.X+int(sx1), sr.Min.Y+int(sy0)).RGBA()
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01ru, s01gu, s01bu, s01au := src.At(sr.Min.X+int(sx0), sr.Min.Y+int(sy1)).RGBA()
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11ru, s11gu, s11bu, s11au := src.At(sr.Min.X+int(sx1), sr.Min.Y+int(sy1)).RGBA()
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			dst.Pix[d] = uint8((19595*pr + 38470*pg + 7471*pb + 1<<15) >> 24)
+		}
+	}
+}
+
+func (ablInterpolator) scale_RGBA_Gray_Src(dst *image.RGBA, dr, adr image.Rectangle, src *image.Gray, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.Stride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+			s00ru := uint32(src.Pix[s00i]) * 0x101
+			s00r := float64(s00ru)
+			s10i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.Stride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+			s10ru := uint32(src.Pix[s10i]) * 0x101
+			s10r := float64(s10ru)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s01i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.Stride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+			s01ru := uint32(src.Pix[s01i]) * 0x101
+			s01r := float64(s01ru)
+			s11i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.Stride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+			s11ru := uint32(src.Pix[s11i]) * 0x101
+			s11r := float64(s11ru)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			pr := uint32(s11r)
+			out := uint8(pr >> 8)
+			dst.Pix[d+0] = out
+			dst.Pix[d+1] = out
+			dst.Pix[d+2] = out
+			dst.Pix[d+3] = 0xff
+		}
+	}
+}
+
+func (ablInterpolator) scale_RGBA_NRGBA_Over(dst *image.RGBA, dr, adr image.Rectangle, src *image.NRGBA, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx0)-src.Rect.Min.X)*4
+			s00au := uint32(src.Pix[s00i+3]) * 0x101
+			s00ru := uint32(src.Pix[s00i+0]) * s00au / 0xff
+			s00gu := uint32(src.Pix[s00i+1]) * s00au / 0xff
+			s00bu := uint32(src.Pix[s00i+2]) * s00au / 0xff
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx1)-src.Rect.Min.X)*4
+			s10au := uint32(src.Pix[s10i+3]) * 0x101
+			s10ru := uint32(src.Pix[s10i+0]) * s10au / 0xff
+			s10gu := uint32(src.Pix[s10i+1]) * s10au / 0xff
+			s10bu := uint32(src.Pix[s10i+2]) * s10au / 0xff
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx0)-src.Rect.Min.X)*4
+			s01au := uint32(src.Pix[s01i+3]) * 0x101
+			s01ru := uint32(src.Pix[s01i+0]) * s01au / 0xff
+			s01gu := uint32(src.Pix[s01i+1]) * s01au / 0xff
+			s01bu := uint32(src.Pix[s01i+2]) * s01au / 0xff
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx1)-src.Rect.Min.X)*4
+			s11au := uint32(src.Pix[s11i+3]) * 0x101
+			s11ru := uint32(src.Pix[s11i+0]) * s11au / 0xff
+			s11gu := uint32(src.Pix[s11i+1]) * s11au / 0xff
+			s11bu := uint32(src.Pix[s11i+2]) * s11au / 0xff
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			pa1 := (0xffff - pa) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pr) >> 8)
+			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + pg) >> 8)
+			dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*pa1/0xffff + pb) >> 8)
+			dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*pa1/0xffff + pa) >> 8)
+		}
+	}
+}
+
+func (ablInterpolator) scale_RGBA_NRGBA_Src(dst *image.RGBA, dr, adr image.Rectangle, src *image.NRGBA, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx0)-src.Rect.Min.X)*4
+			s00au := uint32(src.Pix[s00i+3]) * 0x101
+			s00ru := uint32(src.Pix[s00i+0]) * s00au / 0xff
+			s00gu := uint32(src.Pix[s00i+1]) * s00au / 0xff
+			s00bu := uint32(src.Pix[s00i+2]) * s00au / 0xff
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx1)-src.Rect.Min.X)*4
+			s10au := uint32(src.Pix[s10i+3]) * 0x101
+			s10ru := uint32(src.Pix[s10i+0]) * s10au / 0xff
+			s10gu := uint32(src.Pix[s10i+1]) * s10au / 0xff
+			s10bu := uint32(src.Pix[s10i+2]) * s10au / 0xff
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx0)-src.Rect.Min.X)*4
+			s01au := uint32(src.Pix[s01i+3]) * 0x101
+			s01ru := uint32(src.Pix[s01i+0]) * s01au / 0xff
+			s01gu := uint32(src.Pix[s01i+1]) * s01au / 0xff
+			s01bu := uint32(src.Pix[s01i+2]) * s01au / 0xff
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx1)-src.Rect.Min.X)*4
+			s11au := uint32(src.Pix[s11i+3]) * 0x101
+			s11ru := uint32(src.Pix[s11i+0]) * s11au / 0xff
+			s11gu := uint32(src.Pix[s11i+1]) * s11au / 0xff
+			s11bu := uint32(src.Pix[s11i+2]) * s11au / 0xff
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			dst.Pix[d+0] = uint8(pr >> 8)
+			dst.Pix[d+1] = uint8(pg >> 8)
+			dst.Pix[d+2] = uint8(pb >> 8)
+			dst.Pix[d+3] = uint8(pa >> 8)
+		}
+	}
+}
+
+func (ablInterpolator) scale_RGBA_RGBA_Over(dst *image.RGBA, dr, adr image.Rectangle, src *image.RGBA, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx0)-src.Rect.Min.X)*4
+			s00ru := uint32(src.Pix[s00i+0]) * 0x101
+			s00gu := uint32(src.Pix[s00i+1]) * 0x101
+			s00bu := uint32(src.Pix[s00i+2]) * 0x101
+			s00au := uint32(src.Pix[s00i+3]) * 0x101
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx1)-src.Rect.Min.X)*4
+			s10ru := uint32(src.Pix[s10i+0]) * 0x101
+			s10gu := uint32(src.Pix[s10i+1]) * 0x101
+			s10bu := uint32(src.Pix[s10i+2]) * 0x101
+			s10au := uint32(src.Pix[s10i+3]) * 0x101
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx0)-src.Rect.Min.X)*4
+			s01ru := uint32(src.Pix[s01i+0]) * 0x101
+			s01gu := uint32(src.Pix[s01i+1]) * 0x101
+			s01bu := uint32(src.Pix[s01i+2]) * 0x101
+			s01au := uint32(src.Pix[s01i+3]) * 0x101
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx1)-src.Rect.Min.X)*4
+			s11ru := uint32(src.Pix[s11i+0]) * 0x101
+			s11gu := uint32(src.Pix[s11i+1]) * 0x101
+			s11bu := uint32(src.Pix[s11i+2]) * 0x101
+			s11au := uint32(src.Pix[s11i+3]) * 0x101
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			pa1 := (0xffff - pa) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pr) >> 8)
+			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + pg) >> 8)
+			dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*pa1/0xffff + pb) >> 8)
+			dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*pa1/0xffff + pa) >> 8)
+		}
+	}
+}
+
+func (ablInterpolator) scale_RGBA_RGBA_Src(dst *image.RGBA, dr, adr image.Rectangle, src *image.RGBA, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx0)-src.Rect.Min.X)*4
+			s00ru := uint32(src.Pix[s00i+0]) * 0x101
+			s00gu := uint32(src.Pix[s00i+1]) * 0x101
+			s00bu := uint32(src.Pix[s00i+2]) * 0x101
+			s00au := uint32(src.Pix[s00i+3]) * 0x101
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx1)-src.Rect.Min.X)*4
+			s10ru := uint32(src.Pix[s10i+0]) * 0x101
+			s10gu := uint32(src.Pix[s10i+1]) * 0x101
+			s10bu := uint32(src.Pix[s10i+2]) * 0x101
+			s10au := uint32(src.Pix[s10i+3]) * 0x101
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx0)-src.Rect.Min.X)*4
+			s01ru := uint32(src.Pix[s01i+0]) * 0x101
+			s01gu := uint32(src.Pix[s01i+1]) * 0x101
+			s01bu := uint32(src.Pix[s01i+2]) * 0x101
+			s01au := uint32(src.Pix[s01i+3]) * 0x101
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx1)-src.Rect.Min.X)*4
+			s11ru := uint32(src.Pix[s11i+0]) * 0x101
+			s11gu := uint32(src.Pix[s11i+1]) * 0x101
+			s11bu := uint32(src.Pix[s11i+2]) * 0x101
+			s11au := uint32(src.Pix[s11i+3]) * 0x101
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			dst.Pix[d+0] = uint8(pr >> 8)
+			dst.Pix[d+1] = uint8(pg >> 8)
+			dst.Pix[d+2] = uint8(pb >> 8)
+			dst.Pix[d+3] = uint8(pa >> 8)
+		}
+	}
+}
+
+func (ablInterpolator) scale_RGBA_YCbCr444_Src(dst *image.RGBA, dr, adr image.Rectangle, src *image.YCbCr, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+			s00j := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.CStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s00yy1 := int(src.Y[s00i]) * 0x10101
+			s00cb1 := int(src.Cb[s00j]) - 128
+			s00cr1 := int(src.Cr[s00j]) - 128
+			s00ru := (s00yy1 + 91881*s00cr1) >> 8
+			s00gu := (s00yy1 - 22554*s00cb1 - 46802*s00cr1) >> 8
+			s00bu := (s00yy1 + 116130*s00cb1) >> 8
+			if s00ru < 0 {
+				s00ru = 0
+			} else if s00ru > 0xffff {
+				s00ru = 0xffff
+			}
+			if s00gu < 0 {
+				s00gu = 0
+			} else if s00gu > 0xffff {
+				s00gu = 0xffff
+			}
+			if s00bu < 0 {
+				s00bu = 0
+			} else if s00bu > 0xffff {
+				s00bu = 0xffff
+			}
+
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s10i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+			s10j := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.CStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s10yy1 := int(src.Y[s10i]) * 0x10101
+			s10cb1 := int(src.Cb[s10j]) - 128
+			s10cr1 := int(src.Cr[s10j]) - 128
+			s10ru := (s10yy1 + 91881*s10cr1) >> 8
+			s10gu := (s10yy1 - 22554*s10cb1 - 46802*s10cr1) >> 8
+			s10bu := (s10yy1 + 116130*s10cb1) >> 8
+			if s10ru < 0 {
+				s10ru = 0
+			} else if s10ru > 0xffff {
+				s10ru = 0xffff
+			}
+			if s10gu < 0 {
+				s10gu = 0
+			} else if s10gu > 0xffff {
+				s10gu = 0xffff
+			}
+			if s10bu < 0 {
+				s10bu = 0
+			} else if s10bu > 0xffff {
+				s10bu = 0xffff
+			}
+
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s01i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+			s01j := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.CStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s01yy1 := int(src.Y[s01i]) * 0x10101
+			s01cb1 := int(src.Cb[s01j]) - 128
+			s01cr1 := int(src.Cr[s01j]) - 128
+			s01ru := (s01yy1 + 91881*s01cr1) >> 8
+			s01gu := (s01yy1 - 22554*s01cb1 - 46802*s01cr1) >> 8
+			s01bu := (s01yy1 + 116130*s01cb1) >> 8
+			if s01ru < 0 {
+				s01ru = 0
+			} else if s01ru > 0xffff {
+				s01ru = 0xffff
+			}
+			if s01gu < 0 {
+				s01gu = 0
+			} else if s01gu > 0xffff {
+				s01gu = 0xffff
+			}
+			if s01bu < 0 {
+				s01bu = 0
+			} else if s01bu > 0xffff {
+				s01bu = 0xffff
+			}
+
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s11i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+			s11j := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.CStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s11yy1 := int(src.Y[s11i]) * 0x10101
+			s11cb1 := int(src.Cb[s11j]) - 128
+			s11cr1 := int(src.Cr[s11j]) - 128
+			s11ru := (s11yy1 + 91881*s11cr1) >> 8
+			s11gu := (s11yy1 - 22554*s11cb1 - 46802*s11cr1) >> 8
+			s11bu := (s11yy1 + 116130*s11cb1) >> 8
+			if s11ru < 0 {
+				s11ru = 0
+			} else if s11ru > 0xffff {
+				s11ru = 0xffff
+			}
+			if s11gu < 0 {
+				s11gu = 0
+			} else if s11gu > 0xffff {
+				s11gu = 0xffff
+			}
+			if s11bu < 0 {
+				s11bu = 0
+			} else if s11bu > 0xffff {
+				s11bu = 0xffff
+			}
+
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			dst.Pix[d+0] = uint8(pr >> 8)
+			dst.Pix[d+1] = uint8(pg >> 8)
+			dst.Pix[d+2] = uint8(pb >> 8)
+			dst.Pix[d+3] = 0xff
+		}
+	}
+}
+
+func (ablInterpolator) scale_RGBA_YCbCr422_Src(dst *image.RGBA, dr, adr image.Rectangle, src *image.YCbCr, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+			s00j := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.CStride + ((sr.Min.X+int(sx0))/2 - src.Rect.Min.X/2)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s00yy1 := int(src.Y[s00i]) * 0x10101
+			s00cb1 := int(src.Cb[s00j]) - 128
+			s00cr1 := int(src.Cr[s00j]) - 128
+			s00ru := (s00yy1 + 91881*s00cr1) >> 8
+			s00gu := (s00yy1 - 22554*s00cb1 - 46802*s00cr1) >> 8
+			s00bu := (s00yy1 + 116130*s00cb1) >> 8
+			if s00ru < 0 {
+				s00ru = 0
+			} else if s00ru > 0xffff {
+				s00ru = 0xffff
+			}
+			if s00gu < 0 {
+				s00gu = 0
+			} else if s00gu > 0xffff {
+				s00gu = 0xffff
+			}
+			if s00bu < 0 {
+				s00bu = 0
+			} else if s00bu > 0xffff {
+				s00bu = 0xffff
+			}
+
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s10i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+			s10j := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.CStride + ((sr.Min.X+int(sx1))/2 - src.Rect.Min.X/2)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s10yy1 := int(src.Y[s10i]) * 0x10101
+			s10cb1 := int(src.Cb[s10j]) - 128
+			s10cr1 := int(src.Cr[s10j]) - 128
+			s10ru := (s10yy1 + 91881*s10cr1) >> 8
+			s10gu := (s10yy1 - 22554*s10cb1 - 46802*s10cr1) >> 8
+			s10bu := (s10yy1 + 116130*s10cb1) >> 8
+			if s10ru < 0 {
+				s10ru = 0
+			} else if s10ru > 0xffff {
+				s10ru = 0xffff
+			}
+			if s10gu < 0 {
+				s10gu = 0
+			} else if s10gu > 0xffff {
+				s10gu = 0xffff
+			}
+			if s10bu < 0 {
+				s10bu = 0
+			} else if s10bu > 0xffff {
+				s10bu = 0xffff
+			}
+
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s01i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+			s01j := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.CStride + ((sr.Min.X+int(sx0))/2 - src.Rect.Min.X/2)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s01yy1 := int(src.Y[s01i]) * 0x10101
+			s01cb1 := int(src.Cb[s01j]) - 128
+			s01cr1 := int(src.Cr[s01j]) - 128
+			s01ru := (s01yy1 + 91881*s01cr1) >> 8
+			s01gu := (s01yy1 - 22554*s01cb1 - 46802*s01cr1) >> 8
+			s01bu := (s01yy1 + 116130*s01cb1) >> 8
+			if s01ru < 0 {
+				s01ru = 0
+			} else if s01ru > 0xffff {
+				s01ru = 0xffff
+			}
+			if s01gu < 0 {
+				s01gu = 0
+			} else if s01gu > 0xffff {
+				s01gu = 0xffff
+			}
+			if s01bu < 0 {
+				s01bu = 0
+			} else if s01bu > 0xffff {
+				s01bu = 0xffff
+			}
+
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s11i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+			s11j := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.CStride + ((sr.Min.X+int(sx1))/2 - src.Rect.Min.X/2)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s11yy1 := int(src.Y[s11i]) * 0x10101
+			s11cb1 := int(src.Cb[s11j]) - 128
+			s11cr1 := int(src.Cr[s11j]) - 128
+			s11ru := (s11yy1 + 91881*s11cr1) >> 8
+			s11gu := (s11yy1 - 22554*s11cb1 - 46802*s11cr1) >> 8
+			s11bu := (s11yy1 + 116130*s11cb1) >> 8
+			if s11ru < 0 {
+				s11ru = 0
+			} else if s11ru > 0xffff {
+				s11ru = 0xffff
+			}
+			if s11gu < 0 {
+				s11gu = 0
+			} else if s11gu > 0xffff {
+				s11gu = 0xffff
+			}
+			if s11bu < 0 {
+				s11bu = 0
+			} else if s11bu > 0xffff {
+				s11bu = 0xffff
+			}
+
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			dst.Pix[d+0] = uint8(pr >> 8)
+			dst.Pix[d+1] = uint8(pg >> 8)
+			dst.Pix[d+2] = uint8(pb >> 8)
+			dst.Pix[d+3] = 0xff
+		}
+	}
+}
+
+func (ablInterpolator) scale_RGBA_YCbCr420_Src(dst *image.RGBA, dr, adr image.Rectangle, src *image.YCbCr, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+			s00j := ((sr.Min.Y+int(sy0))/2-src.Rect.Min.Y/2)*src.CStride + ((sr.Min.X+int(sx0))/2 - src.Rect.Min.X/2)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s00yy1 := int(src.Y[s00i]) * 0x10101
+			s00cb1 := int(src.Cb[s00j]) - 128
+			s00cr1 := int(src.Cr[s00j]) - 128
+			s00ru := (s00yy1 + 91881*s00cr1) >> 8
+			s00gu := (s00yy1 - 22554*s00cb1 - 46802*s00cr1) >> 8
+			s00bu := (s00yy1 + 116130*s00cb1) >> 8
+			if s00ru < 0 {
+				s00ru = 0
+			} else if s00ru > 0xffff {
+				s00ru = 0xffff
+			}
+			if s00gu < 0 {
+				s00gu = 0
+			} else if s00gu > 0xffff {
+				s00gu = 0xffff
+			}
+			if s00bu < 0 {
+				s00bu = 0
+			} else if s00bu > 0xffff {
+				s00bu = 0xffff
+			}
+
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s10i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+			s10j := ((sr.Min.Y+int(sy0))/2-src.Rect.Min.Y/2)*src.CStride + ((sr.Min.X+int(sx1))/2 - src.Rect.Min.X/2)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s10yy1 := int(src.Y[s10i]) * 0x10101
+			s10cb1 := int(src.Cb[s10j]) - 128
+			s10cr1 := int(src.Cr[s10j]) - 128
+			s10ru := (s10yy1 + 91881*s10cr1) >> 8
+			s10gu := (s10yy1 - 22554*s10cb1 - 46802*s10cr1) >> 8
+			s10bu := (s10yy1 + 116130*s10cb1) >> 8
+			if s10ru < 0 {
+				s10ru = 0
+			} else if s10ru > 0xffff {
+				s10ru = 0xffff
+			}
+			if s10gu < 0 {
+				s10gu = 0
+			} else if s10gu > 0xffff {
+				s10gu = 0xffff
+			}
+			if s10bu < 0 {
+				s10bu = 0
+			} else if s10bu > 0xffff {
+				s10bu = 0xffff
+			}
+
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s01i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+			s01j := ((sr.Min.Y+int(sy1))/2-src.Rect.Min.Y/2)*src.CStride + ((sr.Min.X+int(sx0))/2 - src.Rect.Min.X/2)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s01yy1 := int(src.Y[s01i]) * 0x10101
+			s01cb1 := int(src.Cb[s01j]) - 128
+			s01cr1 := int(src.Cr[s01j]) - 128
+			s01ru := (s01yy1 + 91881*s01cr1) >> 8
+			s01gu := (s01yy1 - 22554*s01cb1 - 46802*s01cr1) >> 8
+			s01bu := (s01yy1 + 116130*s01cb1) >> 8
+			if s01ru < 0 {
+				s01ru = 0
+			} else if s01ru > 0xffff {
+				s01ru = 0xffff
+			}
+			if s01gu < 0 {
+				s01gu = 0
+			} else if s01gu > 0xffff {
+				s01gu = 0xffff
+			}
+			if s01bu < 0 {
+				s01bu = 0
+			} else if s01bu > 0xffff {
+				s01bu = 0xffff
+			}
+
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s11i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+			s11j := ((sr.Min.Y+int(sy1))/2-src.Rect.Min.Y/2)*src.CStride + ((sr.Min.X+int(sx1))/2 - src.Rect.Min.X/2)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s11yy1 := int(src.Y[s11i]) * 0x10101
+			s11cb1 := int(src.Cb[s11j]) - 128
+			s11cr1 := int(src.Cr[s11j]) - 128
+			s11ru := (s11yy1 + 91881*s11cr1) >> 8
+			s11gu := (s11yy1 - 22554*s11cb1 - 46802*s11cr1) >> 8
+			s11bu := (s11yy1 + 116130*s11cb1) >> 8
+			if s11ru < 0 {
+				s11ru = 0
+			} else if s11ru > 0xffff {
+				s11ru = 0xffff
+			}
+			if s11gu < 0 {
+				s11gu = 0
+			} else if s11gu > 0xffff {
+				s11gu = 0xffff
+			}
+			if s11bu < 0 {
+				s11bu = 0
+			} else if s11bu > 0xffff {
+				s11bu = 0xffff
+			}
+
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			dst.Pix[d+0] = uint8(pr >> 8)
+			dst.Pix[d+1] = uint8(pg >> 8)
+			dst.Pix[d+2] = uint8(pb >> 8)
+			dst.Pix[d+3] = 0xff
+		}
+	}
+}
+
+func (ablInterpolator) scale_RGBA_YCbCr440_Src(dst *image.RGBA, dr, adr image.Rectangle, src *image.YCbCr, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+			s00j := ((sr.Min.Y+int(sy0))/2-src.Rect.Min.Y/2)*src.CStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s00yy1 := int(src.Y[s00i]) * 0x10101
+			s00cb1 := int(src.Cb[s00j]) - 128
+			s00cr1 := int(src.Cr[s00j]) - 128
+			s00ru := (s00yy1 + 91881*s00cr1) >> 8
+			s00gu := (s00yy1 - 22554*s00cb1 - 46802*s00cr1) >> 8
+			s00bu := (s00yy1 + 116130*s00cb1) >> 8
+			if s00ru < 0 {
+				s00ru = 0
+			} else if s00ru > 0xffff {
+				s00ru = 0xffff
+			}
+			if s00gu < 0 {
+				s00gu = 0
+			} else if s00gu > 0xffff {
+				s00gu = 0xffff
+			}
+			if s00bu < 0 {
+				s00bu = 0
+			} else if s00bu > 0xffff {
+				s00bu = 0xffff
+			}
+
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s10i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+			s10j := ((sr.Min.Y+int(sy0))/2-src.Rect.Min.Y/2)*src.CStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s10yy1 := int(src.Y[s10i]) * 0x10101
+			s10cb1 := int(src.Cb[s10j]) - 128
+			s10cr1 := int(src.Cr[s10j]) - 128
+			s10ru := (s10yy1 + 91881*s10cr1) >> 8
+			s10gu := (s10yy1 - 22554*s10cb1 - 46802*s10cr1) >> 8
+			s10bu := (s10yy1 + 116130*s10cb1) >> 8
+			if s10ru < 0 {
+				s10ru = 0
+			} else if s10ru > 0xffff {
+				s10ru = 0xffff
+			}
+			if s10gu < 0 {
+				s10gu = 0
+			} else if s10gu > 0xffff {
+				s10gu = 0xffff
+			}
+			if s10bu < 0 {
+				s10bu = 0
+			} else if s10bu > 0xffff {
+				s10bu = 0xffff
+			}
+
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s01i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+			s01j := ((sr.Min.Y+int(sy1))/2-src.Rect.Min.Y/2)*src.CStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s01yy1 := int(src.Y[s01i]) * 0x10101
+			s01cb1 := int(src.Cb[s01j]) - 128
+			s01cr1 := int(src.Cr[s01j]) - 128
+			s01ru := (s01yy1 + 91881*s01cr1) >> 8
+			s01gu := (s01yy1 - 22554*s01cb1 - 46802*s01cr1) >> 8
+			s01bu := (s01yy1 + 116130*s01cb1) >> 8
+			if s01ru < 0 {
+				s01ru = 0
+			} else if s01ru > 0xffff {
+				s01ru = 0xffff
+			}
+			if s01gu < 0 {
+				s01gu = 0
+			} else if s01gu > 0xffff {
+				s01gu = 0xffff
+			}
+			if s01bu < 0 {
+				s01bu = 0
+			} else if s01bu > 0xffff {
+				s01bu = 0xffff
+			}
+
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s11i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+			s11j := ((sr.Min.Y+int(sy1))/2-src.Rect.Min.Y/2)*src.CStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s11yy1 := int(src.Y[s11i]) * 0x10101
+			s11cb1 := int(src.Cb[s11j]) - 128
+			s11cr1 := int(src.Cr[s11j]) - 128
+			s11ru := (s11yy1 + 91881*s11cr1) >> 8
+			s11gu := (s11yy1 - 22554*s11cb1 - 46802*s11cr1) >> 8
+			s11bu := (s11yy1 + 116130*s11cb1) >> 8
+			if s11ru < 0 {
+				s11ru = 0
+			} else if s11ru > 0xffff {
+				s11ru = 0xffff
+			}
+			if s11gu < 0 {
+				s11gu = 0
+			} else if s11gu > 0xffff {
+				s11gu = 0xffff
+			}
+			if s11bu < 0 {
+				s11bu = 0
+			} else if s11bu > 0xffff {
+				s11bu = 0xffff
+			}
+
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			dst.Pix[d+0] = uint8(pr >> 8)
+			dst.Pix[d+1] = uint8(pg >> 8)
+			dst.Pix[d+2] = uint8(pb >> 8)
+			dst.Pix[d+3] = 0xff
+		}
+	}
+}
+
+func (ablInterpolator) scale_RGBA_NYCbCrA444_Over(dst *image.RGBA, dr, adr image.Rectangle, src *image.NYCbCrA, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+			s00j := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.CStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+			s00k := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.AStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s00yy1 := int(src.Y[s00i]) * 0x10101
+			s00cb1 := int(src.Cb[s00j]) - 128
+			s00cr1 := int(src.Cr[s00j]) - 128
+			s00ru0 := (s00yy1 + 91881*s00cr1) >> 8
+			s00gu0 := (s00yy1 - 22554*s00cb1 - 46802*s00cr1) >> 8
+			s00bu0 := (s00yy1 + 116130*s00cb1) >> 8
+			if s00ru0 < 0 {
+				s00ru0 = 0
+			} else if s00ru0 > 0xffff {
+				s00ru0 = 0xffff
+			}
+			if s00gu0 < 0 {
+				s00gu0 = 0
+			} else if s00gu0 > 0xffff {
+				s00gu0 = 0xffff
+			}
+			if s00bu0 < 0 {
+				s00bu0 = 0
+			} else if s00bu0 > 0xffff {
+				s00bu0 = 0xffff
+			}
+
+			s00au := uint32(src.A[s00k]) * 0x101
+			s00ru := uint32(s00ru0) * s00au / 0xffff
+			s00gu := uint32(s00gu0) * s00au / 0xffff
+			s00bu := uint32(s00bu0) * s00au / 0xffff
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+			s10j := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.CStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+			s10k := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.AStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s10yy1 := int(src.Y[s10i]) * 0x10101
+			s10cb1 := int(src.Cb[s10j]) - 128
+			s10cr1 := int(src.Cr[s10j]) - 128
+			s10ru0 := (s10yy1 + 91881*s10cr1) >> 8
+			s10gu0 := (s10yy1 - 22554*s10cb1 - 46802*s10cr1) >> 8
+			s10bu0 := (s10yy1 + 116130*s10cb1) >> 8
+			if s10ru0 < 0 {
+				s10ru0 = 0
+			} else if s10ru0 > 0xffff {
+				s10ru0 = 0xffff
+			}
+			if s10gu0 < 0 {
+				s10gu0 = 0
+			} else if s10gu0 > 0xffff {
+				s10gu0 = 0xffff
+			}
+			if s10bu0 < 0 {
+				s10bu0 = 0
+			} else if s10bu0 > 0xffff {
+				s10bu0 = 0xffff
+			}
+
+			s10au := uint32(src.A[s10k]) * 0x101
+			s10ru := uint32(s10ru0) * s10au / 0xffff
+			s10gu := uint32(s10gu0) * s10au / 0xffff
+			s10bu := uint32(s10bu0) * s10au / 0xffff
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+			s01j := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.CStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+			s01k := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.AStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s01yy1 := int(src.Y[s01i]) * 0x10101
+			s01cb1 := int(src.Cb[s01j]) - 128
+			s01cr1 := int(src.Cr[s01j]) - 128
+			s01ru0 := (s01yy1 + 91881*s01cr1) >> 8
+			s01gu0 := (s01yy1 - 22554*s01cb1 - 46802*s01cr1) >> 8
+			s01bu0 := (s01yy1 + 116130*s01cb1) >> 8
+			if s01ru0 < 0 {
+				s01ru0 = 0
+			} else if s01ru0 > 0xffff {
+				s01ru0 = 0xffff
+			}
+			if s01gu0 < 0 {
+				s01gu0 = 0
+			} else if s01gu0 > 0xffff {
+				s01gu0 = 0xffff
+			}
+			if s01bu0 < 0 {
+				s01bu0 = 0
+			} else if s01bu0 > 0xffff {
+				s01bu0 = 0xffff
+			}
+
+			s01au := uint32(src.A[s01k]) * 0x101
+			s01ru := uint32(s01ru0) * s01au / 0xffff
+			s01gu := uint32(s01gu0) * s01au / 0xffff
+			s01bu := uint32(s01bu0) * s01au / 0xffff
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+			s11j := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.CStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+			s11k := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.AStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s11yy1 := int(src.Y[s11i]) * 0x10101
+			s11cb1 := int(src.Cb[s11j]) - 128
+			s11cr1 := int(src.Cr[s11j]) - 128
+			s11ru0 := (s11yy1 + 91881*s11cr1) >> 8
+			s11gu0 := (s11yy1 - 22554*s11cb1 - 46802*s11cr1) >> 8
+			s11bu0 := (s11yy1 + 116130*s11cb1) >> 8
+			if s11ru0 < 0 {
+				s11ru0 = 0
+			} else if s11ru0 > 0xffff {
+				s11ru0 = 0xffff
+			}
+			if s11gu0 < 0 {
+				s11gu0 = 0
+			} else if s11gu0 > 0xffff {
+				s11gu0 = 0xffff
+			}
+			if s11bu0 < 0 {
+				s11bu0 = 0
+			} else if s11bu0 > 0xffff {
+				s11bu0 = 0xffff
+			}
+
+			s11au := uint32(src.A[s11k]) * 0x101
+			s11ru := uint32(s11ru0) * s11au / 0xffff
+			s11gu := uint32(s11gu0) * s11au / 0xffff
+			s11bu := uint32(s11bu0) * s11au / 0xffff
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			pa1 := (0xffff - pa) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pr) >> 8)
+			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + pg) >> 8)
+			dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*pa1/0xffff + pb) >> 8)
+			dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*pa1/0xffff + pa) >> 8)
+		}
+	}
+}
+
+func (ablInterpolator) scale_RGBA_NYCbCrA422_Over(dst *image.RGBA, dr, adr image.Rectangle, src *image.NYCbCrA, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+			s00j := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.CStride + ((sr.Min.X+int(sx0))/2 - src.Rect.Min.X/2)
+			s00k := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.AStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s00yy1 := int(src.Y[s00i]) * 0x10101
+			s00cb1 := int(src.Cb[s00j]) - 128
+			s00cr1 := int(src.Cr[s00j]) - 128
+			s00ru0 := (s00yy1 + 91881*s00cr1) >> 8
+			s00gu0 := (s00yy1 - 22554*s00cb1 - 46802*s00cr1) >> 8
+			s00bu0 := (s00yy1 + 116130*s00cb1) >> 8
+			if s00ru0 < 0 {
+				s00ru0 = 0
+			} else if s00ru0 > 0xffff {
+				s00ru0 = 0xffff
+			}
+			if s00gu0 < 0 {
+				s00gu0 = 0
+			} else if s00gu0 > 0xffff {
+				s00gu0 = 0xffff
+			}
+			if s00bu0 < 0 {
+				s00bu0 = 0
+			} else if s00bu0 > 0xffff {
+				s00bu0 = 0xffff
+			}
+
+			s00au := uint32(src.A[s00k]) * 0x101
+			s00ru := uint32(s00ru0) * s00au / 0xffff
+			s00gu := uint32(s00gu0) * s00au / 0xffff
+			s00bu := uint32(s00bu0) * s00au / 0xffff
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+			s10j := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.CStride + ((sr.Min.X+int(sx1))/2 - src.Rect.Min.X/2)
+			s10k := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.AStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s10yy1 := int(src.Y[s10i]) * 0x10101
+			s10cb1 := int(src.Cb[s10j]) - 128
+			s10cr1 := int(src.Cr[s10j]) - 128
+			s10ru0 := (s10yy1 + 91881*s10cr1) >> 8
+			s10gu0 := (s10yy1 - 22554*s10cb1 - 46802*s10cr1) >> 8
+			s10bu0 := (s10yy1 + 116130*s10cb1) >> 8
+			if s10ru0 < 0 {
+				s10ru0 = 0
+			} else if s10ru0 > 0xffff {
+				s10ru0 = 0xffff
+			}
+			if s10gu0 < 0 {
+				s10gu0 = 0
+			} else if s10gu0 > 0xffff {
+				s10gu0 = 0xffff
+			}
+			if s10bu0 < 0 {
+				s10bu0 = 0
+			} else if s10bu0 > 0xffff {
+				s10bu0 = 0xffff
+			}
+
+			s10au := uint32(src.A[s10k]) * 0x101
+			s10ru := uint32(s10ru0) * s10au / 0xffff
+			s10gu := uint32(s10gu0) * s10au / 0xffff
+			s10bu := uint32(s10bu0) * s10au / 0xffff
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+			s01j := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.CStride + ((sr.Min.X+int(sx0))/2 - src.Rect.Min.X/2)
+			s01k := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.AStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s01yy1 := int(src.Y[s01i]) * 0x10101
+			s01cb1 := int(src.Cb[s01j]) - 128
+			s01cr1 := int(src.Cr[s01j]) - 128
+			s01ru0 := (s01yy1 + 91881*s01cr1) >> 8
+			s01gu0 := (s01yy1 - 22554*s01cb1 - 46802*s01cr1) >> 8
+			s01bu0 := (s01yy1 + 116130*s01cb1) >> 8
+			if s01ru0 < 0 {
+				s01ru0 = 0
+			} else if s01ru0 > 0xffff {
+				s01ru0 = 0xffff
+			}
+			if s01gu0 < 0 {
+				s01gu0 = 0
+			} else if s01gu0 > 0xffff {
+				s01gu0 = 0xffff
+			}
+			if s01bu0 < 0 {
+				s01bu0 = 0
+			} else if s01bu0 > 0xffff {
+				s01bu0 = 0xffff
+			}
+
+			s01au := uint32(src.A[s01k]) * 0x101
+			s01ru := uint32(s01ru0) * s01au / 0xffff
+			s01gu := uint32(s01gu0) * s01au / 0xffff
+			s01bu := uint32(s01bu0) * s01au / 0xffff
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+			s11j := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.CStride + ((sr.Min.X+int(sx1))/2 - src.Rect.Min.X/2)
+			s11k := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.AStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s11yy1 := int(src.Y[s11i]) * 0x10101
+			s11cb1 := int(src.Cb[s11j]) - 128
+			s11cr1 := int(src.Cr[s11j]) - 128
+			s11ru0 := (s11yy1 + 91881*s11cr1) >> 8
+			s11gu0 := (s11yy1 - 22554*s11cb1 - 46802*s11cr1) >> 8
+			s11bu0 := (s11yy1 + 116130*s11cb1) >> 8
+			if s11ru0 < 0 {
+				s11ru0 = 0
+			} else if s11ru0 > 0xffff {
+				s11ru0 = 0xffff
+			}
+			if s11gu0 < 0 {
+				s11gu0 = 0
+			} else if s11gu0 > 0xffff {
+				s11gu0 = 0xffff
+			}
+			if s11bu0 < 0 {
+				s11bu0 = 0
+			} else if s11bu0 > 0xffff {
+				s11bu0 = 0xffff
+			}
+
+			s11au := uint32(src.A[s11k]) * 0x101
+			s11ru := uint32(s11ru0) * s11au / 0xffff
+			s11gu := uint32(s11gu0) * s11au / 0xffff
+			s11bu := uint32(s11bu0) * s11au / 0xffff
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			pa1 := (0xffff - pa) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pr) >> 8)
+			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + pg) >> 8)
+			dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*pa1/0xffff + pb) >> 8)
+			dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*pa1/0xffff + pa) >> 8)
+		}
+	}
+}
+
+func (ablInterpolator) scale_RGBA_NYCbCrA420_Over(dst *image.RGBA, dr, adr image.Rectangle, src *image.NYCbCrA, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+			s00j := ((sr.Min.Y+int(sy0))/2-src.Rect.Min.Y/2)*src.CStride + ((sr.Min.X+int(sx0))/2 - src.Rect.Min.X/2)
+			s00k := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.AStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s00yy1 := int(src.Y[s00i]) * 0x10101
+			s00cb1 := int(src.Cb[s00j]) - 128
+			s00cr1 := int(src.Cr[s00j]) - 128
+			s00ru0 := (s00yy1 + 91881*s00cr1) >> 8
+			s00gu0 := (s00yy1 - 22554*s00cb1 - 46802*s00cr1) >> 8
+			s00bu0 := (s00yy1 + 116130*s00cb1) >> 8
+			if s00ru0 < 0 {
+				s00ru0 = 0
+			} else if s00ru0 > 0xffff {
+				s00ru0 = 0xffff
+			}
+			if s00gu0 < 0 {
+				s00gu0 = 0
+			} else if s00gu0 > 0xffff {
+				s00gu0 = 0xffff
+			}
+			if s00bu0 < 0 {
+				s00bu0 = 0
+			} else if s00bu0 > 0xffff {
+				s00bu0 = 0xffff
+			}
+
+			s00au := uint32(src.A[s00k]) * 0x101
+			s00ru := uint32(s00ru0) * s00au / 0xffff
+			s00gu := uint32(s00gu0) * s00au / 0xffff
+			s00bu := uint32(s00bu0) * s00au / 0xffff
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+			s10j := ((sr.Min.Y+int(sy0))/2-src.Rect.Min.Y/2)*src.CStride + ((sr.Min.X+int(sx1))/2 - src.Rect.Min.X/2)
+			s10k := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.AStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s10yy1 := int(src.Y[s10i]) * 0x10101
+			s10cb1 := int(src.Cb[s10j]) - 128
+			s10cr1 := int(src.Cr[s10j]) - 128
+			s10ru0 := (s10yy1 + 91881*s10cr1) >> 8
+			s10gu0 := (s10yy1 - 22554*s10cb1 - 46802*s10cr1) >> 8
+			s10bu0 := (s10yy1 + 116130*s10cb1) >> 8
+			if s10ru0 < 0 {
+				s10ru0 = 0
+			} else if s10ru0 > 0xffff {
+				s10ru0 = 0xffff
+			}
+			if s10gu0 < 0 {
+				s10gu0 = 0
+			} else if s10gu0 > 0xffff {
+				s10gu0 = 0xffff
+			}
+			if s10bu0 < 0 {
+				s10bu0 = 0
+			} else if s10bu0 > 0xffff {
+				s10bu0 = 0xffff
+			}
+
+			s10au := uint32(src.A[s10k]) * 0x101
+			s10ru := uint32(s10ru0) * s10au / 0xffff
+			s10gu := uint32(s10gu0) * s10au / 0xffff
+			s10bu := uint32(s10bu0) * s10au / 0xffff
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+			s01j := ((sr.Min.Y+int(sy1))/2-src.Rect.Min.Y/2)*src.CStride + ((sr.Min.X+int(sx0))/2 - src.Rect.Min.X/2)
+			s01k := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.AStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s01yy1 := int(src.Y[s01i]) * 0x10101
+			s01cb1 := int(src.Cb[s01j]) - 128
+			s01cr1 := int(src.Cr[s01j]) - 128
+			s01ru0 := (s01yy1 + 91881*s01cr1) >> 8
+			s01gu0 := (s01yy1 - 22554*s01cb1 - 46802*s01cr1) >> 8
+			s01bu0 := (s01yy1 + 116130*s01cb1) >> 8
+			if s01ru0 < 0 {
+				s01ru0 = 0
+			} else if s01ru0 > 0xffff {
+				s01ru0 = 0xffff
+			}
+			if s01gu0 < 0 {
+				s01gu0 = 0
+			} else if s01gu0 > 0xffff {
+				s01gu0 = 0xffff
+			}
+			if s01bu0 < 0 {
+				s01bu0 = 0
+			} else if s01bu0 > 0xffff {
+				s01bu0 = 0xffff
+			}
+
+			s01au := uint32(src.A[s01k]) * 0x101
+			s01ru := uint32(s01ru0) * s01au / 0xffff
+			s01gu := uint32(s01gu0) * s01au / 0xffff
+			s01bu := uint32(s01bu0) * s01au / 0xffff
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+			s11j := ((sr.Min.Y+int(sy1))/2-src.Rect.Min.Y/2)*src.CStride + ((sr.Min.X+int(sx1))/2 - src.Rect.Min.X/2)
+			s11k := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.AStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s11yy1 := int(src.Y[s11i]) * 0x10101
+			s11cb1 := int(src.Cb[s11j]) - 128
+			s11cr1 := int(src.Cr[s11j]) - 128
+			s11ru0 := (s11yy1 + 91881*s11cr1) >> 8
+			s11gu0 := (s11yy1 - 22554*s11cb1 - 46802*s11cr1) >> 8
+			s11bu0 := (s11yy1 + 116130*s11cb1) >> 8
+			if s11ru0 < 0 {
+				s11ru0 = 0
+			} else if s11ru0 > 0xffff {
+				s11ru0 = 0xffff
+			}
+			if s11gu0 < 0 {
+				s11gu0 = 0
+			} else if s11gu0 > 0xffff {
+				s11gu0 = 0xffff
+			}
+			if s11bu0 < 0 {
+				s11bu0 = 0
+			} else if s11bu0 > 0xffff {
+				s11bu0 = 0xffff
+			}
+
+			s11au := uint32(src.A[s11k]) * 0x101
+			s11ru := uint32(s11ru0) * s11au / 0xffff
+			s11gu := uint32(s11gu0) * s11au / 0xffff
+			s11bu := uint32(s11bu0) * s11au / 0xffff
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			pa1 := (0xffff - pa) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pr) >> 8)
+			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + pg) >> 8)
+			dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*pa1/0xffff + pb) >> 8)
+			dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*pa1/0xffff + pa) >> 8)
+		}
+	}
+}
+
+func (ablInterpolator) scale_RGBA_NYCbCrA440_Over(dst *image.RGBA, dr, adr image.Rectangle, src *image.NYCbCrA, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+			s00j := ((sr.Min.Y+int(sy0))/2-src.Rect.Min.Y/2)*src.CStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+			s00k := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.AStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s00yy1 := int(src.Y[s00i]) * 0x10101
+			s00cb1 := int(src.Cb[s00j]) - 128
+			s00cr1 := int(src.Cr[s00j]) - 128
+			s00ru0 := (s00yy1 + 91881*s00cr1) >> 8
+			s00gu0 := (s00yy1 - 22554*s00cb1 - 46802*s00cr1) >> 8
+			s00bu0 := (s00yy1 + 116130*s00cb1) >> 8
+			if s00ru0 < 0 {
+				s00ru0 = 0
+			} else if s00ru0 > 0xffff {
+				s00ru0 = 0xffff
+			}
+			if s00gu0 < 0 {
+				s00gu0 = 0
+			} else if s00gu0 > 0xffff {
+				s00gu0 = 0xffff
+			}
+			if s00bu0 < 0 {
+				s00bu0 = 0
+			} else if s00bu0 > 0xffff {
+				s00bu0 = 0xffff
+			}
+
+			s00au := uint32(src.A[s00k]) * 0x101
+			s00ru := uint32(s00ru0) * s00au / 0xffff
+			s00gu := uint32(s00gu0) * s00au / 0xffff
+			s00bu := uint32(s00bu0) * s00au / 0xffff
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+			s10j := ((sr.Min.Y+int(sy0))/2-src.Rect.Min.Y/2)*src.CStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+			s10k := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.AStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s10yy1 := int(src.Y[s10i]) * 0x10101
+			s10cb1 := int(src.Cb[s10j]) - 128
+			s10cr1 := int(src.Cr[s10j]) - 128
+			s10ru0 := (s10yy1 + 91881*s10cr1) >> 8
+			s10gu0 := (s10yy1 - 22554*s10cb1 - 46802*s10cr1) >> 8
+			s10bu0 := (s10yy1 + 116130*s10cb1) >> 8
+			if s10ru0 < 0 {
+				s10ru0 = 0
+			} else if s10ru0 > 0xffff {
+				s10ru0 = 0xffff
+			}
+			if s10gu0 < 0 {
+				s10gu0 = 0
+			} else if s10gu0 > 0xffff {
+				s10gu0 = 0xffff
+			}
+			if s10bu0 < 0 {
+				s10bu0 = 0
+			} else if s10bu0 > 0xffff {
+				s10bu0 = 0xffff
+			}
+
+			s10au := uint32(src.A[s10k]) * 0x101
+			s10ru := uint32(s10ru0) * s10au / 0xffff
+			s10gu := uint32(s10gu0) * s10au / 0xffff
+			s10bu := uint32(s10bu0) * s10au / 0xffff
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+			s01j := ((sr.Min.Y+int(sy1))/2-src.Rect.Min.Y/2)*src.CStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+			s01k := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.AStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s01yy1 := int(src.Y[s01i]) * 0x10101
+			s01cb1 := int(src.Cb[s01j]) - 128
+			s01cr1 := int(src.Cr[s01j]) - 128
+			s01ru0 := (s01yy1 + 91881*s01cr1) >> 8
+			s01gu0 := (s01yy1 - 22554*s01cb1 - 46802*s01cr1) >> 8
+			s01bu0 := (s01yy1 + 116130*s01cb1) >> 8
+			if s01ru0 < 0 {
+				s01ru0 = 0
+			} else if s01ru0 > 0xffff {
+				s01ru0 = 0xffff
+			}
+			if s01gu0 < 0 {
+				s01gu0 = 0
+			} else if s01gu0 > 0xffff {
+				s01gu0 = 0xffff
+			}
+			if s01bu0 < 0 {
+				s01bu0 = 0
+			} else if s01bu0 > 0xffff {
+				s01bu0 = 0xffff
+			}
+
+			s01au := uint32(src.A[s01k]) * 0x101
+			s01ru := uint32(s01ru0) * s01au / 0xffff
+			s01gu := uint32(s01gu0) * s01au / 0xffff
+			s01bu := uint32(s01bu0) * s01au / 0xffff
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+			s11j := ((sr.Min.Y+int(sy1))/2-src.Rect.Min.Y/2)*src.CStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+			s11k := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.AStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s11yy1 := int(src.Y[s11i]) * 0x10101
+			s11cb1 := int(src.Cb[s11j]) - 128
+			s11cr1 := int(src.Cr[s11j]) - 128
+			s11ru0 := (s11yy1 + 91881*s11cr1) >> 8
+			s11gu0 := (s11yy1 - 22554*s11cb1 - 46802*s11cr1) >> 8
+			s11bu0 := (s11yy1 + 116130*s11cb1) >> 8
+			if s11ru0 < 0 {
+				s11ru0 = 0
+			} else if s11ru0 > 0xffff {
+				s11ru0 = 0xffff
+			}
+			if s11gu0 < 0 {
+				s11gu0 = 0
+			} else if s11gu0 > 0xffff {
+				s11gu0 = 0xffff
+			}
+			if s11bu0 < 0 {
+				s11bu0 = 0
+			} else if s11bu0 > 0xffff {
+				s11bu0 = 0xffff
+			}
+
+			s11au := uint32(src.A[s11k]) * 0x101
+			s11ru := uint32(s11ru0) * s11au / 0xffff
+			s11gu := uint32(s11gu0) * s11au / 0xffff
+			s11bu := uint32(s11bu0) * s11au / 0xffff
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			pa1 := (0xffff - pa) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pr) >> 8)
+			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + pg) >> 8)
+			dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*pa1/0xffff + pb) >> 8)
+			dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*pa1/0xffff + pa) >> 8)
+		}
+	}
+}
+
+func (ablInterpolator) scale_RGBA_NYCbCrA444_Src(dst *image.RGBA, dr, adr image.Rectangle, src *image.NYCbCrA, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+			s00j := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.CStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+			s00k := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.AStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s00yy1 := int(src.Y[s00i]) * 0x10101
+			s00cb1 := int(src.Cb[s00j]) - 128
+			s00cr1 := int(src.Cr[s00j]) - 128
+			s00ru0 := (s00yy1 + 91881*s00cr1) >> 8
+			s00gu0 := (s00yy1 - 22554*s00cb1 - 46802*s00cr1) >> 8
+			s00bu0 := (s00yy1 + 116130*s00cb1) >> 8
+			if s00ru0 < 0 {
+				s00ru0 = 0
+			} else if s00ru0 > 0xffff {
+				s00ru0 = 0xffff
+			}
+			if s00gu0 < 0 {
+				s00gu0 = 0
+			} else if s00gu0 > 0xffff {
+				s00gu0 = 0xffff
+			}
+			if s00bu0 < 0 {
+				s00bu0 = 0
+			} else if s00bu0 > 0xffff {
+				s00bu0 = 0xffff
+			}
+
+			s00au := uint32(src.A[s00k]) * 0x101
+			s00ru := uint32(s00ru0) * s00au / 0xffff
+			s00gu := uint32(s00gu0) * s00au / 0xffff
+			s00bu := uint32(s00bu0) * s00au / 0xffff
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+			s10j := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.CStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+			s10k := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.AStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s10yy1 := int(src.Y[s10i]) * 0x10101
+			s10cb1 := int(src.Cb[s10j]) - 128
+			s10cr1 := int(src.Cr[s10j]) - 128
+			s10ru0 := (s10yy1 + 91881*s10cr1) >> 8
+			s10gu0 := (s10yy1 - 22554*s10cb1 - 46802*s10cr1) >> 8
+			s10bu0 := (s10yy1 + 116130*s10cb1) >> 8
+			if s10ru0 < 0 {
+				s10ru0 = 0
+			} else if s10ru0 > 0xffff {
+				s10ru0 = 0xffff
+			}
+			if s10gu0 < 0 {
+				s10gu0 = 0
+			} else if s10gu0 > 0xffff {
+				s10gu0 = 0xffff
+			}
+			if s10bu0 < 0 {
+				s10bu0 = 0
+			} else if s10bu0 > 0xffff {
+				s10bu0 = 0xffff
+			}
+
+			s10au := uint32(src.A[s10k]) * 0x101
+			s10ru := uint32(s10ru0) * s10au / 0xffff
+			s10gu := uint32(s10gu0) * s10au / 0xffff
+			s10bu := uint32(s10bu0) * s10au / 0xffff
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+			s01j := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.CStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+			s01k := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.AStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s01yy1 := int(src.Y[s01i]) * 0x10101
+			s01cb1 := int(src.Cb[s01j]) - 128
+			s01cr1 := int(src.Cr[s01j]) - 128
+			s01ru0 := (s01yy1 + 91881*s01cr1) >> 8
+			s01gu0 := (s01yy1 - 22554*s01cb1 - 46802*s01cr1) >> 8
+			s01bu0 := (s01yy1 + 116130*s01cb1) >> 8
+			if s01ru0 < 0 {
+				s01ru0 = 0
+			} else if s01ru0 > 0xffff {
+				s01ru0 = 0xffff
+			}
+			if s01gu0 < 0 {
+				s01gu0 = 0
+			} else if s01gu0 > 0xffff {
+				s01gu0 = 0xffff
+			}
+			if s01bu0 < 0 {
+				s01bu0 = 0
+			} else if s01bu0 > 0xffff {
+				s01bu0 = 0xffff
+			}
+
+			s01au := uint32(src.A[s01k]) * 0x101
+			s01ru := uint32(s01ru0) * s01au / 0xffff
+			s01gu := uint32(s01gu0) * s01au / 0xffff
+			s01bu := uint32(s01bu0) * s01au / 0xffff
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+			s11j := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.CStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+			s11k := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.AStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s11yy1 := int(src.Y[s11i]) * 0x10101
+			s11cb1 := int(src.Cb[s11j]) - 128
+			s11cr1 := int(src.Cr[s11j]) - 128
+			s11ru0 := (s11yy1 + 91881*s11cr1) >> 8
+			s11gu0 := (s11yy1 - 22554*s11cb1 - 46802*s11cr1) >> 8
+			s11bu0 := (s11yy1 + 116130*s11cb1) >> 8
+			if s11ru0 < 0 {
+				s11ru0 = 0
+			} else if s11ru0 > 0xffff {
+				s11ru0 = 0xffff
+			}
+			if s11gu0 < 0 {
+				s11gu0 = 0
+			} else if s11gu0 > 0xffff {
+				s11gu0 = 0xffff
+			}
+			if s11bu0 < 0 {
+				s11bu0 = 0
+			} else if s11bu0 > 0xffff {
+				s11bu0 = 0xffff
+			}
+
+			s11au := uint32(src.A[s11k]) * 0x101
+			s11ru := uint32(s11ru0) * s11au / 0xffff
+			s11gu := uint32(s11gu0) * s11au / 0xffff
+			s11bu := uint32(s11bu0) * s11au / 0xffff
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			dst.Pix[d+0] = uint8(pr >> 8)
+			dst.Pix[d+1] = uint8(pg >> 8)
+			dst.Pix[d+2] = uint8(pb >> 8)
+			dst.Pix[d+3] = uint8(pa >> 8)
+		}
+	}
+}
+
+func (ablInterpolator) scale_RGBA_NYCbCrA422_Src(dst *image.RGBA, dr, adr image.Rectangle, src *image.NYCbCrA, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+			s00j := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.CStride + ((sr.Min.X+int(sx0))/2 - src.Rect.Min.X/2)
+			s00k := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.AStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s00yy1 := int(src.Y[s00i]) * 0x10101
+			s00cb1 := int(src.Cb[s00j]) - 128
+			s00cr1 := int(src.Cr[s00j]) - 128
+			s00ru0 := (s00yy1 + 91881*s00cr1) >> 8
+			s00gu0 := (s00yy1 - 22554*s00cb1 - 46802*s00cr1) >> 8
+			s00bu0 := (s00yy1 + 116130*s00cb1) >> 8
+			if s00ru0 < 0 {
+				s00ru0 = 0
+			} else if s00ru0 > 0xffff {
+				s00ru0 = 0xffff
+			}
+			if s00gu0 < 0 {
+				s00gu0 = 0
+			} else if s00gu0 > 0xffff {
+				s00gu0 = 0xffff
+			}
+			if s00bu0 < 0 {
+				s00bu0 = 0
+			} else if s00bu0 > 0xffff {
+				s00bu0 = 0xffff
+			}
+
+			s00au := uint32(src.A[s00k]) * 0x101
+			s00ru := uint32(s00ru0) * s00au / 0xffff
+			s00gu := uint32(s00gu0) * s00au / 0xffff
+			s00bu := uint32(s00bu0) * s00au / 0xffff
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+			s10j := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.CStride + ((sr.Min.X+int(sx1))/2 - src.Rect.Min.X/2)
+			s10k := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.AStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s10yy1 := int(src.Y[s10i]) * 0x10101
+			s10cb1 := int(src.Cb[s10j]) - 128
+			s10cr1 := int(src.Cr[s10j]) - 128
+			s10ru0 := (s10yy1 + 91881*s10cr1) >> 8
+			s10gu0 := (s10yy1 - 22554*s10cb1 - 46802*s10cr1) >> 8
+			s10bu0 := (s10yy1 + 116130*s10cb1) >> 8
+			if s10ru0 < 0 {
+				s10ru0 = 0
+			} else if s10ru0 > 0xffff {
+				s10ru0 = 0xffff
+			}
+			if s10gu0 < 0 {
+				s10gu0 = 0
+			} else if s10gu0 > 0xffff {
+				s10gu0 = 0xffff
+			}
+			if s10bu0 < 0 {
+				s10bu0 = 0
+			} else if s10bu0 > 0xffff {
+				s10bu0 = 0xffff
+			}
+
+			s10au := uint32(src.A[s10k]) * 0x101
+			s10ru := uint32(s10ru0) * s10au / 0xffff
+			s10gu := uint32(s10gu0) * s10au / 0xffff
+			s10bu := uint32(s10bu0) * s10au / 0xffff
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+			s01j := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.CStride + ((sr.Min.X+int(sx0))/2 - src.Rect.Min.X/2)
+			s01k := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.AStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s01yy1 := int(src.Y[s01i]) * 0x10101
+			s01cb1 := int(src.Cb[s01j]) - 128
+			s01cr1 := int(src.Cr[s01j]) - 128
+			s01ru0 := (s01yy1 + 91881*s01cr1) >> 8
+			s01gu0 := (s01yy1 - 22554*s01cb1 - 46802*s01cr1) >> 8
+			s01bu0 := (s01yy1 + 116130*s01cb1) >> 8
+			if s01ru0 < 0 {
+				s01ru0 = 0
+			} else if s01ru0 > 0xffff {
+				s01ru0 = 0xffff
+			}
+			if s01gu0 < 0 {
+				s01gu0 = 0
+			} else if s01gu0 > 0xffff {
+				s01gu0 = 0xffff
+			}
+			if s01bu0 < 0 {
+				s01bu0 = 0
+			} else if s01bu0 > 0xffff {
+				s01bu0 = 0xffff
+			}
+
+			s01au := uint32(src.A[s01k]) * 0x101
+			s01ru := uint32(s01ru0) * s01au / 0xffff
+			s01gu := uint32(s01gu0) * s01au / 0xffff
+			s01bu := uint32(s01bu0) * s01au / 0xffff
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+			s11j := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.CStride + ((sr.Min.X+int(sx1))/2 - src.Rect.Min.X/2)
+			s11k := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.AStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s11yy1 := int(src.Y[s11i]) * 0x10101
+			s11cb1 := int(src.Cb[s11j]) - 128
+			s11cr1 := int(src.Cr[s11j]) - 128
+			s11ru0 := (s11yy1 + 91881*s11cr1) >> 8
+			s11gu0 := (s11yy1 - 22554*s11cb1 - 46802*s11cr1) >> 8
+			s11bu0 := (s11yy1 + 116130*s11cb1) >> 8
+			if s11ru0 < 0 {
+				s11ru0 = 0
+			} else if s11ru0 > 0xffff {
+				s11ru0 = 0xffff
+			}
+			if s11gu0 < 0 {
+				s11gu0 = 0
+			} else if s11gu0 > 0xffff {
+				s11gu0 = 0xffff
+			}
+			if s11bu0 < 0 {
+				s11bu0 = 0
+			} else if s11bu0 > 0xffff {
+				s11bu0 = 0xffff
+			}
+
+			s11au := uint32(src.A[s11k]) * 0x101
+			s11ru := uint32(s11ru0) * s11au / 0xffff
+			s11gu := uint32(s11gu0) * s11au / 0xffff
+			s11bu := uint32(s11bu0) * s11au / 0xffff
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			dst.Pix[d+0] = uint8(pr >> 8)
+			dst.Pix[d+1] = uint8(pg >> 8)
+			dst.Pix[d+2] = uint8(pb >> 8)
+			dst.Pix[d+3] = uint8(pa >> 8)
+		}
+	}
+}
+
+func (ablInterpolator) scale_RGBA_NYCbCrA420_Src(dst *image.RGBA, dr, adr image.Rectangle, src *image.NYCbCrA, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+			s00j := ((sr.Min.Y+int(sy0))/2-src.Rect.Min.Y/2)*src.CStride + ((sr.Min.X+int(sx0))/2 - src.Rect.Min.X/2)
+			s00k := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.AStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s00yy1 := int(src.Y[s00i]) * 0x10101
+			s00cb1 := int(src.Cb[s00j]) - 128
+			s00cr1 := int(src.Cr[s00j]) - 128
+			s00ru0 := (s00yy1 + 91881*s00cr1) >> 8
+			s00gu0 := (s00yy1 - 22554*s00cb1 - 46802*s00cr1) >> 8
+			s00bu0 := (s00yy1 + 116130*s00cb1) >> 8
+			if s00ru0 < 0 {
+				s00ru0 = 0
+			} else if s00ru0 > 0xffff {
+				s00ru0 = 0xffff
+			}
+			if s00gu0 < 0 {
+				s00gu0 = 0
+			} else if s00gu0 > 0xffff {
+				s00gu0 = 0xffff
+			}
+			if s00bu0 < 0 {
+				s00bu0 = 0
+			} else if s00bu0 > 0xffff {
+				s00bu0 = 0xffff
+			}
+
+			s00au := uint32(src.A[s00k]) * 0x101
+			s00ru := uint32(s00ru0) * s00au / 0xffff
+			s00gu := uint32(s00gu0) * s00au / 0xffff
+			s00bu := uint32(s00bu0) * s00au / 0xffff
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+			s10j := ((sr.Min.Y+int(sy0))/2-src.Rect.Min.Y/2)*src.CStride + ((sr.Min.X+int(sx1))/2 - src.Rect.Min.X/2)
+			s10k := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.AStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s10yy1 := int(src.Y[s10i]) * 0x10101
+			s10cb1 := int(src.Cb[s10j]) - 128
+			s10cr1 := int(src.Cr[s10j]) - 128
+			s10ru0 := (s10yy1 + 91881*s10cr1) >> 8
+			s10gu0 := (s10yy1 - 22554*s10cb1 - 46802*s10cr1) >> 8
+			s10bu0 := (s10yy1 + 116130*s10cb1) >> 8
+			if s10ru0 < 0 {
+				s10ru0 = 0
+			} else if s10ru0 > 0xffff {
+				s10ru0 = 0xffff
+			}
+			if s10gu0 < 0 {
+				s10gu0 = 0
+			} else if s10gu0 > 0xffff {
+				s10gu0 = 0xffff
+			}
+			if s10bu0 < 0 {
+				s10bu0 = 0
+			} else if s10bu0 > 0xffff {
+				s10bu0 = 0xffff
+			}
+
+			s10au := uint32(src.A[s10k]) * 0x101
+			s10ru := uint32(s10ru0) * s10au / 0xffff
+			s10gu := uint32(s10gu0) * s10au / 0xffff
+			s10bu := uint32(s10bu0) * s10au / 0xffff
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+			s01j := ((sr.Min.Y+int(sy1))/2-src.Rect.Min.Y/2)*src.CStride + ((sr.Min.X+int(sx0))/2 - src.Rect.Min.X/2)
+			s01k := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.AStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s01yy1 := int(src.Y[s01i]) * 0x10101
+			s01cb1 := int(src.Cb[s01j]) - 128
+			s01cr1 := int(src.Cr[s01j]) - 128
+			s01ru0 := (s01yy1 + 91881*s01cr1) >> 8
+			s01gu0 := (s01yy1 - 22554*s01cb1 - 46802*s01cr1) >> 8
+			s01bu0 := (s01yy1 + 116130*s01cb1) >> 8
+			if s01ru0 < 0 {
+				s01ru0 = 0
+			} else if s01ru0 > 0xffff {
+				s01ru0 = 0xffff
+			}
+			if s01gu0 < 0 {
+				s01gu0 = 0
+			} else if s01gu0 > 0xffff {
+				s01gu0 = 0xffff
+			}
+			if s01bu0 < 0 {
+				s01bu0 = 0
+			} else if s01bu0 > 0xffff {
+				s01bu0 = 0xffff
+			}
+
+			s01au := uint32(src.A[s01k]) * 0x101
+			s01ru := uint32(s01ru0) * s01au / 0xffff
+			s01gu := uint32(s01gu0) * s01au / 0xffff
+			s01bu := uint32(s01bu0) * s01au / 0xffff
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+			s11j := ((sr.Min.Y+int(sy1))/2-src.Rect.Min.Y/2)*src.CStride + ((sr.Min.X+int(sx1))/2 - src.Rect.Min.X/2)
+			s11k := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.AStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s11yy1 := int(src.Y[s11i]) * 0x10101
+			s11cb1 := int(src.Cb[s11j]) - 128
+			s11cr1 := int(src.Cr[s11j]) - 128
+			s11ru0 := (s11yy1 + 91881*s11cr1) >> 8
+			s11gu0 := (s11yy1 - 22554*s11cb1 - 46802*s11cr1) >> 8
+			s11bu0 := (s11yy1 + 116130*s11cb1) >> 8
+			if s11ru0 < 0 {
+				s11ru0 = 0
+			} else if s11ru0 > 0xffff {
+				s11ru0 = 0xffff
+			}
+			if s11gu0 < 0 {
+				s11gu0 = 0
+			} else if s11gu0 > 0xffff {
+				s11gu0 = 0xffff
+			}
+			if s11bu0 < 0 {
+				s11bu0 = 0
+			} else if s11bu0 > 0xffff {
+				s11bu0 = 0xffff
+			}
+
+			s11au := uint32(src.A[s11k]) * 0x101
+			s11ru := uint32(s11ru0) * s11au / 0xffff
+			s11gu := uint32(s11gu0) * s11au / 0xffff
+			s11bu := uint32(s11bu0) * s11au / 0xffff
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			dst.Pix[d+0] = uint8(pr >> 8)
+			dst.Pix[d+1] = uint8(pg >> 8)
+			dst.Pix[d+2] = uint8(pb >> 8)
+			dst.Pix[d+3] = uint8(pa >> 8)
+		}
+	}
+}
+
+func (ablInterpolator) scale_RGBA_NYCbCrA440_Src(dst *image.RGBA, dr, adr image.Rectangle, src *image.NYCbCrA, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+			s00j := ((sr.Min.Y+int(sy0))/2-src.Rect.Min.Y/2)*src.CStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+			s00k := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.AStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s00yy1 := int(src.Y[s00i]) * 0x10101
+			s00cb1 := int(src.Cb[s00j]) - 128
+			s00cr1 := int(src.Cr[s00j]) - 128
+			s00ru0 := (s00yy1 + 91881*s00cr1) >> 8
+			s00gu0 := (s00yy1 - 22554*s00cb1 - 46802*s00cr1) >> 8
+			s00bu0 := (s00yy1 + 116130*s00cb1) >> 8
+			if s00ru0 < 0 {
+				s00ru0 = 0
+			} else if s00ru0 > 0xffff {
+				s00ru0 = 0xffff
+			}
+			if s00gu0 < 0 {
+				s00gu0 = 0
+			} else if s00gu0 > 0xffff {
+				s00gu0 = 0xffff
+			}
+			if s00bu0 < 0 {
+				s00bu0 = 0
+			} else if s00bu0 > 0xffff {
+				s00bu0 = 0xffff
+			}
+
+			s00au := uint32(src.A[s00k]) * 0x101
+			s00ru := uint32(s00ru0) * s00au / 0xffff
+			s00gu := uint32(s00gu0) * s00au / 0xffff
+			s00bu := uint32(s00bu0) * s00au / 0xffff
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+			s10j := ((sr.Min.Y+int(sy0))/2-src.Rect.Min.Y/2)*src.CStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+			s10k := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.AStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s10yy1 := int(src.Y[s10i]) * 0x10101
+			s10cb1 := int(src.Cb[s10j]) - 128
+			s10cr1 := int(src.Cr[s10j]) - 128
+			s10ru0 := (s10yy1 + 91881*s10cr1) >> 8
+			s10gu0 := (s10yy1 - 22554*s10cb1 - 46802*s10cr1) >> 8
+			s10bu0 := (s10yy1 + 116130*s10cb1) >> 8
+			if s10ru0 < 0 {
+				s10ru0 = 0
+			} else if s10ru0 > 0xffff {
+				s10ru0 = 0xffff
+			}
+			if s10gu0 < 0 {
+				s10gu0 = 0
+			} else if s10gu0 > 0xffff {
+				s10gu0 = 0xffff
+			}
+			if s10bu0 < 0 {
+				s10bu0 = 0
+			} else if s10bu0 > 0xffff {
+				s10bu0 = 0xffff
+			}
+
+			s10au := uint32(src.A[s10k]) * 0x101
+			s10ru := uint32(s10ru0) * s10au / 0xffff
+			s10gu := uint32(s10gu0) * s10au / 0xffff
+			s10bu := uint32(s10bu0) * s10au / 0xffff
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+			s01j := ((sr.Min.Y+int(sy1))/2-src.Rect.Min.Y/2)*src.CStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+			s01k := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.AStride + (sr.Min.X + int(sx0) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s01yy1 := int(src.Y[s01i]) * 0x10101
+			s01cb1 := int(src.Cb[s01j]) - 128
+			s01cr1 := int(src.Cr[s01j]) - 128
+			s01ru0 := (s01yy1 + 91881*s01cr1) >> 8
+			s01gu0 := (s01yy1 - 22554*s01cb1 - 46802*s01cr1) >> 8
+			s01bu0 := (s01yy1 + 116130*s01cb1) >> 8
+			if s01ru0 < 0 {
+				s01ru0 = 0
+			} else if s01ru0 > 0xffff {
+				s01ru0 = 0xffff
+			}
+			if s01gu0 < 0 {
+				s01gu0 = 0
+			} else if s01gu0 > 0xffff {
+				s01gu0 = 0xffff
+			}
+			if s01bu0 < 0 {
+				s01bu0 = 0
+			} else if s01bu0 > 0xffff {
+				s01bu0 = 0xffff
+			}
+
+			s01au := uint32(src.A[s01k]) * 0x101
+			s01ru := uint32(s01ru0) * s01au / 0xffff
+			s01gu := uint32(s01gu0) * s01au / 0xffff
+			s01bu := uint32(s01bu0) * s01au / 0xffff
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+			s11j := ((sr.Min.Y+int(sy1))/2-src.Rect.Min.Y/2)*src.CStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+			s11k := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.AStride + (sr.Min.X + int(sx1) - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s11yy1 := int(src.Y[s11i]) * 0x10101
+			s11cb1 := int(src.Cb[s11j]) - 128
+			s11cr1 := int(src.Cr[s11j]) - 128
+			s11ru0 := (s11yy1 + 91881*s11cr1) >> 8
+			s11gu0 := (s11yy1 - 22554*s11cb1 - 46802*s11cr1) >> 8
+			s11bu0 := (s11yy1 + 116130*s11cb1) >> 8
+			if s11ru0 < 0 {
+				s11ru0 = 0
+			} else if s11ru0 > 0xffff {
+				s11ru0 = 0xffff
+			}
+			if s11gu0 < 0 {
+				s11gu0 = 0
+			} else if s11gu0 > 0xffff {
+				s11gu0 = 0xffff
+			}
+			if s11bu0 < 0 {
+				s11bu0 = 0
+			} else if s11bu0 > 0xffff {
+				s11bu0 = 0xffff
+			}
+
+			s11au := uint32(src.A[s11k]) * 0x101
+			s11ru := uint32(s11ru0) * s11au / 0xffff
+			s11gu := uint32(s11gu0) * s11au / 0xffff
+			s11bu := uint32(s11bu0) * s11au / 0xffff
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			dst.Pix[d+0] = uint8(pr >> 8)
+			dst.Pix[d+1] = uint8(pg >> 8)
+			dst.Pix[d+2] = uint8(pb >> 8)
+			dst.Pix[d+3] = uint8(pa >> 8)
+		}
+	}
+}
+
+func (ablInterpolator) scale_RGBA_RGBA64Image_Over(dst *image.RGBA, dr, adr image.Rectangle, src image.RGBA64Image, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00u := src.RGBA64At(sr.Min.X+int(sx0), sr.Min.Y+int(sy0))
+			s00r := float64(s00u.R)
+			s00g := float64(s00u.G)
+			s00b := float64(s00u.B)
+			s00a := float64(s00u.A)
+			s10u := src.RGBA64At(sr.Min.X+int(sx1), sr.Min.Y+int(sy0))
+			s10r := float64(s10u.R)
+			s10g := float64(s10u.G)
+			s10b := float64(s10u.B)
+			s10a := float64(s10u.A)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01u := src.RGBA64At(sr.Min.X+int(sx0), sr.Min.Y+int(sy1))
+			s01r := float64(s01u.R)
+			s01g := float64(s01u.G)
+			s01b := float64(s01u.B)
+			s01a := float64(s01u.A)
+			s11u := src.RGBA64At(sr.Min.X+int(sx1), sr.Min.Y+int(sy1))
+			s11r := float64(s11u.R)
+			s11g := float64(s11u.G)
+			s11b := float64(s11u.B)
+			s11a := float64(s11u.A)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			p := color.RGBA64{uint16(s11r), uint16(s11g), uint16(s11b), uint16(s11a)}
+			pa1 := (0xffff - uint32(p.A)) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + uint32(p.R)) >> 8)
+			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + uint32(p.G)) >> 8)
+			dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*pa1/0xffff + uint32(p.B)) >> 8)
+			dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*pa1/0xffff + uint32(p.A)) >> 8)
+		}
+	}
+}
+
+func (ablInterpolator) scale_RGBA_RGBA64Image_Src(dst *image.RGBA, dr, adr image.Rectangle, src image.RGBA64Image, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00u := src.RGBA64At(sr.Min.X+int(sx0), sr.Min.Y+int(sy0))
+			s00r := float64(s00u.R)
+			s00g := float64(s00u.G)
+			s00b := float64(s00u.B)
+			s00a := float64(s00u.A)
+			s10u := src.RGBA64At(sr.Min.X+int(sx1), sr.Min.Y+int(sy0))
+			s10r := float64(s10u.R)
+			s10g := float64(s10u.G)
+			s10b := float64(s10u.B)
+			s10a := float64(s10u.A)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01u := src.RGBA64At(sr.Min.X+int(sx0), sr.Min.Y+int(sy1))
+			s01r := float64(s01u.R)
+			s01g := float64(s01u.G)
+			s01b := float64(s01u.B)
+			s01a := float64(s01u.A)
+			s11u := src.RGBA64At(sr.Min.X+int(sx1), sr.Min.Y+int(sy1))
+			s11r := float64(s11u.R)
+			s11g := float64(s11u.G)
+			s11b := float64(s11u.B)
+			s11a := float64(s11u.A)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			p := color.RGBA64{uint16(s11r), uint16(s11g), uint16(s11b), uint16(s11a)}
+			dst.Pix[d+0] = uint8(p.R >> 8)
+			dst.Pix[d+1] = uint8(p.G >> 8)
+			dst.Pix[d+2] = uint8(p.B >> 8)
+			dst.Pix[d+3] = uint8(p.A >> 8)
+		}
+	}
+}
+
+func (ablInterpolator) scale_RGBA_Image_Over(dst *image.RGBA, dr, adr image.Rectangle, src image.Image, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00ru, s00gu, s00bu, s00au := src.At(sr.Min.X+int(sx0), sr.Min.Y+int(sy0)).RGBA()
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10ru, s10gu, s10bu, s10au := src.At(sr.Min.X+int(sx1), sr.Min.Y+int(sy0)).RGBA()
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01ru, s01gu, s01bu, s01au := src.At(sr.Min.X+int(sx0), sr.Min.Y+int(sy1)).RGBA()
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11ru, s11gu, s11bu, s11au := src.At(sr.Min.X+int(sx1), sr.Min.Y+int(sy1)).RGBA()
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			pa1 := (0xffff - pa) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pr) >> 8)
+			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + pg) >> 8)
+			dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*pa1/0xffff + pb) >> 8)
+			dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*pa1/0xffff + pa) >> 8)
+		}
+	}
+}
+
+func (ablInterpolator) scale_RGBA_Image_Src(dst *image.RGBA, dr, adr image.Rectangle, src image.Image, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00ru, s00gu, s00bu, s00au := src.At(sr.Min.X+int(sx0), sr.Min.Y+int(sy0)).RGBA()
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10ru, s10gu, s10bu, s10au := src.At(sr.Min.X+int(sx1), sr.Min.Y+int(sy0)).RGBA()
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01ru, s01gu, s01bu, s01au := src.At(sr.Min.X+int(sx0), sr.Min.Y+int(sy1)).RGBA()
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11ru, s11gu, s11bu, s11au := src.At(sr.Min.X+int(sx1), sr.Min.Y+int(sy1)).RGBA()
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			dst.Pix[d+0] = uint8(pr >> 8)
+			dst.Pix[d+1] = uint8(pg >> 8)
+			dst.Pix[d+2] = uint8(pb >> 8)
+			dst.Pix[d+3] = uint8(pa >> 8)
+		}
+	}
+}
+
+func (ablInterpolator) scale_RGBA64_NRGBA64_Over(dst *image.RGBA64, dr, adr image.Rectangle, src *image.NRGBA64, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx0)-src.Rect.Min.X)*8
+			s00au := (uint32(src.Pix[s00i+6])<<8 | uint32(src.Pix[s00i+7]))
+			s00ru := (uint32(src.Pix[s00i+0])<<8 | uint32(src.Pix[s00i+1])) * s00au / 0xffff
+			s00gu := (uint32(src.Pix[s00i+2])<<8 | uint32(src.Pix[s00i+3])) * s00au / 0xffff
+			s00bu := (uint32(src.Pix[s00i+4])<<8 | uint32(src.Pix[s00i+5])) * s00au / 0xffff
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx1)-src.Rect.Min.X)*8
+			s10au := (uint32(src.Pix[s10i+6])<<8 | uint32(src.Pix[s10i+7]))
+			s10ru := (uint32(src.Pix[s10i+0])<<8 | uint32(src.Pix[s10i+1])) * s10au / 0xffff
+			s10gu := (uint32(src.Pix[s10i+2])<<8 | uint32(src.Pix[s10i+3])) * s10au / 0xffff
+			s10bu := (uint32(src.Pix[s10i+4])<<8 | uint32(src.Pix[s10i+5])) * s10au / 0xffff
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx0)-src.Rect.Min.X)*8
+			s01au := (uint32(src.Pix[s01i+6])<<8 | uint32(src.Pix[s01i+7]))
+			s01ru := (uint32(src.Pix[s01i+0])<<8 | uint32(src.Pix[s01i+1])) * s01au / 0xffff
+			s01gu := (uint32(src.Pix[s01i+2])<<8 | uint32(src.Pix[s01i+3])) * s01au / 0xffff
+			s01bu := (uint32(src.Pix[s01i+4])<<8 | uint32(src.Pix[s01i+5])) * s01au / 0xffff
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx1)-src.Rect.Min.X)*8
+			s11au := (uint32(src.Pix[s11i+6])<<8 | uint32(src.Pix[s11i+7]))
+			s11ru := (uint32(src.Pix[s11i+0])<<8 | uint32(src.Pix[s11i+1])) * s11au / 0xffff
+			s11gu := (uint32(src.Pix[s11i+2])<<8 | uint32(src.Pix[s11i+3])) * s11au / 0xffff
+			s11bu := (uint32(src.Pix[s11i+4])<<8 | uint32(src.Pix[s11i+5])) * s11au / 0xffff
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			pa1 := 0xffff - pa
+			pr0 := (uint32(dst.Pix[d+0])<<8|uint32(dst.Pix[d+1]))*pa1/0xffff + pr
+			pg0 := (uint32(dst.Pix[d+2])<<8|uint32(dst.Pix[d+3]))*pa1/0xffff + pg
+			pb0 := (uint32(dst.Pix[d+4])<<8|uint32(dst.Pix[d+5]))*pa1/0xffff + pb
+			pa0 := (uint32(dst.Pix[d+6])<<8|uint32(dst.Pix[d+7]))*pa1/0xffff + pa
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
+
+func (ablInterpolator) scale_RGBA64_NRGBA64_Src(dst *image.RGBA64, dr, adr image.Rectangle, src *image.NRGBA64, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx0)-src.Rect.Min.X)*8
+			s00au := (uint32(src.Pix[s00i+6])<<8 | uint32(src.Pix[s00i+7]))
+			s00ru := (uint32(src.Pix[s00i+0])<<8 | uint32(src.Pix[s00i+1])) * s00au / 0xffff
+			s00gu := (uint32(src.Pix[s00i+2])<<8 | uint32(src.Pix[s00i+3])) * s00au / 0xffff
+			s00bu := (uint32(src.Pix[s00i+4])<<8 | uint32(src.Pix[s00i+5])) * s00au / 0xffff
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx1)-src.Rect.Min.X)*8
+			s10au := (uint32(src.Pix[s10i+6])<<8 | uint32(src.Pix[s10i+7]))
+			s10ru := (uint32(src.Pix[s10i+0])<<8 | uint32(src.Pix[s10i+1])) * s10au / 0xffff
+			s10gu := (uint32(src.Pix[s10i+2])<<8 | uint32(src.Pix[s10i+3])) * s10au / 0xffff
+			s10bu := (uint32(src.Pix[s10i+4])<<8 | uint32(src.Pix[s10i+5])) * s10au / 0xffff
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx0)-src.Rect.Min.X)*8
+			s01au := (uint32(src.Pix[s01i+6])<<8 | uint32(src.Pix[s01i+7]))
+			s01ru := (uint32(src.Pix[s01i+0])<<8 | uint32(src.Pix[s01i+1])) * s01au / 0xffff
+			s01gu := (uint32(src.Pix[s01i+2])<<8 | uint32(src.Pix[s01i+3])) * s01au / 0xffff
+			s01bu := (uint32(src.Pix[s01i+4])<<8 | uint32(src.Pix[s01i+5])) * s01au / 0xffff
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx1)-src.Rect.Min.X)*8
+			s11au := (uint32(src.Pix[s11i+6])<<8 | uint32(src.Pix[s11i+7]))
+			s11ru := (uint32(src.Pix[s11i+0])<<8 | uint32(src.Pix[s11i+1])) * s11au / 0xffff
+			s11gu := (uint32(src.Pix[s11i+2])<<8 | uint32(src.Pix[s11i+3])) * s11au / 0xffff
+			s11bu := (uint32(src.Pix[s11i+4])<<8 | uint32(src.Pix[s11i+5])) * s11au / 0xffff
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			pr0 := pr
+			pg0 := pg
+			pb0 := pb
+			pa0 := pa
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
+
+func (ablInterpolator) scale_RGBA64_RGBA64_Over(dst *image.RGBA64, dr, adr image.Rectangle, src *image.RGBA64, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx0)-src.Rect.Min.X)*8
+			s00ru := (uint32(src.Pix[s00i+0])<<8 | uint32(src.Pix[s00i+1]))
+			s00gu := (uint32(src.Pix[s00i+2])<<8 | uint32(src.Pix[s00i+3]))
+			s00bu := (uint32(src.Pix[s00i+4])<<8 | uint32(src.Pix[s00i+5]))
+			s00au := (uint32(src.Pix[s00i+6])<<8 | uint32(src.Pix[s00i+7]))
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx1)-src.Rect.Min.X)*8
+			s10ru := (uint32(src.Pix[s10i+0])<<8 | uint32(src.Pix[s10i+1]))
+			s10gu := (uint32(src.Pix[s10i+2])<<8 | uint32(src.Pix[s10i+3]))
+			s10bu := (uint32(src.Pix[s10i+4])<<8 | uint32(src.Pix[s10i+5]))
+			s10au := (uint32(src.Pix[s10i+6])<<8 | uint32(src.Pix[s10i+7]))
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx0)-src.Rect.Min.X)*8
+			s01ru := (uint32(src.Pix[s01i+0])<<8 | uint32(src.Pix[s01i+1]))
+			s01gu := (uint32(src.Pix[s01i+2])<<8 | uint32(src.Pix[s01i+3]))
+			s01bu := (uint32(src.Pix[s01i+4])<<8 | uint32(src.Pix[s01i+5]))
+			s01au := (uint32(src.Pix[s01i+6])<<8 | uint32(src.Pix[s01i+7]))
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx1)-src.Rect.Min.X)*8
+			s11ru := (uint32(src.Pix[s11i+0])<<8 | uint32(src.Pix[s11i+1]))
+			s11gu := (uint32(src.Pix[s11i+2])<<8 | uint32(src.Pix[s11i+3]))
+			s11bu := (uint32(src.Pix[s11i+4])<<8 | uint32(src.Pix[s11i+5]))
+			s11au := (uint32(src.Pix[s11i+6])<<8 | uint32(src.Pix[s11i+7]))
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			pa1 := 0xffff - pa
+			pr0 := (uint32(dst.Pix[d+0])<<8|uint32(dst.Pix[d+1]))*pa1/0xffff + pr
+			pg0 := (uint32(dst.Pix[d+2])<<8|uint32(dst.Pix[d+3]))*pa1/0xffff + pg
+			pb0 := (uint32(dst.Pix[d+4])<<8|uint32(dst.Pix[d+5]))*pa1/0xffff + pb
+			pa0 := (uint32(dst.Pix[d+6])<<8|uint32(dst.Pix[d+7]))*pa1/0xffff + pa
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
+
+func (ablInterpolator) scale_RGBA64_RGBA64_Src(dst *image.RGBA64, dr, adr image.Rectangle, src *image.RGBA64, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx0)-src.Rect.Min.X)*8
+			s00ru := (uint32(src.Pix[s00i+0])<<8 | uint32(src.Pix[s00i+1]))
+			s00gu := (uint32(src.Pix[s00i+2])<<8 | uint32(src.Pix[s00i+3]))
+			s00bu := (uint32(src.Pix[s00i+4])<<8 | uint32(src.Pix[s00i+5]))
+			s00au := (uint32(src.Pix[s00i+6])<<8 | uint32(src.Pix[s00i+7]))
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx1)-src.Rect.Min.X)*8
+			s10ru := (uint32(src.Pix[s10i+0])<<8 | uint32(src.Pix[s10i+1]))
+			s10gu := (uint32(src.Pix[s10i+2])<<8 | uint32(src.Pix[s10i+3]))
+			s10bu := (uint32(src.Pix[s10i+4])<<8 | uint32(src.Pix[s10i+5]))
+			s10au := (uint32(src.Pix[s10i+6])<<8 | uint32(src.Pix[s10i+7]))
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx0)-src.Rect.Min.X)*8
+			s01ru := (uint32(src.Pix[s01i+0])<<8 | uint32(src.Pix[s01i+1]))
+			s01gu := (uint32(src.Pix[s01i+2])<<8 | uint32(src.Pix[s01i+3]))
+			s01bu := (uint32(src.Pix[s01i+4])<<8 | uint32(src.Pix[s01i+5]))
+			s01au := (uint32(src.Pix[s01i+6])<<8 | uint32(src.Pix[s01i+7]))
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx1)-src.Rect.Min.X)*8
+			s11ru := (uint32(src.Pix[s11i+0])<<8 | uint32(src.Pix[s11i+1]))
+			s11gu := (uint32(src.Pix[s11i+2])<<8 | uint32(src.Pix[s11i+3]))
+			s11bu := (uint32(src.Pix[s11i+4])<<8 | uint32(src.Pix[s11i+5]))
+			s11au := (uint32(src.Pix[s11i+6])<<8 | uint32(src.Pix[s11i+7]))
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			pr0 := pr
+			pg0 := pg
+			pb0 := pb
+			pa0 := pa
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
+
+func (ablInterpolator) scale_RGBA64_RGBA64Image_Over(dst *image.RGBA64, dr, adr image.Rectangle, src image.RGBA64Image, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00u := src.RGBA64At(sr.Min.X+int(sx0), sr.Min.Y+int(sy0))
+			s00r := float64(s00u.R)
+			s00g := float64(s00u.G)
+			s00b := float64(s00u.B)
+			s00a := float64(s00u.A)
+			s10u := src.RGBA64At(sr.Min.X+int(sx1), sr.Min.Y+int(sy0))
+			s10r := float64(s10u.R)
+			s10g := float64(s10u.G)
+			s10b := float64(s10u.B)
+			s10a := float64(s10u.A)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01u := src.RGBA64At(sr.Min.X+int(sx0), sr.Min.Y+int(sy1))
+			s01r := float64(s01u.R)
+			s01g := float64(s01u.G)
+			s01b := float64(s01u.B)
+			s01a := float64(s01u.A)
+			s11u := src.RGBA64At(sr.Min.X+int(sx1), sr.Min.Y+int(sy1))
+			s11r := float64(s11u.R)
+			s11g := float64(s11u.G)
+			s11b := float64(s11u.B)
+			s11a := float64(s11u.A)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			p := color.RGBA64{uint16(s11r), uint16(s11g), uint16(s11b), uint16(s11a)}
+			pa1 := 0xffff - uint32(p.A)
+			pr0 := (uint32(dst.Pix[d+0])<<8|uint32(dst.Pix[d+1]))*pa1/0xffff + uint32(p.R)
+			pg0 := (uint32(dst.Pix[d+2])<<8|uint32(dst.Pix[d+3]))*pa1/0xffff + uint32(p.G)
+			pb0 := (uint32(dst.Pix[d+4])<<8|uint32(dst.Pix[d+5]))*pa1/0xffff + uint32(p.B)
+			pa0 := (uint32(dst.Pix[d+6])<<8|uint32(dst.Pix[d+7]))*pa1/0xffff + uint32(p.A)
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
+
+func (ablInterpolator) scale_RGBA64_RGBA64Image_Src(dst *image.RGBA64, dr, adr image.Rectangle, src image.RGBA64Image, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00u := src.RGBA64At(sr.Min.X+int(sx0), sr.Min.Y+int(sy0))
+			s00r := float64(s00u.R)
+			s00g := float64(s00u.G)
+			s00b := float64(s00u.B)
+			s00a := float64(s00u.A)
+			s10u := src.RGBA64At(sr.Min.X+int(sx1), sr.Min.Y+int(sy0))
+			s10r := float64(s10u.R)
+			s10g := float64(s10u.G)
+			s10b := float64(s10u.B)
+			s10a := float64(s10u.A)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01u := src.RGBA64At(sr.Min.X+int(sx0), sr.Min.Y+int(sy1))
+			s01r := float64(s01u.R)
+			s01g := float64(s01u.G)
+			s01b := float64(s01u.B)
+			s01a := float64(s01u.A)
+			s11u := src.RGBA64At(sr.Min.X+int(sx1), sr.Min.Y+int(sy1))
+			s11r := float64(s11u.R)
+			s11g := float64(s11u.G)
+			s11b := float64(s11u.B)
+			s11a := float64(s11u.A)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			p := color.RGBA64{uint16(s11r), uint16(s11g), uint16(s11b), uint16(s11a)}
+			pr0 := uint32(p.R)
+			pg0 := uint32(p.G)
+			pb0 := uint32(p.B)
+			pa0 := uint32(p.A)
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
+
+func (ablInterpolator) scale_RGBA64_Image_Over(dst *image.RGBA64, dr, adr image.Rectangle, src image.Image, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00ru, s00gu, s00bu, s00au := src.At(sr.Min.X+int(sx0), sr.Min.Y+int(sy0)).RGBA()
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10ru, s10gu, s10bu, s10au := src.At(sr.Min.X+int(sx1), sr.Min.Y+int(sy0)).RGBA()
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01ru, s01gu, s01bu, s01au := src.At(sr.Min.X+int(sx0), sr.Min.Y+int(sy1)).RGBA()
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11ru, s11gu, s11bu, s11au := src.At(sr.Min.X+int(sx1), sr.Min.Y+int(sy1)).RGBA()
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			pa1 := 0xffff - pa
+			pr0 := (uint32(dst.Pix[d+0])<<8|uint32(dst.Pix[d+1]))*pa1/0xffff + pr
+			pg0 := (uint32(dst.Pix[d+2])<<8|uint32(dst.Pix[d+3]))*pa1/0xffff + pg
+			pb0 := (uint32(dst.Pix[d+4])<<8|uint32(dst.Pix[d+5]))*pa1/0xffff + pb
+			pa0 := (uint32(dst.Pix[d+6])<<8|uint32(dst.Pix[d+7]))*pa1/0xffff + pa
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
+
+func (ablInterpolator) scale_RGBA64_Image_Src(dst *image.RGBA64, dr, adr image.Rectangle, src image.Image, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00ru, s00gu, s00bu, s00au := src.At(sr.Min.X+int(sx0), sr.Min.Y+int(sy0)).RGBA()
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10ru, s10gu, s10bu, s10au := src.At(sr.Min.X+int(sx1), sr.Min.Y+int(sy0)).RGBA()
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01ru, s01gu, s01bu, s01au := src.At(sr.Min.X+int(sx0), sr.Min.Y+int(sy1)).RGBA()
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11ru, s11gu, s11bu, s11au := src.At(sr.Min.X+int(sx1), sr.Min.Y+int(sy1)).RGBA()
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			pr0 := pr
+			pg0 := pg
+			pb0 := pb
+			pa0 := pa
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
+
+func (ablInterpolator) scale_NRGBA64_NRGBA64_Over(dst *image.NRGBA64, dr, adr image.Rectangle, src *image.NRGBA64, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx0)-src.Rect.Min.X)*8
+			s00au := (uint32(src.Pix[s00i+6])<<8 | uint32(src.Pix[s00i+7]))
+			s00ru := (uint32(src.Pix[s00i+0])<<8 | uint32(src.Pix[s00i+1])) * s00au / 0xffff
+			s00gu := (uint32(src.Pix[s00i+2])<<8 | uint32(src.Pix[s00i+3])) * s00au / 0xffff
+			s00bu := (uint32(src.Pix[s00i+4])<<8 | uint32(src.Pix[s00i+5])) * s00au / 0xffff
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx1)-src.Rect.Min.X)*8
+			s10au := (uint32(src.Pix[s10i+6])<<8 | uint32(src.Pix[s10i+7]))
+			s10ru := (uint32(src.Pix[s10i+0])<<8 | uint32(src.Pix[s10i+1])) * s10au / 0xffff
+			s10gu := (uint32(src.Pix[s10i+2])<<8 | uint32(src.Pix[s10i+3])) * s10au / 0xffff
+			s10bu := (uint32(src.Pix[s10i+4])<<8 | uint32(src.Pix[s10i+5])) * s10au / 0xffff
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx0)-src.Rect.Min.X)*8
+			s01au := (uint32(src.Pix[s01i+6])<<8 | uint32(src.Pix[s01i+7]))
+			s01ru := (uint32(src.Pix[s01i+0])<<8 | uint32(src.Pix[s01i+1])) * s01au / 0xffff
+			s01gu := (uint32(src.Pix[s01i+2])<<8 | uint32(src.Pix[s01i+3])) * s01au / 0xffff
+			s01bu := (uint32(src.Pix[s01i+4])<<8 | uint32(src.Pix[s01i+5])) * s01au / 0xffff
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx1)-src.Rect.Min.X)*8
+			s11au := (uint32(src.Pix[s11i+6])<<8 | uint32(src.Pix[s11i+7]))
+			s11ru := (uint32(src.Pix[s11i+0])<<8 | uint32(src.Pix[s11i+1])) * s11au / 0xffff
+			s11gu := (uint32(src.Pix[s11i+2])<<8 | uint32(src.Pix[s11i+3])) * s11au / 0xffff
+			s11bu := (uint32(src.Pix[s11i+4])<<8 | uint32(src.Pix[s11i+5])) * s11au / 0xffff
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			pa1 := 0xffff - pa
+			pqa := (uint32(dst.Pix[d+6])<<8 | uint32(dst.Pix[d+7]))
+			pr0 := ((uint32(dst.Pix[d+0])<<8|uint32(dst.Pix[d+1]))*pqa/0xffff)*pa1/0xffff + pr
+			pg0 := ((uint32(dst.Pix[d+2])<<8|uint32(dst.Pix[d+3]))*pqa/0xffff)*pa1/0xffff + pg
+			pb0 := ((uint32(dst.Pix[d+4])<<8|uint32(dst.Pix[d+5]))*pqa/0xffff)*pa1/0xffff + pb
+			pa0 := pqa*pa1/0xffff + pa
+			if pa0 != 0 && pa0 != 0xffff {
+				pr0 = pr0 * 0xffff / pa0
+				pg0 = pg0 * 0xffff / pa0
+				pb0 = pb0 * 0xffff / pa0
+			}
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
+
+func (ablInterpolator) scale_NRGBA64_NRGBA64_Src(dst *image.NRGBA64, dr, adr image.Rectangle, src *image.NRGBA64, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx0)-src.Rect.Min.X)*8
+			s00au := (uint32(src.Pix[s00i+6])<<8 | uint32(src.Pix[s00i+7]))
+			s00ru := (uint32(src.Pix[s00i+0])<<8 | uint32(src.Pix[s00i+1])) * s00au / 0xffff
+			s00gu := (uint32(src.Pix[s00i+2])<<8 | uint32(src.Pix[s00i+3])) * s00au / 0xffff
+			s00bu := (uint32(src.Pix[s00i+4])<<8 | uint32(src.Pix[s00i+5])) * s00au / 0xffff
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx1)-src.Rect.Min.X)*8
+			s10au := (uint32(src.Pix[s10i+6])<<8 | uint32(src.Pix[s10i+7]))
+			s10ru := (uint32(src.Pix[s10i+0])<<8 | uint32(src.Pix[s10i+1])) * s10au / 0xffff
+			s10gu := (uint32(src.Pix[s10i+2])<<8 | uint32(src.Pix[s10i+3])) * s10au / 0xffff
+			s10bu := (uint32(src.Pix[s10i+4])<<8 | uint32(src.Pix[s10i+5])) * s10au / 0xffff
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx0)-src.Rect.Min.X)*8
+			s01au := (uint32(src.Pix[s01i+6])<<8 | uint32(src.Pix[s01i+7]))
+			s01ru := (uint32(src.Pix[s01i+0])<<8 | uint32(src.Pix[s01i+1])) * s01au / 0xffff
+			s01gu := (uint32(src.Pix[s01i+2])<<8 | uint32(src.Pix[s01i+3])) * s01au / 0xffff
+			s01bu := (uint32(src.Pix[s01i+4])<<8 | uint32(src.Pix[s01i+5])) * s01au / 0xffff
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx1)-src.Rect.Min.X)*8
+			s11au := (uint32(src.Pix[s11i+6])<<8 | uint32(src.Pix[s11i+7]))
+			s11ru := (uint32(src.Pix[s11i+0])<<8 | uint32(src.Pix[s11i+1])) * s11au / 0xffff
+			s11gu := (uint32(src.Pix[s11i+2])<<8 | uint32(src.Pix[s11i+3])) * s11au / 0xffff
+			s11bu := (uint32(src.Pix[s11i+4])<<8 | uint32(src.Pix[s11i+5])) * s11au / 0xffff
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			pr0 := pr
+			pg0 := pg
+			pb0 := pb
+			pa0 := pa
+			if pa0 != 0 && pa0 != 0xffff {
+				pr0 = pr0 * 0xffff / pa0
+				pg0 = pg0 * 0xffff / pa0
+				pb0 = pb0 * 0xffff / pa0
+			}
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
+
+func (ablInterpolator) scale_NRGBA64_RGBA64_Over(dst *image.NRGBA64, dr, adr image.Rectangle, src *image.RGBA64, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx0)-src.Rect.Min.X)*8
+			s00ru := (uint32(src.Pix[s00i+0])<<8 | uint32(src.Pix[s00i+1]))
+			s00gu := (uint32(src.Pix[s00i+2])<<8 | uint32(src.Pix[s00i+3]))
+			s00bu := (uint32(src.Pix[s00i+4])<<8 | uint32(src.Pix[s00i+5]))
+			s00au := (uint32(src.Pix[s00i+6])<<8 | uint32(src.Pix[s00i+7]))
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx1)-src.Rect.Min.X)*8
+			s10ru := (uint32(src.Pix[s10i+0])<<8 | uint32(src.Pix[s10i+1]))
+			s10gu := (uint32(src.Pix[s10i+2])<<8 | uint32(src.Pix[s10i+3]))
+			s10bu := (uint32(src.Pix[s10i+4])<<8 | uint32(src.Pix[s10i+5]))
+			s10au := (uint32(src.Pix[s10i+6])<<8 | uint32(src.Pix[s10i+7]))
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx0)-src.Rect.Min.X)*8
+			s01ru := (uint32(src.Pix[s01i+0])<<8 | uint32(src.Pix[s01i+1]))
+			s01gu := (uint32(src.Pix[s01i+2])<<8 | uint32(src.Pix[s01i+3]))
+			s01bu := (uint32(src.Pix[s01i+4])<<8 | uint32(src.Pix[s01i+5]))
+			s01au := (uint32(src.Pix[s01i+6])<<8 | uint32(src.Pix[s01i+7]))
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx1)-src.Rect.Min.X)*8
+			s11ru := (uint32(src.Pix[s11i+0])<<8 | uint32(src.Pix[s11i+1]))
+			s11gu := (uint32(src.Pix[s11i+2])<<8 | uint32(src.Pix[s11i+3]))
+			s11bu := (uint32(src.Pix[s11i+4])<<8 | uint32(src.Pix[s11i+5]))
+			s11au := (uint32(src.Pix[s11i+6])<<8 | uint32(src.Pix[s11i+7]))
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			pa1 := 0xffff - pa
+			pqa := (uint32(dst.Pix[d+6])<<8 | uint32(dst.Pix[d+7]))
+			pr0 := ((uint32(dst.Pix[d+0])<<8|uint32(dst.Pix[d+1]))*pqa/0xffff)*pa1/0xffff + pr
+			pg0 := ((uint32(dst.Pix[d+2])<<8|uint32(dst.Pix[d+3]))*pqa/0xffff)*pa1/0xffff + pg
+			pb0 := ((uint32(dst.Pix[d+4])<<8|uint32(dst.Pix[d+5]))*pqa/0xffff)*pa1/0xffff + pb
+			pa0 := pqa*pa1/0xffff + pa
+			if pa0 != 0 && pa0 != 0xffff {
+				pr0 = pr0 * 0xffff / pa0
+				pg0 = pg0 * 0xffff / pa0
+				pb0 = pb0 * 0xffff / pa0
+			}
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
+
+func (ablInterpolator) scale_NRGBA64_RGBA64_Src(dst *image.NRGBA64, dr, adr image.Rectangle, src *image.RGBA64, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx0)-src.Rect.Min.X)*8
+			s00ru := (uint32(src.Pix[s00i+0])<<8 | uint32(src.Pix[s00i+1]))
+			s00gu := (uint32(src.Pix[s00i+2])<<8 | uint32(src.Pix[s00i+3]))
+			s00bu := (uint32(src.Pix[s00i+4])<<8 | uint32(src.Pix[s00i+5]))
+			s00au := (uint32(src.Pix[s00i+6])<<8 | uint32(src.Pix[s00i+7]))
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10i := (sr.Min.Y+int(sy0)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx1)-src.Rect.Min.X)*8
+			s10ru := (uint32(src.Pix[s10i+0])<<8 | uint32(src.Pix[s10i+1]))
+			s10gu := (uint32(src.Pix[s10i+2])<<8 | uint32(src.Pix[s10i+3]))
+			s10bu := (uint32(src.Pix[s10i+4])<<8 | uint32(src.Pix[s10i+5]))
+			s10au := (uint32(src.Pix[s10i+6])<<8 | uint32(src.Pix[s10i+7]))
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx0)-src.Rect.Min.X)*8
+			s01ru := (uint32(src.Pix[s01i+0])<<8 | uint32(src.Pix[s01i+1]))
+			s01gu := (uint32(src.Pix[s01i+2])<<8 | uint32(src.Pix[s01i+3]))
+			s01bu := (uint32(src.Pix[s01i+4])<<8 | uint32(src.Pix[s01i+5]))
+			s01au := (uint32(src.Pix[s01i+6])<<8 | uint32(src.Pix[s01i+7]))
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11i := (sr.Min.Y+int(sy1)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(sx1)-src.Rect.Min.X)*8
+			s11ru := (uint32(src.Pix[s11i+0])<<8 | uint32(src.Pix[s11i+1]))
+			s11gu := (uint32(src.Pix[s11i+2])<<8 | uint32(src.Pix[s11i+3]))
+			s11bu := (uint32(src.Pix[s11i+4])<<8 | uint32(src.Pix[s11i+5]))
+			s11au := (uint32(src.Pix[s11i+6])<<8 | uint32(src.Pix[s11i+7]))
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			pr0 := pr
+			pg0 := pg
+			pb0 := pb
+			pa0 := pa
+			if pa0 != 0 && pa0 != 0xffff {
+				pr0 = pr0 * 0xffff / pa0
+				pg0 = pg0 * 0xffff / pa0
+				pb0 = pb0 * 0xffff / pa0
+			}
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
+
+func (ablInterpolator) scale_NRGBA64_RGBA64Image_Over(dst *image.NRGBA64, dr, adr image.Rectangle, src image.RGBA64Image, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00u := src.RGBA64At(sr.Min.X+int(sx0), sr.Min.Y+int(sy0))
+			s00r := float64(s00u.R)
+			s00g := float64(s00u.G)
+			s00b := float64(s00u.B)
+			s00a := float64(s00u.A)
+			s10u := src.RGBA64At(sr.Min.X+int(sx1), sr.Min.Y+int(sy0))
+			s10r := float64(s10u.R)
+			s10g := float64(s10u.G)
+			s10b := float64(s10u.B)
+			s10a := float64(s10u.A)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01u := src.RGBA64At(sr.Min.X+int(sx0), sr.Min.Y+int(sy1))
+			s01r := float64(s01u.R)
+			s01g := float64(s01u.G)
+			s01b := float64(s01u.B)
+			s01a := float64(s01u.A)
+			s11u := src.RGBA64At(sr.Min.X+int(sx1), sr.Min.Y+int(sy1))
+			s11r := float64(s11u.R)
+			s11g := float64(s11u.G)
+			s11b := float64(s11u.B)
+			s11a := float64(s11u.A)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			p := color.RGBA64{uint16(s11r), uint16(s11g), uint16(s11b), uint16(s11a)}
+			pa1 := 0xffff - uint32(p.A)
+			pqa := (uint32(dst.Pix[d+6])<<8 | uint32(dst.Pix[d+7]))
+			pr0 := ((uint32(dst.Pix[d+0])<<8|uint32(dst.Pix[d+1]))*pqa/0xffff)*pa1/0xffff + uint32(p.R)
+			pg0 := ((uint32(dst.Pix[d+2])<<8|uint32(dst.Pix[d+3]))*pqa/0xffff)*pa1/0xffff + uint32(p.G)
+			pb0 := ((uint32(dst.Pix[d+4])<<8|uint32(dst.Pix[d+5]))*pqa/0xffff)*pa1/0xffff + uint32(p.B)
+			pa0 := pqa*pa1/0xffff + uint32(p.A)
+			if pa0 != 0 && pa0 != 0xffff {
+				pr0 = pr0 * 0xffff / pa0
+				pg0 = pg0 * 0xffff / pa0
+				pb0 = pb0 * 0xffff / pa0
+			}
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
+
+func (ablInterpolator) scale_NRGBA64_RGBA64Image_Src(dst *image.NRGBA64, dr, adr image.Rectangle, src image.RGBA64Image, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00u := src.RGBA64At(sr.Min.X+int(sx0), sr.Min.Y+int(sy0))
+			s00r := float64(s00u.R)
+			s00g := float64(s00u.G)
+			s00b := float64(s00u.B)
+			s00a := float64(s00u.A)
+			s10u := src.RGBA64At(sr.Min.X+int(sx1), sr.Min.Y+int(sy0))
+			s10r := float64(s10u.R)
+			s10g := float64(s10u.G)
+			s10b := float64(s10u.B)
+			s10a := float64(s10u.A)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01u := src.RGBA64At(sr.Min.X+int(sx0), sr.Min.Y+int(sy1))
+			s01r := float64(s01u.R)
+			s01g := float64(s01u.G)
+			s01b := float64(s01u.B)
+			s01a := float64(s01u.A)
+			s11u := src.RGBA64At(sr.Min.X+int(sx1), sr.Min.Y+int(sy1))
+			s11r := float64(s11u.R)
+			s11g := float64(s11u.G)
+			s11b := float64(s11u.B)
+			s11a := float64(s11u.A)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			p := color.RGBA64{uint16(s11r), uint16(s11g), uint16(s11b), uint16(s11a)}
+			pr0 := uint32(p.R)
+			pg0 := uint32(p.G)
+			pb0 := uint32(p.B)
+			pa0 := uint32(p.A)
+			if pa0 != 0 && pa0 != 0xffff {
+				pr0 = pr0 * 0xffff / pa0
+				pg0 = pg0 * 0xffff / pa0
+				pb0 = pb0 * 0xffff / pa0
+			}
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
+
+func (ablInterpolator) scale_NRGBA64_Image_Over(dst *image.NRGBA64, dr, adr image.Rectangle, src image.Image, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00ru, s00gu, s00bu, s00au := src.At(sr.Min.X+int(sx0), sr.Min.Y+int(sy0)).RGBA()
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10ru, s10gu, s10bu, s10au := src.At(sr.Min.X+int(sx1), sr.Min.Y+int(sy0)).RGBA()
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01ru, s01gu, s01bu, s01au := src.At(sr.Min.X+int(sx0), sr.Min.Y+int(sy1)).RGBA()
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11ru, s11gu, s11bu, s11au := src.At(sr.Min.X+int(sx1), sr.Min.Y+int(sy1)).RGBA()
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			pa1 := 0xffff - pa
+			pqa := (uint32(dst.Pix[d+6])<<8 | uint32(dst.Pix[d+7]))
+			pr0 := ((uint32(dst.Pix[d+0])<<8|uint32(dst.Pix[d+1]))*pqa/0xffff)*pa1/0xffff + pr
+			pg0 := ((uint32(dst.Pix[d+2])<<8|uint32(dst.Pix[d+3]))*pqa/0xffff)*pa1/0xffff + pg
+			pb0 := ((uint32(dst.Pix[d+4])<<8|uint32(dst.Pix[d+5]))*pqa/0xffff)*pa1/0xffff + pb
+			pa0 := pqa*pa1/0xffff + pa
+			if pa0 != 0 && pa0 != 0xffff {
+				pr0 = pr0 * 0xffff / pa0
+				pg0 = pg0 * 0xffff / pa0
+				pb0 = pb0 * 0xffff / pa0
+			}
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
+
+func (ablInterpolator) scale_NRGBA64_Image_Src(dst *image.NRGBA64, dr, adr image.Rectangle, src image.Image, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00ru, s00gu, s00bu, s00au := src.At(sr.Min.X+int(sx0), sr.Min.Y+int(sy0)).RGBA()
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10ru, s10gu, s10bu, s10au := src.At(sr.Min.X+int(sx1), sr.Min.Y+int(sy0)).RGBA()
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01ru, s01gu, s01bu, s01au := src.At(sr.Min.X+int(sx0), sr.Min.Y+int(sy1)).RGBA()
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11ru, s11gu, s11bu, s11au := src.At(sr.Min.X+int(sx1), sr.Min.Y+int(sy1)).RGBA()
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			pr0 := pr
+			pg0 := pg
+			pb0 := pb
+			pa0 := pa
+			if pa0 != 0 && pa0 != 0xffff {
+				pr0 = pr0 * 0xffff / pa0
+				pg0 = pg0 * 0xffff / pa0
+				pb0 = pb0 * 0xffff / pa0
+			}
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
+
+func (ablInterpolator) scale_RGBA64Image_RGBA64Image_Over(dst RGBA64Image, dr, adr image.Rectangle, src image.RGBA64Image, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+	srcMask, smp := opts.SrcMask, opts.SrcMaskP
+	dstMask, dmp := opts.DstMask, opts.DstMaskP
+	dstColorRGBA64 := color.RGBA64{}
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00u := src.RGBA64At(sr.Min.X+int(sx0), sr.Min.Y+int(sy0))
+			if srcMask != nil {
+				_, _, _, ma := srcMask.At(smp.X+sr.Min.X+int(sx0), smp.Y+sr.Min.Y+int(sy0)).RGBA()
+				s00u.R = uint16(uint32(s00u.R) * ma / 0xffff)
+				s00u.G = uint16(uint32(s00u.G) * ma / 0xffff)
+				s00u.B = uint16(uint32(s00u.B) * ma / 0xffff)
+				s00u.A = uint16(uint32(s00u.A) * ma / 0xffff)
+			}
+			s00r := float64(s00u.R)
+			s00g := float64(s00u.G)
+			s00b := float64(s00u.B)
+			s00a := float64(s00u.A)
+			s10u := src.RGBA64At(sr.Min.X+int(sx1), sr.Min.Y+int(sy0))
+			if srcMask != nil {
+				_, _, _, ma := srcMask.At(smp.X+sr.Min.X+int(sx1), smp.Y+sr.Min.Y+int(sy0)).RGBA()
+				s10u.R = uint16(uint32(s10u.R) * ma / 0xffff)
+				s10u.G = uint16(uint32(s10u.G) * ma / 0xffff)
+				s10u.B = uint16(uint32(s10u.B) * ma / 0xffff)
+				s10u.A = uint16(uint32(s10u.A) * ma / 0xffff)
+			}
+			s10r := float64(s10u.R)
+			s10g := float64(s10u.G)
+			s10b := float64(s10u.B)
+			s10a := float64(s10u.A)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01u := src.RGBA64At(sr.Min.X+int(sx0), sr.Min.Y+int(sy1))
+			if srcMask != nil {
+				_, _, _, ma := srcMask.At(smp.X+sr.Min.X+int(sx0), smp.Y+sr.Min.Y+int(sy1)).RGBA()
+				s01u.R = uint16(uint32(s01u.R) * ma / 0xffff)
+				s01u.G = uint16(uint32(s01u.G) * ma / 0xffff)
+				s01u.B = uint16(uint32(s01u.B) * ma / 0xffff)
+				s01u.A = uint16(uint32(s01u.A) * ma / 0xffff)
+			}
+			s01r := float64(s01u.R)
+			s01g := float64(s01u.G)
+			s01b := float64(s01u.B)
+			s01a := float64(s01u.A)
+			s11u := src.RGBA64At(sr.Min.X+int(sx1), sr.Min.Y+int(sy1))
+			if srcMask != nil {
+				_, _, _, ma := srcMask.At(smp.X+sr.Min.X+int(sx1), smp.Y+sr.Min.Y+int(sy1)).RGBA()
+				s11u.R = uint16(uint32(s11u.R) * ma / 0xffff)
+				s11u.G = uint16(uint32(s11u.G) * ma / 0xffff)
+				s11u.B = uint16(uint32(s11u.B) * ma / 0xffff)
+				s11u.A = uint16(uint32(s11u.A) * ma / 0xffff)
+			}
+			s11r := float64(s11u.R)
+			s11g := float64(s11u.G)
+			s11b := float64(s11u.B)
+			s11a := float64(s11u.A)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			p := color.RGBA64{uint16(s11r), uint16(s11g), uint16(s11b), uint16(s11a)}
+			q := dst.RGBA64At(dr.Min.X+int(dx), dr.Min.Y+int(dy))
+			if dstMask != nil {
+				_, _, _, ma := dstMask.At(dmp.X+dr.Min.X+int(dx), dmp.Y+dr.Min.Y+int(dy)).RGBA()
+				p.R = uint16(uint32(p.R) * ma / 0xffff)
+				p.G = uint16(uint32(p.G) * ma / 0xffff)
+				p.B = uint16(uint32(p.B) * ma / 0xffff)
+				p.A = uint16(uint32(p.A) * ma / 0xffff)
+			}
+			pa1 := 0xffff - uint32(p.A)
+			dstColorRGBA64.R = uint16(uint32(q.R)*pa1/0xffff + uint32(p.R))
+			dstColorRGBA64.G = uint16(uint32(q.G)*pa1/0xffff + uint32(p.G))
+			dstColorRGBA64.B = uint16(uint32(q.B)*pa1/0xffff + uint32(p.B))
+			dstColorRGBA64.A = uint16(uint32(q.A)*pa1/0xffff + uint32(p.A))
+			dst.SetRGBA64(dr.Min.X+int(dx), dr.Min.Y+int(dy), dstColorRGBA64)
+		}
+	}
+}
+
+func (ablInterpolator) scale_RGBA64Image_RGBA64Image_Src(dst RGBA64Image, dr, adr image.Rectangle, src image.RGBA64Image, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+	srcMask, smp := opts.SrcMask, opts.SrcMaskP
+	dstMask, dmp := opts.DstMask, opts.DstMaskP
+	dstColorRGBA64 := color.RGBA64{}
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00u := src.RGBA64At(sr.Min.X+int(sx0), sr.Min.Y+int(sy0))
+			if srcMask != nil {
+				_, _, _, ma := srcMask.At(smp.X+sr.Min.X+int(sx0), smp.Y+sr.Min.Y+int(sy0)).RGBA()
+				s00u.R = uint16(uint32(s00u.R) * ma / 0xffff)
+				s00u.G = uint16(uint32(s00u.G) * ma / 0xffff)
+				s00u.B = uint16(uint32(s00u.B) * ma / 0xffff)
+				s00u.A = uint16(uint32(s00u.A) * ma / 0xffff)
+			}
+			s00r := float64(s00u.R)
+			s00g := float64(s00u.G)
+			s00b := float64(s00u.B)
+			s00a := float64(s00u.A)
+			s10u := src.RGBA64At(sr.Min.X+int(sx1), sr.Min.Y+int(sy0))
+			if srcMask != nil {
+				_, _, _, ma := srcMask.At(smp.X+sr.Min.X+int(sx1), smp.Y+sr.Min.Y+int(sy0)).RGBA()
+				s10u.R = uint16(uint32(s10u.R) * ma / 0xffff)
+				s10u.G = uint16(uint32(s10u.G) * ma / 0xffff)
+				s10u.B = uint16(uint32(s10u.B) * ma / 0xffff)
+				s10u.A = uint16(uint32(s10u.A) * ma / 0xffff)
+			}
+			s10r := float64(s10u.R)
+			s10g := float64(s10u.G)
+			s10b := float64(s10u.B)
+			s10a := float64(s10u.A)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01u := src.RGBA64At(sr.Min.X+int(sx0), sr.Min.Y+int(sy1))
+			if srcMask != nil {
+				_, _, _, ma := srcMask.At(smp.X+sr.Min.X+int(sx0), smp.Y+sr.Min.Y+int(sy1)).RGBA()
+				s01u.R = uint16(uint32(s01u.R) * ma / 0xffff)
+				s01u.G = uint16(uint32(s01u.G) * ma / 0xffff)
+				s01u.B = uint16(uint32(s01u.B) * ma / 0xffff)
+				s01u.A = uint16(uint32(s01u.A) * ma / 0xffff)
+			}
+			s01r := float64(s01u.R)
+			s01g := float64(s01u.G)
+			s01b := float64(s01u.B)
+			s01a := float64(s01u.A)
+			s11u := src.RGBA64At(sr.Min.X+int(sx1), sr.Min.Y+int(sy1))
+			if srcMask != nil {
+				_, _, _, ma := srcMask.At(smp.X+sr.Min.X+int(sx1), smp.Y+sr.Min.Y+int(sy1)).RGBA()
+				s11u.R = uint16(uint32(s11u.R) * ma / 0xffff)
+				s11u.G = uint16(uint32(s11u.G) * ma / 0xffff)
+				s11u.B = uint16(uint32(s11u.B) * ma / 0xffff)
+				s11u.A = uint16(uint32(s11u.A) * ma / 0xffff)
+			}
+			s11r := float64(s11u.R)
+			s11g := float64(s11u.G)
+			s11b := float64(s11u.B)
+			s11a := float64(s11u.A)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			p := color.RGBA64{uint16(s11r), uint16(s11g), uint16(s11b), uint16(s11a)}
+			if dstMask != nil {
+				q := dst.RGBA64At(dr.Min.X+int(dx), dr.Min.Y+int(dy))
+				_, _, _, ma := dstMask.At(dmp.X+dr.Min.X+int(dx), dmp.Y+dr.Min.Y+int(dy)).RGBA()
+				p.R = uint16(uint32(p.R) * ma / 0xffff)
+				p.G = uint16(uint32(p.G) * ma / 0xffff)
+				p.B = uint16(uint32(p.B) * ma / 0xffff)
+				p.A = uint16(uint32(p.A) * ma / 0xffff)
+				pa1 := 0xffff - ma
+				dstColorRGBA64.R = uint16(uint32(q.R)*pa1/0xffff + uint32(p.R))
+				dstColorRGBA64.G = uint16(uint32(q.G)*pa1/0xffff + uint32(p.G))
+				dstColorRGBA64.B = uint16(uint32(q.B)*pa1/0xffff + uint32(p.B))
+				dstColorRGBA64.A = uint16(uint32(q.A)*pa1/0xffff + uint32(p.A))
+				dst.SetRGBA64(dr.Min.X+int(dx), dr.Min.Y+int(dy), dstColorRGBA64)
+			} else {
+				dst.SetRGBA64(dr.Min.X+int(dx), dr.Min.Y+int(dy), p)
+			}
+		}
+	}
+}
+
+func (ablInterpolator) scale_Image_Image_Over(dst Image, dr, adr image.Rectangle, src image.Image, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+	srcMask, smp := opts.SrcMask, opts.SrcMaskP
+	dstMask, dmp := opts.DstMask, opts.DstMaskP
+	dstColorRGBA64 := &color.RGBA64{}
+	dstColor := color.Color(dstColorRGBA64)
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00ru, s00gu, s00bu, s00au := src.At(sr.Min.X+int(sx0), sr.Min.Y+int(sy0)).RGBA()
+			if srcMask != nil {
+				_, _, _, ma := srcMask.At(smp.X+sr.Min.X+int(sx0), smp.Y+sr.Min.Y+int(sy0)).RGBA()
+				s00ru = s00ru * ma / 0xffff
+				s00gu = s00gu * ma / 0xffff
+				s00bu = s00bu * ma / 0xffff
+				s00au = s00au * ma / 0xffff
+			}
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10ru, s10gu, s10bu, s10au := src.At(sr.Min.X+int(sx1), sr.Min.Y+int(sy0)).RGBA()
+			if srcMask != nil {
+				_, _, _, ma := srcMask.At(smp.X+sr.Min.X+int(sx1), smp.Y+sr.Min.Y+int(sy0)).RGBA()
+				s10ru = s10ru * ma / 0xffff
+				s10gu = s10gu * ma / 0xffff
+				s10bu = s10bu * ma / 0xffff
+				s10au = s10au * ma / 0xffff
+			}
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01ru, s01gu, s01bu, s01au := src.At(sr.Min.X+int(sx0), sr.Min.Y+int(sy1)).RGBA()
+			if srcMask != nil {
+				_, _, _, ma := srcMask.At(smp.X+sr.Min.X+int(sx0), smp.Y+sr.Min.Y+int(sy1)).RGBA()
+				s01ru = s01ru * ma / 0xffff
+				s01gu = s01gu * ma / 0xffff
+				s01bu = s01bu * ma / 0xffff
+				s01au = s01au * ma / 0xffff
+			}
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11ru, s11gu, s11bu, s11au := src.At(sr.Min.X+int(sx1), sr.Min.Y+int(sy1)).RGBA()
+			if srcMask != nil {
+				_, _, _, ma := srcMask.At(smp.X+sr.Min.X+int(sx1), smp.Y+sr.Min.Y+int(sy1)).RGBA()
+				s11ru = s11ru * ma / 0xffff
+				s11gu = s11gu * ma / 0xffff
+				s11bu = s11bu * ma / 0xffff
+				s11au = s11au * ma / 0xffff
+			}
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			qr, qg, qb, qa := dst.At(dr.Min.X+int(dx), dr.Min.Y+int(dy)).RGBA()
+			if dstMask != nil {
+				_, _, _, ma := dstMask.At(dmp.X+dr.Min.X+int(dx), dmp.Y+dr.Min.Y+int(dy)).RGBA()
+				pr = pr * ma / 0xffff
+				pg = pg * ma / 0xffff
+				pb = pb * ma / 0xffff
+				pa = pa * ma / 0xffff
+			}
+			pa1 := 0xffff - pa
+			dstColorRGBA64.R = uint16(qr*pa1/0xffff + pr)
+			dstColorRGBA64.G = uint16(qg*pa1/0xffff + pg)
+			dstColorRGBA64.B = uint16(qb*pa1/0xffff + pb)
+			dstColorRGBA64.A = uint16(qa*pa1/0xffff + pa)
+			dst.Set(dr.Min.X+int(dx), dr.Min.Y+int(dy), dstColor)
+		}
+	}
+}
+
+func (ablInterpolator) scale_Image_Image_Src(dst Image, dr, adr image.Rectangle, src image.Image, sr image.Rectangle, opts *Options) {
+	sw := int32(sr.Dx())
+	sh := int32(sr.Dy())
+	yscale := float64(sh) / float64(dr.Dy())
+	xscale := float64(sw) / float64(dr.Dx())
+	swMinus1, shMinus1 := sw-1, sh-1
+	srcMask, smp := opts.SrcMask, opts.SrcMaskP
+	dstMask, dmp := opts.DstMask, opts.DstMaskP
+	dstColorRGBA64 := &color.RGBA64{}
+	dstColor := color.Color(dstColorRGBA64)
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		sy := float64((float64(dy)+0.5)*yscale) - 0.5
+		// If sy < 0, we will clamp sy0 to 0 anyway, so it doesn't matter if
+		// we say int32(sy) instead of int32(math.Floor(sy)). Similarly for
+		// sx, below.
+		sy0 := int32(sy)
+		yFrac0 := sy - float64(sy0)
+		yFrac1 := 1 - yFrac0
+		sy1 := sy0 + 1
+		if sy < 0 {
+			sy0, sy1 = 0, 0
+			yFrac0, yFrac1 = 0, 1
+		} else if sy1 > shMinus1 {
+			sy0, sy1 = shMinus1, shMinus1
+			yFrac0, yFrac1 = 1, 0
+		}
+
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
+			sx := float64((float64(dx)+0.5)*xscale) - 0.5
+			sx0 := int32(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx1 := sx0 + 1
+			if sx < 0 {
+				sx0, sx1 = 0, 0
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 > swMinus1 {
+				sx0, sx1 = swMinus1, swMinus1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			s00ru, s00gu, s00bu, s00au := src.At(sr.Min.X+int(sx0), sr.Min.Y+int(sy0)).RGBA()
+			if srcMask != nil {
+				_, _, _, ma := srcMask.At(smp.X+sr.Min.X+int(sx0), smp.Y+sr.Min.Y+int(sy0)).RGBA()
+				s00ru = s00ru * ma / 0xffff
+				s00gu = s00gu * ma / 0xffff
+				s00bu = s00bu * ma / 0xffff
+				s00au = s00au * ma / 0xffff
+			}
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10ru, s10gu, s10bu, s10au := src.At(sr.Min.X+int(sx1), sr.Min.Y+int(sy0)).RGBA()
+			if srcMask != nil {
+				_, _, _, ma := srcMask.At(smp.X+sr.Min.X+int(sx1), smp.Y+sr.Min.Y+int(sy0)).RGBA()
+				s10ru = s10ru * ma / 0xffff
+				s10gu = s10gu * ma / 0xffff
+				s10bu = s10bu * ma / 0xffff
+				s10au = s10au * ma / 0xffff
+			}
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01ru, s01gu, s01bu, s01au := src.At(sr.Min.X+int(sx0), sr.Min.Y+int(sy1)).RGBA()
+			if srcMask != nil {
+				_, _, _, ma := srcMask.At(smp.X+sr.Min.X+int(sx0), smp.Y+sr.Min.Y+int(sy1)).RGBA()
+				s01ru = s01ru * ma / 0xffff
+				s01gu = s01gu * ma / 0xffff
+				s01bu = s01bu * ma / 0xffff
+				s01au = s01au * ma / 0xffff
+			}
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11ru, s11gu, s11bu, s11au := src.At(sr.Min.X+int(sx1), sr.Min.Y+int(sy1)).RGBA()
+			if srcMask != nil {
+				_, _, _, ma := srcMask.At(smp.X+sr.Min.X+int(sx1), smp.Y+sr.Min.Y+int(sy1)).RGBA()
+				s11ru = s11ru * ma / 0xffff
+				s11gu = s11gu * ma / 0xffff
+				s11bu = s11bu * ma / 0xffff
+				s11au = s11au * ma / 0xffff
+			}
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			if dstMask != nil {
+				qr, qg, qb, qa := dst.At(dr.Min.X+int(dx), dr.Min.Y+int(dy)).RGBA()
+				_, _, _, ma := dstMask.At(dmp.X+dr.Min.X+int(dx), dmp.Y+dr.Min.Y+int(dy)).RGBA()
+				pr = pr * ma / 0xffff
+				pg = pg * ma / 0xffff
+				pb = pb * ma / 0xffff
+				pa = pa * ma / 0xffff
+				pa1 := 0xffff - ma
+				dstColorRGBA64.R = uint16(qr*pa1/0xffff + pr)
+				dstColorRGBA64.G = uint16(qg*pa1/0xffff + pg)
+				dstColorRGBA64.B = uint16(qb*pa1/0xffff + pb)
+				dstColorRGBA64.A = uint16(qa*pa1/0xffff + pa)
+				dst.Set(dr.Min.X+int(dx), dr.Min.Y+int(dy), dstColor)
+			} else {
+				dstColorRGBA64.R = uint16(pr)
+				dstColorRGBA64.G = uint16(pg)
+				dstColorRGBA64.B = uint16(pb)
+				dstColorRGBA64.A = uint16(pa)
+				dst.Set(dr.Min.X+int(dx), dr.Min.Y+int(dy), dstColor)
+			}
+		}
+	}
+}
+
+func (ablInterpolator) transform_Alpha_Alpha_Over(dst *image.Alpha, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.Alpha, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00i := (sy0-src.Rect.Min.Y)*src.Stride + (sx0 - src.Rect.Min.X)
+			s00au := uint32(src.Pix[s00i+0]) * 0x101
+			s00a := float64(s00au)
+			s10i := (sy0-src.Rect.Min.Y)*src.Stride + (sx1 - src.Rect.Min.X)
+			s10au := uint32(src.Pix[s10i+0]) * 0x101
+			s10a := float64(s10au)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01i := (sy1-src.Rect.Min.Y)*src.Stride + (sx0 - src.Rect.Min.X)
+			s01au := uint32(src.Pix[s01i+0]) * 0x101
+			s01a := float64(s01au)
+			s11i := (sy1-src.Rect.Min.Y)*src.Stride + (sx1 - src.Rect.Min.X)
+			s11au := uint32(src.Pix[s11i+0]) * 0x101
+			s11a := float64(s11au)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pa := uint32(s11a)
+			pa1 := (0xffff - pa) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pa) >> 8)
+		}
+	}
+}
+
+func (ablInterpolator) transform_Alpha_Alpha_Src(dst *image.Alpha, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.Alpha, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00i := (sy0-src.Rect.Min.Y)*src.Stride + (sx0 - src.Rect.Min.X)
+			s00au := uint32(src.Pix[s00i+0]) * 0x101
+			s00a := float64(s00au)
+			s10i := (sy0-src.Rect.Min.Y)*src.Stride + (sx1 - src.Rect.Min.X)
+			s10au := uint32(src.Pix[s10i+0]) * 0x101
+			s10a := float64(s10au)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01i := (sy1-src.Rect.Min.Y)*src.Stride + (sx0 - src.Rect.Min.X)
+			s01au := uint32(src.Pix[s01i+0]) * 0x101
+			s01a := float64(s01au)
+			s11i := (sy1-src.Rect.Min.Y)*src.Stride + (sx1 - src.Rect.Min.X)
+			s11au := uint32(src.Pix[s11i+0]) * 0x101
+			s11a := float64(s11au)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pa := uint32(s11a)
+			dst.Pix[d+0] = uint8(pa >> 8)
+		}
+	}
+}
+
+func (ablInterpolator) transform_Alpha_RGBA64Image_Over(dst *image.Alpha, dr, adr image.Rectangle, d2s *f64.Aff3, src image.RGBA64Image, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00u := src.RGBA64At(sx0, sy0)
+			s00a := float64(s00u.A)
+			s10u := src.RGBA64At(sx1, sy0)
+			s10a := float64(s10u.A)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01u := src.RGBA64At(sx0, sy1)
+			s01a := float64(s01u.A)
+			s11u := src.RGBA64At(sx1, sy1)
+			s11a := float64(s11u.A)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			p := color.RGBA64{A: uint16(s11a)}
+			pa1 := (0xffff - uint32(p.A)) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + uint32(p.A)) >> 8)
+		}
+	}
+}
+
+func (ablInterpolator) transform_Alpha_RGBA64Image_Src(dst *image.Alpha, dr, adr image.Rectangle, d2s *f64.Aff3, src image.RGBA64Image, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00u := src.RGBA64At(sx0, sy0)
+			s00a := float64(s00u.A)
+			s10u := src.RGBA64At(sx1, sy0)
+			s10a := float64(s10u.A)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01u := src.RGBA64At(sx0, sy1)
+			s01a := float64(s01u.A)
+			s11u := src.RGBA64At(sx1, sy1)
+			s11a := float64(s11u.A)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			p := color.RGBA64{A: uint16(s11a)}
+			dst.Pix[d+0] = uint8(p.A >> 8)
+		}
+	}
+}
+
+func (ablInterpolator) transform_Alpha_Image_Over(dst *image.Alpha, dr, adr image.Rectangle, d2s *f64.Aff3, src image.Image, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			_, _, _, s00au := src.At(sx0, sy0).RGBA()
+			s00a := float64(s00au)
+			_, _, _, s10au := src.At(sx1, sy0).RGBA()
+			s10a := float64(s10au)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			_, _, _, s01au := src.At(sx0, sy1).RGBA()
+			s01a := float64(s01au)
+			_, _, _, s11au := src.At(sx1, sy1).RGBA()
+			s11a := float64(s11au)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pa := uint32(s11a)
+			pa1 := (0xffff - pa) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pa) >> 8)
+		}
+	}
+}
+
+func (ablInterpolator) transform_Alpha_Image_Src(dst *image.Alpha, dr, adr image.Rectangle, d2s *f64.Aff3, src image.Image, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			_, _, _, s00au := src.At(sx0, sy0).RGBA()
+			s00a := float64(s00au)
+			_, _, _, s10au := src.At(sx1, sy0).RGBA()
+			s10a := float64(s10au)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			_, _, _, s01au := src.At(sx0, sy1).RGBA()
+			s01a := float64(s01au)
+			_, _, _, s11au := src.At(sx1, sy1).RGBA()
+			s11a := float64(s11au)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pa := uint32(s11a)
+			dst.Pix[d+0] = uint8(pa >> 8)
+		}
+	}
+}
+
+func (ablInterpolator) transform_Gray_Gray_Src(dst *image.Gray, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.Gray, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00i := (sy0-src.Rect.Min.Y)*src.Stride + (sx0 - src.Rect.Min.X)
+			s00ru := uint32(src.Pix[s00i]) * 0x101
+			s00r := float64(s00ru)
+			s10i := (sy0-src.Rect.Min.Y)*src.Stride + (sx1 - src.Rect.Min.X)
+			s10ru := uint32(src.Pix[s10i]) * 0x101
+			s10r := float64(s10ru)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s01i := (sy1-src.Rect.Min.Y)*src.Stride + (sx0 - src.Rect.Min.X)
+			s01ru := uint32(src.Pix[s01i]) * 0x101
+			s01r := float64(s01ru)
+			s11i := (sy1-src.Rect.Min.Y)*src.Stride + (sx1 - src.Rect.Min.X)
+			s11ru := uint32(src.Pix[s11i]) * 0x101
+			s11r := float64(s11ru)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			pr := uint32(s11r)
+			dst.Pix[d] = uint8(pr >> 8)
+		}
+	}
+}
+
+func (ablInterpolator) transform_Gray_RGBA64Image_Over(dst *image.Gray, dr, adr image.Rectangle, d2s *f64.Aff3, src image.RGBA64Image, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00u := src.RGBA64At(sx0, sy0)
+			s00r := float64(s00u.R)
+			s00g := float64(s00u.G)
+			s00b := float64(s00u.B)
+			s00a := float64(s00u.A)
+			s10u := src.RGBA64At(sx1, sy0)
+			s10r := float64(s10u.R)
+			s10g := float64(s10u.G)
+			s10b := float64(s10u.B)
+			s10a := float64(s10u.A)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01u := src.RGBA64At(sx0, sy1)
+			s01r := float64(s01u.R)
+			s01g := float64(s01u.G)
+			s01b := float64(s01u.B)
+			s01a := float64(s01u.A)
+			s11u := src.RGBA64At(sx1, sy1)
+			s11r := float64(s11u.R)
+			s11g := float64(s11u.G)
+			s11b := float64(s11u.B)
+			s11a := float64(s11u.A)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			p := color.RGBA64{uint16(s11r), uint16(s11g), uint16(s11b), uint16(s11a)}
+			pa1 := 0xffff - uint32(p.A)
+			pq := uint32(dst.Pix[d]) * 0x101 * pa1 / 0xffff
+			dst.Pix[d] = uint8((19595*(pq+uint32(p.R)) + 38470*(pq+uint32(p.G)) + 7471*(pq+uint32(p.B)) + 1<<15) >> 24)
+		}
+	}
+}
+
+func (ablInterpolator) transform_Gray_RGBA64Image_Src(dst *image.Gray, dr, adr image.Rectangle, d2s *f64.Aff3, src image.RGBA64Image, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00u := src.RGBA64At(sx0, sy0)
+			s00r := float64(s00u.R)
+			s00g := float64(s00u.G)
+			s00b := float64(s00u.B)
+			s00a := float64(s00u.A)
+			s10u := src.RGBA64At(sx1, sy0)
+			s10r := float64(s10u.R)
+			s10g := float64(s10u.G)
+			s10b := float64(s10u.B)
+			s10a := float64(s10u.A)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01u := src.RGBA64At(sx0, sy1)
+			s01r := float64(s01u.R)
+			s01g := float64(s01u.G)
+			s01b := float64(s01u.B)
+			s01a := float64(s01u.A)
+			s11u := src.RGBA64At(sx1, sy1)
+			s11r := float64(s11u.R)
+			s11g := float64(s11u.G)
+			s11b := float64(s11u.B)
+			s11a := float64(s11u.A)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			p := color.RGBA64{uint16(s11r), uint16(s11g), uint16(s11b), uint16(s11a)}
+			dst.Pix[d] = uint8((19595*uint32(p.R) + 38470*uint32(p.G) + 7471*uint32(p.B) + 1<<15) >> 24)
+		}
+	}
+}
+
+func (ablInterpolator) transform_Gray_Image_Over(dst *image.Gray, dr, adr image.Rectangle, d2s *f64.Aff3, src image.Image, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00ru, s00gu, s00bu, s00au := src.At(sx0, sy0).RGBA()
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10ru, s10gu, s10bu, s10au := src.At(sx1, sy0).RGBA()
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01ru, s01gu, s01bu, s01au := src.At(sx0, sy1).RGBA()
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11ru, s11gu, s11bu, s11au := src.At(sx1, sy1).RGBA()
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			pa1 := 0xffff - pa
+			pq := uint32(dst.Pix[d]) * 0x101 * pa1 / 0xffff
+			dst.Pix[d] = uint8((19595*(pq+pr) + 38470*(pq+pg) + 7471*(pq+pb) + 1<<15) >> 24)
+		}
+	}
+}
+
+func (ablInterpolator) transform_Gray_Image_Src(dst *image.Gray, dr, adr image.Rectangle, d2s *f64.Aff3, src image.Image, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00ru, s00gu, s00bu, s00au := src.At(sx0, sy0).RGBA()
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10ru, s10gu, s10bu, s10au := src.At(sx1, sy0).RGBA()
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01ru, s01gu, s01bu, s01au := src.At(sx0, sy1).RGBA()
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11ru, s11gu, s11bu, s11au := src.At(sx1, sy1).RGBA()
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			dst.Pix[d] = uint8((19595*pr + 38470*pg + 7471*pb + 1<<15) >> 24)
+		}
+	}
+}
+
+func (ablInterpolator) transform_RGBA_Gray_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.Gray, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00i := (sy0-src.Rect.Min.Y)*src.Stride + (sx0 - src.Rect.Min.X)
+			s00ru := uint32(src.Pix[s00i]) * 0x101
+			s00r := float64(s00ru)
+			s10i := (sy0-src.Rect.Min.Y)*src.Stride + (sx1 - src.Rect.Min.X)
+			s10ru := uint32(src.Pix[s10i]) * 0x101
+			s10r := float64(s10ru)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s01i := (sy1-src.Rect.Min.Y)*src.Stride + (sx0 - src.Rect.Min.X)
+			s01ru := uint32(src.Pix[s01i]) * 0x101
+			s01r := float64(s01ru)
+			s11i := (sy1-src.Rect.Min.Y)*src.Stride + (sx1 - src.Rect.Min.X)
+			s11ru := uint32(src.Pix[s11i]) * 0x101
+			s11r := float64(s11ru)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			pr := uint32(s11r)
+			out := uint8(pr >> 8)
+			dst.Pix[d+0] = out
+			dst.Pix[d+1] = out
+			dst.Pix[d+2] = out
+			dst.Pix[d+3] = 0xff
+		}
+	}
+}
+
+func (ablInterpolator) transform_RGBA_NRGBA_Over(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NRGBA, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00i := (sy0-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*4
+			s00au := uint32(src.Pix[s00i+3]) * 0x101
+			s00ru := uint32(src.Pix[s00i+0]) * s00au / 0xff
+			s00gu := uint32(src.Pix[s00i+1]) * s00au / 0xff
+			s00bu := uint32(src.Pix[s00i+2]) * s00au / 0xff
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10i := (sy0-src.Rect.Min.Y)*src.Stride + (sx1-src.Rect.Min.X)*4
+			s10au := uint32(src.Pix[s10i+3]) * 0x101
+			s10ru := uint32(src.Pix[s10i+0]) * s10au / 0xff
+			s10gu := uint32(src.Pix[s10i+1]) * s10au / 0xff
+			s10bu := uint32(src.Pix[s10i+2]) * s10au / 0xff
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01i := (sy1-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*4
+			s01au := uint32(src.Pix[s01i+3]) * 0x101
+			s01ru := uint32(src.Pix[s01i+0]) * s01au / 0xff
+			s01gu := uint32(src.Pix[s01i+1]) * s01au / 0xff
+			s01bu := uint32(src.Pix[s01i+2]) * s01au / 0xff
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11i := (sy1-src.Rect.Min.Y)*src.Stride + (sx1-src.Rect.Min.X)*4
+			s11au := uint32(src.Pix[s11i+3]) * 0x101
+			s11ru := uint32(src.Pix[s11i+0]) * s11au / 0xff
+			s11gu := uint32(src.Pix[s11i+1]) * s11au / 0xff
+			s11bu := uint32(src.Pix[s11i+2]) * s11au / 0xff
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			pa1 := (0xffff - pa) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pr) >> 8)
+			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + pg) >> 8)
+			dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*pa1/0xffff + pb) >> 8)
+			dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*pa1/0xffff + pa) >> 8)
+		}
+	}
+}
+
+func (ablInterpolator) transform_RGBA_NRGBA_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NRGBA, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00i := (sy0-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*4
+			s00au := uint32(src.Pix[s00i+3]) * 0x101
+			s00ru := uint32(src.Pix[s00i+0]) * s00au / 0xff
+			s00gu := uint32(src.Pix[s00i+1]) * s00au / 0xff
+			s00bu := uint32(src.Pix[s00i+2]) * s00au / 0xff
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10i := (sy0-src.Rect.Min.Y)*src.Stride + (sx1-src.Rect.Min.X)*4
+			s10au := uint32(src.Pix[s10i+3]) * 0x101
+			s10ru := uint32(src.Pix[s10i+0]) * s10au / 0xff
+			s10gu := uint32(src.Pix[s10i+1]) * s10au / 0xff
+			s10bu := uint32(src.Pix[s10i+2]) * s10au / 0xff
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01i := (sy1-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*4
+			s01au := uint32(src.Pix[s01i+3]) * 0x101
+			s01ru := uint32(src.Pix[s01i+0]) * s01au / 0xff
+			s01gu := uint32(src.Pix[s01i+1]) * s01au / 0xff
+			s01bu := uint32(src.Pix[s01i+2]) * s01au / 0xff
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11i := (sy1-src.Rect.Min.Y)*src.Stride + (sx1-src.Rect.Min.X)*4
+			s11au := uint32(src.Pix[s11i+3]) * 0x101
+			s11ru := uint32(src.Pix[s11i+0]) * s11au / 0xff
+			s11gu := uint32(src.Pix[s11i+1]) * s11au / 0xff
+			s11bu := uint32(src.Pix[s11i+2]) * s11au / 0xff
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			dst.Pix[d+0] = uint8(pr >> 8)
+			dst.Pix[d+1] = uint8(pg >> 8)
+			dst.Pix[d+2] = uint8(pb >> 8)
+			dst.Pix[d+3] = uint8(pa >> 8)
+		}
+	}
+}
+
+func (ablInterpolator) transform_RGBA_RGBA_Over(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.RGBA, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00i := (sy0-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*4
+			s00ru := uint32(src.Pix[s00i+0]) * 0x101
+			s00gu := uint32(src.Pix[s00i+1]) * 0x101
+			s00bu := uint32(src.Pix[s00i+2]) * 0x101
+			s00au := uint32(src.Pix[s00i+3]) * 0x101
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10i := (sy0-src.Rect.Min.Y)*src.Stride + (sx1-src.Rect.Min.X)*4
+			s10ru := uint32(src.Pix[s10i+0]) * 0x101
+			s10gu := uint32(src.Pix[s10i+1]) * 0x101
+			s10bu := uint32(src.Pix[s10i+2]) * 0x101
+			s10au := uint32(src.Pix[s10i+3]) * 0x101
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01i := (sy1-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*4
+			s01ru := uint32(src.Pix[s01i+0]) * 0x101
+			s01gu := uint32(src.Pix[s01i+1]) * 0x101
+			s01bu := uint32(src.Pix[s01i+2]) * 0x101
+			s01au := uint32(src.Pix[s01i+3]) * 0x101
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11i := (sy1-src.Rect.Min.Y)*src.Stride + (sx1-src.Rect.Min.X)*4
+			s11ru := uint32(src.Pix[s11i+0]) * 0x101
+			s11gu := uint32(src.Pix[s11i+1]) * 0x101
+			s11bu := uint32(src.Pix[s11i+2]) * 0x101
+			s11au := uint32(src.Pix[s11i+3]) * 0x101
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			pa1 := (0xffff - pa) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pr) >> 8)
+			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + pg) >> 8)
+			dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*pa1/0xffff + pb) >> 8)
+			dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*pa1/0xffff + pa) >> 8)
+		}
+	}
+}
+
+func (ablInterpolator) transform_RGBA_RGBA_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.RGBA, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00i := (sy0-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*4
+			s00ru := uint32(src.Pix[s00i+0]) * 0x101
+			s00gu := uint32(src.Pix[s00i+1]) * 0x101
+			s00bu := uint32(src.Pix[s00i+2]) * 0x101
+			s00au := uint32(src.Pix[s00i+3]) * 0x101
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10i := (sy0-src.Rect.Min.Y)*src.Stride + (sx1-src.Rect.Min.X)*4
+			s10ru := uint32(src.Pix[s10i+0]) * 0x101
+			s10gu := uint32(src.Pix[s10i+1]) * 0x101
+			s10bu := uint32(src.Pix[s10i+2]) * 0x101
+			s10au := uint32(src.Pix[s10i+3]) * 0x101
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01i := (sy1-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*4
+			s01ru := uint32(src.Pix[s01i+0]) * 0x101
+			s01gu := uint32(src.Pix[s01i+1]) * 0x101
+			s01bu := uint32(src.Pix[s01i+2]) * 0x101
+			s01au := uint32(src.Pix[s01i+3]) * 0x101
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11i := (sy1-src.Rect.Min.Y)*src.Stride + (sx1-src.Rect.Min.X)*4
+			s11ru := uint32(src.Pix[s11i+0]) * 0x101
+			s11gu := uint32(src.Pix[s11i+1]) * 0x101
+			s11bu := uint32(src.Pix[s11i+2]) * 0x101
+			s11au := uint32(src.Pix[s11i+3]) * 0x101
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			dst.Pix[d+0] = uint8(pr >> 8)
+			dst.Pix[d+1] = uint8(pg >> 8)
+			dst.Pix[d+2] = uint8(pb >> 8)
+			dst.Pix[d+3] = uint8(pa >> 8)
+		}
+	}
+}
+
+func (ablInterpolator) transform_RGBA_YCbCr444_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.YCbCr, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00i := (sy0-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
+			s00j := (sy0-src.Rect.Min.Y)*src.CStride + (sx0 - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s00yy1 := int(src.Y[s00i]) * 0x10101
+			s00cb1 := int(src.Cb[s00j]) - 128
+			s00cr1 := int(src.Cr[s00j]) - 128
+			s00ru := (s00yy1 + 91881*s00cr1) >> 8
+			s00gu := (s00yy1 - 22554*s00cb1 - 46802*s00cr1) >> 8
+			s00bu := (s00yy1 + 116130*s00cb1) >> 8
+			if s00ru < 0 {
+				s00ru = 0
+			} else if s00ru > 0xffff {
+				s00ru = 0xffff
+			}
+			if s00gu < 0 {
+				s00gu = 0
+			} else if s00gu > 0xffff {
+				s00gu = 0xffff
+			}
+			if s00bu < 0 {
+				s00bu = 0
+			} else if s00bu > 0xffff {
+				s00bu = 0xffff
+			}
+
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s10i := (sy0-src.Rect.Min.Y)*src.YStride + (sx1 - src.Rect.Min.X)
+			s10j := (sy0-src.Rect.Min.Y)*src.CStride + (sx1 - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s10yy1 := int(src.Y[s10i]) * 0x10101
+			s10cb1 := int(src.Cb[s10j]) - 128
+			s10cr1 := int(src.Cr[s10j]) - 128
+			s10ru := (s10yy1 + 91881*s10cr1) >> 8
+			s10gu := (s10yy1 - 22554*s10cb1 - 46802*s10cr1) >> 8
+			s10bu := (s10yy1 + 116130*s10cb1) >> 8
+			if s10ru < 0 {
+				s10ru = 0
+			} else if s10ru > 0xffff {
+				s10ru = 0xffff
+			}
+			if s10gu < 0 {
+				s10gu = 0
+			} else if s10gu > 0xffff {
+				s10gu = 0xffff
+			}
+			if s10bu < 0 {
+				s10bu = 0
+			} else if s10bu > 0xffff {
+				s10bu = 0xffff
+			}
+
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s01i := (sy1-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
+			s01j := (sy1-src.Rect.Min.Y)*src.CStride + (sx0 - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s01yy1 := int(src.Y[s01i]) * 0x10101
+			s01cb1 := int(src.Cb[s01j]) - 128
+			s01cr1 := int(src.Cr[s01j]) - 128
+			s01ru := (s01yy1 + 91881*s01cr1) >> 8
+			s01gu := (s01yy1 - 22554*s01cb1 - 46802*s01cr1) >> 8
+			s01bu := (s01yy1 + 116130*s01cb1) >> 8
+			if s01ru < 0 {
+				s01ru = 0
+			} else if s01ru > 0xffff {
+				s01ru = 0xffff
+			}
+			if s01gu < 0 {
+				s01gu = 0
+			} else if s01gu > 0xffff {
+				s01gu = 0xffff
+			}
+			if s01bu < 0 {
+				s01bu = 0
+			} else if s01bu > 0xffff {
+				s01bu = 0xffff
+			}
+
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s11i := (sy1-src.Rect.Min.Y)*src.YStride + (sx1 - src.Rect.Min.X)
+			s11j := (sy1-src.Rect.Min.Y)*src.CStride + (sx1 - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s11yy1 := int(src.Y[s11i]) * 0x10101
+			s11cb1 := int(src.Cb[s11j]) - 128
+			s11cr1 := int(src.Cr[s11j]) - 128
+			s11ru := (s11yy1 + 91881*s11cr1) >> 8
+			s11gu := (s11yy1 - 22554*s11cb1 - 46802*s11cr1) >> 8
+			s11bu := (s11yy1 + 116130*s11cb1) >> 8
+			if s11ru < 0 {
+				s11ru = 0
+			} else if s11ru > 0xffff {
+				s11ru = 0xffff
+			}
+			if s11gu < 0 {
+				s11gu = 0
+			} else if s11gu > 0xffff {
+				s11gu = 0xffff
+			}
+			if s11bu < 0 {
+				s11bu = 0
+			} else if s11bu > 0xffff {
+				s11bu = 0xffff
+			}
+
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			dst.Pix[d+0] = uint8(pr >> 8)
+			dst.Pix[d+1] = uint8(pg >> 8)
+			dst.Pix[d+2] = uint8(pb >> 8)
+			dst.Pix[d+3] = 0xff
+		}
+	}
+}
+
+func (ablInterpolator) transform_RGBA_YCbCr422_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.YCbCr, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00i := (sy0-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
+			s00j := (sy0-src.Rect.Min.Y)*src.CStride + ((sx0)/2 - src.Rect.Min.X/2)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s00yy1 := int(src.Y[s00i]) * 0x10101
+			s00cb1 := int(src.Cb[s00j]) - 128
+			s00cr1 := int(src.Cr[s00j]) - 128
+			s00ru := (s00yy1 + 91881*s00cr1) >> 8
+			s00gu := (s00yy1 - 22554*s00cb1 - 46802*s00cr1) >> 8
+			s00bu := (s00yy1 + 116130*s00cb1) >> 8
+			if s00ru < 0 {
+				s00ru = 0
+			} else if s00ru > 0xffff {
+				s00ru = 0xffff
+			}
+			if s00gu < 0 {
+				s00gu = 0
+			} else if s00gu > 0xffff {
+				s00gu = 0xffff
+			}
+			if s00bu < 0 {
+				s00bu = 0
+			} else if s00bu > 0xffff {
+				s00bu = 0xffff
+			}
+
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s10i := (sy0-src.Rect.Min.Y)*src.YStride + (sx1 - src.Rect.Min.X)
+			s10j := (sy0-src.Rect.Min.Y)*src.CStride + ((sx1)/2 - src.Rect.Min.X/2)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s10yy1 := int(src.Y[s10i]) * 0x10101
+			s10cb1 := int(src.Cb[s10j]) - 128
+			s10cr1 := int(src.Cr[s10j]) - 128
+			s10ru := (s10yy1 + 91881*s10cr1) >> 8
+			s10gu := (s10yy1 - 22554*s10cb1 - 46802*s10cr1) >> 8
+			s10bu := (s10yy1 + 116130*s10cb1) >> 8
+			if s10ru < 0 {
+				s10ru = 0
+			} else if s10ru > 0xffff {
+				s10ru = 0xffff
+			}
+			if s10gu < 0 {
+				s10gu = 0
+			} else if s10gu > 0xffff {
+				s10gu = 0xffff
+			}
+			if s10bu < 0 {
+				s10bu = 0
+			} else if s10bu > 0xffff {
+				s10bu = 0xffff
+			}
+
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s01i := (sy1-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
+			s01j := (sy1-src.Rect.Min.Y)*src.CStride + ((sx0)/2 - src.Rect.Min.X/2)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s01yy1 := int(src.Y[s01i]) * 0x10101
+			s01cb1 := int(src.Cb[s01j]) - 128
+			s01cr1 := int(src.Cr[s01j]) - 128
+			s01ru := (s01yy1 + 91881*s01cr1) >> 8
+			s01gu := (s01yy1 - 22554*s01cb1 - 46802*s01cr1) >> 8
+			s01bu := (s01yy1 + 116130*s01cb1) >> 8
+			if s01ru < 0 {
+				s01ru = 0
+			} else if s01ru > 0xffff {
+				s01ru = 0xffff
+			}
+			if s01gu < 0 {
+				s01gu = 0
+			} else if s01gu > 0xffff {
+				s01gu = 0xffff
+			}
+			if s01bu < 0 {
+				s01bu = 0
+			} else if s01bu > 0xffff {
+				s01bu = 0xffff
+			}
+
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s11i := (sy1-src.Rect.Min.Y)*src.YStride + (sx1 - src.Rect.Min.X)
+			s11j := (sy1-src.Rect.Min.Y)*src.CStride + ((sx1)/2 - src.Rect.Min.X/2)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s11yy1 := int(src.Y[s11i]) * 0x10101
+			s11cb1 := int(src.Cb[s11j]) - 128
+			s11cr1 := int(src.Cr[s11j]) - 128
+			s11ru := (s11yy1 + 91881*s11cr1) >> 8
+			s11gu := (s11yy1 - 22554*s11cb1 - 46802*s11cr1) >> 8
+			s11bu := (s11yy1 + 116130*s11cb1) >> 8
+			if s11ru < 0 {
+				s11ru = 0
+			} else if s11ru > 0xffff {
+				s11ru = 0xffff
+			}
+			if s11gu < 0 {
+				s11gu = 0
+			} else if s11gu > 0xffff {
+				s11gu = 0xffff
+			}
+			if s11bu < 0 {
+				s11bu = 0
+			} else if s11bu > 0xffff {
+				s11bu = 0xffff
+			}
+
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			dst.Pix[d+0] = uint8(pr >> 8)
+			dst.Pix[d+1] = uint8(pg >> 8)
+			dst.Pix[d+2] = uint8(pb >> 8)
+			dst.Pix[d+3] = 0xff
+		}
+	}
+}
+
+func (ablInterpolator) transform_RGBA_YCbCr420_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.YCbCr, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00i := (sy0-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
+			s00j := ((sy0)/2-src.Rect.Min.Y/2)*src.CStride + ((sx0)/2 - src.Rect.Min.X/2)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s00yy1 := int(src.Y[s00i]) * 0x10101
+			s00cb1 := int(src.Cb[s00j]) - 128
+			s00cr1 := int(src.Cr[s00j]) - 128
+			s00ru := (s00yy1 + 91881*s00cr1) >> 8
+			s00gu := (s00yy1 - 22554*s00cb1 - 46802*s00cr1) >> 8
+			s00bu := (s00yy1 + 116130*s00cb1) >> 8
+			if s00ru < 0 {
+				s00ru = 0
+			} else if s00ru > 0xffff {
+				s00ru = 0xffff
+			}
+			if s00gu < 0 {
+				s00gu = 0
+			} else if s00gu > 0xffff {
+				s00gu = 0xffff
+			}
+			if s00bu < 0 {
+				s00bu = 0
+			} else if s00bu > 0xffff {
+				s00bu = 0xffff
+			}
+
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s10i := (sy0-src.Rect.Min.Y)*src.YStride + (sx1 - src.Rect.Min.X)
+			s10j := ((sy0)/2-src.Rect.Min.Y/2)*src.CStride + ((sx1)/2 - src.Rect.Min.X/2)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s10yy1 := int(src.Y[s10i]) * 0x10101
+			s10cb1 := int(src.Cb[s10j]) - 128
+			s10cr1 := int(src.Cr[s10j]) - 128
+			s10ru := (s10yy1 + 91881*s10cr1) >> 8
+			s10gu := (s10yy1 - 22554*s10cb1 - 46802*s10cr1) >> 8
+			s10bu := (s10yy1 + 116130*s10cb1) >> 8
+			if s10ru < 0 {
+				s10ru = 0
+			} else if s10ru > 0xffff {
+				s10ru = 0xffff
+			}
+			if s10gu < 0 {
+				s10gu = 0
+			} else if s10gu > 0xffff {
+				s10gu = 0xffff
+			}
+			if s10bu < 0 {
+				s10bu = 0
+			} else if s10bu > 0xffff {
+				s10bu = 0xffff
+			}
+
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s01i := (sy1-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
+			s01j := ((sy1)/2-src.Rect.Min.Y/2)*src.CStride + ((sx0)/2 - src.Rect.Min.X/2)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s01yy1 := int(src.Y[s01i]) * 0x10101
+			s01cb1 := int(src.Cb[s01j]) - 128
+			s01cr1 := int(src.Cr[s01j]) - 128
+			s01ru := (s01yy1 + 91881*s01cr1) >> 8
+			s01gu := (s01yy1 - 22554*s01cb1 - 46802*s01cr1) >> 8
+			s01bu := (s01yy1 + 116130*s01cb1) >> 8
+			if s01ru < 0 {
+				s01ru = 0
+			} else if s01ru > 0xffff {
+				s01ru = 0xffff
+			}
+			if s01gu < 0 {
+				s01gu = 0
+			} else if s01gu > 0xffff {
+				s01gu = 0xffff
+			}
+			if s01bu < 0 {
+				s01bu = 0
+			} else if s01bu > 0xffff {
+				s01bu = 0xffff
+			}
+
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s11i := (sy1-src.Rect.Min.Y)*src.YStride + (sx1 - src.Rect.Min.X)
+			s11j := ((sy1)/2-src.Rect.Min.Y/2)*src.CStride + ((sx1)/2 - src.Rect.Min.X/2)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s11yy1 := int(src.Y[s11i]) * 0x10101
+			s11cb1 := int(src.Cb[s11j]) - 128
+			s11cr1 := int(src.Cr[s11j]) - 128
+			s11ru := (s11yy1 + 91881*s11cr1) >> 8
+			s11gu := (s11yy1 - 22554*s11cb1 - 46802*s11cr1) >> 8
+			s11bu := (s11yy1 + 116130*s11cb1) >> 8
+			if s11ru < 0 {
+				s11ru = 0
+			} else if s11ru > 0xffff {
+				s11ru = 0xffff
+			}
+			if s11gu < 0 {
+				s11gu = 0
+			} else if s11gu > 0xffff {
+				s11gu = 0xffff
+			}
+			if s11bu < 0 {
+				s11bu = 0
+			} else if s11bu > 0xffff {
+				s11bu = 0xffff
+			}
+
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			dst.Pix[d+0] = uint8(pr >> 8)
+			dst.Pix[d+1] = uint8(pg >> 8)
+			dst.Pix[d+2] = uint8(pb >> 8)
+			dst.Pix[d+3] = 0xff
+		}
+	}
+}
+
+func (ablInterpolator) transform_RGBA_YCbCr440_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.YCbCr, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00i := (sy0-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
+			s00j := ((sy0)/2-src.Rect.Min.Y/2)*src.CStride + (sx0 - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s00yy1 := int(src.Y[s00i]) * 0x10101
+			s00cb1 := int(src.Cb[s00j]) - 128
+			s00cr1 := int(src.Cr[s00j]) - 128
+			s00ru := (s00yy1 + 91881*s00cr1) >> 8
+			s00gu := (s00yy1 - 22554*s00cb1 - 46802*s00cr1) >> 8
+			s00bu := (s00yy1 + 116130*s00cb1) >> 8
+			if s00ru < 0 {
+				s00ru = 0
+			} else if s00ru > 0xffff {
+				s00ru = 0xffff
+			}
+			if s00gu < 0 {
+				s00gu = 0
+			} else if s00gu > 0xffff {
+				s00gu = 0xffff
+			}
+			if s00bu < 0 {
+				s00bu = 0
+			} else if s00bu > 0xffff {
+				s00bu = 0xffff
+			}
+
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s10i := (sy0-src.Rect.Min.Y)*src.YStride + (sx1 - src.Rect.Min.X)
+			s10j := ((sy0)/2-src.Rect.Min.Y/2)*src.CStride + (sx1 - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s10yy1 := int(src.Y[s10i]) * 0x10101
+			s10cb1 := int(src.Cb[s10j]) - 128
+			s10cr1 := int(src.Cr[s10j]) - 128
+			s10ru := (s10yy1 + 91881*s10cr1) >> 8
+			s10gu := (s10yy1 - 22554*s10cb1 - 46802*s10cr1) >> 8
+			s10bu := (s10yy1 + 116130*s10cb1) >> 8
+			if s10ru < 0 {
+				s10ru = 0
+			} else if s10ru > 0xffff {
+				s10ru = 0xffff
+			}
+			if s10gu < 0 {
+				s10gu = 0
+			} else if s10gu > 0xffff {
+				s10gu = 0xffff
+			}
+			if s10bu < 0 {
+				s10bu = 0
+			} else if s10bu > 0xffff {
+				s10bu = 0xffff
+			}
+
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s01i := (sy1-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
+			s01j := ((sy1)/2-src.Rect.Min.Y/2)*src.CStride + (sx0 - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s01yy1 := int(src.Y[s01i]) * 0x10101
+			s01cb1 := int(src.Cb[s01j]) - 128
+			s01cr1 := int(src.Cr[s01j]) - 128
+			s01ru := (s01yy1 + 91881*s01cr1) >> 8
+			s01gu := (s01yy1 - 22554*s01cb1 - 46802*s01cr1) >> 8
+			s01bu := (s01yy1 + 116130*s01cb1) >> 8
+			if s01ru < 0 {
+				s01ru = 0
+			} else if s01ru > 0xffff {
+				s01ru = 0xffff
+			}
+			if s01gu < 0 {
+				s01gu = 0
+			} else if s01gu > 0xffff {
+				s01gu = 0xffff
+			}
+			if s01bu < 0 {
+				s01bu = 0
+			} else if s01bu > 0xffff {
+				s01bu = 0xffff
+			}
+
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s11i := (sy1-src.Rect.Min.Y)*src.YStride + (sx1 - src.Rect.Min.X)
+			s11j := ((sy1)/2-src.Rect.Min.Y/2)*src.CStride + (sx1 - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s11yy1 := int(src.Y[s11i]) * 0x10101
+			s11cb1 := int(src.Cb[s11j]) - 128
+			s11cr1 := int(src.Cr[s11j]) - 128
+			s11ru := (s11yy1 + 91881*s11cr1) >> 8
+			s11gu := (s11yy1 - 22554*s11cb1 - 46802*s11cr1) >> 8
+			s11bu := (s11yy1 + 116130*s11cb1) >> 8
+			if s11ru < 0 {
+				s11ru = 0
+			} else if s11ru > 0xffff {
+				s11ru = 0xffff
+			}
+			if s11gu < 0 {
+				s11gu = 0
+			} else if s11gu > 0xffff {
+				s11gu = 0xffff
+			}
+			if s11bu < 0 {
+				s11bu = 0
+			} else if s11bu > 0xffff {
+				s11bu = 0xffff
+			}
+
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			dst.Pix[d+0] = uint8(pr >> 8)
+			dst.Pix[d+1] = uint8(pg >> 8)
+			dst.Pix[d+2] = uint8(pb >> 8)
+			dst.Pix[d+3] = 0xff
+		}
+	}
+}
+
+func (ablInterpolator) transform_RGBA_NYCbCrA444_Over(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NYCbCrA, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00i := (sy0-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
+			s00j := (sy0-src.Rect.Min.Y)*src.CStride + (sx0 - src.Rect.Min.X)
+			s00k := (sy0-src.Rect.Min.Y)*src.AStride + (sx0 - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s00yy1 := int(src.Y[s00i]) * 0x10101
+			s00cb1 := int(src.Cb[s00j]) - 128
+			s00cr1 := int(src.Cr[s00j]) - 128
+			s00ru0 := (s00yy1 + 91881*s00cr1) >> 8
+			s00gu0 := (s00yy1 - 22554*s00cb1 - 46802*s00cr1) >> 8
+			s00bu0 := (s00yy1 + 116130*s00cb1) >> 8
+			if s00ru0 < 0 {
+				s00ru0 = 0
+			} else if s00ru0 > 0xffff {
+				s00ru0 = 0xffff
+			}
+			if s00gu0 < 0 {
+				s00gu0 = 0
+			} else if s00gu0 > 0xffff {
+				s00gu0 = 0xffff
+			}
+			if s00bu0 < 0 {
+				s00bu0 = 0
+			} else if s00bu0 > 0xffff {
+				s00bu0 = 0xffff
+			}
+
+			s00au := uint32(src.A[s00k]) * 0x101
+			s00ru := uint32(s00ru0) * s00au / 0xffff
+			s00gu := uint32(s00gu0) * s00au / 0xffff
+			s00bu := uint32(s00bu0) * s00au / 0xffff
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10i := (sy0-src.Rect.Min.Y)*src.YStride + (sx1 - src.Rect.Min.X)
+			s10j := (sy0-src.Rect.Min.Y)*src.CStride + (sx1 - src.Rect.Min.X)
+			s10k := (sy0-src.Rect.Min.Y)*src.AStride + (sx1 - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s10yy1 := int(src.Y[s10i]) * 0x10101
+			s10cb1 := int(src.Cb[s10j]) - 128
+			s10cr1 := int(src.Cr[s10j]) - 128
+			s10ru0 := (s10yy1 + 91881*s10cr1) >> 8
+			s10gu0 := (s10yy1 - 22554*s10cb1 - 46802*s10cr1) >> 8
+			s10bu0 := (s10yy1 + 116130*s10cb1) >> 8
+			if s10ru0 < 0 {
+				s10ru0 = 0
+			} else if s10ru0 > 0xffff {
+				s10ru0 = 0xffff
+			}
+			if s10gu0 < 0 {
+				s10gu0 = 0
+			} else if s10gu0 > 0xffff {
+				s10gu0 = 0xffff
+			}
+			if s10bu0 < 0 {
+				s10bu0 = 0
+			} else if s10bu0 > 0xffff {
+				s10bu0 = 0xffff
+			}
+
+			s10au := uint32(src.A[s10k]) * 0x101
+			s10ru := uint32(s10ru0) * s10au / 0xffff
+			s10gu := uint32(s10gu0) * s10au / 0xffff
+			s10bu := uint32(s10bu0) * s10au / 0xffff
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01i := (sy1-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
+			s01j := (sy1-src.Rect.Min.Y)*src.CStride + (sx0 - src.Rect.Min.X)
+			s01k := (sy1-src.Rect.Min.Y)*src.AStride + (sx0 - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s01yy1 := int(src.Y[s01i]) * 0x10101
+			s01cb1 := int(src.Cb[s01j]) - 128
+			s01cr1 := int(src.Cr[s01j]) - 128
+			s01ru0 := (s01yy1 + 91881*s01cr1) >> 8
+			s01gu0 := (s01yy1 - 22554*s01cb1 - 46802*s01cr1) >> 8
+			s01bu0 := (s01yy1 + 116130*s01cb1) >> 8
+			if s01ru0 < 0 {
+				s01ru0 = 0
+			} else if s01ru0 > 0xffff {
+				s01ru0 = 0xffff
+			}
+			if s01gu0 < 0 {
+				s01gu0 = 0
+			} else if s01gu0 > 0xffff {
+				s01gu0 = 0xffff
+			}
+			if s01bu0 < 0 {
+				s01bu0 = 0
+			} else if s01bu0 > 0xffff {
+				s01bu0 = 0xffff
+			}
+
+			s01au := uint32(src.A[s01k]) * 0x101
+			s01ru := uint32(s01ru0) * s01au / 0xffff
+			s01gu := uint32(s01gu0) * s01au / 0xffff
+			s01bu := uint32(s01bu0) * s01au / 0xffff
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11i := (sy1-src.Rect.Min.Y)*src.YStride + (sx1 - src.Rect.Min.X)
+			s11j := (sy1-src.Rect.Min.Y)*src.CStride + (sx1 - src.Rect.Min.X)
+			s11k := (sy1-src.Rect.Min.Y)*src.AStride + (sx1 - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s11yy1 := int(src.Y[s11i]) * 0x10101
+			s11cb1 := int(src.Cb[s11j]) - 128
+			s11cr1 := int(src.Cr[s11j]) - 128
+			s11ru0 := (s11yy1 + 91881*s11cr1) >> 8
+			s11gu0 := (s11yy1 - 22554*s11cb1 - 46802*s11cr1) >> 8
+			s11bu0 := (s11yy1 + 116130*s11cb1) >> 8
+			if s11ru0 < 0 {
+				s11ru0 = 0
+			} else if s11ru0 > 0xffff {
+				s11ru0 = 0xffff
+			}
+			if s11gu0 < 0 {
+				s11gu0 = 0
+			} else if s11gu0 > 0xffff {
+				s11gu0 = 0xffff
+			}
+			if s11bu0 < 0 {
+				s11bu0 = 0
+			} else if s11bu0 > 0xffff {
+				s11bu0 = 0xffff
+			}
+
+			s11au := uint32(src.A[s11k]) * 0x101
+			s11ru := uint32(s11ru0) * s11au / 0xffff
+			s11gu := uint32(s11gu0) * s11au / 0xffff
+			s11bu := uint32(s11bu0) * s11au / 0xffff
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			pa1 := (0xffff - pa) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pr) >> 8)
+			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + pg) >> 8)
+			dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*pa1/0xffff + pb) >> 8)
+			dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*pa1/0xffff + pa) >> 8)
+		}
+	}
+}
+
+func (ablInterpolator) transform_RGBA_NYCbCrA422_Over(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NYCbCrA, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00i := (sy0-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
+			s00j := (sy0-src.Rect.Min.Y)*src.CStride + ((sx0)/2 - src.Rect.Min.X/2)
+			s00k := (sy0-src.Rect.Min.Y)*src.AStride + (sx0 - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s00yy1 := int(src.Y[s00i]) * 0x10101
+			s00cb1 := int(src.Cb[s00j]) - 128
+			s00cr1 := int(src.Cr[s00j]) - 128
+			s00ru0 := (s00yy1 + 91881*s00cr1) >> 8
+			s00gu0 := (s00yy1 - 22554*s00cb1 - 46802*s00cr1) >> 8
+			s00bu0 := (s00yy1 + 116130*s00cb1) >> 8
+			if s00ru0 < 0 {
+				s00ru0 = 0
+			} else if s00ru0 > 0xffff {
+				s00ru0 = 0xffff
+			}
+			if s00gu0 < 0 {
+				s00gu0 = 0
+			} else if s00gu0 > 0xffff {
+				s00gu0 = 0xffff
+			}
+			if s00bu0 < 0 {
+				s00bu0 = 0
+			} else if s00bu0 > 0xffff {
+				s00bu0 = 0xffff
+			}
+
+			s00au := uint32(src.A[s00k]) * 0x101
+			s00ru := uint32(s00ru0) * s00au / 0xffff
+			s00gu := uint32(s00gu0) * s00au / 0xffff
+			s00bu := uint32(s00bu0) * s00au / 0xffff
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10i := (sy0-src.Rect.Min.Y)*src.YStride + (sx1 - src.Rect.Min.X)
+			s10j := (sy0-src.Rect.Min.Y)*src.CStride + ((sx1)/2 - src.Rect.Min.X/2)
+			s10k := (sy0-src.Rect.Min.Y)*src.AStride + (sx1 - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s10yy1 := int(src.Y[s10i]) * 0x10101
+			s10cb1 := int(src.Cb[s10j]) - 128
+			s10cr1 := int(src.Cr[s10j]) - 128
+			s10ru0 := (s10yy1 + 91881*s10cr1) >> 8
+			s10gu0 := (s10yy1 - 22554*s10cb1 - 46802*s10cr1) >> 8
+			s10bu0 := (s10yy1 + 116130*s10cb1) >> 8
+			if s10ru0 < 0 {
+				s10ru0 = 0
+			} else if s10ru0 > 0xffff {
+				s10ru0 = 0xffff
+			}
+			if s10gu0 < 0 {
+				s10gu0 = 0
+			} else if s10gu0 > 0xffff {
+				s10gu0 = 0xffff
+			}
+			if s10bu0 < 0 {
+				s10bu0 = 0
+			} else if s10bu0 > 0xffff {
+				s10bu0 = 0xffff
+			}
+
+			s10au := uint32(src.A[s10k]) * 0x101
+			s10ru := uint32(s10ru0) * s10au / 0xffff
+			s10gu := uint32(s10gu0) * s10au / 0xffff
+			s10bu := uint32(s10bu0) * s10au / 0xffff
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01i := (sy1-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
+			s01j := (sy1-src.Rect.Min.Y)*src.CStride + ((sx0)/2 - src.Rect.Min.X/2)
+			s01k := (sy1-src.Rect.Min.Y)*src.AStride + (sx0 - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s01yy1 := int(src.Y[s01i]) * 0x10101
+			s01cb1 := int(src.Cb[s01j]) - 128
+			s01cr1 := int(src.Cr[s01j]) - 128
+			s01ru0 := (s01yy1 + 91881*s01cr1) >> 8
+			s01gu0 := (s01yy1 - 22554*s01cb1 - 46802*s01cr1) >> 8
+			s01bu0 := (s01yy1 + 116130*s01cb1) >> 8
+			if s01ru0 < 0 {
+				s01ru0 = 0
+			} else if s01ru0 > 0xffff {
+				s01ru0 = 0xffff
+			}
+			if s01gu0 < 0 {
+				s01gu0 = 0
+			} else if s01gu0 > 0xffff {
+				s01gu0 = 0xffff
+			}
+			if s01bu0 < 0 {
+				s01bu0 = 0
+			} else if s01bu0 > 0xffff {
+				s01bu0 = 0xffff
+			}
+
+			s01au := uint32(src.A[s01k]) * 0x101
+			s01ru := uint32(s01ru0) * s01au / 0xffff
+			s01gu := uint32(s01gu0) * s01au / 0xffff
+			s01bu := uint32(s01bu0) * s01au / 0xffff
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11i := (sy1-src.Rect.Min.Y)*src.YStride + (sx1 - src.Rect.Min.X)
+			s11j := (sy1-src.Rect.Min.Y)*src.CStride + ((sx1)/2 - src.Rect.Min.X/2)
+			s11k := (sy1-src.Rect.Min.Y)*src.AStride + (sx1 - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s11yy1 := int(src.Y[s11i]) * 0x10101
+			s11cb1 := int(src.Cb[s11j]) - 128
+			s11cr1 := int(src.Cr[s11j]) - 128
+			s11ru0 := (s11yy1 + 91881*s11cr1) >> 8
+			s11gu0 := (s11yy1 - 22554*s11cb1 - 46802*s11cr1) >> 8
+			s11bu0 := (s11yy1 + 116130*s11cb1) >> 8
+			if s11ru0 < 0 {
+				s11ru0 = 0
+			} else if s11ru0 > 0xffff {
+				s11ru0 = 0xffff
+			}
+			if s11gu0 < 0 {
+				s11gu0 = 0
+			} else if s11gu0 > 0xffff {
+				s11gu0 = 0xffff
+			}
+			if s11bu0 < 0 {
+				s11bu0 = 0
+			} else if s11bu0 > 0xffff {
+				s11bu0 = 0xffff
+			}
+
+			s11au := uint32(src.A[s11k]) * 0x101
+			s11ru := uint32(s11ru0) * s11au / 0xffff
+			s11gu := uint32(s11gu0) * s11au / 0xffff
+			s11bu := uint32(s11bu0) * s11au / 0xffff
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			pa1 := (0xffff - pa) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pr) >> 8)
+			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + pg) >> 8)
+			dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*pa1/0xffff + pb) >> 8)
+			dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*pa1/0xffff + pa) >> 8)
+		}
+	}
+}
+
+func (ablInterpolator) transform_RGBA_NYCbCrA420_Over(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NYCbCrA, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00i := (sy0-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
+			s00j := ((sy0)/2-src.Rect.Min.Y/2)*src.CStride + ((sx0)/2 - src.Rect.Min.X/2)
+			s00k := (sy0-src.Rect.Min.Y)*src.AStride + (sx0 - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s00yy1 := int(src.Y[s00i]) * 0x10101
+			s00cb1 := int(src.Cb[s00j]) - 128
+			s00cr1 := int(src.Cr[s00j]) - 128
+			s00ru0 := (s00yy1 + 91881*s00cr1) >> 8
+			s00gu0 := (s00yy1 - 22554*s00cb1 - 46802*s00cr1) >> 8
+			s00bu0 := (s00yy1 + 116130*s00cb1) >> 8
+			if s00ru0 < 0 {
+				s00ru0 = 0
+			} else if s00ru0 > 0xffff {
+				s00ru0 = 0xffff
+			}
+			if s00gu0 < 0 {
+				s00gu0 = 0
+			} else if s00gu0 > 0xffff {
+				s00gu0 = 0xffff
+			}
+			if s00bu0 < 0 {
+				s00bu0 = 0
+			} else if s00bu0 > 0xffff {
+				s00bu0 = 0xffff
+			}
+
+			s00au := uint32(src.A[s00k]) * 0x101
+			s00ru := uint32(s00ru0) * s00au / 0xffff
+			s00gu := uint32(s00gu0) * s00au / 0xffff
+			s00bu := uint32(s00bu0) * s00au / 0xffff
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10i := (sy0-src.Rect.Min.Y)*src.YStride + (sx1 - src.Rect.Min.X)
+			s10j := ((sy0)/2-src.Rect.Min.Y/2)*src.CStride + ((sx1)/2 - src.Rect.Min.X/2)
+			s10k := (sy0-src.Rect.Min.Y)*src.AStride + (sx1 - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s10yy1 := int(src.Y[s10i]) * 0x10101
+			s10cb1 := int(src.Cb[s10j]) - 128
+			s10cr1 := int(src.Cr[s10j]) - 128
+			s10ru0 := (s10yy1 + 91881*s10cr1) >> 8
+			s10gu0 := (s10yy1 - 22554*s10cb1 - 46802*s10cr1) >> 8
+			s10bu0 := (s10yy1 + 116130*s10cb1) >> 8
+			if s10ru0 < 0 {
+				s10ru0 = 0
+			} else if s10ru0 > 0xffff {
+				s10ru0 = 0xffff
+			}
+			if s10gu0 < 0 {
+				s10gu0 = 0
+			} else if s10gu0 > 0xffff {
+				s10gu0 = 0xffff
+			}
+			if s10bu0 < 0 {
+				s10bu0 = 0
+			} else if s10bu0 > 0xffff {
+				s10bu0 = 0xffff
+			}
+
+			s10au := uint32(src.A[s10k]) * 0x101
+			s10ru := uint32(s10ru0) * s10au / 0xffff
+			s10gu := uint32(s10gu0) * s10au / 0xffff
+			s10bu := uint32(s10bu0) * s10au / 0xffff
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01i := (sy1-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
+			s01j := ((sy1)/2-src.Rect.Min.Y/2)*src.CStride + ((sx0)/2 - src.Rect.Min.X/2)
+			s01k := (sy1-src.Rect.Min.Y)*src.AStride + (sx0 - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s01yy1 := int(src.Y[s01i]) * 0x10101
+			s01cb1 := int(src.Cb[s01j]) - 128
+			s01cr1 := int(src.Cr[s01j]) - 128
+			s01ru0 := (s01yy1 + 91881*s01cr1) >> 8
+			s01gu0 := (s01yy1 - 22554*s01cb1 - 46802*s01cr1) >> 8
+			s01bu0 := (s01yy1 + 116130*s01cb1) >> 8
+			if s01ru0 < 0 {
+				s01ru0 = 0
+			} else if s01ru0 > 0xffff {
+				s01ru0 = 0xffff
+			}
+			if s01gu0 < 0 {
+				s01gu0 = 0
+			} else if s01gu0 > 0xffff {
+				s01gu0 = 0xffff
+			}
+			if s01bu0 < 0 {
+				s01bu0 = 0
+			} else if s01bu0 > 0xffff {
+				s01bu0 = 0xffff
+			}
+
+			s01au := uint32(src.A[s01k]) * 0x101
+			s01ru := uint32(s01ru0) * s01au / 0xffff
+			s01gu := uint32(s01gu0) * s01au / 0xffff
+			s01bu := uint32(s01bu0) * s01au / 0xffff
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11i := (sy1-src.Rect.Min.Y)*src.YStride + (sx1 - src.Rect.Min.X)
+			s11j := ((sy1)/2-src.Rect.Min.Y/2)*src.CStride + ((sx1)/2 - src.Rect.Min.X/2)
+			s11k := (sy1-src.Rect.Min.Y)*src.AStride + (sx1 - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s11yy1 := int(src.Y[s11i]) * 0x10101
+			s11cb1 := int(src.Cb[s11j]) - 128
+			s11cr1 := int(src.Cr[s11j]) - 128
+			s11ru0 := (s11yy1 + 91881*s11cr1) >> 8
+			s11gu0 := (s11yy1 - 22554*s11cb1 - 46802*s11cr1) >> 8
+			s11bu0 := (s11yy1 + 116130*s11cb1) >> 8
+			if s11ru0 < 0 {
+				s11ru0 = 0
+			} else if s11ru0 > 0xffff {
+				s11ru0 = 0xffff
+			}
+			if s11gu0 < 0 {
+				s11gu0 = 0
+			} else if s11gu0 > 0xffff {
+				s11gu0 = 0xffff
+			}
+			if s11bu0 < 0 {
+				s11bu0 = 0
+			} else if s11bu0 > 0xffff {
+				s11bu0 = 0xffff
+			}
+
+			s11au := uint32(src.A[s11k]) * 0x101
+			s11ru := uint32(s11ru0) * s11au / 0xffff
+			s11gu := uint32(s11gu0) * s11au / 0xffff
+			s11bu := uint32(s11bu0) * s11au / 0xffff
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			pa1 := (0xffff - pa) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pr) >> 8)
+			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + pg) >> 8)
+			dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*pa1/0xffff + pb) >> 8)
+			dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*pa1/0xffff + pa) >> 8)
+		}
+	}
+}
+
+func (ablInterpolator) transform_RGBA_NYCbCrA440_Over(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NYCbCrA, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00i := (sy0-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
+			s00j := ((sy0)/2-src.Rect.Min.Y/2)*src.CStride + (sx0 - src.Rect.Min.X)
+			s00k := (sy0-src.Rect.Min.Y)*src.AStride + (sx0 - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s00yy1 := int(src.Y[s00i]) * 0x10101
+			s00cb1 := int(src.Cb[s00j]) - 128
+			s00cr1 := int(src.Cr[s00j]) - 128
+			s00ru0 := (s00yy1 + 91881*s00cr1) >> 8
+			s00gu0 := (s00yy1 - 22554*s00cb1 - 46802*s00cr1) >> 8
+			s00bu0 := (s00yy1 + 116130*s00cb1) >> 8
+			if s00ru0 < 0 {
+				s00ru0 = 0
+			} else if s00ru0 > 0xffff {
+				s00ru0 = 0xffff
+			}
+			if s00gu0 < 0 {
+				s00gu0 = 0
+			} else if s00gu0 > 0xffff {
+				s00gu0 = 0xffff
+			}
+			if s00bu0 < 0 {
+				s00bu0 = 0
+			} else if s00bu0 > 0xffff {
+				s00bu0 = 0xffff
+			}
+
+			s00au := uint32(src.A[s00k]) * 0x101
+			s00ru := uint32(s00ru0) * s00au / 0xffff
+			s00gu := uint32(s00gu0) * s00au / 0xffff
+			s00bu := uint32(s00bu0) * s00au / 0xffff
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10i := (sy0-src.Rect.Min.Y)*src.YStride + (sx1 - src.Rect.Min.X)
+			s10j := ((sy0)/2-src.Rect.Min.Y/2)*src.CStride + (sx1 - src.Rect.Min.X)
+			s10k := (sy0-src.Rect.Min.Y)*src.AStride + (sx1 - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s10yy1 := int(src.Y[s10i]) * 0x10101
+			s10cb1 := int(src.Cb[s10j]) - 128
+			s10cr1 := int(src.Cr[s10j]) - 128
+			s10ru0 := (s10yy1 + 91881*s10cr1) >> 8
+			s10gu0 := (s10yy1 - 22554*s10cb1 - 46802*s10cr1) >> 8
+			s10bu0 := (s10yy1 + 116130*s10cb1) >> 8
+			if s10ru0 < 0 {
+				s10ru0 = 0
+			} else if s10ru0 > 0xffff {
+				s10ru0 = 0xffff
+			}
+			if s10gu0 < 0 {
+				s10gu0 = 0
+			} else if s10gu0 > 0xffff {
+				s10gu0 = 0xffff
+			}
+			if s10bu0 < 0 {
+				s10bu0 = 0
+			} else if s10bu0 > 0xffff {
+				s10bu0 = 0xffff
+			}
+
+			s10au := uint32(src.A[s10k]) * 0x101
+			s10ru := uint32(s10ru0) * s10au / 0xffff
+			s10gu := uint32(s10gu0) * s10au / 0xffff
+			s10bu := uint32(s10bu0) * s10au / 0xffff
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01i := (sy1-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
+			s01j := ((sy1)/2-src.Rect.Min.Y/2)*src.CStride + (sx0 - src.Rect.Min.X)
+			s01k := (sy1-src.Rect.Min.Y)*src.AStride + (sx0 - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s01yy1 := int(src.Y[s01i]) * 0x10101
+			s01cb1 := int(src.Cb[s01j]) - 128
+			s01cr1 := int(src.Cr[s01j]) - 128
+			s01ru0 := (s01yy1 + 91881*s01cr1) >> 8
+			s01gu0 := (s01yy1 - 22554*s01cb1 - 46802*s01cr1) >> 8
+			s01bu0 := (s01yy1 + 116130*s01cb1) >> 8
+			if s01ru0 < 0 {
+				s01ru0 = 0
+			} else if s01ru0 > 0xffff {
+				s01ru0 = 0xffff
+			}
+			if s01gu0 < 0 {
+				s01gu0 = 0
+			} else if s01gu0 > 0xffff {
+				s01gu0 = 0xffff
+			}
+			if s01bu0 < 0 {
+				s01bu0 = 0
+			} else if s01bu0 > 0xffff {
+				s01bu0 = 0xffff
+			}
+
+			s01au := uint32(src.A[s01k]) * 0x101
+			s01ru := uint32(s01ru0) * s01au / 0xffff
+			s01gu := uint32(s01gu0) * s01au / 0xffff
+			s01bu := uint32(s01bu0) * s01au / 0xffff
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11i := (sy1-src.Rect.Min.Y)*src.YStride + (sx1 - src.Rect.Min.X)
+			s11j := ((sy1)/2-src.Rect.Min.Y/2)*src.CStride + (sx1 - src.Rect.Min.X)
+			s11k := (sy1-src.Rect.Min.Y)*src.AStride + (sx1 - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s11yy1 := int(src.Y[s11i]) * 0x10101
+			s11cb1 := int(src.Cb[s11j]) - 128
+			s11cr1 := int(src.Cr[s11j]) - 128
+			s11ru0 := (s11yy1 + 91881*s11cr1) >> 8
+			s11gu0 := (s11yy1 - 22554*s11cb1 - 46802*s11cr1) >> 8
+			s11bu0 := (s11yy1 + 116130*s11cb1) >> 8
+			if s11ru0 < 0 {
+				s11ru0 = 0
+			} else if s11ru0 > 0xffff {
+				s11ru0 = 0xffff
+			}
+			if s11gu0 < 0 {
+				s11gu0 = 0
+			} else if s11gu0 > 0xffff {
+				s11gu0 = 0xffff
+			}
+			if s11bu0 < 0 {
+				s11bu0 = 0
+			} else if s11bu0 > 0xffff {
+				s11bu0 = 0xffff
+			}
+
+			s11au := uint32(src.A[s11k]) * 0x101
+			s11ru := uint32(s11ru0) * s11au / 0xffff
+			s11gu := uint32(s11gu0) * s11au / 0xffff
+			s11bu := uint32(s11bu0) * s11au / 0xffff
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			pa1 := (0xffff - pa) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pr) >> 8)
+			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + pg) >> 8)
+			dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*pa1/0xffff + pb) >> 8)
+			dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*pa1/0xffff + pa) >> 8)
+		}
+	}
+}
+
+func (ablInterpolator) transform_RGBA_NYCbCrA444_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NYCbCrA, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00i := (sy0-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
+			s00j := (sy0-src.Rect.Min.Y)*src.CStride + (sx0 - src.Rect.Min.X)
+			s00k := (sy0-src.Rect.Min.Y)*src.AStride + (sx0 - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s00yy1 := int(src.Y[s00i]) * 0x10101
+			s00cb1 := int(src.Cb[s00j]) - 128
+			s00cr1 := int(src.Cr[s00j]) - 128
+			s00ru0 := (s00yy1 + 91881*s00cr1) >> 8
+			s00gu0 := (s00yy1 - 22554*s00cb1 - 46802*s00cr1) >> 8
+			s00bu0 := (s00yy1 + 116130*s00cb1) >> 8
+			if s00ru0 < 0 {
+				s00ru0 = 0
+			} else if s00ru0 > 0xffff {
+				s00ru0 = 0xffff
+			}
+			if s00gu0 < 0 {
+				s00gu0 = 0
+			} else if s00gu0 > 0xffff {
+				s00gu0 = 0xffff
+			}
+			if s00bu0 < 0 {
+				s00bu0 = 0
+			} else if s00bu0 > 0xffff {
+				s00bu0 = 0xffff
+			}
+
+			s00au := uint32(src.A[s00k]) * 0x101
+			s00ru := uint32(s00ru0) * s00au / 0xffff
+			s00gu := uint32(s00gu0) * s00au / 0xffff
+			s00bu := uint32(s00bu0) * s00au / 0xffff
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10i := (sy0-src.Rect.Min.Y)*src.YStride + (sx1 - src.Rect.Min.X)
+			s10j := (sy0-src.Rect.Min.Y)*src.CStride + (sx1 - src.Rect.Min.X)
+			s10k := (sy0-src.Rect.Min.Y)*src.AStride + (sx1 - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s10yy1 := int(src.Y[s10i]) * 0x10101
+			s10cb1 := int(src.Cb[s10j]) - 128
+			s10cr1 := int(src.Cr[s10j]) - 128
+			s10ru0 := (s10yy1 + 91881*s10cr1) >> 8
+			s10gu0 := (s10yy1 - 22554*s10cb1 - 46802*s10cr1) >> 8
+			s10bu0 := (s10yy1 + 116130*s10cb1) >> 8
+			if s10ru0 < 0 {
+				s10ru0 = 0
+			} else if s10ru0 > 0xffff {
+				s10ru0 = 0xffff
+			}
+			if s10gu0 < 0 {
+				s10gu0 = 0
+			} else if s10gu0 > 0xffff {
+				s10gu0 = 0xffff
+			}
+			if s10bu0 < 0 {
+				s10bu0 = 0
+			} else if s10bu0 > 0xffff {
+				s10bu0 = 0xffff
+			}
+
+			s10au := uint32(src.A[s10k]) * 0x101
+			s10ru := uint32(s10ru0) * s10au / 0xffff
+			s10gu := uint32(s10gu0) * s10au / 0xffff
+			s10bu := uint32(s10bu0) * s10au / 0xffff
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01i := (sy1-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
+			s01j := (sy1-src.Rect.Min.Y)*src.CStride + (sx0 - src.Rect.Min.X)
+			s01k := (sy1-src.Rect.Min.Y)*src.AStride + (sx0 - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s01yy1 := int(src.Y[s01i]) * 0x10101
+			s01cb1 := int(src.Cb[s01j]) - 128
+			s01cr1 := int(src.Cr[s01j]) - 128
+			s01ru0 := (s01yy1 + 91881*s01cr1) >> 8
+			s01gu0 := (s01yy1 - 22554*s01cb1 - 46802*s01cr1) >> 8
+			s01bu0 := (s01yy1 + 116130*s01cb1) >> 8
+			if s01ru0 < 0 {
+				s01ru0 = 0
+			} else if s01ru0 > 0xffff {
+				s01ru0 = 0xffff
+			}
+			if s01gu0 < 0 {
+				s01gu0 = 0
+			} else if s01gu0 > 0xffff {
+				s01gu0 = 0xffff
+			}
+			if s01bu0 < 0 {
+				s01bu0 = 0
+			} else if s01bu0 > 0xffff {
+				s01bu0 = 0xffff
+			}
+
+			s01au := uint32(src.A[s01k]) * 0x101
+			s01ru := uint32(s01ru0) * s01au / 0xffff
+			s01gu := uint32(s01gu0) * s01au / 0xffff
+			s01bu := uint32(s01bu0) * s01au / 0xffff
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11i := (sy1-src.Rect.Min.Y)*src.YStride + (sx1 - src.Rect.Min.X)
+			s11j := (sy1-src.Rect.Min.Y)*src.CStride + (sx1 - src.Rect.Min.X)
+			s11k := (sy1-src.Rect.Min.Y)*src.AStride + (sx1 - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s11yy1 := int(src.Y[s11i]) * 0x10101
+			s11cb1 := int(src.Cb[s11j]) - 128
+			s11cr1 := int(src.Cr[s11j]) - 128
+			s11ru0 := (s11yy1 + 91881*s11cr1) >> 8
+			s11gu0 := (s11yy1 - 22554*s11cb1 - 46802*s11cr1) >> 8
+			s11bu0 := (s11yy1 + 116130*s11cb1) >> 8
+			if s11ru0 < 0 {
+				s11ru0 = 0
+			} else if s11ru0 > 0xffff {
+				s11ru0 = 0xffff
+			}
+			if s11gu0 < 0 {
+				s11gu0 = 0
+			} else if s11gu0 > 0xffff {
+				s11gu0 = 0xffff
+			}
+			if s11bu0 < 0 {
+				s11bu0 = 0
+			} else if s11bu0 > 0xffff {
+				s11bu0 = 0xffff
+			}
+
+			s11au := uint32(src.A[s11k]) * 0x101
+			s11ru := uint32(s11ru0) * s11au / 0xffff
+			s11gu := uint32(s11gu0) * s11au / 0xffff
+			s11bu := uint32(s11bu0) * s11au / 0xffff
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			dst.Pix[d+0] = uint8(pr >> 8)
+			dst.Pix[d+1] = uint8(pg >> 8)
+			dst.Pix[d+2] = uint8(pb >> 8)
+			dst.Pix[d+3] = uint8(pa >> 8)
+		}
+	}
+}
+
+func (ablInterpolator) transform_RGBA_NYCbCrA422_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NYCbCrA, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00i := (sy0-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
+			s00j := (sy0-src.Rect.Min.Y)*src.CStride + ((sx0)/2 - src.Rect.Min.X/2)
+			s00k := (sy0-src.Rect.Min.Y)*src.AStride + (sx0 - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s00yy1 := int(src.Y[s00i]) * 0x10101
+			s00cb1 := int(src.Cb[s00j]) - 128
+			s00cr1 := int(src.Cr[s00j]) - 128
+			s00ru0 := (s00yy1 + 91881*s00cr1) >> 8
+			s00gu0 := (s00yy1 - 22554*s00cb1 - 46802*s00cr1) >> 8
+			s00bu0 := (s00yy1 + 116130*s00cb1) >> 8
+			if s00ru0 < 0 {
+				s00ru0 = 0
+			} else if s00ru0 > 0xffff {
+				s00ru0 = 0xffff
+			}
+			if s00gu0 < 0 {
+				s00gu0 = 0
+			} else if s00gu0 > 0xffff {
+				s00gu0 = 0xffff
+			}
+			if s00bu0 < 0 {
+				s00bu0 = 0
+			} else if s00bu0 > 0xffff {
+				s00bu0 = 0xffff
+			}
+
+			s00au := uint32(src.A[s00k]) * 0x101
+			s00ru := uint32(s00ru0) * s00au / 0xffff
+			s00gu := uint32(s00gu0) * s00au / 0xffff
+			s00bu := uint32(s00bu0) * s00au / 0xffff
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10i := (sy0-src.Rect.Min.Y)*src.YStride + (sx1 - src.Rect.Min.X)
+			s10j := (sy0-src.Rect.Min.Y)*src.CStride + ((sx1)/2 - src.Rect.Min.X/2)
+			s10k := (sy0-src.Rect.Min.Y)*src.AStride + (sx1 - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s10yy1 := int(src.Y[s10i]) * 0x10101
+			s10cb1 := int(src.Cb[s10j]) - 128
+			s10cr1 := int(src.Cr[s10j]) - 128
+			s10ru0 := (s10yy1 + 91881*s10cr1) >> 8
+			s10gu0 := (s10yy1 - 22554*s10cb1 - 46802*s10cr1) >> 8
+			s10bu0 := (s10yy1 + 116130*s10cb1) >> 8
+			if s10ru0 < 0 {
+				s10ru0 = 0
+			} else if s10ru0 > 0xffff {
+				s10ru0 = 0xffff
+			}
+			if s10gu0 < 0 {
+				s10gu0 = 0
+			} else if s10gu0 > 0xffff {
+				s10gu0 = 0xffff
+			}
+			if s10bu0 < 0 {
+				s10bu0 = 0
+			} else if s10bu0 > 0xffff {
+				s10bu0 = 0xffff
+			}
+
+			s10au := uint32(src.A[s10k]) * 0x101
+			s10ru := uint32(s10ru0) * s10au / 0xffff
+			s10gu := uint32(s10gu0) * s10au / 0xffff
+			s10bu := uint32(s10bu0) * s10au / 0xffff
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01i := (sy1-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
+			s01j := (sy1-src.Rect.Min.Y)*src.CStride + ((sx0)/2 - src.Rect.Min.X/2)
+			s01k := (sy1-src.Rect.Min.Y)*src.AStride + (sx0 - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s01yy1 := int(src.Y[s01i]) * 0x10101
+			s01cb1 := int(src.Cb[s01j]) - 128
+			s01cr1 := int(src.Cr[s01j]) - 128
+			s01ru0 := (s01yy1 + 91881*s01cr1) >> 8
+			s01gu0 := (s01yy1 - 22554*s01cb1 - 46802*s01cr1) >> 8
+			s01bu0 := (s01yy1 + 116130*s01cb1) >> 8
+			if s01ru0 < 0 {
+				s01ru0 = 0
+			} else if s01ru0 > 0xffff {
+				s01ru0 = 0xffff
+			}
+			if s01gu0 < 0 {
+				s01gu0 = 0
+			} else if s01gu0 > 0xffff {
+				s01gu0 = 0xffff
+			}
+			if s01bu0 < 0 {
+				s01bu0 = 0
+			} else if s01bu0 > 0xffff {
+				s01bu0 = 0xffff
+			}
+
+			s01au := uint32(src.A[s01k]) * 0x101
+			s01ru := uint32(s01ru0) * s01au / 0xffff
+			s01gu := uint32(s01gu0) * s01au / 0xffff
+			s01bu := uint32(s01bu0) * s01au / 0xffff
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11i := (sy1-src.Rect.Min.Y)*src.YStride + (sx1 - src.Rect.Min.X)
+			s11j := (sy1-src.Rect.Min.Y)*src.CStride + ((sx1)/2 - src.Rect.Min.X/2)
+			s11k := (sy1-src.Rect.Min.Y)*src.AStride + (sx1 - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s11yy1 := int(src.Y[s11i]) * 0x10101
+			s11cb1 := int(src.Cb[s11j]) - 128
+			s11cr1 := int(src.Cr[s11j]) - 128
+			s11ru0 := (s11yy1 + 91881*s11cr1) >> 8
+			s11gu0 := (s11yy1 - 22554*s11cb1 - 46802*s11cr1) >> 8
+			s11bu0 := (s11yy1 + 116130*s11cb1) >> 8
+			if s11ru0 < 0 {
+				s11ru0 = 0
+			} else if s11ru0 > 0xffff {
+				s11ru0 = 0xffff
+			}
+			if s11gu0 < 0 {
+				s11gu0 = 0
+			} else if s11gu0 > 0xffff {
+				s11gu0 = 0xffff
+			}
+			if s11bu0 < 0 {
+				s11bu0 = 0
+			} else if s11bu0 > 0xffff {
+				s11bu0 = 0xffff
+			}
+
+			s11au := uint32(src.A[s11k]) * 0x101
+			s11ru := uint32(s11ru0) * s11au / 0xffff
+			s11gu := uint32(s11gu0) * s11au / 0xffff
+			s11bu := uint32(s11bu0) * s11au / 0xffff
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			dst.Pix[d+0] = uint8(pr >> 8)
+			dst.Pix[d+1] = uint8(pg >> 8)
+			dst.Pix[d+2] = uint8(pb >> 8)
+			dst.Pix[d+3] = uint8(pa >> 8)
+		}
+	}
+}
+
+func (ablInterpolator) transform_RGBA_NYCbCrA420_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NYCbCrA, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00i := (sy0-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
+			s00j := ((sy0)/2-src.Rect.Min.Y/2)*src.CStride + ((sx0)/2 - src.Rect.Min.X/2)
+			s00k := (sy0-src.Rect.Min.Y)*src.AStride + (sx0 - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s00yy1 := int(src.Y[s00i]) * 0x10101
+			s00cb1 := int(src.Cb[s00j]) - 128
+			s00cr1 := int(src.Cr[s00j]) - 128
+			s00ru0 := (s00yy1 + 91881*s00cr1) >> 8
+			s00gu0 := (s00yy1 - 22554*s00cb1 - 46802*s00cr1) >> 8
+			s00bu0 := (s00yy1 + 116130*s00cb1) >> 8
+			if s00ru0 < 0 {
+				s00ru0 = 0
+			} else if s00ru0 > 0xffff {
+				s00ru0 = 0xffff
+			}
+			if s00gu0 < 0 {
+				s00gu0 = 0
+			} else if s00gu0 > 0xffff {
+				s00gu0 = 0xffff
+			}
+			if s00bu0 < 0 {
+				s00bu0 = 0
+			} else if s00bu0 > 0xffff {
+				s00bu0 = 0xffff
+			}
+
+			s00au := uint32(src.A[s00k]) * 0x101
+			s00ru := uint32(s00ru0) * s00au / 0xffff
+			s00gu := uint32(s00gu0) * s00au / 0xffff
+			s00bu := uint32(s00bu0) * s00au / 0xffff
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10i := (sy0-src.Rect.Min.Y)*src.YStride + (sx1 - src.Rect.Min.X)
+			s10j := ((sy0)/2-src.Rect.Min.Y/2)*src.CStride + ((sx1)/2 - src.Rect.Min.X/2)
+			s10k := (sy0-src.Rect.Min.Y)*src.AStride + (sx1 - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s10yy1 := int(src.Y[s10i]) * 0x10101
+			s10cb1 := int(src.Cb[s10j]) - 128
+			s10cr1 := int(src.Cr[s10j]) - 128
+			s10ru0 := (s10yy1 + 91881*s10cr1) >> 8
+			s10gu0 := (s10yy1 - 22554*s10cb1 - 46802*s10cr1) >> 8
+			s10bu0 := (s10yy1 + 116130*s10cb1) >> 8
+			if s10ru0 < 0 {
+				s10ru0 = 0
+			} else if s10ru0 > 0xffff {
+				s10ru0 = 0xffff
+			}
+			if s10gu0 < 0 {
+				s10gu0 = 0
+			} else if s10gu0 > 0xffff {
+				s10gu0 = 0xffff
+			}
+			if s10bu0 < 0 {
+				s10bu0 = 0
+			} else if s10bu0 > 0xffff {
+				s10bu0 = 0xffff
+			}
+
+			s10au := uint32(src.A[s10k]) * 0x101
+			s10ru := uint32(s10ru0) * s10au / 0xffff
+			s10gu := uint32(s10gu0) * s10au / 0xffff
+			s10bu := uint32(s10bu0) * s10au / 0xffff
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01i := (sy1-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
+			s01j := ((sy1)/2-src.Rect.Min.Y/2)*src.CStride + ((sx0)/2 - src.Rect.Min.X/2)
+			s01k := (sy1-src.Rect.Min.Y)*src.AStride + (sx0 - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s01yy1 := int(src.Y[s01i]) * 0x10101
+			s01cb1 := int(src.Cb[s01j]) - 128
+			s01cr1 := int(src.Cr[s01j]) - 128
+			s01ru0 := (s01yy1 + 91881*s01cr1) >> 8
+			s01gu0 := (s01yy1 - 22554*s01cb1 - 46802*s01cr1) >> 8
+			s01bu0 := (s01yy1 + 116130*s01cb1) >> 8
+			if s01ru0 < 0 {
+				s01ru0 = 0
+			} else if s01ru0 > 0xffff {
+				s01ru0 = 0xffff
+			}
+			if s01gu0 < 0 {
+				s01gu0 = 0
+			} else if s01gu0 > 0xffff {
+				s01gu0 = 0xffff
+			}
+			if s01bu0 < 0 {
+				s01bu0 = 0
+			} else if s01bu0 > 0xffff {
+				s01bu0 = 0xffff
+			}
+
+			s01au := uint32(src.A[s01k]) * 0x101
+			s01ru := uint32(s01ru0) * s01au / 0xffff
+			s01gu := uint32(s01gu0) * s01au / 0xffff
+			s01bu := uint32(s01bu0) * s01au / 0xffff
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11i := (sy1-src.Rect.Min.Y)*src.YStride + (sx1 - src.Rect.Min.X)
+			s11j := ((sy1)/2-src.Rect.Min.Y/2)*src.CStride + ((sx1)/2 - src.Rect.Min.X/2)
+			s11k := (sy1-src.Rect.Min.Y)*src.AStride + (sx1 - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s11yy1 := int(src.Y[s11i]) * 0x10101
+			s11cb1 := int(src.Cb[s11j]) - 128
+			s11cr1 := int(src.Cr[s11j]) - 128
+			s11ru0 := (s11yy1 + 91881*s11cr1) >> 8
+			s11gu0 := (s11yy1 - 22554*s11cb1 - 46802*s11cr1) >> 8
+			s11bu0 := (s11yy1 + 116130*s11cb1) >> 8
+			if s11ru0 < 0 {
+				s11ru0 = 0
+			} else if s11ru0 > 0xffff {
+				s11ru0 = 0xffff
+			}
+			if s11gu0 < 0 {
+				s11gu0 = 0
+			} else if s11gu0 > 0xffff {
+				s11gu0 = 0xffff
+			}
+			if s11bu0 < 0 {
+				s11bu0 = 0
+			} else if s11bu0 > 0xffff {
+				s11bu0 = 0xffff
+			}
+
+			s11au := uint32(src.A[s11k]) * 0x101
+			s11ru := uint32(s11ru0) * s11au / 0xffff
+			s11gu := uint32(s11gu0) * s11au / 0xffff
+			s11bu := uint32(s11bu0) * s11au / 0xffff
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			dst.Pix[d+0] = uint8(pr >> 8)
+			dst.Pix[d+1] = uint8(pg >> 8)
+			dst.Pix[d+2] = uint8(pb >> 8)
+			dst.Pix[d+3] = uint8(pa >> 8)
+		}
+	}
+}
+
+func (ablInterpolator) transform_RGBA_NYCbCrA440_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NYCbCrA, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00i := (sy0-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
+			s00j := ((sy0)/2-src.Rect.Min.Y/2)*src.CStride + (sx0 - src.Rect.Min.X)
+			s00k := (sy0-src.Rect.Min.Y)*src.AStride + (sx0 - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s00yy1 := int(src.Y[s00i]) * 0x10101
+			s00cb1 := int(src.Cb[s00j]) - 128
+			s00cr1 := int(src.Cr[s00j]) - 128
+			s00ru0 := (s00yy1 + 91881*s00cr1) >> 8
+			s00gu0 := (s00yy1 - 22554*s00cb1 - 46802*s00cr1) >> 8
+			s00bu0 := (s00yy1 + 116130*s00cb1) >> 8
+			if s00ru0 < 0 {
+				s00ru0 = 0
+			} else if s00ru0 > 0xffff {
+				s00ru0 = 0xffff
+			}
+			if s00gu0 < 0 {
+				s00gu0 = 0
+			} else if s00gu0 > 0xffff {
+				s00gu0 = 0xffff
+			}
+			if s00bu0 < 0 {
+				s00bu0 = 0
+			} else if s00bu0 > 0xffff {
+				s00bu0 = 0xffff
+			}
+
+			s00au := uint32(src.A[s00k]) * 0x101
+			s00ru := uint32(s00ru0) * s00au / 0xffff
+			s00gu := uint32(s00gu0) * s00au / 0xffff
+			s00bu := uint32(s00bu0) * s00au / 0xffff
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10i := (sy0-src.Rect.Min.Y)*src.YStride + (sx1 - src.Rect.Min.X)
+			s10j := ((sy0)/2-src.Rect.Min.Y/2)*src.CStride + (sx1 - src.Rect.Min.X)
+			s10k := (sy0-src.Rect.Min.Y)*src.AStride + (sx1 - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s10yy1 := int(src.Y[s10i]) * 0x10101
+			s10cb1 := int(src.Cb[s10j]) - 128
+			s10cr1 := int(src.Cr[s10j]) - 128
+			s10ru0 := (s10yy1 + 91881*s10cr1) >> 8
+			s10gu0 := (s10yy1 - 22554*s10cb1 - 46802*s10cr1) >> 8
+			s10bu0 := (s10yy1 + 116130*s10cb1) >> 8
+			if s10ru0 < 0 {
+				s10ru0 = 0
+			} else if s10ru0 > 0xffff {
+				s10ru0 = 0xffff
+			}
+			if s10gu0 < 0 {
+				s10gu0 = 0
+			} else if s10gu0 > 0xffff {
+				s10gu0 = 0xffff
+			}
+			if s10bu0 < 0 {
+				s10bu0 = 0
+			} else if s10bu0 > 0xffff {
+				s10bu0 = 0xffff
+			}
+
+			s10au := uint32(src.A[s10k]) * 0x101
+			s10ru := uint32(s10ru0) * s10au / 0xffff
+			s10gu := uint32(s10gu0) * s10au / 0xffff
+			s10bu := uint32(s10bu0) * s10au / 0xffff
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01i := (sy1-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
+			s01j := ((sy1)/2-src.Rect.Min.Y/2)*src.CStride + (sx0 - src.Rect.Min.X)
+			s01k := (sy1-src.Rect.Min.Y)*src.AStride + (sx0 - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s01yy1 := int(src.Y[s01i]) * 0x10101
+			s01cb1 := int(src.Cb[s01j]) - 128
+			s01cr1 := int(src.Cr[s01j]) - 128
+			s01ru0 := (s01yy1 + 91881*s01cr1) >> 8
+			s01gu0 := (s01yy1 - 22554*s01cb1 - 46802*s01cr1) >> 8
+			s01bu0 := (s01yy1 + 116130*s01cb1) >> 8
+			if s01ru0 < 0 {
+				s01ru0 = 0
+			} else if s01ru0 > 0xffff {
+				s01ru0 = 0xffff
+			}
+			if s01gu0 < 0 {
+				s01gu0 = 0
+			} else if s01gu0 > 0xffff {
+				s01gu0 = 0xffff
+			}
+			if s01bu0 < 0 {
+				s01bu0 = 0
+			} else if s01bu0 > 0xffff {
+				s01bu0 = 0xffff
+			}
+
+			s01au := uint32(src.A[s01k]) * 0x101
+			s01ru := uint32(s01ru0) * s01au / 0xffff
+			s01gu := uint32(s01gu0) * s01au / 0xffff
+			s01bu := uint32(s01bu0) * s01au / 0xffff
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11i := (sy1-src.Rect.Min.Y)*src.YStride + (sx1 - src.Rect.Min.X)
+			s11j := ((sy1)/2-src.Rect.Min.Y/2)*src.CStride + (sx1 - src.Rect.Min.X)
+			s11k := (sy1-src.Rect.Min.Y)*src.AStride + (sx1 - src.Rect.Min.X)
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			s11yy1 := int(src.Y[s11i]) * 0x10101
+			s11cb1 := int(src.Cb[s11j]) - 128
+			s11cr1 := int(src.Cr[s11j]) - 128
+			s11ru0 := (s11yy1 + 91881*s11cr1) >> 8
+			s11gu0 := (s11yy1 - 22554*s11cb1 - 46802*s11cr1) >> 8
+			s11bu0 := (s11yy1 + 116130*s11cb1) >> 8
+			if s11ru0 < 0 {
+				s11ru0 = 0
+			} else if s11ru0 > 0xffff {
+				s11ru0 = 0xffff
+			}
+			if s11gu0 < 0 {
+				s11gu0 = 0
+			} else if s11gu0 > 0xffff {
+				s11gu0 = 0xffff
+			}
+			if s11bu0 < 0 {
+				s11bu0 = 0
+			} else if s11bu0 > 0xffff {
+				s11bu0 = 0xffff
+			}
+
+			s11au := uint32(src.A[s11k]) * 0x101
+			s11ru := uint32(s11ru0) * s11au / 0xffff
+			s11gu := uint32(s11gu0) * s11au / 0xffff
+			s11bu := uint32(s11bu0) * s11au / 0xffff
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			dst.Pix[d+0] = uint8(pr >> 8)
+			dst.Pix[d+1] = uint8(pg >> 8)
+			dst.Pix[d+2] = uint8(pb >> 8)
+			dst.Pix[d+3] = uint8(pa >> 8)
+		}
+	}
+}
+
+func (ablInterpolator) transform_RGBA_RGBA64Image_Over(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src image.RGBA64Image, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00u := src.RGBA64At(sx0, sy0)
+			s00r := float64(s00u.R)
+			s00g := float64(s00u.G)
+			s00b := float64(s00u.B)
+			s00a := float64(s00u.A)
+			s10u := src.RGBA64At(sx1, sy0)
+			s10r := float64(s10u.R)
+			s10g := float64(s10u.G)
+			s10b := float64(s10u.B)
+			s10a := float64(s10u.A)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01u := src.RGBA64At(sx0, sy1)
+			s01r := float64(s01u.R)
+			s01g := float64(s01u.G)
+			s01b := float64(s01u.B)
+			s01a := float64(s01u.A)
+			s11u := src.RGBA64At(sx1, sy1)
+			s11r := float64(s11u.R)
+			s11g := float64(s11u.G)
+			s11b := float64(s11u.B)
+			s11a := float64(s11u.A)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			p := color.RGBA64{uint16(s11r), uint16(s11g), uint16(s11b), uint16(s11a)}
+			pa1 := (0xffff - uint32(p.A)) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + uint32(p.R)) >> 8)
+			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + uint32(p.G)) >> 8)
+			dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*pa1/0xffff + uint32(p.B)) >> 8)
+			dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*pa1/0xffff + uint32(p.A)) >> 8)
+		}
+	}
+}
+
+func (ablInterpolator) transform_RGBA_RGBA64Image_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src image.RGBA64Image, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00u := src.RGBA64At(sx0, sy0)
+			s00r := float64(s00u.R)
+			s00g := float64(s00u.G)
+			s00b := float64(s00u.B)
+			s00a := float64(s00u.A)
+			s10u := src.RGBA64At(sx1, sy0)
+			s10r := float64(s10u.R)
+			s10g := float64(s10u.G)
+			s10b := float64(s10u.B)
+			s10a := float64(s10u.A)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01u := src.RGBA64At(sx0, sy1)
+			s01r := float64(s01u.R)
+			s01g := float64(s01u.G)
+			s01b := float64(s01u.B)
+			s01a := float64(s01u.A)
+			s11u := src.RGBA64At(sx1, sy1)
+			s11r := float64(s11u.R)
+			s11g := float64(s11u.G)
+			s11b := float64(s11u.B)
+			s11a := float64(s11u.A)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			p := color.RGBA64{uint16(s11r), uint16(s11g), uint16(s11b), uint16(s11a)}
+			dst.Pix[d+0] = uint8(p.R >> 8)
+			dst.Pix[d+1] = uint8(p.G >> 8)
+			dst.Pix[d+2] = uint8(p.B >> 8)
+			dst.Pix[d+3] = uint8(p.A >> 8)
+		}
+	}
+}
+
+func (ablInterpolator) transform_RGBA_Image_Over(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src image.Image, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00ru, s00gu, s00bu, s00au := src.At(sx0, sy0).RGBA()
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10ru, s10gu, s10bu, s10au := src.At(sx1, sy0).RGBA()
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01ru, s01gu, s01bu, s01au := src.At(sx0, sy1).RGBA()
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11ru, s11gu, s11bu, s11au := src.At(sx1, sy1).RGBA()
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			pa1 := (0xffff - pa) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pr) >> 8)
+			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + pg) >> 8)
+			dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*pa1/0xffff + pb) >> 8)
+			dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*pa1/0xffff + pa) >> 8)
+		}
+	}
+}
+
+func (ablInterpolator) transform_RGBA_Image_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src image.Image, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00ru, s00gu, s00bu, s00au := src.At(sx0, sy0).RGBA()
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10ru, s10gu, s10bu, s10au := src.At(sx1, sy0).RGBA()
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01ru, s01gu, s01bu, s01au := src.At(sx0, sy1).RGBA()
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11ru, s11gu, s11bu, s11au := src.At(sx1, sy1).RGBA()
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			dst.Pix[d+0] = uint8(pr >> 8)
+			dst.Pix[d+1] = uint8(pg >> 8)
+			dst.Pix[d+2] = uint8(pb >> 8)
+			dst.Pix[d+3] = uint8(pa >> 8)
+		}
+	}
+}
+
+func (ablInterpolator) transform_RGBA64_NRGBA64_Over(dst *image.RGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NRGBA64, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00i := (sy0-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*8
+			s00au := (uint32(src.Pix[s00i+6])<<8 | uint32(src.Pix[s00i+7]))
+			s00ru := (uint32(src.Pix[s00i+0])<<8 | uint32(src.Pix[s00i+1])) * s00au / 0xffff
+			s00gu := (uint32(src.Pix[s00i+2])<<8 | uint32(src.Pix[s00i+3])) * s00au / 0xffff
+			s00bu := (uint32(src.Pix[s00i+4])<<8 | uint32(src.Pix[s00i+5])) * s00au / 0xffff
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10i := (sy0-src.Rect.Min.Y)*src.Stride + (sx1-src.Rect.Min.X)*8
+			s10au := (uint32(src.Pix[s10i+6])<<8 | uint32(src.Pix[s10i+7]))
+			s10ru := (uint32(src.Pix[s10i+0])<<8 | uint32(src.Pix[s10i+1])) * s10au / 0xffff
+			s10gu := (uint32(src.Pix[s10i+2])<<8 | uint32(src.Pix[s10i+3])) * s10au / 0xffff
+			s10bu := (uint32(src.Pix[s10i+4])<<8 | uint32(src.Pix[s10i+5])) * s10au / 0xffff
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01i := (sy1-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*8
+			s01au := (uint32(src.Pix[s01i+6])<<8 | uint32(src.Pix[s01i+7]))
+			s01ru := (uint32(src.Pix[s01i+0])<<8 | uint32(src.Pix[s01i+1])) * s01au / 0xffff
+			s01gu := (uint32(src.Pix[s01i+2])<<8 | uint32(src.Pix[s01i+3])) * s01au / 0xffff
+			s01bu := (uint32(src.Pix[s01i+4])<<8 | uint32(src.Pix[s01i+5])) * s01au / 0xffff
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11i := (sy1-src.Rect.Min.Y)*src.Stride + (sx1-src.Rect.Min.X)*8
+			s11au := (uint32(src.Pix[s11i+6])<<8 | uint32(src.Pix[s11i+7]))
+			s11ru := (uint32(src.Pix[s11i+0])<<8 | uint32(src.Pix[s11i+1])) * s11au / 0xffff
+			s11gu := (uint32(src.Pix[s11i+2])<<8 | uint32(src.Pix[s11i+3])) * s11au / 0xffff
+			s11bu := (uint32(src.Pix[s11i+4])<<8 | uint32(src.Pix[s11i+5])) * s11au / 0xffff
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			pa1 := 0xffff - pa
+			pr0 := (uint32(dst.Pix[d+0])<<8|uint32(dst.Pix[d+1]))*pa1/0xffff + pr
+			pg0 := (uint32(dst.Pix[d+2])<<8|uint32(dst.Pix[d+3]))*pa1/0xffff + pg
+			pb0 := (uint32(dst.Pix[d+4])<<8|uint32(dst.Pix[d+5]))*pa1/0xffff + pb
+			pa0 := (uint32(dst.Pix[d+6])<<8|uint32(dst.Pix[d+7]))*pa1/0xffff + pa
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
+
+func (ablInterpolator) transform_RGBA64_NRGBA64_Src(dst *image.RGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NRGBA64, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00i := (sy0-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*8
+			s00au := (uint32(src.Pix[s00i+6])<<8 | uint32(src.Pix[s00i+7]))
+			s00ru := (uint32(src.Pix[s00i+0])<<8 | uint32(src.Pix[s00i+1])) * s00au / 0xffff
+			s00gu := (uint32(src.Pix[s00i+2])<<8 | uint32(src.Pix[s00i+3])) * s00au / 0xffff
+			s00bu := (uint32(src.Pix[s00i+4])<<8 | uint32(src.Pix[s00i+5])) * s00au / 0xffff
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10i := (sy0-src.Rect.Min.Y)*src.Stride + (sx1-src.Rect.Min.X)*8
+			s10au := (uint32(src.Pix[s10i+6])<<8 | uint32(src.Pix[s10i+7]))
+			s10ru := (uint32(src.Pix[s10i+0])<<8 | uint32(src.Pix[s10i+1])) * s10au / 0xffff
+			s10gu := (uint32(src.Pix[s10i+2])<<8 | uint32(src.Pix[s10i+3])) * s10au / 0xffff
+			s10bu := (uint32(src.Pix[s10i+4])<<8 | uint32(src.Pix[s10i+5])) * s10au / 0xffff
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01i := (sy1-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*8
+			s01au := (uint32(src.Pix[s01i+6])<<8 | uint32(src.Pix[s01i+7]))
+			s01ru := (uint32(src.Pix[s01i+0])<<8 | uint32(src.Pix[s01i+1])) * s01au / 0xffff
+			s01gu := (uint32(src.Pix[s01i+2])<<8 | uint32(src.Pix[s01i+3])) * s01au / 0xffff
+			s01bu := (uint32(src.Pix[s01i+4])<<8 | uint32(src.Pix[s01i+5])) * s01au / 0xffff
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11i := (sy1-src.Rect.Min.Y)*src.Stride + (sx1-src.Rect.Min.X)*8
+			s11au := (uint32(src.Pix[s11i+6])<<8 | uint32(src.Pix[s11i+7]))
+			s11ru := (uint32(src.Pix[s11i+0])<<8 | uint32(src.Pix[s11i+1])) * s11au / 0xffff
+			s11gu := (uint32(src.Pix[s11i+2])<<8 | uint32(src.Pix[s11i+3])) * s11au / 0xffff
+			s11bu := (uint32(src.Pix[s11i+4])<<8 | uint32(src.Pix[s11i+5])) * s11au / 0xffff
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			pr0 := pr
+			pg0 := pg
+			pb0 := pb
+			pa0 := pa
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
+
+func (ablInterpolator) transform_RGBA64_RGBA64_Over(dst *image.RGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.RGBA64, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00i := (sy0-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*8
+			s00ru := (uint32(src.Pix[s00i+0])<<8 | uint32(src.Pix[s00i+1]))
+			s00gu := (uint32(src.Pix[s00i+2])<<8 | uint32(src.Pix[s00i+3]))
+			s00bu := (uint32(src.Pix[s00i+4])<<8 | uint32(src.Pix[s00i+5]))
+			s00au := (uint32(src.Pix[s00i+6])<<8 | uint32(src.Pix[s00i+7]))
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10i := (sy0-src.Rect.Min.Y)*src.Stride + (sx1-src.Rect.Min.X)*8
+			s10ru := (uint32(src.Pix[s10i+0])<<8 | uint32(src.Pix[s10i+1]))
+			s10gu := (uint32(src.Pix[s10i+2])<<8 | uint32(src.Pix[s10i+3]))
+			s10bu := (uint32(src.Pix[s10i+4])<<8 | uint32(src.Pix[s10i+5]))
+			s10au := (uint32(src.Pix[s10i+6])<<8 | uint32(src.Pix[s10i+7]))
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01i := (sy1-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*8
+			s01ru := (uint32(src.Pix[s01i+0])<<8 | uint32(src.Pix[s01i+1]))
+			s01gu := (uint32(src.Pix[s01i+2])<<8 | uint32(src.Pix[s01i+3]))
+			s01bu := (uint32(src.Pix[s01i+4])<<8 | uint32(src.Pix[s01i+5]))
+			s01au := (uint32(src.Pix[s01i+6])<<8 | uint32(src.Pix[s01i+7]))
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11i := (sy1-src.Rect.Min.Y)*src.Stride + (sx1-src.Rect.Min.X)*8
+			s11ru := (uint32(src.Pix[s11i+0])<<8 | uint32(src.Pix[s11i+1]))
+			s11gu := (uint32(src.Pix[s11i+2])<<8 | uint32(src.Pix[s11i+3]))
+			s11bu := (uint32(src.Pix[s11i+4])<<8 | uint32(src.Pix[s11i+5]))
+			s11au := (uint32(src.Pix[s11i+6])<<8 | uint32(src.Pix[s11i+7]))
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			pa1 := 0xffff - pa
+			pr0 := (uint32(dst.Pix[d+0])<<8|uint32(dst.Pix[d+1]))*pa1/0xffff + pr
+			pg0 := (uint32(dst.Pix[d+2])<<8|uint32(dst.Pix[d+3]))*pa1/0xffff + pg
+			pb0 := (uint32(dst.Pix[d+4])<<8|uint32(dst.Pix[d+5]))*pa1/0xffff + pb
+			pa0 := (uint32(dst.Pix[d+6])<<8|uint32(dst.Pix[d+7]))*pa1/0xffff + pa
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
+
+func (ablInterpolator) transform_RGBA64_RGBA64_Src(dst *image.RGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.RGBA64, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00i := (sy0-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*8
+			s00ru := (uint32(src.Pix[s00i+0])<<8 | uint32(src.Pix[s00i+1]))
+			s00gu := (uint32(src.Pix[s00i+2])<<8 | uint32(src.Pix[s00i+3]))
+			s00bu := (uint32(src.Pix[s00i+4])<<8 | uint32(src.Pix[s00i+5]))
+			s00au := (uint32(src.Pix[s00i+6])<<8 | uint32(src.Pix[s00i+7]))
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10i := (sy0-src.Rect.Min.Y)*src.Stride + (sx1-src.Rect.Min.X)*8
+			s10ru := (uint32(src.Pix[s10i+0])<<8 | uint32(src.Pix[s10i+1]))
+			s10gu := (uint32(src.Pix[s10i+2])<<8 | uint32(src.Pix[s10i+3]))
+			s10bu := (uint32(src.Pix[s10i+4])<<8 | uint32(src.Pix[s10i+5]))
+			s10au := (uint32(src.Pix[s10i+6])<<8 | uint32(src.Pix[s10i+7]))
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01i := (sy1-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*8
+			s01ru := (uint32(src.Pix[s01i+0])<<8 | uint32(src.Pix[s01i+1]))
+			s01gu := (uint32(src.Pix[s01i+2])<<8 | uint32(src.Pix[s01i+3]))
+			s01bu := (uint32(src.Pix[s01i+4])<<8 | uint32(src.Pix[s01i+5]))
+			s01au := (uint32(src.Pix[s01i+6])<<8 | uint32(src.Pix[s01i+7]))
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11i := (sy1-src.Rect.Min.Y)*src.Stride + (sx1-src.Rect.Min.X)*8
+			s11ru := (uint32(src.Pix[s11i+0])<<8 | uint32(src.Pix[s11i+1]))
+			s11gu := (uint32(src.Pix[s11i+2])<<8 | uint32(src.Pix[s11i+3]))
+			s11bu := (uint32(src.Pix[s11i+4])<<8 | uint32(src.Pix[s11i+5]))
+			s11au := (uint32(src.Pix[s11i+6])<<8 | uint32(src.Pix[s11i+7]))
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			pr0 := pr
+			pg0 := pg
+			pb0 := pb
+			pa0 := pa
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
+
+func (ablInterpolator) transform_RGBA64_RGBA64Image_Over(dst *image.RGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src image.RGBA64Image, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00u := src.RGBA64At(sx0, sy0)
+			s00r := float64(s00u.R)
+			s00g := float64(s00u.G)
+			s00b := float64(s00u.B)
+			s00a := float64(s00u.A)
+			s10u := src.RGBA64At(sx1, sy0)
+			s10r := float64(s10u.R)
+			s10g := float64(s10u.G)
+			s10b := float64(s10u.B)
+			s10a := float64(s10u.A)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01u := src.RGBA64At(sx0, sy1)
+			s01r := float64(s01u.R)
+			s01g := float64(s01u.G)
+			s01b := float64(s01u.B)
+			s01a := float64(s01u.A)
+			s11u := src.RGBA64At(sx1, sy1)
+			s11r := float64(s11u.R)
+			s11g := float64(s11u.G)
+			s11b := float64(s11u.B)
+			s11a := float64(s11u.A)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			p := color.RGBA64{uint16(s11r), uint16(s11g), uint16(s11b), uint16(s11a)}
+			pa1 := 0xffff - uint32(p.A)
+			pr0 := (uint32(dst.Pix[d+0])<<8|uint32(dst.Pix[d+1]))*pa1/0xffff + uint32(p.R)
+			pg0 := (uint32(dst.Pix[d+2])<<8|uint32(dst.Pix[d+3]))*pa1/0xffff + uint32(p.G)
+			pb0 := (uint32(dst.Pix[d+4])<<8|uint32(dst.Pix[d+5]))*pa1/0xffff + uint32(p.B)
+			pa0 := (uint32(dst.Pix[d+6])<<8|uint32(dst.Pix[d+7]))*pa1/0xffff + uint32(p.A)
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
+
+func (ablInterpolator) transform_RGBA64_RGBA64Image_Src(dst *image.RGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src image.RGBA64Image, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00u := src.RGBA64At(sx0, sy0)
+			s00r := float64(s00u.R)
+			s00g := float64(s00u.G)
+			s00b := float64(s00u.B)
+			s00a := float64(s00u.A)
+			s10u := src.RGBA64At(sx1, sy0)
+			s10r := float64(s10u.R)
+			s10g := float64(s10u.G)
+			s10b := float64(s10u.B)
+			s10a := float64(s10u.A)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01u := src.RGBA64At(sx0, sy1)
+			s01r := float64(s01u.R)
+			s01g := float64(s01u.G)
+			s01b := float64(s01u.B)
+			s01a := float64(s01u.A)
+			s11u := src.RGBA64At(sx1, sy1)
+			s11r := float64(s11u.R)
+			s11g := float64(s11u.G)
+			s11b := float64(s11u.B)
+			s11a := float64(s11u.A)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			p := color.RGBA64{uint16(s11r), uint16(s11g), uint16(s11b), uint16(s11a)}
+			pr0 := uint32(p.R)
+			pg0 := uint32(p.G)
+			pb0 := uint32(p.B)
+			pa0 := uint32(p.A)
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
+
+func (ablInterpolator) transform_RGBA64_Image_Over(dst *image.RGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src image.Image, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00ru, s00gu, s00bu, s00au := src.At(sx0, sy0).RGBA()
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10ru, s10gu, s10bu, s10au := src.At(sx1, sy0).RGBA()
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01ru, s01gu, s01bu, s01au := src.At(sx0, sy1).RGBA()
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11ru, s11gu, s11bu, s11au := src.At(sx1, sy1).RGBA()
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			pa1 := 0xffff - pa
+			pr0 := (uint32(dst.Pix[d+0])<<8|uint32(dst.Pix[d+1]))*pa1/0xffff + pr
+			pg0 := (uint32(dst.Pix[d+2])<<8|uint32(dst.Pix[d+3]))*pa1/0xffff + pg
+			pb0 := (uint32(dst.Pix[d+4])<<8|uint32(dst.Pix[d+5]))*pa1/0xffff + pb
+			pa0 := (uint32(dst.Pix[d+6])<<8|uint32(dst.Pix[d+7]))*pa1/0xffff + pa
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
+
+func (ablInterpolator) transform_RGBA64_Image_Src(dst *image.RGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src image.Image, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00ru, s00gu, s00bu, s00au := src.At(sx0, sy0).RGBA()
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10ru, s10gu, s10bu, s10au := src.At(sx1, sy0).RGBA()
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01ru, s01gu, s01bu, s01au := src.At(sx0, sy1).RGBA()
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11ru, s11gu, s11bu, s11au := src.At(sx1, sy1).RGBA()
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			pr0 := pr
+			pg0 := pg
+			pb0 := pb
+			pa0 := pa
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
+
+func (ablInterpolator) transform_NRGBA64_NRGBA64_Over(dst *image.NRGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NRGBA64, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00i := (sy0-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*8
+			s00au := (uint32(src.Pix[s00i+6])<<8 | uint32(src.Pix[s00i+7]))
+			s00ru := (uint32(src.Pix[s00i+0])<<8 | uint32(src.Pix[s00i+1])) * s00au / 0xffff
+			s00gu := (uint32(src.Pix[s00i+2])<<8 | uint32(src.Pix[s00i+3])) * s00au / 0xffff
+			s00bu := (uint32(src.Pix[s00i+4])<<8 | uint32(src.Pix[s00i+5])) * s00au / 0xffff
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10i := (sy0-src.Rect.Min.Y)*src.Stride + (sx1-src.Rect.Min.X)*8
+			s10au := (uint32(src.Pix[s10i+6])<<8 | uint32(src.Pix[s10i+7]))
+			s10ru := (uint32(src.Pix[s10i+0])<<8 | uint32(src.Pix[s10i+1])) * s10au / 0xffff
+			s10gu := (uint32(src.Pix[s10i+2])<<8 | uint32(src.Pix[s10i+3])) * s10au / 0xffff
+			s10bu := (uint32(src.Pix[s10i+4])<<8 | uint32(src.Pix[s10i+5])) * s10au / 0xffff
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01i := (sy1-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*8
+			s01au := (uint32(src.Pix[s01i+6])<<8 | uint32(src.Pix[s01i+7]))
+			s01ru := (uint32(src.Pix[s01i+0])<<8 | uint32(src.Pix[s01i+1])) * s01au / 0xffff
+			s01gu := (uint32(src.Pix[s01i+2])<<8 | uint32(src.Pix[s01i+3])) * s01au / 0xffff
+			s01bu := (uint32(src.Pix[s01i+4])<<8 | uint32(src.Pix[s01i+5])) * s01au / 0xffff
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11i := (sy1-src.Rect.Min.Y)*src.Stride + (sx1-src.Rect.Min.X)*8
+			s11au := (uint32(src.Pix[s11i+6])<<8 | uint32(src.Pix[s11i+7]))
+			s11ru := (uint32(src.Pix[s11i+0])<<8 | uint32(src.Pix[s11i+1])) * s11au / 0xffff
+			s11gu := (uint32(src.Pix[s11i+2])<<8 | uint32(src.Pix[s11i+3])) * s11au / 0xffff
+			s11bu := (uint32(src.Pix[s11i+4])<<8 | uint32(src.Pix[s11i+5])) * s11au / 0xffff
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			pa1 := 0xffff - pa
+			pqa := (uint32(dst.Pix[d+6])<<8 | uint32(dst.Pix[d+7]))
+			pr0 := ((uint32(dst.Pix[d+0])<<8|uint32(dst.Pix[d+1]))*pqa/0xffff)*pa1/0xffff + pr
+			pg0 := ((uint32(dst.Pix[d+2])<<8|uint32(dst.Pix[d+3]))*pqa/0xffff)*pa1/0xffff + pg
+			pb0 := ((uint32(dst.Pix[d+4])<<8|uint32(dst.Pix[d+5]))*pqa/0xffff)*pa1/0xffff + pb
+			pa0 := pqa*pa1/0xffff + pa
+			if pa0 != 0 && pa0 != 0xffff {
+				pr0 = pr0 * 0xffff / pa0
+				pg0 = pg0 * 0xffff / pa0
+				pb0 = pb0 * 0xffff / pa0
+			}
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
+
+func (ablInterpolator) transform_NRGBA64_NRGBA64_Src(dst *image.NRGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NRGBA64, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00i := (sy0-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*8
+			s00au := (uint32(src.Pix[s00i+6])<<8 | uint32(src.Pix[s00i+7]))
+			s00ru := (uint32(src.Pix[s00i+0])<<8 | uint32(src.Pix[s00i+1])) * s00au / 0xffff
+			s00gu := (uint32(src.Pix[s00i+2])<<8 | uint32(src.Pix[s00i+3])) * s00au / 0xffff
+			s00bu := (uint32(src.Pix[s00i+4])<<8 | uint32(src.Pix[s00i+5])) * s00au / 0xffff
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10i := (sy0-src.Rect.Min.Y)*src.Stride + (sx1-src.Rect.Min.X)*8
+			s10au := (uint32(src.Pix[s10i+6])<<8 | uint32(src.Pix[s10i+7]))
+			s10ru := (uint32(src.Pix[s10i+0])<<8 | uint32(src.Pix[s10i+1])) * s10au / 0xffff
+			s10gu := (uint32(src.Pix[s10i+2])<<8 | uint32(src.Pix[s10i+3])) * s10au / 0xffff
+			s10bu := (uint32(src.Pix[s10i+4])<<8 | uint32(src.Pix[s10i+5])) * s10au / 0xffff
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01i := (sy1-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*8
+			s01au := (uint32(src.Pix[s01i+6])<<8 | uint32(src.Pix[s01i+7]))
+			s01ru := (uint32(src.Pix[s01i+0])<<8 | uint32(src.Pix[s01i+1])) * s01au / 0xffff
+			s01gu := (uint32(src.Pix[s01i+2])<<8 | uint32(src.Pix[s01i+3])) * s01au / 0xffff
+			s01bu := (uint32(src.Pix[s01i+4])<<8 | uint32(src.Pix[s01i+5])) * s01au / 0xffff
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11i := (sy1-src.Rect.Min.Y)*src.Stride + (sx1-src.Rect.Min.X)*8
+			s11au := (uint32(src.Pix[s11i+6])<<8 | uint32(src.Pix[s11i+7]))
+			s11ru := (uint32(src.Pix[s11i+0])<<8 | uint32(src.Pix[s11i+1])) * s11au / 0xffff
+			s11gu := (uint32(src.Pix[s11i+2])<<8 | uint32(src.Pix[s11i+3])) * s11au / 0xffff
+			s11bu := (uint32(src.Pix[s11i+4])<<8 | uint32(src.Pix[s11i+5])) * s11au / 0xffff
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			pr0 := pr
+			pg0 := pg
+			pb0 := pb
+			pa0 := pa
+			if pa0 != 0 && pa0 != 0xffff {
+				pr0 = pr0 * 0xffff / pa0
+				pg0 = pg0 * 0xffff / pa0
+				pb0 = pb0 * 0xffff / pa0
+			}
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
+
+func (ablInterpolator) transform_NRGBA64_RGBA64_Over(dst *image.NRGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.RGBA64, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00i := (sy0-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*8
+			s00ru := (uint32(src.Pix[s00i+0])<<8 | uint32(src.Pix[s00i+1]))
+			s00gu := (uint32(src.Pix[s00i+2])<<8 | uint32(src.Pix[s00i+3]))
+			s00bu := (uint32(src.Pix[s00i+4])<<8 | uint32(src.Pix[s00i+5]))
+			s00au := (uint32(src.Pix[s00i+6])<<8 | uint32(src.Pix[s00i+7]))
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10i := (sy0-src.Rect.Min.Y)*src.Stride + (sx1-src.Rect.Min.X)*8
+			s10ru := (uint32(src.Pix[s10i+0])<<8 | uint32(src.Pix[s10i+1]))
+			s10gu := (uint32(src.Pix[s10i+2])<<8 | uint32(src.Pix[s10i+3]))
+			s10bu := (uint32(src.Pix[s10i+4])<<8 | uint32(src.Pix[s10i+5]))
+			s10au := (uint32(src.Pix[s10i+6])<<8 | uint32(src.Pix[s10i+7]))
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01i := (sy1-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*8
+			s01ru := (uint32(src.Pix[s01i+0])<<8 | uint32(src.Pix[s01i+1]))
+			s01gu := (uint32(src.Pix[s01i+2])<<8 | uint32(src.Pix[s01i+3]))
+			s01bu := (uint32(src.Pix[s01i+4])<<8 | uint32(src.Pix[s01i+5]))
+			s01au := (uint32(src.Pix[s01i+6])<<8 | uint32(src.Pix[s01i+7]))
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11i := (sy1-src.Rect.Min.Y)*src.Stride + (sx1-src.Rect.Min.X)*8
+			s11ru := (uint32(src.Pix[s11i+0])<<8 | uint32(src.Pix[s11i+1]))
+			s11gu := (uint32(src.Pix[s11i+2])<<8 | uint32(src.Pix[s11i+3]))
+			s11bu := (uint32(src.Pix[s11i+4])<<8 | uint32(src.Pix[s11i+5]))
+			s11au := (uint32(src.Pix[s11i+6])<<8 | uint32(src.Pix[s11i+7]))
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			pa1 := 0xffff - pa
+			pqa := (uint32(dst.Pix[d+6])<<8 | uint32(dst.Pix[d+7]))
+			pr0 := ((uint32(dst.Pix[d+0])<<8|uint32(dst.Pix[d+1]))*pqa/0xffff)*pa1/0xffff + pr
+			pg0 := ((uint32(dst.Pix[d+2])<<8|uint32(dst.Pix[d+3]))*pqa/0xffff)*pa1/0xffff + pg
+			pb0 := ((uint32(dst.Pix[d+4])<<8|uint32(dst.Pix[d+5]))*pqa/0xffff)*pa1/0xffff + pb
+			pa0 := pqa*pa1/0xffff + pa
+			if pa0 != 0 && pa0 != 0xffff {
+				pr0 = pr0 * 0xffff / pa0
+				pg0 = pg0 * 0xffff / pa0
+				pb0 = pb0 * 0xffff / pa0
+			}
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
+
+func (ablInterpolator) transform_NRGBA64_RGBA64_Src(dst *image.NRGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.RGBA64, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00i := (sy0-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*8
+			s00ru := (uint32(src.Pix[s00i+0])<<8 | uint32(src.Pix[s00i+1]))
+			s00gu := (uint32(src.Pix[s00i+2])<<8 | uint32(src.Pix[s00i+3]))
+			s00bu := (uint32(src.Pix[s00i+4])<<8 | uint32(src.Pix[s00i+5]))
+			s00au := (uint32(src.Pix[s00i+6])<<8 | uint32(src.Pix[s00i+7]))
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10i := (sy0-src.Rect.Min.Y)*src.Stride + (sx1-src.Rect.Min.X)*8
+			s10ru := (uint32(src.Pix[s10i+0])<<8 | uint32(src.Pix[s10i+1]))
+			s10gu := (uint32(src.Pix[s10i+2])<<8 | uint32(src.Pix[s10i+3]))
+			s10bu := (uint32(src.Pix[s10i+4])<<8 | uint32(src.Pix[s10i+5]))
+			s10au := (uint32(src.Pix[s10i+6])<<8 | uint32(src.Pix[s10i+7]))
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01i := (sy1-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*8
+			s01ru := (uint32(src.Pix[s01i+0])<<8 | uint32(src.Pix[s01i+1]))
+			s01gu := (uint32(src.Pix[s01i+2])<<8 | uint32(src.Pix[s01i+3]))
+			s01bu := (uint32(src.Pix[s01i+4])<<8 | uint32(src.Pix[s01i+5]))
+			s01au := (uint32(src.Pix[s01i+6])<<8 | uint32(src.Pix[s01i+7]))
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11i := (sy1-src.Rect.Min.Y)*src.Stride + (sx1-src.Rect.Min.X)*8
+			s11ru := (uint32(src.Pix[s11i+0])<<8 | uint32(src.Pix[s11i+1]))
+			s11gu := (uint32(src.Pix[s11i+2])<<8 | uint32(src.Pix[s11i+3]))
+			s11bu := (uint32(src.Pix[s11i+4])<<8 | uint32(src.Pix[s11i+5]))
+			s11au := (uint32(src.Pix[s11i+6])<<8 | uint32(src.Pix[s11i+7]))
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			pr0 := pr
+			pg0 := pg
+			pb0 := pb
+			pa0 := pa
+			if pa0 != 0 && pa0 != 0xffff {
+				pr0 = pr0 * 0xffff / pa0
+				pg0 = pg0 * 0xffff / pa0
+				pb0 = pb0 * 0xffff / pa0
+			}
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
+
+func (ablInterpolator) transform_NRGBA64_RGBA64Image_Over(dst *image.NRGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src image.RGBA64Image, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00u := src.RGBA64At(sx0, sy0)
+			s00r := float64(s00u.R)
+			s00g := float64(s00u.G)
+			s00b := float64(s00u.B)
+			s00a := float64(s00u.A)
+			s10u := src.RGBA64At(sx1, sy0)
+			s10r := float64(s10u.R)
+			s10g := float64(s10u.G)
+			s10b := float64(s10u.B)
+			s10a := float64(s10u.A)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01u := src.RGBA64At(sx0, sy1)
+			s01r := float64(s01u.R)
+			s01g := float64(s01u.G)
+			s01b := float64(s01u.B)
+			s01a := float64(s01u.A)
+			s11u := src.RGBA64At(sx1, sy1)
+			s11r := float64(s11u.R)
+			s11g := float64(s11u.G)
+			s11b := float64(s11u.B)
+			s11a := float64(s11u.A)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			p := color.RGBA64{uint16(s11r), uint16(s11g), uint16(s11b), uint16(s11a)}
+			pa1 := 0xffff - uint32(p.A)
+			pqa := (uint32(dst.Pix[d+6])<<8 | uint32(dst.Pix[d+7]))
+			pr0 := ((uint32(dst.Pix[d+0])<<8|uint32(dst.Pix[d+1]))*pqa/0xffff)*pa1/0xffff + uint32(p.R)
+			pg0 := ((uint32(dst.Pix[d+2])<<8|uint32(dst.Pix[d+3]))*pqa/0xffff)*pa1/0xffff + uint32(p.G)
+			pb0 := ((uint32(dst.Pix[d+4])<<8|uint32(dst.Pix[d+5]))*pqa/0xffff)*pa1/0xffff + uint32(p.B)
+			pa0 := pqa*pa1/0xffff + uint32(p.A)
+			if pa0 != 0 && pa0 != 0xffff {
+				pr0 = pr0 * 0xffff / pa0
+				pg0 = pg0 * 0xffff / pa0
+				pb0 = pb0 * 0xffff / pa0
+			}
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
+
+func (ablInterpolator) transform_NRGBA64_RGBA64Image_Src(dst *image.NRGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src image.RGBA64Image, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00u := src.RGBA64At(sx0, sy0)
+			s00r := float64(s00u.R)
+			s00g := float64(s00u.G)
+			s00b := float64(s00u.B)
+			s00a := float64(s00u.A)
+			s10u := src.RGBA64At(sx1, sy0)
+			s10r := float64(s10u.R)
+			s10g := float64(s10u.G)
+			s10b := float64(s10u.B)
+			s10a := float64(s10u.A)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01u := src.RGBA64At(sx0, sy1)
+			s01r := float64(s01u.R)
+			s01g := float64(s01u.G)
+			s01b := float64(s01u.B)
+			s01a := float64(s01u.A)
+			s11u := src.RGBA64At(sx1, sy1)
+			s11r := float64(s11u.R)
+			s11g := float64(s11u.G)
+			s11b := float64(s11u.B)
+			s11a := float64(s11u.A)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			p := color.RGBA64{uint16(s11r), uint16(s11g), uint16(s11b), uint16(s11a)}
+			pr0 := uint32(p.R)
+			pg0 := uint32(p.G)
+			pb0 := uint32(p.B)
+			pa0 := uint32(p.A)
+			if pa0 != 0 && pa0 != 0xffff {
+				pr0 = pr0 * 0xffff / pa0
+				pg0 = pg0 * 0xffff / pa0
+				pb0 = pb0 * 0xffff / pa0
+			}
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
+
+func (ablInterpolator) transform_NRGBA64_Image_Over(dst *image.NRGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src image.Image, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00ru, s00gu, s00bu, s00au := src.At(sx0, sy0).RGBA()
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10ru, s10gu, s10bu, s10au := src.At(sx1, sy0).RGBA()
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01ru, s01gu, s01bu, s01au := src.At(sx0, sy1).RGBA()
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11ru, s11gu, s11bu, s11au := src.At(sx1, sy1).RGBA()
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			pa1 := 0xffff - pa
+			pqa := (uint32(dst.Pix[d+6])<<8 | uint32(dst.Pix[d+7]))
+			pr0 := ((uint32(dst.Pix[d+0])<<8|uint32(dst.Pix[d+1]))*pqa/0xffff)*pa1/0xffff + pr
+			pg0 := ((uint32(dst.Pix[d+2])<<8|uint32(dst.Pix[d+3]))*pqa/0xffff)*pa1/0xffff + pg
+			pb0 := ((uint32(dst.Pix[d+4])<<8|uint32(dst.Pix[d+5]))*pqa/0xffff)*pa1/0xffff + pb
+			pa0 := pqa*pa1/0xffff + pa
+			if pa0 != 0 && pa0 != 0xffff {
+				pr0 = pr0 * 0xffff / pa0
+				pg0 = pg0 * 0xffff / pa0
+				pb0 = pb0 * 0xffff / pa0
+			}
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
+
+func (ablInterpolator) transform_NRGBA64_Image_Src(dst *image.NRGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src image.Image, sr image.Rectangle, bias image.Point, opts *Options) {
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00ru, s00gu, s00bu, s00au := src.At(sx0, sy0).RGBA()
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10ru, s10gu, s10bu, s10au := src.At(sx1, sy0).RGBA()
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01ru, s01gu, s01bu, s01au := src.At(sx0, sy1).RGBA()
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11ru, s11gu, s11bu, s11au := src.At(sx1, sy1).RGBA()
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			pr0 := pr
+			pg0 := pg
+			pb0 := pb
+			pa0 := pa
+			if pa0 != 0 && pa0 != 0xffff {
+				pr0 = pr0 * 0xffff / pa0
+				pg0 = pg0 * 0xffff / pa0
+				pb0 = pb0 * 0xffff / pa0
+			}
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+		}
+	}
+}
+
+func (ablInterpolator) transform_RGBA64Image_RGBA64Image_Over(dst RGBA64Image, dr, adr image.Rectangle, d2s *f64.Aff3, src image.RGBA64Image, sr image.Rectangle, bias image.Point, opts *Options) {
+	srcMask, smp := opts.SrcMask, opts.SrcMaskP
+	dstMask, dmp := opts.DstMask, opts.DstMaskP
+	dstColorRGBA64 := color.RGBA64{}
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00u := src.RGBA64At(sx0, sy0)
+			if srcMask != nil {
+				_, _, _, ma := srcMask.At(smp.X+sx0, smp.Y+sy0).RGBA()
+				s00u.R = uint16(uint32(s00u.R) * ma / 0xffff)
+				s00u.G = uint16(uint32(s00u.G) * ma / 0xffff)
+				s00u.B = uint16(uint32(s00u.B) * ma / 0xffff)
+				s00u.A = uint16(uint32(s00u.A) * ma / 0xffff)
+			}
+			s00r := float64(s00u.R)
+			s00g := float64(s00u.G)
+			s00b := float64(s00u.B)
+			s00a := float64(s00u.A)
+			s10u := src.RGBA64At(sx1, sy0)
+			if srcMask != nil {
+				_, _, _, ma := srcMask.At(smp.X+sx1, smp.Y+sy0).RGBA()
+				s10u.R = uint16(uint32(s10u.R) * ma / 0xffff)
+				s10u.G = uint16(uint32(s10u.G) * ma / 0xffff)
+				s10u.B = uint16(uint32(s10u.B) * ma / 0xffff)
+				s10u.A = uint16(uint32(s10u.A) * ma / 0xffff)
+			}
+			s10r := float64(s10u.R)
+			s10g := float64(s10u.G)
+			s10b := float64(s10u.B)
+			s10a := float64(s10u.A)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01u := src.RGBA64At(sx0, sy1)
+			if srcMask != nil {
+				_, _, _, ma := srcMask.At(smp.X+sx0, smp.Y+sy1).RGBA()
+				s01u.R = uint16(uint32(s01u.R) * ma / 0xffff)
+				s01u.G = uint16(uint32(s01u.G) * ma / 0xffff)
+				s01u.B = uint16(uint32(s01u.B) * ma / 0xffff)
+				s01u.A = uint16(uint32(s01u.A) * ma / 0xffff)
+			}
+			s01r := float64(s01u.R)
+			s01g := float64(s01u.G)
+			s01b := float64(s01u.B)
+			s01a := float64(s01u.A)
+			s11u := src.RGBA64At(sx1, sy1)
+			if srcMask != nil {
+				_, _, _, ma := srcMask.At(smp.X+sx1, smp.Y+sy1).RGBA()
+				s11u.R = uint16(uint32(s11u.R) * ma / 0xffff)
+				s11u.G = uint16(uint32(s11u.G) * ma / 0xffff)
+				s11u.B = uint16(uint32(s11u.B) * ma / 0xffff)
+				s11u.A = uint16(uint32(s11u.A) * ma / 0xffff)
+			}
+			s11r := float64(s11u.R)
+			s11g := float64(s11u.G)
+			s11b := float64(s11u.B)
+			s11a := float64(s11u.A)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			p := color.RGBA64{uint16(s11r), uint16(s11g), uint16(s11b), uint16(s11a)}
+			q := dst.RGBA64At(dr.Min.X+int(dx), dr.Min.Y+int(dy))
+			if dstMask != nil {
+				_, _, _, ma := dstMask.At(dmp.X+dr.Min.X+int(dx), dmp.Y+dr.Min.Y+int(dy)).RGBA()
+				p.R = uint16(uint32(p.R) * ma / 0xffff)
+				p.G = uint16(uint32(p.G) * ma / 0xffff)
+				p.B = uint16(uint32(p.B) * ma / 0xffff)
+				p.A = uint16(uint32(p.A) * ma / 0xffff)
+			}
+			pa1 := 0xffff - uint32(p.A)
+			dstColorRGBA64.R = uint16(uint32(q.R)*pa1/0xffff + uint32(p.R))
+			dstColorRGBA64.G = uint16(uint32(q.G)*pa1/0xffff + uint32(p.G))
+			dstColorRGBA64.B = uint16(uint32(q.B)*pa1/0xffff + uint32(p.B))
+			dstColorRGBA64.A = uint16(uint32(q.A)*pa1/0xffff + uint32(p.A))
+			dst.SetRGBA64(dr.Min.X+int(dx), dr.Min.Y+int(dy), dstColorRGBA64)
+		}
+	}
+}
+
+func (ablInterpolator) transform_RGBA64Image_RGBA64Image_Src(dst RGBA64Image, dr, adr image.Rectangle, d2s *f64.Aff3, src image.RGBA64Image, sr image.Rectangle, bias image.Point, opts *Options) {
+	srcMask, smp := opts.SrcMask, opts.SrcMaskP
+	dstMask, dmp := opts.DstMask, opts.DstMaskP
+	dstColorRGBA64 := color.RGBA64{}
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00u := src.RGBA64At(sx0, sy0)
+			if srcMask != nil {
+				_, _, _, ma := srcMask.At(smp.X+sx0, smp.Y+sy0).RGBA()
+				s00u.R = uint16(uint32(s00u.R) * ma / 0xffff)
+				s00u.G = uint16(uint32(s00u.G) * ma / 0xffff)
+				s00u.B = uint16(uint32(s00u.B) * ma / 0xffff)
+				s00u.A = uint16(uint32(s00u.A) * ma / 0xffff)
+			}
+			s00r := float64(s00u.R)
+			s00g := float64(s00u.G)
+			s00b := float64(s00u.B)
+			s00a := float64(s00u.A)
+			s10u := src.RGBA64At(sx1, sy0)
+			if srcMask != nil {
+				_, _, _, ma := srcMask.At(smp.X+sx1, smp.Y+sy0).RGBA()
+				s10u.R = uint16(uint32(s10u.R) * ma / 0xffff)
+				s10u.G = uint16(uint32(s10u.G) * ma / 0xffff)
+				s10u.B = uint16(uint32(s10u.B) * ma / 0xffff)
+				s10u.A = uint16(uint32(s10u.A) * ma / 0xffff)
+			}
+			s10r := float64(s10u.R)
+			s10g := float64(s10u.G)
+			s10b := float64(s10u.B)
+			s10a := float64(s10u.A)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01u := src.RGBA64At(sx0, sy1)
+			if srcMask != nil {
+				_, _, _, ma := srcMask.At(smp.X+sx0, smp.Y+sy1).RGBA()
+				s01u.R = uint16(uint32(s01u.R) * ma / 0xffff)
+				s01u.G = uint16(uint32(s01u.G) * ma / 0xffff)
+				s01u.B = uint16(uint32(s01u.B) * ma / 0xffff)
+				s01u.A = uint16(uint32(s01u.A) * ma / 0xffff)
+			}
+			s01r := float64(s01u.R)
+			s01g := float64(s01u.G)
+			s01b := float64(s01u.B)
+			s01a := float64(s01u.A)
+			s11u := src.RGBA64At(sx1, sy1)
+			if srcMask != nil {
+				_, _, _, ma := srcMask.At(smp.X+sx1, smp.Y+sy1).RGBA()
+				s11u.R = uint16(uint32(s11u.R) * ma / 0xffff)
+				s11u.G = uint16(uint32(s11u.G) * ma / 0xffff)
+				s11u.B = uint16(uint32(s11u.B) * ma / 0xffff)
+				s11u.A = uint16(uint32(s11u.A) * ma / 0xffff)
+			}
+			s11r := float64(s11u.R)
+			s11g := float64(s11u.G)
+			s11b := float64(s11u.B)
+			s11a := float64(s11u.A)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			p := color.RGBA64{uint16(s11r), uint16(s11g), uint16(s11b), uint16(s11a)}
+			if dstMask != nil {
+				q := dst.RGBA64At(dr.Min.X+int(dx), dr.Min.Y+int(dy))
+				_, _, _, ma := dstMask.At(dmp.X+dr.Min.X+int(dx), dmp.Y+dr.Min.Y+int(dy)).RGBA()
+				p.R = uint16(uint32(p.R) * ma / 0xffff)
+				p.G = uint16(uint32(p.G) * ma / 0xffff)
+				p.B = uint16(uint32(p.B) * ma / 0xffff)
+				p.A = uint16(uint32(p.A) * ma / 0xffff)
+				pa1 := 0xffff - ma
+				dstColorRGBA64.R = uint16(uint32(q.R)*pa1/0xffff + uint32(p.R))
+				dstColorRGBA64.G = uint16(uint32(q.G)*pa1/0xffff + uint32(p.G))
+				dstColorRGBA64.B = uint16(uint32(q.B)*pa1/0xffff + uint32(p.B))
+				dstColorRGBA64.A = uint16(uint32(q.A)*pa1/0xffff + uint32(p.A))
+				dst.SetRGBA64(dr.Min.X+int(dx), dr.Min.Y+int(dy), dstColorRGBA64)
+			} else {
+				dst.SetRGBA64(dr.Min.X+int(dx), dr.Min.Y+int(dy), p)
+			}
+		}
+	}
+}
+
+func (ablInterpolator) transform_Image_Image_Over(dst Image, dr, adr image.Rectangle, d2s *f64.Aff3, src image.Image, sr image.Rectangle, bias image.Point, opts *Options) {
+	srcMask, smp := opts.SrcMask, opts.SrcMaskP
+	dstMask, dmp := opts.DstMask, opts.DstMaskP
+	dstColorRGBA64 := &color.RGBA64{}
+	dstColor := color.Color(dstColorRGBA64)
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
+				sx0, sx1 = sr.Min.X, sr.Min.X
+				xFrac0, xFrac1 = 0, 1
+			} else if sx1 >= sr.Max.X {
+				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
+				xFrac0, xFrac1 = 1, 0
+			}
+
+			sy -= 0.5
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00ru, s00gu, s00bu, s00au := src.At(sx0, sy0).RGBA()
+			if srcMask != nil {
+				_, _, _, ma := srcMask.At(smp.X+sx0, smp.Y+sy0).RGBA()
+				s00ru = s00ru * ma / 0xffff
+				s00gu = s00gu * ma / 0xffff
+				s00bu = s00bu * ma / 0xffff
+				s00au = s00au * ma / 0xffff
+			}
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10ru, s10gu, s10bu, s10au := src.At(sx1, sy0).RGBA()
+			if srcMask != nil {
+				_, _, _, ma := srcMask.At(smp.X+sx1, smp.Y+sy0).RGBA()
+				s10ru = s10ru * ma / 0xffff
+				s10gu = s10gu * ma / 0xffff
+				s10bu = s10bu * ma / 0xffff
+				s10au = s10au * ma / 0xffff
+			}
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01ru, s01gu, s01bu, s01au := src.At(sx0, sy1).RGBA()
+			if srcMask != nil {
+				_, _, _, ma := srcMask.At(smp.X+sx0, smp.Y+sy1).RGBA()
+				s01ru = s01ru * ma / 0xffff
+				s01gu = s01gu * ma / 0xffff
+				s01bu = s01bu * ma / 0xffff
+				s01au = s01au * ma / 0xffff
+			}
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11ru, s11gu, s11bu, s11au := src.At(sx1, sy1).RGBA()
+			if srcMask != nil {
+				_, _, _, ma := srcMask.At(smp.X+sx1, smp.Y+sy1).RGBA()
+				s11ru = s11ru * ma / 0xffff
+				s11gu = s11gu * ma / 0xffff
+				s11bu = s11bu * ma / 0xffff
+				s11au = s11au * ma / 0xffff
+			}
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			qr, qg, qb, qa := dst.At(dr.Min.X+int(dx), dr.Min.Y+int(dy)).RGBA()
+			if dstMask != nil {
+				_, _, _, ma := dstMask.At(dmp.X+dr.Min.X+int(dx), dmp.Y+dr.Min.Y+int(dy)).RGBA()
+				pr = pr * ma / 0xffff
+				pg = pg * ma / 0xffff
+				pb = pb * ma / 0xffff
+				pa = pa * ma / 0xffff
+			}
+			pa1 := 0xffff - pa
+			dstColorRGBA64.R = uint16(qr*pa1/0xffff + pr)
+			dstColorRGBA64.G = uint16(qg*pa1/0xffff + pg)
+			dstColorRGBA64.B = uint16(qb*pa1/0xffff + pb)
+			dstColorRGBA64.A = uint16(qa*pa1/0xffff + pa)
+			dst.Set(dr.Min.X+int(dx), dr.Min.Y+int(dy), dstColor)
+		}
+	}
+}
+
+func (ablInterpolator) transform_Image_Image_Src(dst Image, dr, adr image.Rectangle, d2s *f64.Aff3, src image.Image, sr image.Rectangle, bias image.Point, opts *Options) {
+	srcMask, smp := opts.SrcMask, opts.SrcMaskP
+	dstMask, dmp := opts.DstMask, opts.DstMaskP
+	dstColorRGBA64 := &color.RGBA64{}
+	dstColor := color.Color(dstColorRGBA64)
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			sx -= 0.5
+			sx0 := int(sx)
+			xFrac0 := sx - float64(sx0)
+			xFrac1 := 1 - xFrac0
+			sx0 += bias.X
+			sx1 := sx0 + 1
+			if sx0 < sr.Min.X {
 				sx0, sx1 = sr.Min.X, sr.Min.X
 				xFrac0, xFrac1 = 0, 1
 			} else if sx1 >= sr.Max.X {
@@ -3629,85 +16095,2687 @@ func (ablInterpolator) transform_RGBA_NRGBA_Over(dst *image.RGBA, dr, adr image.
 			}
 
 			sy -= 0.5
-			sy0 := int(sy)
-			yFrac0 := sy - float64(sy0)
-			yFrac1 := 1 - yFrac0
-			sy0 += bias.Y
-			sy1 := sy0 + 1
-			if sy0 < sr.Min.Y {
-				sy0, sy1 = sr.Min.Y, sr.Min.Y
-				yFrac0, yFrac1 = 0, 1
-			} else if sy1 >= sr.Max.Y {
-				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
-				yFrac0, yFrac1 = 1, 0
+			sy0 := int(sy)
+			yFrac0 := sy - float64(sy0)
+			yFrac1 := 1 - yFrac0
+			sy0 += bias.Y
+			sy1 := sy0 + 1
+			if sy0 < sr.Min.Y {
+				sy0, sy1 = sr.Min.Y, sr.Min.Y
+				yFrac0, yFrac1 = 0, 1
+			} else if sy1 >= sr.Max.Y {
+				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
+				yFrac0, yFrac1 = 1, 0
+			}
+
+			s00ru, s00gu, s00bu, s00au := src.At(sx0, sy0).RGBA()
+			if srcMask != nil {
+				_, _, _, ma := srcMask.At(smp.X+sx0, smp.Y+sy0).RGBA()
+				s00ru = s00ru * ma / 0xffff
+				s00gu = s00gu * ma / 0xffff
+				s00bu = s00bu * ma / 0xffff
+				s00au = s00au * ma / 0xffff
+			}
+			s00r := float64(s00ru)
+			s00g := float64(s00gu)
+			s00b := float64(s00bu)
+			s00a := float64(s00au)
+			s10ru, s10gu, s10bu, s10au := src.At(sx1, sy0).RGBA()
+			if srcMask != nil {
+				_, _, _, ma := srcMask.At(smp.X+sx1, smp.Y+sy0).RGBA()
+				s10ru = s10ru * ma / 0xffff
+				s10gu = s10gu * ma / 0xffff
+				s10bu = s10bu * ma / 0xffff
+				s10au = s10au * ma / 0xffff
+			}
+			s10r := float64(s10ru)
+			s10g := float64(s10gu)
+			s10b := float64(s10bu)
+			s10a := float64(s10au)
+			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
+			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
+			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
+			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
+			s01ru, s01gu, s01bu, s01au := src.At(sx0, sy1).RGBA()
+			if srcMask != nil {
+				_, _, _, ma := srcMask.At(smp.X+sx0, smp.Y+sy1).RGBA()
+				s01ru = s01ru * ma / 0xffff
+				s01gu = s01gu * ma / 0xffff
+				s01bu = s01bu * ma / 0xffff
+				s01au = s01au * ma / 0xffff
+			}
+			s01r := float64(s01ru)
+			s01g := float64(s01gu)
+			s01b := float64(s01bu)
+			s01a := float64(s01au)
+			s11ru, s11gu, s11bu, s11au := src.At(sx1, sy1).RGBA()
+			if srcMask != nil {
+				_, _, _, ma := srcMask.At(smp.X+sx1, smp.Y+sy1).RGBA()
+				s11ru = s11ru * ma / 0xffff
+				s11gu = s11gu * ma / 0xffff
+				s11bu = s11bu * ma / 0xffff
+				s11au = s11au * ma / 0xffff
+			}
+			s11r := float64(s11ru)
+			s11g := float64(s11gu)
+			s11b := float64(s11bu)
+			s11a := float64(s11au)
+			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
+			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
+			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
+			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
+			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
+			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
+			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
+			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
+			pr := uint32(s11r)
+			pg := uint32(s11g)
+			pb := uint32(s11b)
+			pa := uint32(s11a)
+			if dstMask != nil {
+				qr, qg, qb, qa := dst.At(dr.Min.X+int(dx), dr.Min.Y+int(dy)).RGBA()
+				_, _, _, ma := dstMask.At(dmp.X+dr.Min.X+int(dx), dmp.Y+dr.Min.Y+int(dy)).RGBA()
+				pr = pr * ma / 0xffff
+				pg = pg * ma / 0xffff
+				pb = pb * ma / 0xffff
+				pa = pa * ma / 0xffff
+				pa1 := 0xffff - ma
+				dstColorRGBA64.R = uint16(qr*pa1/0xffff + pr)
+				dstColorRGBA64.G = uint16(qg*pa1/0xffff + pg)
+				dstColorRGBA64.B = uint16(qb*pa1/0xffff + pb)
+				dstColorRGBA64.A = uint16(qa*pa1/0xffff + pa)
+				dst.Set(dr.Min.X+int(dx), dr.Min.Y+int(dy), dstColor)
+			} else {
+				dstColorRGBA64.R = uint16(pr)
+				dstColorRGBA64.G = uint16(pg)
+				dstColorRGBA64.B = uint16(pb)
+				dstColorRGBA64.A = uint16(pa)
+				dst.Set(dr.Min.X+int(dx), dr.Min.Y+int(dy), dstColor)
+			}
+		}
+	}
+}
+
+func (z *kernelScaler) Scale(dst Image, dr image.Rectangle, src image.Image, sr image.Rectangle, op Op, opts *Options) {
+	if z.dw != int32(dr.Dx()) || z.dh != int32(dr.Dy()) || z.sw != int32(sr.Dx()) || z.sh != int32(sr.Dy()) {
+		newKernelScaler(z.kx, z.ky, dr.Dx(), dr.Dy(), sr.Dx(), sr.Dy(), false).Scale(dst, dr, src, sr, op, opts)
+		return
+	}
+	src, sr = untile(src, sr, opts)
+
+	// Try to simplify a Scale to a Copy, as for the non-kernel
+	// interpolators, when each dst pixel is a copy of one src pixel.
+	// Copy has no fast path when DstMask is not nil.
+	if z.identity && (opts == nil || opts.DstMask == nil) {
+		Copy(dst, dr.Min, unalias(dst, dr, src, sr), sr, op, opts)
+		return
+	}
+
+	var o Options
+	if opts != nil {
+		o = *opts
+	}
+
+	// adr is the affected destination pixels.
+	adr := dst.Bounds().Intersect(dr)
+	adr, o.DstMask = clipAffectedDestRect(adr, o.DstMask, o.DstMaskP)
+	if adr.Empty() || sr.Empty() {
+		return
+	}
+	// Make adr relative to dr.Min.
+	adr = adr.Sub(dr.Min)
+	if op == Over && o.SrcMask == nil && opaque(src) {
+		op = Src
+	}
+
+	// Scaling a uniform source is a fill. The kernel's weights sum
+	// to one, so there is no need to accumulate them.
+	if _, ok := src.(*image.Uniform); ok && o.DstMask == nil && o.SrcMask == nil && sr.In(src.Bounds()) {
+		Draw(palettedDst(dst), adr.Add(dr.Min), src, src.Bounds().Min, op)
+		return
+	}
+
+	// Create a temporary buffer:
+	// scaleX distributes the source image's columns over the temporary image.
+	// scaleY distributes the temporary image's rows over the destination image.
+	var tmp [][4]float64
+	if z.pool.New != nil {
+		tmpp := z.pool.Get().(*[][4]float64)
+		defer z.pool.Put(tmpp)
+		tmp = *tmpp
+	} else {
+		tmp = z.makeTmpBuf()
+	}
+
+	// sr is the source pixels. If it extends beyond the src bounds,
+	// we cannot use the type-specific fast paths, as they access
+	// the Pix fields directly without bounds checking.
+	//
+	// Similarly, the fast paths assume that the masks are nil.
+	if o.SrcMask != nil || !sr.In(src.Bounds()) {
+		z.scaleX_Image(tmp, src, sr, &o)
+	} else {
+		switch src := src.(type) {
+		case *image.Alpha:
+			z.scaleX_Alpha(tmp, src, sr, &o)
+		case image.RGBA64Image:
+			z.scaleX_RGBA64Image(tmp, src, sr, &o)
+		default:
+			z.scaleX_Image(tmp, src, sr, &o)
+		case *image.Gray:
+			z.scaleX_Gray(tmp, src, sr, &o)
+		case *image.NRGBA:
+			z.scaleX_NRGBA(tmp, src, sr, &o)
+		case *image.RGBA:
+			z.scaleX_RGBA(tmp, src, sr, &o)
+		case *image.YCbCr:
+			switch src.SubsampleRatio {
+			default:
+				z.scaleX_Image(tmp, src, sr, &o)
+			case image.YCbCrSubsampleRatio444:
+				z.scaleX_YCbCr444(tmp, src, sr, &o)
+			case image.YCbCrSubsampleRatio422:
+				z.scaleX_YCbCr422(tmp, src, sr, &o)
+			case image.YCbCrSubsampleRatio420:
+				z.scaleX_YCbCr420(tmp, src, sr, &o)
+			case image.YCbCrSubsampleRatio440:
+				z.scaleX_YCbCr440(tmp, src, sr, &o)
+			}
+		case *image.NYCbCrA:
+			switch src.SubsampleRatio {
+			default:
+				z.scaleX_Image(tmp, src, sr, &o)
+			case image.YCbCrSubsampleRatio444:
+				z.scaleX_NYCbCrA444(tmp, src, sr, &o)
+			case image.YCbCrSubsampleRatio422:
+				z.scaleX_NYCbCrA422(tmp, src, sr, &o)
+			case image.YCbCrSubsampleRatio420:
+				z.scaleX_NYCbCrA420(tmp, src, sr, &o)
+			case image.YCbCrSubsampleRatio440:
+				z.scaleX_NYCbCrA440(tmp, src, sr, &o)
+			}
+		case *image.NRGBA64:
+			z.scaleX_NRGBA64(tmp, src, sr, &o)
+		case *image.RGBA64:
+			z.scaleX_RGBA64(tmp, src, sr, &o)
+		}
+	}
+
+	if o.DstMask != nil {
+		switch op {
+		case Over:
+			z.scaleY_Image_Over(dst, dr, adr, tmp, &o)
+		case Src:
+			z.scaleY_Image_Src(dst, dr, adr, tmp, &o)
+		}
+	} else {
+		dst = palettedDst(dst)
+		switch op {
+		case Over:
+			switch dst := dst.(type) {
+			case *image.Alpha:
+				z.scaleY_Alpha_Over(dst, dr, adr, tmp, &o)
+			case *image.Gray:
+				z.scaleY_Gray_Over(dst, dr, adr, tmp, &o)
+			case *image.RGBA:
+				z.scaleY_RGBA_Over(dst, dr, adr, tmp, &o)
+			case *image.RGBA64:
+				z.scaleY_RGBA64_Over(dst, dr, adr, tmp, &o)
+			case *image.NRGBA64:
+				z.scaleY_NRGBA64_Over(dst, dr, adr, tmp, &o)
+			case RGBA64Image:
+				z.scaleY_RGBA64Image_Over(dst, dr, adr, tmp, &o)
+			default:
+				z.scaleY_Image_Over(dst, dr, adr, tmp, &o)
+			}
+		case Src:
+			switch dst := dst.(type) {
+			case *image.Alpha:
+				z.scaleY_Alpha_Src(dst, dr, adr, tmp, &o)
+			case *image.Gray:
+				z.scaleY_Gray_Src(dst, dr, adr, tmp, &o)
+			case *image.RGBA:
+				z.scaleY_RGBA_Src(dst, dr, adr, tmp, &o)
+			case *image.RGBA64:
+				z.scaleY_RGBA64_Src(dst, dr, adr, tmp, &o)
+			case *image.NRGBA64:
+				z.scaleY_NRGBA64_Src(dst, dr, adr, tmp, &o)
+			case RGBA64Image:
+				z.scaleY_RGBA64Image_Src(dst, dr, adr, tmp, &o)
+			default:
+				z.scaleY_Image_Src(dst, dr, adr, tmp, &o)
+			}
+		}
+	}
+}
+
+func (q *Kernel) Transform(dst Image, s2d f64.Aff3, src image.Image, sr image.Rectangle, op Op, opts *Options) {
+	var o Options
+	if opts != nil {
+		o = *opts
+	}
+
+	dr := transformRect(&s2d, &sr)
+	// adr is the affected destination pixels.
+	adr := dst.Bounds().Intersect(dr)
+	adr, o.DstMask = clipAffectedDestRect(adr, o.DstMask, o.DstMaskP)
+	if adr.Empty() || sr.Empty() {
+		return
+	}
+	src = unalias(dst, adr, src, sr)
+	if op == Over && o.SrcMask == nil && opaque(src) {
+		op = Src
+	}
+	d2s := invert(&s2d)
+	// bias is a translation of the mapping from dst coordinates to src
+	// coordinates such that the latter temporarily have non-negative X
+	// and Y coordinates. This allows us to write int(f) instead of
+	// int(math.Floor(f)), since "round to zero" and "round down" are
+	// equivalent when f >= 0, but the former is much cheaper. The X--
+	// and Y-- are because the TransformLeaf methods have a "sx -= 0.5"
+	// adjustment.
+	bias := transformRect(&d2s, &adr).Min
+	bias.X--
+	bias.Y--
+	d2s[2] -= float64(bias.X)
+	d2s[5] -= float64(bias.Y)
+	// Make adr relative to dr.Min.
+	adr = adr.Sub(dr.Min)
+
+	// Likewise, transforming a uniform source fills those
+	// destination pixels that map to inside sr.
+	if u, ok := src.(*image.Uniform); ok && o.DstMask == nil && o.SrcMask == nil && sr.In(src.Bounds()) {
+		transform_Uniform(dst, dr, adr, &d2s, u, sr, bias, op)
+		return
+	}
+
+	xscale := abs(d2s[0])
+	if s := abs(d2s[1]); xscale < s {
+		xscale = s
+	}
+	yscale := abs(d2s[3])
+	if s := abs(d2s[4]); yscale < s {
+		yscale = s
+	}
+
+	// sr is the source pixels. If it extends beyond the src bounds,
+	// we cannot use the type-specific fast paths, as they access
+	// the Pix fields directly without bounds checking.
+	//
+	// Similarly, the fast paths assume that the masks are nil.
+	if o.DstMask != nil || o.SrcMask != nil || !sr.In(src.Bounds()) {
+		switch op {
+		case Over:
+			q.transform_Image_Image_Over(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+		case Src:
+			q.transform_Image_Image_Src(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+		}
+	} else {
+		dst = palettedDst(dst)
+		switch op {
+		case Over:
+			switch dst := dst.(type) {
+			case *image.Alpha:
+				switch src := src.(type) {
+				case *image.Alpha:
+					q.transform_Alpha_Alpha_Over(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+				case image.RGBA64Image:
+					q.transform_Alpha_RGBA64Image_Over(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+				default:
+					q.transform_Alpha_Image_Over(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+				}
+			case *image.Gray:
+				switch src := src.(type) {
+				case image.RGBA64Image:
+					q.transform_Gray_RGBA64Image_Over(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+				default:
+					q.transform_Gray_Image_Over(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+				}
+			case *image.RGBA:
+				switch src := src.(type) {
+				case *image.NRGBA:
+					q.transform_RGBA_NRGBA_Over(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+				case *image.RGBA:
+					q.transform_RGBA_RGBA_Over(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+				case *image.NYCbCrA:
+					switch src.SubsampleRatio {
+					default:
+						q.transform_RGBA_Image_Over(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+					case image.YCbCrSubsampleRatio444:
+						q.transform_RGBA_NYCbCrA444_Over(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+					case image.YCbCrSubsampleRatio422:
+						q.transform_RGBA_NYCbCrA422_Over(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+					case image.YCbCrSubsampleRatio420:
+						q.transform_RGBA_NYCbCrA420_Over(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+					case image.YCbCrSubsampleRatio440:
+						q.transform_RGBA_NYCbCrA440_Over(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+					}
+				case image.RGBA64Image:
+					q.transform_RGBA_RGBA64Image_Over(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+				default:
+					q.transform_RGBA_Image_Over(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+				}
+			case *image.RGBA64:
+				switch src := src.(type) {
+				case *image.NRGBA64:
+					q.transform_RGBA64_NRGBA64_Over(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+				case *image.RGBA64:
+					q.transform_RGBA64_RGBA64_Over(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+				case image.RGBA64Image:
+					q.transform_RGBA64_RGBA64Image_Over(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+				default:
+					q.transform_RGBA64_Image_Over(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+				}
+			case *image.NRGBA64:
+				switch src := src.(type) {
+				case *image.NRGBA64:
+					q.transform_NRGBA64_NRGBA64_Over(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+				case *image.RGBA64:
+					q.transform_NRGBA64_RGBA64_Over(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+				case image.RGBA64Image:
+					q.transform_NRGBA64_RGBA64Image_Over(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+				default:
+					q.transform_NRGBA64_Image_Over(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+				}
+			case RGBA64Image:
+				switch src := src.(type) {
+				case image.RGBA64Image:
+					q.transform_RGBA64Image_RGBA64Image_Over(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+				}
+			default:
+				switch src := src.(type) {
+				default:
+					q.transform_Image_Image_Over(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+				}
+			}
+		case Src:
+			switch dst := dst.(type) {
+			case *image.Alpha:
+				switch src := src.(type) {
+				case *image.Alpha:
+					q.transform_Alpha_Alpha_Src(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+				case image.RGBA64Image:
+					q.transform_Alpha_RGBA64Image_Src(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+				default:
+					q.transform_Alpha_Image_Src(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+				}
+			case *image.Gray:
+				switch src := src.(type) {
+				case *image.Gray:
+					q.transform_Gray_Gray_Src(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+				case image.RGBA64Image:
+					q.transform_Gray_RGBA64Image_Src(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+				default:
+					q.transform_Gray_Image_Src(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+				}
+			case *image.RGBA:
+				switch src := src.(type) {
+				case *image.Gray:
+					q.transform_RGBA_Gray_Src(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+				case *image.NRGBA:
+					q.transform_RGBA_NRGBA_Src(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+				case *image.RGBA:
+					q.transform_RGBA_RGBA_Src(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+				case *image.YCbCr:
+					switch src.SubsampleRatio {
+					default:
+						q.transform_RGBA_Image_Src(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+					case image.YCbCrSubsampleRatio444:
+						q.transform_RGBA_YCbCr444_Src(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+					case image.YCbCrSubsampleRatio422:
+						q.transform_RGBA_YCbCr422_Src(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+					case image.YCbCrSubsampleRatio420:
+						q.transform_RGBA_YCbCr420_Src(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+					case image.YCbCrSubsampleRatio440:
+						q.transform_RGBA_YCbCr440_Src(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+					}
+				case *image.NYCbCrA:
+					switch src.SubsampleRatio {
+					default:
+						q.transform_RGBA_Image_Src(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+					case image.YCbCrSubsampleRatio444:
+						q.transform_RGBA_NYCbCrA444_Src(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+					case image.YCbCrSubsampleRatio422:
+						q.transform_RGBA_NYCbCrA422_Src(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+					case image.YCbCrSubsampleRatio420:
+						q.transform_RGBA_NYCbCrA420_Src(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+					case image.YCbCrSubsampleRatio440:
+						q.transform_RGBA_NYCbCrA440_Src(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+					}
+				case image.RGBA64Image:
+					q.transform_RGBA_RGBA64Image_Src(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+				default:
+					q.transform_RGBA_Image_Src(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+				}
+			case *image.RGBA64:
+				switch src := src.(type) {
+				case *image.NRGBA64:
+					q.transform_RGBA64_NRGBA64_Src(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+				case *image.RGBA64:
+					q.transform_RGBA64_RGBA64_Src(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+				case image.RGBA64Image:
+					q.transform_RGBA64_RGBA64Image_Src(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+				default:
+					q.transform_RGBA64_Image_Src(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+				}
+			case *image.NRGBA64:
+				switch src := src.(type) {
+				case *image.NRGBA64:
+					q.transform_NRGBA64_NRGBA64_Src(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+				case *image.RGBA64:
+					q.transform_NRGBA64_RGBA64_Src(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+				case image.RGBA64Image:
+					q.transform_NRGBA64_RGBA64Image_Src(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+				default:
+					q.transform_NRGBA64_Image_Src(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+				}
+			case RGBA64Image:
+				switch src := src.(type) {
+				case image.RGBA64Image:
+					q.transform_RGBA64Image_RGBA64Image_Src(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+				}
+			default:
+				switch src := src.(type) {
+				default:
+					q.transform_Image_Image_Src(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+				}
+			}
+		}
+	}
+}
+
+func (z *kernelScaler) scaleX_Alpha(tmp [][4]float64, src *image.Alpha, sr image.Rectangle, opts *Options) {
+	t := 0
+	for y := int32(0); y < z.sh; y++ {
+		for _, s := range z.horizontal.sources {
+			var pa float64
+			for _, c := range z.horizontal.contribs[s.i:s.j] {
+				pi := (sr.Min.Y+int(y)-src.Rect.Min.Y)*src.Stride + (sr.Min.X + int(c.coord) - src.Rect.Min.X)
+				pau := uint32(src.Pix[pi+0]) * 0x101
+				pa += float64(float64(pau) * c.weight)
+			}
+			tmp[t] = [4]float64{
+				pa * s.invTotalWeightFFFF,
+				pa * s.invTotalWeightFFFF,
+				pa * s.invTotalWeightFFFF,
+				pa * s.invTotalWeightFFFF,
+			}
+			t++
+		}
+	}
+}
+
+func (z *kernelScaler) scaleX_RGBA64Image(tmp [][4]float64, src image.RGBA64Image, sr image.Rectangle, opts *Options) {
+	t := 0
+	srcMask, smp := opts.SrcMask, opts.SrcMaskP
+	for y := int32(0); y < z.sh; y++ {
+		for _, s := range z.horizontal.sources {
+			var pr, pg, pb, pa float64
+			for _, c := range z.horizontal.contribs[s.i:s.j] {
+				pu := src.RGBA64At(sr.Min.X+int(c.coord), sr.Min.Y+int(y))
+				if srcMask != nil {
+					_, _, _, ma := srcMask.At(smp.X+sr.Min.X+int(c.coord), smp.Y+sr.Min.Y+int(y)).RGBA()
+					pu.R = uint16(uint32(pu.R) * ma / 0xffff)
+					pu.G = uint16(uint32(pu.G) * ma / 0xffff)
+					pu.B = uint16(uint32(pu.B) * ma / 0xffff)
+					pu.A = uint16(uint32(pu.A) * ma / 0xffff)
+				}
+				pr += float64(float64(pu.R) * c.weight)
+				pg += float64(float64(pu.G) * c.weight)
+				pb += float64(float64(pu.B) * c.weight)
+				pa += float64(float64(pu.A) * c.weight)
+			}
+			tmp[t] = [4]float64{
+				pr * s.invTotalWeightFFFF,
+				pg * s.invTotalWeightFFFF,
+				pb * s.invTotalWeightFFFF,
+				pa * s.invTotalWeightFFFF,
+			}
+			t++
+		}
+	}
+}
+
+func (z *kernelScaler) scaleX_Image(tmp [][4]float64, src image.Image, sr image.Rectangle, opts *Options) {
+	t := 0
+	srcMask, smp := opts.SrcMask, opts.SrcMaskP
+	for y := int32(0); y < z.sh; y++ {
+		for _, s := range z.horizontal.sources {
+			var pr, pg, pb, pa float64
+			for _, c := range z.horizontal.contribs[s.i:s.j] {
+				pru, pgu, pbu, pau := src.At(sr.Min.X+int(c.coord), sr.Min.Y+int(y)).RGBA()
+				if srcMask != nil {
+					_, _, _, ma := srcMask.At(smp.X+sr.Min.X+int(c.coord), smp.Y+sr.Min.Y+int(y)).RGBA()
+					pru = pru * ma / 0xffff
+					pgu = pgu * ma / 0xffff
+					pbu = pbu * ma / 0xffff
+					pau = pau * ma / 0xffff
+				}
+				pr += float64(float64(pru) * c.weight)
+				pg += float64(float64(pgu) * c.weight)
+				pb += float64(float64(pbu) * c.weight)
+				pa += float64(float64(pau) * c.weight)
+			}
+			tmp[t] = [4]float64{
+				pr * s.invTotalWeightFFFF,
+				pg * s.invTotalWeightFFFF,
+				pb * s.invTotalWeightFFFF,
+				pa * s.invTotalWeightFFFF,
+			}
+			t++
+		}
+	}
+}
+
+func (z *kernelScaler) scaleX_Gray(tmp [][4]float64, src *image.Gray, sr image.Rectangle, opts *Options) {
+	t := 0
+	for y := int32(0); y < z.sh; y++ {
+		for _, s := range z.horizontal.sources {
+			var pr float64
+			for _, c := range z.horizontal.contribs[s.i:s.j] {
+				pi := (sr.Min.Y+int(y)-src.Rect.Min.Y)*src.Stride + (sr.Min.X + int(c.coord) - src.Rect.Min.X)
+				pru := uint32(src.Pix[pi]) * 0x101
+				pr += float64(float64(pru) * c.weight)
+			}
+			pr *= s.invTotalWeightFFFF
+			tmp[t] = [4]float64{
+				pr,
+				pr,
+				pr,
+				1,
+			}
+			t++
+		}
+	}
+}
+
+func (z *kernelScaler) scaleX_NRGBA(tmp [][4]float64, src *image.NRGBA, sr image.Rectangle, opts *Options) {
+	t := 0
+	for y := int32(0); y < z.sh; y++ {
+		for _, s := range z.horizontal.sources {
+			var pr, pg, pb, pa float64
+			for _, c := range z.horizontal.contribs[s.i:s.j] {
+				pi := (sr.Min.Y+int(y)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(c.coord)-src.Rect.Min.X)*4
+				pau := uint32(src.Pix[pi+3]) * 0x101
+				pru := uint32(src.Pix[pi+0]) * pau / 0xff
+				pgu := uint32(src.Pix[pi+1]) * pau / 0xff
+				pbu := uint32(src.Pix[pi+2]) * pau / 0xff
+				pr += float64(float64(pru) * c.weight)
+				pg += float64(float64(pgu) * c.weight)
+				pb += float64(float64(pbu) * c.weight)
+				pa += float64(float64(pau) * c.weight)
+			}
+			tmp[t] = [4]float64{
+				pr * s.invTotalWeightFFFF,
+				pg * s.invTotalWeightFFFF,
+				pb * s.invTotalWeightFFFF,
+				pa * s.invTotalWeightFFFF,
+			}
+			t++
+		}
+	}
+}
+
+func (z *kernelScaler) scaleX_RGBA(tmp [][4]float64, src *image.RGBA, sr image.Rectangle, opts *Options) {
+	t := 0
+	for y := int32(0); y < z.sh; y++ {
+		for _, s := range z.horizontal.sources {
+			var pr, pg, pb, pa float64
+			for _, c := range z.horizontal.contribs[s.i:s.j] {
+				pi := (sr.Min.Y+int(y)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(c.coord)-src.Rect.Min.X)*4
+				pru := uint32(src.Pix[pi+0]) * 0x101
+				pgu := uint32(src.Pix[pi+1]) * 0x101
+				pbu := uint32(src.Pix[pi+2]) * 0x101
+				pau := uint32(src.Pix[pi+3]) * 0x101
+				pr += float64(float64(pru) * c.weight)
+				pg += float64(float64(pgu) * c.weight)
+				pb += float64(float64(pbu) * c.weight)
+				pa += float64(float64(pau) * c.weight)
+			}
+			tmp[t] = [4]float64{
+				pr * s.invTotalWeightFFFF,
+				pg * s.invTotalWeightFFFF,
+				pb * s.invTotalWeightFFFF,
+				pa * s.invTotalWeightFFFF,
+			}
+			t++
+		}
+	}
+}
+
+func (z *kernelScaler) scaleX_YCbCr444(tmp [][4]float64, src *image.YCbCr, sr image.Rectangle, opts *Options) {
+	t := 0
+	for y := int32(0); y < z.sh; y++ {
+		for _, s := range z.horizontal.sources {
+			var pr, pg, pb float64
+			for _, c := range z.horizontal.contribs[s.i:s.j] {
+				pi := (sr.Min.Y+int(y)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(c.coord) - src.Rect.Min.X)
+				pj := (sr.Min.Y+int(y)-src.Rect.Min.Y)*src.CStride + (sr.Min.X + int(c.coord) - src.Rect.Min.X)
+
+				// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+				pyy1 := int(src.Y[pi]) * 0x10101
+				pcb1 := int(src.Cb[pj]) - 128
+				pcr1 := int(src.Cr[pj]) - 128
+				pru := (pyy1 + 91881*pcr1) >> 8
+				pgu := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
+				pbu := (pyy1 + 116130*pcb1) >> 8
+				if pru < 0 {
+					pru = 0
+				} else if pru > 0xffff {
+					pru = 0xffff
+				}
+				if pgu < 0 {
+					pgu = 0
+				} else if pgu > 0xffff {
+					pgu = 0xffff
+				}
+				if pbu < 0 {
+					pbu = 0
+				} else if pbu > 0xffff {
+					pbu = 0xffff
+				}
+
+				pr += float64(float64(pru) * c.weight)
+				pg += float64(float64(pgu) * c.weight)
+				pb += float64(float64(pbu) * c.weight)
+			}
+			tmp[t] = [4]float64{
+				pr * s.invTotalWeightFFFF,
+				pg * s.invTotalWeightFFFF,
+				pb * s.invTotalWeightFFFF,
+				1,
+			}
+			t++
+		}
+	}
+}
+
+func (z *kernelScaler) scaleX_YCbCr422(tmp [][4]float64, src *image.YCbCr, sr image.Rectangle, opts *Options) {
+	t := 0
+	for y := int32(0); y < z.sh; y++ {
+		for _, s := range z.horizontal.sources {
+			var pr, pg, pb float64
+			for _, c := range z.horizontal.contribs[s.i:s.j] {
+				pi := (sr.Min.Y+int(y)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(c.coord) - src.Rect.Min.X)
+				pj := (sr.Min.Y+int(y)-src.Rect.Min.Y)*src.CStride + ((sr.Min.X+int(c.coord))/2 - src.Rect.Min.X/2)
+
+				// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+				pyy1 := int(src.Y[pi]) * 0x10101
+				pcb1 := int(src.Cb[pj]) - 128
+				pcr1 := int(src.Cr[pj]) - 128
+				pru := (pyy1 + 91881*pcr1) >> 8
+				pgu := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
+				pbu := (pyy1 + 116130*pcb1) >> 8
+				if pru < 0 {
+					pru = 0
+				} else if pru > 0xffff {
+					pru = 0xffff
+				}
+				if pgu < 0 {
+					pgu = 0
+				} else if pgu > 0xffff {
+					pgu = 0xffff
+				}
+				if pbu < 0 {
+					pbu = 0
+				} else if pbu > 0xffff {
+					pbu = 0xffff
+				}
+
+				pr += float64(float64(pru) * c.weight)
+				pg += float64(float64(pgu) * c.weight)
+				pb += float64(float64(pbu) * c.weight)
+			}
+			tmp[t] = [4]float64{
+				pr * s.invTotalWeightFFFF,
+				pg * s.invTotalWeightFFFF,
+				pb * s.invTotalWeightFFFF,
+				1,
+			}
+			t++
+		}
+	}
+}
+
+func (z *kernelScaler) scaleX_YCbCr420(tmp [][4]float64, src *image.YCbCr, sr image.Rectangle, opts *Options) {
+	t := 0
+	for y := int32(0); y < z.sh; y++ {
+		for _, s := range z.horizontal.sources {
+			var pr, pg, pb float64
+			for _, c := range z.horizontal.contribs[s.i:s.j] {
+				pi := (sr.Min.Y+int(y)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(c.coord) - src.Rect.Min.X)
+				pj := ((sr.Min.Y+int(y))/2-src.Rect.Min.Y/2)*src.CStride + ((sr.Min.X+int(c.coord))/2 - src.Rect.Min.X/2)
+
+				// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+				pyy1 := int(src.Y[pi]) * 0x10101
+				pcb1 := int(src.Cb[pj]) - 128
+				pcr1 := int(src.Cr[pj]) - 128
+				pru := (pyy1 + 91881*pcr1) >> 8
+				pgu := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
+				pbu := (pyy1 + 116130*pcb1) >> 8
+				if pru < 0 {
+					pru = 0
+				} else if pru > 0xffff {
+					pru = 0xffff
+				}
+				if pgu < 0 {
+					pgu = 0
+				} else if pgu > 0xffff {
+					pgu = 0xffff
+				}
+				if pbu < 0 {
+					pbu = 0
+				} else if pbu > 0xffff {
+					pbu = 0xffff
+				}
+
+				pr += float64(float64(pru) * c.weight)
+				pg += float64(float64(pgu) * c.weight)
+				pb += float64(float64(pbu) * c.weight)
+			}
+			tmp[t] = [4]float64{
+				pr * s.invTotalWeightFFFF,
+				pg * s.invTotalWeightFFFF,
+				pb * s.invTotalWeightFFFF,
+				1,
+			}
+			t++
+		}
+	}
+}
+
+func (z *kernelScaler) scaleX_YCbCr440(tmp [][4]float64, src *image.YCbCr, sr image.Rectangle, opts *Options) {
+	t := 0
+	for y := int32(0); y < z.sh; y++ {
+		for _, s := range z.horizontal.sources {
+			var pr, pg, pb float64
+			for _, c := range z.horizontal.contribs[s.i:s.j] {
+				pi := (sr.Min.Y+int(y)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(c.coord) - src.Rect.Min.X)
+				pj := ((sr.Min.Y+int(y))/2-src.Rect.Min.Y/2)*src.CStride + (sr.Min.X + int(c.coord) - src.Rect.Min.X)
+
+				// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+				pyy1 := int(src.Y[pi]) * 0x10101
+				pcb1 := int(src.Cb[pj]) - 128
+				pcr1 := int(src.Cr[pj]) - 128
+				pru := (pyy1 + 91881*pcr1) >> 8
+				pgu := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
+				pbu := (pyy1 + 116130*pcb1) >> 8
+				if pru < 0 {
+					pru = 0
+				} else if pru > 0xffff {
+					pru = 0xffff
+				}
+				if pgu < 0 {
+					pgu = 0
+				} else if pgu > 0xffff {
+					pgu = 0xffff
+				}
+				if pbu < 0 {
+					pbu = 0
+				} else if pbu > 0xffff {
+					pbu = 0xffff
+				}
+
+				pr += float64(float64(pru) * c.weight)
+				pg += float64(float64(pgu) * c.weight)
+				pb += float64(float64(pbu) * c.weight)
+			}
+			tmp[t] = [4]float64{
+				pr * s.invTotalWeightFFFF,
+				pg * s.invTotalWeightFFFF,
+				pb * s.invTotalWeightFFFF,
+				1,
+			}
+			t++
+		}
+	}
+}
+
+func (z *kernelScaler) scaleX_NYCbCrA444(tmp [][4]float64, src *image.NYCbCrA, sr image.Rectangle, opts *Options) {
+	t := 0
+	for y := int32(0); y < z.sh; y++ {
+		for _, s := range z.horizontal.sources {
+			var pr, pg, pb, pa float64
+			for _, c := range z.horizontal.contribs[s.i:s.j] {
+				pi := (sr.Min.Y+int(y)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(c.coord) - src.Rect.Min.X)
+				pj := (sr.Min.Y+int(y)-src.Rect.Min.Y)*src.CStride + (sr.Min.X + int(c.coord) - src.Rect.Min.X)
+				pk := (sr.Min.Y+int(y)-src.Rect.Min.Y)*src.AStride + (sr.Min.X + int(c.coord) - src.Rect.Min.X)
+
+				// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+				pyy1 := int(src.Y[pi]) * 0x10101
+				pcb1 := int(src.Cb[pj]) - 128
+				pcr1 := int(src.Cr[pj]) - 128
+				pru0 := (pyy1 + 91881*pcr1) >> 8
+				pgu0 := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
+				pbu0 := (pyy1 + 116130*pcb1) >> 8
+				if pru0 < 0 {
+					pru0 = 0
+				} else if pru0 > 0xffff {
+					pru0 = 0xffff
+				}
+				if pgu0 < 0 {
+					pgu0 = 0
+				} else if pgu0 > 0xffff {
+					pgu0 = 0xffff
+				}
+				if pbu0 < 0 {
+					pbu0 = 0
+				} else if pbu0 > 0xffff {
+					pbu0 = 0xffff
+				}
+
+				pau := uint32(src.A[pk]) * 0x101
+				pru := uint32(pru0) * pau / 0xffff
+				pgu := uint32(pgu0) * pau / 0xffff
+				pbu := uint32(pbu0) * pau / 0xffff
+				pr += float64(float64(pru) * c.weight)
+				pg += float64(float64(pgu) * c.weight)
+				pb += float64(float64(pbu) * c.weight)
+				pa += float64(float64(pau) * c.weight)
+			}
+			tmp[t] = [4]float64{
+				pr * s.invTotalWeightFFFF,
+				pg * s.invTotalWeightFFFF,
+				pb * s.invTotalWeightFFFF,
+				pa * s.invTotalWeightFFFF,
+			}
+			t++
+		}
+	}
+}
+
+func (z *kernelScaler) scaleX_NYCbCrA422(tmp [][4]float64, src *image.NYCbCrA, sr image.Rectangle, opts *Options) {
+	t := 0
+	for y := int32(0); y < z.sh; y++ {
+		for _, s := range z.horizontal.sources {
+			var pr, pg, pb, pa float64
+			for _, c := range z.horizontal.contribs[s.i:s.j] {
+				pi := (sr.Min.Y+int(y)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(c.coord) - src.Rect.Min.X)
+				pj := (sr.Min.Y+int(y)-src.Rect.Min.Y)*src.CStride + ((sr.Min.X+int(c.coord))/2 - src.Rect.Min.X/2)
+				pk := (sr.Min.Y+int(y)-src.Rect.Min.Y)*src.AStride + (sr.Min.X + int(c.coord) - src.Rect.Min.X)
+
+				// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+				pyy1 := int(src.Y[pi]) * 0x10101
+				pcb1 := int(src.Cb[pj]) - 128
+				pcr1 := int(src.Cr[pj]) - 128
+				pru0 := (pyy1 + 91881*pcr1) >> 8
+				pgu0 := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
+				pbu0 := (pyy1 + 116130*pcb1) >> 8
+				if pru0 < 0 {
+					pru0 = 0
+				} else if pru0 > 0xffff {
+					pru0 = 0xffff
+				}
+				if pgu0 < 0 {
+					pgu0 = 0
+				} else if pgu0 > 0xffff {
+					pgu0 = 0xffff
+				}
+				if pbu0 < 0 {
+					pbu0 = 0
+				} else if pbu0 > 0xffff {
+					pbu0 = 0xffff
+				}
+
+				pau := uint32(src.A[pk]) * 0x101
+				pru := uint32(pru0) * pau / 0xffff
+				pgu := uint32(pgu0) * pau / 0xffff
+				pbu := uint32(pbu0) * pau / 0xffff
+				pr += float64(float64(pru) * c.weight)
+				pg += float64(float64(pgu) * c.weight)
+				pb += float64(float64(pbu) * c.weight)
+				pa += float64(float64(pau) * c.weight)
+			}
+			tmp[t] = [4]float64{
+				pr * s.invTotalWeightFFFF,
+				pg * s.invTotalWeightFFFF,
+				pb * s.invTotalWeightFFFF,
+				pa * s.invTotalWeightFFFF,
+			}
+			t++
+		}
+	}
+}
+
+func (z *kernelScaler) scaleX_NYCbCrA420(tmp [][4]float64, src *image.NYCbCrA, sr image.Rectangle, opts *Options) {
+	t := 0
+	for y := int32(0); y < z.sh; y++ {
+		for _, s := range z.horizontal.sources {
+			var pr, pg, pb, pa float64
+			for _, c := range z.horizontal.contribs[s.i:s.j] {
+				pi := (sr.Min.Y+int(y)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(c.coord) - src.Rect.Min.X)
+				pj := ((sr.Min.Y+int(y))/2-src.Rect.Min.Y/2)*src.CStride + ((sr.Min.X+int(c.coord))/2 - src.Rect.Min.X/2)
+				pk := (sr.Min.Y+int(y)-src.Rect.Min.Y)*src.AStride + (sr.Min.X + int(c.coord) - src.Rect.Min.X)
+
+				// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+				pyy1 := int(src.Y[pi]) * 0x10101
+				pcb1 := int(src.Cb[pj]) - 128
+				pcr1 := int(src.Cr[pj]) - 128
+				pru0 := (pyy1 + 91881*pcr1) >> 8
+				pgu0 := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
+				pbu0 := (pyy1 + 116130*pcb1) >> 8
+				if pru0 < 0 {
+					pru0 = 0
+				} else if pru0 > 0xffff {
+					pru0 = 0xffff
+				}
+				if pgu0 < 0 {
+					pgu0 = 0
+				} else if pgu0 > 0xffff {
+					pgu0 = 0xffff
+				}
+				if pbu0 < 0 {
+					pbu0 = 0
+				} else if pbu0 > 0xffff {
+					pbu0 = 0xffff
+				}
+
+				pau := uint32(src.A[pk]) * 0x101
+				pru := uint32(pru0) * pau / 0xffff
+				pgu := uint32(pgu0) * pau / 0xffff
+				pbu := uint32(pbu0) * pau / 0xffff
+				pr += float64(float64(pru) * c.weight)
+				pg += float64(float64(pgu) * c.weight)
+				pb += float64(float64(pbu) * c.weight)
+				pa += float64(float64(pau) * c.weight)
+			}
+			tmp[t] = [4]float64{
+				pr * s.invTotalWeightFFFF,
+				pg * s.invTotalWeightFFFF,
+				pb * s.invTotalWeightFFFF,
+				pa * s.invTotalWeightFFFF,
+			}
+			t++
+		}
+	}
+}
+
+func (z *kernelScaler) scaleX_NYCbCrA440(tmp [][4]float64, src *image.NYCbCrA, sr image.Rectangle, opts *Options) {
+	t := 0
+	for y := int32(0); y < z.sh; y++ {
+		for _, s := range z.horizontal.sources {
+			var pr, pg, pb, pa float64
+			for _, c := range z.horizontal.contribs[s.i:s.j] {
+				pi := (sr.Min.Y+int(y)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(c.coord) - src.Rect.Min.X)
+				pj := ((sr.Min.Y+int(y))/2-src.Rect.Min.Y/2)*src.CStride + (sr.Min.X + int(c.coord) - src.Rect.Min.X)
+				pk := (sr.Min.Y+int(y)-src.Rect.Min.Y)*src.AStride + (sr.Min.X + int(c.coord) - src.Rect.Min.X)
+
+				// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+				pyy1 := int(src.Y[pi]) * 0x10101
+				pcb1 := int(src.Cb[pj]) - 128
+				pcr1 := int(src.Cr[pj]) - 128
+				pru0 := (pyy1 + 91881*pcr1) >> 8
+				pgu0 := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
+				pbu0 := (pyy1 + 116130*pcb1) >> 8
+				if pru0 < 0 {
+					pru0 = 0
+				} else if pru0 > 0xffff {
+					pru0 = 0xffff
+				}
+				if pgu0 < 0 {
+					pgu0 = 0
+				} else if pgu0 > 0xffff {
+					pgu0 = 0xffff
+				}
+				if pbu0 < 0 {
+					pbu0 = 0
+				} else if pbu0 > 0xffff {
+					pbu0 = 0xffff
+				}
+
+				pau := uint32(src.A[pk]) * 0x101
+				pru := uint32(pru0) * pau / 0xffff
+				pgu := uint32(pgu0) * pau / 0xffff
+				pbu := uint32(pbu0) * pau / 0xffff
+				pr += float64(float64(pru) * c.weight)
+				pg += float64(float64(pgu) * c.weight)
+				pb += float64(float64(pbu) * c.weight)
+				pa += float64(float64(pau) * c.weight)
+			}
+			tmp[t] = [4]float64{
+				pr * s.invTotalWeightFFFF,
+				pg * s.invTotalWeightFFFF,
+				pb * s.invTotalWeightFFFF,
+				pa * s.invTotalWeightFFFF,
+			}
+			t++
+		}
+	}
+}
+
+func (z *kernelScaler) scaleX_NRGBA64(tmp [][4]float64, src *image.NRGBA64, sr image.Rectangle, opts *Options) {
+	t := 0
+	for y := int32(0); y < z.sh; y++ {
+		for _, s := range z.horizontal.sources {
+			var pr, pg, pb, pa float64
+			for _, c := range z.horizontal.contribs[s.i:s.j] {
+				pi := (sr.Min.Y+int(y)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(c.coord)-src.Rect.Min.X)*8
+				pau := (uint32(src.Pix[pi+6])<<8 | uint32(src.Pix[pi+7]))
+				pru := (uint32(src.Pix[pi+0])<<8 | uint32(src.Pix[pi+1])) * pau / 0xffff
+				pgu := (uint32(src.Pix[pi+2])<<8 | uint32(src.Pix[pi+3])) * pau / 0xffff
+				pbu := (uint32(src.Pix[pi+4])<<8 | uint32(src.Pix[pi+5])) * pau / 0xffff
+				pr += float64(float64(pru) * c.weight)
+				pg += float64(float64(pgu) * c.weight)
+				pb += float64(float64(pbu) * c.weight)
+				pa += float64(float64(pau) * c.weight)
+			}
+			tmp[t] = [4]float64{
+				pr * s.invTotalWeightFFFF,
+				pg * s.invTotalWeightFFFF,
+				pb * s.invTotalWeightFFFF,
+				pa * s.invTotalWeightFFFF,
+			}
+			t++
+		}
+	}
+}
+
+func (z *kernelScaler) scaleX_RGBA64(tmp [][4]float64, src *image.RGBA64, sr image.Rectangle, opts *Options) {
+	t := 0
+	for y := int32(0); y < z.sh; y++ {
+		for _, s := range z.horizontal.sources {
+			var pr, pg, pb, pa float64
+			for _, c := range z.horizontal.contribs[s.i:s.j] {
+				pi := (sr.Min.Y+int(y)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(c.coord)-src.Rect.Min.X)*8
+				pru := (uint32(src.Pix[pi+0])<<8 | uint32(src.Pix[pi+1]))
+				pgu := (uint32(src.Pix[pi+2])<<8 | uint32(src.Pix[pi+3]))
+				pbu := (uint32(src.Pix[pi+4])<<8 | uint32(src.Pix[pi+5]))
+				pau := (uint32(src.Pix[pi+6])<<8 | uint32(src.Pix[pi+7]))
+				pr += float64(float64(pru) * c.weight)
+				pg += float64(float64(pgu) * c.weight)
+				pb += float64(float64(pbu) * c.weight)
+				pa += float64(float64(pau) * c.weight)
+			}
+			tmp[t] = [4]float64{
+				pr * s.invTotalWeightFFFF,
+				pg * s.invTotalWeightFFFF,
+				pb * s.invTotalWeightFFFF,
+				pa * s.invTotalWeightFFFF,
+			}
+			t++
+		}
+	}
+}
+
+func (z *kernelScaler) scaleY_Alpha_Over(dst *image.Alpha, dr, adr image.Rectangle, tmp [][4]float64, opts *Options) {
+	for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
+		d := (dr.Min.Y+adr.Min.Y-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + int(dx) - dst.Rect.Min.X)
+		for _, s := range z.vertical.sources[adr.Min.Y:adr.Max.Y] {
+			var pa float64
+			for _, c := range z.vertical.contribs[s.i:s.j] {
+				p := &tmp[c.coord*z.dw+dx]
+				pa += float64(p[3] * c.weight)
+			}
+			pa0 := uint32(ftou(pa * s.invTotalWeight))
+			pa1 := (0xffff - uint32(pa0)) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pa0) >> 8)
+			d += dst.Stride
+		}
+	}
+}
+
+func (z *kernelScaler) scaleY_Alpha_Src(dst *image.Alpha, dr, adr image.Rectangle, tmp [][4]float64, opts *Options) {
+	for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
+		d := (dr.Min.Y+adr.Min.Y-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + int(dx) - dst.Rect.Min.X)
+		for _, s := range z.vertical.sources[adr.Min.Y:adr.Max.Y] {
+			var pa float64
+			for _, c := range z.vertical.contribs[s.i:s.j] {
+				p := &tmp[c.coord*z.dw+dx]
+				pa += float64(p[3] * c.weight)
+			}
+			dst.Pix[d+0] = uint8(ftou(pa*s.invTotalWeight) >> 8)
+			d += dst.Stride
+		}
+	}
+}
+
+func (z *kernelScaler) scaleY_Gray_Over(dst *image.Gray, dr, adr image.Rectangle, tmp [][4]float64, opts *Options) {
+	for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
+		d := (dr.Min.Y+adr.Min.Y-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + int(dx) - dst.Rect.Min.X)
+		for _, s := range z.vertical.sources[adr.Min.Y:adr.Max.Y] {
+			var pr, pg, pb, pa float64
+			for _, c := range z.vertical.contribs[s.i:s.j] {
+				p := &tmp[c.coord*z.dw+dx]
+				pr += float64(p[0] * c.weight)
+				pg += float64(p[1] * c.weight)
+				pb += float64(p[2] * c.weight)
+				pa += float64(p[3] * c.weight)
+			}
+
+			if pr > pa {
+				pr = pa
+			}
+			if pg > pa {
+				pg = pa
+			}
+			if pb > pa {
+				pb = pa
+			}
+
+			pr0 := uint32(ftou(pr * s.invTotalWeight))
+			pg0 := uint32(ftou(pg * s.invTotalWeight))
+			pb0 := uint32(ftou(pb * s.invTotalWeight))
+			pa0 := uint32(ftou(pa * s.invTotalWeight))
+			pq := uint32(dst.Pix[d]) * 0x101 * (0xffff - pa0) / 0xffff
+			dst.Pix[d] = uint8((19595*(pq+pr0) + 38470*(pq+pg0) + 7471*(pq+pb0) + 1<<15) >> 24)
+			d += dst.Stride
+		}
+	}
+}
+
+func (z *kernelScaler) scaleY_Gray_Src(dst *image.Gray, dr, adr image.Rectangle, tmp [][4]float64, opts *Options) {
+	for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
+		d := (dr.Min.Y+adr.Min.Y-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + int(dx) - dst.Rect.Min.X)
+		for _, s := range z.vertical.sources[adr.Min.Y:adr.Max.Y] {
+			var pr, pg, pb, pa float64
+			for _, c := range z.vertical.contribs[s.i:s.j] {
+				p := &tmp[c.coord*z.dw+dx]
+				pr += float64(p[0] * c.weight)
+				pg += float64(p[1] * c.weight)
+				pb += float64(p[2] * c.weight)
+				pa += float64(p[3] * c.weight)
+			}
+
+			if pr > pa {
+				pr = pa
+			}
+			if pg > pa {
+				pg = pa
+			}
+			if pb > pa {
+				pb = pa
+			}
+
+			pr0 := uint32(ftou(pr * s.invTotalWeight))
+			pg0 := uint32(ftou(pg * s.invTotalWeight))
+			pb0 := uint32(ftou(pb * s.invTotalWeight))
+			dst.Pix[d] = uint8((19595*pr0 + 38470*pg0 + 7471*pb0 + 1<<15) >> 24)
+			d += dst.Stride
+		}
+	}
+}
+
+func (z *kernelScaler) scaleY_RGBA_Over(dst *image.RGBA, dr, adr image.Rectangle, tmp [][4]float64, opts *Options) {
+	for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
+		d := (dr.Min.Y+adr.Min.Y-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+int(dx)-dst.Rect.Min.X)*4
+		for _, s := range z.vertical.sources[adr.Min.Y:adr.Max.Y] {
+			var pr, pg, pb, pa float64
+			for _, c := range z.vertical.contribs[s.i:s.j] {
+				p := &tmp[c.coord*z.dw+dx]
+				pr += float64(p[0] * c.weight)
+				pg += float64(p[1] * c.weight)
+				pb += float64(p[2] * c.weight)
+				pa += float64(p[3] * c.weight)
+			}
+
+			if pr > pa {
+				pr = pa
+			}
+			if pg > pa {
+				pg = pa
+			}
+			if pb > pa {
+				pb = pa
+			}
+
+			pr0 := uint32(ftou(pr * s.invTotalWeight))
+			pg0 := uint32(ftou(pg * s.invTotalWeight))
+			pb0 := uint32(ftou(pb * s.invTotalWeight))
+			pa0 := uint32(ftou(pa * s.invTotalWeight))
+			pa1 := (0xffff - uint32(pa0)) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pr0) >> 8)
+			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + pg0) >> 8)
+			dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*pa1/0xffff + pb0) >> 8)
+			dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*pa1/0xffff + pa0) >> 8)
+			d += dst.Stride
+		}
+	}
+}
+
+func (z *kernelScaler) scaleY_RGBA_Src(dst *image.RGBA, dr, adr image.Rectangle, tmp [][4]float64, opts *Options) {
+	for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
+		d := (dr.Min.Y+adr.Min.Y-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+int(dx)-dst.Rect.Min.X)*4
+		for _, s := range z.vertical.sources[adr.Min.Y:adr.Max.Y] {
+			var pr, pg, pb, pa float64
+			for _, c := range z.vertical.contribs[s.i:s.j] {
+				p := &tmp[c.coord*z.dw+dx]
+				pr += float64(p[0] * c.weight)
+				pg += float64(p[1] * c.weight)
+				pb += float64(p[2] * c.weight)
+				pa += float64(p[3] * c.weight)
+			}
+
+			if pr > pa {
+				pr = pa
+			}
+			if pg > pa {
+				pg = pa
+			}
+			if pb > pa {
+				pb = pa
+			}
+
+			dst.Pix[d+0] = uint8(ftou(pr*s.invTotalWeight) >> 8)
+			dst.Pix[d+1] = uint8(ftou(pg*s.invTotalWeight) >> 8)
+			dst.Pix[d+2] = uint8(ftou(pb*s.invTotalWeight) >> 8)
+			dst.Pix[d+3] = uint8(ftou(pa*s.invTotalWeight) >> 8)
+			d += dst.Stride
+		}
+	}
+}
+
+func (z *kernelScaler) scaleY_RGBA64_Over(dst *image.RGBA64, dr, adr image.Rectangle, tmp [][4]float64, opts *Options) {
+	for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
+		d := (dr.Min.Y+adr.Min.Y-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+int(dx)-dst.Rect.Min.X)*8
+		for _, s := range z.vertical.sources[adr.Min.Y:adr.Max.Y] {
+			var pr, pg, pb, pa float64
+			for _, c := range z.vertical.contribs[s.i:s.j] {
+				p := &tmp[c.coord*z.dw+dx]
+				pr += float64(p[0] * c.weight)
+				pg += float64(p[1] * c.weight)
+				pb += float64(p[2] * c.weight)
+				pa += float64(p[3] * c.weight)
+			}
+
+			if pr > pa {
+				pr = pa
+			}
+			if pg > pa {
+				pg = pa
+			}
+			if pb > pa {
+				pb = pa
+			}
+
+			pa1 := 0xffff - uint32(ftou(pa*s.invTotalWeight))
+			pr0 := (uint32(dst.Pix[d+0])<<8|uint32(dst.Pix[d+1]))*pa1/0xffff + uint32(ftou(pr*s.invTotalWeight))
+			pg0 := (uint32(dst.Pix[d+2])<<8|uint32(dst.Pix[d+3]))*pa1/0xffff + uint32(ftou(pg*s.invTotalWeight))
+			pb0 := (uint32(dst.Pix[d+4])<<8|uint32(dst.Pix[d+5]))*pa1/0xffff + uint32(ftou(pb*s.invTotalWeight))
+			pa0 := (uint32(dst.Pix[d+6])<<8|uint32(dst.Pix[d+7]))*pa1/0xffff + uint32(ftou(pa*s.invTotalWeight))
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+			d += dst.Stride
+		}
+	}
+}
+
+func (z *kernelScaler) scaleY_RGBA64_Src(dst *image.RGBA64, dr, adr image.Rectangle, tmp [][4]float64, opts *Options) {
+	for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
+		d := (dr.Min.Y+adr.Min.Y-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+int(dx)-dst.Rect.Min.X)*8
+		for _, s := range z.vertical.sources[adr.Min.Y:adr.Max.Y] {
+			var pr, pg, pb, pa float64
+			for _, c := range z.vertical.contribs[s.i:s.j] {
+				p := &tmp[c.coord*z.dw+dx]
+				pr += float64(p[0] * c.weight)
+				pg += float64(p[1] * c.weight)
+				pb += float64(p[2] * c.weight)
+				pa += float64(p[3] * c.weight)
+			}
+
+			if pr > pa {
+				pr = pa
+			}
+			if pg > pa {
+				pg = pa
+			}
+			if pb > pa {
+				pb = pa
+			}
+
+			pr0 := uint32(ftou(pr * s.invTotalWeight))
+			pg0 := uint32(ftou(pg * s.invTotalWeight))
+			pb0 := uint32(ftou(pb * s.invTotalWeight))
+			pa0 := uint32(ftou(pa * s.invTotalWeight))
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+			d += dst.Stride
+		}
+	}
+}
+
+func (z *kernelScaler) scaleY_NRGBA64_Over(dst *image.NRGBA64, dr, adr image.Rectangle, tmp [][4]float64, opts *Options) {
+	for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
+		d := (dr.Min.Y+adr.Min.Y-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+int(dx)-dst.Rect.Min.X)*8
+		for _, s := range z.vertical.sources[adr.Min.Y:adr.Max.Y] {
+			var pr, pg, pb, pa float64
+			for _, c := range z.vertical.contribs[s.i:s.j] {
+				p := &tmp[c.coord*z.dw+dx]
+				pr += float64(p[0] * c.weight)
+				pg += float64(p[1] * c.weight)
+				pb += float64(p[2] * c.weight)
+				pa += float64(p[3] * c.weight)
+			}
+
+			if pr > pa {
+				pr = pa
+			}
+			if pg > pa {
+				pg = pa
+			}
+			if pb > pa {
+				pb = pa
+			}
+
+			pa1 := 0xffff - uint32(ftou(pa*s.invTotalWeight))
+			pqa := (uint32(dst.Pix[d+6])<<8 | uint32(dst.Pix[d+7]))
+			pr0 := ((uint32(dst.Pix[d+0])<<8|uint32(dst.Pix[d+1]))*pqa/0xffff)*pa1/0xffff + uint32(ftou(pr*s.invTotalWeight))
+			pg0 := ((uint32(dst.Pix[d+2])<<8|uint32(dst.Pix[d+3]))*pqa/0xffff)*pa1/0xffff + uint32(ftou(pg*s.invTotalWeight))
+			pb0 := ((uint32(dst.Pix[d+4])<<8|uint32(dst.Pix[d+5]))*pqa/0xffff)*pa1/0xffff + uint32(ftou(pb*s.invTotalWeight))
+			pa0 := pqa*pa1/0xffff + uint32(ftou(pa*s.invTotalWeight))
+			if pa0 != 0 && pa0 != 0xffff {
+				pr0 = pr0 * 0xffff / pa0
+				pg0 = pg0 * 0xffff / pa0
+				pb0 = pb0 * 0xffff / pa0
+			}
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+			d += dst.Stride
+		}
+	}
+}
+
+func (z *kernelScaler) scaleY_NRGBA64_Src(dst *image.NRGBA64, dr, adr image.Rectangle, tmp [][4]float64, opts *Options) {
+	for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
+		d := (dr.Min.Y+adr.Min.Y-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+int(dx)-dst.Rect.Min.X)*8
+		for _, s := range z.vertical.sources[adr.Min.Y:adr.Max.Y] {
+			var pr, pg, pb, pa float64
+			for _, c := range z.vertical.contribs[s.i:s.j] {
+				p := &tmp[c.coord*z.dw+dx]
+				pr += float64(p[0] * c.weight)
+				pg += float64(p[1] * c.weight)
+				pb += float64(p[2] * c.weight)
+				pa += float64(p[3] * c.weight)
+			}
+
+			if pr > pa {
+				pr = pa
+			}
+			if pg > pa {
+				pg = pa
+			}
+			if pb > pa {
+				pb = pa
+			}
+
+			pr0 := uint32(ftou(pr * s.invTotalWeight))
+			pg0 := uint32(ftou(pg * s.invTotalWeight))
+			pb0 := uint32(ftou(pb * s.invTotalWeight))
+			pa0 := uint32(ftou(pa * s.invTotalWeight))
+			if pa0 != 0 && pa0 != 0xffff {
+				pr0 = pr0 * 0xffff / pa0
+				pg0 = pg0 * 0xffff / pa0
+				pb0 = pb0 * 0xffff / pa0
+			}
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
+			d += dst.Stride
+		}
+	}
+}
+
+func (z *kernelScaler) scaleY_RGBA64Image_Over(dst RGBA64Image, dr, adr image.Rectangle, tmp [][4]float64, opts *Options) {
+	dstMask, dmp := opts.DstMask, opts.DstMaskP
+	dstColorRGBA64 := color.RGBA64{}
+
+	for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
+		for dy, s := range z.vertical.sources[adr.Min.Y:adr.Max.Y] {
+			var pr, pg, pb, pa float64
+			for _, c := range z.vertical.contribs[s.i:s.j] {
+				p := &tmp[c.coord*z.dw+dx]
+				pr += float64(p[0] * c.weight)
+				pg += float64(p[1] * c.weight)
+				pb += float64(p[2] * c.weight)
+				pa += float64(p[3] * c.weight)
+			}
+
+			if pr > pa {
+				pr = pa
+			}
+			if pg > pa {
+				pg = pa
+			}
+			if pb > pa {
+				pb = pa
+			}
+
+			q := dst.RGBA64At(dr.Min.X+int(dx), dr.Min.Y+int(adr.Min.Y+dy))
+			pr0 := uint32(ftou(pr * s.invTotalWeight))
+			pg0 := uint32(ftou(pg * s.invTotalWeight))
+			pb0 := uint32(ftou(pb * s.invTotalWeight))
+			pa0 := uint32(ftou(pa * s.invTotalWeight))
+			if dstMask != nil {
+				_, _, _, ma := dstMask.At(dmp.X+dr.Min.X+int(dx), dmp.Y+dr.Min.Y+int(adr.Min.Y+dy)).RGBA()
+				pr0 = pr0 * ma / 0xffff
+				pg0 = pg0 * ma / 0xffff
+				pb0 = pb0 * ma / 0xffff
+				pa0 = pa0 * ma / 0xffff
+			}
+			pa1 := 0xffff - pa0
+			dstColorRGBA64.R = uint16(uint32(q.R)*pa1/0xffff + pr0)
+			dstColorRGBA64.G = uint16(uint32(q.G)*pa1/0xffff + pg0)
+			dstColorRGBA64.B = uint16(uint32(q.B)*pa1/0xffff + pb0)
+			dstColorRGBA64.A = uint16(uint32(q.A)*pa1/0xffff + pa0)
+			dst.SetRGBA64(dr.Min.X+int(dx), dr.Min.Y+int(adr.Min.Y+dy), dstColorRGBA64)
+		}
+	}
+}
+
+func (z *kernelScaler) scaleY_RGBA64Image_Src(dst RGBA64Image, dr, adr image.Rectangle, tmp [][4]float64, opts *Options) {
+	dstMask, dmp := opts.DstMask, opts.DstMaskP
+	dstColorRGBA64 := color.RGBA64{}
+
+	for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
+		for dy, s := range z.vertical.sources[adr.Min.Y:adr.Max.Y] {
+			var pr, pg, pb, pa float64
+			for _, c := range z.vertical.contribs[s.i:s.j] {
+				p := &tmp[c.coord*z.dw+dx]
+				pr += float64(p[0] * c.weight)
+				pg += float64(p[1] * c.weight)
+				pb += float64(p[2] * c.weight)
+				pa += float64(p[3] * c.weight)
+			}
+
+			if pr > pa {
+				pr = pa
+			}
+			if pg > pa {
+				pg = pa
+			}
+			if pb > pa {
+				pb = pa
+			}
+
+			if dstMask != nil {
+				q := dst.RGBA64At(dr.Min.X+int(dx), dr.Min.Y+int(adr.Min.Y+dy))
+				_, _, _, ma := dstMask.At(dmp.X+dr.Min.X+int(dx), dmp.Y+dr.Min.Y+int(adr.Min.Y+dy)).RGBA()
+				pr := uint32(ftou(pr*s.invTotalWeight)) * ma / 0xffff
+				pg := uint32(ftou(pg*s.invTotalWeight)) * ma / 0xffff
+				pb := uint32(ftou(pb*s.invTotalWeight)) * ma / 0xffff
+				pa := uint32(ftou(pa*s.invTotalWeight)) * ma / 0xffff
+				pa1 := 0xffff - ma
+				dstColorRGBA64.R = uint16(uint32(q.R)*pa1/0xffff + pr)
+				dstColorRGBA64.G = uint16(uint32(q.G)*pa1/0xffff + pg)
+				dstColorRGBA64.B = uint16(uint32(q.B)*pa1/0xffff + pb)
+				dstColorRGBA64.A = uint16(uint32(q.A)*pa1/0xffff + pa)
+				dst.SetRGBA64(dr.Min.X+int(dx), dr.Min.Y+int(adr.Min.Y+dy), dstColorRGBA64)
+			} else {
+				dstColorRGBA64.R = ftou(pr * s.invTotalWeight)
+				dstColorRGBA64.G = ftou(pg * s.invTotalWeight)
+				dstColorRGBA64.B = ftou(pb * s.invTotalWeight)
+				dstColorRGBA64.A = ftou(pa * s.invTotalWeight)
+				dst.SetRGBA64(dr.Min.X+int(dx), dr.Min.Y+int(adr.Min.Y+dy), dstColorRGBA64)
+			}
+		}
+	}
+}
+
+func (z *kernelScaler) scaleY_Image_Over(dst Image, dr, adr image.Rectangle, tmp [][4]float64, opts *Options) {
+	dstMask, dmp := opts.DstMask, opts.DstMaskP
+	dstColorRGBA64 := &color.RGBA64{}
+	dstColor := color.Color(dstColorRGBA64)
+	for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
+		for dy, s := range z.vertical.sources[adr.Min.Y:adr.Max.Y] {
+			var pr, pg, pb, pa float64
+			for _, c := range z.vertical.contribs[s.i:s.j] {
+				p := &tmp[c.coord*z.dw+dx]
+				pr += float64(p[0] * c.weight)
+				pg += float64(p[1] * c.weight)
+				pb += float64(p[2] * c.weight)
+				pa += float64(p[3] * c.weight)
+			}
+
+			if pr > pa {
+				pr = pa
+			}
+			if pg > pa {
+				pg = pa
+			}
+			if pb > pa {
+				pb = pa
+			}
+
+			qr, qg, qb, qa := dst.At(dr.Min.X+int(dx), dr.Min.Y+int(adr.Min.Y+dy)).RGBA()
+			pr0 := uint32(ftou(pr * s.invTotalWeight))
+			pg0 := uint32(ftou(pg * s.invTotalWeight))
+			pb0 := uint32(ftou(pb * s.invTotalWeight))
+			pa0 := uint32(ftou(pa * s.invTotalWeight))
+			if dstMask != nil {
+				_, _, _, ma := dstMask.At(dmp.X+dr.Min.X+int(dx), dmp.Y+dr.Min.Y+int(adr.Min.Y+dy)).RGBA()
+				pr0 = pr0 * ma / 0xffff
+				pg0 = pg0 * ma / 0xffff
+				pb0 = pb0 * ma / 0xffff
+				pa0 = pa0 * ma / 0xffff
+			}
+			pa1 := 0xffff - pa0
+			dstColorRGBA64.R = uint16(qr*pa1/0xffff + pr0)
+			dstColorRGBA64.G = uint16(qg*pa1/0xffff + pg0)
+			dstColorRGBA64.B = uint16(qb*pa1/0xffff + pb0)
+			dstColorRGBA64.A = uint16(qa*pa1/0xffff + pa0)
+			dst.Set(dr.Min.X+int(dx), dr.Min.Y+int(adr.Min.Y+dy), dstColor)
+		}
+	}
+}
+
+func (z *kernelScaler) scaleY_Image_Src(dst Image, dr, adr image.Rectangle, tmp [][4]float64, opts *Options) {
+	dstMask, dmp := opts.DstMask, opts.DstMaskP
+	dstColorRGBA64 := &color.RGBA64{}
+	dstColor := color.Color(dstColorRGBA64)
+	for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
+		for dy, s := range z.vertical.sources[adr.Min.Y:adr.Max.Y] {
+			var pr, pg, pb, pa float64
+			for _, c := range z.vertical.contribs[s.i:s.j] {
+				p := &tmp[c.coord*z.dw+dx]
+				pr += float64(p[0] * c.weight)
+				pg += float64(p[1] * c.weight)
+				pb += float64(p[2] * c.weight)
+				pa += float64(p[3] * c.weight)
+			}
+
+			if pr > pa {
+				pr = pa
+			}
+			if pg > pa {
+				pg = pa
+			}
+			if pb > pa {
+				pb = pa
+			}
+
+			if dstMask != nil {
+				qr, qg, qb, qa := dst.At(dr.Min.X+int(dx), dr.Min.Y+int(adr.Min.Y+dy)).RGBA()
+				_, _, _, ma := dstMask.At(dmp.X+dr.Min.X+int(dx), dmp.Y+dr.Min.Y+int(adr.Min.Y+dy)).RGBA()
+				pr := uint32(ftou(pr*s.invTotalWeight)) * ma / 0xffff
+				pg := uint32(ftou(pg*s.invTotalWeight)) * ma / 0xffff
+				pb := uint32(ftou(pb*s.invTotalWeight)) * ma / 0xffff
+				pa := uint32(ftou(pa*s.invTotalWeight)) * ma / 0xffff
+				pa1 := 0xffff - ma
+				dstColorRGBA64.R = uint16(qr*pa1/0xffff + pr)
+				dstColorRGBA64.G = uint16(qg*pa1/0xffff + pg)
+				dstColorRGBA64.B = uint16(qb*pa1/0xffff + pb)
+				dstColorRGBA64.A = uint16(qa*pa1/0xffff + pa)
+				dst.Set(dr.Min.X+int(dx), dr.Min.Y+int(adr.Min.Y+dy), dstColor)
+			} else {
+				dstColorRGBA64.R = ftou(pr * s.invTotalWeight)
+				dstColorRGBA64.G = ftou(pg * s.invTotalWeight)
+				dstColorRGBA64.B = ftou(pb * s.invTotalWeight)
+				dstColorRGBA64.A = ftou(pa * s.invTotalWeight)
+				dst.Set(dr.Min.X+int(dx), dr.Min.Y+int(adr.Min.Y+dy), dstColor)
+			}
+		}
+	}
+}
+
+func (q *Kernel) transform_Alpha_Alpha_Over(dst *image.Alpha, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.Alpha, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+	// When shrinking, broaden the effective kernel support so that we still
+	// visit every source pixel.
+	xHalfWidth, xKernelArgScale := q.Support, 1.0
+	if xscale > 1 {
+		xHalfWidth *= xscale
+		xKernelArgScale = 1 / xscale
+	}
+	yHalfWidth, yKernelArgScale := q.Support, 1.0
+	if yscale > 1 {
+		yHalfWidth *= yscale
+		yKernelArgScale = 1 / yscale
+	}
+
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			// TODO: adjust the bias so that we can use int(f) instead
+			// of math.Floor(f) and math.Ceil(f).
+			sx += float64(bias.X)
+			sx -= 0.5
+			ix := int(math.Floor(sx - xHalfWidth))
+			if ix < sr.Min.X {
+				ix = sr.Min.X
+			}
+			jx := int(math.Ceil(sx + xHalfWidth))
+			if jx > sr.Max.X {
+				jx = sr.Max.X
+			}
+
+			totalXWeight := 0.0
+			for kx := ix; kx < jx; kx++ {
+				xWeight := 0.0
+				if t := abs((sx - float64(kx)) * xKernelArgScale); t < q.Support {
+					xWeight = q.At(t)
+				}
+				xWeights[kx-ix] = xWeight
+				totalXWeight += xWeight
+			}
+			for x := range xWeights[:jx-ix] {
+				xWeights[x] /= totalXWeight
+			}
+
+			sy += float64(bias.Y)
+			sy -= 0.5
+			iy := int(math.Floor(sy - yHalfWidth))
+			if iy < sr.Min.Y {
+				iy = sr.Min.Y
+			}
+			jy := int(math.Ceil(sy + yHalfWidth))
+			if jy > sr.Max.Y {
+				jy = sr.Max.Y
+			}
+
+			totalYWeight := 0.0
+			for ky := iy; ky < jy; ky++ {
+				yWeight := 0.0
+				if t := abs((sy - float64(ky)) * yKernelArgScale); t < q.Support {
+					yWeight = q.At(t)
+				}
+				yWeights[ky-iy] = yWeight
+				totalYWeight += yWeight
+			}
+			for y := range yWeights[:jy-iy] {
+				yWeights[y] /= totalYWeight
+			}
+
+			var pa float64
+			for ky := iy; ky < jy; ky++ {
+				if yWeight := yWeights[ky-iy]; yWeight != 0 {
+					for kx := ix; kx < jx; kx++ {
+						if w := xWeights[kx-ix] * yWeight; w != 0 {
+							pi := (ky-src.Rect.Min.Y)*src.Stride + (kx - src.Rect.Min.X)
+							pau := uint32(src.Pix[pi+0]) * 0x101
+							pa += float64(float64(pau) * w)
+						}
+					}
+				}
+			}
+			pa0 := uint32(fffftou(pa))
+			pa1 := (0xffff - uint32(pa0)) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pa0) >> 8)
+		}
+	}
+}
+
+func (q *Kernel) transform_Alpha_Alpha_Src(dst *image.Alpha, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.Alpha, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+	// When shrinking, broaden the effective kernel support so that we still
+	// visit every source pixel.
+	xHalfWidth, xKernelArgScale := q.Support, 1.0
+	if xscale > 1 {
+		xHalfWidth *= xscale
+		xKernelArgScale = 1 / xscale
+	}
+	yHalfWidth, yKernelArgScale := q.Support, 1.0
+	if yscale > 1 {
+		yHalfWidth *= yscale
+		yKernelArgScale = 1 / yscale
+	}
+
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			// TODO: adjust the bias so that we can use int(f) instead
+			// of math.Floor(f) and math.Ceil(f).
+			sx += float64(bias.X)
+			sx -= 0.5
+			ix := int(math.Floor(sx - xHalfWidth))
+			if ix < sr.Min.X {
+				ix = sr.Min.X
+			}
+			jx := int(math.Ceil(sx + xHalfWidth))
+			if jx > sr.Max.X {
+				jx = sr.Max.X
+			}
+
+			totalXWeight := 0.0
+			for kx := ix; kx < jx; kx++ {
+				xWeight := 0.0
+				if t := abs((sx - float64(kx)) * xKernelArgScale); t < q.Support {
+					xWeight = q.At(t)
+				}
+				xWeights[kx-ix] = xWeight
+				totalXWeight += xWeight
+			}
+			for x := range xWeights[:jx-ix] {
+				xWeights[x] /= totalXWeight
+			}
+
+			sy += float64(bias.Y)
+			sy -= 0.5
+			iy := int(math.Floor(sy - yHalfWidth))
+			if iy < sr.Min.Y {
+				iy = sr.Min.Y
+			}
+			jy := int(math.Ceil(sy + yHalfWidth))
+			if jy > sr.Max.Y {
+				jy = sr.Max.Y
+			}
+
+			totalYWeight := 0.0
+			for ky := iy; ky < jy; ky++ {
+				yWeight := 0.0
+				if t := abs((sy - float64(ky)) * yKernelArgScale); t < q.Support {
+					yWeight = q.At(t)
+				}
+				yWeights[ky-iy] = yWeight
+				totalYWeight += yWeight
+			}
+			for y := range yWeights[:jy-iy] {
+				yWeights[y] /= totalYWeight
+			}
+
+			var pa float64
+			for ky := iy; ky < jy; ky++ {
+				if yWeight := yWeights[ky-iy]; yWeight != 0 {
+					for kx := ix; kx < jx; kx++ {
+						if w := xWeights[kx-ix] * yWeight; w != 0 {
+							pi := (ky-src.Rect.Min.Y)*src.Stride + (kx - src.Rect.Min.X)
+							pau := uint32(src.Pix[pi+0]) * 0x101
+							pa += float64(float64(pau) * w)
+						}
+					}
+				}
+			}
+			dst.Pix[d+0] = uint8(fffftou(pa) >> 8)
+		}
+	}
+}
+
+func (q *Kernel) transform_Alpha_RGBA64Image_Over(dst *image.Alpha, dr, adr image.Rectangle, d2s *f64.Aff3, src image.RGBA64Image, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+	// When shrinking, broaden the effective kernel support so that we still
+	// visit every source pixel.
+	xHalfWidth, xKernelArgScale := q.Support, 1.0
+	if xscale > 1 {
+		xHalfWidth *= xscale
+		xKernelArgScale = 1 / xscale
+	}
+	yHalfWidth, yKernelArgScale := q.Support, 1.0
+	if yscale > 1 {
+		yHalfWidth *= yscale
+		yKernelArgScale = 1 / yscale
+	}
+
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			// TODO: adjust the bias so that we can use int(f) instead
+			// of math.Floor(f) and math.Ceil(f).
+			sx += float64(bias.X)
+			sx -= 0.5
+			ix := int(math.Floor(sx - xHalfWidth))
+			if ix < sr.Min.X {
+				ix = sr.Min.X
+			}
+			jx := int(math.Ceil(sx + xHalfWidth))
+			if jx > sr.Max.X {
+				jx = sr.Max.X
+			}
+
+			totalXWeight := 0.0
+			for kx := ix; kx < jx; kx++ {
+				xWeight := 0.0
+				if t := abs((sx - float64(kx)) * xKernelArgScale); t < q.Support {
+					xWeight = q.At(t)
+				}
+				xWeights[kx-ix] = xWeight
+				totalXWeight += xWeight
+			}
+			for x := range xWeights[:jx-ix] {
+				xWeights[x] /= totalXWeight
+			}
+
+			sy += float64(bias.Y)
+			sy -= 0.5
+			iy := int(math.Floor(sy - yHalfWidth))
+			if iy < sr.Min.Y {
+				iy = sr.Min.Y
+			}
+			jy := int(math.Ceil(sy + yHalfWidth))
+			if jy > sr.Max.Y {
+				jy = sr.Max.Y
+			}
+
+			totalYWeight := 0.0
+			for ky := iy; ky < jy; ky++ {
+				yWeight := 0.0
+				if t := abs((sy - float64(ky)) * yKernelArgScale); t < q.Support {
+					yWeight = q.At(t)
+				}
+				yWeights[ky-iy] = yWeight
+				totalYWeight += yWeight
+			}
+			for y := range yWeights[:jy-iy] {
+				yWeights[y] /= totalYWeight
+			}
+
+			var pa float64
+			for ky := iy; ky < jy; ky++ {
+				if yWeight := yWeights[ky-iy]; yWeight != 0 {
+					for kx := ix; kx < jx; kx++ {
+						if w := xWeights[kx-ix] * yWeight; w != 0 {
+							pu := src.RGBA64At(kx, ky)
+							pa += float64(float64(pu.A) * w)
+						}
+					}
+				}
+			}
+			pa0 := uint32(fffftou(pa))
+			pa1 := (0xffff - uint32(pa0)) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pa0) >> 8)
+		}
+	}
+}
+
+func (q *Kernel) transform_Alpha_RGBA64Image_Src(dst *image.Alpha, dr, adr image.Rectangle, d2s *f64.Aff3, src image.RGBA64Image, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+	// When shrinking, broaden the effective kernel support so that we still
+	// visit every source pixel.
+	xHalfWidth, xKernelArgScale := q.Support, 1.0
+	if xscale > 1 {
+		xHalfWidth *= xscale
+		xKernelArgScale = 1 / xscale
+	}
+	yHalfWidth, yKernelArgScale := q.Support, 1.0
+	if yscale > 1 {
+		yHalfWidth *= yscale
+		yKernelArgScale = 1 / yscale
+	}
+
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			// TODO: adjust the bias so that we can use int(f) instead
+			// of math.Floor(f) and math.Ceil(f).
+			sx += float64(bias.X)
+			sx -= 0.5
+			ix := int(math.Floor(sx - xHalfWidth))
+			if ix < sr.Min.X {
+				ix = sr.Min.X
+			}
+			jx := int(math.Ceil(sx + xHalfWidth))
+			if jx > sr.Max.X {
+				jx = sr.Max.X
+			}
+
+			totalXWeight := 0.0
+			for kx := ix; kx < jx; kx++ {
+				xWeight := 0.0
+				if t := abs((sx - float64(kx)) * xKernelArgScale); t < q.Support {
+					xWeight = q.At(t)
+				}
+				xWeights[kx-ix] = xWeight
+				totalXWeight += xWeight
+			}
+			for x := range xWeights[:jx-ix] {
+				xWeights[x] /= totalXWeight
+			}
+
+			sy += float64(bias.Y)
+			sy -= 0.5
+			iy := int(math.Floor(sy - yHalfWidth))
+			if iy < sr.Min.Y {
+				iy = sr.Min.Y
+			}
+			jy := int(math.Ceil(sy + yHalfWidth))
+			if jy > sr.Max.Y {
+				jy = sr.Max.Y
+			}
+
+			totalYWeight := 0.0
+			for ky := iy; ky < jy; ky++ {
+				yWeight := 0.0
+				if t := abs((sy - float64(ky)) * yKernelArgScale); t < q.Support {
+					yWeight = q.At(t)
+				}
+				yWeights[ky-iy] = yWeight
+				totalYWeight += yWeight
+			}
+			for y := range yWeights[:jy-iy] {
+				yWeights[y] /= totalYWeight
+			}
+
+			var pa float64
+			for ky := iy; ky < jy; ky++ {
+				if yWeight := yWeights[ky-iy]; yWeight != 0 {
+					for kx := ix; kx < jx; kx++ {
+						if w := xWeights[kx-ix] * yWeight; w != 0 {
+							pu := src.RGBA64At(kx, ky)
+							pa += float64(float64(pu.A) * w)
+						}
+					}
+				}
+			}
+			dst.Pix[d+0] = uint8(fffftou(pa) >> 8)
+		}
+	}
+}
+
+func (q *Kernel) transform_Alpha_Image_Over(dst *image.Alpha, dr, adr image.Rectangle, d2s *f64.Aff3, src image.Image, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+	// When shrinking, broaden the effective kernel support so that we still
+	// visit every source pixel.
+	xHalfWidth, xKernelArgScale := q.Support, 1.0
+	if xscale > 1 {
+		xHalfWidth *= xscale
+		xKernelArgScale = 1 / xscale
+	}
+	yHalfWidth, yKernelArgScale := q.Support, 1.0
+	if yscale > 1 {
+		yHalfWidth *= yscale
+		yKernelArgScale = 1 / yscale
+	}
+
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			// TODO: adjust the bias so that we can use int(f) instead
+			// of math.Floor(f) and math.Ceil(f).
+			sx += float64(bias.X)
+			sx -= 0.5
+			ix := int(math.Floor(sx - xHalfWidth))
+			if ix < sr.Min.X {
+				ix = sr.Min.X
+			}
+			jx := int(math.Ceil(sx + xHalfWidth))
+			if jx > sr.Max.X {
+				jx = sr.Max.X
+			}
+
+			totalXWeight := 0.0
+			for kx := ix; kx < jx; kx++ {
+				xWeight := 0.0
+				if t := abs((sx - float64(kx)) * xKernelArgScale); t < q.Support {
+					xWeight = q.At(t)
+				}
+				xWeights[kx-ix] = xWeight
+				totalXWeight += xWeight
+			}
+			for x := range xWeights[:jx-ix] {
+				xWeights[x] /= totalXWeight
+			}
+
+			sy += float64(bias.Y)
+			sy -= 0.5
+			iy := int(math.Floor(sy - yHalfWidth))
+			if iy < sr.Min.Y {
+				iy = sr.Min.Y
+			}
+			jy := int(math.Ceil(sy + yHalfWidth))
+			if jy > sr.Max.Y {
+				jy = sr.Max.Y
+			}
+
+			totalYWeight := 0.0
+			for ky := iy; ky < jy; ky++ {
+				yWeight := 0.0
+				if t := abs((sy - float64(ky)) * yKernelArgScale); t < q.Support {
+					yWeight = q.At(t)
+				}
+				yWeights[ky-iy] = yWeight
+				totalYWeight += yWeight
+			}
+			for y := range yWeights[:jy-iy] {
+				yWeights[y] /= totalYWeight
+			}
+
+			var pa float64
+			for ky := iy; ky < jy; ky++ {
+				if yWeight := yWeights[ky-iy]; yWeight != 0 {
+					for kx := ix; kx < jx; kx++ {
+						if w := xWeights[kx-ix] * yWeight; w != 0 {
+							_, _, _, pau := src.At(kx, ky).RGBA()
+							pa += float64(float64(pau) * w)
+						}
+					}
+				}
+			}
+			pa0 := uint32(fffftou(pa))
+			pa1 := (0xffff - uint32(pa0)) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pa0) >> 8)
+		}
+	}
+}
+
+func (q *Kernel) transform_Alpha_Image_Src(dst *image.Alpha, dr, adr image.Rectangle, d2s *f64.Aff3, src image.Image, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+	// When shrinking, broaden the effective kernel support so that we still
+	// visit every source pixel.
+	xHalfWidth, xKernelArgScale := q.Support, 1.0
+	if xscale > 1 {
+		xHalfWidth *= xscale
+		xKernelArgScale = 1 / xscale
+	}
+	yHalfWidth, yKernelArgScale := q.Support, 1.0
+	if yscale > 1 {
+		yHalfWidth *= yscale
+		yKernelArgScale = 1 / yscale
+	}
+
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			// TODO: adjust the bias so that we can use int(f) instead
+			// of math.Floor(f) and math.Ceil(f).
+			sx += float64(bias.X)
+			sx -= 0.5
+			ix := int(math.Floor(sx - xHalfWidth))
+			if ix < sr.Min.X {
+				ix = sr.Min.X
+			}
+			jx := int(math.Ceil(sx + xHalfWidth))
+			if jx > sr.Max.X {
+				jx = sr.Max.X
+			}
+
+			totalXWeight := 0.0
+			for kx := ix; kx < jx; kx++ {
+				xWeight := 0.0
+				if t := abs((sx - float64(kx)) * xKernelArgScale); t < q.Support {
+					xWeight = q.At(t)
+				}
+				xWeights[kx-ix] = xWeight
+				totalXWeight += xWeight
+			}
+			for x := range xWeights[:jx-ix] {
+				xWeights[x] /= totalXWeight
+			}
+
+			sy += float64(bias.Y)
+			sy -= 0.5
+			iy := int(math.Floor(sy - yHalfWidth))
+			if iy < sr.Min.Y {
+				iy = sr.Min.Y
+			}
+			jy := int(math.Ceil(sy + yHalfWidth))
+			if jy > sr.Max.Y {
+				jy = sr.Max.Y
+			}
+
+			totalYWeight := 0.0
+			for ky := iy; ky < jy; ky++ {
+				yWeight := 0.0
+				if t := abs((sy - float64(ky)) * yKernelArgScale); t < q.Support {
+					yWeight = q.At(t)
+				}
+				yWeights[ky-iy] = yWeight
+				totalYWeight += yWeight
+			}
+			for y := range yWeights[:jy-iy] {
+				yWeights[y] /= totalYWeight
+			}
+
+			var pa float64
+			for ky := iy; ky < jy; ky++ {
+				if yWeight := yWeights[ky-iy]; yWeight != 0 {
+					for kx := ix; kx < jx; kx++ {
+						if w := xWeights[kx-ix] * yWeight; w != 0 {
+							_, _, _, pau := src.At(kx, ky).RGBA()
+							pa += float64(float64(pau) * w)
+						}
+					}
+				}
+			}
+			dst.Pix[d+0] = uint8(fffftou(pa) >> 8)
+		}
+	}
+}
+
+func (q *Kernel) transform_Gray_Gray_Src(dst *image.Gray, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.Gray, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+	// When shrinking, broaden the effective kernel support so that we still
+	// visit every source pixel.
+	xHalfWidth, xKernelArgScale := q.Support, 1.0
+	if xscale > 1 {
+		xHalfWidth *= xscale
+		xKernelArgScale = 1 / xscale
+	}
+	yHalfWidth, yKernelArgScale := q.Support, 1.0
+	if yscale > 1 {
+		yHalfWidth *= yscale
+		yKernelArgScale = 1 / yscale
+	}
+
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			// TODO: adjust the bias so that we can use int(f) instead
+			// of math.Floor(f) and math.Ceil(f).
+			sx += float64(bias.X)
+			sx -= 0.5
+			ix := int(math.Floor(sx - xHalfWidth))
+			if ix < sr.Min.X {
+				ix = sr.Min.X
+			}
+			jx := int(math.Ceil(sx + xHalfWidth))
+			if jx > sr.Max.X {
+				jx = sr.Max.X
+			}
+
+			totalXWeight := 0.0
+			for kx := ix; kx < jx; kx++ {
+				xWeight := 0.0
+				if t := abs((sx - float64(kx)) * xKernelArgScale); t < q.Support {
+					xWeight = q.At(t)
+				}
+				xWeights[kx-ix] = xWeight
+				totalXWeight += xWeight
+			}
+			for x := range xWeights[:jx-ix] {
+				xWeights[x] /= totalXWeight
+			}
+
+			sy += float64(bias.Y)
+			sy -= 0.5
+			iy := int(math.Floor(sy - yHalfWidth))
+			if iy < sr.Min.Y {
+				iy = sr.Min.Y
+			}
+			jy := int(math.Ceil(sy + yHalfWidth))
+			if jy > sr.Max.Y {
+				jy = sr.Max.Y
+			}
+
+			totalYWeight := 0.0
+			for ky := iy; ky < jy; ky++ {
+				yWeight := 0.0
+				if t := abs((sy - float64(ky)) * yKernelArgScale); t < q.Support {
+					yWeight = q.At(t)
+				}
+				yWeights[ky-iy] = yWeight
+				totalYWeight += yWeight
+			}
+			for y := range yWeights[:jy-iy] {
+				yWeights[y] /= totalYWeight
+			}
+
+			var pr float64
+			for ky := iy; ky < jy; ky++ {
+				if yWeight := yWeights[ky-iy]; yWeight != 0 {
+					for kx := ix; kx < jx; kx++ {
+						if w := xWeights[kx-ix] * yWeight; w != 0 {
+							pi := (ky-src.Rect.Min.Y)*src.Stride + (kx - src.Rect.Min.X)
+							pru := uint32(src.Pix[pi]) * 0x101
+							pr += float64(float64(pru) * w)
+						}
+					}
+				}
+			}
+			dst.Pix[d] = uint8(fffftou(pr) >> 8)
+		}
+	}
+}
+
+func (q *Kernel) transform_Gray_RGBA64Image_Over(dst *image.Gray, dr, adr image.Rectangle, d2s *f64.Aff3, src image.RGBA64Image, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+	// When shrinking, broaden the effective kernel support so that we still
+	// visit every source pixel.
+	xHalfWidth, xKernelArgScale := q.Support, 1.0
+	if xscale > 1 {
+		xHalfWidth *= xscale
+		xKernelArgScale = 1 / xscale
+	}
+	yHalfWidth, yKernelArgScale := q.Support, 1.0
+	if yscale > 1 {
+		yHalfWidth *= yscale
+		yKernelArgScale = 1 / yscale
+	}
+
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			// TODO: adjust the bias so that we can use int(f) instead
+			// of math.Floor(f) and math.Ceil(f).
+			sx += float64(bias.X)
+			sx -= 0.5
+			ix := int(math.Floor(sx - xHalfWidth))
+			if ix < sr.Min.X {
+				ix = sr.Min.X
+			}
+			jx := int(math.Ceil(sx + xHalfWidth))
+			if jx > sr.Max.X {
+				jx = sr.Max.X
+			}
+
+			totalXWeight := 0.0
+			for kx := ix; kx < jx; kx++ {
+				xWeight := 0.0
+				if t := abs((sx - float64(kx)) * xKernelArgScale); t < q.Support {
+					xWeight = q.At(t)
+				}
+				xWeights[kx-ix] = xWeight
+				totalXWeight += xWeight
+			}
+			for x := range xWeights[:jx-ix] {
+				xWeights[x] /= totalXWeight
+			}
+
+			sy += float64(bias.Y)
+			sy -= 0.5
+			iy := int(math.Floor(sy - yHalfWidth))
+			if iy < sr.Min.Y {
+				iy = sr.Min.Y
+			}
+			jy := int(math.Ceil(sy + yHalfWidth))
+			if jy > sr.Max.Y {
+				jy = sr.Max.Y
+			}
+
+			totalYWeight := 0.0
+			for ky := iy; ky < jy; ky++ {
+				yWeight := 0.0
+				if t := abs((sy - float64(ky)) * yKernelArgScale); t < q.Support {
+					yWeight = q.At(t)
+				}
+				yWeights[ky-iy] = yWeight
+				totalYWeight += yWeight
+			}
+			for y := range yWeights[:jy-iy] {
+				yWeights[y] /= totalYWeight
+			}
+
+			var pr, pg, pb, pa float64
+			for ky := iy; ky < jy; ky++ {
+				if yWeight := yWeights[ky-iy]; yWeight != 0 {
+					for kx := ix; kx < jx; kx++ {
+						if w := xWeights[kx-ix] * yWeight; w != 0 {
+							pu := src.RGBA64At(kx, ky)
+							pr += float64(float64(pu.R) * w)
+							pg += float64(float64(pu.G) * w)
+							pb += float64(float64(pu.B) * w)
+							pa += float64(float64(pu.A) * w)
+						}
+					}
+				}
+			}
+
+			if pr > pa {
+				pr = pa
+			}
+			if pg > pa {
+				pg = pa
+			}
+			if pb > pa {
+				pb = pa
+			}
+
+			pr0 := uint32(fffftou(pr))
+			pg0 := uint32(fffftou(pg))
+			pb0 := uint32(fffftou(pb))
+			pa0 := uint32(fffftou(pa))
+			pq := uint32(dst.Pix[d]) * 0x101 * (0xffff - pa0) / 0xffff
+			dst.Pix[d] = uint8((19595*(pq+pr0) + 38470*(pq+pg0) + 7471*(pq+pb0) + 1<<15) >> 24)
+		}
+	}
+}
+
+func (q *Kernel) transform_Gray_RGBA64Image_Src(dst *image.Gray, dr, adr image.Rectangle, d2s *f64.Aff3, src image.RGBA64Image, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+	// When shrinking, broaden the effective kernel support so that we still
+	// visit every source pixel.
+	xHalfWidth, xKernelArgScale := q.Support, 1.0
+	if xscale > 1 {
+		xHalfWidth *= xscale
+		xKernelArgScale = 1 / xscale
+	}
+	yHalfWidth, yKernelArgScale := q.Support, 1.0
+	if yscale > 1 {
+		yHalfWidth *= yscale
+		yKernelArgScale = 1 / yscale
+	}
+
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			// TODO: adjust the bias so that we can use int(f) instead
+			// of math.Floor(f) and math.Ceil(f).
+			sx += float64(bias.X)
+			sx -= 0.5
+			ix := int(math.Floor(sx - xHalfWidth))
+			if ix < sr.Min.X {
+				ix = sr.Min.X
+			}
+			jx := int(math.Ceil(sx + xHalfWidth))
+			if jx > sr.Max.X {
+				jx = sr.Max.X
+			}
+
+			totalXWeight := 0.0
+			for kx := ix; kx < jx; kx++ {
+				xWeight := 0.0
+				if t := abs((sx - float64(kx)) * xKernelArgScale); t < q.Support {
+					xWeight = q.At(t)
+				}
+				xWeights[kx-ix] = xWeight
+				totalXWeight += xWeight
+			}
+			for x := range xWeights[:jx-ix] {
+				xWeights[x] /= totalXWeight
+			}
+
+			sy += float64(bias.Y)
+			sy -= 0.5
+			iy := int(math.Floor(sy - yHalfWidth))
+			if iy < sr.Min.Y {
+				iy = sr.Min.Y
+			}
+			jy := int(math.Ceil(sy + yHalfWidth))
+			if jy > sr.Max.Y {
+				jy = sr.Max.Y
+			}
+
+			totalYWeight := 0.0
+			for ky := iy; ky < jy; ky++ {
+				yWeight := 0.0
+				if t := abs((sy - float64(ky)) * yKernelArgScale); t < q.Support {
+					yWeight = q.At(t)
+				}
+				yWeights[ky-iy] = yWeight
+				totalYWeight += yWeight
+			}
+			for y := range yWeights[:jy-iy] {
+				yWeights[y] /= totalYWeight
+			}
+
+			var pr, pg, pb, pa float64
+			for ky := iy; ky < jy; ky++ {
+				if yWeight := yWeights[ky-iy]; yWeight != 0 {
+					for kx := ix; kx < jx; kx++ {
+						if w := xWeights[kx-ix] * yWeight; w != 0 {
+							pu := src.RGBA64At(kx, ky)
+							pr += float64(float64(pu.R) * w)
+							pg += float64(float64(pu.G) * w)
+							pb += float64(float64(pu.B) * w)
+							pa += float64(float64(pu.A) * w)
+						}
+					}
+				}
+			}
+
+			if pr > pa {
+				pr = pa
+			}
+			if pg > pa {
+				pg = pa
+			}
+			if pb > pa {
+				pb = pa
+			}
+
+			pr0 := uint32(fffftou(pr))
+			pg0 := uint32(fffftou(pg))
+			pb0 := uint32(fffftou(pb))
+			dst.Pix[d] = uint8((19595*pr0 + 38470*pg0 + 7471*pb0 + 1<<15) >> 24)
+		}
+	}
+}
+
+func (q *Kernel) transform_Gray_Image_Over(dst *image.Gray, dr, adr image.Rectangle, d2s *f64.Aff3, src image.Image, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+	// When shrinking, broaden the effective kernel support so that we still
+	// visit every source pixel.
+	xHalfWidth, xKernelArgScale := q.Support, 1.0
+	if xscale > 1 {
+		xHalfWidth *= xscale
+		xKernelArgScale = 1 / xscale
+	}
+	yHalfWidth, yKernelArgScale := q.Support, 1.0
+	if yscale > 1 {
+		yHalfWidth *= yscale
+		yKernelArgScale = 1 / yscale
+	}
+
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			// TODO: adjust the bias so that we can use int(f) instead
+			// of math.Floor(f) and math.Ceil(f).
+			sx += float64(bias.X)
+			sx -= 0.5
+			ix := int(math.Floor(sx - xHalfWidth))
+			if ix < sr.Min.X {
+				ix = sr.Min.X
+			}
+			jx := int(math.Ceil(sx + xHalfWidth))
+			if jx > sr.Max.X {
+				jx = sr.Max.X
+			}
+
+			totalXWeight := 0.0
+			for kx := ix; kx < jx; kx++ {
+				xWeight := 0.0
+				if t := abs((sx - float64(kx)) * xKernelArgScale); t < q.Support {
+					xWeight = q.At(t)
+				}
+				xWeights[kx-ix] = xWeight
+				totalXWeight += xWeight
+			}
+			for x := range xWeights[:jx-ix] {
+				xWeights[x] /= totalXWeight
+			}
+
+			sy += float64(bias.Y)
+			sy -= 0.5
+			iy := int(math.Floor(sy - yHalfWidth))
+			if iy < sr.Min.Y {
+				iy = sr.Min.Y
+			}
+			jy := int(math.Ceil(sy + yHalfWidth))
+			if jy > sr.Max.Y {
+				jy = sr.Max.Y
+			}
+
+			totalYWeight := 0.0
+			for ky := iy; ky < jy; ky++ {
+				yWeight := 0.0
+				if t := abs((sy - float64(ky)) * yKernelArgScale); t < q.Support {
+					yWeight = q.At(t)
+				}
+				yWeights[ky-iy] = yWeight
+				totalYWeight += yWeight
+			}
+			for y := range yWeights[:jy-iy] {
+				yWeights[y] /= totalYWeight
+			}
+
+			var pr, pg, pb, pa float64
+			for ky := iy; ky < jy; ky++ {
+				if yWeight := yWeights[ky-iy]; yWeight != 0 {
+					for kx := ix; kx < jx; kx++ {
+						if w := xWeights[kx-ix] * yWeight; w != 0 {
+							pru, pgu, pbu, pau := src.At(kx, ky).RGBA()
+							pr += float64(float64(pru) * w)
+							pg += float64(float64(pgu) * w)
+							pb += float64(float64(pbu) * w)
+							pa += float64(float64(pau) * w)
+						}
+					}
+				}
+			}
+
+			if pr > pa {
+				pr = pa
+			}
+			if pg > pa {
+				pg = pa
+			}
+			if pb > pa {
+				pb = pa
 			}
 
-			s00i := (sy0-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*4
-			s00au := uint32(src.Pix[s00i+3]) * 0x101
-			s00ru := uint32(src.Pix[s00i+0]) * s00au / 0xff
-			s00gu := uint32(src.Pix[s00i+1]) * s00au / 0xff
-			s00bu := uint32(src.Pix[s00i+2]) * s00au / 0xff
-			s00r := float64(s00ru)
-			s00g := float64(s00gu)
-			s00b := float64(s00bu)
-			s00a := float64(s00au)
-			s10i := (sy0-src.Rect.Min.Y)*src.Stride + (sx1-src.Rect.Min.X)*4
-			s10au := uint32(src.Pix[s10i+3]) * 0x101
-			s10ru := uint32(src.Pix[s10i+0]) * s10au / 0xff
-			s10gu := uint32(src.Pix[s10i+1]) * s10au / 0xff
-			s10bu := uint32(src.Pix[s10i+2]) * s10au / 0xff
-			s10r := float64(s10ru)
-			s10g := float64(s10gu)
-			s10b := float64(s10bu)
-			s10a := float64(s10au)
-			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
-			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
-			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
-			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
-			s01i := (sy1-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*4
-			s01au := uint32(src.Pix[s01i+3]) * 0x101
-			s01ru := uint32(src.Pix[s01i+0]) * s01au / 0xff
-			s01gu := uint32(src.Pix[s01i+1]) * s01au / 0xff
-			s01bu := uint32(src.Pix[s01i+2]) * s01au / 0xff
-			s01r := float64(s01ru)
-			s01g := float64(s01gu)
-			s01b := float64(s01bu)
-			s01a := float64(s01au)
-			s11i := (sy1-src.Rect.Min.Y)*src.Stride + (sx1-src.Rect.Min.X)*4
-			s11au := uint32(src.Pix[s11i+3]) * 0x101
-			s11ru := uint32(src.Pix[s11i+0]) * s11au / 0xff
-			s11gu := uint32(src.Pix[s11i+1]) * s11au / 0xff
-			s11bu := uint32(src.Pix[s11i+2]) * s11au / 0xff
-			s11r := float64(s11ru)
-			s11g := float64(s11gu)
-			s11b := float64(s11bu)
-			s11a := float64(s11au)
-			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
-			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
-			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
-			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
-			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
-			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
-			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
-			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
-			pr := uint32(s11r)
-			pg := uint32(s11g)
-			pb := uint32(s11b)
-			pa := uint32(s11a)
-			pa1 := (0xffff - pa) * 0x101
-			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pr) >> 8)
-			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + pg) >> 8)
-			dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*pa1/0xffff + pb) >> 8)
-			dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*pa1/0xffff + pa) >> 8)
+			pr0 := uint32(fffftou(pr))
+			pg0 := uint32(fffftou(pg))
+			pb0 := uint32(fffftou(pb))
+			pa0 := uint32(fffftou(pa))
+			pq := uint32(dst.Pix[d]) * 0x101 * (0xffff - pa0) / 0xffff
+			dst.Pix[d] = uint8((19595*(pq+pr0) + 38470*(pq+pg0) + 7471*(pq+pb0) + 1<<15) >> 24)
 		}
 	}
 }
 
-func (ablInterpolator) transform_RGBA_NRGBA_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NRGBA, sr image.Rectangle, bias image.Point, opts *Options) {
+func (q *Kernel) transform_Gray_Image_Src(dst *image.Gray, dr, adr image.Rectangle, d2s *f64.Aff3, src image.Image, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+	// When shrinking, broaden the effective kernel support so that we still
+	// visit every source pixel.
+	xHalfWidth, xKernelArgScale := q.Support, 1.0
+	if xscale > 1 {
+		xHalfWidth *= xscale
+		xKernelArgScale = 1 / xscale
+	}
+	yHalfWidth, yKernelArgScale := q.Support, 1.0
+	if yscale > 1 {
+		yHalfWidth *= yscale
+		yKernelArgScale = 1 / yscale
+	}
+
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
+
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
 		dyf := float64(dr.Min.Y+int(dy)) + 0.5
-		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
-		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X + adr.Min.X - dst.Rect.Min.X)
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+1 {
 			dxf := float64(dr.Min.X+int(dx)) + 0.5
 			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
 			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
@@ -3715,95 +18783,108 @@ func (ablInterpolator) transform_RGBA_NRGBA_Src(dst *image.RGBA, dr, adr image.R
 				continue
 			}
 
+			// TODO: adjust the bias so that we can use int(f) instead
+			// of math.Floor(f) and math.Ceil(f).
+			sx += float64(bias.X)
 			sx -= 0.5
-			sx0 := int(sx)
-			xFrac0 := sx - float64(sx0)
-			xFrac1 := 1 - xFrac0
-			sx0 += bias.X
-			sx1 := sx0 + 1
-			if sx0 < sr.Min.X {
-				sx0, sx1 = sr.Min.X, sr.Min.X
-				xFrac0, xFrac1 = 0, 1
-			} else if sx1 >= sr.Max.X {
-				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
-				xFrac0, xFrac1 = 1, 0
+			ix := int(math.Floor(sx - xHalfWidth))
+			if ix < sr.Min.X {
+				ix = sr.Min.X
+			}
+			jx := int(math.Ceil(sx + xHalfWidth))
+			if jx > sr.Max.X {
+				jx = sr.Max.X
+			}
+
+			totalXWeight := 0.0
+			for kx := ix; kx < jx; kx++ {
+				xWeight := 0.0
+				if t := abs((sx - float64(kx)) * xKernelArgScale); t < q.Support {
+					xWeight = q.At(t)
+				}
+				xWeights[kx-ix] = xWeight
+				totalXWeight += xWeight
+			}
+			for x := range xWeights[:jx-ix] {
+				xWeights[x] /= totalXWeight
 			}
 
+			sy += float64(bias.Y)
 			sy -= 0.5
-			sy0 := int(sy)
-			yFrac0 := sy - float64(sy0)
-			yFrac1 := 1 - yFrac0
-			sy0 += bias.Y
-			sy1 := sy0 + 1
-			if sy0 < sr.Min.Y {
-				sy0, sy1 = sr.Min.Y, sr.Min.Y
-				yFrac0, yFrac1 = 0, 1
-			} else if sy1 >= sr.Max.Y {
-				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
-				yFrac0, yFrac1 = 1, 0
+			iy := int(math.Floor(sy - yHalfWidth))
+			if iy < sr.Min.Y {
+				iy = sr.Min.Y
+			}
+			jy := int(math.Ceil(sy + yHalfWidth))
+			if jy > sr.Max.Y {
+				jy = sr.Max.Y
 			}
 
-			s00i := (sy0-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*4
-			s00au := uint32(src.Pix[s00i+3]) * 0x101
-			s00ru := uint32(src.Pix[s00i+0]) * s00au / 0xff
-			s00gu := uint32(src.Pix[s00i+1]) * s00au / 0xff
-			s00bu := uint32(src.Pix[s00i+2]) * s00au / 0xff
-			s00r := float64(s00ru)
-			s00g := float64(s00gu)
-			s00b := float64(s00bu)
-			s00a := float64(s00au)
-			s10i := (sy0-src.Rect.Min.Y)*src.Stride + (sx1-src.Rect.Min.X)*4
-			s10au := uint32(src.Pix[s10i+3]) * 0x101
-			s10ru := uint32(src.Pix[s10i+0]) * s10au / 0xff
-			s10gu := uint32(src.Pix[s10i+1]) * s10au / 0xff
-			s10bu := uint32(src.Pix[s10i+2]) * s10au / 0xff
-			s10r := float64(s10ru)
-			s10g := float64(s10gu)
-			s10b := float64(s10bu)
-			s10a := float64(s10au)
-			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
-			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
-			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
-			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
-			s01i := (sy1-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*4
-			s01au := uint32(src.Pix[s01i+3]) * 0x101
-			s01ru := uint32(src.Pix[s01i+0]) * s01au / 0xff
-			s01gu := uint32(src.Pix[s01i+1]) * s01au / 0xff
-			s01bu := uint32(src.Pix[s01i+2]) * s01au / 0xff
-			s01r := float64(s01ru)
-			s01g := float64(s01gu)
-			s01b := float64(s01bu)
-			s01a := float64(s01au)
-			s11i := (sy1-src.Rect.Min.Y)*src.Stride + (sx1-src.Rect.Min.X)*4
-			s11au := uint32(src.Pix[s11i+3]) * 0x101
-			s11ru := uint32(src.Pix[s11i+0]) * s11au / 0xff
-			s11gu := uint32(src.Pix[s11i+1]) * s11au / 0xff
-			s11bu := uint32(src.Pix[s11i+2]) * s11au / 0xff
-			s11r := float64(s11ru)
-			s11g := float64(s11gu)
-			s11b := float64(s11bu)
-			s11a := float64(s11au)
-			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
-			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
-			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
-			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
-			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
-			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
-			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
-			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
-			pr := uint32(s11r)
-			pg := uint32(s11g)
-			pb := uint32(s11b)
-			pa := uint32(s11a)
-			dst.Pix[d+0] = uint8(pr >> 8)
-			dst.Pix[d+1] = uint8(pg >> 8)
-			dst.Pix[d+2] = uint8(pb >> 8)
-			dst.Pix[d+3] = uint8(pa >> 8)
+			totalYWeight := 0.0
+			for ky := iy; ky < jy; ky++ {
+				yWeight := 0.0
+				if t := abs((sy - float64(ky)) * yKernelArgScale); t < q.Support {
+					yWeight = q.At(t)
+				}
+				yWeights[ky-iy] = yWeight
+				totalYWeight += yWeight
+			}
+			for y := range yWeights[:jy-iy] {
+				yWeights[y] /= totalYWeight
+			}
+
+			var pr, pg, pb, pa float64
+			for ky := iy; ky < jy; ky++ {
+				if yWeight := yWeights[ky-iy]; yWeight != 0 {
+					for kx := ix; kx < jx; kx++ {
+						if w := xWeights[kx-ix] * yWeight; w != 0 {
+							pru, pgu, pbu, pau := src.At(kx, ky).RGBA()
+							pr += float64(float64(pru) * w)
+							pg += float64(float64(pgu) * w)
+							pb += float64(float64(pbu) * w)
+							pa += float64(float64(pau) * w)
+						}
+					}
+				}
+			}
+
+			if pr > pa {
+				pr = pa
+			}
+			if pg > pa {
+				pg = pa
+			}
+			if pb > pa {
+				pb = pa
+			}
+
+			pr0 := uint32(fffftou(pr))
+			pg0 := uint32(fffftou(pg))
+			pb0 := uint32(fffftou(pb))
+			dst.Pix[d] = uint8((19595*pr0 + 38470*pg0 + 7471*pb0 + 1<<15) >> 24)
 		}
 	}
 }
 
-func (ablInterpolator) transform_RGBA_RGBA_Over(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.RGBA, sr image.Rectangle, bias image.Point, opts *Options) {
+func (q *Kernel) transform_RGBA_Gray_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.Gray, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+	// When shrinking, broaden the effective kernel support so that we still
+	// visit every source pixel.
+	xHalfWidth, xKernelArgScale := q.Support, 1.0
+	if xscale > 1 {
+		xHalfWidth *= xscale
+		xKernelArgScale = 1 / xscale
+	}
+	yHalfWidth, yKernelArgScale := q.Support, 1.0
+	if yscale > 1 {
+		yHalfWidth *= yscale
+		yKernelArgScale = 1 / yscale
+	}
+
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
+
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
 		dyf := float64(dr.Min.Y+int(dy)) + 0.5
 		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
@@ -3815,96 +18896,96 @@ func (ablInterpolator) transform_RGBA_RGBA_Over(dst *image.RGBA, dr, adr image.R
 				continue
 			}
 
-			sx -= 0.5
-			sx0 := int(sx)
-			xFrac0 := sx - float64(sx0)
-			xFrac1 := 1 - xFrac0
-			sx0 += bias.X
-			sx1 := sx0 + 1
-			if sx0 < sr.Min.X {
-				sx0, sx1 = sr.Min.X, sr.Min.X
-				xFrac0, xFrac1 = 0, 1
-			} else if sx1 >= sr.Max.X {
-				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
-				xFrac0, xFrac1 = 1, 0
+			// TODO: adjust the bias so that we can use int(f) instead
+			// of math.Floor(f) and math.Ceil(f).
+			sx += float64(bias.X)
+			sx -= 0.5
+			ix := int(math.Floor(sx - xHalfWidth))
+			if ix < sr.Min.X {
+				ix = sr.Min.X
+			}
+			jx := int(math.Ceil(sx + xHalfWidth))
+			if jx > sr.Max.X {
+				jx = sr.Max.X
+			}
+
+			totalXWeight := 0.0
+			for kx := ix; kx < jx; kx++ {
+				xWeight := 0.0
+				if t := abs((sx - float64(kx)) * xKernelArgScale); t < q.Support {
+					xWeight = q.At(t)
+				}
+				xWeights[kx-ix] = xWeight
+				totalXWeight += xWeight
+			}
+			for x := range xWeights[:jx-ix] {
+				xWeights[x] /= totalXWeight
 			}
 
+			sy += float64(bias.Y)
 			sy -= 0.5
-			sy0 := int(sy)
-			yFrac0 := sy - float64(sy0)
-			yFrac1 := 1 - yFrac0
-			sy0 += bias.Y
-			sy1 := sy0 + 1
-			if sy0 < sr.Min.Y {
-				sy0, sy1 = sr.Min.Y, sr.Min.Y
-				yFrac0, yFrac1 = 0, 1
-			} else if sy1 >= sr.Max.Y {
-				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
-				yFrac0, yFrac1 = 1, 0
+			iy := int(math.Floor(sy - yHalfWidth))
+			if iy < sr.Min.Y {
+				iy = sr.Min.Y
+			}
+			jy := int(math.Ceil(sy + yHalfWidth))
+			if jy > sr.Max.Y {
+				jy = sr.Max.Y
 			}
 
-			s00i := (sy0-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*4
-			s00ru := uint32(src.Pix[s00i+0]) * 0x101
-			s00gu := uint32(src.Pix[s00i+1]) * 0x101
-			s00bu := uint32(src.Pix[s00i+2]) * 0x101
-			s00au := uint32(src.Pix[s00i+3]) * 0x101
-			s00r := float64(s00ru)
-			s00g := float64(s00gu)
-			s00b := float64(s00bu)
-			s00a := float64(s00au)
-			s10i := (sy0-src.Rect.Min.Y)*src.Stride + (sx1-src.Rect.Min.X)*4
-			s10ru := uint32(src.Pix[s10i+0]) * 0x101
-			s10gu := uint32(src.Pix[s10i+1]) * 0x101
-			s10bu := uint32(src.Pix[s10i+2]) * 0x101
-			s10au := uint32(src.Pix[s10i+3]) * 0x101
-			s10r := float64(s10ru)
-			s10g := float64(s10gu)
-			s10b := float64(s10bu)
-			s10a := float64(s10au)
-			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
-			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
-			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
-			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
-			s01i := (sy1-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*4
-			s01ru := uint32(src.Pix[s01i+0]) * 0x101
-			s01gu := uint32(src.Pix[s01i+1]) * 0x101
-			s01bu := uint32(src.Pix[s01i+2]) * 0x101
-			s01au := uint32(src.Pix[s01i+3]) * 0x101
-			s01r := float64(s01ru)
-			s01g := float64(s01gu)
-			s01b := float64(s01bu)
-			s01a := float64(s01au)
-			s11i := (sy1-src.Rect.Min.Y)*src.Stride + (sx1-src.Rect.Min.X)*4
-			s11ru := uint32(src.Pix[s11i+0]) * 0x101
-			s11gu := uint32(src.Pix[s11i+1]) * 0x101
-			s11bu := uint32(src.Pix[s11i+2]) * 0x101
-			s11au := uint32(src.Pix[s11i+3]) * 0x101
-			s11r := float64(s11ru)
-			s11g := float64(s11gu)
-			s11b := float64(s11bu)
-			s11a := float64(s11au)
-			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
-			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
-			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
-			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
-			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
-			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
-			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
-			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
-			pr := uint32(s11r)
-			pg := uint32(s11g)
-			pb := uint32(s11b)
-			pa := uint32(s11a)
-			pa1 := (0xffff - pa) * 0x101
-			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pr) >> 8)
-			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + pg) >> 8)
-			dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*pa1/0xffff + pb) >> 8)
-			dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*pa1/0xffff + pa) >> 8)
+			totalYWeight := 0.0
+			for ky := iy; ky < jy; ky++ {
+				yWeight := 0.0
+				if t := abs((sy - float64(ky)) * yKernelArgScale); t < q.Support {
+					yWeight = q.At(t)
+				}
+				yWeights[ky-iy] = yWeight
+				totalYWeight += yWeight
+			}
+			for y := range yWeights[:jy-iy] {
+				yWeights[y] /= totalYWeight
+			}
+
+			var pr float64
+			for ky := iy; ky < jy; ky++ {
+				if yWeight := yWeights[ky-iy]; yWeight != 0 {
+					for kx := ix; kx < jx; kx++ {
+						if w := xWeights[kx-ix] * yWeight; w != 0 {
+							pi := (ky-src.Rect.Min.Y)*src.Stride + (kx - src.Rect.Min.X)
+							pru := uint32(src.Pix[pi]) * 0x101
+							pr += float64(float64(pru) * w)
+						}
+					}
+				}
+			}
+			out := uint8(fffftou(pr) >> 8)
+			dst.Pix[d+0] = out
+			dst.Pix[d+1] = out
+			dst.Pix[d+2] = out
+			dst.Pix[d+3] = 0xff
 		}
 	}
 }
 
-func (ablInterpolator) transform_RGBA_RGBA_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.RGBA, sr image.Rectangle, bias image.Point, opts *Options) {
+func (q *Kernel) transform_RGBA_NRGBA_Over(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NRGBA, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+	// When shrinking, broaden the effective kernel support so that we still
+	// visit every source pixel.
+	xHalfWidth, xKernelArgScale := q.Support, 1.0
+	if xscale > 1 {
+		xHalfWidth *= xscale
+		xKernelArgScale = 1 / xscale
+	}
+	yHalfWidth, yKernelArgScale := q.Support, 1.0
+	if yscale > 1 {
+		yHalfWidth *= yscale
+		yKernelArgScale = 1 / yscale
+	}
+
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
+
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
 		dyf := float64(dr.Min.Y+int(dy)) + 0.5
 		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
@@ -3916,95 +18997,117 @@ func (ablInterpolator) transform_RGBA_RGBA_Src(dst *image.RGBA, dr, adr image.Re
 				continue
 			}
 
+			// TODO: adjust the bias so that we can use int(f) instead
+			// of math.Floor(f) and math.Ceil(f).
+			sx += float64(bias.X)
 			sx -= 0.5
-			sx0 := int(sx)
-			xFrac0 := sx - float64(sx0)
-			xFrac1 := 1 - xFrac0
-			sx0 += bias.X
-			sx1 := sx0 + 1
-			if sx0 < sr.Min.X {
-				sx0, sx1 = sr.Min.X, sr.Min.X
-				xFrac0, xFrac1 = 0, 1
-			} else if sx1 >= sr.Max.X {
-				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
-				xFrac0, xFrac1 = 1, 0
+			ix := int(math.Floor(sx - xHalfWidth))
+			if ix < sr.Min.X {
+				ix = sr.Min.X
+			}
+			jx := int(math.Ceil(sx + xHalfWidth))
+			if jx > sr.Max.X {
+				jx = sr.Max.X
 			}
 
-			sy -= 0.5
-			sy0 := int(sy)
-			yFrac0 := sy - float64(sy0)
-			yFrac1 := 1 - yFrac0
-			sy0 += bias.Y
-			sy1 := sy0 + 1
-			if sy0 < sr.Min.Y {
-				sy0, sy1 = sr.Min.Y, sr.Min.Y
-				yFrac0, yFrac1 = 0, 1
-			} else if sy1 >= sr.Max.Y {
-				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
-				yFrac0, yFrac1 = 1, 0
+			totalXWeight := 0.0
+			for kx := ix; kx < jx; kx++ {
+				xWeight := 0.0
+				if t := abs((sx - float64(kx)) * xKernelArgScale); t < q.Support {
+					xWeight = q.At(t)
+				}
+				xWeights[kx-ix] = xWeight
+				totalXWeight += xWeight
+			}
+			for x := range xWeights[:jx-ix] {
+				xWeights[x] /= totalXWeight
 			}
 
-			s00i := (sy0-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*4
-			s00ru := uint32(src.Pix[s00i+0]) * 0x101
-			s00gu := uint32(src.Pix[s00i+1]) * 0x101
-			s00bu := uint32(src.Pix[s00i+2]) * 0x101
-			s00au := uint32(src.Pix[s00i+3]) * 0x101
-			s00r := float64(s00ru)
-			s00g := float64(s00gu)
-			s00b := float64(s00bu)
-			s00a := float64(s00au)
-			s10i := (sy0-src.Rect.Min.Y)*src.Stride + (sx1-src.Rect.Min.X)*4
-			s10ru := uint32(src.Pix[s10i+0]) * 0x101
-			s10gu := uint32(src.Pix[s10i+1]) * 0x101
-			s10bu := uint32(src.Pix[s10i+2]) * 0x101
-			s10au := uint32(src.Pix[s10i+3]) * 0x101
-			s10r := float64(s10ru)
-			s10g := float64(s10gu)
-			s10b := float64(s10bu)
-			s10a := float64(s10au)
-			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
-			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
-			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
-			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
-			s01i := (sy1-src.Rect.Min.Y)*src.Stride + (sx0-src.Rect.Min.X)*4
-			s01ru := uint32(src.Pix[s01i+0]) * 0x101
-			s01gu := uint32(src.Pix[s01i+1]) * 0x101
-			s01bu := uint32(src.Pix[s01i+2]) * 0x101
-			s01au := uint32(src.Pix[s01i+3]) * 0x101
-			s01r := float64(s01ru)
-			s01g := float64(s01gu)
-			s01b := float64(s01bu)
-			s01a := float64(s01au)
-			s11i := (sy1-src.Rect.Min.Y)*src.Stride + (sx1-src.Rect.Min.X)*4
-			s11ru := uint32(src.Pix[s11i+0]) * 0x101
-			s11gu := uint32(src.Pix[s11i+1]) * 0x101
-			s11bu := uint32(src.Pix[s11i+2]) * 0x101
-			s11au := uint32(src.Pix[s11i+3]) * 0x101
-			s11r := float64(s11ru)
-			s11g := float64(s11gu)
-			s11b := float64(s11bu)
-			s11a := float64(s11au)
-			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
-			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
-			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
-			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
-			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
-			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
-			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
-			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
-			pr := uint32(s11r)
-			pg := uint32(s11g)
-			pb := uint32(s11b)
-			pa := uint32(s11a)
-			dst.Pix[d+0] = uint8(pr >> 8)
-			dst.Pix[d+1] = uint8(pg >> 8)
-			dst.Pix[d+2] = uint8(pb >> 8)
-			dst.Pix[d+3] = uint8(pa >> 8)
+			sy += float64(bias.Y)
+			sy -= 0.5
+			iy := int(math.Floor(sy - yHalfWidth))
+			if iy < sr.Min.Y {
+				iy = sr.Min.Y
+			}
+			jy := int(math.Ceil(sy + yHalfWidth))
+			if jy > sr.Max.Y {
+				jy = sr.Max.Y
+			}
+
+			totalYWeight := 0.0
+			for ky := iy; ky < jy; ky++ {
+				yWeight := 0.0
+				if t := abs((sy - float64(ky)) * yKernelArgScale); t < q.Support {
+					yWeight = q.At(t)
+				}
+				yWeights[ky-iy] = yWeight
+				totalYWeight += yWeight
+			}
+			for y := range yWeights[:jy-iy] {
+				yWeights[y] /= totalYWeight
+			}
+
+			var pr, pg, pb, pa float64
+			for ky := iy; ky < jy; ky++ {
+				if yWeight := yWeights[ky-iy]; yWeight != 0 {
+					for kx := ix; kx < jx; kx++ {
+						if w := xWeights[kx-ix] * yWeight; w != 0 {
+							pi := (ky-src.Rect.Min.Y)*src.Stride + (kx-src.Rect.Min.X)*4
+							pau := uint32(src.Pix[pi+3]) * 0x101
+							pru := uint32(src.Pix[pi+0]) * pau / 0xff
+							pgu := uint32(src.Pix[pi+1]) * pau / 0xff
+							pbu := uint32(src.Pix[pi+2]) * pau / 0xff
+							pr += float64(float64(pru) * w)
+							pg += float64(float64(pgu) * w)
+							pb += float64(float64(pbu) * w)
+							pa += float64(float64(pau) * w)
+						}
+					}
+				}
+			}
+
+			if pr > pa {
+				pr = pa
+			}
+			if pg > pa {
+				pg = pa
+			}
+			if pb > pa {
+				pb = pa
+			}
+
+			pr0 := uint32(fffftou(pr))
+			pg0 := uint32(fffftou(pg))
+			pb0 := uint32(fffftou(pb))
+			pa0 := uint32(fffftou(pa))
+			pa1 := (0xffff - uint32(pa0)) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pr0) >> 8)
+			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + pg0) >> 8)
+			dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*pa1/0xffff + pb0) >> 8)
+			dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*pa1/0xffff + pa0) >> 8)
 		}
 	}
 }
 
-func (ablInterpolator) transform_RGBA_YCbCr444_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.YCbCr, sr image.Rectangle, bias image.Point, opts *Options) {
+func (q *Kernel) transform_RGBA_NRGBA_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NRGBA, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+	// When shrinking, broaden the effective kernel support so that we still
+	// visit every source pixel.
+	xHalfWidth, xKernelArgScale := q.Support, 1.0
+	if xscale > 1 {
+		xHalfWidth *= xscale
+		xKernelArgScale = 1 / xscale
+	}
+	yHalfWidth, yKernelArgScale := q.Support, 1.0
+	if yscale > 1 {
+		yHalfWidth *= yscale
+		yKernelArgScale = 1 / yscale
+	}
+
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
+
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
 		dyf := float64(dr.Min.Y+int(dy)) + 0.5
 		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
@@ -4016,171 +19119,112 @@ func (ablInterpolator) transform_RGBA_YCbCr444_Src(dst *image.RGBA, dr, adr imag
 				continue
 			}
 
+			// TODO: adjust the bias so that we can use int(f) instead
+			// of math.Floor(f) and math.Ceil(f).
+			sx += float64(bias.X)
 			sx -= 0.5
-			sx0 := int(sx)
-			xFrac0 := sx - float64(sx0)
-			xFrac1 := 1 - xFrac0
-			sx0 += bias.X
-			sx1 := sx0 + 1
-			if sx0 < sr.Min.X {
-				sx0, sx1 = sr.Min.X, sr.Min.X
-				xFrac0, xFrac1 = 0, 1
-			} else if sx1 >= sr.Max.X {
-				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
-				xFrac0, xFrac1 = 1, 0
+			ix := int(math.Floor(sx - xHalfWidth))
+			if ix < sr.Min.X {
+				ix = sr.Min.X
 			}
-
-			sy -= 0.5
-			sy0 := int(sy)
-			yFrac0 := sy - float64(sy0)
-			yFrac1 := 1 - yFrac0
-			sy0 += bias.Y
-			sy1 := sy0 + 1
-			if sy0 < sr.Min.Y {
-				sy0, sy1 = sr.Min.Y, sr.Min.Y
-				yFrac0, yFrac1 = 0, 1
-			} else if sy1 >= sr.Max.Y {
-				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
-				yFrac0, yFrac1 = 1, 0
+			jx := int(math.Ceil(sx + xHalfWidth))
+			if jx > sr.Max.X {
+				jx = sr.Max.X
 			}
 
-			s00i := (sy0-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
-			s00j := (sy0-src.Rect.Min.Y)*src.CStride + (sx0 - src.Rect.Min.X)
-
-			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
-			s00yy1 := int(src.Y[s00i]) * 0x10101
-			s00cb1 := int(src.Cb[s00j]) - 128
-			s00cr1 := int(src.Cr[s00j]) - 128
-			s00ru := (s00yy1 + 91881*s00cr1) >> 8
-			s00gu := (s00yy1 - 22554*s00cb1 - 46802*s00cr1) >> 8
-			s00bu := (s00yy1 + 116130*s00cb1) >> 8
-			if s00ru < 0 {
-				s00ru = 0
-			} else if s00ru > 0xffff {
-				s00ru = 0xffff
-			}
-			if s00gu < 0 {
-				s00gu = 0
-			} else if s00gu > 0xffff {
-				s00gu = 0xffff
+			totalXWeight := 0.0
+			for kx := ix; kx < jx; kx++ {
+				xWeight := 0.0
+				if t := abs((sx - float64(kx)) * xKernelArgScale); t < q.Support {
+					xWeight = q.At(t)
+				}
+				xWeights[kx-ix] = xWeight
+				totalXWeight += xWeight
 			}
-			if s00bu < 0 {
-				s00bu = 0
-			} else if s00bu > 0xffff {
-				s00bu = 0xffff
+			for x := range xWeights[:jx-ix] {
+				xWeights[x] /= totalXWeight
 			}
 
-			s00r := float64(s00ru)
-			s00g := float64(s00gu)
-			s00b := float64(s00bu)
-			s10i := (sy0-src.Rect.Min.Y)*src.YStride + (sx1 - src.Rect.Min.X)
-			s10j := (sy0-src.Rect.Min.Y)*src.CStride + (sx1 - src.Rect.Min.X)
-
-			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
-			s10yy1 := int(src.Y[s10i]) * 0x10101
-			s10cb1 := int(src.Cb[s10j]) - 128
-			s10cr1 := int(src.Cr[s10j]) - 128
-			s10ru := (s10yy1 + 91881*s10cr1) >> 8
-			s10gu := (s10yy1 - 22554*s10cb1 - 46802*s10cr1) >> 8
-			s10bu := (s10yy1 + 116130*s10cb1) >> 8
-			if s10ru < 0 {
-				s10ru = 0
-			} else if s10ru > 0xffff {
-				s10ru = 0xffff
-			}
-			if s10gu < 0 {
-				s10gu = 0
-			} else if s10gu > 0xffff {
-				s10gu = 0xffff
+			sy += float64(bias.Y)
+			sy -= 0.5
+			iy := int(math.Floor(sy - yHalfWidth))
+			if iy < sr.Min.Y {
+				iy = sr.Min.Y
 			}
-			if s10bu < 0 {
-				s10bu = 0
-			} else if s10bu > 0xffff {
-				s10bu = 0xffff
+			jy := int(math.Ceil(sy + yHalfWidth))
+			if jy > sr.Max.Y {
+				jy = sr.Max.Y
 			}
 
-			s10r := float64(s10ru)
-			s10g := float64(s10gu)
-			s10b := float64(s10bu)
-			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
-			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
-			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
-			s01i := (sy1-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
-			s01j := (sy1-src.Rect.Min.Y)*src.CStride + (sx0 - src.Rect.Min.X)
-
-			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
-			s01yy1 := int(src.Y[s01i]) * 0x10101
-			s01cb1 := int(src.Cb[s01j]) - 128
-			s01cr1 := int(src.Cr[s01j]) - 128
-			s01ru := (s01yy1 + 91881*s01cr1) >> 8
-			s01gu := (s01yy1 - 22554*s01cb1 - 46802*s01cr1) >> 8
-			s01bu := (s01yy1 + 116130*s01cb1) >> 8
-			if s01ru < 0 {
-				s01ru = 0
-			} else if s01ru > 0xffff {
-				s01ru = 0xffff
-			}
-			if s01gu < 0 {
-				s01gu = 0
-			} else if s01gu > 0xffff {
-				s01gu = 0xffff
+			totalYWeight := 0.0
+			for ky := iy; ky < jy; ky++ {
+				yWeight := 0.0
+				if t := abs((sy - float64(ky)) * yKernelArgScale); t < q.Support {
+					yWeight = q.At(t)
+				}
+				yWeights[ky-iy] = yWeight
+				totalYWeight += yWeight
 			}
-			if s01bu < 0 {
-				s01bu = 0
-			} else if s01bu > 0xffff {
-				s01bu = 0xffff
+			for y := range yWeights[:jy-iy] {
+				yWeights[y] /= totalYWeight
 			}
 
-			s01r := float64(s01ru)
-			s01g := float64(s01gu)
-			s01b := float64(s01bu)
-			s11i := (sy1-src.Rect.Min.Y)*src.YStride + (sx1 - src.Rect.Min.X)
-			s11j := (sy1-src.Rect.Min.Y)*src.CStride + (sx1 - src.Rect.Min.X)
-
-			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
-			s11yy1 := int(src.Y[s11i]) * 0x10101
-			s11cb1 := int(src.Cb[s11j]) - 128
-			s11cr1 := int(src.Cr[s11j]) - 128
-			s11ru := (s11yy1 + 91881*s11cr1) >> 8
-			s11gu := (s11yy1 - 22554*s11cb1 - 46802*s11cr1) >> 8
-			s11bu := (s11yy1 + 116130*s11cb1) >> 8
-			if s11ru < 0 {
-				s11ru = 0
-			} else if s11ru > 0xffff {
-				s11ru = 0xffff
+			var pr, pg, pb, pa float64
+			for ky := iy; ky < jy; ky++ {
+				if yWeight := yWeights[ky-iy]; yWeight != 0 {
+					for kx := ix; kx < jx; kx++ {
+						if w := xWeights[kx-ix] * yWeight; w != 0 {
+							pi := (ky-src.Rect.Min.Y)*src.Stride + (kx-src.Rect.Min.X)*4
+							pau := uint32(src.Pix[pi+3]) * 0x101
+							pru := uint32(src.Pix[pi+0]) * pau / 0xff
+							pgu := uint32(src.Pix[pi+1]) * pau / 0xff
+							pbu := uint32(src.Pix[pi+2]) * pau / 0xff
+							pr += float64(float64(pru) * w)
+							pg += float64(float64(pgu) * w)
+							pb += float64(float64(pbu) * w)
+							pa += float64(float64(pau) * w)
+						}
+					}
+				}
 			}
-			if s11gu < 0 {
-				s11gu = 0
-			} else if s11gu > 0xffff {
-				s11gu = 0xffff
+
+			if pr > pa {
+				pr = pa
 			}
-			if s11bu < 0 {
-				s11bu = 0
-			} else if s11bu > 0xffff {
-				s11bu = 0xffff
+			if pg > pa {
+				pg = pa
+			}
+			if pb > pa {
+				pb = pa
 			}
 
-			s11r := float64(s11ru)
-			s11g := float64(s11gu)
-			s11b := float64(s11bu)
-			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
-			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
-			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
-			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
-			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
-			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
-			pr := uint32(s11r)
-			pg := uint32(s11g)
-			pb := uint32(s11b)
-			dst.Pix[d+0] = uint8(pr >> 8)
-			dst.Pix[d+1] = uint8(pg >> 8)
-			dst.Pix[d+2] = uint8(pb >> 8)
-			dst.Pix[d+3] = 0xff
+			dst.Pix[d+0] = uint8(fffftou(pr) >> 8)
+			dst.Pix[d+1] = uint8(fffftou(pg) >> 8)
+			dst.Pix[d+2] = uint8(fffftou(pb) >> 8)
+			dst.Pix[d+3] = uint8(fffftou(pa) >> 8)
 		}
 	}
 }
 
-func (ablInterpolator) transform_RGBA_YCbCr422_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.YCbCr, sr image.Rectangle, bias image.Point, opts *Options) {
+func (q *Kernel) transform_RGBA_RGBA_Over(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.RGBA, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+	// When shrinking, broaden the effective kernel support so that we still
+	// visit every source pixel.
+	xHalfWidth, xKernelArgScale := q.Support, 1.0
+	if xscale > 1 {
+		xHalfWidth *= xscale
+		xKernelArgScale = 1 / xscale
+	}
+	yHalfWidth, yKernelArgScale := q.Support, 1.0
+	if yscale > 1 {
+		yHalfWidth *= yscale
+		yKernelArgScale = 1 / yscale
+	}
+
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
+
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
 		dyf := float64(dr.Min.Y+int(dy)) + 0.5
 		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
@@ -4192,171 +19236,117 @@ func (ablInterpolator) transform_RGBA_YCbCr422_Src(dst *image.RGBA, dr, adr imag
 				continue
 			}
 
+			// TODO: adjust the bias so that we can use int(f) instead
+			// of math.Floor(f) and math.Ceil(f).
+			sx += float64(bias.X)
 			sx -= 0.5
-			sx0 := int(sx)
-			xFrac0 := sx - float64(sx0)
-			xFrac1 := 1 - xFrac0
-			sx0 += bias.X
-			sx1 := sx0 + 1
-			if sx0 < sr.Min.X {
-				sx0, sx1 = sr.Min.X, sr.Min.X
-				xFrac0, xFrac1 = 0, 1
-			} else if sx1 >= sr.Max.X {
-				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
-				xFrac0, xFrac1 = 1, 0
+			ix := int(math.Floor(sx - xHalfWidth))
+			if ix < sr.Min.X {
+				ix = sr.Min.X
 			}
-
-			sy -= 0.5
-			sy0 := int(sy)
-			yFrac0 := sy - float64(sy0)
-			yFrac1 := 1 - yFrac0
-			sy0 += bias.Y
-			sy1 := sy0 + 1
-			if sy0 < sr.Min.Y {
-				sy0, sy1 = sr.Min.Y, sr.Min.Y
-				yFrac0, yFrac1 = 0, 1
-			} else if sy1 >= sr.Max.Y {
-				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
-				yFrac0, yFrac1 = 1, 0
+			jx := int(math.Ceil(sx + xHalfWidth))
+			if jx > sr.Max.X {
+				jx = sr.Max.X
 			}
 
-			s00i := (sy0-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
-			s00j := (sy0-src.Rect.Min.Y)*src.CStride + ((sx0)/2 - src.Rect.Min.X/2)
-
-			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
-			s00yy1 := int(src.Y[s00i]) * 0x10101
-			s00cb1 := int(src.Cb[s00j]) - 128
-			s00cr1 := int(src.Cr[s00j]) - 128
-			s00ru := (s00yy1 + 91881*s00cr1) >> 8
-			s00gu := (s00yy1 - 22554*s00cb1 - 46802*s00cr1) >> 8
-			s00bu := (s00yy1 + 116130*s00cb1) >> 8
-			if s00ru < 0 {
-				s00ru = 0
-			} else if s00ru > 0xffff {
-				s00ru = 0xffff
-			}
-			if s00gu < 0 {
-				s00gu = 0
-			} else if s00gu > 0xffff {
-				s00gu = 0xffff
+			totalXWeight := 0.0
+			for kx := ix; kx < jx; kx++ {
+				xWeight := 0.0
+				if t := abs((sx - float64(kx)) * xKernelArgScale); t < q.Support {
+					xWeight = q.At(t)
+				}
+				xWeights[kx-ix] = xWeight
+				totalXWeight += xWeight
 			}
-			if s00bu < 0 {
-				s00bu = 0
-			} else if s00bu > 0xffff {
-				s00bu = 0xffff
+			for x := range xWeights[:jx-ix] {
+				xWeights[x] /= totalXWeight
 			}
 
-			s00r := float64(s00ru)
-			s00g := float64(s00gu)
-			s00b := float64(s00bu)
-			s10i := (sy0-src.Rect.Min.Y)*src.YStride + (sx1 - src.Rect.Min.X)
-			s10j := (sy0-src.Rect.Min.Y)*src.CStride + ((sx1)/2 - src.Rect.Min.X/2)
-
-			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
-			s10yy1 := int(src.Y[s10i]) * 0x10101
-			s10cb1 := int(src.Cb[s10j]) - 128
-			s10cr1 := int(src.Cr[s10j]) - 128
-			s10ru := (s10yy1 + 91881*s10cr1) >> 8
-			s10gu := (s10yy1 - 22554*s10cb1 - 46802*s10cr1) >> 8
-			s10bu := (s10yy1 + 116130*s10cb1) >> 8
-			if s10ru < 0 {
-				s10ru = 0
-			} else if s10ru > 0xffff {
-				s10ru = 0xffff
-			}
-			if s10gu < 0 {
-				s10gu = 0
-			} else if s10gu > 0xffff {
-				s10gu = 0xffff
+			sy += float64(bias.Y)
+			sy -= 0.5
+			iy := int(math.Floor(sy - yHalfWidth))
+			if iy < sr.Min.Y {
+				iy = sr.Min.Y
 			}
-			if s10bu < 0 {
-				s10bu = 0
-			} else if s10bu > 0xffff {
-				s10bu = 0xffff
+			jy := int(math.Ceil(sy + yHalfWidth))
+			if jy > sr.Max.Y {
+				jy = sr.Max.Y
 			}
 
-			s10r := float64(s10ru)
-			s10g := float64(s10gu)
-			s10b := float64(s10bu)
-			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
-			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
-			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
-			s01i := (sy1-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
-			s01j := (sy1-src.Rect.Min.Y)*src.CStride + ((sx0)/2 - src.Rect.Min.X/2)
-
-			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
-			s01yy1 := int(src.Y[s01i]) * 0x10101
-			s01cb1 := int(src.Cb[s01j]) - 128
-			s01cr1 := int(src.Cr[s01j]) - 128
-			s01ru := (s01yy1 + 91881*s01cr1) >> 8
-			s01gu := (s01yy1 - 22554*s01cb1 - 46802*s01cr1) >> 8
-			s01bu := (s01yy1 + 116130*s01cb1) >> 8
-			if s01ru < 0 {
-				s01ru = 0
-			} else if s01ru > 0xffff {
-				s01ru = 0xffff
-			}
-			if s01gu < 0 {
-				s01gu = 0
-			} else if s01gu > 0xffff {
-				s01gu = 0xffff
+			totalYWeight := 0.0
+			for ky := iy; ky < jy; ky++ {
+				yWeight := 0.0
+				if t := abs((sy - float64(ky)) * yKernelArgScale); t < q.Support {
+					yWeight = q.At(t)
+				}
+				yWeights[ky-iy] = yWeight
+				totalYWeight += yWeight
 			}
-			if s01bu < 0 {
-				s01bu = 0
-			} else if s01bu > 0xffff {
-				s01bu = 0xffff
+			for y := range yWeights[:jy-iy] {
+				yWeights[y] /= totalYWeight
 			}
 
-			s01r := float64(s01ru)
-			s01g := float64(s01gu)
-			s01b := float64(s01bu)
-			s11i := (sy1-src.Rect.Min.Y)*src.YStride + (sx1 - src.Rect.Min.X)
-			s11j := (sy1-src.Rect.Min.Y)*src.CStride + ((sx1)/2 - src.Rect.Min.X/2)
+			var pr, pg, pb, pa float64
+			for ky := iy; ky < jy; ky++ {
+				if yWeight := yWeights[ky-iy]; yWeight != 0 {
+					for kx := ix; kx < jx; kx++ {
+						if w := xWeights[kx-ix] * yWeight; w != 0 {
+							pi := (ky-src.Rect.Min.Y)*src.Stride + (kx-src.Rect.Min.X)*4
+							pru := uint32(src.Pix[pi+0]) * 0x101
+							pgu := uint32(src.Pix[pi+1]) * 0x101
+							pbu := uint32(src.Pix[pi+2]) * 0x101
+							pau := uint32(src.Pix[pi+3]) * 0x101
+							pr += float64(float64(pru) * w)
+							pg += float64(float64(pgu) * w)
+							pb += float64(float64(pbu) * w)
+							pa += float64(float64(pau) * w)
+						}
+					}
+				}
+			}
 
-			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
-			s11yy1 := int(src.Y[s11i]) * 0x10101
-			s11cb1 := int(src.Cb[s11j]) - 128
-			s11cr1 := int(src.Cr[s11j]) - 128
-			s11ru := (s11yy1 + 91881*s11cr1) >> 8
-			s11gu := (s11yy1 - 22554*s11cb1 - 46802*s11cr1) >> 8
-			s11bu := (s11yy1 + 116130*s11cb1) >> 8
-			if s11ru < 0 {
-				s11ru = 0
-			} else if s11ru > 0xffff {
-				s11ru = 0xffff
+			if pr > pa {
+				pr = pa
 			}
-			if s11gu < 0 {
-				s11gu = 0
-			} else if s11gu > 0xffff {
-				s11gu = 0xffff
+			if pg > pa {
+				pg = pa
 			}
-			if s11bu < 0 {
-				s11bu = 0
-			} else if s11bu > 0xffff {
-				s11bu = 0xffff
+			if pb > pa {
+				pb = pa
 			}
 
-			s11r := float64(s11ru)
-			s11g := float64(s11gu)
-			s11b := float64(s11bu)
-			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
-			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
-			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
-			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
-			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
-			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
-			pr := uint32(s11r)
-			pg := uint32(s11g)
-			pb := uint32(s11b)
-			dst.Pix[d+0] = uint8(pr >> 8)
-			dst.Pix[d+1] = uint8(pg >> 8)
-			dst.Pix[d+2] = uint8(pb >> 8)
-			dst.Pix[d+3] = 0xff
+			pr0 := uint32(fffftou(pr))
+			pg0 := uint32(fffftou(pg))
+			pb0 := uint32(fffftou(pb))
+			pa0 := uint32(fffftou(pa))
+			pa1 := (0xffff - uint32(pa0)) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pr0) >> 8)
+			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + pg0) >> 8)
+			dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*pa1/0xffff + pb0) >> 8)
+			dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*pa1/0xffff + pa0) >> 8)
 		}
 	}
-}
+}
+
+func (q *Kernel) transform_RGBA_RGBA_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.RGBA, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+	// When shrinking, broaden the effective kernel support so that we still
+	// visit every source pixel.
+	xHalfWidth, xKernelArgScale := q.Support, 1.0
+	if xscale > 1 {
+		xHalfWidth *= xscale
+		xKernelArgScale = 1 / xscale
+	}
+	yHalfWidth, yKernelArgScale := q.Support, 1.0
+	if yscale > 1 {
+		yHalfWidth *= yscale
+		yKernelArgScale = 1 / yscale
+	}
+
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
 
-func (ablInterpolator) transform_RGBA_YCbCr420_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.YCbCr, sr image.Rectangle, bias image.Point, opts *Options) {
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
 		dyf := float64(dr.Min.Y+int(dy)) + 0.5
 		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
@@ -4368,171 +19358,238 @@ func (ablInterpolator) transform_RGBA_YCbCr420_Src(dst *image.RGBA, dr, adr imag
 				continue
 			}
 
+			// TODO: adjust the bias so that we can use int(f) instead
+			// of math.Floor(f) and math.Ceil(f).
+			sx += float64(bias.X)
 			sx -= 0.5
-			sx0 := int(sx)
-			xFrac0 := sx - float64(sx0)
-			xFrac1 := 1 - xFrac0
-			sx0 += bias.X
-			sx1 := sx0 + 1
-			if sx0 < sr.Min.X {
-				sx0, sx1 = sr.Min.X, sr.Min.X
-				xFrac0, xFrac1 = 0, 1
-			} else if sx1 >= sr.Max.X {
-				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
-				xFrac0, xFrac1 = 1, 0
+			ix := int(math.Floor(sx - xHalfWidth))
+			if ix < sr.Min.X {
+				ix = sr.Min.X
 			}
-
-			sy -= 0.5
-			sy0 := int(sy)
-			yFrac0 := sy - float64(sy0)
-			yFrac1 := 1 - yFrac0
-			sy0 += bias.Y
-			sy1 := sy0 + 1
-			if sy0 < sr.Min.Y {
-				sy0, sy1 = sr.Min.Y, sr.Min.Y
-				yFrac0, yFrac1 = 0, 1
-			} else if sy1 >= sr.Max.Y {
-				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
-				yFrac0, yFrac1 = 1, 0
+			jx := int(math.Ceil(sx + xHalfWidth))
+			if jx > sr.Max.X {
+				jx = sr.Max.X
 			}
 
-			s00i := (sy0-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
-			s00j := ((sy0)/2-src.Rect.Min.Y/2)*src.CStride + ((sx0)/2 - src.Rect.Min.X/2)
+			totalXWeight := 0.0
+			for kx := ix; kx < jx; kx++ {
+				xWeight := 0.0
+				if t := abs((sx - float64(kx)) * xKernelArgScale); t < q.Support {
+					xWeight = q.At(t)
+				}
+				xWeights[kx-ix] = xWeight
+				totalXWeight += xWeight
+			}
+			for x := range xWeights[:jx-ix] {
+				xWeights[x] /= totalXWeight
+			}
 
-			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
-			s00yy1 := int(src.Y[s00i]) * 0x10101
-			s00cb1 := int(src.Cb[s00j]) - 128
-			s00cr1 := int(src.Cr[s00j]) - 128
-			s00ru := (s00yy1 + 91881*s00cr1) >> 8
-			s00gu := (s00yy1 - 22554*s00cb1 - 46802*s00cr1) >> 8
-			s00bu := (s00yy1 + 116130*s00cb1) >> 8
-			if s00ru < 0 {
-				s00ru = 0
-			} else if s00ru > 0xffff {
-				s00ru = 0xffff
+			sy += float64(bias.Y)
+			sy -= 0.5
+			iy := int(math.Floor(sy - yHalfWidth))
+			if iy < sr.Min.Y {
+				iy = sr.Min.Y
 			}
-			if s00gu < 0 {
-				s00gu = 0
-			} else if s00gu > 0xffff {
-				s00gu = 0xffff
+			jy := int(math.Ceil(sy + yHalfWidth))
+			if jy > sr.Max.Y {
+				jy = sr.Max.Y
 			}
-			if s00bu < 0 {
-				s00bu = 0
-			} else if s00bu > 0xffff {
-				s00bu = 0xffff
+
+			totalYWeight := 0.0
+			for ky := iy; ky < jy; ky++ {
+				yWeight := 0.0
+				if t := abs((sy - float64(ky)) * yKernelArgScale); t < q.Support {
+					yWeight = q.At(t)
+				}
+				yWeights[ky-iy] = yWeight
+				totalYWeight += yWeight
+			}
+			for y := range yWeights[:jy-iy] {
+				yWeights[y] /= totalYWeight
 			}
 
-			s00r := float64(s00ru)
-			s00g := float64(s00gu)
-			s00b := float64(s00bu)
-			s10i := (sy0-src.Rect.Min.Y)*src.YStride + (sx1 - src.Rect.Min.X)
-			s10j := ((sy0)/2-src.Rect.Min.Y/2)*src.CStride + ((sx1)/2 - src.Rect.Min.X/2)
+			var pr, pg, pb, pa float64
+			for ky := iy; ky < jy; ky++ {
+				if yWeight := yWeights[ky-iy]; yWeight != 0 {
+					for kx := ix; kx < jx; kx++ {
+						if w := xWeights[kx-ix] * yWeight; w != 0 {
+							pi := (ky-src.Rect.Min.Y)*src.Stride + (kx-src.Rect.Min.X)*4
+							pru := uint32(src.Pix[pi+0]) * 0x101
+							pgu := uint32(src.Pix[pi+1]) * 0x101
+							pbu := uint32(src.Pix[pi+2]) * 0x101
+							pau := uint32(src.Pix[pi+3]) * 0x101
+							pr += float64(float64(pru) * w)
+							pg += float64(float64(pgu) * w)
+							pb += float64(float64(pbu) * w)
+							pa += float64(float64(pau) * w)
+						}
+					}
+				}
+			}
 
-			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
-			s10yy1 := int(src.Y[s10i]) * 0x10101
-			s10cb1 := int(src.Cb[s10j]) - 128
-			s10cr1 := int(src.Cr[s10j]) - 128
-			s10ru := (s10yy1 + 91881*s10cr1) >> 8
-			s10gu := (s10yy1 - 22554*s10cb1 - 46802*s10cr1) >> 8
-			s10bu := (s10yy1 + 116130*s10cb1) >> 8
-			if s10ru < 0 {
-				s10ru = 0
-			} else if s10ru > 0xffff {
-				s10ru = 0xffff
+			if pr > pa {
+				pr = pa
 			}
-			if s10gu < 0 {
-				s10gu = 0
-			} else if s10gu > 0xffff {
-				s10gu = 0xffff
+			if pg > pa {
+				pg = pa
 			}
-			if s10bu < 0 {
-				s10bu = 0
-			} else if s10bu > 0xffff {
-				s10bu = 0xffff
+			if pb > pa {
+				pb = pa
 			}
 
-			s10r := float64(s10ru)
-			s10g := float64(s10gu)
-			s10b := float64(s10bu)
-			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
-			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
-			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
-			s01i := (sy1-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
-			s01j := ((sy1)/2-src.Rect.Min.Y/2)*src.CStride + ((sx0)/2 - src.Rect.Min.X/2)
+			dst.Pix[d+0] = uint8(fffftou(pr) >> 8)
+			dst.Pix[d+1] = uint8(fffftou(pg) >> 8)
+			dst.Pix[d+2] = uint8(fffftou(pb) >> 8)
+			dst.Pix[d+3] = uint8(fffftou(pa) >> 8)
+		}
+	}
+}
 
-			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
-			s01yy1 := int(src.Y[s01i]) * 0x10101
-			s01cb1 := int(src.Cb[s01j]) - 128
-			s01cr1 := int(src.Cr[s01j]) - 128
-			s01ru := (s01yy1 + 91881*s01cr1) >> 8
-			s01gu := (s01yy1 - 22554*s01cb1 - 46802*s01cr1) >> 8
-			s01bu := (s01yy1 + 116130*s01cb1) >> 8
-			if s01ru < 0 {
-				s01ru = 0
-			} else if s01ru > 0xffff {
-				s01ru = 0xffff
+func (q *Kernel) transform_RGBA_YCbCr444_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.YCbCr, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+	// When shrinking, broaden the effective kernel support so that we still
+	// visit every source pixel.
+	xHalfWidth, xKernelArgScale := q.Support, 1.0
+	if xscale > 1 {
+		xHalfWidth *= xscale
+		xKernelArgScale = 1 / xscale
+	}
+	yHalfWidth, yKernelArgScale := q.Support, 1.0
+	if yscale > 1 {
+		yHalfWidth *= yscale
+		yKernelArgScale = 1 / yscale
+	}
+
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
 			}
-			if s01gu < 0 {
-				s01gu = 0
-			} else if s01gu > 0xffff {
-				s01gu = 0xffff
+
+			// TODO: adjust the bias so that we can use int(f) instead
+			// of math.Floor(f) and math.Ceil(f).
+			sx += float64(bias.X)
+			sx -= 0.5
+			ix := int(math.Floor(sx - xHalfWidth))
+			if ix < sr.Min.X {
+				ix = sr.Min.X
 			}
-			if s01bu < 0 {
-				s01bu = 0
-			} else if s01bu > 0xffff {
-				s01bu = 0xffff
+			jx := int(math.Ceil(sx + xHalfWidth))
+			if jx > sr.Max.X {
+				jx = sr.Max.X
 			}
 
-			s01r := float64(s01ru)
-			s01g := float64(s01gu)
-			s01b := float64(s01bu)
-			s11i := (sy1-src.Rect.Min.Y)*src.YStride + (sx1 - src.Rect.Min.X)
-			s11j := ((sy1)/2-src.Rect.Min.Y/2)*src.CStride + ((sx1)/2 - src.Rect.Min.X/2)
+			totalXWeight := 0.0
+			for kx := ix; kx < jx; kx++ {
+				xWeight := 0.0
+				if t := abs((sx - float64(kx)) * xKernelArgScale); t < q.Support {
+					xWeight = q.At(t)
+				}
+				xWeights[kx-ix] = xWeight
+				totalXWeight += xWeight
+			}
+			for x := range xWeights[:jx-ix] {
+				xWeights[x] /= totalXWeight
+			}
 
-			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
-			s11yy1 := int(src.Y[s11i]) * 0x10101
-			s11cb1 := int(src.Cb[s11j]) - 128
-			s11cr1 := int(src.Cr[s11j]) - 128
-			s11ru := (s11yy1 + 91881*s11cr1) >> 8
-			s11gu := (s11yy1 - 22554*s11cb1 - 46802*s11cr1) >> 8
-			s11bu := (s11yy1 + 116130*s11cb1) >> 8
-			if s11ru < 0 {
-				s11ru = 0
-			} else if s11ru > 0xffff {
-				s11ru = 0xffff
+			sy += float64(bias.Y)
+			sy -= 0.5
+			iy := int(math.Floor(sy - yHalfWidth))
+			if iy < sr.Min.Y {
+				iy = sr.Min.Y
 			}
-			if s11gu < 0 {
-				s11gu = 0
-			} else if s11gu > 0xffff {
-				s11gu = 0xffff
+			jy := int(math.Ceil(sy + yHalfWidth))
+			if jy > sr.Max.Y {
+				jy = sr.Max.Y
 			}
-			if s11bu < 0 {
-				s11bu = 0
-			} else if s11bu > 0xffff {
-				s11bu = 0xffff
+
+			totalYWeight := 0.0
+			for ky := iy; ky < jy; ky++ {
+				yWeight := 0.0
+				if t := abs((sy - float64(ky)) * yKernelArgScale); t < q.Support {
+					yWeight = q.At(t)
+				}
+				yWeights[ky-iy] = yWeight
+				totalYWeight += yWeight
+			}
+			for y := range yWeights[:jy-iy] {
+				yWeights[y] /= totalYWeight
 			}
 
-			s11r := float64(s11ru)
-			s11g := float64(s11gu)
-			s11b := float64(s11bu)
-			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
-			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
-			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
-			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
-			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
-			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
-			pr := uint32(s11r)
-			pg := uint32(s11g)
-			pb := uint32(s11b)
-			dst.Pix[d+0] = uint8(pr >> 8)
-			dst.Pix[d+1] = uint8(pg >> 8)
-			dst.Pix[d+2] = uint8(pb >> 8)
+			var pr, pg, pb float64
+			for ky := iy; ky < jy; ky++ {
+				if yWeight := yWeights[ky-iy]; yWeight != 0 {
+					for kx := ix; kx < jx; kx++ {
+						if w := xWeights[kx-ix] * yWeight; w != 0 {
+							pi := (ky-src.Rect.Min.Y)*src.YStride + (kx - src.Rect.Min.X)
+							pj := (ky-src.Rect.Min.Y)*src.CStride + (kx - src.Rect.Min.X)
+
+							// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+							pyy1 := int(src.Y[pi]) * 0x10101
+							pcb1 := int(src.Cb[pj]) - 128
+							pcr1 := int(src.Cr[pj]) - 128
+							pru := (pyy1 + 91881*pcr1) >> 8
+							pgu := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
+							pbu := (pyy1 + 116130*pcb1) >> 8
+							if pru < 0 {
+								pru = 0
+							} else if pru > 0xffff {
+								pru = 0xffff
+							}
+							if pgu < 0 {
+								pgu = 0
+							} else if pgu > 0xffff {
+								pgu = 0xffff
+							}
+							if pbu < 0 {
+								pbu = 0
+							} else if pbu > 0xffff {
+								pbu = 0xffff
+							}
+
+							pr += float64(float64(pru) * w)
+							pg += float64(float64(pgu) * w)
+							pb += float64(float64(pbu) * w)
+						}
+					}
+				}
+			}
+			dst.Pix[d+0] = uint8(fffftou(pr) >> 8)
+			dst.Pix[d+1] = uint8(fffftou(pg) >> 8)
+			dst.Pix[d+2] = uint8(fffftou(pb) >> 8)
 			dst.Pix[d+3] = 0xff
 		}
 	}
 }
 
-func (ablInterpolator) transform_RGBA_YCbCr440_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.YCbCr, sr image.Rectangle, bias image.Point, opts *Options) {
+func (q *Kernel) transform_RGBA_YCbCr422_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.YCbCr, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+	// When shrinking, broaden the effective kernel support so that we still
+	// visit every source pixel.
+	xHalfWidth, xKernelArgScale := q.Support, 1.0
+	if xscale > 1 {
+		xHalfWidth *= xscale
+		xKernelArgScale = 1 / xscale
+	}
+	yHalfWidth, yKernelArgScale := q.Support, 1.0
+	if yscale > 1 {
+		yHalfWidth *= yscale
+		yKernelArgScale = 1 / yscale
+	}
+
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
+
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
 		dyf := float64(dr.Min.Y+int(dy)) + 0.5
 		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
@@ -4544,171 +19601,121 @@ func (ablInterpolator) transform_RGBA_YCbCr440_Src(dst *image.RGBA, dr, adr imag
 				continue
 			}
 
+			// TODO: adjust the bias so that we can use int(f) instead
+			// of math.Floor(f) and math.Ceil(f).
+			sx += float64(bias.X)
 			sx -= 0.5
-			sx0 := int(sx)
-			xFrac0 := sx - float64(sx0)
-			xFrac1 := 1 - xFrac0
-			sx0 += bias.X
-			sx1 := sx0 + 1
-			if sx0 < sr.Min.X {
-				sx0, sx1 = sr.Min.X, sr.Min.X
-				xFrac0, xFrac1 = 0, 1
-			} else if sx1 >= sr.Max.X {
-				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
-				xFrac0, xFrac1 = 1, 0
+			ix := int(math.Floor(sx - xHalfWidth))
+			if ix < sr.Min.X {
+				ix = sr.Min.X
 			}
-
-			sy -= 0.5
-			sy0 := int(sy)
-			yFrac0 := sy - float64(sy0)
-			yFrac1 := 1 - yFrac0
-			sy0 += bias.Y
-			sy1 := sy0 + 1
-			if sy0 < sr.Min.Y {
-				sy0, sy1 = sr.Min.Y, sr.Min.Y
-				yFrac0, yFrac1 = 0, 1
-			} else if sy1 >= sr.Max.Y {
-				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
-				yFrac0, yFrac1 = 1, 0
+			jx := int(math.Ceil(sx + xHalfWidth))
+			if jx > sr.Max.X {
+				jx = sr.Max.X
 			}
 
-			s00i := (sy0-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
-			s00j := ((sy0)/2-src.Rect.Min.Y/2)*src.CStride + (sx0 - src.Rect.Min.X)
-
-			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
-			s00yy1 := int(src.Y[s00i]) * 0x10101
-			s00cb1 := int(src.Cb[s00j]) - 128
-			s00cr1 := int(src.Cr[s00j]) - 128
-			s00ru := (s00yy1 + 91881*s00cr1) >> 8
-			s00gu := (s00yy1 - 22554*s00cb1 - 46802*s00cr1) >> 8
-			s00bu := (s00yy1 + 116130*s00cb1) >> 8
-			if s00ru < 0 {
-				s00ru = 0
-			} else if s00ru > 0xffff {
-				s00ru = 0xffff
-			}
-			if s00gu < 0 {
-				s00gu = 0
-			} else if s00gu > 0xffff {
-				s00gu = 0xffff
+			totalXWeight := 0.0
+			for kx := ix; kx < jx; kx++ {
+				xWeight := 0.0
+				if t := abs((sx - float64(kx)) * xKernelArgScale); t < q.Support {
+					xWeight = q.At(t)
+				}
+				xWeights[kx-ix] = xWeight
+				totalXWeight += xWeight
 			}
-			if s00bu < 0 {
-				s00bu = 0
-			} else if s00bu > 0xffff {
-				s00bu = 0xffff
+			for x := range xWeights[:jx-ix] {
+				xWeights[x] /= totalXWeight
 			}
 
-			s00r := float64(s00ru)
-			s00g := float64(s00gu)
-			s00b := float64(s00bu)
-			s10i := (sy0-src.Rect.Min.Y)*src.YStride + (sx1 - src.Rect.Min.X)
-			s10j := ((sy0)/2-src.Rect.Min.Y/2)*src.CStride + (sx1 - src.Rect.Min.X)
-
-			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
-			s10yy1 := int(src.Y[s10i]) * 0x10101
-			s10cb1 := int(src.Cb[s10j]) - 128
-			s10cr1 := int(src.Cr[s10j]) - 128
-			s10ru := (s10yy1 + 91881*s10cr1) >> 8
-			s10gu := (s10yy1 - 22554*s10cb1 - 46802*s10cr1) >> 8
-			s10bu := (s10yy1 + 116130*s10cb1) >> 8
-			if s10ru < 0 {
-				s10ru = 0
-			} else if s10ru > 0xffff {
-				s10ru = 0xffff
-			}
-			if s10gu < 0 {
-				s10gu = 0
-			} else if s10gu > 0xffff {
-				s10gu = 0xffff
+			sy += float64(bias.Y)
+			sy -= 0.5
+			iy := int(math.Floor(sy - yHalfWidth))
+			if iy < sr.Min.Y {
+				iy = sr.Min.Y
 			}
-			if s10bu < 0 {
-				s10bu = 0
-			} else if s10bu > 0xffff {
-				s10bu = 0xffff
+			jy := int(math.Ceil(sy + yHalfWidth))
+			if jy > sr.Max.Y {
+				jy = sr.Max.Y
 			}
 
-			s10r := float64(s10ru)
-			s10g := float64(s10gu)
-			s10b := float64(s10bu)
-			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
-			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
-			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
-			s01i := (sy1-src.Rect.Min.Y)*src.YStride + (sx0 - src.Rect.Min.X)
-			s01j := ((sy1)/2-src.Rect.Min.Y/2)*src.CStride + (sx0 - src.Rect.Min.X)
-
-			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
-			s01yy1 := int(src.Y[s01i]) * 0x10101
-			s01cb1 := int(src.Cb[s01j]) - 128
-			s01cr1 := int(src.Cr[s01j]) - 128
-			s01ru := (s01yy1 + 91881*s01cr1) >> 8
-			s01gu := (s01yy1 - 22554*s01cb1 - 46802*s01cr1) >> 8
-			s01bu := (s01yy1 + 116130*s01cb1) >> 8
-			if s01ru < 0 {
-				s01ru = 0
-			} else if s01ru > 0xffff {
-				s01ru = 0xffff
-			}
-			if s01gu < 0 {
-				s01gu = 0
-			} else if s01gu > 0xffff {
-				s01gu = 0xffff
+			totalYWeight := 0.0
+			for ky := iy; ky < jy; ky++ {
+				yWeight := 0.0
+				if t := abs((sy - float64(ky)) * yKernelArgScale); t < q.Support {
+					yWeight = q.At(t)
+				}
+				yWeights[ky-iy] = yWeight
+				totalYWeight += yWeight
 			}
-			if s01bu < 0 {
-				s01bu = 0
-			} else if s01bu > 0xffff {
-				s01bu = 0xffff
+			for y := range yWeights[:jy-iy] {
+				yWeights[y] /= totalYWeight
 			}
 
-			s01r := float64(s01ru)
-			s01g := float64(s01gu)
-			s01b := float64(s01bu)
-			s11i := (sy1-src.Rect.Min.Y)*src.YStride + (sx1 - src.Rect.Min.X)
-			s11j := ((sy1)/2-src.Rect.Min.Y/2)*src.CStride + (sx1 - src.Rect.Min.X)
+			var pr, pg, pb float64
+			for ky := iy; ky < jy; ky++ {
+				if yWeight := yWeights[ky-iy]; yWeight != 0 {
+					for kx := ix; kx < jx; kx++ {
+						if w := xWeights[kx-ix] * yWeight; w != 0 {
+							pi := (ky-src.Rect.Min.Y)*src.YStride + (kx - src.Rect.Min.X)
+							pj := (ky-src.Rect.Min.Y)*src.CStride + ((kx)/2 - src.Rect.Min.X/2)
+
+							// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+							pyy1 := int(src.Y[pi]) * 0x10101
+							pcb1 := int(src.Cb[pj]) - 128
+							pcr1 := int(src.Cr[pj]) - 128
+							pru := (pyy1 + 91881*pcr1) >> 8
+							pgu := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
+							pbu := (pyy1 + 116130*pcb1) >> 8
+							if pru < 0 {
+								pru = 0
+							} else if pru > 0xffff {
+								pru = 0xffff
+							}
+							if pgu < 0 {
+								pgu = 0
+							} else if pgu > 0xffff {
+								pgu = 0xffff
+							}
+							if pbu < 0 {
+								pbu = 0
+							} else if pbu > 0xffff {
+								pbu = 0xffff
+							}
 
-			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
-			s11yy1 := int(src.Y[s11i]) * 0x10101
-			s11cb1 := int(src.Cb[s11j]) - 128
-			s11cr1 := int(src.Cr[s11j]) - 128
-			s11ru := (s11yy1 + 91881*s11cr1) >> 8
-			s11gu := (s11yy1 - 22554*s11cb1 - 46802*s11cr1) >> 8
-			s11bu := (s11yy1 + 116130*s11cb1) >> 8
-			if s11ru < 0 {
-				s11ru = 0
-			} else if s11ru > 0xffff {
-				s11ru = 0xffff
-			}
-			if s11gu < 0 {
-				s11gu = 0
-			} else if s11gu > 0xffff {
-				s11gu = 0xffff
-			}
-			if s11bu < 0 {
-				s11bu = 0
-			} else if s11bu > 0xffff {
-				s11bu = 0xffff
+							pr += float64(float64(pru) * w)
+							pg += float64(float64(pgu) * w)
+							pb += float64(float64(pbu) * w)
+						}
+					}
+				}
 			}
-
-			s11r := float64(s11ru)
-			s11g := float64(s11gu)
-			s11b := float64(s11bu)
-			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
-			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
-			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
-			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
-			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
-			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
-			pr := uint32(s11r)
-			pg := uint32(s11g)
-			pb := uint32(s11b)
-			dst.Pix[d+0] = uint8(pr >> 8)
-			dst.Pix[d+1] = uint8(pg >> 8)
-			dst.Pix[d+2] = uint8(pb >> 8)
+			dst.Pix[d+0] = uint8(fffftou(pr) >> 8)
+			dst.Pix[d+1] = uint8(fffftou(pg) >> 8)
+			dst.Pix[d+2] = uint8(fffftou(pb) >> 8)
 			dst.Pix[d+3] = 0xff
 		}
 	}
 }
 
-func (ablInterpolator) transform_RGBA_RGBA64Image_Over(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src image.RGBA64Image, sr image.Rectangle, bias image.Point, opts *Options) {
+func (q *Kernel) transform_RGBA_YCbCr420_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.YCbCr, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+	// When shrinking, broaden the effective kernel support so that we still
+	// visit every source pixel.
+	xHalfWidth, xKernelArgScale := q.Support, 1.0
+	if xscale > 1 {
+		xHalfWidth *= xscale
+		xKernelArgScale = 1 / xscale
+	}
+	yHalfWidth, yKernelArgScale := q.Support, 1.0
+	if yscale > 1 {
+		yHalfWidth *= yscale
+		yKernelArgScale = 1 / yscale
+	}
+
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
+
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
 		dyf := float64(dr.Min.Y+int(dy)) + 0.5
 		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
@@ -4720,77 +19727,121 @@ func (ablInterpolator) transform_RGBA_RGBA64Image_Over(dst *image.RGBA, dr, adr
 				continue
 			}
 
+			// TODO: adjust the bias so that we can use int(f) instead
+			// of math.Floor(f) and math.Ceil(f).
+			sx += float64(bias.X)
 			sx -= 0.5
-			sx0 := int(sx)
-			xFrac0 := sx - float64(sx0)
-			xFrac1 := 1 - xFrac0
-			sx0 += bias.X
-			sx1 := sx0 + 1
-			if sx0 < sr.Min.X {
-				sx0, sx1 = sr.Min.X, sr.Min.X
-				xFrac0, xFrac1 = 0, 1
-			} else if sx1 >= sr.Max.X {
-				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
-				xFrac0, xFrac1 = 1, 0
+			ix := int(math.Floor(sx - xHalfWidth))
+			if ix < sr.Min.X {
+				ix = sr.Min.X
+			}
+			jx := int(math.Ceil(sx + xHalfWidth))
+			if jx > sr.Max.X {
+				jx = sr.Max.X
+			}
+
+			totalXWeight := 0.0
+			for kx := ix; kx < jx; kx++ {
+				xWeight := 0.0
+				if t := abs((sx - float64(kx)) * xKernelArgScale); t < q.Support {
+					xWeight = q.At(t)
+				}
+				xWeights[kx-ix] = xWeight
+				totalXWeight += xWeight
+			}
+			for x := range xWeights[:jx-ix] {
+				xWeights[x] /= totalXWeight
 			}
 
+			sy += float64(bias.Y)
 			sy -= 0.5
-			sy0 := int(sy)
-			yFrac0 := sy - float64(sy0)
-			yFrac1 := 1 - yFrac0
-			sy0 += bias.Y
-			sy1 := sy0 + 1
-			if sy0 < sr.Min.Y {
-				sy0, sy1 = sr.Min.Y, sr.Min.Y
-				yFrac0, yFrac1 = 0, 1
-			} else if sy1 >= sr.Max.Y {
-				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
-				yFrac0, yFrac1 = 1, 0
+			iy := int(math.Floor(sy - yHalfWidth))
+			if iy < sr.Min.Y {
+				iy = sr.Min.Y
+			}
+			jy := int(math.Ceil(sy + yHalfWidth))
+			if jy > sr.Max.Y {
+				jy = sr.Max.Y
 			}
 
-			s00u := src.RGBA64At(sx0, sy0)
-			s00r := float64(s00u.R)
-			s00g := float64(s00u.G)
-			s00b := float64(s00u.B)
-			s00a := float64(s00u.A)
-			s10u := src.RGBA64At(sx1, sy0)
-			s10r := float64(s10u.R)
-			s10g := float64(s10u.G)
-			s10b := float64(s10u.B)
-			s10a := float64(s10u.A)
-			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
-			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
-			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
-			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
-			s01u := src.RGBA64At(sx0, sy1)
-			s01r := float64(s01u.R)
-			s01g := float64(s01u.G)
-			s01b := float64(s01u.B)
-			s01a := float64(s01u.A)
-			s11u := src.RGBA64At(sx1, sy1)
-			s11r := float64(s11u.R)
-			s11g := float64(s11u.G)
-			s11b := float64(s11u.B)
-			s11a := float64(s11u.A)
-			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
-			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
-			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
-			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
-			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
-			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
-			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
-			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
-			p := color.RGBA64{uint16(s11r), uint16(s11g), uint16(s11b), uint16(s11a)}
-			pa1 := (0xffff - uint32(p.A)) * 0x101
-			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + uint32(p.R)) >> 8)
-			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + uint32(p.G)) >> 8)
-			dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*pa1/0xffff + uint32(p.B)) >> 8)
-			dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*pa1/0xffff + uint32(p.A)) >> 8)
+			totalYWeight := 0.0
+			for ky := iy; ky < jy; ky++ {
+				yWeight := 0.0
+				if t := abs((sy - float64(ky)) * yKernelArgScale); t < q.Support {
+					yWeight = q.At(t)
+				}
+				yWeights[ky-iy] = yWeight
+				totalYWeight += yWeight
+			}
+			for y := range yWeights[:jy-iy] {
+				yWeights[y] /= totalYWeight
+			}
+
+			var pr, pg, pb float64
+			for ky := iy; ky < jy; ky++ {
+				if yWeight := yWeights[ky-iy]; yWeight != 0 {
+					for kx := ix; kx < jx; kx++ {
+						if w := xWeights[kx-ix] * yWeight; w != 0 {
+							pi := (ky-src.Rect.Min.Y)*src.YStride + (kx - src.Rect.Min.X)
+							pj := ((ky)/2-src.Rect.Min.Y/2)*src.CStride + ((kx)/2 - src.Rect.Min.X/2)
+
+							// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+							pyy1 := int(src.Y[pi]) * 0x10101
+							pcb1 := int(src.Cb[pj]) - 128
+							pcr1 := int(src.Cr[pj]) - 128
+							pru := (pyy1 + 91881*pcr1) >> 8
+							pgu := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
+							pbu := (pyy1 + 116130*pcb1) >> 8
+							if pru < 0 {
+								pru = 0
+							} else if pru > 0xffff {
+								pru = 0xffff
+							}
+							if pgu < 0 {
+								pgu = 0
+							} else if pgu > 0xffff {
+								pgu = 0xffff
+							}
+							if pbu < 0 {
+								pbu = 0
+							} else if pbu > 0xffff {
+								pbu = 0xffff
+							}
+
+							pr += float64(float64(pru) * w)
+							pg += float64(float64(pgu) * w)
+							pb += float64(float64(pbu) * w)
+						}
+					}
+				}
+			}
+			dst.Pix[d+0] = uint8(fffftou(pr) >> 8)
+			dst.Pix[d+1] = uint8(fffftou(pg) >> 8)
+			dst.Pix[d+2] = uint8(fffftou(pb) >> 8)
+			dst.Pix[d+3] = 0xff
 		}
 	}
 }
 
-func (ablInterpolator) transform_RGBA_RGBA64Image_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src image.RGBA64Image, sr image.Rectangle, bias image.Point, opts *Options) {
+func (q *Kernel) transform_RGBA_YCbCr440_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.YCbCr, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+	// When shrinking, broaden the effective kernel support so that we still
+	// visit every source pixel.
+	xHalfWidth, xKernelArgScale := q.Support, 1.0
+	if xscale > 1 {
+		xHalfWidth *= xscale
+		xKernelArgScale = 1 / xscale
+	}
+	yHalfWidth, yKernelArgScale := q.Support, 1.0
+	if yscale > 1 {
+		yHalfWidth *= yscale
+		yKernelArgScale = 1 / yscale
+	}
+
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
+
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
 		dyf := float64(dr.Min.Y+int(dy)) + 0.5
 		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
@@ -4802,76 +19853,121 @@ func (ablInterpolator) transform_RGBA_RGBA64Image_Src(dst *image.RGBA, dr, adr i
 				continue
 			}
 
+			// TODO: adjust the bias so that we can use int(f) instead
+			// of math.Floor(f) and math.Ceil(f).
+			sx += float64(bias.X)
 			sx -= 0.5
-			sx0 := int(sx)
-			xFrac0 := sx - float64(sx0)
-			xFrac1 := 1 - xFrac0
-			sx0 += bias.X
-			sx1 := sx0 + 1
-			if sx0 < sr.Min.X {
-				sx0, sx1 = sr.Min.X, sr.Min.X
-				xFrac0, xFrac1 = 0, 1
-			} else if sx1 >= sr.Max.X {
-				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
-				xFrac0, xFrac1 = 1, 0
+			ix := int(math.Floor(sx - xHalfWidth))
+			if ix < sr.Min.X {
+				ix = sr.Min.X
+			}
+			jx := int(math.Ceil(sx + xHalfWidth))
+			if jx > sr.Max.X {
+				jx = sr.Max.X
+			}
+
+			totalXWeight := 0.0
+			for kx := ix; kx < jx; kx++ {
+				xWeight := 0.0
+				if t := abs((sx - float64(kx)) * xKernelArgScale); t < q.Support {
+					xWeight = q.At(t)
+				}
+				xWeights[kx-ix] = xWeight
+				totalXWeight += xWeight
+			}
+			for x := range xWeights[:jx-ix] {
+				xWeights[x] /= totalXWeight
 			}
 
+			sy += float64(bias.Y)
 			sy -= 0.5
-			sy0 := int(sy)
-			yFrac0 := sy - float64(sy0)
-			yFrac1 := 1 - yFrac0
-			sy0 += bias.Y
-			sy1 := sy0 + 1
-			if sy0 < sr.Min.Y {
-				sy0, sy1 = sr.Min.Y, sr.Min.Y
-				yFrac0, yFrac1 = 0, 1
-			} else if sy1 >= sr.Max.Y {
-				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
-				yFrac0, yFrac1 = 1, 0
+			iy := int(math.Floor(sy - yHalfWidth))
+			if iy < sr.Min.Y {
+				iy = sr.Min.Y
+			}
+			jy := int(math.Ceil(sy + yHalfWidth))
+			if jy > sr.Max.Y {
+				jy = sr.Max.Y
 			}
 
-			s00u := src.RGBA64At(sx0, sy0)
-			s00r := float64(s00u.R)
-			s00g := float64(s00u.G)
-			s00b := float64(s00u.B)
-			s00a := float64(s00u.A)
-			s10u := src.RGBA64At(sx1, sy0)
-			s10r := float64(s10u.R)
-			s10g := float64(s10u.G)
-			s10b := float64(s10u.B)
-			s10a := float64(s10u.A)
-			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
-			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
-			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
-			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
-			s01u := src.RGBA64At(sx0, sy1)
-			s01r := float64(s01u.R)
-			s01g := float64(s01u.G)
-			s01b := float64(s01u.B)
-			s01a := float64(s01u.A)
-			s11u := src.RGBA64At(sx1, sy1)
-			s11r := float64(s11u.R)
-			s11g := float64(s11u.G)
-			s11b := float64(s11u.B)
-			s11a := float64(s11u.A)
-			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
-			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
-			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
-			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
-			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
-			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
-			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
-			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
-			p := color.RGBA64{uint16(s11r), uint16(s11g), uint16(s11b), uint16(s11a)}
-			dst.Pix[d+0] = uint8(p.R >> 8)
-			dst.Pix[d+1] = uint8(p.G >> 8)
-			dst.Pix[d+2] = uint8(p.B >> 8)
-			dst.Pix[d+3] = uint8(p.A >> 8)
+			totalYWeight := 0.0
+			for ky := iy; ky < jy; ky++ {
+				yWeight := 0.0
+				if t := abs((sy - float64(ky)) * yKernelArgScale); t < q.Support {
+					yWeight = q.At(t)
+				}
+				yWeights[ky-iy] = yWeight
+				totalYWeight += yWeight
+			}
+			for y := range yWeights[:jy-iy] {
+				yWeights[y] /= totalYWeight
+			}
+
+			var pr, pg, pb float64
+			for ky := iy; ky < jy; ky++ {
+				if yWeight := yWeights[ky-iy]; yWeight != 0 {
+					for kx := ix; kx < jx; kx++ {
+						if w := xWeights[kx-ix] * yWeight; w != 0 {
+							pi := (ky-src.Rect.Min.Y)*src.YStride + (kx - src.Rect.Min.X)
+							pj := ((ky)/2-src.Rect.Min.Y/2)*src.CStride + (kx - src.Rect.Min.X)
+
+							// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+							pyy1 := int(src.Y[pi]) * 0x10101
+							pcb1 := int(src.Cb[pj]) - 128
+							pcr1 := int(src.Cr[pj]) - 128
+							pru := (pyy1 + 91881*pcr1) >> 8
+							pgu := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
+							pbu := (pyy1 + 116130*pcb1) >> 8
+							if pru < 0 {
+								pru = 0
+							} else if pru > 0xffff {
+								pru = 0xffff
+							}
+							if pgu < 0 {
+								pgu = 0
+							} else if pgu > 0xffff {
+								pgu = 0xffff
+							}
+							if pbu < 0 {
+								pbu = 0
+							} else if pbu > 0xffff {
+								pbu = 0xffff
+							}
+
+							pr += float64(float64(pru) * w)
+							pg += float64(float64(pgu) * w)
+							pb += float64(float64(pbu) * w)
+						}
+					}
+				}
+			}
+			dst.Pix[d+0] = uint8(fffftou(pr) >> 8)
+			dst.Pix[d+1] = uint8(fffftou(pg) >> 8)
+			dst.Pix[d+2] = uint8(fffftou(pb) >> 8)
+			dst.Pix[d+3] = 0xff
 		}
 	}
 }
 
-func (ablInterpolator) transform_RGBA_Image_Over(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src image.Image, sr image.Rectangle, bias image.Point, opts *Options) {
+func (q *Kernel) transform_RGBA_NYCbCrA444_Over(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NYCbCrA, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+	// When shrinking, broaden the effective kernel support so that we still
+	// visit every source pixel.
+	xHalfWidth, xKernelArgScale := q.Support, 1.0
+	if xscale > 1 {
+		xHalfWidth *= xscale
+		xKernelArgScale = 1 / xscale
+	}
+	yHalfWidth, yKernelArgScale := q.Support, 1.0
+	if yscale > 1 {
+		yHalfWidth *= yscale
+		yKernelArgScale = 1 / yscale
+	}
+
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
+
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
 		dyf := float64(dr.Min.Y+int(dy)) + 0.5
 		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
@@ -4883,80 +19979,143 @@ func (ablInterpolator) transform_RGBA_Image_Over(dst *image.RGBA, dr, adr image.
 				continue
 			}
 
+			// TODO: adjust the bias so that we can use int(f) instead
+			// of math.Floor(f) and math.Ceil(f).
+			sx += float64(bias.X)
 			sx -= 0.5
-			sx0 := int(sx)
-			xFrac0 := sx - float64(sx0)
-			xFrac1 := 1 - xFrac0
-			sx0 += bias.X
-			sx1 := sx0 + 1
-			if sx0 < sr.Min.X {
-				sx0, sx1 = sr.Min.X, sr.Min.X
-				xFrac0, xFrac1 = 0, 1
-			} else if sx1 >= sr.Max.X {
-				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
-				xFrac0, xFrac1 = 1, 0
+			ix := int(math.Floor(sx - xHalfWidth))
+			if ix < sr.Min.X {
+				ix = sr.Min.X
+			}
+			jx := int(math.Ceil(sx + xHalfWidth))
+			if jx > sr.Max.X {
+				jx = sr.Max.X
+			}
+
+			totalXWeight := 0.0
+			for kx := ix; kx < jx; kx++ {
+				xWeight := 0.0
+				if t := abs((sx - float64(kx)) * xKernelArgScale); t < q.Support {
+					xWeight = q.At(t)
+				}
+				xWeights[kx-ix] = xWeight
+				totalXWeight += xWeight
+			}
+			for x := range xWeights[:jx-ix] {
+				xWeights[x] /= totalXWeight
 			}
 
+			sy += float64(bias.Y)
 			sy -= 0.5
-			sy0 := int(sy)
-			yFrac0 := sy - float64(sy0)
-			yFrac1 := 1 - yFrac0
-			sy0 += bias.Y
-			sy1 := sy0 + 1
-			if sy0 < sr.Min.Y {
-				sy0, sy1 = sr.Min.Y, sr.Min.Y
-				yFrac0, yFrac1 = 0, 1
-			} else if sy1 >= sr.Max.Y {
-				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
-				yFrac0, yFrac1 = 1, 0
+			iy := int(math.Floor(sy - yHalfWidth))
+			if iy < sr.Min.Y {
+				iy = sr.Min.Y
+			}
+			jy := int(math.Ceil(sy + yHalfWidth))
+			if jy > sr.Max.Y {
+				jy = sr.Max.Y
 			}
 
-			s00ru, s00gu, s00bu, s00au := src.At(sx0, sy0).RGBA()
-			s00r := float64(s00ru)
-			s00g := float64(s00gu)
-			s00b := float64(s00bu)
-			s00a := float64(s00au)
-			s10ru, s10gu, s10bu, s10au := src.At(sx1, sy0).RGBA()
-			s10r := float64(s10ru)
-			s10g := float64(s10gu)
-			s10b := float64(s10bu)
-			s10a := float64(s10au)
-			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
-			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
-			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
-			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
-			s01ru, s01gu, s01bu, s01au := src.At(sx0, sy1).RGBA()
-			s01r := float64(s01ru)
-			s01g := float64(s01gu)
-			s01b := float64(s01bu)
-			s01a := float64(s01au)
-			s11ru, s11gu, s11bu, s11au := src.At(sx1, sy1).RGBA()
-			s11r := float64(s11ru)
-			s11g := float64(s11gu)
-			s11b := float64(s11bu)
-			s11a := float64(s11au)
-			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
-			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
-			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
-			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
-			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
-			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
-			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
-			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
-			pr := uint32(s11r)
-			pg := uint32(s11g)
-			pb := uint32(s11b)
-			pa := uint32(s11a)
-			pa1 := (0xffff - pa) * 0x101
-			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pr) >> 8)
-			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + pg) >> 8)
-			dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*pa1/0xffff + pb) >> 8)
-			dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*pa1/0xffff + pa) >> 8)
+			totalYWeight := 0.0
+			for ky := iy; ky < jy; ky++ {
+				yWeight := 0.0
+				if t := abs((sy - float64(ky)) * yKernelArgScale); t < q.Support {
+					yWeight = q.At(t)
+				}
+				yWeights[ky-iy] = yWeight
+				totalYWeight += yWeight
+			}
+			for y := range yWeights[:jy-iy] {
+				yWeights[y] /= totalYWeight
+			}
+
+			var pr, pg, pb, pa float64
+			for ky := iy; ky < jy; ky++ {
+				if yWeight := yWeights[ky-iy]; yWeight != 0 {
+					for kx := ix; kx < jx; kx++ {
+						if w := xWeights[kx-ix] * yWeight; w != 0 {
+							pi := (ky-src.Rect.Min.Y)*src.YStride + (kx - src.Rect.Min.X)
+							pj := (ky-src.Rect.Min.Y)*src.CStride + (kx - src.Rect.Min.X)
+							pk := (ky-src.Rect.Min.Y)*src.AStride + (kx - src.Rect.Min.X)
+
+							// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+							pyy1 := int(src.Y[pi]) * 0x10101
+							pcb1 := int(src.Cb[pj]) - 128
+							pcr1 := int(src.Cr[pj]) - 128
+							pru0 := (pyy1 + 91881*pcr1) >> 8
+							pgu0 := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
+							pbu0 := (pyy1 + 116130*pcb1) >> 8
+							if pru0 < 0 {
+								pru0 = 0
+							} else if pru0 > 0xffff {
+								pru0 = 0xffff
+							}
+							if pgu0 < 0 {
+								pgu0 = 0
+							} else if pgu0 > 0xffff {
+								pgu0 = 0xffff
+							}
+							if pbu0 < 0 {
+								pbu0 = 0
+							} else if pbu0 > 0xffff {
+								pbu0 = 0xffff
+							}
+
+							pau := uint32(src.A[pk]) * 0x101
+							pru := uint32(pru0) * pau / 0xffff
+							pgu := uint32(pgu0) * pau / 0xffff
+							pbu := uint32(pbu0) * pau / 0xffff
+							pr += float64(float64(pru) * w)
+							pg += float64(float64(pgu) * w)
+							pb += float64(float64(pbu) * w)
+							pa += float64(float64(pau) * w)
+						}
+					}
+				}
+			}
+
+			if pr > pa {
+				pr = pa
+			}
+			if pg > pa {
+				pg = pa
+			}
+			if pb > pa {
+				pb = pa
+			}
+
+			pr0 := uint32(fffftou(pr))
+			pg0 := uint32(fffftou(pg))
+			pb0 := uint32(fffftou(pb))
+			pa0 := uint32(fffftou(pa))
+			pa1 := (0xffff - uint32(pa0)) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pr0) >> 8)
+			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + pg0) >> 8)
+			dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*pa1/0xffff + pb0) >> 8)
+			dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*pa1/0xffff + pa0) >> 8)
 		}
 	}
 }
 
-func (ablInterpolator) transform_RGBA_Image_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src image.Image, sr image.Rectangle, bias image.Point, opts *Options) {
+func (q *Kernel) transform_RGBA_NYCbCrA422_Over(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NYCbCrA, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+	// When shrinking, broaden the effective kernel support so that we still
+	// visit every source pixel.
+	xHalfWidth, xKernelArgScale := q.Support, 1.0
+	if xscale > 1 {
+		xHalfWidth *= xscale
+		xKernelArgScale = 1 / xscale
+	}
+	yHalfWidth, yKernelArgScale := q.Support, 1.0
+	if yscale > 1 {
+		yHalfWidth *= yscale
+		yKernelArgScale = 1 / yscale
+	}
+
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
+
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
 		dyf := float64(dr.Min.Y+int(dy)) + 0.5
 		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
@@ -4968,86 +20127,147 @@ func (ablInterpolator) transform_RGBA_Image_Src(dst *image.RGBA, dr, adr image.R
 				continue
 			}
 
+			// TODO: adjust the bias so that we can use int(f) instead
+			// of math.Floor(f) and math.Ceil(f).
+			sx += float64(bias.X)
 			sx -= 0.5
-			sx0 := int(sx)
-			xFrac0 := sx - float64(sx0)
-			xFrac1 := 1 - xFrac0
-			sx0 += bias.X
-			sx1 := sx0 + 1
-			if sx0 < sr.Min.X {
-				sx0, sx1 = sr.Min.X, sr.Min.X
-				xFrac0, xFrac1 = 0, 1
-			} else if sx1 >= sr.Max.X {
-				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
-				xFrac0, xFrac1 = 1, 0
+			ix := int(math.Floor(sx - xHalfWidth))
+			if ix < sr.Min.X {
+				ix = sr.Min.X
+			}
+			jx := int(math.Ceil(sx + xHalfWidth))
+			if jx > sr.Max.X {
+				jx = sr.Max.X
+			}
+
+			totalXWeight := 0.0
+			for kx := ix; kx < jx; kx++ {
+				xWeight := 0.0
+				if t := abs((sx - float64(kx)) * xKernelArgScale); t < q.Support {
+					xWeight = q.At(t)
+				}
+				xWeights[kx-ix] = xWeight
+				totalXWeight += xWeight
+			}
+			for x := range xWeights[:jx-ix] {
+				xWeights[x] /= totalXWeight
 			}
 
+			sy += float64(bias.Y)
 			sy -= 0.5
-			sy0 := int(sy)
-			yFrac0 := sy - float64(sy0)
-			yFrac1 := 1 - yFrac0
-			sy0 += bias.Y
-			sy1 := sy0 + 1
-			if sy0 < sr.Min.Y {
-				sy0, sy1 = sr.Min.Y, sr.Min.Y
-				yFrac0, yFrac1 = 0, 1
-			} else if sy1 >= sr.Max.Y {
-				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
-				yFrac0, yFrac1 = 1, 0
+			iy := int(math.Floor(sy - yHalfWidth))
+			if iy < sr.Min.Y {
+				iy = sr.Min.Y
+			}
+			jy := int(math.Ceil(sy + yHalfWidth))
+			if jy > sr.Max.Y {
+				jy = sr.Max.Y
 			}
 
-			s00ru, s00gu, s00bu, s00au := src.At(sx0, sy0).RGBA()
-			s00r := float64(s00ru)
-			s00g := float64(s00gu)
-			s00b := float64(s00bu)
-			s00a := float64(s00au)
-			s10ru, s10gu, s10bu, s10au := src.At(sx1, sy0).RGBA()
-			s10r := float64(s10ru)
-			s10g := float64(s10gu)
-			s10b := float64(s10bu)
-			s10a := float64(s10au)
-			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
-			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
-			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
-			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
-			s01ru, s01gu, s01bu, s01au := src.At(sx0, sy1).RGBA()
-			s01r := float64(s01ru)
-			s01g := float64(s01gu)
-			s01b := float64(s01bu)
-			s01a := float64(s01au)
-			s11ru, s11gu, s11bu, s11au := src.At(sx1, sy1).RGBA()
-			s11r := float64(s11ru)
-			s11g := float64(s11gu)
-			s11b := float64(s11bu)
-			s11a := float64(s11au)
-			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
-			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
-			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
-			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
-			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
-			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
-			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
-			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
-			pr := uint32(s11r)
-			pg := uint32(s11g)
-			pb := uint32(s11b)
-			pa := uint32(s11a)
-			dst.Pix[d+0] = uint8(pr >> 8)
-			dst.Pix[d+1] = uint8(pg >> 8)
-			dst.Pix[d+2] = uint8(pb >> 8)
-			dst.Pix[d+3] = uint8(pa >> 8)
+			totalYWeight := 0.0
+			for ky := iy; ky < jy; ky++ {
+				yWeight := 0.0
+				if t := abs((sy - float64(ky)) * yKernelArgScale); t < q.Support {
+					yWeight = q.At(t)
+				}
+				yWeights[ky-iy] = yWeight
+				totalYWeight += yWeight
+			}
+			for y := range yWeights[:jy-iy] {
+				yWeights[y] /= totalYWeight
+			}
+
+			var pr, pg, pb, pa float64
+			for ky := iy; ky < jy; ky++ {
+				if yWeight := yWeights[ky-iy]; yWeight != 0 {
+					for kx := ix; kx < jx; kx++ {
+						if w := xWeights[kx-ix] * yWeight; w != 0 {
+							pi := (ky-src.Rect.Min.Y)*src.YStride + (kx - src.Rect.Min.X)
+							pj := (ky-src.Rect.Min.Y)*src.CStride + ((kx)/2 - src.Rect.Min.X/2)
+							pk := (ky-src.Rect.Min.Y)*src.AStride + (kx - src.Rect.Min.X)
+
+							// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+							pyy1 := int(src.Y[pi]) * 0x10101
+							pcb1 := int(src.Cb[pj]) - 128
+							pcr1 := int(src.Cr[pj]) - 128
+							pru0 := (pyy1 + 91881*pcr1) >> 8
+							pgu0 := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
+							pbu0 := (pyy1 + 116130*pcb1) >> 8
+							if pru0 < 0 {
+								pru0 = 0
+							} else if pru0 > 0xffff {
+								pru0 = 0xffff
+							}
+							if pgu0 < 0 {
+								pgu0 = 0
+							} else if pgu0 > 0xffff {
+								pgu0 = 0xffff
+							}
+							if pbu0 < 0 {
+								pbu0 = 0
+							} else if pbu0 > 0xffff {
+								pbu0 = 0xffff
+							}
+
+							pau := uint32(src.A[pk]) * 0x101
+							pru := uint32(pru0) * pau / 0xffff
+							pgu := uint32(pgu0) * pau / 0xffff
+							pbu := uint32(pbu0) * pau / 0xffff
+							pr += float64(float64(pru) * w)
+							pg += float64(float64(pgu) * w)
+							pb += float64(float64(pbu) * w)
+							pa += float64(float64(pau) * w)
+						}
+					}
+				}
+			}
+
+			if pr > pa {
+				pr = pa
+			}
+			if pg > pa {
+				pg = pa
+			}
+			if pb > pa {
+				pb = pa
+			}
+
+			pr0 := uint32(fffftou(pr))
+			pg0 := uint32(fffftou(pg))
+			pb0 := uint32(fffftou(pb))
+			pa0 := uint32(fffftou(pa))
+			pa1 := (0xffff - uint32(pa0)) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pr0) >> 8)
+			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + pg0) >> 8)
+			dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*pa1/0xffff + pb0) >> 8)
+			dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*pa1/0xffff + pa0) >> 8)
 		}
 	}
 }
 
-func (ablInterpolator) transform_RGBA64Image_RGBA64Image_Over(dst RGBA64Image, dr, adr image.Rectangle, d2s *f64.Aff3, src image.RGBA64Image, sr image.Rectangle, bias image.Point, opts *Options) {
-	srcMask, smp := opts.SrcMask, opts.SrcMaskP
-	dstMask, dmp := opts.DstMask, opts.DstMaskP
-	dstColorRGBA64 := color.RGBA64{}
+func (q *Kernel) transform_RGBA_NYCbCrA420_Over(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NYCbCrA, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+	// When shrinking, broaden the effective kernel support so that we still
+	// visit every source pixel.
+	xHalfWidth, xKernelArgScale := q.Support, 1.0
+	if xscale > 1 {
+		xHalfWidth *= xscale
+		xKernelArgScale = 1 / xscale
+	}
+	yHalfWidth, yKernelArgScale := q.Support, 1.0
+	if yscale > 1 {
+		yHalfWidth *= yscale
+		yKernelArgScale = 1 / yscale
+	}
+
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
 
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
 		dyf := float64(dr.Min.Y+int(dy)) + 0.5
-		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
 			dxf := float64(dr.Min.X+int(dx)) + 0.5
 			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
 			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
@@ -5055,121 +20275,147 @@ func (ablInterpolator) transform_RGBA64Image_RGBA64Image_Over(dst RGBA64Image, d
 				continue
 			}
 
+			// TODO: adjust the bias so that we can use int(f) instead
+			// of math.Floor(f) and math.Ceil(f).
+			sx += float64(bias.X)
 			sx -= 0.5
-			sx0 := int(sx)
-			xFrac0 := sx - float64(sx0)
-			xFrac1 := 1 - xFrac0
-			sx0 += bias.X
-			sx1 := sx0 + 1
-			if sx0 < sr.Min.X {
-				sx0, sx1 = sr.Min.X, sr.Min.X
-				xFrac0, xFrac1 = 0, 1
-			} else if sx1 >= sr.Max.X {
-				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
-				xFrac0, xFrac1 = 1, 0
+			ix := int(math.Floor(sx - xHalfWidth))
+			if ix < sr.Min.X {
+				ix = sr.Min.X
+			}
+			jx := int(math.Ceil(sx + xHalfWidth))
+			if jx > sr.Max.X {
+				jx = sr.Max.X
+			}
+
+			totalXWeight := 0.0
+			for kx := ix; kx < jx; kx++ {
+				xWeight := 0.0
+				if t := abs((sx - float64(kx)) * xKernelArgScale); t < q.Support {
+					xWeight = q.At(t)
+				}
+				xWeights[kx-ix] = xWeight
+				totalXWeight += xWeight
+			}
+			for x := range xWeights[:jx-ix] {
+				xWeights[x] /= totalXWeight
+			}
+
+			sy += float64(bias.Y)
+			sy -= 0.5
+			iy := int(math.Floor(sy - yHalfWidth))
+			if iy < sr.Min.Y {
+				iy = sr.Min.Y
 			}
-
-			sy -= 0.5
-			sy0 := int(sy)
-			yFrac0 := sy - float64(sy0)
-			yFrac1 := 1 - yFrac0
-			sy0 += bias.Y
-			sy1 := sy0 + 1
-			if sy0 < sr.Min.Y {
-				sy0, sy1 = sr.Min.Y, sr.Min.Y
-				yFrac0, yFrac1 = 0, 1
-			} else if sy1 >= sr.Max.Y {
-				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
-				yFrac0, yFrac1 = 1, 0
+			jy := int(math.Ceil(sy + yHalfWidth))
+			if jy > sr.Max.Y {
+				jy = sr.Max.Y
 			}
 
-			s00u := src.RGBA64At(sx0, sy0)
-			if srcMask != nil {
-				_, _, _, ma := srcMask.At(smp.X+sx0, smp.Y+sy0).RGBA()
-				s00u.R = uint16(uint32(s00u.R) * ma / 0xffff)
-				s00u.G = uint16(uint32(s00u.G) * ma / 0xffff)
-				s00u.B = uint16(uint32(s00u.B) * ma / 0xffff)
-				s00u.A = uint16(uint32(s00u.A) * ma / 0xffff)
+			totalYWeight := 0.0
+			for ky := iy; ky < jy; ky++ {
+				yWeight := 0.0
+				if t := abs((sy - float64(ky)) * yKernelArgScale); t < q.Support {
+					yWeight = q.At(t)
+				}
+				yWeights[ky-iy] = yWeight
+				totalYWeight += yWeight
 			}
-			s00r := float64(s00u.R)
-			s00g := float64(s00u.G)
-			s00b := float64(s00u.B)
-			s00a := float64(s00u.A)
-			s10u := src.RGBA64At(sx1, sy0)
-			if srcMask != nil {
-				_, _, _, ma := srcMask.At(smp.X+sx1, smp.Y+sy0).RGBA()
-				s10u.R = uint16(uint32(s10u.R) * ma / 0xffff)
-				s10u.G = uint16(uint32(s10u.G) * ma / 0xffff)
-				s10u.B = uint16(uint32(s10u.B) * ma / 0xffff)
-				s10u.A = uint16(uint32(s10u.A) * ma / 0xffff)
+			for y := range yWeights[:jy-iy] {
+				yWeights[y] /= totalYWeight
 			}
-			s10r := float64(s10u.R)
-			s10g := float64(s10u.G)
-			s10b := float64(s10u.B)
-			s10a := float64(s10u.A)
-			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
-			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
-			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
-			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
-			s01u := src.RGBA64At(sx0, sy1)
-			if srcMask != nil {
-				_, _, _, ma := srcMask.At(smp.X+sx0, smp.Y+sy1).RGBA()
-				s01u.R = uint16(uint32(s01u.R) * ma / 0xffff)
-				s01u.G = uint16(uint32(s01u.G) * ma / 0xffff)
-				s01u.B = uint16(uint32(s01u.B) * ma / 0xffff)
-				s01u.A = uint16(uint32(s01u.A) * ma / 0xffff)
+
+			var pr, pg, pb, pa float64
+			for ky := iy; ky < jy; ky++ {
+				if yWeight := yWeights[ky-iy]; yWeight != 0 {
+					for kx := ix; kx < jx; kx++ {
+						if w := xWeights[kx-ix] * yWeight; w != 0 {
+							pi := (ky-src.Rect.Min.Y)*src.YStride + (kx - src.Rect.Min.X)
+							pj := ((ky)/2-src.Rect.Min.Y/2)*src.CStride + ((kx)/2 - src.Rect.Min.X/2)
+							pk := (ky-src.Rect.Min.Y)*src.AStride + (kx - src.Rect.Min.X)
+
+							// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+							pyy1 := int(src.Y[pi]) * 0x10101
+							pcb1 := int(src.Cb[pj]) - 128
+							pcr1 := int(src.Cr[pj]) - 128
+							pru0 := (pyy1 + 91881*pcr1) >> 8
+							pgu0 := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
+							pbu0 := (pyy1 + 116130*pcb1) >> 8
+							if pru0 < 0 {
+								pru0 = 0
+							} else if pru0 > 0xffff {
+								pru0 = 0xffff
+							}
+							if pgu0 < 0 {
+								pgu0 = 0
+							} else if pgu0 > 0xffff {
+								pgu0 = 0xffff
+							}
+							if pbu0 < 0 {
+								pbu0 = 0
+							} else if pbu0 > 0xffff {
+								pbu0 = 0xffff
+							}
+
+							pau := uint32(src.A[pk]) * 0x101
+							pru := uint32(pru0) * pau / 0xffff
+							pgu := uint32(pgu0) * pau / 0xffff
+							pbu := uint32(pbu0) * pau / 0xffff
+							pr += float64(float64(pru) * w)
+							pg += float64(float64(pgu) * w)
+							pb += float64(float64(pbu) * w)
+							pa += float64(float64(pau) * w)
+						}
+					}
+				}
 			}
-			s01r := float64(s01u.R)
-			s01g := float64(s01u.G)
-			s01b := float64(s01u.B)
-			s01a := float64(s01u.A)
-			s11u := src.RGBA64At(sx1, sy1)
-			if srcMask != nil {
-				_, _, _, ma := srcMask.At(smp.X+sx1, smp.Y+sy1).RGBA()
-				s11u.R = uint16(uint32(s11u.R) * ma / 0xffff)
-				s11u.G = uint16(uint32(s11u.G) * ma / 0xffff)
-				s11u.B = uint16(uint32(s11u.B) * ma / 0xffff)
-				s11u.A = uint16(uint32(s11u.A) * ma / 0xffff)
+
+			if pr > pa {
+				pr = pa
 			}
-			s11r := float64(s11u.R)
-			s11g := float64(s11u.G)
-			s11b := float64(s11u.B)
-			s11a := float64(s11u.A)
-			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
-			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
-			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
-			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
-			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
-			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
-			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
-			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
-			p := color.RGBA64{uint16(s11r), uint16(s11g), uint16(s11b), uint16(s11a)}
-			q := dst.RGBA64At(dr.Min.X+int(dx), dr.Min.Y+int(dy))
-			if dstMask != nil {
-				_, _, _, ma := dstMask.At(dmp.X+dr.Min.X+int(dx), dmp.Y+dr.Min.Y+int(dy)).RGBA()
-				p.R = uint16(uint32(p.R) * ma / 0xffff)
-				p.G = uint16(uint32(p.G) * ma / 0xffff)
-				p.B = uint16(uint32(p.B) * ma / 0xffff)
-				p.A = uint16(uint32(p.A) * ma / 0xffff)
+			if pg > pa {
+				pg = pa
 			}
-			pa1 := 0xffff - uint32(p.A)
-			dstColorRGBA64.R = uint16(uint32(q.R)*pa1/0xffff + uint32(p.R))
-			dstColorRGBA64.G = uint16(uint32(q.G)*pa1/0xffff + uint32(p.G))
-			dstColorRGBA64.B = uint16(uint32(q.B)*pa1/0xffff + uint32(p.B))
-			dstColorRGBA64.A = uint16(uint32(q.A)*pa1/0xffff + uint32(p.A))
-			dst.Set(dr.Min.X+int(dx), dr.Min.Y+int(dy), dstColorRGBA64)
+			if pb > pa {
+				pb = pa
+			}
+
+			pr0 := uint32(fffftou(pr))
+			pg0 := uint32(fffftou(pg))
+			pb0 := uint32(fffftou(pb))
+			pa0 := uint32(fffftou(pa))
+			pa1 := (0xffff - uint32(pa0)) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pr0) >> 8)
+			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + pg0) >> 8)
+			dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*pa1/0xffff + pb0) >> 8)
+			dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*pa1/0xffff + pa0) >> 8)
 		}
 	}
 }
 
-func (ablInterpolator) transform_RGBA64Image_RGBA64Image_Src(dst RGBA64Image, dr, adr image.Rectangle, d2s *f64.Aff3, src image.RGBA64Image, sr image.Rectangle, bias image.Point, opts *Options) {
-	srcMask, smp := opts.SrcMask, opts.SrcMaskP
-	dstMask, dmp := opts.DstMask, opts.DstMaskP
-	dstColorRGBA64 := color.RGBA64{}
+func (q *Kernel) transform_RGBA_NYCbCrA440_Over(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NYCbCrA, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+	// When shrinking, broaden the effective kernel support so that we still
+	// visit every source pixel.
+	xHalfWidth, xKernelArgScale := q.Support, 1.0
+	if xscale > 1 {
+		xHalfWidth *= xscale
+		xKernelArgScale = 1 / xscale
+	}
+	yHalfWidth, yKernelArgScale := q.Support, 1.0
+	if yscale > 1 {
+		yHalfWidth *= yscale
+		yKernelArgScale = 1 / yscale
+	}
+
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
 
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
 		dyf := float64(dr.Min.Y+int(dy)) + 0.5
-		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
 			dxf := float64(dr.Min.X+int(dx)) + 0.5
 			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
 			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
@@ -5177,123 +20423,147 @@ func (ablInterpolator) transform_RGBA64Image_RGBA64Image_Src(dst RGBA64Image, dr
 				continue
 			}
 
+			// TODO: adjust the bias so that we can use int(f) instead
+			// of math.Floor(f) and math.Ceil(f).
+			sx += float64(bias.X)
 			sx -= 0.5
-			sx0 := int(sx)
-			xFrac0 := sx - float64(sx0)
-			xFrac1 := 1 - xFrac0
-			sx0 += bias.X
-			sx1 := sx0 + 1
-			if sx0 < sr.Min.X {
-				sx0, sx1 = sr.Min.X, sr.Min.X
-				xFrac0, xFrac1 = 0, 1
-			} else if sx1 >= sr.Max.X {
-				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
-				xFrac0, xFrac1 = 1, 0
+			ix := int(math.Floor(sx - xHalfWidth))
+			if ix < sr.Min.X {
+				ix = sr.Min.X
+			}
+			jx := int(math.Ceil(sx + xHalfWidth))
+			if jx > sr.Max.X {
+				jx = sr.Max.X
+			}
+
+			totalXWeight := 0.0
+			for kx := ix; kx < jx; kx++ {
+				xWeight := 0.0
+				if t := abs((sx - float64(kx)) * xKernelArgScale); t < q.Support {
+					xWeight = q.At(t)
+				}
+				xWeights[kx-ix] = xWeight
+				totalXWeight += xWeight
+			}
+			for x := range xWeights[:jx-ix] {
+				xWeights[x] /= totalXWeight
 			}
 
+			sy += float64(bias.Y)
 			sy -= 0.5
-			sy0 := int(sy)
-			yFrac0 := sy - float64(sy0)
-			yFrac1 := 1 - yFrac0
-			sy0 += bias.Y
-			sy1 := sy0 + 1
-			if sy0 < sr.Min.Y {
-				sy0, sy1 = sr.Min.Y, sr.Min.Y
-				yFrac0, yFrac1 = 0, 1
-			} else if sy1 >= sr.Max.Y {
-				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
-				yFrac0, yFrac1 = 1, 0
+			iy := int(math.Floor(sy - yHalfWidth))
+			if iy < sr.Min.Y {
+				iy = sr.Min.Y
+			}
+			jy := int(math.Ceil(sy + yHalfWidth))
+			if jy > sr.Max.Y {
+				jy = sr.Max.Y
 			}
 
-			s00u := src.RGBA64At(sx0, sy0)
-			if srcMask != nil {
-				_, _, _, ma := srcMask.At(smp.X+sx0, smp.Y+sy0).RGBA()
-				s00u.R = uint16(uint32(s00u.R) * ma / 0xffff)
-				s00u.G = uint16(uint32(s00u.G) * ma / 0xffff)
-				s00u.B = uint16(uint32(s00u.B) * ma / 0xffff)
-				s00u.A = uint16(uint32(s00u.A) * ma / 0xffff)
+			totalYWeight := 0.0
+			for ky := iy; ky < jy; ky++ {
+				yWeight := 0.0
+				if t := abs((sy - float64(ky)) * yKernelArgScale); t < q.Support {
+					yWeight = q.At(t)
+				}
+				yWeights[ky-iy] = yWeight
+				totalYWeight += yWeight
 			}
-			s00r := float64(s00u.R)
-			s00g := float64(s00u.G)
-			s00b := float64(s00u.B)
-			s00a := float64(s00u.A)
-			s10u := src.RGBA64At(sx1, sy0)
-			if srcMask != nil {
-				_, _, _, ma := srcMask.At(smp.X+sx1, smp.Y+sy0).RGBA()
-				s10u.R = uint16(uint32(s10u.R) * ma / 0xffff)
-				s10u.G = uint16(uint32(s10u.G) * ma / 0xffff)
-				s10u.B = uint16(uint32(s10u.B) * ma / 0xffff)
-				s10u.A = uint16(uint32(s10u.A) * ma / 0xffff)
+			for y := range yWeights[:jy-iy] {
+				yWeights[y] /= totalYWeight
+			}
+
+			var pr, pg, pb, pa float64
+			for ky := iy; ky < jy; ky++ {
+				if yWeight := yWeights[ky-iy]; yWeight != 0 {
+					for kx := ix; kx < jx; kx++ {
+						if w := xWeights[kx-ix] * yWeight; w != 0 {
+							pi := (ky-src.Rect.Min.Y)*src.YStride + (kx - src.Rect.Min.X)
+							pj := ((ky)/2-src.Rect.Min.Y/2)*src.CStride + (kx - src.Rect.Min.X)
+							pk := (ky-src.Rect.Min.Y)*src.AStride + (kx - src.Rect.Min.X)
+
+							// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+							pyy1 := int(src.Y[pi]) * 0x10101
+							pcb1 := int(src.Cb[pj]) - 128
+							pcr1 := int(src.Cr[pj]) - 128
+							pru0 := (pyy1 + 91881*pcr1) >> 8
+							pgu0 := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
+							pbu0 := (pyy1 + 116130*pcb1) >> 8
+							if pru0 < 0 {
+								pru0 = 0
+							} else if pru0 > 0xffff {
+								pru0 = 0xffff
+							}
+							if pgu0 < 0 {
+								pgu0 = 0
+							} else if pgu0 > 0xffff {
+								pgu0 = 0xffff
+							}
+							if pbu0 < 0 {
+								pbu0 = 0
+							} else if pbu0 > 0xffff {
+								pbu0 = 0xffff
+							}
+
+							pau := uint32(src.A[pk]) * 0x101
+							pru := uint32(pru0) * pau / 0xffff
+							pgu := uint32(pgu0) * pau / 0xffff
+							pbu := uint32(pbu0) * pau / 0xffff
+							pr += float64(float64(pru) * w)
+							pg += float64(float64(pgu) * w)
+							pb += float64(float64(pbu) * w)
+							pa += float64(float64(pau) * w)
+						}
+					}
+				}
 			}
-			s10r := float64(s10u.R)
-			s10g := float64(s10u.G)
-			s10b := float64(s10u.B)
-			s10a := float64(s10u.A)
-			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
-			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
-			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
-			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
-			s01u := src.RGBA64At(sx0, sy1)
-			if srcMask != nil {
-				_, _, _, ma := srcMask.At(smp.X+sx0, smp.Y+sy1).RGBA()
-				s01u.R = uint16(uint32(s01u.R) * ma / 0xffff)
-				s01u.G = uint16(uint32(s01u.G) * ma / 0xffff)
-				s01u.B = uint16(uint32(s01u.B) * ma / 0xffff)
-				s01u.A = uint16(uint32(s01u.A) * ma / 0xffff)
+
+			if pr > pa {
+				pr = pa
 			}
-			s01r := float64(s01u.R)
-			s01g := float64(s01u.G)
-			s01b := float64(s01u.B)
-			s01a := float64(s01u.A)
-			s11u := src.RGBA64At(sx1, sy1)
-			if srcMask != nil {
-				_, _, _, ma := srcMask.At(smp.X+sx1, smp.Y+sy1).RGBA()
-				s11u.R = uint16(uint32(s11u.R) * ma / 0xffff)
-				s11u.G = uint16(uint32(s11u.G) * ma / 0xffff)
-				s11u.B = uint16(uint32(s11u.B) * ma / 0xffff)
-				s11u.A = uint16(uint32(s11u.A) * ma / 0xffff)
+			if pg > pa {
+				pg = pa
 			}
-			s11r := float64(s11u.R)
-			s11g := float64(s11u.G)
-			s11b := float64(s11u.B)
-			s11a := float64(s11u.A)
-			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
-			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
-			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
-			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
-			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
-			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
-			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
-			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
-			p := color.RGBA64{uint16(s11r), uint16(s11g), uint16(s11b), uint16(s11a)}
-			if dstMask != nil {
-				q := dst.RGBA64At(dr.Min.X+int(dx), dr.Min.Y+int(dy))
-				_, _, _, ma := dstMask.At(dmp.X+dr.Min.X+int(dx), dmp.Y+dr.Min.Y+int(dy)).RGBA()
-				p.R = uint16(uint32(p.R) * ma / 0xffff)
-				p.G = uint16(uint32(p.G) * ma / 0xffff)
-				p.B = uint16(uint32(p.B) * ma / 0xffff)
-				p.A = uint16(uint32(p.A) * ma / 0xffff)
-				pa1 := 0xffff - ma
-				dstColorRGBA64.R = uint16(uint32(q.R)*pa1/0xffff + uint32(p.R))
-				dstColorRGBA64.G = uint16(uint32(q.G)*pa1/0xffff + uint32(p.G))
-				dstColorRGBA64.B = uint16(uint32(q.B)*pa1/0xffff + uint32(p.B))
-				dstColorRGBA64.A = uint16(uint32(q.A)*pa1/0xffff + uint32(p.A))
-				dst.Set(dr.Min.X+int(dx), dr.Min.Y+int(dy), dstColorRGBA64)
-			} else {
-				dst.Set(dr.Min.X+int(dx), dr.Min.Y+int(dy), p)
+			if pb > pa {
+				pb = pa
 			}
+
+			pr0 := uint32(fffftou(pr))
+			pg0 := uint32(fffftou(pg))
+			pb0 := uint32(fffftou(pb))
+			pa0 := uint32(fffftou(pa))
+			pa1 := (0xffff - uint32(pa0)) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pr0) >> 8)
+			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + pg0) >> 8)
+			dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*pa1/0xffff + pb0) >> 8)
+			dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*pa1/0xffff + pa0) >> 8)
 		}
 	}
 }
 
-func (ablInterpolator) transform_Image_Image_Over(dst Image, dr, adr image.Rectangle, d2s *f64.Aff3, src image.Image, sr image.Rectangle, bias image.Point, opts *Options) {
-	srcMask, smp := opts.SrcMask, opts.SrcMaskP
-	dstMask, dmp := opts.DstMask, opts.DstMaskP
-	dstColorRGBA64 := &color.RGBA64{}
-	dstColor := color.Color(dstColorRGBA64)
+func (q *Kernel) transform_RGBA_NYCbCrA444_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NYCbCrA, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+	// When shrinking, broaden the effective kernel support so that we still
+	// visit every source pixel.
+	xHalfWidth, xKernelArgScale := q.Support, 1.0
+	if xscale > 1 {
+		xHalfWidth *= xscale
+		xKernelArgScale = 1 / xscale
+	}
+	yHalfWidth, yKernelArgScale := q.Support, 1.0
+	if yscale > 1 {
+		yHalfWidth *= yscale
+		yKernelArgScale = 1 / yscale
+	}
+
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
+
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
 		dyf := float64(dr.Min.Y+int(dy)) + 0.5
-		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
 			dxf := float64(dr.Min.X+int(dx)) + 0.5
 			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
 			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
@@ -5301,124 +20571,142 @@ func (ablInterpolator) transform_Image_Image_Over(dst Image, dr, adr image.Recta
 				continue
 			}
 
+			// TODO: adjust the bias so that we can use int(f) instead
+			// of math.Floor(f) and math.Ceil(f).
+			sx += float64(bias.X)
 			sx -= 0.5
-			sx0 := int(sx)
-			xFrac0 := sx - float64(sx0)
-			xFrac1 := 1 - xFrac0
-			sx0 += bias.X
-			sx1 := sx0 + 1
-			if sx0 < sr.Min.X {
-				sx0, sx1 = sr.Min.X, sr.Min.X
-				xFrac0, xFrac1 = 0, 1
-			} else if sx1 >= sr.Max.X {
-				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
-				xFrac0, xFrac1 = 1, 0
+			ix := int(math.Floor(sx - xHalfWidth))
+			if ix < sr.Min.X {
+				ix = sr.Min.X
+			}
+			jx := int(math.Ceil(sx + xHalfWidth))
+			if jx > sr.Max.X {
+				jx = sr.Max.X
 			}
 
-			sy -= 0.5
-			sy0 := int(sy)
-			yFrac0 := sy - float64(sy0)
-			yFrac1 := 1 - yFrac0
-			sy0 += bias.Y
-			sy1 := sy0 + 1
-			if sy0 < sr.Min.Y {
-				sy0, sy1 = sr.Min.Y, sr.Min.Y
-				yFrac0, yFrac1 = 0, 1
-			} else if sy1 >= sr.Max.Y {
-				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
-				yFrac0, yFrac1 = 1, 0
+			totalXWeight := 0.0
+			for kx := ix; kx < jx; kx++ {
+				xWeight := 0.0
+				if t := abs((sx - float64(kx)) * xKernelArgScale); t < q.Support {
+					xWeight = q.At(t)
+				}
+				xWeights[kx-ix] = xWeight
+				totalXWeight += xWeight
+			}
+			for x := range xWeights[:jx-ix] {
+				xWeights[x] /= totalXWeight
 			}
 
-			s00ru, s00gu, s00bu, s00au := src.At(sx0, sy0).RGBA()
-			if srcMask != nil {
-				_, _, _, ma := srcMask.At(smp.X+sx0, smp.Y+sy0).RGBA()
-				s00ru = s00ru * ma / 0xffff
-				s00gu = s00gu * ma / 0xffff
-				s00bu = s00bu * ma / 0xffff
-				s00au = s00au * ma / 0xffff
+			sy += float64(bias.Y)
+			sy -= 0.5
+			iy := int(math.Floor(sy - yHalfWidth))
+			if iy < sr.Min.Y {
+				iy = sr.Min.Y
 			}
-			s00r := float64(s00ru)
-			s00g := float64(s00gu)
-			s00b := float64(s00bu)
-			s00a := float64(s00au)
-			s10ru, s10gu, s10bu, s10au := src.At(sx1, sy0).RGBA()
-			if srcMask != nil {
-				_, _, _, ma := srcMask.At(smp.X+sx1, smp.Y+sy0).RGBA()
-				s10ru = s10ru * ma / 0xffff
-				s10gu = s10gu * ma / 0xffff
-				s10bu = s10bu * ma / 0xffff
-				s10au = s10au * ma / 0xffff
+			jy := int(math.Ceil(sy + yHalfWidth))
+			if jy > sr.Max.Y {
+				jy = sr.Max.Y
 			}
-			s10r := float64(s10ru)
-			s10g := float64(s10gu)
-			s10b := float64(s10bu)
-			s10a := float64(s10au)
-			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
-			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
-			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
-			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
-			s01ru, s01gu, s01bu, s01au := src.At(sx0, sy1).RGBA()
-			if srcMask != nil {
-				_, _, _, ma := srcMask.At(smp.X+sx0, smp.Y+sy1).RGBA()
-				s01ru = s01ru * ma / 0xffff
-				s01gu = s01gu * ma / 0xffff
-				s01bu = s01bu * ma / 0xffff
-				s01au = s01au * ma / 0xffff
+
+			totalYWeight := 0.0
+			for ky := iy; ky < jy; ky++ {
+				yWeight := 0.0
+				if t := abs((sy - float64(ky)) * yKernelArgScale); t < q.Support {
+					yWeight = q.At(t)
+				}
+				yWeights[ky-iy] = yWeight
+				totalYWeight += yWeight
 			}
-			s01r := float64(s01ru)
-			s01g := float64(s01gu)
-			s01b := float64(s01bu)
-			s01a := float64(s01au)
-			s11ru, s11gu, s11bu, s11au := src.At(sx1, sy1).RGBA()
-			if srcMask != nil {
-				_, _, _, ma := srcMask.At(smp.X+sx1, smp.Y+sy1).RGBA()
-				s11ru = s11ru * ma / 0xffff
-				s11gu = s11gu * ma / 0xffff
-				s11bu = s11bu * ma / 0xffff
-				s11au = s11au * ma / 0xffff
+			for y := range yWeights[:jy-iy] {
+				yWeights[y] /= totalYWeight
 			}
-			s11r := float64(s11ru)
-			s11g := float64(s11gu)
-			s11b := float64(s11bu)
-			s11a := float64(s11au)
-			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
-			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
-			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
-			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
-			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
-			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
-			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
-			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
-			pr := uint32(s11r)
-			pg := uint32(s11g)
-			pb := uint32(s11b)
-			pa := uint32(s11a)
-			qr, qg, qb, qa := dst.At(dr.Min.X+int(dx), dr.Min.Y+int(dy)).RGBA()
-			if dstMask != nil {
-				_, _, _, ma := dstMask.At(dmp.X+dr.Min.X+int(dx), dmp.Y+dr.Min.Y+int(dy)).RGBA()
-				pr = pr * ma / 0xffff
-				pg = pg * ma / 0xffff
-				pb = pb * ma / 0xffff
-				pa = pa * ma / 0xffff
+
+			var pr, pg, pb, pa float64
+			for ky := iy; ky < jy; ky++ {
+				if yWeight := yWeights[ky-iy]; yWeight != 0 {
+					for kx := ix; kx < jx; kx++ {
+						if w := xWeights[kx-ix] * yWeight; w != 0 {
+							pi := (ky-src.Rect.Min.Y)*src.YStride + (kx - src.Rect.Min.X)
+							pj := (ky-src.Rect.Min.Y)*src.CStride + (kx - src.Rect.Min.X)
+							pk := (ky-src.Rect.Min.Y)*src.AStride + (kx - src.Rect.Min.X)
+
+							// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+							pyy1 := int(src.Y[pi]) * 0x10101
+							pcb1 := int(src.Cb[pj]) - 128
+							pcr1 := int(src.Cr[pj]) - 128
+							pru0 := (pyy1 + 91881*pcr1) >> 8
+							pgu0 := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
+							pbu0 := (pyy1 + 116130*pcb1) >> 8
+							if pru0 < 0 {
+								pru0 = 0
+							} else if pru0 > 0xffff {
+								pru0 = 0xffff
+							}
+							if pgu0 < 0 {
+								pgu0 = 0
+							} else if pgu0 > 0xffff {
+								pgu0 = 0xffff
+							}
+							if pbu0 < 0 {
+								pbu0 = 0
+							} else if pbu0 > 0xffff {
+								pbu0 = 0xffff
+							}
+
+							pau := uint32(src.A[pk]) * 0x101
+							pru := uint32(pru0) * pau / 0xffff
+							pgu := uint32(pgu0) * pau / 0xffff
+							pbu := uint32(pbu0) * pau / 0xffff
+							pr += float64(float64(pru) * w)
+							pg += float64(float64(pgu) * w)
+							pb += float64(float64(pbu) * w)
+							pa += float64(float64(pau) * w)
+						}
+					}
+				}
+			}
+
+			if pr > pa {
+				pr = pa
 			}
-			pa1 := 0xffff - pa
-			dstColorRGBA64.R = uint16(qr*pa1/0xffff + pr)
-			dstColorRGBA64.G = uint16(qg*pa1/0xffff + pg)
-			dstColorRGBA64.B = uint16(qb*pa1/0xffff + pb)
-			dstColorRGBA64.A = uint16(qa*pa1/0xffff + pa)
-			dst.Set(dr.Min.X+int(dx), dr.Min.Y+int(dy), dstColor)
+			if pg > pa {
+				pg = pa
+			}
+			if pb > pa {
+				pb = pa
+			}
+
+			dst.Pix[d+0] = uint8(fffftou(pr) >> 8)
+			dst.Pix[d+1] = uint8(fffftou(pg) >> 8)
+			dst.Pix[d+2] = uint8(fffftou(pb) >> 8)
+			dst.Pix[d+3] = uint8(fffftou(pa) >> 8)
 		}
 	}
 }
 
-func (ablInterpolator) transform_Image_Image_Src(dst Image, dr, adr image.Rectangle, d2s *f64.Aff3, src image.Image, sr image.Rectangle, bias image.Point, opts *Options) {
-	srcMask, smp := opts.SrcMask, opts.SrcMaskP
-	dstMask, dmp := opts.DstMask, opts.DstMaskP
-	dstColorRGBA64 := &color.RGBA64{}
-	dstColor := color.Color(dstColorRGBA64)
+func (q *Kernel) transform_RGBA_NYCbCrA422_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NYCbCrA, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+	// When shrinking, broaden the effective kernel support so that we still
+	// visit every source pixel.
+	xHalfWidth, xKernelArgScale := q.Support, 1.0
+	if xscale > 1 {
+		xHalfWidth *= xscale
+		xKernelArgScale = 1 / xscale
+	}
+	yHalfWidth, yKernelArgScale := q.Support, 1.0
+	if yscale > 1 {
+		yHalfWidth *= yscale
+		yKernelArgScale = 1 / yscale
+	}
+
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
+
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
 		dyf := float64(dr.Min.Y+int(dy)) + 0.5
-		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
 			dxf := float64(dr.Min.X+int(dx)) + 0.5
 			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
 			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
@@ -5426,692 +20714,729 @@ func (ablInterpolator) transform_Image_Image_Src(dst Image, dr, adr image.Rectan
 				continue
 			}
 
+			// TODO: adjust the bias so that we can use int(f) instead
+			// of math.Floor(f) and math.Ceil(f).
+			sx += float64(bias.X)
 			sx -= 0.5
-			sx0 := int(sx)
-			xFrac0 := sx - float64(sx0)
-			xFrac1 := 1 - xFrac0
-			sx0 += bias.X
-			sx1 := sx0 + 1
-			if sx0 < sr.Min.X {
-				sx0, sx1 = sr.Min.X, sr.Min.X
-				xFrac0, xFrac1 = 0, 1
-			} else if sx1 >= sr.Max.X {
-				sx0, sx1 = sr.Max.X-1, sr.Max.X-1
-				xFrac0, xFrac1 = 1, 0
+			ix := int(math.Floor(sx - xHalfWidth))
+			if ix < sr.Min.X {
+				ix = sr.Min.X
 			}
-
-			sy -= 0.5
-			sy0 := int(sy)
-			yFrac0 := sy - float64(sy0)
-			yFrac1 := 1 - yFrac0
-			sy0 += bias.Y
-			sy1 := sy0 + 1
-			if sy0 < sr.Min.Y {
-				sy0, sy1 = sr.Min.Y, sr.Min.Y
-				yFrac0, yFrac1 = 0, 1
-			} else if sy1 >= sr.Max.Y {
-				sy0, sy1 = sr.Max.Y-1, sr.Max.Y-1
-				yFrac0, yFrac1 = 1, 0
+			jx := int(math.Ceil(sx + xHalfWidth))
+			if jx > sr.Max.X {
+				jx = sr.Max.X
 			}
 
-			s00ru, s00gu, s00bu, s00au := src.At(sx0, sy0).RGBA()
-			if srcMask != nil {
-				_, _, _, ma := srcMask.At(smp.X+sx0, smp.Y+sy0).RGBA()
-				s00ru = s00ru * ma / 0xffff
-				s00gu = s00gu * ma / 0xffff
-				s00bu = s00bu * ma / 0xffff
-				s00au = s00au * ma / 0xffff
-			}
-			s00r := float64(s00ru)
-			s00g := float64(s00gu)
-			s00b := float64(s00bu)
-			s00a := float64(s00au)
-			s10ru, s10gu, s10bu, s10au := src.At(sx1, sy0).RGBA()
-			if srcMask != nil {
-				_, _, _, ma := srcMask.At(smp.X+sx1, smp.Y+sy0).RGBA()
-				s10ru = s10ru * ma / 0xffff
-				s10gu = s10gu * ma / 0xffff
-				s10bu = s10bu * ma / 0xffff
-				s10au = s10au * ma / 0xffff
+			totalXWeight := 0.0
+			for kx := ix; kx < jx; kx++ {
+				xWeight := 0.0
+				if t := abs((sx - float64(kx)) * xKernelArgScale); t < q.Support {
+					xWeight = q.At(t)
+				}
+				xWeights[kx-ix] = xWeight
+				totalXWeight += xWeight
 			}
-			s10r := float64(s10ru)
-			s10g := float64(s10gu)
-			s10b := float64(s10bu)
-			s10a := float64(s10au)
-			s10r = float64(xFrac1*s00r) + float64(xFrac0*s10r)
-			s10g = float64(xFrac1*s00g) + float64(xFrac0*s10g)
-			s10b = float64(xFrac1*s00b) + float64(xFrac0*s10b)
-			s10a = float64(xFrac1*s00a) + float64(xFrac0*s10a)
-			s01ru, s01gu, s01bu, s01au := src.At(sx0, sy1).RGBA()
-			if srcMask != nil {
-				_, _, _, ma := srcMask.At(smp.X+sx0, smp.Y+sy1).RGBA()
-				s01ru = s01ru * ma / 0xffff
-				s01gu = s01gu * ma / 0xffff
-				s01bu = s01bu * ma / 0xffff
-				s01au = s01au * ma / 0xffff
+			for x := range xWeights[:jx-ix] {
+				xWeights[x] /= totalXWeight
 			}
-			s01r := float64(s01ru)
-			s01g := float64(s01gu)
-			s01b := float64(s01bu)
-			s01a := float64(s01au)
-			s11ru, s11gu, s11bu, s11au := src.At(sx1, sy1).RGBA()
-			if srcMask != nil {
-				_, _, _, ma := srcMask.At(smp.X+sx1, smp.Y+sy1).RGBA()
-				s11ru = s11ru * ma / 0xffff
-				s11gu = s11gu * ma / 0xffff
-				s11bu = s11bu * ma / 0xffff
-				s11au = s11au * ma / 0xffff
+
+			sy += float64(bias.Y)
+			sy -= 0.5
+			iy := int(math.Floor(sy - yHalfWidth))
+			if iy < sr.Min.Y {
+				iy = sr.Min.Y
 			}
-			s11r := float64(s11ru)
-			s11g := float64(s11gu)
-			s11b := float64(s11bu)
-			s11a := float64(s11au)
-			s11r = float64(xFrac1*s01r) + float64(xFrac0*s11r)
-			s11g = float64(xFrac1*s01g) + float64(xFrac0*s11g)
-			s11b = float64(xFrac1*s01b) + float64(xFrac0*s11b)
-			s11a = float64(xFrac1*s01a) + float64(xFrac0*s11a)
-			s11r = float64(yFrac1*s10r) + float64(yFrac0*s11r)
-			s11g = float64(yFrac1*s10g) + float64(yFrac0*s11g)
-			s11b = float64(yFrac1*s10b) + float64(yFrac0*s11b)
-			s11a = float64(yFrac1*s10a) + float64(yFrac0*s11a)
-			pr := uint32(s11r)
-			pg := uint32(s11g)
-			pb := uint32(s11b)
-			pa := uint32(s11a)
-			if dstMask != nil {
-				qr, qg, qb, qa := dst.At(dr.Min.X+int(dx), dr.Min.Y+int(dy)).RGBA()
-				_, _, _, ma := dstMask.At(dmp.X+dr.Min.X+int(dx), dmp.Y+dr.Min.Y+int(dy)).RGBA()
-				pr = pr * ma / 0xffff
-				pg = pg * ma / 0xffff
-				pb = pb * ma / 0xffff
-				pa = pa * ma / 0xffff
-				pa1 := 0xffff - ma
-				dstColorRGBA64.R = uint16(qr*pa1/0xffff + pr)
-				dstColorRGBA64.G = uint16(qg*pa1/0xffff + pg)
-				dstColorRGBA64.B = uint16(qb*pa1/0xffff + pb)
-				dstColorRGBA64.A = uint16(qa*pa1/0xffff + pa)
-				dst.Set(dr.Min.X+int(dx), dr.Min.Y+int(dy), dstColor)
-			} else {
-				dstColorRGBA64.R = uint16(pr)
-				dstColorRGBA64.G = uint16(pg)
-				dstColorRGBA64.B = uint16(pb)
-				dstColorRGBA64.A = uint16(pa)
-				dst.Set(dr.Min.X+int(dx), dr.Min.Y+int(dy), dstColor)
+			jy := int(math.Ceil(sy + yHalfWidth))
+			if jy > sr.Max.Y {
+				jy = sr.Max.Y
 			}
-		}
-	}
-}
-
-func (z *kernelScaler) Scale(dst Image, dr image.Rectangle, src image.Image, sr image.Rectangle, op Op, opts *Options) {
-	if z.dw != int32(dr.Dx()) || z.dh != int32(dr.Dy()) || z.sw != int32(sr.Dx()) || z.sh != int32(sr.Dy()) {
-		z.kernel.Scale(dst, dr, src, sr, op, opts)
-		return
-	}
-
-	var o Options
-	if opts != nil {
-		o = *opts
-	}
-
-	// adr is the affected destination pixels.
-	adr := dst.Bounds().Intersect(dr)
-	adr, o.DstMask = clipAffectedDestRect(adr, o.DstMask, o.DstMaskP)
-	if adr.Empty() || sr.Empty() {
-		return
-	}
-	// Make adr relative to dr.Min.
-	adr = adr.Sub(dr.Min)
-	if op == Over && o.SrcMask == nil && opaque(src) {
-		op = Src
-	}
-
-	if _, ok := src.(*image.Uniform); ok && o.DstMask == nil && o.SrcMask == nil && sr.In(src.Bounds()) {
-		Draw(dst, dr, src, src.Bounds().Min, op)
-		return
-	}
 
-	// Create a temporary buffer:
-	// scaleX distributes the source image's columns over the temporary image.
-	// scaleY distributes the temporary image's rows over the destination image.
-	var tmp [][4]float64
-	if z.pool.New != nil {
-		tmpp := z.pool.Get().(*[][4]float64)
-		defer z.pool.Put(tmpp)
-		tmp = *tmpp
-	} else {
-		tmp = z.makeTmpBuf()
-	}
+			totalYWeight := 0.0
+			for ky := iy; ky < jy; ky++ {
+				yWeight := 0.0
+				if t := abs((sy - float64(ky)) * yKernelArgScale); t < q.Support {
+					yWeight = q.At(t)
+				}
+				yWeights[ky-iy] = yWeight
+				totalYWeight += yWeight
+			}
+			for y := range yWeights[:jy-iy] {
+				yWeights[y] /= totalYWeight
+			}
 
-	// sr is the source pixels. If it extends beyond the src bounds,
-	// we cannot use the type-specific fast paths, as they access
-	// the Pix fields directly without bounds checking.
-	//
-	// Similarly, the fast paths assume that the masks are nil.
-	if o.SrcMask != nil || !sr.In(src.Bounds()) {
-		z.scaleX_Image(tmp, src, sr, &o)
-	} else {
-		switch src := src.(type) {
-		case *image.Gray:
-			z.scaleX_Gray(tmp, src, sr, &o)
-		case *image.NRGBA:
-			z.scaleX_NRGBA(tmp, src, sr, &o)
-		case *image.RGBA:
-			z.scaleX_RGBA(tmp, src, sr, &o)
-		case *image.YCbCr:
-			switch src.SubsampleRatio {
-			default:
-				z.scaleX_Image(tmp, src, sr, &o)
-			case image.YCbCrSubsampleRatio444:
-				z.scaleX_YCbCr444(tmp, src, sr, &o)
-			case image.YCbCrSubsampleRatio422:
-				z.scaleX_YCbCr422(tmp, src, sr, &o)
-			case image.YCbCrSubsampleRatio420:
-				z.scaleX_YCbCr420(tmp, src, sr, &o)
-			case image.YCbCrSubsampleRatio440:
-				z.scaleX_YCbCr440(tmp, src, sr, &o)
+			var pr, pg, pb, pa float64
+			for ky := iy; ky < jy; ky++ {
+				if yWeight := yWeights[ky-iy]; yWeight != 0 {
+					for kx := ix; kx < jx; kx++ {
+						if w := xWeights[kx-ix] * yWeight; w != 0 {
+							pi := (ky-src.Rect.Min.Y)*src.YStride + (kx - src.Rect.Min.X)
+							pj := (ky-src.Rect.Min.Y)*src.CStride + ((kx)/2 - src.Rect.Min.X/2)
+							pk := (ky-src.Rect.Min.Y)*src.AStride + (kx - src.Rect.Min.X)
+
+							// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+							pyy1 := int(src.Y[pi]) * 0x10101
+							pcb1 := int(src.Cb[pj]) - 128
+							pcr1 := int(src.Cr[pj]) - 128
+							pru0 := (pyy1 + 91881*pcr1) >> 8
+							pgu0 := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
+							pbu0 := (pyy1 + 116130*pcb1) >> 8
+							if pru0 < 0 {
+								pru0 = 0
+							} else if pru0 > 0xffff {
+								pru0 = 0xffff
+							}
+							if pgu0 < 0 {
+								pgu0 = 0
+							} else if pgu0 > 0xffff {
+								pgu0 = 0xffff
+							}
+							if pbu0 < 0 {
+								pbu0 = 0
+							} else if pbu0 > 0xffff {
+								pbu0 = 0xffff
+							}
+
+							pau := uint32(src.A[pk]) * 0x101
+							pru := uint32(pru0) * pau / 0xffff
+							pgu := uint32(pgu0) * pau / 0xffff
+							pbu := uint32(pbu0) * pau / 0xffff
+							pr += float64(float64(pru) * w)
+							pg += float64(float64(pgu) * w)
+							pb += float64(float64(pbu) * w)
+							pa += float64(float64(pau) * w)
+						}
+					}
+				}
 			}
-		case image.RGBA64Image:
-			z.scaleX_RGBA64Image(tmp, src, sr, &o)
-		default:
-			z.scaleX_Image(tmp, src, sr, &o)
-		}
-	}
 
-	if o.DstMask != nil {
-		switch op {
-		case Over:
-			z.scaleY_Image_Over(dst, dr, adr, tmp, &o)
-		case Src:
-			z.scaleY_Image_Src(dst, dr, adr, tmp, &o)
-		}
-	} else {
-		switch op {
-		case Over:
-			switch dst := dst.(type) {
-			case *image.RGBA:
-				z.scaleY_RGBA_Over(dst, dr, adr, tmp, &o)
-			case RGBA64Image:
-				z.scaleY_RGBA64Image_Over(dst, dr, adr, tmp, &o)
-			default:
-				z.scaleY_Image_Over(dst, dr, adr, tmp, &o)
+			if pr > pa {
+				pr = pa
 			}
-		case Src:
-			switch dst := dst.(type) {
-			case *image.RGBA:
-				z.scaleY_RGBA_Src(dst, dr, adr, tmp, &o)
-			case RGBA64Image:
-				z.scaleY_RGBA64Image_Src(dst, dr, adr, tmp, &o)
-			default:
-				z.scaleY_Image_Src(dst, dr, adr, tmp, &o)
+			if pg > pa {
+				pg = pa
 			}
+			if pb > pa {
+				pb = pa
+			}
+
+			dst.Pix[d+0] = uint8(fffftou(pr) >> 8)
+			dst.Pix[d+1] = uint8(fffftou(pg) >> 8)
+			dst.Pix[d+2] = uint8(fffftou(pb) >> 8)
+			dst.Pix[d+3] = uint8(fffftou(pa) >> 8)
 		}
 	}
 }
 
-func (q *Kernel) Transform(dst Image, s2d f64.Aff3, src image.Image, sr image.Rectangle, op Op, opts *Options) {
-	var o Options
-	if opts != nil {
-		o = *opts
-	}
-
-	dr := transformRect(&s2d, &sr)
-	// adr is the affected destination pixels.
-	adr := dst.Bounds().Intersect(dr)
-	adr, o.DstMask = clipAffectedDestRect(adr, o.DstMask, o.DstMaskP)
-	if adr.Empty() || sr.Empty() {
-		return
+func (q *Kernel) transform_RGBA_NYCbCrA420_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NYCbCrA, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+	// When shrinking, broaden the effective kernel support so that we still
+	// visit every source pixel.
+	xHalfWidth, xKernelArgScale := q.Support, 1.0
+	if xscale > 1 {
+		xHalfWidth *= xscale
+		xKernelArgScale = 1 / xscale
 	}
-	if op == Over && o.SrcMask == nil && opaque(src) {
-		op = Src
+	yHalfWidth, yKernelArgScale := q.Support, 1.0
+	if yscale > 1 {
+		yHalfWidth *= yscale
+		yKernelArgScale = 1 / yscale
 	}
-	d2s := invert(&s2d)
-	// bias is a translation of the mapping from dst coordinates to src
-	// coordinates such that the latter temporarily have non-negative X
-	// and Y coordinates. This allows us to write int(f) instead of
-	// int(math.Floor(f)), since "round to zero" and "round down" are
-	// equivalent when f >= 0, but the former is much cheaper. The X--
-	// and Y-- are because the TransformLeaf methods have a "sx -= 0.5"
-	// adjustment.
-	bias := transformRect(&d2s, &adr).Min
-	bias.X--
-	bias.Y--
-	d2s[2] -= float64(bias.X)
-	d2s[5] -= float64(bias.Y)
-	// Make adr relative to dr.Min.
-	adr = adr.Sub(dr.Min)
 
-	if u, ok := src.(*image.Uniform); ok && o.DstMask != nil && o.SrcMask != nil && sr.In(src.Bounds()) {
-		transform_Uniform(dst, dr, adr, &d2s, u, sr, bias, op)
-		return
-	}
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
 
-	xscale := abs(d2s[0])
-	if s := abs(d2s[1]); xscale < s {
-		xscale = s
-	}
-	yscale := abs(d2s[3])
-	if s := abs(d2s[4]); yscale < s {
-		yscale = s
-	}
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
 
-	// sr is the source pixels. If it extends beyond the src bounds,
-	// we cannot use the type-specific fast paths, as they access
-	// the Pix fields directly without bounds checking.
-	//
-	// Similarly, the fast paths assume that the masks are nil.
-	if o.DstMask != nil || o.SrcMask != nil || !sr.In(src.Bounds()) {
-		switch op {
-		case Over:
-			q.transform_Image_Image_Over(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
-		case Src:
-			q.transform_Image_Image_Src(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
-		}
-	} else {
-		switch op {
-		case Over:
-			switch dst := dst.(type) {
-			case *image.RGBA:
-				switch src := src.(type) {
-				case *image.NRGBA:
-					q.transform_RGBA_NRGBA_Over(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
-				case *image.RGBA:
-					q.transform_RGBA_RGBA_Over(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
-				case image.RGBA64Image:
-					q.transform_RGBA_RGBA64Image_Over(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
-				default:
-					q.transform_RGBA_Image_Over(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
-				}
-			case RGBA64Image:
-				switch src := src.(type) {
-				case image.RGBA64Image:
-					q.transform_RGBA64Image_RGBA64Image_Over(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
-				}
-			default:
-				switch src := src.(type) {
-				default:
-					q.transform_Image_Image_Over(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
-				}
+			// TODO: adjust the bias so that we can use int(f) instead
+			// of math.Floor(f) and math.Ceil(f).
+			sx += float64(bias.X)
+			sx -= 0.5
+			ix := int(math.Floor(sx - xHalfWidth))
+			if ix < sr.Min.X {
+				ix = sr.Min.X
 			}
-		case Src:
-			switch dst := dst.(type) {
-			case *image.RGBA:
-				switch src := src.(type) {
-				case *image.Gray:
-					q.transform_RGBA_Gray_Src(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
-				case *image.NRGBA:
-					q.transform_RGBA_NRGBA_Src(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
-				case *image.RGBA:
-					q.transform_RGBA_RGBA_Src(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
-				case *image.YCbCr:
-					switch src.SubsampleRatio {
-					default:
-						q.transform_RGBA_Image_Src(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
-					case image.YCbCrSubsampleRatio444:
-						q.transform_RGBA_YCbCr444_Src(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
-					case image.YCbCrSubsampleRatio422:
-						q.transform_RGBA_YCbCr422_Src(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
-					case image.YCbCrSubsampleRatio420:
-						q.transform_RGBA_YCbCr420_Src(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
-					case image.YCbCrSubsampleRatio440:
-						q.transform_RGBA_YCbCr440_Src(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
-					}
-				case image.RGBA64Image:
-					q.transform_RGBA_RGBA64Image_Src(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
-				default:
-					q.transform_RGBA_Image_Src(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+			jx := int(math.Ceil(sx + xHalfWidth))
+			if jx > sr.Max.X {
+				jx = sr.Max.X
+			}
+
+			totalXWeight := 0.0
+			for kx := ix; kx < jx; kx++ {
+				xWeight := 0.0
+				if t := abs((sx - float64(kx)) * xKernelArgScale); t < q.Support {
+					xWeight = q.At(t)
 				}
-			case RGBA64Image:
-				switch src := src.(type) {
-				case image.RGBA64Image:
-					q.transform_RGBA64Image_RGBA64Image_Src(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+				xWeights[kx-ix] = xWeight
+				totalXWeight += xWeight
+			}
+			for x := range xWeights[:jx-ix] {
+				xWeights[x] /= totalXWeight
+			}
+
+			sy += float64(bias.Y)
+			sy -= 0.5
+			iy := int(math.Floor(sy - yHalfWidth))
+			if iy < sr.Min.Y {
+				iy = sr.Min.Y
+			}
+			jy := int(math.Ceil(sy + yHalfWidth))
+			if jy > sr.Max.Y {
+				jy = sr.Max.Y
+			}
+
+			totalYWeight := 0.0
+			for ky := iy; ky < jy; ky++ {
+				yWeight := 0.0
+				if t := abs((sy - float64(ky)) * yKernelArgScale); t < q.Support {
+					yWeight = q.At(t)
 				}
-			default:
-				switch src := src.(type) {
-				default:
-					q.transform_Image_Image_Src(dst, dr, adr, &d2s, src, sr, bias, xscale, yscale, &o)
+				yWeights[ky-iy] = yWeight
+				totalYWeight += yWeight
+			}
+			for y := range yWeights[:jy-iy] {
+				yWeights[y] /= totalYWeight
+			}
+
+			var pr, pg, pb, pa float64
+			for ky := iy; ky < jy; ky++ {
+				if yWeight := yWeights[ky-iy]; yWeight != 0 {
+					for kx := ix; kx < jx; kx++ {
+						if w := xWeights[kx-ix] * yWeight; w != 0 {
+							pi := (ky-src.Rect.Min.Y)*src.YStride + (kx - src.Rect.Min.X)
+							pj := ((ky)/2-src.Rect.Min.Y/2)*src.CStride + ((kx)/2 - src.Rect.Min.X/2)
+							pk := (ky-src.Rect.Min.Y)*src.AStride + (kx - src.Rect.Min.X)
+
+							// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+							pyy1 := int(src.Y[pi]) * 0x10101
+							pcb1 := int(src.Cb[pj]) - 128
+							pcr1 := int(src.Cr[pj]) - 128
+							pru0 := (pyy1 + 91881*pcr1) >> 8
+							pgu0 := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
+							pbu0 := (pyy1 + 116130*pcb1) >> 8
+							if pru0 < 0 {
+								pru0 = 0
+							} else if pru0 > 0xffff {
+								pru0 = 0xffff
+							}
+							if pgu0 < 0 {
+								pgu0 = 0
+							} else if pgu0 > 0xffff {
+								pgu0 = 0xffff
+							}
+							if pbu0 < 0 {
+								pbu0 = 0
+							} else if pbu0 > 0xffff {
+								pbu0 = 0xffff
+							}
+
+							pau := uint32(src.A[pk]) * 0x101
+							pru := uint32(pru0) * pau / 0xffff
+							pgu := uint32(pgu0) * pau / 0xffff
+							pbu := uint32(pbu0) * pau / 0xffff
+							pr += float64(float64(pru) * w)
+							pg += float64(float64(pgu) * w)
+							pb += float64(float64(pbu) * w)
+							pa += float64(float64(pau) * w)
+						}
+					}
 				}
 			}
-		}
-	}
-}
 
-func (z *kernelScaler) scaleX_Gray(tmp [][4]float64, src *image.Gray, sr image.Rectangle, opts *Options) {
-	t := 0
-	for y := int32(0); y < z.sh; y++ {
-		for _, s := range z.horizontal.sources {
-			var pr float64
-			for _, c := range z.horizontal.contribs[s.i:s.j] {
-				pi := (sr.Min.Y+int(y)-src.Rect.Min.Y)*src.Stride + (sr.Min.X + int(c.coord) - src.Rect.Min.X)
-				pru := uint32(src.Pix[pi]) * 0x101
-				pr += float64(float64(pru) * c.weight)
+			if pr > pa {
+				pr = pa
 			}
-			pr *= s.invTotalWeightFFFF
-			tmp[t] = [4]float64{
-				pr,
-				pr,
-				pr,
-				1,
+			if pg > pa {
+				pg = pa
 			}
-			t++
+			if pb > pa {
+				pb = pa
+			}
+
+			dst.Pix[d+0] = uint8(fffftou(pr) >> 8)
+			dst.Pix[d+1] = uint8(fffftou(pg) >> 8)
+			dst.Pix[d+2] = uint8(fffftou(pb) >> 8)
+			dst.Pix[d+3] = uint8(fffftou(pa) >> 8)
 		}
 	}
 }
 
-func (z *kernelScaler) scaleX_NRGBA(tmp [][4]float64, src *image.NRGBA, sr image.Rectangle, opts *Options) {
-	t := 0
-	for y := int32(0); y < z.sh; y++ {
-		for _, s := range z.horizontal.sources {
-			var pr, pg, pb, pa float64
-			for _, c := range z.horizontal.contribs[s.i:s.j] {
-				pi := (sr.Min.Y+int(y)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(c.coord)-src.Rect.Min.X)*4
-				pau := uint32(src.Pix[pi+3]) * 0x101
-				pru := uint32(src.Pix[pi+0]) * pau / 0xff
-				pgu := uint32(src.Pix[pi+1]) * pau / 0xff
-				pbu := uint32(src.Pix[pi+2]) * pau / 0xff
-				pr += float64(float64(pru) * c.weight)
-				pg += float64(float64(pgu) * c.weight)
-				pb += float64(float64(pbu) * c.weight)
-				pa += float64(float64(pau) * c.weight)
+func (q *Kernel) transform_RGBA_NYCbCrA440_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NYCbCrA, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+	// When shrinking, broaden the effective kernel support so that we still
+	// visit every source pixel.
+	xHalfWidth, xKernelArgScale := q.Support, 1.0
+	if xscale > 1 {
+		xHalfWidth *= xscale
+		xKernelArgScale = 1 / xscale
+	}
+	yHalfWidth, yKernelArgScale := q.Support, 1.0
+	if yscale > 1 {
+		yHalfWidth *= yscale
+		yKernelArgScale = 1 / yscale
+	}
+
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
 			}
-			tmp[t] = [4]float64{
-				pr * s.invTotalWeightFFFF,
-				pg * s.invTotalWeightFFFF,
-				pb * s.invTotalWeightFFFF,
-				pa * s.invTotalWeightFFFF,
+
+			// TODO: adjust the bias so that we can use int(f) instead
+			// of math.Floor(f) and math.Ceil(f).
+			sx += float64(bias.X)
+			sx -= 0.5
+			ix := int(math.Floor(sx - xHalfWidth))
+			if ix < sr.Min.X {
+				ix = sr.Min.X
+			}
+			jx := int(math.Ceil(sx + xHalfWidth))
+			if jx > sr.Max.X {
+				jx = sr.Max.X
+			}
+
+			totalXWeight := 0.0
+			for kx := ix; kx < jx; kx++ {
+				xWeight := 0.0
+				if t := abs((sx - float64(kx)) * xKernelArgScale); t < q.Support {
+					xWeight = q.At(t)
+				}
+				xWeights[kx-ix] = xWeight
+				totalXWeight += xWeight
+			}
+			for x := range xWeights[:jx-ix] {
+				xWeights[x] /= totalXWeight
+			}
+
+			sy += float64(bias.Y)
+			sy -= 0.5
+			iy := int(math.Floor(sy - yHalfWidth))
+			if iy < sr.Min.Y {
+				iy = sr.Min.Y
+			}
+			jy := int(math.Ceil(sy + yHalfWidth))
+			if jy > sr.Max.Y {
+				jy = sr.Max.Y
+			}
+
+			totalYWeight := 0.0
+			for ky := iy; ky < jy; ky++ {
+				yWeight := 0.0
+				if t := abs((sy - float64(ky)) * yKernelArgScale); t < q.Support {
+					yWeight = q.At(t)
+				}
+				yWeights[ky-iy] = yWeight
+				totalYWeight += yWeight
+			}
+			for y := range yWeights[:jy-iy] {
+				yWeights[y] /= totalYWeight
 			}
-			t++
-		}
-	}
-}
 
-func (z *kernelScaler) scaleX_RGBA(tmp [][4]float64, src *image.RGBA, sr image.Rectangle, opts *Options) {
-	t := 0
-	for y := int32(0); y < z.sh; y++ {
-		for _, s := range z.horizontal.sources {
 			var pr, pg, pb, pa float64
-			for _, c := range z.horizontal.contribs[s.i:s.j] {
-				pi := (sr.Min.Y+int(y)-src.Rect.Min.Y)*src.Stride + (sr.Min.X+int(c.coord)-src.Rect.Min.X)*4
-				pru := uint32(src.Pix[pi+0]) * 0x101
-				pgu := uint32(src.Pix[pi+1]) * 0x101
-				pbu := uint32(src.Pix[pi+2]) * 0x101
-				pau := uint32(src.Pix[pi+3]) * 0x101
-				pr += float64(float64(pru) * c.weight)
-				pg += float64(float64(pgu) * c.weight)
-				pb += float64(float64(pbu) * c.weight)
-				pa += float64(float64(pau) * c.weight)
+			for ky := iy; ky < jy; ky++ {
+				if yWeight := yWeights[ky-iy]; yWeight != 0 {
+					for kx := ix; kx < jx; kx++ {
+						if w := xWeights[kx-ix] * yWeight; w != 0 {
+							pi := (ky-src.Rect.Min.Y)*src.YStride + (kx - src.Rect.Min.X)
+							pj := ((ky)/2-src.Rect.Min.Y/2)*src.CStride + (kx - src.Rect.Min.X)
+							pk := (ky-src.Rect.Min.Y)*src.AStride + (kx - src.Rect.Min.X)
+
+							// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+							pyy1 := int(src.Y[pi]) * 0x10101
+							pcb1 := int(src.Cb[pj]) - 128
+							pcr1 := int(src.Cr[pj]) - 128
+							pru0 := (pyy1 + 91881*pcr1) >> 8
+							pgu0 := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
+							pbu0 := (pyy1 + 116130*pcb1) >> 8
+							if pru0 < 0 {
+								pru0 = 0
+							} else if pru0 > 0xffff {
+								pru0 = 0xffff
+							}
+							if pgu0 < 0 {
+								pgu0 = 0
+							} else if pgu0 > 0xffff {
+								pgu0 = 0xffff
+							}
+							if pbu0 < 0 {
+								pbu0 = 0
+							} else if pbu0 > 0xffff {
+								pbu0 = 0xffff
+							}
+
+							pau := uint32(src.A[pk]) * 0x101
+							pru := uint32(pru0) * pau / 0xffff
+							pgu := uint32(pgu0) * pau / 0xffff
+							pbu := uint32(pbu0) * pau / 0xffff
+							pr += float64(float64(pru) * w)
+							pg += float64(float64(pgu) * w)
+							pb += float64(float64(pbu) * w)
+							pa += float64(float64(pau) * w)
+						}
+					}
+				}
 			}
-			tmp[t] = [4]float64{
-				pr * s.invTotalWeightFFFF,
-				pg * s.invTotalWeightFFFF,
-				pb * s.invTotalWeightFFFF,
-				pa * s.invTotalWeightFFFF,
+
+			if pr > pa {
+				pr = pa
 			}
-			t++
+			if pg > pa {
+				pg = pa
+			}
+			if pb > pa {
+				pb = pa
+			}
+
+			dst.Pix[d+0] = uint8(fffftou(pr) >> 8)
+			dst.Pix[d+1] = uint8(fffftou(pg) >> 8)
+			dst.Pix[d+2] = uint8(fffftou(pb) >> 8)
+			dst.Pix[d+3] = uint8(fffftou(pa) >> 8)
 		}
 	}
 }
 
-func (z *kernelScaler) scaleX_YCbCr444(tmp [][4]float64, src *image.YCbCr, sr image.Rectangle, opts *Options) {
-	t := 0
-	for y := int32(0); y < z.sh; y++ {
-		for _, s := range z.horizontal.sources {
-			var pr, pg, pb float64
-			for _, c := range z.horizontal.contribs[s.i:s.j] {
-				pi := (sr.Min.Y+int(y)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(c.coord) - src.Rect.Min.X)
-				pj := (sr.Min.Y+int(y)-src.Rect.Min.Y)*src.CStride + (sr.Min.X + int(c.coord) - src.Rect.Min.X)
+func (q *Kernel) transform_RGBA_RGBA64Image_Over(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src image.RGBA64Image, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+	// When shrinking, broaden the effective kernel support so that we still
+	// visit every source pixel.
+	xHalfWidth, xKernelArgScale := q.Support, 1.0
+	if xscale > 1 {
+		xHalfWidth *= xscale
+		xKernelArgScale = 1 / xscale
+	}
+	yHalfWidth, yKernelArgScale := q.Support, 1.0
+	if yscale > 1 {
+		yHalfWidth *= yscale
+		yKernelArgScale = 1 / yscale
+	}
 
-				// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
-				pyy1 := int(src.Y[pi]) * 0x10101
-				pcb1 := int(src.Cb[pj]) - 128
-				pcr1 := int(src.Cr[pj]) - 128
-				pru := (pyy1 + 91881*pcr1) >> 8
-				pgu := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
-				pbu := (pyy1 + 116130*pcb1) >> 8
-				if pru < 0 {
-					pru = 0
-				} else if pru > 0xffff {
-					pru = 0xffff
-				}
-				if pgu < 0 {
-					pgu = 0
-				} else if pgu > 0xffff {
-					pgu = 0xffff
-				}
-				if pbu < 0 {
-					pbu = 0
-				} else if pbu > 0xffff {
-					pbu = 0xffff
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			// TODO: adjust the bias so that we can use int(f) instead
+			// of math.Floor(f) and math.Ceil(f).
+			sx += float64(bias.X)
+			sx -= 0.5
+			ix := int(math.Floor(sx - xHalfWidth))
+			if ix < sr.Min.X {
+				ix = sr.Min.X
+			}
+			jx := int(math.Ceil(sx + xHalfWidth))
+			if jx > sr.Max.X {
+				jx = sr.Max.X
+			}
+
+			totalXWeight := 0.0
+			for kx := ix; kx < jx; kx++ {
+				xWeight := 0.0
+				if t := abs((sx - float64(kx)) * xKernelArgScale); t < q.Support {
+					xWeight = q.At(t)
 				}
-
-				pr += float64(float64(pru) * c.weight)
-				pg += float64(float64(pgu) * c.weight)
-				pb += float64(float64(pbu) * c.weight)
+				xWeights[kx-ix] = xWeight
+				totalXWeight += xWeight
 			}
-			tmp[t] = [4]float64{
-				pr * s.invTotalWeightFFFF,
-				pg * s.invTotalWeightFFFF,
-				pb * s.invTotalWeightFFFF,
-				1,
+			for x := range xWeights[:jx-ix] {
+				xWeights[x] /= totalXWeight
 			}
-			t++
-		}
-	}
-}
 
-func (z *kernelScaler) scaleX_YCbCr422(tmp [][4]float64, src *image.YCbCr, sr image.Rectangle, opts *Options) {
-	t := 0
-	for y := int32(0); y < z.sh; y++ {
-		for _, s := range z.horizontal.sources {
-			var pr, pg, pb float64
-			for _, c := range z.horizontal.contribs[s.i:s.j] {
-				pi := (sr.Min.Y+int(y)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(c.coord) - src.Rect.Min.X)
-				pj := (sr.Min.Y+int(y)-src.Rect.Min.Y)*src.CStride + ((sr.Min.X+int(c.coord))/2 - src.Rect.Min.X/2)
+			sy += float64(bias.Y)
+			sy -= 0.5
+			iy := int(math.Floor(sy - yHalfWidth))
+			if iy < sr.Min.Y {
+				iy = sr.Min.Y
+			}
+			jy := int(math.Ceil(sy + yHalfWidth))
+			if jy > sr.Max.Y {
+				jy = sr.Max.Y
+			}
 
-				// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
-				pyy1 := int(src.Y[pi]) * 0x10101
-				pcb1 := int(src.Cb[pj]) - 128
-				pcr1 := int(src.Cr[pj]) - 128
-				pru := (pyy1 + 91881*pcr1) >> 8
-				pgu := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
-				pbu := (pyy1 + 116130*pcb1) >> 8
-				if pru < 0 {
-					pru = 0
-				} else if pru > 0xffff {
-					pru = 0xffff
-				}
-				if pgu < 0 {
-					pgu = 0
-				} else if pgu > 0xffff {
-					pgu = 0xffff
+			totalYWeight := 0.0
+			for ky := iy; ky < jy; ky++ {
+				yWeight := 0.0
+				if t := abs((sy - float64(ky)) * yKernelArgScale); t < q.Support {
+					yWeight = q.At(t)
 				}
-				if pbu < 0 {
-					pbu = 0
-				} else if pbu > 0xffff {
-					pbu = 0xffff
+				yWeights[ky-iy] = yWeight
+				totalYWeight += yWeight
+			}
+			for y := range yWeights[:jy-iy] {
+				yWeights[y] /= totalYWeight
+			}
+
+			var pr, pg, pb, pa float64
+			for ky := iy; ky < jy; ky++ {
+				if yWeight := yWeights[ky-iy]; yWeight != 0 {
+					for kx := ix; kx < jx; kx++ {
+						if w := xWeights[kx-ix] * yWeight; w != 0 {
+							pu := src.RGBA64At(kx, ky)
+							pr += float64(float64(pu.R) * w)
+							pg += float64(float64(pu.G) * w)
+							pb += float64(float64(pu.B) * w)
+							pa += float64(float64(pu.A) * w)
+						}
+					}
 				}
+			}
 
-				pr += float64(float64(pru) * c.weight)
-				pg += float64(float64(pgu) * c.weight)
-				pb += float64(float64(pbu) * c.weight)
+			if pr > pa {
+				pr = pa
 			}
-			tmp[t] = [4]float64{
-				pr * s.invTotalWeightFFFF,
-				pg * s.invTotalWeightFFFF,
-				pb * s.invTotalWeightFFFF,
-				1,
+			if pg > pa {
+				pg = pa
 			}
-			t++
+			if pb > pa {
+				pb = pa
+			}
+
+			pr0 := uint32(fffftou(pr))
+			pg0 := uint32(fffftou(pg))
+			pb0 := uint32(fffftou(pb))
+			pa0 := uint32(fffftou(pa))
+			pa1 := (0xffff - uint32(pa0)) * 0x101
+			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pr0) >> 8)
+			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + pg0) >> 8)
+			dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*pa1/0xffff + pb0) >> 8)
+			dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*pa1/0xffff + pa0) >> 8)
 		}
 	}
 }
 
-func (z *kernelScaler) scaleX_YCbCr420(tmp [][4]float64, src *image.YCbCr, sr image.Rectangle, opts *Options) {
-	t := 0
-	for y := int32(0); y < z.sh; y++ {
-		for _, s := range z.horizontal.sources {
-			var pr, pg, pb float64
-			for _, c := range z.horizontal.contribs[s.i:s.j] {
-				pi := (sr.Min.Y+int(y)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(c.coord) - src.Rect.Min.X)
-				pj := ((sr.Min.Y+int(y))/2-src.Rect.Min.Y/2)*src.CStride + ((sr.Min.X+int(c.coord))/2 - src.Rect.Min.X/2)
+func (q *Kernel) transform_RGBA_RGBA64Image_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src image.RGBA64Image, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+	// When shrinking, broaden the effective kernel support so that we still
+	// visit every source pixel.
+	xHalfWidth, xKernelArgScale := q.Support, 1.0
+	if xscale > 1 {
+		xHalfWidth *= xscale
+		xKernelArgScale = 1 / xscale
+	}
+	yHalfWidth, yKernelArgScale := q.Support, 1.0
+	if yscale > 1 {
+		yHalfWidth *= yscale
+		yKernelArgScale = 1 / yscale
+	}
 
-				// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
-				pyy1 := int(src.Y[pi]) * 0x10101
-				pcb1 := int(src.Cb[pj]) - 128
-				pcr1 := int(src.Cr[pj]) - 128
-				pru := (pyy1 + 91881*pcr1) >> 8
-				pgu := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
-				pbu := (pyy1 + 116130*pcb1) >> 8
-				if pru < 0 {
-					pru = 0
-				} else if pru > 0xffff {
-					pru = 0xffff
-				}
-				if pgu < 0 {
-					pgu = 0
-				} else if pgu > 0xffff {
-					pgu = 0xffff
-				}
-				if pbu < 0 {
-					pbu = 0
-				} else if pbu > 0xffff {
-					pbu = 0xffff
-				}
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
 
-				pr += float64(float64(pru) * c.weight)
-				pg += float64(float64(pgu) * c.weight)
-				pb += float64(float64(pbu) * c.weight)
-			}
-			tmp[t] = [4]float64{
-				pr * s.invTotalWeightFFFF,
-				pg * s.invTotalWeightFFFF,
-				pb * s.invTotalWeightFFFF,
-				1,
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
 			}
-			t++
-		}
-	}
-}
 
-func (z *kernelScaler) scaleX_YCbCr440(tmp [][4]float64, src *image.YCbCr, sr image.Rectangle, opts *Options) {
-	t := 0
-	for y := int32(0); y < z.sh; y++ {
-		for _, s := range z.horizontal.sources {
-			var pr, pg, pb float64
-			for _, c := range z.horizontal.contribs[s.i:s.j] {
-				pi := (sr.Min.Y+int(y)-src.Rect.Min.Y)*src.YStride + (sr.Min.X + int(c.coord) - src.Rect.Min.X)
-				pj := ((sr.Min.Y+int(y))/2-src.Rect.Min.Y/2)*src.CStride + (sr.Min.X + int(c.coord) - src.Rect.Min.X)
+			// TODO: adjust the bias so that we can use int(f) instead
+			// of math.Floor(f) and math.Ceil(f).
+			sx += float64(bias.X)
+			sx -= 0.5
+			ix := int(math.Floor(sx - xHalfWidth))
+			if ix < sr.Min.X {
+				ix = sr.Min.X
+			}
+			jx := int(math.Ceil(sx + xHalfWidth))
+			if jx > sr.Max.X {
+				jx = sr.Max.X
+			}
 
-				// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
-				pyy1 := int(src.Y[pi]) * 0x10101
-				pcb1 := int(src.Cb[pj]) - 128
-				pcr1 := int(src.Cr[pj]) - 128
-				pru := (pyy1 + 91881*pcr1) >> 8
-				pgu := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
-				pbu := (pyy1 + 116130*pcb1) >> 8
-				if pru < 0 {
-					pru = 0
-				} else if pru > 0xffff {
-					pru = 0xffff
+			totalXWeight := 0.0
+			for kx := ix; kx < jx; kx++ {
+				xWeight := 0.0
+				if t := abs((sx - float64(kx)) * xKernelArgScale); t < q.Support {
+					xWeight = q.At(t)
 				}
-				if pgu < 0 {
-					pgu = 0
-				} else if pgu > 0xffff {
-					pgu = 0xffff
+				xWeights[kx-ix] = xWeight
+				totalXWeight += xWeight
+			}
+			for x := range xWeights[:jx-ix] {
+				xWeights[x] /= totalXWeight
+			}
+
+			sy += float64(bias.Y)
+			sy -= 0.5
+			iy := int(math.Floor(sy - yHalfWidth))
+			if iy < sr.Min.Y {
+				iy = sr.Min.Y
+			}
+			jy := int(math.Ceil(sy + yHalfWidth))
+			if jy > sr.Max.Y {
+				jy = sr.Max.Y
+			}
+
+			totalYWeight := 0.0
+			for ky := iy; ky < jy; ky++ {
+				yWeight := 0.0
+				if t := abs((sy - float64(ky)) * yKernelArgScale); t < q.Support {
+					yWeight = q.At(t)
 				}
-				if pbu < 0 {
-					pbu = 0
-				} else if pbu > 0xffff {
-					pbu = 0xffff
+				yWeights[ky-iy] = yWeight
+				totalYWeight += yWeight
+			}
+			for y := range yWeights[:jy-iy] {
+				yWeights[y] /= totalYWeight
+			}
+
+			var pr, pg, pb, pa float64
+			for ky := iy; ky < jy; ky++ {
+				if yWeight := yWeights[ky-iy]; yWeight != 0 {
+					for kx := ix; kx < jx; kx++ {
+						if w := xWeights[kx-ix] * yWeight; w != 0 {
+							pu := src.RGBA64At(kx, ky)
+							pr += float64(float64(pu.R) * w)
+							pg += float64(float64(pu.G) * w)
+							pb += float64(float64(pu.B) * w)
+							pa += float64(float64(pu.A) * w)
+						}
+					}
 				}
+			}
 
-				pr += float64(float64(pru) * c.weight)
-				pg += float64(float64(pgu) * c.weight)
-				pb += float64(float64(pbu) * c.weight)
+			if pr > pa {
+				pr = pa
 			}
-			tmp[t] = [4]float64{
-				pr * s.invTotalWeightFFFF,
-				pg * s.invTotalWeightFFFF,
-				pb * s.invTotalWeightFFFF,
-				1,
+			if pg > pa {
+				pg = pa
 			}
-			t++
+			if pb > pa {
+				pb = pa
+			}
+
+			dst.Pix[d+0] = uint8(fffftou(pr) >> 8)
+			dst.Pix[d+1] = uint8(fffftou(pg) >> 8)
+			dst.Pix[d+2] = uint8(fffftou(pb) >> 8)
+			dst.Pix[d+3] = uint8(fffftou(pa) >> 8)
 		}
 	}
 }
 
-func (z *kernelScaler) scaleX_RGBA64Image(tmp [][4]float64, src image.RGBA64Image, sr image.Rectangle, opts *Options) {
-	t := 0
-	srcMask, smp := opts.SrcMask, opts.SrcMaskP
-	for y := int32(0); y < z.sh; y++ {
-		for _, s := range z.horizontal.sources {
-			var pr, pg, pb, pa float64
-			for _, c := range z.horizontal.contribs[s.i:s.j] {
-				pu := src.RGBA64At(sr.Min.X+int(c.coord), sr.Min.Y+int(y))
-				if srcMask != nil {
-					_, _, _, ma := srcMask.At(smp.X+sr.Min.X+int(c.coord), smp.Y+sr.Min.Y+int(y)).RGBA()
-					pu.R = uint16(uint32(pu.R) * ma / 0xffff)
-					pu.G = uint16(uint32(pu.G) * ma / 0xffff)
-					pu.B = uint16(uint32(pu.B) * ma / 0xffff)
-					pu.A = uint16(uint32(pu.A) * ma / 0xffff)
+func (q *Kernel) transform_RGBA_Image_Over(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src image.Image, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+	// When shrinking, broaden the effective kernel support so that we still
+	// visit every source pixel.
+	xHalfWidth, xKernelArgScale := q.Support, 1.0
+	if xscale > 1 {
+		xHalfWidth *= xscale
+		xKernelArgScale = 1 / xscale
+	}
+	yHalfWidth, yKernelArgScale := q.Support, 1.0
+	if yscale > 1 {
+		yHalfWidth *= yscale
+		yKernelArgScale = 1 / yscale
+	}
+
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			// TODO: adjust the bias so that we can use int(f) instead
+			// of math.Floor(f) and math.Ceil(f).
+			sx += float64(bias.X)
+			sx -= 0.5
+			ix := int(math.Floor(sx - xHalfWidth))
+			if ix < sr.Min.X {
+				ix = sr.Min.X
+			}
+			jx := int(math.Ceil(sx + xHalfWidth))
+			if jx > sr.Max.X {
+				jx = sr.Max.X
+			}
+
+			totalXWeight := 0.0
+			for kx := ix; kx < jx; kx++ {
+				xWeight := 0.0
+				if t := abs((sx - float64(kx)) * xKernelArgScale); t < q.Support {
+					xWeight = q.At(t)
 				}
-				pr += float64(float64(pu.R) * c.weight)
-				pg += float64(float64(pu.G) * c.weight)
-				pb += float64(float64(pu.B) * c.weight)
-				pa += float64(float64(pu.A) * c.weight)
+				xWeights[kx-ix] = xWeight
+				totalXWeight += xWeight
 			}
-			tmp[t] = [4]float64{
-				pr * s.invTotalWeightFFFF,
-				pg * s.invTotalWeightFFFF,
-				pb * s.invTotalWeightFFFF,
-				pa * s.invTotalWeightFFFF,
+			for x := range xWeights[:jx-ix] {
+				xWeights[x] /= totalXWeight
 			}
-			t++
-		}
-	}
-}
 
-func (z *kernelScaler) scaleX_Image(tmp [][4]float64, src image.Image, sr image.Rectangle, opts *Options) {
-	t := 0
-	srcMask, smp := opts.SrcMask, opts.SrcMaskP
-	for y := int32(0); y < z.sh; y++ {
-		for _, s := range z.horizontal.sources {
-			var pr, pg, pb, pa float64
-			for _, c := range z.horizontal.contribs[s.i:s.j] {
-				pru, pgu, pbu, pau := src.At(sr.Min.X+int(c.coord), sr.Min.Y+int(y)).RGBA()
-				if srcMask != nil {
-					_, _, _, ma := srcMask.At(smp.X+sr.Min.X+int(c.coord), smp.Y+sr.Min.Y+int(y)).RGBA()
-					pru = pru * ma / 0xffff
-					pgu = pgu * ma / 0xffff
-					pbu = pbu * ma / 0xffff
-					pau = pau * ma / 0xffff
+			sy += float64(bias.Y)
+			sy -= 0.5
+			iy := int(math.Floor(sy - yHalfWidth))
+			if iy < sr.Min.Y {
+				iy = sr.Min.Y
+			}
+			jy := int(math.Ceil(sy + yHalfWidth))
+			if jy > sr.Max.Y {
+				jy = sr.Max.Y
+			}
+
+			totalYWeight := 0.0
+			for ky := iy; ky < jy; ky++ {
+				yWeight := 0.0
+				if t := abs((sy - float64(ky)) * yKernelArgScale); t < q.Support {
+					yWeight = q.At(t)
 				}
-				pr += float64(float64(pru) * c.weight)
-				pg += float64(float64(pgu) * c.weight)
-				pb += float64(float64(pbu) * c.weight)
-				pa += float64(float64(pau) * c.weight)
+				yWeights[ky-iy] = yWeight
+				totalYWeight += yWeight
 			}
-			tmp[t] = [4]float64{
-				pr * s.invTotalWeightFFFF,
-				pg * s.invTotalWeightFFFF,
-				pb * s.invTotalWeightFFFF,
-				pa * s.invTotalWeightFFFF,
+			for y := range yWeights[:jy-iy] {
+				yWeights[y] /= totalYWeight
 			}
-			t++
-		}
-	}
-}
 
-func (z *kernelScaler) scaleY_RGBA_Over(dst *image.RGBA, dr, adr image.Rectangle, tmp [][4]float64, opts *Options) {
-	for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
-		d := (dr.Min.Y+adr.Min.Y-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+int(dx)-dst.Rect.Min.X)*4
-		for _, s := range z.vertical.sources[adr.Min.Y:adr.Max.Y] {
 			var pr, pg, pb, pa float64
-			for _, c := range z.vertical.contribs[s.i:s.j] {
-				p := &tmp[c.coord*z.dw+dx]
-				pr += float64(p[0] * c.weight)
-				pg += float64(p[1] * c.weight)
-				pb += float64(p[2] * c.weight)
-				pa += float64(p[3] * c.weight)
+			for ky := iy; ky < jy; ky++ {
+				if yWeight := yWeights[ky-iy]; yWeight != 0 {
+					for kx := ix; kx < jx; kx++ {
+						if w := xWeights[kx-ix] * yWeight; w != 0 {
+							pru, pgu, pbu, pau := src.At(kx, ky).RGBA()
+							pr += float64(float64(pru) * w)
+							pg += float64(float64(pgu) * w)
+							pb += float64(float64(pbu) * w)
+							pa += float64(float64(pau) * w)
+						}
+					}
+				}
 			}
 
 			if pr > pa {
@@ -6124,31 +21449,112 @@ func (z *kernelScaler) scaleY_RGBA_Over(dst *image.RGBA, dr, adr image.Rectangle
 				pb = pa
 			}
 
-			pr0 := uint32(ftou(pr * s.invTotalWeight))
-			pg0 := uint32(ftou(pg * s.invTotalWeight))
-			pb0 := uint32(ftou(pb * s.invTotalWeight))
-			pa0 := uint32(ftou(pa * s.invTotalWeight))
+			pr0 := uint32(fffftou(pr))
+			pg0 := uint32(fffftou(pg))
+			pb0 := uint32(fffftou(pb))
+			pa0 := uint32(fffftou(pa))
 			pa1 := (0xffff - uint32(pa0)) * 0x101
 			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pr0) >> 8)
 			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + pg0) >> 8)
 			dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*pa1/0xffff + pb0) >> 8)
 			dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*pa1/0xffff + pa0) >> 8)
-			d += dst.Stride
 		}
 	}
 }
 
-func (z *kernelScaler) scaleY_RGBA_Src(dst *image.RGBA, dr, adr image.Rectangle, tmp [][4]float64, opts *Options) {
-	for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
-		d := (dr.Min.Y+adr.Min.Y-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+int(dx)-dst.Rect.Min.X)*4
-		for _, s := range z.vertical.sources[adr.Min.Y:adr.Max.Y] {
+func (q *Kernel) transform_RGBA_Image_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src image.Image, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+	// When shrinking, broaden the effective kernel support so that we still
+	// visit every source pixel.
+	xHalfWidth, xKernelArgScale := q.Support, 1.0
+	if xscale > 1 {
+		xHalfWidth *= xscale
+		xKernelArgScale = 1 / xscale
+	}
+	yHalfWidth, yKernelArgScale := q.Support, 1.0
+	if yscale > 1 {
+		yHalfWidth *= yscale
+		yKernelArgScale = 1 / yscale
+	}
+
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			// TODO: adjust the bias so that we can use int(f) instead
+			// of math.Floor(f) and math.Ceil(f).
+			sx += float64(bias.X)
+			sx -= 0.5
+			ix := int(math.Floor(sx - xHalfWidth))
+			if ix < sr.Min.X {
+				ix = sr.Min.X
+			}
+			jx := int(math.Ceil(sx + xHalfWidth))
+			if jx > sr.Max.X {
+				jx = sr.Max.X
+			}
+
+			totalXWeight := 0.0
+			for kx := ix; kx < jx; kx++ {
+				xWeight := 0.0
+				if t := abs((sx - float64(kx)) * xKernelArgScale); t < q.Support {
+					xWeight = q.At(t)
+				}
+				xWeights[kx-ix] = xWeight
+				totalXWeight += xWeight
+			}
+			for x := range xWeights[:jx-ix] {
+				xWeights[x] /= totalXWeight
+			}
+
+			sy += float64(bias.Y)
+			sy -= 0.5
+			iy := int(math.Floor(sy - yHalfWidth))
+			if iy < sr.Min.Y {
+				iy = sr.Min.Y
+			}
+			jy := int(math.Ceil(sy + yHalfWidth))
+			if jy > sr.Max.Y {
+				jy = sr.Max.Y
+			}
+
+			totalYWeight := 0.0
+			for ky := iy; ky < jy; ky++ {
+				yWeight := 0.0
+				if t := abs((sy - float64(ky)) * yKernelArgScale); t < q.Support {
+					yWeight = q.At(t)
+				}
+				yWeights[ky-iy] = yWeight
+				totalYWeight += yWeight
+			}
+			for y := range yWeights[:jy-iy] {
+				yWeights[y] /= totalYWeight
+			}
+
 			var pr, pg, pb, pa float64
-			for _, c := range z.vertical.contribs[s.i:s.j] {
-				p := &tmp[c.coord*z.dw+dx]
-				pr += float64(p[0] * c.weight)
-				pg += float64(p[1] * c.weight)
-				pb += float64(p[2] * c.weight)
-				pa += float64(p[3] * c.weight)
+			for ky := iy; ky < jy; ky++ {
+				if yWeight := yWeights[ky-iy]; yWeight != 0 {
+					for kx := ix; kx < jx; kx++ {
+						if w := xWeights[kx-ix] * yWeight; w != 0 {
+							pru, pgu, pbu, pau := src.At(kx, ky).RGBA()
+							pr += float64(float64(pru) * w)
+							pg += float64(float64(pgu) * w)
+							pb += float64(float64(pbu) * w)
+							pa += float64(float64(pau) * w)
+						}
+					}
+				}
 			}
 
 			if pr > pa {
@@ -6161,75 +21567,111 @@ func (z *kernelScaler) scaleY_RGBA_Src(dst *image.RGBA, dr, adr image.Rectangle,
 				pb = pa
 			}
 
-			dst.Pix[d+0] = uint8(ftou(pr*s.invTotalWeight) >> 8)
-			dst.Pix[d+1] = uint8(ftou(pg*s.invTotalWeight) >> 8)
-			dst.Pix[d+2] = uint8(ftou(pb*s.invTotalWeight) >> 8)
-			dst.Pix[d+3] = uint8(ftou(pa*s.invTotalWeight) >> 8)
-			d += dst.Stride
+			dst.Pix[d+0] = uint8(fffftou(pr) >> 8)
+			dst.Pix[d+1] = uint8(fffftou(pg) >> 8)
+			dst.Pix[d+2] = uint8(fffftou(pb) >> 8)
+			dst.Pix[d+3] = uint8(fffftou(pa) >> 8)
 		}
 	}
 }
 
-func (z *kernelScaler) scaleY_RGBA64Image_Over(dst RGBA64Image, dr, adr image.Rectangle, tmp [][4]float64, opts *Options) {
-	dstMask, dmp := opts.DstMask, opts.DstMaskP
-	dstColorRGBA64 := color.RGBA64{}
+func (q *Kernel) transform_RGBA64_NRGBA64_Over(dst *image.RGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NRGBA64, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+	// When shrinking, broaden the effective kernel support so that we still
+	// visit every source pixel.
+	xHalfWidth, xKernelArgScale := q.Support, 1.0
+	if xscale > 1 {
+		xHalfWidth *= xscale
+		xKernelArgScale = 1 / xscale
+	}
+	yHalfWidth, yKernelArgScale := q.Support, 1.0
+	if yscale > 1 {
+		yHalfWidth *= yscale
+		yKernelArgScale = 1 / yscale
+	}
 
-	for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
-		for dy, s := range z.vertical.sources[adr.Min.Y:adr.Max.Y] {
-			var pr, pg, pb, pa float64
-			for _, c := range z.vertical.contribs[s.i:s.j] {
-				p := &tmp[c.coord*z.dw+dx]
-				pr += float64(p[0] * c.weight)
-				pg += float64(p[1] * c.weight)
-				pb += float64(p[2] * c.weight)
-				pa += float64(p[3] * c.weight)
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
 			}
 
-			if pr > pa {
-				pr = pa
+			// TODO: adjust the bias so that we can use int(f) instead
+			// of math.Floor(f) and math.Ceil(f).
+			sx += float64(bias.X)
+			sx -= 0.5
+			ix := int(math.Floor(sx - xHalfWidth))
+			if ix < sr.Min.X {
+				ix = sr.Min.X
 			}
-			if pg > pa {
-				pg = pa
+			jx := int(math.Ceil(sx + xHalfWidth))
+			if jx > sr.Max.X {
+				jx = sr.Max.X
 			}
-			if pb > pa {
-				pb = pa
+
+			totalXWeight := 0.0
+			for kx := ix; kx < jx; kx++ {
+				xWeight := 0.0
+				if t := abs((sx - float64(kx)) * xKernelArgScale); t < q.Support {
+					xWeight = q.At(t)
+				}
+				xWeights[kx-ix] = xWeight
+				totalXWeight += xWeight
+			}
+			for x := range xWeights[:jx-ix] {
+				xWeights[x] /= totalXWeight
 			}
 
-			q := dst.RGBA64At(dr.Min.X+int(dx), dr.Min.Y+int(adr.Min.Y+dy))
-			pr0 := uint32(ftou(pr * s.invTotalWeight))
-			pg0 := uint32(ftou(pg * s.invTotalWeight))
-			pb0 := uint32(ftou(pb * s.invTotalWeight))
-			pa0 := uint32(ftou(pa * s.invTotalWeight))
-			if dstMask != nil {
-				_, _, _, ma := dstMask.At(dmp.X+dr.Min.X+int(dx), dmp.Y+dr.Min.Y+int(adr.Min.Y+dy)).RGBA()
-				pr0 = pr0 * ma / 0xffff
-				pg0 = pg0 * ma / 0xffff
-				pb0 = pb0 * ma / 0xffff
-				pa0 = pa0 * ma / 0xffff
+			sy += float64(bias.Y)
+			sy -= 0.5
+			iy := int(math.Floor(sy - yHalfWidth))
+			if iy < sr.Min.Y {
+				iy = sr.Min.Y
+			}
+			jy := int(math.Ceil(sy + yHalfWidth))
+			if jy > sr.Max.Y {
+				jy = sr.Max.Y
 			}
-			pa1 := 0xffff - pa0
-			dstColorRGBA64.R = uint16(uint32(q.R)*pa1/0xffff + pr0)
-			dstColorRGBA64.G = uint16(uint32(q.G)*pa1/0xffff + pg0)
-			dstColorRGBA64.B = uint16(uint32(q.B)*pa1/0xffff + pb0)
-			dstColorRGBA64.A = uint16(uint32(q.A)*pa1/0xffff + pa0)
-			dst.SetRGBA64(dr.Min.X+int(dx), dr.Min.Y+int(adr.Min.Y+dy), dstColorRGBA64)
-		}
-	}
-}
 
-func (z *kernelScaler) scaleY_RGBA64Image_Src(dst RGBA64Image, dr, adr image.Rectangle, tmp [][4]float64, opts *Options) {
-	dstMask, dmp := opts.DstMask, opts.DstMaskP
-	dstColorRGBA64 := color.RGBA64{}
+			totalYWeight := 0.0
+			for ky := iy; ky < jy; ky++ {
+				yWeight := 0.0
+				if t := abs((sy - float64(ky)) * yKernelArgScale); t < q.Support {
+					yWeight = q.At(t)
+				}
+				yWeights[ky-iy] = yWeight
+				totalYWeight += yWeight
+			}
+			for y := range yWeights[:jy-iy] {
+				yWeights[y] /= totalYWeight
+			}
 
-	for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
-		for dy, s := range z.vertical.sources[adr.Min.Y:adr.Max.Y] {
 			var pr, pg, pb, pa float64
-			for _, c := range z.vertical.contribs[s.i:s.j] {
-				p := &tmp[c.coord*z.dw+dx]
-				pr += float64(p[0] * c.weight)
-				pg += float64(p[1] * c.weight)
-				pb += float64(p[2] * c.weight)
-				pa += float64(p[3] * c.weight)
+			for ky := iy; ky < jy; ky++ {
+				if yWeight := yWeights[ky-iy]; yWeight != 0 {
+					for kx := ix; kx < jx; kx++ {
+						if w := xWeights[kx-ix] * yWeight; w != 0 {
+							pi := (ky-src.Rect.Min.Y)*src.Stride + (kx-src.Rect.Min.X)*8
+							pau := (uint32(src.Pix[pi+6])<<8 | uint32(src.Pix[pi+7]))
+							pru := (uint32(src.Pix[pi+0])<<8 | uint32(src.Pix[pi+1])) * pau / 0xffff
+							pgu := (uint32(src.Pix[pi+2])<<8 | uint32(src.Pix[pi+3])) * pau / 0xffff
+							pbu := (uint32(src.Pix[pi+4])<<8 | uint32(src.Pix[pi+5])) * pau / 0xffff
+							pr += float64(float64(pru) * w)
+							pg += float64(float64(pgu) * w)
+							pb += float64(float64(pbu) * w)
+							pa += float64(float64(pau) * w)
+						}
+					}
+				}
 			}
 
 			if pr > pa {
@@ -6242,43 +21684,120 @@ func (z *kernelScaler) scaleY_RGBA64Image_Src(dst RGBA64Image, dr, adr image.Rec
 				pb = pa
 			}
 
-			if dstMask != nil {
-				q := dst.RGBA64At(dr.Min.X+int(dx), dr.Min.Y+int(adr.Min.Y+dy))
-				_, _, _, ma := dstMask.At(dmp.X+dr.Min.X+int(dx), dmp.Y+dr.Min.Y+int(adr.Min.Y+dy)).RGBA()
-				pr := uint32(ftou(pr*s.invTotalWeight)) * ma / 0xffff
-				pg := uint32(ftou(pg*s.invTotalWeight)) * ma / 0xffff
-				pb := uint32(ftou(pb*s.invTotalWeight)) * ma / 0xffff
-				pa := uint32(ftou(pa*s.invTotalWeight)) * ma / 0xffff
-				pa1 := 0xffff - ma
-				dstColorRGBA64.R = uint16(uint32(q.R)*pa1/0xffff + pr)
-				dstColorRGBA64.G = uint16(uint32(q.G)*pa1/0xffff + pg)
-				dstColorRGBA64.B = uint16(uint32(q.B)*pa1/0xffff + pb)
-				dstColorRGBA64.A = uint16(uint32(q.A)*pa1/0xffff + pa)
-				dst.SetRGBA64(dr.Min.X+int(dx), dr.Min.Y+int(adr.Min.Y+dy), dstColorRGBA64)
-			} else {
-				dstColorRGBA64.R = ftou(pr * s.invTotalWeight)
-				dstColorRGBA64.G = ftou(pg * s.invTotalWeight)
-				dstColorRGBA64.B = ftou(pb * s.invTotalWeight)
-				dstColorRGBA64.A = ftou(pa * s.invTotalWeight)
-				dst.SetRGBA64(dr.Min.X+int(dx), dr.Min.Y+int(adr.Min.Y+dy), dstColorRGBA64)
-			}
+			pa1 := 0xffff - uint32(fffftou(pa))
+			pr0 := (uint32(dst.Pix[d+0])<<8|uint32(dst.Pix[d+1]))*pa1/0xffff + uint32(fffftou(pr))
+			pg0 := (uint32(dst.Pix[d+2])<<8|uint32(dst.Pix[d+3]))*pa1/0xffff + uint32(fffftou(pg))
+			pb0 := (uint32(dst.Pix[d+4])<<8|uint32(dst.Pix[d+5]))*pa1/0xffff + uint32(fffftou(pb))
+			pa0 := (uint32(dst.Pix[d+6])<<8|uint32(dst.Pix[d+7]))*pa1/0xffff + uint32(fffftou(pa))
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
 		}
 	}
 }
 
-func (z *kernelScaler) scaleY_Image_Over(dst Image, dr, adr image.Rectangle, tmp [][4]float64, opts *Options) {
-	dstMask, dmp := opts.DstMask, opts.DstMaskP
-	dstColorRGBA64 := &color.RGBA64{}
-	dstColor := color.Color(dstColorRGBA64)
-	for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
-		for dy, s := range z.vertical.sources[adr.Min.Y:adr.Max.Y] {
-			var pr, pg, pb, pa float64
-			for _, c := range z.vertical.contribs[s.i:s.j] {
-				p := &tmp[c.coord*z.dw+dx]
-				pr += float64(p[0] * c.weight)
-				pg += float64(p[1] * c.weight)
-				pb += float64(p[2] * c.weight)
-				pa += float64(p[3] * c.weight)
+func (q *Kernel) transform_RGBA64_NRGBA64_Src(dst *image.RGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NRGBA64, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+	// When shrinking, broaden the effective kernel support so that we still
+	// visit every source pixel.
+	xHalfWidth, xKernelArgScale := q.Support, 1.0
+	if xscale > 1 {
+		xHalfWidth *= xscale
+		xKernelArgScale = 1 / xscale
+	}
+	yHalfWidth, yKernelArgScale := q.Support, 1.0
+	if yscale > 1 {
+		yHalfWidth *= yscale
+		yKernelArgScale = 1 / yscale
+	}
+
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			// TODO: adjust the bias so that we can use int(f) instead
+			// of math.Floor(f) and math.Ceil(f).
+			sx += float64(bias.X)
+			sx -= 0.5
+			ix := int(math.Floor(sx - xHalfWidth))
+			if ix < sr.Min.X {
+				ix = sr.Min.X
+			}
+			jx := int(math.Ceil(sx + xHalfWidth))
+			if jx > sr.Max.X {
+				jx = sr.Max.X
+			}
+
+			totalXWeight := 0.0
+			for kx := ix; kx < jx; kx++ {
+				xWeight := 0.0
+				if t := abs((sx - float64(kx)) * xKernelArgScale); t < q.Support {
+					xWeight = q.At(t)
+				}
+				xWeights[kx-ix] = xWeight
+				totalXWeight += xWeight
+			}
+			for x := range xWeights[:jx-ix] {
+				xWeights[x] /= totalXWeight
+			}
+
+			sy += float64(bias.Y)
+			sy -= 0.5
+			iy := int(math.Floor(sy - yHalfWidth))
+			if iy < sr.Min.Y {
+				iy = sr.Min.Y
+			}
+			jy := int(math.Ceil(sy + yHalfWidth))
+			if jy > sr.Max.Y {
+				jy = sr.Max.Y
+			}
+
+			totalYWeight := 0.0
+			for ky := iy; ky < jy; ky++ {
+				yWeight := 0.0
+				if t := abs((sy - float64(ky)) * yKernelArgScale); t < q.Support {
+					yWeight = q.At(t)
+				}
+				yWeights[ky-iy] = yWeight
+				totalYWeight += yWeight
+			}
+			for y := range yWeights[:jy-iy] {
+				yWeights[y] /= totalYWeight
+			}
+
+			var pr, pg, pb, pa float64
+			for ky := iy; ky < jy; ky++ {
+				if yWeight := yWeights[ky-iy]; yWeight != 0 {
+					for kx := ix; kx < jx; kx++ {
+						if w := xWeights[kx-ix] * yWeight; w != 0 {
+							pi := (ky-src.Rect.Min.Y)*src.Stride + (kx-src.Rect.Min.X)*8
+							pau := (uint32(src.Pix[pi+6])<<8 | uint32(src.Pix[pi+7]))
+							pru := (uint32(src.Pix[pi+0])<<8 | uint32(src.Pix[pi+1])) * pau / 0xffff
+							pgu := (uint32(src.Pix[pi+2])<<8 | uint32(src.Pix[pi+3])) * pau / 0xffff
+							pbu := (uint32(src.Pix[pi+4])<<8 | uint32(src.Pix[pi+5])) * pau / 0xffff
+							pr += float64(float64(pru) * w)
+							pg += float64(float64(pgu) * w)
+							pb += float64(float64(pbu) * w)
+							pa += float64(float64(pau) * w)
+						}
+					}
+				}
 			}
 
 			if pr > pa {
@@ -6291,41 +21810,119 @@ func (z *kernelScaler) scaleY_Image_Over(dst Image, dr, adr image.Rectangle, tmp
 				pb = pa
 			}
 
-			qr, qg, qb, qa := dst.At(dr.Min.X+int(dx), dr.Min.Y+int(adr.Min.Y+dy)).RGBA()
-			pr0 := uint32(ftou(pr * s.invTotalWeight))
-			pg0 := uint32(ftou(pg * s.invTotalWeight))
-			pb0 := uint32(ftou(pb * s.invTotalWeight))
-			pa0 := uint32(ftou(pa * s.invTotalWeight))
-			if dstMask != nil {
-				_, _, _, ma := dstMask.At(dmp.X+dr.Min.X+int(dx), dmp.Y+dr.Min.Y+int(adr.Min.Y+dy)).RGBA()
-				pr0 = pr0 * ma / 0xffff
-				pg0 = pg0 * ma / 0xffff
-				pb0 = pb0 * ma / 0xffff
-				pa0 = pa0 * ma / 0xffff
-			}
-			pa1 := 0xffff - pa0
-			dstColorRGBA64.R = uint16(qr*pa1/0xffff + pr0)
-			dstColorRGBA64.G = uint16(qg*pa1/0xffff + pg0)
-			dstColorRGBA64.B = uint16(qb*pa1/0xffff + pb0)
-			dstColorRGBA64.A = uint16(qa*pa1/0xffff + pa0)
-			dst.Set(dr.Min.X+int(dx), dr.Min.Y+int(adr.Min.Y+dy), dstColor)
+			pr0 := uint32(fffftou(pr))
+			pg0 := uint32(fffftou(pg))
+			pb0 := uint32(fffftou(pb))
+			pa0 := uint32(fffftou(pa))
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
 		}
 	}
 }
 
-func (z *kernelScaler) scaleY_Image_Src(dst Image, dr, adr image.Rectangle, tmp [][4]float64, opts *Options) {
-	dstMask, dmp := opts.DstMask, opts.DstMaskP
-	dstColorRGBA64 := &color.RGBA64{}
-	dstColor := color.Color(dstColorRGBA64)
-	for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
-		for dy, s := range z.vertical.sources[adr.Min.Y:adr.Max.Y] {
+func (q *Kernel) transform_RGBA64_RGBA64_Over(dst *image.RGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.RGBA64, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+	// When shrinking, broaden the effective kernel support so that we still
+	// visit every source pixel.
+	xHalfWidth, xKernelArgScale := q.Support, 1.0
+	if xscale > 1 {
+		xHalfWidth *= xscale
+		xKernelArgScale = 1 / xscale
+	}
+	yHalfWidth, yKernelArgScale := q.Support, 1.0
+	if yscale > 1 {
+		yHalfWidth *= yscale
+		yKernelArgScale = 1 / yscale
+	}
+
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
+
+	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+		dyf := float64(dr.Min.Y+int(dy)) + 0.5
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
+			dxf := float64(dr.Min.X+int(dx)) + 0.5
+			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
+			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
+			if !(image.Point{int(sx) + bias.X, int(sy) + bias.Y}).In(sr) {
+				continue
+			}
+
+			// TODO: adjust the bias so that we can use int(f) instead
+			// of math.Floor(f) and math.Ceil(f).
+			sx += float64(bias.X)
+			sx -= 0.5
+			ix := int(math.Floor(sx - xHalfWidth))
+			if ix < sr.Min.X {
+				ix = sr.Min.X
+			}
+			jx := int(math.Ceil(sx + xHalfWidth))
+			if jx > sr.Max.X {
+				jx = sr.Max.X
+			}
+
+			totalXWeight := 0.0
+			for kx := ix; kx < jx; kx++ {
+				xWeight := 0.0
+				if t := abs((sx - float64(kx)) * xKernelArgScale); t < q.Support {
+					xWeight = q.At(t)
+				}
+				xWeights[kx-ix] = xWeight
+				totalXWeight += xWeight
+			}
+			for x := range xWeights[:jx-ix] {
+				xWeights[x] /= totalXWeight
+			}
+
+			sy += float64(bias.Y)
+			sy -= 0.5
+			iy := int(math.Floor(sy - yHalfWidth))
+			if iy < sr.Min.Y {
+				iy = sr.Min.Y
+			}
+			jy := int(math.Ceil(sy + yHalfWidth))
+			if jy > sr.Max.Y {
+				jy = sr.Max.Y
+			}
+
+			totalYWeight := 0.0
+			for ky := iy; ky < jy; ky++ {
+				yWeight := 0.0
+				if t := abs((sy - float64(ky)) * yKernelArgScale); t < q.Support {
+					yWeight = q.At(t)
+				}
+				yWeights[ky-iy] = yWeight
+				totalYWeight += yWeight
+			}
+			for y := range yWeights[:jy-iy] {
+				yWeights[y] /= totalYWeight
+			}
+
 			var pr, pg, pb, pa float64
-			for _, c := range z.vertical.contribs[s.i:s.j] {
-				p := &tmp[c.coord*z.dw+dx]
-				pr += float64(p[0] * c.weight)
-				pg += float64(p[1] * c.weight)
-				pb += float64(p[2] * c.weight)
-				pa += float64(p[3] * c.weight)
+			for ky := iy; ky < jy; ky++ {
+				if yWeight := yWeights[ky-iy]; yWeight != 0 {
+					for kx := ix; kx < jx; kx++ {
+						if w := xWeights[kx-ix] * yWeight; w != 0 {
+							pi := (ky-src.Rect.Min.Y)*src.Stride + (kx-src.Rect.Min.X)*8
+							pru := (uint32(src.Pix[pi+0])<<8 | uint32(src.Pix[pi+1]))
+							pgu := (uint32(src.Pix[pi+2])<<8 | uint32(src.Pix[pi+3]))
+							pbu := (uint32(src.Pix[pi+4])<<8 | uint32(src.Pix[pi+5]))
+							pau := (uint32(src.Pix[pi+6])<<8 | uint32(src.Pix[pi+7]))
+							pr += float64(float64(pru) * w)
+							pg += float64(float64(pgu) * w)
+							pb += float64(float64(pbu) * w)
+							pa += float64(float64(pau) * w)
+						}
+					}
+				}
 			}
 
 			if pr > pa {
@@ -6338,31 +21935,24 @@ func (z *kernelScaler) scaleY_Image_Src(dst Image, dr, adr image.Rectangle, tmp
 				pb = pa
 			}
 
-			if dstMask != nil {
-				qr, qg, qb, qa := dst.At(dr.Min.X+int(dx), dr.Min.Y+int(adr.Min.Y+dy)).RGBA()
-				_, _, _, ma := dstMask.At(dmp.X+dr.Min.X+int(dx), dmp.Y+dr.Min.Y+int(adr.Min.Y+dy)).RGBA()
-				pr := uint32(ftou(pr*s.invTotalWeight)) * ma / 0xffff
-				pg := uint32(ftou(pg*s.invTotalWeight)) * ma / 0xffff
-				pb := uint32(ftou(pb*s.invTotalWeight)) * ma / 0xffff
-				pa := uint32(ftou(pa*s.invTotalWeight)) * ma / 0xffff
-				pa1 := 0xffff - ma
-				dstColorRGBA64.R = uint16(qr*pa1/0xffff + pr)
-				dstColorRGBA64.G = uint16(qg*pa1/0xffff + pg)
-				dstColorRGBA64.B = uint16(qb*pa1/0xffff + pb)
-				dstColorRGBA64.A = uint16(qa*pa1/0xffff + pa)
-				dst.Set(dr.Min.X+int(dx), dr.Min.Y+int(adr.Min.Y+dy), dstColor)
-			} else {
-				dstColorRGBA64.R = ftou(pr * s.invTotalWeight)
-				dstColorRGBA64.G = ftou(pg * s.invTotalWeight)
-				dstColorRGBA64.B = ftou(pb * s.invTotalWeight)
-				dstColorRGBA64.A = ftou(pa * s.invTotalWeight)
-				dst.Set(dr.Min.X+int(dx), dr.Min.Y+int(adr.Min.Y+dy), dstColor)
-			}
+			pa1 := 0xffff - uint32(fffftou(pa))
+			pr0 := (uint32(dst.Pix[d+0])<<8|uint32(dst.Pix[d+1]))*pa1/0xffff + uint32(fffftou(pr))
+			pg0 := (uint32(dst.Pix[d+2])<<8|uint32(dst.Pix[d+3]))*pa1/0xffff + uint32(fffftou(pg))
+			pb0 := (uint32(dst.Pix[d+4])<<8|uint32(dst.Pix[d+5]))*pa1/0xffff + uint32(fffftou(pb))
+			pa0 := (uint32(dst.Pix[d+6])<<8|uint32(dst.Pix[d+7]))*pa1/0xffff + uint32(fffftou(pa))
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
 		}
 	}
 }
 
-func (q *Kernel) transform_RGBA_Gray_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.Gray, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+func (q *Kernel) transform_RGBA64_RGBA64_Src(dst *image.RGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.RGBA64, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
 	// When shrinking, broaden the effective kernel support so that we still
 	// visit every source pixel.
 	xHalfWidth, xKernelArgScale := q.Support, 1.0
@@ -6376,13 +21966,15 @@ func (q *Kernel) transform_RGBA_Gray_Src(dst *image.RGBA, dr, adr image.Rectangl
 		yKernelArgScale = 1 / yscale
 	}
 
-	xWeights := make([]float64, 1+2*int(math.Ceil(xHalfWidth)))
-	yWeights := make([]float64, 1+2*int(math.Ceil(yHalfWidth)))
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
 
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
 		dyf := float64(dr.Min.Y+int(dy)) + 0.5
-		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
-		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
 			dxf := float64(dr.Min.X+int(dx)) + 0.5
 			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
 			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
@@ -6440,28 +22032,52 @@ func (q *Kernel) transform_RGBA_Gray_Src(dst *image.RGBA, dr, adr image.Rectangl
 				yWeights[y] /= totalYWeight
 			}
 
-			var pr float64
+			var pr, pg, pb, pa float64
 			for ky := iy; ky < jy; ky++ {
 				if yWeight := yWeights[ky-iy]; yWeight != 0 {
 					for kx := ix; kx < jx; kx++ {
 						if w := xWeights[kx-ix] * yWeight; w != 0 {
-							pi := (ky-src.Rect.Min.Y)*src.Stride + (kx - src.Rect.Min.X)
-							pru := uint32(src.Pix[pi]) * 0x101
+							pi := (ky-src.Rect.Min.Y)*src.Stride + (kx-src.Rect.Min.X)*8
+							pru := (uint32(src.Pix[pi+0])<<8 | uint32(src.Pix[pi+1]))
+							pgu := (uint32(src.Pix[pi+2])<<8 | uint32(src.Pix[pi+3]))
+							pbu := (uint32(src.Pix[pi+4])<<8 | uint32(src.Pix[pi+5]))
+							pau := (uint32(src.Pix[pi+6])<<8 | uint32(src.Pix[pi+7]))
 							pr += float64(float64(pru) * w)
+							pg += float64(float64(pgu) * w)
+							pb += float64(float64(pbu) * w)
+							pa += float64(float64(pau) * w)
 						}
 					}
 				}
 			}
-			out := uint8(fffftou(pr) >> 8)
-			dst.Pix[d+0] = out
-			dst.Pix[d+1] = out
-			dst.Pix[d+2] = out
-			dst.Pix[d+3] = 0xff
+
+			if pr > pa {
+				pr = pa
+			}
+			if pg > pa {
+				pg = pa
+			}
+			if pb > pa {
+				pb = pa
+			}
+
+			pr0 := uint32(fffftou(pr))
+			pg0 := uint32(fffftou(pg))
+			pb0 := uint32(fffftou(pb))
+			pa0 := uint32(fffftou(pa))
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
 		}
 	}
 }
 
-func (q *Kernel) transform_RGBA_NRGBA_Over(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NRGBA, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+func (q *Kernel) transform_RGBA64_RGBA64Image_Over(dst *image.RGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src image.RGBA64Image, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
 	// When shrinking, broaden the effective kernel support so that we still
 	// visit every source pixel.
 	xHalfWidth, xKernelArgScale := q.Support, 1.0
@@ -6475,13 +22091,15 @@ func (q *Kernel) transform_RGBA_NRGBA_Over(dst *image.RGBA, dr, adr image.Rectan
 		yKernelArgScale = 1 / yscale
 	}
 
-	xWeights := make([]float64, 1+2*int(math.Ceil(xHalfWidth)))
-	yWeights := make([]float64, 1+2*int(math.Ceil(yHalfWidth)))
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
 
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
 		dyf := float64(dr.Min.Y+int(dy)) + 0.5
-		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
-		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
 			dxf := float64(dr.Min.X+int(dx)) + 0.5
 			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
 			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
@@ -6544,15 +22162,11 @@ func (q *Kernel) transform_RGBA_NRGBA_Over(dst *image.RGBA, dr, adr image.Rectan
 				if yWeight := yWeights[ky-iy]; yWeight != 0 {
 					for kx := ix; kx < jx; kx++ {
 						if w := xWeights[kx-ix] * yWeight; w != 0 {
-							pi := (ky-src.Rect.Min.Y)*src.Stride + (kx-src.Rect.Min.X)*4
-							pau := uint32(src.Pix[pi+3]) * 0x101
-							pru := uint32(src.Pix[pi+0]) * pau / 0xff
-							pgu := uint32(src.Pix[pi+1]) * pau / 0xff
-							pbu := uint32(src.Pix[pi+2]) * pau / 0xff
-							pr += float64(float64(pru) * w)
-							pg += float64(float64(pgu) * w)
-							pb += float64(float64(pbu) * w)
-							pa += float64(float64(pau) * w)
+							pu := src.RGBA64At(kx, ky)
+							pr += float64(float64(pu.R) * w)
+							pg += float64(float64(pu.G) * w)
+							pb += float64(float64(pu.B) * w)
+							pa += float64(float64(pu.A) * w)
 						}
 					}
 				}
@@ -6568,20 +22182,24 @@ func (q *Kernel) transform_RGBA_NRGBA_Over(dst *image.RGBA, dr, adr image.Rectan
 				pb = pa
 			}
 
-			pr0 := uint32(fffftou(pr))
-			pg0 := uint32(fffftou(pg))
-			pb0 := uint32(fffftou(pb))
-			pa0 := uint32(fffftou(pa))
-			pa1 := (0xffff - uint32(pa0)) * 0x101
-			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pr0) >> 8)
-			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + pg0) >> 8)
-			dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*pa1/0xffff + pb0) >> 8)
-			dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*pa1/0xffff + pa0) >> 8)
+			pa1 := 0xffff - uint32(fffftou(pa))
+			pr0 := (uint32(dst.Pix[d+0])<<8|uint32(dst.Pix[d+1]))*pa1/0xffff + uint32(fffftou(pr))
+			pg0 := (uint32(dst.Pix[d+2])<<8|uint32(dst.Pix[d+3]))*pa1/0xffff + uint32(fffftou(pg))
+			pb0 := (uint32(dst.Pix[d+4])<<8|uint32(dst.Pix[d+5]))*pa1/0xffff + uint32(fffftou(pb))
+			pa0 := (uint32(dst.Pix[d+6])<<8|uint32(dst.Pix[d+7]))*pa1/0xffff + uint32(fffftou(pa))
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
 		}
 	}
 }
 
-func (q *Kernel) transform_RGBA_NRGBA_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NRGBA, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+func (q *Kernel) transform_RGBA64_RGBA64Image_Src(dst *image.RGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src image.RGBA64Image, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
 	// When shrinking, broaden the effective kernel support so that we still
 	// visit every source pixel.
 	xHalfWidth, xKernelArgScale := q.Support, 1.0
@@ -6595,13 +22213,15 @@ func (q *Kernel) transform_RGBA_NRGBA_Src(dst *image.RGBA, dr, adr image.Rectang
 		yKernelArgScale = 1 / yscale
 	}
 
-	xWeights := make([]float64, 1+2*int(math.Ceil(xHalfWidth)))
-	yWeights := make([]float64, 1+2*int(math.Ceil(yHalfWidth)))
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
 
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
 		dyf := float64(dr.Min.Y+int(dy)) + 0.5
-		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
-		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
 			dxf := float64(dr.Min.X+int(dx)) + 0.5
 			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
 			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
@@ -6664,15 +22284,11 @@ func (q *Kernel) transform_RGBA_NRGBA_Src(dst *image.RGBA, dr, adr image.Rectang
 				if yWeight := yWeights[ky-iy]; yWeight != 0 {
 					for kx := ix; kx < jx; kx++ {
 						if w := xWeights[kx-ix] * yWeight; w != 0 {
-							pi := (ky-src.Rect.Min.Y)*src.Stride + (kx-src.Rect.Min.X)*4
-							pau := uint32(src.Pix[pi+3]) * 0x101
-							pru := uint32(src.Pix[pi+0]) * pau / 0xff
-							pgu := uint32(src.Pix[pi+1]) * pau / 0xff
-							pbu := uint32(src.Pix[pi+2]) * pau / 0xff
-							pr += float64(float64(pru) * w)
-							pg += float64(float64(pgu) * w)
-							pb += float64(float64(pbu) * w)
-							pa += float64(float64(pau) * w)
+							pu := src.RGBA64At(kx, ky)
+							pr += float64(float64(pu.R) * w)
+							pg += float64(float64(pu.G) * w)
+							pb += float64(float64(pu.B) * w)
+							pa += float64(float64(pu.A) * w)
 						}
 					}
 				}
@@ -6688,15 +22304,23 @@ func (q *Kernel) transform_RGBA_NRGBA_Src(dst *image.RGBA, dr, adr image.Rectang
 				pb = pa
 			}
 
-			dst.Pix[d+0] = uint8(fffftou(pr) >> 8)
-			dst.Pix[d+1] = uint8(fffftou(pg) >> 8)
-			dst.Pix[d+2] = uint8(fffftou(pb) >> 8)
-			dst.Pix[d+3] = uint8(fffftou(pa) >> 8)
+			pr0 := uint32(fffftou(pr))
+			pg0 := uint32(fffftou(pg))
+			pb0 := uint32(fffftou(pb))
+			pa0 := uint32(fffftou(pa))
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
 		}
 	}
 }
 
-func (q *Kernel) transform_RGBA_RGBA_Over(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.RGBA, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+func (q *Kernel) transform_RGBA64_Image_Over(dst *image.RGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src image.Image, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
 	// When shrinking, broaden the effective kernel support so that we still
 	// visit every source pixel.
 	xHalfWidth, xKernelArgScale := q.Support, 1.0
@@ -6710,13 +22334,15 @@ func (q *Kernel) transform_RGBA_RGBA_Over(dst *image.RGBA, dr, adr image.Rectang
 		yKernelArgScale = 1 / yscale
 	}
 
-	xWeights := make([]float64, 1+2*int(math.Ceil(xHalfWidth)))
-	yWeights := make([]float64, 1+2*int(math.Ceil(yHalfWidth)))
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
 
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
 		dyf := float64(dr.Min.Y+int(dy)) + 0.5
-		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
-		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
 			dxf := float64(dr.Min.X+int(dx)) + 0.5
 			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
 			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
@@ -6779,11 +22405,7 @@ func (q *Kernel) transform_RGBA_RGBA_Over(dst *image.RGBA, dr, adr image.Rectang
 				if yWeight := yWeights[ky-iy]; yWeight != 0 {
 					for kx := ix; kx < jx; kx++ {
 						if w := xWeights[kx-ix] * yWeight; w != 0 {
-							pi := (ky-src.Rect.Min.Y)*src.Stride + (kx-src.Rect.Min.X)*4
-							pru := uint32(src.Pix[pi+0]) * 0x101
-							pgu := uint32(src.Pix[pi+1]) * 0x101
-							pbu := uint32(src.Pix[pi+2]) * 0x101
-							pau := uint32(src.Pix[pi+3]) * 0x101
+							pru, pgu, pbu, pau := src.At(kx, ky).RGBA()
 							pr += float64(float64(pru) * w)
 							pg += float64(float64(pgu) * w)
 							pb += float64(float64(pbu) * w)
@@ -6803,20 +22425,24 @@ func (q *Kernel) transform_RGBA_RGBA_Over(dst *image.RGBA, dr, adr image.Rectang
 				pb = pa
 			}
 
-			pr0 := uint32(fffftou(pr))
-			pg0 := uint32(fffftou(pg))
-			pb0 := uint32(fffftou(pb))
-			pa0 := uint32(fffftou(pa))
-			pa1 := (0xffff - uint32(pa0)) * 0x101
-			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pr0) >> 8)
-			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + pg0) >> 8)
-			dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*pa1/0xffff + pb0) >> 8)
-			dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*pa1/0xffff + pa0) >> 8)
+			pa1 := 0xffff - uint32(fffftou(pa))
+			pr0 := (uint32(dst.Pix[d+0])<<8|uint32(dst.Pix[d+1]))*pa1/0xffff + uint32(fffftou(pr))
+			pg0 := (uint32(dst.Pix[d+2])<<8|uint32(dst.Pix[d+3]))*pa1/0xffff + uint32(fffftou(pg))
+			pb0 := (uint32(dst.Pix[d+4])<<8|uint32(dst.Pix[d+5]))*pa1/0xffff + uint32(fffftou(pb))
+			pa0 := (uint32(dst.Pix[d+6])<<8|uint32(dst.Pix[d+7]))*pa1/0xffff + uint32(fffftou(pa))
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
 		}
 	}
 }
 
-func (q *Kernel) transform_RGBA_RGBA_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.RGBA, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+func (q *Kernel) transform_RGBA64_Image_Src(dst *image.RGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src image.Image, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
 	// When shrinking, broaden the effective kernel support so that we still
 	// visit every source pixel.
 	xHalfWidth, xKernelArgScale := q.Support, 1.0
@@ -6830,13 +22456,15 @@ func (q *Kernel) transform_RGBA_RGBA_Src(dst *image.RGBA, dr, adr image.Rectangl
 		yKernelArgScale = 1 / yscale
 	}
 
-	xWeights := make([]float64, 1+2*int(math.Ceil(xHalfWidth)))
-	yWeights := make([]float64, 1+2*int(math.Ceil(yHalfWidth)))
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
 
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
 		dyf := float64(dr.Min.Y+int(dy)) + 0.5
-		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
-		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
 			dxf := float64(dr.Min.X+int(dx)) + 0.5
 			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
 			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
@@ -6899,11 +22527,7 @@ func (q *Kernel) transform_RGBA_RGBA_Src(dst *image.RGBA, dr, adr image.Rectangl
 				if yWeight := yWeights[ky-iy]; yWeight != 0 {
 					for kx := ix; kx < jx; kx++ {
 						if w := xWeights[kx-ix] * yWeight; w != 0 {
-							pi := (ky-src.Rect.Min.Y)*src.Stride + (kx-src.Rect.Min.X)*4
-							pru := uint32(src.Pix[pi+0]) * 0x101
-							pgu := uint32(src.Pix[pi+1]) * 0x101
-							pbu := uint32(src.Pix[pi+2]) * 0x101
-							pau := uint32(src.Pix[pi+3]) * 0x101
+							pru, pgu, pbu, pau := src.At(kx, ky).RGBA()
 							pr += float64(float64(pru) * w)
 							pg += float64(float64(pgu) * w)
 							pb += float64(float64(pbu) * w)
@@ -6923,15 +22547,23 @@ func (q *Kernel) transform_RGBA_RGBA_Src(dst *image.RGBA, dr, adr image.Rectangl
 				pb = pa
 			}
 
-			dst.Pix[d+0] = uint8(fffftou(pr) >> 8)
-			dst.Pix[d+1] = uint8(fffftou(pg) >> 8)
-			dst.Pix[d+2] = uint8(fffftou(pb) >> 8)
-			dst.Pix[d+3] = uint8(fffftou(pa) >> 8)
+			pr0 := uint32(fffftou(pr))
+			pg0 := uint32(fffftou(pg))
+			pb0 := uint32(fffftou(pb))
+			pa0 := uint32(fffftou(pa))
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
 		}
 	}
 }
 
-func (q *Kernel) transform_RGBA_YCbCr444_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.YCbCr, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+func (q *Kernel) transform_NRGBA64_NRGBA64_Over(dst *image.NRGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NRGBA64, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
 	// When shrinking, broaden the effective kernel support so that we still
 	// visit every source pixel.
 	xHalfWidth, xKernelArgScale := q.Support, 1.0
@@ -6945,13 +22577,15 @@ func (q *Kernel) transform_RGBA_YCbCr444_Src(dst *image.RGBA, dr, adr image.Rect
 		yKernelArgScale = 1 / yscale
 	}
 
-	xWeights := make([]float64, 1+2*int(math.Ceil(xHalfWidth)))
-	yWeights := make([]float64, 1+2*int(math.Ceil(yHalfWidth)))
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
 
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
 		dyf := float64(dr.Min.Y+int(dy)) + 0.5
-		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
-		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
 			dxf := float64(dr.Min.X+int(dx)) + 0.5
 			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
 			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
@@ -7009,53 +22643,59 @@ func (q *Kernel) transform_RGBA_YCbCr444_Src(dst *image.RGBA, dr, adr image.Rect
 				yWeights[y] /= totalYWeight
 			}
 
-			var pr, pg, pb float64
+			var pr, pg, pb, pa float64
 			for ky := iy; ky < jy; ky++ {
 				if yWeight := yWeights[ky-iy]; yWeight != 0 {
 					for kx := ix; kx < jx; kx++ {
 						if w := xWeights[kx-ix] * yWeight; w != 0 {
-							pi := (ky-src.Rect.Min.Y)*src.YStride + (kx - src.Rect.Min.X)
-							pj := (ky-src.Rect.Min.Y)*src.CStride + (kx - src.Rect.Min.X)
-
-							// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
-							pyy1 := int(src.Y[pi]) * 0x10101
-							pcb1 := int(src.Cb[pj]) - 128
-							pcr1 := int(src.Cr[pj]) - 128
-							pru := (pyy1 + 91881*pcr1) >> 8
-							pgu := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
-							pbu := (pyy1 + 116130*pcb1) >> 8
-							if pru < 0 {
-								pru = 0
-							} else if pru > 0xffff {
-								pru = 0xffff
-							}
-							if pgu < 0 {
-								pgu = 0
-							} else if pgu > 0xffff {
-								pgu = 0xffff
-							}
-							if pbu < 0 {
-								pbu = 0
-							} else if pbu > 0xffff {
-								pbu = 0xffff
-							}
-
+							pi := (ky-src.Rect.Min.Y)*src.Stride + (kx-src.Rect.Min.X)*8
+							pau := (uint32(src.Pix[pi+6])<<8 | uint32(src.Pix[pi+7]))
+							pru := (uint32(src.Pix[pi+0])<<8 | uint32(src.Pix[pi+1])) * pau / 0xffff
+							pgu := (uint32(src.Pix[pi+2])<<8 | uint32(src.Pix[pi+3])) * pau / 0xffff
+							pbu := (uint32(src.Pix[pi+4])<<8 | uint32(src.Pix[pi+5])) * pau / 0xffff
 							pr += float64(float64(pru) * w)
 							pg += float64(float64(pgu) * w)
 							pb += float64(float64(pbu) * w)
+							pa += float64(float64(pau) * w)
 						}
 					}
 				}
 			}
-			dst.Pix[d+0] = uint8(fffftou(pr) >> 8)
-			dst.Pix[d+1] = uint8(fffftou(pg) >> 8)
-			dst.Pix[d+2] = uint8(fffftou(pb) >> 8)
-			dst.Pix[d+3] = 0xff
+
+			if pr > pa {
+				pr = pa
+			}
+			if pg > pa {
+				pg = pa
+			}
+			if pb > pa {
+				pb = pa
+			}
+
+			pa1 := 0xffff - uint32(fffftou(pa))
+			pqa := (uint32(dst.Pix[d+6])<<8 | uint32(dst.Pix[d+7]))
+			pr0 := ((uint32(dst.Pix[d+0])<<8|uint32(dst.Pix[d+1]))*pqa/0xffff)*pa1/0xffff + uint32(fffftou(pr))
+			pg0 := ((uint32(dst.Pix[d+2])<<8|uint32(dst.Pix[d+3]))*pqa/0xffff)*pa1/0xffff + uint32(fffftou(pg))
+			pb0 := ((uint32(dst.Pix[d+4])<<8|uint32(dst.Pix[d+5]))*pqa/0xffff)*pa1/0xffff + uint32(fffftou(pb))
+			pa0 := pqa*pa1/0xffff + uint32(fffftou(pa))
+			if pa0 != 0 && pa0 != 0xffff {
+				pr0 = pr0 * 0xffff / pa0
+				pg0 = pg0 * 0xffff / pa0
+				pb0 = pb0 * 0xffff / pa0
+			}
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
 		}
 	}
 }
 
-func (q *Kernel) transform_RGBA_YCbCr422_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.YCbCr, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+func (q *Kernel) transform_NRGBA64_NRGBA64_Src(dst *image.NRGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.NRGBA64, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
 	// When shrinking, broaden the effective kernel support so that we still
 	// visit every source pixel.
 	xHalfWidth, xKernelArgScale := q.Support, 1.0
@@ -7069,13 +22709,15 @@ func (q *Kernel) transform_RGBA_YCbCr422_Src(dst *image.RGBA, dr, adr image.Rect
 		yKernelArgScale = 1 / yscale
 	}
 
-	xWeights := make([]float64, 1+2*int(math.Ceil(xHalfWidth)))
-	yWeights := make([]float64, 1+2*int(math.Ceil(yHalfWidth)))
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
 
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
 		dyf := float64(dr.Min.Y+int(dy)) + 0.5
-		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
-		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
 			dxf := float64(dr.Min.X+int(dx)) + 0.5
 			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
 			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
@@ -7133,53 +22775,57 @@ func (q *Kernel) transform_RGBA_YCbCr422_Src(dst *image.RGBA, dr, adr image.Rect
 				yWeights[y] /= totalYWeight
 			}
 
-			var pr, pg, pb float64
+			var pr, pg, pb, pa float64
 			for ky := iy; ky < jy; ky++ {
 				if yWeight := yWeights[ky-iy]; yWeight != 0 {
 					for kx := ix; kx < jx; kx++ {
 						if w := xWeights[kx-ix] * yWeight; w != 0 {
-							pi := (ky-src.Rect.Min.Y)*src.YStride + (kx - src.Rect.Min.X)
-							pj := (ky-src.Rect.Min.Y)*src.CStride + ((kx)/2 - src.Rect.Min.X/2)
-
-							// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
-							pyy1 := int(src.Y[pi]) * 0x10101
-							pcb1 := int(src.Cb[pj]) - 128
-							pcr1 := int(src.Cr[pj]) - 128
-							pru := (pyy1 + 91881*pcr1) >> 8
-							pgu := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
-							pbu := (pyy1 + 116130*pcb1) >> 8
-							if pru < 0 {
-								pru = 0
-							} else if pru > 0xffff {
-								pru = 0xffff
-							}
-							if pgu < 0 {
-								pgu = 0
-							} else if pgu > 0xffff {
-								pgu = 0xffff
-							}
-							if pbu < 0 {
-								pbu = 0
-							} else if pbu > 0xffff {
-								pbu = 0xffff
-							}
-
+							pi := (ky-src.Rect.Min.Y)*src.Stride + (kx-src.Rect.Min.X)*8
+							pau := (uint32(src.Pix[pi+6])<<8 | uint32(src.Pix[pi+7]))
+							pru := (uint32(src.Pix[pi+0])<<8 | uint32(src.Pix[pi+1])) * pau / 0xffff
+							pgu := (uint32(src.Pix[pi+2])<<8 | uint32(src.Pix[pi+3])) * pau / 0xffff
+							pbu := (uint32(src.Pix[pi+4])<<8 | uint32(src.Pix[pi+5])) * pau / 0xffff
 							pr += float64(float64(pru) * w)
 							pg += float64(float64(pgu) * w)
 							pb += float64(float64(pbu) * w)
+							pa += float64(float64(pau) * w)
 						}
 					}
 				}
 			}
-			dst.Pix[d+0] = uint8(fffftou(pr) >> 8)
-			dst.Pix[d+1] = uint8(fffftou(pg) >> 8)
-			dst.Pix[d+2] = uint8(fffftou(pb) >> 8)
-			dst.Pix[d+3] = 0xff
+
+			if pr > pa {
+				pr = pa
+			}
+			if pg > pa {
+				pg = pa
+			}
+			if pb > pa {
+				pb = pa
+			}
+
+			pr0 := uint32(fffftou(pr))
+			pg0 := uint32(fffftou(pg))
+			pb0 := uint32(fffftou(pb))
+			pa0 := uint32(fffftou(pa))
+			if pa0 != 0 && pa0 != 0xffff {
+				pr0 = pr0 * 0xffff / pa0
+				pg0 = pg0 * 0xffff / pa0
+				pb0 = pb0 * 0xffff / pa0
+			}
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
 		}
 	}
 }
 
-func (q *Kernel) transform_RGBA_YCbCr420_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.YCbCr, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+func (q *Kernel) transform_NRGBA64_RGBA64_Over(dst *image.NRGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.RGBA64, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
 	// When shrinking, broaden the effective kernel support so that we still
 	// visit every source pixel.
 	xHalfWidth, xKernelArgScale := q.Support, 1.0
@@ -7193,13 +22839,15 @@ func (q *Kernel) transform_RGBA_YCbCr420_Src(dst *image.RGBA, dr, adr image.Rect
 		yKernelArgScale = 1 / yscale
 	}
 
-	xWeights := make([]float64, 1+2*int(math.Ceil(xHalfWidth)))
-	yWeights := make([]float64, 1+2*int(math.Ceil(yHalfWidth)))
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
 
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
 		dyf := float64(dr.Min.Y+int(dy)) + 0.5
-		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
-		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
 			dxf := float64(dr.Min.X+int(dx)) + 0.5
 			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
 			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
@@ -7257,53 +22905,59 @@ func (q *Kernel) transform_RGBA_YCbCr420_Src(dst *image.RGBA, dr, adr image.Rect
 				yWeights[y] /= totalYWeight
 			}
 
-			var pr, pg, pb float64
+			var pr, pg, pb, pa float64
 			for ky := iy; ky < jy; ky++ {
 				if yWeight := yWeights[ky-iy]; yWeight != 0 {
 					for kx := ix; kx < jx; kx++ {
 						if w := xWeights[kx-ix] * yWeight; w != 0 {
-							pi := (ky-src.Rect.Min.Y)*src.YStride + (kx - src.Rect.Min.X)
-							pj := ((ky)/2-src.Rect.Min.Y/2)*src.CStride + ((kx)/2 - src.Rect.Min.X/2)
-
-							// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
-							pyy1 := int(src.Y[pi]) * 0x10101
-							pcb1 := int(src.Cb[pj]) - 128
-							pcr1 := int(src.Cr[pj]) - 128
-							pru := (pyy1 + 91881*pcr1) >> 8
-							pgu := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
-							pbu := (pyy1 + 116130*pcb1) >> 8
-							if pru < 0 {
-								pru = 0
-							} else if pru > 0xffff {
-								pru = 0xffff
-							}
-							if pgu < 0 {
-								pgu = 0
-							} else if pgu > 0xffff {
-								pgu = 0xffff
-							}
-							if pbu < 0 {
-								pbu = 0
-							} else if pbu > 0xffff {
-								pbu = 0xffff
-							}
-
+							pi := (ky-src.Rect.Min.Y)*src.Stride + (kx-src.Rect.Min.X)*8
+							pru := (uint32(src.Pix[pi+0])<<8 | uint32(src.Pix[pi+1]))
+							pgu := (uint32(src.Pix[pi+2])<<8 | uint32(src.Pix[pi+3]))
+							pbu := (uint32(src.Pix[pi+4])<<8 | uint32(src.Pix[pi+5]))
+							pau := (uint32(src.Pix[pi+6])<<8 | uint32(src.Pix[pi+7]))
 							pr += float64(float64(pru) * w)
 							pg += float64(float64(pgu) * w)
 							pb += float64(float64(pbu) * w)
+							pa += float64(float64(pau) * w)
 						}
 					}
 				}
 			}
-			dst.Pix[d+0] = uint8(fffftou(pr) >> 8)
-			dst.Pix[d+1] = uint8(fffftou(pg) >> 8)
-			dst.Pix[d+2] = uint8(fffftou(pb) >> 8)
-			dst.Pix[d+3] = 0xff
+
+			if pr > pa {
+				pr = pa
+			}
+			if pg > pa {
+				pg = pa
+			}
+			if pb > pa {
+				pb = pa
+			}
+
+			pa1 := 0xffff - uint32(fffftou(pa))
+			pqa := (uint32(dst.Pix[d+6])<<8 | uint32(dst.Pix[d+7]))
+			pr0 := ((uint32(dst.Pix[d+0])<<8|uint32(dst.Pix[d+1]))*pqa/0xffff)*pa1/0xffff + uint32(fffftou(pr))
+			pg0 := ((uint32(dst.Pix[d+2])<<8|uint32(dst.Pix[d+3]))*pqa/0xffff)*pa1/0xffff + uint32(fffftou(pg))
+			pb0 := ((uint32(dst.Pix[d+4])<<8|uint32(dst.Pix[d+5]))*pqa/0xffff)*pa1/0xffff + uint32(fffftou(pb))
+			pa0 := pqa*pa1/0xffff + uint32(fffftou(pa))
+			if pa0 != 0 && pa0 != 0xffff {
+				pr0 = pr0 * 0xffff / pa0
+				pg0 = pg0 * 0xffff / pa0
+				pb0 = pb0 * 0xffff / pa0
+			}
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
 		}
 	}
 }
 
-func (q *Kernel) transform_RGBA_YCbCr440_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.YCbCr, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+func (q *Kernel) transform_NRGBA64_RGBA64_Src(dst *image.NRGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.RGBA64, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
 	// When shrinking, broaden the effective kernel support so that we still
 	// visit every source pixel.
 	xHalfWidth, xKernelArgScale := q.Support, 1.0
@@ -7317,13 +22971,15 @@ func (q *Kernel) transform_RGBA_YCbCr440_Src(dst *image.RGBA, dr, adr image.Rect
 		yKernelArgScale = 1 / yscale
 	}
 
-	xWeights := make([]float64, 1+2*int(math.Ceil(xHalfWidth)))
-	yWeights := make([]float64, 1+2*int(math.Ceil(yHalfWidth)))
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
 
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
 		dyf := float64(dr.Min.Y+int(dy)) + 0.5
-		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
-		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
 			dxf := float64(dr.Min.X+int(dx)) + 0.5
 			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
 			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
@@ -7381,53 +23037,57 @@ func (q *Kernel) transform_RGBA_YCbCr440_Src(dst *image.RGBA, dr, adr image.Rect
 				yWeights[y] /= totalYWeight
 			}
 
-			var pr, pg, pb float64
+			var pr, pg, pb, pa float64
 			for ky := iy; ky < jy; ky++ {
 				if yWeight := yWeights[ky-iy]; yWeight != 0 {
 					for kx := ix; kx < jx; kx++ {
 						if w := xWeights[kx-ix] * yWeight; w != 0 {
-							pi := (ky-src.Rect.Min.Y)*src.YStride + (kx - src.Rect.Min.X)
-							pj := ((ky)/2-src.Rect.Min.Y/2)*src.CStride + (kx - src.Rect.Min.X)
-
-							// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
-							pyy1 := int(src.Y[pi]) * 0x10101
-							pcb1 := int(src.Cb[pj]) - 128
-							pcr1 := int(src.Cr[pj]) - 128
-							pru := (pyy1 + 91881*pcr1) >> 8
-							pgu := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
-							pbu := (pyy1 + 116130*pcb1) >> 8
-							if pru < 0 {
-								pru = 0
-							} else if pru > 0xffff {
-								pru = 0xffff
-							}
-							if pgu < 0 {
-								pgu = 0
-							} else if pgu > 0xffff {
-								pgu = 0xffff
-							}
-							if pbu < 0 {
-								pbu = 0
-							} else if pbu > 0xffff {
-								pbu = 0xffff
-							}
-
+							pi := (ky-src.Rect.Min.Y)*src.Stride + (kx-src.Rect.Min.X)*8
+							pru := (uint32(src.Pix[pi+0])<<8 | uint32(src.Pix[pi+1]))
+							pgu := (uint32(src.Pix[pi+2])<<8 | uint32(src.Pix[pi+3]))
+							pbu := (uint32(src.Pix[pi+4])<<8 | uint32(src.Pix[pi+5]))
+							pau := (uint32(src.Pix[pi+6])<<8 | uint32(src.Pix[pi+7]))
 							pr += float64(float64(pru) * w)
 							pg += float64(float64(pgu) * w)
 							pb += float64(float64(pbu) * w)
+							pa += float64(float64(pau) * w)
 						}
 					}
 				}
 			}
-			dst.Pix[d+0] = uint8(fffftou(pr) >> 8)
-			dst.Pix[d+1] = uint8(fffftou(pg) >> 8)
-			dst.Pix[d+2] = uint8(fffftou(pb) >> 8)
-			dst.Pix[d+3] = 0xff
+
+			if pr > pa {
+				pr = pa
+			}
+			if pg > pa {
+				pg = pa
+			}
+			if pb > pa {
+				pb = pa
+			}
+
+			pr0 := uint32(fffftou(pr))
+			pg0 := uint32(fffftou(pg))
+			pb0 := uint32(fffftou(pb))
+			pa0 := uint32(fffftou(pa))
+			if pa0 != 0 && pa0 != 0xffff {
+				pr0 = pr0 * 0xffff / pa0
+				pg0 = pg0 * 0xffff / pa0
+				pb0 = pb0 * 0xffff / pa0
+			}
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
 		}
 	}
 }
 
-func (q *Kernel) transform_RGBA_RGBA64Image_Over(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src image.RGBA64Image, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+func (q *Kernel) transform_NRGBA64_RGBA64Image_Over(dst *image.NRGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src image.RGBA64Image, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
 	// When shrinking, broaden the effective kernel support so that we still
 	// visit every source pixel.
 	xHalfWidth, xKernelArgScale := q.Support, 1.0
@@ -7441,13 +23101,15 @@ func (q *Kernel) transform_RGBA_RGBA64Image_Over(dst *image.RGBA, dr, adr image.
 		yKernelArgScale = 1 / yscale
 	}
 
-	xWeights := make([]float64, 1+2*int(math.Ceil(xHalfWidth)))
-	yWeights := make([]float64, 1+2*int(math.Ceil(yHalfWidth)))
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
 
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
 		dyf := float64(dr.Min.Y+int(dy)) + 0.5
-		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
-		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
 			dxf := float64(dr.Min.X+int(dx)) + 0.5
 			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
 			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
@@ -7530,20 +23192,30 @@ func (q *Kernel) transform_RGBA_RGBA64Image_Over(dst *image.RGBA, dr, adr image.
 				pb = pa
 			}
 
-			pr0 := uint32(fffftou(pr))
-			pg0 := uint32(fffftou(pg))
-			pb0 := uint32(fffftou(pb))
-			pa0 := uint32(fffftou(pa))
-			pa1 := (0xffff - uint32(pa0)) * 0x101
-			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pr0) >> 8)
-			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + pg0) >> 8)
-			dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*pa1/0xffff + pb0) >> 8)
-			dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*pa1/0xffff + pa0) >> 8)
+			pa1 := 0xffff - uint32(fffftou(pa))
+			pqa := (uint32(dst.Pix[d+6])<<8 | uint32(dst.Pix[d+7]))
+			pr0 := ((uint32(dst.Pix[d+0])<<8|uint32(dst.Pix[d+1]))*pqa/0xffff)*pa1/0xffff + uint32(fffftou(pr))
+			pg0 := ((uint32(dst.Pix[d+2])<<8|uint32(dst.Pix[d+3]))*pqa/0xffff)*pa1/0xffff + uint32(fffftou(pg))
+			pb0 := ((uint32(dst.Pix[d+4])<<8|uint32(dst.Pix[d+5]))*pqa/0xffff)*pa1/0xffff + uint32(fffftou(pb))
+			pa0 := pqa*pa1/0xffff + uint32(fffftou(pa))
+			if pa0 != 0 && pa0 != 0xffff {
+				pr0 = pr0 * 0xffff / pa0
+				pg0 = pg0 * 0xffff / pa0
+				pb0 = pb0 * 0xffff / pa0
+			}
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
 		}
 	}
 }
 
-func (q *Kernel) transform_RGBA_RGBA64Image_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src image.RGBA64Image, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+func (q *Kernel) transform_NRGBA64_RGBA64Image_Src(dst *image.NRGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src image.RGBA64Image, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
 	// When shrinking, broaden the effective kernel support so that we still
 	// visit every source pixel.
 	xHalfWidth, xKernelArgScale := q.Support, 1.0
@@ -7557,13 +23229,15 @@ func (q *Kernel) transform_RGBA_RGBA64Image_Src(dst *image.RGBA, dr, adr image.R
 		yKernelArgScale = 1 / yscale
 	}
 
-	xWeights := make([]float64, 1+2*int(math.Ceil(xHalfWidth)))
-	yWeights := make([]float64, 1+2*int(math.Ceil(yHalfWidth)))
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
 
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
 		dyf := float64(dr.Min.Y+int(dy)) + 0.5
-		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
-		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
 			dxf := float64(dr.Min.X+int(dx)) + 0.5
 			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
 			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
@@ -7646,15 +23320,28 @@ func (q *Kernel) transform_RGBA_RGBA64Image_Src(dst *image.RGBA, dr, adr image.R
 				pb = pa
 			}
 
-			dst.Pix[d+0] = uint8(fffftou(pr) >> 8)
-			dst.Pix[d+1] = uint8(fffftou(pg) >> 8)
-			dst.Pix[d+2] = uint8(fffftou(pb) >> 8)
-			dst.Pix[d+3] = uint8(fffftou(pa) >> 8)
+			pr0 := uint32(fffftou(pr))
+			pg0 := uint32(fffftou(pg))
+			pb0 := uint32(fffftou(pb))
+			pa0 := uint32(fffftou(pa))
+			if pa0 != 0 && pa0 != 0xffff {
+				pr0 = pr0 * 0xffff / pa0
+				pg0 = pg0 * 0xffff / pa0
+				pb0 = pb0 * 0xffff / pa0
+			}
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
 		}
 	}
 }
 
-func (q *Kernel) transform_RGBA_Image_Over(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src image.Image, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+func (q *Kernel) transform_NRGBA64_Image_Over(dst *image.NRGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src image.Image, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
 	// When shrinking, broaden the effective kernel support so that we still
 	// visit every source pixel.
 	xHalfWidth, xKernelArgScale := q.Support, 1.0
@@ -7668,13 +23355,15 @@ func (q *Kernel) transform_RGBA_Image_Over(dst *image.RGBA, dr, adr image.Rectan
 		yKernelArgScale = 1 / yscale
 	}
 
-	xWeights := make([]float64, 1+2*int(math.Ceil(xHalfWidth)))
-	yWeights := make([]float64, 1+2*int(math.Ceil(yHalfWidth)))
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
 
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
 		dyf := float64(dr.Min.Y+int(dy)) + 0.5
-		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
-		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
 			dxf := float64(dr.Min.X+int(dx)) + 0.5
 			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
 			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
@@ -7757,20 +23446,30 @@ func (q *Kernel) transform_RGBA_Image_Over(dst *image.RGBA, dr, adr image.Rectan
 				pb = pa
 			}
 
-			pr0 := uint32(fffftou(pr))
-			pg0 := uint32(fffftou(pg))
-			pb0 := uint32(fffftou(pb))
-			pa0 := uint32(fffftou(pa))
-			pa1 := (0xffff - uint32(pa0)) * 0x101
-			dst.Pix[d+0] = uint8((uint32(dst.Pix[d+0])*pa1/0xffff + pr0) >> 8)
-			dst.Pix[d+1] = uint8((uint32(dst.Pix[d+1])*pa1/0xffff + pg0) >> 8)
-			dst.Pix[d+2] = uint8((uint32(dst.Pix[d+2])*pa1/0xffff + pb0) >> 8)
-			dst.Pix[d+3] = uint8((uint32(dst.Pix[d+3])*pa1/0xffff + pa0) >> 8)
+			pa1 := 0xffff - uint32(fffftou(pa))
+			pqa := (uint32(dst.Pix[d+6])<<8 | uint32(dst.Pix[d+7]))
+			pr0 := ((uint32(dst.Pix[d+0])<<8|uint32(dst.Pix[d+1]))*pqa/0xffff)*pa1/0xffff + uint32(fffftou(pr))
+			pg0 := ((uint32(dst.Pix[d+2])<<8|uint32(dst.Pix[d+3]))*pqa/0xffff)*pa1/0xffff + uint32(fffftou(pg))
+			pb0 := ((uint32(dst.Pix[d+4])<<8|uint32(dst.Pix[d+5]))*pqa/0xffff)*pa1/0xffff + uint32(fffftou(pb))
+			pa0 := pqa*pa1/0xffff + uint32(fffftou(pa))
+			if pa0 != 0 && pa0 != 0xffff {
+				pr0 = pr0 * 0xffff / pa0
+				pg0 = pg0 * 0xffff / pa0
+				pb0 = pb0 * 0xffff / pa0
+			}
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
 		}
 	}
 }
 
-func (q *Kernel) transform_RGBA_Image_Src(dst *image.RGBA, dr, adr image.Rectangle, d2s *f64.Aff3, src image.Image, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
+func (q *Kernel) transform_NRGBA64_Image_Src(dst *image.NRGBA64, dr, adr image.Rectangle, d2s *f64.Aff3, src image.Image, sr image.Rectangle, bias image.Point, xscale, yscale float64, opts *Options) {
 	// When shrinking, broaden the effective kernel support so that we still
 	// visit every source pixel.
 	xHalfWidth, xKernelArgScale := q.Support, 1.0
@@ -7784,13 +23483,15 @@ func (q *Kernel) transform_RGBA_Image_Src(dst *image.RGBA, dr, adr image.Rectang
 		yKernelArgScale = 1 / yscale
 	}
 
-	xWeights := make([]float64, 1+2*int(math.Ceil(xHalfWidth)))
-	yWeights := make([]float64, 1+2*int(math.Ceil(yHalfWidth)))
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
 
 	for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
 		dyf := float64(dr.Min.Y+int(dy)) + 0.5
-		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
-		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+4 {
+		d := (dr.Min.Y+int(dy)-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*8
+		for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx, d = dx+1, d+8 {
 			dxf := float64(dr.Min.X+int(dx)) + 0.5
 			sx := float64(d2s[0]*dxf) + float64(d2s[1]*dyf) + d2s[2]
 			sy := float64(d2s[3]*dxf) + float64(d2s[4]*dyf) + d2s[5]
@@ -7873,10 +23574,23 @@ func (q *Kernel) transform_RGBA_Image_Src(dst *image.RGBA, dr, adr image.Rectang
 				pb = pa
 			}
 
-			dst.Pix[d+0] = uint8(fffftou(pr) >> 8)
-			dst.Pix[d+1] = uint8(fffftou(pg) >> 8)
-			dst.Pix[d+2] = uint8(fffftou(pb) >> 8)
-			dst.Pix[d+3] = uint8(fffftou(pa) >> 8)
+			pr0 := uint32(fffftou(pr))
+			pg0 := uint32(fffftou(pg))
+			pb0 := uint32(fffftou(pb))
+			pa0 := uint32(fffftou(pa))
+			if pa0 != 0 && pa0 != 0xffff {
+				pr0 = pr0 * 0xffff / pa0
+				pg0 = pg0 * 0xffff / pa0
+				pb0 = pb0 * 0xffff / pa0
+			}
+			dst.Pix[d+0] = uint8(pr0 >> 8)
+			dst.Pix[d+1] = uint8(pr0)
+			dst.Pix[d+2] = uint8(pg0 >> 8)
+			dst.Pix[d+3] = uint8(pg0)
+			dst.Pix[d+4] = uint8(pb0 >> 8)
+			dst.Pix[d+5] = uint8(pb0)
+			dst.Pix[d+6] = uint8(pa0 >> 8)
+			dst.Pix[d+7] = uint8(pa0)
 		}
 	}
 }
@@ -7895,8 +23609,10 @@ func (q *Kernel) transform_RGBA64Image_RGBA64Image_Over(dst RGBA64Image, dr, adr
 		yKernelArgScale = 1 / yscale
 	}
 
-	xWeights := make([]float64, 1+2*int(math.Ceil(xHalfWidth)))
-	yWeights := make([]float64, 1+2*int(math.Ceil(yHalfWidth)))
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
 
 	srcMask, smp := opts.SrcMask, opts.SrcMaskP
 	dstMask, dmp := opts.DstMask, opts.DstMaskP
@@ -8030,8 +23746,10 @@ func (q *Kernel) transform_RGBA64Image_RGBA64Image_Src(dst RGBA64Image, dr, adr
 		yKernelArgScale = 1 / yscale
 	}
 
-	xWeights := make([]float64, 1+2*int(math.Ceil(xHalfWidth)))
-	yWeights := make([]float64, 1+2*int(math.Ceil(yHalfWidth)))
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
 
 	srcMask, smp := opts.SrcMask, opts.SrcMaskP
 	dstMask, dmp := opts.DstMask, opts.DstMaskP
@@ -8167,8 +23885,10 @@ func (q *Kernel) transform_Image_Image_Over(dst Image, dr, adr image.Rectangle,
 		yKernelArgScale = 1 / yscale
 	}
 
-	xWeights := make([]float64, 1+2*int(math.Ceil(xHalfWidth)))
-	yWeights := make([]float64, 1+2*int(math.Ceil(yHalfWidth)))
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
 
 	srcMask, smp := opts.SrcMask, opts.SrcMaskP
 	dstMask, dmp := opts.DstMask, opts.DstMaskP
@@ -8302,8 +24022,10 @@ func (q *Kernel) transform_Image_Image_Src(dst Image, dr, adr image.Rectangle, d
 		yKernelArgScale = 1 / yscale
 	}
 
-	xWeights := make([]float64, 1+2*int(math.Ceil(xHalfWidth)))
-	yWeights := make([]float64, 1+2*int(math.Ceil(yHalfWidth)))
+	// The weights of small kernels fit in arrays on the stack.
+	var xWeightsBuf, yWeightsBuf [32]float64
+	xWeights := makeWeights(xWeightsBuf[:], 1+2*int(math.Ceil(xHalfWidth)))
+	yWeights := makeWeights(yWeightsBuf[:], 1+2*int(math.Ceil(yHalfWidth)))
 
 	srcMask, smp := opts.SrcMask, opts.SrcMaskP
 	dstMask, dmp := opts.DstMask, opts.DstMaskP
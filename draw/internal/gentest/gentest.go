@@ -0,0 +1,103 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:generate go run golang.org/x/image/draw/cmd/drawgen -config config.json -o impl.go
+
+// Package gentest holds image types that are not in the image package, and
+// the fast paths that the draw package's generator, drawgen, generates for
+// them from config.json.
+package gentest
+
+import (
+	"image"
+	"image/color"
+)
+
+// BGRA is an in-memory image like image.RGBA, but with the red and blue
+// bytes of each pixel swapped.
+type BGRA struct {
+	Pix    []uint8
+	Stride int
+	Rect   image.Rectangle
+}
+
+// NewBGRA returns a new BGRA image with the given bounds.
+func NewBGRA(r image.Rectangle) *BGRA {
+	return &BGRA{
+		Pix:    make([]uint8, 4*r.Dx()*r.Dy()),
+		Stride: 4 * r.Dx(),
+		Rect:   r,
+	}
+}
+
+func (p *BGRA) ColorModel() color.Model { return color.RGBAModel }
+
+func (p *BGRA) Bounds() image.Rectangle { return p.Rect }
+
+func (p *BGRA) At(x, y int) color.Color {
+	if !(image.Point{x, y}.In(p.Rect)) {
+		return color.RGBA{}
+	}
+	s := p.Pix[p.PixOffset(x, y):]
+	return color.RGBA{s[2], s[1], s[0], s[3]}
+}
+
+func (p *BGRA) Set(x, y int, c color.Color) {
+	if !(image.Point{x, y}.In(p.Rect)) {
+		return
+	}
+	c1 := color.RGBAModel.Convert(c).(color.RGBA)
+	s := p.Pix[p.PixOffset(x, y):]
+	s[0], s[1], s[2], s[3] = c1.B, c1.G, c1.R, c1.A
+}
+
+func (p *BGRA) PixOffset(x, y int) int {
+	return (y-p.Rect.Min.Y)*p.Stride + (x-p.Rect.Min.X)*4
+}
+
+// BGRX is an opaque in-memory image like BGRA, whose fourth byte of each
+// pixel is not used.
+type BGRX struct {
+	Pix    []uint8
+	Stride int
+	Rect   image.Rectangle
+}
+
+// NewBGRX returns a new BGRX image with the given bounds.
+func NewBGRX(r image.Rectangle) *BGRX {
+	return &BGRX{
+		Pix:    make([]uint8, 4*r.Dx()*r.Dy()),
+		Stride: 4 * r.Dx(),
+		Rect:   r,
+	}
+}
+
+func (p *BGRX) ColorModel() color.Model { return color.RGBAModel }
+
+func (p *BGRX) Bounds() image.Rectangle { return p.Rect }
+
+func (p *BGRX) At(x, y int) color.Color {
+	if !(image.Point{x, y}.In(p.Rect)) {
+		return color.RGBA{}
+	}
+	s := p.Pix[p.PixOffset(x, y):]
+	return color.RGBA{s[2], s[1], s[0], 0xff}
+}
+
+// Set sets the pixel at (x, y) to the alpha-premultiplied color of c, as if
+// it were over black.
+func (p *BGRX) Set(x, y int, c color.Color) {
+	if !(image.Point{x, y}.In(p.Rect)) {
+		return
+	}
+	c1 := color.RGBAModel.Convert(c).(color.RGBA)
+	s := p.Pix[p.PixOffset(x, y):]
+	s[0], s[1], s[2] = c1.B, c1.G, c1.R
+}
+
+func (p *BGRX) PixOffset(x, y int) int {
+	return (y-p.Rect.Min.Y)*p.Stride + (x-p.Rect.Min.X)*4
+}
+
+func (p *BGRX) Opaque() bool { return true }
@@ -0,0 +1,155 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gentest
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"math/rand"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/math/f64"
+)
+
+func randomImage(rng *rand.Rand, t string, r image.Rectangle) draw.Image {
+	var m draw.Image
+	switch t {
+	case "BGRA":
+		m = NewBGRA(r)
+	case "BGRX":
+		m = NewBGRX(r)
+	case "Gray":
+		m = image.NewGray(r)
+	case "NRGBA":
+		m = image.NewNRGBA(r)
+	case "RGBA":
+		m = image.NewRGBA(r)
+	}
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			c := color.NRGBA{uint8(rng.Intn(256)), uint8(rng.Intn(256)), uint8(rng.Intn(256)), uint8(rng.Intn(256))}
+			if x%3 == 0 {
+				c.A = 0xff
+			}
+			m.Set(x, y, c)
+		}
+	}
+	return m
+}
+
+// standard returns m, or a copy of m as an *image.RGBA if its type is not in
+// the image package.
+func standard(m draw.Image) draw.Image {
+	switch m.(type) {
+	case *BGRA, *BGRX:
+		c := image.NewRGBA(m.Bounds())
+		draw.Draw(c, c.Rect, m, c.Rect.Min, draw.Src)
+		return c
+	}
+	return m
+}
+
+func TestGenerated(t *testing.T) {
+	testCases := []struct {
+		dst, src string
+		q, want  draw.Interpolator
+	}{
+		{"BGRA", "BGRA", NearestNeighbor, draw.NearestNeighbor},
+		{"BGRA", "NRGBA", NearestNeighbor, draw.NearestNeighbor},
+		{"BGRX", "BGRX", NearestNeighbor, draw.NearestNeighbor},
+		{"BGRA", "BGRA", ApproxBiLinear, draw.ApproxBiLinear},
+		{"BGRA", "Gray", ApproxBiLinear, draw.ApproxBiLinear},
+		{"BGRX", "BGRA", ApproxBiLinear, draw.ApproxBiLinear},
+		{"RGBA", "BGRX", ApproxBiLinear, draw.ApproxBiLinear},
+		{"BGRA", "BGRA", CatmullRom, draw.CatmullRom},
+		{"BGRA", "RGBA", CatmullRom, draw.CatmullRom},
+		{"BGRX", "BGRX", BiLinear, draw.BiLinear},
+		{"RGBA", "BGRA", CatmullRom, draw.CatmullRom},
+		// Types without generated fast paths fall back to the draw package.
+		{"BGRA", "RGBA", NearestNeighbor, draw.NearestNeighbor},
+	}
+	dr := image.Rect(-3, 2, 21, 17)
+	sr := image.Rect(1, -2, 11, 9)
+	s2d := f64.Aff3{
+		1.6, 0.5, -3,
+		-0.4, 1.2, 7,
+	}
+	for _, tc := range testCases {
+		for _, op := range []draw.Op{draw.Over, draw.Src} {
+			rng := rand.New(rand.NewSource(1))
+			dst := randomImage(rng, tc.dst, image.Rect(0, 0, 20, 20))
+			src := randomImage(rng, tc.src, image.Rect(-5, -5, 15, 15))
+			for _, transform := range []bool{false, true} {
+				got, want := randomImage(rng, tc.dst, dst.Bounds()), standard(dst)
+				draw.Draw(got, got.Bounds(), dst, got.Bounds().Min, draw.Src)
+				if transform {
+					tc.q.Transform(got, s2d, src, sr, op, nil)
+					tc.want.Transform(want, s2d, standard(src), sr, op, nil)
+				} else {
+					tc.q.Scale(got, dr, src, sr, op, nil)
+					if _, ok := tc.q.(*Kernel); ok {
+						// The Kernel's Scale is a Transform by the matrix
+						// that maps sr to dr.
+						tc.want.Transform(want, f64.Aff3{
+							2.4, 0, -3 - 2.4*1,
+							0, 15.0 / 11, 2 + 15.0/11*2,
+						}, standard(src), sr, op, nil)
+					} else {
+						tc.want.Scale(want, dr, standard(src), sr, op, nil)
+					}
+				}
+
+				b := got.Bounds()
+				for y := b.Min.Y; y < b.Max.Y; y++ {
+					for x := b.Min.X; x < b.Max.X; x++ {
+						g := color.RGBAModel.Convert(got.At(x, y)).(color.RGBA)
+						w := color.RGBAModel.Convert(want.At(x, y)).(color.RGBA)
+						if tc.dst == "BGRX" {
+							g.A, w.A = 0, 0
+						}
+						if g != w {
+							t.Fatalf("%s from %s, %T, op=%v, transform=%t: at (%d, %d): got %v, want %v",
+								tc.dst, tc.src, tc.q, op, transform, x, y, g, w)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// TestImplUpToDate tests that impl.go is what the generator generates from
+// config.json, so that changes to the generator's templates are also tested
+// in standalone mode.
+func TestImplUpToDate(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping in short mode")
+	}
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go tool not found")
+	}
+	out := filepath.Join(t.TempDir(), "impl.go")
+	cmd := exec.Command(goTool, "run", "golang.org/x/image/draw/cmd/drawgen", "-config", "config.json", "-o", out)
+	if b, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("generator failed: %v\n%s", err, b)
+	}
+	want, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile("impl.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("impl.go is out of date; run go generate")
+	}
+}
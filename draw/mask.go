@@ -0,0 +1,150 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package draw
+
+import (
+	"image"
+)
+
+// CopyWithMask copies the part of the source image defined by src and sr,
+// multiplied by the mask image's alpha, and writes the result of a Porter-Duff
+// composition to the part of the destination image defined by dst and the
+// translation of sr so that sr.Min translates to dp. The point mp in mask is
+// aligned with sr.Min in src. A nil mask is treated as opaque.
+//
+// It is equivalent to calling DrawMask with the destination rectangle implied
+// by dp and sr, but is faster for some common combinations of image types,
+// such as tinting an icon by drawing an *image.Uniform or *image.NRGBA source
+// through an *image.Alpha mask onto an *image.RGBA destination.
+func CopyWithMask(dst Image, dp image.Point, src image.Image, sr image.Rectangle, mask image.Image, mp image.Point, op Op) {
+	drawMask(dst, sr.Add(dp.Sub(sr.Min)), src, sr.Min, mask, mp, op)
+}
+
+// drawMask is like DrawMask, with the same results, but with additional fast
+// paths for an *image.Alpha mask.
+func drawMask(dst Image, r image.Rectangle, src image.Image, sp image.Point, mask image.Image, mp image.Point, op Op) {
+	dst0, _ := dst.(*image.RGBA)
+	mask0, _ := mask.(*image.Alpha)
+	if dst0 == nil || mask0 == nil {
+		DrawMask(dst, r, src, sp, mask, mp, op)
+		return
+	}
+
+	// Clip r, and adjust sp and mp to match, in the same way as DrawMask.
+	orig := r.Min
+	r = r.Intersect(dst0.Rect).Intersect(src.Bounds().Add(orig.Sub(sp))).Intersect(mask0.Rect.Add(orig.Sub(mp)))
+	if r.Empty() {
+		return
+	}
+	delta := r.Min.Sub(orig)
+	sp, mp = sp.Add(delta), mp.Add(delta)
+
+	switch src0 := src.(type) {
+	case *image.NRGBA:
+		maskNRGBA(dst0, r, src0, sp, mask0, mp, op)
+		return
+	case *image.RGBA:
+		// The image/draw package already has a fast path for Over. For Src,
+		// overlapping images would need to be processed back to front.
+		if op == Src && src0 != dst0 {
+			maskRGBASrc(dst0, r, src0, sp, mask0, mp)
+			return
+		}
+	case *image.Uniform:
+		// The image/draw package already has a fast path for Over.
+		if op == Src {
+			maskUniformSrc(dst0, r, src0, mask0, mp)
+			return
+		}
+	}
+	DrawMask(dst, r, src, sp, mask, mp, op)
+}
+
+// The maskXxx functions below assume that r and the corresponding src and mask
+// rectangles have already been clipped. Their formulae match those of the
+// image/draw package.
+
+func maskNRGBA(dst *image.RGBA, r image.Rectangle, src *image.NRGBA, sp image.Point, mask *image.Alpha, mp image.Point, op Op) {
+	n := r.Dx()
+	d := dst.PixOffset(r.Min.X, r.Min.Y)
+	s := src.PixOffset(sp.X, sp.Y)
+	m := mask.PixOffset(mp.X, mp.Y)
+	for y := r.Min.Y; y < r.Max.Y; y, d, s, m = y+1, d+dst.Stride, s+src.Stride, m+mask.Stride {
+		dpix := dst.Pix[d : d+4*n]
+		spix := src.Pix[s : s+4*n]
+		mpix := mask.Pix[m : m+n]
+		for i, ma := range mpix {
+			q := dpix[4*i : 4*i+4 : 4*i+4]
+			if ma == 0 {
+				if op == Src {
+					q[0], q[1], q[2], q[3] = 0, 0, 0, 0
+				}
+				continue
+			}
+			p := spix[4*i : 4*i+4 : 4*i+4]
+			ma := uint32(ma) * 0x101
+			pa := uint32(p[3]) * 0x101
+			pr := uint32(p[0]) * pa / 0xff
+			pg := uint32(p[1]) * pa / 0xff
+			pb := uint32(p[2]) * pa / 0xff
+			if op == Src {
+				q[0] = uint8(pr * ma / 0xffff >> 8)
+				q[1] = uint8(pg * ma / 0xffff >> 8)
+				q[2] = uint8(pb * ma / 0xffff >> 8)
+				q[3] = uint8(pa * ma / 0xffff >> 8)
+				continue
+			}
+			a := (0xffff - (pa * ma / 0xffff)) * 0x101
+			q[0] = uint8((uint32(q[0])*a + pr*ma) / 0xffff >> 8)
+			q[1] = uint8((uint32(q[1])*a + pg*ma) / 0xffff >> 8)
+			q[2] = uint8((uint32(q[2])*a + pb*ma) / 0xffff >> 8)
+			q[3] = uint8((uint32(q[3])*a + pa*ma) / 0xffff >> 8)
+		}
+	}
+}
+
+func maskRGBASrc(dst *image.RGBA, r image.Rectangle, src *image.RGBA, sp image.Point, mask *image.Alpha, mp image.Point) {
+	n := r.Dx()
+	d := dst.PixOffset(r.Min.X, r.Min.Y)
+	s := src.PixOffset(sp.X, sp.Y)
+	m := mask.PixOffset(mp.X, mp.Y)
+	for y := r.Min.Y; y < r.Max.Y; y, d, s, m = y+1, d+dst.Stride, s+src.Stride, m+mask.Stride {
+		dpix := dst.Pix[d : d+4*n]
+		spix := src.Pix[s : s+4*n]
+		mpix := mask.Pix[m : m+n]
+		for i, ma := range mpix {
+			q := dpix[4*i : 4*i+4 : 4*i+4]
+			p := spix[4*i : 4*i+4 : 4*i+4]
+			if ma == 0xff {
+				copy(q, p)
+				continue
+			}
+			ma := uint32(ma) * 0x101
+			q[0] = uint8(uint32(p[0]) * 0x101 * ma / 0xffff >> 8)
+			q[1] = uint8(uint32(p[1]) * 0x101 * ma / 0xffff >> 8)
+			q[2] = uint8(uint32(p[2]) * 0x101 * ma / 0xffff >> 8)
+			q[3] = uint8(uint32(p[3]) * 0x101 * ma / 0xffff >> 8)
+		}
+	}
+}
+
+func maskUniformSrc(dst *image.RGBA, r image.Rectangle, src *image.Uniform, mask *image.Alpha, mp image.Point) {
+	pr, pg, pb, pa := src.RGBA()
+	n := r.Dx()
+	d := dst.PixOffset(r.Min.X, r.Min.Y)
+	m := mask.PixOffset(mp.X, mp.Y)
+	for y := r.Min.Y; y < r.Max.Y; y, d, m = y+1, d+dst.Stride, m+mask.Stride {
+		dpix := dst.Pix[d : d+4*n]
+		mpix := mask.Pix[m : m+n]
+		for i, ma := range mpix {
+			q := dpix[4*i : 4*i+4 : 4*i+4]
+			ma := uint32(ma) * 0x101
+			q[0] = uint8(pr * ma / 0xffff >> 8)
+			q[1] = uint8(pg * ma / 0xffff >> 8)
+			q[2] = uint8(pb * ma / 0xffff >> 8)
+			q[3] = uint8(pa * ma / 0xffff >> 8)
+		}
+	}
+}
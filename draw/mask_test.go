@@ -0,0 +1,116 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package draw
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"math/rand"
+	"testing"
+)
+
+func TestCopyWithMask(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	nrgba := image.NewNRGBA(image.Rect(-2, -3, 14, 11))
+	fillPix(rng, nrgba.Pix)
+	rgba := image.NewRGBA(image.Rect(1, 2, 17, 13))
+	fillPix(rng, rgba.Pix)
+	// Make the RGBA source valid alpha-premultiplied color.
+	for i := 0; i < len(rgba.Pix); i += 4 {
+		a := rgba.Pix[i+3]
+		rgba.Pix[i+0] = uint8(uint32(rgba.Pix[i+0]) * uint32(a) / 0xff)
+		rgba.Pix[i+1] = uint8(uint32(rgba.Pix[i+1]) * uint32(a) / 0xff)
+		rgba.Pix[i+2] = uint8(uint32(rgba.Pix[i+2]) * uint32(a) / 0xff)
+	}
+	gray := image.NewGray(image.Rect(0, 0, 12, 12))
+	fillPix(rng, gray.Pix)
+	srcs := []image.Image{
+		nrgba,
+		rgba,
+		gray,
+		image.NewUniform(color.NRGBA{0x40, 0x80, 0xc0, 0x90}),
+	}
+
+	alpha := image.NewAlpha(image.Rect(-4, 0, 10, 15))
+	fillPix(rng, alpha.Pix)
+	// Include fully transparent and fully opaque mask pixels.
+	for i := 0; i < len(alpha.Pix); i += 5 {
+		alpha.Pix[i] = 0
+	}
+	for i := 2; i < len(alpha.Pix); i += 7 {
+		alpha.Pix[i] = 0xff
+	}
+	gray16 := image.NewGray16(alpha.Rect)
+	fillPix(rng, gray16.Pix)
+	masks := []image.Image{nil, alpha, gray16}
+
+	testCases := []struct {
+		dp image.Point
+		sr image.Rectangle
+		mp image.Point
+	}{
+		{image.Pt(0, 0), image.Rect(0, 0, 8, 8), image.Pt(0, 0)},
+		{image.Pt(3, 2), image.Rect(1, 3, 9, 10), image.Pt(-2, 1)},
+		{image.Pt(-5, 4), image.Rect(-4, -4, 20, 20), image.Pt(0, 3)}, // Clipped.
+		{image.Pt(40, 40), image.Rect(0, 0, 5, 5), image.Pt(0, 0)},    // Out-of-bounds.
+	}
+
+	for _, op := range []Op{Over, Src} {
+		for _, src := range srcs {
+			for _, mask := range masks {
+				for _, tc := range testCases {
+					want := image.NewRGBA(image.Rect(-3, -1, 13, 12))
+					fillPix(rand.New(rand.NewSource(0)), want.Pix)
+					got := image.NewRGBA(want.Rect)
+					copy(got.Pix, want.Pix)
+					viaCopy := image.NewRGBA(want.Rect)
+					copy(viaCopy.Pix, want.Pix)
+
+					dr := tc.sr.Add(tc.dp.Sub(tc.sr.Min))
+					DrawMask(want, dr, src, tc.sr.Min, mask, tc.mp, op)
+					CopyWithMask(got, tc.dp, src, tc.sr, mask, tc.mp, op)
+					Copy(viaCopy, tc.dp, src, tc.sr, op, &Options{
+						SrcMask:  mask,
+						SrcMaskP: tc.mp.Sub(tc.sr.Min),
+					})
+
+					if !bytes.Equal(got.Pix, want.Pix) {
+						t.Errorf("CopyWithMask: op=%v, src=%T, mask=%T, dp=%v, sr=%v, mp=%v: pixels differ",
+							op, src, mask, tc.dp, tc.sr, tc.mp)
+					}
+					if !bytes.Equal(viaCopy.Pix, want.Pix) {
+						t.Errorf("Copy: op=%v, src=%T, mask=%T, dp=%v, sr=%v, mp=%v: pixels differ",
+							op, src, mask, tc.dp, tc.sr, tc.mp)
+					}
+				}
+			}
+		}
+	}
+}
+
+func benchCopyWithMask(b *testing.B, op Op, src image.Image) {
+	dst := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	mask := image.NewAlpha(dst.Rect)
+	fillPix(rand.New(rand.NewSource(0)), mask.Pix)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		CopyWithMask(dst, image.Point{}, src, dst.Rect, mask, image.Point{}, op)
+	}
+}
+
+func BenchmarkCopyWithMaskOverNRGBA(b *testing.B) {
+	src, err := srcNRGBA(image.Rect(0, 0, 64, 64))
+	if err != nil {
+		b.Fatal(err)
+	}
+	benchCopyWithMask(b, Over, src)
+}
+
+func BenchmarkCopyWithMaskSrcUniform(b *testing.B) {
+	benchCopyWithMask(b, Src, image.NewUniform(color.NRGBA{0x40, 0x80, 0xc0, 0x90}))
+}
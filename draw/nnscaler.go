@@ -0,0 +1,226 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package draw
+
+import (
+	"image"
+)
+
+// NewNearestNeighborScaler returns a Scaler that gives the same results as
+// NearestNeighbor but is optimized for scaling multiple times with the same
+// fixed destination and source width and height, such as when scaling the
+// frames of a video.
+//
+// The source column and row for each destination column and row are computed
+// once, when the Scaler is created, instead of once per pixel. Scaling with a
+// different width and height falls back to NearestNeighbor.Scale.
+func NewNearestNeighborScaler(dw, dh, sw, sh int) Scaler {
+	return &nnScaler{
+		dw: int32(dw),
+		dh: int32(dh),
+		sw: int32(sw),
+		sh: int32(sh),
+		xs: newNNIndexes(dw, sw),
+		ys: newNNIndexes(dh, sh),
+	}
+}
+
+type nnScaler struct {
+	dw, dh, sw, sh int32
+	// xs and ys are the source columns and rows, relative to sr.Min, that
+	// the destination columns and rows, relative to dr.Min, map to.
+	xs, ys []int32
+}
+
+// newNNIndexes returns the nearest neighbor source index for each of the dn
+// destination indexes, using the same formula as nnInterpolator.
+func newNNIndexes(dn, sn int) []int32 {
+	if dn <= 0 || sn <= 0 {
+		return nil
+	}
+	s := make([]int32, dn)
+	dn2, sn64 := uint64(dn)*2, uint64(sn)
+	for i := range s {
+		s[i] = int32((2*uint64(i) + 1) * sn64 / dn2)
+	}
+	return s
+}
+
+// Scale implements the Scaler interface.
+func (z *nnScaler) Scale(dst Image, dr image.Rectangle, src image.Image, sr image.Rectangle, op Op, opts *Options) {
+	if z.dw != int32(dr.Dx()) || z.dh != int32(dr.Dy()) || z.sw != int32(sr.Dx()) || z.sh != int32(sr.Dy()) ||
+		dr.Size() == sr.Size() || (opts != nil && (opts.DstMask != nil || opts.SrcMask != nil)) {
+		NearestNeighbor.Scale(dst, dr, src, sr, op, opts)
+		return
+	}
+
+	// adr is the affected destination pixels.
+	adr := dst.Bounds().Intersect(dr)
+	if adr.Empty() || sr.Empty() {
+		return
+	}
+	// Make adr relative to dr.Min.
+	adr = adr.Sub(dr.Min)
+	if op == Over && opaque(src) {
+		op = Src
+	}
+
+	// Like nnInterpolator's type-specific fast paths, these access the Pix
+	// fields directly and so require sr to be within the src bounds.
+	dstRGBA, ok := dst.(*image.RGBA)
+	if ok && sr.In(src.Bounds()) {
+		switch src := src.(type) {
+		case *image.Gray:
+			if op == Src {
+				z.scaleRGBAGraySrc(dstRGBA, dr, adr, src, sr)
+				return
+			}
+		case *image.NRGBA:
+			z.scaleRGBANRGBA(dstRGBA, dr, adr, src, sr, op)
+			return
+		case *image.RGBA:
+			z.scaleRGBARGBA(dstRGBA, dr, adr, src, sr, op)
+			return
+		case *image.YCbCr:
+			if op == Src && z.scaleRGBAYCbCrSrc(dstRGBA, dr, adr, src, sr) {
+				return
+			}
+		}
+	}
+	NearestNeighbor.Scale(dst, dr, src, sr, op, opts)
+}
+
+func (z *nnScaler) scaleRGBAGraySrc(dst *image.RGBA, dr, adr image.Rectangle, src *image.Gray, sr image.Rectangle) {
+	xs := z.xs[adr.Min.X:adr.Max.X]
+	for dy := adr.Min.Y; dy < adr.Max.Y; dy++ {
+		d := (dr.Min.Y+dy-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		dpix := dst.Pix[d : d+4*len(xs)]
+		s := (sr.Min.Y+int(z.ys[dy])-src.Rect.Min.Y)*src.Stride + (sr.Min.X - src.Rect.Min.X)
+		for i, sx := range xs {
+			out := src.Pix[s+int(sx)]
+			dpix[4*i+0] = out
+			dpix[4*i+1] = out
+			dpix[4*i+2] = out
+			dpix[4*i+3] = 0xff
+		}
+	}
+}
+
+func (z *nnScaler) scaleRGBANRGBA(dst *image.RGBA, dr, adr image.Rectangle, src *image.NRGBA, sr image.Rectangle, op Op) {
+	xs := z.xs[adr.Min.X:adr.Max.X]
+	for dy := adr.Min.Y; dy < adr.Max.Y; dy++ {
+		d := (dr.Min.Y+dy-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		dpix := dst.Pix[d : d+4*len(xs)]
+		s := (sr.Min.Y+int(z.ys[dy])-src.Rect.Min.Y)*src.Stride + (sr.Min.X-src.Rect.Min.X)*4
+		for i, sx := range xs {
+			pi := s + 4*int(sx)
+			pa := uint32(src.Pix[pi+3]) * 0x101
+			pr := uint32(src.Pix[pi+0]) * pa / 0xff
+			pg := uint32(src.Pix[pi+1]) * pa / 0xff
+			pb := uint32(src.Pix[pi+2]) * pa / 0xff
+			q := dpix[4*i : 4*i+4]
+			if op == Src {
+				q[0] = uint8(pr >> 8)
+				q[1] = uint8(pg >> 8)
+				q[2] = uint8(pb >> 8)
+				q[3] = uint8(pa >> 8)
+				continue
+			}
+			pa1 := (0xffff - pa) * 0x101
+			q[0] = uint8((uint32(q[0])*pa1/0xffff + pr) >> 8)
+			q[1] = uint8((uint32(q[1])*pa1/0xffff + pg) >> 8)
+			q[2] = uint8((uint32(q[2])*pa1/0xffff + pb) >> 8)
+			q[3] = uint8((uint32(q[3])*pa1/0xffff + pa) >> 8)
+		}
+	}
+}
+
+func (z *nnScaler) scaleRGBARGBA(dst *image.RGBA, dr, adr image.Rectangle, src *image.RGBA, sr image.Rectangle, op Op) {
+	xs := z.xs[adr.Min.X:adr.Max.X]
+	for dy := adr.Min.Y; dy < adr.Max.Y; dy++ {
+		d := (dr.Min.Y+dy-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		dpix := dst.Pix[d : d+4*len(xs)]
+		s := (sr.Min.Y+int(z.ys[dy])-src.Rect.Min.Y)*src.Stride + (sr.Min.X-src.Rect.Min.X)*4
+		for i, sx := range xs {
+			pi := s + 4*int(sx)
+			p := src.Pix[pi : pi+4]
+			q := dpix[4*i : 4*i+4]
+			if op == Src {
+				copy(q, p)
+				continue
+			}
+			pr := uint32(p[0]) * 0x101
+			pg := uint32(p[1]) * 0x101
+			pb := uint32(p[2]) * 0x101
+			pa := uint32(p[3]) * 0x101
+			pa1 := (0xffff - pa) * 0x101
+			q[0] = uint8((uint32(q[0])*pa1/0xffff + pr) >> 8)
+			q[1] = uint8((uint32(q[1])*pa1/0xffff + pg) >> 8)
+			q[2] = uint8((uint32(q[2])*pa1/0xffff + pb) >> 8)
+			q[3] = uint8((uint32(q[3])*pa1/0xffff + pa) >> 8)
+		}
+	}
+}
+
+// scaleRGBAYCbCrSrc returns whether src's subsample ratio is one that it
+// handles.
+func (z *nnScaler) scaleRGBAYCbCrSrc(dst *image.RGBA, dr, adr image.Rectangle, src *image.YCbCr, sr image.Rectangle) bool {
+	// hShift and vShift are 1 if the chroma planes are subsampled
+	// horizontally and vertically.
+	hShift, vShift := 0, 0
+	switch src.SubsampleRatio {
+	case image.YCbCrSubsampleRatio444:
+	case image.YCbCrSubsampleRatio422:
+		hShift = 1
+	case image.YCbCrSubsampleRatio420:
+		hShift, vShift = 1, 1
+	case image.YCbCrSubsampleRatio440:
+		vShift = 1
+	default:
+		return false
+	}
+
+	xs := z.xs[adr.Min.X:adr.Max.X]
+	for dy := adr.Min.Y; dy < adr.Max.Y; dy++ {
+		d := (dr.Min.Y+dy-dst.Rect.Min.Y)*dst.Stride + (dr.Min.X+adr.Min.X-dst.Rect.Min.X)*4
+		dpix := dst.Pix[d : d+4*len(xs)]
+		y := sr.Min.Y + int(z.ys[dy])
+		yRow := (y - src.Rect.Min.Y) * src.YStride
+		cRow := (y/(1<<vShift) - src.Rect.Min.Y/(1<<vShift)) * src.CStride
+		for i, sx := range xs {
+			x := sr.Min.X + int(sx)
+			pi := yRow + (x - src.Rect.Min.X)
+			pj := cRow + (x/(1<<hShift) - src.Rect.Min.X/(1<<hShift))
+
+			// This is an inline version of image/color/ycbcr.go's YCbCr.RGBA method.
+			pyy1 := int(src.Y[pi]) * 0x10101
+			pcb1 := int(src.Cb[pj]) - 128
+			pcr1 := int(src.Cr[pj]) - 128
+			pr := (pyy1 + 91881*pcr1) >> 8
+			pg := (pyy1 - 22554*pcb1 - 46802*pcr1) >> 8
+			pb := (pyy1 + 116130*pcb1) >> 8
+			if pr < 0 {
+				pr = 0
+			} else if pr > 0xffff {
+				pr = 0xffff
+			}
+			if pg < 0 {
+				pg = 0
+			} else if pg > 0xffff {
+				pg = 0xffff
+			}
+			if pb < 0 {
+				pb = 0
+			} else if pb > 0xffff {
+				pb = 0xffff
+			}
+			dpix[4*i+0] = uint8(pr >> 8)
+			dpix[4*i+1] = uint8(pg >> 8)
+			dpix[4*i+2] = uint8(pb >> 8)
+			dpix[4*i+3] = 0xff
+		}
+	}
+	return true
+}
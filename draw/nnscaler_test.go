@@ -0,0 +1,109 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package draw
+
+import (
+	"bytes"
+	"image"
+	"math/rand"
+	"testing"
+)
+
+func TestNearestNeighborScaler(t *testing.T) {
+	srcfs := []func(image.Rectangle) (image.Image, error){
+		srcGray,
+		srcNRGBA,
+		srcRGBA,
+		srcUnif,
+		srcYCbCr,
+		srcRGBA64,
+	}
+	ratios := []image.YCbCrSubsampleRatio{
+		image.YCbCrSubsampleRatio444,
+		image.YCbCrSubsampleRatio422,
+		image.YCbCrSubsampleRatio440,
+		image.YCbCrSubsampleRatio411,
+	}
+	var srcs []image.Image
+	for _, srcf := range srcfs {
+		src, err := srcf(image.Rect(-3, -2, 17, 13))
+		if err != nil {
+			t.Fatal(err)
+		}
+		srcs = append(srcs, src)
+	}
+	for _, ratio := range ratios {
+		m := image.NewYCbCr(image.Rect(-3, -2, 17, 13), ratio)
+		fillPix(rand.New(rand.NewSource(5)), m.Y, m.Cb, m.Cr)
+		srcs = append(srcs, m)
+	}
+
+	testCases := []struct {
+		dr, sr image.Rectangle
+	}{
+		{image.Rect(0, 0, 30, 25), image.Rect(-3, -2, 17, 13)}, // Scale up.
+		{image.Rect(2, 3, 9, 8), image.Rect(-1, 0, 16, 12)},    // Scale down.
+		{image.Rect(-7, -4, 13, 19), image.Rect(0, 1, 11, 9)},  // Partial dst out-of-bounds.
+		{image.Rect(1, 1, 23, 17), image.Rect(10, 8, 21, 16)},  // Partial src out-of-bounds.
+		{image.Rect(40, 40, 50, 50), image.Rect(0, 0, 5, 5)},   // Complete dst out-of-bounds.
+		{image.Rect(3, 2, 10, 6), image.Rect(4, 4, 11, 8)},     // Same size.
+	}
+
+	for _, op := range []Op{Over, Src} {
+		for _, src := range srcs {
+			for _, tc := range testCases {
+				want := image.NewRGBA(image.Rect(-1, -2, 24, 20))
+				fillPix(rand.New(rand.NewSource(0)), want.Pix)
+				got := image.NewRGBA(want.Rect)
+				copy(got.Pix, want.Pix)
+
+				NearestNeighbor.Scale(want, tc.dr, src, tc.sr, op, nil)
+				z := NewNearestNeighborScaler(tc.dr.Dx(), tc.dr.Dy(), tc.sr.Dx(), tc.sr.Dy())
+				z.Scale(got, tc.dr, src, tc.sr, op, nil)
+
+				if !bytes.Equal(got.Pix, want.Pix) {
+					t.Errorf("op=%v, src=%T, dr=%v, sr=%v: pixels differ", op, src, tc.dr, tc.sr)
+				}
+			}
+		}
+	}
+}
+
+func TestNearestNeighborScalerSizeMismatch(t *testing.T) {
+	src, err := srcRGBA(image.Rect(0, 0, 16, 16))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dr, sr := image.Rect(0, 0, 20, 10), image.Rect(0, 0, 16, 16)
+
+	want := image.NewRGBA(dr)
+	NearestNeighbor.Scale(want, dr, src, sr, Src, nil)
+	got := image.NewRGBA(dr)
+	NewNearestNeighborScaler(7, 7, 3, 3).Scale(got, dr, src, sr, Src, nil)
+
+	if !bytes.Equal(got.Pix, want.Pix) {
+		t.Errorf("pixels differ")
+	}
+}
+
+func benchNNScaler(b *testing.B, w int, h int, op Op, srcf func(image.Rectangle) (image.Image, error)) {
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	src, err := srcf(image.Rect(0, 0, 1024, 768))
+	if err != nil {
+		b.Fatal(err)
+	}
+	dr, sr := dst.Bounds(), src.Bounds()
+	z := NewNearestNeighborScaler(dr.Dx(), dr.Dy(), sr.Dx(), sr.Dy())
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		z.Scale(dst, dr, src, sr, op, nil)
+	}
+}
+
+func BenchmarkNNScalerSrcRGBA(b *testing.B)   { benchNNScaler(b, 200, 150, Src, srcRGBA) }
+func BenchmarkNNScalerSrcYCbCr(b *testing.B)  { benchNNScaler(b, 200, 150, Src, srcYCbCr) }
+func BenchmarkNNScalerOverNRGBA(b *testing.B) { benchNNScaler(b, 200, 150, Over, srcNRGBA) }
@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-//go:generate go run gen.go
+//go:generate go run ./cmd/drawgen
 
 package draw
 
@@ -24,8 +24,10 @@ func Copy(dst Image, dp image.Point, src image.Image, sr image.Rectangle, op Op,
 		o = *opts
 	}
 	dr := sr.Add(dp.Sub(sr.Min))
-	if o.DstMask == nil {
-		DrawMask(dst, dr, src, sr.Min, o.SrcMask, o.SrcMaskP.Add(sr.Min), op)
+	if t, ok := src.(*tile); ok && o.DstMask == nil && o.SrcMask == nil {
+		t.draw(dst, dr, sr.Min, op)
+	} else if o.DstMask == nil {
+		drawMask(dst, dr, src, sr.Min, o.SrcMask, o.SrcMaskP.Add(sr.Min), op)
 	} else {
 		NearestNeighbor.Scale(dst, dr, src, sr, op, opts)
 	}
@@ -35,6 +37,13 @@ func Copy(dst Image, dp image.Point, src image.Image, sr image.Rectangle, op Op,
 // the result of a Porter-Duff composition to the part of the destination image
 // defined by dst and dr.
 //
+// The dst and src images may share pixels, such as when they are the same
+// image or sub-images of the same image. If the affected parts overlap, the
+// result is as if the src pixels were read before any dst pixels were written,
+// at the cost of copying them to a temporary image. The overlap is detected
+// for the image types in the standard library's image package, and otherwise
+// only when dst and src are the same image.
+//
 // A Scaler is safe to use concurrently.
 type Scaler interface {
 	Scale(dst Image, dr image.Rectangle, src image.Image, sr image.Rectangle, op Op, opts *Options)
@@ -52,6 +61,8 @@ type Scaler interface {
 // then the src-space point (sx, sy) maps to the dst-space point
 // (m00*sx + m01*sy + m02, m10*sx + m11*sy + m12).
 //
+// As for a Scaler, the dst and src images may share pixels.
+//
 // A Transformer is safe to use concurrently.
 type Transformer interface {
 	Transform(dst Image, m f64.Aff3, src image.Image, sr image.Rectangle, op Op, opts *Options)
@@ -128,20 +139,43 @@ func (q *Kernel) Scale(dst Image, dr image.Rectangle, src image.Image, sr image.
 
 // NewScaler returns a Scaler that is optimized for scaling multiple times with
 // the same fixed destination and source width and height.
+//
+// Unlike Kernel.Scale, which computes the kernel weights and allocates a
+// temporary buffer on every call, the returned Scaler re-uses them. Once it
+// has been used, scaling between the image types of the standard library's
+// image package does not usually allocate memory, unless the dst and src
+// pixels overlap.
 func (q *Kernel) NewScaler(dw, dh, sw, sh int) Scaler {
 	return q.newScaler(dw, dh, sw, sh, true)
 }
 
 func (q *Kernel) newScaler(dw, dh, sw, sh int, usePool bool) Scaler {
+	return newKernelScaler(q, q, dw, dh, sw, sh, usePool)
+}
+
+// NewScaler2 is like Kernel.NewScaler but uses separate kernels for the
+// horizontal and vertical axes. This can avoid aliasing along one axis when
+// the horizontal and vertical scales differ greatly, such as when stretching
+// a waveform: a kernel with a wide support can be used for the axis that is
+// shrunk a lot and a sharper kernel for the other.
+//
+// Passing the same kernel for kx and ky is equivalent to kx.NewScaler.
+func NewScaler2(kx, ky *Kernel, dw, dh, sw, sh int) Scaler {
+	return newKernelScaler(kx, ky, dw, dh, sw, sh, true)
+}
+
+func newKernelScaler(kx, ky *Kernel, dw, dh, sw, sh int, usePool bool) *kernelScaler {
 	z := &kernelScaler{
-		kernel:     q,
+		kx:         kx,
+		ky:         ky,
 		dw:         int32(dw),
 		dh:         int32(dh),
 		sw:         int32(sw),
 		sh:         int32(sh),
-		horizontal: newDistrib(q, int32(dw), int32(sw)),
-		vertical:   newDistrib(q, int32(dh), int32(sh)),
+		horizontal: newDistrib(kx, int32(dw), int32(sw)),
+		vertical:   newDistrib(ky, int32(dh), int32(sh)),
 	}
+	z.identity = z.horizontal.isIdentity() && z.vertical.isIdentity()
 	if usePool {
 		z.pool.New = func() interface{} {
 			tmp := z.makeTmpBuf()
@@ -195,10 +229,15 @@ type nnInterpolator struct{}
 type ablInterpolator struct{}
 
 type kernelScaler struct {
-	kernel               *Kernel
+	// kx and ky are the horizontal and vertical kernels.
+	kx, ky               *Kernel
 	dw, dh, sw, sh       int32
 	horizontal, vertical distrib
-	pool                 sync.Pool
+	// identity is whether each dst pixel is the src pixel at the same
+	// relative position, such as for a 1:1 scale by the BiLinear or
+	// CatmullRom kernels, so that scaling is a copy.
+	identity bool
+	pool     sync.Pool
 }
 
 func (z *kernelScaler) makeTmpBuf() [][4]float64 {
@@ -291,6 +330,22 @@ func newDistrib(q *Kernel, dw, sw int32) distrib {
 	return distrib{sources, contribs}
 }
 
+// isIdentity returns whether d maps each destination column (or row) to the
+// source column (or row) with the same index, and no other. A 1:1 distrib of
+// a kernel that is zero at every non-zero integer, such as the BiLinear and
+// CatmullRom kernels, is an identity.
+func (d *distrib) isIdentity() bool {
+	if len(d.sources) != len(d.contribs) {
+		return false
+	}
+	for k, s := range d.sources {
+		if s.i != int32(k) || s.j != int32(k+1) || d.contribs[k].coord != int32(k) {
+			return false
+		}
+	}
+	return true
+}
+
 // abs is like math.Abs, but it doesn't care about negative zero, infinities or
 // NaNs.
 func abs(f float64) float64 {
@@ -302,24 +357,22 @@ func abs(f float64) float64 {
 
 // ftou converts the range [0.0, 1.0] to [0, 0xffff].
 func ftou(f float64) uint16 {
-	i := int32(float64(0xffff*f) + 0.5)
-	if i > 0xffff {
-		return 0xffff
-	}
-	if i > 0 {
-		return uint16(i)
-	}
-	return 0
+	return fffftou(float64(0xffff * f))
 }
 
 // fffftou converts the range [0.0, 65535.0] to [0, 0xffff].
+//
+// Out of range values, including NaN, saturate instead of wrapping around: the
+// comparisons are made before converting to an integer type, as converting a
+// float64 that does not fit in an int32 gives an implementation-specific
+// result.
 func fffftou(f float64) uint16 {
-	i := int32(f + 0.5)
-	if i > 0xffff {
+	f += 0.5
+	if f >= 0xffff {
 		return 0xffff
 	}
-	if i > 0 {
-		return uint16(i)
+	if f >= 1 {
+		return uint16(f)
 	}
 	return 0
 }
@@ -416,6 +469,7 @@ func clipAffectedDestRect(adr image.Rectangle, dstMask image.Image, dstMaskP ima
 }
 
 func transform_Uniform(dst Image, dr, adr image.Rectangle, d2s *f64.Aff3, src *image.Uniform, sr image.Rectangle, bias image.Point, op Op) {
+	dst = palettedDst(dst)
 	switch op {
 	case Over:
 		switch dst := dst.(type) {
@@ -440,6 +494,29 @@ func transform_Uniform(dst Image, dr, adr image.Rectangle, d2s *f64.Aff3, src *i
 				}
 			}
 
+		case RGBA64Image:
+			pr, pg, pb, pa := src.C.RGBA()
+			pa1 := 0xffff - pa
+
+			for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+				dyf := float64(dr.Min.Y+int(dy)) + 0.5
+				for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
+					dxf := float64(dr.Min.X+int(dx)) + 0.5
+					sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+					sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+					if !(image.Point{sx0, sy0}).In(sr) {
+						continue
+					}
+					q := dst.RGBA64At(dr.Min.X+int(dx), dr.Min.Y+int(dy))
+					dst.SetRGBA64(dr.Min.X+int(dx), dr.Min.Y+int(dy), color.RGBA64{
+						uint16(uint32(q.R)*pa1/0xffff + pr),
+						uint16(uint32(q.G)*pa1/0xffff + pg),
+						uint16(uint32(q.B)*pa1/0xffff + pb),
+						uint16(uint32(q.A)*pa1/0xffff + pa),
+					})
+				}
+			}
+
 		default:
 			pr, pg, pb, pa := src.C.RGBA()
 			pa1 := 0xffff - pa
@@ -491,6 +568,28 @@ func transform_Uniform(dst Image, dr, adr image.Rectangle, d2s *f64.Aff3, src *i
 				}
 			}
 
+		case RGBA64Image:
+			pr, pg, pb, pa := src.C.RGBA()
+			dstColorRGBA64 := color.RGBA64{
+				uint16(pr),
+				uint16(pg),
+				uint16(pb),
+				uint16(pa),
+			}
+
+			for dy := int32(adr.Min.Y); dy < int32(adr.Max.Y); dy++ {
+				dyf := float64(dr.Min.Y+int(dy)) + 0.5
+				for dx := int32(adr.Min.X); dx < int32(adr.Max.X); dx++ {
+					dxf := float64(dr.Min.X+int(dx)) + 0.5
+					sx0 := int(float64(d2s[0]*dxf)+float64(d2s[1]*dyf)+d2s[2]) + bias.X
+					sy0 := int(float64(d2s[3]*dxf)+float64(d2s[4]*dyf)+d2s[5]) + bias.Y
+					if !(image.Point{sx0, sy0}).In(sr) {
+						continue
+					}
+					dst.SetRGBA64(dr.Min.X+int(dx), dr.Min.Y+int(dy), dstColorRGBA64)
+				}
+			}
+
 		default:
 			pr, pg, pb, pa := src.C.RGBA()
 			dstColorRGBA64 := &color.RGBA64{
@@ -517,6 +616,63 @@ func transform_Uniform(dst Image, dr, adr image.Rectangle, d2s *f64.Aff3, src *i
 	}
 }
 
+// makeWeights returns a slice of n kernel weights, using buf if it is large
+// enough.
+func makeWeights(buf []float64, n int) []float64 {
+	if n <= len(buf) {
+		return buf[:n]
+	}
+	return make([]float64, n)
+}
+
+// palettedImage is an *image.Paletted whose SetRGBA64 method does not
+// allocate. That of *image.Paletted converts its color to a color.Color to
+// find the nearest palette color, which allocates for every pixel.
+type palettedImage struct {
+	*image.Paletted
+}
+
+func (p palettedImage) SetRGBA64(x, y int, c color.RGBA64) {
+	if !(image.Point{x, y}.In(p.Rect)) {
+		return
+	}
+	p.Pix[p.PixOffset(x, y)] = uint8(paletteIndex(p.Palette, c))
+}
+
+// palettedDst returns dst, wrapped in a palettedImage if it is an
+// *image.Paletted, for the fast paths that set pixels with SetRGBA64.
+func palettedDst(dst Image) Image {
+	if p, ok := dst.(*image.Paletted); ok {
+		return palettedImage{p}
+	}
+	return dst
+}
+
+// paletteIndex is like p.Index(c), returning the index of the palette color
+// closest to c in Euclidean R,G,B,A space, but without converting c to a
+// color.Color.
+func paletteIndex(p color.Palette, c color.RGBA64) int {
+	ret, bestSum := 0, uint32(1<<32-1)
+	for i, v := range p {
+		vr, vg, vb, va := v.RGBA()
+		sum := sqDiff(uint32(c.R), vr) + sqDiff(uint32(c.G), vg) + sqDiff(uint32(c.B), vb) + sqDiff(uint32(c.A), va)
+		if sum < bestSum {
+			if sum == 0 {
+				return i
+			}
+			ret, bestSum = i, sum
+		}
+	}
+	return ret
+}
+
+// sqDiff returns the squared-difference of x and y, shifted by 2 so that
+// adding four of those won't overflow a uint32, as color.Palette.Index does.
+func sqDiff(x, y uint32) uint32 {
+	d := x - y
+	return (d * d) >> 2
+}
+
 func opaque(m image.Image) bool {
 	o, ok := m.(interface {
 		Opaque() bool
@@ -10,7 +10,9 @@ import (
 	"fmt"
 	"image"
 	"image/color"
+	"image/color/palette"
 	"image/png"
+	"math"
 	"math/rand"
 	"os"
 	"reflect"
@@ -21,6 +23,9 @@ import (
 	_ "image/jpeg"
 )
 
+// raceEnabled is whether the race detector is enabled.
+var raceEnabled = false
+
 var genGoldenFiles = flag.Bool("gen_golden_files", false, "whether to generate the TestXxx golden files.")
 
 var transformMatrix = func(scale, tx, ty float64) f64.Aff3 {
@@ -276,6 +281,51 @@ func fillPix(r *rand.Rand, pixs ...[]byte) {
 	}
 }
 
+func TestNewScaler2(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 60, 4))
+	fillPix(rand.New(rand.NewSource(0)), src.Pix)
+	sr := src.Bounds()
+	dr := image.Rect(0, 0, 15, 16)
+
+	scale := func(z Scaler, dr image.Rectangle) *image.RGBA {
+		dst := image.NewRGBA(dr)
+		z.Scale(dst, dr, src, sr, Src, nil)
+		return dst
+	}
+
+	// Using the same kernel for both axes is the same as Kernel.NewScaler.
+	for _, q := range []*Kernel{BiLinear, CatmullRom} {
+		got := scale(NewScaler2(q, q, dr.Dx(), dr.Dy(), sr.Dx(), sr.Dy()), dr)
+		want := scale(q.NewScaler(dr.Dx(), dr.Dy(), sr.Dx(), sr.Dy()), dr)
+		if !bytes.Equal(got.Pix, want.Pix) {
+			t.Errorf("same kernels: pixels differ from Kernel.NewScaler")
+		}
+	}
+
+	// Different kernels are used along each axis.
+	z := NewScaler2(BiLinear, CatmullRom, dr.Dx(), dr.Dy(), sr.Dx(), sr.Dy()).(*kernelScaler)
+	if want := newDistrib(BiLinear, int32(dr.Dx()), int32(sr.Dx())); !reflect.DeepEqual(z.horizontal, want) {
+		t.Errorf("horizontal distrib does not use the kx kernel")
+	}
+	if want := newDistrib(CatmullRom, int32(dr.Dy()), int32(sr.Dy())); !reflect.DeepEqual(z.vertical, want) {
+		t.Errorf("vertical distrib does not use the ky kernel")
+	}
+	mixed := scale(z, dr)
+	for _, q := range []*Kernel{BiLinear, CatmullRom} {
+		if other := scale(q.NewScaler(dr.Dx(), dr.Dy(), sr.Dx(), sr.Dy()), dr); bytes.Equal(mixed.Pix, other.Pix) {
+			t.Errorf("different kernels: pixels match a single kernel's")
+		}
+	}
+
+	// Scaling with a different size should still use both kernels.
+	dr2 := image.Rect(0, 0, 20, 9)
+	got := scale(z, dr2)
+	want := scale(NewScaler2(BiLinear, CatmullRom, dr2.Dx(), dr2.Dy(), sr.Dx(), sr.Dy()), dr2)
+	if !bytes.Equal(got.Pix, want.Pix) {
+		t.Errorf("size mismatch: pixels differ")
+	}
+}
+
 func TestInterpClipCommute(t *testing.T) {
 	src := image.NewNRGBA(image.Rect(0, 0, 20, 20))
 	fillPix(rand.New(rand.NewSource(0)), src.Pix)
@@ -549,6 +599,51 @@ func TestRectDstMask(t *testing.T) {
 	}
 }
 
+func TestUniform(t *testing.T) {
+	src := image.NewUniform(color.NRGBA{0x40, 0x80, 0xc0, 0x90})
+	sr := image.Rect(0, 0, 7, 5)
+	qs := []Interpolator{
+		NearestNeighbor,
+		ApproxBiLinear,
+		BiLinear,
+		CatmullRom,
+	}
+	optss := []*Options{
+		nil,
+		{DstMask: image.Rect(10, 5, 25, 17), DstMaskP: image.Point{3, 2}},
+	}
+	for _, q := range qs {
+		for _, op := range []Op{Over, Src} {
+			for _, opts := range optss {
+				for _, transform := range []bool{false, true} {
+					got := image.NewRGBA(image.Rect(0, 0, 40, 30))
+					fillPix(rand.New(rand.NewSource(0)), got.Pix)
+					want := image.NewRGBA(got.Rect)
+					copy(want.Pix, got.Pix)
+
+					// The srcWrapper hides the *image.Uniform type, so that
+					// want is computed by the generic code path.
+					if transform {
+						q.Transform(got, transformMatrix(3, 20, 2), src, sr, op, opts)
+						q.Transform(want, transformMatrix(3, 20, 2), srcWrapper{src}, sr, op, opts)
+					} else {
+						q.Scale(got, image.Rect(2, 3, 33, 27), src, sr, op, opts)
+						q.Scale(want, image.Rect(2, 3, 33, 27), srcWrapper{src}, sr, op, opts)
+					}
+
+					for i := range got.Pix {
+						if d := int(got.Pix[i]) - int(want.Pix[i]); d < -1 || 1 < d {
+							t.Errorf("q=%T, op=%v, opts=%v, transform=%t: Pix[%d]: got %#02x, want %#02x",
+								q, op, opts, transform, i, got.Pix[i], want.Pix[i])
+							break
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
 func TestDstMaskSameSizeCopy(t *testing.T) {
 	bounds := image.Rect(0, 0, 42, 42)
 	src := image.Opaque
@@ -560,16 +655,60 @@ func TestDstMaskSameSizeCopy(t *testing.T) {
 	})
 }
 
+func TestScaleSameSize(t *testing.T) {
+	src, err := srcRGBA(image.Rect(0, 0, 37, 29))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sr := image.Rect(3, 2, 34, 27)
+	dr := image.Rect(10, 5, 41, 30)
+	gaussian := &Kernel{2, func(t float64) float64 {
+		return math.Exp(-2 * t * t)
+	}}
+	for _, op := range []Op{Over, Src} {
+		for name, q := range map[string]Interpolator{
+			"nn": NearestNeighbor,
+			"ab": ApproxBiLinear,
+			"bl": BiLinear,
+			"cr": CatmullRom,
+		} {
+			want := image.NewRGBA(image.Rect(0, 0, 50, 40))
+			fillPix(rand.New(rand.NewSource(1)), want.Pix)
+			got := image.NewRGBA(want.Rect)
+			copy(got.Pix, want.Pix)
+
+			Copy(want, dr.Min, src, sr, op, nil)
+			q.Scale(got, dr, src, sr, op, nil)
+			if !bytes.Equal(got.Pix, want.Pix) {
+				t.Errorf("op=%d, %s: Scale and Copy differ", op, name)
+			}
+		}
+	}
+
+	// A kernel that is not zero at 1 blurs, even without scaling.
+	if newKernelScaler(gaussian, gaussian, 31, 25, 31, 25, false).identity {
+		t.Error("gaussian: got identity, want not")
+	}
+	for _, q := range []*Kernel{BiLinear, CatmullRom} {
+		if !newKernelScaler(q, q, 31, 25, 31, 25, false).identity {
+			t.Error("31x25 to 31x25: got not identity, want identity")
+		}
+		if newKernelScaler(q, q, 31, 25, 31, 26, false).identity {
+			t.Error("31x25 to 31x26: got identity, want not")
+		}
+	}
+}
+
 func TestScaleRGBA64ImageAllocations(t *testing.T) {
 	// The goal of RGBA64Image is to prevent heap allocation of the color
 	// argument by using a non-interface type. Assert that we meet that goal.
-	// This assumes there is no fast path for *image.RGBA64.
+	// The images are wrapped so that the *image.RGBA64 fast paths are not used.
 	src := image.NewRGBA64(image.Rect(0, 0, 16, 32))
 	dst := image.NewRGBA64(image.Rect(0, 0, 32, 16))
 	fillPix(rand.New(rand.NewSource(1)), src.Pix, dst.Pix)
 	t.Run("Over", func(t *testing.T) {
 		allocs := testing.AllocsPerRun(10, func() {
-			CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), Over, nil)
+			CatmullRom.Scale(rgba64Wrapper{dst}, dst.Bounds(), rgba64Wrapper{src}, src.Bounds(), Over, nil)
 		})
 		// Scale and Transform below allocate on their own, so allocations will
 		// never be zero. The expectation we want to check is that the number
@@ -582,7 +721,7 @@ func TestScaleRGBA64ImageAllocations(t *testing.T) {
 	})
 	t.Run("Src", func(t *testing.T) {
 		allocs := testing.AllocsPerRun(10, func() {
-			CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), Src, nil)
+			CatmullRom.Scale(rgba64Wrapper{dst}, dst.Bounds(), rgba64Wrapper{src}, src.Bounds(), Src, nil)
 		})
 		if allocs > 8 {
 			t.Errorf("too many allocations: %v", allocs)
@@ -591,7 +730,7 @@ func TestScaleRGBA64ImageAllocations(t *testing.T) {
 }
 
 func TestTransformRGBA64ImageAllocations(t *testing.T) {
-	// This assumes there is no fast path for *image.RGBA64.
+	// The images are wrapped so that the *image.RGBA64 fast paths are not used.
 	src := image.NewRGBA64(image.Rect(0, 0, 16, 32))
 	dst := image.NewRGBA64(image.Rect(0, 0, 32, 16))
 	fillPix(rand.New(rand.NewSource(1)), src.Pix, dst.Pix)
@@ -601,7 +740,7 @@ func TestTransformRGBA64ImageAllocations(t *testing.T) {
 	}
 	t.Run("Over", func(t *testing.T) {
 		allocs := testing.AllocsPerRun(10, func() {
-			CatmullRom.Transform(dst, mat, src, src.Bounds(), Over, nil)
+			CatmullRom.Transform(rgba64Wrapper{dst}, mat, rgba64Wrapper{src}, src.Bounds(), Over, nil)
 		})
 		if allocs > 8 {
 			t.Errorf("too many allocations: %v", allocs)
@@ -609,7 +748,7 @@ func TestTransformRGBA64ImageAllocations(t *testing.T) {
 	})
 	t.Run("Src", func(t *testing.T) {
 		allocs := testing.AllocsPerRun(10, func() {
-			CatmullRom.Transform(dst, mat, src, src.Bounds(), Src, nil)
+			CatmullRom.Transform(rgba64Wrapper{dst}, mat, rgba64Wrapper{src}, src.Bounds(), Src, nil)
 		})
 		if allocs > 8 {
 			t.Errorf("too many allocations: %v", allocs)
@@ -617,13 +756,185 @@ func TestTransformRGBA64ImageAllocations(t *testing.T) {
 	})
 }
 
+func TestScaleZeroAllocations(t *testing.T) {
+	// Scaling with preallocated scalers, and transforming with the non-kernel
+	// interpolators, allocates nothing for small images of the standard
+	// library's types.
+	rng := rand.New(rand.NewSource(1))
+	sr := image.Rect(0, 0, 24, 20)
+	srcRGBA, srcNRGBA := image.NewRGBA(sr), image.NewNRGBA(sr)
+	fillPix(rng, srcRGBA.Pix, srcNRGBA.Pix)
+	srcs := []image.Image{
+		srcRGBA,
+		srcNRGBA,
+		image.NewGray(sr),
+		image.NewYCbCr(sr, image.YCbCrSubsampleRatio420),
+		image.NewNYCbCrA(sr, image.YCbCrSubsampleRatio420),
+		image.NewUniform(color.RGBA{0x40, 0x20, 0x10, 0x80}),
+	}
+	dr := image.Rect(0, 0, 16, 16)
+	dsts := []Image{
+		image.NewRGBA(dr),
+		image.NewNRGBA(dr),
+		image.NewGray(dr),
+		image.NewRGBA64(dr),
+		image.NewPaletted(dr, palette.Plan9),
+	}
+	scalers := []struct {
+		name string
+		q    Scaler
+		pool bool
+	}{
+		{"NearestNeighbor", NearestNeighbor, false},
+		{"ApproxBiLinear", ApproxBiLinear, false},
+		{"CatmullRom.NewScaler", CatmullRom.NewScaler(dr.Dx(), dr.Dy(), sr.Dx(), sr.Dy()), true},
+		{"NewScaler2", NewScaler2(BiLinear, CatmullRom, dr.Dx(), dr.Dy(), sr.Dx(), sr.Dy()), true},
+	}
+	transformers := []struct {
+		name string
+		q    Transformer
+	}{
+		{"NearestNeighbor", NearestNeighbor},
+		{"ApproxBiLinear", ApproxBiLinear},
+		{"CatmullRom", CatmullRom},
+	}
+	m := f64.Aff3{0.5, 0.1, 1, -0.1, 0.6, 3}
+	for _, dst := range dsts {
+		for _, src := range srcs {
+			for _, op := range []Op{Over, Src} {
+				for _, s := range scalers {
+					if s.pool && raceEnabled {
+						// The race detector makes sync.Pool drop items.
+						continue
+					}
+					allocs := testing.AllocsPerRun(10, func() {
+						s.q.Scale(dst, dr, src, sr, op, nil)
+					})
+					if allocs != 0 {
+						t.Errorf("%s: Scale(%T, %T, %v): got %v allocations, want 0", s.name, dst, src, op, allocs)
+					}
+				}
+				for _, tr := range transformers {
+					allocs := testing.AllocsPerRun(10, func() {
+						tr.q.Transform(dst, m, src, sr, op, nil)
+					})
+					if allocs != 0 {
+						t.Errorf("%s: Transform(%T, %T, %v): got %v allocations, want 0", tr.name, dst, src, op, allocs)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestScalePaletted(t *testing.T) {
+	// The *image.Paletted fast paths choose the same palette colors as the
+	// generic path, which an opaque DstMask forces.
+	sr := image.Rect(0, 0, 24, 20)
+	rgba := image.NewRGBA(sr)
+	fillPix(rand.New(rand.NewSource(1)), rgba.Pix)
+	uniform := image.NewUniform(color.RGBA{0x40, 0x20, 0x10, 0x80})
+	dr := image.Rect(0, 0, 16, 16)
+	mask := &Options{DstMask: image.NewUniform(color.Opaque)}
+	for _, src := range []image.Image{rgba, uniform} {
+		for _, q := range []Interpolator{NearestNeighbor, ApproxBiLinear, CatmullRom} {
+			for _, op := range []Op{Over, Src} {
+				got := image.NewPaletted(dr, palette.Plan9)
+				want := image.NewPaletted(dr, palette.Plan9)
+				for i := range got.Pix {
+					got.Pix[i] = uint8(i)
+					want.Pix[i] = uint8(i)
+				}
+				q.Scale(got, dr, src, sr, op, nil)
+				q.Scale(want, dr, src, sr, op, mask)
+				if !bytes.Equal(got.Pix, want.Pix) {
+					t.Errorf("%T, %T, %v: fast path and generic path differ", src, q, op)
+				}
+			}
+		}
+	}
+}
+
+// TestRGBA64Precision tests that the *image.RGBA64 and *image.NRGBA64 fast
+// paths keep all 16 bits of each channel, instead of only the high 8 bits.
+func TestRGBA64Precision(t *testing.T) {
+	qs := []Interpolator{
+		NearestNeighbor,
+		ApproxBiLinear,
+		CatmullRom,
+	}
+	c := color.RGBA64{0x1234, 0x5678, 0x9abc, 0xffff}
+	for _, q := range qs {
+		for _, op := range []Op{Over, Src} {
+			srcs := []image.Image{
+				image.NewNRGBA64(image.Rect(0, 0, 4, 4)),
+				image.NewRGBA64(image.Rect(0, 0, 4, 4)),
+			}
+			for _, src := range srcs {
+				Draw(src.(Image), src.Bounds(), image.NewUniform(c), image.Point{}, Src)
+				dsts := []Image{
+					image.NewNRGBA64(image.Rect(0, 0, 7, 5)),
+					image.NewRGBA64(image.Rect(0, 0, 7, 5)),
+				}
+				for _, dst := range dsts {
+					q.Scale(dst, dst.Bounds(), src, src.Bounds(), op, nil)
+					b := dst.Bounds()
+					for y := b.Min.Y; y < b.Max.Y; y++ {
+						for x := b.Min.X; x < b.Max.X; x++ {
+							if got := color.RGBA64Model.Convert(dst.At(x, y)); got != c {
+								t.Fatalf("q=%T, op=%v, src=%T, dst=%T: at (%d, %d): got %v, want %v",
+									q, op, src, dst, x, y, got, c)
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestFtouSaturates(t *testing.T) {
+	testCases := []struct {
+		f    float64
+		want uint16
+	}{
+		{-1e20, 0},
+		{-1, 0},
+		{0, 0},
+		{0.5 / 0xffff, 1},
+		{0.5, 0x8000},
+		{1, 0xffff},
+		{2, 0xffff},
+		{1e20, 0xffff},
+		{math.Inf(+1), 0xffff},
+		{math.Inf(-1), 0},
+		{math.NaN(), 0},
+	}
+	for _, tc := range testCases {
+		if got := ftou(tc.f); got != tc.want {
+			t.Errorf("ftou(%v): got %#04x, want %#04x", tc.f, got, tc.want)
+		}
+		if got := fffftou(0xffff * tc.f); got != tc.want {
+			t.Errorf("fffftou(0xffff * %v): got %#04x, want %#04x", tc.f, got, tc.want)
+		}
+	}
+}
+
 // The fooWrapper types wrap the dst or src image to avoid triggering the
-// type-specific fast path implementations.
+// type-specific fast path implementations. An rgba64Wrapper is still an
+// RGBA64Image.
 type (
-	dstWrapper struct{ Image }
-	srcWrapper struct{ image.Image }
+	dstWrapper    struct{ Image }
+	srcWrapper    struct{ image.Image }
+	rgba64Wrapper struct{ *image.RGBA64 }
 )
 
+func srcAlpha(boundsHint image.Rectangle) (image.Image, error) {
+	m := image.NewAlpha(boundsHint)
+	fillPix(rand.New(rand.NewSource(3)), m.Pix)
+	return m, nil
+}
+
 func srcGray(boundsHint image.Rectangle) (image.Image, error) {
 	m := image.NewGray(boundsHint)
 	fillPix(rand.New(rand.NewSource(0)), m.Pix)
@@ -636,6 +947,12 @@ func srcNRGBA(boundsHint image.Rectangle) (image.Image, error) {
 	return m, nil
 }
 
+func srcNRGBA64(boundsHint image.Rectangle) (image.Image, error) {
+	m := image.NewNRGBA64(boundsHint)
+	fillPix(rand.New(rand.NewSource(5)), m.Pix)
+	return m, nil
+}
+
 func srcRGBA(boundsHint image.Rectangle) (image.Image, error) {
 	m := image.NewRGBA(boundsHint)
 	fillPix(rand.New(rand.NewSource(2)), m.Pix)
@@ -659,9 +976,25 @@ func srcYCbCr(boundsHint image.Rectangle) (image.Image, error) {
 	return m, nil
 }
 
+func srcNYCbCrA(boundsHint image.Rectangle) (image.Image, error) {
+	m := image.NewNYCbCrA(boundsHint, image.YCbCrSubsampleRatio420)
+	fillPix(rand.New(rand.NewSource(6)), m.Y, m.Cb, m.Cr, m.A)
+	return m, nil
+}
+
 func srcRGBA64(boundsHint image.Rectangle) (image.Image, error) {
 	m := image.NewRGBA64(boundsHint)
 	fillPix(rand.New(rand.NewSource(4)), m.Pix)
+	// RGBA64 is alpha-premultiplied, so the R, G and B values should
+	// be <= the A values.
+	for i := 0; i < len(m.Pix); i += 8 {
+		a := uint32(m.Pix[i+6])<<8 | uint32(m.Pix[i+7])
+		for j := i; j < i+6; j += 2 {
+			v := (uint32(m.Pix[j+0])<<8 | uint32(m.Pix[j+1])) * a / 0xffff
+			m.Pix[j+0] = uint8(v >> 8)
+			m.Pix[j+1] = uint8(v)
+		}
+	}
 	return m, nil
 }
 
@@ -737,6 +1070,26 @@ func BenchmarkScaleABUp(b *testing.B) { benchScale(b, 800, 600, Src, srcTux, App
 func BenchmarkScaleBLUp(b *testing.B) { benchScale(b, 800, 600, Src, srcTux, BiLinear) }
 func BenchmarkScaleCRUp(b *testing.B) { benchScale(b, 800, 600, Src, srcTux, CatmullRom) }
 
+// The Same benchmarks scale without changing the size, which is a copy.
+func BenchmarkScaleNNSame(b *testing.B) { benchScale(b, 1024, 768, Src, srcRGBA, NearestNeighbor) }
+func BenchmarkScaleABSame(b *testing.B) { benchScale(b, 1024, 768, Src, srcRGBA, ApproxBiLinear) }
+func BenchmarkScaleBLSame(b *testing.B) { benchScale(b, 1024, 768, Src, srcRGBA, BiLinear) }
+func BenchmarkScaleCRSame(b *testing.B) { benchScale(b, 1024, 768, Src, srcRGBA, CatmullRom) }
+
+func BenchmarkCopySame(b *testing.B) {
+	dst := image.NewRGBA(image.Rect(0, 0, 1024, 768))
+	src, err := srcRGBA(dst.Bounds())
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Copy(dst, image.Point{}, src, src.Bounds(), Src, nil)
+	}
+}
+
 func BenchmarkScaleNNSrcRGBA(b *testing.B) { benchScale(b, 200, 150, Src, srcRGBA, NearestNeighbor) }
 func BenchmarkScaleNNSrcUnif(b *testing.B) { benchScale(b, 200, 150, Src, srcUnif, NearestNeighbor) }
 
@@ -759,6 +1112,9 @@ func BenchmarkScaleABOverGray(b *testing.B)  { benchScale(b, 200, 150, Over, src
 func BenchmarkScaleABOverNRGBA(b *testing.B) { benchScale(b, 200, 150, Over, srcNRGBA, ApproxBiLinear) }
 func BenchmarkScaleABOverRGBA(b *testing.B)  { benchScale(b, 200, 150, Over, srcRGBA, ApproxBiLinear) }
 func BenchmarkScaleABOverYCbCr(b *testing.B) { benchScale(b, 200, 150, Over, srcYCbCr, ApproxBiLinear) }
+func BenchmarkScaleABOverNYCbCrA(b *testing.B) {
+	benchScale(b, 200, 150, Over, srcNYCbCrA, ApproxBiLinear)
+}
 func BenchmarkScaleABOverRGBA64(b *testing.B) {
 	benchScale(b, 200, 150, Over, srcRGBA64, ApproxBiLinear)
 }
@@ -783,11 +1139,12 @@ func BenchmarkScaleCRSrcRGBA(b *testing.B)   { benchScale(b, 200, 150, Src, srcR
 func BenchmarkScaleCRSrcYCbCr(b *testing.B)  { benchScale(b, 200, 150, Src, srcYCbCr, CatmullRom) }
 func BenchmarkScaleCRSrcRGBA64(b *testing.B) { benchScale(b, 200, 150, Src, srcRGBA64, CatmullRom) }
 
-func BenchmarkScaleCROverGray(b *testing.B)   { benchScale(b, 200, 150, Over, srcGray, CatmullRom) }
-func BenchmarkScaleCROverNRGBA(b *testing.B)  { benchScale(b, 200, 150, Over, srcNRGBA, CatmullRom) }
-func BenchmarkScaleCROverRGBA(b *testing.B)   { benchScale(b, 200, 150, Over, srcRGBA, CatmullRom) }
-func BenchmarkScaleCROverYCbCr(b *testing.B)  { benchScale(b, 200, 150, Over, srcYCbCr, CatmullRom) }
-func BenchmarkScaleCROverRGBA64(b *testing.B) { benchScale(b, 200, 150, Over, srcRGBA64, CatmullRom) }
+func BenchmarkScaleCROverGray(b *testing.B)    { benchScale(b, 200, 150, Over, srcGray, CatmullRom) }
+func BenchmarkScaleCROverNRGBA(b *testing.B)   { benchScale(b, 200, 150, Over, srcNRGBA, CatmullRom) }
+func BenchmarkScaleCROverRGBA(b *testing.B)    { benchScale(b, 200, 150, Over, srcRGBA, CatmullRom) }
+func BenchmarkScaleCROverYCbCr(b *testing.B)   { benchScale(b, 200, 150, Over, srcYCbCr, CatmullRom) }
+func BenchmarkScaleCROverNYCbCrA(b *testing.B) { benchScale(b, 200, 150, Over, srcNYCbCrA, CatmullRom) }
+func BenchmarkScaleCROverRGBA64(b *testing.B)  { benchScale(b, 200, 150, Over, srcRGBA64, CatmullRom) }
 
 func BenchmarkTformCRSrcGray(b *testing.B)   { benchTform(b, 200, 150, Src, srcGray, CatmullRom) }
 func BenchmarkTformCRSrcNRGBA(b *testing.B)  { benchTform(b, 200, 150, Src, srcNRGBA, CatmullRom) }
@@ -0,0 +1,126 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package draw
+
+import (
+	"image"
+	"math"
+)
+
+// ScaleTile is like q.Scale(dst, dr, src, sr, op, opts), but it only draws the
+// r part of dr, with exactly the pixels that q.Scale would draw there. dst
+// need only cover r, such as an image whose bounds are r, so that a scaled
+// image that is too large for one machine can be drawn a tile at a time, on
+// different machines, and the tiles joined without seams.
+//
+// For the kernel interpolators, and the Scalers that they return, the time
+// and memory that ScaleTile takes grow with the size of r and of the part of
+// sr that it needs, instead of with the size of dr and sr. Other Scalers draw
+// to dst clipped to r, which gives the same pixels as q.Scale for the nearest
+// neighbor and approximate bi-linear interpolators.
+func ScaleTile(dst Image, dr image.Rectangle, src image.Image, sr, r image.Rectangle, q Scaler, op Op, opts *Options) {
+	tr := r.Intersect(dr).Intersect(dst.Bounds())
+	if tr.Empty() || sr.Empty() {
+		return
+	}
+	var z *kernelScaler
+	switch q := q.(type) {
+	case *Kernel:
+		z = newKernelScaler(q, q, dr.Dx(), dr.Dy(), sr.Dx(), sr.Dy(), false)
+	case *kernelScaler:
+		z = q
+		if z.dw != int32(dr.Dx()) || z.dh != int32(dr.Dy()) || z.sw != int32(sr.Dx()) || z.sh != int32(sr.Dy()) {
+			z = newKernelScaler(q.kx, q.ky, dr.Dx(), dr.Dy(), sr.Dx(), sr.Dy(), false)
+		}
+	}
+	if z == nil || z.identity {
+		q.Scale(clipDst(dst, tr), dr, src, sr, op, opts)
+		return
+	}
+
+	// The tile's part of sr could take a different code path than the whole
+	// of sr would, with different rounding, so make the same choices here.
+	src, sr = untile(src, sr, opts)
+	if op == Over && (opts == nil || opts.SrcMask == nil) && opaque(src) {
+		op = Src
+	}
+	if _, ok := src.(*tile); ok || !sr.In(src.Bounds()) {
+		// Hide src's type from the type-specific fast paths.
+		src = struct{ image.Image }{src}
+	}
+	t, tsr := z.tileScaler(sr, dr, tr)
+	t.Scale(dst, tr, src, tsr, op, opts)
+}
+
+// tileScaler returns a kernelScaler that scales the returned part of sr to
+// the tr part of dr, as z would scale sr to dr. Each of its dst pixels is the
+// same weighted sum of the same src pixels. That part of sr is the columns
+// and rows that the tile has contribs from.
+func (z *kernelScaler) tileScaler(sr, dr, tr image.Rectangle) (*kernelScaler, image.Rectangle) {
+	horizontal, c0, c1 := z.horizontal.tile(tr.Min.X-dr.Min.X, tr.Max.X-dr.Min.X)
+	vertical, r0, r1 := z.vertical.tile(tr.Min.Y-dr.Min.Y, tr.Max.Y-dr.Min.Y)
+	t := &kernelScaler{
+		kx:         z.kx,
+		ky:         z.ky,
+		dw:         int32(tr.Dx()),
+		dh:         int32(tr.Dy()),
+		sw:         c1 - c0,
+		sh:         r1 - r0,
+		horizontal: horizontal,
+		vertical:   vertical,
+	}
+	return t, image.Rect(sr.Min.X+int(c0), sr.Min.Y+int(r0), sr.Min.X+int(c1), sr.Min.Y+int(r1))
+}
+
+// tile returns the distrib for d's dst columns (or rows) i0 to i1, and the
+// range lo to hi of src columns (or rows) that they have contribs from. The
+// returned distrib's contribs are relative to lo, so that they index the
+// tile's part of sr.
+func (d *distrib) tile(i0, i1 int) (t distrib, lo, hi int32) {
+	lo, hi = math.MaxInt32, 0
+	for _, s := range d.sources[i0:i1] {
+		for _, c := range d.contribs[s.i:s.j] {
+			if lo > c.coord {
+				lo = c.coord
+			}
+			if hi < c.coord+1 {
+				hi = c.coord + 1
+			}
+		}
+	}
+	if lo > hi {
+		lo, hi = 0, 0
+	}
+	t.sources = make([]source, 0, i1-i0)
+	for _, s := range d.sources[i0:i1] {
+		i := int32(len(t.contribs))
+		for _, c := range d.contribs[s.i:s.j] {
+			t.contribs = append(t.contribs, contrib{c.coord - lo, c.weight})
+		}
+		s.i, s.j = i, int32(len(t.contribs))
+		t.sources = append(t.sources, s)
+	}
+	return t, lo, hi
+}
+
+// clipDst returns dst clipped to r, which must be within dst's bounds.
+func clipDst(dst Image, r image.Rectangle) Image {
+	if s, ok := dst.(interface {
+		SubImage(image.Rectangle) image.Image
+	}); ok {
+		if d, ok := s.SubImage(r).(Image); ok {
+			return d
+		}
+	}
+	return clippedImage{dst, r}
+}
+
+// clippedImage is an Image whose bounds are r, within those of the Image.
+type clippedImage struct {
+	Image
+	r image.Rectangle
+}
+
+func (c clippedImage) Bounds() image.Rectangle { return c.r }
@@ -0,0 +1,167 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package draw
+
+import (
+	"image"
+	"image/color"
+	"math/rand"
+	"testing"
+)
+
+func TestScaleTile(t *testing.T) {
+	rgba := image.NewRGBA(image.Rect(-3, 2, 37, 27))
+	fillPix(rand.New(rand.NewSource(1)), rgba.Pix)
+	ycbcr, err := srcYCbCr(rgba.Bounds())
+	if err != nil {
+		t.Fatal(err)
+	}
+	bg := image.NewUniform(color.RGBA{0x40, 0x80, 0xc0, 0xff})
+
+	testCases := []struct {
+		desc string
+		src  image.Image
+		sr   image.Rectangle
+		dr   image.Rectangle
+	}{
+		{"RGBA down", rgba, rgba.Bounds(), image.Rect(5, -4, 28, 13)},
+		{"RGBA up", rgba, image.Rect(0, 5, 11, 14), image.Rect(5, -4, 48, 37)},
+		{"RGBA same size", rgba, rgba.Bounds(), image.Rect(0, 0, 40, 25)},
+		{"YCbCr up and down", ycbcr, ycbcr.Bounds(), image.Rect(-7, 1, 60, 18)},
+		{"sr outside src", rgba, image.Rect(-10, 0, 30, 40), image.Rect(0, 0, 31, 29)},
+		{"tiled src", Tile(rgba), image.Rect(30, 20, 75, 40), image.Rect(0, 0, 26, 33)},
+	}
+	for _, tc := range testCases {
+		qs := []struct {
+			name string
+			q    Scaler
+		}{
+			{"NearestNeighbor", NearestNeighbor},
+			{"ApproxBiLinear", ApproxBiLinear},
+			{"BiLinear", BiLinear},
+			{"CatmullRom", CatmullRom},
+			{"Kernel.NewScaler", CatmullRom.NewScaler(tc.dr.Dx(), tc.dr.Dy(), tc.sr.Dx(), tc.sr.Dy())},
+			{"NewScaler2", NewScaler2(BiLinear, CatmullRom, 1, 1, 1, 1)},
+		}
+		for _, q := range qs {
+			for _, op := range []Op{Over, Src} {
+				// The tiles overlap the edges of dr, and the dst's bounds.
+				bounds := tc.dr.Inset(-4)
+				want := image.NewRGBA(bounds)
+				Draw(want, bounds, bg, image.Point{}, Src)
+				q.q.Scale(want, tc.dr, tc.src, tc.sr, op, nil)
+
+				const tw, th = 7, 5
+				for y := bounds.Min.Y - 2; y < bounds.Max.Y; y += th {
+					for x := bounds.Min.X - 3; x < bounds.Max.X; x += tw {
+						tile := image.Rect(x, y, x+tw, y+th)
+						got := image.NewRGBA(tile.Intersect(bounds))
+						Draw(got, got.Bounds(), bg, image.Point{}, Src)
+						ScaleTile(got, tc.dr, tc.src, tc.sr, tile, q.q, op, nil)
+						if r := got.Bounds(); !equalRGBA(got, want.SubImage(r).(*image.RGBA)) {
+							t.Fatalf("%s, %s, op=%v: tile %v differs from Scale", tc.desc, q.name, op, r)
+						}
+					}
+				}
+
+				// Pixels outside the tile are left alone.
+				tile := image.Rect(tc.dr.Min.X+3, tc.dr.Min.Y+2, tc.dr.Min.X+9, tc.dr.Min.Y+6)
+				got := image.NewRGBA(bounds)
+				Draw(got, bounds, bg, image.Point{}, Src)
+				ScaleTile(got, tc.dr, tc.src, tc.sr, tile, q.q, op, nil)
+				for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+					for x := bounds.Min.X; x < bounds.Max.X; x++ {
+						w := want.RGBAAt(x, y)
+						if !image.Pt(x, y).In(tile) {
+							w = bg.C.(color.RGBA)
+						}
+						if g := got.RGBAAt(x, y); g != w {
+							t.Fatalf("%s, %s, op=%v: tile %v: at (%d, %d): got %v, want %v", tc.desc, q.name, op, tile, x, y, g, w)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestScaleTileOptions(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 30, 30))
+	fillPix(rand.New(rand.NewSource(2)), src.Pix)
+	dr := image.Rect(0, 0, 23, 41)
+	opts := &Options{
+		DstMask:  image.NewUniform(color.Alpha{0x80}),
+		SrcMask:  image.NewUniform(color.Alpha{0xc0}),
+		SrcMaskP: image.Pt(1, 2),
+	}
+	for _, q := range []Scaler{ApproxBiLinear, CatmullRom} {
+		want := image.NewRGBA(dr)
+		q.Scale(want, dr, src, src.Bounds(), Over, opts)
+		for y := 0; y < dr.Max.Y; y += 8 {
+			tile := image.Rect(5, y, 17, y+8)
+			got := image.NewRGBA(tile.Intersect(dr))
+			ScaleTile(got, dr, src, src.Bounds(), tile, q, Over, opts)
+			if !equalRGBA(got, want.SubImage(got.Bounds()).(*image.RGBA)) {
+				t.Errorf("q=%T: tile %v differs from Scale", q, tile)
+			}
+		}
+	}
+}
+
+func TestScaleTileSrcRect(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 100, 100))
+	fillPix(rand.New(rand.NewSource(3)), src.Pix)
+	sr, dr := src.Bounds(), image.Rect(0, 0, 50, 50)
+	tile := image.Rect(20, 30, 32, 42)
+	for _, q := range []*Kernel{BiLinear, CatmullRom} {
+		// The tile is about a quarter of dr in each direction, so it needs
+		// about a quarter of sr's columns and rows, plus the kernel's support.
+		z := newKernelScaler(q, q, dr.Dx(), dr.Dy(), sr.Dx(), sr.Dy(), false)
+		_, tsr := z.tileScaler(sr, dr, tile)
+		if want := image.Rect(36, 56, 68, 88); !tsr.In(want) {
+			t.Errorf("support %v: got src rect %v, want within %v", q.Support, tsr, want)
+		}
+
+		// That part of src is all that ScaleTile needs.
+		want := image.NewRGBA(dr)
+		q.Scale(want, dr, src, sr, Src, nil)
+		got := image.NewRGBA(tile)
+		ScaleTile(got, dr, src.SubImage(tsr), sr, tile, q, Src, nil)
+		if !equalRGBA(got, want.SubImage(tile).(*image.RGBA)) {
+			t.Errorf("support %v: tile differs from Scale", q.Support)
+		}
+	}
+}
+
+// equalRGBA returns whether a and b have the same bounds and pixels.
+func equalRGBA(a, b *image.RGBA) bool {
+	r := a.Bounds()
+	if r != b.Bounds() {
+		return false
+	}
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if a.RGBAAt(x, y) != b.RGBAAt(x, y) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func BenchmarkScaleTile(b *testing.B) {
+	src, err := srcLarge(image.Rectangle{})
+	if err != nil {
+		b.Fatal(err)
+	}
+	sr := src.Bounds()
+	dr := image.Rect(0, 0, 4*sr.Dx(), 4*sr.Dy())
+	dst := image.NewRGBA(image.Rect(0, 0, 256, 256))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ScaleTile(dst, dr, src, sr, dst.Bounds(), CatmullRom, Src, nil)
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package srgb converts between the gamma-encoded values of the sRGB color
+// space and linear light.
+//
+// Pixel values in image files, and in the image package's color types, are
+// almost always sRGB-encoded: equal steps in value are roughly equal steps in
+// perceived brightness, not in light intensity. Operations that mix pixel
+// values, such as scaling, blurring or compositing, are only physically
+// correct on linear light values. For example, averaging black and white
+// sRGB values gives 0x80 (a mid gray that is about 22% as bright as white)
+// instead of the 50% gray, 0xbc, that a half black, half white region looks
+// like from a distance.
+//
+// Linear light values are 16 bits wide, as 8 bits are too few for dark
+// colors: the sRGB-encoded values from 0 to 6 would all become a linear value
+// of 0. Converting an 8-bit value to linear light and back again gives the
+// original value.
+//
+// The conversions use lookup tables, so that converting a slice of values, such
+// as an image row's samples, is cheap. The table for converting from linear
+// light is 64 KiB, and is built the first time that it is needed.
+package srgb // import "golang.org/x/image/draw/srgb"
+
+import (
+	"math"
+	"sync"
+)
+
+// toLinear maps each 8-bit sRGB-encoded value to a 16-bit linear value.
+var toLinear [256]uint16
+
+// fromLinear maps each 16-bit linear value to the nearest 8-bit sRGB-encoded
+// value. It is built by fromLinearOnce.
+var (
+	fromLinear     [65536]uint8
+	fromLinearOnce sync.Once
+)
+
+func init() {
+	for i := range toLinear {
+		toLinear[i] = uint16(math.Round(0xffff * decode(float64(i)/0xff)))
+	}
+}
+
+// buildFromLinear builds the fromLinear table. The 8-bit value v is the
+// nearest one for the linear values from the decoded (v-0.5)/255 up to the
+// decoded (v+0.5)/255, so each of the 255 thresholds between successive
+// values is computed only once.
+func buildFromLinear() {
+	v := 0
+	for i := 1; i < 256; i++ {
+		threshold := int(math.Ceil(0xffff * decode((float64(i)-0.5)/0xff)))
+		for ; v < threshold; v++ {
+			fromLinear[v] = uint8(i - 1)
+		}
+	}
+	for ; v < len(fromLinear); v++ {
+		fromLinear[v] = 0xff
+	}
+}
+
+// decode converts an sRGB-encoded value in [0, 1] to linear light.
+func decode(c float64) float64 {
+	if c <= 0.04045 {
+		return c / 12.92
+	}
+	return math.Pow((c+0.055)/1.055, 2.4)
+}
+
+// ToLinear converts an 8-bit sRGB-encoded value to a 16-bit linear light
+// value.
+func ToLinear(c uint8) uint16 {
+	return toLinear[c]
+}
+
+// FromLinear converts a 16-bit linear light value to the nearest 8-bit
+// sRGB-encoded value.
+func FromLinear(l uint16) uint8 {
+	fromLinearOnce.Do(buildFromLinear)
+	return fromLinear[l]
+}
+
+// ToLinearSlice converts the 8-bit sRGB-encoded values in src to 16-bit linear
+// light values in dst. Like the built-in copy function, it converts
+// min(len(dst), len(src)) values, and returns that number.
+func ToLinearSlice(dst []uint16, src []uint8) int {
+	if len(dst) > len(src) {
+		dst = dst[:len(src)]
+	}
+	for i := range dst {
+		dst[i] = toLinear[src[i]]
+	}
+	return len(dst)
+}
+
+// FromLinearSlice converts the 16-bit linear light values in src to the
+// nearest 8-bit sRGB-encoded values in dst. Like the built-in copy function,
+// it converts min(len(dst), len(src)) values, and returns that number.
+func FromLinearSlice(dst []uint8, src []uint16) int {
+	fromLinearOnce.Do(buildFromLinear)
+	if len(dst) > len(src) {
+		dst = dst[:len(src)]
+	}
+	for i := range dst {
+		dst[i] = fromLinear[src[i]]
+	}
+	return len(dst)
+}
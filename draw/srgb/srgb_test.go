@@ -0,0 +1,84 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package srgb
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRoundTrip(t *testing.T) {
+	for i := 0; i < 256; i++ {
+		c := uint8(i)
+		if got := FromLinear(ToLinear(c)); got != c {
+			t.Errorf("FromLinear(ToLinear(%#02x)): got %#02x", c, got)
+		}
+	}
+}
+
+func TestKnownValues(t *testing.T) {
+	testCases := []struct {
+		c uint8
+		l uint16
+	}{
+		{0x00, 0x0000},
+		{0x01, 0x0014},
+		{0x80, 0x3742},
+		{0xbc, 0x80bd},
+		{0xff, 0xffff},
+	}
+	for _, tc := range testCases {
+		if got := ToLinear(tc.c); got != tc.l {
+			t.Errorf("ToLinear(%#02x): got %#04x, want %#04x", tc.c, got, tc.l)
+		}
+	}
+	// Half of white's light intensity is closest to 0xbc.
+	if got := FromLinear(0x8000); got != 0xbc {
+		t.Errorf("FromLinear(0x8000): got %#02x, want 0xbc", got)
+	}
+}
+
+func TestFromLinearNearest(t *testing.T) {
+	for i := 0; i < 65536; i++ {
+		got := FromLinear(uint16(i))
+		// The nearest value, in the sRGB-encoded space.
+		l := float64(i) / 0xffff
+		var c float64
+		if l <= 0.0031308 {
+			c = 12.92 * l
+		} else {
+			c = 1.055*math.Pow(l, 1/2.4) - 0.055
+		}
+		if want := c * 0xff; math.Abs(float64(got)-want) > 0.5+1e-6 {
+			t.Fatalf("FromLinear(%#04x): got %#02x, want %.3f", i, got, want)
+		}
+	}
+}
+
+func TestSlices(t *testing.T) {
+	src := []uint8{0x00, 0x40, 0x80, 0xc0, 0xff}
+	lin := make([]uint16, 3)
+	if n := ToLinearSlice(lin, src); n != 3 {
+		t.Fatalf("ToLinearSlice: got %d, want 3", n)
+	}
+	for i, l := range lin {
+		if want := ToLinear(src[i]); l != want {
+			t.Errorf("ToLinearSlice: element %d: got %#04x, want %#04x", i, l, want)
+		}
+	}
+	dst := make([]uint8, 5)
+	if n := FromLinearSlice(dst, lin); n != 3 {
+		t.Fatalf("FromLinearSlice: got %d, want 3", n)
+	}
+	for i, c := range dst {
+		want := uint8(0)
+		if i < 3 {
+			want = src[i]
+		}
+		if c != want {
+			t.Errorf("FromLinearSlice: element %d: got %#02x, want %#02x", i, c, want)
+		}
+	}
+}
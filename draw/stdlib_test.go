@@ -31,11 +31,15 @@ func TestFastPaths(t *testing.T) {
 		image.Rect(5, 5, 5, 5),     // Empty.
 	}
 	srcfs := []func(image.Rectangle) (image.Image, error){
+		srcAlpha,
 		srcGray,
 		srcNRGBA,
+		srcNRGBA64,
 		srcRGBA,
+		srcRGBA64,
 		srcUnif,
 		srcYCbCr,
+		srcNYCbCrA,
 	}
 	var srcs []image.Image
 	for _, srcf := range srcfs {
@@ -54,31 +58,41 @@ func TestFastPaths(t *testing.T) {
 		Over,
 		Src,
 	}
+	// newDsts return a new dst image and its pixels.
+	newDsts := []func(image.Rectangle) (Image, []byte){
+		func(r image.Rectangle) (Image, []byte) { m := image.NewAlpha(r); return m, m.Pix },
+		func(r image.Rectangle) (Image, []byte) { m := image.NewGray(r); return m, m.Pix },
+		func(r image.Rectangle) (Image, []byte) { m := image.NewNRGBA64(r); return m, m.Pix },
+		func(r image.Rectangle) (Image, []byte) { m := image.NewRGBA(r); return m, m.Pix },
+		func(r image.Rectangle) (Image, []byte) { m := image.NewRGBA64(r); return m, m.Pix },
+	}
 	blue := image.NewUniform(color.RGBA{0x11, 0x22, 0x44, 0x7f})
 
-	for _, dr := range drs {
-		for _, src := range srcs {
-			for _, sr := range srs {
-				for _, transform := range []bool{false, true} {
-					for _, q := range qs {
-						for _, op := range ops {
-							dst0 := image.NewRGBA(drs[0])
-							dst1 := image.NewRGBA(drs[0])
-							Draw(dst0, dst0.Bounds(), blue, image.Point{}, Src)
-							Draw(dstWrapper{dst1}, dst1.Bounds(), srcWrapper{blue}, image.Point{}, Src)
+	for _, newDst := range newDsts {
+		for _, dr := range drs {
+			for _, src := range srcs {
+				for _, sr := range srs {
+					for _, transform := range []bool{false, true} {
+						for _, q := range qs {
+							for _, op := range ops {
+								dst0, pix0 := newDst(drs[0])
+								dst1, pix1 := newDst(drs[0])
+								Draw(dst0, dst0.Bounds(), blue, image.Point{}, Src)
+								Draw(dstWrapper{dst1}, dst1.Bounds(), srcWrapper{blue}, image.Point{}, Src)
 
-							if transform {
-								m := transformMatrix(3.75, 2, 1)
-								q.Transform(dst0, m, src, sr, op, nil)
-								q.Transform(dstWrapper{dst1}, m, srcWrapper{src}, sr, op, nil)
-							} else {
-								q.Scale(dst0, dr, src, sr, op, nil)
-								q.Scale(dstWrapper{dst1}, dr, srcWrapper{src}, sr, op, nil)
-							}
+								if transform {
+									m := transformMatrix(3.75, 2, 1)
+									q.Transform(dst0, m, src, sr, op, nil)
+									q.Transform(dstWrapper{dst1}, m, srcWrapper{src}, sr, op, nil)
+								} else {
+									q.Scale(dst0, dr, src, sr, op, nil)
+									q.Scale(dstWrapper{dst1}, dr, srcWrapper{src}, sr, op, nil)
+								}
 
-							if !bytes.Equal(dst0.Pix, dst1.Pix) {
-								t.Errorf("pix differ for dr=%v, src=%T, sr=%v, transform=%t, q=%T",
-									dr, src, sr, transform, q)
+								if !bytes.Equal(pix0, pix1) {
+									t.Errorf("pix differ for dst=%T, dr=%v, src=%T, sr=%v, transform=%t, q=%T, op=%v",
+										dst0, dr, src, sr, transform, q, op)
+								}
 							}
 						}
 					}
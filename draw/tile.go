@@ -0,0 +1,168 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package draw
+
+import (
+	"image"
+	"image/color"
+)
+
+// Tile returns an image that repeats src infinitely in every direction, such
+// as for a wallpaper-style pattern fill. The pixel at any point p is the src
+// pixel at the point in src.Bounds() that is congruent to p, modulo the width
+// and height of src.Bounds().
+//
+// Like an *image.Uniform, the returned image has very large bounds. Copy and
+// Scale recognize it, drawing each repetition of src with the fast paths for
+// src's type instead of calling the returned image's At method for each
+// pixel.
+//
+// If src's bounds are empty, the returned image is also empty.
+func Tile(src image.Image) image.Image {
+	return &tile{src: src, r: src.Bounds()}
+}
+
+type tile struct {
+	src image.Image
+	// r is src's bounds, the period of the repetition.
+	r image.Rectangle
+}
+
+// tileBounds matches the bounds of an *image.Uniform.
+var tileBounds = image.Rectangle{image.Point{-1e9, -1e9}, image.Point{1e9, 1e9}}
+
+func (t *tile) ColorModel() color.Model { return t.src.ColorModel() }
+
+func (t *tile) Bounds() image.Rectangle {
+	if t.r.Empty() {
+		return image.Rectangle{}
+	}
+	return tileBounds
+}
+
+func (t *tile) At(x, y int) color.Color {
+	if t.r.Empty() {
+		return color.RGBA64{}
+	}
+	p := t.wrap(image.Point{x, y})
+	return t.src.At(p.X, p.Y)
+}
+
+func (t *tile) RGBA64At(x, y int) color.RGBA64 {
+	if t.r.Empty() {
+		return color.RGBA64{}
+	}
+	p := t.wrap(image.Point{x, y})
+	if src, ok := t.src.(image.RGBA64Image); ok {
+		return src.RGBA64At(p.X, p.Y)
+	}
+	r, g, b, a := t.src.At(p.X, p.Y).RGBA()
+	return color.RGBA64{uint16(r), uint16(g), uint16(b), uint16(a)}
+}
+
+// Opaque scans the entire image and reports whether it is fully opaque.
+func (t *tile) Opaque() bool {
+	return !t.r.Empty() && opaque(t.src)
+}
+
+// wrap returns the point in t.r that is congruent to p. t.r must not be
+// empty.
+func (t *tile) wrap(p image.Point) image.Point {
+	x := (p.X - t.r.Min.X) % t.r.Dx()
+	if x < 0 {
+		x += t.r.Dx()
+	}
+	y := (p.Y - t.r.Min.Y) % t.r.Dy()
+	if y < 0 {
+		y += t.r.Dy()
+	}
+	return image.Point{t.r.Min.X + x, t.r.Min.Y + y}
+}
+
+// untile returns the src image and sr rectangle to scale instead of the given
+// ones. If src is a Tile image and sr lies within a single repetition, they
+// are the underlying image and the congruent rectangle, so that the
+// type-specific fast paths apply. Otherwise, they are unchanged.
+func untile(src image.Image, sr image.Rectangle, opts *Options) (image.Image, image.Rectangle) {
+	t, ok := src.(*tile)
+	if !ok || t.r.Empty() || sr.Empty() || (opts != nil && opts.SrcMask != nil) {
+		return src, sr
+	}
+	sr1 := sr.Add(t.wrap(sr.Min).Sub(sr.Min))
+	if !sr1.In(t.r) {
+		return src, sr
+	}
+	return t.src, sr1
+}
+
+// draw draws the part of t defined by sp and the translation of r so that
+// r.Min translates to sp, onto the r part of dst. Each repetition of t.src is
+// drawn separately, so that drawMask's type-specific fast paths apply.
+func (t *tile) draw(dst Image, r image.Rectangle, sp image.Point, op Op) {
+	if t.r.Empty() {
+		DrawMask(dst, r, t, sp, nil, image.Point{}, op)
+		return
+	}
+
+	// Clip r to dst, before iterating over what could be a very large area.
+	if clipped := r.Intersect(dst.Bounds()); clipped != r {
+		sp = sp.Add(clipped.Min.Sub(r.Min))
+		r = clipped
+	}
+	if r.Empty() {
+		return
+	}
+	sp = t.wrap(sp)
+
+	// For an *image.RGBA dst, draw only the first repetition of the
+	// pattern, then copy those bytes to fill the rest of r.
+	if dst0, ok := dst.(*image.RGBA); ok && (op == Src || t.Opaque()) {
+		w, h := t.r.Dx(), t.r.Dy()
+		first := r
+		if first.Dx() > w {
+			first.Max.X = first.Min.X + w
+		}
+		if first.Dy() > h {
+			first.Max.Y = first.Min.Y + h
+		}
+		t.drawRepetitions(dst, first, sp, Src)
+
+		i0 := dst0.PixOffset(r.Min.X, r.Min.Y)
+		n := 4 * r.Dx()
+		for y := r.Min.Y; y < first.Max.Y; y++ {
+			row := dst0.Pix[i0 : i0+n]
+			for filled := 4 * first.Dx(); filled < n; {
+				filled += copy(row[filled:], row[:filled])
+			}
+			i0 += dst0.Stride
+		}
+		for y := first.Max.Y; y < r.Max.Y; y++ {
+			copy(dst0.Pix[i0:i0+n], dst0.Pix[i0-h*dst0.Stride:])
+			i0 += dst0.Stride
+		}
+		return
+	}
+
+	t.drawRepetitions(dst, r, sp, op)
+}
+
+// drawRepetitions is like draw, but calls drawMask once for each repetition
+// of t.src that r overlaps. r must be within dst's bounds, and sp must be in
+// t.r.
+func (t *tile) drawRepetitions(dst Image, r image.Rectangle, sp image.Point, op Op) {
+	for y, sy := r.Min.Y, sp.Y; y < r.Max.Y; y, sy = y+(t.r.Max.Y-sy), t.r.Min.Y {
+		y1 := y + (t.r.Max.Y - sy)
+		if y1 > r.Max.Y {
+			y1 = r.Max.Y
+		}
+		for x, sx := r.Min.X, sp.X; x < r.Max.X; x, sx = x+(t.r.Max.X-sx), t.r.Min.X {
+			x1 := x + (t.r.Max.X - sx)
+			if x1 > r.Max.X {
+				x1 = r.Max.X
+			}
+			drawMask(dst, image.Rect(x, y, x1, y1), t.src, image.Point{sx, sy}, nil, image.Point{}, op)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package draw
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"math/rand"
+	"testing"
+)
+
+// slowImage hides the concrete type, and any RGBA64At method, of an image so
+// that drawing from it takes the generic At path.
+type slowImage struct {
+	image.Image
+}
+
+func tileSources(t *testing.T, r image.Rectangle) []image.Image {
+	rgba, err := srcRGBA(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nrgba, err := srcNRGBA(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gray, err := srcGray(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ycbcr, err := srcYCbCr(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	paletted := image.NewPaletted(r, palette.Plan9)
+	fillPix(rand.New(rand.NewSource(7)), paletted.Pix)
+	return []image.Image{rgba, nrgba, gray, ycbcr, paletted}
+}
+
+func TestTile(t *testing.T) {
+	src := image.NewGray(image.Rect(-1, 2, 2, 4))
+	for i := range src.Pix {
+		src.Pix[i] = uint8(i)
+	}
+	m := Tile(src)
+	if got := m.Bounds(); !got.In(image.Rect(-1e9, -1e9, 1e9, 1e9)) || got.Dx() < 1e9 {
+		t.Errorf("Bounds: got %v, want very large bounds", got)
+	}
+	testCases := []struct {
+		x, y int
+		want uint8
+	}{
+		{-1, 2, 0},
+		{1, 3, 5},
+		{2, 2, 0},
+		{-2, 2, 2},
+		{-4, 1, 3},
+		{6, 11, 4},
+	}
+	for _, tc := range testCases {
+		if got := m.At(tc.x, tc.y); got != (color.Gray{tc.want}) {
+			t.Errorf("At(%d, %d): got %v, want %v", tc.x, tc.y, got, color.Gray{tc.want})
+		}
+		want := color.RGBA64Model.Convert(color.Gray{tc.want})
+		if got := m.(image.RGBA64Image).RGBA64At(tc.x, tc.y); got != want {
+			t.Errorf("RGBA64At(%d, %d): got %v, want %v", tc.x, tc.y, got, want)
+		}
+	}
+
+	empty := Tile(image.NewGray(image.Rect(3, 3, 3, 8)))
+	if got := empty.Bounds(); !got.Empty() {
+		t.Errorf("empty Bounds: got %v, want empty", got)
+	}
+	if _, _, _, a := empty.At(0, 0).RGBA(); a != 0 {
+		t.Errorf("empty At: got alpha %#x, want 0", a)
+	}
+}
+
+func TestTileCopy(t *testing.T) {
+	srcs := tileSources(t, image.Rect(-3, 2, 4, 7))
+	testCases := []struct {
+		dp image.Point
+		sr image.Rectangle
+	}{
+		{image.Pt(0, 0), image.Rect(0, 0, 40, 30)},
+		{image.Pt(3, 2), image.Rect(-13, 5, 9, 21)},
+		{image.Pt(-6, -4), image.Rect(1, 2, 50, 40)}, // Partially out-of-bounds.
+		{image.Pt(5, 7), image.Rect(-2, 3, 3, 6)},    // Within a single repetition.
+		{image.Pt(5, 7), image.Rect(100, 100, 101, 140)},
+	}
+	dsts := []func() Image{
+		func() Image { return image.NewRGBA(image.Rect(-2, -1, 33, 27)) },
+		func() Image { return image.NewNRGBA(image.Rect(-2, -1, 33, 27)) },
+	}
+	for _, op := range []Op{Over, Src} {
+		for _, src := range srcs {
+			for _, newDst := range dsts {
+				for _, tc := range testCases {
+					got, want := newDst(), newDst()
+					fillPix(rand.New(rand.NewSource(0)), pix(got), pix(want))
+					copy(pix(want), pix(got))
+
+					Copy(got, tc.dp, Tile(src), tc.sr, op, nil)
+					DrawMask(want, tc.sr.Add(tc.dp.Sub(tc.sr.Min)), slowImage{Tile(src)}, tc.sr.Min, nil, image.Point{}, op)
+
+					if !bytes.Equal(pix(got), pix(want)) {
+						t.Errorf("op=%v, src=%T, dst=%T, dp=%v, sr=%v: pixels differ", op, src, got, tc.dp, tc.sr)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestTileScale(t *testing.T) {
+	srcs := tileSources(t, image.Rect(-3, 2, 14, 13))
+	testCases := []struct {
+		dr, sr image.Rectangle
+	}{
+		{image.Rect(0, 0, 30, 25), image.Rect(-2, 3, 12, 11)},    // Within a single repetition.
+		{image.Rect(0, 0, 30, 25), image.Rect(20, -30, 27, -26)}, // Within a single repetition.
+		{image.Rect(2, 1, 19, 23), image.Rect(-10, 0, 30, 40)},   // Multiple repetitions.
+	}
+	for _, q := range []Interpolator{NearestNeighbor, ApproxBiLinear, CatmullRom} {
+		for _, op := range []Op{Over, Src} {
+			for _, src := range srcs {
+				for _, tc := range testCases {
+					got := image.NewRGBA(image.Rect(0, 0, 32, 32))
+					fillPix(rand.New(rand.NewSource(0)), got.Pix)
+					want := image.NewRGBA(got.Rect)
+					copy(want.Pix, got.Pix)
+
+					q.Scale(got, tc.dr, Tile(src), tc.sr, op, nil)
+					// The reference is the underlying image, translated,
+					// for a single repetition, and the slow path otherwise.
+					wsrc, wsr := untile(Tile(src), tc.sr, nil)
+					if wsrc != src {
+						wsrc = slowImage{wsrc}
+					}
+					q.Scale(want, tc.dr, wsrc, wsr, op, nil)
+
+					if !bytes.Equal(got.Pix, want.Pix) {
+						t.Errorf("q=%T, op=%v, src=%T, dr=%v, sr=%v: pixels differ", q, op, src, tc.dr, tc.sr)
+					}
+				}
+			}
+		}
+	}
+}
+
+func pix(m Image) []byte {
+	switch m := m.(type) {
+	case *image.RGBA:
+		return m.Pix
+	case *image.NRGBA:
+		return m.Pix
+	}
+	panic("unsupported image type")
+}
+
+func benchTileCopy(b *testing.B, tileSize int, op Op) {
+	dst := image.NewRGBA(image.Rect(0, 0, 800, 600))
+	src, err := srcRGBA(image.Rect(0, 0, tileSize, tileSize))
+	if err != nil {
+		b.Fatal(err)
+	}
+	m := Tile(src)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Copy(dst, image.Point{}, m, dst.Bounds(), op, nil)
+	}
+}
+
+func BenchmarkTileCopySrc4(b *testing.B)   { benchTileCopy(b, 4, Src) }
+func BenchmarkTileCopySrc64(b *testing.B)  { benchTileCopy(b, 64, Src) }
+func BenchmarkTileCopyOver64(b *testing.B) { benchTileCopy(b, 64, Over) }
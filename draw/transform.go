@@ -0,0 +1,44 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package draw
+
+import (
+	"image"
+	"math"
+
+	"golang.org/x/image/math/f64"
+)
+
+// TransformBounds returns the smallest rectangle that contains sr transformed
+// by s2d. A Transformer only writes to the dst pixels in that rectangle when
+// transforming the part of a src image defined by sr, so it is the bounds of
+// the smallest dst image that holds all of the transformed src.
+//
+// For example, scaling a 10×10 sr by 2 and translating it by (3, 4) gives
+// the rectangle with Min (3, 4) and Max (23, 24).
+func TransformBounds(s2d f64.Aff3, sr image.Rectangle) image.Rectangle {
+	if sr.Empty() {
+		return image.Rectangle{}
+	}
+	minX, minY := math.Inf(+1), math.Inf(+1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, p := range [...]image.Point{sr.Min, {sr.Max.X, sr.Min.Y}, {sr.Min.X, sr.Max.Y}, sr.Max} {
+		x := float64(s2d[0]*float64(p.X)) + float64(s2d[1]*float64(p.Y)) + s2d[2]
+		y := float64(s2d[3]*float64(p.X)) + float64(s2d[4]*float64(p.Y)) + s2d[5]
+		minX, maxX = math.Min(minX, x), math.Max(maxX, x)
+		minY, maxY = math.Min(minY, y), math.Max(maxY, y)
+	}
+	return image.Rect(int(math.Floor(minX)), int(math.Floor(minY)), int(math.Ceil(maxX)), int(math.Ceil(maxY)))
+}
+
+// TransformRGBA returns a new *image.RGBA, whose bounds are those returned by
+// TransformBounds, that holds the part of the source image defined by src and
+// sr transformed by s2d, using the Transformer q. The dst pixels that the
+// transformed sr does not cover are transparent.
+func TransformRGBA(q Transformer, s2d f64.Aff3, src image.Image, sr image.Rectangle, opts *Options) *image.RGBA {
+	dst := image.NewRGBA(TransformBounds(s2d, sr))
+	q.Transform(dst, s2d, src, sr, Src, opts)
+	return dst
+}
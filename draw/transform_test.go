@@ -0,0 +1,106 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package draw
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"math"
+	"math/rand"
+	"testing"
+
+	"golang.org/x/image/math/f64"
+)
+
+func TestTransformBounds(t *testing.T) {
+	sr := image.Rect(2, 1, 12, 11)
+	rot := func(theta float64) f64.Aff3 {
+		c, s := math.Cos(theta), math.Sin(theta)
+		return f64.Aff3{c, -s, 40, s, c, 30}
+	}
+	testCases := []struct {
+		s2d  f64.Aff3
+		want image.Rectangle
+	}{
+		{f64.Aff3{1, 0, 0, 0, 1, 0}, image.Rect(2, 1, 12, 11)},
+		{f64.Aff3{1, 0, 3, 0, 1, -4}, image.Rect(5, -3, 15, 7)},
+		{f64.Aff3{2, 0, 3, 0, 2, 4}, image.Rect(7, 6, 27, 26)},
+		{f64.Aff3{0.5, 0, 0, 0, 0.5, 0}, image.Rect(1, 0, 6, 6)},
+		{f64.Aff3{-1, 0, 0, 0, 1, 0}, image.Rect(-12, 1, -2, 11)},
+		{f64.Aff3{0, 1, 0, 1, 0, 0}, image.Rect(1, 2, 11, 12)},
+		{rot(math.Pi / 4), image.Rect(33, 32, 48, 47)},
+		{rot(math.Pi / 6), image.Rect(36, 31, 50, 46)},
+	}
+	src := image.NewUniform(color.Opaque)
+	for _, tc := range testCases {
+		got := TransformBounds(tc.s2d, sr)
+		if got != tc.want {
+			t.Errorf("s2d=%v: got %v, want %v", tc.s2d, got, tc.want)
+			continue
+		}
+		// Check that the Transformers only write inside the bounds.
+		for _, q := range []Interpolator{NearestNeighbor, ApproxBiLinear, CatmullRom} {
+			dst := image.NewAlpha(image.Rect(-50, -50, 100, 100))
+			q.Transform(dst, tc.s2d, src, sr, Src, nil)
+			for y := dst.Rect.Min.Y; y < dst.Rect.Max.Y; y++ {
+				for x := dst.Rect.Min.X; x < dst.Rect.Max.X; x++ {
+					if dst.AlphaAt(x, y).A != 0 && !(image.Point{x, y}).In(got) {
+						t.Errorf("s2d=%v, %T: wrote (%d, %d), outside %v", tc.s2d, q, x, y, got)
+					}
+				}
+			}
+		}
+	}
+
+	if got := TransformBounds(f64.Aff3{2, 0, 3, 0, 2, 4}, image.Rectangle{}); got != (image.Rectangle{}) {
+		t.Errorf("empty sr: got %v, want the empty rectangle", got)
+	}
+}
+
+func TestTransformRGBA(t *testing.T) {
+	src := image.NewRGBA(image.Rect(3, 5, 23, 19))
+	fillPix(rand.New(rand.NewSource(1)), src.Pix)
+	s2d := f64.Aff3{0.8, -0.6, 7, 0.6, 0.8, -2}
+	for _, q := range []Interpolator{NearestNeighbor, ApproxBiLinear, CatmullRom} {
+		got := TransformRGBA(q, s2d, src, src.Bounds(), nil)
+		if want := TransformBounds(s2d, src.Bounds()); got.Rect != want {
+			t.Errorf("%T: got bounds %v, want %v", q, got.Rect, want)
+			continue
+		}
+		want := image.NewRGBA(got.Rect)
+		q.Transform(want, s2d, src, src.Bounds(), Src, nil)
+		if !bytes.Equal(got.Pix, want.Pix) {
+			t.Errorf("%T: TransformRGBA and Transform differ", q)
+		}
+	}
+}
+
+func TestTransformTranslateCopy(t *testing.T) {
+	// An integer translation is simplified to a Copy, which must translate
+	// sr.Min by the same amount as a Transform would.
+	src := image.NewRGBA(image.Rect(0, 0, 20, 20))
+	fillPix(rand.New(rand.NewSource(1)), src.Pix)
+	for i := 3; i < len(src.Pix); i += 4 {
+		src.Pix[i] = 0xff
+	}
+	sr := image.Rect(2, 6, 12, 16)
+	s2d := f64.Aff3{1, 0, 5, 0, 1, 3}
+	for _, q := range []Interpolator{NearestNeighbor, ApproxBiLinear, CatmullRom} {
+		dst := image.NewRGBA(image.Rect(0, 0, 30, 30))
+		q.Transform(dst, s2d, src, sr, Src, nil)
+		for y := 0; y < 30; y++ {
+			for x := 0; x < 30; x++ {
+				want := color.RGBA{}
+				if p := image.Pt(x-5, y-3); p.In(sr) {
+					want = src.RGBAAt(p.X, p.Y)
+				}
+				if got := dst.RGBAAt(x, y); got != want {
+					t.Fatalf("%T: (%d, %d): got %v, want %v", q, x, y, got, want)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package draw
+
+import (
+	"image"
+
+	"golang.org/x/image/math/f64"
+)
+
+// ScaleYCbCr scales the part of the source image defined by src and sr and
+// writes the result to the part of the destination image defined by dst and
+// dr, using the interpolator q.
+//
+// Unlike q.Scale, which needs a dst image with a Set method, it scales the Y,
+// Cb and Cr planes separately, without converting to and from RGBA. The src
+// and dst images may have different subsample ratios. Each chroma sample is
+// taken to be at the center of the luma samples that share it, and is
+// resampled at that position in the src image, so that the chroma planes stay
+// aligned with the luma plane.
+//
+// Chroma samples that are only partly inside dr are also written.
+func ScaleYCbCr(dst *image.YCbCr, dr image.Rectangle, src *image.YCbCr, sr image.Rectangle, q Interpolator) {
+	if dr.Empty() || sr.Empty() {
+		return
+	}
+	q.Scale(yPlane(dst), dr, yPlane(src), sr, Src, nil)
+
+	dw, dh := subsample(dst.SubsampleRatio)
+	sw, sh := subsample(src.SubsampleRatio)
+	if aligned(dr, dw, dh) && aligned(sr, sw, sh) {
+		// The chroma rectangles are exactly dr and sr in chroma plane
+		// coordinates, so the chroma planes can be scaled like the luma plane.
+		pdr, psr := planeRect(dr, dw, dh), planeRect(sr, sw, sh)
+		dcb, dcr := cPlanes(dst)
+		scb, scr := cPlanes(src)
+		q.Scale(dcb, pdr, scb, psr, Src, nil)
+		q.Scale(dcr, pdr, scr, psr, Src, nil)
+		return
+	}
+
+	// s2d maps src luma coordinates to dst luma coordinates.
+	xs := float64(dr.Dx()) / float64(sr.Dx())
+	ys := float64(dr.Dy()) / float64(sr.Dy())
+	s2d := f64.Aff3{
+		xs, 0, float64(dr.Min.X) - float64(sr.Min.X)*xs,
+		0, ys, float64(dr.Min.Y) - float64(sr.Min.Y)*ys,
+	}
+	transformChroma(dst, dr, s2d, src, sr, q)
+}
+
+// TransformYCbCr is like ScaleYCbCr, but transforms the part of the source
+// image defined by src and sr by the affine transform m, as a Transformer
+// does.
+//
+// The luma plane is transformed by q.Transform. As for ScaleYCbCr, the chroma
+// samples are resampled at their centers, and those that are only partly
+// inside the transformed sr are also written.
+func TransformYCbCr(dst *image.YCbCr, m f64.Aff3, src *image.YCbCr, sr image.Rectangle, q Interpolator) {
+	if sr.Empty() {
+		return
+	}
+	q.Transform(yPlane(dst), m, yPlane(src), sr, Src, nil)
+	transformChroma(dst, TransformBounds(m, sr), m, src, sr, q)
+}
+
+// transformChroma writes the chroma samples of dst that overlap dr, by
+// transforming the chroma samples of src that overlap sr by s2d, which maps
+// src luma coordinates to dst luma coordinates.
+//
+// A chroma sample at the edge of dr can have its center outside dr, and so
+// map to outside sr, where a Transformer would not write it. To still write
+// it, the src chroma samples are copied with a one sample border that repeats
+// the edge samples.
+func transformChroma(dst *image.YCbCr, dr image.Rectangle, s2d f64.Aff3, src *image.YCbCr, sr image.Rectangle, q Interpolator) {
+	dw, dh := subsample(dst.SubsampleRatio)
+	sw, sh := subsample(src.SubsampleRatio)
+	dcb, dcr := cPlanes(dst)
+	scb, scr := cPlanes(src)
+	pdr := planeRect(dr, dw, dh).Intersect(dcb.Rect)
+	psr := planeRect(sr, sw, sh).Intersect(scb.Rect)
+	if pdr.Empty() || psr.Empty() {
+		return
+	}
+
+	// In chroma plane coordinates, a luma coordinate x is x/w, so the src
+	// plane is scaled up by sw and sh, and the dst plane scaled down by dw and
+	// dh.
+	fdw, fdh, fsw, fsh := float64(dw), float64(dh), float64(sw), float64(sh)
+	m := f64.Aff3{
+		s2d[0] * fsw / fdw, s2d[1] * fsh / fdw, s2d[2] / fdw,
+		s2d[3] * fsw / fdh, s2d[4] * fsh / fdh, s2d[5] / fdh,
+	}
+	q.Transform(dcb.SubImage(pdr).(*image.Gray), m, padPlane(scb, psr), psr.Inset(-1), Src, nil)
+	q.Transform(dcr.SubImage(pdr).(*image.Gray), m, padPlane(scr, psr), psr.Inset(-1), Src, nil)
+}
+
+// padPlane returns a copy of the r part of p, with a one pixel border that
+// repeats the pixels at the edges of r.
+func padPlane(p *image.Gray, r image.Rectangle) *image.Gray {
+	c := image.NewGray(r.Inset(-1))
+	w := r.Dx()
+	for y := c.Rect.Min.Y; y < c.Rect.Max.Y; y++ {
+		sy := y
+		if sy < r.Min.Y {
+			sy = r.Min.Y
+		} else if sy >= r.Max.Y {
+			sy = r.Max.Y - 1
+		}
+		s := p.Pix[p.PixOffset(r.Min.X, sy):]
+		d := c.Pix[c.PixOffset(c.Rect.Min.X, y):]
+		d[0] = s[0]
+		copy(d[1:1+w], s[:w])
+		d[1+w] = s[w-1]
+	}
+	return c
+}
+
+// subsample returns the number of luma columns and rows per chroma sample.
+func subsample(ratio image.YCbCrSubsampleRatio) (w, h int) {
+	switch ratio {
+	case image.YCbCrSubsampleRatio422:
+		return 2, 1
+	case image.YCbCrSubsampleRatio420:
+		return 2, 2
+	case image.YCbCrSubsampleRatio440:
+		return 1, 2
+	case image.YCbCrSubsampleRatio411:
+		return 4, 1
+	case image.YCbCrSubsampleRatio410:
+		return 4, 2
+	}
+	return 1, 1
+}
+
+// planeRect returns the chroma samples that overlap the luma rectangle r,
+// rounding as the image package's YCbCr type does.
+func planeRect(r image.Rectangle, w, h int) image.Rectangle {
+	return image.Rect(r.Min.X/w, r.Min.Y/h, (r.Max.X+w-1)/w, (r.Max.Y+h-1)/h)
+}
+
+// aligned reports whether r's edges are on chroma sample boundaries.
+func aligned(r image.Rectangle, w, h int) bool {
+	p := planeRect(r, w, h)
+	return p.Min.X*w == r.Min.X && p.Min.Y*h == r.Min.Y && p.Max.X*w == r.Max.X && p.Max.Y*h == r.Max.Y
+}
+
+// yPlane returns m's luma plane as a gray image.
+func yPlane(m *image.YCbCr) *image.Gray {
+	return &image.Gray{Pix: m.Y, Stride: m.YStride, Rect: m.Rect}
+}
+
+// cPlanes returns m's chroma planes as gray images, in chroma plane
+// coordinates.
+func cPlanes(m *image.YCbCr) (cb, cr *image.Gray) {
+	w, h := subsample(m.SubsampleRatio)
+	r := planeRect(m.Rect, w, h)
+	return &image.Gray{Pix: m.Cb, Stride: m.CStride, Rect: r},
+		&image.Gray{Pix: m.Cr, Stride: m.CStride, Rect: r}
+}
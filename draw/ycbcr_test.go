@@ -0,0 +1,150 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package draw
+
+import (
+	"fmt"
+	"image"
+	"testing"
+
+	"golang.org/x/image/math/f64"
+)
+
+var ycbcrRatios = []image.YCbCrSubsampleRatio{
+	image.YCbCrSubsampleRatio444,
+	image.YCbCrSubsampleRatio422,
+	image.YCbCrSubsampleRatio420,
+	image.YCbCrSubsampleRatio440,
+	image.YCbCrSubsampleRatio411,
+	image.YCbCrSubsampleRatio410,
+}
+
+func TestScaleYCbCr(t *testing.T) {
+	// The src samples are linear functions of the position of their centers.
+	// The bi-linearly scaled dst chroma samples, away from the edges, are the
+	// same functions of the position that their centers map to.
+	src := image.NewYCbCr(image.Rect(0, 0, 40, 30), image.YCbCrSubsampleRatio444)
+	for y := 0; y < 30; y++ {
+		for x := 0; x < 40; x++ {
+			i := src.COffset(x, y)
+			src.Cb[i] = uint8(2*x + y)
+			src.Cr[i] = uint8(100 + x)
+		}
+	}
+	for _, ratio := range ycbcrRatios {
+		for _, dr := range []image.Rectangle{
+			image.Rect(0, 0, 80, 60),
+			image.Rect(3, 5, 83, 65),
+		} {
+			name := fmt.Sprintf("ratio %v, dr %v", ratio, dr)
+			dst := image.NewYCbCr(image.Rect(0, 0, 90, 70), ratio)
+			ScaleYCbCr(dst, dr, src, src.Rect, BiLinear)
+
+			w, h := subsample(ratio)
+			cb, cr := cPlanes(dst)
+			for y := cb.Rect.Min.Y; y < cb.Rect.Max.Y; y++ {
+				for x := cb.Rect.Min.X; x < cb.Rect.Max.X; x++ {
+					cx := (float64(x)+0.5)*float64(w) - float64(dr.Min.X)
+					cy := (float64(y)+0.5)*float64(h) - float64(dr.Min.Y)
+					if cx < 4 || cy < 4 || cx > float64(dr.Dx())-4 || cy > float64(dr.Dy())-4 {
+						continue
+					}
+					// Scaling by 2 maps (cx, cy) to the src sample at
+					// (cx/2 - 0.5, cy/2 - 0.5).
+					wantCb := 2*(cx/2-0.5) + (cy/2 - 0.5)
+					wantCr := 100 + (cx/2 - 0.5)
+					gotCb := float64(cb.Pix[cb.PixOffset(x, y)])
+					gotCr := float64(cr.Pix[cr.PixOffset(x, y)])
+					if d := gotCb - wantCb; d < -1 || d > 1 {
+						t.Errorf("%s: Cb at (%d, %d): got %v, want %v", name, x, y, gotCb, wantCb)
+					}
+					if d := gotCr - wantCr; d < -1 || d > 1 {
+						t.Errorf("%s: Cr at (%d, %d): got %v, want %v", name, x, y, gotCr, wantCr)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestScaleYCbCrCoverage(t *testing.T) {
+	// Every sample that overlaps dr is written, and no other sample.
+	for _, sratio := range ycbcrRatios {
+		for _, dratio := range ycbcrRatios {
+			src := image.NewYCbCr(image.Rect(1, 2, 36, 27), sratio)
+			for i := range src.Y {
+				src.Y[i] = 0x40
+			}
+			for i := range src.Cb {
+				src.Cb[i], src.Cr[i] = 0x50, 0x60
+			}
+			for _, q := range []Interpolator{NearestNeighbor, ApproxBiLinear, BiLinear, CatmullRom} {
+				for _, dr := range []image.Rectangle{
+					image.Rect(0, 0, 64, 48),
+					image.Rect(5, 3, 22, 18),
+				} {
+					name := fmt.Sprintf("ratios %v, %v, q %T, dr %v", sratio, dratio, q, dr)
+					dst := image.NewYCbCr(image.Rect(0, 0, 70, 50), dratio)
+					ScaleYCbCr(dst, dr, src, image.Rect(2, 3, 35, 26), q)
+					checkCoverage(t, name, dst, dr, 0x40, 0x50, 0x60)
+				}
+			}
+		}
+	}
+}
+
+func TestTransformYCbCr(t *testing.T) {
+	src := image.NewYCbCr(image.Rect(0, 0, 31, 21), image.YCbCrSubsampleRatio420)
+	for i := range src.Y {
+		src.Y[i] = 0x40
+	}
+	for i := range src.Cb {
+		src.Cb[i], src.Cr[i] = 0x50, 0x60
+	}
+	for _, ratio := range ycbcrRatios {
+		dst := image.NewYCbCr(image.Rect(0, 0, 80, 60), ratio)
+		// Flip horizontally, and scale by 2.
+		m := f64.Aff3{
+			-2, 0, 2*31 + 7,
+			0, 2, 5,
+		}
+		TransformYCbCr(dst, m, src, src.Rect, CatmullRom)
+		checkCoverage(t, fmt.Sprintf("ratio %v", ratio), dst, image.Rect(7, 5, 69, 47), 0x40, 0x50, 0x60)
+	}
+}
+
+// checkCoverage checks that dst's samples that overlap dr are y, cb and cr,
+// and that the others are zero.
+func checkCoverage(t *testing.T, name string, dst *image.YCbCr, dr image.Rectangle, y, cb, cr uint8) {
+	t.Helper()
+	for j := dst.Rect.Min.Y; j < dst.Rect.Max.Y; j++ {
+		for i := dst.Rect.Min.X; i < dst.Rect.Max.X; i++ {
+			want := y
+			if !(image.Point{i, j}).In(dr) {
+				want = 0
+			}
+			if got := dst.Y[dst.YOffset(i, j)]; got != want {
+				t.Errorf("%s: Y at (%d, %d): got %#02x, want %#02x", name, i, j, got, want)
+				return
+			}
+		}
+	}
+	w, h := subsample(dst.SubsampleRatio)
+	pdr := planeRect(dr, w, h)
+	dcb, dcr := cPlanes(dst)
+	for j := dcb.Rect.Min.Y; j < dcb.Rect.Max.Y; j++ {
+		for i := dcb.Rect.Min.X; i < dcb.Rect.Max.X; i++ {
+			wantCb, wantCr := cb, cr
+			if !(image.Point{i, j}).In(pdr) {
+				wantCb, wantCr = 0, 0
+			}
+			gotCb, gotCr := dcb.Pix[dcb.PixOffset(i, j)], dcr.Pix[dcr.PixOffset(i, j)]
+			if gotCb != wantCb || gotCr != wantCr {
+				t.Errorf("%s: Cb, Cr at (%d, %d): got %#02x, %#02x, want %#02x, %#02x", name, i, j, gotCb, gotCr, wantCb, wantCr)
+				return
+			}
+		}
+	}
+}
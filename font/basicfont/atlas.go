@@ -0,0 +1,113 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package basicfont
+
+import (
+	"image"
+	"image/draw"
+	"sort"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// Runes returns the runes that f has glyphs for, in increasing order.
+func (f *Face) Runes() []rune {
+	var runes []rune
+	for _, rng := range f.Ranges {
+		for r := rng.Low; r < rng.High; r++ {
+			runes = append(runes, r)
+		}
+	}
+	return runes
+}
+
+// MaskRect returns the bounds of the sub-image of f.Mask that holds the glyph
+// for r. It returns false if f has no glyph for r.
+//
+// Unlike Glyph, it does not fall back to the glyph for U+FFFD.
+func (f *Face) MaskRect(r rune) (rect image.Rectangle, ok bool) {
+	found, rng := f.find(r)
+	if rng == nil || found != r {
+		return image.Rectangle{}, false
+	}
+	h := f.Ascent + f.Descent
+	y := (int(r-rng.Low) + rng.Offset) * h
+	return image.Rect(0, y, f.Width, y+h), true
+}
+
+// NewFace returns a Face whose Mask is an *image.Alpha that holds src's
+// glyphs for the given runes, rasterized once, so that the returned Face can
+// draw text without rasterizing glyphs. Runes that src has no glyph for are
+// skipped, as are repeated runes.
+//
+// All of a Face's glyphs have the same metrics. The returned Face's glyphs are
+// large enough to hold each of src's glyphs, drawn at a whole-pixel dot, and
+// their advance is the largest of those glyphs' advances, rounded to a whole
+// pixel. NewFace is therefore best suited to monospaced faces.
+func NewFace(src font.Face, runes []rune) *Face {
+	runes = append([]rune(nil), runes...)
+	sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+
+	m := src.Metrics()
+	f := &Face{
+		Height:  m.Height.Ceil(),
+		Ascent:  m.Ascent.Ceil(),
+		Descent: m.Descent.Ceil(),
+	}
+
+	// Find the glyphs, and the smallest cell that holds all of them.
+	n, minX, maxX, advance := 0, 0, 0, fixed.Int26_6(0)
+	for i, r := range runes {
+		if i > 0 && r == runes[i-1] {
+			continue
+		}
+		b, a, ok := src.GlyphBounds(r)
+		if !ok {
+			continue
+		}
+		runes[n] = r
+		if n == 0 || b.Min.X.Floor() < minX {
+			minX = b.Min.X.Floor()
+		}
+		if n == 0 || b.Max.X.Ceil() > maxX {
+			maxX = b.Max.X.Ceil()
+		}
+		if a > advance {
+			advance = a
+		}
+		if y := -b.Min.Y.Floor(); y > f.Ascent {
+			f.Ascent = y
+		}
+		if y := b.Max.Y.Ceil(); y > f.Descent {
+			f.Descent = y
+		}
+		n++
+	}
+	runes = runes[:n]
+	f.Advance = advance.Round()
+	f.Left = minX
+	f.Width = maxX - minX
+
+	// Draw each glyph into its own cell, and record the runes' ranges.
+	h := f.Ascent + f.Descent
+	mask := image.NewAlpha(image.Rect(0, 0, f.Width, n*h))
+	for i, r := range runes {
+		cell := image.Rect(0, i*h, f.Width, (i+1)*h)
+		dot := fixed.P(-f.Left, i*h+f.Ascent)
+		dr, glyph, glyphp, _, ok := src.Glyph(dot, r)
+		if ok {
+			clipped := dr.Intersect(cell)
+			draw.Draw(mask, clipped, glyph, glyphp.Add(clipped.Min.Sub(dr.Min)), draw.Src)
+		}
+		if i > 0 && r == runes[i-1]+1 {
+			f.Ranges[len(f.Ranges)-1].High++
+		} else {
+			f.Ranges = append(f.Ranges, Range{Low: r, High: r + 1, Offset: i})
+		}
+	}
+	f.Mask = mask
+	return f
+}
@@ -5,10 +5,16 @@
 package basicfont
 
 import (
+	"bytes"
 	"image"
+	"image/draw"
+	"reflect"
 	"testing"
 
 	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
 )
 
 func TestMetrics(t *testing.T) {
@@ -17,3 +23,60 @@ func TestMetrics(t *testing.T) {
 		t.Errorf("Face7x13: Metrics: got %v want %v", got, want)
 	}
 }
+
+// render draws the glyph for r, at a whole pixel dot, into a new image.
+func render(f font.Face, r rune) *image.Alpha {
+	dst := image.NewAlpha(image.Rect(-20, -40, 40, 20))
+	dr, mask, maskp, _, ok := f.Glyph(fixed.P(3, 0), r)
+	if ok {
+		draw.Draw(dst, dr, mask, maskp, draw.Src)
+	}
+	return dst
+}
+
+func TestNewFace(t *testing.T) {
+	ttf, err := opentype.Parse(goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	goFace, err := opentype.NewFace(ttf, &opentype.FaceOptions{Size: 12, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runes := []rune("zyx!ABC abc ÀÖ0123456789�\U0010ffff")
+	for _, src := range []font.Face{Face7x13, goFace} {
+		f := NewFace(src, runes)
+		for _, r := range runes {
+			_, _, srcOK := src.GlyphBounds(r)
+			if r == '\U0010ffff' {
+				srcOK = false
+			}
+			if _, ok := f.MaskRect(r); ok != srcOK {
+				t.Errorf("%T: MaskRect(%U): got ok %t, want %t", src, r, ok, srcOK)
+				continue
+			}
+			if !srcOK {
+				continue
+			}
+			if got, want := render(f, r), render(src, r); !bytes.Equal(got.Pix, want.Pix) {
+				t.Errorf("%T: glyph for %U differs", src, r)
+			}
+			if a, _ := f.GlyphAdvance(r); a.Ceil() > f.Advance {
+				t.Errorf("%T: advance for %U: got %v, want at most %d", src, r, a, f.Advance)
+			}
+		}
+	}
+
+	// Converting Face7x13 gives the same face.
+	f := NewFace(Face7x13, Face7x13.Runes())
+	if f.Advance != 7 || f.Width != 6 || f.Height != 13 || f.Ascent != 11 || f.Descent != 2 || f.Left != 0 {
+		t.Errorf("Face7x13: got metrics %d, %d, %d, %d, %d, %d", f.Advance, f.Width, f.Height, f.Ascent, f.Descent, f.Left)
+	}
+	if !reflect.DeepEqual(f.Ranges, Face7x13.Ranges) {
+		t.Errorf("Face7x13: got ranges %v, want %v", f.Ranges, Face7x13.Ranges)
+	}
+	if !bytes.Equal(f.Mask.(*image.Alpha).Pix, Face7x13.Mask.(*image.Alpha).Pix) {
+		t.Errorf("Face7x13: masks differ")
+	}
+}
@@ -127,6 +127,9 @@ type Drawer struct {
 	// be below or to the left. For example, drawing a 'j' in an italic face
 	// may affect pixels below and to the left of the dot.
 	Dot fixed.Point26_6
+	// Cache, if non-nil, caches the bounds and advances returned by the
+	// BoundXxx and MeasureXxx methods. It may be shared by many Drawers.
+	Cache *MeasureCache
 
 	// TODO: Clip image.Image?
 	// TODO: SrcP image.Point for Src images other than *image.Uniform? How
@@ -189,7 +192,7 @@ func (d *Drawer) DrawString(s string) {
 //
 // It is equivalent to BoundBytes(string(s)) but may be more efficient.
 func (d *Drawer) BoundBytes(s []byte) (bounds fixed.Rectangle26_6, advance fixed.Int26_6) {
-	bounds, advance = BoundBytes(d.Face, s)
+	bounds, advance = d.Cache.BoundBytes(d.Face, s)
 	bounds.Min = bounds.Min.Add(d.Dot)
 	bounds.Max = bounds.Max.Add(d.Dot)
 	return
@@ -198,7 +201,7 @@ func (d *Drawer) BoundBytes(s []byte) (bounds fixed.Rectangle26_6, advance fixed
 // BoundString returns the bounding box of s, drawn at the drawer dot, as well
 // as the advance.
 func (d *Drawer) BoundString(s string) (bounds fixed.Rectangle26_6, advance fixed.Int26_6) {
-	bounds, advance = BoundString(d.Face, s)
+	bounds, advance = d.Cache.BoundString(d.Face, s)
 	bounds.Min = bounds.Min.Add(d.Dot)
 	bounds.Max = bounds.Max.Add(d.Dot)
 	return
@@ -208,12 +211,12 @@ func (d *Drawer) BoundString(s string) (bounds fixed.Rectangle26_6, advance fixe
 //
 // It is equivalent to MeasureString(string(s)) but may be more efficient.
 func (d *Drawer) MeasureBytes(s []byte) (advance fixed.Int26_6) {
-	return MeasureBytes(d.Face, s)
+	return d.Cache.MeasureBytes(d.Face, s)
 }
 
 // MeasureString returns how far dot would advance by drawing s.
 func (d *Drawer) MeasureString(s string) (advance fixed.Int26_6) {
-	return MeasureString(d.Face, s)
+	return d.Cache.MeasureString(d.Face, s)
 }
 
 // BoundBytes returns the bounding box of s with f, drawn at a dot equal to the
@@ -0,0 +1,187 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package font
+
+import (
+	"container/list"
+	"reflect"
+	"sync"
+
+	"golang.org/x/image/math/fixed"
+)
+
+// MeasureCache caches the bounds and advances of strings measured with faces,
+// as returned by BoundString and MeasureString. It holds up to a fixed number
+// of strings, evicting the least recently used.
+//
+// A MeasureCache is safe for concurrent use by multiple goroutines, so that
+// one cache can be shared by many Drawers, such as those of concurrent
+// requests in a server that draws the same labels many times. Measuring with
+// a Face is still not safe for concurrent use, so those Drawers should not
+// share a Face.
+//
+// Entries are keyed by the Face value and the string, so a Face's measurements
+// are only cached if its dynamic type is comparable, such as a pointer type.
+// Faces are assumed to be immutable: a cached measurement is reused as long as
+// it is in the cache. A cache keeps a reference to each Face that it has
+// entries for.
+//
+// A nil *MeasureCache is valid, and caches nothing.
+type MeasureCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	faces      map[Face]map[string]*list.Element
+	// lru holds each *measureEntry, most recently used first.
+	lru list.List
+}
+
+// measureEntry is a MeasureCache entry.
+type measureEntry struct {
+	face Face
+	s    string
+
+	advance   fixed.Int26_6
+	bounds    fixed.Rectangle26_6
+	hasBounds bool
+}
+
+// NewMeasureCache returns a MeasureCache that holds up to maxEntries strings.
+// If maxEntries is not positive, it caches nothing.
+func NewMeasureCache(maxEntries int) *MeasureCache {
+	return &MeasureCache{
+		maxEntries: maxEntries,
+		faces:      map[Face]map[string]*list.Element{},
+	}
+}
+
+// Len returns the number of strings in the cache.
+func (c *MeasureCache) Len() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+// BoundBytes is like the BoundBytes function, but returns the cached result
+// for f and s if there is one.
+func (c *MeasureCache) BoundBytes(f Face, s []byte) (bounds fixed.Rectangle26_6, advance fixed.Int26_6) {
+	if !c.caches(f) {
+		return BoundBytes(f, s)
+	}
+	c.mu.Lock()
+	m, ok := c.get(c.faces[f][string(s)], true)
+	c.mu.Unlock()
+	if ok {
+		return m.bounds, m.advance
+	}
+	bounds, advance = BoundBytes(f, s)
+	c.add(f, string(s), advance, &bounds)
+	return bounds, advance
+}
+
+// BoundString is like the BoundString function, but returns the cached result
+// for f and s if there is one.
+func (c *MeasureCache) BoundString(f Face, s string) (bounds fixed.Rectangle26_6, advance fixed.Int26_6) {
+	if !c.caches(f) {
+		return BoundString(f, s)
+	}
+	c.mu.Lock()
+	m, ok := c.get(c.faces[f][s], true)
+	c.mu.Unlock()
+	if ok {
+		return m.bounds, m.advance
+	}
+	bounds, advance = BoundString(f, s)
+	c.add(f, s, advance, &bounds)
+	return bounds, advance
+}
+
+// MeasureBytes is like the MeasureBytes function, but returns the cached
+// result for f and s if there is one.
+func (c *MeasureCache) MeasureBytes(f Face, s []byte) (advance fixed.Int26_6) {
+	if !c.caches(f) {
+		return MeasureBytes(f, s)
+	}
+	c.mu.Lock()
+	m, ok := c.get(c.faces[f][string(s)], false)
+	c.mu.Unlock()
+	if ok {
+		return m.advance
+	}
+	advance = MeasureBytes(f, s)
+	c.add(f, string(s), advance, nil)
+	return advance
+}
+
+// MeasureString is like the MeasureString function, but returns the cached
+// result for f and s if there is one.
+func (c *MeasureCache) MeasureString(f Face, s string) (advance fixed.Int26_6) {
+	if !c.caches(f) {
+		return MeasureString(f, s)
+	}
+	c.mu.Lock()
+	m, ok := c.get(c.faces[f][s], false)
+	c.mu.Unlock()
+	if ok {
+		return m.advance
+	}
+	advance = MeasureString(f, s)
+	c.add(f, s, advance, nil)
+	return advance
+}
+
+// caches returns whether c can cache f's measurements.
+func (c *MeasureCache) caches(f Face) bool {
+	return c != nil && c.maxEntries > 0 && f != nil && reflect.TypeOf(f).Comparable()
+}
+
+// get returns a copy of e's entry, if e is non-nil and, if wantBounds, has
+// the bounds. c.mu must be held.
+func (c *MeasureCache) get(e *list.Element, wantBounds bool) (m measureEntry, ok bool) {
+	if e == nil {
+		return measureEntry{}, false
+	}
+	c.lru.MoveToFront(e)
+	m = *e.Value.(*measureEntry)
+	return m, m.hasBounds || !wantBounds
+}
+
+// add adds the advance, and the bounds if non-nil, of s measured with f to
+// the cache, evicting the least recently used strings if it is full.
+func (c *MeasureCache) add(f Face, s string, advance fixed.Int26_6, bounds *fixed.Rectangle26_6) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	strs := c.faces[f]
+	if e := strs[s]; e != nil {
+		// Another call added s, or s is cached without its bounds.
+		if bounds != nil {
+			m := e.Value.(*measureEntry)
+			m.bounds, m.hasBounds = *bounds, true
+		}
+		c.lru.MoveToFront(e)
+		return
+	}
+	if strs == nil {
+		strs = map[string]*list.Element{}
+		c.faces[f] = strs
+	}
+	m := &measureEntry{face: f, s: s, advance: advance}
+	if bounds != nil {
+		m.bounds, m.hasBounds = *bounds, true
+	}
+	strs[s] = c.lru.PushFront(m)
+
+	for c.lru.Len() > c.maxEntries {
+		old := c.lru.Remove(c.lru.Back()).(*measureEntry)
+		oldStrs := c.faces[old.face]
+		delete(oldStrs, old.s)
+		if len(oldStrs) == 0 {
+			delete(c.faces, old.face)
+		}
+	}
+}
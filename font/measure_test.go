@@ -0,0 +1,143 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package font
+
+import (
+	"testing"
+
+	"golang.org/x/image/math/fixed"
+)
+
+// countingFace is a toyFace that counts its GlyphBounds and GlyphAdvance
+// calls.
+type countingFace struct {
+	toyFace
+	nBounds, nAdvances int
+}
+
+func (f *countingFace) GlyphBounds(r rune) (fixed.Rectangle26_6, fixed.Int26_6, bool) {
+	f.nBounds++
+	return f.toyFace.GlyphBounds(r)
+}
+
+func (f *countingFace) GlyphAdvance(r rune) (fixed.Int26_6, bool) {
+	f.nAdvances++
+	return f.toyFace.GlyphAdvance(r)
+}
+
+func TestMeasureCache(t *testing.T) {
+	f := &countingFace{}
+	c := NewMeasureCache(2)
+
+	if got, want := c.MeasureString(f, "abc"), 3*toyAdvance; got != want {
+		t.Fatalf("MeasureString: got %v, want %v", got, want)
+	}
+	if got, want := c.MeasureBytes(f, []byte("abc")), 3*toyAdvance; got != want {
+		t.Fatalf("MeasureBytes: got %v, want %v", got, want)
+	}
+	if f.nAdvances != 3 {
+		t.Errorf("after measuring: got %d GlyphAdvance calls, want 3", f.nAdvances)
+	}
+
+	// The bounds are not cached by measuring, but are by bounding.
+	wantBounds, wantAdvance := BoundString(toyFace{}, "abc")
+	for i := 0; i < 2; i++ {
+		bounds, advance := c.BoundString(f, "abc")
+		if bounds != wantBounds || advance != wantAdvance {
+			t.Fatalf("BoundString: got %v, %v, want %v, %v", bounds, advance, wantBounds, wantAdvance)
+		}
+		bounds, advance = c.BoundBytes(f, []byte("abc"))
+		if bounds != wantBounds || advance != wantAdvance {
+			t.Fatalf("BoundBytes: got %v, %v, want %v, %v", bounds, advance, wantBounds, wantAdvance)
+		}
+	}
+	if f.nBounds != 3 {
+		t.Errorf("after bounding: got %d GlyphBounds calls, want 3", f.nBounds)
+	}
+
+	// Other faces and strings have their own entries, and the least recently
+	// used entry is evicted.
+	g := &countingFace{}
+	c.MeasureString(g, "abc")
+	c.MeasureString(f, "abc")
+	c.MeasureString(f, "de")
+	if got := c.Len(); got != 2 {
+		t.Errorf("Len: got %d, want 2", got)
+	}
+	c.MeasureString(g, "abc")
+	if g.nAdvances != 6 {
+		t.Errorf("g: got %d GlyphAdvance calls, want 6", g.nAdvances)
+	}
+	c.MeasureString(f, "de")
+	if f.nAdvances != 5 {
+		t.Errorf("f: got %d GlyphAdvance calls, want 5", f.nAdvances)
+	}
+}
+
+// sliceFace is a Face whose type is not comparable.
+type sliceFace struct {
+	toyFace
+	_ []int
+}
+
+func TestMeasureCacheNotCached(t *testing.T) {
+	want := 2 * toyAdvance
+	caches := []*MeasureCache{
+		nil,
+		{},
+		NewMeasureCache(0),
+		NewMeasureCache(10),
+	}
+	for i, c := range caches {
+		if got := c.MeasureString(sliceFace{}, "ab"); got != want {
+			t.Errorf("cache #%d: got %v, want %v", i, got, want)
+		}
+		if got := c.MeasureString(&countingFace{}, "ab"); got != want {
+			t.Errorf("cache #%d: got %v, want %v", i, got, want)
+		}
+		if got, want := c.Len(), i/3; got != want {
+			t.Errorf("cache #%d: Len: got %d, want %d", i, got, want)
+		}
+	}
+}
+
+func TestMeasureCacheBytesAllocations(t *testing.T) {
+	f := &countingFace{}
+	c := NewMeasureCache(10)
+	s := []byte("hello")
+	c.MeasureBytes(f, s)
+	allocs := testing.AllocsPerRun(10, func() {
+		c.MeasureBytes(f, s)
+	})
+	if allocs != 0 {
+		t.Errorf("got %v allocations, want 0", allocs)
+	}
+}
+
+func TestDrawerCache(t *testing.T) {
+	f := &countingFace{}
+	c := NewMeasureCache(10)
+	d0 := &Drawer{Face: f, Cache: c}
+	d1 := &Drawer{Face: f, Cache: c, Dot: fixed.P(5, 7)}
+
+	if got, want := d0.MeasureString("xyz"), 3*toyAdvance; got != want {
+		t.Errorf("d0: got %v, want %v", got, want)
+	}
+	if got, want := d1.MeasureString("xyz"), 3*toyAdvance; got != want {
+		t.Errorf("d1: got %v, want %v", got, want)
+	}
+	if f.nAdvances != 3 {
+		t.Errorf("got %d GlyphAdvance calls, want 3", f.nAdvances)
+	}
+
+	b0, _ := d0.BoundString("xyz")
+	b1, _ := d1.BoundString("xyz")
+	if got, want := b1, b0.Add(fixed.P(5, 7)); got != want {
+		t.Errorf("d1 bounds: got %v, want %v", got, want)
+	}
+	if f.nBounds != 3 {
+		t.Errorf("got %d GlyphBounds calls, want 3", f.nBounds)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package opentype
+
+import (
+	"image"
+
+	"golang.org/x/image/math/fixed"
+)
+
+// GlyphRect returns the dr that Glyph returns for r at the dot, without
+// rasterizing the glyph. Its size is the size of the glyph's mask, which is
+// the space that DrawGlyph needs, not counting padding.
+//
+// It returns !ok if the face does not contain a glyph for r, as Glyph does.
+func (f *Face) GlyphRect(dot fixed.Point26_6, r rune) (dr image.Rectangle, ok bool) {
+	x, _, dr, _, _, ok := f.loadGlyph(dot, r)
+	if !ok {
+		return image.Rectangle{}, false
+	}
+	return dr, x != 0
+}
+
+// DrawGlyph is like Glyph, but rasterizes the glyph directly into the rect
+// part of dst, such as a slot in a glyph atlas texture that holds many
+// glyphs, instead of into a mask that the Face owns and that the caller then
+// has to copy.
+//
+// The glyph's mask is drawn padding pixels inside the top-left corner of
+// rect, and the rest of rect is cleared, so that sampling the atlas with
+// bilinear filtering does not blend in the neighboring glyphs. DrawGlyph
+// returns the same dr, advance and ok as Glyph, and maskp, the point in dst
+// that corresponds to dr.Min. Drawing with dst as the mask, at maskp, is
+// therefore equivalent to drawing with Glyph's mask.
+//
+// The mask's size is that of the dr returned by GlyphRect, so rect needs to
+// be 2*padding pixels larger in each dimension. If rect is smaller than that,
+// or is not inside dst's bounds, DrawGlyph does not modify dst and returns
+// !ok.
+func (f *Face) DrawGlyph(dst *image.Alpha, rect image.Rectangle, padding int, dot fixed.Point26_6, r rune) (dr image.Rectangle, maskp image.Point, advance fixed.Int26_6, ok bool) {
+	x, segments, dr, bias, advance, ok := f.loadGlyph(dot, r)
+	if !ok || padding < 0 || !rect.In(dst.Bounds()) {
+		return image.Rectangle{}, image.Point{}, 0, false
+	}
+	maskp = rect.Min.Add(image.Pt(padding, padding))
+	mr := image.Rectangle{Min: maskp, Max: maskp.Add(dr.Size())}
+	if !mr.Inset(-padding).In(rect) {
+		return image.Rectangle{}, image.Point{}, 0, false
+	}
+
+	// Clear rect. The rasterizer then replaces the pixels of mr.
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		row := dst.Pix[dst.PixOffset(rect.Min.X, y):dst.PixOffset(rect.Max.X, y)]
+		for i := range row {
+			row[i] = 0
+		}
+	}
+	if !mr.Empty() {
+		f.rasterize(dst, mr, segments, bias)
+	}
+	return dr, maskp, advance, x != 0
+}
@@ -0,0 +1,90 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package opentype
+
+import (
+	"bytes"
+	"image"
+	"testing"
+
+	"golang.org/x/image/math/fixed"
+)
+
+func TestDrawGlyph(t *testing.T) {
+	f := regular.(*Face)
+	const padding = 2
+	for _, test := range runeTests {
+		for _, dot := range []fixed.Point26_6{
+			{X: fixed.I(10), Y: fixed.I(20)},
+			{X: fixed.I(10) + 21, Y: fixed.I(20) + 45},
+		} {
+			wantDR, mask, maskp, wantAdvance, wantOK := f.Glyph(dot, test.r)
+			want := mask.(*image.Alpha)
+
+			gotDR, ok := f.GlyphRect(dot, test.r)
+			if gotDR != wantDR || ok != wantOK {
+				t.Errorf("%q, dot %v: GlyphRect: got %v, %t, want %v, %t", test.r, dot, gotDR, ok, wantDR, wantOK)
+			}
+
+			// The atlas is initially opaque, so that the test can check which
+			// pixels DrawGlyph clears.
+			atlas := image.NewAlpha(image.Rect(0, 0, 40, 40))
+			for i := range atlas.Pix {
+				atlas.Pix[i] = 0xff
+			}
+			rect := image.Rect(5, 7, 5+wantDR.Dx()+2*padding+1, 7+wantDR.Dy()+2*padding)
+			dr, mp, advance, ok := f.DrawGlyph(atlas, rect, padding, dot, test.r)
+			if dr != wantDR || advance != wantAdvance || ok != wantOK {
+				t.Errorf("%q, dot %v: got %v, %v, %t, want %v, %v, %t",
+					test.r, dot, dr, advance, ok, wantDR, wantAdvance, wantOK)
+				continue
+			}
+			if want := rect.Min.Add(image.Pt(padding, padding)); mp != want {
+				t.Errorf("%q, dot %v: got maskp %v, want %v", test.r, dot, mp, want)
+			}
+			for y := atlas.Rect.Min.Y; y < atlas.Rect.Max.Y; y++ {
+				for x := atlas.Rect.Min.X; x < atlas.Rect.Max.X; x++ {
+					w := uint8(0xff)
+					if p := image.Pt(x, y); p.In(rect) {
+						w = 0
+						if q := p.Sub(mp).Add(maskp); q.In(want.Rect) {
+							w = want.AlphaAt(q.X, q.Y).A
+						}
+					}
+					if g := atlas.AlphaAt(x, y).A; g != w {
+						t.Fatalf("%q, dot %v: pixel (%d, %d): got %#02x, want %#02x", test.r, dot, x, y, g, w)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestDrawGlyphNoRoom(t *testing.T) {
+	f := regular.(*Face)
+	dot := fixed.P(10, 20)
+	dr, _ := f.GlyphRect(dot, 'A')
+	atlas := image.NewAlpha(image.Rect(0, 0, 40, 40))
+	for i := range atlas.Pix {
+		atlas.Pix[i] = 0x80
+	}
+	for _, test := range []struct {
+		rect    image.Rectangle
+		padding int
+	}{
+		{image.Rect(0, 0, dr.Dx()+1, dr.Dy()+2), 1},
+		{image.Rect(0, 0, dr.Dx()+2, dr.Dy()+1), 1},
+		{image.Rect(0, 0, dr.Dx(), dr.Dy()), -1},
+		{image.Rect(35, 35, 35+dr.Dx(), 35+dr.Dy()), 0},
+	} {
+		before := append([]byte(nil), atlas.Pix...)
+		if _, _, _, ok := f.DrawGlyph(atlas, test.rect, test.padding, dot, 'A'); ok {
+			t.Errorf("rect %v, padding %d: got ok, want !ok", test.rect, test.padding)
+		}
+		if !bytes.Equal(atlas.Pix, before) {
+			t.Errorf("rect %v, padding %d: atlas was modified", test.rect, test.padding)
+		}
+	}
+}
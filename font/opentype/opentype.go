@@ -72,6 +72,18 @@ type FaceOptions struct {
 	Size    float64      // Size is the font size in points
 	DPI     float64      // DPI is the dots per inch resolution
 	Hinting font.Hinting // Hinting selects how to quantize a vector font's glyph nodes
+
+	// Tracking is whether to apply the font's size-specific tracking (letter
+	// spacing), from its trak table, for Size. If so, it is included in the
+	// values returned by Kern, so that a font.Drawer applies it between each
+	// pair of glyphs.
+	Tracking bool
+
+	// SnapToPixels is whether Glyph rounds the dot to the nearest whole pixel
+	// before rasterizing. A glyph's mask then does not change as the dot
+	// moves by a fraction of a pixel, such as when text is animated, which
+	// avoids shimmering. Advances and kerning are not rounded.
+	SnapToPixels bool
 }
 
 func defaultFaceOptions() *FaceOptions {
@@ -82,14 +94,36 @@ func defaultFaceOptions() *FaceOptions {
 	}
 }
 
+// ReaderAtCloser is the interface that groups the ReadAt and Close methods.
+// Both *os.File and memory-mapped files typically implement it.
+type ReaderAtCloser interface {
+	io.ReaderAt
+	io.Closer
+}
+
 // Face implements the font.Face interface for Font values.
 //
+// A Face caches its font metrics and the scratch buffers used to load and
+// rasterize glyphs. Close releases them, along with the font data source if
+// the Face owns it.
+//
 // A Face is not safe to use concurrently.
 type Face struct {
 	f       *Font
 	hinting font.Hinting
 	scale   fixed.Int26_6
 
+	// tracking is added to each Kern result. It is zero unless
+	// FaceOptions.Tracking was set.
+	tracking fixed.Int26_6
+
+	// snap is whether Glyph rounds the dot to whole pixels.
+	snap bool
+
+	// src is the font data source that the Face owns, if any. It is closed
+	// by Close.
+	src io.Closer
+
 	metrics    font.Metrics
 	metricsSet bool
 
@@ -109,13 +143,62 @@ func NewFace(f *Font, opts *FaceOptions) (font.Face, error) {
 		f:       f,
 		hinting: opts.Hinting,
 		scale:   fixed.Int26_6(0.5 + (opts.Size * opts.DPI * 64 / 72)),
+		snap:    opts.SnapToPixels,
+	}
+	if opts.Tracking {
+		tracking, err := f.Tracking(&face.buf, fixed.Int26_6(0.5+opts.Size*64), face.scale, opts.Hinting)
+		if err != nil {
+			return nil, err
+		}
+		face.tracking = tracking
+	}
+	return face, nil
+}
+
+// NewFaceReaderAt parses the OpenType font, such as TTF or OTF data, in src and
+// returns a new font.Face for it. If src holds a font collection, the first
+// font is used.
+//
+// The returned Face takes ownership of src: its Close method closes src, after
+// which the Face must not be used. If NewFaceReaderAt returns an error, src
+// is not closed and remains the caller's responsibility.
+//
+// If opts is nil, sensible defaults will be used.
+func NewFaceReaderAt(src ReaderAtCloser, opts *FaceOptions) (font.Face, error) {
+	c, err := sfnt.ParseCollectionReaderAt(src)
+	if err != nil {
+		return nil, err
+	}
+	f, err := c.Font(0)
+	if err != nil {
+		return nil, err
+	}
+	face, err := NewFace(f, opts)
+	if err != nil {
+		return nil, err
 	}
+	face.(*Face).src = src
 	return face, nil
 }
 
-// Close satisfies the font.Face interface.
+// Close satisfies the font.Face interface. It releases the Face's cached
+// metrics and scratch buffers and, if the Face was returned by
+// NewFaceReaderAt, closes its font data source.
+//
+// A Face returned by NewFace can still be used after Close, re-building its
+// caches as needed. Calling Close more than once is a no-op.
 func (f *Face) Close() error {
-	return nil
+	f.metrics = font.Metrics{}
+	f.metricsSet = false
+	f.buf = sfnt.Buffer{}
+	f.rast = vector.Rasterizer{}
+	f.mask = image.Alpha{}
+	src := f.src
+	if src == nil {
+		return nil
+	}
+	f.src = nil
+	return src.Close()
 }
 
 // Metrics satisfies the font.Face interface.
@@ -137,16 +220,45 @@ func (f *Face) Kern(r0, r1 rune) fixed.Int26_6 {
 	x1, _ := f.f.GlyphIndex(&f.buf, r1)
 	k, err := f.f.Kern(&f.buf, x0, x1, fixed.Int26_6(f.f.UnitsPerEm()), f.hinting)
 	if err != nil {
-		return 0
+		return f.tracking
 	}
-	return k
+	return k + f.tracking
 }
 
 // Glyph satisfies the font.Face interface.
 func (f *Face) Glyph(dot fixed.Point26_6, r rune) (dr image.Rectangle, mask image.Image, maskp image.Point, advance fixed.Int26_6, ok bool) {
+	x, segments, dr, bias, advance, ok := f.loadGlyph(dot, r)
+	if !ok {
+		return image.Rectangle{}, nil, image.Point{}, 0, false
+	}
+	width := dr.Dx()
+	height := dr.Dy()
+
+	// Configure the mask image, re-allocating its buffer if necessary.
+	nPixels := width * height
+	if cap(f.mask.Pix) < nPixels {
+		f.mask.Pix = make([]uint8, 2*nPixels)
+	}
+	f.mask.Pix = f.mask.Pix[:nPixels]
+	f.mask.Stride = width
+	f.mask.Rect.Min.X = 0
+	f.mask.Rect.Min.Y = 0
+	f.mask.Rect.Max.X = width
+	f.mask.Rect.Max.Y = height
+
+	f.rasterize(&f.mask, f.mask.Bounds(), segments, bias)
+	return dr, &f.mask, f.mask.Rect.Min, advance, x != 0
+}
+
+// loadGlyph loads the glyph for r, and returns its segments, which are only
+// valid until f.buf is re-used, the integer-pixel bounds dr of the glyph
+// drawn at the dot, and the bias that translates the segments from glyph
+// space to rasterizer space, whose origin is at dr.Min. It returns !ok if the
+// glyph could not be loaded.
+func (f *Face) loadGlyph(dot fixed.Point26_6, r rune) (x sfnt.GlyphIndex, segments sfnt.Segments, dr image.Rectangle, bias fixed.Point26_6, advance fixed.Int26_6, ok bool) {
 	x, err := f.f.GlyphIndex(&f.buf, r)
 	if err != nil {
-		return image.Rectangle{}, nil, image.Point{}, 0, false
+		return 0, nil, image.Rectangle{}, fixed.Point26_6{}, 0, false
 	}
 
 	// Call f.f.GlyphAdvance before f.f.LoadGlyph because the LoadGlyph docs
@@ -155,12 +267,12 @@ func (f *Face) Glyph(dot fixed.Point26_6, r rune) (dr image.Rectangle, mask imag
 
 	advance, err = f.f.GlyphAdvance(&f.buf, x, f.scale, f.hinting)
 	if err != nil {
-		return image.Rectangle{}, nil, image.Point{}, 0, false
+		return 0, nil, image.Rectangle{}, fixed.Point26_6{}, 0, false
 	}
 
-	segments, err := f.f.LoadGlyph(&f.buf, x, f.scale, nil)
+	segments, err = f.f.LoadGlyph(&f.buf, x, f.scale, nil)
 	if err != nil {
-		return image.Rectangle{}, nil, image.Point{}, 0, false
+		return 0, nil, image.Rectangle{}, fixed.Point26_6{}, 0, false
 	}
 
 	// Numerical notation used below:
@@ -170,6 +282,11 @@ func (f *Face) Glyph(dot fixed.Point26_6, r rune) (dr image.Rectangle, mask imag
 	// Using 26.6 fixed point numbers means that there are 64 sub-pixel units
 	// in 1 integer pixel unit.
 
+	if f.snap {
+		dot.X = fixed.I(dot.X.Round())
+		dot.Y = fixed.I(dot.Y.Round())
+	}
+
 	// Translate the sub-pixel bounding box from glyph space (where the glyph
 	// origin is at (0:00, 0:00)) to dst space (where the glyph origin is at
 	// the dot). dst space is the coordinate space that contains both the dot
@@ -181,10 +298,8 @@ func (f *Face) Glyph(dot fixed.Point26_6, r rune) (dr image.Rectangle, mask imag
 	dr.Min.Y = dBounds.Min.Y.Floor()
 	dr.Max.X = dBounds.Max.X.Ceil()
 	dr.Max.Y = dBounds.Max.Y.Ceil()
-	width := dr.Dx()
-	height := dr.Dy()
-	if width < 0 || height < 0 {
-		return image.Rectangle{}, nil, image.Point{}, 0, false
+	if dr.Dx() < 0 || dr.Dy() < 0 {
+		return 0, nil, image.Rectangle{}, fixed.Point26_6{}, 0, false
 	}
 
 	// Calculate the sub-pixel bias to convert from glyph space to rasterizer
@@ -201,57 +316,50 @@ func (f *Face) Glyph(dot fixed.Point26_6, r rune) (dr image.Rectangle, mask imag
 	// and biasX = 25:48 - 27:00 = -1:16. A vertical stroke at 1:20 in glyph
 	// space becomes (1:20 + -1:16) = 0:04 in rasterizer space. 0:04 as a
 	// fixed.Int26_6 value is float32(4)/64.0 = 0.0625 as a float32 value.
-	biasX := dot.X - fixed.Int26_6(dr.Min.X<<6)
-	biasY := dot.Y - fixed.Int26_6(dr.Min.Y<<6)
+	bias.X = dot.X - fixed.Int26_6(dr.Min.X<<6)
+	bias.Y = dot.Y - fixed.Int26_6(dr.Min.Y<<6)
 
-	// Configure the mask image, re-allocating its buffer if necessary.
-	nPixels := width * height
-	if cap(f.mask.Pix) < nPixels {
-		f.mask.Pix = make([]uint8, 2*nPixels)
-	}
-	f.mask.Pix = f.mask.Pix[:nPixels]
-	f.mask.Stride = width
-	f.mask.Rect.Min.X = 0
-	f.mask.Rect.Min.Y = 0
-	f.mask.Rect.Max.X = width
-	f.mask.Rect.Max.Y = height
+	return x, segments, dr, bias, advance, true
+}
 
+// rasterize rasterizes the biased segments into the r part of dst, replacing
+// its pixels. r must be inside dst's bounds.
+func (f *Face) rasterize(dst *image.Alpha, r image.Rectangle, segments sfnt.Segments, bias fixed.Point26_6) {
 	// Rasterize the biased segments, converting from fixed.Int26_6 to float32.
-	f.rast.Reset(width, height)
+	f.rast.Reset(r.Dx(), r.Dy())
 	f.rast.DrawOp = draw.Src
-	for _, seg := range segments {
-		switch seg.Op {
-		case sfnt.SegmentOpMoveTo:
-			f.rast.MoveTo(
-				float32(seg.Args[0].X+biasX)/64,
-				float32(seg.Args[0].Y+biasY)/64,
-			)
-		case sfnt.SegmentOpLineTo:
-			f.rast.LineTo(
-				float32(seg.Args[0].X+biasX)/64,
-				float32(seg.Args[0].Y+biasY)/64,
-			)
-		case sfnt.SegmentOpQuadTo:
-			f.rast.QuadTo(
-				float32(seg.Args[0].X+biasX)/64,
-				float32(seg.Args[0].Y+biasY)/64,
-				float32(seg.Args[1].X+biasX)/64,
-				float32(seg.Args[1].Y+biasY)/64,
-			)
-		case sfnt.SegmentOpCubeTo:
-			f.rast.CubeTo(
-				float32(seg.Args[0].X+biasX)/64,
-				float32(seg.Args[0].Y+biasY)/64,
-				float32(seg.Args[1].X+biasX)/64,
-				float32(seg.Args[1].Y+biasY)/64,
-				float32(seg.Args[2].X+biasX)/64,
-				float32(seg.Args[2].Y+biasY)/64,
-			)
-		}
-	}
-	f.rast.Draw(&f.mask, f.mask.Bounds(), image.Opaque, image.Point{})
+	vector.AppendSegments(&f.rast, segments, bias)
+	f.rast.Draw(dst, r, image.Opaque, image.Point{})
+}
 
-	return dr, &f.mask, f.mask.Rect.Min, advance, x != 0
+// GlyphPath returns the outline of the glyph for r, scaled to the Face's size,
+// and the glyph's advance width. The segments are in glyph space, where the
+// glyph origin (the dot) is at (0, 0) and the y axis increases downwards, and
+// are the same segments that Glyph rasterizes. Translating them by the dot
+// and drawing them, such as in SVG or PDF output, therefore matches Glyph's
+// raster output.
+//
+// The returned segments are owned by the caller, and remain valid after
+// subsequent calls to the Face's methods.
+//
+// It returns !ok if the face does not contain a glyph for r, in which case
+// the segments are those of the font's notdef glyph, as for Glyph.
+func (f *Face) GlyphPath(r rune) (segments sfnt.Segments, advance fixed.Int26_6, ok bool) {
+	x, err := f.f.GlyphIndex(&f.buf, r)
+	if err != nil {
+		return nil, 0, false
+	}
+	advance, err = f.f.GlyphAdvance(&f.buf, x, f.scale, f.hinting)
+	if err != nil {
+		return nil, 0, false
+	}
+	segments, err = f.f.LoadGlyph(&f.buf, x, f.scale, nil)
+	if err != nil {
+		return nil, 0, false
+	}
+	// Copy the segments, as f.buf owns them.
+	segments = append(sfnt.Segments(nil), segments...)
+	return segments, advance, x != 0
 }
 
 // GlyphBounds satisfies the font.Face interface.
@@ -267,3 +375,43 @@ func (f *Face) GlyphAdvance(r rune) (advance fixed.Int26_6, ok bool) {
 	advance, err := f.f.GlyphAdvance(&f.buf, x, f.scale, f.hinting)
 	return advance, (err == nil) && (x != 0)
 }
+
+// AppendString adds the outlines of the glyphs of s to p, laid out from dot
+// as font.Drawer.DrawString lays them out, with kerning, and returns the dot
+// after the last glyph. The outlines are in pixel units, in p's coordinate
+// space, in which a *vector.Rasterizer's bounds' top-left corner is at (0, 0).
+//
+// Rasterizing p then draws all of s in one pass, for single-color text,
+// instead of compositing each glyph's mask in turn. As with DrawString, runes
+// without a glyph are drawn as the font's notdef glyph.
+func (f *Face) AppendString(p vector.PathBuilder, dot fixed.Point26_6, s string) fixed.Point26_6 {
+	prevC := rune(-1)
+	for _, c := range s {
+		if prevC >= 0 {
+			dot.X += f.Kern(prevC, c)
+		}
+		prevC = c
+		x, err := f.f.GlyphIndex(&f.buf, c)
+		if err != nil {
+			continue
+		}
+		// As for Glyph, call GlyphAdvance before LoadGlyph, whose segments
+		// are only valid until f.buf is re-used.
+		advance, err := f.f.GlyphAdvance(&f.buf, x, f.scale, f.hinting)
+		if err != nil {
+			continue
+		}
+		segments, err := f.f.LoadGlyph(&f.buf, x, f.scale, nil)
+		if err != nil {
+			continue
+		}
+		bias := dot
+		if f.snap {
+			bias.X = fixed.I(bias.X.Round())
+			bias.Y = fixed.I(bias.Y.Round())
+		}
+		vector.AppendSegments(p, segments, bias)
+		dot.X += advance
+	}
+	return dot
+}
@@ -5,6 +5,8 @@
 package opentype
 
 import (
+	"bytes"
+	"encoding/binary"
 	"image"
 	"testing"
 
@@ -12,6 +14,7 @@ import (
 	"golang.org/x/image/font/gofont/goregular"
 	"golang.org/x/image/font/sfnt"
 	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
 )
 
 var (
@@ -109,6 +112,114 @@ func TestFaceGlyph(t *testing.T) {
 	}
 }
 
+func TestFaceSnapToPixels(t *testing.T) {
+	f, err := sfnt.Parse(goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, snap := range []bool{false, true} {
+		opts := defaultFaceOptions()
+		opts.SnapToPixels = snap
+		face, err := NewFace(f, opts)
+		if err != nil {
+			t.Fatalf("NewFace: %v", err)
+		}
+		_, mask0, _, _, _ := face.Glyph(fixed.P(10, 20), 'A')
+		want := append([]uint8(nil), mask0.(*image.Alpha).Pix...)
+		// 10:16 and 20:40 round to 10 and 21.
+		dr, mask1, _, _, _ := face.Glyph(fixed.Point26_6{X: 10<<6 + 16, Y: 20<<6 + 40}, 'A')
+		same := bytes.Equal(mask1.(*image.Alpha).Pix, want)
+		if snap {
+			if wantDr := runeTests[1].dr.Add(image.Pt(10, 21)); dr != wantDr {
+				t.Errorf("snap=%t: dr: got %v, want %v", snap, dr, wantDr)
+			}
+			if !same {
+				t.Errorf("snap=%t: masks differ for dots in the same pixel", snap)
+			}
+		} else if same {
+			t.Errorf("snap=%t: masks are the same for different sub-pixel dots", snap)
+		}
+	}
+}
+
+func TestFaceGlyphPath(t *testing.T) {
+	for _, test := range runeTests {
+		segments, advance, ok := regular.(*Face).GlyphPath(test.r)
+		if !ok {
+			t.Errorf("could not get glyph path for %q", test.r)
+			continue
+		}
+		if advance != test.advance {
+			t.Errorf("%q: glyph advance width=%d. want=%d", test.r, advance, test.advance)
+			continue
+		}
+		bounds, _, _ := regular.GlyphBounds(test.r)
+		if got := segments.Bounds(); got != bounds {
+			t.Errorf("%q: glyph path bounds=%v. want=%v", test.r, got, bounds)
+			continue
+		}
+
+		// The segments must remain valid after loading another glyph.
+		want := append(sfnt.Segments(nil), segments...)
+		regular.Glyph(fixed.Point26_6{}, 'W')
+		for i := range want {
+			if segments[i] != want[i] {
+				t.Errorf("%q: segment %d changed after loading another glyph", test.r, i)
+				break
+			}
+		}
+	}
+
+	if _, _, ok := regular.(*Face).GlyphPath('\U0010fffd'); ok {
+		t.Errorf("GlyphPath of a missing rune: got ok, want !ok")
+	}
+}
+
+func TestFaceAppendString(t *testing.T) {
+	const s = "Jumpy AVa, fox!"
+	face := regular.(*Face)
+	start := fixed.Point26_6{X: 3<<6 + 20, Y: 20 << 6}
+	r := image.Rect(0, 0, 128, 28)
+
+	want := image.NewAlpha(r)
+	d := font.Drawer{Dst: want, Src: image.Opaque, Face: face, Dot: start}
+	d.DrawString(s)
+
+	var z vector.Rasterizer
+	z.Reset(r.Dx(), r.Dy())
+	dot := face.AppendString(&z, start, s)
+	if dot != d.Dot {
+		t.Errorf("dot: got %v, want %v", dot, d.Dot)
+	}
+	got := image.NewAlpha(r)
+	z.Draw(got, r, image.Opaque, image.Point{})
+
+	// Compositing glyph masks and rasterizing one path differ by rounding,
+	// and where glyphs share a pixel, such as where kerning brings them
+	// together.
+	nDiff := 0
+	for i := range got.Pix {
+		if d := int(got.Pix[i]) - int(want.Pix[i]); d < -1 || d > 1 {
+			nDiff++
+		}
+	}
+	if nDiff > 8 {
+		t.Errorf("got %d different pixels, want at most 8", nDiff)
+	}
+
+	// Appending to a Path and adding that to a Rasterizer is the same as
+	// appending to the Rasterizer.
+	var p vector.Path
+	face.AppendString(&p, start, s)
+	z.Reset(r.Dx(), r.Dy())
+	p.AddTo(&z)
+	viaPath := image.NewAlpha(r)
+	z.Draw(viaPath, r, image.Opaque, image.Point{})
+	if !bytes.Equal(viaPath.Pix, got.Pix) {
+		t.Error("Path and Rasterizer results differ")
+	}
+}
+
 func BenchmarkFaceGlyph(b *testing.B) {
 	fixedDot := fixed.P(200, 500)
 	r := 'A'
@@ -158,3 +269,124 @@ func TestFaceMetrics(t *testing.T) {
 		t.Fatalf("metrics failed. got=%#v. want=%#v", got, want)
 	}
 }
+
+type countingCloser struct {
+	*bytes.Reader
+	closed int
+}
+
+func (c *countingCloser) Close() error {
+	c.closed++
+	return nil
+}
+
+func TestNewFaceReaderAt(t *testing.T) {
+	src := &countingCloser{Reader: bytes.NewReader(goregular.TTF)}
+	face, err := NewFaceReaderAt(src, defaultFaceOptions())
+	if err != nil {
+		t.Fatalf("NewFaceReaderAt: %v", err)
+	}
+	for _, rt := range runeTests {
+		got, ok := face.GlyphAdvance(rt.r)
+		if !ok || got != rt.advance {
+			t.Errorf("GlyphAdvance(%q): got (%v, %t), want (%v, true)", rt.r, got, ok, rt.advance)
+		}
+	}
+	if got, want := face.Metrics(), regular.Metrics(); got != want {
+		t.Errorf("Metrics: got %#v, want %#v", got, want)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := face.Close(); err != nil {
+			t.Fatalf("Close #%d: %v", i, err)
+		}
+		if src.closed != 1 {
+			t.Fatalf("Close #%d: src closed %d times, want 1", i, src.closed)
+		}
+	}
+
+	invalid := &countingCloser{Reader: bytes.NewReader([]byte("not a font"))}
+	if _, err := NewFaceReaderAt(invalid, nil); err == nil {
+		t.Fatalf("NewFaceReaderAt: got nil error for invalid data")
+	}
+	if invalid.closed != 0 {
+		t.Fatalf("NewFaceReaderAt: src closed on error")
+	}
+}
+
+func TestFaceCloseReusable(t *testing.T) {
+	f, err := sfnt.Parse(goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	face, err := NewFace(f, defaultFaceOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := face.Metrics()
+	if err := face.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if got := face.Metrics(); got != want {
+		t.Fatalf("Metrics after Close: got %#v, want %#v", got, want)
+	}
+	if _, _, _, _, ok := face.Glyph(fixed.Point26_6{}, 'A'); !ok {
+		t.Fatalf("Glyph after Close: not ok")
+	}
+}
+
+// withTrakTable returns a copy of the font data src with a trak table whose
+// normal track is 20 font units at 12 points, and 0 at 24 points. It assumes
+// that "trak" sorts after the tags of all of src's tables.
+func withTrakTable(src []byte) []byte {
+	trak := []byte{
+		0, 1, 0, 0, 0, 0, 0, 12, 0, 0, 0, 0, // Header.
+		0, 1, 0, 2, 0, 0, 0, 28, // TrackData.
+		0, 0, 0, 0, 1, 0, 0, 36, // TrackTableEntry.
+		0, 12, 0, 0, 0, 24, 0, 0, // Sizes.
+		0, 20, 0, 0, // Values.
+	}
+	const headerSize, recordSize = 12, 16
+	n := int(binary.BigEndian.Uint16(src[4:]))
+	dst := append([]byte(nil), src[:headerSize+recordSize*n]...)
+	binary.BigEndian.PutUint16(dst[4:], uint16(n+1))
+	for i := 0; i < n; i++ {
+		o := dst[headerSize+recordSize*i+8:]
+		binary.BigEndian.PutUint32(o, binary.BigEndian.Uint32(o)+recordSize)
+	}
+	record := make([]byte, recordSize)
+	copy(record, "trak")
+	dst = append(dst, record...)
+	dst = append(dst, src[headerSize+recordSize*n:]...)
+	for len(dst)%4 != 0 {
+		dst = append(dst, 0)
+	}
+	binary.BigEndian.PutUint32(dst[headerSize+recordSize*n+8:], uint32(len(dst)))
+	binary.BigEndian.PutUint32(dst[headerSize+recordSize*n+12:], uint32(len(trak)))
+	return append(dst, trak...)
+}
+
+func TestFaceTracking(t *testing.T) {
+	f, err := sfnt.Parse(withTrakTable(goregular.TTF))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		size     float64
+		tracking bool
+		want     fixed.Int26_6
+	}{
+		{12, false, 0},
+		{12, true, 8}, // 20 * 12 * 64 / 2048 = 7.5.
+		{18, true, 6}, // 10 * 18 * 64 / 2048 = 5.625.
+		{24, true, 0},
+	} {
+		face, err := NewFace(f, &FaceOptions{Size: tc.size, DPI: 72, Tracking: tc.tracking})
+		if err != nil {
+			t.Fatalf("NewFace: %v", err)
+		}
+		if got := face.Kern('A', 'V'); got != tc.want {
+			t.Errorf("size=%v, tracking=%t: got %v, want %v", tc.size, tc.tracking, got, tc.want)
+		}
+	}
+}
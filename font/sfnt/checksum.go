@@ -0,0 +1,144 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sfnt
+
+import (
+	"fmt"
+)
+
+// checkSumAdjustmentMagic is the value that the checksum of a whole font,
+// including its head table's checkSumAdjustment, should be.
+const checkSumAdjustmentMagic = 0xb1b0afba
+
+// ChecksumError reports that a checksum in the font data does not match the
+// data that it covers.
+type ChecksumError struct {
+	// Table is the tag of the table whose checksum does not match, such as
+	// "glyf". It is empty if it is the head table's checkSumAdjustment, which
+	// covers the whole font, that does not match.
+	Table string
+	// Got is the checksum computed from the data and Want is the checksum
+	// recorded in the font. For the checkSumAdjustment, they are adjustment
+	// values.
+	Got, Want uint32
+}
+
+func (e *ChecksumError) Error() string {
+	if e.Table == "" {
+		return fmt.Sprintf("sfnt: invalid checkSumAdjustment: got 0x%08x, want 0x%08x", e.Got, e.Want)
+	}
+	return fmt.Sprintf("sfnt: invalid %q table checksum: got 0x%08x, want 0x%08x", e.Table, e.Got, e.Want)
+}
+
+// VerifyChecksums checks the checksum of every table in f's table directory,
+// and the head table's checkSumAdjustment, against the font data. It returns
+// a *ChecksumError for the first checksum that does not match.
+//
+// For a font in a TTC or OTC font collection, the checkSumAdjustment is not
+// checked, as it is not well defined for a font whose tables may be shared
+// with other fonts, and is often wrong in practice.
+//
+// Parsing a font does not check its checksums, as doing so reads all of the
+// font data.
+func (f *Font) VerifyChecksums(b *Buffer) error {
+	if b == nil {
+		b = &Buffer{}
+	}
+
+	// https://www.microsoft.com/typography/otspec/otff.htm "Calculating
+	// Checksums".
+	const headerSize, recordSize = 12, 16
+	offset := int(f.initialOffset)
+	buf, err := b.view(&f.src, offset, headerSize)
+	if err != nil {
+		return err
+	}
+	numTables := int(u16(buf[4:]))
+	fontSum, err := f.checksum(b, offset, headerSize+recordSize*numTables)
+	if err != nil {
+		return err
+	}
+	buf, err = b.view(&f.src, offset+headerSize, recordSize*numTables)
+	if err != nil {
+		return err
+	}
+	// Copy the table records, as checksum re-uses b's buffer.
+	records := append([]byte(nil), buf...)
+
+	hasHead, adjustment := false, uint32(0)
+	for ; len(records) > 0; records = records[recordSize:] {
+		tag, want := u32(records), u32(records[4:])
+		o, n := u32(records[8:]), u32(records[12:])
+		if f.cached.isDfont {
+			o += uint32(f.initialOffset)
+		}
+		got, err := f.checksum(b, int(o), int(n))
+		if err != nil {
+			return err
+		}
+		if tag == 0x68656164 { // "head".
+			// The head table's checksum is computed with a zero
+			// checkSumAdjustment, which is 4-byte aligned at offset 8.
+			if n < 12 {
+				return errInvalidHeadTable
+			}
+			buf, err := b.view(&f.src, int(o)+8, 4)
+			if err != nil {
+				return err
+			}
+			hasHead, adjustment = true, u32(buf)
+			got -= adjustment
+		}
+		if got != want {
+			return &ChecksumError{
+				Table: string([]byte{byte(tag >> 24), byte(tag >> 16), byte(tag >> 8), byte(tag)}),
+				Got:   got,
+				Want:  want,
+			}
+		}
+		fontSum += got
+	}
+
+	// A font in a collection has a non-zero initialOffset, as the
+	// collection's header comes first. That is also true of dfont
+	// collections, but their fonts are complete SFNT fonts.
+	inTTC := f.initialOffset != 0 && !f.cached.isDfont
+	if hasHead && !inTTC {
+		if got := checkSumAdjustmentMagic - fontSum; got != adjustment {
+			return &ChecksumError{Got: got, Want: adjustment}
+		}
+	}
+	return nil
+}
+
+// checksum returns the sum of the big-endian uint32 values in the length
+// bytes of f's source at the given offset, padded with zeroes to a multiple of
+// 4 bytes.
+func (f *Font) checksum(b *Buffer, offset, length int) (uint32, error) {
+	sum := uint32(0)
+	for length > 0 {
+		// Read at most 4096 bytes at a time, as WriteSourceTo does, so that
+		// the buffer stays small for an io.ReaderAt source.
+		n := length
+		if n > 4096 {
+			n = 4096
+		}
+		buf, err := b.view(&f.src, offset, n)
+		if err != nil {
+			return 0, err
+		}
+		for ; len(buf) >= 4; buf = buf[4:] {
+			sum += u32(buf)
+		}
+		if len(buf) > 0 {
+			var pad [4]byte
+			copy(pad[:], buf)
+			sum += u32(pad[:])
+		}
+		offset += n
+		length -= n
+	}
+	return sum, nil
+}
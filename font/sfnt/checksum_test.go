@@ -0,0 +1,140 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sfnt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// tableOffset returns the offset of the table with the given tag in the font
+// data src.
+func tableOffset(t *testing.T, src []byte, tag string) int {
+	t.Helper()
+	numTables := int(binary.BigEndian.Uint16(src[4:]))
+	for i := 0; i < numTables; i++ {
+		r := src[12+16*i:]
+		if string(r[:4]) == tag {
+			return int(binary.BigEndian.Uint32(r[8:]))
+		}
+	}
+	t.Fatalf("table %q not found", tag)
+	return 0
+}
+
+// ttcOf returns a TTC font collection that holds only the font data src.
+func ttcOf(t *testing.T, src []byte) []byte {
+	t.Helper()
+	const headerSize = 16
+	ttc := make([]byte, headerSize, headerSize+len(src))
+	copy(ttc, "ttcf\x00\x01\x00\x00\x00\x00\x00\x01")
+	binary.BigEndian.PutUint32(ttc[12:], headerSize)
+	ttc = append(ttc, src...)
+	// The table offsets are relative to the start of the collection.
+	numTables := int(binary.BigEndian.Uint16(src[4:]))
+	for i := 0; i < numTables; i++ {
+		r := ttc[headerSize+12+16*i:]
+		binary.BigEndian.PutUint32(r[8:], binary.BigEndian.Uint32(r[8:])+headerSize)
+	}
+	return ttc
+}
+
+func TestVerifyChecksums(t *testing.T) {
+	fonts := map[string][]byte{"goregular": goregular.TTF}
+	for _, name := range []string{"CFFTest.otf", "cmapTest.ttf", "glyfTest.ttf"} {
+		src, err := os.ReadFile(filepath.FromSlash("../testdata/" + name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		fonts[name] = src
+	}
+
+	for name, src := range fonts {
+		// Parse does not read the name table, so corrupting it does not
+		// make Parse fail.
+		corruptTable := append([]byte(nil), src...)
+		corruptTable[tableOffset(t, src, "name")+20]++
+		corruptAdjustment := append([]byte(nil), src...)
+		corruptAdjustment[tableOffset(t, src, "head")+11]++
+
+		testCases := []struct {
+			desc  string
+			src   []byte
+			table string
+			ok    bool
+		}{
+			{"original", src, "", true},
+			{"corrupt name", corruptTable, "name", false},
+			{"corrupt checkSumAdjustment", corruptAdjustment, "", false},
+		}
+		for _, tc := range testCases {
+			for _, readerAt := range []bool{false, true} {
+				var f *Font
+				var err error
+				if readerAt {
+					f, err = ParseReaderAt(bytes.NewReader(tc.src))
+				} else {
+					f, err = Parse(tc.src)
+				}
+				if err != nil {
+					t.Fatalf("%s: %s: Parse: %v", name, tc.desc, err)
+				}
+				err = f.VerifyChecksums(nil)
+				if tc.ok {
+					if err != nil {
+						t.Errorf("%s: %s, readerAt=%t: %v", name, tc.desc, readerAt, err)
+					}
+					continue
+				}
+				var ce *ChecksumError
+				if !errors.As(err, &ce) {
+					t.Errorf("%s: %s, readerAt=%t: got %v, want a *ChecksumError", name, tc.desc, readerAt, err)
+					continue
+				}
+				if ce.Table != tc.table {
+					t.Errorf("%s: %s, readerAt=%t: got table %q, want %q", name, tc.desc, readerAt, ce.Table, tc.table)
+				}
+			}
+		}
+	}
+}
+
+func TestVerifyChecksumsCollection(t *testing.T) {
+	src := ttcOf(t, goregular.TTF)
+	// The table offsets, and so the whole font's checksum, differ from those
+	// of goregular.TTF, so its checkSumAdjustment does not match, but it is
+	// not checked for a font in a collection.
+	c, err := ParseCollection(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := c.Font(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.VerifyChecksums(nil); err != nil {
+		t.Errorf("original: %v", err)
+	}
+
+	// The tables' checksums are still checked.
+	src[tableOffset(t, src[16:], "name")+20]++
+	c, err = ParseCollection(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f, err = c.Font(0); err != nil {
+		t.Fatal(err)
+	}
+	var ce *ChecksumError
+	if err := f.VerifyChecksums(nil); !errors.As(err, &ce) || ce.Table != "name" {
+		t.Errorf("corrupt name: got %v, want a name table *ChecksumError", err)
+	}
+}
@@ -5,7 +5,16 @@
 package sfnt
 
 import (
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
 	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
 )
 
 // Platform IDs and Platform Specific IDs as per
@@ -21,11 +30,19 @@ const (
 	// for the Unicode Platform ID (value 0). See
 	// https://github.com/fontforge/fontforge/issues/2728
 
-	psidMacintoshRoman = 0
-
-	psidWindowsSymbol = 0
-	psidWindowsUCS2   = 1
-	psidWindowsUCS4   = 10
+	psidMacintoshRoman              = 0
+	psidMacintoshJapanese           = 1
+	psidMacintoshChineseTraditional = 2
+	psidMacintoshKorean             = 3
+	psidMacintoshChineseSimplified  = 25
+
+	psidWindowsSymbol   = 0
+	psidWindowsUCS2     = 1
+	psidWindowsShiftJIS = 2
+	psidWindowsPRC      = 3
+	psidWindowsBig5     = 4
+	psidWindowsWansung  = 5
+	psidWindowsUCS4     = 10
 )
 
 // platformEncodingWidth returns the number of bytes per character assumed by
@@ -42,7 +59,14 @@ const (
 // the legacy encodings if e.g. their repertoire is limited to the BMP, for
 // greater compatibility with older software, or because the resultant file
 // size can be smaller.
+//
+// Legacy CJK fonts may only have a cmap for a multi-byte encoding such as
+// Shift-JIS, which takes up to 2 bytes per character. See legacyEncoding.
 func platformEncodingWidth(pid, psid uint16) int {
+	if legacyEncoding(pid, psid) != nil {
+		return 2
+	}
+
 	switch pid {
 	case pidUnicode:
 		switch psid {
@@ -71,6 +95,42 @@ func platformEncodingWidth(pid, psid uint16) int {
 	return 0
 }
 
+// legacyEncoding returns the multi-byte character encoding assumed by the
+// given Platform ID and Platform Specific ID, or nil if they do not specify
+// such an encoding. Runes are converted to that encoding before being looked
+// up in the cmap subtable, which must be in format 2.
+//
+// The Macintosh encodings are approximated by their more common Windows
+// counterparts.
+func legacyEncoding(pid, psid uint16) encoding.Encoding {
+	switch pid {
+	case pidMacintosh:
+		switch psid {
+		case psidMacintoshJapanese:
+			return japanese.ShiftJIS
+		case psidMacintoshChineseTraditional:
+			return traditionalchinese.Big5
+		case psidMacintoshKorean:
+			return korean.EUCKR
+		case psidMacintoshChineseSimplified:
+			return simplifiedchinese.GBK
+		}
+
+	case pidWindows:
+		switch psid {
+		case psidWindowsShiftJIS:
+			return japanese.ShiftJIS
+		case psidWindowsPRC:
+			return simplifiedchinese.GBK
+		case psidWindowsBig5:
+			return traditionalchinese.Big5
+		case psidWindowsWansung:
+			return korean.EUCKR
+		}
+	}
+	return nil
+}
+
 // The various cmap formats are described at
 // https://www.microsoft.com/typography/otspec/cmap.htm
 
@@ -78,24 +138,32 @@ var supportedCmapFormat = func(format, pid, psid uint16) bool {
 	switch format {
 	case 0:
 		return pid == pidMacintosh && psid == psidMacintoshRoman
+	case 2:
+		return legacyEncoding(pid, psid) != nil
 	case 4:
-		return true
+		return legacyEncoding(pid, psid) == nil
 	case 6:
-		return true
+		return legacyEncoding(pid, psid) == nil
+	case 8:
+		return legacyEncoding(pid, psid) == nil
 	case 12:
-		return true
+		return legacyEncoding(pid, psid) == nil
 	}
 	return false
 }
 
-func (f *Font) makeCachedGlyphIndex(buf []byte, offset, length uint32, format uint16) ([]byte, glyphIndexFunc, error) {
+func (f *Font) makeCachedGlyphIndex(buf []byte, offset, length uint32, format, pid, psid uint16) ([]byte, glyphIndexFunc, error) {
 	switch format {
 	case 0:
 		return f.makeCachedGlyphIndexFormat0(buf, offset, length)
+	case 2:
+		return f.makeCachedGlyphIndexFormat2(buf, offset, length, legacyEncoding(pid, psid))
 	case 4:
 		return f.makeCachedGlyphIndexFormat4(buf, offset, length)
 	case 6:
 		return f.makeCachedGlyphIndexFormat6(buf, offset, length)
+	case 8:
+		return f.makeCachedGlyphIndexFormat8(buf, offset, length)
 	case 12:
 		return f.makeCachedGlyphIndexFormat12(buf, offset, length)
 	}
@@ -123,6 +191,95 @@ func (f *Font) makeCachedGlyphIndexFormat0(buf []byte, offset, length uint32) ([
 	}, nil
 }
 
+func (f *Font) makeCachedGlyphIndexFormat2(buf []byte, offset, length uint32, enc encoding.Encoding) ([]byte, glyphIndexFunc, error) {
+	const headerSize, subHeaderSize = 6 + 2*256, 8
+	if length < headerSize || offset+length > f.cmap.length {
+		return nil, nil, errInvalidCmapTable
+	}
+	var err error
+	buf, err = f.src.view(buf, int(f.cmap.offset+offset), headerSize)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Each subHeaderKeys value is 8 times an index into the subHeaders
+	// array. A zero value means that the high byte is a single-byte
+	// character, and that subHeaders[0] maps it.
+	var keys [256]uint16
+	numSubHeaders := uint32(0)
+	for i := range keys {
+		k := u16(buf[6+2*i:])
+		if k%subHeaderSize != 0 {
+			return nil, nil, errInvalidCmapTable
+		}
+		keys[i] = k / subHeaderSize
+		if n := uint32(keys[i]) + 1; numSubHeaders < n {
+			numSubHeaders = n
+		}
+	}
+
+	eLength := subHeaderSize * numSubHeaders
+	if headerSize+eLength > length {
+		return nil, nil, errInvalidCmapTable
+	}
+	buf, err = f.src.view(buf, int(f.cmap.offset+offset+headerSize), int(eLength))
+	if err != nil {
+		return nil, nil, err
+	}
+	entries := make([]cmapEntry2, numSubHeaders)
+	for i := range entries {
+		entries[i] = cmapEntry2{
+			firstCode:  u16(buf[0+8*i:]),
+			entryCount: u16(buf[2+8*i:]),
+			delta:      u16(buf[4+8*i:]),
+			offset:     u16(buf[6+8*i:]),
+		}
+	}
+	subtableBase := f.cmap.offset + offset
+
+	return buf, func(f *Font, b *Buffer, r rune) (GlyphIndex, error) {
+		var src, dst [utf8.UTFMax]byte
+		nSrc := utf8.EncodeRune(src[:], r)
+		nDst, _, err := enc.NewEncoder().Transform(dst[:], src[:nSrc], true)
+		if err != nil {
+			// The source rune r is not representable in the encoding.
+			return 0, nil
+		}
+
+		var h int
+		var lo uint16
+		switch {
+		case nDst == 1 && keys[dst[0]] == 0:
+			h, lo = 0, uint16(dst[0])
+		case nDst == 2 && keys[dst[0]] != 0:
+			h, lo = int(keys[dst[0]]), uint16(dst[1])
+		default:
+			return 0, nil
+		}
+		entry := &entries[h]
+		if lo < entry.firstCode || lo-entry.firstCode >= entry.entryCount {
+			return 0, nil
+		}
+
+		// The idRangeOffset is relative to its own position in the subtable.
+		o := headerSize + subHeaderSize*uint32(h) + 6 + uint32(entry.offset) + 2*uint32(lo-entry.firstCode)
+		if o+2 > length {
+			return 0, errInvalidCmapTable
+		}
+		if b == nil {
+			b = &Buffer{}
+		}
+		x, err := b.view(&f.src, int(subtableBase+o), 2)
+		if err != nil {
+			return 0, err
+		}
+		if g := u16(x); g != 0 {
+			return GlyphIndex(g + entry.delta), nil
+		}
+		return 0, nil
+	}, nil
+}
+
 func (f *Font) makeCachedGlyphIndexFormat4(buf []byte, offset, length uint32) ([]byte, glyphIndexFunc, error) {
 	const headerSize = 14
 	if offset+headerSize > f.cmap.length {
@@ -246,6 +403,67 @@ func (f *Font) makeCachedGlyphIndexFormat6(buf []byte, offset, length uint32) ([
 	}, nil
 }
 
+func (f *Font) makeCachedGlyphIndexFormat8(buf []byte, offset, _ uint32) ([]byte, glyphIndexFunc, error) {
+	// The header is followed by the 8192-byte is32 array, which says which
+	// 16-bit values start a 32-bit character code. It is not needed to look
+	// up a given rune, so only the numGroups field after it is read.
+	const headerSize = 12 + 8192 + 4
+	if offset+headerSize > f.cmap.length {
+		return nil, nil, errInvalidCmapTable
+	}
+	var err error
+	buf, err = f.src.view(buf, int(f.cmap.offset+offset), 12)
+	if err != nil {
+		return nil, nil, err
+	}
+	length := u32(buf[4:])
+	if f.cmap.length < offset || length > f.cmap.length-offset {
+		return nil, nil, errInvalidCmapTable
+	}
+	buf, err = f.src.view(buf, int(f.cmap.offset+offset+headerSize-4), 4)
+	if err != nil {
+		return nil, nil, err
+	}
+	offset += headerSize
+
+	numGroups := u32(buf)
+	if numGroups > maxCmapSegments {
+		return nil, nil, errUnsupportedNumberOfCmapSegments
+	}
+
+	eLength := 12 * numGroups
+	if headerSize+eLength != length {
+		return nil, nil, errInvalidCmapTable
+	}
+	buf, err = f.src.view(buf, int(f.cmap.offset+offset), int(eLength))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entries := make([]cmapEntry32, numGroups)
+	for i := range entries {
+		entries[i] = cmapEntry32{
+			start: u32(buf[0+12*i:]),
+			end:   u32(buf[4+12*i:]),
+			delta: u32(buf[8+12*i:]),
+		}
+	}
+
+	return buf, func(f *Font, b *Buffer, r rune) (GlyphIndex, error) {
+		// Format 8 is for UTF-16: a 32-bit character code is a surrogate
+		// pair, with the high surrogate in the high 16 bits.
+		c := uint32(r)
+		if r > 0xffff {
+			if r > unicode.MaxRune {
+				return 0, nil
+			}
+			r1, r2 := utf16.EncodeRune(r)
+			c = uint32(r1)<<16 | uint32(r2)
+		}
+		return lookupCmapEntry32(entries, c), nil
+	}, nil
+}
+
 func (f *Font) makeCachedGlyphIndexFormat12(buf []byte, offset, _ uint32) ([]byte, glyphIndexFunc, error) {
 	const headerSize = 16
 	if offset+headerSize > f.cmap.length {
@@ -287,22 +505,31 @@ func (f *Font) makeCachedGlyphIndexFormat12(buf []byte, offset, _ uint32) ([]byt
 	}
 
 	return buf, func(f *Font, b *Buffer, r rune) (GlyphIndex, error) {
-		c := uint32(r)
-		for i, j := 0, len(entries); i < j; {
-			h := i + (j-i)/2
-			entry := &entries[h]
-			if c < entry.start {
-				j = h
-			} else if entry.end < c {
-				i = h + 1
-			} else {
-				return GlyphIndex(c - entry.start + entry.delta), nil
-			}
-		}
-		return 0, nil
+		return lookupCmapEntry32(entries, uint32(r)), nil
 	}, nil
 }
 
+// lookupCmapEntry32 returns the glyph index for the character code c, given
+// the sorted groups of a format 8 or format 12 subtable.
+func lookupCmapEntry32(entries []cmapEntry32, c uint32) GlyphIndex {
+	for i, j := 0, len(entries); i < j; {
+		h := i + (j-i)/2
+		entry := &entries[h]
+		if c < entry.start {
+			j = h
+		} else if entry.end < c {
+			i = h + 1
+		} else {
+			return GlyphIndex(c - entry.start + entry.delta)
+		}
+	}
+	return 0
+}
+
+type cmapEntry2 struct {
+	firstCode, entryCount, delta, offset uint16
+}
+
 type cmapEntry16 struct {
 	end, start, delta, offset uint16
 }
@@ -310,3 +537,329 @@ type cmapEntry16 struct {
 type cmapEntry32 struct {
 	start, end, delta uint32
 }
+
+// cmapSubtable is the index of the encoding record, the location, relative
+// to the cmap table, and the encoding of the cmap subtable that a Font uses.
+type cmapSubtable struct {
+	index             int
+	offset, length    uint32
+	format, pid, psid uint16
+	language          uint32
+}
+
+func (t cmapSubtable) isSymbol() bool {
+	return t.pid == pidWindows && t.psid == psidWindowsSymbol
+}
+
+func (t cmapSubtable) export() CmapSubtable {
+	return CmapSubtable{
+		Index:      t.index,
+		PlatformID: t.pid,
+		EncodingID: t.psid,
+		Format:     t.format,
+		Language:   t.language,
+		Supported:  platformEncodingWidth(t.pid, t.psid) != 0 && supportedCmapFormat(t.format, t.pid, t.psid),
+	}
+}
+
+// CmapSubtable describes one of the subtables of a font's cmap table, each of
+// which maps the character codes of one encoding to glyph indexes.
+type CmapSubtable struct {
+	// Index is the position of the subtable's encoding record in the cmap
+	// table, as passed to Font.SelectCmapSubtable.
+	Index int
+
+	// PlatformID and EncodingID identify the subtable's encoding, such as 3
+	// and 1 for Unicode's Basic Multilingual Plane on the Windows platform,
+	// or 0 and 3 for the same on the Unicode platform.
+	PlatformID, EncodingID uint16
+
+	// Format is the subtable's format, such as 4 or 12.
+	Format uint16
+
+	// Language is the subtable's language field. It is only meaningful for
+	// the Macintosh platform, whose subtables may be specific to a language.
+	// There, it is one more than a Macintosh language code, or zero for a
+	// subtable that is not specific to a language.
+	Language uint32
+
+	// Supported is whether this package can map runes with the subtable.
+	Supported bool
+}
+
+// CmapSubtable returns the cmap subtable that f uses to map runes to glyph
+// indexes. By default, that is the subtable with the widest supported
+// encoding, preferring Unicode over legacy and symbol encodings, and earlier
+// subtables over later ones. SelectCmapSubtable changes it.
+func (f *Font) CmapSubtable() CmapSubtable {
+	return f.cached.cmapSubtable.export()
+}
+
+// CmapSubtables returns all of the subtables of f's cmap table, in the order
+// of their encoding records.
+func (f *Font) CmapSubtables(b *Buffer) ([]CmapSubtable, error) {
+	if b == nil {
+		b = &Buffer{}
+	}
+	n, err := f.numCmapSubtables(b)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]CmapSubtable, n)
+	for i := range ret {
+		t, err := f.viewCmapSubtable(b, i)
+		if err != nil {
+			return nil, err
+		}
+		ret[i] = t.export()
+	}
+	return ret, nil
+}
+
+// SelectCmapSubtable makes f use the cmap subtable with the given index to
+// map runes to glyph indexes, instead of the one it chose. It is useful for
+// fonts whose subtables disagree, such as those whose Unicode platform
+// subtable maps some runes differently from their Windows platform one, or
+// whose Macintosh subtables are for different languages. It returns an error
+// if the subtable is not Supported.
+//
+// SelectCmapSubtable modifies f, so it must not be called concurrently with
+// other Font methods.
+func (f *Font) SelectCmapSubtable(b *Buffer, index int) error {
+	if b == nil {
+		b = &Buffer{}
+	}
+	n, err := f.numCmapSubtables(b)
+	if err != nil {
+		return err
+	}
+	if index < 0 || n <= index {
+		return errInvalidCmapSubtable
+	}
+	t, err := f.viewCmapSubtable(b, index)
+	if err != nil {
+		return err
+	}
+	if !t.export().Supported {
+		return errUnsupportedCmapEncodings
+	}
+	buf, glyphIndex, err := f.makeCachedGlyphIndex(b.buf, t.offset, t.length, t.format, t.pid, t.psid)
+	if err != nil {
+		return err
+	}
+	if f.src.viewBufferWritable() {
+		b.buf = buf
+	}
+	f.cached.cmapSubtable = t
+	f.cached.glyphIndex = glyphIndex
+	f.cached.isSymbol = t.isSymbol()
+	return nil
+}
+
+// numCmapSubtables returns the number of encoding records in f's cmap table,
+// which was validated by parseCmap.
+func (f *Font) numCmapSubtables(b *Buffer) (int, error) {
+	buf, err := b.view(&f.src, int(f.cmap.offset), 4)
+	if err != nil {
+		return 0, err
+	}
+	return int(u16(buf[2:])), nil
+}
+
+// viewCmapSubtable returns the cmap subtable whose encoding record has the
+// given index.
+func (f *Font) viewCmapSubtable(b *Buffer, index int) (cmapSubtable, error) {
+	const headerSize, entrySize = 4, 8
+	buf, err := b.view(&f.src, int(f.cmap.offset)+headerSize+entrySize*index, entrySize)
+	if err != nil {
+		return cmapSubtable{}, err
+	}
+	t := cmapSubtable{
+		index:  index,
+		offset: u32(buf[4:]),
+		pid:    u16(buf),
+		psid:   u16(buf[2:]),
+	}
+	if t.offset > f.cmap.length-4 {
+		return cmapSubtable{}, errInvalidCmapTable
+	}
+	buf, err = b.view(&f.src, int(f.cmap.offset+t.offset), 4)
+	if err != nil {
+		return cmapSubtable{}, err
+	}
+	t.format = u16(buf)
+	t.length = uint32(u16(buf[2:]))
+	buf, t.language, err = f.cmapLanguage(b.buf, t.offset, t.format)
+	if err != nil {
+		return cmapSubtable{}, err
+	}
+	if f.src.viewBufferWritable() {
+		b.buf = buf
+	}
+	return t, nil
+}
+
+// cmapLanguage returns the language field of the cmap subtable of the given
+// format at the given offset, relative to the cmap table. Formats without a
+// language field, such as format 14, have a zero language.
+func (f *Font) cmapLanguage(buf []byte, offset uint32, format uint16) ([]byte, uint32, error) {
+	// Formats 0 to 6 have 16-bit length and language fields, and the later
+	// formats have 32-bit ones, after a reserved field.
+	n := 0
+	switch format {
+	case 0, 2, 4, 6:
+		n = 6
+	case 8, 10, 12, 13:
+		n = 12
+	default:
+		return buf, 0, nil
+	}
+	if f.cmap.length < uint32(n) || offset > f.cmap.length-uint32(n) {
+		return nil, 0, errInvalidCmapTable
+	}
+	buf, err := f.src.view(buf, int(f.cmap.offset+offset), n)
+	if err != nil {
+		return nil, 0, err
+	}
+	if n == 6 {
+		return buf, uint32(u16(buf[4:])), nil
+	}
+	return buf, u32(buf[8:]), nil
+}
+
+// allRunesFormat4 implements Font.AllRunes for a format 4 subtable, walking
+// its segments instead of looking up each rune.
+func (f *Font) allRunesFormat4(b *Buffer, fn func(r rune, x GlyphIndex) bool) error {
+	// The subtable was validated by makeCachedGlyphIndexFormat4.
+	const headerSize = 14
+	offset := f.cached.cmapSubtable.offset
+	buf, err := b.view(&f.src, int(f.cmap.offset+offset), headerSize)
+	if err != nil {
+		return err
+	}
+	offset += headerSize
+	segCount := u16(buf[6:]) / 2
+	eLength := 8*uint32(segCount) + 2
+	buf, err = b.view(&f.src, int(f.cmap.offset+offset), int(eLength))
+	if err != nil {
+		return err
+	}
+	offset += eLength
+
+	entries := make([]cmapEntry16, segCount)
+	for i := range entries {
+		entries[i] = cmapEntry16{
+			end:    u16(buf[0*len(entries)+0+2*i:]),
+			start:  u16(buf[2*len(entries)+2+2*i:]),
+			delta:  u16(buf[4*len(entries)+2+2*i:]),
+			offset: u16(buf[6*len(entries)+2+2*i:]),
+		}
+	}
+	indexesBase := f.cmap.offset + offset
+	indexesLength := f.cmap.length - offset
+
+	// next is the smallest rune that has not yet been passed to fn. It skips
+	// the runes of any overlapping segments, which Font.GlyphIndex's binary
+	// search would not find.
+	next := rune(0)
+	for h, entry := range entries {
+		if rune(entry.end) < next || entry.end < entry.start {
+			continue
+		}
+		start := entry.start
+		if rune(start) < next {
+			start = uint16(next)
+		}
+		var indexes []byte
+		if entry.offset != 0 {
+			o := uint32(entry.offset) + 2*uint32(h-len(entries)+int(start-entry.start))
+			n := 2 * (uint32(entry.end-start) + 1)
+			if o > indexesLength || n > indexesLength-o {
+				return errInvalidCmapTable
+			}
+			indexes, err = b.view(&f.src, int(indexesBase+o), int(n))
+			if err != nil {
+				return err
+			}
+		}
+		for c := start; ; c++ {
+			x := GlyphIndex(c + entry.delta)
+			if indexes != nil {
+				x = GlyphIndex(u16(indexes[2*(c-start):]))
+			}
+			if x != 0 && utf8.ValidRune(rune(c)) && !fn(rune(c), x) {
+				return nil
+			}
+			if c == entry.end {
+				break
+			}
+		}
+		next = rune(entry.end) + 1
+	}
+	return nil
+}
+
+// allRunesFormat12 implements Font.AllRunes for a format 12 subtable, walking
+// its groups instead of looking up each rune.
+func (f *Font) allRunesFormat12(b *Buffer, fn func(r rune, x GlyphIndex) bool) error {
+	// The subtable was validated by makeCachedGlyphIndexFormat12.
+	const headerSize = 16
+	offset := f.cached.cmapSubtable.offset
+	buf, err := b.view(&f.src, int(f.cmap.offset+offset), headerSize)
+	if err != nil {
+		return err
+	}
+	numGroups := u32(buf[12:])
+	buf, err = b.view(&f.src, int(f.cmap.offset+offset+headerSize), int(12*numGroups))
+	if err != nil {
+		return err
+	}
+
+	next := uint32(0)
+	for ; len(buf) >= 12; buf = buf[12:] {
+		start, end, delta := u32(buf[0:]), u32(buf[4:]), u32(buf[8:])
+		if end > unicode.MaxRune {
+			end = unicode.MaxRune
+		}
+		if end < next || end < start {
+			continue
+		}
+		c := start
+		if c < next {
+			c = next
+		}
+		for ; c <= end; c++ {
+			x := GlyphIndex(c - start + delta)
+			if x != 0 && utf8.ValidRune(rune(c)) && !fn(rune(c), x) {
+				return nil
+			}
+		}
+		next = end + 1
+	}
+	return nil
+}
+
+// allRunesProbe implements Font.AllRunes for the other subtable formats, by
+// looking up each rune that they can map.
+func (f *Font) allRunesProbe(b *Buffer, fn func(r rune, x GlyphIndex) bool) error {
+	// Only format 8 has character codes outside of the Basic Multilingual
+	// Plane. The lookups for some other formats truncate runes to 16 bits,
+	// so looking up larger runes would give false positives.
+	max := rune(0xffff)
+	if f.cached.cmapSubtable.format == 8 {
+		max = unicode.MaxRune
+	}
+	for r := rune(0); r <= max; r++ {
+		if !utf8.ValidRune(r) {
+			continue
+		}
+		x, err := f.cached.glyphIndex(f, b, r)
+		if err != nil {
+			return err
+		}
+		if x != 0 && !fn(r, x) {
+			return nil
+		}
+	}
+	return nil
+}
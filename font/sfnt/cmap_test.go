@@ -0,0 +1,372 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sfnt
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// withCmap returns a copy of the font data src with its cmap table replaced.
+func withCmap(t *testing.T, src []byte, cmap []byte) []byte {
+	t.Helper()
+	const headerSize, recordSize = 12, 16
+	dst := append([]byte(nil), src...)
+	numTables := int(binary.BigEndian.Uint16(dst[4:]))
+	for i := 0; i < numTables; i++ {
+		r := dst[headerSize+recordSize*i:]
+		if string(r[:4]) == "cmap" {
+			// Renaming the old table keeps the table records sorted.
+			copy(r, "cmaq")
+			return withTable(t, dst, "cmap", cmap)
+		}
+	}
+	t.Fatal("no cmap table")
+	return nil
+}
+
+// cmapTable returns a cmap table with the given encoding records, each of
+// which is a Platform ID, a Platform Specific ID and a subtable.
+func cmapTable(records ...interface{}) []byte {
+	n := len(records) / 3
+	b := be16(0, uint16(n))
+	var subtables []byte
+	for i := 0; i < n; i++ {
+		b = append(b, be16(records[3*i].(uint16), records[3*i+1].(uint16))...)
+		offset := uint32(4 + 8*n + len(subtables))
+		b = append(b, be16(uint16(offset>>16), uint16(offset))...)
+		subtables = append(subtables, records[3*i+2].([]byte)...)
+	}
+	return append(b, subtables...)
+}
+
+// testCmapFormat2 is a format 2 subtable, for Shift-JIS, that maps the
+// single-byte characters from 0x20 to 0x7e, and the two-byte characters from
+// 0x829f to 0x82a1.
+var testCmapFormat2 = func() []byte {
+	var keys [256]uint16
+	keys[0x82] = 8
+	glyphs0 := make([]uint16, 0x5f)
+	for i := range glyphs0 {
+		glyphs0[i] = uint16(i + 3)
+	}
+	return concat(
+		// Header: format, length, language.
+		be16(2, 730, 0),
+		be16(keys[:]...),
+		// SubHeaders: firstCode, entryCount, idDelta, idRangeOffset.
+		be16(0x20, 0x5f, 0, 10),
+		be16(0x9f, 3, 100, 192),
+		be16(glyphs0...),
+		be16(1, 0, 2),
+	)
+}()
+
+// testCmapFormat8 is a format 8 subtable that maps U+0020 to U+007E, and
+// U+1F600 and U+1F601, encoded as UTF-16 surrogate pairs.
+var testCmapFormat8 = func() []byte {
+	b := concat(
+		// Header: format, reserved, length, language.
+		be16(8, 0, 0, 8232, 0, 0),
+		make([]byte, 8192),
+		// numGroups.
+		be16(0, 2),
+		// Groups: startCharCode, endCharCode, startGlyphID.
+		be16(0, 0x20, 0, 0x7e, 0, 3),
+		be16(0xd83d, 0xde00, 0xd83d, 0xde01, 0, 200),
+	)
+	b[12+0xd83d/8] |= 0x80 >> (0xd83d % 8)
+	return b
+}()
+
+// testCmapFormat6 is a format 6 subtable that maps U+0041 and U+0042.
+var testCmapFormat6 = be16(6, 14, 0, 0x41, 2, 50, 51)
+
+func TestCmapFormats(t *testing.T) {
+	testCases := []struct {
+		name string
+		cmap []byte
+		want map[rune]GlyphIndex
+	}{{
+		name: "format 2",
+		cmap: cmapTable(uint16(pidWindows), uint16(psidWindowsShiftJIS), testCmapFormat2),
+		want: map[rune]GlyphIndex{
+			' ':          3,
+			'A':          36,
+			'~':          97,
+			'\u00e9':     0,
+			'\u3041':     101, // HIRAGANA LETTER SMALL A is 0x829f.
+			'\u3042':     0,   // Mapped to glyph 0 by the glyphIdArray.
+			'\u3043':     102,
+			'\u3044':     0, // Past the end of the subHeader's range.
+			'\u4e9c':     0, // 0x889f has no subHeader.
+			'\U0001f600': 0,
+		},
+	}, {
+		name: "format 8",
+		cmap: cmapTable(uint16(pidWindows), uint16(psidWindowsUCS4), testCmapFormat8),
+		want: map[rune]GlyphIndex{
+			' ':          3,
+			'A':          36,
+			'\u00e9':     0,
+			'\U0001f5ff': 0,
+			'\U0001f600': 200,
+			'\U0001f601': 201,
+			'\U0001f602': 0,
+			'\U0010ffff': 0,
+		},
+	}, {
+		name: "Unicode preferred over Shift-JIS",
+		cmap: cmapTable(
+			uint16(pidMacintosh), uint16(psidMacintoshJapanese), testCmapFormat2,
+			uint16(pidWindows), uint16(psidWindowsUCS2), testCmapFormat6,
+		),
+		want: map[rune]GlyphIndex{
+			' ':      0,
+			'A':      50,
+			'B':      51,
+			'\u3041': 0,
+		},
+	}}
+
+	for _, tc := range testCases {
+		f, err := Parse(withCmap(t, goregular.TTF, tc.cmap))
+		if err != nil {
+			t.Errorf("%s: Parse: %v", tc.name, err)
+			continue
+		}
+		var b Buffer
+		for r, want := range tc.want {
+			got, err := f.GlyphIndex(&b, r)
+			if err != nil {
+				t.Errorf("%s: r=%q: %v", tc.name, r, err)
+				continue
+			}
+			if got != want {
+				t.Errorf("%s: r=%q: got %d, want %d", tc.name, r, got, want)
+			}
+		}
+	}
+}
+
+func TestSymbolEncoded(t *testing.T) {
+	// These format 6 subtables map two character codes, starting at U+F041
+	// and U+0041.
+	pua := be16(6, 14, 0, 0xf041, 2, 50, 51)
+	ascii := be16(6, 14, 0, 0x41, 2, 70, 71)
+
+	testCases := []struct {
+		name       string
+		cmap       []byte
+		wantSymbol bool
+		r          rune
+		want       GlyphIndex
+		wantRaw    GlyphIndex
+	}{
+		{"PUA", cmapTable(uint16(pidWindows), uint16(psidWindowsSymbol), pua), true, 'A', 50, 0},
+		{"PUA", cmapTable(uint16(pidWindows), uint16(psidWindowsSymbol), pua), true, 'C', 0, 0},
+		{"PUA", cmapTable(uint16(pidWindows), uint16(psidWindowsSymbol), pua), true, '\uf042', 51, 51},
+		{"ASCII", cmapTable(uint16(pidWindows), uint16(psidWindowsSymbol), ascii), true, 'B', 71, 71},
+		{"ASCII", cmapTable(uint16(pidWindows), uint16(psidWindowsSymbol), ascii), true, '\uf042', 0, 0},
+		{
+			"Unicode preferred over symbol",
+			cmapTable(
+				uint16(pidWindows), uint16(psidWindowsSymbol), pua,
+				uint16(pidWindows), uint16(psidWindowsUCS2), ascii,
+			),
+			false, 'A', 70, 70,
+		},
+	}
+
+	for _, tc := range testCases {
+		f, err := Parse(withCmap(t, goregular.TTF, tc.cmap))
+		if err != nil {
+			t.Errorf("%s: Parse: %v", tc.name, err)
+			continue
+		}
+		if got := f.IsSymbolEncoded(); got != tc.wantSymbol {
+			t.Errorf("%s: IsSymbolEncoded: got %t, want %t", tc.name, got, tc.wantSymbol)
+		}
+		var b Buffer
+		if got, err := f.GlyphIndex(&b, tc.r); err != nil || got != tc.want {
+			t.Errorf("%s: GlyphIndex(%q): got %d, %v, want %d, nil", tc.name, tc.r, got, err, tc.want)
+		}
+		if got, err := f.RawGlyphIndex(&b, tc.r); err != nil || got != tc.wantRaw {
+			t.Errorf("%s: RawGlyphIndex(%q): got %d, %v, want %d, nil", tc.name, tc.r, got, err, tc.wantRaw)
+		}
+	}
+
+	f, err := Parse(goregular.TTF)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if f.IsSymbolEncoded() {
+		t.Errorf("goregular: IsSymbolEncoded: got true, want false")
+	}
+}
+
+func TestAllRunes(t *testing.T) {
+	// testCmapFormat4 maps U+0041 and U+0043 through its glyphIdArray, and
+	// U+0100 to U+0102 through its idDelta.
+	testCmapFormat4 := concat(
+		// Header: format, length, language, segCountX2, searchRange,
+		// entrySelector, rangeShift.
+		be16(4, 46, 0, 6, 4, 1, 2),
+		// endCode, reservedPad, startCode, idDelta, idRangeOffset.
+		be16(0x43, 0x102, 0xffff), be16(0),
+		be16(0x41, 0x100, 0xffff),
+		be16(0, 10, 1),
+		be16(6, 0, 0),
+		// glyphIdArray.
+		be16(5, 0, 7),
+	)
+	// testCmapFormat12 has a group whose endCharCode is past unicode.MaxRune.
+	testCmapFormat12 := concat(
+		// Header: format, reserved, length, language, numGroups.
+		be16(12, 0, 0, 52, 0, 0, 0, 3),
+		// Groups: startCharCode, endCharCode, startGlyphID.
+		be16(0, 0x41, 0, 0x42, 0, 3),
+		be16(1, 0xf600, 1, 0xf601, 0, 200),
+		be16(0x10, 0xfffe, 0xffff, 0xffff, 0, 300),
+	)
+
+	testCases := []struct {
+		name string
+		src  []byte
+		want map[rune]GlyphIndex
+	}{
+		{"goregular", goregular.TTF, nil},
+		{"format 4", withCmap(t, goregular.TTF, cmapTable(uint16(pidWindows), uint16(psidWindowsUCS2), testCmapFormat4)), map[rune]GlyphIndex{
+			0x41: 5, 0x43: 7, 0x100: 0x10a, 0x101: 0x10b, 0x102: 0x10c,
+		}},
+		{"format 8", withCmap(t, goregular.TTF, cmapTable(uint16(pidWindows), uint16(psidWindowsUCS4), testCmapFormat8)), nil},
+		{"format 12", withCmap(t, goregular.TTF, cmapTable(uint16(pidWindows), uint16(psidWindowsUCS4), testCmapFormat12)), map[rune]GlyphIndex{
+			0x41: 3, 0x42: 4, 0x1f600: 200, 0x1f601: 201, 0x10fffe: 300, 0x10ffff: 301,
+		}},
+	}
+
+	for _, tc := range testCases {
+		f, err := Parse(tc.src)
+		if err != nil {
+			t.Errorf("%s: Parse: %v", tc.name, err)
+			continue
+		}
+		var b Buffer
+		got := map[rune]GlyphIndex{}
+		prev := rune(-1)
+		err = f.AllRunes(&b, func(r rune, x GlyphIndex) bool {
+			if r <= prev {
+				t.Errorf("%s: r=%U: not in increasing order", tc.name, r)
+			}
+			prev = r
+			got[r] = x
+			return true
+		})
+		if err != nil {
+			t.Errorf("%s: AllRunes: %v", tc.name, err)
+			continue
+		}
+
+		want := tc.want
+		if want == nil {
+			want = map[rune]GlyphIndex{}
+			for r := rune(0); r <= unicode.MaxRune; r++ {
+				if x, err := f.RawGlyphIndex(&b, r); err != nil {
+					t.Fatalf("%s: RawGlyphIndex(%U): %v", tc.name, r, err)
+				} else if x != 0 && utf8.ValidRune(r) {
+					want[r] = x
+				}
+			}
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %d runes, want %d\ngot  %v\nwant %v", tc.name, len(got), len(want), got, want)
+		}
+
+		// Returning false stops the iteration.
+		n := 0
+		f.AllRunes(&b, func(r rune, x GlyphIndex) bool {
+			n++
+			return n < 2
+		})
+		if n != 2 {
+			t.Errorf("%s: stopping: fn called %d times, want 2", tc.name, n)
+		}
+	}
+}
+
+func TestSelectCmapSubtable(t *testing.T) {
+	// macRoman returns a format 0 subtable, with the given language, that
+	// maps 'A' to the glyph x.
+	macRoman := func(language uint16, x byte) []byte {
+		var table [256]byte
+		table['A'] = x
+		return append(be16(0, 262, language), table[:]...)
+	}
+	src := withCmap(t, goregular.TTF, cmapTable(
+		uint16(pidUnicode), uint16(psidUnicode2BMPOnly), be16(6, 14, 0, 0x41, 2, 50, 51),
+		uint16(pidWindows), uint16(psidWindowsUCS2), be16(6, 14, 0, 0x41, 2, 70, 71),
+		uint16(pidMacintosh), uint16(psidMacintoshRoman), macRoman(1, 90),
+		uint16(pidMacintosh), uint16(psidMacintoshRoman), macRoman(13, 91),
+		// A format 14 subtable, for Unicode Variation Sequences, is not
+		// supported.
+		uint16(pidUnicode), uint16(5), be16(14, 0, 10, 0, 0),
+	))
+	f, err := Parse(src)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	var b Buffer
+	subtables, err := f.CmapSubtables(&b)
+	if err != nil {
+		t.Fatalf("CmapSubtables: %v", err)
+	}
+	want := []CmapSubtable{
+		{Index: 0, PlatformID: 0, EncodingID: 3, Format: 6, Supported: true},
+		{Index: 1, PlatformID: 3, EncodingID: 1, Format: 6, Supported: true},
+		{Index: 2, PlatformID: 1, EncodingID: 0, Format: 0, Language: 1, Supported: true},
+		{Index: 3, PlatformID: 1, EncodingID: 0, Format: 0, Language: 13, Supported: true},
+		{Index: 4, PlatformID: 0, EncodingID: 5, Format: 14},
+	}
+	if !reflect.DeepEqual(subtables, want) {
+		t.Errorf("CmapSubtables:\ngot  %v\nwant %v", subtables, want)
+	}
+
+	// The first of the equally wide Unicode subtables is chosen by default.
+	check := func(desc string, wantIndex int, wantGlyph GlyphIndex) {
+		t.Helper()
+		if got := f.CmapSubtable(); got != want[wantIndex] {
+			t.Errorf("%s: CmapSubtable: got %v, want %v", desc, got, want[wantIndex])
+		}
+		if got, err := f.GlyphIndex(&b, 'A'); err != nil || got != wantGlyph {
+			t.Errorf("%s: GlyphIndex: got %d, %v, want %d, nil", desc, got, err, wantGlyph)
+		}
+	}
+	check("default", 0, 50)
+	for _, i := range []int{1, 3, 2} {
+		if err := f.SelectCmapSubtable(&b, i); err != nil {
+			t.Fatalf("SelectCmapSubtable(%d): %v", i, err)
+		}
+	}
+	check("selected", 2, 90)
+	for _, i := range []int{-1, 4, 5} {
+		if err := f.SelectCmapSubtable(&b, i); err == nil {
+			t.Errorf("SelectCmapSubtable(%d): got nil error, want non-nil", i)
+		}
+	}
+	check("unchanged", 2, 90)
+
+	f, err = Parse(goregular.TTF)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if got := f.CmapSubtable(); !got.Supported || got.Format != 4 {
+		t.Errorf("goregular: CmapSubtable: got %v", got)
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sfnt
+
+import (
+	"math/bits"
+	"unicode"
+)
+
+// Coverage is a set of runes, such as the runes that a font has glyphs for. It
+// is a compact bitmap: it takes 32 bytes for each block of 256 consecutive
+// runes that has any runes in the set, plus 512 bytes for each Unicode plane
+// that has any. Looking a rune up is a few array accesses, so that selecting
+// a font from a fallback chain of many fonts, for every rune of a string,
+// does not need to look the rune up in each font's character map.
+//
+// The zero value is an empty set. A Coverage is safe for concurrent use by
+// multiple goroutines, as long as none of them calls Add.
+type Coverage struct {
+	planes [numPlanes]*coveragePlane
+}
+
+// numPlanes is the number of Unicode planes, of 65536 runes each.
+const numPlanes = unicode.MaxRune>>16 + 1
+
+// coveragePlane is the part of a Coverage for one Unicode plane.
+type coveragePlane struct {
+	// n is the number of runes in the plane.
+	n int
+	// index maps each block of 256 runes to one plus the block's index into
+	// blocks, or zero if the block has no runes.
+	index [256]uint16
+	// blocks holds the bitmaps of the blocks that have any runes.
+	blocks [][4]uint64
+}
+
+// Add adds r to the set. It does nothing if r is not a valid rune, in the
+// range from 0 to unicode.MaxRune.
+func (c *Coverage) Add(r rune) {
+	if r < 0 || r > unicode.MaxRune {
+		return
+	}
+	p := c.planes[r>>16]
+	if p == nil {
+		p = &coveragePlane{}
+		c.planes[r>>16] = p
+	}
+	i := p.index[(r>>8)&0xff]
+	if i == 0 {
+		p.blocks = append(p.blocks, [4]uint64{})
+		i = uint16(len(p.blocks))
+		p.index[(r>>8)&0xff] = i
+	}
+	w, mask := &p.blocks[i-1][(r>>6)&3], uint64(1)<<uint(r&63)
+	if *w&mask == 0 {
+		*w |= mask
+		p.n++
+	}
+}
+
+// Contains returns whether r is in the set.
+func (c *Coverage) Contains(r rune) bool {
+	if r < 0 || r > unicode.MaxRune {
+		return false
+	}
+	p := c.planes[r>>16]
+	if p == nil {
+		return false
+	}
+	i := p.index[(r>>8)&0xff]
+	if i == 0 {
+		return false
+	}
+	return p.blocks[i-1][(r>>6)&3]&(1<<uint(r&63)) != 0
+}
+
+// ContainsAll returns whether every rune of s is in the set. An invalid UTF-8
+// sequence in s is the rune utf8.RuneError, U+FFFD.
+func (c *Coverage) ContainsAll(s string) bool {
+	for _, r := range s {
+		if !c.Contains(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// Len returns the number of runes in the set.
+func (c *Coverage) Len() int {
+	n := 0
+	for _, p := range c.planes {
+		if p != nil {
+			n += p.n
+		}
+	}
+	return n
+}
+
+// PlaneLen returns the number of runes in the set that are in the given
+// Unicode plane, from 0, the Basic Multilingual Plane, to 16. A font that has
+// no glyphs for a plane can be skipped without looking up its runes.
+func (c *Coverage) PlaneLen(plane int) int {
+	if plane < 0 || plane >= numPlanes || c.planes[plane] == nil {
+		return 0
+	}
+	return c.planes[plane].n
+}
+
+// Union adds the runes of d to c.
+func (c *Coverage) Union(d *Coverage) {
+	for pi, dp := range d.planes {
+		if dp == nil {
+			continue
+		}
+		for bi, i := range dp.index {
+			if i == 0 {
+				continue
+			}
+			base := rune(pi)<<16 | rune(bi)<<8
+			for wi, w := range dp.blocks[i-1] {
+				for ; w != 0; w &= w - 1 {
+					c.Add(base | rune(wi)<<6 | rune(bits.TrailingZeros64(w)))
+				}
+			}
+		}
+	}
+}
+
+// CoverageBitmap returns the set of runes that f has glyphs for, that is,
+// those for which GlyphIndex returns a non-zero glyph index. For
+// symbol-encoded fonts, that includes the runes from U+0020 to U+00FF that
+// GlyphIndex remaps to the Private Use Area.
+//
+// The set is built by walking f's character map once, as for AllRunes. It is
+// not updated if SelectCmapSubtable later changes f's character map.
+func (f *Font) CoverageBitmap(b *Buffer) (*Coverage, error) {
+	c := &Coverage{}
+	err := f.AllRunes(b, func(r rune, x GlyphIndex) bool {
+		c.Add(r)
+		if f.cached.isSymbol && 0xf020 <= r && r <= 0xf0ff {
+			c.Add(r - 0xf000)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sfnt
+
+import (
+	"testing"
+	"unicode"
+
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+func TestCoverageBitmap(t *testing.T) {
+	f, err := Parse(goregular.TTF)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	var b Buffer
+	c, err := f.CoverageBitmap(&b)
+	if err != nil {
+		t.Fatalf("CoverageBitmap: %v", err)
+	}
+	n, planeN := 0, 0
+	for r := rune(0); r <= unicode.MaxRune+1; r++ {
+		x, err := f.GlyphIndex(&b, r)
+		if err != nil {
+			t.Fatalf("GlyphIndex(%U): %v", r, err)
+		}
+		if got, want := c.Contains(r), x != 0; got != want {
+			t.Fatalf("Contains(%U): got %t, want %t", r, got, want)
+		}
+		if x != 0 {
+			n++
+			if r <= 0xffff {
+				planeN++
+			}
+		}
+	}
+	if got := c.Len(); got != n {
+		t.Errorf("Len: got %d, want %d", got, n)
+	}
+	if got := c.PlaneLen(0); got != planeN {
+		t.Errorf("PlaneLen(0): got %d, want %d", got, planeN)
+	}
+	if got := c.PlaneLen(1); got != 0 {
+		t.Errorf("PlaneLen(1): got %d, want 0", got)
+	}
+	if !c.ContainsAll("Hello, wörld") {
+		t.Errorf("ContainsAll: got false for a Latin string, want true")
+	}
+	if c.ContainsAll("Hello, 世界") {
+		t.Errorf("ContainsAll: got true for a CJK string, want false")
+	}
+	if c.Contains(-1) {
+		t.Errorf("Contains(-1): got true, want false")
+	}
+}
+
+func TestCoverageBitmapSymbolEncoded(t *testing.T) {
+	// This format 6 subtable maps U+F041 and U+F042.
+	pua := be16(6, 14, 0, 0xf041, 2, 50, 51)
+	f, err := Parse(withCmap(t, goregular.TTF, cmapTable(uint16(pidWindows), uint16(psidWindowsSymbol), pua)))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	c, err := f.CoverageBitmap(nil)
+	if err != nil {
+		t.Fatalf("CoverageBitmap: %v", err)
+	}
+	for _, r := range []rune{'A', 'B', '\uf041', '\uf042'} {
+		if !c.Contains(r) {
+			t.Errorf("Contains(%U): got false, want true", r)
+		}
+	}
+	if c.Contains('C') {
+		t.Errorf("Contains(%U): got true, want false", 'C')
+	}
+	if got := c.Len(); got != 4 {
+		t.Errorf("Len: got %d, want 4", got)
+	}
+}
+
+func TestCoverageUnion(t *testing.T) {
+	var c, d Coverage
+	for _, r := range []rune{'a', 'b', 0x4e16} {
+		c.Add(r)
+	}
+	for _, r := range []rune{'b', 0x1f600, unicode.MaxRune, unicode.MaxRune + 1} {
+		d.Add(r)
+	}
+	c.Union(&d)
+	for _, r := range []rune{'a', 'b', 0x4e16, 0x1f600, unicode.MaxRune} {
+		if !c.Contains(r) {
+			t.Errorf("Contains(%U): got false, want true", r)
+		}
+	}
+	if got := c.Len(); got != 5 {
+		t.Errorf("Len: got %d, want 5", got)
+	}
+	if got := c.PlaneLen(1); got != 1 {
+		t.Errorf("PlaneLen(1): got %d, want 1", got)
+	}
+}
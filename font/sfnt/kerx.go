@@ -0,0 +1,308 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sfnt
+
+import (
+	"sort"
+)
+
+const (
+	kerxCoverageVertical    = 0x80000000
+	kerxCoverageCrossStream = 0x40000000
+	kerxCoverageVariation   = 0x20000000
+	kerxCoverageFormatMask  = 0x000000ff
+
+	kerxSubtableHeaderSize = 12
+)
+
+// parseKerx returns a kernFunc for Apple's extended kerning table, or nil if
+// there is no such table or none of its subtables are supported.
+//
+// Only the simple, non-contextual subtable formats 0 (ordered list of kerning
+// pairs) and 2 (two-dimensional class array) are supported, and only for
+// horizontal, non-cross-stream, non-variation kerning. Other subtables,
+// including the state table formats, are ignored.
+func (f *Font) parseKerx(buf []byte, numGlyphs int32) ([]byte, []kernFunc, error) {
+	// https://developer.apple.com/fonts/TrueType-Reference-Manual/RM06/Chap6kerx.html
+
+	if f.kerx.length == 0 {
+		return buf, nil, nil
+	}
+	const headerSize = 8
+	if f.kerx.length < headerSize {
+		return buf, nil, errInvalidKerxTable
+	}
+	buf, err := f.src.view(buf, int(f.kerx.offset), headerSize)
+	if err != nil {
+		return buf, nil, err
+	}
+	if version := u16(buf); version < 2 || version > 4 {
+		return buf, nil, errUnsupportedKerxTable
+	}
+	numTables := u32(buf[4:])
+
+	var subtables []kernFunc
+	offset, end := int(f.kerx.offset)+headerSize, int(f.kerx.offset)+int(f.kerx.length)
+	for i := uint32(0); i < numTables; i++ {
+		if end-offset < kerxSubtableHeaderSize {
+			return buf, nil, errInvalidKerxTable
+		}
+		buf, err = f.src.view(buf, offset, kerxSubtableHeaderSize)
+		if err != nil {
+			return buf, nil, err
+		}
+		length := int(u32(buf))
+		coverage := u32(buf[4:])
+		tupleCount := u32(buf[8:])
+		if length < kerxSubtableHeaderSize || end-offset < length {
+			return buf, nil, errInvalidKerxTable
+		}
+
+		const unsupported = kerxCoverageVertical | kerxCoverageCrossStream | kerxCoverageVariation
+		if coverage&unsupported == 0 && tupleCount == 0 {
+			var kern kernFunc
+			switch coverage & kerxCoverageFormatMask {
+			case 0:
+				buf, kern, err = f.parseKerxFormat0(buf, offset, length)
+			case 2:
+				buf, kern, err = f.parseKerxFormat2(buf, offset, length, numGlyphs)
+			}
+			if err != nil {
+				return buf, nil, err
+			}
+			if kern != nil {
+				subtables = append(subtables, kern)
+			}
+		}
+		offset += length
+	}
+
+	if len(subtables) == 0 {
+		return buf, nil, nil
+	}
+	return buf, []kernFunc{makeKerxKernFunc(subtables)}, nil
+}
+
+// makeKerxKernFunc returns a kernFunc that sums the values of the given
+// subtables' kernFuncs, as kerx kerning values are cumulative. It returns
+// ErrNotFound if none of the subtables has a value for the pair.
+func makeKerxKernFunc(subtables []kernFunc) kernFunc {
+	return func(a, b GlyphIndex) (int16, error) {
+		found, sum := false, int32(0)
+		for _, kf := range subtables {
+			v, err := kf(a, b)
+			if err == ErrNotFound {
+				continue
+			}
+			if err != nil {
+				return 0, err
+			}
+			found, sum = true, sum+int32(v)
+		}
+		if !found {
+			return 0, ErrNotFound
+		}
+		if sum < -0x8000 {
+			sum = -0x8000
+		} else if sum > 0x7fff {
+			sum = 0x7fff
+		}
+		return int16(sum), nil
+	}
+}
+
+func (f *Font) parseKerxFormat0(buf []byte, offset, length int) ([]byte, kernFunc, error) {
+	// Format 0: nPairs, searchRange, entrySelector, rangeShift (all uint32),
+	// []pairs{left, right, value}.
+	const headerSize, entrySize = 16, 6
+	if length < kerxSubtableHeaderSize+headerSize {
+		return buf, nil, errInvalidKerxTable
+	}
+	buf, err := f.src.view(buf, offset+kerxSubtableHeaderSize, headerSize)
+	if err != nil {
+		return buf, nil, err
+	}
+	numPairs := u32(buf)
+	if numPairs > uint32(length-kerxSubtableHeaderSize-headerSize)/entrySize {
+		return buf, nil, errInvalidKerxTable
+	}
+	buf, err = f.src.view(buf, offset+kerxSubtableHeaderSize+headerSize, int(numPairs)*entrySize)
+	if err != nil {
+		return buf, nil, err
+	}
+
+	pairs := make([]byte, len(buf))
+	copy(pairs, buf)
+	return buf, func(a, b GlyphIndex) (int16, error) {
+		key := uint32(a)<<16 | uint32(b)
+		n := len(pairs) / entrySize
+		i := sort.Search(n, func(i int) bool {
+			return u32(pairs[i*entrySize:]) >= key
+		})
+		if i < n && u32(pairs[i*entrySize:]) == key {
+			return int16(u16(pairs[i*entrySize+4:])), nil
+		}
+		return 0, ErrNotFound
+	}, nil
+}
+
+func (f *Font) parseKerxFormat2(buf []byte, offset, length int, numGlyphs int32) ([]byte, kernFunc, error) {
+	// Format 2: rowWidth, leftClassTable, rightClassTable, kerningArray (all
+	// uint32). The three offsets are from the start of the subtable.
+	const headerSize = 16
+	if length < kerxSubtableHeaderSize+headerSize {
+		return buf, nil, errInvalidKerxTable
+	}
+	buf, err := f.src.view(buf, offset, length)
+	if err != nil {
+		return buf, nil, err
+	}
+	leftOffset := u32(buf[kerxSubtableHeaderSize+4:])
+	rightOffset := u32(buf[kerxSubtableHeaderSize+8:])
+	arrayOffset := u32(buf[kerxSubtableHeaderSize+12:])
+	if leftOffset >= uint32(length) || rightOffset >= uint32(length) || arrayOffset > uint32(length) {
+		return buf, nil, errInvalidKerxTable
+	}
+
+	subtable := make([]byte, len(buf))
+	copy(subtable, buf)
+	left := aatLookupTable{subtable[leftOffset:], numGlyphs}
+	right := aatLookupTable{subtable[rightOffset:], numGlyphs}
+	if !left.supported() || !right.supported() {
+		return buf, nil, nil
+	}
+	array := subtable[arrayOffset:]
+	return buf, func(a, b GlyphIndex) (int16, error) {
+		l, ok, err := left.lookup(a)
+		if err != nil || !ok {
+			return 0, notFoundOr(err)
+		}
+		r, ok, err := right.lookup(b)
+		if err != nil || !ok {
+			return 0, notFoundOr(err)
+		}
+		// The class values are pre-multiplied, so that their sum is an index
+		// into the array of int16 kerning values.
+		i := 2 * (int(l) + int(r))
+		if i+2 > len(array) {
+			return 0, errInvalidKerxTable
+		}
+		return int16(u16(array[i:])), nil
+	}, nil
+}
+
+func notFoundOr(err error) error {
+	if err != nil {
+		return err
+	}
+	return ErrNotFound
+}
+
+// aatLookupTable is an AAT lookup table, mapping glyph indexes to uint16
+// values.
+//
+// https://developer.apple.com/fonts/TrueType-Reference-Manual/RM06/Chap6Tables.html
+type aatLookupTable struct {
+	data      []byte
+	numGlyphs int32
+}
+
+func (t aatLookupTable) supported() bool {
+	if len(t.data) < 2 {
+		return false
+	}
+	switch u16(t.data) {
+	case 0, 2, 4, 6, 8:
+		return true
+	}
+	return false
+}
+
+// lookup returns the value for glyph x, and whether x has a value.
+func (t aatLookupTable) lookup(x GlyphIndex) (value uint16, found bool, err error) {
+	d := t.data
+	switch u16(d) {
+	case 0:
+		// Simple array: one value per glyph.
+		if int32(x) >= t.numGlyphs {
+			return 0, false, nil
+		}
+		i := 2 + 2*int(x)
+		if i+2 > len(d) {
+			return 0, false, errInvalidKerxTable
+		}
+		return u16(d[i:]), true, nil
+
+	case 2, 4:
+		// Segment single or segment array: []{lastGlyph, firstGlyph, value},
+		// sorted by lastGlyph. For format 4, the value is an offset, from the
+		// start of the lookup table, to an array of per-glyph values.
+		seg, ok, err := t.binarySearch(x, true)
+		if err != nil || !ok {
+			return 0, false, err
+		}
+		if GlyphIndex(u16(seg[2:])) > x {
+			return 0, false, nil
+		}
+		v := u16(seg[4:])
+		if u16(d) == 2 {
+			return v, true, nil
+		}
+		i := int(v) + 2*int(x-GlyphIndex(u16(seg[2:])))
+		if i+2 > len(d) {
+			return 0, false, errInvalidKerxTable
+		}
+		return u16(d[i:]), true, nil
+
+	case 6:
+		// Single table: []{glyph, value}, sorted by glyph.
+		entry, ok, err := t.binarySearch(x, false)
+		if err != nil || !ok || GlyphIndex(u16(entry)) != x {
+			return 0, false, err
+		}
+		return u16(entry[2:]), true, nil
+
+	case 8:
+		// Trimmed array: firstGlyph, glyphCount, []values.
+		if len(d) < 6 {
+			return 0, false, errInvalidKerxTable
+		}
+		first, count := GlyphIndex(u16(d[2:])), int(u16(d[4:]))
+		if x < first || int(x-first) >= count {
+			return 0, false, nil
+		}
+		i := 6 + 2*int(x-first)
+		if i+2 > len(d) {
+			return 0, false, errInvalidKerxTable
+		}
+		return u16(d[i:]), true, nil
+	}
+	return 0, false, nil
+}
+
+// binarySearch returns the first unit of a binary searchable lookup table
+// whose key is >= x. The key is the first uint16 of each unit. If segments is
+// true, the units are at least 6 bytes long, otherwise at least 4 bytes long.
+func (t aatLookupTable) binarySearch(x GlyphIndex, segments bool) (unit []byte, found bool, err error) {
+	// Binary search header: unitSize, nUnits, searchRange, entrySelector,
+	// rangeShift (all uint16).
+	const headerSize = 2 + 10
+	d := t.data
+	if len(d) < headerSize {
+		return nil, false, errInvalidKerxTable
+	}
+	unitSize, numUnits := int(u16(d[2:])), int(u16(d[4:]))
+	if (segments && unitSize < 6) || unitSize < 4 || len(d) < headerSize+unitSize*numUnits {
+		return nil, false, errInvalidKerxTable
+	}
+	units := d[headerSize : headerSize+unitSize*numUnits]
+	i := sort.Search(numUnits, func(i int) bool {
+		return GlyphIndex(u16(units[i*unitSize:])) >= x
+	})
+	if i == numUnits {
+		return nil, false, nil
+	}
+	return units[i*unitSize : (i+1)*unitSize], true, nil
+}
@@ -0,0 +1,187 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sfnt
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/math/fixed"
+)
+
+// withTable returns a copy of the font data src with an extra table, which
+// must not already be in src.
+func withTable(t *testing.T, src []byte, tag string, data []byte) []byte {
+	t.Helper()
+	const headerSize, recordSize = 12, 16
+	numTables := int(binary.BigEndian.Uint16(src[4:]))
+	records := src[headerSize : headerSize+recordSize*numTables]
+
+	// Keep the table records sorted by tag, and shift the existing tables'
+	// offsets past the new record.
+	var dst []byte
+	dst = append(dst, src[:headerSize]...)
+	binary.BigEndian.PutUint16(dst[4:], uint16(numTables+1))
+	newRecord := make([]byte, recordSize)
+	copy(newRecord, tag)
+	inserted := false
+	for i := 0; i < numTables; i++ {
+		r := records[i*recordSize : (i+1)*recordSize]
+		if string(r[:4]) == tag {
+			t.Fatalf("table %q already present", tag)
+		}
+		if !inserted && string(r[:4]) > tag {
+			dst = append(dst, newRecord...)
+			inserted = true
+		}
+		dst = append(dst, r...)
+		o := binary.BigEndian.Uint32(r[8:])
+		binary.BigEndian.PutUint32(dst[len(dst)-8:], o+recordSize)
+	}
+	if !inserted {
+		dst = append(dst, newRecord...)
+	}
+	dst = append(dst, src[headerSize+recordSize*numTables:]...)
+
+	// Append the new table, 4-byte aligned.
+	for len(dst)%4 != 0 {
+		dst = append(dst, 0)
+	}
+	for i := headerSize; i < headerSize+recordSize*(numTables+1); i += recordSize {
+		if string(dst[i:i+4]) == tag {
+			binary.BigEndian.PutUint32(dst[i+8:], uint32(len(dst)))
+			binary.BigEndian.PutUint32(dst[i+12:], uint32(len(data)))
+		}
+	}
+	return append(dst, data...)
+}
+
+// be16 returns the big-endian encoding of the given uint16 values.
+func be16(values ...uint16) []byte {
+	b := make([]byte, 2*len(values))
+	for i, v := range values {
+		binary.BigEndian.PutUint16(b[2*i:], v)
+	}
+	return b
+}
+
+// kerxSubtable returns a kerx subtable with the given coverage and body.
+func kerxSubtable(coverage uint32, body []byte) []byte {
+	b := make([]byte, kerxSubtableHeaderSize, kerxSubtableHeaderSize+len(body))
+	binary.BigEndian.PutUint32(b[0:], uint32(kerxSubtableHeaderSize+len(body)))
+	binary.BigEndian.PutUint32(b[4:], coverage)
+	return append(b, body...)
+}
+
+func kerxFormat0(pairs ...uint16) []byte {
+	body := make([]byte, 16)
+	binary.BigEndian.PutUint32(body, uint32(len(pairs)/3))
+	return append(body, be16(pairs...)...)
+}
+
+func TestKerx(t *testing.T) {
+	const (
+		left  = 16
+		right = 16 + 16
+		array = right + 20
+	)
+	var format2 []byte
+	format2 = append(format2, make([]byte, 16)...)
+	binary.BigEndian.PutUint32(format2[0:], 4)
+	binary.BigEndian.PutUint32(format2[4:], kerxSubtableHeaderSize+left)
+	binary.BigEndian.PutUint32(format2[8:], kerxSubtableHeaderSize+right)
+	binary.BigEndian.PutUint32(format2[12:], kerxSubtableHeaderSize+array)
+	// The left class table is a trimmed array (format 8) for glyphs 10, 11
+	// and 12. Its values are row indexes, pre-multiplied by the row width.
+	format2 = append(format2, be16(8, 10, 3, 2, 0, 2, 0, 0)...)
+	// The right class table is a single table (format 6) for glyphs 11 and
+	// 13.
+	format2 = append(format2, be16(6, 4, 2, 0, 0, 0, 11, 1, 13, 1)...)
+	// The kerning array has 2 rows of 2 columns.
+	format2 = append(format2, be16(0, 0, 0xfffb, 0xfff7)...)
+
+	var kerx []byte
+	kerx = append(kerx, be16(2, 0, 0, 3)...)
+	kerx = append(kerx, kerxSubtable(0, kerxFormat0(10, 11, 0xffce, 10, 12, 20, 12, 10, 7))...)
+	kerx = append(kerx, kerxSubtable(kerxCoverageVertical, kerxFormat0(10, 11, 999))...)
+	kerx = append(kerx, kerxSubtable(2, format2)...)
+
+	f, err := Parse(withTable(t, goregular.TTF, "kerx", kerx))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	ppem := fixed.Int26_6(f.UnitsPerEm())
+
+	testCases := []struct {
+		x0, x1  GlyphIndex
+		want    fixed.Int26_6
+		wantErr error
+	}{
+		{10, 11, -50 - 9, nil}, // Both subtables.
+		{10, 12, 20, nil},      // Format 0 only.
+		{12, 10, 7, nil},       // Format 0 only.
+		{12, 13, -9, nil},      // Format 2 only.
+		{11, 13, 0, nil},       // Format 2 only, with a zero value.
+		{14, 15, 0, ErrNotFound},
+	}
+	var b Buffer
+	for _, tc := range testCases {
+		got, err := f.Kern(&b, tc.x0, tc.x1, ppem, font.HintingNone)
+		if err != tc.wantErr || got != tc.want {
+			t.Errorf("Kern(%d, %d): got %v, %v, want %v, %v", tc.x0, tc.x1, got, err, tc.want, tc.wantErr)
+		}
+	}
+}
+
+func TestKerxInvalid(t *testing.T) {
+	testCases := []struct {
+		desc string
+		kerx []byte
+	}{
+		{"short header", be16(2, 0)},
+		{"unsupported version", be16(1, 0, 0, 0)},
+		{"missing subtable", be16(2, 0, 0, 1)},
+		{"subtable too long", append(be16(2, 0, 0, 1), be16(0, 100, 0, 0, 0, 0)...)},
+		{"too many pairs", append(be16(2, 0, 0, 1), kerxSubtable(0, be16(0, 1, 0, 0, 0, 0, 0, 0))...)},
+	}
+	for _, tc := range testCases {
+		if _, err := Parse(withTable(t, goregular.TTF, "kerx", tc.kerx)); err == nil {
+			t.Errorf("%s: got nil error, want non-nil", tc.desc)
+		}
+	}
+}
+
+func TestAATLookupTable(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		data  []byte
+		x     GlyphIndex
+		want  uint16
+		found bool
+	}{
+		{"format 0", be16(0, 5, 6, 7, 8), 2, 7, true},
+		{"format 0 out of range", be16(0, 5, 6, 7, 8), 4, 0, false},
+		{"format 2", be16(2, 6, 2, 0, 0, 0, 20, 10, 100, 0xffff, 0xffff, 0), 15, 100, true},
+		{"format 2 before first", be16(2, 6, 2, 0, 0, 0, 20, 10, 100, 0xffff, 0xffff, 0), 9, 0, false},
+		{"format 4", be16(4, 6, 1, 0, 0, 0, 12, 10, 18, 40, 41, 42), 11, 41, true},
+		{"format 6", be16(6, 4, 2, 0, 0, 0, 3, 30, 9, 90), 9, 90, true},
+		{"format 6 missing", be16(6, 4, 2, 0, 0, 0, 3, 30, 9, 90), 5, 0, false},
+		{"format 8", be16(8, 10, 2, 70, 71), 11, 71, true},
+		{"format 8 after last", be16(8, 10, 2, 70, 71), 12, 0, false},
+	}
+	for _, tc := range testCases {
+		lt := aatLookupTable{tc.data, 4}
+		if !lt.supported() {
+			t.Errorf("%s: not supported", tc.desc)
+			continue
+		}
+		got, found, err := lt.lookup(tc.x)
+		if err != nil || got != tc.want || found != tc.found {
+			t.Errorf("%s: got %d, %t, %v, want %d, %t, nil", tc.desc, got, found, err, tc.want, tc.found)
+		}
+	}
+}
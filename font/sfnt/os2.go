@@ -0,0 +1,362 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sfnt
+
+import (
+	"sort"
+)
+
+// UnicodeRanges is the set of Unicode ranges that a font's "OS/2" table
+// claims that the font functionally supports, as its ulUnicodeRange1 to
+// ulUnicodeRange4 fields. Bit i of the set is bit i%32 of element i/32. The
+// bits are numbered from 0 to 122, and each one stands for one or more
+// Unicode blocks, such as bit 13 for the Arabic and Arabic Supplement blocks.
+//
+// Fonts set these bits by hand, or by rules of thumb, so that they are only a
+// hint. They are useful for quickly ruling out fonts for a script, before
+// looking its runes up in each candidate font's character map.
+//
+// See https://docs.microsoft.com/en-us/typography/opentype/spec/os2#ur
+type UnicodeRanges [4]uint32
+
+// Has returns whether bit i of the set is set.
+func (u UnicodeRanges) Has(i int) bool {
+	return 0 <= i && i < 128 && u[i/32]&(1<<uint(i%32)) != 0
+}
+
+// ContainsRune returns whether the set has the bit for the Unicode block of
+// r, as given by UnicodeRangeBit. It returns false if r is in no such block.
+func (u UnicodeRanges) ContainsRune(r rune) bool {
+	i, ok := UnicodeRangeBit(r)
+	return ok && u.Has(i)
+}
+
+// rightToLeftRangeBits are the bits of a UnicodeRanges for the blocks of the
+// right-to-left scripts in modern use, and their presentation forms: Hebrew,
+// Arabic, NKo, Arabic Presentation Forms-A, Arabic Presentation Forms-B,
+// Syriac and Thaana.
+var rightToLeftRangeBits = [...]int{11, 13, 14, 63, 67, 71, 72}
+
+// HasRightToLeft returns whether the set has the bit for any right-to-left
+// script in modern use: Hebrew, Arabic, Syriac, Thaana or NKo. Text in such a
+// script needs bidirectional layout.
+func (u UnicodeRanges) HasRightToLeft() bool {
+	for _, i := range rightToLeftRangeBits {
+		if u.Has(i) {
+			return true
+		}
+	}
+	return false
+}
+
+// UnicodeRangeBit returns the bit of a UnicodeRanges that stands for the
+// Unicode block of r. It returns false if r is in no block that has a bit.
+//
+// Runes beyond the Basic Multilingual Plane that are in no other block have
+// bit 57, which a font sets if it supports any such rune.
+func UnicodeRangeBit(r rune) (i int, ok bool) {
+	j := sort.Search(len(unicodeRangeBits), func(j int) bool {
+		return unicodeRangeBits[j].hi >= r
+	})
+	if j < len(unicodeRangeBits) && unicodeRangeBits[j].lo <= r {
+		return int(unicodeRangeBits[j].bit), true
+	}
+	if 0xffff < r && r <= 0x10ffff {
+		return 57, true
+	}
+	return 0, false
+}
+
+// unicodeRangeBits maps the Unicode blocks, from lo to hi inclusive, to their
+// bits of a UnicodeRanges. It is sorted by lo, and the blocks do not overlap.
+var unicodeRangeBits = [...]struct {
+	lo, hi rune
+	bit    uint8
+}{
+	{0x0000, 0x007f, 0},      // Basic Latin.
+	{0x0080, 0x00ff, 1},      // Latin-1 Supplement.
+	{0x0100, 0x017f, 2},      // Latin Extended-A.
+	{0x0180, 0x024f, 3},      // Latin Extended-B.
+	{0x0250, 0x02af, 4},      // IPA Extensions.
+	{0x02b0, 0x02ff, 5},      // Spacing Modifier Letters.
+	{0x0300, 0x036f, 6},      // Combining Diacritical Marks.
+	{0x0370, 0x03ff, 7},      // Greek and Coptic.
+	{0x0400, 0x04ff, 9},      // Cyrillic.
+	{0x0500, 0x052f, 9},      // Cyrillic Supplement.
+	{0x0530, 0x058f, 10},     // Armenian.
+	{0x0590, 0x05ff, 11},     // Hebrew.
+	{0x0600, 0x06ff, 13},     // Arabic.
+	{0x0700, 0x074f, 71},     // Syriac.
+	{0x0750, 0x077f, 13},     // Arabic Supplement.
+	{0x0780, 0x07bf, 72},     // Thaana.
+	{0x07c0, 0x07ff, 14},     // NKo.
+	{0x0900, 0x097f, 15},     // Devanagari.
+	{0x0980, 0x09ff, 16},     // Bengali.
+	{0x0a00, 0x0a7f, 17},     // Gurmukhi.
+	{0x0a80, 0x0aff, 18},     // Gujarati.
+	{0x0b00, 0x0b7f, 19},     // Oriya.
+	{0x0b80, 0x0bff, 20},     // Tamil.
+	{0x0c00, 0x0c7f, 21},     // Telugu.
+	{0x0c80, 0x0cff, 22},     // Kannada.
+	{0x0d00, 0x0d7f, 23},     // Malayalam.
+	{0x0d80, 0x0dff, 73},     // Sinhala.
+	{0x0e00, 0x0e7f, 24},     // Thai.
+	{0x0e80, 0x0eff, 25},     // Lao.
+	{0x0f00, 0x0fff, 70},     // Tibetan.
+	{0x1000, 0x109f, 74},     // Myanmar.
+	{0x10a0, 0x10ff, 26},     // Georgian.
+	{0x1100, 0x11ff, 28},     // Hangul Jamo.
+	{0x1200, 0x137f, 75},     // Ethiopic.
+	{0x1380, 0x139f, 75},     // Ethiopic Supplement.
+	{0x13a0, 0x13ff, 76},     // Cherokee.
+	{0x1400, 0x167f, 77},     // Unified Canadian Aboriginal Syllabics.
+	{0x1680, 0x169f, 78},     // Ogham.
+	{0x16a0, 0x16ff, 79},     // Runic.
+	{0x1700, 0x171f, 84},     // Tagalog.
+	{0x1720, 0x173f, 84},     // Hanunoo.
+	{0x1740, 0x175f, 84},     // Buhid.
+	{0x1760, 0x177f, 84},     // Tagbanwa.
+	{0x1780, 0x17ff, 80},     // Khmer.
+	{0x1800, 0x18af, 81},     // Mongolian.
+	{0x1900, 0x194f, 93},     // Limbu.
+	{0x1950, 0x197f, 94},     // Tai Le.
+	{0x1980, 0x19df, 95},     // New Tai Lue.
+	{0x19e0, 0x19ff, 80},     // Khmer Symbols.
+	{0x1a00, 0x1a1f, 96},     // Buginese.
+	{0x1b00, 0x1b7f, 27},     // Balinese.
+	{0x1b80, 0x1bbf, 112},    // Sundanese.
+	{0x1c00, 0x1c4f, 113},    // Lepcha.
+	{0x1c50, 0x1c7f, 114},    // Ol Chiki.
+	{0x1d00, 0x1d7f, 4},      // Phonetic Extensions.
+	{0x1d80, 0x1dbf, 4},      // Phonetic Extensions Supplement.
+	{0x1dc0, 0x1dff, 6},      // Combining Diacritical Marks Supplement.
+	{0x1e00, 0x1eff, 29},     // Latin Extended Additional.
+	{0x1f00, 0x1fff, 30},     // Greek Extended.
+	{0x2000, 0x206f, 31},     // General Punctuation.
+	{0x2070, 0x209f, 32},     // Superscripts And Subscripts.
+	{0x20a0, 0x20cf, 33},     // Currency Symbols.
+	{0x20d0, 0x20ff, 34},     // Combining Diacritical Marks For Symbols.
+	{0x2100, 0x214f, 35},     // Letterlike Symbols.
+	{0x2150, 0x218f, 36},     // Number Forms.
+	{0x2190, 0x21ff, 37},     // Arrows.
+	{0x2200, 0x22ff, 38},     // Mathematical Operators.
+	{0x2300, 0x23ff, 39},     // Miscellaneous Technical.
+	{0x2400, 0x243f, 40},     // Control Pictures.
+	{0x2440, 0x245f, 41},     // Optical Character Recognition.
+	{0x2460, 0x24ff, 42},     // Enclosed Alphanumerics.
+	{0x2500, 0x257f, 43},     // Box Drawing.
+	{0x2580, 0x259f, 44},     // Block Elements.
+	{0x25a0, 0x25ff, 45},     // Geometric Shapes.
+	{0x2600, 0x26ff, 46},     // Miscellaneous Symbols.
+	{0x2700, 0x27bf, 47},     // Dingbats.
+	{0x27c0, 0x27ef, 38},     // Miscellaneous Mathematical Symbols-A.
+	{0x27f0, 0x27ff, 37},     // Supplemental Arrows-A.
+	{0x2800, 0x28ff, 82},     // Braille Patterns.
+	{0x2900, 0x297f, 37},     // Supplemental Arrows-B.
+	{0x2980, 0x29ff, 38},     // Miscellaneous Mathematical Symbols-B.
+	{0x2a00, 0x2aff, 38},     // Supplemental Mathematical Operators.
+	{0x2b00, 0x2bff, 37},     // Miscellaneous Symbols and Arrows.
+	{0x2c00, 0x2c5f, 97},     // Glagolitic.
+	{0x2c60, 0x2c7f, 29},     // Latin Extended-C.
+	{0x2c80, 0x2cff, 8},      // Coptic.
+	{0x2d00, 0x2d2f, 26},     // Georgian Supplement.
+	{0x2d30, 0x2d7f, 98},     // Tifinagh.
+	{0x2d80, 0x2ddf, 75},     // Ethiopic Extended.
+	{0x2de0, 0x2dff, 9},      // Cyrillic Extended-A.
+	{0x2e00, 0x2e7f, 31},     // Supplemental Punctuation.
+	{0x2e80, 0x2eff, 59},     // CJK Radicals Supplement.
+	{0x2f00, 0x2fdf, 59},     // Kangxi Radicals.
+	{0x2ff0, 0x2fff, 59},     // Ideographic Description Characters.
+	{0x3000, 0x303f, 48},     // CJK Symbols And Punctuation.
+	{0x3040, 0x309f, 49},     // Hiragana.
+	{0x30a0, 0x30ff, 50},     // Katakana.
+	{0x3100, 0x312f, 51},     // Bopomofo.
+	{0x3130, 0x318f, 52},     // Hangul Compatibility Jamo.
+	{0x3190, 0x319f, 59},     // Kanbun.
+	{0x31a0, 0x31bf, 51},     // Bopomofo Extended.
+	{0x31c0, 0x31ef, 61},     // CJK Strokes.
+	{0x31f0, 0x31ff, 50},     // Katakana Phonetic Extensions.
+	{0x3200, 0x32ff, 54},     // Enclosed CJK Letters And Months.
+	{0x3300, 0x33ff, 55},     // CJK Compatibility.
+	{0x3400, 0x4dbf, 59},     // CJK Unified Ideographs Extension A.
+	{0x4dc0, 0x4dff, 99},     // Yijing Hexagram Symbols.
+	{0x4e00, 0x9fff, 59},     // CJK Unified Ideographs.
+	{0xa000, 0xa48f, 83},     // Yi Syllables.
+	{0xa490, 0xa4cf, 83},     // Yi Radicals.
+	{0xa500, 0xa63f, 12},     // Vai.
+	{0xa640, 0xa69f, 9},      // Cyrillic Extended-B.
+	{0xa700, 0xa71f, 5},      // Modifier Tone Letters.
+	{0xa720, 0xa7ff, 29},     // Latin Extended-D.
+	{0xa800, 0xa82f, 100},    // Syloti Nagri.
+	{0xa840, 0xa87f, 53},     // Phags-pa.
+	{0xa880, 0xa8df, 115},    // Saurashtra.
+	{0xa900, 0xa92f, 116},    // Kayah Li.
+	{0xa930, 0xa95f, 117},    // Rejang.
+	{0xaa00, 0xaa5f, 118},    // Cham.
+	{0xac00, 0xd7af, 56},     // Hangul Syllables.
+	{0xd800, 0xdfff, 57},     // Non-Plane 0.
+	{0xe000, 0xf8ff, 60},     // Private Use Area (plane 0).
+	{0xf900, 0xfaff, 61},     // CJK Compatibility Ideographs.
+	{0xfb00, 0xfb4f, 62},     // Alphabetic Presentation Forms.
+	{0xfb50, 0xfdff, 63},     // Arabic Presentation Forms-A.
+	{0xfe00, 0xfe0f, 91},     // Variation Selectors.
+	{0xfe10, 0xfe1f, 65},     // Vertical Forms.
+	{0xfe20, 0xfe2f, 64},     // Combining Half Marks.
+	{0xfe30, 0xfe4f, 65},     // CJK Compatibility Forms.
+	{0xfe50, 0xfe6f, 66},     // Small Form Variants.
+	{0xfe70, 0xfeff, 67},     // Arabic Presentation Forms-B.
+	{0xff00, 0xffef, 68},     // Halfwidth And Fullwidth Forms.
+	{0xfff0, 0xffff, 69},     // Specials.
+	{0x10000, 0x1007f, 101},  // Linear B Syllabary.
+	{0x10080, 0x100ff, 101},  // Linear B Ideograms.
+	{0x10100, 0x1013f, 101},  // Aegean Numbers.
+	{0x10140, 0x1018f, 102},  // Ancient Greek Numbers.
+	{0x10190, 0x101cf, 119},  // Ancient Symbols.
+	{0x101d0, 0x101ff, 120},  // Phaistos Disc.
+	{0x10280, 0x1029f, 121},  // Lycian.
+	{0x102a0, 0x102df, 121},  // Carian.
+	{0x10300, 0x1032f, 85},   // Old Italic.
+	{0x10330, 0x1034f, 86},   // Gothic.
+	{0x10380, 0x1039f, 103},  // Ugaritic.
+	{0x103a0, 0x103df, 104},  // Old Persian.
+	{0x10400, 0x1044f, 87},   // Deseret.
+	{0x10450, 0x1047f, 105},  // Shavian.
+	{0x10480, 0x104af, 106},  // Osmanya.
+	{0x10800, 0x1083f, 107},  // Cypriot Syllabary.
+	{0x10900, 0x1091f, 58},   // Phoenician.
+	{0x10920, 0x1093f, 121},  // Lydian.
+	{0x10a00, 0x10a5f, 108},  // Kharoshthi.
+	{0x12000, 0x123ff, 110},  // Cuneiform.
+	{0x12400, 0x1247f, 110},  // Cuneiform Numbers and Punctuation.
+	{0x1d000, 0x1d0ff, 88},   // Byzantine Musical Symbols.
+	{0x1d100, 0x1d1ff, 88},   // Musical Symbols.
+	{0x1d200, 0x1d24f, 88},   // Ancient Greek Musical Notation.
+	{0x1d300, 0x1d35f, 109},  // Tai Xuan Jing Symbols.
+	{0x1d360, 0x1d37f, 111},  // Counting Rod Numerals.
+	{0x1d400, 0x1d7ff, 89},   // Mathematical Alphanumeric Symbols.
+	{0x1f000, 0x1f02f, 122},  // Mahjong Tiles.
+	{0x1f030, 0x1f09f, 122},  // Domino Tiles.
+	{0x20000, 0x2a6df, 59},   // CJK Unified Ideographs Extension B.
+	{0x2f800, 0x2fa1f, 61},   // CJK Compatibility Ideographs Supplement.
+	{0xe0000, 0xe007f, 92},   // Tags.
+	{0xe0100, 0xe01ef, 91},   // Variation Selectors Supplement.
+	{0xf0000, 0xffffd, 90},   // Private Use (plane 15).
+	{0x100000, 0x10fffd, 90}, // Private Use (plane 16).
+}
+
+// CodePageRanges is the set of code pages that a font's "OS/2" table claims
+// that the font functionally supports, as its ulCodePageRange1 and
+// ulCodePageRange2 fields. Bit i of the set is bit i%32 of element i/32. Like
+// UnicodeRanges, it is only a hint.
+//
+// See https://docs.microsoft.com/en-us/typography/opentype/spec/os2#cpr
+type CodePageRanges [2]uint32
+
+// Has returns whether bit i of the set is set.
+func (c CodePageRanges) Has(i int) bool {
+	return 0 <= i && i < 64 && c[i/32]&(1<<uint(i%32)) != 0
+}
+
+// HasCodePage returns whether the set has the bit for the code page with the
+// given number, such as 1252 for the Windows Latin 1 code page or 932 for the
+// Japanese Shift JIS code page. It returns false for code pages that have no
+// bit.
+func (c CodePageRanges) HasCodePage(codePage int) bool {
+	i, ok := codePageBits[codePage]
+	return ok && c.Has(int(i))
+}
+
+// codePageBits maps code page numbers to their bits of a CodePageRanges. Bits
+// 29, 30 and 31, for the Macintosh character set, the OEM character set and
+// the Symbol character set, have no code page number.
+var codePageBits = map[int]uint8{
+	1252: 0,  // Latin 1.
+	1250: 1,  // Latin 2: Eastern Europe.
+	1251: 2,  // Cyrillic.
+	1253: 3,  // Greek.
+	1254: 4,  // Turkish.
+	1255: 5,  // Hebrew.
+	1256: 6,  // Arabic.
+	1257: 7,  // Windows Baltic.
+	1258: 8,  // Vietnamese.
+	874:  16, // Thai.
+	932:  17, // JIS/Japan.
+	936:  18, // Chinese: Simplified chars--PRC and Singapore.
+	949:  19, // Korean Wansung.
+	950:  20, // Chinese: Traditional chars--Taiwan and Hong Kong.
+	1361: 21, // Korean Johab.
+	869:  48, // IBM Greek.
+	866:  49, // MS-DOS Russian.
+	865:  50, // MS-DOS Nordic.
+	864:  51, // Arabic.
+	863:  52, // MS-DOS Canadian French.
+	862:  53, // Hebrew.
+	861:  54, // MS-DOS Icelandic.
+	860:  55, // MS-DOS Portuguese.
+	857:  56, // IBM Turkish.
+	855:  57, // IBM Cyrillic; primarily Russian.
+	852:  58, // Latin 2.
+	775:  59, // MS-DOS Baltic.
+	737:  60, // Greek; former 437 G.
+	708:  61, // Arabic; ASMO 708.
+	850:  62, // WE/Latin 1.
+	437:  63, // US.
+}
+
+// UnicodeRanges returns the Unicode ranges that f's "OS/2" table claims that
+// f supports. It returns false if f has no such table, as some Apple TrueType
+// fonts do not.
+func (f *Font) UnicodeRanges() (UnicodeRanges, bool) {
+	return f.cached.os2Ranges.unicode, f.cached.os2Ranges.hasUnicode
+}
+
+// CodePageRanges returns the code pages that f's "OS/2" table claims that f
+// supports. It returns false if f has no such table, or if the table's
+// version is 0, which predates those fields.
+func (f *Font) CodePageRanges() (CodePageRanges, bool) {
+	return f.cached.os2Ranges.codePage, f.cached.os2Ranges.hasCodePage
+}
+
+// os2Ranges holds the Unicode and code page ranges of an "OS/2" table.
+type os2Ranges struct {
+	unicode     UnicodeRanges
+	codePage    CodePageRanges
+	hasUnicode  bool
+	hasCodePage bool
+}
+
+// parseOS2Ranges parses the ulUnicodeRange and ulCodePageRange fields of the
+// "OS/2" table. It is called after parseOS2, which checks the table's length.
+func (f *Font) parseOS2Ranges(buf []byte) (buf1 []byte, r os2Ranges, err error) {
+	if f.os2.length == 0 {
+		return buf, os2Ranges{}, nil
+	}
+	vers, err := f.src.u16(buf, f.os2, 0)
+	if err != nil {
+		return nil, os2Ranges{}, err
+	}
+	for i := range r.unicode {
+		x, err := f.src.u32(buf, f.os2, 42+4*i)
+		if err != nil {
+			return nil, os2Ranges{}, err
+		}
+		r.unicode[i] = x
+	}
+	r.hasUnicode = true
+	// Version 1 added the ulCodePageRange fields, but some fonts that claim
+	// version 1 have the 78 bytes of version 0 of the Microsoft specification.
+	if vers < 1 || f.os2.length < 86 {
+		return buf, r, nil
+	}
+	for i := range r.codePage {
+		x, err := f.src.u32(buf, f.os2, 78+4*i)
+		if err != nil {
+			return nil, os2Ranges{}, err
+		}
+		r.codePage[i] = x
+	}
+	r.hasCodePage = true
+	return buf, r, nil
+}
@@ -0,0 +1,147 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sfnt
+
+import (
+	"testing"
+
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+func TestOS2Ranges(t *testing.T) {
+	f, err := Parse(goregular.TTF)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	u, ok := f.UnicodeRanges()
+	if !ok {
+		t.Fatal("UnicodeRanges: got false, want true")
+	}
+	for _, tc := range []struct {
+		r    rune
+		want bool
+	}{
+		{'A', true},
+		{'é', true},     // Latin-1 Supplement.
+		{'ő', true},     // Latin Extended-A.
+		{'λ', true},     // Greek and Coptic.
+		{'Ж', true},     // Cyrillic.
+		{'ễ', true},     // Latin Extended Additional.
+		{'‰', true},     // General Punctuation.
+		{'א', false},    // Hebrew.
+		{'ع', false},    // Arabic.
+		{'世', false},    // CJK Unified Ideographs.
+		{0x0800, false}, // Samaritan, which has no bit.
+	} {
+		if got := u.ContainsRune(tc.r); got != tc.want {
+			t.Errorf("ContainsRune(%U): got %t, want %t", tc.r, got, tc.want)
+		}
+	}
+	if u.HasRightToLeft() {
+		t.Error("HasRightToLeft: got true, want false")
+	}
+
+	c, ok := f.CodePageRanges()
+	if !ok {
+		t.Fatal("CodePageRanges: got false, want true")
+	}
+	for _, tc := range []struct {
+		codePage int
+		want     bool
+	}{
+		{1252, true},
+		{1250, true},
+		{1251, true},
+		{1253, true},
+		{1254, true},
+		{1257, true},
+		{437, true},
+		{850, true},
+		{1255, false},
+		{1256, false},
+		{932, false},
+		{65001, false}, // UTF-8, which has no bit.
+	} {
+		if got := c.HasCodePage(tc.codePage); got != tc.want {
+			t.Errorf("HasCodePage(%d): got %t, want %t", tc.codePage, got, tc.want)
+		}
+	}
+}
+
+func TestUnicodeRangeBit(t *testing.T) {
+	for i, b := range unicodeRangeBits {
+		if b.lo > b.hi {
+			t.Fatalf("unicodeRangeBits[%d]: lo %U > hi %U", i, b.lo, b.hi)
+		}
+		if i > 0 && unicodeRangeBits[i-1].hi >= b.lo {
+			t.Fatalf("unicodeRangeBits[%d]: %U overlaps the previous block", i, b.lo)
+		}
+		if b.bit > 122 {
+			t.Fatalf("unicodeRangeBits[%d]: bit %d is out of range", i, b.bit)
+		}
+	}
+
+	for _, tc := range []struct {
+		r      rune
+		wantI  int
+		wantOK bool
+	}{
+		{'A', 0, true},
+		{0x00ff, 1, true},
+		{0x0530, 10, true},
+		{0x05d0, 11, true},
+		{0x0627, 13, true},
+		{0x0710, 71, true},
+		{0x0760, 13, true},
+		{0x0800, 0, false},
+		{0x3042, 49, true},
+		{0x4e16, 59, true},
+		{0xac00, 56, true},
+		{0xfb50, 63, true},
+		{0xfeff, 67, true},
+		{0x10300, 85, true},
+		{0x1f600, 57, true}, // Emoticons, which has no bit of its own.
+		{0x20000, 59, true},
+		{0x10fffd, 90, true},
+		{0x110000, 0, false},
+		{-1, 0, false},
+	} {
+		i, ok := UnicodeRangeBit(tc.r)
+		if i != tc.wantI || ok != tc.wantOK {
+			t.Errorf("UnicodeRangeBit(%U): got %d, %t, want %d, %t", tc.r, i, ok, tc.wantI, tc.wantOK)
+		}
+	}
+}
+
+func TestUnicodeRangesHas(t *testing.T) {
+	u := UnicodeRanges{1 << 0, 1 << 31, 0, 1 << 26}
+	for i := -1; i < 130; i++ {
+		want := i == 0 || i == 63 || i == 122
+		if got := u.Has(i); got != want {
+			t.Errorf("Has(%d): got %t, want %t", i, got, want)
+		}
+	}
+	if !u.HasRightToLeft() {
+		t.Error("HasRightToLeft: got false, want true")
+	}
+	if !u.ContainsRune(0xfc00) {
+		t.Error("ContainsRune(U+FC00): got false, want true")
+	}
+
+	c := CodePageRanges{1 << 17, 1 << 31}
+	for _, tc := range []struct {
+		codePage int
+		want     bool
+	}{
+		{932, true},
+		{437, true},
+		{1252, false},
+		{850, false},
+	} {
+		if got := c.HasCodePage(tc.codePage); got != tc.want {
+			t.Errorf("HasCodePage(%d): got %t, want %t", tc.codePage, got, tc.want)
+		}
+	}
+}
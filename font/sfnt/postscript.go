@@ -245,6 +245,10 @@ func (p *cffParser) parse(numGlyphs int32) (ret glyphData, err error) {
 		}
 	}
 
+	// Parsing the Font Dicts of a CID-keyed font re-uses p.psi.topDict, so
+	// save the Top DICT's FDSelect location first.
+	fdSelectOffset := p.psi.topDict.fdSelect
+
 	if !p.psi.topDict.isCIDFont {
 		// Parse the Private DICT, whose location was found in the Top DICT.
 		ret.singleSubrs, err = p.parsePrivateDICT(
@@ -256,13 +260,6 @@ func (p *cffParser) parse(numGlyphs int32) (ret glyphData, err error) {
 		}
 
 	} else {
-		// Parse the Font Dict Select data, whose location was found in the Top
-		// DICT.
-		ret.fdSelect, err = p.parseFDSelect(p.psi.topDict.fdSelect, numGlyphs)
-		if err != nil {
-			return glyphData{}, err
-		}
-
 		// Parse the Font Dicts. Each one contains its own Private DICT.
 		if !p.seekFromBase(p.psi.topDict.fdArray) {
 			return glyphData{}, errInvalidCFFTable
@@ -272,6 +269,9 @@ func (p *cffParser) parse(numGlyphs int32) (ret glyphData, err error) {
 		if !ok {
 			return glyphData{}, p.err
 		}
+		if count == 0 {
+			return glyphData{}, errInvalidCFFTable
+		}
 		if count > maxNumFontDicts {
 			return glyphData{}, errUnsupportedNumberOfFontDicts
 		}
@@ -298,6 +298,14 @@ func (p *cffParser) parse(numGlyphs int32) (ret glyphData, err error) {
 			privateDicts[i].length = p.psi.topDict.privateDictLength
 		}
 
+		// Parse the Font Dict Select data, whose location was found in the Top
+		// DICT. The p.psi.topDict fields were overwritten by the Font Dicts
+		// above, so we use the value saved before parsing them.
+		ret.fdSelect, err = p.parseFDSelect(fdSelectOffset, numGlyphs, count)
+		if err != nil {
+			return glyphData{}, err
+		}
+
 		ret.multiSubrs = make([][]uint32, count)
 		for i, pd := range privateDicts {
 			ret.multiSubrs[i], err = p.parsePrivateDICT(pd.offset, pd.length)
@@ -311,9 +319,11 @@ func (p *cffParser) parse(numGlyphs int32) (ret glyphData, err error) {
 }
 
 // parseFDSelect parses the Font Dict Select data as per 5176.CFF.pdf section
-// 19 "FDSelect".
-func (p *cffParser) parseFDSelect(offset int32, numGlyphs int32) (ret fdSelect, err error) {
-	if !p.seekFromBase(p.psi.topDict.fdSelect) {
+// 19 "FDSelect". Every glyph must map to one of the numFontDicts Font Dicts, so
+// that a glyph's Private DICT (and hence its local subroutines) can be found
+// without any further validation when loading that glyph.
+func (p *cffParser) parseFDSelect(offset int32, numGlyphs int32, numFontDicts int32) (ret fdSelect, err error) {
+	if !p.seekFromBase(offset) {
 		return fdSelect{}, errInvalidCFFTable
 	}
 	if !p.read(1) {
@@ -322,20 +332,44 @@ func (p *cffParser) parseFDSelect(offset int32, numGlyphs int32) (ret fdSelect,
 	ret.format = p.buf[0]
 	switch ret.format {
 	case 0:
-		if p.end-p.offset < int(numGlyphs) {
+		ret.offset = int32(p.offset)
+		if !p.read(int(numGlyphs)) {
 			return fdSelect{}, errInvalidCFFTable
 		}
-		ret.offset = int32(p.offset)
+		for _, fd := range p.buf {
+			if int32(fd) >= numFontDicts {
+				return fdSelect{}, errInvalidCFFTable
+			}
+		}
 		return ret, nil
 	case 3:
 		if !p.read(2) {
 			return fdSelect{}, p.err
 		}
 		ret.numRanges = u16(p.buf)
-		if p.end-p.offset < 3*int(ret.numRanges)+2 {
+		if ret.numRanges == 0 {
 			return fdSelect{}, errInvalidCFFTable
 		}
 		ret.offset = int32(p.offset)
+		// Each range is a uint16 first glyph and a uint8 Font Dict index. The
+		// ranges are followed by a uint16 sentinel glyph.
+		if !p.read(3*int(ret.numRanges) + 2) {
+			return fdSelect{}, errInvalidCFFTable
+		}
+		// 5176.CFF.pdf section 19 says that "The first range must have a
+		// 'first' GID of 0" and that the "sentinel GID is set equal to the
+		// number of glyphs in the font".
+		prev, buf := -1, p.buf
+		for i := 0; i < int(ret.numRanges); i++ {
+			first := int(u16(buf[3*i:]))
+			if (i == 0 && first != 0) || first <= prev || int32(buf[3*i+2]) >= numFontDicts {
+				return fdSelect{}, errInvalidCFFTable
+			}
+			prev = first
+		}
+		if sentinel := int32(u16(buf[3*ret.numRanges:])); sentinel < numGlyphs || int(sentinel) <= prev {
+			return fdSelect{}, errInvalidCFFTable
+		}
 		return ret, nil
 	}
 	return fdSelect{}, errUnsupportedCFFFDSelectTable
@@ -982,7 +1016,9 @@ var psOperators = [...][2][]psOperator{
 	}, {
 		// 2-byte operators. The first byte is the escape byte.
 		34: {+7, "hflex", t2CHflex},
+		35: {+13, "flex", t2CFlex},
 		36: {+9, "hflex1", t2CHflex1},
+		37: {+11, "flex1", t2CFlex1},
 		// TODO: more operators.
 	}},
 }
@@ -1322,6 +1358,54 @@ func t2CHflex1(p *psInterpreter) error {
 	return nil
 }
 
+func t2CFlex(p *psInterpreter) error {
+	p.type2Charstrings.cubeTo(
+		p.argStack.a[0], p.argStack.a[1],
+		p.argStack.a[2], p.argStack.a[3],
+		p.argStack.a[4], p.argStack.a[5],
+	)
+	p.type2Charstrings.cubeTo(
+		p.argStack.a[6], p.argStack.a[7],
+		p.argStack.a[8], p.argStack.a[9],
+		p.argStack.a[10], p.argStack.a[11],
+	)
+	return nil
+}
+
+func t2CFlex1(p *psInterpreter) error {
+	// 5177.Type2.pdf section 4.1 says that the last argument, d6, is either
+	// dx6 or dy6, depending on whether the sum of the first five deltas is
+	// larger in x or in y. The other of dx6 and dy6 returns the curve to the
+	// starting y or x coordinate.
+	dx, dy := int32(0), int32(0)
+	for i := 0; i < 10; i += 2 {
+		dx += p.argStack.a[i+0]
+		dy += p.argStack.a[i+1]
+	}
+	dx6, dy6 := p.argStack.a[10], -dy
+	if abs32(dx) <= abs32(dy) {
+		dx6, dy6 = -dx, p.argStack.a[10]
+	}
+	p.type2Charstrings.cubeTo(
+		p.argStack.a[0], p.argStack.a[1],
+		p.argStack.a[2], p.argStack.a[3],
+		p.argStack.a[4], p.argStack.a[5],
+	)
+	p.type2Charstrings.cubeTo(
+		p.argStack.a[6], p.argStack.a[7],
+		p.argStack.a[8], p.argStack.a[9],
+		dx6, dy6,
+	)
+	return nil
+}
+
+func abs32(x int32) int32 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
 // subrBias returns the subroutine index bias as per 5177.Type2.pdf section 4.7
 // "Subroutine Operators".
 func subrBias(numSubroutines int) int32 {
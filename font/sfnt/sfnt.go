@@ -43,10 +43,13 @@ package sfnt // import "golang.org/x/image/font/sfnt"
 
 import (
 	"errors"
+	"fmt"
 	"image"
 	"io"
+	"sync"
 
 	"golang.org/x/image/font"
+	"golang.org/x/image/math/f32"
 	"golang.org/x/image/math/fixed"
 	"golang.org/x/text/encoding/charmap"
 )
@@ -95,6 +98,7 @@ var (
 
 	errInvalidBounds          = errors.New("sfnt: invalid bounds")
 	errInvalidCFFTable        = errors.New("sfnt: invalid CFF table")
+	errInvalidCmapSubtable    = errors.New("sfnt: invalid cmap subtable index")
 	errInvalidCmapTable       = errors.New("sfnt: invalid cmap table")
 	errInvalidDfont           = errors.New("sfnt: invalid dfont")
 	errInvalidFont            = errors.New("sfnt: invalid font")
@@ -106,6 +110,7 @@ var (
 	errInvalidHheaTable       = errors.New("sfnt: invalid hhea table")
 	errInvalidHmtxTable       = errors.New("sfnt: invalid hmtx table")
 	errInvalidKernTable       = errors.New("sfnt: invalid kern table")
+	errInvalidKerxTable       = errors.New("sfnt: invalid kerx table")
 	errInvalidLocaTable       = errors.New("sfnt: invalid loca table")
 	errInvalidLocationData    = errors.New("sfnt: invalid location data")
 	errInvalidMaxpTable       = errors.New("sfnt: invalid maxp table")
@@ -116,6 +121,7 @@ var (
 	errInvalidSourceData      = errors.New("sfnt: invalid source data")
 	errInvalidTableOffset     = errors.New("sfnt: invalid table offset")
 	errInvalidTableTagOrder   = errors.New("sfnt: invalid table tag order")
+	errInvalidTrakTable       = errors.New("sfnt: invalid trak table")
 	errInvalidUCS2String      = errors.New("sfnt: invalid UCS-2 string")
 
 	errUnsupportedCFFFDSelectTable     = errors.New("sfnt: unsupported CFF FDSelect table")
@@ -129,6 +135,7 @@ var (
 	errUnsupportedGPOSTable            = errors.New("sfnt: unsupported GPOS table")
 	errUnsupportedGlyphDataLength      = errors.New("sfnt: unsupported glyph data length")
 	errUnsupportedKernTable            = errors.New("sfnt: unsupported kern table")
+	errUnsupportedKerxTable            = errors.New("sfnt: unsupported kerx table")
 	errUnsupportedNumberOfCmapSegments = errors.New("sfnt: unsupported number of cmap segments")
 	errUnsupportedNumberOfFontDicts    = errors.New("sfnt: unsupported number of font dicts")
 	errUnsupportedNumberOfFonts        = errors.New("sfnt: unsupported number of fonts")
@@ -139,6 +146,7 @@ var (
 	errUnsupportedPostTable            = errors.New("sfnt: unsupported post table")
 	errUnsupportedRealNumberEncoding   = errors.New("sfnt: unsupported real number encoding")
 	errUnsupportedTableOffsetLength    = errors.New("sfnt: unsupported table offset or length")
+	errUnsupportedTrakTable            = errors.New("sfnt: unsupported trak table")
 	errUnsupportedType2Charstring      = errors.New("sfnt: unsupported Type 2 Charstring")
 )
 
@@ -559,6 +567,46 @@ func ParseReaderAt(src io.ReaderAt) (*Font, error) {
 	return f, nil
 }
 
+// ParseOptions are optional arguments to ParseWithOptions and
+// ParseReaderAtWithOptions.
+type ParseOptions struct {
+	// Permissive is whether to recover from some invalid tables, such as
+	// those of fonts on embedded devices, instead of returning an error. The
+	// Font's Warnings method lists the problems that were recovered from.
+	//
+	// It recovers from a head or maxp table whose length is wrong, as long as
+	// the table holds the fields that this package reads, and from a head
+	// table's unitsPerEm of zero, which becomes 1000 for PostScript fonts and
+	// 2048 for TrueType fonts.
+	Permissive bool
+}
+
+// ParseWithOptions is like Parse, but with optional arguments. A nil opts is
+// equivalent to calling Parse.
+func ParseWithOptions(src []byte, opts *ParseOptions) (*Font, error) {
+	f := &Font{src: source{b: src}}
+	if opts != nil {
+		f.permissive = opts.Permissive
+	}
+	if err := f.initialize(0, false); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// ParseReaderAtWithOptions is like ParseReaderAt, but with optional
+// arguments. A nil opts is equivalent to calling ParseReaderAt.
+func ParseReaderAtWithOptions(src io.ReaderAt, opts *ParseOptions) (*Font, error) {
+	f := &Font{src: source{r: src}}
+	if opts != nil {
+		f.permissive = opts.Permissive
+	}
+	if err := f.initialize(0, false); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
 // Font is an SFNT font.
 //
 // Many of its methods take a *Buffer argument, as re-using buffers can reduce
@@ -594,6 +642,12 @@ type Font struct {
 	// non-zero for fonts within a font collection.
 	initialOffset int32
 
+	// permissive is whether to recover from some invalid tables, and warnings
+	// describes the problems that were recovered from. See
+	// ParseOptions.Permissive.
+	permissive bool
+	warnings   []string
+
 	// https://www.microsoft.com/typography/otspec/otff.htm#otttables
 	// "Required Tables".
 	cmap table
@@ -637,22 +691,33 @@ type Font struct {
 	// TODO: hdmx, vmtx? Others?
 	kern table
 
+	// https://developer.apple.com/fonts/TrueType-Reference-Manual/RM06/Chap6.html
+	// "Apple Advanced Typography Tables".
+	kerx table
+	trak table
+
 	cached struct {
 		ascent           int32
 		capHeight        int32
+		cmapSubtable     cmapSubtable
 		finalTableOffset int32
+		fixedAdvance     int32 // The advance width of every glyph, or -1.
 		glyphData        glyphData
 		glyphIndex       glyphIndexFunc
 		bounds           [4]int16
 		descent          int32
 		indexToLocFormat bool // false means short, true means long.
 		isColorBitmap    bool
+		isDfont          bool
 		isPostScript     bool
+		isSymbol         bool
 		kernNumPairs     int32
 		kernOffset       int32
 		kernFuncs        []kernFunc
 		lineGap          int32
+		maxComplexity    GlyphComplexity // The zero value for PostScript fonts, or if unknown.
 		numHMetrics      int32
+		os2Ranges        os2Ranges
 		post             *PostTable
 		slope            [2]int32
 		unitsPerEm       Units
@@ -666,6 +731,16 @@ func (f *Font) NumGlyphs() int { return len(f.cached.glyphData.locations) - 1 }
 // UnitsPerEm returns the number of units per em for f.
 func (f *Font) UnitsPerEm() Units { return f.cached.unitsPerEm }
 
+// Warnings returns a description of each of the problems with f's data that
+// parsing it with ParseOptions.Permissive recovered from. It returns nil if
+// there were none.
+func (f *Font) Warnings() []string { return f.warnings }
+
+// warnf records a warning that parsing recovered from a problem.
+func (f *Font) warnf(format string, args ...interface{}) {
+	f.warnings = append(f.warnings, fmt.Sprintf("sfnt: "+format, args...))
+}
+
 func (f *Font) initialize(offset int, isDfont bool) error {
 	if !f.src.valid() {
 		return errInvalidSourceData
@@ -683,11 +758,11 @@ func (f *Font) initialize(offset int, isDfont bool) error {
 	// When implementing new parseXxx methods, take care not to call methods
 	// such as Font.NumGlyphs that implicitly depend on f.cached fields.
 
-	buf, bounds, indexToLocFormat, unitsPerEm, err := f.parseHead(buf)
+	buf, bounds, indexToLocFormat, unitsPerEm, err := f.parseHead(buf, isPostScript)
 	if err != nil {
 		return err
 	}
-	buf, numGlyphs, err := f.parseMaxp(buf, isPostScript)
+	buf, numGlyphs, maxComplexity, err := f.parseMaxp(buf, isPostScript)
 	if err != nil {
 		return err
 	}
@@ -695,7 +770,7 @@ func (f *Font) initialize(offset int, isDfont bool) error {
 	if err != nil {
 		return err
 	}
-	buf, glyphIndex, err := f.parseCmap(buf)
+	buf, glyphIndex, cmapSubtable, err := f.parseCmap(buf)
 	if err != nil {
 		return err
 	}
@@ -707,11 +782,19 @@ func (f *Font) initialize(offset int, isDfont bool) error {
 	if err != nil {
 		return err
 	}
+	if kernFuncs == nil {
+		// Without GPOS kerning, prefer Apple's extended kerning table, if
+		// present, over the kern table.
+		buf, kernFuncs, err = f.parseKerx(buf, numGlyphs)
+		if err != nil {
+			return err
+		}
+	}
 	buf, ascent, descent, lineGap, run, rise, numHMetrics, err := f.parseHhea(buf, numGlyphs)
 	if err != nil {
 		return err
 	}
-	buf, err = f.parseHmtx(buf, numGlyphs, numHMetrics)
+	buf, fixedAdvance, err := f.parseHmtx(buf, numGlyphs, numHMetrics)
 	if err != nil {
 		return err
 	}
@@ -719,6 +802,10 @@ func (f *Font) initialize(offset int, isDfont bool) error {
 	if err != nil {
 		return err
 	}
+	buf, os2Ranges, err := f.parseOS2Ranges(buf)
+	if err != nil {
+		return err
+	}
 	buf, post, err := f.parsePost(buf, numGlyphs)
 	if err != nil {
 		return err
@@ -726,19 +813,25 @@ func (f *Font) initialize(offset int, isDfont bool) error {
 
 	f.cached.ascent = ascent
 	f.cached.capHeight = capHeight
+	f.cached.cmapSubtable = cmapSubtable
 	f.cached.finalTableOffset = finalTableOffset
+	f.cached.fixedAdvance = fixedAdvance
 	f.cached.glyphData = glyphData
 	f.cached.glyphIndex = glyphIndex
 	f.cached.bounds = bounds
 	f.cached.descent = descent
 	f.cached.indexToLocFormat = indexToLocFormat
 	f.cached.isColorBitmap = isColorBitmap
+	f.cached.isDfont = isDfont
+	f.cached.isSymbol = cmapSubtable.isSymbol()
 	f.cached.isPostScript = isPostScript
 	f.cached.kernNumPairs = kernNumPairs
 	f.cached.kernOffset = kernOffset
 	f.cached.kernFuncs = kernFuncs
 	f.cached.lineGap = lineGap
+	f.cached.maxComplexity = maxComplexity
 	f.cached.numHMetrics = numHMetrics
+	f.cached.os2Ranges = os2Ranges
 	f.cached.post = post
 	f.cached.slope = [2]int32{run, rise}
 	f.cached.unitsPerEm = unitsPerEm
@@ -817,8 +910,8 @@ func (f *Font) initializeTables(offset int, isDfont bool) (buf1 []byte, finalTab
 		if o > maxTableOffset || n > maxTableLength {
 			return nil, 0, false, errUnsupportedTableOffsetLength
 		}
-		// We ignore the checksums, but "all tables must begin on four byte
-		// boundries [sic]".
+		// We ignore the checksums, which VerifyChecksums checks, but "all
+		// tables must begin on four byte boundries [sic]".
 		if o&3 != 0 {
 			return nil, 0, false, errInvalidTableOffset
 		}
@@ -848,6 +941,8 @@ func (f *Font) initializeTables(offset int, isDfont bool) (buf1 []byte, finalTab
 			f.hmtx = table{o, n}
 		case 0x6b65726e:
 			f.kern = table{o, n}
+		case 0x6b657278:
+			f.kerx = table{o, n}
 		case 0x6c6f6361:
 			f.loca = table{o, n}
 		case 0x6d617870:
@@ -856,6 +951,8 @@ func (f *Font) initializeTables(offset int, isDfont bool) (buf1 []byte, finalTab
 			f.name = table{o, n}
 		case 0x706f7374:
 			f.post = table{o, n}
+		case 0x7472616b:
+			f.trak = table{o, n}
 		}
 	}
 
@@ -865,50 +962,52 @@ func (f *Font) initializeTables(offset int, isDfont bool) (buf1 []byte, finalTab
 	return buf, finalTableOffset, isPostScript, nil
 }
 
-func (f *Font) parseCmap(buf []byte) (buf1 []byte, glyphIndex glyphIndexFunc, err error) {
+func (f *Font) parseCmap(buf []byte) (buf1 []byte, glyphIndex glyphIndexFunc, subtable cmapSubtable, err error) {
 	// https://www.microsoft.com/typography/OTSPEC/cmap.htm
 
 	const headerSize, entrySize = 4, 8
 	if f.cmap.length < headerSize {
-		return nil, nil, errInvalidCmapTable
+		return nil, nil, cmapSubtable{}, errInvalidCmapTable
 	}
 	u, err := f.src.u16(buf, f.cmap, 2)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, cmapSubtable{}, err
 	}
 	numSubtables := int(u)
 	if f.cmap.length < headerSize+entrySize*uint32(numSubtables) {
-		return nil, nil, errInvalidCmapTable
+		return nil, nil, cmapSubtable{}, errInvalidCmapTable
 	}
 
 	var (
-		bestWidth  int
-		bestOffset uint32
-		bestLength uint32
-		bestFormat uint16
+		bestWidth   int
+		bestUnicode bool
+		best        cmapSubtable
 	)
 
 	// Scan all of the subtables, picking the widest supported one. See the
-	// platformEncodingWidth comment for more discussion of width.
+	// platformEncodingWidth comment for more discussion of width. For equal
+	// widths, a Unicode subtable is preferred over one for a legacy or symbol
+	// encoding.
 	for i := 0; i < numSubtables; i++ {
 		buf, err = f.src.view(buf, int(f.cmap.offset)+headerSize+entrySize*i, entrySize)
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, cmapSubtable{}, err
 		}
 		pid := u16(buf)
 		psid := u16(buf[2:])
 		width := platformEncodingWidth(pid, psid)
-		if width <= bestWidth {
+		unicode := legacyEncoding(pid, psid) == nil && !(pid == pidWindows && psid == psidWindowsSymbol)
+		if width == 0 || width < bestWidth || (width == bestWidth && (bestUnicode || !unicode)) {
 			continue
 		}
 		offset := u32(buf[4:])
 
 		if offset > f.cmap.length-4 {
-			return nil, nil, errInvalidCmapTable
+			return nil, nil, cmapSubtable{}, errInvalidCmapTable
 		}
 		buf, err = f.src.view(buf, int(f.cmap.offset+offset), 4)
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, cmapSubtable{}, err
 		}
 		format := u16(buf)
 		if !supportedCmapFormat(format, pid, psid) {
@@ -917,22 +1016,41 @@ func (f *Font) parseCmap(buf []byte) (buf1 []byte, glyphIndex glyphIndexFunc, er
 		length := uint32(u16(buf[2:]))
 
 		bestWidth = width
-		bestOffset = offset
-		bestLength = length
-		bestFormat = format
+		bestUnicode = unicode
+		best = cmapSubtable{
+			index:  i,
+			offset: offset,
+			length: length,
+			format: format,
+			pid:    pid,
+			psid:   psid,
+		}
 	}
 
 	if bestWidth == 0 {
-		return nil, nil, errUnsupportedCmapEncodings
+		return nil, nil, cmapSubtable{}, errUnsupportedCmapEncodings
 	}
-	return f.makeCachedGlyphIndex(buf, bestOffset, bestLength, bestFormat)
+	buf, glyphIndex, err = f.makeCachedGlyphIndex(buf, best.offset, best.length, best.format, best.pid, best.psid)
+	if err != nil {
+		return nil, nil, cmapSubtable{}, err
+	}
+	buf, best.language, err = f.cmapLanguage(buf, best.offset, best.format)
+	if err != nil {
+		return nil, nil, cmapSubtable{}, err
+	}
+	return buf, glyphIndex, best, nil
 }
 
-func (f *Font) parseHead(buf []byte) (buf1 []byte, bounds [4]int16, indexToLocFormat bool, unitsPerEm Units, err error) {
+func (f *Font) parseHead(buf []byte, isPostScript bool) (buf1 []byte, bounds [4]int16, indexToLocFormat bool, unitsPerEm Units, err error) {
 	// https://www.microsoft.com/typography/otspec/head.htm
 
 	if f.head.length != 54 {
-		return nil, [4]int16{}, false, 0, errInvalidHeadTable
+		// The last field that this package reads, indexToLocFormat, ends at
+		// offset 52.
+		if !f.permissive || f.head.length < 52 {
+			return nil, [4]int16{}, false, 0, errInvalidHeadTable
+		}
+		f.warnf("head table length is %d, want 54", f.head.length)
 	}
 
 	u, err := f.src.u16(buf, f.head, 18)
@@ -940,7 +1058,16 @@ func (f *Font) parseHead(buf []byte) (buf1 []byte, bounds [4]int16, indexToLocFo
 		return nil, [4]int16{}, false, 0, err
 	}
 	if u == 0 {
-		return nil, [4]int16{}, false, 0, errInvalidHeadTable
+		if !f.permissive {
+			return nil, [4]int16{}, false, 0, errInvalidHeadTable
+		}
+		// PostScript fonts' default FontMatrix has 1000 units per em, and
+		// TrueType fonts typically have 2048.
+		u = 2048
+		if isPostScript {
+			u = 1000
+		}
+		f.warnf("head table unitsPerEm is 0, using %d", u)
 	}
 	unitsPerEm = Units(u)
 
@@ -996,16 +1123,26 @@ func (f *Font) parseHhea(buf []byte, numGlyphs int32) (buf1 []byte, ascent, desc
 	return buf, int32(int16(a)), int32(int16(d)), int32(int16(l)), int32(int16(ru)), int32(int16(ri)), int32(u), nil
 }
 
-func (f *Font) parseHmtx(buf []byte, numGlyphs, numHMetrics int32) (buf1 []byte, err error) {
+func (f *Font) parseHmtx(buf []byte, numGlyphs, numHMetrics int32) (buf1 []byte, fixedAdvance int32, err error) {
 	// https://www.microsoft.com/typography/OTSPEC/hmtx.htm
 
 	// The spec says that the hmtx table's length should be
 	// "4*numHMetrics+2*(numGlyphs-numHMetrics)". However, some fonts seen in the
 	// wild omit the "2*(nG-nHM)". See https://github.com/golang/go/issues/28379
 	if f.hmtx.length != uint32(4*numHMetrics) && f.hmtx.length != uint32(4*numHMetrics+2*(numGlyphs-numHMetrics)) {
-		return nil, errInvalidHmtxTable
+		return nil, 0, errInvalidHmtxTable
 	}
-	return buf, nil
+
+	// With a single record, as in many monospaced fonts, every glyph has that
+	// record's advance width, and advance queries need not view the table.
+	if numHMetrics != 1 {
+		return buf, -1, nil
+	}
+	u, err := f.src.u16(buf, f.hmtx, 0)
+	if err != nil {
+		return nil, 0, err
+	}
+	return buf, int32(u), nil
 }
 
 func (f *Font) parseKern(buf []byte) (buf1 []byte, kernNumPairs, kernOffset int32, err error) {
@@ -1110,23 +1247,42 @@ func (f *Font) parseKernFormat0(buf []byte, offset, length int, subtableLengthU1
 	return buf, kernNumPairs, int32(offset) + headerSize, nil
 }
 
-func (f *Font) parseMaxp(buf []byte, isPostScript bool) (buf1 []byte, numGlyphs int32, err error) {
+func (f *Font) parseMaxp(buf []byte, isPostScript bool) (buf1 []byte, numGlyphs int32, maxComplexity GlyphComplexity, err error) {
 	// https://www.microsoft.com/typography/otspec/maxp.htm
 
+	wantLength := uint32(32)
 	if isPostScript {
-		if f.maxp.length != 6 {
-			return nil, 0, errInvalidMaxpTable
-		}
-	} else {
-		if f.maxp.length != 32 {
-			return nil, 0, errInvalidMaxpTable
+		wantLength = 6
+	}
+	if f.maxp.length != wantLength {
+		// The numGlyphs field ends at offset 6.
+		if !f.permissive || f.maxp.length < 6 {
+			return nil, 0, GlyphComplexity{}, errInvalidMaxpTable
 		}
+		f.warnf("maxp table length is %d, want %d", f.maxp.length, wantLength)
 	}
-	u, err := f.src.u16(buf, f.maxp, 4)
+	buf, err = f.src.view(buf, int(f.maxp.offset), int(f.maxp.length))
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, GlyphComplexity{}, err
+	}
+	if !isPostScript && len(buf) >= 32 {
+		// Version 1.0 of the table records the maximum number of points and
+		// contours for simple glyphs (at offsets 6 and 8) and for compound
+		// glyphs (at offsets 10 and 12), and the maximum number of
+		// top-level components (at offset 28).
+		maxComplexity = GlyphComplexity{
+			Contours:   int(u16(buf[8:])),
+			Points:     int(u16(buf[6:])),
+			Components: int(u16(buf[28:])),
+		}
+		if n := int(u16(buf[12:])); maxComplexity.Contours < n {
+			maxComplexity.Contours = n
+		}
+		if n := int(u16(buf[10:])); maxComplexity.Points < n {
+			maxComplexity.Points = n
+		}
 	}
-	return buf, int32(u), nil
+	return buf, int32(u16(buf[4:])), maxComplexity, nil
 }
 
 type glyphData struct {
@@ -1277,6 +1433,16 @@ type PostTable struct {
 	IsFixedPitch bool
 }
 
+// IsFixedPitch reports whether f is monospaced: whether its "post" table marks
+// it as fixed pitch, or its "hmtx" table gives every glyph the same advance
+// width. If so, callers such as terminal emulators can call GlyphAdvance once
+// instead of for every glyph. Some fixed pitch fonts still have glyphs, such
+// as combining marks or wide characters, whose advance width is zero or a
+// multiple of the usual advance width.
+func (f *Font) IsFixedPitch() bool {
+	return f.cached.fixedAdvance >= 0 || (f.cached.post != nil && f.cached.post.IsFixedPitch)
+}
+
 // PostTable returns the information from the font's "post" table. It can
 // return nil, if the font doesn't have such a table.
 //
@@ -1376,10 +1542,55 @@ type glyphIndexFunc func(f *Font, b *Buffer, r rune) (GlyphIndex, error)
 // codes that do not correspond to any glyph in the font should be mapped to
 // glyph index 0. The glyph at this location must be a special glyph
 // representing a missing character, commonly known as .notdef."
+//
+// For symbol-encoded fonts, such as Wingdings, whose character codes are
+// typically in the U+F020 to U+F0FF range of the Private Use Area, a rune r
+// from U+0020 to U+00FF is first looked up as U+F000 + r, the same as Windows
+// does. If that has no glyph, r itself is looked up. Use RawGlyphIndex to
+// look up r without that remapping.
 func (f *Font) GlyphIndex(b *Buffer, r rune) (GlyphIndex, error) {
+	if f.cached.isSymbol && 0x20 <= r && r <= 0xff {
+		x, err := f.cached.glyphIndex(f, b, 0xf000+r)
+		if x != 0 || err != nil {
+			return x, err
+		}
+	}
+	return f.cached.glyphIndex(f, b, r)
+}
+
+// RawGlyphIndex is like GlyphIndex, but it never remaps the runes of
+// symbol-encoded fonts.
+func (f *Font) RawGlyphIndex(b *Buffer, r rune) (GlyphIndex, error) {
 	return f.cached.glyphIndex(f, b, r)
 }
 
+// AllRunes calls fn for each rune that f's character map maps to a non-zero
+// glyph index, in increasing order, until fn returns false. For the common
+// cmap subtable formats, it walks the subtable's ranges of runes, which is
+// much faster than calling GlyphIndex for every rune.
+//
+// The runes are f's character codes, without the remapping that GlyphIndex
+// does for symbol-encoded fonts, as for RawGlyphIndex.
+//
+// fn must not call Font methods with the same *Buffer b.
+func (f *Font) AllRunes(b *Buffer, fn func(r rune, x GlyphIndex) bool) error {
+	if b == nil {
+		b = &Buffer{}
+	}
+	switch f.cached.cmapSubtable.format {
+	case 4:
+		return f.allRunesFormat4(b, fn)
+	case 12:
+		return f.allRunesFormat12(b, fn)
+	}
+	return f.allRunesProbe(b, fn)
+}
+
+// IsSymbolEncoded returns whether f's character map is for the Windows Symbol
+// encoding, as used by fonts such as Wingdings, instead of for Unicode. See
+// GlyphIndex for how such fonts' character codes are looked up.
+func (f *Font) IsSymbolEncoded() bool { return f.cached.isSymbol }
+
 func (f *Font) viewGlyphData(b *Buffer, x GlyphIndex) (buf []byte, offset, length uint32, err error) {
 	xx := int(x)
 	if f.NumGlyphs() <= xx {
@@ -1416,24 +1627,7 @@ func (f *Font) LoadGlyph(b *Buffer, x GlyphIndex, ppem fixed.Int26_6, opts *Load
 	if b == nil {
 		b = &Buffer{}
 	}
-
-	b.segments = b.segments[:0]
-	if f.cached.isColorBitmap {
-		return nil, ErrColoredGlyph
-	}
-	if f.cached.isPostScript {
-		buf, offset, length, err := f.viewGlyphData(b, x)
-		if err != nil {
-			return nil, err
-		}
-		b.psi.type2Charstrings.initialize(f, b, x)
-		if err := b.psi.run(psContextType2Charstring, buf, offset, length); err != nil {
-			return nil, err
-		}
-		if !b.psi.type2Charstrings.ended {
-			return nil, errInvalidCFFTable
-		}
-	} else if err := loadGlyf(f, b, x, 0, 0); err != nil {
+	if err := f.loadGlyphUnscaled(b, x); err != nil {
 		return nil, err
 	}
 
@@ -1458,6 +1652,67 @@ func (f *Font) LoadGlyph(b *Buffer, x GlyphIndex, ppem fixed.Int26_6, opts *Load
 	return b.segments, nil
 }
 
+// LoadGlyphF32 is like LoadGlyph but returns the segments' coordinates as
+// float32 values instead of fixed.Int26_6 values, scaled so that 1 em is em
+// units long. For example, an em of 1 gives coordinates normalized to the em
+// square, independent of the font's units per em.
+//
+// The float32 coordinates are not rounded to the 26.6 fixed point grid, so that
+// they are suitable for uploading directly to a GPU.
+//
+// If b is non-nil, the segments become invalid to use once b is re-used.
+//
+// In the returned segments' (x, y) coordinates, the Y axis increases down.
+//
+// It returns ErrNotFound if the glyph index is out of range. It returns
+// ErrColoredGlyph if the glyph is not a monochrome vector glyph, such as a
+// colored (bitmap or vector) emoji glyph.
+func (f *Font) LoadGlyphF32(b *Buffer, x GlyphIndex, em float32) ([]SegmentF32, error) {
+	if b == nil {
+		b = &Buffer{}
+	}
+	if err := f.loadGlyphUnscaled(b, x); err != nil {
+		return nil, err
+	}
+
+	// As for LoadGlyph, flip the Y coordinates.
+	k := em / float32(f.cached.unitsPerEm)
+	b.segmentsF32 = b.segmentsF32[:0]
+	for _, seg := range b.segments {
+		s := SegmentF32{Op: seg.Op}
+		for j, a := range seg.Args {
+			s.Args[j] = f32.Vec2{+float32(a.X) * k, -float32(a.Y) * k}
+		}
+		b.segmentsF32 = append(b.segmentsF32, s)
+	}
+	return b.segmentsF32, nil
+}
+
+// loadGlyphUnscaled loads the x'th glyph's segments into b.segments, in font
+// units and with the Y axis increasing up.
+func (f *Font) loadGlyphUnscaled(b *Buffer, x GlyphIndex) error {
+	b.segments = b.segments[:0]
+	if f.cached.isColorBitmap {
+		return ErrColoredGlyph
+	}
+	if f.cached.isPostScript {
+		buf, offset, length, err := f.viewGlyphData(b, x)
+		if err != nil {
+			return err
+		}
+		b.psi.type2Charstrings.initialize(f, b, x)
+		if err := b.psi.run(psContextType2Charstring, buf, offset, length); err != nil {
+			return err
+		}
+		if !b.psi.type2Charstrings.ended {
+			return errInvalidCFFTable
+		}
+	} else if err := loadGlyf(f, b, x, 0, 0); err != nil {
+		return err
+	}
+	return nil
+}
+
 func (f *Font) glyphNameFormat10(x GlyphIndex) (string, error) {
 	if x >= numBuiltInPostNames {
 		return "", ErrNotFound
@@ -1550,6 +1805,72 @@ func (f *Font) GlyphName(b *Buffer, x GlyphIndex) (string, error) {
 	}
 }
 
+// GlyphComplexity measures how complex a glyph's outline is, such as for
+// budgeting how much work tessellating or rasterizing it takes.
+type GlyphComplexity struct {
+	// Contours is the number of contours, or closed subpaths.
+	Contours int
+	// Points is the number of control points, both on and off the curve.
+	Points int
+	// Components is the number of components of a TrueType compound glyph,
+	// not counting the components' own components. It is zero for other
+	// glyphs.
+	Components int
+}
+
+// GlyphComplexity returns the complexity of the x'th glyph. The contours and
+// points of a TrueType compound glyph are those of all of its components.
+//
+// For TrueType fonts, only the glyph data's headers are read, which is
+// cheaper than loading the glyph's segments. For PostScript fonts, the glyph's
+// segments are loaded and counted, with each segment's end point counting as a
+// point.
+//
+// It returns ErrNotFound if the glyph index is out of range. It returns
+// ErrColoredGlyph if the glyph is not a monochrome vector glyph.
+func (f *Font) GlyphComplexity(b *Buffer, x GlyphIndex) (GlyphComplexity, error) {
+	if b == nil {
+		b = &Buffer{}
+	}
+	if f.cached.isColorBitmap {
+		return GlyphComplexity{}, ErrColoredGlyph
+	}
+	if !f.cached.isPostScript {
+		return glyfComplexity(f, b, x, 0, 0)
+	}
+	if err := f.loadGlyphUnscaled(b, x); err != nil {
+		return GlyphComplexity{}, err
+	}
+	c := GlyphComplexity{}
+	for _, seg := range b.segments {
+		switch seg.Op {
+		case SegmentOpMoveTo:
+			c.Contours++
+			c.Points++
+		case SegmentOpLineTo:
+			c.Points++
+		case SegmentOpQuadTo:
+			c.Points += 2
+		case SegmentOpCubeTo:
+			c.Points += 3
+		}
+	}
+	return c, nil
+}
+
+// MaxGlyphComplexity returns the maximum complexity of any of f's glyphs, as
+// recorded in the font's maxp table, so that it costs nothing to call. A
+// font's recorded values may be larger than any glyph's actual ones.
+//
+// Each field is maximized separately. The Contours and Points fields are the
+// larger of the maxima for simple and compound glyphs.
+//
+// It returns false if the font does not record the maxima, which is the case
+// for PostScript fonts.
+func (f *Font) MaxGlyphComplexity() (GlyphComplexity, bool) {
+	return f.cached.maxComplexity, !f.cached.isPostScript
+}
+
 // GlyphBounds returns the bounding box of the x'th glyph, drawn at a dot equal
 // to the origin, and that glyph's advance width. ppem is the number of pixels
 // in 1 em.
@@ -1577,16 +1898,10 @@ func (f *Font) GlyphBounds(b *Buffer, x GlyphIndex, ppem fixed.Int26_6, h font.H
 		metricIndex = n
 	}
 
-	buf, err := b.view(&f.src, int(f.hmtx.offset)+4*int(metricIndex), 2)
+	advance, err = f.glyphAdvance(b, metricIndex, ppem, h)
 	if err != nil {
 		return fixed.Rectangle26_6{}, 0, err
 	}
-	advance = fixed.Int26_6(u16(buf))
-	advance = scale(advance*ppem, f.cached.unitsPerEm)
-	if h == font.HintingFull {
-		// Quantize the fixed.Int26_6 value to the nearest pixel.
-		advance = (advance + 32) &^ 63
-	}
 
 	// Ignore the hmtx LSB entries and the glyf bounding boxes. Instead, always
 	// calculate bounds from the segments. OpenType does contain the bounds for
@@ -1622,18 +1937,83 @@ func (f *Font) GlyphAdvance(b *Buffer, x GlyphIndex, ppem fixed.Int26_6, h font.
 	if n := GlyphIndex(f.cached.numHMetrics - 1); x > n {
 		x = n
 	}
+	return f.glyphAdvance(b, x, ppem, h)
+}
 
+// glyphAdvance returns the advance width of the x'th hmtx record, which must
+// be in range, scaled to ppem.
+func (f *Font) glyphAdvance(b *Buffer, x GlyphIndex, ppem fixed.Int26_6, h font.Hinting) (fixed.Int26_6, error) {
+	if f.cached.fixedAdvance >= 0 {
+		return scaleAdvance(fixed.Int26_6(f.cached.fixedAdvance), ppem, f.cached.unitsPerEm, h), nil
+	}
 	buf, err := b.view(&f.src, int(f.hmtx.offset)+4*int(x), 2)
 	if err != nil {
 		return 0, err
 	}
-	adv := fixed.Int26_6(u16(buf))
-	adv = scale(adv*ppem, f.cached.unitsPerEm)
+	return scaleAdvance(fixed.Int26_6(u16(buf)), ppem, f.cached.unitsPerEm, h), nil
+}
+
+// scaleAdvance scales the advance width adv, in font units, to ppem.
+func scaleAdvance(adv, ppem fixed.Int26_6, unitsPerEm Units, h font.Hinting) fixed.Int26_6 {
+	adv = scale(adv*ppem, unitsPerEm)
 	if h == font.HintingFull {
 		// Quantize the fixed.Int26_6 value to the nearest pixel.
 		adv = (adv + 32) &^ 63
 	}
-	return adv, nil
+	return adv
+}
+
+// GlyphAdvances returns the advance widths for the glyphs xs, in the same
+// order. ppem is the number of pixels in 1 em.
+//
+// It is equivalent to calling GlyphAdvance for each element of xs, but the
+// hmtx table is viewed and bounds checked once for the whole slice, which is
+// faster for long runs of text such as a paragraph.
+//
+// It returns ErrNotFound if any glyph index is out of range.
+func (f *Font) GlyphAdvances(b *Buffer, xs []GlyphIndex, ppem fixed.Int26_6, h font.Hinting) ([]fixed.Int26_6, error) {
+	if len(xs) == 0 {
+		return nil, nil
+	}
+	if b == nil {
+		b = &Buffer{}
+	}
+
+	// As per GlyphAdvance, glyphs past the last hmtx record use that last
+	// record's advance width. Only view as many records as are needed.
+	numGlyphs, lastMetric := f.NumGlyphs(), GlyphIndex(f.cached.numHMetrics-1)
+	maxX := GlyphIndex(0)
+	for _, x := range xs {
+		if int(x) >= numGlyphs {
+			return nil, ErrNotFound
+		}
+		if maxX < x {
+			maxX = x
+		}
+	}
+	if maxX > lastMetric {
+		maxX = lastMetric
+	}
+
+	advances := make([]fixed.Int26_6, len(xs))
+	if f.cached.fixedAdvance >= 0 {
+		adv := scaleAdvance(fixed.Int26_6(f.cached.fixedAdvance), ppem, f.cached.unitsPerEm, h)
+		for i := range advances {
+			advances[i] = adv
+		}
+		return advances, nil
+	}
+	buf, err := b.view(&f.src, int(f.hmtx.offset), 4*(int(maxX)+1))
+	if err != nil {
+		return nil, err
+	}
+	for i, x := range xs {
+		if x > lastMetric {
+			x = lastMetric
+		}
+		advances[i] = scaleAdvance(fixed.Int26_6(u16(buf[4*int(x):])), ppem, f.cached.unitsPerEm, h)
+	}
+	return advances, nil
 }
 
 // Kern returns the horizontal adjustment for the kerning pair (x0, x1). A
@@ -1880,11 +2260,24 @@ func stringifyUCS2(b []byte) (string, error) {
 // of repeated Font method calls.
 //
 // See the Font type's documentation comment for more details.
+//
+// A Buffer retains, between calls, the memory that its slices have grown to:
+// a byte buffer, as large as the largest table or glyph data read from a Font
+// whose source is an io.ReaderAt, and the segments of the largest glyph
+// loaded. The Segments returned by LoadGlyph, and the []SegmentF32 returned by
+// LoadGlyphF32, are that memory, and are overwritten by the next call. After
+// loading a glyph from an OpenType/CFF font, a Buffer also refers to that Font
+// until its next such call, which keeps the Font and its source alive.
+//
+// A BufferPool can share Buffers between goroutines without retaining more
+// than that.
 type Buffer struct {
 	// buf is a byte buffer for when a Font's source is an io.ReaderAt.
 	buf []byte
 	// segments holds glyph vector path segments.
 	segments Segments
+	// segmentsF32 holds glyph vector path segments with float32 coordinates.
+	segmentsF32 []SegmentF32
 	// compoundStack holds the components of a TrueType compound glyph.
 	compoundStack [maxCompoundStackSize]struct {
 		glyphIndex   GlyphIndex
@@ -1912,6 +2305,62 @@ func (b *Buffer) view(src *source, offset, length int) ([]byte, error) {
 	return buf, nil
 }
 
+// These constants bound the memory that a Buffer in a BufferPool retains
+// between uses. Buffers that have grown larger have those slices dropped when
+// they are put back in the pool.
+const (
+	maxPooledBufLen      = 64 * 1024
+	maxPooledSegmentsLen = 4096
+)
+
+// reset prepares b for re-use by an unrelated caller, dropping its reference
+// to any Font and any oversized slices.
+func (b *Buffer) reset() {
+	b.psi = psInterpreter{}
+	if cap(b.buf) > maxPooledBufLen {
+		b.buf = nil
+	}
+	if cap(b.segments) > maxPooledSegmentsLen {
+		b.segments = nil
+	}
+	if cap(b.segmentsF32) > maxPooledSegmentsLen {
+		b.segmentsF32 = nil
+	}
+}
+
+// BufferPool is a pool of Buffers, for servers that call Font methods from
+// many goroutines. It is safe to use concurrently.
+//
+// A Buffer that is put back in the pool no longer refers to any Font, and
+// keeps only moderately sized slices for re-use.
+type BufferPool struct {
+	p sync.Pool
+}
+
+// NewBufferPool returns a new, empty BufferPool.
+func NewBufferPool() *BufferPool {
+	return &BufferPool{
+		p: sync.Pool{
+			New: func() interface{} { return new(Buffer) },
+		},
+	}
+}
+
+// Get returns a Buffer from the pool, allocating one if the pool is empty.
+func (p *BufferPool) Get() *Buffer {
+	return p.p.Get().(*Buffer)
+}
+
+// Put puts b back in the pool. The caller must not use b, or any Segments or
+// []SegmentF32 returned by a Font method given b, after calling Put.
+func (p *BufferPool) Put(b *Buffer) {
+	if b == nil {
+		return
+	}
+	b.reset()
+	p.p.Put(b)
+}
+
 // Segment is a segment of a vector path.
 type Segment struct {
 	// Op is the operator.
@@ -1920,6 +2369,15 @@ type Segment struct {
 	Args [3]fixed.Point26_6
 }
 
+// SegmentF32 is like Segment but with float32 coordinates. The Y axis
+// increases down.
+type SegmentF32 struct {
+	// Op is the operator.
+	Op SegmentOp
+	// Args is up to three (x, y) coordinates.
+	Args [3]f32.Vec2
+}
+
 // SegmentOp is a vector path segment's operator.
 type SegmentOp uint32
 
@@ -6,10 +6,12 @@ package sfnt
 
 import (
 	"bytes"
+	"encoding/binary"
 	"fmt"
 	"image"
 	"io/ioutil"
 	"path/filepath"
+	"reflect"
 	"testing"
 
 	"golang.org/x/image/font"
@@ -136,6 +138,87 @@ func TestTrueTypeParseReaderAt(t *testing.T) {
 	testTrueType(t, f, goregular.TTF)
 }
 
+func TestParsePermissive(t *testing.T) {
+	cffTest, err := ioutil.ReadFile(filepath.FromSlash("../testdata/CFFTest.otf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// withTableLength returns a copy of src whose table record for tag has
+	// the given length.
+	withTableLength := func(src []byte, tag string, length uint32) []byte {
+		dst := append([]byte(nil), src...)
+		numTables := int(binary.BigEndian.Uint16(dst[4:]))
+		for i := 0; i < numTables; i++ {
+			if r := dst[12+16*i:]; string(r[:4]) == tag {
+				binary.BigEndian.PutUint32(r[12:], length)
+				return dst
+			}
+		}
+		t.Fatalf("table %q not found", tag)
+		return nil
+	}
+	// withZeroUnitsPerEm returns a copy of src whose head table's unitsPerEm
+	// is zero.
+	withZeroUnitsPerEm := func(src []byte) []byte {
+		dst := append([]byte(nil), src...)
+		binary.BigEndian.PutUint16(dst[tableOffset(t, dst, "head")+18:], 0)
+		return dst
+	}
+
+	testCases := []struct {
+		desc           string
+		src            []byte
+		wantUnitsPerEm Units
+		wantWarnings   int
+	}{
+		{"valid", goregular.TTF, 2048, 0},
+		{"long head", withTableLength(goregular.TTF, "head", 56), 2048, 1},
+		{"short head", withTableLength(goregular.TTF, "head", 52), 2048, 1},
+		{"short maxp", withTableLength(goregular.TTF, "maxp", 6), 2048, 1},
+		{"long PostScript maxp", withTableLength(cffTest, "maxp", 32), 1000, 1},
+		{"zero unitsPerEm", withZeroUnitsPerEm(goregular.TTF), 2048, 1},
+		{"zero PostScript unitsPerEm", withZeroUnitsPerEm(cffTest), 1000, 1},
+		{"short head, zero unitsPerEm", withTableLength(withZeroUnitsPerEm(goregular.TTF), "head", 53), 2048, 2},
+	}
+	for _, tc := range testCases {
+		if tc.wantWarnings != 0 {
+			if _, err := Parse(tc.src); err == nil {
+				t.Errorf("%s: Parse: got nil error", tc.desc)
+			}
+		}
+		for _, readerAt := range []bool{false, true} {
+			var f *Font
+			if readerAt {
+				f, err = ParseReaderAtWithOptions(bytes.NewReader(tc.src), &ParseOptions{Permissive: true})
+			} else {
+				f, err = ParseWithOptions(tc.src, &ParseOptions{Permissive: true})
+			}
+			if err != nil {
+				t.Errorf("%s, readerAt=%t: %v", tc.desc, readerAt, err)
+				continue
+			}
+			if got := f.UnitsPerEm(); got != tc.wantUnitsPerEm {
+				t.Errorf("%s, readerAt=%t: UnitsPerEm: got %d, want %d", tc.desc, readerAt, got, tc.wantUnitsPerEm)
+			}
+			if got := f.Warnings(); len(got) != tc.wantWarnings {
+				t.Errorf("%s, readerAt=%t: Warnings: got %q, want %d warnings", tc.desc, readerAt, got, tc.wantWarnings)
+			}
+			if f.NumGlyphs() == 0 {
+				t.Errorf("%s, readerAt=%t: NumGlyphs: got 0", tc.desc, readerAt)
+			}
+		}
+	}
+
+	// Tables that are too short to hold the fields that are read are still
+	// invalid.
+	for _, tag := range []string{"head", "maxp"} {
+		src := withTableLength(goregular.TTF, tag, 4)
+		if _, err := ParseWithOptions(src, &ParseOptions{Permissive: true}); err == nil {
+			t.Errorf("short %s: got nil error", tag)
+		}
+	}
+}
+
 func testTrueType(t *testing.T, f *Font, wantSrc []byte) {
 	if got, want := f.UnitsPerEm(), Units(2048); got != want {
 		t.Errorf("UnitsPerEm: got %d, want %d", got, want)
@@ -410,6 +493,215 @@ func TestGlyphAdvance(t *testing.T) {
 	}
 }
 
+func TestGlyphAdvances(t *testing.T) {
+	for _, name := range []string{"gobold", "gomono", "goregular"} {
+		f, err := Parse(fontData(name))
+		if err != nil {
+			t.Errorf("Parse(%q): %v", name, err)
+			continue
+		}
+		ppem := fixed.Int26_6(f.UnitsPerEm())
+
+		var b Buffer
+		xs := make([]GlyphIndex, f.NumGlyphs())
+		for i := range xs {
+			// Visit the glyphs in a non-monotonic order.
+			xs[i] = GlyphIndex((i * 7) % len(xs))
+		}
+		for _, h := range []font.Hinting{font.HintingNone, font.HintingFull} {
+			got, err := f.GlyphAdvances(&b, xs, ppem/3, h)
+			if err != nil {
+				t.Errorf("name=%q: GlyphAdvances: %v", name, err)
+				continue
+			}
+			for i, x := range xs {
+				want, err := f.GlyphAdvance(&b, x, ppem/3, h)
+				if err != nil {
+					t.Errorf("name=%q, x=%d: GlyphAdvance: %v", name, x, err)
+					break
+				}
+				if got[i] != want {
+					t.Errorf("name=%q, x=%d: got %d, want %d", name, x, got[i], want)
+					break
+				}
+			}
+		}
+
+		if _, err := f.GlyphAdvances(&b, []GlyphIndex{0, GlyphIndex(len(xs))}, ppem, font.HintingNone); err != ErrNotFound {
+			t.Errorf("name=%q: out of range: got %v, want %v", name, err, ErrNotFound)
+		}
+	}
+}
+
+func TestIsFixedPitch(t *testing.T) {
+	testCases := []struct {
+		name string
+		want bool
+		// constant is whether every glyph has the same advance width.
+		constant bool
+	}{
+		{"gobold", false, false},
+		{"gomono", true, true},
+		{"goregular", false, false},
+		// The post table marks cmapTest.ttf as fixed pitch, but it has more
+		// than one hmtx record.
+		{"cmapTest.ttf", true, false},
+		{"glyfTest.ttf", false, false},
+	}
+	for _, tc := range testCases {
+		var data []byte
+		if filepath.Ext(tc.name) == ".ttf" {
+			var err error
+			data, err = ioutil.ReadFile(filepath.FromSlash("../testdata/" + tc.name))
+			if err != nil {
+				t.Fatalf("ReadFile: %v", err)
+			}
+		} else {
+			data = fontData(tc.name)
+		}
+		f, err := Parse(data)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tc.name, err)
+			continue
+		}
+		if got := f.IsFixedPitch(); got != tc.want {
+			t.Errorf("%s: IsFixedPitch: got %t, want %t", tc.name, got, tc.want)
+		}
+		if got := f.cached.fixedAdvance >= 0; got != tc.constant {
+			t.Errorf("%s: constant advance: got %t, want %t", tc.name, got, tc.constant)
+		}
+	}
+}
+
+func TestLoadGlyphF32(t *testing.T) {
+	cffTest, err := ioutil.ReadFile(filepath.FromSlash("../testdata/CFFTest.otf"))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	for _, tc := range []struct {
+		name string
+		data []byte
+	}{
+		{"goregular", goregular.TTF},
+		{"CFFTest.otf", cffTest},
+	} {
+		f, err := Parse(tc.data)
+		if err != nil {
+			t.Errorf("%s: Parse: %v", tc.name, err)
+			continue
+		}
+		// With a ppem equal to the units per em, LoadGlyph's coordinates are
+		// in font units. An em of unitsPerEm/64 should give the same
+		// coordinates, in pixels instead of 26.6 fixed point units.
+		ppem := fixed.Int26_6(f.UnitsPerEm())
+		em := float32(f.UnitsPerEm()) / 64
+
+		var b, bF32 Buffer
+		for i, n := 0, f.NumGlyphs(); i < n; i++ {
+			x := GlyphIndex(i)
+			want, err := f.LoadGlyph(&b, x, ppem, nil)
+			if err != nil {
+				t.Errorf("%s, x=%d: LoadGlyph: %v", tc.name, x, err)
+				continue
+			}
+			got, err := f.LoadGlyphF32(&bF32, x, em)
+			if err != nil {
+				t.Errorf("%s, x=%d: LoadGlyphF32: %v", tc.name, x, err)
+				continue
+			}
+			if len(got) != len(want) {
+				t.Errorf("%s, x=%d: got %d segments, want %d", tc.name, x, len(got), len(want))
+				continue
+			}
+		loop:
+			for j := range got {
+				if got[j].Op != want[j].Op {
+					t.Errorf("%s, x=%d, j=%d: got op %d, want %d", tc.name, x, j, got[j].Op, want[j].Op)
+					break
+				}
+				for k, a := range want[j].Args {
+					if g := got[j].Args[k]; g[0]*64 != float32(a.X) || g[1]*64 != float32(a.Y) {
+						t.Errorf("%s, x=%d, j=%d: got %v, want %v", tc.name, x, j, got[j].Args, want[j].Args)
+						break loop
+					}
+				}
+			}
+		}
+
+		if _, err := f.LoadGlyphF32(nil, GlyphIndex(f.NumGlyphs()), 1); err != ErrNotFound {
+			t.Errorf("%s: out of range: got %v, want %v", tc.name, err, ErrNotFound)
+		}
+	}
+}
+
+func TestBufferPool(t *testing.T) {
+	cffTest, err := ioutil.ReadFile(filepath.FromSlash("../testdata/CFFTest.otf"))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	fonts := make([]*Font, 2)
+	for i, src := range [][]byte{goregular.TTF, cffTest} {
+		if fonts[i], err = Parse(src); err != nil {
+			t.Fatalf("Parse: %v", err)
+		}
+	}
+
+	// Load the same glyphs, concurrently, with and without pooled Buffers.
+	pool := NewBufferPool()
+	errc := make(chan error, 8)
+	for g := 0; g < cap(errc); g++ {
+		go func(g int) {
+			for i := 0; i < 40; i++ {
+				f := fonts[(g+i)%len(fonts)]
+				x := GlyphIndex(i % f.NumGlyphs())
+				want, err := f.LoadGlyph(nil, x, fixed.I(20), nil)
+				if err != nil {
+					errc <- err
+					return
+				}
+				b := pool.Get()
+				got, err := f.LoadGlyph(b, x, fixed.I(20), nil)
+				if err != nil {
+					errc <- err
+					return
+				}
+				if len(got) != len(want) || (len(want) != 0 && !reflect.DeepEqual(got, want)) {
+					errc <- fmt.Errorf("glyph %d: got %v, want %v", x, got, want)
+					return
+				}
+				pool.Put(b)
+			}
+			errc <- nil
+		}(g)
+	}
+	for g := 0; g < cap(errc); g++ {
+		if err := <-errc; err != nil {
+			t.Error(err)
+		}
+	}
+
+	// A Buffer put back in the pool no longer refers to a Font, or keeps
+	// oversized slices.
+	b := new(Buffer)
+	if _, err := fonts[1].LoadGlyph(b, 1, fixed.I(20), nil); err != nil {
+		t.Fatalf("LoadGlyph: %v", err)
+	}
+	if b.psi.type2Charstrings.f == nil {
+		t.Fatal("before Put: Buffer does not refer to the Font")
+	}
+	b.buf = make([]byte, maxPooledBufLen+1)
+	pool.Put(b)
+	if b.psi.type2Charstrings.f != nil {
+		t.Error("after Put: Buffer refers to the Font")
+	}
+	if b.buf != nil {
+		t.Error("after Put: Buffer keeps an oversized byte buffer")
+	}
+	if b.segments == nil {
+		t.Error("after Put: Buffer does not keep its segments")
+	}
+}
+
 func TestGoRegularGlyphIndex(t *testing.T) {
 	f, err := Parse(goregular.TTF)
 	if err != nil {
@@ -655,6 +947,133 @@ func TestPostScriptSegments(t *testing.T) {
 	testSegments(t, "CFFTest.otf", wants)
 }
 
+func TestFDSelect(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		data    []byte
+		wantErr bool
+		want    []int
+	}{{
+		desc: "format 0",
+		data: []byte{0x00, 0x01, 0x01, 0x00, 0x02, 0x02},
+		want: []int{1, 1, 0, 2, 2},
+	}, {
+		desc:    "format 0 bad index",
+		data:    []byte{0x00, 0x01, 0x01, 0x00, 0x03, 0x02},
+		wantErr: true,
+	}, {
+		desc: "format 3",
+		data: []byte{
+			0x03, 0x00, 0x03, // Format, numRanges.
+			0x00, 0x00, 0x02, // Range #0.
+			0x00, 0x01, 0x00, // Range #1.
+			0x00, 0x03, 0x01, // Range #2.
+			0x00, 0x05, // Sentinel.
+		},
+		want: []int{2, 0, 0, 1, 1},
+	}, {
+		desc: "format 3 first range not zero",
+		data: []byte{
+			0x03, 0x00, 0x01, // Format, numRanges.
+			0x00, 0x01, 0x00, // Range #0.
+			0x00, 0x05, // Sentinel.
+		},
+		wantErr: true,
+	}, {
+		desc: "format 3 short sentinel",
+		data: []byte{
+			0x03, 0x00, 0x01, // Format, numRanges.
+			0x00, 0x00, 0x00, // Range #0.
+			0x00, 0x04, // Sentinel.
+		},
+		wantErr: true,
+	}, {
+		desc:    "format 1",
+		data:    []byte{0x01, 0x00, 0x00, 0x00},
+		wantErr: true,
+	}}
+
+	const numGlyphs, numFontDicts = 5, 3
+	for _, tc := range testCases {
+		f := &Font{src: source{b: tc.data}}
+		p := cffParser{src: &f.src, end: len(tc.data)}
+		fds, err := p.parseFDSelect(0, numGlyphs, numFontDicts)
+		if gotErr := err != nil; gotErr != tc.wantErr {
+			t.Errorf("%s: parseFDSelect: got err %v, want error %t", tc.desc, err, tc.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		var b Buffer
+		for x, want := range tc.want {
+			got, err := fds.lookup(f, &b, GlyphIndex(x))
+			if err != nil {
+				t.Errorf("%s: lookup(%d): %v", tc.desc, x, err)
+				continue
+			}
+			if got != want {
+				t.Errorf("%s: lookup(%d): got %d, want %d", tc.desc, x, got, want)
+			}
+		}
+	}
+}
+
+func TestFlexOperators(t *testing.T) {
+	testCases := []struct {
+		desc string
+		op   func(*psInterpreter) error
+		args []int32
+		want []Segment
+	}{{
+		desc: "flex",
+		op:   t2CFlex,
+		args: []int32{10, 1, 10, 2, 10, 3, 10, -3, 10, -2, 10, -1, 50},
+		want: []Segment{
+			cubeTo(10, 1, 20, 3, 30, 6),
+			cubeTo(40, 3, 50, 1, 60, 0),
+		},
+	}, {
+		desc: "flex1 horizontal",
+		op:   t2CFlex1,
+		args: []int32{10, 1, 10, 2, 10, 3, 10, -3, 10, -2, 10},
+		want: []Segment{
+			cubeTo(10, 1, 20, 3, 30, 6),
+			cubeTo(40, 3, 50, 1, 60, 0),
+		},
+	}, {
+		desc: "flex1 vertical",
+		op:   t2CFlex1,
+		args: []int32{1, 10, 2, 10, 3, 10, -3, 10, -2, 10, 10},
+		want: []Segment{
+			cubeTo(1, 10, 3, 20, 6, 30),
+			cubeTo(3, 40, 1, 50, 0, 60),
+		},
+	}}
+
+	for _, tc := range testCases {
+		var (
+			b Buffer
+			p psInterpreter
+		)
+		p.type2Charstrings.initialize(nil, &b, 0)
+		p.argStack.top = int32(copy(p.argStack.a[:], tc.args))
+		if err := tc.op(&p); err != nil {
+			t.Errorf("%s: %v", tc.desc, err)
+			continue
+		}
+		if len(b.segments) != len(tc.want) {
+			t.Errorf("%s: got %d segments, want %d", tc.desc, len(b.segments), len(tc.want))
+			continue
+		}
+		for i, got := range b.segments {
+			if want := tc.want[i]; got != want {
+				t.Errorf("%s: segment %d: got %v, want %v", tc.desc, i, got, want)
+			}
+		}
+	}
+}
+
 func TestTrueTypeSegments(t *testing.T) {
 	// wants' vectors correspond 1-to-1 to what's in the glyfTest.sfd file,
 	// although FontForge's SFD format stores quadratic Bézier curves as cubics
@@ -966,3 +1385,91 @@ func TestBuiltInPostNames(t *testing.T) {
 		}
 	}
 }
+
+func TestGlyphComplexity(t *testing.T) {
+	glyfTest, err := ioutil.ReadFile(filepath.FromSlash("../testdata/glyfTest.ttf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cffTest, err := ioutil.ReadFile(filepath.FromSlash("../testdata/CFFTest.otf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		name           string
+		data           []byte
+		wantCompound   int
+		wantMaxPresent bool
+	}{
+		{"glyfTest.ttf", glyfTest, 4, true},
+		{"CFFTest.otf", cffTest, 0, false},
+		{"goregular", goregular.TTF, 0, true},
+	}
+	for _, tc := range testCases {
+		f, err := Parse(tc.data)
+		if err != nil {
+			t.Errorf("%s: Parse: %v", tc.name, err)
+			continue
+		}
+		max, ok := f.MaxGlyphComplexity()
+		if ok != tc.wantMaxPresent {
+			t.Errorf("%s: MaxGlyphComplexity: got ok %t, want %t", tc.name, ok, tc.wantMaxPresent)
+		}
+		var b Buffer
+		compound := 0
+		for i := 0; i < f.NumGlyphs(); i++ {
+			x := GlyphIndex(i)
+			c, err := f.GlyphComplexity(&b, x)
+			if err != nil {
+				t.Fatalf("%s: GlyphComplexity(%d): %v", tc.name, x, err)
+			}
+			segments, err := f.LoadGlyph(&b, x, 1024, nil)
+			if err != nil {
+				t.Fatalf("%s: LoadGlyph(%d): %v", tc.name, x, err)
+			}
+			contours := 0
+			for _, seg := range segments {
+				if seg.Op == SegmentOpMoveTo {
+					contours++
+				}
+			}
+			if c.Contours != contours {
+				t.Errorf("%s: glyph %d: got %d contours, want %d", tc.name, x, c.Contours, contours)
+			}
+			if c.Points < contours || (contours == 0 && c.Points != 0) {
+				t.Errorf("%s: glyph %d: got %d points for %d contours", tc.name, x, c.Points, contours)
+			}
+			if c.Components > 0 {
+				compound++
+			}
+			if ok && (c.Contours > max.Contours || c.Points > max.Points || c.Components > max.Components) {
+				t.Errorf("%s: glyph %d: got %+v, greater than the maximum %+v", tc.name, x, c, max)
+			}
+		}
+		if compound != tc.wantCompound {
+			t.Errorf("%s: got %d compound glyphs, want %d", tc.name, compound, tc.wantCompound)
+		}
+	}
+
+	// In glyfTest.ttf, the .notdef glyph is two rectangles, and the compound
+	// glyph 6 is two one-rectangle glyphs.
+	f, err := Parse(glyfTest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []struct {
+		x GlyphIndex
+		c GlyphComplexity
+	}{
+		{0, GlyphComplexity{Contours: 2, Points: 8}},
+		{4, GlyphComplexity{Contours: 1, Points: 4}},
+		{6, GlyphComplexity{Contours: 2, Points: 8, Components: 2}},
+	} {
+		if got, err := f.GlyphComplexity(nil, want.x); err != nil || got != want.c {
+			t.Errorf("glyfTest.ttf: GlyphComplexity(%d): got %+v, %v, want %+v", want.x, got, err, want.c)
+		}
+	}
+	if _, err := f.GlyphComplexity(nil, GlyphIndex(f.NumGlyphs())); err != ErrNotFound {
+		t.Errorf("out of range glyph: got %v, want %v", err, ErrNotFound)
+	}
+}
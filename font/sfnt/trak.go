@@ -0,0 +1,124 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sfnt
+
+import (
+	"math"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// Tracking returns the horizontal tracking adjustment, from the font's trak
+// table, for text set at the given point size. Tracking is the designer's
+// size-specific letter spacing: it is typically positive (looser) at small
+// sizes and negative (tighter) at large sizes. It should be added to the
+// advance between each pair of glyphs, in the same way as Kern. ppem is the
+// number of pixels in 1 em.
+//
+// The normal track (the one with a track value of zero) is used. Sizes
+// between those listed in the trak table are linearly interpolated, and sizes
+// outside that range are linearly extrapolated.
+//
+// It returns zero, and a nil error, if the font has no trak table or the trak
+// table has no normal track for horizontal text.
+func (f *Font) Tracking(b *Buffer, size, ppem fixed.Int26_6, h font.Hinting) (fixed.Int26_6, error) {
+	// https://developer.apple.com/fonts/TrueType-Reference-Manual/RM06/Chap6trak.html
+
+	if f.trak.length == 0 {
+		return 0, nil
+	}
+	if b == nil {
+		b = &Buffer{}
+	}
+	base, length := int(f.trak.offset), int(f.trak.length)
+	view := func(offset, n int) ([]byte, error) {
+		if offset < 0 || n < 0 || length-offset < n {
+			return nil, errInvalidTrakTable
+		}
+		return b.view(&f.src, base+offset, n)
+	}
+
+	// Header: version (Fixed), format, horizOffset, vertOffset, reserved.
+	buf, err := view(0, 12)
+	if err != nil {
+		return 0, err
+	}
+	if u32(buf) != 0x00010000 || u16(buf[4:]) != 0 {
+		return 0, errUnsupportedTrakTable
+	}
+	horizOffset := int(u16(buf[6:]))
+	if horizOffset == 0 {
+		return 0, nil
+	}
+
+	// TrackData: nTracks, nSizes, sizeTableOffset (uint32), []trackTable.
+	buf, err = view(horizOffset, 8)
+	if err != nil {
+		return 0, err
+	}
+	nTracks, nSizes, sizeTableOffset := int(u16(buf)), int(u16(buf[2:])), int(u32(buf[4:]))
+	if nSizes == 0 {
+		return 0, nil
+	}
+
+	// Each TrackTableEntry is track (Fixed), nameIndex, offset.
+	const entrySize = 8
+	buf, err = view(horizOffset+8, nTracks*entrySize)
+	if err != nil {
+		return 0, err
+	}
+	valuesOffset := -1
+	for i := 0; i < nTracks; i++ {
+		if u32(buf[i*entrySize:]) == 0 {
+			valuesOffset = int(u16(buf[i*entrySize+6:]))
+			break
+		}
+	}
+	if valuesOffset < 0 {
+		return 0, nil
+	}
+
+	// Find the two sizes to interpolate between. This matches HarfBuzz.
+	buf, err = view(sizeTableOffset, nSizes*4)
+	if err != nil {
+		return 0, err
+	}
+	target := float64(size) / 64
+	i := 0
+	for ; i < nSizes-1; i++ {
+		if float64(int32(u32(buf[4*i:])))/0x10000 >= target {
+			break
+		}
+	}
+	if i > 0 {
+		i--
+	}
+	j := i + 1
+	if j == nSizes {
+		j = i
+	}
+	s0 := float64(int32(u32(buf[4*i:]))) / 0x10000
+	s1 := float64(int32(u32(buf[4*j:]))) / 0x10000
+
+	buf, err = view(valuesOffset, nSizes*2)
+	if err != nil {
+		return 0, err
+	}
+	v0 := float64(int16(u16(buf[2*i:])))
+	v1 := float64(int16(u16(buf[2*j:])))
+
+	v := v0
+	if s0 != s1 {
+		t := (target - s0) / (s1 - s0)
+		v = t*v1 + (1-t)*v0
+	}
+	track := fixed.Int26_6(math.Round(v * float64(ppem) / float64(f.cached.unitsPerEm)))
+	if h == font.HintingFull {
+		// Quantize the fixed.Int26_6 value to the nearest pixel.
+		track = (track + 32) &^ 63
+	}
+	return track, nil
+}
@@ -0,0 +1,92 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sfnt
+
+import (
+	"testing"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/math/fixed"
+)
+
+// testTrak is a trak table whose horizontal tracks are a tight track and a
+// normal track, for the sizes 9, 12 and 24 points. The normal track's values
+// are 100, 50 and -100 font units.
+var testTrak = concat(
+	// Header: version, format, horizOffset, vertOffset, reserved.
+	be16(1, 0, 0, 12, 0, 0),
+	// TrackData: nTracks, nSizes, sizeTableOffset.
+	be16(2, 3, 0, 36),
+	// TrackTableEntry: track, nameIndex, offset.
+	be16(0xffff, 0, 256, 48),
+	be16(0, 0, 257, 54),
+	// Sizes.
+	be16(9, 0, 12, 0, 24, 0),
+	// Values for the tight and normal tracks.
+	be16(50, 0, 0xff38),
+	be16(100, 50, 0xff9c),
+)
+
+func concat(bs ...[]byte) (ret []byte) {
+	for _, b := range bs {
+		ret = append(ret, b...)
+	}
+	return ret
+}
+
+func TestTracking(t *testing.T) {
+	f, err := Parse(withTable(t, goregular.TTF, "trak", testTrak))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	upem := fixed.Int26_6(f.UnitsPerEm())
+
+	testCases := []struct {
+		size, ppem fixed.Int26_6
+		h          font.Hinting
+		want       fixed.Int26_6
+	}{
+		{fixed.I(9), upem, font.HintingNone, 100},
+		{fixed.I(12), upem, font.HintingNone, 50},
+		{fixed.I(24), upem, font.HintingNone, -100},
+		{fixed.I(18), upem, font.HintingNone, -25},      // Interpolated.
+		{fixed.I(6), upem, font.HintingNone, 150},       // Extrapolated.
+		{fixed.I(36), upem, font.HintingNone, -250},     // Extrapolated.
+		{fixed.I(9), fixed.I(12), font.HintingNone, 38}, // 100 * 12 * 64 / 2048 = 37.5.
+		{fixed.I(9), fixed.I(12), font.HintingFull, 64},
+	}
+	var b Buffer
+	for _, tc := range testCases {
+		got, err := f.Tracking(&b, tc.size, tc.ppem, tc.h)
+		if err != nil {
+			t.Errorf("size=%v, ppem=%v, h=%v: %v", tc.size, tc.ppem, tc.h, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("size=%v, ppem=%v, h=%v: got %v, want %v", tc.size, tc.ppem, tc.h, got, tc.want)
+		}
+	}
+
+	// A font without a trak table has no tracking.
+	f, err = Parse(goregular.TTF)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if got, err := f.Tracking(nil, fixed.I(9), fixed.I(12), font.HintingNone); got != 0 || err != nil {
+		t.Errorf("no trak table: got %v, %v, want 0, nil", got, err)
+	}
+}
+
+func TestTrackingInvalid(t *testing.T) {
+	short := testTrak[:len(testTrak)-2]
+	f, err := Parse(withTable(t, goregular.TTF, "trak", short))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if _, err := f.Tracking(nil, fixed.I(9), fixed.I(12), font.HintingNone); err == nil {
+		t.Errorf("got nil error, want non-nil")
+	}
+}
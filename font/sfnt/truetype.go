@@ -324,6 +324,95 @@ func loadCompoundGlyf(f *Font, b *Buffer, data []byte, stackBottom, recursionDep
 	return nil
 }
 
+// glyfComplexity returns the complexity of the x'th glyph, summing the
+// contours and points of a compound glyph's components. Like loadGlyf, it
+// uses b.compoundStack from stackBottom upwards.
+func glyfComplexity(f *Font, b *Buffer, x GlyphIndex, stackBottom, recursionDepth uint32) (GlyphComplexity, error) {
+	data, _, _, err := f.viewGlyphData(b, x)
+	if err != nil {
+		return GlyphComplexity{}, err
+	}
+	if len(data) == 0 {
+		return GlyphComplexity{}, nil
+	}
+	if len(data) < glyfHeaderLen {
+		return GlyphComplexity{}, errInvalidGlyphData
+	}
+
+	switch numContours := int16(u16(data)); {
+	case numContours == -1:
+		// We have a compound glyph.
+	case numContours == 0:
+		return GlyphComplexity{}, nil
+	case numContours > 0:
+		// We have a simple (non-compound) glyph. Its number of points is one
+		// more than the end point of its last contour.
+		index := glyfHeaderLen + 2*int(numContours)
+		if index > len(data) {
+			return GlyphComplexity{}, errInvalidGlyphData
+		}
+		return GlyphComplexity{
+			Contours: int(numContours),
+			Points:   1 + int(u16(data[index-2:])),
+		}, nil
+	default:
+		return GlyphComplexity{}, errInvalidGlyphData
+	}
+
+	if recursionDepth++; recursionDepth == maxCompoundRecursionDepth {
+		return GlyphComplexity{}, errUnsupportedCompoundGlyph
+	}
+
+	// Read the components' glyph indexes before visiting the components, as
+	// visiting them can overwrite the data slice's backing array. Unlike
+	// loadCompoundGlyf, the components' offsets and transforms are skipped.
+	data = data[glyfHeaderLen:]
+	stackTop := stackBottom
+	for {
+		if stackTop >= maxCompoundStackSize {
+			return GlyphComplexity{}, errUnsupportedCompoundGlyph
+		}
+		if len(data) < 4 {
+			return GlyphComplexity{}, errInvalidGlyphData
+		}
+		flags := u16(data)
+		b.compoundStack[stackTop].glyphIndex = GlyphIndex(u16(data[2:]))
+		stackTop++
+
+		n := 6
+		if flags&flagArg1And2AreWords != 0 {
+			n = 8
+		}
+		switch {
+		case flags&flagWeHaveAScale != 0:
+			n += 2
+		case flags&flagWeHaveAnXAndYScale != 0:
+			n += 4
+		case flags&flagWeHaveATwoByTwo != 0:
+			n += 8
+		}
+		if len(data) < n {
+			return GlyphComplexity{}, errInvalidGlyphData
+		}
+		data = data[n:]
+
+		if flags&flagMoreComponents == 0 {
+			break
+		}
+	}
+
+	c := GlyphComplexity{Components: int(stackTop - stackBottom)}
+	for i := stackBottom; i < stackTop; i++ {
+		d, err := glyfComplexity(f, b, b.compoundStack[i].glyphIndex, stackTop, recursionDepth)
+		if err != nil {
+			return GlyphComplexity{}, err
+		}
+		c.Contours += d.Contours
+		c.Points += d.Points
+	}
+	return c, nil
+}
+
 type glyfIter struct {
 	data []byte
 	err  error
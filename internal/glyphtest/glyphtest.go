@@ -0,0 +1,390 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package glyphtest provides a corpus of glyph outlines, and a reference
+// rasterizer to compare against, for testing and benchmarking vector graphics
+// rasterizers such as the one in golang.org/x/image/vector.
+//
+// The reference rasterizer computes the exact area coverage of each pixel,
+// under the non-zero winding rule, in float64 math after flattening curves
+// much more finely than is usual. This is the same model as FreeType's
+// anti-aliasing rasterizer, so the reference images can stand in for
+// FreeType's output, without depending on it.
+package glyphtest // import "golang.org/x/image/internal/glyphtest"
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"sync"
+	"testing"
+
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goitalic"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/f32"
+)
+
+// Path is the subset of a vector graphics rasterizer's methods, such as those
+// of a *vector.Rasterizer, that are needed to trace a Glyph.
+type Path interface {
+	MoveTo(ax, ay float32)
+	LineTo(bx, by float32)
+	QuadTo(bx, by, cx, cy float32)
+	CubeTo(bx, by, cx, cy, dx, dy float32)
+	ClosePath()
+}
+
+// Glyph is a glyph outline, in pixel coordinates with the Y axis increasing
+// down, that fits in the rectangle from (0, 0) to Size.
+type Glyph struct {
+	// Name identifies the glyph in test failure messages, such as
+	// "goregular/g@32" for the 'g' glyph of the Go Regular font at 32 pixels
+	// per em.
+	Name string
+	// Size is the width and height of the glyph's bounding box, plus a
+	// margin of one pixel on each side.
+	Size image.Point
+	// Segments are the glyph's contours. Each contour starts with a
+	// sfnt.SegmentOpMoveTo segment and is implicitly closed.
+	Segments []sfnt.SegmentF32
+}
+
+// Trace adds the glyph's contours to p, explicitly closing each one.
+func (g *Glyph) Trace(p Path) {
+	for i, s := range g.Segments {
+		switch s.Op {
+		case sfnt.SegmentOpMoveTo:
+			if i != 0 {
+				p.ClosePath()
+			}
+			p.MoveTo(s.Args[0][0], s.Args[0][1])
+		case sfnt.SegmentOpLineTo:
+			p.LineTo(s.Args[0][0], s.Args[0][1])
+		case sfnt.SegmentOpQuadTo:
+			p.QuadTo(s.Args[0][0], s.Args[0][1], s.Args[1][0], s.Args[1][1])
+		case sfnt.SegmentOpCubeTo:
+			p.CubeTo(s.Args[0][0], s.Args[0][1], s.Args[1][0], s.Args[1][1], s.Args[2][0], s.Args[2][1])
+		}
+	}
+	if len(g.Segments) != 0 {
+		p.ClosePath()
+	}
+}
+
+// Flatten returns a copy of the glyph whose curves are replaced by the line
+// segments that the reference rasterizer uses to approximate them. Comparing
+// a rasterizer's output for the flattened glyph against the reference tests
+// its area accumulation separately from its own curve flattening.
+func (g *Glyph) Flatten() Glyph {
+	var segments []sfnt.SegmentF32
+	var penX, penY float64
+	lineTo := func(x, y float64) {
+		penX, penY = x, y
+		segments = append(segments, sfnt.SegmentF32{
+			Op:   sfnt.SegmentOpLineTo,
+			Args: [3]f32.Vec2{{float32(x), float32(y)}},
+		})
+	}
+	for _, s := range g.Segments {
+		a := s.Args
+		switch s.Op {
+		case sfnt.SegmentOpMoveTo:
+			penX, penY = float64(a[0][0]), float64(a[0][1])
+			segments = append(segments, s)
+		case sfnt.SegmentOpLineTo:
+			lineTo(float64(a[0][0]), float64(a[0][1]))
+		case sfnt.SegmentOpQuadTo:
+			flattenQuad(penX, penY,
+				float64(a[0][0]), float64(a[0][1]),
+				float64(a[1][0]), float64(a[1][1]),
+				lineTo)
+		case sfnt.SegmentOpCubeTo:
+			flattenCube(penX, penY,
+				float64(a[0][0]), float64(a[0][1]),
+				float64(a[1][0]), float64(a[1][1]),
+				float64(a[2][0]), float64(a[2][1]),
+				lineTo)
+		}
+	}
+	return Glyph{
+		Name:     g.Name + "/flat",
+		Size:     g.Size,
+		Segments: segments,
+	}
+}
+
+var (
+	corpusOnce sync.Once
+	corpus     []Glyph
+	corpusErr  error
+)
+
+// corpusFonts and corpusRunes give a mix of straight and curved outlines,
+// holes, and upright and italic shapes.
+var (
+	corpusFonts = []struct {
+		name string
+		ttf  []byte
+	}{
+		{"goregular", goregular.TTF},
+		{"gobold", gobold.TTF},
+		{"goitalic", goitalic.TTF},
+		{"gomono", gomono.TTF},
+	}
+	corpusRunes = "aegkmosxAMQRSW&@0258"
+)
+
+// Corpus returns glyphs from the Go fonts at 12, 24, 48 and 160 pixels per em,
+// followed by larger glyphs and cubic Bézier shapes. The larger glyphs have a
+// height of more than 512 pixels, the threshold above which the
+// golang.org/x/image/vector rasterizer uses floating point math.
+//
+// The returned slice is shared, and must not be modified.
+func Corpus() ([]Glyph, error) {
+	corpusOnce.Do(func() {
+		corpus, corpusErr = makeCorpus()
+	})
+	return corpus, corpusErr
+}
+
+func makeCorpus() ([]Glyph, error) {
+	var glyphs []Glyph
+	var b sfnt.Buffer
+	for _, ppem := range []float32{12, 24, 48, 160, 800} {
+		for _, font := range corpusFonts {
+			f, err := sfnt.Parse(font.ttf)
+			if err != nil {
+				return nil, err
+			}
+			runes := corpusRunes
+			if ppem > 160 {
+				// Keep the corpus small enough to rasterize quickly.
+				runes = "g@"
+			}
+			for _, r := range runes {
+				x, err := f.GlyphIndex(&b, r)
+				if err != nil {
+					return nil, err
+				}
+				if x == 0 {
+					return nil, fmt.Errorf("glyphtest: %s has no glyph for %q", font.name, r)
+				}
+				segments, err := f.LoadGlyphF32(&b, x, ppem)
+				if err != nil {
+					return nil, err
+				}
+				name := fmt.Sprintf("%s/%c@%g", font.name, r, ppem)
+				glyphs = append(glyphs, newGlyph(name, append([]sfnt.SegmentF32(nil), segments...)))
+			}
+		}
+	}
+	for _, size := range []float32{10, 37.5, 600} {
+		glyphs = append(glyphs,
+			newGlyph(fmt.Sprintf("circle@%g", size), circle(size)),
+			newGlyph(fmt.Sprintf("star@%g", size), star(size)),
+		)
+	}
+	return glyphs, nil
+}
+
+// newGlyph returns a Glyph for the segments, translated so that their
+// control points' bounding box is one pixel from the top left.
+func newGlyph(name string, segments []sfnt.SegmentF32) Glyph {
+	minX, minY := float32(math.Inf(+1)), float32(math.Inf(+1))
+	maxX, maxY := float32(math.Inf(-1)), float32(math.Inf(-1))
+	for _, s := range segments {
+		for _, a := range s.Args[:nArgs(s.Op)] {
+			minX, maxX = min32(minX, a[0]), max32(maxX, a[0])
+			minY, maxY = min32(minY, a[1]), max32(maxY, a[1])
+		}
+	}
+	if len(segments) == 0 {
+		minX, minY, maxX, maxY = 0, 0, 0, 0
+	}
+	dx, dy := 1-float32(math.Floor(float64(minX))), 1-float32(math.Floor(float64(minY)))
+	for i := range segments {
+		for j := range segments[i].Args[:nArgs(segments[i].Op)] {
+			segments[i].Args[j][0] += dx
+			segments[i].Args[j][1] += dy
+		}
+	}
+	return Glyph{
+		Name: name,
+		Size: image.Point{
+			X: int(math.Ceil(float64(maxX+dx))) + 1,
+			Y: int(math.Ceil(float64(maxY+dy))) + 1,
+		},
+		Segments: segments,
+	}
+}
+
+// circle returns a circle with the given diameter, approximated by four cubic
+// Bézier segments.
+func circle(diameter float32) []sfnt.SegmentF32 {
+	const k = 0.5522847498 // 4 * (sqrt(2) - 1) / 3.
+	r := diameter / 2
+	c := r * k
+	return []sfnt.SegmentF32{
+		{Op: sfnt.SegmentOpMoveTo, Args: [3]f32.Vec2{{r, 0}}},
+		{Op: sfnt.SegmentOpCubeTo, Args: [3]f32.Vec2{{r + c, 0}, {2 * r, r - c}, {2 * r, r}}},
+		{Op: sfnt.SegmentOpCubeTo, Args: [3]f32.Vec2{{2 * r, r + c}, {r + c, 2 * r}, {r, 2 * r}}},
+		{Op: sfnt.SegmentOpCubeTo, Args: [3]f32.Vec2{{r - c, 2 * r}, {0, r + c}, {0, r}}},
+		{Op: sfnt.SegmentOpCubeTo, Args: [3]f32.Vec2{{0, r - c}, {r - c, 0}, {r, 0}}},
+	}
+}
+
+// star returns a self-intersecting five-pointed star with the given
+// diameter, whose center is inside the path under the non-zero winding rule
+// but not under the even-odd rule.
+func star(diameter float32) []sfnt.SegmentF32 {
+	r := float64(diameter / 2)
+	s := make([]sfnt.SegmentF32, 5)
+	for i := range s {
+		theta := 2 * math.Pi * float64(2*i) / 5
+		s[i].Op = sfnt.SegmentOpLineTo
+		s[i].Args[0] = f32.Vec2{float32(r + r*math.Sin(theta)), float32(r - r*math.Cos(theta))}
+	}
+	s[0].Op = sfnt.SegmentOpMoveTo
+	return s
+}
+
+func nArgs(op sfnt.SegmentOp) int {
+	switch op {
+	case sfnt.SegmentOpQuadTo:
+		return 2
+	case sfnt.SegmentOpCubeTo:
+		return 3
+	}
+	return 1
+}
+
+func min32(x, y float32) float32 {
+	if x < y {
+		return x
+	}
+	return y
+}
+
+func max32(x, y float32) float32 {
+	if x > y {
+		return x
+	}
+	return y
+}
+
+// Compare returns an error if any of the pixels of got and want differ by
+// more than tolerance, or if their bounds differ. The error message gives the
+// number of such pixels and the largest difference.
+func Compare(got, want *image.Alpha, tolerance uint8) error {
+	if got.Rect != want.Rect {
+		return fmt.Errorf("bounds differ: got %v, want %v", got.Rect, want.Rect)
+	}
+	n, maxDiff, at := 0, 0, image.Point{}
+	for y := want.Rect.Min.Y; y < want.Rect.Max.Y; y++ {
+		for x := want.Rect.Min.X; x < want.Rect.Max.X; x++ {
+			d := int(got.AlphaAt(x, y).A) - int(want.AlphaAt(x, y).A)
+			if d < 0 {
+				d = -d
+			}
+			if d > int(tolerance) {
+				n++
+			}
+			if d > maxDiff {
+				maxDiff, at = d, image.Point{x, y}
+			}
+		}
+	}
+	if n != 0 {
+		return fmt.Errorf("%d pixels differ by more than %d, the most by %d at %v", n, tolerance, maxDiff, at)
+	}
+	return nil
+}
+
+// Stats summarizes the differences between two images' pixels, or between
+// many pairs of images, as accumulated by Add.
+type Stats struct {
+	// Pixels is the number of pixels compared.
+	Pixels int
+	// SumError and SumSquaredError are the sums of the absolute differences,
+	// in 8-bit alpha values, and of their squares.
+	SumError, SumSquaredError int64
+	// Over is the number of pixels that differ by more than the threshold
+	// passed to Measure.
+	Over int
+	// MaxError is the largest difference.
+	MaxError int
+}
+
+// Measure returns the Stats of the differences between got and want, which
+// must have the same bounds, counting the pixels that differ by more than
+// threshold as Over.
+func Measure(got, want *image.Alpha, threshold uint8) Stats {
+	var s Stats
+	for y := want.Rect.Min.Y; y < want.Rect.Max.Y; y++ {
+		for x := want.Rect.Min.X; x < want.Rect.Max.X; x++ {
+			d := int(got.AlphaAt(x, y).A) - int(want.AlphaAt(x, y).A)
+			if d < 0 {
+				d = -d
+			}
+			s.Pixels++
+			s.SumError += int64(d)
+			s.SumSquaredError += int64(d * d)
+			if d > int(threshold) {
+				s.Over++
+			}
+			if d > s.MaxError {
+				s.MaxError = d
+			}
+		}
+	}
+	return s
+}
+
+// Add accumulates t into s.
+func (s *Stats) Add(t Stats) {
+	s.Pixels += t.Pixels
+	s.SumError += t.SumError
+	s.SumSquaredError += t.SumSquaredError
+	s.Over += t.Over
+	if t.MaxError > s.MaxError {
+		s.MaxError = t.MaxError
+	}
+}
+
+// MeanError returns the mean absolute difference per pixel.
+func (s *Stats) MeanError() float64 {
+	if s.Pixels == 0 {
+		return 0
+	}
+	return float64(s.SumError) / float64(s.Pixels)
+}
+
+// RMSError returns the root mean square difference per pixel.
+func (s *Stats) RMSError() float64 {
+	if s.Pixels == 0 {
+		return 0
+	}
+	return math.Sqrt(float64(s.SumSquaredError) / float64(s.Pixels))
+}
+
+// Benchmark runs rasterize once per glyph, for each of b.N iterations. It
+// reports the throughput in pixels, counting each glyph's Size, as bytes per
+// second.
+func Benchmark(b *testing.B, glyphs []Glyph, rasterize func(g *Glyph)) {
+	n := int64(0)
+	for i := range glyphs {
+		n += int64(glyphs[i].Size.X * glyphs[i].Size.Y)
+	}
+	b.SetBytes(n)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := range glyphs {
+			rasterize(&glyphs[j])
+		}
+	}
+}
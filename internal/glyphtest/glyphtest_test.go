@@ -0,0 +1,75 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glyphtest
+
+import (
+	"bytes"
+	"image"
+	"testing"
+
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/f32"
+)
+
+func TestReference(t *testing.T) {
+	// A rectangle from (0.25, 0.5) to (2.5, 1), and a triangle.
+	g := &Glyph{
+		Size: image.Point{4, 3},
+		Segments: []sfnt.SegmentF32{
+			{Op: sfnt.SegmentOpMoveTo, Args: [3]f32.Vec2{{0.25, 0.5}}},
+			{Op: sfnt.SegmentOpLineTo, Args: [3]f32.Vec2{{2.5, 0.5}}},
+			{Op: sfnt.SegmentOpLineTo, Args: [3]f32.Vec2{{2.5, 1}}},
+			{Op: sfnt.SegmentOpLineTo, Args: [3]f32.Vec2{{0.25, 1}}},
+			{Op: sfnt.SegmentOpMoveTo, Args: [3]f32.Vec2{{3, 1}}},
+			{Op: sfnt.SegmentOpLineTo, Args: [3]f32.Vec2{{4, 3}}},
+			{Op: sfnt.SegmentOpLineTo, Args: [3]f32.Vec2{{3, 3}}},
+		},
+	}
+	want := []byte{
+		0x60, 0x80, 0x40, 0x00,
+		0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00,
+	}
+	// The triangle covers a quarter of pixel (3, 1) and three quarters of
+	// pixel (3, 2).
+	want[4+3], want[8+3] = 0x40, 0xbf
+	if got := Reference(g).Pix; !bytes.Equal(got, want) {
+		t.Errorf("got  %#02x\nwant %#02x", got, want)
+	}
+
+	// Flattening a glyph without curves does not change it.
+	if got := Reference(&Glyph{Size: g.Size, Segments: g.Flatten().Segments}).Pix; !bytes.Equal(got, want) {
+		t.Errorf("flattened: got  %#02x\nwant %#02x", got, want)
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	r := image.Rect(0, 0, 4, 1)
+	got := &image.Alpha{Pix: []byte{0x00, 0x10, 0x80, 0xff}, Stride: 4, Rect: r}
+	want := &image.Alpha{Pix: []byte{0x00, 0x11, 0x7d, 0xf0}, Stride: 4, Rect: r}
+	s := Measure(got, want, 2)
+	if s != (Stats{Pixels: 4, SumError: 19, SumSquaredError: 235, Over: 2, MaxError: 15}) {
+		t.Errorf("got %+v", s)
+	}
+	s.Add(Measure(got, got, 2))
+	if got, want := s.MeanError(), 19.0/8; got != want {
+		t.Errorf("MeanError: got %v, want %v", got, want)
+	}
+	if s.Pixels != 8 || s.Over != 2 || s.MaxError != 15 {
+		t.Errorf("Add: got %+v", s)
+	}
+}
+
+func TestCorpus(t *testing.T) {
+	glyphs, err := Corpus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, g := range glyphs {
+		if g.Size.X <= 2 || g.Size.Y <= 2 {
+			t.Errorf("%s: got size %v, want a non-empty glyph", g.Name, g.Size)
+		}
+	}
+}
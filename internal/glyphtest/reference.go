@@ -0,0 +1,184 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glyphtest
+
+import (
+	"image"
+	"math"
+)
+
+// Reference returns the exact coverage of the glyph's pixels, rounded to the
+// nearest 8-bit alpha value.
+func Reference(g *Glyph) *image.Alpha {
+	z := &reference{
+		w:   g.Size.X,
+		h:   g.Size.Y,
+		acc: make([]float64, g.Size.X*g.Size.Y),
+	}
+	g.Trace(z)
+
+	dst := image.NewAlpha(image.Rectangle{Max: g.Size})
+	for y := 0; y < z.h; y++ {
+		cover := 0.0
+		for x := 0; x < z.w; x++ {
+			cover += z.acc[y*z.w+x]
+			a := math.Abs(cover)
+			if a > 1 {
+				a = 1
+			}
+			dst.Pix[y*dst.Stride+x] = uint8(math.Round(a * 0xff))
+		}
+	}
+	return dst
+}
+
+// reference is a Path that accumulates the signed area of its line segments.
+//
+// Each element of acc is the change in coverage, from the pixel to its left,
+// of the pixel with the same index in the glyph's image. Summing each row's
+// elements from left to right gives the coverage, and every line segment
+// affects at most two elements for each pixel that it crosses.
+type reference struct {
+	w, h           int
+	acc            []float64
+	firstX, firstY float64
+	penX, penY     float64
+}
+
+// maxFlatness is the maximum distance, in pixels, between a Bézier curve and
+// the line segments that approximate it. It is small enough for the error to
+// be well below a rounding error in the 8-bit alpha values.
+const maxFlatness = 1.0 / 1024
+
+func (z *reference) MoveTo(ax, ay float32) {
+	z.firstX, z.firstY = float64(ax), float64(ay)
+	z.penX, z.penY = float64(ax), float64(ay)
+}
+
+func (z *reference) ClosePath() {
+	z.lineTo(z.firstX, z.firstY)
+}
+
+func (z *reference) LineTo(bx, by float32) {
+	z.lineTo(float64(bx), float64(by))
+}
+
+func (z *reference) QuadTo(bx, by, cx, cy float32) {
+	flattenQuad(z.penX, z.penY, float64(bx), float64(by), float64(cx), float64(cy), z.lineTo)
+}
+
+func (z *reference) CubeTo(bx, by, cx, cy, dx, dy float32) {
+	flattenCube(z.penX, z.penY, float64(bx), float64(by), float64(cx), float64(cy), float64(dx), float64(dy), z.lineTo)
+}
+
+// flattenQuad calls lineTo with the end points of the line segments that
+// approximate a quadratic Bézier curve to within maxFlatness.
+func flattenQuad(ax, ay, bx, by, cx, cy float64, lineTo func(x, y float64)) {
+	// The distance between a quadratic Bézier curve and its chord is at most
+	// a quarter of the second difference of its control points, and
+	// splitting the curve into n pieces divides that by n².
+	d := math.Hypot(ax-2*bx+cx, ay-2*by+cy) / 4
+	n := int(math.Ceil(math.Sqrt(d / maxFlatness)))
+	for i := 1; i < n; i++ {
+		t := float64(i) / float64(n)
+		u := 1 - t
+		lineTo(u*u*ax+2*u*t*bx+t*t*cx, u*u*ay+2*u*t*by+t*t*cy)
+	}
+	lineTo(cx, cy)
+}
+
+// flattenCube is like flattenQuad, for a cubic Bézier curve.
+func flattenCube(ax, ay, bx, by, cx, cy, dx, dy float64, lineTo func(x, y float64)) {
+	// Similarly, the distance is at most three quarters of the larger second
+	// difference.
+	d := 0.75 * math.Max(
+		math.Hypot(ax-2*bx+cx, ay-2*by+cy),
+		math.Hypot(bx-2*cx+dx, by-2*cy+dy),
+	)
+	n := int(math.Ceil(math.Sqrt(d / maxFlatness)))
+	for i := 1; i < n; i++ {
+		t := float64(i) / float64(n)
+		u := 1 - t
+		lineTo(
+			u*u*u*ax+3*u*u*t*bx+3*u*t*t*cx+t*t*t*dx,
+			u*u*u*ay+3*u*u*t*by+3*u*t*t*cy+t*t*t*dy,
+		)
+	}
+	lineTo(dx, dy)
+}
+
+func (z *reference) lineTo(bx, by float64) {
+	ax, ay := z.penX, z.penY
+	z.penX, z.penY = bx, by
+	if ay == by {
+		return
+	}
+	dir := 1.0
+	if ay > by {
+		dir, ax, ay, bx, by = -1, bx, by, ax, ay
+	}
+
+	// Split the segment at each row boundary, and then each row's piece at
+	// each column boundary, so that each sub-segment is within a single
+	// pixel. Clamping x to [0, w] does not change the coverage of the
+	// pixels inside the image.
+	y0 := math.Max(ay, 0)
+	y1 := math.Min(by, float64(z.h))
+	for row := int(math.Floor(y0)); float64(row) < y1; row++ {
+		ya := math.Max(y0, float64(row))
+		yb := math.Min(y1, float64(row+1))
+		if ya >= yb {
+			continue
+		}
+		xa := ax + (bx-ax)*(ya-ay)/(by-ay)
+		xb := ax + (bx-ax)*(yb-ay)/(by-ay)
+		z.rowPiece(row, clampX(xa, z.w), ya, clampX(xb, z.w), yb, dir)
+	}
+}
+
+// rowPiece accumulates the signed area of a line segment within a row,
+// from (xa, ya) to (xb, yb) with ya < yb.
+func (z *reference) rowPiece(row int, xa, ya, xb, yb, dir float64) {
+	acc := z.acc[row*z.w : (row+1)*z.w]
+	if xa > xb {
+		xa, ya, xb, yb = xb, yb, xa, ya
+	}
+	// Walk from xa to xb, one column at a time.
+	for x := xa; ; {
+		col := math.Floor(x)
+		xNext := math.Min(xb, col+1)
+		var yx, yNext float64
+		if xb == xa {
+			yx, yNext = ya, yb
+		} else {
+			yx = ya + (yb-ya)*(x-xa)/(xb-xa)
+			yNext = ya + (yb-ya)*(xNext-xa)/(xb-xa)
+		}
+		dy := math.Abs(yNext-yx) * dir
+		// The sub-segment covers the part of its pixel to its right, and all
+		// of the pixels further right.
+		mid := (x+xNext)/2 - col
+		if i := int(col); i < z.w {
+			acc[i] += dy * (1 - mid)
+			if i+1 < z.w {
+				acc[i+1] += dy * mid
+			}
+		}
+		if xNext >= xb {
+			break
+		}
+		x = xNext
+	}
+}
+
+func clampX(x float64, w int) float64 {
+	if x < 0 {
+		return 0
+	}
+	if x > float64(w) {
+		return float64(w)
+	}
+	return x
+}
@@ -0,0 +1,184 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package ycbcr converts Y'CbCr images, such as decoded VP8 frames, to RGBA
+// images.
+//
+// The conversions give exactly the same results as the standard library's
+// color.YCbCr and color.NYCbCrA RGBA methods, but are faster than calling
+// those methods, or image/draw, per pixel. For chroma subsampled images, the
+// chroma terms are computed once for all of the pixels that share each
+// chroma sample.
+//
+// The conversions are written in plain Go, without SIMD assembly, so they run
+// the same code on every architecture.
+//
+// TODO: add SIMD implementations, such as for amd64 and arm64, tested against
+// the plain Go ones as vp8's SIMD inverse DCT and loop filters are.
+package ycbcr // import "golang.org/x/image/internal/ycbcr"
+
+import (
+	"image"
+)
+
+// ToRGBA converts the pixels of src in r to the same pixels of dst. r must be
+// within both images' bounds.
+func ToRGBA(dst *image.RGBA, r image.Rectangle, src *image.YCbCr) {
+	toRGBA(dst, r, src, nil, 0)
+}
+
+// NYCbCrAToRGBA is like ToRGBA, but for a source image with alpha. The
+// converted colors are alpha-premultiplied.
+func NYCbCrAToRGBA(dst *image.RGBA, r image.Rectangle, src *image.NYCbCrA) {
+	toRGBA(dst, r, &src.YCbCr, src.A, src.AStride)
+}
+
+func toRGBA(dst *image.RGBA, r image.Rectangle, src *image.YCbCr, a []uint8, aStride int) {
+	// w is the number of luma columns per chroma column.
+	w := 1
+	switch src.SubsampleRatio {
+	case image.YCbCrSubsampleRatio422, image.YCbCrSubsampleRatio420:
+		w = 2
+	case image.YCbCrSubsampleRatio411, image.YCbCrSubsampleRatio410:
+		w = 4
+	}
+	// The chroma column of x is x/w, rounded towards zero as in COffset, so
+	// for non-negative x, each chroma sample is shared by a run of w pixels
+	// starting at a multiple of w.
+	fast := a == nil && w <= 2 && r.Min.X >= 0
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		d := dst.Pix[dst.PixOffset(r.Min.X, y):]
+		d = d[:4*r.Dx()]
+		yi := src.YOffset(r.Min.X, y)
+		if fast {
+			x := r.Min.X
+			if x%w != 0 {
+				ci := src.COffset(x, y)
+				put(d, src.Y[yi], src.Cb[ci], src.Cr[ci])
+				d, yi, x = d[4:], yi+1, x+1
+			}
+			n := r.Max.X - x
+			ci := src.COffset(x, y)
+			if w == 1 {
+				row444(d, src.Y[yi:yi+n], src.Cb[ci:ci+n], src.Cr[ci:ci+n])
+			} else {
+				row420(d, src.Y[yi:yi+n], src.Cb[ci:ci+(n+1)/2], src.Cr[ci:ci+(n+1)/2])
+			}
+			continue
+		}
+		ai := (y-src.Rect.Min.Y)*aStride + (r.Min.X - src.Rect.Min.X)
+		for x := r.Min.X; x < r.Max.X; {
+			// Convert the run of pixels, starting at x, that share a chroma
+			// sample.
+			ci := src.COffset(x, y)
+			n := 1
+			for x+n < r.Max.X && (x+n)/w == x/w {
+				n++
+			}
+			cb1 := int32(src.Cb[ci]) - 128
+			cr1 := int32(src.Cr[ci]) - 128
+			rc := 91881 * cr1
+			gc := -22554*cb1 - 46802*cr1
+			bc := 116130 * cb1
+			for ; n > 0; n-- {
+				yy1 := int32(src.Y[yi]) * 0x10101
+				if a == nil {
+					d[0] = clamp8(yy1 + rc)
+					d[1] = clamp8(yy1 + gc)
+					d[2] = clamp8(yy1 + bc)
+					d[3] = 0xff
+				} else {
+					aa := uint32(a[ai]) * 0x101
+					d[0] = uint8(clamp16(yy1+rc) * aa / 0xffff >> 8)
+					d[1] = uint8(clamp16(yy1+gc) * aa / 0xffff >> 8)
+					d[2] = uint8(clamp16(yy1+bc) * aa / 0xffff >> 8)
+					d[3] = uint8(aa >> 8)
+					ai++
+				}
+				d = d[4:]
+				yi++
+				x++
+			}
+		}
+	}
+}
+
+// put converts one pixel, writing it to d[:4].
+func put(d []uint8, y, cb, cr uint8) {
+	// The spec says that Y' is in the range [16, 235], but in practice, a Y'
+	// of 0 should be black and 255 white. As in the standard library,
+	// multiply by 0x10101 instead of 0x10000 so that the result's high byte
+	// is the rounded Y'.
+	yy1 := int32(y) * 0x10101
+	cb1 := int32(cb) - 128
+	cr1 := int32(cr) - 128
+	d = d[:4]
+	d[0] = clamp8(yy1 + 91881*cr1)
+	d[1] = clamp8(yy1 - 22554*cb1 - 46802*cr1)
+	d[2] = clamp8(yy1 + 116130*cb1)
+	d[3] = 0xff
+}
+
+// row444 converts a row of pixels that each have their own chroma sample.
+func row444(d, ys, cbs, crs []uint8) {
+	cbs = cbs[:len(ys)]
+	crs = crs[:len(ys)]
+	for i, y := range ys {
+		put(d[4*i:], y, cbs[i], crs[i])
+	}
+}
+
+// row420 converts a row of pixels, starting at an even column, in which each
+// pair of pixels shares a chroma sample. It is also used for 4:2:2 images.
+func row420(d, ys, cbs, crs []uint8) {
+	cbs = cbs[:(len(ys)+1)/2]
+	crs = crs[:len(cbs)]
+	for i, cb := range cbs {
+		cb1 := int32(cb) - 128
+		cr1 := int32(crs[i]) - 128
+		rc := 91881 * cr1
+		gc := -22554*cb1 - 46802*cr1
+		bc := 116130 * cb1
+		for j := 2 * i; j < 2*i+2 && j < len(ys); j++ {
+			yy1 := int32(ys[j]) * 0x10101
+			p := d[4*j : 4*j+4]
+			p[0] = clamp8(yy1 + rc)
+			p[1] = clamp8(yy1 + gc)
+			p[2] = clamp8(yy1 + bc)
+			p[3] = 0xff
+		}
+	}
+}
+
+// clamp8 returns the high byte of the 8.16 fixed point value v, clamped to
+// the range [0, 0xff].
+func clamp8(v int32) uint8 {
+	// A look-up table avoids the branch mispredictions of comparisons, which
+	// are common for noisy images. The integer part of v is in the range
+	// [-256, 512), and masking it maps the negative part to the top of the
+	// table.
+	return clampTable[(v>>16)&0x3ff]
+}
+
+// clampTable maps i to i clamped to [0, 0xff] for i in [0, 512), and to zero
+// for i in [768, 1024), which are the masked values of i in [-256, 0).
+var clampTable = func() (t [1024]uint8) {
+	for i := range t[:512] {
+		if i < 0xff {
+			t[i] = uint8(i)
+		} else {
+			t[i] = 0xff
+		}
+	}
+	return t
+}()
+
+// clamp16 returns the high 16 bits of the 8.16 fixed point value v, clamped
+// to the range [0, 0xffff].
+func clamp16(v int32) uint32 {
+	if uint32(v)&0xff000000 == 0 {
+		return uint32(v >> 8)
+	}
+	return uint32(^(v >> 31)) & 0xffff
+}
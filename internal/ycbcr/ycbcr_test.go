@@ -0,0 +1,97 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ycbcr
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math/rand"
+	"testing"
+)
+
+var ratios = []image.YCbCrSubsampleRatio{
+	image.YCbCrSubsampleRatio444,
+	image.YCbCrSubsampleRatio422,
+	image.YCbCrSubsampleRatio420,
+	image.YCbCrSubsampleRatio440,
+	image.YCbCrSubsampleRatio411,
+	image.YCbCrSubsampleRatio410,
+}
+
+func newYCbCr(r image.Rectangle, ratio image.YCbCrSubsampleRatio, rng *rand.Rand) *image.YCbCr {
+	m := image.NewYCbCr(r, ratio)
+	rng.Read(m.Y)
+	rng.Read(m.Cb)
+	rng.Read(m.Cr)
+	return m
+}
+
+func TestToRGBA(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	// The bounds have odd and negative coordinates, so that the runs of
+	// pixels that share a chroma sample are split at both ends.
+	bounds := image.Rect(-3, -2, 14, 11)
+	for _, ratio := range ratios {
+		src := newYCbCr(bounds, ratio, rng)
+		alpha := &image.NYCbCrA{
+			YCbCr:   *src,
+			A:       make([]uint8, bounds.Dx()*bounds.Dy()),
+			AStride: bounds.Dx(),
+		}
+		rng.Read(alpha.A)
+		// Include the extreme alpha values.
+		alpha.A[0], alpha.A[1] = 0x00, 0xff
+
+		for _, r := range []image.Rectangle{bounds, image.Rect(-1, 1, 12, 9)} {
+			dst := image.NewRGBA(bounds)
+			ToRGBA(dst, r, src)
+			for y := r.Min.Y; y < r.Max.Y; y++ {
+				for x := r.Min.X; x < r.Max.X; x++ {
+					got := dst.RGBAAt(x, y)
+					want := color.RGBAModel.Convert(src.At(x, y))
+					if got != want {
+						t.Fatalf("ratio %v, r %v: (%d, %d): got %v, want %v", ratio, r, x, y, got, want)
+					}
+				}
+			}
+
+			NYCbCrAToRGBA(dst, r, alpha)
+			for y := r.Min.Y; y < r.Max.Y; y++ {
+				for x := r.Min.X; x < r.Max.X; x++ {
+					got := dst.RGBAAt(x, y)
+					want := color.RGBAModel.Convert(alpha.At(x, y))
+					if got != want {
+						t.Fatalf("alpha, ratio %v, r %v: (%d, %d): got %v, want %v", ratio, r, x, y, got, want)
+					}
+				}
+			}
+		}
+	}
+}
+
+func benchmark(b *testing.B, ratio image.YCbCrSubsampleRatio, convert func(dst *image.RGBA, src *image.YCbCr)) {
+	r := image.Rect(0, 0, 1024, 768)
+	src := newYCbCr(r, ratio, rand.New(rand.NewSource(1)))
+	dst := image.NewRGBA(r)
+	b.SetBytes(int64(len(dst.Pix)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		convert(dst, src)
+	}
+}
+
+func toRGBAFunc(dst *image.RGBA, src *image.YCbCr) {
+	ToRGBA(dst, src.Rect, src)
+}
+
+func drawFunc(dst *image.RGBA, src *image.YCbCr) {
+	draw.Draw(dst, src.Rect, src, src.Rect.Min, draw.Src)
+}
+
+func BenchmarkToRGBA420(b *testing.B) { benchmark(b, image.YCbCrSubsampleRatio420, toRGBAFunc) }
+func BenchmarkToRGBA444(b *testing.B) { benchmark(b, image.YCbCrSubsampleRatio444, toRGBAFunc) }
+func BenchmarkDraw420(b *testing.B)   { benchmark(b, image.YCbCrSubsampleRatio420, drawFunc) }
+func BenchmarkDraw444(b *testing.B)   { benchmark(b, image.YCbCrSubsampleRatio444, drawFunc) }
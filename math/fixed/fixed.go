@@ -7,6 +7,7 @@ package fixed // import "golang.org/x/image/math/fixed"
 
 import (
 	"fmt"
+	"math"
 )
 
 // TODO: implement fmt.Formatter for %f and %g.
@@ -61,6 +62,66 @@ func (x Int26_6) Mul(y Int26_6) Int26_6 {
 	return Int26_6((int64(x)*int64(y) + 1<<5) >> 6)
 }
 
+// AddOverflow returns x+y, wrapping around on overflow like the + operator,
+// and whether it overflowed.
+func (x Int26_6) AddOverflow(y Int26_6) (z Int26_6, overflow bool) {
+	z = x + y
+	return z, (x^z)&(y^z) < 0
+}
+
+// SubOverflow returns x-y, wrapping around on overflow like the - operator,
+// and whether it overflowed.
+func (x Int26_6) SubOverflow(y Int26_6) (z Int26_6, overflow bool) {
+	z = x - y
+	return z, (x^y)&(x^z) < 0
+}
+
+// MulOverflow returns x*y in 26.6 fixed-point arithmetic, wrapping around on
+// overflow like Mul, and whether it overflowed.
+func (x Int26_6) MulOverflow(y Int26_6) (z Int26_6, overflow bool) {
+	v := (int64(x)*int64(y) + 1<<5) >> 6
+	return Int26_6(v), v != int64(int32(v))
+}
+
+// AddSat returns x+y, saturating at the largest or smallest Int26_6 value
+// instead of wrapping around on overflow.
+func (x Int26_6) AddSat(y Int26_6) Int26_6 {
+	z, overflow := x.AddOverflow(y)
+	if overflow {
+		return saturate26_6(y >= 0)
+	}
+	return z
+}
+
+// SubSat returns x-y, saturating at the largest or smallest Int26_6 value
+// instead of wrapping around on overflow.
+func (x Int26_6) SubSat(y Int26_6) Int26_6 {
+	z, overflow := x.SubOverflow(y)
+	if overflow {
+		return saturate26_6(y < 0)
+	}
+	return z
+}
+
+// MulSat returns x*y in 26.6 fixed-point arithmetic, saturating at the
+// largest or smallest Int26_6 value instead of wrapping around on overflow.
+func (x Int26_6) MulSat(y Int26_6) Int26_6 {
+	z, overflow := x.MulOverflow(y)
+	if overflow {
+		return saturate26_6((x < 0) == (y < 0))
+	}
+	return z
+}
+
+// saturate26_6 returns the largest Int26_6 value if positive is true, and the
+// smallest one otherwise.
+func saturate26_6(positive bool) Int26_6 {
+	if positive {
+		return math.MaxInt32
+	}
+	return math.MinInt32
+}
+
 // Int52_12 is a signed 52.12 fixed-point number.
 //
 // The integer part ranges from -2251799813685248 to 2251799813685247,
@@ -109,6 +170,76 @@ func (x Int52_12) Mul(y Int52_12) Int52_12 {
 	return ret
 }
 
+// AddOverflow returns x+y, wrapping around on overflow like the + operator,
+// and whether it overflowed.
+func (x Int52_12) AddOverflow(y Int52_12) (z Int52_12, overflow bool) {
+	z = x + y
+	return z, (x^z)&(y^z) < 0
+}
+
+// SubOverflow returns x-y, wrapping around on overflow like the - operator,
+// and whether it overflowed.
+func (x Int52_12) SubOverflow(y Int52_12) (z Int52_12, overflow bool) {
+	z = x - y
+	return z, (x^y)&(x^z) < 0
+}
+
+// MulOverflow returns x*y in 52.12 fixed-point arithmetic, wrapping around on
+// overflow like Mul, and whether it overflowed.
+func (x Int52_12) MulOverflow(y Int52_12) (z Int52_12, overflow bool) {
+	const M, N = 52, 12
+	lo, hi := muli64(int64(x), int64(y))
+	z = Int52_12(hi<<M | lo>>N)
+	// The 128-bit product, shifted right by N, fits in z if the bits above
+	// z's sign bit are all copies of it.
+	overflow = int64(hi)>>N != int64(z)>>63
+	if (lo>>(N-1))&1 != 0 {
+		// Round to nearest, instead of rounding down.
+		overflow = overflow || z == math.MaxInt64
+		z++
+	}
+	return z, overflow
+}
+
+// AddSat returns x+y, saturating at the largest or smallest Int52_12 value
+// instead of wrapping around on overflow.
+func (x Int52_12) AddSat(y Int52_12) Int52_12 {
+	z, overflow := x.AddOverflow(y)
+	if overflow {
+		return saturate52_12(y >= 0)
+	}
+	return z
+}
+
+// SubSat returns x-y, saturating at the largest or smallest Int52_12 value
+// instead of wrapping around on overflow.
+func (x Int52_12) SubSat(y Int52_12) Int52_12 {
+	z, overflow := x.SubOverflow(y)
+	if overflow {
+		return saturate52_12(y < 0)
+	}
+	return z
+}
+
+// MulSat returns x*y in 52.12 fixed-point arithmetic, saturating at the
+// largest or smallest Int52_12 value instead of wrapping around on overflow.
+func (x Int52_12) MulSat(y Int52_12) Int52_12 {
+	z, overflow := x.MulOverflow(y)
+	if overflow {
+		return saturate52_12((x < 0) == (y < 0))
+	}
+	return z
+}
+
+// saturate52_12 returns the largest Int52_12 value if positive is true, and
+// the smallest one otherwise.
+func saturate52_12(positive bool) Int52_12 {
+	if positive {
+		return math.MaxInt64
+	}
+	return math.MinInt64
+}
+
 // muli64 multiplies two int64 values, returning the 128-bit signed integer
 // result as two uint64 values.
 //
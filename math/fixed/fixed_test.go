@@ -6,6 +6,7 @@ package fixed
 
 import (
 	"math"
+	"math/big"
 	"math/rand"
 	"testing"
 )
@@ -346,6 +347,126 @@ func TestInt26_6MulVsMul(t *testing.T) {
 	}
 }
 
+func TestInt26_6Sat(t *testing.T) {
+	const max, min = Int26_6(math.MaxInt32), Int26_6(math.MinInt32)
+	testCases := []struct {
+		op           string
+		x, y         Int26_6
+		want         Int26_6
+		wantOverflow bool
+		wantSat      Int26_6
+	}{
+		{"+", 1 << 6, 2 << 6, 3 << 6, false, 3 << 6},
+		{"+", max, 1, min, true, max},
+		{"+", min, -1, max, true, min},
+		{"+", max, min, -1, false, -1},
+		{"-", 1 << 6, 2 << 6, -1 << 6, false, -1 << 6},
+		{"-", min, 1, max, true, min},
+		{"-", max, -1, min, true, max},
+		{"-", -1, max, min, false, min},
+		{"-", 0, min, min, true, max},
+		{"*", 3 << 6, -2 << 6, -6 << 6, false, -6 << 6},
+		{"*", max, 1 << 6, max, false, max},
+		{"*", min, 1 << 6, min, false, min},
+		{"*", 1 << 18, 1 << 18, 1 << 30, false, 1 << 30},
+		{"*", 1 << 19, 1 << 19, 0, true, max},
+		{"*", -1 << 19, -1 << 19, 0, true, max},
+		{"*", 1 << 19, -1 << 18, min, false, min},
+		{"*", 1 << 19, -1<<18 - 1, max - 1<<13 + 1, true, min},
+		{"*", min, -1 << 6, min, true, max},
+	}
+	for _, tc := range testCases {
+		var got, gotSat Int26_6
+		var gotOverflow bool
+		switch tc.op {
+		case "+":
+			got, gotOverflow = tc.x.AddOverflow(tc.y)
+			gotSat = tc.x.AddSat(tc.y)
+		case "-":
+			got, gotOverflow = tc.x.SubOverflow(tc.y)
+			gotSat = tc.x.SubSat(tc.y)
+		case "*":
+			got, gotOverflow = tc.x.MulOverflow(tc.y)
+			gotSat = tc.x.MulSat(tc.y)
+			if want := tc.x.Mul(tc.y); got != want {
+				t.Errorf("%d * %d: MulOverflow and Mul differ: %d and %d", tc.x, tc.y, got, want)
+			}
+		}
+		if got != tc.want || gotOverflow != tc.wantOverflow {
+			t.Errorf("%d %s %d: got (%d, %t), want (%d, %t)",
+				tc.x, tc.op, tc.y, got, gotOverflow, tc.want, tc.wantOverflow)
+		}
+		if gotSat != tc.wantSat {
+			t.Errorf("%d %s %d: saturated: got %d, want %d", tc.x, tc.op, tc.y, gotSat, tc.wantSat)
+		}
+	}
+}
+
+func TestInt52_12MulOverflow(t *testing.T) {
+	maxInt64, minInt64 := big.NewInt(math.MaxInt64), big.NewInt(math.MinInt64)
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		// Vary the magnitudes, so that both overflowing and non-overflowing
+		// products are common.
+		u := Int52_12(int64(rng.Uint64()) >> uint(rng.Intn(64)))
+		v := Int52_12(int64(rng.Uint64()) >> uint(rng.Intn(64)))
+
+		// want is u*v, shifted right by 12 and rounded to nearest.
+		want := new(big.Int).Mul(big.NewInt(int64(u)), big.NewInt(int64(v)))
+		want.Add(want, big.NewInt(1<<11))
+		want.Rsh(want, 12)
+		wantOverflow := want.Cmp(maxInt64) > 0 || want.Cmp(minInt64) < 0
+
+		got, gotOverflow := u.MulOverflow(v)
+		if gotOverflow != wantOverflow {
+			t.Errorf("u=%d, v=%d: overflow: got %t, want %t", u, v, gotOverflow, wantOverflow)
+		}
+		if Mul := u.Mul(v); got != Mul {
+			t.Errorf("u=%d, v=%d: MulOverflow and Mul differ: %d and %d", u, v, got, Mul)
+		}
+		wantSat := Int52_12(0)
+		switch {
+		case want.Cmp(maxInt64) > 0:
+			wantSat = math.MaxInt64
+		case want.Cmp(minInt64) < 0:
+			wantSat = math.MinInt64
+		default:
+			wantSat = Int52_12(want.Int64())
+		}
+		if gotSat := u.MulSat(v); gotSat != wantSat {
+			t.Errorf("u=%d, v=%d: MulSat: got %d, want %d", u, v, gotSat, wantSat)
+		}
+	}
+}
+
+func TestInt52_12AddSubSat(t *testing.T) {
+	const max, min = Int52_12(math.MaxInt64), Int52_12(math.MinInt64)
+	if got, overflow := max.AddOverflow(1); got != min || !overflow {
+		t.Errorf("max.AddOverflow(1): got (%d, %t), want (%d, true)", got, overflow, min)
+	}
+	if got := max.AddSat(1); got != max {
+		t.Errorf("max.AddSat(1): got %d, want %d", got, max)
+	}
+	if got := min.AddSat(-1); got != min {
+		t.Errorf("min.AddSat(-1): got %d, want %d", got, min)
+	}
+	if got, overflow := max.AddOverflow(min); got != -1 || overflow {
+		t.Errorf("max.AddOverflow(min): got (%d, %t), want (-1, false)", got, overflow)
+	}
+	if got, overflow := min.SubOverflow(1); got != max || !overflow {
+		t.Errorf("min.SubOverflow(1): got (%d, %t), want (%d, true)", got, overflow, max)
+	}
+	if got := min.SubSat(1); got != min {
+		t.Errorf("min.SubSat(1): got %d, want %d", got, min)
+	}
+	if got := Int52_12(0).SubSat(min); got != max {
+		t.Errorf("0.SubSat(min): got %d, want %d", got, max)
+	}
+	if got := Int52_12(5 << 12).SubSat(7 << 12); got != -2<<12 {
+		t.Errorf("5.SubSat(7): got %v, want -2:0000", got)
+	}
+}
+
 func TestMuli32(t *testing.T) {
 	rng := rand.New(rand.NewSource(2))
 	for i := 0; i < 10000; i++ {
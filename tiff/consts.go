@@ -29,13 +29,27 @@ const (
 	dtShort    = 3
 	dtLong     = 4
 	dtRational = 5
+
+	// The following data types were added in TIFF 6.0 (p. 16 of the spec).
+	dtSByte     = 6
+	dtUndefined = 7
+	dtSShort    = 8
+	dtSLong     = 9
+	dtSRational = 10
+	dtFloat     = 11
+	dtDouble    = 12
+
+	// The IFD data type, for offsets of other IFDs, is from TIFF Technical
+	// Note 1. Its values are like those of the Long data type.
+	dtIFD = 13
 )
 
 // The length of one instance of each data type in bytes.
-var lengths = [...]uint32{0, 1, 1, 2, 4, 8}
+var lengths = [...]uint32{0, 1, 1, 2, 4, 8, 1, 1, 2, 4, 8, 4, 8}
 
 // Tags (see p. 28-41 of the spec).
 const (
+	tNewSubfileType            = 254
 	tImageWidth                = 256
 	tImageLength               = 257
 	tBitsPerSample             = 258
@@ -48,6 +62,8 @@ const (
 	tSamplesPerPixel = 277
 	tRowsPerStrip    = 278
 	tStripByteCounts = 279
+	tMinSampleValue  = 280
+	tMaxSampleValue  = 281
 
 	tT4Options = 292 // CCITT Group 3 options, a set of 32 flag bits.
 	tT6Options = 293 // CCITT Group 4 options, a set of 32 flag bits.
@@ -57,14 +73,25 @@ const (
 	tTileOffsets    = 324
 	tTileByteCounts = 325
 
+	tSubIFDs = 330 // From TIFF Technical Note 1.
+
+	tInkSet   = 332
+	tInkNames = 333
+
 	tXResolution    = 282
 	tYResolution    = 283
 	tResolutionUnit = 296
 
-	tPredictor    = 317
-	tColorMap     = 320
-	tExtraSamples = 338
-	tSampleFormat = 339
+	tPredictor       = 317
+	tColorMap        = 320
+	tExtraSamples    = 338
+	tSampleFormat    = 339
+	tSMinSampleValue = 340
+	tSMaxSampleValue = 341
+
+	tJPEGTables = 347 // From TIFF Technical Note 2.
+
+	tYCbCrSubSampling = 530
 )
 
 // Compression types (defined in various places in the spec and supplements).
@@ -91,6 +118,31 @@ const (
 	pCMYK        = 5
 	pYCbCr       = 6
 	pCIELab      = 8
+	pICCLab      = 9 // CIELab with unsigned a* and b*, as per TIFF Technical Note 4.
+)
+
+// Values for the tInkSet tag (page 70 of the spec).
+const (
+	inkSetCMYK    = 1
+	inkSetNotCMYK = 2
+)
+
+// Flag bits of the tNewSubfileType tag (page 36 of the spec).
+const (
+	nsReducedResolution = 1 // A reduced-resolution version of another image.
+)
+
+// Values for the tExtraSamples tag (page 31-32 of the spec).
+const (
+	esUnspecified       = 0
+	esAssociatedAlpha   = 1 // Premultiplied alpha.
+	esUnassociatedAlpha = 2 // Straight alpha.
+)
+
+// Values for the tSampleFormat tag (page 80 of the spec).
+const (
+	sfUint = 1
+	sfInt  = 2
 )
 
 // Values for the tPredictor tag (page 64-65 of the spec).
@@ -114,22 +166,32 @@ const (
 	mPaletted
 	mGray
 	mGrayInvert
+	mGrayA  // Gray with associated (premultiplied) alpha.
+	mGrayNA // Gray with unassociated (straight) alpha.
 	mRGB
 	mRGBA
 	mNRGBA
 	mCMYK
+	mCIELab
+	mICCLab
+	mYCbCr
 )
 
 // CompressionType describes the type of compression used in Options.
 type CompressionType int
 
-// Constants for supported compression types.
+// Constants for supported compression types. The CCITT compression types,
+// as used for faxes, are only for bilevel images.
 const (
 	Uncompressed CompressionType = iota
 	Deflate
 	LZW
 	CCITTGroup3
 	CCITTGroup4
+	// CCITTRLE is CCITT Modified Huffman run-length encoding: Group 3
+	// compression without EOL codes, with each row starting on a byte
+	// boundary.
+	CCITTRLE
 )
 
 // specValue returns the compression type constant from the TIFF spec that
@@ -144,6 +206,70 @@ func (c CompressionType) specValue() uint32 {
 		return cG3
 	case CCITTGroup4:
 		return cG4
+	case CCITTRLE:
+		return cCCITT
 	}
 	return cNone
 }
+
+// PhotometricInterpretation describes whether the zero bits of a bilevel
+// image written by Encode are white or black.
+type PhotometricInterpretation int
+
+// Constants for the supported photometric interpretations of bilevel images.
+// Their values are those of the TIFF spec.
+const (
+	// WhiteIsZero is the usual interpretation for fax images.
+	WhiteIsZero PhotometricInterpretation = pWhiteIsZero
+	BlackIsZero PhotometricInterpretation = pBlackIsZero
+)
+
+// SampleFormat describes how the samples of an image written by Encode are
+// interpreted.
+type SampleFormat int
+
+// Constants for the supported sample formats.
+const (
+	// UnsignedInteger is the default, and the only sample format that Decode
+	// supports.
+	UnsignedInteger SampleFormat = iota
+	// SignedInteger is two's complement signed integer data.
+	SignedInteger
+)
+
+// specValue returns the sample format constant from the TIFF spec that is
+// equivalent to f.
+func (f SampleFormat) specValue() uint32 {
+	if f == SignedInteger {
+		return sfInt
+	}
+	return sfUint
+}
+
+// ResolutionUnit is the unit of the resolution of an image, as per page 18
+// of the spec.
+type ResolutionUnit int
+
+// Constants for the resolution units.
+const (
+	// Inch is the default unit, for pixels per inch.
+	Inch ResolutionUnit = iota
+	// Centimeter is for pixels per centimeter.
+	Centimeter
+	// NoUnit is for images, such as those of some screens, that have no
+	// absolute size. Their resolution only gives their pixels' aspect
+	// ratio.
+	NoUnit
+)
+
+// specValue returns the resolution unit constant from the TIFF spec that is
+// equivalent to u.
+func (u ResolutionUnit) specValue() uint32 {
+	switch u {
+	case Centimeter:
+		return resPerCM
+	case NoUnit:
+		return resNone
+	}
+	return resPerInch
+}
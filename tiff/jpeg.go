@@ -0,0 +1,87 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tiff
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+)
+
+// JPEG compression, as per TIFF Technical Note 2, stores each strip or tile
+// as a JPEG stream. The streams may leave out the quantization and Huffman
+// tables that they have in common, which the JPEGTables tag then holds as an
+// abbreviated JPEG stream, from its SOI marker to its EOI marker.
+
+// decodeJPEG decodes the JPEG stream of n bytes at offset, of a strip or tile
+// of w×h pixels, into d.buf as 8-bit samples, d.spp per pixel.
+func (d *decoder) decodeJPEG(offset, n int64, w, h int) error {
+	data, err := safeReadAt(d.r, uint64(n), offset)
+	if err != nil {
+		return err
+	}
+	if t := d.jpegTables; len(t) >= 4 && isSOI(t) && isSOI(data) &&
+		t[len(t)-2] == 0xff && t[len(t)-1] == 0xd9 {
+		// Put the tables between the stream's SOI marker and the rest of
+		// the stream, by replacing the tables' EOI marker with the stream
+		// after its SOI marker.
+		data = append(t[:len(t)-2:len(t)-2], data[2:]...)
+	}
+	m, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil {
+		if _, ok := err.(jpeg.UnsupportedError); ok {
+			return errorf(ErrUnsupportedCompression, "JPEG: %v", err)
+		}
+		return errorf(ErrBadJPEG, "%v", err)
+	}
+	b := m.Bounds()
+	if b.Dx() < w || b.Dy() < h {
+		return errorf(ErrNoPixels, "%dx%d JPEG image for a %dx%d block", b.Dx(), b.Dy(), w, h)
+	}
+
+	size := w * h * d.spp
+	if cap(d.buf) < size {
+		d.buf = make([]byte, size)
+	}
+	d.buf = d.buf[:size]
+	i := 0
+	for y := b.Min.Y; y < b.Min.Y+h; y++ {
+		switch m := m.(type) {
+		case *image.Gray:
+			if d.spp == 1 {
+				i += copy(d.buf[i:], m.Pix[m.PixOffset(b.Min.X, y):][:w])
+				continue
+			}
+		case *image.YCbCr:
+			if d.spp == 1 {
+				i += copy(d.buf[i:], m.Y[m.YOffset(b.Min.X, y):][:w])
+				continue
+			}
+			for x := b.Min.X; x < b.Min.X+w; x++ {
+				yi, ci := m.YOffset(x, y), m.COffset(x, y)
+				d.buf[i+0], d.buf[i+1], d.buf[i+2] = color.YCbCrToRGB(m.Y[yi], m.Cb[ci], m.Cr[ci])
+				i += 3
+			}
+			continue
+		}
+		for x := b.Min.X; x < b.Min.X+w; x++ {
+			c := m.At(x, y)
+			if d.spp == 1 {
+				d.buf[i] = color.GrayModel.Convert(c).(color.Gray).Y
+			} else {
+				rgba := color.RGBAModel.Convert(c).(color.RGBA)
+				d.buf[i+0], d.buf[i+1], d.buf[i+2] = rgba.R, rgba.G, rgba.B
+			}
+			i += d.spp
+		}
+	}
+	return nil
+}
+
+// isSOI returns whether b starts with a JPEG SOI (Start Of Image) marker.
+func isSOI(b []byte) bool {
+	return len(b) >= 2 && b[0] == 0xff && b[1] == 0xd8
+}
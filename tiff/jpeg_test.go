@@ -0,0 +1,217 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"testing"
+)
+
+// splitJPEG splits the JPEG stream b into an abbreviated stream of its
+// quantization and Huffman tables, as for the JPEGTables tag, and the
+// stream without those tables.
+func splitJPEG(t *testing.T, b []byte) (tables, rest []byte) {
+	t.Helper()
+	tables = []byte{0xff, 0xd8}
+	rest = []byte{0xff, 0xd8}
+	for i := 2; ; {
+		if i+4 > len(b) || b[i] != 0xff {
+			t.Fatalf("bad JPEG marker at offset %d", i)
+		}
+		if b[i+1] == 0xda { // SOS.
+			rest = append(rest, b[i:]...)
+			break
+		}
+		n := 2 + int(binary.BigEndian.Uint16(b[i+2:]))
+		switch b[i+1] {
+		case 0xdb, 0xc4: // DQT, DHT.
+			tables = append(tables, b[i:i+n]...)
+		default:
+			rest = append(rest, b[i:i+n]...)
+		}
+		i += n
+	}
+	return append(tables, 0xff, 0xd9), rest
+}
+
+func TestDecodeJPEG(t *testing.T) {
+	const w, h = 40, 36
+	src := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			src.SetRGBA(x, y, color.RGBA{uint8(6 * x), uint8(7 * y), uint8(3 * (x + y)), 0xff})
+		}
+	}
+	gray := image.NewGray(src.Bounds())
+	draw.Draw(gray, gray.Bounds(), src, image.Point{}, draw.Src)
+
+	testCases := []struct {
+		desc        string
+		src         image.Image
+		photometric uint16
+		spp         int
+		blockSize   int
+		tiled       bool
+		tables      bool
+	}{
+		{"YCbCr strips", src, pYCbCr, 3, 16, false, false},
+		{"YCbCr strips with JPEGTables", src, pYCbCr, 3, 16, false, true},
+		{"YCbCr tiles with JPEGTables", src, pYCbCr, 3, 16, true, true},
+		{"gray strips", gray, pBlackIsZero, 1, 8, false, false},
+		{"gray tiles with JPEGTables", gray, pBlackIsZero, 1, 32, true, true},
+	}
+	for _, tc := range testCases {
+		enc := binary.LittleEndian
+		b := newTIFF(enc)
+		// want is what the JPEG decoder decodes the blocks as.
+		var want image.Image
+		if tc.spp == 1 {
+			want = image.NewGray(tc.src.Bounds())
+		} else {
+			want = image.NewRGBA(tc.src.Bounds())
+		}
+		var offsets, counts []uint32
+		var tables []byte
+		bw, bh := w, tc.blockSize
+		if tc.tiled {
+			bw = tc.blockSize
+		}
+		for y0 := 0; y0 < h; y0 += bh {
+			for x0 := 0; x0 < w; x0 += bw {
+				// Tiles have their full size, and strips end at the bottom
+				// of the image.
+				r := image.Rect(x0, y0, x0+bw, y0+bh)
+				if !tc.tiled && r.Max.Y > h {
+					r.Max.Y = h
+				}
+				block := image.NewRGBA(image.Rect(0, 0, r.Dx(), r.Dy()))
+				draw.Draw(block, block.Bounds(), tc.src, r.Min, draw.Src)
+				var m image.Image = block
+				if tc.spp == 1 {
+					g := image.NewGray(block.Bounds())
+					draw.Draw(g, g.Bounds(), block, image.Point{}, draw.Src)
+					m = g
+				}
+				var buf bytes.Buffer
+				if err := jpeg.Encode(&buf, m, &jpeg.Options{Quality: 90}); err != nil {
+					t.Fatal(err)
+				}
+				data := buf.Bytes()
+				decoded, err := jpeg.Decode(bytes.NewReader(data))
+				if err != nil {
+					t.Fatal(err)
+				}
+				draw.Draw(want.(draw.Image), r, decoded, image.Point{}, draw.Src)
+				if tc.tables {
+					tables, data = splitJPEG(t, data)
+				}
+				offsets = append(offsets, uint32(len(b)))
+				counts = append(counts, uint32(len(data)))
+				b = append(b, data...)
+			}
+		}
+		bps := make([]uint16, tc.spp)
+		for i := range bps {
+			bps[i] = 8
+		}
+		entries := map[uint16]interface{}{
+			tImageWidth:                uint32(w),
+			tImageLength:               uint32(h),
+			tBitsPerSample:             bps,
+			tCompression:               uint16(cJPEG),
+			tPhotometricInterpretation: tc.photometric,
+		}
+		if tc.tiled {
+			entries[tTileWidth] = uint32(bw)
+			entries[tTileLength] = uint32(bh)
+			entries[tTileOffsets] = offsets
+			entries[tTileByteCounts] = counts
+		} else {
+			entries[tRowsPerStrip] = uint32(bh)
+			entries[tStripOffsets] = offsets
+			entries[tStripByteCounts] = counts
+		}
+		if tc.tables {
+			entries[tJPEGTables] = tables
+		}
+		b = appendIFD(b, enc, entries)
+
+		got, err := Decode(bytes.NewReader(b))
+		if err != nil {
+			t.Errorf("%s: Decode: %v", tc.desc, err)
+			continue
+		}
+		compare(t, got, want)
+		if err := Validate(bytes.NewReader(b)); err != nil {
+			t.Errorf("%s: Validate: %v", tc.desc, err)
+		}
+
+		// Without the tables, the blocks cannot be decoded.
+		if tc.tables {
+			delete(entries, tJPEGTables)
+			b = appendIFD(b, enc, entries)
+			if _, err := Decode(bytes.NewReader(b)); !errors.Is(err, ErrBadJPEG) {
+				t.Errorf("%s: without JPEGTables: got %v, want %v", tc.desc, err, ErrBadJPEG)
+			}
+		}
+	}
+}
+
+func TestDecodeJPEGErrors(t *testing.T) {
+	const w, h = 4, 2
+	enc := binary.BigEndian
+	build := func(edit func(map[uint16]interface{})) []byte {
+		b := newTIFF(enc)
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, w, h)), nil); err != nil {
+			t.Fatal(err)
+		}
+		entries := map[uint16]interface{}{
+			tImageWidth:                uint32(w),
+			tImageLength:               uint32(h),
+			tBitsPerSample:             uint16(8),
+			tCompression:               uint16(cJPEG),
+			tPhotometricInterpretation: uint16(pBlackIsZero),
+			tRowsPerStrip:              uint32(h),
+			tStripOffsets:              uint32(len(b)),
+			tStripByteCounts:           uint32(buf.Len()),
+		}
+		b = append(b, buf.Bytes()...)
+		edit(entries)
+		return appendIFD(b, enc, entries)
+	}
+	if _, err := Decode(bytes.NewReader(build(func(map[uint16]interface{}) {}))); err != nil {
+		t.Fatalf("valid: %v", err)
+	}
+
+	testCases := []struct {
+		desc string
+		edit func(map[uint16]interface{})
+		want error
+	}{
+		{"corrupt data", func(e map[uint16]interface{}) { e[tStripOffsets] = uint32(4) }, ErrBadJPEG},
+		{"too small JPEG image", func(e map[uint16]interface{}) {
+			e[tImageLength] = uint32(h + 1)
+			e[tRowsPerStrip] = uint32(h + 1)
+		}, ErrNoPixels},
+		{"16-bit samples", func(e map[uint16]interface{}) { e[tBitsPerSample] = uint16(16) }, ErrUnsupportedCompression},
+		{"extra samples", func(e map[uint16]interface{}) { e[tBitsPerSample] = []uint16{8, 8} }, ErrUnsupportedCompression},
+		{"CMYK", func(e map[uint16]interface{}) {
+			e[tPhotometricInterpretation] = uint16(pCMYK)
+			e[tBitsPerSample] = []uint16{8, 8, 8, 8}
+		}, ErrUnsupportedCompression},
+	}
+	for _, tc := range testCases {
+		if _, err := Decode(bytes.NewReader(build(tc.edit))); !errors.Is(err, tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.desc, err, tc.want)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tiff
+
+import "math"
+
+// The CIELab and ICCLab photometric interpretations store colors in the CIE
+// 1976 L*a*b* color space, relative to the D50 white point. Both store L* as
+// an unsigned value in [0, 100]. CIELab stores a* and b* as signed values,
+// and ICCLab stores them as unsigned values offset by 128. Decoding converts
+// them to sRGB, as Adobe Photoshop and libtiff's RGBA interface do.
+
+// labColorSamples returns the number of color samples, 1 for L* only or 3 for
+// L*a*b*, of an image with spp samples per pixel. Any other samples are
+// extra samples.
+func labColorSamples(spp int) int {
+	if spp < 3 {
+		return 1
+	}
+	return 3
+}
+
+// lab returns the L*, a* and b* values of the pixel whose samples start at
+// p[0]. The a* and b* values are zero if the image stores only L*.
+func (d *decoder) lab(p []byte) (l, a, b float64) {
+	if d.bpp == 16 {
+		l = float64(d.byteOrder.Uint16(p[0:2])) * 100 / 0xffff
+		if labColorSamples(d.spp) == 1 {
+			return l, 0, 0
+		}
+		u, v := d.byteOrder.Uint16(p[2:4]), d.byteOrder.Uint16(p[4:6])
+		if d.mode == mICCLab {
+			return l, float64(u)/256 - 128, float64(v)/256 - 128
+		}
+		return l, float64(int16(u)) / 256, float64(int16(v)) / 256
+	}
+	l = float64(p[0]) * 100 / 0xff
+	if labColorSamples(d.spp) == 1 {
+		return l, 0, 0
+	}
+	if d.mode == mICCLab {
+		return l, float64(p[1]) - 128, float64(p[2]) - 128
+	}
+	return l, float64(int8(p[1])), float64(int8(p[2]))
+}
+
+// labInv is the inverse of the CIE L*a*b* companding function.
+func labInv(t float64) float64 {
+	const delta = 6.0 / 29
+	if t > delta {
+		return t * t * t
+	}
+	return 3 * delta * delta * (t - 4.0/29)
+}
+
+// labToRGB converts a D50 L*a*b* color to sRGB, with components in [0, 1].
+func labToRGB(l, a, b float64) (rr, gg, bb float64) {
+	// Convert to XYZ, relative to the D50 white point.
+	fy := (l + 16) / 116
+	x := 0.96422 * labInv(fy+a/500)
+	y := labInv(fy)
+	z := 0.82521 * labInv(fy-b/200)
+
+	// Convert to linear sRGB. The matrix includes the Bradford chromatic
+	// adaptation from D50 to sRGB's D65 white point.
+	rr = srgbEncode(+3.1338561*x - 1.6168667*y - 0.4906146*z)
+	gg = srgbEncode(-0.9787684*x + 1.9161415*y + 0.0334540*z)
+	bb = srgbEncode(+0.0719453*x - 0.2289914*y + 1.4052427*z)
+	return rr, gg, bb
+}
+
+// labToGray converts an L* value to an sRGB gray level in [0, 1].
+func labToGray(l float64) float64 {
+	return srgbEncode(labInv((l + 16) / 116))
+}
+
+// srgbEncode clamps the linear value c to [0, 1] and applies the sRGB
+// transfer function.
+func srgbEncode(c float64) float64 {
+	if c <= 0.0031308 {
+		return math.Max(12.92*c, 0)
+	}
+	return math.Min(1.055*math.Pow(c, 1/2.4)-0.055, 1)
+}
@@ -0,0 +1,160 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tiff
+
+import (
+	"encoding/binary"
+	"errors"
+	"image"
+	"io"
+)
+
+// A Page is one of the images of a multi-page TIFF file, such as a page of a
+// scanned document or of a fax.
+type Page struct {
+	Image image.Image
+	// Tags are the entries of the page's IFD that DecodeExtraTags would
+	// return if it were the first IFD, such as its PageNumber and
+	// DocumentName.
+	Tags []Tag
+	// XResolution, YResolution and ResolutionUnit are the page's
+	// resolution, as DecodeResolution returns it, so that the page can be
+	// encoded with the same resolution.
+	XResolution    float64
+	YResolution    float64
+	ResolutionUnit ResolutionUnit
+}
+
+// DecodeAll reads the pages of a multi-page TIFF file from r. Those are the
+// images of the first IFD and of the IFDs that follow it, in order, other
+// than those whose NewSubfileType marks them as a reduced-resolution version
+// of another image, such as a thumbnail. The first page is the image that
+// Decode returns, unless the first IFD is such a reduced-resolution image.
+//
+// It returns an error if any of the pages cannot be decoded. A file whose
+// IFDs form a loop is read up to the first repeated IFD.
+func DecodeAll(r io.Reader) ([]Page, error) {
+	ra := newReaderAt(r)
+	byteOrder, ifdOffset, err := readHeader(ra)
+	if err != nil {
+		return nil, err
+	}
+
+	var pages []Page
+	seen := map[int64]bool{}
+	for off := ifdOffset; off != 0 && !seen[off]; {
+		seen[off] = true
+		d, err := newIFDDecoder(ra, byteOrder, off)
+		if err != nil {
+			return nil, err
+		}
+		if d.firstVal(tNewSubfileType)&nsReducedResolution == 0 {
+			img, err := d.decodeImage()
+			if err != nil {
+				return nil, err
+			}
+			tags, err := readIFDExtraTags(ra, byteOrder, off)
+			if err != nil {
+				return nil, err
+			}
+			x, y, unit := d.resolution()
+			pages = append(pages, Page{
+				Image:          img,
+				Tags:           tags,
+				XResolution:    x,
+				YResolution:    y,
+				ResolutionUnit: unit,
+			})
+		}
+		if off, err = nextIFD(ra, byteOrder, off); err != nil {
+			return nil, err
+		}
+	}
+	return pages, nil
+}
+
+// An Encoder writes a multi-page TIFF file, one image at a time, with the
+// images' IFDs chained in the order that they are given. Call Encode for each
+// page, and then Close. The byte order of the file is that of the first
+// page's options, and the other pages must have the same byte order.
+//
+// Each page's IFD is written after the pixel data of the page that follows
+// it, or when the Encoder is closed, as it ends with the offset of that next
+// page's IFD. Compressed pixel data is held in memory until it is written.
+type Encoder struct {
+	w io.Writer
+	// off is the number of bytes written to w.
+	off int
+	// ifd is the IFD of the most recently encoded page, which is written at
+	// offset off, once the offset of the next IFD is known.
+	ifd   []ifdEntry
+	order binary.ByteOrder
+	err   error
+}
+
+// NewEncoder returns an Encoder that writes a multi-page TIFF file to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// Encode writes the image m as the next page of the file. opt determines the
+// options used for encoding this page, as for the Encode function, and so
+// each page can be encoded with different options. Per-page tags, such as a
+// page's PageNumber, can be given in opt.ExtraTags.
+func (e *Encoder) Encode(m image.Image, opt *Options) error {
+	if e.err != nil {
+		return e.err
+	}
+	o, err := parseOptions(opt)
+	if err != nil {
+		return err
+	}
+	if e.ifd != nil && o.byteOrder != e.order {
+		return errors.New("tiff: pages with different byte orders")
+	}
+	p, err := newPageEncoder(m, o)
+	if err != nil {
+		return err
+	}
+
+	if e.ifd == nil {
+		// The first IFD follows the first page's pixel data, which follows
+		// the 8 header bytes.
+		e.order = o.byteOrder
+		if e.err = writeFileHeader(e.w, e.order, 8+p.imageLen); e.err != nil {
+			return e.err
+		}
+		e.off = 8
+	} else {
+		// The previous page's IFD is followed by this page's pixel data,
+		// and then by this page's IFD.
+		ifdOffset, n := e.off, ifdLength(e.ifd)
+		if e.err = writeIFD(e.w, e.order, ifdOffset, e.ifd, ifdOffset+n+p.imageLen); e.err != nil {
+			return e.err
+		}
+		e.off += n
+	}
+
+	if e.err = p.writePixels(e.w); e.err != nil {
+		return e.err
+	}
+	e.ifd = p.ifd(e.off)
+	e.off += p.imageLen
+	return nil
+}
+
+// Close finishes writing the file, after every page has been written by
+// Encode. It returns an error if no page was written. It does not close the
+// underlying io.Writer.
+func (e *Encoder) Close() error {
+	if e.err != nil {
+		return e.err
+	}
+	e.err = errors.New("tiff: Encoder is closed")
+	if e.ifd == nil {
+		return errors.New("tiff: no images to encode")
+	}
+	return writeIFD(e.w, e.order, e.off, e.ifd, 0)
+}
@@ -0,0 +1,199 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// setNextIFD links the IFD at off in b to the IFD at next.
+func setNextIFD(b []byte, enc byteOrder, off, next uint32) {
+	n := uint32(enc.Uint16(b[off:]))
+	enc.PutUint32(b[off+2+ifdLen*n:], next)
+}
+
+func TestDecodeAll(t *testing.T) {
+	const tPageNumber = 297
+	for _, enc := range []byteOrder{binary.LittleEndian, binary.BigEndian} {
+		page := func(i int, gray uint16) thumbnailTestIFD {
+			return thumbnailTestIFD{w: 10 + i, h: 3, rowsPerStrip: 2, bpp: 8, pix: constPix(gray), extra: map[uint16]interface{}{
+				tPageNumber: []uint16{uint16(i), 3},
+			}}
+		}
+		b := newTIFF(enc)
+		b, o0 := appendThumbnailTestIFD(b, enc, page(0, 0x1010))
+		b, o1 := appendThumbnailTestIFD(b, enc, thumbnailTestIFD{w: 5, h: 1, rowsPerStrip: 1, bpp: 8, pix: constPix(0xffff), extra: map[uint16]interface{}{
+			tNewSubfileType: uint32(nsReducedResolution),
+		}})
+		b, o2 := appendThumbnailTestIFD(b, enc, page(1, 0x2020))
+		b, o3 := appendThumbnailTestIFD(b, enc, page(2, 0x3030))
+		enc.PutUint32(b[4:8], o0)
+		setNextIFD(b, enc, o0, o1)
+		setNextIFD(b, enc, o1, o2)
+		setNextIFD(b, enc, o2, o3)
+
+		pages, err := DecodeAll(bytes.NewReader(b))
+		if err != nil {
+			t.Fatalf("%v: DecodeAll: %v", enc, err)
+		}
+		if len(pages) != 3 {
+			t.Fatalf("%v: got %d pages, want 3", enc, len(pages))
+		}
+		first, err := Decode(bytes.NewReader(b))
+		if err != nil {
+			t.Fatalf("%v: Decode: %v", enc, err)
+		}
+		compare(t, pages[0].Image, first)
+		for i, p := range pages {
+			if got, want := p.Image.Bounds().Dx(), 10+i; got != want {
+				t.Errorf("%v: page %d: width: got %d, want %d", enc, i, got, want)
+			}
+			if got, want := color.GrayModel.Convert(p.Image.At(1, 2)).(color.Gray).Y, uint8(0x10*(i+1)); got != want {
+				t.Errorf("%v: page %d: gray: got %#02x, want %#02x", enc, i, got, want)
+			}
+			wantTags := []Tag{{ID: tPageNumber, Type: dtShort, Count: 2, Value: []byte{byte(i), 0, 3, 0}}}
+			if !reflect.DeepEqual(p.Tags, wantTags) {
+				t.Errorf("%v: page %d: tags: got %v, want %v", enc, i, p.Tags, wantTags)
+			}
+		}
+
+		// A loop back to the second page ends the pages.
+		setNextIFD(b, enc, o3, o2)
+		if pages, err := DecodeAll(bytes.NewReader(b)); err != nil || len(pages) != 3 {
+			t.Errorf("%v: loop: got %d pages, %v, want 3 pages", enc, len(pages), err)
+		}
+
+		// An undecodable page is an error.
+		b, o4 := appendThumbnailTestIFD(b, enc, thumbnailTestIFD{w: 5, h: 1, rowsPerStrip: 1, bpp: 8, pix: constPix(0), extra: map[uint16]interface{}{
+			tCompression: uint16(cJPEGOld),
+		}})
+		enc.PutUint32(b[4:8], o0)
+		setNextIFD(b, enc, o3, o4)
+		if _, err := DecodeAll(bytes.NewReader(b)); err == nil {
+			t.Errorf("%v: undecodable page: got nil error", enc)
+		}
+	}
+}
+
+func TestDecodeAllTestdata(t *testing.T) {
+	for _, filename := range []string{"bw-deflate.tiff", "video-001.tiff", "video-001-tile-64x64.tiff"} {
+		b, err := os.ReadFile(testdataDir + filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pages, err := DecodeAll(bytes.NewReader(b))
+		if err != nil {
+			t.Fatalf("%s: %v", filename, err)
+		}
+		if len(pages) != 1 {
+			t.Fatalf("%s: got %d pages, want 1", filename, len(pages))
+		}
+		m, err := Decode(bytes.NewReader(b))
+		if err != nil {
+			t.Fatalf("%s: Decode: %v", filename, err)
+		}
+		compare(t, pages[0].Image, m)
+	}
+}
+
+func TestEncoder(t *testing.T) {
+	const tPageNumber = 297
+	pageNumber := func(i uint16) []Tag {
+		return []Tag{{ID: tPageNumber, Type: dtShort, Count: 2, Value: []byte{byte(i), 0, 3, 0}}}
+	}
+	gray, err := openImage("video-001-gray.tiff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rgba, err := openImage("video-001.tiff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The bilevel page is 13 pixels wide, so that its pixel data has an odd
+	// length.
+	bilevel := image.NewGray(image.Rect(0, 0, 13, 5))
+	for i := range bilevel.Pix {
+		bilevel.Pix[i] = uint8(i%2) * 0xff
+	}
+
+	pages := []struct {
+		m   image.Image
+		opt *Options
+	}{
+		{gray, &Options{ExtraTags: pageNumber(0)}},
+		{bilevel, &Options{Bilevel: true, ExtraTags: pageNumber(1)}},
+		{rgba, &Options{Compression: Deflate, Predictor: true, ExtraTags: pageNumber(2)}},
+	}
+	// Encode the pages in turn, with the compressed page both last and in the
+	// middle.
+	for _, order := range [][]int{{0, 1, 2}, {2, 1, 0}} {
+		var buf bytes.Buffer
+		e := NewEncoder(&buf)
+		for _, i := range order {
+			if err := e.Encode(pages[i].m, pages[i].opt); err != nil {
+				t.Fatalf("order %v: Encode: %v", order, err)
+			}
+		}
+		if err := e.Close(); err != nil {
+			t.Fatalf("order %v: Close: %v", order, err)
+		}
+
+		got, err := DecodeAll(bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			t.Fatalf("order %v: DecodeAll: %v", order, err)
+		}
+		if len(got) != len(order) {
+			t.Fatalf("order %v: got %d pages, want %d", order, len(got), len(order))
+		}
+		for j, i := range order {
+			compare(t, got[j].Image, pages[i].m)
+			if !reflect.DeepEqual(got[j].Tags, pages[i].opt.ExtraTags) {
+				t.Errorf("order %v: page %d: tags: got %v, want %v", order, j, got[j].Tags, pages[i].opt.ExtraTags)
+			}
+		}
+
+		// Decode returns the first page.
+		first, err := Decode(bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			t.Fatalf("order %v: Decode: %v", order, err)
+		}
+		compare(t, first, pages[order[0]].m)
+	}
+}
+
+func TestEncoderErrors(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Close(); err == nil {
+		t.Error("Close without pages: got nil error")
+	}
+
+	e := NewEncoder(&buf)
+	m := image.NewGray(image.Rect(0, 0, 1, 1))
+	if err := e.Encode(m, &Options{Compression: LZW}); err == nil {
+		t.Error("Encode(LZW): got nil error")
+	}
+	// The failed page does not stop other pages from being encoded.
+	if err := e.Encode(m, nil); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if err := e.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := e.Encode(m, nil); err == nil {
+		t.Error("Encode after Close: got nil error")
+	}
+	if err := e.Close(); err == nil {
+		t.Error("Close after Close: got nil error")
+	}
+	if pages, err := DecodeAll(bytes.NewReader(buf.Bytes())); err != nil || len(pages) != 1 {
+		t.Errorf("DecodeAll: got %d pages, %v, want 1 page", len(pages), err)
+	}
+}
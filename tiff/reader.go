@@ -16,6 +16,7 @@ import (
 	"image/color"
 	"io"
 	"math"
+	"math/bits"
 
 	"golang.org/x/image/ccitt"
 	"golang.org/x/image/tiff/lzw"
@@ -36,11 +37,48 @@ func (e UnsupportedError) Error() string {
 	return "tiff: unsupported feature: " + string(e)
 }
 
+// The errors that Decode and DecodeConfig return for invalid or unsupported
+// input wrap one of these, often with more detail, so that callers can check
+// for a specific problem with errors.Is, or for any invalid or any
+// unsupported input with errors.As and a FormatError or UnsupportedError.
 var (
-	errNoPixels          = FormatError("not enough pixel data")
-	errInvalidColorIndex = FormatError("invalid color index")
+	ErrMalformedHeader   = FormatError("malformed header")
+	ErrBadIFDEntry       = FormatError("bad IFD entry")
+	ErrIFDTooLarge       = FormatError("IFD data too large")
+	ErrUnsortedTags      = FormatError("tags are not sorted in ascending order")
+	ErrBadColorMap       = FormatError("bad ColorMap")
+	ErrBadBitsPerSample  = FormatError("bad BitsPerSample")
+	ErrBadExtraSamples   = FormatError("bad ExtraSamples")
+	ErrBadTileSize       = FormatError("tile size is too small")
+	ErrBadStripCount     = FormatError("wrong number of strip or tile offsets or byte counts")
+	ErrNoPixels          = FormatError("not enough pixel data")
+	ErrInvalidColorIndex = FormatError("invalid color index")
+	ErrBadJPEG           = FormatError("bad JPEG data")
+	ErrBadSubsampling    = FormatError("bad YCbCrSubSampling")
+
+	ErrUnsupportedDataType      = UnsupportedError("IFD entry datatype")
+	ErrUnsupportedBitsPerSample = UnsupportedError("BitsPerSample")
+	ErrUnsupportedSampleFormat  = UnsupportedError("sample format")
+	ErrUnsupportedColorModel    = UnsupportedError("color model")
+	ErrUnsupportedExtraSamples  = UnsupportedError("extra samples")
+	ErrUnsupportedPredictor     = UnsupportedError("predictor")
+	ErrUnsupportedCompression   = UnsupportedError("compression")
 )
 
+// detailError adds detail to one of the Err values.
+type detailError struct {
+	err    error
+	detail string
+}
+
+func (e *detailError) Error() string { return e.err.Error() + ": " + e.detail }
+func (e *detailError) Unwrap() error { return e.err }
+
+// errorf returns an error that wraps err with the formatted detail.
+func errorf(err error, format string, a ...interface{}) error {
+	return &detailError{err, fmt.Sprintf(format, a...)}
+}
+
 const maxChunkSize = 10 << 20 // 10M
 
 // safeReadAt is a verbatim copy of internal/saferio.ReadDataAt from the
@@ -93,8 +131,24 @@ type decoder struct {
 	config    image.Config
 	mode      imageMode
 	bpp       uint
+	spp       int // Samples per pixel.
 	features  map[int][]uint
 	palette   []color.Color
+	// jpegTables is the JPEGTables tag's abbreviated JPEG stream, if any,
+	// which holds the tables that the JPEG data of the strips or tiles use.
+	jpegTables []byte
+	// inkNames is the InkNames tag's NUL-terminated names of the inks of a
+	// separated image, if any.
+	inkNames []byte
+	// subsampleX and subsampleY are how many luma samples there are for
+	// each chroma sample of a YCbCr image, horizontally and vertically.
+	subsampleX, subsampleY int
+	// xRes and yRes are the XResolution and YResolution tags' values, or
+	// zero if the tags are missing or invalid.
+	xRes, yRes float64
+	// sampleMax, if non-zero, is the sample value that is scaled to the
+	// largest value for d.bpp, as per DecodeOptions.
+	sampleMax uint32
 
 	buf   []byte
 	off   int    // Current offset in buf.
@@ -117,17 +171,17 @@ func (d *decoder) firstVal(tag int) uint {
 func (d *decoder) ifdUint(p []byte) (u []uint, err error) {
 	var raw []byte
 	if len(p) < ifdLen {
-		return nil, FormatError("bad IFD entry")
+		return nil, ErrBadIFDEntry
 	}
 
 	datatype := d.byteOrder.Uint16(p[2:4])
 	if dt := int(datatype); dt <= 0 || dt >= len(lengths) {
-		return nil, UnsupportedError("IFD entry datatype")
+		return nil, errorf(ErrUnsupportedDataType, "%d", dt)
 	}
 
 	count := d.byteOrder.Uint32(p[4:8])
 	if count > math.MaxInt32/lengths[datatype] {
-		return nil, FormatError("IFD data too large")
+		return nil, errorf(ErrIFDTooLarge, "%d values of tag %d", count, d.byteOrder.Uint16(p[0:2]))
 	}
 	if datalen := lengths[datatype] * count; datalen > 4 {
 		// The IFD contains a pointer to the real value.
@@ -154,11 +208,48 @@ func (d *decoder) ifdUint(p []byte) (u []uint, err error) {
 			u[i] = uint(d.byteOrder.Uint32(raw[4*i : 4*(i+1)]))
 		}
 	default:
-		return nil, UnsupportedError("data type")
+		return nil, errorf(ErrUnsupportedDataType, "%d for tag %d", datatype, d.byteOrder.Uint16(p[0:2]))
 	}
 	return u, nil
 }
 
+// ifdBytes returns the data of the IFD entry in p, which must be of the Byte,
+// ASCII or Undefined type.
+func (d *decoder) ifdBytes(p []byte) ([]byte, error) {
+	if len(p) < ifdLen {
+		return nil, ErrBadIFDEntry
+	}
+	datatype := d.byteOrder.Uint16(p[2:4])
+	if datatype != dtByte && datatype != dtASCII && datatype != dtUndefined {
+		return nil, errorf(ErrUnsupportedDataType, "%d for tag %d", datatype, d.byteOrder.Uint16(p[0:2]))
+	}
+	count := d.byteOrder.Uint32(p[4:8])
+	if count > math.MaxInt32 {
+		return nil, errorf(ErrIFDTooLarge, "%d values of tag %d", count, d.byteOrder.Uint16(p[0:2]))
+	}
+	if count <= 4 {
+		return append([]byte(nil), p[8:8+count]...), nil
+	}
+	return safeReadAt(d.r, uint64(count), int64(d.byteOrder.Uint32(p[8:12])))
+}
+
+// ifdRational decodes the IFD entry in p, which must be of the Rational type,
+// and returns its first value, and whether it is a valid positive number.
+func (d *decoder) ifdRational(p []byte) (float64, bool) {
+	if len(p) < ifdLen || d.byteOrder.Uint16(p[2:4]) != dtRational || d.byteOrder.Uint32(p[4:8]) == 0 {
+		return 0, false
+	}
+	var raw [8]byte
+	if _, err := d.r.ReadAt(raw[:], int64(d.byteOrder.Uint32(p[8:12]))); err != nil {
+		return 0, false
+	}
+	num, den := d.byteOrder.Uint32(raw[0:4]), d.byteOrder.Uint32(raw[4:8])
+	if num == 0 || den == 0 {
+		return 0, false
+	}
+	return float64(num) / float64(den), true
+}
+
 // parseIFD decides whether the IFD entry in p is "interesting" and
 // stows away the data in the decoder. It returns the tag number of the
 // entry and an error, if any.
@@ -181,12 +272,35 @@ func (d *decoder) parseIFD(p []byte) (int, error) {
 		tImageWidth,
 		tFillOrder,
 		tT4Options,
-		tT6Options:
+		tT6Options,
+		tInkSet,
+		tYCbCrSubSampling,
+		tMaxSampleValue:
 		val, err := d.ifdUint(p)
 		if err != nil {
 			return 0, err
 		}
 		d.features[int(tag)] = val
+	case tNewSubfileType, tSubIFDs, tResolutionUnit:
+		// These are only used to find thumbnails, or as metadata, so
+		// invalid values are ignored instead of failing to decode the
+		// image.
+		if tag == tSubIFDs && d.byteOrder.Uint16(p[2:4]) == dtIFD {
+			q := make([]byte, ifdLen)
+			copy(q, p)
+			d.byteOrder.PutUint16(q[2:4], dtLong)
+			p = q
+		}
+		if val, err := d.ifdUint(p); err == nil {
+			d.features[int(tag)] = val
+		}
+	case tXResolution, tYResolution:
+		// As for ResolutionUnit, invalid values are ignored.
+		if v, ok := d.ifdRational(p); ok && tag == tXResolution {
+			d.xRes = v
+		} else if ok {
+			d.yRes = v
+		}
 	case tColorMap:
 		val, err := d.ifdUint(p)
 		if err != nil {
@@ -194,7 +308,7 @@ func (d *decoder) parseIFD(p []byte) (int, error) {
 		}
 		numcolors := len(val) / 3
 		if len(val)%3 != 0 || numcolors <= 0 || numcolors > 256 {
-			return 0, FormatError("bad ColorMap length")
+			return 0, errorf(ErrBadColorMap, "%d values", len(val))
 		}
 		d.palette = make([]color.Color, numcolors)
 		for i := 0; i < numcolors; i++ {
@@ -205,6 +319,18 @@ func (d *decoder) parseIFD(p []byte) (int, error) {
 				0xffff,
 			}
 		}
+	case tJPEGTables:
+		val, err := d.ifdBytes(p)
+		if err != nil {
+			return 0, err
+		}
+		d.jpegTables = val
+	case tInkNames:
+		val, err := d.ifdBytes(p)
+		if err != nil {
+			return 0, err
+		}
+		d.inkNames = val
 	case tSampleFormat:
 		// Page 27 of the spec: If the SampleFormat is present and
 		// the value is not 1 [= unsigned integer data], a Baseline
@@ -216,7 +342,7 @@ func (d *decoder) parseIFD(p []byte) (int, error) {
 		}
 		for _, v := range val {
 			if v != 1 {
-				return 0, UnsupportedError("sample format")
+				return 0, errorf(ErrUnsupportedSampleFormat, "%d", v)
 			}
 		}
 	}
@@ -247,6 +373,104 @@ func (d *decoder) flushBits() {
 	d.nbits = 0
 }
 
+// extraSamplesAlpha returns the meaning of the first extra sample of an image
+// with n color samples per pixel: esUnspecified if there is no extra sample or
+// if its meaning is unspecified, and esAssociatedAlpha or esUnassociatedAlpha
+// if it is an alpha channel.
+func (d *decoder) extraSamplesAlpha(n int) (uint, error) {
+	for _, b := range d.features[tBitsPerSample] {
+		if b != d.bpp {
+			return 0, errorf(ErrUnsupportedBitsPerSample, "different values %v", d.features[tBitsPerSample])
+		}
+	}
+	if d.spp == n {
+		return esUnspecified, nil
+	}
+	switch v := d.firstVal(tExtraSamples); v {
+	case esUnspecified, esAssociatedAlpha, esUnassociatedAlpha:
+		return v, nil
+	}
+	return 0, errorf(ErrBadExtraSamples, "%d", d.firstVal(tExtraSamples))
+}
+
+// setYCbCr sets d's image mode for a YCbCr image that is not
+// JPEG-compressed. Such images have three 8-bit samples, with subsampled
+// chroma as per the YCbCrSubSampling tag, which is stored in data units of
+// subsampleX×subsampleY luma samples followed by one Cb and one Cr sample.
+func (d *decoder) setYCbCr() error {
+	if d.bpp != 8 {
+		return errorf(ErrUnsupportedBitsPerSample, "%d for YCbCr", d.bpp)
+	}
+	if d.spp != 3 {
+		return errorf(ErrUnsupportedExtraSamples, "%d samples for YCbCr", d.spp)
+	}
+	if _, err := d.extraSamplesAlpha(3); err != nil {
+		return err
+	}
+	// The default subsampling is 2×2, as per page 92 of the spec.
+	d.subsampleX, d.subsampleY = 2, 2
+	if sub, ok := d.features[tYCbCrSubSampling]; ok {
+		if len(sub) != 2 {
+			return errorf(ErrBadSubsampling, "%v", sub)
+		}
+		d.subsampleX, d.subsampleY = int(sub[0]), int(sub[1])
+	}
+	valid := func(v int) bool { return v == 1 || v == 2 || v == 4 }
+	if !valid(d.subsampleX) || !valid(d.subsampleY) || d.subsampleY > d.subsampleX {
+		return errorf(ErrBadSubsampling, "%dx%d", d.subsampleX, d.subsampleY)
+	}
+	if _, ok := d.subsampleRatio(); !ok {
+		return errorf(ErrUnsupportedColorModel, "YCbCr with %dx%d subsampling", d.subsampleX, d.subsampleY)
+	}
+	d.mode = mYCbCr
+	d.config.ColorModel = color.YCbCrModel
+	return nil
+}
+
+// subsampleRatio returns the image.YCbCr subsample ratio for d's YCbCr
+// subsampling, and whether there is one.
+func (d *decoder) subsampleRatio() (image.YCbCrSubsampleRatio, bool) {
+	switch [2]int{d.subsampleX, d.subsampleY} {
+	case [2]int{1, 1}:
+		return image.YCbCrSubsampleRatio444, true
+	case [2]int{2, 1}:
+		return image.YCbCrSubsampleRatio422, true
+	case [2]int{2, 2}:
+		return image.YCbCrSubsampleRatio420, true
+	case [2]int{4, 1}:
+		return image.YCbCrSubsampleRatio411, true
+	case [2]int{4, 2}:
+		return image.YCbCrSubsampleRatio410, true
+	}
+	return 0, false
+}
+
+// ycbcrUnits returns the number of YCbCr data units across and down w×h
+// pixels.
+func (d *decoder) ycbcrUnits(w, h int) (across, down int64) {
+	return int64((w + d.subsampleX - 1) / d.subsampleX), int64((h + d.subsampleY - 1) / d.subsampleY)
+}
+
+// ycbcrUnitSize returns the number of bytes in a YCbCr data unit.
+func (d *decoder) ycbcrUnitSize() int64 {
+	return int64(d.subsampleX*d.subsampleY + 2)
+}
+
+// cmykInkNames returns whether the InkNames tag's value names the cyan,
+// magenta, yellow and black inks, in that order.
+func cmykInkNames(names []byte) bool {
+	n := bytes.Split(bytes.TrimRight(names, "\x00"), []byte{0})
+	if len(n) != 4 {
+		return false
+	}
+	for i, want := range []string{"cyan", "magenta", "yellow", "black"} {
+		if !bytes.EqualFold(n[i], []byte(want)) {
+			return false
+		}
+	}
+	return true
+}
+
 // minInt returns the smaller of x or y.
 func minInt(a, b int) int {
 	if a <= b {
@@ -272,7 +496,7 @@ func (d *decoder) decode(dst image.Image, xmin, ymin, xmax, ymax int) error {
 				off += n
 				for x := 0; x < (xmax-xmin-1)*n; x += 2 {
 					if off+2 > len(d.buf) {
-						return errNoPixels
+						return ErrNoPixels
 					}
 					v0 := d.byteOrder.Uint16(d.buf[off-n : off-n+2])
 					v1 := d.byteOrder.Uint16(d.buf[off : off+2])
@@ -287,17 +511,21 @@ func (d *decoder) decode(dst image.Image, xmin, ymin, xmax, ymax int) error {
 				off += n
 				for x := 0; x < (xmax-xmin-1)*n; x++ {
 					if off >= len(d.buf) {
-						return errNoPixels
+						return ErrNoPixels
 					}
 					d.buf[off] += d.buf[off-n]
 					off++
 				}
 			}
-		case 1:
-			return UnsupportedError("horizontal predictor with 1 BitsPerSample")
+		case 1, 2, 4:
+			return errorf(ErrUnsupportedPredictor, "horizontal predictor with %d BitsPerSample", d.bpp)
 		}
 	}
 
+	if d.sampleMax != 0 {
+		d.scaleSamples()
+	}
+
 	rMaxX := minInt(xmax, dst.Bounds().Max.X)
 	rMaxY := minInt(ymax, dst.Bounds().Max.Y)
 	switch d.mode {
@@ -307,28 +535,36 @@ func (d *decoder) decode(dst image.Image, xmin, ymin, xmax, ymax int) error {
 			for y := ymin; y < rMaxY; y++ {
 				for x := xmin; x < rMaxX; x++ {
 					if d.off+2 > len(d.buf) {
-						return errNoPixels
+						return ErrNoPixels
 					}
 					v := d.byteOrder.Uint16(d.buf[d.off : d.off+2])
-					d.off += 2
+					d.off += 2 * d.spp
 					if d.mode == mGrayInvert {
 						v = 0xffff - v
 					}
 					img.SetGray16(x, y, color.Gray16{v})
 				}
 				if rMaxX == img.Bounds().Max.X {
-					d.off += 2 * (xmax - img.Bounds().Max.X)
+					d.off += 2 * d.spp * (xmax - img.Bounds().Max.X)
 				}
 			}
 		} else {
 			img := dst.(*image.Gray)
 			max := uint32((1 << d.bpp) - 1)
+			rowBytes := ((xmax-xmin)*d.spp*int(d.bpp) + 7) / 8
 			for y := ymin; y < rMaxY; y++ {
+				// Rows start on a byte boundary. Seeking to the start of
+				// each row also skips any tile padding past rMaxX.
+				d.off = (y - ymin) * rowBytes
+				d.flushBits()
 				for x := xmin; x < rMaxX; x++ {
 					v, ok := d.readBits(d.bpp)
 					if !ok {
-						return errNoPixels
+						return ErrNoPixels
 					}
+					// Extra samples are only allowed with 8 bits per
+					// sample, so they can be skipped a byte at a time.
+					d.off += d.spp - 1
 					v = v * 0xff / max
 					if d.mode == mGrayInvert {
 						v = 0xff - v
@@ -341,32 +577,81 @@ func (d *decoder) decode(dst image.Image, xmin, ymin, xmax, ymax int) error {
 	case mPaletted:
 		img := dst.(*image.Paletted)
 		pLen := len(d.palette)
+		rowBytes := ((xmax-xmin)*int(d.bpp) + 7) / 8
 		for y := ymin; y < rMaxY; y++ {
+			d.off = (y - ymin) * rowBytes
+			d.flushBits()
 			for x := xmin; x < rMaxX; x++ {
 				v, ok := d.readBits(d.bpp)
 				if !ok {
-					return errNoPixels
+					return ErrNoPixels
 				}
 				idx := uint8(v)
 				if int(idx) >= pLen {
-					return errInvalidColorIndex
+					return ErrInvalidColorIndex
 				}
 				img.SetColorIndex(x, y, idx)
 			}
 			d.flushBits()
 		}
+	case mGrayA, mGrayNA:
+		if d.bpp == 16 {
+			for y := ymin; y < rMaxY; y++ {
+				d.off = (y - ymin) * (xmax - xmin) * 2 * d.spp
+				for x := xmin; x < rMaxX; x++ {
+					if d.off+4 > len(d.buf) {
+						return ErrNoPixels
+					}
+					v := d.byteOrder.Uint16(d.buf[d.off+0 : d.off+2])
+					a := d.byteOrder.Uint16(d.buf[d.off+2 : d.off+4])
+					d.off += 2 * d.spp
+					if d.mode == mGrayA {
+						dst.(*image.RGBA64).SetRGBA64(x, y, color.RGBA64{v, v, v, a})
+					} else {
+						dst.(*image.NRGBA64).SetNRGBA64(x, y, color.NRGBA64{v, v, v, a})
+					}
+				}
+			}
+		} else {
+			var pix []uint8
+			var stride int
+			var r image.Rectangle
+			switch img := dst.(type) {
+			case *image.RGBA:
+				pix, stride, r = img.Pix, img.Stride, img.Rect
+			case *image.NRGBA:
+				pix, stride, r = img.Pix, img.Stride, img.Rect
+			}
+			for y := ymin; y < rMaxY; y++ {
+				i := (y-r.Min.Y)*stride + (xmin-r.Min.X)*4
+				off := (y - ymin) * (xmax - xmin) * d.spp
+				for x := xmin; x < rMaxX; x, i, off = x+1, i+4, off+d.spp {
+					if off+2 > len(d.buf) {
+						return ErrNoPixels
+					}
+					v, a := d.buf[off+0], d.buf[off+1]
+					pix[i+0] = v
+					pix[i+1] = v
+					pix[i+2] = v
+					pix[i+3] = a
+				}
+			}
+		}
 	case mRGB:
 		if d.bpp == 16 {
 			img := dst.(*image.RGBA64)
 			for y := ymin; y < rMaxY; y++ {
+				// Seeking to the start of each row skips any tile padding
+				// past rMaxX.
+				d.off = (y - ymin) * (xmax - xmin) * 2 * d.spp
 				for x := xmin; x < rMaxX; x++ {
 					if d.off+6 > len(d.buf) {
-						return errNoPixels
+						return ErrNoPixels
 					}
 					r := d.byteOrder.Uint16(d.buf[d.off+0 : d.off+2])
 					g := d.byteOrder.Uint16(d.buf[d.off+2 : d.off+4])
 					b := d.byteOrder.Uint16(d.buf[d.off+4 : d.off+6])
-					d.off += 6
+					d.off += 2 * d.spp
 					img.SetRGBA64(x, y, color.RGBA64{r, g, b, 0xffff})
 				}
 			}
@@ -375,16 +660,16 @@ func (d *decoder) decode(dst image.Image, xmin, ymin, xmax, ymax int) error {
 			for y := ymin; y < rMaxY; y++ {
 				min := img.PixOffset(xmin, y)
 				max := img.PixOffset(rMaxX, y)
-				off := (y - ymin) * (xmax - xmin) * 3
+				off := (y - ymin) * (xmax - xmin) * d.spp
 				for i := min; i < max; i += 4 {
 					if off+3 > len(d.buf) {
-						return errNoPixels
+						return ErrNoPixels
 					}
 					img.Pix[i+0] = d.buf[off+0]
 					img.Pix[i+1] = d.buf[off+1]
 					img.Pix[i+2] = d.buf[off+2]
 					img.Pix[i+3] = 0xff
-					off += 3
+					off += d.spp
 				}
 			}
 		}
@@ -392,15 +677,16 @@ func (d *decoder) decode(dst image.Image, xmin, ymin, xmax, ymax int) error {
 		if d.bpp == 16 {
 			img := dst.(*image.NRGBA64)
 			for y := ymin; y < rMaxY; y++ {
+				d.off = (y - ymin) * (xmax - xmin) * 2 * d.spp
 				for x := xmin; x < rMaxX; x++ {
 					if d.off+8 > len(d.buf) {
-						return errNoPixels
+						return ErrNoPixels
 					}
 					r := d.byteOrder.Uint16(d.buf[d.off+0 : d.off+2])
 					g := d.byteOrder.Uint16(d.buf[d.off+2 : d.off+4])
 					b := d.byteOrder.Uint16(d.buf[d.off+4 : d.off+6])
 					a := d.byteOrder.Uint16(d.buf[d.off+6 : d.off+8])
-					d.off += 8
+					d.off += 2 * d.spp
 					img.SetNRGBA64(x, y, color.NRGBA64{r, g, b, a})
 				}
 			}
@@ -409,26 +695,33 @@ func (d *decoder) decode(dst image.Image, xmin, ymin, xmax, ymax int) error {
 			for y := ymin; y < rMaxY; y++ {
 				min := img.PixOffset(xmin, y)
 				max := img.PixOffset(rMaxX, y)
-				i0, i1 := (y-ymin)*(xmax-xmin)*4, (y-ymin+1)*(xmax-xmin)*4
+				i0, i1 := (y-ymin)*(xmax-xmin)*d.spp, (y-ymin+1)*(xmax-xmin)*d.spp
 				if i1 > len(d.buf) {
-					return errNoPixels
+					return ErrNoPixels
+				}
+				if d.spp == 4 {
+					copy(img.Pix[min:max], d.buf[i0:i1])
+					continue
+				}
+				for i, j := min, i0; i < max; i, j = i+4, j+d.spp {
+					copy(img.Pix[i:i+4], d.buf[j:j+4])
 				}
-				copy(img.Pix[min:max], d.buf[i0:i1])
 			}
 		}
 	case mRGBA:
 		if d.bpp == 16 {
 			img := dst.(*image.RGBA64)
 			for y := ymin; y < rMaxY; y++ {
+				d.off = (y - ymin) * (xmax - xmin) * 2 * d.spp
 				for x := xmin; x < rMaxX; x++ {
 					if d.off+8 > len(d.buf) {
-						return errNoPixels
+						return ErrNoPixels
 					}
 					r := d.byteOrder.Uint16(d.buf[d.off+0 : d.off+2])
 					g := d.byteOrder.Uint16(d.buf[d.off+2 : d.off+4])
 					b := d.byteOrder.Uint16(d.buf[d.off+4 : d.off+6])
 					a := d.byteOrder.Uint16(d.buf[d.off+6 : d.off+8])
-					d.off += 8
+					d.off += 2 * d.spp
 					img.SetRGBA64(x, y, color.RGBA64{r, g, b, a})
 				}
 			}
@@ -437,11 +730,76 @@ func (d *decoder) decode(dst image.Image, xmin, ymin, xmax, ymax int) error {
 			for y := ymin; y < rMaxY; y++ {
 				min := img.PixOffset(xmin, y)
 				max := img.PixOffset(rMaxX, y)
-				i0, i1 := (y-ymin)*(xmax-xmin)*4, (y-ymin+1)*(xmax-xmin)*4
+				i0, i1 := (y-ymin)*(xmax-xmin)*d.spp, (y-ymin+1)*(xmax-xmin)*d.spp
 				if i1 > len(d.buf) {
-					return errNoPixels
+					return ErrNoPixels
+				}
+				if d.spp == 4 {
+					copy(img.Pix[min:max], d.buf[i0:i1])
+					continue
+				}
+				for i, j := min, i0; i < max; i, j = i+4, j+d.spp {
+					copy(img.Pix[i:i+4], d.buf[j:j+4])
+				}
+			}
+		}
+	case mCMYK:
+		img := dst.(*image.CMYK)
+		for y := ymin; y < rMaxY; y++ {
+			min := img.PixOffset(xmin, y)
+			max := img.PixOffset(rMaxX, y)
+			off := (y - ymin) * (xmax - xmin) * d.spp
+			for i := min; i < max; i, off = i+4, off+d.spp {
+				if off+4 > len(d.buf) {
+					return ErrNoPixels
+				}
+				copy(img.Pix[i:i+4], d.buf[off:off+4])
+			}
+		}
+	case mYCbCr:
+		img := dst.(*image.YCbCr)
+		sx, sy := d.subsampleX, d.subsampleY
+		n := sx*sy + 2 // Bytes per data unit.
+		unitsPerRow := (xmax - xmin + sx - 1) / sx
+		for y0 := ymin; y0 < rMaxY; y0 += sy {
+			off := (y0 - ymin) / sy * unitsPerRow * n
+			for x0 := xmin; x0 < rMaxX; x0, off = x0+sx, off+n {
+				if off+n > len(d.buf) {
+					return ErrNoPixels
+				}
+				// The luma samples of a data unit that are past the
+				// right or bottom edge of the image are padding.
+				for j := 0; j < sy && y0+j < rMaxY; j++ {
+					for i := 0; i < sx && x0+i < rMaxX; i++ {
+						img.Y[img.YOffset(x0+i, y0+j)] = d.buf[off+j*sx+i]
+					}
+				}
+				c := img.COffset(x0, y0)
+				img.Cb[c] = d.buf[off+n-2]
+				img.Cr[c] = d.buf[off+n-1]
+			}
+		}
+	case mCIELab, mICCLab:
+		n := int(d.bpp/8) * d.spp // Bytes per pixel.
+		for y := ymin; y < rMaxY; y++ {
+			off := (y - ymin) * (xmax - xmin) * n
+			for x := xmin; x < rMaxX; x, off = x+1, off+n {
+				if off+n > len(d.buf) {
+					return ErrNoPixels
+				}
+				l, a, b := d.lab(d.buf[off : off+n])
+				switch img := dst.(type) {
+				case *image.Gray:
+					img.SetGray(x, y, color.Gray{uint8(labToGray(l)*0xff + 0.5)})
+				case *image.Gray16:
+					img.SetGray16(x, y, color.Gray16{uint16(labToGray(l)*0xffff + 0.5)})
+				case *image.RGBA:
+					r, g, b := labToRGB(l, a, b)
+					img.SetRGBA(x, y, color.RGBA{uint8(r*0xff + 0.5), uint8(g*0xff + 0.5), uint8(b*0xff + 0.5), 0xff})
+				case *image.RGBA64:
+					r, g, b := labToRGB(l, a, b)
+					img.SetRGBA64(x, y, color.RGBA64{uint16(r*0xffff + 0.5), uint16(g*0xffff + 0.5), uint16(b*0xffff + 0.5), 0xffff})
 				}
-				copy(img.Pix[min:max], d.buf[i0:i1])
 			}
 		}
 	}
@@ -449,35 +807,105 @@ func (d *decoder) decode(dst image.Image, xmin, ymin, xmax, ymax int) error {
 	return nil
 }
 
+// scaleSamples scales the 8- or 16-bit samples in d.buf so that d.sampleMax
+// becomes the largest value for d.bpp. Larger samples are clamped.
+func (d *decoder) scaleSamples() {
+	max := d.sampleMax
+	switch d.bpp {
+	case 8:
+		for i, v := range d.buf {
+			d.buf[i] = uint8((minUint32(uint32(v), max)*0xff + max/2) / max)
+		}
+	case 16:
+		for i := 0; i+2 <= len(d.buf); i += 2 {
+			v := uint32(d.byteOrder.Uint16(d.buf[i : i+2]))
+			d.byteOrder.PutUint16(d.buf[i:i+2], uint16((minUint32(v, max)*0xffff+max/2)/max))
+		}
+	}
+}
+
+func minUint32(a, b uint32) uint32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// setSampleMax sets d.sampleMax as per opts. Samples are only scaled for
+// images with 8 or 16 bits per sample that are not paletted, CIELab or YCbCr.
+func (d *decoder) setSampleMax(opts *DecodeOptions) {
+	d.sampleMax = 0
+	if opts == nil || (d.bpp != 8 && d.bpp != 16) {
+		return
+	}
+	switch d.mode {
+	case mPaletted, mCIELab, mICCLab, mYCbCr:
+		return
+	}
+	full := uint32(1)<<d.bpp - 1
+	max := uint32(0)
+	switch {
+	case opts.MaxSampleValue > 0:
+		max = uint32(minInt(opts.MaxSampleValue, int(full)))
+	case opts.SignificantBits > 0:
+		if opts.SignificantBits < int(d.bpp) {
+			max = uint32(1)<<uint(opts.SignificantBits) - 1
+		}
+	case opts.UseMaxSampleValue:
+		if v := d.firstVal(tMaxSampleValue); v > 0 && v < uint(full) {
+			max = uint32(v)
+		}
+	}
+	if max != full {
+		d.sampleMax = max
+	}
+}
+
 func newDecoder(r io.Reader) (*decoder, error) {
-	d := &decoder{
-		r:        newReaderAt(r),
-		features: make(map[int][]uint),
+	ra := newReaderAt(r)
+	byteOrder, ifdOffset, err := readHeader(ra)
+	if err != nil {
+		return nil, err
 	}
+	return newIFDDecoder(ra, byteOrder, ifdOffset)
+}
 
+// readHeader reads the header at the start of r, and returns the byte order
+// and the offset of the first IFD.
+func readHeader(r io.ReaderAt) (byteOrder binary.ByteOrder, ifdOffset int64, err error) {
 	p := make([]byte, 8)
-	if _, err := d.r.ReadAt(p, 0); err != nil {
+	if _, err := r.ReadAt(p, 0); err != nil {
 		if err == io.EOF {
 			err = io.ErrUnexpectedEOF
 		}
-		return nil, err
+		return nil, 0, err
 	}
 	switch string(p[0:4]) {
 	case leHeader:
-		d.byteOrder = binary.LittleEndian
+		byteOrder = binary.LittleEndian
 	case beHeader:
-		d.byteOrder = binary.BigEndian
+		byteOrder = binary.BigEndian
 	default:
-		return nil, FormatError("malformed header")
+		return nil, 0, ErrMalformedHeader
 	}
+	return byteOrder, int64(byteOrder.Uint32(p[4:8])), nil
+}
 
-	ifdOffset := int64(d.byteOrder.Uint32(p[4:8]))
+// newIFDDecoder returns a decoder for the image described by the IFD at
+// ifdOffset in r.
+func newIFDDecoder(r io.ReaderAt, byteOrder binary.ByteOrder, ifdOffset int64) (*decoder, error) {
+	d := &decoder{
+		r:         r,
+		byteOrder: byteOrder,
+		features:  make(map[int][]uint),
+	}
 
 	// The first two bytes contain the number of entries (12 bytes each).
-	if _, err := d.r.ReadAt(p[0:2], ifdOffset); err != nil {
+	p := make([]byte, 2)
+	if _, err := d.r.ReadAt(p, ifdOffset); err != nil {
 		return nil, err
 	}
-	numItems := int(d.byteOrder.Uint16(p[0:2]))
+	numItems := int(d.byteOrder.Uint16(p))
 
 	// All IFD entries are read in one chunk.
 	var err error
@@ -493,7 +921,7 @@ func newDecoder(r io.Reader) (*decoder, error) {
 			return nil, err
 		}
 		if tag <= prevTag {
-			return nil, FormatError("tags are not sorted in ascending order")
+			return nil, errorf(ErrUnsortedTags, "tag %d after tag %d", tag, prevTag)
 		}
 		prevTag = tag
 	}
@@ -506,28 +934,41 @@ func newDecoder(r io.Reader) (*decoder, error) {
 		d.features[tBitsPerSample] = []uint{1}
 	}
 	d.bpp = d.firstVal(tBitsPerSample)
+	d.spp = len(d.features[tBitsPerSample])
 	switch d.bpp {
 	case 0:
-		return nil, FormatError("BitsPerSample must not be 0")
-	case 1, 8, 16:
+		return nil, errorf(ErrBadBitsPerSample, "0")
+	case 1, 2, 4, 8, 16:
 		// Nothing to do, these are accepted by this implementation.
 	default:
-		return nil, UnsupportedError(fmt.Sprintf("BitsPerSample of %v", d.bpp))
+		return nil, errorf(ErrUnsupportedBitsPerSample, "%d", d.bpp)
 	}
 
 	// Determine the image mode.
 	switch d.firstVal(tPhotometricInterpretation) {
+	case pYCbCr:
+		// The JPEG decoder converts YCbCr to RGB, so JPEG-compressed images
+		// decode as RGB images do.
+		if d.firstVal(tCompression) != cJPEG {
+			if err := d.setYCbCr(); err != nil {
+				return nil, err
+			}
+			break
+		}
+		fallthrough
 	case pRGB:
 		if d.bpp == 16 {
 			for _, b := range d.features[tBitsPerSample] {
 				if b != 16 {
-					return nil, FormatError("wrong number of samples for 16bit RGB")
+					return nil, errorf(ErrBadBitsPerSample, "%v for 16-bit RGB", d.features[tBitsPerSample])
 				}
 			}
+		} else if d.bpp != 8 {
+			return nil, errorf(ErrUnsupportedBitsPerSample, "%d for RGB", d.bpp)
 		} else {
 			for _, b := range d.features[tBitsPerSample] {
 				if b != 8 {
-					return nil, FormatError("wrong number of samples for 8bit RGB")
+					return nil, errorf(ErrBadBitsPerSample, "%v for 8-bit RGB", d.features[tBitsPerSample])
 				}
 			}
 		}
@@ -535,62 +976,157 @@ func newDecoder(r io.Reader) (*decoder, error) {
 		// If there are more, ExtraSamples (p. 31-32 of the spec)
 		// gives their meaning (usually an alpha channel).
 		//
-		// This implementation does not support extra samples
-		// of an unspecified type.
-		switch len(d.features[tBitsPerSample]) {
-		case 3:
+		// Only the first extra sample is used, and only if it is
+		// associated (premultiplied) or unassociated (straight)
+		// alpha. Other extra samples are skipped.
+		if d.spp < 3 {
+			return nil, errorf(ErrBadBitsPerSample, "%d samples for RGB", d.spp)
+		}
+		alpha, err := d.extraSamplesAlpha(3)
+		if err != nil {
+			return nil, err
+		}
+		switch alpha {
+		case esUnspecified:
 			d.mode = mRGB
 			if d.bpp == 16 {
 				d.config.ColorModel = color.RGBA64Model
 			} else {
 				d.config.ColorModel = color.RGBAModel
 			}
-		case 4:
-			switch d.firstVal(tExtraSamples) {
-			case 1:
-				d.mode = mRGBA
-				if d.bpp == 16 {
-					d.config.ColorModel = color.RGBA64Model
-				} else {
-					d.config.ColorModel = color.RGBAModel
-				}
-			case 2:
-				d.mode = mNRGBA
-				if d.bpp == 16 {
-					d.config.ColorModel = color.NRGBA64Model
-				} else {
-					d.config.ColorModel = color.NRGBAModel
-				}
-			default:
-				return nil, FormatError("wrong number of samples for RGB")
+		case esAssociatedAlpha:
+			d.mode = mRGBA
+			if d.bpp == 16 {
+				d.config.ColorModel = color.RGBA64Model
+			} else {
+				d.config.ColorModel = color.RGBAModel
+			}
+		case esUnassociatedAlpha:
+			d.mode = mNRGBA
+			if d.bpp == 16 {
+				d.config.ColorModel = color.NRGBA64Model
+			} else {
+				d.config.ColorModel = color.NRGBAModel
 			}
-		default:
-			return nil, FormatError("wrong number of samples for RGB")
 		}
 	case pPaletted:
 		d.mode = mPaletted
 		d.config.ColorModel = color.Palette(d.palette)
-	case pWhiteIsZero:
-		d.mode = mGrayInvert
-		if d.bpp == 16 {
-			d.config.ColorModel = color.Gray16Model
-		} else {
-			d.config.ColorModel = color.GrayModel
+	case pCMYK:
+		// Separated images have CMYK inks unless InkSet says otherwise, but
+		// some writers that say otherwise name the CMYK inks in InkNames.
+		if d.firstVal(tInkSet) == inkSetNotCMYK && !cmykInkNames(d.inkNames) {
+			return nil, errorf(ErrUnsupportedColorModel, "separated image with InkNames %q", d.inkNames)
 		}
-	case pBlackIsZero:
-		d.mode = mGray
-		if d.bpp == 16 {
+		if d.bpp != 8 {
+			return nil, errorf(ErrUnsupportedBitsPerSample, "%d for CMYK", d.bpp)
+		}
+		if d.spp < 4 {
+			return nil, errorf(ErrBadBitsPerSample, "%d samples for CMYK", d.spp)
+		}
+		alpha, err := d.extraSamplesAlpha(4)
+		if err != nil {
+			return nil, err
+		}
+		if alpha != esUnspecified {
+			return nil, errorf(ErrUnsupportedColorModel, "CMYK with alpha")
+		}
+		d.mode = mCMYK
+		d.config.ColorModel = color.CMYKModel
+	case pWhiteIsZero, pBlackIsZero:
+		// As for RGB, extra samples beyond the first gray sample may hold
+		// an alpha channel.
+		if d.spp > 1 && d.bpp != 8 && d.bpp != 16 {
+			return nil, errorf(ErrUnsupportedBitsPerSample, "%d with ExtraSamples", d.bpp)
+		}
+		alpha, err := d.extraSamplesAlpha(1)
+		if err != nil {
+			return nil, err
+		}
+		switch alpha {
+		case esUnspecified:
+			d.mode = mGray
+			if d.firstVal(tPhotometricInterpretation) == pWhiteIsZero {
+				d.mode = mGrayInvert
+			}
+			if d.bpp == 16 {
+				d.config.ColorModel = color.Gray16Model
+			} else {
+				d.config.ColorModel = color.GrayModel
+			}
+		case esAssociatedAlpha:
+			if d.firstVal(tPhotometricInterpretation) == pWhiteIsZero {
+				return nil, errorf(ErrUnsupportedColorModel, "WhiteIsZero with alpha")
+			}
+			d.mode = mGrayA
+			if d.bpp == 16 {
+				d.config.ColorModel = color.RGBA64Model
+			} else {
+				d.config.ColorModel = color.RGBAModel
+			}
+		case esUnassociatedAlpha:
+			if d.firstVal(tPhotometricInterpretation) == pWhiteIsZero {
+				return nil, errorf(ErrUnsupportedColorModel, "WhiteIsZero with alpha")
+			}
+			d.mode = mGrayNA
+			if d.bpp == 16 {
+				d.config.ColorModel = color.NRGBA64Model
+			} else {
+				d.config.ColorModel = color.NRGBAModel
+			}
+		}
+	case pCIELab, pICCLab:
+		// The L* and any a* and b* samples are converted to sRGB gray or
+		// RGB. Extra samples are skipped, and alpha is not supported.
+		if d.bpp != 8 && d.bpp != 16 {
+			return nil, errorf(ErrUnsupportedBitsPerSample, "%d for CIELab", d.bpp)
+		}
+		alpha, err := d.extraSamplesAlpha(labColorSamples(d.spp))
+		if err != nil {
+			return nil, err
+		}
+		if alpha != esUnspecified {
+			return nil, errorf(ErrUnsupportedColorModel, "CIELab with alpha")
+		}
+		d.mode = mCIELab
+		if d.firstVal(tPhotometricInterpretation) == pICCLab {
+			d.mode = mICCLab
+		}
+		switch {
+		case labColorSamples(d.spp) == 1 && d.bpp == 16:
 			d.config.ColorModel = color.Gray16Model
-		} else {
+		case labColorSamples(d.spp) == 1:
 			d.config.ColorModel = color.GrayModel
+		case d.bpp == 16:
+			d.config.ColorModel = color.RGBA64Model
+		default:
+			d.config.ColorModel = color.RGBAModel
 		}
 	default:
-		return nil, UnsupportedError("color model")
+		return nil, errorf(ErrUnsupportedColorModel, "PhotometricInterpretation %d", d.firstVal(tPhotometricInterpretation))
 	}
-	if d.firstVal(tPhotometricInterpretation) != pRGB {
-		if len(d.features[tBitsPerSample]) != 1 {
-			return nil, UnsupportedError("extra samples")
+	if d.mode == mPaletted {
+		if d.spp != 1 {
+			return nil, errorf(ErrUnsupportedExtraSamples, "%d samples for a paletted image", d.spp)
 		}
+		if d.palette == nil {
+			return nil, errorf(ErrBadColorMap, "missing for a paletted image")
+		}
+	}
+	// The ExtraSamples values, if any, describe the samples after the color
+	// samples.
+	colorSamples := 1
+	if d.mode == mRGB || d.mode == mRGBA || d.mode == mNRGBA {
+		colorSamples = 3
+	} else if d.mode == mCIELab || d.mode == mICCLab {
+		colorSamples = labColorSamples(d.spp)
+	} else if d.mode == mCMYK {
+		colorSamples = 4
+	} else if d.mode == mYCbCr {
+		colorSamples = 3
+	}
+	if n := len(d.features[tExtraSamples]); n != 0 && n != d.spp-colorSamples {
+		return nil, errorf(ErrBadExtraSamples, "%d values for %d samples per pixel", n, d.spp)
 	}
 
 	return d, nil
@@ -606,6 +1142,42 @@ func DecodeConfig(r io.Reader) (image.Config, error) {
 	return d.config, nil
 }
 
+// DecodeResolution returns the resolution of a TIFF image, as per its
+// XResolution, YResolution and ResolutionUnit tags, without decoding the
+// entire image. x and y are the number of pixels per unit, horizontally and
+// vertically, such as 300 for a scan at 300 dpi, and they are zero if the
+// image does not record its resolution. They can be given to Encode in
+// Options, so that the image is encoded with the same resolution.
+//
+// It returns the same errors as DecodeConfig.
+func DecodeResolution(r io.Reader) (x, y float64, unit ResolutionUnit, err error) {
+	d, err := newDecoder(r)
+	if err != nil {
+		return 0, 0, Inch, err
+	}
+	x, y, unit = d.resolution()
+	return x, y, unit, nil
+}
+
+// resolution returns the resolution of d's image, as for DecodeResolution.
+// If only one of the XResolution and YResolution tags is valid, it is used
+// for both.
+func (d *decoder) resolution() (x, y float64, unit ResolutionUnit) {
+	x, y = d.xRes, d.yRes
+	if x == 0 {
+		x = y
+	} else if y == 0 {
+		y = x
+	}
+	switch d.firstVal(tResolutionUnit) {
+	case resPerCM:
+		unit = Centimeter
+	case resNone:
+		unit = NoUnit
+	}
+	return x, y, unit
+}
+
 func ccittFillOrder(tiffFillOrder uint) ccitt.Order {
 	if tiffFillOrder == 2 {
 		return ccitt.LSB
@@ -613,164 +1185,342 @@ func ccittFillOrder(tiffFillOrder uint) ccitt.Order {
 	return ccitt.MSB
 }
 
+// ccittSubFormat returns the ccitt package's sub-format for the image's CCITT
+// compression.
+func (d *decoder) ccittSubFormat() ccitt.SubFormat {
+	switch d.firstVal(tCompression) {
+	case cCCITT:
+		return ccitt.ModifiedHuffman
+	case cG3:
+		// Bit 0 of T4Options means that rows may be coded
+		// two-dimensionally.
+		if d.firstVal(tT4Options)&1 != 0 {
+			return ccitt.Group3TwoD
+		}
+		return ccitt.Group3
+	}
+	return ccitt.Group4
+}
+
 // Decode reads a TIFF image from r and returns it as an image.Image.
 // The type of Image returned depends on the contents of the TIFF.
+//
+// CIELab and ICCLab images, as used in prepress, are converted from D50
+// L*a*b* to sRGB, and decode as an *image.RGBA or *image.RGBA64, or as an
+// *image.Gray or *image.Gray16 if they only store L*. JPEG-compressed
+// images, including YCbCr images, decode as an *image.RGBA or *image.Gray.
+// Other YCbCr images decode as an *image.YCbCr, with their chroma
+// subsampling, and CMYK images, as used in prepress, as an *image.CMYK.
 func Decode(r io.Reader) (img image.Image, err error) {
 	d, err := newDecoder(r)
 	if err != nil {
 		return
 	}
+	return d.decodeImage()
+}
 
-	blockPadding := false
-	blockWidth := d.config.Width
-	blockHeight := d.config.Height
-	blocksAcross := 1
-	blocksDown := 1
+// DecodeOptions are the decoding parameters. They scale the samples of images
+// whose samples do not use their whole range, such as those of cameras and
+// scanners that store 12-bit samples in 16 bits, which would otherwise decode
+// too dark. Scaling only applies to images with 8 or 16 bits per sample,
+// other than paletted, CIELab and YCbCr images, and it applies to all of
+// their samples, including alpha.
+type DecodeOptions struct {
+	// MaxSampleValue, if positive, is the largest sample value, which is
+	// scaled to the largest value for the image's BitsPerSample, 0xff or
+	// 0xffff. Larger samples are clamped.
+	MaxSampleValue int
+
+	// SignificantBits, if positive, is the number of significant bits of
+	// each sample, similar to a PNG image's sBIT chunk. The samples are
+	// scaled as if MaxSampleValue were 1<<SignificantBits - 1. It is ignored
+	// if MaxSampleValue is positive.
+	SignificantBits int
+
+	// UseMaxSampleValue is whether to scale the samples as per the image's
+	// MaxSampleValue tag, if it has one. It is ignored if MaxSampleValue or
+	// SignificantBits is positive.
+	UseMaxSampleValue bool
+}
+
+// DecodeWithOptions is like Decode but with decoding parameters. A nil opts
+// means to use the default parameters, which do not scale the samples.
+func DecodeWithOptions(r io.Reader, opts *DecodeOptions) (image.Image, error) {
+	d, err := newDecoder(r)
+	if err != nil {
+		return nil, err
+	}
+	d.setSampleMax(opts)
+	return d.decodeImage()
+}
+
+// decodeImage decodes the image described by d's IFD.
+func (d *decoder) decodeImage() (image.Image, error) {
+	b, err := d.blocks()
+	if err != nil {
+		return nil, err
+	}
+	img := d.newImage(image.Rect(0, 0, d.config.Width, d.config.Height))
+	for i := 0; i < b.across; i++ {
+		for j := 0; j < b.down; j++ {
+			if err := d.decodeBlock(img, &b, i, j); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return img, nil
+}
+
+// blockLayout describes how an image is divided into strips or tiles.
+type blockLayout struct {
+	// padding is whether the blocks are tiles, which are padded to the
+	// block size at the right and bottom edges of the image.
+	padding bool
+	// width and height are the size of a block.
+	width, height int
+	// across and down are the number of blocks in each row and column.
+	across, down int
+	// offsets and counts are the offsets and byte counts of the blocks, row
+	// by row.
+	offsets, counts []uint
+	// maxDataSize limits the size of the uncompressed data of a block.
+	maxDataSize int64
+}
+
+// blocks returns the layout of the image's strips or tiles. It also checks
+// that the predictor and compression scheme, which DecodeConfig does not
+// need, are supported.
+func (d *decoder) blocks() (b blockLayout, err error) {
+	// DecodeConfig does not need to read the pixel data, so it does not
+	// check these.
+	switch p := d.firstVal(tPredictor); p {
+	case 0, prNone, prHorizontal:
+	default:
+		return blockLayout{}, errorf(ErrUnsupportedPredictor, "%d", p)
+	}
+	switch c := d.firstVal(tCompression); c {
+	case 0, cNone, cCCITT, cG3, cG4, cLZW, cDeflate, cDeflateOld, cPackBits:
+	case cJPEG:
+		// JPEG data has 8-bit gray or RGB samples, without extra samples.
+		gray := d.spp == 1 && (d.mode == mGray || d.mode == mGrayInvert)
+		rgb := d.spp == 3 && d.mode == mRGB
+		if d.bpp != 8 || !(gray || rgb) {
+			return blockLayout{}, errorf(ErrUnsupportedCompression, "JPEG with %d samples of %d bits", d.spp, d.bpp)
+		}
+	default:
+		return blockLayout{}, errorf(ErrUnsupportedCompression, "%d", c)
+	}
+	// The horizontal predictor is for pixels, not for data units of
+	// subsampled YCbCr samples.
+	if d.mode == mYCbCr && d.subsampleX*d.subsampleY > 1 && d.firstVal(tPredictor) == prHorizontal {
+		return blockLayout{}, errorf(ErrUnsupportedPredictor, "horizontal predictor with subsampled YCbCr")
+	}
+
+	b.width = d.config.Width
+	b.height = d.config.Height
+	b.across = 1
+	b.down = 1
 
 	if d.config.Width == 0 {
-		blocksAcross = 0
+		b.across = 0
 	}
 	if d.config.Height == 0 {
-		blocksDown = 0
+		b.down = 0
 	}
 
-	var blockOffsets, blockCounts []uint
-
 	if int(d.firstVal(tTileWidth)) != 0 {
-		blockPadding = true
+		b.padding = true
 
-		blockWidth = int(d.firstVal(tTileWidth))
-		blockHeight = int(d.firstVal(tTileLength))
+		b.width = int(d.firstVal(tTileWidth))
+		b.height = int(d.firstVal(tTileLength))
 
 		// The specification says that tile widths and lengths must be a multiple of 16.
 		// We currently permit invalid sizes, but reject anything too small to limit the
 		// amount of work a malicious input can force us to perform.
-		if blockWidth < 8 || blockHeight < 8 {
-			return nil, FormatError("tile size is too small")
+		if b.width < 8 || b.height < 8 {
+			return blockLayout{}, errorf(ErrBadTileSize, "%dx%d", b.width, b.height)
 		}
 
-		if blockWidth != 0 {
-			blocksAcross = (d.config.Width + blockWidth - 1) / blockWidth
+		if b.width != 0 {
+			b.across = (d.config.Width + b.width - 1) / b.width
 		}
-		if blockHeight != 0 {
-			blocksDown = (d.config.Height + blockHeight - 1) / blockHeight
+		if b.height != 0 {
+			b.down = (d.config.Height + b.height - 1) / b.height
 		}
 
-		blockCounts = d.features[tTileByteCounts]
-		blockOffsets = d.features[tTileOffsets]
+		b.counts = d.features[tTileByteCounts]
+		b.offsets = d.features[tTileOffsets]
 
 	} else {
 		if int(d.firstVal(tRowsPerStrip)) != 0 {
-			blockHeight = int(d.firstVal(tRowsPerStrip))
+			b.height = int(d.firstVal(tRowsPerStrip))
 		}
 
-		if blockHeight != 0 {
-			blocksDown = (d.config.Height + blockHeight - 1) / blockHeight
+		if b.height != 0 {
+			b.down = (d.config.Height + b.height - 1) / b.height
 		}
 
-		blockOffsets = d.features[tStripOffsets]
-		blockCounts = d.features[tStripByteCounts]
+		b.offsets = d.features[tStripOffsets]
+		b.counts = d.features[tStripByteCounts]
 	}
 
 	// Check if we have the right number of strips/tiles, offsets and counts.
-	if n := blocksAcross * blocksDown; len(blockOffsets) < n || len(blockCounts) < n {
-		return nil, FormatError("inconsistent header")
+	if n := b.across * b.down; len(b.offsets) < n || len(b.counts) < n {
+		return blockLayout{}, errorf(ErrBadStripCount, "%d offsets and %d byte counts for %d blocks", len(b.offsets), len(b.counts), n)
 	}
 
-	imgRect := image.Rect(0, 0, d.config.Width, d.config.Height)
+	// Maximum data per pixel is 8 bytes (RGBA64), or 2 bytes per sample if
+	// there are more than 4 samples per pixel.
+	b.maxDataSize = int64(b.width) * int64(b.height) * 8
+	if d.spp > 4 {
+		b.maxDataSize = int64(b.width) * int64(b.height) * 2 * int64(d.spp)
+	}
+
+	if d.mode == mYCbCr {
+		// Each block starts with a whole data unit, so the blocks other
+		// than the last strip have a whole number of data units, and
+		// those past the image's edges are padded.
+		if (b.padding && b.width%d.subsampleX != 0) || (b.down > 1 && b.height%d.subsampleY != 0) {
+			return blockLayout{}, errorf(ErrBadSubsampling, "%dx%d for %dx%d blocks", d.subsampleX, d.subsampleY, b.width, b.height)
+		}
+		across, down := d.ycbcrUnits(b.width, b.height)
+		b.maxDataSize = across * down * d.ycbcrUnitSize()
+	}
+	return b, nil
+}
+
+// newImage returns a new image, of the type that Decode returns, with the
+// given bounds.
+func (d *decoder) newImage(r image.Rectangle) (img image.Image) {
 	switch d.mode {
 	case mGray, mGrayInvert:
 		if d.bpp == 16 {
-			img = image.NewGray16(imgRect)
+			img = image.NewGray16(r)
 		} else {
-			img = image.NewGray(imgRect)
+			img = image.NewGray(r)
 		}
 	case mPaletted:
-		img = image.NewPaletted(imgRect, d.palette)
-	case mNRGBA:
+		img = image.NewPaletted(r, d.palette)
+	case mNRGBA, mGrayNA:
 		if d.bpp == 16 {
-			img = image.NewNRGBA64(imgRect)
+			img = image.NewNRGBA64(r)
 		} else {
-			img = image.NewNRGBA(imgRect)
+			img = image.NewNRGBA(r)
 		}
-	case mRGB, mRGBA:
+	case mRGB, mRGBA, mGrayA:
 		if d.bpp == 16 {
-			img = image.NewRGBA64(imgRect)
+			img = image.NewRGBA64(r)
 		} else {
-			img = image.NewRGBA(imgRect)
+			img = image.NewRGBA(r)
+		}
+	case mCMYK:
+		img = image.NewCMYK(r)
+	case mYCbCr:
+		ratio, _ := d.subsampleRatio()
+		img = image.NewYCbCr(r, ratio)
+	case mCIELab, mICCLab:
+		switch d.config.ColorModel {
+		case color.Gray16Model:
+			img = image.NewGray16(r)
+		case color.GrayModel:
+			img = image.NewGray(r)
+		case color.RGBA64Model:
+			img = image.NewRGBA64(r)
+		default:
+			img = image.NewRGBA(r)
 		}
 	}
+	return img
+}
 
-	if blocksAcross == 0 || blocksDown == 0 {
-		return
+// decodeBlock decodes the block in column i and row j of the layout b into
+// dst, which must be of the type returned by newImage and contain the part
+// of the block that is inside the image.
+func (d *decoder) decodeBlock(dst image.Image, b *blockLayout, i, j int) (err error) {
+	blkW := b.width
+	if !b.padding && i == b.across-1 && d.config.Width%b.width != 0 {
+		blkW = d.config.Width % b.width
 	}
-	// Maximum data per pixel is 8 bytes (RGBA64).
-	blockMaxDataSize := int64(blockWidth) * int64(blockHeight) * 8
-	for i := 0; i < blocksAcross; i++ {
-		blkW := blockWidth
-		if !blockPadding && i == blocksAcross-1 && d.config.Width%blockWidth != 0 {
-			blkW = d.config.Width % blockWidth
-		}
-		for j := 0; j < blocksDown; j++ {
-			blkH := blockHeight
-			if !blockPadding && j == blocksDown-1 && d.config.Height%blockHeight != 0 {
-				blkH = d.config.Height % blockHeight
-			}
-			offset := int64(blockOffsets[j*blocksAcross+i])
-			n := int64(blockCounts[j*blocksAcross+i])
-			switch d.firstVal(tCompression) {
-
-			// According to the spec, Compression does not have a default value,
-			// but some tools interpret a missing Compression value as none so we do
-			// the same.
-			case cNone, 0:
-				if b, ok := d.r.(*buffer); ok {
-					d.buf, err = b.Slice(int(offset), int(n))
-				} else {
-					d.buf, err = safeReadAt(d.r, uint64(n), offset)
-				}
-			case cG3:
-				inv := d.firstVal(tPhotometricInterpretation) == pWhiteIsZero
-				order := ccittFillOrder(d.firstVal(tFillOrder))
-				r := ccitt.NewReader(io.NewSectionReader(d.r, offset, n), order, ccitt.Group3, blkW, blkH, &ccitt.Options{Invert: inv, Align: false})
-				d.buf, err = readBuf(r, d.buf, blockMaxDataSize)
-			case cG4:
-				inv := d.firstVal(tPhotometricInterpretation) == pWhiteIsZero
-				order := ccittFillOrder(d.firstVal(tFillOrder))
-				r := ccitt.NewReader(io.NewSectionReader(d.r, offset, n), order, ccitt.Group4, blkW, blkH, &ccitt.Options{Invert: inv, Align: false})
-				d.buf, err = readBuf(r, d.buf, blockMaxDataSize)
-			case cLZW:
-				r := lzw.NewReader(io.NewSectionReader(d.r, offset, n), lzw.MSB, 8)
-				d.buf, err = readBuf(r, d.buf, blockMaxDataSize)
-				r.Close()
-			case cDeflate, cDeflateOld:
-				var r io.ReadCloser
-				r, err = zlib.NewReader(io.NewSectionReader(d.r, offset, n))
-				if err != nil {
-					return nil, err
-				}
-				d.buf, err = readBuf(r, d.buf, blockMaxDataSize)
-				r.Close()
-			case cPackBits:
-				d.buf, err = unpackBits(io.NewSectionReader(d.r, offset, n))
-			default:
-				err = UnsupportedError(fmt.Sprintf("compression value %d", d.firstVal(tCompression)))
-			}
-			if err != nil {
-				return nil, err
-			}
+	blkH := b.height
+	if !b.padding && j == b.down-1 && d.config.Height%b.height != 0 {
+		blkH = d.config.Height % b.height
+	}
+	offset := int64(b.offsets[j*b.across+i])
+	n := int64(b.counts[j*b.across+i])
+	// The CCITT decoders handle FillOrder themselves. For other
+	// compression schemes, as per libtiff, a FillOrder of 2 means to
+	// reverse the bits of each byte of the raw (compressed) data.
+	reverse := d.firstVal(tFillOrder) == 2
+	var raw io.Reader = io.NewSectionReader(d.r, offset, n)
+	if reverse {
+		raw = reverseBitsReader{raw}
+	}
+	switch d.firstVal(tCompression) {
 
-			xmin := i * blockWidth
-			ymin := j * blockHeight
-			xmax := xmin + blkW
-			ymax := ymin + blkH
-			err = d.decode(img, xmin, ymin, xmax, ymax)
-			if err != nil {
-				return nil, err
-			}
+	// According to the spec, Compression does not have a default value,
+	// but some tools interpret a missing Compression value as none so we do
+	// the same.
+	case cNone, 0:
+		if reverse {
+			d.buf, err = safeReadAt(d.r, uint64(n), offset)
+			reverseBits(d.buf)
+		} else if buf, ok := d.r.(*buffer); ok {
+			d.buf, err = buf.Slice(int(offset), int(n))
+		} else {
+			d.buf, err = safeReadAt(d.r, uint64(n), offset)
+		}
+	case cCCITT, cG3, cG4:
+		inv := d.firstVal(tPhotometricInterpretation) == pWhiteIsZero
+		order := ccittFillOrder(d.firstVal(tFillOrder))
+		r := ccitt.NewReader(io.NewSectionReader(d.r, offset, n), order, d.ccittSubFormat(), blkW, blkH, &ccitt.Options{Invert: inv, Align: false})
+		d.buf, err = readBuf(r, d.buf, b.maxDataSize)
+	case cLZW:
+		r := lzw.NewReader(raw, lzw.MSB, 8)
+		d.buf, err = readBuf(r, d.buf, b.maxDataSize)
+		r.Close()
+	case cDeflate, cDeflateOld:
+		var r io.ReadCloser
+		r, err = zlib.NewReader(raw)
+		if err != nil {
+			return err
 		}
+		d.buf, err = readBuf(r, d.buf, b.maxDataSize)
+		r.Close()
+	case cPackBits:
+		d.buf, err = unpackBits(raw)
+	case cJPEG:
+		err = d.decodeJPEG(offset, n, blkW, blkH)
+	default:
+		err = errorf(ErrUnsupportedCompression, "%d", d.firstVal(tCompression))
+	}
+	if err != nil {
+		return err
 	}
-	return
+
+	xmin := i * b.width
+	ymin := j * b.height
+	xmax := xmin + blkW
+	ymax := ymin + blkH
+	return d.decode(dst, xmin, ymin, xmax, ymax)
+}
+
+// reverseBits reverses the order of the bits within each byte of b.
+func reverseBits(b []byte) {
+	for i, x := range b {
+		b[i] = bits.Reverse8(x)
+	}
+}
+
+// reverseBitsReader reverses the order of the bits within each byte read from
+// the underlying io.Reader.
+type reverseBitsReader struct {
+	r io.Reader
+}
+
+func (r reverseBitsReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	reverseBits(p[:n])
+	return n, err
 }
 
 func readBuf(r io.Reader, buf []byte, lim int64) ([]byte, error) {
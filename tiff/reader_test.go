@@ -12,13 +12,18 @@ import (
 	"errors"
 	"fmt"
 	"image"
+	"image/color"
 	"io"
 	"io/ioutil"
+	"math"
+	"math/bits"
 	"os"
 	"sort"
 	"strings"
 	"testing"
 
+	"golang.org/x/image/ccitt"
+
 	_ "image/png"
 )
 
@@ -232,6 +237,60 @@ func TestDecodeCCITT(t *testing.T) {
 	}
 }
 
+// TestDecodeCCITTTwoD tests decoding Group 3 compressed data with
+// two-dimensionally coded rows, as given by bit 0 of T4Options.
+func TestDecodeCCITTTwoD(t *testing.T) {
+	const w, h = 153, 55
+	want, err := load("bw-gopher.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g4, err := os.ReadFile("../ccitt/testdata/bw-gopher.ccitt_group4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pix, err := io.ReadAll(ccitt.NewReader(bytes.NewReader(g4), ccitt.MSB, ccitt.Group4, w, h, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var strip bytes.Buffer
+	cw := ccitt.NewWriter(&strip, ccitt.MSB, ccitt.Group3TwoD, w, nil)
+	if _, err := cw.Write(pix); err != nil {
+		t.Fatal(err)
+	}
+	if err := cw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, t4Options := range []uint32{0, 1} {
+		enc := binary.LittleEndian
+		b := append(newTIFF(enc), strip.Bytes()...)
+		b = appendIFD(b, enc, map[uint16]interface{}{
+			tImageWidth:                uint32(w),
+			tImageLength:               uint32(h),
+			tBitsPerSample:             uint16(1),
+			tCompression:               uint16(cG3),
+			tPhotometricInterpretation: uint16(pBlackIsZero),
+			tStripOffsets:              uint32(8),
+			tRowsPerStrip:              uint32(h),
+			tStripByteCounts:           uint32(strip.Len()),
+			tT4Options:                 t4Options,
+		})
+		m, err := Decode(bytes.NewReader(b))
+		if t4Options == 0 {
+			// The data is not valid one-dimensionally coded data.
+			if err == nil {
+				t.Error("T4Options 0: no error returned, expected an error")
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("T4Options %d: %v", t4Options, err)
+		}
+		compare(t, want, m)
+	}
+}
+
 // TestDecodeTagOrder tests that a malformed image with unsorted IFD entries is
 // correctly rejected.
 func TestDecodeTagOrder(t *testing.T) {
@@ -424,6 +483,132 @@ func TestInvalidPaletteRef(t *testing.T) {
 	}
 }
 
+// packSamples packs each row of samples into bpp bits per sample, MSB first,
+// with each row starting on a byte boundary. If lsbFirst is set, the bits in
+// each byte are then reversed, as per a FillOrder of 2.
+func packSamples(rows [][]uint8, bpp int, lsbFirst bool) []byte {
+	var b []byte
+	for _, row := range rows {
+		var acc, nAcc uint
+		for _, v := range row {
+			acc = acc<<uint(bpp) | uint(v)
+			nAcc += uint(bpp)
+			if nAcc == 8 {
+				b = append(b, uint8(acc))
+				acc, nAcc = 0, 0
+			}
+		}
+		if nAcc != 0 {
+			b = append(b, uint8(acc<<(8-nAcc)))
+		}
+	}
+	if lsbFirst {
+		for i := range b {
+			b[i] = bits.Reverse8(b[i])
+		}
+	}
+	return b
+}
+
+// TestDecodeLowBitDepth tests decoding 1, 2 and 4 bits per sample gray and
+// paletted images, with both fill orders, in strips and in (padded) tiles.
+func TestDecodeLowBitDepth(t *testing.T) {
+	const w, h = 5, 10
+	for _, bpp := range []int{1, 2, 4} {
+		max := uint8(1<<uint(bpp) - 1)
+		rows := make([][]uint8, h)
+		for y := range rows {
+			rows[y] = make([]uint8, w)
+			for x := range rows[y] {
+				rows[y][x] = uint8(3*x+y) & max
+			}
+		}
+		palette := make([]uint16, 3*(int(max)+1))
+		for i := 0; i <= int(max); i++ {
+			palette[i] = uint16(i) * 0x1111
+			palette[i+int(max)+1] = 0xffff - uint16(i)*0x1111
+			palette[i+2*(int(max)+1)] = 0x8000
+		}
+
+		for _, photometric := range []uint16{pWhiteIsZero, pBlackIsZero, pPaletted} {
+			for _, fillOrder := range []uint16{1, 2} {
+				for _, tiled := range []bool{false, true} {
+					enc := binary.BigEndian
+					b := newTIFF(enc)
+					entries := map[uint16]interface{}{
+						tImageWidth:                uint16(w),
+						tImageLength:               uint16(h),
+						tBitsPerSample:             uint16(bpp),
+						tCompression:               uint16(cNone),
+						tPhotometricInterpretation: photometric,
+						tFillOrder:                 fillOrder,
+					}
+					if photometric == pPaletted {
+						entries[tColorMap] = palette
+					}
+					if tiled {
+						// Two 8x8 tiles, each row padded to 8 samples.
+						var tiles [2][]byte
+						for i := range tiles {
+							tileRows := make([][]uint8, 8)
+							for y := range tileRows {
+								tileRows[y] = make([]uint8, 8)
+								if yy := 8*i + y; yy < h {
+									copy(tileRows[y], rows[yy])
+								}
+							}
+							tiles[i] = packSamples(tileRows, bpp, fillOrder == 2)
+						}
+						entries[tTileWidth] = uint16(8)
+						entries[tTileLength] = uint16(8)
+						entries[tTileOffsets] = []uint32{uint32(len(b)), uint32(len(b) + len(tiles[0]))}
+						entries[tTileByteCounts] = []uint32{uint32(len(tiles[0])), uint32(len(tiles[1]))}
+						b = append(b, tiles[0]...)
+						b = append(b, tiles[1]...)
+					} else {
+						data := packSamples(rows, bpp, fillOrder == 2)
+						entries[tRowsPerStrip] = uint16(h)
+						entries[tStripOffsets] = uint32(len(b))
+						entries[tStripByteCounts] = uint32(len(data))
+						b = append(b, data...)
+					}
+					b = appendIFD(b, enc, entries)
+
+					desc := fmt.Sprintf("bpp=%d, photometric=%d, fillOrder=%d, tiled=%t", bpp, photometric, fillOrder, tiled)
+					m, err := Decode(bytes.NewReader(b))
+					if err != nil {
+						t.Errorf("%s: %v", desc, err)
+						continue
+					}
+				loop:
+					for y := 0; y < h; y++ {
+						for x := 0; x < w; x++ {
+							v := rows[y][x]
+							switch photometric {
+							case pPaletted:
+								got := m.(*image.Paletted).ColorIndexAt(x, y)
+								if got != v {
+									t.Errorf("%s: (%d, %d): got index %d, want %d", desc, x, y, got, v)
+									break loop
+								}
+							default:
+								want := uint8(uint32(v) * 0xff / uint32(max))
+								if photometric == pWhiteIsZero {
+									want = 0xff - want
+								}
+								if got := m.(*image.Gray).GrayAt(x, y).Y; got != want {
+									t.Errorf("%s: (%d, %d): got %#02x, want %#02x", desc, x, y, got, want)
+									break loop
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
 // benchmarkDecode benchmarks the decoding of an image.
 func benchmarkDecode(b *testing.B, filename string) {
 	b.Helper()
@@ -517,7 +702,7 @@ type byteOrder interface {
 
 // newTIFF returns the TIFF header.
 func newTIFF(enc byteOrder) []byte {
-	b := []byte{0, 0, 0, 42, 0, 0, 0, 0}
+	b := []byte{0, 0, 0, 0, 0, 0, 0, 0}
 	switch enc.Uint16([]byte{1, 0}) {
 	case 0x1:
 		b[0], b[1] = 'I', 'I'
@@ -526,6 +711,7 @@ func newTIFF(enc byteOrder) []byte {
 	default:
 		panic("odd byte order")
 	}
+	enc.PutUint16(b[2:4], 42)
 	return b
 }
 
@@ -560,6 +746,9 @@ func appendIFD(b []byte, enc byteOrder, entries map[uint16]interface{}) []byte {
 			case 0:
 				ifd = enc.AppendUint32(ifd, 0)
 			case 1:
+				ifd = enc.AppendUint16(ifd, v[0])
+				ifd = enc.AppendUint16(ifd, 0)
+			case 2:
 				ifd = enc.AppendUint16(ifd, v[0])
 				ifd = enc.AppendUint16(ifd, v[1])
 			default:
@@ -582,6 +771,26 @@ func appendIFD(b []byte, enc byteOrder, entries map[uint16]interface{}) []byte {
 					b = enc.AppendUint32(b, e)
 				}
 			}
+		case []byte:
+			ifd = enc.AppendUint16(ifd, dtUndefined)
+			ifd = enc.AppendUint32(ifd, uint32(len(v)))
+			if len(v) <= 4 {
+				ifd = append(ifd, v...)
+				ifd = append(ifd, make([]byte, 4-len(v))...)
+			} else {
+				ifd = enc.AppendUint32(ifd, uint32(len(b)))
+				b = append(b, v...)
+			}
+		case string:
+			ifd = enc.AppendUint16(ifd, dtASCII)
+			ifd = enc.AppendUint32(ifd, uint32(len(v)))
+			if len(v) <= 4 {
+				ifd = append(ifd, v...)
+				ifd = append(ifd, make([]byte, 4-len(v))...)
+			} else {
+				ifd = enc.AppendUint32(ifd, uint32(len(b)))
+				b = append(b, v...)
+			}
 		default:
 			panic(fmt.Errorf("unhandled type %T", v))
 		}
@@ -593,3 +802,693 @@ func appendIFD(b []byte, enc byteOrder, entries map[uint16]interface{}) []byte {
 	b = enc.AppendUint32(b, 0)
 	return b
 }
+
+func TestDecodeExtraSamples(t *testing.T) {
+	const w, h = 3, 2
+	testCases := []struct {
+		photometric  uint16
+		bpp          int
+		spp          int
+		extraSamples interface{} // nil, uint16 or []uint16.
+		want         string      // The decoded image type, or "error".
+	}{
+		{pBlackIsZero, 8, 2, uint16(esUnspecified), "*image.Gray"},
+		{pBlackIsZero, 8, 2, uint16(esAssociatedAlpha), "*image.RGBA"},
+		{pBlackIsZero, 8, 2, uint16(esUnassociatedAlpha), "*image.NRGBA"},
+		{pBlackIsZero, 16, 2, uint16(esAssociatedAlpha), "*image.RGBA64"},
+		{pBlackIsZero, 16, 2, uint16(esUnassociatedAlpha), "*image.NRGBA64"},
+		{pBlackIsZero, 8, 3, []uint16{esUnassociatedAlpha, esUnspecified}, "*image.NRGBA"},
+		{pWhiteIsZero, 8, 2, uint16(esUnassociatedAlpha), "error"},
+		{pBlackIsZero, 4, 2, uint16(esUnassociatedAlpha), "error"},
+		{pRGB, 8, 4, nil, "*image.RGBA"},
+		{pRGB, 8, 4, uint16(esAssociatedAlpha), "*image.RGBA"},
+		{pRGB, 8, 4, uint16(esUnassociatedAlpha), "*image.NRGBA"},
+		{pRGB, 8, 5, []uint16{esAssociatedAlpha, esUnspecified}, "*image.RGBA"},
+		{pRGB, 16, 5, []uint16{esUnassociatedAlpha, esUnspecified}, "*image.NRGBA64"},
+		{pRGB, 8, 4, uint16(3), "error"},
+	}
+
+	sample := func(x, y, s int) uint16 {
+		return uint16(37*(y*w+x)+11*s) & 0xff * 0x101
+	}
+	for _, tc := range testCases {
+		desc := fmt.Sprintf("photometric=%d, bpp=%d, spp=%d, extraSamples=%v", tc.photometric, tc.bpp, tc.spp, tc.extraSamples)
+		enc := binary.BigEndian
+		var data []byte
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				for s := 0; s < tc.spp; s++ {
+					switch tc.bpp {
+					case 4:
+						if s%2 == 0 {
+							data = append(data, uint8(sample(x, y, s)&0xf0))
+						}
+					case 8:
+						data = append(data, uint8(sample(x, y, s)))
+					case 16:
+						data = enc.AppendUint16(data, sample(x, y, s))
+					}
+				}
+			}
+		}
+		bpps := make([]uint16, tc.spp)
+		for i := range bpps {
+			bpps[i] = uint16(tc.bpp)
+		}
+
+		b := newTIFF(enc)
+		entries := map[uint16]interface{}{
+			tImageWidth:                uint16(w),
+			tImageLength:               uint16(h),
+			tBitsPerSample:             bpps,
+			tSamplesPerPixel:           uint16(tc.spp),
+			tCompression:               uint16(cNone),
+			tPhotometricInterpretation: tc.photometric,
+			tRowsPerStrip:              uint16(h),
+			tStripOffsets:              uint32(len(b)),
+			tStripByteCounts:           uint32(len(data)),
+		}
+		if tc.extraSamples != nil {
+			entries[tExtraSamples] = tc.extraSamples
+		}
+		b = append(b, data...)
+		b = appendIFD(b, enc, entries)
+
+		m, err := Decode(bytes.NewReader(b))
+		if tc.want == "error" {
+			if err == nil {
+				t.Errorf("%s: got nil error, want non-nil", desc)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", desc, err)
+			continue
+		}
+		if got := fmt.Sprintf("%T", m); got != tc.want {
+			t.Errorf("%s: got %s, want %s", desc, got, tc.want)
+			continue
+		}
+		cfg, err := DecodeConfig(bytes.NewReader(b))
+		if err != nil {
+			t.Errorf("%s: DecodeConfig: %v", desc, err)
+			continue
+		}
+		if cfg.ColorModel != m.ColorModel() {
+			t.Errorf("%s: DecodeConfig color model differs from the decoded image's", desc)
+		}
+
+	loop:
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				s0, s1, s2, s3 := sample(x, y, 0), sample(x, y, 1), sample(x, y, 2), sample(x, y, 3)
+				var got, want [4]uint16
+				switch m := m.(type) {
+				case *image.Gray:
+					got[0], want[0] = uint16(m.GrayAt(x, y).Y), s0>>8
+				case *image.RGBA:
+					c := m.RGBAAt(x, y)
+					got = [4]uint16{uint16(c.R), uint16(c.G), uint16(c.B), uint16(c.A)}
+				case *image.NRGBA:
+					c := m.NRGBAAt(x, y)
+					got = [4]uint16{uint16(c.R), uint16(c.G), uint16(c.B), uint16(c.A)}
+				case *image.RGBA64:
+					c := m.RGBA64At(x, y)
+					got = [4]uint16{c.R, c.G, c.B, c.A}
+				case *image.NRGBA64:
+					c := m.NRGBA64At(x, y)
+					got = [4]uint16{c.R, c.G, c.B, c.A}
+				}
+				if _, ok := m.(*image.Gray); !ok {
+					switch {
+					case tc.photometric != pRGB:
+						want = [4]uint16{s0, s0, s0, s1}
+					case tc.extraSamples == nil:
+						want = [4]uint16{s0, s1, s2, 0xffff}
+					default:
+						want = [4]uint16{s0, s1, s2, s3}
+					}
+					if tc.bpp == 8 {
+						for i := range want {
+							want[i] >>= 8
+						}
+					}
+				}
+				if got != want {
+					t.Errorf("%s: (%d, %d): got %v, want %v", desc, x, y, got, want)
+					break loop
+				}
+			}
+		}
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	const w, h = 4, 2
+	testCases := []struct {
+		desc string
+		// edit changes the entries of a valid 8-bit gray image's IFD.
+		edit func(entries map[uint16]interface{})
+		want error
+		// configOK is whether DecodeConfig succeeds.
+		configOK bool
+	}{{
+		desc:     "unsupported compression",
+		edit:     func(e map[uint16]interface{}) { e[tCompression] = uint16(cJPEGOld) },
+		want:     ErrUnsupportedCompression,
+		configOK: true,
+	}, {
+		desc:     "unsupported predictor",
+		edit:     func(e map[uint16]interface{}) { e[tPredictor] = uint16(3) },
+		want:     ErrUnsupportedPredictor,
+		configOK: true,
+	}, {
+		desc: "too few strip byte counts",
+		edit: func(e map[uint16]interface{}) {
+			e[tRowsPerStrip] = uint16(1)
+			e[tStripOffsets] = []uint32{8, 8 + w}
+		},
+		want:     ErrBadStripCount,
+		configOK: true,
+	}, {
+		desc:     "not enough pixel data",
+		edit:     func(e map[uint16]interface{}) { e[tStripByteCounts] = uint32(w) },
+		want:     ErrNoPixels,
+		configOK: true,
+	}, {
+		desc: "zero BitsPerSample",
+		edit: func(e map[uint16]interface{}) { e[tBitsPerSample] = []uint16{0} },
+		want: ErrBadBitsPerSample,
+	}, {
+		desc: "unsupported BitsPerSample",
+		edit: func(e map[uint16]interface{}) { e[tBitsPerSample] = []uint16{32} },
+		want: ErrUnsupportedBitsPerSample,
+	}, {
+		desc: "paletted without ColorMap",
+		edit: func(e map[uint16]interface{}) { e[tPhotometricInterpretation] = uint16(pPaletted) },
+		want: ErrBadColorMap,
+	}, {
+		desc: "ExtraSamples without extra samples",
+		edit: func(e map[uint16]interface{}) { e[tExtraSamples] = uint16(esUnassociatedAlpha) },
+		want: ErrBadExtraSamples,
+	}, {
+		desc: "unsupported color model",
+		edit: func(e map[uint16]interface{}) { e[tPhotometricInterpretation] = uint16(pTransMask) },
+		want: ErrUnsupportedColorModel,
+	}}
+
+	enc := binary.BigEndian
+	for _, tc := range testCases {
+		b := newTIFF(enc)
+		entries := map[uint16]interface{}{
+			tImageWidth:                uint16(w),
+			tImageLength:               uint16(h),
+			tBitsPerSample:             []uint16{8},
+			tCompression:               uint16(cNone),
+			tPhotometricInterpretation: uint16(pBlackIsZero),
+			tRowsPerStrip:              uint16(h),
+			tStripOffsets:              uint32(len(b)),
+			tStripByteCounts:           uint32(w * h),
+		}
+		b = append(b, make([]byte, w*h)...)
+		tc.edit(entries)
+		b = appendIFD(b, enc, entries)
+
+		_, err := Decode(bytes.NewReader(b))
+		if !errors.Is(err, tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.desc, err, tc.want)
+			continue
+		}
+		var formatErr FormatError
+		var unsupportedErr UnsupportedError
+		if _, ok := tc.want.(FormatError); ok != errors.As(err, &formatErr) {
+			t.Errorf("%s: errors.As(err, FormatError) = %t, want %t", tc.desc, !ok, ok)
+		}
+		if _, ok := tc.want.(UnsupportedError); ok != errors.As(err, &unsupportedErr) {
+			t.Errorf("%s: errors.As(err, UnsupportedError) = %t, want %t", tc.desc, !ok, ok)
+		}
+
+		_, err = DecodeConfig(bytes.NewReader(b))
+		if tc.configOK && err != nil {
+			t.Errorf("%s: DecodeConfig: %v", tc.desc, err)
+		} else if !tc.configOK && !errors.Is(err, tc.want) {
+			t.Errorf("%s: DecodeConfig: got %v, want %v", tc.desc, err, tc.want)
+		}
+	}
+}
+
+func TestDecodeLab(t *testing.T) {
+	// The colors are the D50 L*a*b* values of sRGB colors.
+	colors := []struct {
+		l, a, b float64
+		want    color.RGBA
+	}{
+		{100, 0, 0, color.RGBA{0xff, 0xff, 0xff, 0xff}},
+		{0, 0, 0, color.RGBA{0x00, 0x00, 0x00, 0xff}},
+		{54.2917, 80.8125, 69.8851, color.RGBA{0xff, 0x00, 0x00, 0xff}},
+		{29.5676, 68.2986, -112.0294, color.RGBA{0x00, 0x00, 0xff, 0xff}},
+		{50, 0, 0, color.RGBA{0x77, 0x77, 0x77, 0xff}},
+	}
+	testCases := []struct {
+		photometric uint16
+		bpp         int
+		spp         int
+		want        string // The decoded image type.
+	}{
+		{pCIELab, 8, 3, "*image.RGBA"},
+		{pCIELab, 16, 3, "*image.RGBA64"},
+		{pICCLab, 8, 3, "*image.RGBA"},
+		{pICCLab, 16, 3, "*image.RGBA64"},
+		{pCIELab, 8, 4, "*image.RGBA"},
+		{pCIELab, 8, 1, "*image.Gray"},
+		{pICCLab, 16, 1, "*image.Gray16"},
+	}
+
+	// near reports whether x and y differ by at most one 8-bit level, as
+	// the L*a*b* values are quantized.
+	near := func(x, y uint32) bool {
+		return x <= y+0x101 && y <= x+0x101
+	}
+	enc := binary.BigEndian
+	for _, tc := range testCases {
+		desc := fmt.Sprintf("photometric=%d, bpp=%d, spp=%d", tc.photometric, tc.bpp, tc.spp)
+		var data []byte
+		for _, c := range colors {
+			samples := [...]float64{c.l * 255 / 100, c.a, c.b, 0}
+			if tc.bpp == 16 {
+				samples[0] = c.l * 0xffff / 100
+			}
+			for s := 0; s < tc.spp; s++ {
+				v := int(math.Round(samples[s]))
+				if s == 1 || s == 2 {
+					if tc.bpp == 16 {
+						v = int(math.Round(samples[s] * 256))
+					}
+					if tc.photometric == pICCLab {
+						v += 1 << (tc.bpp - 1)
+					}
+				}
+				if tc.bpp == 16 {
+					data = enc.AppendUint16(data, uint16(v))
+				} else {
+					data = append(data, uint8(v))
+				}
+			}
+		}
+		bpps := make([]uint16, tc.spp)
+		for i := range bpps {
+			bpps[i] = uint16(tc.bpp)
+		}
+
+		b := newTIFF(enc)
+		entries := map[uint16]interface{}{
+			tImageWidth:                uint16(len(colors)),
+			tImageLength:               uint16(1),
+			tBitsPerSample:             bpps,
+			tSamplesPerPixel:           uint16(tc.spp),
+			tCompression:               uint16(cNone),
+			tPhotometricInterpretation: tc.photometric,
+			tRowsPerStrip:              uint16(1),
+			tStripOffsets:              uint32(len(b)),
+			tStripByteCounts:           uint32(len(data)),
+		}
+		b = append(b, data...)
+		b = appendIFD(b, enc, entries)
+
+		m, err := Decode(bytes.NewReader(b))
+		if err != nil {
+			t.Errorf("%s: %v", desc, err)
+			continue
+		}
+		if got := fmt.Sprintf("%T", m); got != tc.want {
+			t.Errorf("%s: got %s, want %s", desc, got, tc.want)
+			continue
+		}
+		cfg, err := DecodeConfig(bytes.NewReader(b))
+		if err != nil {
+			t.Errorf("%s: DecodeConfig: %v", desc, err)
+		} else if cfg.ColorModel != m.ColorModel() {
+			t.Errorf("%s: DecodeConfig color model differs from the decoded image's", desc)
+		}
+		for x, c := range colors {
+			if tc.spp == 1 && (c.a != 0 || c.b != 0) {
+				continue
+			}
+			gr, gg, gb, ga := m.At(x, 0).RGBA()
+			wr, wg, wb, wa := c.want.RGBA()
+			if !near(gr, wr) || !near(gg, wg) || !near(gb, wb) || ga != wa {
+				t.Errorf("%s: x=%d: got %v, want %v", desc, x, m.At(x, 0), c.want)
+			}
+		}
+	}
+
+	// Alpha is not supported.
+	b := newTIFF(enc)
+	b = append(b, 0, 0, 0, 0)
+	b = appendIFD(b, enc, map[uint16]interface{}{
+		tImageWidth:                uint16(1),
+		tImageLength:               uint16(1),
+		tBitsPerSample:             []uint16{8, 8, 8, 8},
+		tSamplesPerPixel:           uint16(4),
+		tPhotometricInterpretation: uint16(pCIELab),
+		tExtraSamples:              uint16(esUnassociatedAlpha),
+		tStripOffsets:              uint32(8),
+		tStripByteCounts:           uint32(4),
+	})
+	if _, err := Decode(bytes.NewReader(b)); !errors.Is(err, ErrUnsupportedColorModel) {
+		t.Errorf("alpha: got %v, want %v", err, ErrUnsupportedColorModel)
+	}
+}
+
+func TestDecodeWithOptions(t *testing.T) {
+	enc := binary.BigEndian
+	// gray16 is a 4x1 16-bit gray image with 12-bit samples, one of which is
+	// out of range, and the given MaxSampleValue tag, if non-zero.
+	gray16 := func(maxSampleValue uint16) []byte {
+		b := newTIFF(enc)
+		for _, v := range []uint16{0, 2048, 4095, 5000} {
+			b = enc.AppendUint16(b, v)
+		}
+		entries := map[uint16]interface{}{
+			tImageWidth:                uint16(4),
+			tImageLength:               uint16(1),
+			tBitsPerSample:             uint16(16),
+			tPhotometricInterpretation: uint16(pBlackIsZero),
+			tStripOffsets:              uint32(8),
+			tStripByteCounts:           uint32(8),
+		}
+		if maxSampleValue != 0 {
+			entries[tMaxSampleValue] = maxSampleValue
+		}
+		return appendIFD(b, enc, entries)
+	}
+	// rgb8 is a 2x1 8-bit RGB image.
+	rgb8 := func() []byte {
+		b := newTIFF(enc)
+		b = append(b, 0, 50, 100, 200, 99, 1)
+		return appendIFD(b, enc, map[uint16]interface{}{
+			tImageWidth:                uint16(2),
+			tImageLength:               uint16(1),
+			tBitsPerSample:             []uint16{8, 8, 8},
+			tSamplesPerPixel:           uint16(3),
+			tPhotometricInterpretation: uint16(pRGB),
+			tStripOffsets:              uint32(8),
+			tStripByteCounts:           uint32(6),
+		})
+	}
+
+	scaled16 := []uint16{0, 32776, 0xffff, 0xffff}
+	testCases := []struct {
+		desc string
+		data []byte
+		opts *DecodeOptions
+		want []uint16 // The samples, as 16-bit values.
+	}{
+		{"nil", gray16(0), nil, []uint16{0, 2048, 4095, 5000}},
+		{"MaxSampleValue", gray16(0), &DecodeOptions{MaxSampleValue: 4095}, scaled16},
+		{"SignificantBits", gray16(0), &DecodeOptions{SignificantBits: 12}, scaled16},
+		{"SignificantBits=16", gray16(0), &DecodeOptions{SignificantBits: 16}, []uint16{0, 2048, 4095, 5000}},
+		{"MaxSampleValue before SignificantBits", gray16(0), &DecodeOptions{MaxSampleValue: 4095, SignificantBits: 8}, scaled16},
+		{"tag", gray16(4095), &DecodeOptions{UseMaxSampleValue: true}, scaled16},
+		{"tag unused", gray16(4095), &DecodeOptions{}, []uint16{0, 2048, 4095, 5000}},
+		{"no tag", gray16(0), &DecodeOptions{UseMaxSampleValue: true}, []uint16{0, 2048, 4095, 5000}},
+		{"8-bit", rgb8(), &DecodeOptions{MaxSampleValue: 100}, []uint16{0, 0x8080, 0xffff, 0xffff, 0xfcfc, 0x0303}},
+	}
+	for _, tc := range testCases {
+		m, err := DecodeWithOptions(bytes.NewReader(tc.data), tc.opts)
+		if err != nil {
+			t.Errorf("%s: %v", tc.desc, err)
+			continue
+		}
+		var got []uint16
+		switch m := m.(type) {
+		case *image.Gray16:
+			for x := 0; x < 4; x++ {
+				got = append(got, m.Gray16At(x, 0).Y)
+			}
+		case *image.RGBA:
+			for x := 0; x < 2; x++ {
+				c := m.RGBAAt(x, 0)
+				got = append(got, uint16(c.R)*0x101, uint16(c.G)*0x101, uint16(c.B)*0x101)
+			}
+		default:
+			t.Errorf("%s: got %T", tc.desc, m)
+			continue
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.desc, got, tc.want)
+		}
+	}
+}
+
+func TestDecodeCMYK(t *testing.T) {
+	const w, h = 5, 3
+	want := image.NewCMYK(image.Rect(0, 0, w, h))
+	for i := range want.Pix {
+		want.Pix[i] = uint8(37 * i)
+	}
+	cmykNames := "Cyan\x00Magenta\x00Yellow\x00Black\x00"
+	testCases := []struct {
+		desc string
+		spp  int
+		// edit changes the entries of the image's IFD.
+		edit func(entries map[uint16]interface{})
+		want error
+	}{
+		{"CMYK", 4, func(map[uint16]interface{}) {}, nil},
+		{"extra sample", 5, func(e map[uint16]interface{}) { e[tExtraSamples] = uint16(esUnspecified) }, nil},
+		{"CMYK InkSet", 4, func(e map[uint16]interface{}) { e[tInkSet] = uint16(inkSetCMYK) }, nil},
+		{"CMYK InkNames", 4, func(e map[uint16]interface{}) {
+			e[tInkSet] = uint16(inkSetNotCMYK)
+			e[tInkNames] = cmykNames
+		}, nil},
+		{"other inks", 4, func(e map[uint16]interface{}) {
+			e[tInkSet] = uint16(inkSetNotCMYK)
+			e[tInkNames] = "Cyan\x00Magenta\x00Yellow\x00PANTONE 123 C\x00"
+		}, ErrUnsupportedColorModel},
+		{"too few samples", 3, func(map[uint16]interface{}) {}, ErrBadBitsPerSample},
+		{"alpha", 5, func(e map[uint16]interface{}) { e[tExtraSamples] = uint16(esAssociatedAlpha) }, ErrUnsupportedColorModel},
+		{"16-bit samples", 4, func(e map[uint16]interface{}) { e[tBitsPerSample] = []uint16{16, 16, 16, 16} }, ErrUnsupportedBitsPerSample},
+	}
+	for _, tc := range testCases {
+		enc := binary.LittleEndian
+		b := newTIFF(enc)
+		offset := uint32(len(b))
+		for i := 0; i < len(want.Pix); i += 4 {
+			b = append(b, want.Pix[i:i+4]...)
+			for j := 4; j < tc.spp; j++ {
+				b = append(b, 0xee)
+			}
+		}
+		bps := make([]uint16, tc.spp)
+		for i := range bps {
+			bps[i] = 8
+		}
+		entries := map[uint16]interface{}{
+			tImageWidth:                uint16(w),
+			tImageLength:               uint16(h),
+			tBitsPerSample:             bps,
+			tCompression:               uint16(cNone),
+			tPhotometricInterpretation: uint16(pCMYK),
+			tRowsPerStrip:              uint16(h),
+			tStripOffsets:              offset,
+			tStripByteCounts:           uint32(len(b)) - offset,
+		}
+		tc.edit(entries)
+		b = appendIFD(b, enc, entries)
+
+		got, err := Decode(bytes.NewReader(b))
+		if !errors.Is(err, tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.desc, err, tc.want)
+			continue
+		}
+		if tc.want != nil {
+			continue
+		}
+		if m, ok := got.(*image.CMYK); !ok || !bytes.Equal(m.Pix, want.Pix) {
+			t.Errorf("%s: got %T, want the *image.CMYK pixels", tc.desc, got)
+		}
+		if cfg, err := DecodeConfig(bytes.NewReader(b)); err != nil || cfg.ColorModel != color.CMYKModel {
+			t.Errorf("%s: DecodeConfig: got %v, %v, want the CMYK color model", tc.desc, cfg.ColorModel, err)
+		}
+	}
+}
+
+// encodeYCbCr returns the data units of the w×h block at (x0, y0) of m,
+// padded past m's bounds, as per page 92 of the spec.
+func encodeYCbCr(m *image.YCbCr, sx, sy, x0, y0, w, h int) []byte {
+	var b []byte
+	r := m.Bounds()
+	for y := y0; y < y0+h; y += sy {
+		for x := x0; x < x0+w; x += sx {
+			for j := 0; j < sy; j++ {
+				for i := 0; i < sx; i++ {
+					if image.Pt(x+i, y+j).In(r) {
+						b = append(b, m.Y[m.YOffset(x+i, y+j)])
+					} else {
+						b = append(b, 0)
+					}
+				}
+			}
+			if !image.Pt(x, y).In(r) {
+				b = append(b, 0, 0)
+				continue
+			}
+			c := m.COffset(x, y)
+			b = append(b, m.Cb[c], m.Cr[c])
+		}
+	}
+	return b
+}
+
+func TestDecodeYCbCr(t *testing.T) {
+	const w, h = 21, 13
+	testCases := []struct {
+		sx, sy int
+		ratio  image.YCbCrSubsampleRatio
+		// blockSize is the tile size, or the RowsPerStrip if not tiled.
+		blockSize int
+		tiled     bool
+		compress  bool
+	}{
+		{1, 1, image.YCbCrSubsampleRatio444, 4, false, false},
+		{2, 1, image.YCbCrSubsampleRatio422, 5, false, false},
+		{2, 2, image.YCbCrSubsampleRatio420, 4, false, true},
+		{2, 2, image.YCbCrSubsampleRatio420, 16, true, false},
+		{4, 1, image.YCbCrSubsampleRatio411, 13, false, false},
+		{4, 2, image.YCbCrSubsampleRatio410, 16, true, true},
+	}
+	for _, tc := range testCases {
+		desc := fmt.Sprintf("%dx%d, block size %d, tiled=%t, compress=%t", tc.sx, tc.sy, tc.blockSize, tc.tiled, tc.compress)
+		want := image.NewYCbCr(image.Rect(0, 0, w, h), tc.ratio)
+		for i := range want.Y {
+			want.Y[i] = uint8(7 * i)
+		}
+		for i := range want.Cb {
+			want.Cb[i] = uint8(11 * i)
+			want.Cr[i] = uint8(13*i + 5)
+		}
+
+		enc := binary.BigEndian
+		b := newTIFF(enc)
+		var offsets, counts []uint32
+		bw, bh := w, tc.blockSize
+		if tc.tiled {
+			bw = tc.blockSize
+		}
+		for y0 := 0; y0 < h; y0 += bh {
+			for x0 := 0; x0 < w; x0 += bw {
+				// Tiles have their full size, and strips end at the bottom
+				// of the image.
+				blkH := bh
+				if !tc.tiled && y0+blkH > h {
+					blkH = h - y0
+				}
+				data := encodeYCbCr(want, tc.sx, tc.sy, x0, y0, bw, blkH)
+				if tc.compress {
+					var buf bytes.Buffer
+					zw := zlib.NewWriter(&buf)
+					zw.Write(data)
+					zw.Close()
+					data = buf.Bytes()
+				}
+				offsets = append(offsets, uint32(len(b)))
+				counts = append(counts, uint32(len(data)))
+				b = append(b, data...)
+			}
+		}
+		entries := map[uint16]interface{}{
+			tImageWidth:                uint32(w),
+			tImageLength:               uint32(h),
+			tBitsPerSample:             []uint16{8, 8, 8},
+			tCompression:               uint16(cNone),
+			tPhotometricInterpretation: uint16(pYCbCr),
+			tYCbCrSubSampling:          []uint16{uint16(tc.sx), uint16(tc.sy)},
+		}
+		if tc.compress {
+			entries[tCompression] = uint16(cDeflate)
+		}
+		if tc.tiled {
+			entries[tTileWidth] = uint32(bw)
+			entries[tTileLength] = uint32(bh)
+			entries[tTileOffsets] = offsets
+			entries[tTileByteCounts] = counts
+		} else {
+			entries[tRowsPerStrip] = uint32(bh)
+			entries[tStripOffsets] = offsets
+			entries[tStripByteCounts] = counts
+		}
+		b = appendIFD(b, enc, entries)
+
+		got, err := Decode(bytes.NewReader(b))
+		if err != nil {
+			t.Errorf("%s: Decode: %v", desc, err)
+			continue
+		}
+		m, ok := got.(*image.YCbCr)
+		if !ok || m.SubsampleRatio != tc.ratio {
+			t.Errorf("%s: got %T, want an *image.YCbCr with ratio %v", desc, got, tc.ratio)
+			continue
+		}
+		if !bytes.Equal(m.Y, want.Y) || !bytes.Equal(m.Cb, want.Cb) || !bytes.Equal(m.Cr, want.Cr) {
+			t.Errorf("%s: samples differ", desc)
+		}
+		if cfg, err := DecodeConfig(bytes.NewReader(b)); err != nil || cfg.ColorModel != color.YCbCrModel {
+			t.Errorf("%s: DecodeConfig: got %v, %v, want the YCbCr color model", desc, cfg.ColorModel, err)
+		}
+		if err := Validate(bytes.NewReader(b)); err != nil {
+			t.Errorf("%s: Validate: %v", desc, err)
+		}
+	}
+}
+
+func TestDecodeYCbCrErrors(t *testing.T) {
+	const w, h = 6, 4
+	m := image.NewYCbCr(image.Rect(0, 0, w, h), image.YCbCrSubsampleRatio420)
+	data := encodeYCbCr(m, 2, 2, 0, 0, w, h)
+	testCases := []struct {
+		desc string
+		// edit changes the entries of a valid 2×2 subsampled image's IFD.
+		edit func(entries map[uint16]interface{})
+		want error
+	}{
+		{"valid", func(map[uint16]interface{}) {}, nil},
+		{"default subsampling", func(e map[uint16]interface{}) { delete(e, tYCbCrSubSampling) }, nil},
+		{"3x1 subsampling", func(e map[uint16]interface{}) { e[tYCbCrSubSampling] = []uint16{3, 1} }, ErrBadSubsampling},
+		{"1x2 subsampling", func(e map[uint16]interface{}) { e[tYCbCrSubSampling] = []uint16{1, 2} }, ErrBadSubsampling},
+		{"4x4 subsampling", func(e map[uint16]interface{}) { e[tYCbCrSubSampling] = []uint16{4, 4} }, ErrUnsupportedColorModel},
+		{"odd RowsPerStrip", func(e map[uint16]interface{}) {
+			e[tRowsPerStrip] = uint16(3)
+			e[tStripOffsets] = []uint32{8, 8}
+			e[tStripByteCounts] = []uint32{uint32(len(data)), uint32(len(data))}
+		}, ErrBadSubsampling},
+		{"horizontal predictor", func(e map[uint16]interface{}) { e[tPredictor] = uint16(prHorizontal) }, ErrUnsupportedPredictor},
+		{"16-bit samples", func(e map[uint16]interface{}) { e[tBitsPerSample] = []uint16{16, 16, 16} }, ErrUnsupportedBitsPerSample},
+		{"extra sample", func(e map[uint16]interface{}) { e[tBitsPerSample] = []uint16{8, 8, 8, 8} }, ErrUnsupportedExtraSamples},
+		{"not enough pixel data", func(e map[uint16]interface{}) { e[tStripByteCounts] = uint32(len(data) - 1) }, ErrNoPixels},
+	}
+	enc := binary.LittleEndian
+	for _, tc := range testCases {
+		b := newTIFF(enc)
+		entries := map[uint16]interface{}{
+			tImageWidth:                uint16(w),
+			tImageLength:               uint16(h),
+			tBitsPerSample:             []uint16{8, 8, 8},
+			tCompression:               uint16(cNone),
+			tPhotometricInterpretation: uint16(pYCbCr),
+			tYCbCrSubSampling:          []uint16{2, 2},
+			tRowsPerStrip:              uint16(h),
+			tStripOffsets:              uint32(len(b)),
+			tStripByteCounts:           uint32(len(data)),
+		}
+		b = append(b, data...)
+		tc.edit(entries)
+		b = appendIFD(b, enc, entries)
+		if _, err := Decode(bytes.NewReader(b)); !errors.Is(err, tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.desc, err, tc.want)
+		}
+		if err := Validate(bytes.NewReader(b)); !errors.Is(err, tc.want) {
+			t.Errorf("%s: Validate: got %v, want %v", tc.desc, err, tc.want)
+		}
+	}
+}
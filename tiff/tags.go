@@ -0,0 +1,160 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tiff
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+)
+
+// A Tag is a single IFD entry that this package does not otherwise interpret,
+// such as a GeoTIFF key or a vendor's private metadata.
+type Tag struct {
+	// ID is the tag number, such as 33550 for GeoTIFF's ModelPixelScaleTag.
+	ID uint16
+	// Type is the TIFF data type of the values, from 1 (BYTE) to 12
+	// (DOUBLE), as listed on page 15-16 of the spec.
+	Type uint16
+	// Count is the number of values.
+	Count uint32
+	// Value holds the Count values, in little-endian byte order, whatever
+	// the byte order of the file that they are read from or written to.
+	Value []byte
+}
+
+// isLayoutTag returns whether the tag describes the layout of the image
+// data, or refers to other parts of the file by offset. The values of such
+// tags are specific to how the image was encoded, so they cannot be copied
+// from one file to another.
+func isLayoutTag(tag int) bool {
+	switch {
+	case tag >= 254 && tag <= 263, // NewSubfileType to Threshholding.
+		tag == tFillOrder,
+		tag == tStripOffsets,
+		tag >= tSamplesPerPixel && tag <= 284, // Up to PlanarConfiguration.
+		tag >= 288 && tag <= tT6Options,       // FreeOffsets to T6Options.
+		tag == tResolutionUnit,
+		tag == tPredictor,
+		tag == tColorMap,
+		tag >= tTileWidth && tag <= 341, // Up to SMaxSampleValue, including SubIFDs.
+		tag == 347,                      // JPEGTables.
+		tag >= 512 && tag <= 521,        // Old-style JPEG.
+		tag >= 529 && tag <= 532,        // YCbCr and ReferenceBlackWhite.
+		tag == 34665,                    // Exif IFD.
+		tag == 34853,                    // GPS IFD.
+		tag == 40965:                    // Interoperability IFD.
+		return true
+	}
+	return false
+}
+
+// DecodeExtraTags returns the entries of the first IFD of the TIFF image in r
+// that can be copied to another TIFF file. These are the entries that this
+// package neither interprets nor writes itself, other than those that
+// describe the layout of the image data or that refer to other parts of the
+// file by offset, such as the Exif IFD and any maker notes in it.
+//
+// Entries with data types that are not part of TIFF 6.0 are skipped.
+func DecodeExtraTags(r io.Reader) ([]Tag, error) {
+	return readExtraTags(newReaderAt(r))
+}
+
+func readExtraTags(r io.ReaderAt) ([]Tag, error) {
+	byteOrder, ifdOffset, err := readHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	return readIFDExtraTags(r, byteOrder, ifdOffset)
+}
+
+// readIFDExtraTags is like readExtraTags but for the IFD at ifdOffset in r.
+func readIFDExtraTags(r io.ReaderAt, byteOrder binary.ByteOrder, ifdOffset int64) ([]Tag, error) {
+	p := make([]byte, 2)
+	if _, err := r.ReadAt(p, ifdOffset); err != nil {
+		return nil, err
+	}
+	numItems := int(byteOrder.Uint16(p))
+	p, err := safeReadAt(r, uint64(ifdLen*numItems), ifdOffset+2)
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []Tag
+	for i := 0; i < len(p); i += ifdLen {
+		e := p[i : i+ifdLen]
+		tag, datatype := byteOrder.Uint16(e[0:2]), byteOrder.Uint16(e[2:4])
+		if isLayoutTag(int(tag)) || datatype == 0 || int(datatype) >= len(lengths) {
+			continue
+		}
+		count := byteOrder.Uint32(e[4:8])
+		if count > math.MaxInt32/lengths[datatype] {
+			return nil, errorf(ErrIFDTooLarge, "%d values of tag %d", count, tag)
+		}
+		datalen := lengths[datatype] * count
+		value := make([]byte, datalen)
+		if datalen > 4 {
+			raw, err := safeReadAt(r, uint64(datalen), int64(byteOrder.Uint32(e[8:12])))
+			if err != nil {
+				return nil, err
+			}
+			copy(value, raw)
+		} else {
+			copy(value, e[8:8+datalen])
+		}
+		if byteOrder == binary.BigEndian {
+			swapBytes(value, datatype)
+		}
+		tags = append(tags, Tag{ID: tag, Type: datatype, Count: count, Value: value})
+	}
+	return tags, nil
+}
+
+// swapBytes reverses the byte order of each of the values in b, which are of
+// the given data type.
+func swapBytes(b []byte, datatype uint16) {
+	n := int(lengths[datatype])
+	if datatype == dtRational || datatype == dtSRational {
+		// A rational is a pair of 32-bit integers.
+		n = 4
+	}
+	for ; len(b) >= n; b = b[n:] {
+		for i, j := 0, n-1; i < j; i, j = i+1, j-1 {
+			b[i], b[j] = b[j], b[i]
+		}
+	}
+}
+
+// Reencode decodes the TIFF image in r and encodes it to w with the given
+// options, such as a different compression type. Unlike decoding the image
+// and passing it to Encode, it also copies the entries returned by
+// DecodeExtraTags, such as GeoTIFF keys, to the output. Any opt.ExtraTags take
+// precedence over copied entries with the same tag number. The image keeps its
+// resolution unless opt gives one.
+func Reencode(w io.Writer, r io.Reader, opt *Options) error {
+	ra := newReaderAt(r)
+	d, err := newDecoder(io.NewSectionReader(ra, 0, math.MaxInt64))
+	if err != nil {
+		return err
+	}
+	m, err := d.decodeImage()
+	if err != nil {
+		return err
+	}
+	tags, err := readExtraTags(ra)
+	if err != nil {
+		return err
+	}
+
+	o := Options{}
+	if opt != nil {
+		o = *opt
+	}
+	o.ExtraTags = append(o.ExtraTags[:len(o.ExtraTags):len(o.ExtraTags)], tags...)
+	if o.XResolution == 0 && o.YResolution == 0 {
+		o.XResolution, o.YResolution, o.ResolutionUnit = d.resolution()
+	}
+	return Encode(w, m, &o)
+}
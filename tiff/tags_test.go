@@ -0,0 +1,141 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"math"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestEncodeExtraTags(t *testing.T) {
+	scale := make([]byte, 24)
+	for i, v := range []float64{0.5, 0.25, 0} {
+		binary.LittleEndian.PutUint64(scale[8*i:], math.Float64bits(v))
+	}
+	geoKeys := []byte{1, 0, 1, 0, 0, 0, 1, 0, 0, 4, 0, 0, 1, 0, 1, 0}
+	extra := []Tag{
+		{ID: 34735, Type: dtShort, Count: 8, Value: geoKeys},
+		{ID: 33550, Type: dtDouble, Count: 3, Value: scale},
+		{ID: 33432, Type: dtASCII, Count: 5, Value: []byte("Gold\x00")},
+		{ID: 274, Type: dtShort, Count: 1, Value: []byte{6, 0}},
+		{ID: 274, Type: dtShort, Count: 1, Value: []byte{8, 0}},           // Duplicate.
+		{ID: tImageWidth, Type: dtLong, Count: 1, Value: make([]byte, 4)}, // Written by Encode.
+		{ID: tStripOffsets, Type: dtLong, Count: 1, Value: make([]byte, 4)},
+		{ID: 34665, Type: dtLong, Count: 1, Value: make([]byte, 4)}, // Exif IFD.
+		{ID: 65000, Type: dtUndefined, Count: 0},
+	}
+	want := []Tag{
+		{ID: 274, Type: dtShort, Count: 1, Value: []byte{6, 0}},
+		{ID: 33432, Type: dtASCII, Count: 5, Value: []byte("Gold\x00")},
+		{ID: 33550, Type: dtDouble, Count: 3, Value: scale},
+		{ID: 34735, Type: dtShort, Count: 8, Value: geoKeys},
+		{ID: 65000, Type: dtUndefined, Count: 0, Value: []byte{}},
+	}
+
+	m := image.NewGray(image.Rect(0, 0, 3, 5))
+	for i := range m.Pix {
+		m.Pix[i] = uint8(17 * i)
+	}
+	var buf bytes.Buffer
+	if err := Encode(&buf, m, &Options{ExtraTags: extra}); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	m2, err := Decode(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	compare(t, m, m2)
+	got, err := DecodeExtraTags(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("DecodeExtraTags: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DecodeExtraTags:\ngot  %v\nwant %v", got, want)
+	}
+}
+
+func TestEncodeInvalidExtraTags(t *testing.T) {
+	m := image.NewGray(image.Rect(0, 0, 1, 1))
+	for _, tag := range []Tag{
+		{ID: 65000, Type: 0, Count: 1, Value: []byte{0}},
+		{ID: 65000, Type: 13, Count: 1, Value: []byte{0, 0, 0, 0}},
+		{ID: 65000, Type: dtShort, Count: 2, Value: []byte{0, 0}},
+	} {
+		if err := Encode(&bytes.Buffer{}, m, &Options{ExtraTags: []Tag{tag}}); err == nil {
+			t.Errorf("%v: got nil error, want non-nil", tag)
+		}
+	}
+}
+
+func TestDecodeExtraTagsBigEndian(t *testing.T) {
+	enc := binary.BigEndian
+	b := newTIFF(enc)
+	b = append(b, 0x00, 0xff)
+	b = appendIFD(b, enc, map[uint16]interface{}{
+		tImageWidth:                uint16(2),
+		tImageLength:               uint16(1),
+		tBitsPerSample:             []uint16{8},
+		tPhotometricInterpretation: uint16(pBlackIsZero),
+		tStripOffsets:              uint32(8),
+		tStripByteCounts:           uint32(2),
+		34735:                      []uint16{1, 1, 0, 1, 1024, 0, 1, 1},
+		65000:                      uint32(0x01020304),
+	})
+
+	got, err := DecodeExtraTags(bytes.NewReader(b))
+	if err != nil {
+		t.Fatalf("DecodeExtraTags: %v", err)
+	}
+	want := []Tag{
+		{ID: 34735, Type: dtShort, Count: 8, Value: []byte{1, 0, 1, 0, 0, 0, 1, 0, 0, 4, 0, 0, 1, 0, 1, 0}},
+		{ID: 65000, Type: dtLong, Count: 1, Value: []byte{4, 3, 2, 1}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got  %v\nwant %v", got, want)
+	}
+}
+
+func TestReencode(t *testing.T) {
+	// This file is big-endian, with DocumentName, Orientation and Software
+	// tags.
+	data, err := os.ReadFile(testdataDir + "video-001-uncompressed.tiff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantTags, err := DecodeExtraTags(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("DecodeExtraTags: %v", err)
+	}
+	if len(wantTags) != 3 {
+		t.Fatalf("got %d extra tags in the original, want 3", len(wantTags))
+	}
+
+	var buf bytes.Buffer
+	if err := Reencode(&buf, bytes.NewReader(data), &Options{Compression: Deflate}); err != nil {
+		t.Fatalf("Reencode: %v", err)
+	}
+	gotTags, err := DecodeExtraTags(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("DecodeExtraTags: %v", err)
+	}
+	if !reflect.DeepEqual(gotTags, wantTags) {
+		t.Errorf("extra tags:\ngot  %v\nwant %v", gotTags, wantTags)
+	}
+
+	m0, err := Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m1, err := Decode(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	compare(t, m0, m1)
+}
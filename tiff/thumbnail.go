@@ -0,0 +1,192 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tiff
+
+import (
+	"encoding/binary"
+	"image"
+	"io"
+	"sort"
+)
+
+const (
+	// thumbnailSize is the largest width and height of the images that
+	// DecodeThumbnail downscales.
+	thumbnailSize = 256
+
+	// maxThumbnailIFDs limits the number of IFDs that DecodeThumbnail reads,
+	// including the first one.
+	maxThumbnailIFDs = 64
+)
+
+// DecodeThumbnail reads a TIFF image from r and returns a small version of
+// it, such as for a gallery view, without decoding the full image if the
+// file has a smaller one.
+//
+// It returns the smallest reduced-resolution image in the file that it can
+// decode. Those are the images, among the first IFD, the IFDs that follow it
+// and the first IFD's SubIFDs, whose NewSubfileType marks them as a reduced
+// resolution version of another image. The first image is not one of these
+// in most files, but it is in, for example, DNG files.
+//
+// If there is no such image, it decodes the first image one strip or tile at
+// a time, and downscales it by averaging, by the smallest integer factor that
+// makes it no more than 256 pixels wide and high. The downscaled image is an
+// *image.RGBA64 for 16-bit images, and an *image.RGBA otherwise. Images that
+// are already small enough are returned as by Decode.
+func DecodeThumbnail(r io.Reader) (image.Image, error) {
+	ra := newReaderAt(r)
+	byteOrder, ifdOffset, err := readHeader(ra)
+	if err != nil {
+		return nil, err
+	}
+	first, err := newIFDDecoder(ra, byteOrder, ifdOffset)
+	if err != nil {
+		return nil, err
+	}
+
+	var reduced []*decoder
+	seen := map[int64]bool{}
+	// visit adds the image described by the IFD at off to reduced, if it is
+	// a reduced-resolution image. IFDs that cannot be decoded are skipped.
+	visit := func(d *decoder, off int64) {
+		if d == nil {
+			var err error
+			if d, err = newIFDDecoder(ra, byteOrder, off); err != nil {
+				return
+			}
+		}
+		if d.firstVal(tNewSubfileType)&nsReducedResolution != 0 && d.config.Width > 0 && d.config.Height > 0 {
+			reduced = append(reduced, d)
+		}
+	}
+
+	seen[ifdOffset] = true
+	visit(first, ifdOffset)
+	for _, off := range first.features[tSubIFDs] {
+		if len(seen) < maxThumbnailIFDs && !seen[int64(off)] {
+			seen[int64(off)] = true
+			visit(nil, int64(off))
+		}
+	}
+	for off := ifdOffset; len(seen) < maxThumbnailIFDs; {
+		off, err = nextIFD(ra, byteOrder, off)
+		if err != nil || off == 0 || seen[off] {
+			break
+		}
+		seen[off] = true
+		visit(nil, off)
+	}
+
+	sort.SliceStable(reduced, func(i, j int) bool {
+		return reduced[i].config.Width*reduced[i].config.Height < reduced[j].config.Width*reduced[j].config.Height
+	})
+	for _, d := range reduced {
+		if img, err := d.decodeImage(); err == nil {
+			return img, nil
+		}
+	}
+	return first.decodeDownscaled(thumbnailSize)
+}
+
+// nextIFD returns the offset of the IFD that follows the IFD at ifdOffset in
+// r, or 0 if that is the last IFD.
+func nextIFD(r io.ReaderAt, byteOrder binary.ByteOrder, ifdOffset int64) (int64, error) {
+	p := make([]byte, 4)
+	if _, err := r.ReadAt(p[:2], ifdOffset); err != nil {
+		return 0, err
+	}
+	numItems := int64(byteOrder.Uint16(p[:2]))
+	if _, err := r.ReadAt(p, ifdOffset+2+ifdLen*numItems); err != nil {
+		return 0, err
+	}
+	return int64(byteOrder.Uint32(p)), nil
+}
+
+// decodeDownscaled decodes the image described by d's IFD, downscaled by the
+// smallest integer factor that makes it no more than maxSize pixels wide and
+// high. Each block is decoded and downscaled in turn, so that the full image
+// is never held in memory.
+func (d *decoder) decodeDownscaled(maxSize int) (image.Image, error) {
+	w, h := d.config.Width, d.config.Height
+	f := 1
+	if w > maxSize || h > maxSize {
+		f = (w + maxSize - 1) / maxSize
+		if g := (h + maxSize - 1) / maxSize; f < g {
+			f = g
+		}
+	}
+	if f == 1 {
+		return d.decodeImage()
+	}
+
+	b, err := d.blocks()
+	if err != nil {
+		return nil, err
+	}
+	imgRect := image.Rect(0, 0, w, h)
+	tw, th := (w+f-1)/f, (h+f-1)/f
+	// sums holds the sums of the premultiplied red, green, blue and alpha
+	// values of the pixels that each thumbnail pixel averages.
+	sums := make([][4]uint64, tw*th)
+	for i := 0; i < b.across; i++ {
+		for j := 0; j < b.down; j++ {
+			r := image.Rect(i*b.width, j*b.height, (i+1)*b.width, (j+1)*b.height).Intersect(imgRect)
+			m := d.newImage(r)
+			if err := d.decodeBlock(m, &b, i, j); err != nil {
+				return nil, err
+			}
+			src := m.(image.RGBA64Image)
+			for y := r.Min.Y; y < r.Max.Y; y++ {
+				row := sums[(y/f)*tw : (y/f+1)*tw]
+				for x := r.Min.X; x < r.Max.X; x++ {
+					c := src.RGBA64At(x, y)
+					s := &row[x/f]
+					s[0] += uint64(c.R)
+					s[1] += uint64(c.G)
+					s[2] += uint64(c.B)
+					s[3] += uint64(c.A)
+				}
+			}
+		}
+	}
+
+	var rgba *image.RGBA
+	var rgba64 *image.RGBA64
+	if d.bpp == 16 {
+		rgba64 = image.NewRGBA64(image.Rect(0, 0, tw, th))
+	} else {
+		rgba = image.NewRGBA(image.Rect(0, 0, tw, th))
+	}
+	for ty := 0; ty < th; ty++ {
+		// The pixels at the right and bottom edges may average fewer than
+		// f×f pixels.
+		fh := uint64(minInt(f, h-ty*f))
+		for tx := 0; tx < tw; tx++ {
+			n := fh * uint64(minInt(f, w-tx*f))
+			s := &sums[ty*tw+tx]
+			var v [4]uint16
+			for k := range v {
+				v[k] = uint16((s[k] + n/2) / n)
+			}
+			if rgba64 != nil {
+				i := rgba64.PixOffset(tx, ty)
+				for k := range v {
+					rgba64.Pix[i+2*k+0] = uint8(v[k] >> 8)
+					rgba64.Pix[i+2*k+1] = uint8(v[k])
+				}
+			} else {
+				i := rgba.PixOffset(tx, ty)
+				for k := range v {
+					rgba.Pix[i+k] = uint8(v[k] >> 8)
+				}
+			}
+		}
+	}
+	if rgba64 != nil {
+		return rgba64, nil
+	}
+	return rgba, nil
+}
@@ -0,0 +1,256 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// thumbnailTestIFD describes an uncompressed gray image for the
+// DecodeThumbnail tests.
+type thumbnailTestIFD struct {
+	w, h int
+	// rowsPerStrip is the number of rows per strip, or if tiled, the tile
+	// width and height.
+	rowsPerStrip int
+	tiled        bool
+	bpp          int
+	// pix returns the 16-bit gray value of the pixel at (x, y).
+	pix   func(x, y int) uint16
+	extra map[uint16]interface{}
+}
+
+// appendThumbnailTestIFD appends the image data and IFD of m to b, and
+// returns the offset of the IFD.
+func appendThumbnailTestIFD(b []byte, enc byteOrder, m thumbnailTestIFD) ([]byte, uint32) {
+	bw, bh := m.w, m.rowsPerStrip
+	if m.tiled {
+		bw = m.rowsPerStrip
+	}
+	var offsets, counts []uint32
+	for y0 := 0; y0 < m.h; y0 += bh {
+		for x0 := 0; x0 < m.w; x0 += bw {
+			offsets = append(offsets, uint32(len(b)))
+			n := len(b)
+			for y := y0; y < y0+bh && (m.tiled || y < m.h); y++ {
+				for x := x0; x < x0+bw; x++ {
+					v := m.pix(x, y)
+					if m.bpp == 16 {
+						b = enc.AppendUint16(b, v)
+					} else {
+						b = append(b, uint8(v>>8))
+					}
+				}
+			}
+			counts = append(counts, uint32(len(b)-n))
+		}
+	}
+	entries := map[uint16]interface{}{
+		tImageWidth:                uint32(m.w),
+		tImageLength:               uint32(m.h),
+		tBitsPerSample:             uint16(m.bpp),
+		tPhotometricInterpretation: uint16(pBlackIsZero),
+	}
+	if m.tiled {
+		entries[tTileWidth] = uint32(bw)
+		entries[tTileLength] = uint32(bh)
+		entries[tTileOffsets] = offsets
+		entries[tTileByteCounts] = counts
+	} else {
+		entries[tRowsPerStrip] = uint32(bh)
+		entries[tStripOffsets] = offsets
+		entries[tStripByteCounts] = counts
+	}
+	for k, v := range m.extra {
+		entries[k] = v
+	}
+	b = appendIFD(b, enc, entries)
+	return b, enc.Uint32(b[4:8])
+}
+
+// constPix returns a pix function for a constant gray value.
+func constPix(v uint16) func(x, y int) uint16 {
+	return func(x, y int) uint16 { return v }
+}
+
+func TestDecodeThumbnailReduced(t *testing.T) {
+	enc := binary.BigEndian
+	reduced := map[uint16]interface{}{tNewSubfileType: uint32(nsReducedResolution)}
+	main := thumbnailTestIFD{w: 300, h: 20, rowsPerStrip: 20, bpp: 8, pix: constPix(0x1010)}
+
+	testCases := []struct {
+		desc  string
+		build func() []byte
+		// wantW and wantGray are the expected width and gray value.
+		wantW    int
+		wantGray uint8
+	}{{
+		desc: "smallest SubIFD",
+		build: func() []byte {
+			b := newTIFF(enc)
+			b, o0 := appendThumbnailTestIFD(b, enc, thumbnailTestIFD{w: 30, h: 2, rowsPerStrip: 2, bpp: 8, pix: constPix(0x2020), extra: reduced})
+			b, o1 := appendThumbnailTestIFD(b, enc, thumbnailTestIFD{w: 15, h: 1, rowsPerStrip: 1, bpp: 8, pix: constPix(0x3030), extra: reduced})
+			m := main
+			m.extra = map[uint16]interface{}{tSubIFDs: []uint32{o0, o1}}
+			b, _ = appendThumbnailTestIFD(b, enc, m)
+			return b
+		},
+		wantW:    15,
+		wantGray: 0x30,
+	}, {
+		desc: "undecodable SubIFD",
+		build: func() []byte {
+			b := newTIFF(enc)
+			b, o0 := appendThumbnailTestIFD(b, enc, thumbnailTestIFD{w: 30, h: 2, rowsPerStrip: 2, bpp: 8, pix: constPix(0x2020), extra: reduced})
+			b, o1 := appendThumbnailTestIFD(b, enc, thumbnailTestIFD{w: 15, h: 1, rowsPerStrip: 1, bpp: 8, pix: constPix(0x3030), extra: map[uint16]interface{}{
+				tNewSubfileType: uint32(nsReducedResolution),
+				tCompression:    uint16(cJPEGOld),
+			}})
+			m := main
+			m.extra = map[uint16]interface{}{tSubIFDs: []uint32{o0, o1}}
+			b, _ = appendThumbnailTestIFD(b, enc, m)
+			return b
+		},
+		wantW:    30,
+		wantGray: 0x20,
+	}, {
+		desc: "reduced page",
+		build: func() []byte {
+			b := newTIFF(enc)
+			b, o1 := appendThumbnailTestIFD(b, enc, thumbnailTestIFD{w: 10, h: 1, rowsPerStrip: 1, bpp: 8, pix: constPix(0x4040), extra: reduced})
+			b, _ = appendThumbnailTestIFD(b, enc, main)
+			// Link the first IFD to the reduced page.
+			enc.PutUint32(b[len(b)-4:], o1)
+			return b
+		},
+		wantW:    10,
+		wantGray: 0x40,
+	}, {
+		desc: "reduced first IFD",
+		build: func() []byte {
+			b := newTIFF(enc)
+			b, o1 := appendThumbnailTestIFD(b, enc, main)
+			m := thumbnailTestIFD{w: 20, h: 1, rowsPerStrip: 1, bpp: 8, pix: constPix(0x5050), extra: map[uint16]interface{}{
+				tNewSubfileType: uint32(nsReducedResolution),
+				tSubIFDs:        []uint32{o1},
+			}}
+			b, _ = appendThumbnailTestIFD(b, enc, m)
+			return b
+		},
+		wantW:    20,
+		wantGray: 0x50,
+	}, {
+		desc: "other page and cycle",
+		build: func() []byte {
+			b := newTIFF(enc)
+			b, o1 := appendThumbnailTestIFD(b, enc, thumbnailTestIFD{w: 10, h: 1, rowsPerStrip: 1, bpp: 8, pix: constPix(0x4040)})
+			// The second page links back to itself.
+			enc.PutUint32(b[len(b)-4:], o1)
+			b, _ = appendThumbnailTestIFD(b, enc, main)
+			enc.PutUint32(b[len(b)-4:], o1)
+			return b
+		},
+		// The first image is downscaled by 2.
+		wantW:    150,
+		wantGray: 0x10,
+	}}
+
+	for _, tc := range testCases {
+		m, err := DecodeThumbnail(bytes.NewReader(tc.build()))
+		if err != nil {
+			t.Errorf("%s: %v", tc.desc, err)
+			continue
+		}
+		if got := m.Bounds().Dx(); got != tc.wantW {
+			t.Errorf("%s: width: got %d, want %d", tc.desc, got, tc.wantW)
+		}
+		if got := color.GrayModel.Convert(m.At(0, 0)).(color.Gray).Y; got != tc.wantGray {
+			t.Errorf("%s: gray: got %#02x, want %#02x", tc.desc, got, tc.wantGray)
+		}
+	}
+}
+
+// downscale returns m downscaled by f, as DecodeThumbnail does.
+func downscale(m image.Image, f int) *image.RGBA64 {
+	b := m.Bounds()
+	dst := image.NewRGBA64(image.Rect(0, 0, (b.Dx()+f-1)/f, (b.Dy()+f-1)/f))
+	for ty := 0; ty < dst.Rect.Dy(); ty++ {
+		for tx := 0; tx < dst.Rect.Dx(); tx++ {
+			var s [4]uint64
+			n := uint64(0)
+			for y := ty * f; y < (ty+1)*f && y < b.Dy(); y++ {
+				for x := tx * f; x < (tx+1)*f && x < b.Dx(); x++ {
+					r, g, b, a := m.At(x, y).RGBA()
+					s[0], s[1], s[2], s[3] = s[0]+uint64(r), s[1]+uint64(g), s[2]+uint64(b), s[3]+uint64(a)
+					n++
+				}
+			}
+			dst.SetRGBA64(tx, ty, color.RGBA64{
+				uint16((s[0] + n/2) / n),
+				uint16((s[1] + n/2) / n),
+				uint16((s[2] + n/2) / n),
+				uint16((s[3] + n/2) / n),
+			})
+		}
+	}
+	return dst
+}
+
+func TestDecodeThumbnailDownscaled(t *testing.T) {
+	enc := binary.BigEndian
+	pix := func(x, y int) uint16 {
+		return uint16(7*x+13*y) * 0x101
+	}
+	testCases := []struct {
+		m    thumbnailTestIFD
+		f    int
+		want string
+	}{
+		{thumbnailTestIFD{w: 600, h: 10, rowsPerStrip: 4, bpp: 8, pix: pix}, 3, "*image.RGBA"},
+		{thumbnailTestIFD{w: 20, h: 1000, rowsPerStrip: 7, bpp: 16, pix: pix}, 4, "*image.RGBA64"},
+		{thumbnailTestIFD{w: 520, h: 50, rowsPerStrip: 16, tiled: true, bpp: 8, pix: pix}, 3, "*image.RGBA"},
+		{thumbnailTestIFD{w: 256, h: 200, rowsPerStrip: 50, bpp: 8, pix: pix}, 1, "*image.Gray"},
+	}
+	for _, tc := range testCases {
+		desc := fmt.Sprintf("%dx%d, %d bpp, tiled=%t", tc.m.w, tc.m.h, tc.m.bpp, tc.m.tiled)
+		data, _ := appendThumbnailTestIFD(newTIFF(enc), enc, tc.m)
+		got, err := DecodeThumbnail(bytes.NewReader(data))
+		if err != nil {
+			t.Errorf("%s: %v", desc, err)
+			continue
+		}
+		if s := fmt.Sprintf("%T", got); s != tc.want {
+			t.Errorf("%s: got %s, want %s", desc, s, tc.want)
+			continue
+		}
+		full, err := Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Errorf("%s: Decode: %v", desc, err)
+			continue
+		}
+		want := downscale(full, tc.f)
+		if got.Bounds() != want.Bounds() {
+			t.Errorf("%s: bounds: got %v, want %v", desc, got.Bounds(), want.Bounds())
+			continue
+		}
+		for y := 0; y < want.Rect.Dy(); y++ {
+			for x := 0; x < want.Rect.Dx(); x++ {
+				g := color.RGBA64Model.Convert(got.At(x, y))
+				w := color.Color(want.At(x, y))
+				if tc.m.bpp != 16 {
+					w = color.RGBAModel.Convert(w)
+				}
+				if w = color.RGBA64Model.Convert(w); g != w {
+					t.Fatalf("%s: at (%d, %d): got %v, want %v", desc, x, y, g, w)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tiff
+
+import "io"
+
+// Validate checks, without decompressing any pixel data, that r holds a TIFF
+// image that Decode supports and whose strips or tiles are consistent with
+// the image and within the data. It returns the error that Decode would
+// return for the problems that it finds, and it is much faster than Decode
+// for large images, which makes it suitable for triaging many files.
+//
+// The TIFF format has no checksums, so Validate cannot detect corrupt
+// compressed data, or any other problem that only decompressing the strips
+// or tiles would reveal. For uncompressed images, it checks that each strip
+// or tile holds enough bytes for its pixels. Like Decode, it only checks the
+// first image of a multi-page file.
+func Validate(r io.Reader) error {
+	d, err := newDecoder(r)
+	if err != nil {
+		return err
+	}
+	b, err := d.blocks()
+	if err != nil {
+		return err
+	}
+	kind := "strip"
+	if b.padding {
+		kind = "tile"
+	}
+	// end is the end of the data, as far as it is known to extend.
+	end := int64(0)
+	for j := 0; j < b.down; j++ {
+		for i := 0; i < b.across; i++ {
+			k := j*b.across + i
+			offset, n := int64(b.offsets[k]), int64(b.counts[k])
+			if offset < 0 || n < 0 || offset+n < offset {
+				return errorf(ErrNoPixels, "%s %d: offset %d and byte count %d", kind, k, b.offsets[k], b.counts[k])
+			}
+			if min := d.minBlockSize(&b, i, j); n < min {
+				return errorf(ErrNoPixels, "%s %d: %d bytes, want at least %d", kind, k, n, min)
+			}
+			if offset+n > end {
+				var p [1]byte
+				if _, err := d.r.ReadAt(p[:], offset+n-1); err != nil {
+					if err == io.EOF || err == io.ErrUnexpectedEOF {
+						return errorf(ErrNoPixels, "%s %d: bytes %d to %d are past the end of the data", kind, k, offset, offset+n)
+					}
+					return err
+				}
+				end = offset + n
+			}
+		}
+	}
+	return nil
+}
+
+// minBlockSize returns the smallest byte count that the block at column i and
+// row j of b can have for Decode to succeed: for uncompressed images, the
+// bytes up to the first sample of the block's last pixel within the image,
+// and otherwise 1.
+func (d *decoder) minBlockSize(b *blockLayout, i, j int) int64 {
+	if c := d.firstVal(tCompression); c != cNone && c != 0 {
+		return 1
+	}
+	// The rows of uncompressed blocks start on a byte boundary.
+	w := minInt(b.width, d.config.Width-i*b.width)
+	h := minInt(b.height, d.config.Height-j*b.height)
+	if d.mode == mYCbCr {
+		// The block's last pixel is in its last data unit.
+		rowUnits, _ := d.ycbcrUnits(w, h)
+		if b.padding {
+			rowUnits, _ = d.ycbcrUnits(b.width, h)
+		}
+		across, down := d.ycbcrUnits(w, h)
+		return ((down-1)*rowUnits + across) * d.ycbcrUnitSize()
+	}
+	sampleBits := int64(d.bpp) * int64(d.spp)
+	rowBytes := (int64(b.width)*sampleBits + 7) / 8
+	if !b.padding {
+		rowBytes = (int64(w)*sampleBits + 7) / 8
+	}
+	return int64(h-1)*rowBytes + ((int64(w-1)*sampleBits+int64(d.bpp))+7)/8
+}
@@ -0,0 +1,84 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/iotest"
+)
+
+func TestValidateTestdata(t *testing.T) {
+	filenames, err := filepath.Glob("../testdata/*.tiff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, filename := range filenames {
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Decode(bytes.NewReader(data)); err != nil {
+			continue
+		}
+		// Check both an io.ReaderAt and a plain io.Reader.
+		if err := Validate(bytes.NewReader(data)); err != nil {
+			t.Errorf("%s: Validate: %v", filename, err)
+		}
+		if err := Validate(iotest.HalfReader(bytes.NewReader(data))); err != nil {
+			t.Errorf("%s: Validate of an io.Reader: %v", filename, err)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	// The image is 16×10 8-bit gray pixels, in strips of 4 rows.
+	const w, h, rowsPerStrip = 16, 10, 4
+	build := func(compression uint16, offsets, counts []uint32) []byte {
+		enc := binary.BigEndian
+		data := newTIFF(enc)
+		data = append(data, make([]byte, w*h)...)
+		return appendIFD(data, enc, map[uint16]interface{}{
+			tImageWidth:                uint32(w),
+			tImageLength:               uint32(h),
+			tBitsPerSample:             uint16(8),
+			tCompression:               compression,
+			tPhotometricInterpretation: uint16(pBlackIsZero),
+			tStripOffsets:              offsets,
+			tRowsPerStrip:              uint32(rowsPerStrip),
+			tStripByteCounts:           counts,
+		})
+	}
+	offsets := []uint32{8, 8 + 64, 8 + 128}
+	testCases := []struct {
+		desc string
+		data []byte
+		want error
+	}{
+		{"valid", build(cNone, offsets, []uint32{64, 64, 32}), nil},
+		{"short strip", build(cNone, offsets, []uint32{64, 63, 32}), ErrNoPixels},
+		{"short last strip", build(cNone, offsets, []uint32{64, 64, 31}), ErrNoPixels},
+		{"past the end", build(cNone, []uint32{8, 8 + 64, 1 << 20}, []uint32{64, 64, 32}), ErrNoPixels},
+		{"huge byte count", build(cNone, offsets, []uint32{64, 64, 1<<32 - 1}), ErrNoPixels},
+		{"empty compressed strip", build(cDeflate, offsets, []uint32{64, 0, 32}), ErrNoPixels},
+		{"too few strips", build(cNone, offsets[:2], []uint32{64, 64}), ErrBadStripCount},
+		{"unsupported compression", build(cJPEGOld, offsets, []uint32{64, 64, 32}), ErrUnsupportedCompression},
+		{"bad header", append([]byte("XX"), build(cNone, offsets, []uint32{64, 64, 32})[2:]...), ErrMalformedHeader},
+	}
+	for _, tc := range testCases {
+		err := Validate(bytes.NewReader(tc.data))
+		if !errors.Is(err, tc.want) || (err == nil) != (tc.want == nil) {
+			t.Errorf("%s: got %v, want %v", tc.desc, err, tc.want)
+		}
+		// Validate only reports problems that Decode also has.
+		if _, decodeErr := Decode(bytes.NewReader(tc.data)); (decodeErr == nil) != (tc.want == nil) {
+			t.Errorf("%s: Decode: got %v, Validate got %v", tc.desc, decodeErr, err)
+		}
+	}
+}
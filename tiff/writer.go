@@ -10,8 +10,12 @@ import (
 	"encoding/binary"
 	"errors"
 	"image"
+	"image/color"
 	"io"
+	"math"
 	"sort"
+
+	"golang.org/x/image/ccitt"
 )
 
 // The TIFF format allows to choose the order of the different elements freely.
@@ -22,9 +26,6 @@ import (
 //   3. Image File Directory (IFD).
 //   4. "Pointer area" for larger entries in the IFD.
 
-// We only write little-endian TIFF files.
-var enc = binary.LittleEndian
-
 // An ifdEntry is a single entry in an Image File Directory.
 // A value of type dtRational is composed of two 32-bit values,
 // thus data contains two uints (numerator and denominator) for a single number.
@@ -32,19 +33,29 @@ type ifdEntry struct {
 	tag      int
 	datatype int
 	data     []uint32
+	// raw, if non-nil, holds the entry's values, already encoded in
+	// little-endian byte order, instead of data.
+	raw []byte
 }
 
-func (e ifdEntry) putData(p []byte) {
+func (e ifdEntry) putData(p []byte, order binary.ByteOrder) {
+	if e.raw != nil {
+		copy(p, e.raw)
+		if order == binary.BigEndian {
+			swapBytes(p[:len(e.raw)], uint16(e.datatype))
+		}
+		return
+	}
 	for _, d := range e.data {
 		switch e.datatype {
-		case dtByte, dtASCII:
+		case dtByte, dtASCII, dtSByte:
 			p[0] = byte(d)
 			p = p[1:]
-		case dtShort:
-			enc.PutUint16(p, uint16(d))
+		case dtShort, dtSShort:
+			order.PutUint16(p, uint16(d))
 			p = p[2:]
 		case dtLong, dtRational:
-			enc.PutUint32(p, uint32(d))
+			order.PutUint32(p, uint32(d))
 			p = p[4:]
 		}
 	}
@@ -79,7 +90,7 @@ func encodeGray(w io.Writer, pix []uint8, dx, dy, stride int, predictor bool) er
 	return nil
 }
 
-func encodeGray16(w io.Writer, pix []uint8, dx, dy, stride int, predictor bool) error {
+func encodeGray16(w io.Writer, pix []uint8, dx, dy, stride int, predictor bool, order binary.ByteOrder) error {
 	buf := make([]byte, dx*2)
 	for y := 0; y < dy; y++ {
 		min := y*stride + 0
@@ -92,9 +103,7 @@ func encodeGray16(w io.Writer, pix []uint8, dx, dy, stride int, predictor bool)
 			if predictor {
 				v0, v1 = v1, v1-v0
 			}
-			// We only write little-endian TIFF files.
-			buf[off+0] = byte(v1)
-			buf[off+1] = byte(v1 >> 8)
+			order.PutUint16(buf[off:], v1)
 			off += 2
 		}
 		if _, err := w.Write(buf); err != nil {
@@ -130,7 +139,7 @@ func encodeRGBA(w io.Writer, pix []uint8, dx, dy, stride int, predictor bool) er
 	return nil
 }
 
-func encodeRGBA64(w io.Writer, pix []uint8, dx, dy, stride int, predictor bool) error {
+func encodeRGBA64(w io.Writer, pix []uint8, dx, dy, stride int, predictor bool, order binary.ByteOrder) error {
 	buf := make([]byte, dx*8)
 	for y := 0; y < dy; y++ {
 		min := y*stride + 0
@@ -149,15 +158,10 @@ func encodeRGBA64(w io.Writer, pix []uint8, dx, dy, stride int, predictor bool)
 				b0, b1 = b1, b1-b0
 				a0, a1 = a1, a1-a0
 			}
-			// We only write little-endian TIFF files.
-			buf[off+0] = byte(r1)
-			buf[off+1] = byte(r1 >> 8)
-			buf[off+2] = byte(g1)
-			buf[off+3] = byte(g1 >> 8)
-			buf[off+4] = byte(b1)
-			buf[off+5] = byte(b1 >> 8)
-			buf[off+6] = byte(a1)
-			buf[off+7] = byte(a1 >> 8)
+			order.PutUint16(buf[off+0:], r1)
+			order.PutUint16(buf[off+2:], g1)
+			order.PutUint16(buf[off+4:], b1)
+			order.PutUint16(buf[off+6:], a1)
 			off += 8
 		}
 		if _, err := w.Write(buf); err != nil {
@@ -167,6 +171,76 @@ func encodeRGBA64(w io.Writer, pix []uint8, dx, dy, stride int, predictor bool)
 	return nil
 }
 
+// encodeBilevel writes m with 1 bit per pixel. Pixels whose gray value is at
+// least half are white, and the others are black. Each row starts on a byte
+// boundary, and the bits of each byte are in most-significant-first order.
+func encodeBilevel(w io.Writer, m image.Image, whiteIsZero bool) error {
+	bounds := m.Bounds()
+	buf := make([]byte, (bounds.Dx()+7)/8)
+	gray, _ := m.(*image.Gray)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		var b, mask byte = 0, 0x80
+		off := 0
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			var v uint8
+			if gray != nil {
+				v = gray.Pix[gray.PixOffset(x, y)]
+			} else {
+				v = color.GrayModel.Convert(m.At(x, y)).(color.Gray).Y
+			}
+			if (v >= 0x80) != whiteIsZero {
+				b |= mask
+			}
+			if mask >>= 1; mask == 0 {
+				buf[off] = b
+				b, mask = 0, 0x80
+				off++
+			}
+		}
+		if mask != 0x80 {
+			buf[off] = b
+		}
+		if _, err := w.Write(buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// convert16 returns m as an *image.Gray16, *image.RGBA64 or *image.NRGBA64
+// if its color model is that of one of those types, copying its pixels if
+// needed, so that Encode writes its 16-bit samples without truncating them to
+// 8 bits. Other images are returned as they are.
+func convert16(m image.Image) image.Image {
+	var dst interface {
+		image.Image
+		Set(x, y int, c color.Color)
+	}
+	switch m.(type) {
+	case *image.Gray16, *image.RGBA64, *image.NRGBA64:
+		return m
+	}
+	switch m.ColorModel() {
+	case color.Gray16Model:
+		dst = image.NewGray16(m.Bounds())
+	case color.RGBA64Model:
+		dst = image.NewRGBA64(m.Bounds())
+	case color.NRGBA64Model:
+		dst = image.NewNRGBA64(m.Bounds())
+	default:
+		return m
+	}
+	// Set converts each color with the color model, which leaves colors that
+	// are already in it, such as unassociated alpha ones, unchanged.
+	b := m.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			dst.Set(x, y, m.At(x, y))
+		}
+	}
+	return dst
+}
+
 func encode(w io.Writer, m image.Image, predictor bool) error {
 	bounds := m.Bounds()
 	buf := make([]byte, 4*bounds.Dx())
@@ -221,7 +295,44 @@ func writePix(w io.Writer, pix []byte, nrows, length, stride int) error {
 	return nil
 }
 
-func writeIFD(w io.Writer, ifdOffset int, d []ifdEntry) error {
+// count returns the number of values of e.
+func (e ifdEntry) count() uint32 {
+	if e.raw != nil {
+		return uint32(len(e.raw)) / lengths[e.datatype]
+	}
+	if e.datatype == dtRational {
+		return uint32(len(e.data)) / 2
+	}
+	return uint32(len(e.data))
+}
+
+// ifdLength returns the number of bytes that writeIFD writes for d.
+func ifdLength(d []ifdEntry) int {
+	n := 2 + ifdLen*len(d) + 4
+	for _, ent := range d {
+		if datalen := int(ent.count() * lengths[ent.datatype]); datalen > 4 {
+			n += datalen + datalen&1
+		}
+	}
+	return n
+}
+
+// writeFileHeader writes the 8 byte header of a file with the given byte
+// order, whose first IFD starts at ifdOffset.
+func writeFileHeader(w io.Writer, order binary.ByteOrder, ifdOffset int) error {
+	header := leHeader
+	if order == binary.BigEndian {
+		header = beHeader
+	}
+	if _, err := io.WriteString(w, header); err != nil {
+		return err
+	}
+	return binary.Write(w, order, uint32(ifdOffset))
+}
+
+// writeIFD writes the IFD d, which starts at ifdOffset in the file, and ends
+// with the offset of the next IFD, or zero if it is the last one.
+func writeIFD(w io.Writer, order binary.ByteOrder, ifdOffset int, d []ifdEntry, nextIFD int) error {
 	var buf [ifdLen]byte
 	// Make space for "pointer area" containing IFD entry data
 	// longer than 4 bytes.
@@ -233,33 +344,31 @@ func writeIFD(w io.Writer, ifdOffset int, d []ifdEntry) error {
 	sort.Sort(byTag(d))
 
 	// Write the number of entries in this IFD.
-	if err := binary.Write(w, enc, uint16(len(d))); err != nil {
+	if err := binary.Write(w, order, uint16(len(d))); err != nil {
 		return err
 	}
 	for _, ent := range d {
-		enc.PutUint16(buf[0:2], uint16(ent.tag))
-		enc.PutUint16(buf[2:4], uint16(ent.datatype))
-		count := uint32(len(ent.data))
-		if ent.datatype == dtRational {
-			count /= 2
-		}
-		enc.PutUint32(buf[4:8], count)
+		order.PutUint16(buf[0:2], uint16(ent.tag))
+		order.PutUint16(buf[2:4], uint16(ent.datatype))
+		count := ent.count()
+		order.PutUint32(buf[4:8], count)
 		datalen := int(count * lengths[ent.datatype])
 		if datalen <= 4 {
-			ent.putData(buf[8:12])
+			ent.putData(buf[8:12], order)
 		} else {
-			if (o + datalen) > len(parea) {
+			if (o + datalen + 1) > len(parea) {
 				newlen := len(parea) + 1024
-				for (o + datalen) > newlen {
+				for (o + datalen + 1) > newlen {
 					newlen += 1024
 				}
 				newarea := make([]byte, newlen)
 				copy(newarea, parea)
 				parea = newarea
 			}
-			ent.putData(parea[o : o+datalen])
-			enc.PutUint32(buf[8:12], uint32(pstart+o))
-			o += datalen
+			ent.putData(parea[o:o+datalen], order)
+			order.PutUint32(buf[8:12], uint32(pstart+o))
+			// Values must begin on a word boundary (page 15).
+			o += datalen + datalen&1
 		}
 		if _, err := w.Write(buf[:]); err != nil {
 			return err
@@ -267,13 +376,202 @@ func writeIFD(w io.Writer, ifdOffset int, d []ifdEntry) error {
 	}
 	// The IFD ends with the offset of the next IFD in the file,
 	// or zero if it is the last one (page 14).
-	if err := binary.Write(w, enc, uint32(0)); err != nil {
+	if err := binary.Write(w, order, uint32(nextIFD)); err != nil {
 		return err
 	}
 	_, err := w.Write(parea[:o])
 	return err
 }
 
+// pixelFormat holds the IFD fields that describe the pixel format of an image
+// written by this package.
+type pixelFormat struct {
+	photometricInterpretation uint32
+	samplesPerPixel           uint32
+	bitsPerSample             []uint32
+	extraSamples              uint32
+	colorMap                  []uint32
+}
+
+// stripLayout returns the IFD entries for the pixel data of a d.X×d.Y image
+// whose imageLen bytes are one strip that starts at stripOffset in the file.
+func stripLayout(d image.Point, stripOffset, imageLen int) []ifdEntry {
+	return []ifdEntry{
+		{tag: tStripOffsets, datatype: dtLong, data: []uint32{uint32(stripOffset)}},
+		{tag: tRowsPerStrip, datatype: dtShort, data: []uint32{uint32(d.Y)}},
+		{tag: tStripByteCounts, datatype: dtLong, data: []uint32{uint32(imageLen)}},
+	}
+}
+
+// tileLayout returns the IFD entries for the pixel data of an image whose
+// tiles have the given size and byte counts, and are stored one after another
+// from offset in the file.
+func tileLayout(size image.Point, offset int, byteCounts []int) []ifdEntry {
+	offsets := make([]uint32, len(byteCounts))
+	counts := make([]uint32, len(byteCounts))
+	for i, n := range byteCounts {
+		offsets[i] = uint32(offset)
+		counts[i] = uint32(n)
+		offset += n
+	}
+	return []ifdEntry{
+		{tag: tTileWidth, datatype: dtShort, data: []uint32{uint32(size.X)}},
+		{tag: tTileLength, datatype: dtShort, data: []uint32{uint32(size.Y)}},
+		{tag: tTileOffsets, datatype: dtLong, data: offsets},
+		{tag: tTileByteCounts, datatype: dtLong, data: counts},
+	}
+}
+
+// imageIFD returns the IFD entries for a d.X×d.Y image with the given pixel
+// format, whose pixel data is laid out as the layout entries describe.
+func imageIFD(d image.Point, compression, pr uint32, layout []ifdEntry, f pixelFormat) []ifdEntry {
+	ifd := []ifdEntry{
+		{tag: tImageWidth, datatype: dtShort, data: []uint32{uint32(d.X)}},
+		{tag: tImageLength, datatype: dtShort, data: []uint32{uint32(d.Y)}},
+		{tag: tBitsPerSample, datatype: dtShort, data: f.bitsPerSample},
+		{tag: tCompression, datatype: dtShort, data: []uint32{compression}},
+		{tag: tPhotometricInterpretation, datatype: dtShort, data: []uint32{f.photometricInterpretation}},
+		{tag: tSamplesPerPixel, datatype: dtShort, data: []uint32{f.samplesPerPixel}},
+	}
+	ifd = append(ifd, layout...)
+	if pr != prNone {
+		ifd = append(ifd, ifdEntry{tag: tPredictor, datatype: dtShort, data: []uint32{pr}})
+	}
+	if len(f.colorMap) != 0 {
+		ifd = append(ifd, ifdEntry{tag: tColorMap, datatype: dtShort, data: f.colorMap})
+	}
+	// The CCITT data is coded one-dimensionally, without fill bits. Those
+	// are the default options, but some fax software requires the tags.
+	switch compression {
+	case cG3:
+		ifd = append(ifd, ifdEntry{tag: tT4Options, datatype: dtLong, data: []uint32{0}})
+	case cG4:
+		ifd = append(ifd, ifdEntry{tag: tT6Options, datatype: dtLong, data: []uint32{0}})
+	}
+	if f.extraSamples > 0 {
+		ifd = append(ifd, ifdEntry{tag: tExtraSamples, datatype: dtShort, data: []uint32{f.extraSamples}})
+	}
+	return ifd
+}
+
+// resolutionIFD returns the IFD entries for the resolution of an image
+// written with the options o.
+func resolutionIFD(o encodeOptions) []ifdEntry {
+	return []ifdEntry{
+		{tag: tXResolution, datatype: dtRational, data: o.xRes[:]},
+		{tag: tYResolution, datatype: dtRational, data: o.yRes[:]},
+		{tag: tResolutionUnit, datatype: dtShort, data: []uint32{o.resUnit}},
+	}
+}
+
+// toRational returns the numerator and denominator of the rational that is
+// closest to v, of those whose numerator and denominator fit in 32 bits, and
+// whether v is positive and that rational is too.
+func toRational(v float64) (r [2]uint32, ok bool) {
+	if !(v > 0 && v <= math.MaxUint32) {
+		return r, false
+	}
+	// The convergents h1/k1 of v's continued fraction are its best
+	// rational approximations.
+	h0, h1 := uint64(0), uint64(1)
+	k0, k1 := uint64(1), uint64(0)
+	for x := v; ; {
+		a := math.Floor(x)
+		if a > math.MaxUint32 {
+			break
+		}
+		h2, k2 := uint64(a)*h1+h0, uint64(a)*k1+k0
+		if h2 > math.MaxUint32 || k2 > math.MaxUint32 {
+			break
+		}
+		h0, h1, k0, k1 = h1, h2, k1, k2
+		if x == a || float64(h1)/float64(k1) == v {
+			break
+		}
+		x = 1 / (x - a)
+	}
+	return [2]uint32{uint32(h1), uint32(k1)}, h1 > 0 && k1 > 0
+}
+
+// appendExtraTags appends entries for the tags in extra to ifd, skipping
+// those that ifd already has and those that describe the image data layout.
+func appendExtraTags(ifd []ifdEntry, extra []Tag) []ifdEntry {
+	seen := make(map[int]bool, len(ifd)+len(extra))
+	for _, e := range ifd {
+		seen[e.tag] = true
+	}
+	for _, t := range extra {
+		if seen[int(t.ID)] || isLayoutTag(int(t.ID)) {
+			continue
+		}
+		seen[int(t.ID)] = true
+		ifd = append(ifd, ifdEntry{tag: int(t.ID), datatype: int(t.Type), raw: t.Value})
+	}
+	return ifd
+}
+
+// checkSamples returns an error if the sample format and range of sample
+// values of o are invalid for samples with the given number of bits.
+func (o encodeOptions) checkSamples(bitsPerSample uint32, paletted bool) error {
+	if o.sampleFormat != sfUint && (paletted || o.bilevel) {
+		return errors.New("tiff: signed samples need a gray or RGB image")
+	}
+	if o.minSample >= o.maxSample {
+		return nil
+	}
+	min, max := 0, 1<<bitsPerSample-1
+	if o.sampleFormat == sfInt {
+		min, max = -1<<(bitsPerSample-1), 1<<(bitsPerSample-1)-1
+	}
+	if o.minSample < min || o.maxSample > max {
+		return errors.New("tiff: invalid sample value range")
+	}
+	return nil
+}
+
+// sampleIFD returns the IFD entries for the sample format and the range of
+// sample values of o, for an image with the pixel format f.
+func sampleIFD(o encodeOptions, f pixelFormat) []ifdEntry {
+	repeat := func(v uint32) []uint32 {
+		data := make([]uint32, f.samplesPerPixel)
+		for i := range data {
+			data[i] = v
+		}
+		return data
+	}
+	var ifd []ifdEntry
+	// Unsigned integers are the default (page 80 of the spec).
+	if o.sampleFormat != sfUint {
+		ifd = append(ifd, ifdEntry{tag: tSampleFormat, datatype: dtShort, data: repeat(o.sampleFormat)})
+	}
+	if o.minSample >= o.maxSample {
+		return ifd
+	}
+	// The SMinSampleValue and SMaxSampleValue fields have the type that
+	// best matches the samples.
+	datatype := dtShort
+	switch {
+	case o.sampleFormat == sfInt && f.bitsPerSample[0] <= 8:
+		datatype = dtSByte
+	case o.sampleFormat == sfInt:
+		datatype = dtSShort
+	case f.bitsPerSample[0] <= 8:
+		datatype = dtByte
+	}
+	min, max := repeat(uint32(o.minSample)), repeat(uint32(o.maxSample))
+	ifd = append(ifd,
+		ifdEntry{tag: tSMinSampleValue, datatype: datatype, data: min},
+		ifdEntry{tag: tSMaxSampleValue, datatype: datatype, data: max},
+	)
+	if o.sampleFormat == sfUint {
+		ifd = append(ifd,
+			ifdEntry{tag: tMinSampleValue, datatype: dtShort, data: min},
+			ifdEntry{tag: tMaxSampleValue, datatype: dtShort, data: max},
+		)
+	}
+	return ifd
+}
+
 // Options are the encoding parameters.
 type Options struct {
 	// Compression is the type of compression used.
@@ -284,158 +582,676 @@ type Options struct {
 	// types of images and compressors. For example, it works well for
 	// photos with Deflate compression.
 	Predictor bool
+	// ExtraTags are additional IFD entries to write, such as those returned
+	// by DecodeExtraTags. Entries for tags that Encode writes itself, or that
+	// describe the layout of the image data, are ignored, as are all but the
+	// first entry for each tag.
+	ExtraTags []Tag
+	// Bilevel, if true, writes the image with 1 bit per pixel. Pixels whose
+	// gray value is at least half are written as white, and the others as
+	// black.
+	Bilevel bool
+	// PhotometricInterpretation selects whether the zero bits of a bilevel
+	// image are white, the default, or black. Consumers of fax images
+	// disagree on which to expect. It is ignored unless Bilevel is true.
+	PhotometricInterpretation PhotometricInterpretation
+	// ByteOrder is the byte order of the file, binary.LittleEndian or
+	// binary.BigEndian. If it is nil, the file is little-endian.
+	ByteOrder binary.ByteOrder
+	// SampleFormat is how readers interpret the image's samples, which are
+	// written unchanged. For example, with SignedInteger, the samples of an
+	// *image.Gray16 whose values are int16 values converted to uint16 are
+	// read by other readers as those int16 values. It must be
+	// UnsignedInteger for paletted and bilevel images.
+	SampleFormat SampleFormat
+	// MinSampleValue and MaxSampleValue, if MaxSampleValue is greater, are
+	// the range of the values of every sample of the image, including any
+	// alpha sample, as interpreted by SampleFormat. They are written as the
+	// SMinSampleValue and SMaxSampleValue tags, and, for unsigned integers,
+	// as the baseline MinSampleValue and MaxSampleValue tags too.
+	MinSampleValue int
+	MaxSampleValue int
+	// XResolution and YResolution, if positive, are the number of pixels
+	// per ResolutionUnit, horizontally and vertically, such as 300 for a
+	// scan at 300 dpi. If one of them is zero, it is the same as the other.
+	// If both are zero, the image is written with 72 pixels per inch, as
+	// TIFF readers require a resolution.
+	XResolution float64
+	YResolution float64
+	// ResolutionUnit is the unit of XResolution and YResolution. It is
+	// ignored if they are both zero.
+	ResolutionUnit ResolutionUnit
+	// TileSize, if non-zero, is the size of the tiles that the image is
+	// written as, instead of as a single strip, so that readers can decode
+	// any part of the image without reading the rest of it. Its width and
+	// height must both be multiples of 16. The tiles on the right and bottom
+	// edges are padded with zeros if the image's size is not a multiple of
+	// the tile size. Writer does not support tiles.
+	TileSize image.Point
 }
 
-// Encode writes the image m to w. opt determines the options used for
-// encoding, such as the compression type. If opt is nil, an uncompressed
-// image is written.
-func Encode(w io.Writer, m image.Image, opt *Options) error {
-	d := m.Bounds().Size()
+// encodeOptions are the validated encoding parameters of an Options.
+type encodeOptions struct {
+	compression uint32
+	predictor   bool
+	extraTags   []Tag
+	bilevel     bool
+	photometric PhotometricInterpretation
+	tileSize    image.Point
+	byteOrder   binary.ByteOrder
+	// sampleFormat is the tSampleFormat value. minSample and maxSample are
+	// the range of sample values, if minSample < maxSample.
+	sampleFormat uint32
+	minSample    int
+	maxSample    int
+	// xRes and yRes are the tXResolution and tYResolution rationals, and
+	// resUnit is the tResolutionUnit value.
+	xRes, yRes [2]uint32
+	resUnit    uint32
+}
 
-	compression := uint32(cNone)
-	predictor := false
+// parseOptions validates opt, which may be nil, and returns its parameters.
+func parseOptions(opt *Options) (encodeOptions, error) {
+	o := encodeOptions{
+		compression:  cNone,
+		photometric:  WhiteIsZero,
+		byteOrder:    binary.LittleEndian,
+		sampleFormat: sfUint,
+		xRes:         [2]uint32{72, 1},
+		yRes:         [2]uint32{72, 1},
+		resUnit:      resPerInch,
+	}
 	if opt != nil {
-		compression = opt.Compression.specValue()
+		o.compression = opt.Compression.specValue()
 		// The predictor field is only used with LZW. See page 64 of the spec.
-		predictor = opt.Predictor && compression == cLZW
+		o.predictor = opt.Predictor && o.compression == cLZW
+		o.extraTags = opt.ExtraTags
+		o.bilevel = opt.Bilevel
+		o.photometric = opt.PhotometricInterpretation
+		o.tileSize = opt.TileSize
+		if opt.ByteOrder != nil {
+			o.byteOrder = opt.ByteOrder
+		}
+		o.sampleFormat = opt.SampleFormat.specValue()
+		o.minSample = opt.MinSampleValue
+		o.maxSample = opt.MaxSampleValue
+	}
+	if o.byteOrder != binary.LittleEndian && o.byteOrder != binary.BigEndian {
+		return encodeOptions{}, errors.New("tiff: unsupported byte order")
+	}
+	if opt != nil && opt.SampleFormat != UnsignedInteger && opt.SampleFormat != SignedInteger {
+		return encodeOptions{}, errors.New("tiff: unsupported sample format")
+	}
+	if opt != nil && (opt.XResolution != 0 || opt.YResolution != 0) {
+		x, y := opt.XResolution, opt.YResolution
+		if x == 0 {
+			x = y
+		} else if y == 0 {
+			y = x
+		}
+		var xOK, yOK bool
+		o.xRes, xOK = toRational(x)
+		o.yRes, yOK = toRational(y)
+		if !xOK || !yOK {
+			return encodeOptions{}, errors.New("tiff: invalid resolution")
+		}
+		if opt.ResolutionUnit < Inch || opt.ResolutionUnit > NoUnit {
+			return encodeOptions{}, errors.New("tiff: invalid resolution unit")
+		}
+		o.resUnit = opt.ResolutionUnit.specValue()
+	}
+	if o.tileSize != (image.Point{}) {
+		if o.tileSize.X <= 0 || o.tileSize.Y <= 0 || o.tileSize.X%16 != 0 || o.tileSize.Y%16 != 0 {
+			return encodeOptions{}, errors.New("tiff: invalid tile size")
+		}
 	}
+	if o.bilevel {
+		if o.photometric != WhiteIsZero && o.photometric != BlackIsZero {
+			return encodeOptions{}, errors.New("tiff: invalid photometric interpretation")
+		}
+		// There is no differencing predictor for bilevel images.
+		o.predictor = false
+	} else if isCCITT(o.compression) {
+		return encodeOptions{}, errors.New("tiff: CCITT compression needs a bilevel image")
+	}
+	for _, t := range o.extraTags {
+		if t.Type == 0 || int(t.Type) >= len(lengths) || uint64(len(t.Value)) != uint64(t.Count)*uint64(lengths[t.Type]) {
+			return encodeOptions{}, errors.New("tiff: invalid extra tag")
+		}
+	}
+	return o, nil
+}
 
-	_, err := io.WriteString(w, leHeader)
+// Encode writes the image m to w. opt determines the options used for
+// encoding, such as the compression type. If opt is nil, an uncompressed
+// image is written.
+//
+// Images whose color model is color.Gray16Model, color.RGBA64Model or
+// color.NRGBA64Model are written with 16 bits per sample, and Decode returns
+// them as an *image.Gray16, *image.RGBA64 or *image.NRGBA64. Other images,
+// except for those of the image package's 8-bit types, are written as 8-bit
+// RGBA.
+func Encode(w io.Writer, m image.Image, opt *Options) error {
+	o, err := parseOptions(opt)
 	if err != nil {
 		return err
 	}
+	p, err := newPageEncoder(m, o)
+	if err != nil {
+		return err
+	}
+	// The IFD follows the pixel data, which follows the 8 header bytes.
+	if err := writeFileHeader(w, o.byteOrder, p.imageLen+8); err != nil {
+		return err
+	}
+	if err := p.writePixels(w); err != nil {
+		return err
+	}
+	return writeIFD(w, o.byteOrder, p.imageLen+8, p.ifd(8), 0)
+}
 
-	// Compressed data is written into a buffer first, so that we
-	// know the compressed size.
-	var buf bytes.Buffer
-	// dst holds the destination for the pixel data of the image --
-	// either w or a writer to buf.
-	var dst io.Writer
+// pageEncoder encodes one image, as one strip or a number of tiles of pixel
+// data, and its IFD.
+type pageEncoder struct {
+	m image.Image
+	o encodeOptions
+	// blocks are the parts of the image whose pixel data are written, in
+	// order, which are its tiles, or its bounds if it is not tiled.
+	blocks []image.Rectangle
+	// blockLens are the lengths of the blocks' pixel data in bytes.
+	blockLens []int
 	// imageLen is the length of the pixel data in bytes.
-	// The offset of the IFD is imageLen + 8 header bytes.
-	var imageLen int
+	imageLen int
+	// buf holds the compressed pixel data, for compressed images, which is
+	// compressed by newPageEncoder so that its size is known.
+	buf bytes.Buffer
+	// pr and format are set by encodePixels.
+	pr     uint32
+	format pixelFormat
+}
 
-	switch compression {
+// newPageEncoder returns a pageEncoder for m, with the options o. It
+// compresses the pixel data, for compressed images.
+func newPageEncoder(m image.Image, o encodeOptions) (*pageEncoder, error) {
+	if !o.bilevel {
+		m = convert16(m)
+	}
+	bitsPerSample, paletted := uint32(8), false
+	switch m.(type) {
+	case *image.Gray16, *image.RGBA64, *image.NRGBA64:
+		bitsPerSample = 16
+	case *image.Paletted:
+		paletted = true
+	}
+	if o.bilevel {
+		bitsPerSample = 1
+	}
+	if err := o.checkSamples(bitsPerSample, paletted); err != nil {
+		return nil, err
+	}
+
+	p := &pageEncoder{m: m, o: o}
+	if o.tileSize == (image.Point{}) {
+		p.blocks = []image.Rectangle{m.Bounds()}
+	} else {
+		b, size := m.Bounds(), o.tileSize
+		for y := b.Min.Y; y < b.Max.Y; y += size.Y {
+			for x := b.Min.X; x < b.Max.X; x += size.X {
+				p.blocks = append(p.blocks, image.Rectangle{image.Pt(x, y), image.Pt(x, y).Add(size)})
+			}
+		}
+	}
+	switch o.compression {
 	case cNone:
-		dst = w
-		// Write IFD offset before outputting pixel data.
-		switch m.(type) {
+		for _, r := range p.blocks {
+			n := pixelDataLen(m, o.bilevel, r.Size())
+			p.blockLens = append(p.blockLens, n)
+			p.imageLen += n
+		}
+	case cDeflate:
+		dst := zlib.NewWriter(&p.buf)
+		for _, r := range p.blocks {
+			n := p.buf.Len()
+			dst.Reset(&p.buf)
+			if err := p.encodePixels(dst, r); err != nil {
+				return nil, err
+			}
+			if err := dst.Close(); err != nil {
+				return nil, err
+			}
+			p.blockLens = append(p.blockLens, p.buf.Len()-n)
+		}
+		p.imageLen = p.buf.Len()
+	case cCCITT, cG3, cG4:
+		for _, r := range p.blocks {
+			n := p.buf.Len()
+			dst := newCCITTWriter(&p.buf, o, r.Dx())
+			if err := p.encodePixels(dst, r); err != nil {
+				return nil, err
+			}
+			if err := dst.Close(); err != nil {
+				return nil, err
+			}
+			p.blockLens = append(p.blockLens, p.buf.Len()-n)
+		}
+		p.imageLen = p.buf.Len()
+	default:
+		return nil, errors.New("tiff: unsupported compression")
+	}
+	return p, nil
+}
+
+// isCCITT returns whether compression is one of the CCITT compression schemes.
+func isCCITT(compression uint32) bool {
+	return compression == cCCITT || compression == cG3 || compression == cG4
+}
+
+// newCCITTWriter returns a writer that compresses the rows of a bilevel
+// image of the given width, as encodeBilevel writes them for the options o,
+// to w, with the CCITT compression of o.
+func newCCITTWriter(w io.Writer, o encodeOptions, width int) io.WriteCloser {
+	sf := ccitt.Group4
+	switch o.compression {
+	case cCCITT:
+		sf = ccitt.ModifiedHuffman
+	case cG3:
+		sf = ccitt.Group3
+	}
+	// The ccitt package's 1 bits are white unless inverted.
+	opts := &ccitt.Options{Invert: o.photometric == WhiteIsZero}
+	return ccitt.NewWriter(w, ccitt.MSB, sf, width, opts)
+}
+
+// pixelDataLen returns the length in bytes of the uncompressed pixel data of
+// a d.X×d.Y image of the same type as m.
+func pixelDataLen(m image.Image, bilevel bool, d image.Point) int {
+	if bilevel {
+		return (d.X + 7) / 8 * d.Y
+	}
+	switch m.(type) {
+	case *image.Paletted:
+		return d.X * d.Y * 1
+	case *image.Gray:
+		return d.X * d.Y * 1
+	case *image.Gray16:
+		return d.X * d.Y * 2
+	case *image.RGBA64:
+		return d.X * d.Y * 8
+	case *image.NRGBA64:
+		return d.X * d.Y * 8
+	}
+	return d.X * d.Y * 4
+}
+
+// writePixels writes the p.imageLen bytes of pixel data to w.
+func (p *pageEncoder) writePixels(w io.Writer) error {
+	if p.o.compression == cNone {
+		for _, r := range p.blocks {
+			if err := p.encodePixels(w, r); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	_, err := p.buf.WriteTo(w)
+	return err
+}
+
+// tileImage returns the r part of m as an image of the same type whose bounds
+// are r, or as an *image.Gray if bilevel is true, so that the pixels of a tile
+// that are outside m's bounds are zero. Other image types are returned as an
+// *image.RGBA, whose pixels are those that encode writes.
+func tileImage(m image.Image, r image.Rectangle, bilevel bool) image.Image {
+	s := r.Intersect(m.Bounds())
+	copyRows := func(dst []uint8, dstStride int, src []uint8, srcStride, rowLen int) {
+		for y := 0; y < s.Dy(); y++ {
+			copy(dst[y*dstStride:y*dstStride+rowLen], src[y*srcStride:])
+		}
+	}
+	if m, ok := m.(*image.Gray); ok {
+		t := image.NewGray(r)
+		copyRows(t.Pix[t.PixOffset(s.Min.X, s.Min.Y):], t.Stride, m.Pix[m.PixOffset(s.Min.X, s.Min.Y):], m.Stride, s.Dx())
+		return t
+	}
+	var t interface {
+		image.Image
+		Set(x, y int, c color.Color)
+	}
+	if bilevel {
+		t = image.NewGray(r)
+	} else {
+		switch m := m.(type) {
 		case *image.Paletted:
-			imageLen = d.X * d.Y * 1
-		case *image.Gray:
-			imageLen = d.X * d.Y * 1
+			t := image.NewPaletted(r, m.Palette)
+			copyRows(t.Pix[t.PixOffset(s.Min.X, s.Min.Y):], t.Stride, m.Pix[m.PixOffset(s.Min.X, s.Min.Y):], m.Stride, s.Dx())
+			return t
 		case *image.Gray16:
-			imageLen = d.X * d.Y * 2
-		case *image.RGBA64:
-			imageLen = d.X * d.Y * 8
+			t := image.NewGray16(r)
+			copyRows(t.Pix[t.PixOffset(s.Min.X, s.Min.Y):], t.Stride, m.Pix[m.PixOffset(s.Min.X, s.Min.Y):], m.Stride, s.Dx()*2)
+			return t
+		case *image.NRGBA:
+			t := image.NewNRGBA(r)
+			copyRows(t.Pix[t.PixOffset(s.Min.X, s.Min.Y):], t.Stride, m.Pix[m.PixOffset(s.Min.X, s.Min.Y):], m.Stride, s.Dx()*4)
+			return t
 		case *image.NRGBA64:
-			imageLen = d.X * d.Y * 8
-		default:
-			imageLen = d.X * d.Y * 4
+			t := image.NewNRGBA64(r)
+			copyRows(t.Pix[t.PixOffset(s.Min.X, s.Min.Y):], t.Stride, m.Pix[m.PixOffset(s.Min.X, s.Min.Y):], m.Stride, s.Dx()*8)
+			return t
+		case *image.RGBA:
+			t := image.NewRGBA(r)
+			copyRows(t.Pix[t.PixOffset(s.Min.X, s.Min.Y):], t.Stride, m.Pix[m.PixOffset(s.Min.X, s.Min.Y):], m.Stride, s.Dx()*4)
+			return t
+		case *image.RGBA64:
+			t := image.NewRGBA64(r)
+			copyRows(t.Pix[t.PixOffset(s.Min.X, s.Min.Y):], t.Stride, m.Pix[m.PixOffset(s.Min.X, s.Min.Y):], m.Stride, s.Dx()*8)
+			return t
 		}
-		err = binary.Write(w, enc, uint32(imageLen+8))
-		if err != nil {
-			return err
+		t = image.NewRGBA(r)
+	}
+	for y := s.Min.Y; y < s.Max.Y; y++ {
+		for x := s.Min.X; x < s.Max.X; x++ {
+			t.Set(x, y, m.At(x, y))
 		}
-	case cDeflate:
-		dst = zlib.NewWriter(&buf)
-	default:
-		return errors.New("tiff: unsupported compression")
 	}
+	return t
+}
 
-	pr := uint32(prNone)
-	photometricInterpretation := uint32(pRGB)
-	samplesPerPixel := uint32(4)
-	bitsPerSample := []uint32{8, 8, 8, 8}
-	extraSamples := uint32(0)
-	colorMap := []uint32{}
-
+// encodePixels writes the uncompressed pixel data of the block r to dst, and
+// sets p.pr and p.format.
+func (p *pageEncoder) encodePixels(dst io.Writer, r image.Rectangle) (err error) {
+	m := p.m
+	if r != m.Bounds() {
+		m = tileImage(m, r, p.o.bilevel)
+	}
+	d, predictor := r.Size(), p.o.predictor
+	p.pr = prNone
 	if predictor {
-		pr = prHorizontal
+		p.pr = prHorizontal
+	}
+	p.format = pixelFormat{
+		photometricInterpretation: pRGB,
+		samplesPerPixel:           4,
+		bitsPerSample:             []uint32{8, 8, 8, 8},
+	}
+	f := &p.format
+	if p.o.bilevel {
+		f.photometricInterpretation = uint32(p.o.photometric)
+		f.samplesPerPixel = 1
+		f.bitsPerSample = []uint32{1}
+		return encodeBilevel(dst, m, p.o.photometric == WhiteIsZero)
 	}
 	switch m := m.(type) {
 	case *image.Paletted:
-		photometricInterpretation = pPaletted
-		samplesPerPixel = 1
-		bitsPerSample = []uint32{8}
-		colorMap = make([]uint32, 256*3)
+		f.photometricInterpretation = pPaletted
+		f.samplesPerPixel = 1
+		f.bitsPerSample = []uint32{8}
+		f.colorMap = make([]uint32, 256*3)
 		for i := 0; i < 256 && i < len(m.Palette); i++ {
 			r, g, b, _ := m.Palette[i].RGBA()
-			colorMap[i+0*256] = uint32(r)
-			colorMap[i+1*256] = uint32(g)
-			colorMap[i+2*256] = uint32(b)
+			f.colorMap[i+0*256] = uint32(r)
+			f.colorMap[i+1*256] = uint32(g)
+			f.colorMap[i+2*256] = uint32(b)
 		}
 		err = encodeGray(dst, m.Pix, d.X, d.Y, m.Stride, predictor)
 	case *image.Gray:
-		photometricInterpretation = pBlackIsZero
-		samplesPerPixel = 1
-		bitsPerSample = []uint32{8}
+		f.photometricInterpretation = pBlackIsZero
+		f.samplesPerPixel = 1
+		f.bitsPerSample = []uint32{8}
 		err = encodeGray(dst, m.Pix, d.X, d.Y, m.Stride, predictor)
 	case *image.Gray16:
-		photometricInterpretation = pBlackIsZero
-		samplesPerPixel = 1
-		bitsPerSample = []uint32{16}
-		err = encodeGray16(dst, m.Pix, d.X, d.Y, m.Stride, predictor)
+		f.photometricInterpretation = pBlackIsZero
+		f.samplesPerPixel = 1
+		f.bitsPerSample = []uint32{16}
+		err = encodeGray16(dst, m.Pix, d.X, d.Y, m.Stride, predictor, p.o.byteOrder)
 	case *image.NRGBA:
-		extraSamples = 2 // Unassociated alpha.
+		f.extraSamples = 2 // Unassociated alpha.
 		err = encodeRGBA(dst, m.Pix, d.X, d.Y, m.Stride, predictor)
 	case *image.NRGBA64:
-		extraSamples = 2 // Unassociated alpha.
-		bitsPerSample = []uint32{16, 16, 16, 16}
-		err = encodeRGBA64(dst, m.Pix, d.X, d.Y, m.Stride, predictor)
+		f.extraSamples = 2 // Unassociated alpha.
+		f.bitsPerSample = []uint32{16, 16, 16, 16}
+		err = encodeRGBA64(dst, m.Pix, d.X, d.Y, m.Stride, predictor, p.o.byteOrder)
 	case *image.RGBA:
-		extraSamples = 1 // Associated alpha.
+		f.extraSamples = 1 // Associated alpha.
 		err = encodeRGBA(dst, m.Pix, d.X, d.Y, m.Stride, predictor)
 	case *image.RGBA64:
-		extraSamples = 1 // Associated alpha.
-		bitsPerSample = []uint32{16, 16, 16, 16}
-		err = encodeRGBA64(dst, m.Pix, d.X, d.Y, m.Stride, predictor)
+		f.extraSamples = 1 // Associated alpha.
+		f.bitsPerSample = []uint32{16, 16, 16, 16}
+		err = encodeRGBA64(dst, m.Pix, d.X, d.Y, m.Stride, predictor, p.o.byteOrder)
 	default:
-		extraSamples = 1 // Associated alpha.
+		f.extraSamples = 1 // Associated alpha.
 		err = encode(dst, m, predictor)
 	}
+	return err
+}
+
+// ifd returns the IFD entries for the image, whose pixel data starts at
+// offset in the file. It must be called after writePixels.
+func (p *pageEncoder) ifd(offset int) []ifdEntry {
+	d := p.m.Bounds().Size()
+	layout := stripLayout(d, offset, p.imageLen)
+	if p.o.tileSize != (image.Point{}) {
+		layout = tileLayout(p.o.tileSize, offset, p.blockLens)
+	}
+	ifd := imageIFD(d, p.o.compression, p.pr, layout, p.format)
+	ifd = append(ifd, resolutionIFD(p.o)...)
+	ifd = append(ifd, sampleIFD(p.o, p.format)...)
+	return appendExtraTags(ifd, p.o.extraTags)
+}
+
+// Writer writes a TIFF image whose pixel data is given a number of rows at a
+// time, such as when the rows come from a scanner, without first holding the
+// whole image in an image.Image. Call WriteRows until every row has been
+// given, and then Close.
+//
+// Uncompressed pixel data is written to the underlying io.Writer as it is
+// given. Compressed pixel data is buffered until Close, as the image's
+// header, which comes first, records where the data ends.
+type Writer struct {
+	w           io.Writer
+	width       int
+	height      int
+	rowLen      int
+	rows        int
+	o           encodeOptions
+	format      pixelFormat
+	encodeRows  func(w io.Writer, pix []uint8, dx, dy, stride int, predictor bool) error
+	buf         bytes.Buffer
+	dst         io.Writer
+	imageLen    int
+	err         error
+	wroteHeader bool
+}
+
+// NewWriter returns a Writer that writes a width×height image to w, with the
+// given options, as Encode does. Default options are used if opt is nil.
+//
+// The model determines the layout of the pixel data given to WriteRows, which
+// is that of the Pix field of the image type with that color model. It must
+// be one of color.GrayModel (*image.Gray), color.Gray16Model
+// (*image.Gray16), color.RGBAModel (*image.RGBA), color.RGBA64Model
+// (*image.RGBA64), color.NRGBAModel (*image.NRGBA), color.NRGBA64Model
+// (*image.NRGBA64) or a color.Palette (*image.Paletted). If opt.Bilevel is
+// true, the model must be color.GrayModel.
+func NewWriter(w io.Writer, width, height int, model color.Model, opt *Options) (*Writer, error) {
+	if width <= 0 || height <= 0 {
+		return nil, errors.New("tiff: invalid image size for encoding")
+	}
+	o, err := parseOptions(opt)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if o.compression != cNone && o.compression != cDeflate && !isCCITT(o.compression) {
+		return nil, errors.New("tiff: unsupported compression")
+	}
+	if o.tileSize != (image.Point{}) {
+		return nil, errors.New("tiff: Writer does not support tiles")
+	}
+	z := &Writer{
+		w:      w,
+		width:  width,
+		height: height,
+		o:      o,
+		format: pixelFormat{
+			photometricInterpretation: pRGB,
+			samplesPerPixel:           4,
+			bitsPerSample:             []uint32{8, 8, 8, 8},
+		},
+	}
+	bytesPerPixel := 4
+	switch model {
+	case color.GrayModel:
+		bytesPerPixel = 1
+		z.format = pixelFormat{
+			photometricInterpretation: pBlackIsZero,
+			samplesPerPixel:           1,
+			bitsPerSample:             []uint32{8},
+		}
+		z.encodeRows = encodeGray
+	case color.Gray16Model:
+		bytesPerPixel = 2
+		z.format = pixelFormat{
+			photometricInterpretation: pBlackIsZero,
+			samplesPerPixel:           1,
+			bitsPerSample:             []uint32{16},
+		}
+		z.encodeRows = func(w io.Writer, pix []uint8, dx, dy, stride int, predictor bool) error {
+			return encodeGray16(w, pix, dx, dy, stride, predictor, o.byteOrder)
+		}
+	case color.RGBAModel:
+		z.format.extraSamples = 1 // Associated alpha.
+		z.encodeRows = encodeRGBA
+	case color.NRGBAModel:
+		z.format.extraSamples = 2 // Unassociated alpha.
+		z.encodeRows = encodeRGBA
+	case color.RGBA64Model:
+		bytesPerPixel = 8
+		z.format.bitsPerSample = []uint32{16, 16, 16, 16}
+		z.format.extraSamples = 1 // Associated alpha.
+		z.encodeRows = func(w io.Writer, pix []uint8, dx, dy, stride int, predictor bool) error {
+			return encodeRGBA64(w, pix, dx, dy, stride, predictor, o.byteOrder)
+		}
+	case color.NRGBA64Model:
+		bytesPerPixel = 8
+		z.format.bitsPerSample = []uint32{16, 16, 16, 16}
+		z.format.extraSamples = 2 // Unassociated alpha.
+		z.encodeRows = func(w io.Writer, pix []uint8, dx, dy, stride int, predictor bool) error {
+			return encodeRGBA64(w, pix, dx, dy, stride, predictor, o.byteOrder)
+		}
+	default:
+		p, ok := model.(color.Palette)
+		if !ok {
+			return nil, errors.New("tiff: unsupported color model")
+		}
+		bytesPerPixel = 1
+		colorMap := make([]uint32, 256*3)
+		for i := 0; i < 256 && i < len(p); i++ {
+			r, g, b, _ := p[i].RGBA()
+			colorMap[i+0*256] = uint32(r)
+			colorMap[i+1*256] = uint32(g)
+			colorMap[i+2*256] = uint32(b)
+		}
+		z.format = pixelFormat{
+			photometricInterpretation: pPaletted,
+			samplesPerPixel:           1,
+			bitsPerSample:             []uint32{8},
+			colorMap:                  colorMap,
+		}
+		z.encodeRows = encodeGray
 	}
+	z.rowLen = width * bytesPerPixel
 
-	if compression != cNone {
-		if err = dst.(io.Closer).Close(); err != nil {
-			return err
+	z.imageLen = height * z.rowLen
+	if o.bilevel {
+		if model != color.GrayModel {
+			return nil, errors.New("tiff: bilevel images need a gray color model")
 		}
-		imageLen = buf.Len()
-		if err = binary.Write(w, enc, uint32(imageLen+8)); err != nil {
-			return err
+		z.format = pixelFormat{
+			photometricInterpretation: uint32(o.photometric),
+			samplesPerPixel:           1,
+			bitsPerSample:             []uint32{1},
 		}
-		if _, err = buf.WriteTo(w); err != nil {
-			return err
+		whiteIsZero := o.photometric == WhiteIsZero
+		z.encodeRows = func(w io.Writer, pix []uint8, dx, dy, stride int, _ bool) error {
+			m := &image.Gray{Pix: pix, Stride: stride, Rect: image.Rect(0, 0, dx, dy)}
+			return encodeBilevel(w, m, whiteIsZero)
 		}
+		z.imageLen = (width + 7) / 8 * height
+	}
+	if err := o.checkSamples(z.format.bitsPerSample[0], z.format.photometricInterpretation == pPaletted); err != nil {
+		return nil, err
 	}
+	switch {
+	case o.compression == cNone:
+		z.dst = w
+	case isCCITT(o.compression):
+		z.dst = newCCITTWriter(&z.buf, o, width)
+	default:
+		z.dst = zlib.NewWriter(&z.buf)
+	}
+	return z, nil
+}
 
-	ifd := []ifdEntry{
-		{tImageWidth, dtShort, []uint32{uint32(d.X)}},
-		{tImageLength, dtShort, []uint32{uint32(d.Y)}},
-		{tBitsPerSample, dtShort, bitsPerSample},
-		{tCompression, dtShort, []uint32{compression}},
-		{tPhotometricInterpretation, dtShort, []uint32{photometricInterpretation}},
-		{tStripOffsets, dtLong, []uint32{8}},
-		{tSamplesPerPixel, dtShort, []uint32{samplesPerPixel}},
-		{tRowsPerStrip, dtShort, []uint32{uint32(d.Y)}},
-		{tStripByteCounts, dtLong, []uint32{uint32(imageLen)}},
-		// There is currently no support for storing the image
-		// resolution, so give a bogus value of 72x72 dpi.
-		{tXResolution, dtRational, []uint32{72, 1}},
-		{tYResolution, dtRational, []uint32{72, 1}},
-		{tResolutionUnit, dtShort, []uint32{resPerInch}},
+// writeHeader writes the image's header, which ends with the offset of the
+// IFD that follows the z.imageLen bytes of pixel data.
+func (z *Writer) writeHeader() error {
+	z.wroteHeader = true
+	return writeFileHeader(z.w, z.o.byteOrder, z.imageLen+8)
+}
+
+// WriteRows writes the next n rows of the image. pix holds the rows'
+// pixels, one row after another, in the layout of the Pix field of the image
+// type with the Writer's color model, so that its length is at least n times
+// the image's width times the number of bytes per pixel.
+func (z *Writer) WriteRows(pix []byte, n int) error {
+	if z.err != nil {
+		return z.err
 	}
-	if pr != prNone {
-		ifd = append(ifd, ifdEntry{tPredictor, dtShort, []uint32{pr}})
+	if n < 0 || n > z.height-z.rows || len(pix)/z.rowLen < n {
+		z.err = errors.New("tiff: invalid number of rows")
+		return z.err
 	}
-	if len(colorMap) != 0 {
-		ifd = append(ifd, ifdEntry{tColorMap, dtShort, colorMap})
+	if z.dst == z.w && !z.wroteHeader {
+		if z.err = z.writeHeader(); z.err != nil {
+			return z.err
+		}
 	}
-	if extraSamples > 0 {
-		ifd = append(ifd, ifdEntry{tExtraSamples, dtShort, []uint32{extraSamples}})
+	if z.err = z.encodeRows(z.dst, pix, z.width, n, z.rowLen, z.o.predictor); z.err != nil {
+		return z.err
 	}
+	z.rows += n
+	return nil
+}
 
-	return writeIFD(w, imageLen+8, ifd)
+// Close finishes writing the image, after every row has been written by
+// WriteRows. It does not close the underlying io.Writer.
+func (z *Writer) Close() error {
+	if z.err != nil {
+		return z.err
+	}
+	z.err = errors.New("tiff: Writer is closed")
+	if z.rows != z.height {
+		return errors.New("tiff: fewer rows written than the image height")
+	}
+	if z.dst != z.w {
+		if err := z.dst.(io.Closer).Close(); err != nil {
+			return err
+		}
+		z.imageLen = z.buf.Len()
+		if err := z.writeHeader(); err != nil {
+			return err
+		}
+		if _, err := z.buf.WriteTo(z.w); err != nil {
+			return err
+		}
+	}
+	pr := uint32(prNone)
+	if z.o.predictor {
+		pr = prHorizontal
+	}
+	d := image.Point{z.width, z.height}
+	ifd := imageIFD(d, z.o.compression, pr, stripLayout(d, 8, z.imageLen), z.format)
+	ifd = append(ifd, resolutionIFD(z.o)...)
+	ifd = append(ifd, sampleIFD(z.o, z.format)...)
+	ifd = appendExtraTags(ifd, z.o.extraTags)
+	return writeIFD(z.w, z.o.byteOrder, z.imageLen+8, ifd, 0)
 }
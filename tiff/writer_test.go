@@ -6,9 +6,13 @@ package tiff
 
 import (
 	"bytes"
+	"encoding/binary"
 	"image"
+	"image/color"
 	"io/ioutil"
+	"math"
 	"os"
+	"reflect"
 	"testing"
 )
 
@@ -25,6 +29,20 @@ var roundtripTests = []struct {
 	{"video-001.tiff", &Options{Predictor: true}},
 	{"video-001.tiff", &Options{Compression: Deflate}},
 	{"video-001.tiff", &Options{Predictor: true, Compression: Deflate}},
+	{"bw-packbits.tiff", &Options{Bilevel: true}},
+	{"bw-packbits.tiff", &Options{Bilevel: true, PhotometricInterpretation: BlackIsZero}},
+	{"bw-packbits.tiff", &Options{Bilevel: true, Compression: Deflate}},
+	{"video-001.tiff", &Options{TileSize: image.Pt(32, 16)}},
+	{"video-001.tiff", &Options{TileSize: image.Pt(64, 64), Predictor: true, Compression: Deflate}},
+	{"video-001-16bit.tiff", &Options{TileSize: image.Pt(48, 32)}},
+	{"video-001-gray.tiff", &Options{TileSize: image.Pt(16, 16), Compression: Deflate}},
+	{"video-001-gray-16bit.tiff", &Options{TileSize: image.Pt(32, 32)}},
+	{"video-001-paletted.tiff", &Options{TileSize: image.Pt(32, 32)}},
+	{"bw-packbits.tiff", &Options{TileSize: image.Pt(16, 32), Bilevel: true}},
+	{"bw-packbits.tiff", &Options{Bilevel: true, Compression: CCITTRLE}},
+	{"bw-packbits.tiff", &Options{Bilevel: true, Compression: CCITTGroup3}},
+	{"bw-packbits.tiff", &Options{Bilevel: true, Compression: CCITTGroup4, PhotometricInterpretation: BlackIsZero}},
+	{"bw-packbits.tiff", &Options{TileSize: image.Pt(32, 16), Bilevel: true, Compression: CCITTGroup4}},
 }
 
 func openImage(filename string) (image.Image, error) {
@@ -75,6 +93,451 @@ func TestRoundtrip2(t *testing.T) {
 	compare(t, m0, m1)
 }
 
+func TestEncodeBilevel(t *testing.T) {
+	// The image is 10 pixels wide, so that each row has a padding byte. Its
+	// pixels alternate between white and black, starting with white, except
+	// for the light gray and dark gray ones at the end of each row.
+	m := image.NewNRGBA(image.Rect(0, 0, 10, 2))
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 8; x++ {
+			if x%2 == 0 {
+				m.Set(x, y, color.White)
+			} else {
+				m.Set(x, y, color.Black)
+			}
+		}
+		m.Set(8, y, color.Gray{0xc0})
+		m.Set(9, y, color.Gray{0x40})
+	}
+	testCases := []struct {
+		photometric PhotometricInterpretation
+		want        []byte
+	}{
+		{WhiteIsZero, []byte{0x55, 0x40, 0x55, 0x40}},
+		{BlackIsZero, []byte{0xaa, 0x80, 0xaa, 0x80}},
+	}
+	for _, tc := range testCases {
+		out := new(bytes.Buffer)
+		if err := Encode(out, m, &Options{Bilevel: true, PhotometricInterpretation: tc.photometric}); err != nil {
+			t.Fatal(err)
+		}
+		// The pixel data immediately follows the 8 byte header.
+		if got := out.Bytes()[8:12]; !bytes.Equal(got, tc.want) {
+			t.Errorf("photometric %d: pixel data: got %#02x, want %#02x", tc.photometric, got, tc.want)
+		}
+		m1, err := Decode(bytes.NewReader(out.Bytes()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for x, want := range []uint8{0xff, 0x00, 0xff, 0x00, 0xff, 0x00, 0xff, 0x00, 0xff, 0x00} {
+			if got := color.GrayModel.Convert(m1.At(x, 1)).(color.Gray).Y; got != want {
+				t.Errorf("photometric %d: pixel at (%d, 1): got %#02x, want %#02x", tc.photometric, x, got, want)
+			}
+		}
+	}
+
+	err := Encode(ioutil.Discard, m, &Options{Bilevel: true, PhotometricInterpretation: 2})
+	if err == nil {
+		t.Error("invalid photometric interpretation: no error returned, expected an error")
+	}
+}
+
+func TestEncodeCCITT(t *testing.T) {
+	gopher, err := load("bw-gopher.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	readFile := func(filename string) []byte {
+		b, err := os.ReadFile(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+	g3, g4 := readFile("../ccitt/testdata/bw-gopher.ccitt_group3"), readFile("../ccitt/testdata/bw-gopher.ccitt_group4")
+
+	for _, tc := range []struct {
+		compression CompressionType
+		wantTag     uint16
+		wantStrip   []byte
+	}{
+		{CCITTRLE, 0, nil},
+		{CCITTGroup3, tT4Options, g3},
+		{CCITTGroup4, tT6Options, g4},
+	} {
+		for _, photometric := range []PhotometricInterpretation{WhiteIsZero, BlackIsZero} {
+			out := new(bytes.Buffer)
+			opt := &Options{Compression: tc.compression, Bilevel: true, PhotometricInterpretation: photometric}
+			if err := Encode(out, gopher, opt); err != nil {
+				t.Fatalf("%+v: Encode: %v", opt, err)
+			}
+			m, err := Decode(bytes.NewReader(out.Bytes()))
+			if err != nil {
+				t.Fatalf("%+v: Decode: %v", opt, err)
+			}
+			compare(t, gopher, m)
+
+			ifd := readTestIFD(out.Bytes())
+			if got, want := ifd[tCompression].values, []int{int(tc.compression.specValue())}; !reflect.DeepEqual(got, want) {
+				t.Errorf("%+v: Compression: got %v, want %v", opt, got, want)
+			}
+			if tc.wantTag != 0 {
+				if got, want := ifd[tc.wantTag], (testIFDEntry{dtLong, []int{0}}); !reflect.DeepEqual(got, want) {
+					t.Errorf("%+v: tag %d: got %v, want %v", opt, tc.wantTag, got, want)
+				}
+			}
+			// The CCITT data codes colors, not bits, so that it does not depend
+			// on the photometric interpretation.
+			if tc.wantStrip != nil {
+				off, n := ifd[tStripOffsets].values[0], ifd[tStripByteCounts].values[0]
+				if got := out.Bytes()[off : off+n]; !bytes.Equal(got, tc.wantStrip) {
+					t.Errorf("%+v: strip differs from the ccitt package's testdata", opt)
+				}
+			}
+		}
+	}
+
+	// CCITT compression is only for bilevel images.
+	if err := Encode(ioutil.Discard, gopher, &Options{Compression: CCITTGroup4}); err == nil {
+		t.Error("CCITTGroup4 without Bilevel: no error returned, expected an error")
+	}
+	if _, err := NewWriter(ioutil.Discard, 4, 4, color.GrayModel, &Options{Compression: CCITTGroup3}); err == nil {
+		t.Error("NewWriter: CCITTGroup3 without Bilevel: no error returned, expected an error")
+	}
+}
+
+func TestEncodeTiled(t *testing.T) {
+	// The image's origin is not (0, 0), and its size is not a multiple of
+	// the tile size.
+	m := image.NewNRGBA(image.Rect(3, 4, 3+40, 4+20))
+	for i := range m.Pix {
+		m.Pix[i] = byte(i * 7)
+	}
+	for _, tc := range []struct {
+		m   image.Image
+		opt *Options
+	}{
+		{m, &Options{TileSize: image.Pt(16, 16)}},
+		{m, &Options{TileSize: image.Pt(32, 16), Compression: Deflate}},
+		{imageWrapper{m}, &Options{TileSize: image.Pt(16, 16)}},
+		{imageWrapper{m}, &Options{TileSize: image.Pt(16, 32), Bilevel: true}},
+	} {
+		strips, tiles := new(bytes.Buffer), new(bytes.Buffer)
+		if err := Encode(strips, tc.m, &Options{Compression: tc.opt.Compression, Bilevel: tc.opt.Bilevel}); err != nil {
+			t.Fatal(err)
+		}
+		if err := Encode(tiles, tc.m, tc.opt); err != nil {
+			t.Fatal(err)
+		}
+		want, err := Decode(bytes.NewReader(strips.Bytes()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := Decode(bytes.NewReader(tiles.Bytes()))
+		if err != nil {
+			t.Fatalf("%T, %+v: Decode: %v", tc.m, *tc.opt, err)
+		}
+		compare(t, got, want)
+
+		r := bytes.NewReader(tiles.Bytes())
+		byteOrder, ifdOffset, err := readHeader(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d, err := newIFDDecoder(r, byteOrder, ifdOffset)
+		if err != nil {
+			t.Fatal(err)
+		}
+		size := tc.opt.TileSize
+		if got, want := image.Pt(int(d.firstVal(tTileWidth)), int(d.firstVal(tTileLength))), size; got != want {
+			t.Errorf("%T, %+v: tile size: got %v, want %v", tc.m, *tc.opt, got, want)
+		}
+		n := (40 + size.X - 1) / size.X * ((20 + size.Y - 1) / size.Y)
+		if got := len(d.features[tTileOffsets]); got != n {
+			t.Errorf("%T, %+v: got %d tile offsets, want %d", tc.m, *tc.opt, got, n)
+		}
+		if got := len(d.features[tTileByteCounts]); got != n {
+			t.Errorf("%T, %+v: got %d tile byte counts, want %d", tc.m, *tc.opt, got, n)
+		}
+		if _, ok := d.features[tStripOffsets]; ok {
+			t.Errorf("%T, %+v: got StripOffsets, want none", tc.m, *tc.opt)
+		}
+	}
+
+	for _, size := range []image.Point{{16, 0}, {0, 16}, {-16, 16}, {8, 16}, {16, 24}} {
+		if err := Encode(ioutil.Discard, m, &Options{TileSize: size}); err == nil {
+			t.Errorf("tile size %v: got nil error", size)
+		}
+	}
+	if _, err := NewWriter(ioutil.Discard, 40, 20, color.NRGBAModel, &Options{TileSize: image.Pt(16, 16)}); err == nil {
+		t.Error("NewWriter with tiles: got nil error")
+	}
+}
+
+func TestEncodeByteOrder(t *testing.T) {
+	extra := []Tag{
+		{ID: 305, Type: dtASCII, Count: 3, Value: []byte("Go\x00")}, // Software.
+		{ID: 33550, Type: dtDouble, Count: 1, Value: []byte{0, 0, 0, 0, 0, 0, 0xf0, 0x3f}},
+		{ID: 50000, Type: dtShort, Count: 3, Value: []byte{1, 0, 2, 0, 3, 0}},
+	}
+	for _, rt := range roundtripTests {
+		img, err := openImage(rt.filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var le, be Options
+		if rt.opts != nil {
+			le = *rt.opts
+		}
+		le.ExtraTags = extra
+		be = le
+		be.ByteOrder = binary.BigEndian
+
+		leOut, beOut := new(bytes.Buffer), new(bytes.Buffer)
+		if err := Encode(leOut, img, &le); err != nil {
+			t.Fatal(err)
+		}
+		if err := Encode(beOut, img, &be); err != nil {
+			t.Fatal(err)
+		}
+		if got := beOut.String()[:4]; got != beHeader {
+			t.Errorf("%s, %+v: header: got %q, want %q", rt.filename, be, got, beHeader)
+		}
+		if leOut.Len() != beOut.Len() {
+			t.Errorf("%s, %+v: got %d bytes, want %d", rt.filename, be, beOut.Len(), leOut.Len())
+		}
+		m, err := Decode(bytes.NewReader(beOut.Bytes()))
+		if err != nil {
+			t.Fatalf("%s, %+v: Decode: %v", rt.filename, be, err)
+		}
+		compare(t, img, m)
+		tags, err := DecodeExtraTags(bytes.NewReader(beOut.Bytes()))
+		if err != nil {
+			t.Fatalf("%s, %+v: DecodeExtraTags: %v", rt.filename, be, err)
+		}
+		if !reflect.DeepEqual(tags, extra) {
+			t.Errorf("%s, %+v: extra tags: got %v, want %v", rt.filename, be, tags, extra)
+		}
+	}
+
+	// The Writer and Encoder write big-endian files too.
+	m, err := openImage("video-001-16bit.tiff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rgba64 := m.(*image.RGBA64)
+	out := new(bytes.Buffer)
+	opt := &Options{ByteOrder: binary.BigEndian, Compression: Deflate, Predictor: true}
+	z, err := NewWriter(out, rgba64.Rect.Dx(), rgba64.Rect.Dy(), color.RGBA64Model, opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := z.WriteRows(rgba64.Pix, rgba64.Rect.Dy()); err != nil {
+		t.Fatal(err)
+	}
+	if err := z.Close(); err != nil {
+		t.Fatal(err)
+	}
+	m1, err := Decode(bytes.NewReader(out.Bytes()))
+	if err != nil {
+		t.Fatalf("Writer: Decode: %v", err)
+	}
+	compare(t, m, m1)
+
+	out.Reset()
+	e := NewEncoder(out)
+	for i := 0; i < 2; i++ {
+		if err := e.Encode(m, opt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := e.Encode(m, nil); err == nil {
+		t.Error("Encoder: little-endian page after big-endian pages: got nil error")
+	}
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+	pages, err := DecodeAll(bytes.NewReader(out.Bytes()))
+	if err != nil {
+		t.Fatalf("Encoder: DecodeAll: %v", err)
+	}
+	if len(pages) != 2 {
+		t.Fatalf("Encoder: got %d pages, want 2", len(pages))
+	}
+	for _, p := range pages {
+		compare(t, m, p.Image)
+	}
+}
+
+// testIFDEntry is an IFD entry whose values are integers.
+type testIFDEntry struct {
+	datatype uint16
+	values   []int
+}
+
+// readTestIFD returns the integer entries of the first IFD of the TIFF file b.
+func readTestIFD(b []byte) map[uint16]testIFDEntry {
+	var bo binary.ByteOrder = binary.LittleEndian
+	if string(b[:4]) == beHeader {
+		bo = binary.BigEndian
+	}
+	off := bo.Uint32(b[4:8])
+	entries := map[uint16]testIFDEntry{}
+	for i, n := uint32(0), uint32(bo.Uint16(b[off:])); i < n; i++ {
+		e := b[off+2+ifdLen*i:]
+		tag, datatype, count := bo.Uint16(e[0:2]), bo.Uint16(e[2:4]), bo.Uint32(e[4:8])
+		p := e[8:12]
+		if count*lengths[datatype] > 4 {
+			p = b[bo.Uint32(e[8:12]):]
+		}
+		var values []int
+		for j := 0; j < int(count); j++ {
+			switch datatype {
+			case dtByte:
+				values = append(values, int(p[j]))
+			case dtSByte:
+				values = append(values, int(int8(p[j])))
+			case dtShort:
+				values = append(values, int(bo.Uint16(p[2*j:])))
+			case dtSShort:
+				values = append(values, int(int16(bo.Uint16(p[2*j:]))))
+			case dtLong:
+				values = append(values, int(bo.Uint32(p[4*j:])))
+			}
+		}
+		entries[tag] = testIFDEntry{datatype, values}
+	}
+	return entries
+}
+
+func TestEncodeSampleFormat(t *testing.T) {
+	gray16 := image.NewGray16(image.Rect(0, 0, 3, 1))
+	for i, v := range []int16{-100, 0, 200} {
+		gray16.SetGray16(i, 0, color.Gray16{uint16(v)})
+	}
+	gray := image.NewGray(image.Rect(0, 0, 3, 1))
+	rgba64 := image.NewRGBA64(image.Rect(0, 0, 3, 1))
+
+	testCases := []struct {
+		desc string
+		m    image.Image
+		opt  *Options
+		want map[uint16]testIFDEntry
+	}{{
+		"signed gray16",
+		gray16,
+		&Options{SampleFormat: SignedInteger, MinSampleValue: -100, MaxSampleValue: 200},
+		map[uint16]testIFDEntry{
+			tSampleFormat:    {dtShort, []int{sfInt}},
+			tSMinSampleValue: {dtSShort, []int{-100}},
+			tSMaxSampleValue: {dtSShort, []int{200}},
+		},
+	}, {
+		"signed gray16, big-endian",
+		gray16,
+		&Options{SampleFormat: SignedInteger, MinSampleValue: -32768, MaxSampleValue: 32767, ByteOrder: binary.BigEndian},
+		map[uint16]testIFDEntry{
+			tSampleFormat:    {dtShort, []int{sfInt}},
+			tSMinSampleValue: {dtSShort, []int{-32768}},
+			tSMaxSampleValue: {dtSShort, []int{32767}},
+		},
+	}, {
+		"signed gray without range",
+		gray,
+		&Options{SampleFormat: SignedInteger},
+		map[uint16]testIFDEntry{
+			tSampleFormat: {dtShort, []int{sfInt}},
+		},
+	}, {
+		"signed gray",
+		gray,
+		&Options{SampleFormat: SignedInteger, MinSampleValue: -128, MaxSampleValue: 127},
+		map[uint16]testIFDEntry{
+			tSampleFormat:    {dtShort, []int{sfInt}},
+			tSMinSampleValue: {dtSByte, []int{-128}},
+			tSMaxSampleValue: {dtSByte, []int{127}},
+		},
+	}, {
+		"unsigned rgba64",
+		rgba64,
+		&Options{MinSampleValue: 16, MaxSampleValue: 4095},
+		map[uint16]testIFDEntry{
+			tSMinSampleValue: {dtShort, []int{16, 16, 16, 16}},
+			tSMaxSampleValue: {dtShort, []int{4095, 4095, 4095, 4095}},
+			tMinSampleValue:  {dtShort, []int{16, 16, 16, 16}},
+			tMaxSampleValue:  {dtShort, []int{4095, 4095, 4095, 4095}},
+		},
+	}, {
+		"unsigned gray",
+		gray,
+		&Options{MinSampleValue: 0, MaxSampleValue: 255},
+		map[uint16]testIFDEntry{
+			tSMinSampleValue: {dtByte, []int{0}},
+			tSMaxSampleValue: {dtByte, []int{255}},
+			tMinSampleValue:  {dtShort, []int{0}},
+			tMaxSampleValue:  {dtShort, []int{255}},
+		},
+	}}
+	for _, tc := range testCases {
+		out := new(bytes.Buffer)
+		if err := Encode(out, tc.m, tc.opt); err != nil {
+			t.Fatalf("%s: %v", tc.desc, err)
+		}
+		entries := readTestIFD(out.Bytes())
+		for _, tag := range []uint16{tSampleFormat, tSMinSampleValue, tSMaxSampleValue, tMinSampleValue, tMaxSampleValue} {
+			got, ok := entries[tag]
+			want, wantOK := tc.want[tag]
+			if ok != wantOK || !reflect.DeepEqual(got, want) {
+				t.Errorf("%s: tag %d: got %v (%t), want %v (%t)", tc.desc, tag, got, ok, want, wantOK)
+			}
+		}
+	}
+
+	// The samples are written unchanged, after the 8 byte header.
+	out := new(bytes.Buffer)
+	if err := Encode(out, gray16, &Options{SampleFormat: SignedInteger, ByteOrder: binary.BigEndian}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := out.Bytes()[8:14], gray16.Pix; !bytes.Equal(got, want) {
+		t.Errorf("signed samples: got %#02x, want %#02x", got, want)
+	}
+
+	// The unsigned range is read by DecodeWithOptions.
+	rgba64.SetRGBA64(0, 0, color.RGBA64{4095, 0, 2048, 4095})
+	out.Reset()
+	if err := Encode(out, rgba64, &Options{MaxSampleValue: 4095}); err != nil {
+		t.Fatal(err)
+	}
+	m, err := DecodeWithOptions(bytes.NewReader(out.Bytes()), &DecodeOptions{UseMaxSampleValue: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := m.At(0, 0), (color.RGBA64{0xffff, 0, 0x8008, 0xffff}); got != want {
+		t.Errorf("scaled sample: got %v, want %v", got, want)
+	}
+
+	paletted := image.NewPaletted(image.Rect(0, 0, 3, 1), color.Palette{color.Black, color.White})
+	for _, tc := range []struct {
+		desc string
+		m    image.Image
+		opt  *Options
+	}{
+		{"signed paletted", paletted, &Options{SampleFormat: SignedInteger}},
+		{"signed bilevel", gray, &Options{SampleFormat: SignedInteger, Bilevel: true}},
+		{"unsupported sample format", gray, &Options{SampleFormat: 3}},
+		{"unsigned range", gray, &Options{MinSampleValue: 0, MaxSampleValue: 256}},
+		{"negative unsigned range", gray16, &Options{MinSampleValue: -1, MaxSampleValue: 10}},
+		{"signed range", gray16, &Options{SampleFormat: SignedInteger, MinSampleValue: -32769, MaxSampleValue: 0}},
+	} {
+		if err := Encode(ioutil.Discard, tc.m, tc.opt); err == nil {
+			t.Errorf("%s: got nil error", tc.desc)
+		}
+	}
+	if _, err := NewWriter(ioutil.Discard, 3, 1, color.GrayModel, &Options{SampleFormat: SignedInteger, MinSampleValue: -1, MaxSampleValue: 128}); err == nil {
+		t.Error("NewWriter: signed range: got nil error")
+	}
+}
+
 func TestUnsupported(t *testing.T) {
 	img := image.NewGray(image.Rect(0, 0, 1, 1))
 	out := new(bytes.Buffer)
@@ -84,6 +547,209 @@ func TestUnsupported(t *testing.T) {
 	}
 }
 
+// imageWrapper hides the concrete type of an image.Image, so that Encode
+// cannot use its Pix field.
+type imageWrapper struct {
+	image.Image
+}
+
+func TestRoundtrip16Bit(t *testing.T) {
+	// The samples use all 16 bits, which must all survive the round trip.
+	rect := image.Rect(0, 0, 13, 5)
+	gray16 := image.NewGray16(rect)
+	rgba64 := image.NewRGBA64(rect)
+	nrgba64 := image.NewNRGBA64(rect)
+	for y := 0; y < rect.Dy(); y++ {
+		for x := 0; x < rect.Dx(); x++ {
+			v := uint16(x*4999 + y*307)
+			gray16.SetGray16(x, y, color.Gray16{v})
+			rgba64.SetRGBA64(x, y, color.RGBA64{v / 3, v / 2, v / 5, v})
+			nrgba64.SetNRGBA64(x, y, color.NRGBA64{v, 0xffff - v, v ^ 0x5a5a, uint16(x * 3)})
+		}
+	}
+	testCases := []struct {
+		m   image.Image
+		pix []byte
+	}{
+		{gray16, gray16.Pix},
+		{rgba64, rgba64.Pix},
+		{nrgba64, nrgba64.Pix},
+		{imageWrapper{gray16}, gray16.Pix},
+		{imageWrapper{rgba64}, rgba64.Pix},
+		{imageWrapper{nrgba64}, nrgba64.Pix},
+		{nrgba64.SubImage(image.Rect(0, 0, 13, 5)), nrgba64.Pix},
+	}
+	for _, tc := range testCases {
+		for _, opt := range []*Options{nil, {Compression: Deflate, Predictor: true}} {
+			buf := new(bytes.Buffer)
+			if err := Encode(buf, tc.m, opt); err != nil {
+				t.Fatalf("%T: Encode: %v", tc.m, err)
+			}
+			m, err := Decode(buf)
+			if err != nil {
+				t.Fatalf("%T: Decode: %v", tc.m, err)
+			}
+			var pix []byte
+			switch m := m.(type) {
+			case *image.Gray16:
+				pix = m.Pix
+			case *image.RGBA64:
+				pix = m.Pix
+			case *image.NRGBA64:
+				pix = m.Pix
+			}
+			if got, want := m.ColorModel(), tc.m.ColorModel(); got != want {
+				t.Errorf("%T, %+v: got color model %v, want %v", tc.m, opt, got, want)
+			} else if !bytes.Equal(pix, tc.pix) {
+				t.Errorf("%T, %+v: pixels differ", tc.m, opt)
+			}
+		}
+	}
+}
+
+func TestWriter(t *testing.T) {
+	const w, h = 10, 7
+	rect := image.Rect(0, 0, w, h)
+	gray := image.NewGray(rect)
+	for i := range gray.Pix {
+		gray.Pix[i] = uint8(i * 37)
+	}
+	gray16 := image.NewGray16(rect)
+	rgba := image.NewRGBA(rect)
+	nrgba := image.NewNRGBA(rect)
+	rgba64 := image.NewRGBA64(rect)
+	nrgba64 := image.NewNRGBA64(rect)
+	paletted := image.NewPaletted(rect, color.Palette{color.Black, color.White, color.RGBA{0xff, 0, 0, 0xff}})
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := uint8(x*25 + y*3)
+			gray16.SetGray16(x, y, color.Gray16{uint16(v) * 0x101})
+			rgba.SetRGBA(x, y, color.RGBA{v / 2, v / 4, v / 8, 0x80})
+			nrgba.SetNRGBA(x, y, color.NRGBA{v, 0xff - v, v / 2, v})
+			rgba64.SetRGBA64(x, y, color.RGBA64{uint16(v) * 0x80, 0x1234, 0, 0xffff})
+			nrgba64.SetNRGBA64(x, y, color.NRGBA64{uint16(v) * 0x101, 0xffff, 0x4321, uint16(v) * 0x100})
+			paletted.SetColorIndex(x, y, uint8(x+y)%3)
+		}
+	}
+	testCases := []struct {
+		m     image.Image
+		pix   []byte
+		model color.Model
+	}{
+		{gray, gray.Pix, color.GrayModel},
+		{gray16, gray16.Pix, color.Gray16Model},
+		{rgba, rgba.Pix, color.RGBAModel},
+		{nrgba, nrgba.Pix, color.NRGBAModel},
+		{rgba64, rgba64.Pix, color.RGBA64Model},
+		{nrgba64, nrgba64.Pix, color.NRGBA64Model},
+		{paletted, paletted.Pix, paletted.Palette},
+	}
+	options := []*Options{
+		nil,
+		{Compression: Deflate},
+		{Compression: Deflate, Predictor: true},
+		{ExtraTags: []Tag{{ID: 305, Type: 2, Count: 5, Value: []byte("test\x00")}}},
+	}
+	for _, tc := range testCases {
+		for _, opt := range options {
+			want := new(bytes.Buffer)
+			if err := Encode(want, tc.m, opt); err != nil {
+				t.Fatalf("%T: Encode: %v", tc.m, err)
+			}
+			got := new(bytes.Buffer)
+			z, err := NewWriter(got, w, h, tc.model, opt)
+			if err != nil {
+				t.Fatalf("%T: NewWriter: %v", tc.m, err)
+			}
+			// Write the rows in batches of 1, 2 and 4 rows.
+			rowLen := len(tc.pix) / h
+			for y, n := 0, 1; y < h; y, n = y+n, n+1 {
+				if y+n > h {
+					n = h - y
+				}
+				if err := z.WriteRows(tc.pix[y*rowLen:], n); err != nil {
+					t.Fatalf("%T: WriteRows: %v", tc.m, err)
+				}
+			}
+			if err := z.Close(); err != nil {
+				t.Fatalf("%T: Close: %v", tc.m, err)
+			}
+			if !bytes.Equal(got.Bytes(), want.Bytes()) {
+				t.Errorf("%T, %+v: Writer and Encode output differ", tc.m, opt)
+			}
+		}
+	}
+
+	// A bilevel image.
+	for _, opt := range []*Options{
+		{Bilevel: true},
+		{Bilevel: true, Compression: Deflate, PhotometricInterpretation: BlackIsZero},
+		{Bilevel: true, Compression: CCITTGroup4},
+	} {
+		want := new(bytes.Buffer)
+		if err := Encode(want, gray, opt); err != nil {
+			t.Fatalf("bilevel: Encode: %v", err)
+		}
+		got := new(bytes.Buffer)
+		z, err := NewWriter(got, w, h, color.GrayModel, opt)
+		if err != nil {
+			t.Fatalf("bilevel: NewWriter: %v", err)
+		}
+		if err := z.WriteRows(gray.Pix, 3); err != nil {
+			t.Fatalf("bilevel: WriteRows: %v", err)
+		}
+		if err := z.WriteRows(gray.Pix[3*w:], 4); err != nil {
+			t.Fatalf("bilevel: WriteRows: %v", err)
+		}
+		if err := z.Close(); err != nil {
+			t.Fatalf("bilevel: Close: %v", err)
+		}
+		if !bytes.Equal(got.Bytes(), want.Bytes()) {
+			t.Errorf("bilevel, %+v: Writer and Encode output differ", opt)
+		}
+	}
+}
+
+func TestWriterErrors(t *testing.T) {
+	if _, err := NewWriter(ioutil.Discard, 4, 4, color.CMYKModel, nil); err == nil {
+		t.Error("CMYK model: no error returned, expected an error")
+	}
+	if _, err := NewWriter(ioutil.Discard, 4, 4, color.RGBAModel, &Options{Bilevel: true}); err == nil {
+		t.Error("bilevel RGBA model: no error returned, expected an error")
+	}
+	if _, err := NewWriter(ioutil.Discard, 0, 4, color.GrayModel, nil); err == nil {
+		t.Error("zero width: no error returned, expected an error")
+	}
+
+	pix := make([]byte, 4*4)
+	z, err := NewWriter(ioutil.Discard, 4, 4, color.GrayModel, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := z.WriteRows(pix[:7], 2); err == nil {
+		t.Error("short pix: no error returned, expected an error")
+	}
+
+	z, err = NewWriter(ioutil.Discard, 4, 4, color.GrayModel, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := z.WriteRows(pix, 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := z.Close(); err == nil {
+		t.Error("Close after 3 of 4 rows: no error returned, expected an error")
+	}
+
+	z, err = NewWriter(ioutil.Discard, 4, 2, color.GrayModel, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := z.WriteRows(pix, 3); err == nil {
+		t.Error("3 rows of 2: no error returned, expected an error")
+	}
+}
+
 func benchmarkEncode(b *testing.B, name string, pixelSize int) {
 	b.Helper()
 	img, err := openImage(name)
@@ -104,3 +770,130 @@ func BenchmarkEncodeGray(b *testing.B)     { benchmarkEncode(b, "video-001-gray.
 func BenchmarkEncodeGray16(b *testing.B)   { benchmarkEncode(b, "video-001-gray-16bit.tiff", 2) }
 func BenchmarkEncodeRGBA(b *testing.B)     { benchmarkEncode(b, "video-001.tiff", 4) }
 func BenchmarkEncodeRGBA64(b *testing.B)   { benchmarkEncode(b, "video-001-16bit.tiff", 8) }
+
+func TestEncodeResolution(t *testing.T) {
+	m := image.NewGray(image.Rect(0, 0, 3, 2))
+	testCases := []struct {
+		opts     *Options
+		wantX    float64
+		wantY    float64
+		wantUnit ResolutionUnit
+		wantErr  bool
+	}{
+		{nil, 72, 72, Inch, false},
+		{&Options{ResolutionUnit: Centimeter}, 72, 72, Inch, false},
+		{&Options{XResolution: 300}, 300, 300, Inch, false},
+		{&Options{YResolution: 600}, 600, 600, Inch, false},
+		{&Options{XResolution: 600, YResolution: 300}, 600, 300, Inch, false},
+		{&Options{XResolution: 118.11, ResolutionUnit: Centimeter}, 118.11, 118.11, Centimeter, false},
+		{&Options{XResolution: 1, YResolution: 2, ResolutionUnit: NoUnit}, 1, 2, NoUnit, false},
+		{&Options{XResolution: -300}, 0, 0, Inch, true},
+		{&Options{XResolution: math.Inf(1)}, 0, 0, Inch, true},
+		{&Options{XResolution: math.NaN()}, 0, 0, Inch, true},
+		{&Options{XResolution: 300, ResolutionUnit: NoUnit + 1}, 0, 0, Inch, true},
+	}
+	for _, tc := range testCases {
+		var buf bytes.Buffer
+		err := Encode(&buf, m, tc.opts)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("%+v: Encode: got nil error, want non-nil", tc.opts)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%+v: Encode: %v", tc.opts, err)
+			continue
+		}
+		x, y, unit, err := DecodeResolution(bytes.NewReader(buf.Bytes()))
+		if err != nil || x != tc.wantX || y != tc.wantY || unit != tc.wantUnit {
+			t.Errorf("%+v: DecodeResolution: got %v, %v, %v, %v, want %v, %v, %v, <nil>",
+				tc.opts, x, y, unit, err, tc.wantX, tc.wantY, tc.wantUnit)
+		}
+
+		// The Writer writes the same resolution.
+		buf.Reset()
+		w, err := NewWriter(&buf, 3, 2, color.GrayModel, tc.opts)
+		if err != nil {
+			t.Fatalf("%+v: NewWriter: %v", tc.opts, err)
+		}
+		if err := w.WriteRows(m.Pix, 2); err != nil {
+			t.Fatalf("%+v: WriteRows: %v", tc.opts, err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("%+v: Close: %v", tc.opts, err)
+		}
+		if x, y, unit, err := DecodeResolution(bytes.NewReader(buf.Bytes())); err != nil || x != tc.wantX || y != tc.wantY || unit != tc.wantUnit {
+			t.Errorf("%+v: Writer: got %v, %v, %v, %v, want %v, %v, %v, <nil>",
+				tc.opts, x, y, unit, err, tc.wantX, tc.wantY, tc.wantUnit)
+		}
+	}
+}
+
+func TestDecodeResolutionPages(t *testing.T) {
+	m := image.NewGray(image.Rect(0, 0, 3, 2))
+	var buf bytes.Buffer
+	e := NewEncoder(&buf)
+	opts := []*Options{
+		{XResolution: 300},
+		{XResolution: 200, YResolution: 100, ResolutionUnit: Centimeter},
+	}
+	for _, o := range opts {
+		if err := e.Encode(m, o); err != nil {
+			t.Fatalf("Encode: %v", err)
+		}
+	}
+	if err := e.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	pages, err := DecodeAll(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("DecodeAll: %v", err)
+	}
+	want := [][3]interface{}{{300.0, 300.0, Inch}, {200.0, 100.0, Centimeter}}
+	for i, p := range pages {
+		if got := [3]interface{}{p.XResolution, p.YResolution, p.ResolutionUnit}; got != want[i] {
+			t.Errorf("page %d: got %v, want %v", i, got, want[i])
+		}
+	}
+
+	// Reencode keeps the first page's resolution, unless it is given one.
+	var out bytes.Buffer
+	if err := Reencode(&out, bytes.NewReader(buf.Bytes()), &Options{Compression: Deflate}); err != nil {
+		t.Fatalf("Reencode: %v", err)
+	}
+	if x, y, unit, err := DecodeResolution(bytes.NewReader(out.Bytes())); err != nil || x != 300 || y != 300 || unit != Inch {
+		t.Errorf("Reencode: got %v, %v, %v, %v, want 300, 300, Inch, <nil>", x, y, unit, err)
+	}
+	out.Reset()
+	if err := Reencode(&out, bytes.NewReader(buf.Bytes()), &Options{XResolution: 50, ResolutionUnit: Centimeter}); err != nil {
+		t.Fatalf("Reencode: %v", err)
+	}
+	if x, y, unit, err := DecodeResolution(bytes.NewReader(out.Bytes())); err != nil || x != 50 || y != 50 || unit != Centimeter {
+		t.Errorf("Reencode with a resolution: got %v, %v, %v, %v, want 50, 50, Centimeter, <nil>", x, y, unit, err)
+	}
+}
+
+func TestToRational(t *testing.T) {
+	testCases := []struct {
+		v    float64
+		want [2]uint32
+		ok   bool
+	}{
+		{300, [2]uint32{300, 1}, true},
+		{0.5, [2]uint32{1, 2}, true},
+		{118.11, [2]uint32{11811, 100}, true},
+		{1.0 / 3, [2]uint32{1, 3}, true},
+		{math.MaxUint32, [2]uint32{math.MaxUint32, 1}, true},
+		{0, [2]uint32{}, false},
+		{-1, [2]uint32{}, false},
+		{1e10, [2]uint32{}, false},
+		{1e-10, [2]uint32{0, 1}, false},
+		{math.NaN(), [2]uint32{}, false},
+	}
+	for _, tc := range testCases {
+		if got, ok := toRational(tc.v); got != tc.want || ok != tc.ok {
+			t.Errorf("toRational(%v): got %v, %t, want %v, %t", tc.v, got, ok, tc.want, tc.ok)
+		}
+	}
+}
@@ -0,0 +1,392 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vector
+
+import (
+	"math"
+	"sort"
+)
+
+// Union returns a path that fills the area that is filled by p, by q or by
+// both.
+//
+// Like the other boolean operations, Intersection and Difference, it uses the
+// non-zero winding rule, as a Rasterizer does, and implicitly closes open
+// subpaths, as fills do. The returned path consists of closed polygons: curves
+// are flattened into line segments, as finely as a Rasterizer flattens them,
+// and coordinates are rounded to 1/256th of a unit. Its subpaths wind
+// consistently, so that every point that it fills has a winding number of 1.
+//
+// The running time is quadratic in the number of line segments of p and q,
+// which suits the shapes of user interfaces, such as clipping regions and
+// punch-out masks, but not very large paths.
+func (p *Path) Union(q *Path) *Path {
+	return booleanOp(p, q, func(inP, inQ bool) bool { return inP || inQ })
+}
+
+// Intersection returns a path that fills the area that is filled by both p
+// and q. See Union for details.
+func (p *Path) Intersection(q *Path) *Path {
+	return booleanOp(p, q, func(inP, inQ bool) bool { return inP && inQ })
+}
+
+// Difference returns a path that fills the area that is filled by p but not
+// by q. See Union for details.
+func (p *Path) Difference(q *Path) *Path {
+	return booleanOp(p, q, func(inP, inQ bool) bool { return inP && !inQ })
+}
+
+// booleanGrid is the inverse of the grid spacing that a boolean operation's
+// coordinates are rounded to. Rounding makes the end points of split edges,
+// and of the edges that overlap them, compare equal.
+const booleanGrid = 256
+
+// bPoint is a point, rounded to the grid.
+type bPoint struct {
+	x, y float64
+}
+
+func snap(x, y float32) bPoint {
+	return bPoint{
+		math.Round(float64(x)*booleanGrid) / booleanGrid,
+		math.Round(float64(y)*booleanGrid) / booleanGrid,
+	}
+}
+
+// less orders points by y and then by x.
+func (a bPoint) less(b bPoint) bool {
+	return a.y < b.y || (a.y == b.y && a.x < b.x)
+}
+
+// bEdge is a line segment of one of a boolean operation's operands.
+type bEdge struct {
+	p0, p1 bPoint
+	// operand is 0 for the first operand and 1 for the second.
+	operand int
+	// splits are the points, strictly between p0 and p1, where other edges
+	// intersect this one.
+	splits []bPoint
+}
+
+// bGroup is a set of coincident edges, after splitting, whose end points are
+// lo and hi, with lo.less(hi). Its winding is the sum, for each operand, of
+// the edges' directions: +1 for an edge from lo to hi and -1 for one from hi
+// to lo.
+type bGroup struct {
+	lo, hi  bPoint
+	winding [2]int
+}
+
+// booleanOp returns the boolean combination of p and q whose filled area
+// holds the points for which f returns true, given whether p and q fill them.
+//
+// The operands' edges are split wherever they intersect, and then coincident
+// edges are merged. Each remaining edge lies on the boundary of the result if
+// f gives different results on its two sides, in which case it is directed to
+// have the result's inside on its left. Finally, those edges are joined into
+// closed subpaths.
+func booleanOp(p, q *Path, f func(inP, inQ bool) bool) *Path {
+	edges := appendBooleanEdges(nil, p, 0)
+	edges = appendBooleanEdges(edges, q, 1)
+	splitBooleanEdges(edges)
+	groups := groupBooleanEdges(edges)
+
+	// The winding numbers are computed along a ray in the +x direction. A
+	// ray at exactly a vertex's y coordinate counts the edges that span
+	// [y, y+ε), or, with below false, (y-ε, y].
+	windingAt := func(m bPoint, self int, below bool) (w [2]int) {
+		for i := range groups {
+			g := &groups[i]
+			if i == self {
+				continue
+			}
+			if below {
+				if m.y < g.lo.y || g.hi.y <= m.y {
+					continue
+				}
+			} else if m.y <= g.lo.y || g.hi.y < m.y {
+				continue
+			}
+			x := g.lo.x + (m.y-g.lo.y)*(g.hi.x-g.lo.x)/(g.hi.y-g.lo.y)
+			if x > m.x {
+				w[0] += g.winding[0]
+				w[1] += g.winding[1]
+			}
+		}
+		return w
+	}
+	in := func(w [2]int) bool {
+		return f(w[0] != 0, w[1] != 0)
+	}
+
+	var result []bEdge
+	for i := range groups {
+		g := &groups[i]
+		m := bPoint{(g.lo.x + g.hi.x) / 2, (g.lo.y + g.hi.y) / 2}
+		// inLeft and inRight are whether the result fills the left and right
+		// side of the edge from lo to hi, where the y axis points up.
+		var inLeft, inRight bool
+		if g.lo.y == g.hi.y {
+			// The edge is horizontal, and goes in the +x direction.
+			inLeft, inRight = in(windingAt(m, i, true)), in(windingAt(m, i, false))
+		} else {
+			// The edge goes in the +y direction, and adds its winding to the
+			// points to its left, whose rays cross it.
+			w := windingAt(m, i, true)
+			inRight = in(w)
+			w[0] += g.winding[0]
+			w[1] += g.winding[1]
+			inLeft = in(w)
+		}
+		if inLeft && !inRight {
+			result = append(result, bEdge{p0: g.lo, p1: g.hi})
+		} else if inRight && !inLeft {
+			result = append(result, bEdge{p0: g.hi, p1: g.lo})
+		}
+	}
+	return joinBooleanEdges(result)
+}
+
+// appendBooleanEdges appends the edges of p's subpaths, implicitly closed and
+// with their curves flattened, to edges.
+func appendBooleanEdges(edges []bEdge, p *Path, operand int) []bEdge {
+	var firstX, firstY, penX, penY float32
+	lineTo := func(x, y float32) {
+		if a, b := snap(penX, penY), snap(x, y); a != b {
+			edges = append(edges, bEdge{p0: a, p1: b, operand: operand})
+		}
+		penX, penY = x, y
+	}
+	for i := range p.Segments {
+		s := &p.Segments[i]
+		switch s.Op {
+		case PathOpMoveTo:
+			lineTo(firstX, firstY)
+			firstX, firstY = s.Args[0][0], s.Args[0][1]
+			penX, penY = firstX, firstY
+		case PathOpLineTo:
+			lineTo(s.Args[0][0], s.Args[0][1])
+		case PathOpQuadTo:
+			// This flattens the curve as Rasterizer.QuadTo does.
+			ax, ay := penX, penY
+			bx, by := s.Args[0][0], s.Args[0][1]
+			cx, cy := s.Args[1][0], s.Args[1][1]
+			devsq := devSquared(ax, ay, bx, by, cx, cy)
+			if devsq >= 0.333 {
+				const tol = 3
+				n := 1 + int(math.Sqrt(math.Sqrt(tol*float64(devsq))))
+				t, nInv := float32(0), 1/float32(n)
+				for i := 0; i < n-1; i++ {
+					t += nInv
+					abx, aby := lerp(t, ax, ay, bx, by)
+					bcx, bcy := lerp(t, bx, by, cx, cy)
+					lineTo(lerp(t, abx, aby, bcx, bcy))
+				}
+			}
+			lineTo(cx, cy)
+		case PathOpCubeTo:
+			// This flattens the curve as Rasterizer.CubeTo does.
+			ax, ay := penX, penY
+			bx, by := s.Args[0][0], s.Args[0][1]
+			cx, cy := s.Args[1][0], s.Args[1][1]
+			dx, dy := s.Args[2][0], s.Args[2][1]
+			devsq := devSquared(ax, ay, bx, by, dx, dy)
+			if devsqAlt := devSquared(ax, ay, cx, cy, dx, dy); devsq < devsqAlt {
+				devsq = devsqAlt
+			}
+			if devsq >= 0.333 {
+				const tol = 3
+				n := 1 + int(math.Sqrt(math.Sqrt(tol*float64(devsq))))
+				t, nInv := float32(0), 1/float32(n)
+				for i := 0; i < n-1; i++ {
+					t += nInv
+					abx, aby := lerp(t, ax, ay, bx, by)
+					bcx, bcy := lerp(t, bx, by, cx, cy)
+					cdx, cdy := lerp(t, cx, cy, dx, dy)
+					abcx, abcy := lerp(t, abx, aby, bcx, bcy)
+					bcdx, bcdy := lerp(t, bcx, bcy, cdx, cdy)
+					lineTo(lerp(t, abcx, abcy, bcdx, bcdy))
+				}
+			}
+			lineTo(dx, dy)
+		case PathOpClose:
+			lineTo(firstX, firstY)
+		}
+	}
+	lineTo(firstX, firstY)
+	return edges
+}
+
+func cross(ax, ay, bx, by float64) float64 {
+	return ax*by - ay*bx
+}
+
+// splitBooleanEdges records, in each edge's splits, the points where the
+// other edges intersect it, touch it or, if collinear, start or end on it.
+func splitBooleanEdges(edges []bEdge) {
+	// Sort the edges by their smallest x coordinate, so that the inner loop
+	// below can stop at the first edge to the right of edges[i].
+	minX := func(e *bEdge) float64 { return math.Min(e.p0.x, e.p1.x) }
+	sort.SliceStable(edges, func(i, j int) bool { return minX(&edges[i]) < minX(&edges[j]) })
+
+	// addSplit records that c lies on e, if it is not one of e's end points.
+	addSplit := func(e *bEdge, c bPoint) {
+		if c != e.p0 && c != e.p1 {
+			e.splits = append(e.splits, c)
+		}
+	}
+	// within returns whether c, which is collinear with e, lies on e.
+	within := func(e *bEdge, c bPoint) bool {
+		dx, dy := e.p1.x-e.p0.x, e.p1.y-e.p0.y
+		t := (c.x-e.p0.x)*dx + (c.y-e.p0.y)*dy
+		return 0 < t && t < dx*dx+dy*dy
+	}
+
+	for i := range edges {
+		a := &edges[i]
+		aMaxX := math.Max(a.p0.x, a.p1.x)
+		aMinY, aMaxY := math.Min(a.p0.y, a.p1.y), math.Max(a.p0.y, a.p1.y)
+		for j := i + 1; j < len(edges) && minX(&edges[j]) <= aMaxX; j++ {
+			b := &edges[j]
+			if math.Max(b.p0.y, b.p1.y) < aMinY || aMaxY < math.Min(b.p0.y, b.p1.y) {
+				continue
+			}
+			adx, ady := a.p1.x-a.p0.x, a.p1.y-a.p0.y
+			bdx, bdy := b.p1.x-b.p0.x, b.p1.y-b.p0.y
+			ox, oy := b.p0.x-a.p0.x, b.p0.y-a.p0.y
+			den := cross(adx, ady, bdx, bdy)
+			if den == 0 {
+				if cross(ox, oy, adx, ady) != 0 {
+					// The edges are parallel, but not collinear.
+					continue
+				}
+				for _, c := range [2]bPoint{b.p0, b.p1} {
+					if within(a, c) {
+						addSplit(a, c)
+					}
+				}
+				for _, c := range [2]bPoint{a.p0, a.p1} {
+					if within(b, c) {
+						addSplit(b, c)
+					}
+				}
+				continue
+			}
+			t := cross(ox, oy, bdx, bdy) / den
+			u := cross(ox, oy, adx, ady) / den
+			if t < 0 || 1 < t || u < 0 || 1 < u {
+				continue
+			}
+			c := snap(float32(a.p0.x+t*adx), float32(a.p0.y+t*ady))
+			addSplit(a, c)
+			addSplit(b, c)
+		}
+	}
+}
+
+// groupBooleanEdges splits the edges at their splits, and merges the
+// resultant coincident edges into groups.
+func groupBooleanEdges(edges []bEdge) []bGroup {
+	var groups []bGroup
+	index := map[[2]bPoint]int{}
+	add := func(p0, p1 bPoint, operand int) {
+		if p0 == p1 {
+			return
+		}
+		dir := 1
+		if p1.less(p0) {
+			p0, p1, dir = p1, p0, -1
+		}
+		k := [2]bPoint{p0, p1}
+		i, ok := index[k]
+		if !ok {
+			i = len(groups)
+			index[k] = i
+			groups = append(groups, bGroup{lo: p0, hi: p1})
+		}
+		groups[i].winding[operand] += dir
+	}
+
+	for i := range edges {
+		e := &edges[i]
+		dx, dy := e.p1.x-e.p0.x, e.p1.y-e.p0.y
+		dist := func(c bPoint) float64 { return (c.x-e.p0.x)*dx + (c.y-e.p0.y)*dy }
+		sort.Slice(e.splits, func(i, j int) bool { return dist(e.splits[i]) < dist(e.splits[j]) })
+		prev := e.p0
+		for _, c := range e.splits {
+			add(prev, c, e.operand)
+			prev = c
+		}
+		add(prev, e.p1, e.operand)
+	}
+	return groups
+}
+
+// joinBooleanEdges joins directed edges, which enter and leave each vertex
+// equally often, into closed subpaths.
+func joinBooleanEdges(edges []bEdge) *Path {
+	outgoing := map[bPoint][]int{}
+	for i := range edges {
+		outgoing[edges[i].p0] = append(outgoing[edges[i].p0], i)
+	}
+	used := make([]bool, len(edges))
+	next := func(c bPoint) int {
+		for _, i := range outgoing[c] {
+			if !used[i] {
+				return i
+			}
+		}
+		return -1
+	}
+
+	p := &Path{}
+	var points []bPoint
+	for i := range edges {
+		if used[i] {
+			continue
+		}
+		points = points[:0]
+		for j := i; j >= 0; j = next(edges[j].p1) {
+			used[j] = true
+			points = append(points, edges[j].p0)
+		}
+		points = removeCollinear(points)
+		if len(points) < 3 {
+			continue
+		}
+		p.MoveTo(float32(points[0].x), float32(points[0].y))
+		for _, c := range points[1:] {
+			p.LineTo(float32(c.x), float32(c.y))
+		}
+		p.ClosePath()
+	}
+	return p
+}
+
+// removeCollinear removes the vertices of the closed polygon that lie on the
+// straight line between their neighbors, such as those where the operands'
+// edges were split.
+func removeCollinear(points []bPoint) []bPoint {
+	for changed := true; changed && len(points) >= 3; {
+		changed = false
+		out := points[:0]
+		n := len(points)
+		for i, c := range points {
+			prev, next := points[(i+n-1)%n], points[(i+1)%n]
+			if len(out) > 0 {
+				prev = out[len(out)-1]
+			}
+			ax, ay := c.x-prev.x, c.y-prev.y
+			bx, by := next.x-c.x, next.y-c.y
+			if cross(ax, ay, bx, by) == 0 && ax*bx+ay*by > 0 {
+				changed = true
+				continue
+			}
+			out = append(out, c)
+		}
+		points = out
+	}
+	return points
+}
@@ -0,0 +1,148 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vector
+
+import (
+	"image"
+	"strings"
+	"testing"
+)
+
+// rasterizePath rasterizes p, implicitly closing its open subpaths as the
+// boolean operations do.
+func rasterizePath(p *Path, w, h int) *image.Alpha {
+	z := NewRasterizer(w, h)
+	z.ClosePolicy = CloseImplicit
+	p.AddTo(z)
+	dst := image.NewAlpha(z.Bounds())
+	z.Draw(dst, dst.Bounds(), image.Opaque, image.Point{})
+	return dst
+}
+
+func TestBooleanOps(t *testing.T) {
+	const w, h = 48, 48
+	shapes := []struct {
+		name, svg string
+	}{
+		{"square", "M4 4H28V28H4Z"},
+		{"offset square", "M16 12H40V36H16Z"},
+		// The same square as offset square, in the opposite direction.
+		{"reversed square", "M16 12V36H40V12Z"},
+		{"circle", "M24 6A18 18 0 0 1 24 42A18 18 0 0 1 24 6Z"},
+		// A self-intersecting star, whose center is filled under the
+		// non-zero winding rule, and two overlapping triangles.
+		{"star", "M24 2L37 42L3 17L45 17L11 42Z"},
+		{"overlapping", "M2 2L30 2L2 30ZM10 10L40 10L10 40Z"},
+		// A square with a square hole, and an open subpath.
+		{"frame", "M8 8H40V40H8ZM16 16V32H32V16Z"},
+		{"open", "M0 20Q24 0 48 20L48 30"},
+		// Squares that share edges with square.
+		{"adjacent", "M28 4H40V28H28ZM4 10H28V20H4Z"},
+	}
+	ops := []struct {
+		name string
+		op   func(p, q *Path) *Path
+		f    func(inP, inQ bool) bool
+	}{
+		{"Union", (*Path).Union, func(a, b bool) bool { return a || b }},
+		{"Intersection", (*Path).Intersection, func(a, b bool) bool { return a && b }},
+		{"Difference", (*Path).Difference, func(a, b bool) bool { return a && !b }},
+	}
+
+	for _, sp := range shapes {
+		p, err := ParseSVGPath(sp.svg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		mp := rasterizePath(p, w, h)
+		for _, sq := range shapes {
+			q, err := ParseSVGPath(sq.svg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			mq := rasterizePath(q, w, h)
+			for _, o := range ops {
+				got := rasterizePath(o.op(p, q), w, h)
+				// Check the pixels that each operand either fully covers or
+				// does not cover at all.
+				n := 0
+				for i := range got.Pix {
+					a, b := mp.Pix[i], mq.Pix[i]
+					if (a != 0 && a != 0xff) || (b != 0 && b != 0xff) {
+						continue
+					}
+					want := 0
+					if o.f(a == 0xff, b == 0xff) {
+						want = 0xff
+					}
+					if d := int(got.Pix[i]) - want; d < -2 || 2 < d {
+						n++
+					}
+				}
+				if n != 0 {
+					t.Errorf("%s.%s(%s): %d pixels differ", sp.name, o.name, sq.name, n)
+				}
+			}
+		}
+	}
+}
+
+func TestBooleanOpsPath(t *testing.T) {
+	square, err := ParseSVGPath("M0 0H8V8H0Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := ParseSVGPath("M4 4H12V12H4Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		name string
+		got  *Path
+		want string
+	}{
+		{"Union", square.Union(other), "M0 0L8 0L8 4L12 4L12 12L4 12L4 8L0 8Z"},
+		{"Intersection", square.Intersection(other), "M4 4L8 4L8 8L4 8Z"},
+		{"Difference", square.Difference(other), "M0 0L8 0L8 4L4 4L4 8L0 8Z"},
+		{"self Difference", square.Difference(square), ""},
+		{"empty Union", (&Path{}).Union(square), "M0 0L8 0L8 8L0 8Z"},
+	}
+	for _, tc := range testCases {
+		got := string(tc.got.AppendSVG(nil))
+		if !equalPolygons(got, tc.want) {
+			t.Errorf("%s: got %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+// equalPolygons returns whether the SVG path data a and b, each of which is a
+// single closed polygon, or empty, have the same vertices in the same order,
+// starting from any of them.
+func equalPolygons(a, b string) bool {
+	if a == "" || b == "" {
+		return a == b
+	}
+	split := func(s string) []string {
+		s = strings.TrimSuffix(strings.TrimPrefix(s, "M"), "Z")
+		return strings.Split(s, "L")
+	}
+	va, vb := split(a), split(b)
+	if len(va) != len(vb) {
+		return false
+	}
+	for i := range va {
+		match := true
+		for j := range vb {
+			if va[(i+j)%len(va)] != vb[j] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,487 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vector
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"golang.org/x/image/math/f32"
+)
+
+// PathOp is a path segment's operator.
+type PathOp uint32
+
+const (
+	PathOpMoveTo PathOp = iota
+	PathOpLineTo
+	PathOpQuadTo
+	PathOpCubeTo
+	PathOpClose
+)
+
+// PathSegment is a segment of a Path.
+type PathSegment struct {
+	// Op is the operator.
+	Op PathOp
+	// Args is up to three (x, y) coordinates. The number of coordinates used
+	// is 1 for MoveTo and LineTo, 2 for QuadTo, 3 for CubeTo and 0 for Close.
+	Args [3]f32.Vec2
+}
+
+// Path is a recorded sequence of path segments, which can be added to a
+// Rasterizer, and converted to and from SVG path data.
+//
+// Its MoveTo, LineTo, QuadTo, CubeTo and ClosePath methods are like those of
+// a Rasterizer. The zero value is an empty path.
+type Path struct {
+	// Segments are the path's segments.
+	Segments []PathSegment
+
+	firstX float32
+	firstY float32
+	penX   float32
+	penY   float32
+}
+
+// Pen returns the location of the path-drawing pen: the last argument to the
+// most recent XxxTo call, or the start of the path after ClosePath.
+func (p *Path) Pen() (x, y float32) {
+	return p.penX, p.penY
+}
+
+// MoveTo starts a new path and moves the pen to (ax, ay).
+func (p *Path) MoveTo(ax, ay float32) {
+	p.Segments = append(p.Segments, PathSegment{
+		Op:   PathOpMoveTo,
+		Args: [3]f32.Vec2{{ax, ay}},
+	})
+	p.firstX, p.firstY = ax, ay
+	p.penX, p.penY = ax, ay
+}
+
+// LineTo adds a line segment, from the pen to (bx, by), and moves the pen to
+// (bx, by).
+func (p *Path) LineTo(bx, by float32) {
+	p.Segments = append(p.Segments, PathSegment{
+		Op:   PathOpLineTo,
+		Args: [3]f32.Vec2{{bx, by}},
+	})
+	p.penX, p.penY = bx, by
+}
+
+// QuadTo adds a quadratic Bézier segment, from the pen via (bx, by) to (cx,
+// cy), and moves the pen to (cx, cy).
+func (p *Path) QuadTo(bx, by, cx, cy float32) {
+	p.Segments = append(p.Segments, PathSegment{
+		Op:   PathOpQuadTo,
+		Args: [3]f32.Vec2{{bx, by}, {cx, cy}},
+	})
+	p.penX, p.penY = cx, cy
+}
+
+// CubeTo adds a cubic Bézier segment, from the pen via (bx, by) and (cx, cy)
+// to (dx, dy), and moves the pen to (dx, dy).
+func (p *Path) CubeTo(bx, by, cx, cy, dx, dy float32) {
+	p.Segments = append(p.Segments, PathSegment{
+		Op:   PathOpCubeTo,
+		Args: [3]f32.Vec2{{bx, by}, {cx, cy}, {dx, dy}},
+	})
+	p.penX, p.penY = dx, dy
+}
+
+// ClosePath closes the current path, and moves the pen to its start.
+func (p *Path) ClosePath() {
+	p.Segments = append(p.Segments, PathSegment{Op: PathOpClose})
+	p.penX, p.penY = p.firstX, p.firstY
+}
+
+// ArcTo adds an elliptical arc, from the pen to (x, y), and moves the pen to
+// (x, y). The arguments are those of the SVG path data "A" command: the
+// ellipse has radii rx and ry and is rotated by xAxisRotation degrees, and of
+// the four arcs that join the pen and (x, y), the largeArc and sweep flags
+// choose the one that spans more than 180 degrees or not, and that is drawn
+// in the positive-angle direction or not.
+//
+// The arc is approximated by cubic Bézier segments, each spanning at most 90
+// degrees. As in SVG, radii that are too small to join the pen and (x, y) are
+// scaled up, and an arc with a zero radius is a line segment. So is an arc
+// whose control points would not be finite float32 values, such as one with a
+// tiny radius that is scaled up by a huge factor.
+func (p *Path) ArcTo(rx, ry, xAxisRotation float32, largeArc, sweep bool, x, y float32) {
+	x1, y1 := float64(p.penX), float64(p.penY)
+	x2, y2 := float64(x), float64(y)
+	if x1 == x2 && y1 == y2 {
+		return
+	}
+	frx, fry := math.Abs(float64(rx)), math.Abs(float64(ry))
+	if frx == 0 || fry == 0 {
+		p.LineTo(x, y)
+		return
+	}
+
+	// This follows the conversion from endpoint to center parameterization
+	// in the SVG 1.1 specification, section F.6.5. The primed coordinates
+	// are in the ellipse's rotated frame, relative to the chord's midpoint.
+	sinPhi, cosPhi := math.Sincos(float64(xAxisRotation) * math.Pi / 180)
+	dx2, dy2 := (x1-x2)/2, (y1-y2)/2
+	x1p := cosPhi*dx2 + sinPhi*dy2
+	y1p := cosPhi*dy2 - sinPhi*dx2
+	if lambda := x1p*x1p/(frx*frx) + y1p*y1p/(fry*fry); lambda > 1 {
+		s := math.Sqrt(lambda)
+		frx, fry = frx*s, fry*s
+	}
+	rx2, ry2 := frx*frx, fry*fry
+	num := rx2*ry2 - rx2*y1p*y1p - ry2*x1p*x1p
+	den := rx2*y1p*y1p + ry2*x1p*x1p
+	coef := 0.0
+	if num > 0 {
+		coef = math.Sqrt(num / den)
+	}
+	if largeArc == sweep {
+		coef = -coef
+	}
+	cxp := coef * frx * y1p / fry
+	cyp := -coef * fry * x1p / frx
+	ox := cosPhi*cxp - sinPhi*cyp + (x1+x2)/2
+	oy := sinPhi*cxp + cosPhi*cyp + (y1+y2)/2
+
+	theta := math.Atan2((y1p-cyp)/fry, (x1p-cxp)/frx)
+	dTheta := math.Atan2((-y1p-cyp)/fry, (-x1p-cxp)/frx) - theta
+	if sweep && dTheta < 0 {
+		dTheta += 2 * math.Pi
+	} else if !sweep && dTheta > 0 {
+		dTheta -= 2 * math.Pi
+	}
+
+	// Each segment is the standard cubic approximation of a unit circle arc,
+	// mapped onto the ellipse.
+	n := int(math.Ceil(math.Abs(dTheta)/(math.Pi/2) - 1e-9))
+	if n < 1 {
+		n = 1
+	}
+	delta := dTheta / float64(n)
+	k := 4 / 3.0 * math.Tan(delta/4)
+	ellipse := func(ux, uy float64) (float32, float32) {
+		ux, uy = frx*ux, fry*uy
+		return float32(ox + cosPhi*ux - sinPhi*uy), float32(oy + sinPhi*ux + cosPhi*uy)
+	}
+	var args [4][6]float32
+	sin0, cos0 := math.Sincos(theta)
+	for i := 1; i <= n; i++ {
+		sin1, cos1 := math.Sincos(theta + float64(i)*delta)
+		a := &args[i-1]
+		a[0], a[1] = ellipse(cos0-k*sin0, sin0+k*cos0)
+		a[2], a[3] = ellipse(cos1+k*sin1, sin1-k*cos1)
+		a[4], a[5] = ellipse(cos1, sin1)
+		if i == n {
+			a[4], a[5] = x, y
+		}
+		for _, v := range a {
+			if math.IsInf(float64(v), 0) || math.IsNaN(float64(v)) {
+				p.LineTo(x, y)
+				return
+			}
+		}
+		sin0, cos0 = sin1, cos1
+	}
+	for _, a := range args[:n] {
+		p.CubeTo(a[0], a[1], a[2], a[3], a[4], a[5])
+	}
+}
+
+// AddTo adds the path's segments to z's path.
+func (p *Path) AddTo(z *Rasterizer) {
+	for i := range p.Segments {
+		s := &p.Segments[i]
+		switch s.Op {
+		case PathOpMoveTo:
+			z.MoveTo(s.Args[0][0], s.Args[0][1])
+		case PathOpLineTo:
+			z.LineTo(s.Args[0][0], s.Args[0][1])
+		case PathOpQuadTo:
+			z.QuadTo(s.Args[0][0], s.Args[0][1], s.Args[1][0], s.Args[1][1])
+		case PathOpCubeTo:
+			z.CubeTo(s.Args[0][0], s.Args[0][1], s.Args[1][0], s.Args[1][1], s.Args[2][0], s.Args[2][1])
+		case PathOpClose:
+			z.ClosePath()
+		}
+	}
+}
+
+// AppendSVG appends the path's segments, as SVG path data, to b and returns
+// the extended buffer. It uses only the absolute M, L, Q, C and Z commands,
+// and the shortest decimal representation of each coordinate that parses
+// back to the same float32 value, so that ParseSVGPath recovers the same
+// segments.
+func (p *Path) AppendSVG(b []byte) []byte {
+	for i := range p.Segments {
+		s := &p.Segments[i]
+		n := 0
+		switch s.Op {
+		case PathOpMoveTo:
+			b, n = append(b, 'M'), 1
+		case PathOpLineTo:
+			b, n = append(b, 'L'), 1
+		case PathOpQuadTo:
+			b, n = append(b, 'Q'), 2
+		case PathOpCubeTo:
+			b, n = append(b, 'C'), 3
+		case PathOpClose:
+			b = append(b, 'Z')
+		}
+		for j, v := range s.Args[:n] {
+			if j != 0 {
+				b = append(b, ' ')
+			}
+			b = strconv.AppendFloat(b, float64(v[0]), 'g', -1, 32)
+			b = append(b, ' ')
+			b = strconv.AppendFloat(b, float64(v[1]), 'g', -1, 32)
+		}
+	}
+	return b
+}
+
+// ParseSVGPath parses SVG path data, such as "M 10 10 L 20 10 Q 20 20 10 20
+// Z".
+//
+// All of the SVG 1.1 path commands are supported, in both their absolute
+// (upper case) and relative (lower case) forms. The H, V, S and T commands
+// are converted to line, quadratic and cubic segments, and the A command's
+// elliptical arcs are approximated by cubic segments, as by Path.ArcTo.
+func ParseSVGPath(s string) (*Path, error) {
+	p := &svgPathParser{s: s}
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+	return &p.path, nil
+}
+
+// svgPathParser is the state of a ParseSVGPath call.
+type svgPathParser struct {
+	s    string
+	i    int
+	path Path
+
+	// ctrlX and ctrlY are the last control point of the previous segment, if
+	// prevOp is a QuadTo or CubeTo, for the S and T commands.
+	ctrlX  float32
+	ctrlY  float32
+	prevOp PathOp
+}
+
+// svgArgCounts are the number of arguments for each SVG path command.
+var svgArgCounts = [256]int8{
+	'A': 7, 'C': 6, 'H': 1, 'L': 2, 'M': 2, 'Q': 4, 'S': 4, 'T': 2, 'V': 1, 'Z': 0,
+	'a': 7, 'c': 6, 'h': 1, 'l': 2, 'm': 2, 'q': 4, 's': 4, 't': 2, 'v': 1, 'z': 0,
+}
+
+func isSVGCommand(c byte) bool {
+	switch c {
+	case 'A', 'C', 'H', 'L', 'M', 'Q', 'S', 'T', 'V', 'Z',
+		'a', 'c', 'h', 'l', 'm', 'q', 's', 't', 'v', 'z':
+		return true
+	}
+	return false
+}
+
+func (p *svgPathParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("vector: invalid SVG path data at offset %d: "+format, append([]interface{}{p.i}, args...)...)
+}
+
+func (p *svgPathParser) skipSpace() {
+	for ; p.i < len(p.s); p.i++ {
+		switch p.s[p.i] {
+		case ' ', '\t', '\n', '\f', '\r':
+			continue
+		}
+		return
+	}
+}
+
+// skipSeparator skips the optional whitespace and comma between arguments.
+func (p *svgPathParser) skipSeparator() {
+	p.skipSpace()
+	if p.i < len(p.s) && p.s[p.i] == ',' {
+		p.i++
+		p.skipSpace()
+	}
+}
+
+func (p *svgPathParser) parse() error {
+	cmd := byte(0)
+	for {
+		p.skipSpace()
+		if p.i == len(p.s) {
+			return nil
+		}
+		if c := p.s[p.i]; isSVGCommand(c) {
+			cmd = c
+			p.i++
+		} else if cmd == 0 {
+			return p.errorf("missing command")
+		} else if cmd == 'Z' || cmd == 'z' {
+			return p.errorf("unexpected %q", c)
+		}
+		if len(p.path.Segments) == 0 && cmd != 'M' && cmd != 'm' {
+			return p.errorf("path does not start with a move")
+		}
+
+		var args [7]float32
+		for j := 0; j < int(svgArgCounts[cmd]); j++ {
+			if j != 0 {
+				p.skipSeparator()
+			} else {
+				p.skipSpace()
+			}
+			var err error
+			if (cmd == 'A' || cmd == 'a') && (j == 3 || j == 4) {
+				args[j], err = p.flag()
+			} else {
+				args[j], err = p.number()
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if err := p.command(cmd, &args); err != nil {
+			return err
+		}
+
+		// Arguments after a move are implicit line commands.
+		switch cmd {
+		case 'M':
+			cmd = 'L'
+		case 'm':
+			cmd = 'l'
+		}
+		p.skipSeparator()
+	}
+}
+
+// command adds the segments for one SVG path command with arguments args.
+func (p *svgPathParser) command(cmd byte, args *[7]float32) error {
+	x, y := p.path.Pen()
+	if cmd >= 'a' {
+		// Make the relative coordinates absolute.
+		cmd -= 'a' - 'A'
+		switch cmd {
+		case 'H':
+			args[0] += x
+		case 'V':
+			args[0] += y
+		case 'A':
+			args[5] += x
+			args[6] += y
+		default:
+			for j := 0; j < int(svgArgCounts[cmd]); j += 2 {
+				args[j] += x
+				args[j+1] += y
+			}
+		}
+	}
+
+	// rx and ry are the reflection of the previous control point, for the S
+	// and T commands.
+	rx, ry := x, y
+	if cmd == 'S' && p.prevOp == PathOpCubeTo || cmd == 'T' && p.prevOp == PathOpQuadTo {
+		rx, ry = 2*x-p.ctrlX, 2*y-p.ctrlY
+	}
+	// Relative coordinates and reflections can overflow.
+	if math.IsInf(float64(rx), 0) || math.IsInf(float64(ry), 0) {
+		return p.errorf("coordinate out of range")
+	}
+	for _, v := range args[:svgArgCounts[cmd]] {
+		if math.IsInf(float64(v), 0) {
+			return p.errorf("coordinate out of range")
+		}
+	}
+	op := PathOpLineTo
+
+	switch cmd {
+	case 'M':
+		p.path.MoveTo(args[0], args[1])
+		op = PathOpMoveTo
+	case 'L':
+		p.path.LineTo(args[0], args[1])
+	case 'H':
+		p.path.LineTo(args[0], y)
+	case 'V':
+		p.path.LineTo(x, args[0])
+	case 'Q':
+		p.path.QuadTo(args[0], args[1], args[2], args[3])
+		p.ctrlX, p.ctrlY, op = args[0], args[1], PathOpQuadTo
+	case 'T':
+		p.path.QuadTo(rx, ry, args[0], args[1])
+		p.ctrlX, p.ctrlY, op = rx, ry, PathOpQuadTo
+	case 'C':
+		p.path.CubeTo(args[0], args[1], args[2], args[3], args[4], args[5])
+		p.ctrlX, p.ctrlY, op = args[2], args[3], PathOpCubeTo
+	case 'S':
+		p.path.CubeTo(rx, ry, args[0], args[1], args[2], args[3])
+		p.ctrlX, p.ctrlY, op = args[0], args[1], PathOpCubeTo
+	case 'A':
+		p.path.ArcTo(args[0], args[1], args[2], args[3] != 0, args[4] != 0, args[5], args[6])
+	case 'Z':
+		p.path.ClosePath()
+		op = PathOpClose
+	}
+	p.prevOp = op
+	return nil
+}
+
+// number parses an SVG number: an optionally signed decimal, with an optional
+// exponent.
+func (p *svgPathParser) number() (float32, error) {
+	start, i, s := p.i, p.i, p.s
+	if i < len(s) && (s[i] == '+' || s[i] == '-') {
+		i++
+	}
+	digits := false
+	for ; i < len(s) && '0' <= s[i] && s[i] <= '9'; i++ {
+		digits = true
+	}
+	if i < len(s) && s[i] == '.' {
+		for i++; i < len(s) && '0' <= s[i] && s[i] <= '9'; i++ {
+			digits = true
+		}
+	}
+	if !digits {
+		return 0, p.errorf("expected a number")
+	}
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		j := i + 1
+		if j < len(s) && (s[j] == '+' || s[j] == '-') {
+			j++
+		}
+		if j < len(s) && '0' <= s[j] && s[j] <= '9' {
+			for i = j; i < len(s) && '0' <= s[i] && s[i] <= '9'; i++ {
+			}
+		}
+	}
+	v, err := strconv.ParseFloat(s[start:i], 32)
+	if err != nil {
+		return 0, p.errorf("%v", err)
+	}
+	p.i = i
+	return float32(v), nil
+}
+
+// flag parses an arc flag, which is a single "0" or "1" that need not be
+// separated from what follows.
+func (p *svgPathParser) flag() (float32, error) {
+	if p.i < len(p.s) {
+		switch p.s[p.i] {
+		case '0':
+			p.i++
+			return 0, nil
+		case '1':
+			p.i++
+			return 1, nil
+		}
+	}
+	return 0, p.errorf("expected a flag")
+}
@@ -0,0 +1,208 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vector
+
+import (
+	"bytes"
+	"image"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestParseSVGPath(t *testing.T) {
+	testCases := []struct {
+		s, want string
+	}{
+		{"", ""},
+		{"M1 2L3 4", "M1 2L3 4"},
+		{"  M 1,2 L 3 , 4 Z ", "M1 2L3 4Z"},
+		{"M1 2 3 4 5 6", "M1 2L3 4L5 6"},
+		{"m1 2 3 4z", "M1 2L4 6Z"},
+		{"M0,0h10v10H0z", "M0 0L10 0L10 10L0 10Z"},
+		{"M1 1l1 0zl0 1", "M1 1L2 1ZL1 2"},
+		{"M1 1M2 2m1 1", "M1 1M2 2M3 3"},
+		{"M.5.5l-1e1-.5", "M0.5 0.5L-9.5 0"},
+		{"M1e+2 -2.5E-1", "M100 -0.25"},
+		{"M0 0Q1 1 2 0T4 0", "M0 0Q1 1 2 0Q3 -1 4 0"},
+		{"M0 0q1 1 2 0t2 0t2 0", "M0 0Q1 1 2 0Q3 -1 4 0Q5 1 6 0"},
+		{"M0 0T2 0", "M0 0Q0 0 2 0"},
+		{"M0 0C1 1 2 1 3 0S5 -1 6 0", "M0 0C1 1 2 1 3 0C4 -1 5 -1 6 0"},
+		{"M0 0c1 1 2 1 3 0s2 -1 3 0", "M0 0C1 1 2 1 3 0C4 -1 5 -1 6 0"},
+		{"M0 0Q1 1 2 0S3 1 4 0", "M0 0Q1 1 2 0C2 0 3 1 4 0"},
+		{"M0 0A0 1 0 0 1 1 1", "M0 0L1 1"},
+		{"M0 0A1 1 0 0 1 0 0", "M0 0"},
+	}
+	for _, tc := range testCases {
+		p, err := ParseSVGPath(tc.s)
+		if err != nil {
+			t.Errorf("%q: %v", tc.s, err)
+			continue
+		}
+		if got := string(p.AppendSVG(nil)); got != tc.want {
+			t.Errorf("%q:\ngot  %q\nwant %q", tc.s, got, tc.want)
+		}
+	}
+}
+
+func TestParseSVGPathErrors(t *testing.T) {
+	testCases := []string{
+		"1 2",
+		"L1 2",
+		"Z",
+		"M1",
+		"M1 2 3",
+		"M1 2L",
+		"M1 2Z3 4",
+		"M1 2 x",
+		"M1 2L3 -",
+		"M3e38 0l3e38 0",
+		"M0 0C1 1 -3e38 0 3e38 0S1 1 2 2",
+		"M1 2L3 .",
+		"M1e50 0",
+		"M0 0A1 1 0 2 0 1 1",
+		"M0 0A1 1 0 0",
+	}
+	for _, s := range testCases {
+		if _, err := ParseSVGPath(s); err == nil {
+			t.Errorf("%q: got nil error, want non-nil", s)
+		}
+	}
+}
+
+func TestSVGPathRoundTrip(t *testing.T) {
+	p := &Path{}
+	p.MoveTo(1.5, -2.25)
+	p.LineTo(1e-7, 3e+9)
+	p.QuadTo(0.1, 0.2, 0.3, 0.4)
+	p.CubeTo(-1, -2, float32(math.Pi), float32(math.E), 7, 8)
+	p.ClosePath()
+	p.LineTo(9, 10)
+	got, err := ParseSVGPath(string(p.AppendSVG(nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.Segments, p.Segments) {
+		t.Errorf("\ngot  %v\nwant %v", got.Segments, p.Segments)
+	}
+
+	// Parsing, formatting and parsing again gives the same segments, even for
+	// arcs whose radii are scaled up by a huge factor.
+	for _, s := range []string{
+		"M0 0A1 1e-40 0 000 10",
+		"M0 0A1e-40 1e-40 45 1 1 3e38 -3e38",
+		"M0 0A2 1 30 1 0 10 5Z",
+	} {
+		p, err := ParseSVGPath(s)
+		if err != nil {
+			t.Errorf("%q: %v", s, err)
+			continue
+		}
+		got, err := ParseSVGPath(string(p.AppendSVG(nil)))
+		if err != nil {
+			t.Errorf("%q: round trip: %v", s, err)
+			continue
+		}
+		if !reflect.DeepEqual(got.Segments, p.Segments) {
+			t.Errorf("%q: round trip:\ngot  %v\nwant %v", s, got.Segments, p.Segments)
+		}
+	}
+}
+
+func TestArcTo(t *testing.T) {
+	testCases := []struct {
+		s string
+		// cx, cy, rx and ry are the arc's ellipse, which is not rotated.
+		cx, cy, rx, ry float64
+		n              int
+	}{
+		{"M1 0A1 1 0 0 1 -1 0", 0, 0, 1, 1, 2},
+		{"M1 0A1 1 0 0 0 -1 0", 0, 0, 1, 1, 2},
+		{"M10 5a5 5 0 0 1 -5 5", 5, 5, 5, 5, 1},
+		{"M10 5a5 5 0 1 0 -5 5", 5, 5, 5, 5, 3},
+		{"M3 0A3 2 0 0 1 0 2", 0, 0, 3, 2, 1},
+		{"M2 0A1 1 0 0 1 0 0", 1, 0, 1, 1, 2},
+		{"M0 0a1 1 0 0110 0", 5, 0, 5, 5, 2},
+	}
+	for _, tc := range testCases {
+		p, err := ParseSVGPath(tc.s)
+		if err != nil {
+			t.Errorf("%q: %v", tc.s, err)
+			continue
+		}
+		segs := p.Segments[1:]
+		if len(segs) != tc.n {
+			t.Errorf("%q: got %d segments, want %d", tc.s, len(segs), tc.n)
+			continue
+		}
+		for i, s := range segs {
+			if s.Op != PathOpCubeTo {
+				t.Errorf("%q: segment %d: got op %d, want %d", tc.s, i, s.Op, PathOpCubeTo)
+				continue
+			}
+			// Each segment's end point, and its mid point, should be on the
+			// ellipse.
+			ax, ay := float64(p.Segments[i].Args[0][0]), float64(p.Segments[i].Args[0][1])
+			if i != 0 {
+				ax, ay = float64(p.Segments[i].Args[2][0]), float64(p.Segments[i].Args[2][1])
+			}
+			b, c, d := s.Args[0], s.Args[1], s.Args[2]
+			mx := (ax + 3*float64(b[0]) + 3*float64(c[0]) + float64(d[0])) / 8
+			my := (ay + 3*float64(b[1]) + 3*float64(c[1]) + float64(d[1])) / 8
+			for _, q := range [][2]float64{{float64(d[0]), float64(d[1])}, {mx, my}} {
+				ex, ey := (q[0]-tc.cx)/tc.rx, (q[1]-tc.cy)/tc.ry
+				if r := math.Hypot(ex, ey); math.Abs(r-1) > 1e-3 {
+					t.Errorf("%q: segment %d: point %v is not on the ellipse: r=%v", tc.s, i, q, r)
+				}
+			}
+		}
+	}
+
+	// The sweep flag chooses between the arcs through (0, 1) and (0, -1).
+	for _, tc := range []struct {
+		s     string
+		wantY float32
+	}{
+		{"M1 0A1 1 0 0 1 -1 0", +1},
+		{"M1 0A1 1 0 0 0 -1 0", -1},
+	} {
+		p, err := ParseSVGPath(tc.s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		mid := p.Segments[1].Args[2]
+		if math.Abs(float64(mid[0])) > 1e-6 || math.Abs(float64(mid[1]-tc.wantY)) > 1e-6 {
+			t.Errorf("%q: got mid point %v, want (0, %v)", tc.s, mid, tc.wantY)
+		}
+	}
+}
+
+func TestPathAddTo(t *testing.T) {
+	const w, h = 40, 30
+	want := NewRasterizer(w, h)
+	want.MoveTo(2, 3)
+	want.LineTo(30, 4)
+	want.QuadTo(38, 20, 20, 28)
+	want.CubeTo(10, 25, 5, 20, 8, 12)
+	want.ClosePath()
+
+	p, err := ParseSVGPath("M2 3L30 4Q38 20 20 28C10 25 5 20 8 12Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := NewRasterizer(w, h)
+	p.AddTo(got)
+
+	wantDst := image.NewAlpha(image.Rect(0, 0, w, h))
+	want.Draw(wantDst, wantDst.Bounds(), image.Opaque, image.Point{})
+	gotDst := image.NewAlpha(image.Rect(0, 0, w, h))
+	got.Draw(gotDst, gotDst.Bounds(), image.Opaque, image.Point{})
+	if !bytes.Equal(gotDst.Pix, wantDst.Pix) {
+		t.Error("pixels differ")
+	}
+	if gotDst.Pix[gotDst.PixOffset(20, 15)] != 0xff {
+		t.Error("path was not drawn")
+	}
+}
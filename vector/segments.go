@@ -0,0 +1,79 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vector
+
+import (
+	"golang.org/x/image/math/fixed"
+)
+
+// PathBuilder is a path that segments can be added to. It is implemented by
+// *Rasterizer and *Path.
+type PathBuilder interface {
+	MoveTo(ax, ay float32)
+	LineTo(bx, by float32)
+	QuadTo(bx, by, cx, cy float32)
+	CubeTo(bx, by, cx, cy, dx, dy float32)
+}
+
+// Segment operators, as for AppendSegments. They have the same values as the
+// sfnt package's SegmentOp constants.
+const (
+	segmentOpMoveTo = 0
+	segmentOpLineTo = 1
+	segmentOpQuadTo = 2
+	segmentOpCubeTo = 3
+)
+
+// AppendSegments adds the path segments segs, such as the sfnt.Segments
+// returned by sfnt.Font.LoadGlyph, to p. Each segment's points are translated
+// by bias and converted from fixed.Int26_6 to float32, so that a point at
+// (x, y) is added to the path at ((x+bias.X)/64, (y+bias.Y)/64).
+//
+// A segment's Op is a MoveTo, LineTo, QuadTo or CubeTo if it is 0, 1, 2 or 3,
+// as for sfnt.SegmentOp, and its Args hold that many points. The type
+// parameters let this package take sfnt.Segments without depending on the
+// sfnt package.
+//
+// When rasterizing a glyph, bias is typically the glyph's origin (the dot)
+// relative to the top-left corner of the Rasterizer's bounds.
+func AppendSegments[S ~struct {
+	Op   O
+	Args [3]fixed.Point26_6
+}, O ~uint32](p PathBuilder, segs []S, bias fixed.Point26_6) {
+	for _, s := range segs {
+		seg := struct {
+			Op   O
+			Args [3]fixed.Point26_6
+		}(s)
+		switch seg.Op {
+		case segmentOpMoveTo:
+			p.MoveTo(
+				float32(seg.Args[0].X+bias.X)/64,
+				float32(seg.Args[0].Y+bias.Y)/64,
+			)
+		case segmentOpLineTo:
+			p.LineTo(
+				float32(seg.Args[0].X+bias.X)/64,
+				float32(seg.Args[0].Y+bias.Y)/64,
+			)
+		case segmentOpQuadTo:
+			p.QuadTo(
+				float32(seg.Args[0].X+bias.X)/64,
+				float32(seg.Args[0].Y+bias.Y)/64,
+				float32(seg.Args[1].X+bias.X)/64,
+				float32(seg.Args[1].Y+bias.Y)/64,
+			)
+		case segmentOpCubeTo:
+			p.CubeTo(
+				float32(seg.Args[0].X+bias.X)/64,
+				float32(seg.Args[0].Y+bias.Y)/64,
+				float32(seg.Args[1].X+bias.X)/64,
+				float32(seg.Args[1].Y+bias.Y)/64,
+				float32(seg.Args[2].X+bias.X)/64,
+				float32(seg.Args[2].Y+bias.Y)/64,
+			)
+		}
+	}
+}
@@ -22,6 +22,7 @@ package vector // import "golang.org/x/image/vector"
 // https://people.gnome.org/~mathieu/libart/internals.html#INTERNALS-SCANLINE
 
 import (
+	"errors"
 	"image"
 	"image/color"
 	"image/draw"
@@ -100,25 +101,42 @@ type Rasterizer struct {
 	penX   float32
 	penY   float32
 
+	// alpha is the opacity set by SetAlpha, scaled to the range [0, 0xffff].
+	alpha uint32
+
+	// err is the error returned by Err.
+	err error
+
 	// DrawOp is the operator used for the Draw method.
 	//
 	// The zero value is draw.Over.
 	DrawOp draw.Op
 
+	// ClosePolicy is what happens to open subpaths, those whose pen is not
+	// back at their start, when MoveTo starts a new subpath or when Draw or
+	// DrawSpans rasterizes them.
+	//
+	// The zero value is CloseNone.
+	ClosePolicy ClosePolicy
+
 	// TODO: an exported field equivalent to the mask point in the
 	// draw.DrawMask function in the stdlib image/draw package?
 }
 
 // Reset resets a Rasterizer as if it was just returned by NewRasterizer.
 //
-// This includes setting z.DrawOp to draw.Over.
+// This includes setting z.DrawOp to draw.Over, z.ClosePolicy to CloseNone and
+// the alpha to 0xff, and clearing the error returned by Err.
 func (z *Rasterizer) Reset(w, h int) {
 	z.size = image.Point{w, h}
 	z.firstX = 0
 	z.firstY = 0
 	z.penX = 0
 	z.penY = 0
+	z.alpha = 0xffff
+	z.err = nil
 	z.DrawOp = draw.Over
+	z.ClosePolicy = CloseNone
 
 	z.setUseFloatingPointMath(w > floatingPointMathThreshold || h > floatingPointMathThreshold)
 }
@@ -148,6 +166,15 @@ func (z *Rasterizer) setUseFloatingPointMath(b bool) {
 	}
 }
 
+// SetAlpha sets the opacity with which the Draw and DrawSpans methods
+// composite, from 0 (fully transparent) to 0xff (fully opaque, the default).
+// The coverage of each pixel is multiplied by a/0xff, which draws a faded
+// shape without needing a translucent src image. Unlike a translucent src,
+// it keeps the fast paths for an opaque *image.Uniform src.
+func (z *Rasterizer) SetAlpha(a uint8) {
+	z.alpha = uint32(a) * 0x101
+}
+
 // Size returns the width and height passed to NewRasterizer or Reset.
 func (z *Rasterizer) Size() image.Point {
 	return z.size
@@ -165,6 +192,48 @@ func (z *Rasterizer) Pen() (x, y float32) {
 	return z.penX, z.penY
 }
 
+// ClosePolicy is what a Rasterizer does with open subpaths. Canvas and SVG
+// fills close them implicitly, and code ported from those APIs may rely on
+// that.
+type ClosePolicy uint8
+
+const (
+	// CloseNone rasterizes open subpaths as they are. Their missing closing
+	// edges leave the coverage to their right unbalanced, so that the mask
+	// typically extends to the right edge of the Rasterizer's bounds.
+	CloseNone ClosePolicy = iota
+	// CloseImplicit closes open subpaths with a line segment back to their
+	// start, as if ClosePath was called.
+	CloseImplicit
+	// CloseError treats open subpaths as an error: Draw and DrawSpans draw
+	// nothing, and Err returns ErrOpenSubpath.
+	CloseError
+)
+
+// ErrOpenSubpath is the error returned by Err when a Rasterizer whose
+// ClosePolicy is CloseError has an open subpath.
+var ErrOpenSubpath = errors.New("vector: open subpath")
+
+// Err returns ErrOpenSubpath if the paths added since the last Reset have an
+// open subpath and z.ClosePolicy is CloseError, and nil otherwise. The last
+// subpath is only checked by Draw and DrawSpans.
+func (z *Rasterizer) Err() error {
+	return z.err
+}
+
+// endSubpath applies z.ClosePolicy to the current subpath.
+func (z *Rasterizer) endSubpath() {
+	if z.penX == z.firstX && z.penY == z.firstY {
+		return
+	}
+	switch z.ClosePolicy {
+	case CloseImplicit:
+		z.ClosePath()
+	case CloseError:
+		z.err = ErrOpenSubpath
+	}
+}
+
 // ClosePath closes the current path.
 func (z *Rasterizer) ClosePath() {
 	z.LineTo(z.firstX, z.firstY)
@@ -174,6 +243,7 @@ func (z *Rasterizer) ClosePath() {
 //
 // The coordinates are allowed to be out of the Rasterizer's bounds.
 func (z *Rasterizer) MoveTo(ax, ay float32) {
+	z.endSubpath()
 	z.firstX = ax
 	z.firstY = ay
 	z.penX = ax
@@ -269,6 +339,10 @@ func (z *Rasterizer) Draw(dst draw.Image, r image.Rectangle, src image.Image, sp
 	// TODO: adjust r and sp (and mp?) if src.Bounds() doesn't contain
 	// r.Add(sp.Sub(r.Min)).
 
+	if z.endSubpath(); z.err != nil {
+		return
+	}
+
 	if src, ok := src.(*image.Uniform); ok {
 		srcR, srcG, srcB, srcA := src.RGBA()
 		switch dst := dst.(type) {
@@ -299,6 +373,43 @@ func (z *Rasterizer) Draw(dst draw.Image, r image.Rectangle, src image.Image, sp
 	}
 }
 
+// SpanFunc is called by DrawSpans for each horizontal run of pixels, on row y
+// from column x0 inclusive to column x1 exclusive, that have the same non-zero
+// coverage. That coverage, alpha, ranges up to 0xffff (fully covered), the
+// same range as the values returned by the color.Color interface's RGBA
+// method.
+type SpanFunc func(y, x0, x1 int, alpha uint32)
+
+// DrawSpans rasterizes the vector paths previously added via the XxxTo calls,
+// calling fn for each span of non-zero coverage, in increasing y order and
+// then increasing x order within each row. Adjacent pixels with equal coverage
+// are merged into a single span.
+//
+// It lets custom compositors consume the rasterization without an
+// intermediate mask image. Like Draw, it should be called at most once per
+// set of paths. Call Reset before adding new paths.
+func (z *Rasterizer) DrawSpans(fn SpanFunc) {
+	if z.endSubpath(); z.err != nil {
+		return
+	}
+	z.accumulateMask()
+	w := z.size.X
+	for y := 0; y < z.size.Y; y++ {
+		row := z.bufU32[y*w : (y+1)*w]
+		for x0 := 0; x0 < w; {
+			ma := row[x0]
+			x1 := x0 + 1
+			for x1 < w && row[x1] == ma {
+				x1++
+			}
+			if ma != 0 {
+				fn(y, x0, x1, ma)
+			}
+			x0 = x1
+		}
+	}
+}
+
 func (z *Rasterizer) accumulateMask() {
 	if z.useFloatingPointMath {
 		if n := z.size.X * z.size.Y; n > cap(z.bufU32) {
@@ -318,11 +429,16 @@ func (z *Rasterizer) accumulateMask() {
 			fixedAccumulateMask(z.bufU32)
 		}
 	}
+	if z.alpha != 0xffff {
+		for i, ma := range z.bufU32 {
+			z.bufU32[i] = ma * z.alpha / 0xffff
+		}
+	}
 }
 
 func (z *Rasterizer) rasterizeDstAlphaSrcOpaqueOpOver(dst *image.Alpha, r image.Rectangle) {
 	// TODO: non-zero vs even-odd winding?
-	if r == dst.Bounds() && r == z.Bounds() {
+	if r == dst.Bounds() && r == z.Bounds() && z.alpha == 0xffff {
 		// We bypass the z.accumulateMask step and convert straight from
 		// z.bufF32 or z.bufU32 to dst.Pix.
 		if z.useFloatingPointMath {
@@ -358,7 +474,7 @@ func (z *Rasterizer) rasterizeDstAlphaSrcOpaqueOpOver(dst *image.Alpha, r image.
 
 func (z *Rasterizer) rasterizeDstAlphaSrcOpaqueOpSrc(dst *image.Alpha, r image.Rectangle) {
 	// TODO: non-zero vs even-odd winding?
-	if r == dst.Bounds() && r == z.Bounds() {
+	if r == dst.Bounds() && r == z.Bounds() && z.alpha == 0xffff {
 		// We bypass the z.accumulateMask step and convert straight from
 		// z.bufF32 or z.bufU32 to dst.Pix.
 		if z.useFloatingPointMath {
@@ -7,6 +7,7 @@ package vector
 // TODO: add tests for NaN and Inf coordinates.
 
 import (
+	"bytes"
 	"fmt"
 	"image"
 	"image/color"
@@ -17,6 +18,10 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/internal/glyphtest"
+	"golang.org/x/image/math/fixed"
 )
 
 // encodePNG is useful for manually debugging the tests.
@@ -351,11 +356,261 @@ func TestBasicPathDstRGBA(t *testing.T) {
 	}
 }
 
+func TestSetAlpha(t *testing.T) {
+	translucentBlue := image.NewUniform(color.RGBA{0x00, 0x00, 0x80, 0x80})
+	for _, floatingPointMath := range []bool{false, true} {
+		for _, op := range []draw.Op{draw.Over, draw.Src} {
+			for _, xPadding := range []int{0, 7} {
+				prefix := fmt.Sprintf("floatingPointMath=%t, op=%v, xPadding=%d", floatingPointMath, op, xPadding)
+				newBasicPath := func() *Rasterizer {
+					z := NewRasterizer(16, 16)
+					z.setUseFloatingPointMath(floatingPointMath)
+					z.MoveTo(2, 2)
+					z.LineTo(8, 2)
+					z.QuadTo(14, 2, 14, 14)
+					z.CubeTo(8, 2, 5, 20, 2, 8)
+					z.ClosePath()
+					z.DrawOp = op
+					return z
+				}
+				bounds := image.Rect(0, 0, 16+xPadding, 16)
+
+				// Drawing with an alpha of 0x80 is like drawing a half
+				// transparent src.
+				dstAlpha := image.NewAlpha(bounds)
+				dstRGBA := image.NewRGBA(bounds)
+				wantAlpha := make([]byte, len(dstAlpha.Pix))
+				wantRGBA := make([]byte, len(dstRGBA.Pix))
+				for y := 0; y < 16; y++ {
+					for x := 0; x < 16; x++ {
+						ma := uint8(uint32(basicMask[16*y+x]) * 0x80 / 0xff)
+						wantAlpha[dstAlpha.PixOffset(x, y)] = ma
+						i := dstRGBA.PixOffset(x, y)
+						wantRGBA[i+2], wantRGBA[i+3] = ma, ma
+					}
+				}
+
+				for _, tc := range []struct {
+					dst  draw.Image
+					src  image.Image
+					want []byte
+				}{
+					{dstAlpha, image.Opaque, wantAlpha},
+					{dstRGBA, image.NewUniform(color.RGBA{0x00, 0x00, 0xff, 0xff}), wantRGBA},
+					{image.NewNRGBA(bounds), image.NewUniform(color.RGBA{0x00, 0x00, 0xff, 0xff}), nil},
+				} {
+					z := newBasicPath()
+					z.SetAlpha(0x80)
+					z.Draw(tc.dst, z.Bounds(), tc.src, image.Point{})
+
+					if tc.want == nil {
+						// Compare the slow path against the translucent src.
+						want := image.NewNRGBA(bounds)
+						z = newBasicPath()
+						z.Draw(want, z.Bounds(), translucentBlue, image.Point{})
+						tc.want = want.Pix
+					}
+
+					var got []byte
+					switch dst := tc.dst.(type) {
+					case *image.Alpha:
+						got = dst.Pix
+					case *image.RGBA:
+						got = dst.Pix
+					case *image.NRGBA:
+						got = dst.Pix
+					}
+					for i := range got {
+						if delta := int(got[i]) - int(tc.want[i]); delta < -2 || +2 < delta {
+							t.Errorf("%s, dst %T: i=%d: got %#02x, want %#02x", prefix, tc.dst, i, got[i], tc.want[i])
+							break
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
 const (
 	benchmarkGlyphWidth  = 893
 	benchmarkGlyphHeight = 1122
 )
 
+func TestDrawSpans(t *testing.T) {
+	for _, floatingPointMath := range []bool{false, true} {
+		z := NewRasterizer(16, 16)
+		z.setUseFloatingPointMath(floatingPointMath)
+		z.MoveTo(2, 2)
+		z.LineTo(8, 2)
+		z.QuadTo(14, 2, 14, 14)
+		z.CubeTo(8, 2, 5, 20, 2, 8)
+		z.ClosePath()
+
+		got := make([]byte, 16*16)
+		prevY, prevX1, prevAlpha := -1, 0, uint32(0)
+		z.DrawSpans(func(y, x0, x1 int, alpha uint32) {
+			if y < prevY || (y == prevY && x0 < prevX1) {
+				t.Errorf("floatingPointMath=%t: span (%d, %d, %d) out of order", floatingPointMath, y, x0, x1)
+			}
+			if x0 >= x1 || alpha == 0 || alpha > 0xffff {
+				t.Errorf("floatingPointMath=%t: bad span (%d, %d, %d, %#04x)", floatingPointMath, y, x0, x1, alpha)
+			}
+			if y == prevY && x0 == prevX1 && alpha == prevAlpha {
+				t.Errorf("floatingPointMath=%t: span (%d, %d, %d) was not merged", floatingPointMath, y, x0, x1)
+			}
+			for x := x0; x < x1; x++ {
+				got[16*y+x] = uint8(alpha >> 8)
+			}
+			prevY, prevX1, prevAlpha = y, x1, alpha
+		})
+
+		for i := range got {
+			delta := int(got[i]) - int(basicMask[i])
+			if delta < -2 || +2 < delta {
+				t.Errorf("floatingPointMath=%t: i=%d: got %#02x, want %#02x", floatingPointMath, i, got[i], basicMask[i])
+				break
+			}
+		}
+	}
+}
+
+func TestGlyphCorpus(t *testing.T) {
+	glyphs, err := glyphtest.Corpus()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The baselines are the current implementation's RMS difference from the
+	// reference, in 8-bit alpha values, and its number of pixels that differ
+	// by more than 2, over the whole corpus. A change that makes either of
+	// them more than 1% worse fails the test. The tolerances bound each
+	// pixel's difference, as a secondary check. Comparing flattened glyphs
+	// tests the accumulation separately from the curve flattening.
+	testCases := []struct {
+		floating  bool
+		flatten   bool
+		rms       float64
+		over      int
+		tolerance uint8
+	}{
+		// The floating point accumulation is exact, up to rounding errors.
+		{true, true, 0.1001, 0, 1},
+		// The curves are flattened more coarsely than the reference does.
+		{true, false, 2.5563, 79518, 56},
+		// The fixed point math loses precision along long, steep lines.
+		{false, true, 1.2945, 9662, 72},
+		{false, false, 3.8241, 55800, 72},
+	}
+	const slack = 1.01
+
+	z := &Rasterizer{}
+	for _, tc := range testCases {
+		var stats glyphtest.Stats
+		for i := range glyphs {
+			g := &glyphs[i]
+			if !tc.floating && (g.Size.X > floatingPointMathThreshold || g.Size.Y > floatingPointMathThreshold) {
+				continue
+			}
+			if tc.flatten {
+				flat := g.Flatten()
+				g = &flat
+			}
+			z.Reset(g.Size.X, g.Size.Y)
+			z.setUseFloatingPointMath(tc.floating)
+			g.Trace(z)
+			got := image.NewAlpha(z.Bounds())
+			z.Draw(got, got.Bounds(), image.Opaque, image.Point{})
+			want := glyphtest.Reference(g)
+			if err := glyphtest.Compare(got, want, tc.tolerance); err != nil {
+				t.Errorf("floating=%t: %s: %v", tc.floating, g.Name, err)
+			}
+			stats.Add(glyphtest.Measure(got, want, 2))
+		}
+		if rms := stats.RMSError(); rms > tc.rms*slack {
+			t.Errorf("floating=%t, flatten=%t: RMS error: got %.4f, baseline %.4f", tc.floating, tc.flatten, rms, tc.rms)
+		}
+		if float64(stats.Over) > float64(tc.over)*slack {
+			t.Errorf("floating=%t, flatten=%t: pixels differing by more than 2: got %d, baseline %d",
+				tc.floating, tc.flatten, stats.Over, tc.over)
+		}
+	}
+}
+
+func TestAppendSegments(t *testing.T) {
+	// AppendSegments does not import the sfnt package, so check that its
+	// operators match sfnt's.
+	if sfnt.SegmentOpMoveTo != segmentOpMoveTo || sfnt.SegmentOpLineTo != segmentOpLineTo ||
+		sfnt.SegmentOpQuadTo != segmentOpQuadTo || sfnt.SegmentOpCubeTo != segmentOpCubeTo {
+		t.Fatal("segment operators differ from sfnt.SegmentOp")
+	}
+
+	p := func(x, y fixed.Int26_6) fixed.Point26_6 { return fixed.Point26_6{X: x, Y: y} }
+	segs := []sfnt.Segment{
+		{Op: sfnt.SegmentOpMoveTo, Args: [3]fixed.Point26_6{p(64, 128)}},
+		{Op: sfnt.SegmentOpLineTo, Args: [3]fixed.Point26_6{p(1000, 96)}},
+		{Op: sfnt.SegmentOpQuadTo, Args: [3]fixed.Point26_6{p(1200, 700), p(900, 1100)}},
+		{Op: sfnt.SegmentOpCubeTo, Args: [3]fixed.Point26_6{p(600, 1300), p(100, 900), p(64, 128)}},
+		{Op: sfnt.SegmentOpMoveTo, Args: [3]fixed.Point26_6{p(300, 300)}},
+		{Op: sfnt.SegmentOpLineTo, Args: [3]fixed.Point26_6{p(300, 700)}},
+		{Op: sfnt.SegmentOpLineTo, Args: [3]fixed.Point26_6{p(700, 700)}},
+	}
+	bias := p(-40, 57)
+
+	// The bias is in 26.6 fixed point, so (-40, 57) is (-0.625, 0.890625).
+	want := NewRasterizer(24, 24)
+	want.MoveTo(1-0.625, 2+0.890625)
+	want.LineTo(15.625-0.625, 1.5+0.890625)
+	want.QuadTo(18.75-0.625, 10.9375+0.890625, 14.0625-0.625, 17.1875+0.890625)
+	want.CubeTo(9.375-0.625, 20.3125+0.890625, 1.5625-0.625, 14.0625+0.890625, 1-0.625, 2+0.890625)
+	want.MoveTo(4.6875-0.625, 4.6875+0.890625)
+	want.LineTo(4.6875-0.625, 10.9375+0.890625)
+	want.LineTo(10.9375-0.625, 10.9375+0.890625)
+	wantDst := image.NewAlpha(want.Bounds())
+	want.Draw(wantDst, wantDst.Bounds(), image.Opaque, image.Point{})
+
+	got := NewRasterizer(24, 24)
+	AppendSegments(got, segs, bias)
+	if gx, gy := got.Pen(); gx != 10.9375-0.625 || gy != 10.9375+0.890625 {
+		t.Errorf("pen: got (%v, %v), want (%v, %v)", gx, gy, 10.9375-0.625, 10.9375+0.890625)
+	}
+	gotDst := image.NewAlpha(got.Bounds())
+	got.Draw(gotDst, gotDst.Bounds(), image.Opaque, image.Point{})
+
+	for i := range gotDst.Pix {
+		if gotDst.Pix[i] != wantDst.Pix[i] {
+			t.Fatalf("pixel %d: got %#02x, want %#02x", i, gotDst.Pix[i], wantDst.Pix[i])
+		}
+	}
+}
+
+func benchGlyphCorpus(b *testing.B, floating bool) {
+	corpus, err := glyphtest.Corpus()
+	if err != nil {
+		b.Fatal(err)
+	}
+	// Only use the glyphs that are small enough for either implementation.
+	var glyphs []glyphtest.Glyph
+	for _, g := range corpus {
+		if g.Size.X <= floatingPointMathThreshold && g.Size.Y <= floatingPointMathThreshold {
+			glyphs = append(glyphs, g)
+		}
+	}
+
+	dst := image.NewAlpha(image.Rect(0, 0, floatingPointMathThreshold, floatingPointMathThreshold))
+	z := &Rasterizer{}
+	glyphtest.Benchmark(b, glyphs, func(g *glyphtest.Glyph) {
+		z.Reset(g.Size.X, g.Size.Y)
+		z.setUseFloatingPointMath(floating)
+		z.DrawOp = draw.Src
+		g.Trace(z)
+		z.Draw(dst, z.Bounds(), image.Opaque, image.Point{})
+	})
+}
+
+func BenchmarkGlyphCorpusFixed(b *testing.B)    { benchGlyphCorpus(b, false) }
+func BenchmarkGlyphCorpusFloating(b *testing.B) { benchGlyphCorpus(b, true) }
+
 type benchmarkGlyphDatum struct {
 	// n being 0, 1 or 2 means moveTo, lineTo or quadTo.
 	n  uint32
@@ -517,3 +772,65 @@ func BenchmarkGlyphNRGBA256Over(b *testing.B)  { benchGlyph(b, 'N', false, 256,
 func BenchmarkGlyphNRGBA256Src(b *testing.B)   { benchGlyph(b, 'N', false, 256, draw.Src) }
 func BenchmarkGlyphNRGBA1024Over(b *testing.B) { benchGlyph(b, 'N', false, 1024, draw.Over) }
 func BenchmarkGlyphNRGBA1024Src(b *testing.B)  { benchGlyph(b, 'N', false, 1024, draw.Src) }
+
+func TestClosePolicy(t *testing.T) {
+	rasterize := func(policy ClosePolicy, close bool) (*image.Alpha, error) {
+		z := NewRasterizer(16, 16)
+		z.ClosePolicy = policy
+		// Two triangles, the first of which is closed by the second's MoveTo,
+		// and the second by Draw, unless close is true.
+		z.MoveTo(1, 1)
+		z.LineTo(7, 1)
+		z.LineTo(1, 7)
+		if close {
+			z.ClosePath()
+		}
+		z.MoveTo(9, 9)
+		z.LineTo(15, 9)
+		z.LineTo(9, 15)
+		if close {
+			z.ClosePath()
+		}
+		dst := image.NewAlpha(z.Bounds())
+		z.Draw(dst, dst.Bounds(), image.Opaque, image.Point{})
+		return dst, z.Err()
+	}
+
+	want, err := rasterize(CloseNone, true)
+	if err != nil {
+		t.Fatalf("closed: %v", err)
+	}
+	for _, policy := range []ClosePolicy{CloseNone, CloseImplicit, CloseError} {
+		got, err := rasterize(policy, true)
+		if err != nil || !bytes.Equal(got.Pix, want.Pix) {
+			t.Errorf("policy %d, closed: got different pixels or error %v", policy, err)
+		}
+	}
+
+	if got, err := rasterize(CloseImplicit, false); err != nil || !bytes.Equal(got.Pix, want.Pix) {
+		t.Errorf("CloseImplicit: got different pixels from closing explicitly, or error %v", err)
+	}
+	if got, _ := rasterize(CloseNone, false); bytes.Equal(got.Pix, want.Pix) {
+		t.Errorf("CloseNone: got the same pixels as closing explicitly")
+	}
+	got, err := rasterize(CloseError, false)
+	if err != ErrOpenSubpath {
+		t.Errorf("CloseError: got error %v, want ErrOpenSubpath", err)
+	}
+	if !bytes.Equal(got.Pix, make([]byte, len(got.Pix))) {
+		t.Errorf("CloseError: got non-zero pixels")
+	}
+
+	// Reset clears the error and the policy.
+	z := NewRasterizer(4, 4)
+	z.ClosePolicy = CloseError
+	z.LineTo(2, 2)
+	z.MoveTo(0, 0)
+	if z.Err() != ErrOpenSubpath {
+		t.Errorf("MoveTo: got error %v, want ErrOpenSubpath", z.Err())
+	}
+	z.Reset(4, 4)
+	if z.Err() != nil || z.ClosePolicy != CloseNone {
+		t.Errorf("Reset: got error %v and policy %d, want nil and CloseNone", z.Err(), z.ClosePolicy)
+	}
+}
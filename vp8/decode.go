@@ -2,7 +2,8 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// Package vp8 implements a decoder for the VP8 lossy image format.
+// Package vp8 implements a decoder and an encoder for the VP8 lossy image
+// format.
 //
 // The VP8 specification is RFC 6386.
 package vp8 // import "golang.org/x/image/vp8"
@@ -13,6 +14,8 @@ import (
 	"errors"
 	"image"
 	"io"
+
+	"golang.org/x/image/internal/ycbcr"
 )
 
 // limitReader wraps an io.Reader to read at most n bytes from it.
@@ -31,6 +34,21 @@ func (r *limitReader) ReadFull(p []byte) error {
 	return err
 }
 
+// readAvailable is like ReadFull except that, if the underlying reader ends
+// before len(p) bytes are read, it returns the bytes that were read instead
+// of an error.
+func (r *limitReader) readAvailable(p []byte) ([]byte, error) {
+	if len(p) > r.n {
+		p = p[:r.n]
+	}
+	n, err := io.ReadFull(r.r, p)
+	r.n -= n
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		err = nil
+	}
+	return p[:n], err
+}
+
 // FrameHeader is a frame header, as specified in section 9.1.
 type FrameHeader struct {
 	KeyFrame          bool
@@ -89,12 +107,38 @@ type mb struct {
 	nzY16 uint8
 }
 
+// ConcealFunc is called by a Decoder that has error concealment enabled, once
+// for each macroblock whose data is missing or truncated. mbx and mby are the
+// macroblock's column and row, in units of 16x16 pixels.
+//
+// Returning true conceals the macroblock by predicting its pixels from those
+// of its already decoded neighbors, and decoding continues. Returning false
+// stops decoding, and DecodeFrame returns io.ErrUnexpectedEOF.
+type ConcealFunc func(mbx, mby int) bool
+
 // Decoder decodes VP8 bitstreams into frames. Decoding one frame consists of
 // calling Init, DecodeFrameHeader and then DecodeFrame in that order.
 // A Decoder can be re-used to decode multiple frames.
 type Decoder struct {
 	// r is the input bitsream.
 	r limitReader
+	// conceal is the error concealment callback. It is nil if error
+	// concealment is disabled.
+	conceal ConcealFunc
+	// concealStop is whether conceal returned false during this frame.
+	concealStop bool
+	// parallelism is the number of goroutines that reconstruct and loop
+	// filter the frame. Values of 1 or less mean the calling goroutine only.
+	parallelism int
+	// crop is the part of the frame that DecodeFrame needs to decode. It is
+	// empty if the whole frame is needed.
+	crop image.Rectangle
+	// nextMBY is the next row of macroblocks that DecodeFrameRows decodes.
+	// bottomY, bottomCb and bottomCr hold the unfiltered bottom row of pixels
+	// of the row of macroblocks above it, which predicting its pixels needs,
+	// as loop filtering the row above changed the image's pixels.
+	nextMBY                     int
+	bottomY, bottomCb, bottomCr []byte
 	// scratch is a scratch buffer.
 	scratch [8]byte
 	// img is the YCbCr image to decode into.
@@ -105,15 +149,23 @@ type Decoder struct {
 	// frames that aren't key frames will inherit the Width, Height,
 	// XScale and YScale of the most recent key frame.
 	frameHeader FrameHeader
-	// Other headers.
-	segmentHeader segmentHeader
-	filterHeader  filterHeader
+	// Other headers. otherHeadersParsed is whether they have been parsed
+	// since the frame header was, and otherHeadersErr is the result.
+	segmentHeader      segmentHeader
+	filterHeader       filterHeader
+	quantHeader        quantHeader
+	otherHeadersParsed bool
+	otherHeadersErr    error
 	// The image data is divided into a number of independent partitions.
 	// There is 1 "first partition" and between 1 and 8 "other partitions"
 	// for coefficient data.
 	fp  partition
 	op  [8]partition
 	nOP int
+	// fpData and opData hold the partitions' data, and opLens the other
+	// partitions' encoded lengths. They are re-used from frame to frame.
+	fpData, opData []byte
+	opLens         [3 * 7]byte
 	// Quantization factors.
 	quant [nSegment]quant
 	// DCT/WHT coefficient decoding probabilities.
@@ -156,9 +208,38 @@ func (d *Decoder) Init(r io.Reader, n int) {
 	d.r = limitReader{r, n}
 }
 
+// SetConcealFunc enables error concealment, calling f for every macroblock
+// that cannot be decoded. A nil f, the default, disables it.
+//
+// With error concealment enabled, DecodeFrame tolerates coefficient data, as
+// opposed to frame header data, that ends prematurely, such as in a truncated
+// file. Each partition is decoded for as long as its data lasts, and damaged
+// macroblocks are filled in from their neighbors instead of failing the
+// whole frame. Partitions are assigned to rows of macroblocks in turn, so an
+// image encoded with multiple partitions may still have later rows that
+// decode correctly.
+func (d *Decoder) SetConcealFunc(f ConcealFunc) {
+	d.conceal = f
+}
+
+// SetCrop limits the work that DecodeFrame and DecodeFrameInto do to what is
+// needed for the pixels within r, in the frame's coordinates. The returned
+// image still has the frame's bounds, but its pixels outside r are
+// unspecified. An empty r, the default, decodes the whole frame.
+//
+// Every macroblock's data is still parsed up to the last row of macroblocks
+// that r needs, as the bitstream can only be read in order, but decoding
+// stops after that row, and the macroblocks that none of r's pixels depend on,
+// through prediction or loop filtering, are not reconstructed or filtered.
+func (d *Decoder) SetCrop(r image.Rectangle) {
+	d.crop = r
+}
+
 // DecodeFrameHeader decodes the frame header.
 func (d *Decoder) DecodeFrameHeader() (fh FrameHeader, err error) {
 	// All frame headers are at least 3 bytes long.
+	d.otherHeadersParsed = false
+	d.nextMBY = 0
 	b := d.scratch[:3]
 	if err = d.r.ReadFull(b); err != nil {
 		return
@@ -194,8 +275,74 @@ func (d *Decoder) DecodeFrameHeader() (fh FrameHeader, err error) {
 	return d.frameHeader, nil
 }
 
+// FrameInfo holds a frame's headers: its frame header, and the quantization
+// and loop filter settings that apply to its macroblocks.
+type FrameInfo struct {
+	FrameHeader FrameHeader
+	// Segmentation is whether the frame's macroblocks are divided into up to
+	// four segments, with their own quantizer indexes and filter levels.
+	Segmentation bool
+	// Quantizer is the quantizer index of each segment, from 0 (the finest
+	// quantization) to 127 (the coarsest). Without segmentation, all four are
+	// the frame's quantizer index.
+	Quantizer [4]int
+	// Y1DCDelta, Y2DCDelta, Y2ACDelta, UVDCDelta and UVACDelta are the
+	// adjustments to the quantizer index for the luma DC coefficients, the
+	// second-order luma DC and AC coefficients and the chroma DC and AC
+	// coefficients.
+	Y1DCDelta, Y2DCDelta, Y2ACDelta, UVDCDelta, UVACDelta int
+	// SimpleFilter is whether the loop filter is the simple filter, instead
+	// of the normal one.
+	SimpleFilter bool
+	// FilterLevel is the loop filter level of each segment, from 0 (no
+	// filtering) to 63. Without segmentation, all four are the frame's
+	// filter level. Reference frame and prediction mode based adjustments to
+	// the level are not included.
+	FilterLevel [4]int
+	// FilterSharpness is the loop filter's sharpness, from 0 to 7.
+	FilterSharpness int
+	// NumPartitions is the number of partitions that the DCT/WHT coefficient
+	// data is divided into: 1, 2, 4 or 8.
+	NumPartitions int
+}
+
+// DecodeFrameInfo decodes the frame's headers. It must be called after
+// DecodeFrameHeader. It can be called before DecodeFrame or DecodeFrameInto,
+// which then use the decoded headers, or after them, to inspect the headers
+// of the frame that they decoded.
+func (d *Decoder) DecodeFrameInfo() (FrameInfo, error) {
+	if err := d.parseOtherHeaders(); err != nil {
+		return FrameInfo{}, err
+	}
+	fi := FrameInfo{
+		FrameHeader:     d.frameHeader,
+		Segmentation:    d.segmentHeader.useSegment,
+		Y1DCDelta:       int(d.quantHeader.dqy1DC),
+		Y2DCDelta:       int(d.quantHeader.dqy2DC),
+		Y2ACDelta:       int(d.quantHeader.dqy2AC),
+		UVDCDelta:       int(d.quantHeader.dquvDC),
+		UVACDelta:       int(d.quantHeader.dquvAC),
+		SimpleFilter:    d.filterHeader.simple,
+		FilterSharpness: int(d.filterHeader.sharpness),
+		NumPartitions:   d.nOP,
+	}
+	for i := range fi.Quantizer {
+		fi.Quantizer[i] = int(clip(d.quantHeader.index[i], 0, 127))
+		if d.filterHeader.level == 0 {
+			continue
+		}
+		level := d.filterHeader.level
+		if d.segmentHeader.useSegment {
+			level = d.filterHeader.perSegmentLevel[i]
+		}
+		fi.FilterLevel[i] = int(clip(int32(level), 0, 63))
+	}
+	return fi, nil
+}
+
 // ensureImg ensures that d.img is large enough to hold the decoded frame.
 func (d *Decoder) ensureImg() {
+	d.ensureMBState()
 	if d.img != nil {
 		p0, p1 := d.img.Rect.Min, d.img.Rect.Max
 		if p0.X == 0 && p0.Y == 0 && p1.X >= 16*d.mbw && p1.Y >= 16*d.mbh {
@@ -204,8 +351,45 @@ func (d *Decoder) ensureImg() {
 	}
 	m := image.NewYCbCr(image.Rect(0, 0, 16*d.mbw, 16*d.mbh), image.YCbCrSubsampleRatio420)
 	d.img = m.SubImage(image.Rect(0, 0, d.frameHeader.Width, d.frameHeader.Height)).(*image.YCbCr)
-	d.perMBFilterParams = make([]filterParam, d.mbw*d.mbh)
-	d.upMB = make([]mb, d.mbw)
+}
+
+// ensureMBState ensures that d.perMBFilterParams and d.upMB are large enough
+// for the frame's macroblocks, re-using their capacity if possible.
+func (d *Decoder) ensureMBState() {
+	if n := d.mbw * d.mbh; cap(d.perMBFilterParams) >= n {
+		d.perMBFilterParams = d.perMBFilterParams[:n]
+	} else {
+		d.perMBFilterParams = make([]filterParam, n)
+	}
+	if cap(d.upMB) >= d.mbw {
+		d.upMB = d.upMB[:d.mbw]
+	} else {
+		d.upMB = make([]mb, d.mbw)
+	}
+}
+
+// setImg sets dst to hold the decoded frame, re-using its planes if their
+// capacity is large enough, and sets d.img to dst.
+func (d *Decoder) setImg(dst *image.YCbCr) {
+	yStride, cStride := 16*d.mbw, 8*d.mbw
+	yLen, cLen := yStride*16*d.mbh, cStride*8*d.mbh
+	y, cb, cr := dst.Y, dst.Cb, dst.Cr
+	if cap(y) < yLen || cap(cb) < cLen || cap(cr) < cLen {
+		// Allocate the three planes at once, as image.NewYCbCr does.
+		b := make([]byte, yLen+2*cLen)
+		y, cb, cr = b[:yLen:yLen], b[yLen:yLen+cLen:yLen+cLen], b[yLen+cLen:]
+	}
+	*dst = image.YCbCr{
+		Y:              y[:yLen],
+		Cb:             cb[:cLen],
+		Cr:             cr[:cLen],
+		YStride:        yStride,
+		CStride:        cStride,
+		SubsampleRatio: image.YCbCrSubsampleRatio420,
+		Rect:           image.Rect(0, 0, d.frameHeader.Width, d.frameHeader.Height),
+	}
+	d.img = dst
+	d.ensureMBState()
 }
 
 // parseSegmentHeader parses the segment header, as specified in section 9.3.
@@ -283,14 +467,17 @@ func (d *Decoder) parseOtherPartitions() error {
 		return io.ErrUnexpectedEOF
 	}
 	if n > 0 {
-		buf := make([]byte, n)
+		buf := d.opLens[:n]
 		if err := d.r.ReadFull(buf); err != nil {
 			return err
 		}
 		for i := 0; i < d.nOP-1; i++ {
 			pl := int(buf[3*i+0]) | int(buf[3*i+1])<<8 | int(buf[3*i+2])<<16
 			if pl > partLens[d.nOP-1] {
-				return io.ErrUnexpectedEOF
+				if d.conceal == nil {
+					return io.ErrUnexpectedEOF
+				}
+				pl = partLens[d.nOP-1]
 			}
 			partLens[i] = pl
 			partLens[d.nOP-1] -= pl
@@ -306,28 +493,65 @@ func (d *Decoder) parseOtherPartitions() error {
 		return errors.New("vp8: too much data to decode")
 	}
 
-	buf := make([]byte, d.r.n)
-	if err := d.r.ReadFull(buf); err != nil {
-		return err
+	d.opData = grow(d.opData, d.r.n)
+	buf := d.opData
+	if d.conceal == nil {
+		if err := d.r.ReadFull(buf); err != nil {
+			return err
+		}
+	} else {
+		var err error
+		if buf, err = d.r.readAvailable(buf); err != nil {
+			return err
+		}
 	}
 	for i, pl := range partLens {
 		if i == d.nOP {
 			break
 		}
+		if pl > len(buf) {
+			// This can only happen when concealing errors in truncated data.
+			pl = len(buf)
+		}
 		d.op[i].init(buf[:pl])
 		buf = buf[pl:]
 	}
 	return nil
 }
 
-// parseOtherHeaders parses header information other than the frame header.
+// grow returns a slice of length n, re-using b if its capacity is large
+// enough.
+func grow(b []byte, n int) []byte {
+	if cap(b) < n {
+		return make([]byte, n)
+	}
+	return b[:n]
+}
+
+// parseOtherHeaders parses header information other than the frame header,
+// unless it has already been parsed since the frame header was. In that case,
+// it returns the earlier result.
 func (d *Decoder) parseOtherHeaders() error {
+	if !d.otherHeadersParsed {
+		d.otherHeadersParsed = true
+		d.otherHeadersErr = d.readOtherHeaders()
+	}
+	return d.otherHeadersErr
+}
+
+// readOtherHeaders reads and parses header information other than the frame
+// header.
+func (d *Decoder) readOtherHeaders() error {
 	// Initialize and parse the first partition.
-	firstPartition := make([]byte, d.frameHeader.FirstPartitionLen)
-	if err := d.r.ReadFull(firstPartition); err != nil {
+	n := int(d.frameHeader.FirstPartitionLen)
+	if n > d.r.n {
+		return io.ErrUnexpectedEOF
+	}
+	d.fpData = grow(d.fpData, n)
+	if err := d.r.ReadFull(d.fpData); err != nil {
 		return err
 	}
-	d.fp.init(firstPartition)
+	d.fp.init(d.fpData)
 	if d.frameHeader.KeyFrame {
 		// Read and ignore the color space and pixel clamp values. They are
 		// specified in section 9.2, but are unimplemented.
@@ -363,41 +587,253 @@ func (d *Decoder) parseOtherHeaders() error {
 // The image's contents are valid up until the next call to Decoder.Init.
 func (d *Decoder) DecodeFrame() (*image.YCbCr, error) {
 	d.ensureImg()
-	if err := d.parseOtherHeaders(); err != nil {
+	if err := d.decodeFrame(); err != nil {
 		return nil, err
 	}
+	return d.img, nil
+}
+
+// DecodeFrameInto is like DecodeFrame, but it decodes the frame into dst,
+// setting dst to a 4:2:0 Y'CbCr image whose bounds are the frame's bounds. It
+// re-uses dst's planes if their capacity is large enough for the frame,
+// padded to a whole number of macroblocks. Decoding a series of frames of the
+// same size, with the same Decoder, into the same dst therefore does not
+// allocate memory after the first frame. Unlike DecodeFrame's image, dst's contents remain valid after the next
+// call to Decoder.Init.
+//
+// If DecodeFrameInto returns an error, dst's contents are unspecified.
+func (d *Decoder) DecodeFrameInto(dst *image.YCbCr) error {
+	img := d.img
+	defer func() { d.img = img }()
+	d.setImg(dst)
+	return d.decodeFrame()
+}
+
+// decodeFrame decodes the frame into d.img.
+func (d *Decoder) decodeFrame() error {
+	if err := d.parseOtherHeaders(); err != nil {
+		return err
+	}
+	// Find the macroblocks that are needed. Loop filtering the edges of the
+	// macroblocks right of and below a macroblock changes its pixels, so the
+	// loop filter covers one more column and row than the crop rectangle. A
+	// macroblock's pixels are predicted from those of the macroblocks left,
+	// above-left, above and above-right of it, so each row above the last
+	// one reconstructs one more column than the row below it.
+	filterW, filterH := d.mbw, d.mbh
+	if !d.crop.Empty() {
+		filterW = minInt(maxInt((d.crop.Max.X+0x0f)>>4+1, 1), d.mbw)
+		filterH = minInt(maxInt((d.crop.Max.Y+0x0f)>>4+1, 1), d.mbh)
+	}
 	// Reconstruct the rows.
 	for mbx := 0; mbx < d.mbw; mbx++ {
 		d.upMB[mbx] = mb{}
 	}
-	for mby := 0; mby < d.mbh; mby++ {
-		d.leftMB = mb{}
-		for mbx := 0; mbx < d.mbw; mbx++ {
-			skip := d.reconstruct(mbx, mby)
-			fs := d.filterParams[d.segment][btou(!d.usePredY16)]
-			fs.inner = fs.inner || !skip
-			d.perMBFilterParams[d.mbw*mby+mbx] = fs
+	d.concealStop = false
+	parallel := d.parallelism > 1 && filterH > 1
+	if parallel {
+		rowW := func(mby int) int {
+			return minInt(filterW+filterH-1-mby, d.mbw)
 		}
-	}
-	if d.fp.unexpectedEOF {
-		return nil, io.ErrUnexpectedEOF
-	}
-	for i := 0; i < d.nOP; i++ {
-		if d.op[i].unexpectedEOF {
-			return nil, io.ErrUnexpectedEOF
+		if err := d.reconstructParallel(filterH, rowW); err != nil {
+			return err
+		}
+	} else {
+		for mby := 0; mby < filterH; mby++ {
+			if err := d.reconstructRow(mby, filterW+filterH-1-mby); err != nil {
+				return err
+			}
 		}
 	}
+	if err := d.checkUnexpectedEOF(); err != nil {
+		return err
+	}
 	// Apply the loop filter.
 	//
 	// Even if we are using per-segment levels, section 15 says that "loop
 	// filtering must be skipped entirely if loop_filter_level at either the
 	// frame header level or macroblock override level is 0".
+	if d.filterHeader.level != 0 {
+		switch {
+		case parallel && d.filterHeader.simple:
+			d.filterParallel(filterW, filterH, d.simpleFilterMB)
+		case parallel:
+			d.filterParallel(filterW, filterH, d.normalFilterMB)
+		case d.filterHeader.simple:
+			d.simpleFilter(filterW, filterH)
+		default:
+			d.normalFilter(filterW, filterH)
+		}
+	}
+	return nil
+}
+
+// reconstructRow parses the row mby of macroblocks, and reconstructs the
+// pixels of its leftmost reconstructW macroblocks.
+func (d *Decoder) reconstructRow(mby, reconstructW int) error {
+	d.leftMB = mb{}
+	for mbx := 0; mbx < d.mbw; mbx++ {
+		skip := d.reconstruct(mbx, mby, mbx < reconstructW)
+		if d.concealStop {
+			return io.ErrUnexpectedEOF
+		}
+		fs := d.filterParams[d.segment][btou(!d.usePredY16)]
+		fs.inner = fs.inner || !skip
+		d.perMBFilterParams[d.mbw*mby+mbx] = fs
+	}
+	return nil
+}
+
+// checkUnexpectedEOF returns io.ErrUnexpectedEOF if any partition's data
+// ended prematurely, unless error concealment is enabled.
+func (d *Decoder) checkUnexpectedEOF() error {
+	if d.conceal != nil {
+		return nil
+	}
+	if d.fp.unexpectedEOF {
+		return io.ErrUnexpectedEOF
+	}
+	for i := 0; i < d.nOP; i++ {
+		if d.op[i].unexpectedEOF {
+			return io.ErrUnexpectedEOF
+		}
+	}
+	return nil
+}
+
+// DecodeFrameRows is like DecodeFrame, but it decodes the frame a part at a
+// time, such as for showing the top of an image before the rest of it is
+// decoded. Each call decodes the next n rows of macroblocks, of 16 pixels
+// each, after those that the previous calls since DecodeFrameHeader decoded.
+//
+// It returns the frame and the number of rows of pixels, from the top of the
+// frame, whose values are final: the loop filter changes the bottom pixels of
+// a row of macroblocks when it filters the row below. The other pixels are
+// unspecified. The frame is completely decoded when that number is the
+// frame's height, and further calls do nothing. The image's contents are
+// valid up until the next call to Decoder.Init, and the final rows do not
+// change when later calls decode more rows.
+//
+// A frame that is decoded by DecodeFrameRows must not also be decoded by
+// DecodeFrame or DecodeFrameInto. DecodeFrameRows ignores the crop rectangle
+// and the parallelism.
+func (d *Decoder) DecodeFrameRows(n int) (*image.YCbCr, int, error) {
+	if d.nextMBY == 0 {
+		if err := d.parseOtherHeaders(); err != nil {
+			return nil, 0, err
+		}
+		d.ensureImg()
+		for mbx := 0; mbx < d.mbw; mbx++ {
+			d.upMB[mbx] = mb{}
+		}
+		d.concealStop = false
+	}
+	mby0, mby1 := d.nextMBY, minInt(d.nextMBY+maxInt(n, 0), d.mbh)
+	if mby0 == mby1 {
+		return d.img, d.finalRows(), nil
+	}
+
+	// Reconstruct the rows, from the unfiltered pixels of the row above.
+	if mby0 > 0 {
+		d.swapBottom(mby0 - 1)
+	}
+	for mby := mby0; mby < mby1; mby++ {
+		if err := d.reconstructRow(mby, d.mbw); err != nil {
+			return nil, 0, err
+		}
+	}
+	if err := d.checkUnexpectedEOF(); err != nil {
+		return nil, 0, err
+	}
+	if mby0 > 0 {
+		d.swapBottom(mby0 - 1)
+	}
+	if mby1 < d.mbh {
+		d.bottomY = grow(d.bottomY, 16*d.mbw)
+		d.bottomCb = grow(d.bottomCb, 8*d.mbw)
+		d.bottomCr = grow(d.bottomCr, 8*d.mbw)
+		yIndex, cIndex := (16*mby1-1)*d.img.YStride, (8*mby1-1)*d.img.CStride
+		copy(d.bottomY, d.img.Y[yIndex:])
+		copy(d.bottomCb, d.img.Cb[cIndex:])
+		copy(d.bottomCr, d.img.Cr[cIndex:])
+	}
+
+	// Apply the loop filter, as decodeFrame does, to the new rows.
+	if d.filterHeader.level != 0 {
+		filterMB := d.normalFilterMB
+		if d.filterHeader.simple {
+			filterMB = d.simpleFilterMB
+		}
+		for mby := mby0; mby < mby1; mby++ {
+			for mbx := 0; mbx < d.mbw; mbx++ {
+				filterMB(mbx, mby)
+			}
+		}
+	}
+	d.nextMBY = mby1
+	return d.img, d.finalRows(), nil
+}
+
+// swapBottom swaps the bottom row of pixels of the row mby of macroblocks
+// with d.bottomY, d.bottomCb and d.bottomCr.
+func (d *Decoder) swapBottom(mby int) {
+	y := d.img.Y[(16*mby+15)*d.img.YStride:]
+	for i, b := range d.bottomY {
+		y[i], d.bottomY[i] = b, y[i]
+	}
+	cb := d.img.Cb[(8*mby+7)*d.img.CStride:]
+	cr := d.img.Cr[(8*mby+7)*d.img.CStride:]
+	for i, b := range d.bottomCb {
+		cb[i], d.bottomCb[i] = b, cb[i]
+	}
+	for i, b := range d.bottomCr {
+		cr[i], d.bottomCr[i] = b, cr[i]
+	}
+}
+
+// finalRows returns the number of rows of pixels, from the top of the frame,
+// that DecodeFrameRows has finished decoding.
+func (d *Decoder) finalRows() int {
+	if d.nextMBY == d.mbh {
+		return d.frameHeader.Height
+	}
+	// Filtering the top edges of the next row of macroblocks changes up to 1
+	// row of luma pixels above them, for the simple filter, or 3 rows of luma
+	// pixels and 3 rows of chroma samples, which are 6 rows of pixels, for
+	// the normal filter.
+	rows := 16 * d.nextMBY
 	if d.filterHeader.level != 0 {
 		if d.filterHeader.simple {
-			d.simpleFilter()
+			rows -= 1
 		} else {
-			d.normalFilter()
+			rows -= 6
 		}
 	}
-	return d.img, nil
+	return minInt(maxInt(rows, 0), d.frameHeader.Height)
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// DecodeFrameRGBA is like DecodeFrame, but it converts the frame to a newly
+// allocated RGBA image.
+func (d *Decoder) DecodeFrameRGBA() (*image.RGBA, error) {
+	m, err := d.DecodeFrame()
+	if err != nil {
+		return nil, err
+	}
+	dst := image.NewRGBA(m.Rect)
+	ycbcr.ToRGBA(dst, m.Rect, m)
+	return dst, nil
 }
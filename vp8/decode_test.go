@@ -0,0 +1,373 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vp8
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"io"
+	"os"
+	"testing"
+)
+
+// readVP8 returns the payload of the VP8 chunk of a simple format (lossy)
+// WEBP file.
+func readVP8(t testing.TB, filename string) []byte {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b) < 20 || string(b[0:4]) != "RIFF" || string(b[8:16]) != "WEBPVP8 " {
+		t.Fatalf("%s: not a simple format WEBP file", filename)
+	}
+	n := int(binary.LittleEndian.Uint32(b[16:20]))
+	if n > len(b)-20 {
+		t.Fatalf("%s: short VP8 chunk", filename)
+	}
+	return b[20 : 20+n]
+}
+
+func decodeVP8(data []byte, conceal ConcealFunc) (*image.YCbCr, error) {
+	d := NewDecoder()
+	d.SetConcealFunc(conceal)
+	d.Init(bytes.NewReader(data), len(data))
+	if _, err := d.DecodeFrameHeader(); err != nil {
+		return nil, err
+	}
+	return d.DecodeFrame()
+}
+
+func TestConceal(t *testing.T) {
+	data := readVP8(t, "../testdata/video-001.lossy.webp")
+	want, err := decodeVP8(data, nil)
+	if err != nil {
+		t.Fatalf("decoding complete data: %v", err)
+	}
+
+	// Concealment should be a no-op for undamaged data.
+	got, err := decodeVP8(data, func(mbx, mby int) bool {
+		t.Errorf("complete data: unexpected concealment of macroblock (%d, %d)", mbx, mby)
+		return true
+	})
+	if err != nil {
+		t.Fatalf("decoding complete data with concealment: %v", err)
+	}
+	if !bytes.Equal(got.Y, want.Y) || !bytes.Equal(got.Cb, want.Cb) || !bytes.Equal(got.Cr, want.Cr) {
+		t.Fatalf("decoding complete data with concealment: pixels differ")
+	}
+
+	// Truncate the data. The truncated reader still claims the full length,
+	// just like a truncated file whose RIFF header is intact.
+	truncated := data[:len(data)*3/4]
+	if _, err := decodeVP8(truncated, nil); err != io.ErrUnexpectedEOF {
+		t.Fatalf("decoding truncated data without concealment: got %v, want %v", err, io.ErrUnexpectedEOF)
+	}
+	d := NewDecoder()
+	var concealed []image.Point
+	d.SetConcealFunc(func(mbx, mby int) bool {
+		concealed = append(concealed, image.Point{mbx, mby})
+		return true
+	})
+	d.Init(bytes.NewReader(truncated), len(data))
+	if _, err := d.DecodeFrameHeader(); err != nil {
+		t.Fatalf("decoding truncated data with concealment: %v", err)
+	}
+	got, err = d.DecodeFrame()
+	if err != nil {
+		t.Fatalf("decoding truncated data with concealment: %v", err)
+	}
+	if len(concealed) == 0 {
+		t.Fatalf("decoding truncated data with concealment: no macroblocks were concealed")
+	}
+	if b := got.Bounds(); b != want.Bounds() {
+		t.Fatalf("bounds: got %v, want %v", b, want.Bounds())
+	}
+
+	// Pixels above the first concealed macroblock's row, and far enough from
+	// it to be unaffected by loop filtering, should match the complete decode.
+	// Macroblocks are decoded, and so concealed, in raster order.
+	firstY := 16 * concealed[0].Y
+	if firstY < 16 {
+		t.Fatalf("first concealed row: got %d, want >= 16", firstY)
+	}
+	for y := 0; y < firstY-4; y++ {
+		i := y * got.YStride
+		if !bytes.Equal(got.Y[i:i+got.Rect.Dx()], want.Y[i:i+want.Rect.Dx()]) {
+			t.Fatalf("row %d: pixels differ from the complete decode", y)
+		}
+	}
+
+	// Returning false should stop decoding.
+	_, err = decodeVP8(truncated, func(mbx, mby int) bool { return false })
+	if err != io.ErrUnexpectedEOF {
+		t.Fatalf("stopping concealment: got %v, want %v", err, io.ErrUnexpectedEOF)
+	}
+}
+
+func TestParallelism(t *testing.T) {
+	decode := func(data []byte, n int, crop image.Rectangle, conceal ConcealFunc) (*image.YCbCr, error) {
+		d := NewDecoder()
+		d.SetParallelism(n)
+		d.SetCrop(crop)
+		d.SetConcealFunc(conceal)
+		d.Init(bytes.NewReader(data), len(data))
+		if _, err := d.DecodeFrameHeader(); err != nil {
+			return nil, err
+		}
+		return d.DecodeFrame()
+	}
+	equal := func(m0, m1 *image.YCbCr) bool {
+		return bytes.Equal(m0.Y, m1.Y) && bytes.Equal(m0.Cb, m1.Cb) && bytes.Equal(m0.Cr, m1.Cr)
+	}
+
+	for _, name := range []string{
+		"blue-purple-pink-large.no-filter",
+		"blue-purple-pink-large.normal-filter",
+		"blue-purple-pink-large.simple-filter",
+		"video-001",
+	} {
+		data := readVP8(t, "../testdata/"+name+".lossy.webp")
+		for _, crop := range []image.Rectangle{{}, image.Rect(20, 10, 60, 40)} {
+			want, err := decode(data, 1, crop, nil)
+			if err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			for _, n := range []int{2, 3, 8, 100} {
+				got, err := decode(data, n, crop, nil)
+				if err != nil {
+					t.Fatalf("%s, crop %v, parallelism %d: %v", name, crop, n, err)
+				}
+				if !equal(got, want) {
+					t.Errorf("%s, crop %v, parallelism %d: pixels differ", name, crop, n)
+				}
+			}
+		}
+	}
+
+	// Concealing damaged macroblocks, and stopping, work the same in parallel.
+	data := readVP8(t, "../testdata/video-001.lossy.webp")
+	truncated := data[:len(data)*3/4]
+	conceal := func(mbx, mby int) bool { return true }
+	want, err := decode(truncated, 1, image.Rectangle{}, conceal)
+	if err != nil {
+		t.Fatalf("truncated data: %v", err)
+	}
+	got, err := decode(truncated, 4, image.Rectangle{}, conceal)
+	if err != nil {
+		t.Fatalf("truncated data, in parallel: %v", err)
+	}
+	if !equal(got, want) {
+		t.Errorf("truncated data, in parallel: pixels differ")
+	}
+	_, err = decode(truncated, 4, image.Rectangle{}, func(mbx, mby int) bool { return false })
+	if err != io.ErrUnexpectedEOF {
+		t.Errorf("stopping concealment, in parallel: got %v, want %v", err, io.ErrUnexpectedEOF)
+	}
+}
+
+func TestDecodeFrameInfo(t *testing.T) {
+	testCases := []struct {
+		name         string
+		simpleFilter bool
+		filtered     bool
+	}{
+		{"blue-purple-pink-large.no-filter", false, false},
+		{"blue-purple-pink-large.normal-filter", false, true},
+		{"blue-purple-pink-large.simple-filter", true, true},
+	}
+	for _, tc := range testCases {
+		data := readVP8(t, "../testdata/"+tc.name+".lossy.webp")
+		want, err := decodeVP8(data, nil)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+
+		// Decode the frame info before the frame.
+		d := NewDecoder()
+		d.Init(bytes.NewReader(data), len(data))
+		fh, err := d.DecodeFrameHeader()
+		if err != nil {
+			t.Fatalf("%s: DecodeFrameHeader: %v", tc.name, err)
+		}
+		fi, err := d.DecodeFrameInfo()
+		if err != nil {
+			t.Fatalf("%s: DecodeFrameInfo: %v", tc.name, err)
+		}
+		if fi.FrameHeader != fh {
+			t.Errorf("%s: got frame header %+v, want %+v", tc.name, fi.FrameHeader, fh)
+		}
+		if fi.SimpleFilter != tc.simpleFilter {
+			t.Errorf("%s: SimpleFilter: got %t, want %t", tc.name, fi.SimpleFilter, tc.simpleFilter)
+		}
+		if got := fi.FilterLevel[0] != 0; got != tc.filtered {
+			t.Errorf("%s: FilterLevel: got %v, want filtering %t", tc.name, fi.FilterLevel, tc.filtered)
+		}
+		if n := fi.NumPartitions; n != 1 && n != 2 && n != 4 && n != 8 {
+			t.Errorf("%s: NumPartitions: got %d", tc.name, n)
+		}
+		got, err := d.DecodeFrame()
+		if err != nil {
+			t.Fatalf("%s: DecodeFrame: %v", tc.name, err)
+		}
+		if !bytes.Equal(got.Y, want.Y) || !bytes.Equal(got.Cb, want.Cb) || !bytes.Equal(got.Cr, want.Cr) {
+			t.Errorf("%s: pixels differ after DecodeFrameInfo", tc.name)
+		}
+
+		// Decoding it after the frame gives the same info.
+		if fi1, err := d.DecodeFrameInfo(); err != nil || fi1 != fi {
+			t.Errorf("%s: after DecodeFrame: got %+v, %v, want %+v", tc.name, fi1, err, fi)
+		}
+	}
+
+	// A frame from EncodeFrame has the given quantizer index, with no deltas or
+	// segments.
+	m := image.NewYCbCr(image.Rect(0, 0, 40, 24), image.YCbCrSubsampleRatio420)
+	buf := &bytes.Buffer{}
+	if err := EncodeFrame(buf, m, 42); err != nil {
+		t.Fatal(err)
+	}
+	d := NewDecoder()
+	d.Init(bytes.NewReader(buf.Bytes()), buf.Len())
+	if _, err := d.DecodeFrameHeader(); err != nil {
+		t.Fatal(err)
+	}
+	fi, err := d.DecodeFrameInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Segmentation || fi.Quantizer != [4]int{42, 42, 42, 42} || fi.Y1DCDelta != 0 || fi.UVACDelta != 0 {
+		t.Errorf("EncodeFrame(42): got %+v", fi)
+	}
+}
+
+func TestDecodeFrameInto(t *testing.T) {
+	d := NewDecoder()
+	dst := &image.YCbCr{}
+	for _, name := range []string{
+		"blue-purple-pink-large.normal-filter",
+		"video-001",
+		"blue-purple-pink-large.simple-filter",
+		"video-001",
+	} {
+		data := readVP8(t, "../testdata/"+name+".lossy.webp")
+		want, err := decodeVP8(data, nil)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		d.Init(bytes.NewReader(data), len(data))
+		if _, err := d.DecodeFrameHeader(); err != nil {
+			t.Fatalf("%s: DecodeFrameHeader: %v", name, err)
+		}
+		var y0 *byte
+		if len(dst.Y) > 0 {
+			y0 = &dst.Y[0]
+		}
+		if err := d.DecodeFrameInto(dst); err != nil {
+			t.Fatalf("%s: DecodeFrameInto: %v", name, err)
+		}
+		if dst.Rect != want.Rect || dst.SubsampleRatio != want.SubsampleRatio {
+			t.Fatalf("%s: got bounds %v, ratio %v, want %v, %v",
+				name, dst.Rect, dst.SubsampleRatio, want.Rect, want.SubsampleRatio)
+		}
+		if name == "video-001" && &dst.Y[0] != y0 {
+			// The large image's planes have room for the smaller image.
+			t.Errorf("%s: the planes were not re-used", name)
+		}
+		for y := want.Rect.Min.Y; y < want.Rect.Max.Y; y++ {
+			for x := want.Rect.Min.X; x < want.Rect.Max.X; x++ {
+				if got, want := dst.YCbCrAt(x, y), want.YCbCrAt(x, y); got != want {
+					t.Fatalf("%s: pixel (%d, %d): got %v, want %v", name, x, y, got, want)
+				}
+			}
+		}
+	}
+
+	// Decoding a frame of the same size into the same dst does not allocate.
+	data := readVP8(t, "../testdata/video-001.lossy.webp")
+	r := bytes.NewReader(data)
+	allocs := testing.AllocsPerRun(10, func() {
+		r.Reset(data)
+		d.Init(r, len(data))
+		if _, err := d.DecodeFrameHeader(); err != nil {
+			t.Fatal(err)
+		}
+		if err := d.DecodeFrameInto(dst); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("got %v allocations per frame, want 0", allocs)
+	}
+}
+
+func TestDecodeFrameRows(t *testing.T) {
+	for _, name := range []string{
+		"blue-purple-pink-large.no-filter",
+		"blue-purple-pink-large.normal-filter",
+		"blue-purple-pink-large.simple-filter",
+		"video-001",
+	} {
+		data := readVP8(t, "../testdata/"+name+".lossy.webp")
+		want, err := decodeVP8(data, nil)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		height := want.Rect.Dy()
+		// rowsEqual returns whether the first n rows of pixels of m and want
+		// are equal.
+		rowsEqual := func(m *image.YCbCr, n int) bool {
+			for y := 0; y < n; y++ {
+				for x := 0; x < want.Rect.Dx(); x++ {
+					if m.YCbCrAt(x, y) != want.YCbCrAt(x, y) {
+						return false
+					}
+				}
+			}
+			return true
+		}
+
+		for _, n := range []int{1, 2, 3, 100} {
+			d := NewDecoder()
+			d.Init(bytes.NewReader(data), len(data))
+			if _, err := d.DecodeFrameHeader(); err != nil {
+				t.Fatalf("%s: DecodeFrameHeader: %v", name, err)
+			}
+			prevRows := 0
+			for calls := 1; ; calls++ {
+				m, rows, err := d.DecodeFrameRows(n)
+				if err != nil {
+					t.Fatalf("%s, n=%d: DecodeFrameRows: %v", name, n, err)
+				}
+				if rows <= prevRows || rows > 16*n*calls {
+					t.Fatalf("%s, n=%d, call #%d: got %d rows after %d rows", name, n, calls, rows, prevRows)
+				}
+				if !rowsEqual(m, rows) {
+					t.Fatalf("%s, n=%d, call #%d: the first %d rows differ from DecodeFrame's", name, n, calls, rows)
+				}
+				prevRows = rows
+				if rows == height {
+					break
+				}
+			}
+			// Further calls do nothing.
+			if _, rows, err := d.DecodeFrameRows(n); rows != height || err != nil {
+				t.Errorf("%s, n=%d: after the last row: got %d rows, %v, want %d rows", name, n, rows, err, height)
+			}
+		}
+	}
+
+	// Truncated data fails, unless concealed.
+	data := readVP8(t, "../testdata/video-001.lossy.webp")
+	truncated := data[:len(data)*3/4]
+	d := NewDecoder()
+	d.Init(bytes.NewReader(truncated), len(truncated))
+	if _, err := d.DecodeFrameHeader(); err != nil {
+		t.Fatalf("truncated data: DecodeFrameHeader: %v", err)
+	}
+	if _, _, err := d.DecodeFrameRows(100); err == nil {
+		t.Error("truncated data: got nil error")
+	}
+}
@@ -0,0 +1,892 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vp8
+
+// This file implements encoding key frames.
+//
+// The encoder reconstructs each macroblock with the same prediction and
+// inverse transform functions as the Decoder, so that its predictions are
+// made from exactly the pixels that a decoder sees. Luma is predicted either
+// as one 16x16 region or as 16 4x4 regions, whichever has the lower
+// rate-distortion cost, and chroma as two 8x8 regions. The default token
+// probabilities are used, and all macroblocks are in the one segment.
+
+import (
+	"errors"
+	"image"
+	"io"
+)
+
+// boolEncoder writes arithmetic-coded bits. It is the inverse of a partition,
+// and follows the encoder specified in section 7.3.
+type boolEncoder struct {
+	// buf is the output bytes.
+	buf []byte
+	// rng is the range, in the arithmetic coding sense.
+	rng uint32
+	// bottom holds the low end of the interval, of which the top bitCount
+	// bits are not yet written to buf.
+	bottom   uint32
+	bitCount int
+}
+
+// init initializes the encoder.
+func (e *boolEncoder) init() {
+	e.buf = e.buf[:0]
+	e.rng = 255
+	e.bottom = 0
+	e.bitCount = 24
+}
+
+// writeBit writes a bit whose probability of being 0 is prob/256.
+func (e *boolEncoder) writeBit(bit bool, prob uint8) {
+	split := 1 + (e.rng-1)*uint32(prob)>>8
+	if bit {
+		e.bottom += split
+		e.rng -= split
+	} else {
+		e.rng = split
+	}
+	for e.rng < 128 {
+		e.rng <<= 1
+		if e.bottom&(1<<31) != 0 {
+			// Propagate the carry into the bytes already written.
+			i := len(e.buf) - 1
+			for ; i >= 0 && e.buf[i] == 0xff; i-- {
+				e.buf[i] = 0
+			}
+			if i >= 0 {
+				e.buf[i]++
+			}
+		}
+		e.bottom <<= 1
+		e.bitCount--
+		if e.bitCount == 0 {
+			e.buf = append(e.buf, uint8(e.bottom>>24))
+			e.bottom &= 1<<24 - 1
+			e.bitCount = 8
+		}
+	}
+}
+
+// writeUint writes the n-bit unsigned integer u.
+func (e *boolEncoder) writeUint(u uint32, prob, n uint8) {
+	for n > 0 {
+		n--
+		e.writeBit(u&(1<<n) != 0, prob)
+	}
+}
+
+// writeInt writes the n-bit signed integer x.
+func (e *boolEncoder) writeInt(x int32, prob, n uint8) {
+	if x < 0 {
+		e.writeUint(uint32(-x), prob, n)
+		e.writeBit(true, prob)
+	} else {
+		e.writeUint(uint32(x), prob, n)
+		e.writeBit(false, prob)
+	}
+}
+
+// writeOptionalInt writes the n-bit signed integer x in an encoding where the
+// likely value is zero.
+func (e *boolEncoder) writeOptionalInt(x int32, prob, n uint8) {
+	e.writeBit(x != 0, prob)
+	if x != 0 {
+		e.writeInt(x, prob, n)
+	}
+}
+
+// flush writes the bits that are still held in bottom.
+func (e *boolEncoder) flush() {
+	for i := 0; i < 32; i++ {
+		e.writeBit(false, uniformProb)
+	}
+}
+
+// mbHeader is the per-macroblock header that is written to the first
+// partition.
+type mbHeader struct {
+	skip       bool
+	usePredY16 bool
+	predY16    uint8
+	predC8     uint8
+	predY4     [4][4]uint8
+}
+
+// encoder is the state for encoding one frame.
+type encoder struct {
+	// d holds the reconstructed frame and the per-macroblock state shared
+	// with the Decoder's prediction and reconstruction functions. Its coeff
+	// holds the dequantized coefficients of the current macroblock.
+	d Decoder
+	// src is the frame to encode, padded to a whole number of macroblocks.
+	src *image.YCbCr
+	// srcYBR holds the current macroblock's source pixels, laid out like
+	// d.ybr.
+	srcYBR [1 + 16 + 1 + 8][32]uint8
+	// levels holds the current macroblock's quantized coefficients, laid out
+	// like d.coeff.
+	levels [1*16*16 + 2*8*8 + 1*4*4]int16
+	// quant and quantIndex are the quantization factors and their index.
+	quant      quant
+	quantIndex int
+	// lambda weighs rate, in bits, against distortion, in squared error, for
+	// choosing between prediction modes.
+	lambda int
+	// fp and op are the first partition and the other partitions.
+	fp  boolEncoder
+	op  [8]boolEncoder
+	nOP int
+	// mbs is the header of each macroblock.
+	mbs []mbHeader
+}
+
+// EncodeFrame writes m as a VP8 key frame to w. m must have 4:2:0 chroma
+// subsampling, and be between 1 and 16383 pixels wide and high.
+//
+// quantIndex is the quantizer index, between 0 and 127 inclusive, as specified
+// in section 9.6. Lower values give better quality and larger output.
+func EncodeFrame(w io.Writer, m *image.YCbCr, quantIndex int) error {
+	if m.SubsampleRatio != image.YCbCrSubsampleRatio420 {
+		return errors.New("vp8: unsupported chroma subsampling")
+	}
+	width, height := m.Rect.Dx(), m.Rect.Dy()
+	if width <= 0 || height <= 0 || width > 0x3fff || height > 0x3fff {
+		return errors.New("vp8: invalid image size")
+	}
+	if quantIndex < 0 || quantIndex > 127 {
+		return errors.New("vp8: invalid quantizer index")
+	}
+
+	e := &encoder{quantIndex: quantIndex}
+	d := &e.d
+	d.frameHeader = FrameHeader{
+		KeyFrame:  true,
+		ShowFrame: true,
+		Width:     width,
+		Height:    height,
+	}
+	d.mbw = (width + 0x0f) >> 4
+	d.mbh = (height + 0x0f) >> 4
+	d.ensureImg()
+	e.src = padFrame(m, d.mbw, d.mbh)
+	e.quant = makeQuant(int32(quantIndex), 0, 0, 0, 0, 0)
+	e.lambda = int(e.quant.y1[1]) * int(e.quant.y1[1]) / 32
+	e.mbs = make([]mbHeader, d.mbw*d.mbh)
+
+	// Use more partitions for larger frames, so that no partition's size
+	// overflows the 24 bits that the format allows.
+	e.nOP = 1
+	for e.nOP < 8 && 2*e.nOP <= d.mbh && d.mbw*d.mbh > 4096*e.nOP {
+		e.nOP *= 2
+	}
+	for i := 0; i < e.nOP; i++ {
+		e.op[i].init()
+	}
+
+	nSkip := 0
+	for mby := 0; mby < d.mbh; mby++ {
+		d.leftMB = mb{}
+		for mbx := 0; mbx < d.mbw; mbx++ {
+			h := &e.mbs[d.mbw*mby+mbx]
+			e.encodeMacroblock(mbx, mby, h)
+			if h.skip {
+				// Skipped macroblocks have no coefficients, and reset the
+				// non-zero contexts as in Decoder.reconstruct.
+				nSkip++
+				if h.usePredY16 {
+					d.leftMB.nzY16 = 0
+					d.upMB[mbx].nzY16 = 0
+				}
+				d.leftMB.nzMask = 0
+				d.upMB[mbx].nzMask = 0
+			} else {
+				e.writeResiduals(&e.op[mby&(e.nOP-1)], mbx, h.usePredY16)
+			}
+		}
+	}
+	for i := 0; i < e.nOP; i++ {
+		e.op[i].flush()
+		if len(e.op[i].buf) >= 1<<24 {
+			return errors.New("vp8: too much data to encode")
+		}
+	}
+
+	// The probability that a macroblock is not skipped.
+	skipProb := 255 * (len(e.mbs) - nSkip) / len(e.mbs)
+	if skipProb < 1 {
+		skipProb = 1
+	}
+	e.writeFirstPartition(uint8(skipProb))
+	if len(e.fp.buf) >= 1<<19 {
+		return errors.New("vp8: too much data to encode")
+	}
+
+	// Write the frame header, as specified in section 9.1, and then the
+	// partitions.
+	n := len(e.fp.buf)
+	hdr := make([]byte, 10)
+	hdr[0] = 1<<4 | uint8(n<<5) // A shown key frame, version number 0.
+	hdr[1] = uint8(n >> 3)
+	hdr[2] = uint8(n >> 11)
+	hdr[3], hdr[4], hdr[5] = 0x9d, 0x01, 0x2a
+	hdr[6] = uint8(width)
+	hdr[7] = uint8(width >> 8)
+	hdr[8] = uint8(height)
+	hdr[9] = uint8(height >> 8)
+	if _, err := w.Write(hdr); err != nil {
+		return err
+	}
+	if _, err := w.Write(e.fp.buf); err != nil {
+		return err
+	}
+	// All but the last of the other partitions' sizes are explicit.
+	partLens := make([]byte, 0, 3*(e.nOP-1))
+	for i := 0; i < e.nOP-1; i++ {
+		n := len(e.op[i].buf)
+		partLens = append(partLens, uint8(n), uint8(n>>8), uint8(n>>16))
+	}
+	if _, err := w.Write(partLens); err != nil {
+		return err
+	}
+	for i := 0; i < e.nOP; i++ {
+		if _, err := w.Write(e.op[i].buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// padFrame returns a copy of m, whose origin is at (0, 0) and whose size is
+// a whole number of macroblocks. The pixels beyond m's bounds repeat those at
+// its right and bottom edges.
+func padFrame(m *image.YCbCr, mbw, mbh int) *image.YCbCr {
+	dst := image.NewYCbCr(image.Rect(0, 0, 16*mbw, 16*mbh), image.YCbCrSubsampleRatio420)
+	r := m.Rect
+	for y := 0; y < 16*mbh; y++ {
+		sy := r.Min.Y + y
+		if sy >= r.Max.Y {
+			sy = r.Max.Y - 1
+		}
+		row := dst.Y[y*dst.YStride : (y+1)*dst.YStride]
+		n := copy(row, m.Y[m.YOffset(r.Min.X, sy):m.YOffset(r.Max.X-1, sy)+1])
+		for x := n; x < len(row); x++ {
+			row[x] = row[n-1]
+		}
+	}
+	for cy := 0; cy < 8*mbh; cy++ {
+		sy := r.Min.Y + 2*cy
+		if sy >= r.Max.Y {
+			sy = r.Max.Y - 1
+		}
+		for cx := 0; cx < 8*mbw; cx++ {
+			sx := r.Min.X + 2*cx
+			if sx >= r.Max.X {
+				sx = r.Max.X - 1
+			}
+			i := m.COffset(sx, sy)
+			dst.Cb[cy*dst.CStride+cx] = m.Cb[i]
+			dst.Cr[cy*dst.CStride+cx] = m.Cr[i]
+		}
+	}
+	return dst
+}
+
+// encodeMacroblock chooses the prediction modes for a macroblock, quantizes
+// its residuals to e.levels and reconstructs it to e.d.img, as a decoder
+// would.
+func (e *encoder) encodeMacroblock(mbx, mby int, h *mbHeader) {
+	d := &e.d
+	for i := range e.levels {
+		e.levels[i] = 0
+	}
+	for i, y := (mby*e.src.YStride+mbx)*16, 0; y < 16; i, y = i+e.src.YStride, y+1 {
+		copy(e.srcYBR[ybrYY+y][ybrYX:ybrYX+16], e.src.Y[i:i+16])
+	}
+	for i, y := (mby*e.src.CStride+mbx)*8, 0; y < 8; i, y = i+e.src.CStride, y+1 {
+		copy(e.srcYBR[ybrBY+y][ybrBX:ybrBX+8], e.src.Cb[i:i+8])
+		copy(e.srcYBR[ybrRY+y][ybrRX:ybrRX+8], e.src.Cr[i:i+8])
+	}
+	d.prepareYBR(mbx, mby)
+
+	// Try 16x16 luma prediction, and then 4x4, keeping the 16x16 result if
+	// it is cheaper.
+	var (
+		ybr16    [16][16]uint8
+		levels16 [16*16 + 4*4]int16
+	)
+	cost16 := e.encodeY16(mbx, mby, h)
+	for y := 0; y < 16; y++ {
+		copy(ybr16[y][:], d.ybr[ybrYY+y][ybrYX:ybrYX+16])
+	}
+	copy(levels16[:16*16], e.levels[:16*16])
+	copy(levels16[16*16:], e.levels[whtCoeffBase:])
+	if cost4 := e.encodeY4(h); cost4 < cost16 {
+		h.usePredY16 = false
+	} else {
+		h.usePredY16 = true
+		for y := 0; y < 16; y++ {
+			copy(d.ybr[ybrYY+y][ybrYX:ybrYX+16], ybr16[y][:])
+		}
+		copy(e.levels[:16*16], levels16[:16*16])
+		copy(e.levels[whtCoeffBase:], levels16[16*16:])
+	}
+	e.encodeC8(mbx, mby, h)
+
+	h.skip = true
+	for _, l := range e.levels {
+		if l != 0 {
+			h.skip = false
+			break
+		}
+	}
+
+	// Copy the reconstructed macroblock to the image, as the decoder does.
+	for i, y := (mby*d.img.YStride+mbx)*16, 0; y < 16; i, y = i+d.img.YStride, y+1 {
+		copy(d.img.Y[i:i+16], d.ybr[ybrYY+y][ybrYX:ybrYX+16])
+	}
+	for i, y := (mby*d.img.CStride+mbx)*8, 0; y < 8; i, y = i+d.img.CStride, y+1 {
+		copy(d.img.Cb[i:i+8], d.ybr[ybrBY+y][ybrBX:ybrBX+8])
+		copy(d.img.Cr[i:i+8], d.ybr[ybrRY+y][ybrRX:ybrRX+8])
+	}
+}
+
+// encodeY16 encodes the luma with 16x16 prediction, choosing the mode whose
+// prediction is closest to the source, and returns the rate-distortion cost.
+func (e *encoder) encodeY16(mbx, mby int, h *mbHeader) int {
+	d := &e.d
+	best, bestSSE := uint8(0), -1
+	for _, p := range [...]uint8{predDC, predTM, predVE, predHE} {
+		predFunc16[checkTopLeftPred(mbx, mby, p)](d, ybrYY, ybrYX)
+		if sse := e.sse(ybrYY, ybrYX, 16); bestSSE < 0 || sse < bestSSE {
+			best, bestSSE = p, sse
+		}
+	}
+	h.predY16 = best
+	predFunc16[checkTopLeftPred(mbx, mby, best)](d, ybrYY, ybrYX)
+
+	// Transform and quantize each 4x4 region, with the DC coefficients going
+	// to the Y2 region.
+	var dc [16]int32
+	for n := 0; n < 16; n++ {
+		y, x := ybrYY+4*(n/4), ybrYX+4*(n%4)
+		c := e.forwardDCT4(y, x)
+		dc[n] = c[0]
+		for i := 1; i < 16; i++ {
+			e.levels[16*n+i] = quantize(c[i], e.quant.y1[1], acBias)
+		}
+	}
+	c := forwardWHT(&dc)
+	for i := range c {
+		if i == 0 {
+			e.levels[whtCoeffBase] = quantize(c[0], e.quant.y2[0], dcBias)
+		} else {
+			e.levels[whtCoeffBase+i] = quantize(c[i], e.quant.y2[1], acBias)
+		}
+	}
+
+	// Reconstruct the luma.
+	for i := 0; i < 16*16; i++ {
+		d.coeff[i] = 0
+	}
+	for i := 0; i < 16; i++ {
+		d.coeff[whtCoeffBase+i] = dequantize(e.levels[whtCoeffBase+i], e.quant.y2, i)
+	}
+	d.inverseWHT16()
+	rate := 3 + e.rate(e.levels[whtCoeffBase:whtCoeffBase+16], 0)
+	for n := 0; n < 16; n++ {
+		for i := 1; i < 16; i++ {
+			d.coeff[16*n+i] = dequantize(e.levels[16*n+i], e.quant.y1, i)
+		}
+		d.inverseDCT4(ybrYY+4*(n/4), ybrYX+4*(n%4), 16*n)
+		rate += e.rate(e.levels[16*n:16*n+16], 1)
+	}
+	return e.sse(ybrYY, ybrYX, 16) + e.lambda*rate
+}
+
+// encodeY4 encodes the luma with 4x4 prediction, choosing each region's mode
+// in turn, and returns the rate-distortion cost.
+func (e *encoder) encodeY4(h *mbHeader) int {
+	d := &e.d
+	rate := 0
+	for n := 0; n < 16; n++ {
+		j, i := n/4, n%4
+		y, x := ybrYY+4*j, ybrYX+4*i
+		best, bestCost := uint8(0), -1
+		for p := uint8(0); p < nPred; p++ {
+			predFunc4[p](d, y, x)
+			// Prefer the simpler modes, which are cheaper to write.
+			cost := e.sse(y, x, 4) + e.lambda*int(p/4+1)
+			if bestCost < 0 || cost < bestCost {
+				best, bestCost = p, cost
+			}
+		}
+		h.predY4[j][i] = best
+		predFunc4[best](d, y, x)
+
+		c := e.forwardDCT4(y, x)
+		for k := range c {
+			q := e.quant.y1[btou(k > 0)]
+			bias := acBias
+			if k == 0 {
+				bias = dcBias
+			}
+			e.levels[16*n+k] = quantize(c[k], q, bias)
+			d.coeff[16*n+k] = dequantize(e.levels[16*n+k], e.quant.y1, k)
+		}
+		d.inverseDCT4(y, x, 16*n)
+		rate += 3 + e.rate(e.levels[16*n:16*n+16], 0)
+	}
+	return e.sse(ybrYY, ybrYX, 16) + e.lambda*rate
+}
+
+// encodeC8 encodes the chroma, choosing the mode whose prediction is closest
+// to the source.
+func (e *encoder) encodeC8(mbx, mby int, h *mbHeader) {
+	d := &e.d
+	best, bestSSE := uint8(0), -1
+	for _, p := range [...]uint8{predDC, predTM, predVE, predHE} {
+		q := checkTopLeftPred(mbx, mby, p)
+		predFunc8[q](d, ybrBY, ybrBX)
+		predFunc8[q](d, ybrRY, ybrRX)
+		if sse := e.sse(ybrBY, ybrBX, 8) + e.sse(ybrRY, ybrRX, 8); bestSSE < 0 || sse < bestSSE {
+			best, bestSSE = p, sse
+		}
+	}
+	h.predC8 = best
+	q := checkTopLeftPred(mbx, mby, best)
+	for _, plane := range [2]struct{ y, x, coeffBase int }{
+		{ybrBY, ybrBX, bCoeffBase},
+		{ybrRY, ybrRX, rCoeffBase},
+	} {
+		predFunc8[q](d, plane.y, plane.x)
+		for n := 0; n < 4; n++ {
+			y, x := plane.y+4*(n/2), plane.x+4*(n%2)
+			base := plane.coeffBase + 16*n
+			c := e.forwardDCT4(y, x)
+			for k := range c {
+				q := e.quant.uv[btou(k > 0)]
+				bias := acBias
+				if k == 0 {
+					bias = dcBias
+				}
+				e.levels[base+k] = quantize(c[k], q, bias)
+				d.coeff[base+k] = dequantize(e.levels[base+k], e.quant.uv, k)
+			}
+			d.inverseDCT4(y, x, base)
+		}
+	}
+}
+
+// sse returns the sum of the squared differences between the source and the
+// reconstructed or predicted pixels of the size×size region at (y, x) of the
+// ybr workspace.
+func (e *encoder) sse(y, x, size int) int {
+	sum := 0
+	for j := y; j < y+size; j++ {
+		for i := x; i < x+size; i++ {
+			v := int(e.srcYBR[j][i]) - int(e.d.ybr[j][i])
+			sum += v * v
+		}
+	}
+	return sum
+}
+
+// rate estimates the number of bits needed to write the quantized
+// coefficients, from the index first onwards, of a 4x4 region.
+func (e *encoder) rate(levels []int16, first int) int {
+	bits := 1
+	for _, l := range levels[first:] {
+		if l < 0 {
+			l = -l
+		}
+		bits++
+		for ; l != 0; l >>= 1 {
+			bits += 2
+		}
+	}
+	return bits
+}
+
+// The quantization biases, in 1/256ths of the quantization factor, that round
+// coefficients towards zero.
+const (
+	dcBias int32 = 128
+	acBias int32 = 96
+)
+
+// maxLevel is the largest magnitude of a quantized coefficient that the token
+// encoding can represent.
+const maxLevel = 2048
+
+// quantize returns the quantized value of the coefficient c.
+func quantize(c int32, q uint16, bias int32) int16 {
+	neg := c < 0
+	if neg {
+		c = -c
+	}
+	l := (c*256 + bias*int32(q)) / (256 * int32(q))
+	if l > maxLevel {
+		l = maxLevel
+	}
+	if neg {
+		l = -l
+	}
+	return int16(l)
+}
+
+// dequantize returns the coefficient that a decoder reconstructs from the
+// quantized value l at index i of a 4x4 region, as parseResiduals4 does.
+func dequantize(l int16, q [2]uint16, i int) int16 {
+	return int16(int32(l) * int32(q[btou(i > 0)]))
+}
+
+// forwardDCT4 returns the forward DCT of the difference between the source
+// and the predicted pixels of the 4x4 region at (y, x) of the ybr workspace.
+// It is the transform in the libvpx encoder, which inverseDCT4 inverts.
+func (e *encoder) forwardDCT4(y, x int) (c [16]int32) {
+	var m [16]int32
+	for j := 0; j < 4; j++ {
+		s, p := &e.srcYBR[y+j], &e.d.ybr[y+j]
+		d0 := int32(s[x+0]) - int32(p[x+0])
+		d1 := int32(s[x+1]) - int32(p[x+1])
+		d2 := int32(s[x+2]) - int32(p[x+2])
+		d3 := int32(s[x+3]) - int32(p[x+3])
+		a := (d0 + d3) * 8
+		b := (d1 + d2) * 8
+		cc := (d1 - d2) * 8
+		dd := (d0 - d3) * 8
+		m[4*j+0] = a + b
+		m[4*j+2] = a - b
+		m[4*j+1] = (cc*2217 + dd*5352 + 14500) >> 12
+		m[4*j+3] = (dd*2217 - cc*5352 + 7500) >> 12
+	}
+	for i := 0; i < 4; i++ {
+		a := m[i] + m[12+i]
+		b := m[4+i] + m[8+i]
+		cc := m[4+i] - m[8+i]
+		dd := m[i] - m[12+i]
+		c[i] = (a + b + 7) >> 4
+		c[8+i] = (a - b + 7) >> 4
+		c[4+i] = (cc*2217+dd*5352+12000)>>16 + int32(btou(dd != 0))
+		c[12+i] = (dd*2217 - cc*5352 + 51000) >> 16
+	}
+	return c
+}
+
+// forwardWHT returns the forward Walsh-Hadamard transform of the DC
+// coefficients of a macroblock's 16 luma regions. inverseWHT16 inverts it.
+func forwardWHT(dc *[16]int32) (c [16]int32) {
+	var m [16]int32
+	for j := 0; j < 4; j++ {
+		a := (dc[4*j+0] + dc[4*j+2]) * 4
+		d := (dc[4*j+1] + dc[4*j+3]) * 4
+		cc := (dc[4*j+1] - dc[4*j+3]) * 4
+		b := (dc[4*j+0] - dc[4*j+2]) * 4
+		m[4*j+0] = a + d + int32(btou(a != 0))
+		m[4*j+1] = b + cc
+		m[4*j+2] = b - cc
+		m[4*j+3] = a - d
+	}
+	for i := 0; i < 4; i++ {
+		a := m[i] + m[8+i]
+		d := m[4+i] + m[12+i]
+		cc := m[4+i] - m[12+i]
+		b := m[i] - m[8+i]
+		for k, v := range [4]int32{a + d, b + cc, b - cc, a - d} {
+			if v < 0 {
+				v++
+			}
+			c[4*k+i] = v >> 3
+		}
+	}
+	return c
+}
+
+// writeResiduals writes the current macroblock's quantized coefficients to p,
+// maintaining the non-zero contexts as parseResiduals does.
+func (e *encoder) writeResiduals(p *boolEncoder, mbx int, usePredY16 bool) {
+	d := &e.d
+	plane := planeY1SansY2
+	if usePredY16 {
+		nz := e.writeResiduals4(p, planeY2, d.leftMB.nzY16+d.upMB[mbx].nzY16, e.levels[whtCoeffBase:], false)
+		d.leftMB.nzY16 = nz
+		d.upMB[mbx].nzY16 = nz
+		plane = planeY1WithY2
+	}
+
+	coeffBase := 0
+	lnz := unpack[d.leftMB.nzMask&0x0f]
+	unz := unpack[d.upMB[mbx].nzMask&0x0f]
+	for y := 0; y < 4; y++ {
+		nz := lnz[y]
+		for x := 0; x < 4; x++ {
+			nz = e.writeResiduals4(p, plane, nz+unz[x], e.levels[coeffBase:], usePredY16)
+			unz[x] = nz
+			coeffBase += 16
+		}
+		lnz[y] = nz
+	}
+	lnzMask := pack(lnz, 0)
+	unzMask := pack(unz, 0)
+
+	lnz = unpack[d.leftMB.nzMask>>4]
+	unz = unpack[d.upMB[mbx].nzMask>>4]
+	for c := 0; c < 4; c += 2 {
+		for y := 0; y < 2; y++ {
+			nz := lnz[y+c]
+			for x := 0; x < 2; x++ {
+				nz = e.writeResiduals4(p, planeUV, nz+unz[x+c], e.levels[coeffBase:], false)
+				unz[x+c] = nz
+				coeffBase += 16
+			}
+			lnz[y+c] = nz
+		}
+	}
+	lnzMask |= pack(lnz, 4)
+	unzMask |= pack(unz, 4)
+
+	d.leftMB.nzMask = uint8(lnzMask)
+	d.upMB[mbx].nzMask = uint8(unzMask)
+}
+
+// writeResiduals4 writes the quantized coefficients of a 4x4 region, as
+// specified in section 13, and returns a 0/1 value indicating whether there
+// was at least one non-zero coefficient. It is the inverse of
+// parseResiduals4.
+func (e *encoder) writeResiduals4(w *boolEncoder, plane int, context uint8, levels []int16, skipFirstCoeff bool) uint8 {
+	prob, n := &defaultTokenProb[plane], 0
+	if skipFirstCoeff {
+		n = 1
+	}
+	last := -1
+	for i := n; i < 16; i++ {
+		if levels[zigzag[i]] != 0 {
+			last = i
+		}
+	}
+	p := prob[bands[n]][context]
+	if last < 0 {
+		w.writeBit(false, p[0])
+		return 0
+	}
+	w.writeBit(true, p[0])
+	for {
+		l := int32(levels[zigzag[n]])
+		n++
+		if l == 0 {
+			w.writeBit(false, p[1])
+			p = prob[bands[n]][0]
+			continue
+		}
+		w.writeBit(true, p[1])
+		v := l
+		if v < 0 {
+			v = -v
+		}
+		if v == 1 {
+			w.writeBit(false, p[2])
+			p = prob[bands[n]][1]
+		} else {
+			w.writeBit(true, p[2])
+			switch {
+			case v <= 4:
+				w.writeBit(false, p[3])
+				if v == 2 {
+					w.writeBit(false, p[4])
+				} else {
+					w.writeBit(true, p[4])
+					w.writeBit(v == 4, p[5])
+				}
+			case v <= 10:
+				w.writeBit(true, p[3])
+				w.writeBit(false, p[6])
+				if v <= 6 {
+					// Category 1.
+					w.writeBit(false, p[7])
+					w.writeBit(v == 6, 159)
+				} else {
+					// Category 2.
+					w.writeBit(true, p[7])
+					w.writeBit((v-7)&2 != 0, 165)
+					w.writeBit((v-7)&1 != 0, 145)
+				}
+			default:
+				// Categories 3, 4, 5 or 6.
+				w.writeBit(true, p[3])
+				w.writeBit(true, p[6])
+				cat := uint32(0)
+				for cat < 3 && v >= 3+(16<<cat) {
+					cat++
+				}
+				w.writeBit(cat >= 2, p[8])
+				w.writeBit(cat&1 != 0, p[9+cat>>1])
+				tab := &cat3456[cat]
+				nBits := 0
+				for tab[nBits] != 0 {
+					nBits++
+				}
+				u := uint32(v) - 3 - 8<<cat
+				for i := 0; i < nBits; i++ {
+					w.writeBit(u&(1<<uint(nBits-1-i)) != 0, tab[i])
+				}
+			}
+			p = prob[bands[n]][2]
+		}
+		w.writeBit(l < 0, uniformProb)
+		if n == 16 {
+			return 1
+		}
+		if n > last {
+			w.writeBit(false, p[0])
+			return 1
+		}
+		w.writeBit(true, p[0])
+	}
+}
+
+// writeFirstPartition writes the frame's headers, other than the frame header,
+// and the macroblock headers to e.fp. It is the inverse of parseOtherHeaders
+// and the parsing of the predictor modes.
+func (e *encoder) writeFirstPartition(skipProb uint8) {
+	fp := &e.fp
+	fp.init()
+	// The color space and pixel clamp values.
+	fp.writeBit(false, uniformProb)
+	fp.writeBit(false, uniformProb)
+	// The segment header: segments are not used.
+	fp.writeBit(false, uniformProb)
+	// The filter header: a normal filter whose level rises with the
+	// quantization factor, and no adjustments.
+	level := e.quant.y1[1] / 2
+	if level > 63 {
+		level = 63
+	}
+	fp.writeBit(false, uniformProb)
+	fp.writeUint(uint32(level), uniformProb, 6)
+	fp.writeUint(0, uniformProb, 3)
+	fp.writeBit(false, uniformProb)
+	// The number of other partitions, as a power of two.
+	nOPLog2 := uint32(0)
+	for 1<<nOPLog2 < e.nOP {
+		nOPLog2++
+	}
+	fp.writeUint(nOPLog2, uniformProb, 2)
+	// The quantizer index, without deltas.
+	fp.writeUint(uint32(e.quantIndex), uniformProb, 7)
+	for i := 0; i < 5; i++ {
+		fp.writeOptionalInt(0, uniformProb, 4)
+	}
+	// The refresh_entropy_probs bit.
+	fp.writeBit(false, uniformProb)
+	// The token probabilities are not updated.
+	for i := range tokenProbUpdateProb {
+		for j := range tokenProbUpdateProb[i] {
+			for k := range tokenProbUpdateProb[i][j] {
+				for l := range tokenProbUpdateProb[i][j][k] {
+					fp.writeBit(false, tokenProbUpdateProb[i][j][k][l])
+				}
+			}
+		}
+	}
+	fp.writeBit(true, uniformProb)
+	fp.writeUint(uint32(skipProb), uniformProb, 8)
+
+	// Write the macroblock headers.
+	mbw := e.d.mbw
+	upPred := make([][4]uint8, mbw)
+	var leftPred [4]uint8
+	for i, h := range e.mbs {
+		mbx := i % mbw
+		if mbx == 0 {
+			leftPred = [4]uint8{}
+		}
+		fp.writeBit(h.skip, skipProb)
+		fp.writeBit(h.usePredY16, 145)
+		if h.usePredY16 {
+			switch h.predY16 {
+			case predDC:
+				fp.writeBit(false, 156)
+				fp.writeBit(false, 163)
+			case predVE:
+				fp.writeBit(false, 156)
+				fp.writeBit(true, 163)
+			case predHE:
+				fp.writeBit(true, 156)
+				fp.writeBit(false, 128)
+			case predTM:
+				fp.writeBit(true, 156)
+				fp.writeBit(true, 128)
+			}
+			for j := 0; j < 4; j++ {
+				upPred[mbx][j] = h.predY16
+				leftPred[j] = h.predY16
+			}
+		} else {
+			for j := 0; j < 4; j++ {
+				for i := 0; i < 4; i++ {
+					p := h.predY4[j][i]
+					writePredModeY4(fp, p, &predProb[upPred[mbx][i]][leftPred[j]])
+					upPred[mbx][i] = p
+					leftPred[j] = p
+				}
+			}
+		}
+		switch h.predC8 {
+		case predDC:
+			fp.writeBit(false, 142)
+		case predVE:
+			fp.writeBit(true, 142)
+			fp.writeBit(false, 114)
+		case predHE:
+			fp.writeBit(true, 142)
+			fp.writeBit(true, 114)
+			fp.writeBit(false, 183)
+		case predTM:
+			fp.writeBit(true, 142)
+			fp.writeBit(true, 114)
+			fp.writeBit(true, 183)
+		}
+	}
+	fp.flush()
+}
+
+// writePredModeY4 writes a 4x4 region's predictor mode p, given the
+// probabilities for the modes of the regions above and left of it. It is the
+// inverse of the tree in parsePredModeY4.
+func writePredModeY4(fp *boolEncoder, p uint8, prob *[9]uint8) {
+	fp.writeBit(p != predDC, prob[0])
+	if p == predDC {
+		return
+	}
+	fp.writeBit(p != predTM, prob[1])
+	if p == predTM {
+		return
+	}
+	fp.writeBit(p != predVE, prob[2])
+	if p == predVE {
+		return
+	}
+	switch p {
+	case predHE, predRD, predVR:
+		fp.writeBit(false, prob[3])
+		fp.writeBit(p != predHE, prob[4])
+		if p != predHE {
+			fp.writeBit(p == predVR, prob[5])
+		}
+	default:
+		fp.writeBit(true, prob[3])
+		fp.writeBit(p != predLD, prob[6])
+		if p == predLD {
+			return
+		}
+		fp.writeBit(p != predVL, prob[7])
+		if p == predVL {
+			return
+		}
+		fp.writeBit(p == predHU, prob[8])
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vp8
+
+import (
+	"bytes"
+	"image"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestBoolEncoder(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	bits := make([]bool, 10000)
+	probs := make([]uint8, len(bits))
+	for i := range bits {
+		probs[i] = uint8(rng.Intn(256))
+		// Make the bits as likely as their probabilities say, mostly.
+		bits[i] = rng.Intn(256) >= int(probs[i])
+	}
+	var e boolEncoder
+	e.init()
+	for i, b := range bits {
+		e.writeBit(b, probs[i])
+	}
+	e.writeUint(0x5a, uniformProb, 7)
+	e.writeOptionalInt(-9, uniformProb, 4)
+	e.flush()
+
+	var p partition
+	p.init(e.buf)
+	for i, b := range bits {
+		if got := p.readBit(probs[i]); got != b {
+			t.Fatalf("bit #%d: got %t, want %t", i, got, b)
+		}
+	}
+	if got := p.readUint(uniformProb, 7); got != 0x5a {
+		t.Errorf("readUint: got %#x, want 0x5a", got)
+	}
+	if got := p.readOptionalInt(uniformProb, 4); got != -9 {
+		t.Errorf("readOptionalInt: got %d, want -9", got)
+	}
+	if p.unexpectedEOF {
+		t.Error("unexpected EOF")
+	}
+}
+
+// psnr returns the peak signal-to-noise ratio, in decibels, of the Y'CbCr
+// samples of m1 compared to those of m0, which have the same bounds.
+func psnr(m0, m1 *image.YCbCr) float64 {
+	sse, n := 0.0, 0
+	b := m0.Rect
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c0, c1 := m0.YCbCrAt(x, y), m1.YCbCrAt(x, y)
+			for _, d := range [3]float64{
+				float64(c0.Y) - float64(c1.Y),
+				float64(c0.Cb) - float64(c1.Cb),
+				float64(c0.Cr) - float64(c1.Cr),
+			} {
+				sse += d * d
+			}
+			n += 3
+		}
+	}
+	if sse == 0 {
+		return math.Inf(1)
+	}
+	return 10 * math.Log10(255*255*float64(n)/sse)
+}
+
+func TestEncodeFrame(t *testing.T) {
+	src, err := decodeVP8(readVP8(t, "../testdata/yellow_rose.lossy.webp"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Lower quantizer indexes should give larger and better images.
+	prevSize, prevPSNR := 0, 0.0
+	for _, q := range []int{127, 64, 32, 0} {
+		buf := &bytes.Buffer{}
+		if err := EncodeFrame(buf, src, q); err != nil {
+			t.Fatalf("q=%d: %v", q, err)
+		}
+		m, err := decodeVP8(buf.Bytes(), nil)
+		if err != nil {
+			t.Fatalf("q=%d: decoding: %v", q, err)
+		}
+		if m.Rect != src.Rect {
+			t.Fatalf("q=%d: bounds: got %v, want %v", q, m.Rect, src.Rect)
+		}
+		p := psnr(src, m)
+		if buf.Len() <= prevSize || p <= prevPSNR {
+			t.Errorf("q=%d: got %d bytes with PSNR %.2f, previous quantizer index gave %d bytes with PSNR %.2f",
+				q, buf.Len(), p, prevSize, prevPSNR)
+		}
+		prevSize, prevPSNR = buf.Len(), p
+	}
+	if prevPSNR < 40 {
+		t.Errorf("q=0: got PSNR %.2f, want at least 40", prevPSNR)
+	}
+}
+
+func TestEncodeFrameSizes(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, r := range []image.Rectangle{
+		image.Rect(0, 0, 1, 1),
+		image.Rect(0, 0, 17, 3),
+		image.Rect(5, 7, 40, 57),
+		image.Rect(0, 0, 300, 1),
+	} {
+		full := image.NewYCbCr(image.Rect(0, 0, r.Max.X, r.Max.Y), image.YCbCrSubsampleRatio420)
+		// A smooth image, with some noise.
+		for y := 0; y < r.Max.Y; y++ {
+			for x := 0; x < r.Max.X; x++ {
+				full.Y[full.YOffset(x, y)] = uint8(2*x + 3*y + rng.Intn(8))
+				i := full.COffset(x, y)
+				full.Cb[i] = uint8(128 + x - y)
+				full.Cr[i] = uint8(64 + y)
+			}
+		}
+		src := full.SubImage(r).(*image.YCbCr)
+		buf := &bytes.Buffer{}
+		if err := EncodeFrame(buf, src, 4); err != nil {
+			t.Errorf("%v: %v", r, err)
+			continue
+		}
+		m, err := decodeVP8(buf.Bytes(), nil)
+		if err != nil {
+			t.Errorf("%v: decoding: %v", r, err)
+			continue
+		}
+		if got, want := m.Rect.Size(), r.Size(); got != want {
+			t.Errorf("%v: size: got %v, want %v", r, got, want)
+			continue
+		}
+		want := image.NewYCbCr(image.Rectangle{Max: r.Size()}, image.YCbCrSubsampleRatio420)
+		for y := 0; y < r.Dy(); y++ {
+			for x := 0; x < r.Dx(); x++ {
+				want.Y[want.YOffset(x, y)] = src.Y[src.YOffset(r.Min.X+x, r.Min.Y+y)]
+				want.Cb[want.COffset(x, y)] = src.Cb[src.COffset(r.Min.X+x&^1, r.Min.Y+y&^1)]
+				want.Cr[want.COffset(x, y)] = src.Cr[src.COffset(r.Min.X+x&^1, r.Min.Y+y&^1)]
+			}
+		}
+		if p := psnr(want, m); p < 35 {
+			t.Errorf("%v: got PSNR %.2f, want at least 35", r, p)
+		}
+	}
+}
+
+func TestEncodeFrameErrors(t *testing.T) {
+	testCases := []struct {
+		desc string
+		m    *image.YCbCr
+		q    int
+	}{
+		{"empty", image.NewYCbCr(image.Rect(0, 0, 0, 8), image.YCbCrSubsampleRatio420), 0},
+		{"too wide", image.NewYCbCr(image.Rect(0, 0, 0x4000, 1), image.YCbCrSubsampleRatio420), 0},
+		{"4:4:4", image.NewYCbCr(image.Rect(0, 0, 8, 8), image.YCbCrSubsampleRatio444), 0},
+		{"quantizer index", image.NewYCbCr(image.Rect(0, 0, 8, 8), image.YCbCrSubsampleRatio420), 128},
+	}
+	for _, tc := range testCases {
+		if err := EncodeFrame(&bytes.Buffer{}, tc.m, tc.q); err == nil {
+			t.Errorf("%s: got nil error, want non-nil", tc.desc)
+		}
+	}
+}
+
+func BenchmarkEncodeFrame(b *testing.B) {
+	src, err := decodeVP8(readVP8(b, "../testdata/yellow_rose.lossy.webp"), nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := EncodeFrame(&bytes.Buffer{}, src, 32); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
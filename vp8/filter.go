@@ -4,8 +4,23 @@
 
 package vp8
 
+// The filterSIMD modes.
+const (
+	filterSIMDMode2 = iota
+	filterSIMDMode4
+	filterSIMDMode6
+)
+
 // filter2 modifies a 2-pixel wide or 2-pixel high band along an edge.
 func filter2(pix []byte, level, index, iStep, jStep int) {
+	if haveSIMD {
+		// filterSIMD also reads the p3, p2, q2 and q3 pixels, which are in
+		// bounds for every edge that the decoder filters.
+		if lo, hi := index-4*jStep, index+15*iStep+3*jStep+1; lo >= 0 && hi <= len(pix) {
+			filterSIMD(pix[lo:hi], iStep, jStep, 16, level, 0, 0, filterSIMDMode2)
+			return
+		}
+	}
 	for n := 16; n > 0; n, index = n-1, index+iStep {
 		p1 := int(pix[index-2*jStep])
 		p0 := int(pix[index-1*jStep])
@@ -24,6 +39,14 @@ func filter2(pix []byte, level, index, iStep, jStep int) {
 
 // filter246 modifies a 2-, 4- or 6-pixel wide or high band along an edge.
 func filter246(pix []byte, n, level, ilevel, hlevel, index, iStep, jStep int, fourNotSix bool) {
+	if haveSIMD && n%8 == 0 {
+		mode := filterSIMDMode6
+		if fourNotSix {
+			mode = filterSIMDMode4
+		}
+		filterSIMD(pix[index-4*jStep:index+(n-1)*iStep+3*jStep+1], iStep, jStep, n, level, ilevel, hlevel, mode)
+		return
+	}
 	for ; n > 0; n, index = n-1, index+iStep {
 		p3 := int(pix[index-4*jStep])
 		p2 := int(pix[index-3*jStep])
@@ -77,75 +100,89 @@ func filter246(pix []byte, n, level, ilevel, hlevel, index, iStep, jStep int, fo
 	}
 }
 
-// simpleFilter implements the simple filter, as specified in section 15.2.
-func (d *Decoder) simpleFilter() {
-	for mby := 0; mby < d.mbh; mby++ {
-		for mbx := 0; mbx < d.mbw; mbx++ {
-			f := d.perMBFilterParams[d.mbw*mby+mbx]
-			if f.level == 0 {
-				continue
-			}
-			l := int(f.level)
-			yIndex := (mby*d.img.YStride + mbx) * 16
-			if mbx > 0 {
-				filter2(d.img.Y, l+4, yIndex, d.img.YStride, 1)
-			}
-			if f.inner {
-				filter2(d.img.Y, l, yIndex+0x4, d.img.YStride, 1)
-				filter2(d.img.Y, l, yIndex+0x8, d.img.YStride, 1)
-				filter2(d.img.Y, l, yIndex+0xc, d.img.YStride, 1)
-			}
-			if mby > 0 {
-				filter2(d.img.Y, l+4, yIndex, 1, d.img.YStride)
-			}
-			if f.inner {
-				filter2(d.img.Y, l, yIndex+d.img.YStride*0x4, 1, d.img.YStride)
-				filter2(d.img.Y, l, yIndex+d.img.YStride*0x8, 1, d.img.YStride)
-				filter2(d.img.Y, l, yIndex+d.img.YStride*0xc, 1, d.img.YStride)
-			}
+// simpleFilter implements the simple filter, as specified in section 15.2,
+// for the top-left mbw×mbh macroblocks.
+func (d *Decoder) simpleFilter(mbw, mbh int) {
+	for mby := 0; mby < mbh; mby++ {
+		for mbx := 0; mbx < mbw; mbx++ {
+			d.simpleFilterMB(mbx, mby)
 		}
 	}
 }
 
-// normalFilter implements the normal filter, as specified in section 15.3.
-func (d *Decoder) normalFilter() {
-	for mby := 0; mby < d.mbh; mby++ {
-		for mbx := 0; mbx < d.mbw; mbx++ {
-			f := d.perMBFilterParams[d.mbw*mby+mbx]
-			if f.level == 0 {
-				continue
-			}
-			l, il, hl := int(f.level), int(f.ilevel), int(f.hlevel)
-			yIndex := (mby*d.img.YStride + mbx) * 16
-			cIndex := (mby*d.img.CStride + mbx) * 8
-			if mbx > 0 {
-				filter246(d.img.Y, 16, l+4, il, hl, yIndex, d.img.YStride, 1, false)
-				filter246(d.img.Cb, 8, l+4, il, hl, cIndex, d.img.CStride, 1, false)
-				filter246(d.img.Cr, 8, l+4, il, hl, cIndex, d.img.CStride, 1, false)
-			}
-			if f.inner {
-				filter246(d.img.Y, 16, l, il, hl, yIndex+0x4, d.img.YStride, 1, true)
-				filter246(d.img.Y, 16, l, il, hl, yIndex+0x8, d.img.YStride, 1, true)
-				filter246(d.img.Y, 16, l, il, hl, yIndex+0xc, d.img.YStride, 1, true)
-				filter246(d.img.Cb, 8, l, il, hl, cIndex+0x4, d.img.CStride, 1, true)
-				filter246(d.img.Cr, 8, l, il, hl, cIndex+0x4, d.img.CStride, 1, true)
-			}
-			if mby > 0 {
-				filter246(d.img.Y, 16, l+4, il, hl, yIndex, 1, d.img.YStride, false)
-				filter246(d.img.Cb, 8, l+4, il, hl, cIndex, 1, d.img.CStride, false)
-				filter246(d.img.Cr, 8, l+4, il, hl, cIndex, 1, d.img.CStride, false)
-			}
-			if f.inner {
-				filter246(d.img.Y, 16, l, il, hl, yIndex+d.img.YStride*0x4, 1, d.img.YStride, true)
-				filter246(d.img.Y, 16, l, il, hl, yIndex+d.img.YStride*0x8, 1, d.img.YStride, true)
-				filter246(d.img.Y, 16, l, il, hl, yIndex+d.img.YStride*0xc, 1, d.img.YStride, true)
-				filter246(d.img.Cb, 8, l, il, hl, cIndex+d.img.CStride*0x4, 1, d.img.CStride, true)
-				filter246(d.img.Cr, 8, l, il, hl, cIndex+d.img.CStride*0x4, 1, d.img.CStride, true)
-			}
+// simpleFilterMB applies the simple filter to one macroblock's left and top
+// edges and, if needed, its inner edges.
+func (d *Decoder) simpleFilterMB(mbx, mby int) {
+	f := d.perMBFilterParams[d.mbw*mby+mbx]
+	if f.level == 0 {
+		return
+	}
+	l := int(f.level)
+	yIndex := (mby*d.img.YStride + mbx) * 16
+	if mbx > 0 {
+		filter2(d.img.Y, l+4, yIndex, d.img.YStride, 1)
+	}
+	if f.inner {
+		filter2(d.img.Y, l, yIndex+0x4, d.img.YStride, 1)
+		filter2(d.img.Y, l, yIndex+0x8, d.img.YStride, 1)
+		filter2(d.img.Y, l, yIndex+0xc, d.img.YStride, 1)
+	}
+	if mby > 0 {
+		filter2(d.img.Y, l+4, yIndex, 1, d.img.YStride)
+	}
+	if f.inner {
+		filter2(d.img.Y, l, yIndex+d.img.YStride*0x4, 1, d.img.YStride)
+		filter2(d.img.Y, l, yIndex+d.img.YStride*0x8, 1, d.img.YStride)
+		filter2(d.img.Y, l, yIndex+d.img.YStride*0xc, 1, d.img.YStride)
+	}
+}
+
+// normalFilter implements the normal filter, as specified in section 15.3,
+// for the top-left mbw×mbh macroblocks.
+func (d *Decoder) normalFilter(mbw, mbh int) {
+	for mby := 0; mby < mbh; mby++ {
+		for mbx := 0; mbx < mbw; mbx++ {
+			d.normalFilterMB(mbx, mby)
 		}
 	}
 }
 
+// normalFilterMB applies the normal filter to one macroblock's left and top
+// edges and, if needed, its inner edges.
+func (d *Decoder) normalFilterMB(mbx, mby int) {
+	f := d.perMBFilterParams[d.mbw*mby+mbx]
+	if f.level == 0 {
+		return
+	}
+	l, il, hl := int(f.level), int(f.ilevel), int(f.hlevel)
+	yIndex := (mby*d.img.YStride + mbx) * 16
+	cIndex := (mby*d.img.CStride + mbx) * 8
+	if mbx > 0 {
+		filter246(d.img.Y, 16, l+4, il, hl, yIndex, d.img.YStride, 1, false)
+		filter246(d.img.Cb, 8, l+4, il, hl, cIndex, d.img.CStride, 1, false)
+		filter246(d.img.Cr, 8, l+4, il, hl, cIndex, d.img.CStride, 1, false)
+	}
+	if f.inner {
+		filter246(d.img.Y, 16, l, il, hl, yIndex+0x4, d.img.YStride, 1, true)
+		filter246(d.img.Y, 16, l, il, hl, yIndex+0x8, d.img.YStride, 1, true)
+		filter246(d.img.Y, 16, l, il, hl, yIndex+0xc, d.img.YStride, 1, true)
+		filter246(d.img.Cb, 8, l, il, hl, cIndex+0x4, d.img.CStride, 1, true)
+		filter246(d.img.Cr, 8, l, il, hl, cIndex+0x4, d.img.CStride, 1, true)
+	}
+	if mby > 0 {
+		filter246(d.img.Y, 16, l+4, il, hl, yIndex, 1, d.img.YStride, false)
+		filter246(d.img.Cb, 8, l+4, il, hl, cIndex, 1, d.img.CStride, false)
+		filter246(d.img.Cr, 8, l+4, il, hl, cIndex, 1, d.img.CStride, false)
+	}
+	if f.inner {
+		filter246(d.img.Y, 16, l, il, hl, yIndex+d.img.YStride*0x4, 1, d.img.YStride, true)
+		filter246(d.img.Y, 16, l, il, hl, yIndex+d.img.YStride*0x8, 1, d.img.YStride, true)
+		filter246(d.img.Y, 16, l, il, hl, yIndex+d.img.YStride*0xc, 1, d.img.YStride, true)
+		filter246(d.img.Cb, 8, l, il, hl, cIndex+d.img.CStride*0x4, 1, d.img.CStride, true)
+		filter246(d.img.Cr, 8, l, il, hl, cIndex+d.img.CStride*0x4, 1, d.img.CStride, true)
+	}
+}
+
 // filterParam holds the loop filter parameters for a macroblock.
 type filterParam struct {
 	// The first three fields are thresholds used by the loop filter to smooth
@@ -22,6 +22,11 @@ func (z *Decoder) inverseDCT4(y, x, coeffBase int) {
 		c1 = 85627 // 65536 * cos(pi/8) * sqrt(2).
 		c2 = 35468 // 65536 * sin(pi/8) * sqrt(2).
 	)
+	if haveSIMD {
+		_, _ = z.ybr[y+3][x+3], z.coeff[coeffBase+15]
+		inverseDCT4SIMD(&z.ybr[y][x], &z.coeff[coeffBase])
+		return
+	}
 	var m [4][4]int32
 	for i := 0; i < 4; i++ {
 		a := int32(z.coeff[coeffBase+0]) + int32(z.coeff[coeffBase+8])
@@ -0,0 +1,238 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vp8
+
+// This file implements reconstructing and loop filtering the rows of
+// macroblocks on multiple goroutines.
+//
+// The bitstream can only be parsed in order: the first partition holds every
+// macroblock's predictor modes, and the contexts for parsing a macroblock's
+// residuals depend on the macroblocks left of and above it, even when its row
+// has its own token partition. Parsing therefore stays on the calling
+// goroutine, which saves each parsed row of macroblocks for other goroutines
+// to reconstruct. Those goroutines each take the next row to work on, and
+// work on it from left to right in a wavefront: a macroblock's pixels are
+// predicted from those of the macroblocks above and above-right of it, so
+// each row stays at least two macroblocks behind the row above it. Loop
+// filtering a macroblock changes the pixels of the macroblocks above and left
+// of it, and it follows reconstruction in the same wavefront order.
+
+import (
+	"io"
+	"sync"
+)
+
+// SetParallelism sets the number of goroutines, other than the calling one,
+// that DecodeFrame and DecodeFrameInto use to reconstruct and loop filter the
+// frame's rows of macroblocks. A value of 1 or less, the default, decodes the
+// whole frame on the calling goroutine.
+//
+// The decoded pixels do not depend on the parallelism. Decoding in parallel
+// uses more memory, for each goroutine's workspace and for parsed
+// macroblocks that are waiting to be reconstructed.
+func (d *Decoder) SetParallelism(n int) {
+	d.parallelism = n
+}
+
+// mbData is a macroblock's parsed predictor modes and residuals: all that
+// reconstructing its pixels needs, other than its neighbors' pixels.
+type mbData struct {
+	usePredY16         bool
+	predY16            uint8
+	predC8             uint8
+	predY4             [4][4]uint8
+	nzDCMask, nzACMask uint32
+	coeff              [1*16*16 + 2*8*8 + 1*4*4]int16
+}
+
+// save saves the parsed state of d's current macroblock to m.
+func (m *mbData) save(d *Decoder) {
+	m.usePredY16 = d.usePredY16
+	m.predY16 = d.predY16
+	m.predC8 = d.predC8
+	m.predY4 = d.predY4
+	m.nzDCMask = d.nzDCMask
+	m.nzACMask = d.nzACMask
+	m.coeff = d.coeff
+}
+
+// load sets d's current macroblock's parsed state from m.
+func (m *mbData) load(d *Decoder) {
+	d.usePredY16 = m.usePredY16
+	d.predY16 = m.predY16
+	d.predC8 = m.predC8
+	d.predY4 = m.predY4
+	d.nzDCMask = m.nzDCMask
+	d.nzACMask = m.nzACMask
+	d.coeff = m.coeff
+}
+
+// rowProgress tracks the progress of the goroutines that work on the rows of
+// macroblocks, so that they can wait for each other.
+type rowProgress struct {
+	mu   sync.Mutex
+	cond sync.Cond
+	// ready is the number of rows, from the top, that can be worked on.
+	ready int
+	// next is the next row for a goroutine to take.
+	next int
+	// done[mby] is the number of macroblocks of row mby that are done.
+	done []int
+	// stop is whether the work was abandoned.
+	stop bool
+}
+
+func newRowProgress(mbh, ready int) *rowProgress {
+	p := &rowProgress{ready: ready, done: make([]int, mbh)}
+	p.cond.L = &p.mu
+	return p
+}
+
+// take returns the next row to work on, after waiting for it to be ready. It
+// returns false if there are no more rows or the work was abandoned.
+func (p *rowProgress) take() (mby int, ok bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.next == len(p.done) {
+		return 0, false
+	}
+	mby, p.next = p.next, p.next+1
+	for !p.stop && p.ready <= mby {
+		p.cond.Wait()
+	}
+	return mby, !p.stop
+}
+
+// wait waits for n macroblocks of row mby to be done. It returns false if the
+// work was abandoned.
+func (p *rowProgress) wait(mby, n int) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for !p.stop && p.done[mby] < n {
+		p.cond.Wait()
+	}
+	return !p.stop
+}
+
+// advance records that one more macroblock of row mby is done.
+func (p *rowProgress) advance(mby int) {
+	p.mu.Lock()
+	p.done[mby]++
+	p.mu.Unlock()
+	p.cond.Broadcast()
+}
+
+// setReady records that the rows above row mby can be worked on.
+func (p *rowProgress) setReady(mby int) {
+	p.mu.Lock()
+	p.ready = mby
+	p.mu.Unlock()
+	p.cond.Broadcast()
+}
+
+// abandon stops the goroutines that are waiting or that later wait.
+func (p *rowProgress) abandon() {
+	p.mu.Lock()
+	p.stop = true
+	p.mu.Unlock()
+	p.cond.Broadcast()
+}
+
+// reconstructParallel parses the top filterH rows of macroblocks, like the
+// serial loop in decodeFrame, and reconstructs them on up to d.parallelism
+// other goroutines. Row mby's pixels are reconstructed for its leftmost
+// rowW(mby) macroblocks.
+func (d *Decoder) reconstructParallel(filterH int, rowW func(mby int) int) error {
+	n := minInt(d.parallelism, filterH)
+	// The parsed rows are kept in a ring, so that parsing can get ahead of
+	// reconstructing by a bounded number of rows.
+	ring := make([][]mbData, 2*n)
+	for i := range ring {
+		ring[i] = make([]mbData, d.mbw)
+	}
+	p := newRowProgress(filterH, 0)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w := &Decoder{img: d.img, mbw: d.mbw}
+			for {
+				mby, ok := p.take()
+				if !ok {
+					return
+				}
+				row, width := ring[mby%len(ring)], rowW(mby)
+				for mbx := 0; mbx < width; mbx++ {
+					if mby > 0 && !p.wait(mby-1, minInt(mbx+2, rowW(mby-1))) {
+						return
+					}
+					row[mbx].load(w)
+					w.reconstructPixels(mbx, mby)
+					p.advance(mby)
+				}
+			}
+		}()
+	}
+
+	var err error
+	for mby := 0; mby < filterH && err == nil; mby++ {
+		// Wait for the row that last used this row's place in the ring.
+		if prev := mby - len(ring); prev >= 0 && !p.wait(prev, rowW(prev)) {
+			break
+		}
+		row, width := ring[mby%len(ring)], rowW(mby)
+		d.leftMB = mb{}
+		for mbx := 0; mbx < d.mbw; mbx++ {
+			skip := d.reconstruct(mbx, mby, false)
+			if d.concealStop {
+				err = io.ErrUnexpectedEOF
+				break
+			}
+			fs := d.filterParams[d.segment][btou(!d.usePredY16)]
+			fs.inner = fs.inner || !skip
+			d.perMBFilterParams[d.mbw*mby+mbx] = fs
+			if mbx < width {
+				row[mbx].save(d)
+			}
+		}
+		if err == nil {
+			p.setReady(mby + 1)
+		}
+	}
+	if err != nil {
+		p.abandon()
+	}
+	wg.Wait()
+	return err
+}
+
+// filterParallel loop filters the top-left filterW×filterH macroblocks on up
+// to d.parallelism goroutines, calling filterMB for each macroblock.
+func (d *Decoder) filterParallel(filterW, filterH int, filterMB func(mbx, mby int)) {
+	n := minInt(d.parallelism, filterH)
+	p := newRowProgress(filterH, filterH)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				mby, ok := p.take()
+				if !ok {
+					return
+				}
+				for mbx := 0; mbx < filterW; mbx++ {
+					if mby > 0 {
+						p.wait(mby-1, minInt(mbx+2, filterW))
+					}
+					filterMB(mbx, mby)
+					p.advance(mby)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -13,6 +13,14 @@ type quant struct {
 	uv [2]uint16
 }
 
+// quantHeader holds the quantization header information, as specified in
+// section 9.6.
+type quantHeader struct {
+	// index is each segment's quantizer index, before clipping.
+	index                                  [nSegment]int32
+	dqy1DC, dqy2DC, dqy2AC, dquvDC, dquvAC int32
+}
+
 // clip clips x to the range [min, max] inclusive.
 func clip(x, min, max int32) int32 {
 	if x < min {
@@ -28,7 +36,6 @@ func clip(x, min, max int32) int32 {
 func (d *Decoder) parseQuant() {
 	baseQ0 := d.fp.readUint(uniformProb, 7)
 	dqy1DC := d.fp.readOptionalInt(uniformProb, 4)
-	const dqy1AC = 0
 	dqy2DC := d.fp.readOptionalInt(uniformProb, 4)
 	dqy2AC := d.fp.readOptionalInt(uniformProb, 4)
 	dquvDC := d.fp.readOptionalInt(uniformProb, 4)
@@ -42,19 +49,33 @@ func (d *Decoder) parseQuant() {
 				q = int32(d.segmentHeader.quantizer[i])
 			}
 		}
-		d.quant[i].y1[0] = dequantTableDC[clip(q+dqy1DC, 0, 127)]
-		d.quant[i].y1[1] = dequantTableAC[clip(q+dqy1AC, 0, 127)]
-		d.quant[i].y2[0] = dequantTableDC[clip(q+dqy2DC, 0, 127)] * 2
-		d.quant[i].y2[1] = dequantTableAC[clip(q+dqy2AC, 0, 127)] * 155 / 100
-		if d.quant[i].y2[1] < 8 {
-			d.quant[i].y2[1] = 8
-		}
-		// The 117 is not a typo. The dequant_init function in the spec's Reference
-		// Decoder Source Code (http://tools.ietf.org/html/rfc6386#section-9.6 Page 145)
-		// says to clamp the LHS value at 132, which is equal to dequantTableDC[117].
-		d.quant[i].uv[0] = dequantTableDC[clip(q+dquvDC, 0, 117)]
-		d.quant[i].uv[1] = dequantTableAC[clip(q+dquvAC, 0, 127)]
+		d.quantHeader.index[i] = q
+		d.quant[i] = makeQuant(q, dqy1DC, dqy2DC, dqy2AC, dquvDC, dquvAC)
 	}
+	d.quantHeader.dqy1DC = dqy1DC
+	d.quantHeader.dqy2DC = dqy2DC
+	d.quantHeader.dqy2AC = dqy2AC
+	d.quantHeader.dquvDC = dquvDC
+	d.quantHeader.dquvAC = dquvAC
+}
+
+// makeQuant returns the quantization factors for the quantizer index q and the
+// deltas to it, as specified in section 9.6.
+func makeQuant(q, dqy1DC, dqy2DC, dqy2AC, dquvDC, dquvAC int32) (x quant) {
+	const dqy1AC = 0
+	x.y1[0] = dequantTableDC[clip(q+dqy1DC, 0, 127)]
+	x.y1[1] = dequantTableAC[clip(q+dqy1AC, 0, 127)]
+	x.y2[0] = dequantTableDC[clip(q+dqy2DC, 0, 127)] * 2
+	x.y2[1] = dequantTableAC[clip(q+dqy2AC, 0, 127)] * 155 / 100
+	if x.y2[1] < 8 {
+		x.y2[1] = 8
+	}
+	// The 117 is not a typo. The dequant_init function in the spec's Reference
+	// Decoder Source Code (http://tools.ietf.org/html/rfc6386#section-9.6 Page 145)
+	// says to clamp the LHS value at 132, which is equal to dequantTableDC[117].
+	x.uv[0] = dequantTableDC[clip(q+dquvDC, 0, 117)]
+	x.uv[1] = dequantTableAC[clip(q+dquvAC, 0, 127)]
+	return x
 }
 
 // The dequantization tables are specified in section 14.1.
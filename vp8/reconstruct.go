@@ -390,9 +390,9 @@ func (d *Decoder) reconstructMacroblock(mbx, mby int) {
 	}
 }
 
-// reconstruct reconstructs one macroblock and returns whether inner loop
-// filtering should be skipped for it.
-func (d *Decoder) reconstruct(mbx, mby int) (skip bool) {
+// reconstruct parses one macroblock and, if pixels is true, reconstructs it.
+// It returns whether inner loop filtering should be skipped for it.
+func (d *Decoder) reconstruct(mbx, mby int, pixels bool) (skip bool) {
 	if d.segmentHeader.updateMap {
 		if !d.fp.readBit(d.segmentHeader.prob[0]) {
 			d.segment = int(d.fp.readUint(d.segmentHeader.prob[1], 1))
@@ -407,7 +407,6 @@ func (d *Decoder) reconstruct(mbx, mby int) (skip bool) {
 	for i := range d.coeff {
 		d.coeff[i] = 0
 	}
-	d.prepareYBR(mbx, mby)
 	// Parse the predictor modes.
 	d.usePredY16 = d.fp.readBit(145)
 	if d.usePredY16 {
@@ -429,7 +428,23 @@ func (d *Decoder) reconstruct(mbx, mby int) (skip bool) {
 		d.nzDCMask = 0
 		d.nzACMask = 0
 	}
-	// Reconstruct the YCbCr data and copy it to the image.
+	if d.conceal != nil && (d.fp.unexpectedEOF || d.op[mby&(d.nOP-1)].unexpectedEOF) {
+		if !d.conceal(mbx, mby) {
+			d.concealStop = true
+			return skip
+		}
+		skip = d.concealMacroblock(mbx)
+	}
+	if pixels {
+		d.reconstructPixels(mbx, mby)
+	}
+	return skip
+}
+
+// reconstructPixels reconstructs the YCbCr data of the macroblock whose
+// predictor modes and residuals were just parsed, and copies it to the image.
+func (d *Decoder) reconstructPixels(mbx, mby int) {
+	d.prepareYBR(mbx, mby)
 	d.reconstructMacroblock(mbx, mby)
 	for i, y := (mby*d.img.YStride+mbx)*16, 0; y < 16; i, y = i+d.img.YStride, y+1 {
 		copy(d.img.Y[i:i+16], d.ybr[ybrYY+y][ybrYX:ybrYX+16])
@@ -438,5 +453,25 @@ func (d *Decoder) reconstruct(mbx, mby int) (skip bool) {
 		copy(d.img.Cb[i:i+8], d.ybr[ybrBY+y][ybrBX:ybrBX+8])
 		copy(d.img.Cr[i:i+8], d.ybr[ybrRY+y][ybrRX:ybrRX+8])
 	}
-	return skip
+}
+
+// concealMacroblock replaces the parsed predictor modes and residuals of a
+// damaged macroblock so that it is reconstructed by DC prediction alone: each
+// plane is filled with the average of the neighboring pixels above and to the
+// left. It returns whether inner loop filtering should be skipped.
+func (d *Decoder) concealMacroblock(mbx int) (skip bool) {
+	d.usePredY16 = true
+	d.predY16 = predDC
+	d.predC8 = predDC
+	for i := 0; i < 4; i++ {
+		d.upMB[mbx].pred[i] = predDC
+		d.leftMB.pred[i] = predDC
+	}
+	d.leftMB.nzY16 = 0
+	d.upMB[mbx].nzY16 = 0
+	d.leftMB.nzMask = 0
+	d.upMB[mbx].nzMask = 0
+	d.nzDCMask = 0
+	d.nzACMask = 0
+	return true
 }
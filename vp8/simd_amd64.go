@@ -0,0 +1,38 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !appengine && gc && !noasm
+
+package vp8
+
+// Only the inverse DCT and the loop filters, which dominate decoding time,
+// have SIMD implementations, and only on amd64.
+//
+// TODO: add SIMD implementations of the intra prediction functions in
+// predfunc.go, and arm64 NEON implementations of all three. Until then, those
+// use the pure Go code, as other architectures do.
+
+// haveSSE4_1 returns whether the CPU supports SSE4.1, and SSSE3, which every
+// SSE4.1 CPU also supports.
+func haveSSE4_1() bool
+
+// haveSIMD is whether inverseDCT4SIMD and filterSIMD can be called. It is a
+// variable, not a constant, so that tests can compare the SIMD and pure Go
+// implementations.
+var haveSIMD = haveSSE4_1()
+
+// inverseDCT4SIMD is the SIMD implementation of inverseDCT4. dst points to the
+// top-left pixel of the 4×4 block, whose rows are 32 bytes apart, and coeff
+// points to the block's 16 coefficients.
+//
+//go:noescape
+func inverseDCT4SIMD(dst *uint8, coeff *int16)
+
+// filterSIMD is the SIMD implementation of filter2 and filter246, depending on
+// mode. pix[0] is the p3 pixel of the first of n pixels along the edge, where
+// n is a multiple of 8, and iStep and jStep are as for filter246. One of
+// iStep and jStep must be 1, and pix must hold the last pixel's q3.
+//
+//go:noescape
+func filterSIMD(pix []byte, iStep, jStep, n, level, ilevel, hlevel, mode int)
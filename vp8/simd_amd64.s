@@ -0,0 +1,537 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !appengine && gc && !noasm
+
+#include "textflag.h"
+
+// The inverse DCT works on four int32 values per XMM register, so that its
+// arithmetic, including its multiplications' wraparound, matches idct.go's.
+// The loop filters work on eight int16 values per XMM register: for pixel
+// values in [0, 255], none of filter.go's intermediate values overflow int16.
+
+// Each wN constant is eight int16 values of N.
+DATA w1<>+0x00(SB)/8, $0x0001000100010001
+DATA w1<>+0x08(SB)/8, $0x0001000100010001
+DATA w3<>+0x00(SB)/8, $0x0003000300030003
+DATA w3<>+0x08(SB)/8, $0x0003000300030003
+DATA w4<>+0x00(SB)/8, $0x0004000400040004
+DATA w4<>+0x08(SB)/8, $0x0004000400040004
+DATA w9<>+0x00(SB)/8, $0x0009000900090009
+DATA w9<>+0x08(SB)/8, $0x0009000900090009
+DATA w15<>+0x00(SB)/8, $0x000f000f000f000f
+DATA w15<>+0x08(SB)/8, $0x000f000f000f000f
+DATA w18<>+0x00(SB)/8, $0x0012001200120012
+DATA w18<>+0x08(SB)/8, $0x0012001200120012
+DATA w27<>+0x00(SB)/8, $0x001b001b001b001b
+DATA w27<>+0x08(SB)/8, $0x001b001b001b001b
+DATA w63<>+0x00(SB)/8, $0x003f003f003f003f
+DATA w63<>+0x08(SB)/8, $0x003f003f003f003f
+DATA w127<>+0x00(SB)/8, $0x007f007f007f007f
+DATA w127<>+0x08(SB)/8, $0x007f007f007f007f
+DATA wMinus16<>+0x00(SB)/8, $0xfff0fff0fff0fff0
+DATA wMinus16<>+0x08(SB)/8, $0xfff0fff0fff0fff0
+DATA wMinus128<>+0x00(SB)/8, $0xff80ff80ff80ff80
+DATA wMinus128<>+0x08(SB)/8, $0xff80ff80ff80ff80
+
+GLOBL w1<>(SB), (NOPTR+RODATA), $16
+GLOBL w3<>(SB), (NOPTR+RODATA), $16
+GLOBL w4<>(SB), (NOPTR+RODATA), $16
+GLOBL w9<>(SB), (NOPTR+RODATA), $16
+GLOBL w15<>(SB), (NOPTR+RODATA), $16
+GLOBL w18<>(SB), (NOPTR+RODATA), $16
+GLOBL w27<>(SB), (NOPTR+RODATA), $16
+GLOBL w63<>(SB), (NOPTR+RODATA), $16
+GLOBL w127<>(SB), (NOPTR+RODATA), $16
+GLOBL wMinus16<>(SB), (NOPTR+RODATA), $16
+GLOBL wMinus128<>(SB), (NOPTR+RODATA), $16
+
+// func haveSSE4_1() bool
+TEXT ·haveSSE4_1(SB), NOSPLIT, $0
+	MOVQ $1, AX
+	CPUID
+	ANDL $(1<<9 | 1<<19), CX
+	CMPL CX, $(1<<9 | 1<<19)
+	SETEQ ret+0(FP)
+	RET
+
+// ----------------------------------------------------------------------------
+
+// TRANSPOSE4 transposes the 4×4 int32 matrix whose rows are a, b, c and d,
+// leaving the result's rows in e, b, f and a, using c as scratch space.
+#define TRANSPOSE4(a, b, c, d, e, f) \
+	MOVO      a, e  \
+	PUNPCKLLQ b, e  \
+	PUNPCKHLQ b, a  \
+	MOVO      c, b  \
+	PUNPCKLLQ d, b  \
+	PUNPCKHLQ d, c  \
+	MOVO      e, f  \
+	PUNPCKLQDQ b, e \
+	PUNPCKHQDQ b, f \
+	MOVO      f, b  \
+	MOVO      a, f  \
+	PUNPCKLQDQ c, f \
+	PUNPCKHQDQ c, a
+
+// MULC1 sets dst to (src * c1) >> 16 and MULC2 sets dst to (src * c2) >> 16,
+// for the c1 and c2 constants in X14 and X15.
+#define MULC1(src, dst) \
+	MOVO   src, dst \
+	PMULLD X14, dst \
+	PSRAL  $16, dst
+
+#define MULC2(src, dst) \
+	MOVO   src, dst \
+	PMULLD X15, dst \
+	PSRAL  $16, dst
+
+// func inverseDCT4SIMD(dst *uint8, coeff *int16)
+//
+// XMM registers:
+//	xmm0-xmm3	the input's rows, and then the first pass's output
+//	xmm4-xmm11	scratch
+//	xmm13	4
+//	xmm14	c1
+//	xmm15	c2
+TEXT ·inverseDCT4SIMD(SB), NOSPLIT, $0-16
+	MOVQ dst+0(FP), DI
+	MOVQ coeff+8(FP), SI
+
+	MOVL    $85627, AX
+	MOVQ    AX, X14
+	PSHUFD  $0, X14, X14
+	MOVL    $35468, AX
+	MOVQ    AX, X15
+	PSHUFD  $0, X15, X15
+	MOVL    $4, AX
+	MOVQ    AX, X13
+	PSHUFD  $0, X13, X13
+
+	// Lane i of xmmK is coeff[4*k+i].
+	PMOVSXWD 0(SI), X0
+	PMOVSXWD 8(SI), X1
+	PMOVSXWD 16(SI), X2
+	PMOVSXWD 24(SI), X3
+
+	// The first pass. Lane i of xmmK is m[i][k].
+	MOVO  X0, X4
+	PADDL X2, X4 // a
+	MOVO  X0, X5
+	PSUBL X2, X5 // b
+	MULC2(X1, X6)
+	MULC1(X3, X7)
+	PSUBL X7, X6 // c
+	MULC1(X1, X8)
+	MULC2(X3, X9)
+	PADDL X9, X8 // d
+	MOVO  X4, X0
+	PADDL X8, X0
+	MOVO  X5, X1
+	PADDL X6, X1
+	MOVO  X5, X2
+	PSUBL X6, X2
+	MOVO  X4, X3
+	PSUBL X8, X3
+
+	// Transpose, so that lane j of xmm4, xmm1, xmm5 and xmm0 is m[0][j],
+	// m[1][j], m[2][j] and m[3][j].
+	TRANSPOSE4(X0, X1, X2, X3, X4, X5)
+
+	// The second pass. Lane j of xmm0-xmm3 is column 0-3 of row j.
+	PADDL X13, X4 // dc
+	MOVO  X4, X6
+	PADDL X5, X6  // a
+	MOVO  X4, X7
+	PSUBL X5, X7  // b
+	MULC2(X1, X8)
+	MULC1(X0, X9)
+	PSUBL X9, X8  // c
+	MULC1(X1, X10)
+	MULC2(X0, X11)
+	PADDL X11, X10 // d
+	MOVO  X6, X0
+	PADDL X10, X0
+	PSRAL $3, X0
+	MOVO  X7, X1
+	PADDL X8, X1
+	PSRAL $3, X1
+	MOVO  X7, X2
+	PSUBL X8, X2
+	PSRAL $3, X2
+	MOVO  X6, X3
+	PSUBL X10, X3
+	PSRAL $3, X3
+
+	// Transpose, so that xmm4, xmm1, xmm5 and xmm0 are rows 0-3.
+	TRANSPOSE4(X0, X1, X2, X3, X4, X5)
+
+	// Add each row to the prediction, clipping to [0, 255].
+	PMOVZXBD 0(DI), X6
+	PADDL    X4, X6
+	PACKSSLW X6, X6
+	PACKUSWB X6, X6
+	MOVL     X6, 0(DI)
+	PMOVZXBD 32(DI), X6
+	PADDL    X1, X6
+	PACKSSLW X6, X6
+	PACKUSWB X6, X6
+	MOVL     X6, 32(DI)
+	PMOVZXBD 64(DI), X6
+	PADDL    X5, X6
+	PACKSSLW X6, X6
+	PACKUSWB X6, X6
+	MOVL     X6, 64(DI)
+	PMOVZXBD 96(DI), X6
+	PADDL    X0, X6
+	PACKSSLW X6, X6
+	PACKUSWB X6, X6
+	MOVL     X6, 96(DI)
+	RET
+
+// ----------------------------------------------------------------------------
+
+// TRANSPOSE8 transposes the 8×8 byte matrix whose rows are the low 8 bytes of
+// xmm0-xmm7, leaving the result's rows 0-1 in xmm0, 2-3 in xmm2, 4-5 in xmm1
+// and 6-7 in xmm5.
+#define TRANSPOSE8 \
+	PUNPCKLBW X1, X0 \
+	PUNPCKLBW X3, X2 \
+	PUNPCKLBW X5, X4 \
+	PUNPCKLBW X7, X6 \
+	MOVO      X0, X1 \
+	PUNPCKLWL X2, X0 \
+	PUNPCKHWL X2, X1 \
+	MOVO      X4, X3 \
+	PUNPCKLWL X6, X4 \
+	PUNPCKHWL X6, X3 \
+	MOVO      X0, X2 \
+	PUNPCKLLQ X4, X0 \
+	PUNPCKHLQ X4, X2 \
+	MOVO      X1, X5 \
+	PUNPCKLLQ X3, X1 \
+	PUNPCKHLQ X3, X5
+
+// ABSDIFF sets dst to |a - b|.
+#define ABSDIFF(a, b, dst) \
+	MOVO  a, dst   \
+	PSUBW b, dst   \
+	PABSW dst, dst
+
+// BROADCAST sets all eight int16 values of dst to the low 16 bits of src.
+#define BROADCAST(src, dst) \
+	MOVQ    src, dst       \
+	PSHUFLW $0, dst, dst   \
+	PSHUFD  $0, dst, dst
+
+// func filterSIMD(pix []byte, iStep, jStep, n, level, ilevel, hlevel, mode int)
+//
+// Each iteration filters eight pixels along the edge.
+//
+// General purpose registers:
+//	si	the first pixel's p3
+//	r8	mode
+//	r9	level
+//	r10	ilevel
+//	r11	hlevel
+//	r12	iStep
+//	r13	jStep
+//	cx	the number of iterations left
+//
+// XMM registers:
+//	xmm0-xmm7	p3, p2, p1, p0, q0, q1, q2 and q3
+//	xmm8	whether to filter
+//	xmm9	w, and then a for the 6-pixel filter
+//	xmm10	whether the high edge variance is above the threshold
+//	xmm11-xmm15	scratch
+TEXT ·filterSIMD(SB), NOSPLIT, $0-80
+	MOVQ pix_base+0(FP), SI
+	MOVQ iStep+24(FP), R12
+	MOVQ jStep+32(FP), R13
+	MOVQ n+40(FP), CX
+	MOVQ level+48(FP), R9
+	MOVQ ilevel+56(FP), R10
+	MOVQ hlevel+64(FP), R11
+	MOVQ mode+72(FP), R8
+	SHRQ $3, CX
+	JZ   done
+
+loop:
+	CMPQ R13, $1
+	JEQ  loadVertical
+
+	// The edge is horizontal: each of p3-q3 is a row of eight pixels.
+	MOVQ      SI, DI
+	PXOR      X15, X15
+	MOVQ      (DI), X0
+	PUNPCKLBW X15, X0
+	ADDQ      R13, DI
+	MOVQ      (DI), X1
+	PUNPCKLBW X15, X1
+	ADDQ      R13, DI
+	MOVQ      (DI), X2
+	PUNPCKLBW X15, X2
+	ADDQ      R13, DI
+	MOVQ      (DI), X3
+	PUNPCKLBW X15, X3
+	ADDQ      R13, DI
+	MOVQ      (DI), X4
+	PUNPCKLBW X15, X4
+	ADDQ      R13, DI
+	MOVQ      (DI), X5
+	PUNPCKLBW X15, X5
+	ADDQ      R13, DI
+	MOVQ      (DI), X6
+	PUNPCKLBW X15, X6
+	ADDQ      R13, DI
+	MOVQ      (DI), X7
+	PUNPCKLBW X15, X7
+	JMP       loaded
+
+loadVertical:
+	// The edge is vertical: each of eight rows holds p3-q3 in consecutive
+	// bytes, so transpose them.
+	MOVQ SI, DI
+	MOVQ (DI), X0
+	ADDQ R12, DI
+	MOVQ (DI), X1
+	ADDQ R12, DI
+	MOVQ (DI), X2
+	ADDQ R12, DI
+	MOVQ (DI), X3
+	ADDQ R12, DI
+	MOVQ (DI), X4
+	ADDQ R12, DI
+	MOVQ (DI), X5
+	ADDQ R12, DI
+	MOVQ (DI), X6
+	ADDQ R12, DI
+	MOVQ (DI), X7
+	TRANSPOSE8
+	PXOR      X15, X15
+	MOVO      X0, X8
+	MOVO      X2, X9
+	MOVO      X1, X10
+	MOVO      X5, X11
+	MOVO      X8, X0
+	PUNPCKLBW X15, X0
+	MOVO      X8, X1
+	PUNPCKHBW X15, X1
+	MOVO      X9, X2
+	PUNPCKLBW X15, X2
+	MOVO      X9, X3
+	PUNPCKHBW X15, X3
+	MOVO      X10, X4
+	PUNPCKLBW X15, X4
+	MOVO      X10, X5
+	PUNPCKHBW X15, X5
+	MOVO      X11, X6
+	PUNPCKLBW X15, X6
+	MOVO      X11, X7
+	PUNPCKHBW X15, X7
+
+loaded:
+	// Filter where |p0-q0|*2 + |p1-q1|/2 <= level.
+	ABSDIFF(X3, X4, X8)
+	PADDW X8, X8
+	ABSDIFF(X2, X5, X11)
+	PSRLW $1, X11
+	PADDW X11, X8
+	BROADCAST(R9, X11)
+	PCMPGTW X11, X8
+	PCMPEQW X11, X11
+	PXOR    X11, X8
+
+	// The simple filter has no interior or high edge variance limits, and it
+	// filters 2 pixels, like the normal filter for a high edge variance.
+	CMPQ    R8, $0
+	JNE     normal
+	PCMPEQW X10, X10
+	JMP     filterW
+
+normal:
+	// Filter only where the interior differences are all <= ilevel.
+	ABSDIFF(X2, X3, X10)
+	ABSDIFF(X5, X4, X11)
+	PMAXSW X11, X10
+	MOVO   X10, X9
+	ABSDIFF(X0, X1, X11)
+	PMAXSW X11, X9
+	ABSDIFF(X1, X2, X11)
+	PMAXSW X11, X9
+	ABSDIFF(X6, X5, X11)
+	PMAXSW X11, X9
+	ABSDIFF(X7, X6, X11)
+	PMAXSW X11, X9
+	BROADCAST(R10, X11)
+	PCMPGTW X11, X9
+	PANDN   X8, X9
+	MOVO    X9, X8
+
+	// The high edge variance is above the threshold where |p1-p0| or |q1-q0|
+	// is > hlevel.
+	BROADCAST(R11, X11)
+	PCMPGTW X11, X10
+
+filterW:
+	// w = 3*(q0-p0) + clamp127(p1-q1), except that the 4-pixel filter omits
+	// the clamp127 term.
+	MOVO   X2, X11
+	PSUBW  X5, X11
+	MOVOU  w127<>(SB), X12
+	PMINSW X12, X11
+	MOVOU  wMinus128<>(SB), X12
+	PMAXSW X12, X11
+	CMPQ   R8, $1
+	JNE    sum
+	PAND   X10, X11
+
+sum:
+	MOVO  X4, X9
+	PSUBW X3, X9
+	MOVO  X9, X12
+	PADDW X9, X9
+	PADDW X12, X9
+	PADDW X11, X9
+
+	// a1 = clamp15((w+4)>>3) in xmm11 and a2 = clamp15((w+3)>>3) in xmm12.
+	MOVOU  w15<>(SB), X13
+	MOVOU  wMinus16<>(SB), X14
+	MOVO   X9, X11
+	MOVOU  w4<>(SB), X15
+	PADDW  X15, X11
+	PSRAW  $3, X11
+	PMINSW X13, X11
+	PMAXSW X14, X11
+	MOVO   X9, X12
+	MOVOU  w3<>(SB), X15
+	PADDW  X15, X12
+	PSRAW  $3, X12
+	PMINSW X13, X12
+	PMAXSW X14, X12
+	CMPQ   R8, $2
+	JEQ    six
+
+	// Filter 2 pixels, or 4 pixels where the high edge variance is below
+	// the threshold.
+	PAND  X8, X11
+	PAND  X8, X12
+	PADDW X12, X3
+	PSUBW X11, X4
+	CMPQ  R8, $1
+	JNE   store
+	MOVOU w1<>(SB), X15
+	PADDW X15, X11
+	PSRAW $1, X11
+	PANDN X11, X10
+	PADDW X10, X2
+	PSUBW X10, X5
+	JMP   store
+
+six:
+	// Filter 2 pixels where the high edge variance is above the threshold.
+	MOVO X8, X13
+	PAND X10, X13
+	PAND X13, X11
+	PAND X13, X12
+
+	// Filter 6 pixels elsewhere, with a = clamp127(w) and (k*a + 63) >> 7
+	// for k = 27, 18 and 9 in xmm13, xmm14 and xmm8.
+	PANDN  X8, X10
+	MOVOU  w127<>(SB), X13
+	PMINSW X13, X9
+	MOVOU  wMinus128<>(SB), X13
+	PMAXSW X13, X9
+	MOVOU  w63<>(SB), X15
+	MOVOU  w27<>(SB), X13
+	PMULLW X9, X13
+	PADDW  X15, X13
+	PSRAW  $7, X13
+	PAND   X10, X13
+	MOVOU  w18<>(SB), X14
+	PMULLW X9, X14
+	PADDW  X15, X14
+	PSRAW  $7, X14
+	PAND   X10, X14
+	MOVOU  w9<>(SB), X8
+	PMULLW X9, X8
+	PADDW  X15, X8
+	PSRAW  $7, X8
+	PAND   X10, X8
+	PADDW  X13, X12
+	PADDW  X13, X11
+	PADDW  X12, X3
+	PSUBW  X11, X4
+	PADDW  X14, X2
+	PSUBW  X14, X5
+	PADDW  X8, X1
+	PSUBW  X8, X6
+
+store:
+	CMPQ R13, $1
+	JEQ  storeVertical
+
+	// Store the rows that the mode changes, clipping to [0, 255].
+	LEAQ     (R13)(R13*2), DI
+	ADDQ     SI, DI
+	PACKUSWB X3, X3
+	MOVQ     X3, (DI)
+	PACKUSWB X4, X4
+	MOVQ     X4, (DI)(R13*1)
+	CMPQ     R8, $0
+	JEQ      nextHorizontal
+	SUBQ     R13, DI
+	LEAQ     (R13)(R13*2), AX
+	PACKUSWB X2, X2
+	MOVQ     X2, (DI)
+	PACKUSWB X5, X5
+	MOVQ     X5, (DI)(AX*1)
+	CMPQ     R8, $1
+	JEQ      nextHorizontal
+	SUBQ     R13, DI
+	LEAQ     (R13)(R13*4), AX
+	PACKUSWB X1, X1
+	MOVQ     X1, (DI)
+	PACKUSWB X6, X6
+	MOVQ     X6, (DI)(AX*1)
+
+nextHorizontal:
+	ADDQ $8, SI
+	JMP  next
+
+storeVertical:
+	// Pack the columns, clipping to [0, 255], and transpose them back.
+	PACKUSWB X0, X0
+	PACKUSWB X1, X1
+	PACKUSWB X2, X2
+	PACKUSWB X3, X3
+	PACKUSWB X4, X4
+	PACKUSWB X5, X5
+	PACKUSWB X6, X6
+	PACKUSWB X7, X7
+	TRANSPOSE8
+	MOVQ   SI, DI
+	MOVQ   X0, (DI)
+	ADDQ   R12, DI
+	MOVHPS X0, (DI)
+	ADDQ   R12, DI
+	MOVQ   X2, (DI)
+	ADDQ   R12, DI
+	MOVHPS X2, (DI)
+	ADDQ   R12, DI
+	MOVQ   X1, (DI)
+	ADDQ   R12, DI
+	MOVHPS X1, (DI)
+	ADDQ   R12, DI
+	MOVQ   X5, (DI)
+	ADDQ   R12, DI
+	MOVHPS X5, (DI)
+	LEAQ   (SI)(R12*8), SI
+
+next:
+	DECQ CX
+	JNZ  loop
+
+done:
+	RET
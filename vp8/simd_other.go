@@ -0,0 +1,15 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !amd64 || appengine || !gc || noasm
+
+package vp8
+
+// There are no SIMD implementations for this architecture, including arm64,
+// or build configuration. See simd_amd64.go.
+
+var haveSIMD = false
+
+func inverseDCT4SIMD(dst *uint8, coeff *int16)                                {}
+func filterSIMD(pix []byte, iStep, jStep, n, level, ilevel, hlevel, mode int) {}
@@ -0,0 +1,115 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vp8
+
+import (
+	"bytes"
+	"image"
+	"math/rand"
+	"testing"
+)
+
+// withoutSIMD calls f with haveSIMD set to false.
+func withoutSIMD(f func()) {
+	defer func(b bool) { haveSIMD = b }(haveSIMD)
+	haveSIMD = false
+	f()
+}
+
+func TestInverseDCT4SIMD(t *testing.T) {
+	if !haveSIMD {
+		t.Skip("No SIMD implementation")
+	}
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		var got, want Decoder
+		for j := range got.ybr {
+			rng.Read(got.ybr[j][:])
+		}
+		// Use both typical and extreme coefficients, for which the pure Go
+		// implementation's int32 arithmetic wraps around.
+		for j := range got.coeff {
+			if i%2 == 0 {
+				got.coeff[j] = int16(rng.Intn(512) - 256)
+			} else {
+				got.coeff[j] = int16(rng.Uint32())
+			}
+		}
+		want = got
+		y, x, coeffBase := rng.Intn(len(got.ybr)-3), rng.Intn(32-3), 16*rng.Intn(len(got.coeff)/16)
+		got.inverseDCT4(y, x, coeffBase)
+		withoutSIMD(func() { want.inverseDCT4(y, x, coeffBase) })
+		if got.ybr != want.ybr {
+			t.Fatalf("i=%d, y=%d, x=%d, coeff=%v: SIMD and pure Go results differ",
+				i, y, x, got.coeff[coeffBase:coeffBase+16])
+		}
+	}
+}
+
+func TestFilterSIMD(t *testing.T) {
+	if !haveSIMD {
+		t.Skip("No SIMD implementation")
+	}
+	const stride, height = 40, 24
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		// Vary the pixels by a small random amount from a base value, so that
+		// every branch of the filters is taken for some pixels.
+		pix := make([]byte, stride*height)
+		base, spread := rng.Intn(256), 1+rng.Intn(64)
+		for j := range pix {
+			pix[j] = clamp255(base + rng.Intn(spread) - spread/2)
+		}
+		want := append([]byte(nil), pix...)
+
+		n := 8 * (1 + rng.Intn(2))
+		level, ilevel, hlevel := rng.Intn(140), 1+rng.Intn(63), rng.Intn(4)
+		index, iStep, jStep := 4*stride+4, 1, stride
+		if i%2 == 0 {
+			iStep, jStep = stride, 1
+		}
+		mode := i / 2 % 3
+		filter := func(pix []byte) {
+			switch mode {
+			case 0:
+				filter2(pix, level, index, iStep, jStep)
+			default:
+				filter246(pix, n, level, ilevel, hlevel, index, iStep, jStep, mode == 1)
+			}
+		}
+		filter(pix)
+		withoutSIMD(func() { filter(want) })
+		if !bytes.Equal(pix, want) {
+			t.Fatalf("i=%d, mode=%d, n=%d, iStep=%d, jStep=%d, level=%d, ilevel=%d, hlevel=%d: "+
+				"SIMD and pure Go results differ", i, mode, n, iStep, jStep, level, ilevel, hlevel)
+		}
+	}
+}
+
+func TestDecodeFrameSIMD(t *testing.T) {
+	if !haveSIMD {
+		t.Skip("No SIMD implementation")
+	}
+	for _, filename := range []string{
+		"../testdata/blue-purple-pink-large.no-filter.lossy.webp",
+		"../testdata/blue-purple-pink-large.normal-filter.lossy.webp",
+		"../testdata/blue-purple-pink-large.simple-filter.lossy.webp",
+		"../testdata/video-001.lossy.webp",
+	} {
+		data := readVP8(t, filename)
+		got, err := decodeVP8(data, nil)
+		if err != nil {
+			t.Fatalf("%s: %v", filename, err)
+		}
+		var want *image.YCbCr
+		withoutSIMD(func() { want, err = decodeVP8(data, nil) })
+		if err != nil {
+			t.Fatalf("%s: without SIMD: %v", filename, err)
+		}
+		if !bytes.Equal(got.Y, want.Y) || !bytes.Equal(got.Cb, want.Cb) || !bytes.Equal(got.Cr, want.Cr) {
+			t.Errorf("%s: SIMD and pure Go decodings differ", filename)
+		}
+	}
+}
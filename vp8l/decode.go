@@ -58,25 +58,50 @@ func distanceMap(w int32, code uint32) int32 {
 
 // decoder holds the bit-stream for a VP8L image.
 type decoder struct {
-	r     io.ByteReader
-	bits  uint32
+	r io.ByteReader
+	// bits holds nBits unread bits from r, least significant bit first.
+	bits  uint64
 	nBits uint32
+	// eof is whether r has returned io.EOF.
+	eof bool
+	// hasAlpha is the header's alpha_is_used hint.
+	hasAlpha bool
+	// pixBuf, if large enough, is re-used for the top-level pixels.
+	pixBuf []byte
+	// ccBuf is re-used for the color cache of each of the entropy-coded
+	// images. It is sized for the largest cache seen so far.
+	ccBuf []uint32
 }
 
-// read reads the next n bits from the decoder's bit-stream.
-func (d *decoder) read(n uint32) (uint32, error) {
-	for d.nBits < n {
+// fill reads bytes from the decoder's bit-stream, if there are any left,
+// until it holds more than 56 bits.
+func (d *decoder) fill() error {
+	for d.nBits <= 56 && !d.eof {
 		c, err := d.r.ReadByte()
 		if err != nil {
-			if err == io.EOF {
-				err = io.ErrUnexpectedEOF
+			if err != io.EOF {
+				return err
 			}
-			return 0, err
+			d.eof = true
+			break
 		}
-		d.bits |= uint32(c) << d.nBits
+		d.bits |= uint64(c) << d.nBits
 		d.nBits += 8
 	}
-	u := d.bits & (1<<n - 1)
+	return nil
+}
+
+// read reads the next n bits from the decoder's bit-stream.
+func (d *decoder) read(n uint32) (uint32, error) {
+	if d.nBits < n {
+		if err := d.fill(); err != nil {
+			return 0, err
+		}
+		if d.nBits < n {
+			return 0, io.ErrUnexpectedEOF
+		}
+	}
+	u := uint32(d.bits) & (1<<n - 1)
 	d.bits >>= n
 	d.nBits -= n
 	return u, nil
@@ -365,7 +390,15 @@ func (d *decoder) decodePix(w int32, h int32, minCap int32, topLevel bool) ([]by
 			return nil, errors.New("vp8l: invalid color cache parameters")
 		}
 		ccShift = 32 - ccBits
-		ccEntries = make([]uint32, 1<<ccBits)
+		if n := 1 << ccBits; cap(d.ccBuf) >= n {
+			ccEntries = d.ccBuf[:n]
+			for i := range ccEntries {
+				ccEntries[i] = 0
+			}
+		} else {
+			ccEntries = make([]uint32, n)
+			d.ccBuf = ccEntries
+		}
 	}
 
 	// Decode the Huffman groups.
@@ -379,10 +412,15 @@ func (d *decoder) decodePix(w int32, h int32, minCap int32, topLevel bool) ([]by
 	}
 
 	// Decode the pixels.
-	if minCap < 4*w*h {
-		minCap = 4 * w * h
+	var pix []byte
+	if topLevel && cap(d.pixBuf) >= int(4*w*h) {
+		pix = d.pixBuf[:4*w*h]
+	} else {
+		if minCap < 4*w*h {
+			minCap = 4 * w * h
+		}
+		pix = make([]byte, 4*w*h, minCap)
 	}
-	pix := make([]byte, 4*w*h, minCap)
 	p, cachedP := 0, 0
 	x, y := int32(0), int32(0)
 	hg, lookupHG := &hGroups[0], hMask != 0
@@ -392,29 +430,40 @@ func (d *decoder) decodePix(w int32, h int32, minCap int32, topLevel bool) ([]by
 			hg = &hGroups[uint32(hPix[i])<<8|uint32(hPix[i+1])]
 		}
 
-		green, err := hg[huffGreen].next(d)
-		if err != nil {
-			return nil, err
+		// This is the inner loop, so call the inlinable nextFast before
+		// falling back to nextSlow, instead of calling next.
+		green, ok := hg[huffGreen].nextFast(d)
+		if !ok {
+			if green, err = hg[huffGreen].nextSlow(d); err != nil {
+				return nil, err
+			}
 		}
 		switch {
 		case green < nLiteralCodes:
 			// We have a literal pixel.
-			red, err := hg[huffRed].next(d)
-			if err != nil {
-				return nil, err
+			red, ok := hg[huffRed].nextFast(d)
+			if !ok {
+				if red, err = hg[huffRed].nextSlow(d); err != nil {
+					return nil, err
+				}
 			}
-			blue, err := hg[huffBlue].next(d)
-			if err != nil {
-				return nil, err
+			blue, ok := hg[huffBlue].nextFast(d)
+			if !ok {
+				if blue, err = hg[huffBlue].nextSlow(d); err != nil {
+					return nil, err
+				}
 			}
-			alpha, err := hg[huffAlpha].next(d)
-			if err != nil {
-				return nil, err
+			alpha, ok := hg[huffAlpha].nextFast(d)
+			if !ok {
+				if alpha, err = hg[huffAlpha].nextSlow(d); err != nil {
+					return nil, err
+				}
 			}
-			pix[p+0] = uint8(red)
-			pix[p+1] = uint8(green)
-			pix[p+2] = uint8(blue)
-			pix[p+3] = uint8(alpha)
+			s := pix[p : p+4 : p+4]
+			s[0] = uint8(red)
+			s[1] = uint8(green)
+			s[2] = uint8(blue)
+			s[3] = uint8(alpha)
 			p += 4
 
 			x++
@@ -444,8 +493,12 @@ func (d *decoder) decodePix(w int32, h int32, minCap int32, topLevel bool) ([]by
 			if p < 0 || len(pix) < pEnd || q < 0 || len(pix) < qEnd {
 				return nil, errors.New("vp8l: invalid LZ77 parameters")
 			}
-			for ; p < pEnd; p, q = p+1, q+1 {
-				pix[p] = pix[q]
+			// The source and destination overlap if dist < length, in which
+			// case the pixels repeat with a period of dist. Copying from
+			// pix[q:p] at each step, which grows with each copy, is still
+			// correct, and needs fewer steps than copying one byte at a time.
+			for p < pEnd {
+				p += copy(pix[p:pEnd], pix[q:p])
 			}
 
 			x += int32(length)
@@ -459,10 +512,11 @@ func (d *decoder) decodePix(w int32, h int32, minCap int32, topLevel bool) ([]by
 			// into the cache. Note that VP8L assumes ARGB order, but the
 			// Go image.RGBA type is in RGBA order.
 			for ; cachedP < p; cachedP += 4 {
-				argb := uint32(pix[cachedP+0])<<16 |
-					uint32(pix[cachedP+1])<<8 |
-					uint32(pix[cachedP+2])<<0 |
-					uint32(pix[cachedP+3])<<24
+				s := pix[cachedP : cachedP+4 : cachedP+4]
+				argb := uint32(s[0])<<16 |
+					uint32(s[1])<<8 |
+					uint32(s[2])<<0 |
+					uint32(s[3])<<24
 				ccEntries[(argb*colorCacheMultiplier)>>ccShift] = argb
 			}
 			green -= nLiteralCodes + nLengthCodes
@@ -470,10 +524,11 @@ func (d *decoder) decodePix(w int32, h int32, minCap int32, topLevel bool) ([]by
 				return nil, errors.New("vp8l: invalid color cache index")
 			}
 			argb := ccEntries[green]
-			pix[p+0] = uint8(argb >> 16)
-			pix[p+1] = uint8(argb >> 8)
-			pix[p+2] = uint8(argb >> 0)
-			pix[p+3] = uint8(argb >> 24)
+			s := pix[p : p+4 : p+4]
+			s[0] = uint8(argb >> 16)
+			s[1] = uint8(argb >> 8)
+			s[2] = uint8(argb >> 0)
+			s[3] = uint8(argb >> 24)
 			p += 4
 
 			x++
@@ -525,10 +580,11 @@ func decodeHeader(r io.Reader) (d *decoder, w int32, h int32, err error) {
 		return nil, 0, 0, err
 	}
 	height++
-	_, err = d.read(1) // Read and ignore the hasAlpha hint.
+	hasAlpha, err := d.read(1)
 	if err != nil {
 		return nil, 0, 0, err
 	}
+	d.hasAlpha = hasAlpha != 0
 	version, err := d.read(3)
 	if err != nil {
 		return nil, 0, 0, err
@@ -539,25 +595,112 @@ func decodeHeader(r io.Reader) (d *decoder, w int32, h int32, err error) {
 	return d, int32(width), int32(height), nil
 }
 
+// Config is the configuration of a VP8L image, as given by its header.
+type Config struct {
+	image.Config
+	// HasAlpha is the header's alpha_is_used bit. Encoders clear it when
+	// every alpha value is 0xff, so that callers can choose an RGB
+	// destination instead of an NRGBA one. The specification says that the
+	// bit is only a hint, though: decoding does not depend on it, and Decode
+	// returns the alpha values that the image holds either way.
+	HasAlpha bool
+}
+
 // DecodeConfig decodes the color model and dimensions of a VP8L image from r.
 func DecodeConfig(r io.Reader) (image.Config, error) {
-	_, w, h, err := decodeHeader(r)
+	c, err := DecodeExtendedConfig(r)
+	return c.Config, err
+}
+
+// DecodeExtendedConfig is like DecodeConfig but also reports the header's
+// hint for whether the image uses its alpha values. The color model is
+// color.NRGBAModel either way, as that is what Decode returns.
+func DecodeExtendedConfig(r io.Reader) (Config, error) {
+	d, w, h, err := decodeHeader(r)
 	if err != nil {
-		return image.Config{}, err
-	}
-	return image.Config{
-		ColorModel: color.NRGBAModel,
-		Width:      int(w),
-		Height:     int(h),
+		return Config{}, err
+	}
+	return Config{
+		Config: image.Config{
+			ColorModel: color.NRGBAModel,
+			Width:      int(w),
+			Height:     int(h),
+		},
+		HasAlpha: d.hasAlpha,
 	}, nil
 }
 
 // Decode decodes a VP8L image from r.
 func Decode(r io.Reader) (image.Image, error) {
-	d, w, h, err := decodeHeader(r)
+	m := &image.NRGBA{}
+	if err := decode(m, r); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// DecodeInto decodes a VP8L image from r into dst, setting dst's bounds to
+// the image's bounds. It re-uses dst.Pix if its capacity is large enough, so
+// that decoding a series of images into the same dst allocates less memory
+// than calling Decode for each one.
+//
+// If DecodeInto returns an error, dst's contents are unspecified.
+func DecodeInto(dst *image.NRGBA, r io.Reader) error {
+	return decode(dst, r)
+}
+
+// DecodeGreen decodes a VP8L image from r and returns only its green values,
+// one byte per pixel, in row-major order. The WEBP format's compressed alpha
+// channels are VP8L images whose green values are the alpha values.
+//
+// The returned slice re-uses dst if its capacity is large enough. If the
+// capacity is at least four times the number of pixels, the image is decoded
+// in place without allocating any pixel buffers. Decoding only the green
+// values also skips the inverse transforms that only change the red and blue
+// values, where that does not change the green values.
+//
+// If DecodeGreen returns an error, dst's contents are unspecified.
+func DecodeGreen(dst []byte, r io.Reader) ([]byte, error) {
+	pix, w, h, err := decodeImagePix(dst, r, true)
 	if err != nil {
 		return nil, err
 	}
+	// Pack the green values. When pix and green share their backing array,
+	// this works in place, as each green value moves to a lower index.
+	n := int(w) * int(h)
+	green := dst
+	if cap(green) >= n {
+		green = green[:n]
+	} else {
+		green = make([]byte, n)
+	}
+	for i := range green {
+		green[i] = pix[4*i+1]
+	}
+	return green, nil
+}
+
+func decode(dst *image.NRGBA, r io.Reader) error {
+	pix, w, h, err := decodeImagePix(dst.Pix, r, false)
+	if err != nil {
+		return err
+	}
+	*dst = image.NRGBA{
+		Pix:    pix,
+		Stride: 4 * int(w),
+		Rect:   image.Rect(0, 0, int(w), int(h)),
+	}
+	return nil
+}
+
+// decodeImagePix decodes a VP8L image from r, returning its pixels as 4
+// bytes per pixel, re-using buf if its capacity is large enough. If greenOnly
+// is true, only the green values of the returned pixels are valid.
+func decodeImagePix(buf []byte, r io.Reader, greenOnly bool) (pix []byte, w int32, h int32, err error) {
+	d, w, h, err := decodeHeader(r)
+	if err != nil {
+		return nil, 0, 0, err
+	}
 	// Decode the transforms.
 	var (
 		nTransforms    int
@@ -568,7 +711,7 @@ func Decode(r io.Reader) (image.Image, error) {
 	for {
 		more, err := d.read(1)
 		if err != nil {
-			return nil, err
+			return nil, 0, 0, err
 		}
 		if more == 0 {
 			break
@@ -576,28 +719,52 @@ func Decode(r io.Reader) (image.Image, error) {
 		var t transform
 		t, w, err = d.decodeTransform(w, h)
 		if err != nil {
-			return nil, err
+			return nil, 0, 0, err
 		}
 		if transformsSeen[t.transformType] {
-			return nil, errors.New("vp8l: repeated transform")
+			return nil, 0, 0, errors.New("vp8l: repeated transform")
 		}
 		transformsSeen[t.transformType] = true
 		transforms[nTransforms] = t
 		nTransforms++
 	}
+	// Re-use buf for the final pixels. These are the decoded pixels,
+	// inverse-transformed in place, unless a color-indexing transform packs
+	// more than one pixel into each decoded pixel. In that case, they are
+	// the output of that transform.
+	if w == originalW {
+		d.pixBuf = buf
+	} else {
+		for i := 0; i < nTransforms; i++ {
+			if transforms[i].transformType == transformTypeColorIndexing {
+				transforms[i].dst = buf
+			}
+		}
+	}
 	// Decode the transformed pixels.
-	pix, err := d.decodePix(w, h, 0, true)
+	pix, err = d.decodePix(w, h, 0, true)
 	if err != nil {
-		return nil, err
+		return nil, 0, 0, err
+	}
+	// Apply the inverse transformations. The cross-color and subtract-green
+	// transforms do not change the green values, and when only those are
+	// wanted, they can be skipped unless an inverse predictor transform, whose
+	// Select mode looks at every channel, comes after them.
+	predictorIndex := -1
+	for i := 0; i < nTransforms; i++ {
+		if transforms[i].transformType == transformTypePredictor {
+			predictorIndex = i
+		}
 	}
-	// Apply the inverse transformations.
 	for i := nTransforms - 1; i >= 0; i-- {
 		t := &transforms[i]
+		switch t.transformType {
+		case transformTypeCrossColor, transformTypeSubtractGreen:
+			if greenOnly && (predictorIndex < 0 || predictorIndex > i) {
+				continue
+			}
+		}
 		pix = inverseTransforms[t.transformType](t, pix, h)
 	}
-	return &image.NRGBA{
-		Pix:    pix,
-		Stride: 4 * int(originalW),
-		Rect:   image.Rect(0, 0, int(originalW), int(h)),
-	}, nil
+	return pix, originalW, h, nil
 }
@@ -0,0 +1,199 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vp8l
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"os"
+	"testing"
+)
+
+// loadVP8L returns the contents of the VP8L chunk of the named WEBP file in
+// the testdata directory.
+func loadVP8L(tb testing.TB, name string) []byte {
+	tb.Helper()
+	data, err := os.ReadFile("../testdata/" + name + ".lossless.webp")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	// Skip the RIFF header, then walk the chunks.
+	for p := 12; p+8 <= len(data); {
+		n := int(binary.LittleEndian.Uint32(data[p+4:]))
+		if p+8+n > len(data) {
+			break
+		}
+		if string(data[p:p+4]) == "VP8L" {
+			return data[p+8 : p+8+n]
+		}
+		p += 8 + n + n&1
+	}
+	tb.Fatalf("%s: no VP8L chunk", name)
+	return nil
+}
+
+var testImages = []string{
+	"blue-purple-pink",
+	"blue-purple-pink-large",
+	"gopher-doc.1bpp",
+	"gopher-doc.2bpp",
+	"gopher-doc.4bpp",
+	"gopher-doc.8bpp",
+	"tux",
+	"yellow_rose",
+}
+
+func TestDecodeInto(t *testing.T) {
+	// Decode the test images from largest to smallest and back, so that dst's
+	// buffer is both too small and larger than needed.
+	var names []string
+	names = append(names, testImages...)
+	for i := len(testImages) - 1; i >= 0; i-- {
+		names = append(names, testImages[i])
+	}
+
+	dst := &image.NRGBA{}
+	for _, name := range names {
+		data := loadVP8L(t, name)
+		m, err := Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("%s: Decode: %v", name, err)
+		}
+		want := m.(*image.NRGBA)
+		if err := DecodeInto(dst, bytes.NewReader(data)); err != nil {
+			t.Fatalf("%s: DecodeInto: %v", name, err)
+		}
+		if dst.Rect != want.Rect || dst.Stride != want.Stride {
+			t.Errorf("%s: got bounds %v and stride %d, want %v and %d", name, dst.Rect, dst.Stride, want.Rect, want.Stride)
+			continue
+		}
+		if !bytes.Equal(dst.Pix, want.Pix) {
+			t.Errorf("%s: pixels differ", name)
+		}
+	}
+}
+
+func TestDecodeGreen(t *testing.T) {
+	for _, name := range testImages {
+		data := loadVP8L(t, name)
+		m, err := Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("%s: Decode: %v", name, err)
+		}
+		pix := m.(*image.NRGBA).Pix
+		want := make([]byte, len(pix)/4)
+		for i := range want {
+			want[i] = pix[4*i+1]
+		}
+
+		// Decode into no buffer, a buffer that only fits the green values and
+		// a buffer that fits all of the pixels.
+		for _, n := range []int{0, len(want), len(pix)} {
+			dst := make([]byte, n)
+			got, err := DecodeGreen(dst, bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("%s: DecodeGreen with a %d byte buffer: %v", name, n, err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("%s: DecodeGreen with a %d byte buffer: green values differ", name, n)
+			}
+			if n > 0 && &got[0] != &dst[0] {
+				t.Errorf("%s: DecodeGreen with a %d byte buffer: buffer not re-used", name, n)
+			}
+		}
+	}
+}
+
+func TestDecodeTruncated(t *testing.T) {
+	data := loadVP8L(t, "gopher-doc.2bpp")
+	for n := 0; n < len(data); n++ {
+		if _, err := Decode(bytes.NewReader(data[:n])); err == nil {
+			t.Errorf("%d of %d bytes: got nil error, want non-nil", n, len(data))
+		}
+	}
+}
+
+func benchmarkDecode(b *testing.B, name string, reuse bool) {
+	data := loadVP8L(b, name)
+	cfg, err := DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		b.Fatal(err)
+	}
+	dst := &image.NRGBA{}
+	b.SetBytes(int64(cfg.Width * cfg.Height * 4))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if reuse {
+			err = DecodeInto(dst, bytes.NewReader(data))
+		} else {
+			_, err = Decode(bytes.NewReader(data))
+		}
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodePhoto(b *testing.B)           { benchmarkDecode(b, "blue-purple-pink-large", false) }
+func BenchmarkDecodePhotoReuse(b *testing.B)      { benchmarkDecode(b, "blue-purple-pink-large", true) }
+func BenchmarkDecodeScreenshot(b *testing.B)      { benchmarkDecode(b, "gopher-doc.8bpp", false) }
+func BenchmarkDecodeScreenshotReuse(b *testing.B) { benchmarkDecode(b, "gopher-doc.8bpp", true) }
+func BenchmarkDecodePaletted(b *testing.B)        { benchmarkDecode(b, "gopher-doc.2bpp", false) }
+func BenchmarkDecodePalettedReuse(b *testing.B)   { benchmarkDecode(b, "gopher-doc.2bpp", true) }
+
+func TestDecodeExtendedConfig(t *testing.T) {
+	for _, name := range testImages {
+		data := loadVP8L(t, name)
+		c, err := DecodeExtendedConfig(bytes.NewReader(data))
+		if err != nil {
+			t.Errorf("%s: DecodeExtendedConfig: %v", name, err)
+			continue
+		}
+		m, err := Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Errorf("%s: Decode: %v", name, err)
+			continue
+		}
+		if got, want := image.Rect(0, 0, c.Width, c.Height), m.Bounds(); got != want {
+			t.Errorf("%s: bounds: got %v, want %v", name, got, want)
+		}
+		if c.HasAlpha {
+			continue
+		}
+		pix := m.(*image.NRGBA).Pix
+		for i := 3; i < len(pix); i += 4 {
+			if pix[i] != 0xff {
+				t.Errorf("%s: HasAlpha is false but pixel %d has alpha %#02x", name, i/4, pix[i])
+				break
+			}
+		}
+	}
+
+	// The header is the 0x2f signature byte, followed by the width-1 and
+	// height-1 in 14 bits each, the alpha_is_used bit and a 3-bit version,
+	// least significant bit first.
+	for _, hasAlpha := range []bool{false, true} {
+		bits := uint32(3-1) | uint32(5-1)<<14
+		if hasAlpha {
+			bits |= 1 << 28
+		}
+		header := []byte{0x2f, 0, 0, 0, 0}
+		binary.LittleEndian.PutUint32(header[1:], bits)
+		c, err := DecodeExtendedConfig(bytes.NewReader(header))
+		if err != nil {
+			t.Errorf("hasAlpha=%t: DecodeExtendedConfig: %v", hasAlpha, err)
+			continue
+		}
+		if c.Width != 3 || c.Height != 5 || c.HasAlpha != hasAlpha {
+			t.Errorf("hasAlpha=%t: got %dx%d, HasAlpha=%t, want 3x5, HasAlpha=%t",
+				hasAlpha, c.Width, c.Height, c.HasAlpha, hasAlpha)
+		}
+		if _, err := DecodeConfig(bytes.NewReader(header)); err != nil {
+			t.Errorf("hasAlpha=%t: DecodeConfig: %v", hasAlpha, err)
+		}
+	}
+}
@@ -40,6 +40,9 @@ type hNode struct {
 
 const leafNode = -1
 
+// maxAllowedCodeLength is the maximum length of a Huffman code, in bits.
+const maxAllowedCodeLength = 15
+
 // lutSize is the log-2 size of an hTree's look-up table.
 const lutSize, lutMask = 7, 1<<7 - 1
 
@@ -122,7 +125,6 @@ func codeLengthsToCodes(codeLengths []uint32) ([]uint32, error) {
 			maxCodeLength = cl
 		}
 	}
-	const maxAllowedCodeLength = 15
 	if len(codeLengths) == 0 || maxCodeLength > maxAllowedCodeLength {
 		return nil, errInvalidHuffmanTree
 	}
@@ -197,47 +199,61 @@ func (h *hTree) buildSimple(nSymbols uint32, symbols [2]uint32, alphabetSize uin
 
 // next returns the next Huffman-encoded symbol from the bit-stream d.
 func (h *hTree) next(d *decoder) (uint32, error) {
-	var n uint32
-	// Read enough bits so that we can use the look-up table.
-	if d.nBits < lutSize {
-		c, err := d.r.ReadByte()
-		if err != nil {
-			if err == io.EOF {
-				// There are no more bytes of data, but we may still be able
-				// to read the next symbol out of the previously read bits.
-				goto slowPath
-			}
+	if symbol, ok := h.nextFast(d); ok {
+		return symbol, nil
+	}
+	return h.nextSlow(d)
+}
+
+// nextFast is like next, but only handles the common case, where d holds
+// enough bits for the longest code and the look-up table gives the symbol
+// directly. It returns ok == false, without consuming any bits, otherwise.
+//
+// It is small enough to be inlined, unlike next.
+func (h *hTree) nextFast(d *decoder) (symbol uint32, ok bool) {
+	n := h.lut[d.bits&lutMask]
+	b := n & 0xff
+	if b == 0 || d.nBits < maxAllowedCodeLength {
+		return 0, false
+	}
+	b--
+	d.bits >>= b
+	d.nBits -= b
+	return n >> 8, true
+}
+
+// nextSlow is like next, but handles all cases.
+func (h *hTree) nextSlow(d *decoder) (uint32, error) {
+	// Read enough bits for the longest code, if there are that many left.
+	if d.nBits < maxAllowedCodeLength {
+		if err := d.fill(); err != nil {
 			return 0, err
 		}
-		d.bits |= uint32(c) << d.nBits
-		d.nBits += 8
 	}
 	// Use the look-up table.
-	n = h.lut[d.bits&lutMask]
+	n := h.lut[d.bits&lutMask]
 	if b := n & 0xff; b != 0 {
 		b--
+		if d.nBits < b {
+			return 0, io.ErrUnexpectedEOF
+		}
 		d.bits >>= b
 		d.nBits -= b
 		return n >> 8, nil
 	}
+	if d.nBits < lutSize {
+		return 0, io.ErrUnexpectedEOF
+	}
 	n >>= 8
 	d.bits >>= lutSize
 	d.nBits -= lutSize
 
-slowPath:
+	// Walk the rest of the tree, one bit at a time.
 	for h.nodes[n].children != leafNode {
 		if d.nBits == 0 {
-			c, err := d.r.ReadByte()
-			if err != nil {
-				if err == io.EOF {
-					err = io.ErrUnexpectedEOF
-				}
-				return 0, err
-			}
-			d.bits = uint32(c)
-			d.nBits = 8
+			return 0, io.ErrUnexpectedEOF
 		}
-		n = uint32(h.nodes[n].children) + 1&d.bits
+		n = uint32(h.nodes[n].children) + 1&uint32(d.bits)
 		d.bits >>= 1
 		d.nBits--
 	}
@@ -37,6 +37,9 @@ type transform struct {
 	// pix is the tile values, for the predictor and cross-color
 	// transforms, and the color palette, for the color-index transform.
 	pix []byte
+	// dst, if large enough, is re-used for the output of a color-index
+	// transform that reduces the width.
+	dst []byte
 }
 
 var inverseTransforms = [nTransformTypes]func(*transform, []byte, int32) []byte{
@@ -224,12 +227,11 @@ func inverseSubtractGreen(t *transform, pix []byte, h int32) []byte {
 
 func inverseColorIndexing(t *transform, pix []byte, h int32) []byte {
 	if t.bits == 0 {
-		for p := 0; p < len(pix); p += 4 {
-			i := 4 * uint32(pix[p+1])
-			pix[p+0] = t.pix[i+0]
-			pix[p+1] = t.pix[i+1]
-			pix[p+2] = t.pix[i+2]
-			pix[p+3] = t.pix[i+3]
+		for p := 0; p+4 <= len(pix); p += 4 {
+			s := pix[p : p+4 : p+4]
+			i := 4 * uint32(s[1])
+			c := t.pix[i : i+4 : i+4]
+			s[0], s[1], s[2], s[3] = c[0], c[1], c[2], c[3]
 		}
 		return pix
 	}
@@ -244,7 +246,12 @@ func inverseColorIndexing(t *transform, pix []byte, h int32) []byte {
 		vMask, xMask = 0x01, 0x07
 	}
 
-	d, p, v, dst := 0, 0, uint32(0), make([]byte, 4*t.oldWidth*h)
+	d, p, v, dst := 0, 0, uint32(0), t.dst
+	if n := int(4 * t.oldWidth * h); cap(dst) >= n {
+		dst = dst[:n]
+	} else {
+		dst = make([]byte, n)
+	}
 	for y := int32(0); y < h; y++ {
 		for x := int32(0); x < t.oldWidth; x++ {
 			if x&xMask == 0 {
@@ -253,10 +260,8 @@ func inverseColorIndexing(t *transform, pix []byte, h int32) []byte {
 			}
 
 			i := 4 * (v & vMask)
-			dst[d+0] = t.pix[i+0]
-			dst[d+1] = t.pix[i+1]
-			dst[d+2] = t.pix[i+2]
-			dst[d+3] = t.pix[i+3]
+			s, c := dst[d:d+4:d+4], t.pix[i:i+4:i+4]
+			s[0], s[1], s[2], s[3] = c[0], c[1], c[2], c[3]
 			d += 4
 
 			v >>= bitsPerPixel
@@ -9,30 +9,104 @@ import (
 	"errors"
 	"image"
 	"image/color"
+	"image/draw"
 	"io"
 
+	"golang.org/x/image/internal/ycbcr"
 	"golang.org/x/image/riff"
 	"golang.org/x/image/vp8"
 	"golang.org/x/image/vp8l"
 )
 
-var errInvalidFormat = errors.New("webp: invalid format")
+// ErrInvalidFormat is returned, possibly wrapped, when the data is not a
+// valid WEBP image. Errors from decoding a corrupt VP8 or VP8L bitstream keep
+// their own messages, but also match ErrInvalidFormat under errors.Is.
+var ErrInvalidFormat = errors.New("webp: invalid format")
+
+// An UnsupportedError reports that the data is a valid WEBP image, but uses a
+// feature that this package does not implement, such as animation. Another
+// decoder may be able to decode it.
+type UnsupportedError string
+
+func (e UnsupportedError) Error() string { return "webp: unsupported feature: " + string(e) }
+
+// formatError is an error from decoding the image data, such as a vp8 or vp8l
+// error, that is reported as an ErrInvalidFormat.
+type formatError struct {
+	err error
+}
+
+func (e formatError) Error() string        { return e.err.Error() }
+func (e formatError) Unwrap() error        { return e.err }
+func (e formatError) Is(target error) bool { return target == ErrInvalidFormat }
+
+// ErrLimitExceeded is returned when an image exceeds one of the limits set by
+// DecodeOptions, such as MaxPixels.
+var ErrLimitExceeded = errors.New("webp: decoding limit exceeded")
+
+// errReader records the first error, other than io.EOF, returned by r, so
+// that errors from reading can be told apart from invalid data.
+type errReader struct {
+	r   io.Reader
+	err error
+}
+
+func (e *errReader) Read(p []byte) (int, error) {
+	n, err := e.r.Read(p)
+	if err != nil && err != io.EOF && e.err == nil {
+		e.err = err
+	}
+	return n, err
+}
 
 var (
 	fccALPH = riff.FourCC{'A', 'L', 'P', 'H'}
+	fccANIM = riff.FourCC{'A', 'N', 'I', 'M'}
+	fccANMF = riff.FourCC{'A', 'N', 'M', 'F'}
+	fccEXIF = riff.FourCC{'E', 'X', 'I', 'F'}
+	fccICCP = riff.FourCC{'I', 'C', 'C', 'P'}
 	fccVP8  = riff.FourCC{'V', 'P', '8', ' '}
 	fccVP8L = riff.FourCC{'V', 'P', '8', 'L'}
 	fccVP8X = riff.FourCC{'V', 'P', '8', 'X'}
 	fccWEBP = riff.FourCC{'W', 'E', 'B', 'P'}
+	fccXMP  = riff.FourCC{'X', 'M', 'P', ' '}
 )
 
-func decode(r io.Reader, configOnly bool) (image.Image, image.Config, error) {
+// decode decodes the WEBP image in r. If md is non-nil, the image's metadata
+// chunks are recorded in it, reading past the image data to the end of the
+// RIFF data, as the EXIF and XMP chunks typically follow the image data. If
+// dst is non-nil, its pixel buffers are re-used as described for DecodeInto.
+// If opts is non-nil, its crop rectangle and limits apply, as described for
+// DecodeOptions, but it does not scale the image. If p is non-nil, a lossy
+// image's pixels are not decoded, but its decoder is recorded in p, for
+// DecodePartial to decode the pixels.
+func decode(r io.Reader, configOnly bool, md *Metadata, dst image.Image, opts *DecodeOptions, p *Partial) (image.Image, image.Config, error) {
+	er := &errReader{r: r}
+	if p != nil {
+		p.er = er
+	}
+	m, c, err := decodeChunks(er, configOnly, md, dst, opts, p)
+	return m, c, er.wrap(err)
+}
+
+// wrap returns err, an error from decoding the data read by e, as a
+// formatError if it is neither from reading nor one of this package's errors.
+func (e *errReader) wrap(err error) error {
+	if err != nil && e.err == nil && err != ErrInvalidFormat && err != ErrLimitExceeded {
+		if _, ok := err.(UnsupportedError); !ok {
+			err = formatError{err}
+		}
+	}
+	return err
+}
+
+func decodeChunks(r io.Reader, configOnly bool, md *Metadata, dst image.Image, opts *DecodeOptions, p *Partial) (image.Image, image.Config, error) {
 	formType, riffReader, err := riff.NewReader(r)
 	if err != nil {
 		return nil, image.Config{}, err
 	}
 	if formType != fccWEBP {
-		return nil, image.Config{}, errInvalidFormat
+		return nil, image.Config{}, ErrInvalidFormat
 	}
 
 	var (
@@ -44,37 +118,78 @@ func decode(r io.Reader, configOnly bool) (image.Image, image.Config, error) {
 		heightMinusOne uint32
 		buf            [10]byte
 	)
+	// The buffers to re-use, if any.
+	var (
+		dstYCbCr   *image.YCbCr
+		dstNYCbCrA *image.NYCbCrA
+		dstNRGBA   *image.NRGBA
+		dstAlpha   []byte
+	)
+	var (
+		crop           image.Rectangle
+		maxPixels      int
+		maxChunkSize   int
+		maxPartitions  int
+		maxFilterLevel int
+	)
+	if opts != nil {
+		crop, maxPixels, maxChunkSize = opts.Crop, opts.MaxPixels, opts.MaxChunkSize
+		maxPartitions, maxFilterLevel = opts.MaxPartitions, opts.MaxFilterLevel
+	}
+	// tooLarge returns whether a w×h image has more pixels than the limit.
+	tooLarge := func(w, h int) bool {
+		return maxPixels > 0 && int64(w)*int64(h) > int64(maxPixels)
+	}
+	switch dst := dst.(type) {
+	case *image.YCbCr:
+		dstYCbCr = dst
+	case *image.NYCbCrA:
+		dstYCbCr, dstNYCbCrA, dstAlpha = &dst.YCbCr, dst, dst.A
+	case *image.NRGBA:
+		dstNRGBA = dst
+	}
 	for {
 		chunkID, chunkLen, chunkData, err := riffReader.Next()
 		if err == io.EOF {
-			err = errInvalidFormat
+			err = ErrInvalidFormat
 		}
 		if err != nil {
 			return nil, image.Config{}, err
 		}
+		if maxChunkSize > 0 && chunkLen > uint32(maxChunkSize) {
+			return nil, image.Config{}, ErrLimitExceeded
+		}
 
 		switch chunkID {
 		case fccALPH:
 			if !wantAlpha {
-				return nil, image.Config{}, errInvalidFormat
+				return nil, image.Config{}, ErrInvalidFormat
 			}
 			wantAlpha = false
 			// Read the Pre-processing | Filter | Compression byte.
 			if _, err := io.ReadFull(chunkData, buf[:1]); err != nil {
 				if err == io.EOF {
-					err = errInvalidFormat
+					err = ErrInvalidFormat
 				}
 				return nil, image.Config{}, err
 			}
-			alpha, alphaStride, err = readAlpha(chunkData, widthMinusOne, heightMinusOne, buf[0]&0x03)
+			alpha, alphaStride, err = readAlpha(dstAlpha, chunkData, widthMinusOne, heightMinusOne, buf[0]&0x03)
 			if err != nil {
 				return nil, image.Config{}, err
 			}
 			unfilterAlpha(alpha, alphaStride, (buf[0]>>2)&0x03)
 
+		case fccANIM, fccANMF:
+			return nil, image.Config{}, UnsupportedError("animation")
+
 		case fccVP8:
-			if wantAlpha || int32(chunkLen) < 0 {
-				return nil, image.Config{}, errInvalidFormat
+			if wantAlpha {
+				// The VP8X chunk's alpha flag is set, but there is no ALPH
+				// chunk. The format allows this, but this package does not.
+				return nil, image.Config{}, UnsupportedError("VP8X alpha flag without an ALPH chunk")
+			}
+			if int32(chunkLen) < 0 {
+				return nil, image.Config{}, ErrInvalidFormat
 			}
 			d := vp8.NewDecoder()
 			d.Init(chunkData, int(chunkLen))
@@ -89,11 +204,47 @@ func decode(r io.Reader, configOnly bool) (image.Image, image.Config, error) {
 					Height:     fh.Height,
 				}, nil
 			}
-			m, err := d.DecodeFrame()
+			if tooLarge(fh.Width, fh.Height) {
+				return nil, image.Config{}, ErrLimitExceeded
+			}
+			if maxPartitions > 0 || maxFilterLevel > 0 {
+				// Check the partition count and filter levels in the frame's
+				// other headers, which DecodeFrame then re-uses.
+				fi, err := d.DecodeFrameInfo()
+				if err != nil {
+					return nil, image.Config{}, err
+				}
+				if maxPartitions > 0 && fi.NumPartitions > maxPartitions {
+					return nil, image.Config{}, ErrLimitExceeded
+				}
+				for _, level := range fi.FilterLevel {
+					if maxFilterLevel > 0 && level > maxFilterLevel {
+						return nil, image.Config{}, ErrLimitExceeded
+					}
+				}
+			}
+			if p != nil {
+				p.d, p.alpha, p.alphaStride = d, alpha, alphaStride
+				return nil, image.Config{}, nil
+			}
+			d.SetCrop(crop)
+			m := dstYCbCr
+			if m != nil {
+				err = d.DecodeFrameInto(m)
+			} else {
+				m, err = d.DecodeFrame()
+			}
 			if err != nil {
 				return nil, image.Config{}, err
 			}
+			if err := readMetadata(riffReader, md); err != nil {
+				return nil, image.Config{}, err
+			}
 			if alpha != nil {
+				if dstNYCbCrA != nil {
+					dstNYCbCrA.A, dstNYCbCrA.AStride = alpha, alphaStride
+					return dstNYCbCrA, image.Config{}, nil
+				}
 				return &image.NYCbCrA{
 					YCbCr:   *m,
 					A:       alpha,
@@ -103,23 +254,54 @@ func decode(r io.Reader, configOnly bool) (image.Image, image.Config, error) {
 			return m, image.Config{}, nil
 
 		case fccVP8L:
-			if wantAlpha || alpha != nil {
-				return nil, image.Config{}, errInvalidFormat
+			// A VP8L bitstream has its own alpha channel, so the VP8X chunk's
+			// alpha flag may be set, but there must not be an ALPH chunk.
+			if alpha != nil {
+				return nil, image.Config{}, ErrInvalidFormat
 			}
 			if configOnly {
 				c, err := vp8l.DecodeConfig(chunkData)
 				return nil, c, err
 			}
-			m, err := vp8l.Decode(chunkData)
-			return m, image.Config{}, err
+			if maxPixels > 0 {
+				// Check the size in the 5-byte VP8L header, and then decode
+				// the whole bitstream, header included.
+				n, _ := io.ReadFull(chunkData, buf[:5])
+				hdr := append([]byte(nil), buf[:n]...)
+				if n == 5 {
+					bits := uint32(hdr[1]) | uint32(hdr[2])<<8 | uint32(hdr[3])<<16 | uint32(hdr[4])<<24
+					if tooLarge(int(bits&0x3fff)+1, int(bits>>14&0x3fff)+1) {
+						return nil, image.Config{}, ErrLimitExceeded
+					}
+				}
+				chunkData = io.MultiReader(bytes.NewReader(hdr), chunkData)
+			}
+			var m image.Image
+			if dstNRGBA != nil {
+				m, err = dstNRGBA, vp8l.DecodeInto(dstNRGBA, chunkData)
+			} else {
+				m, err = vp8l.Decode(chunkData)
+			}
+			if err != nil {
+				return nil, image.Config{}, err
+			}
+			if err := readMetadata(riffReader, md); err != nil {
+				return nil, image.Config{}, err
+			}
+			return m, image.Config{}, nil
+
+		case fccEXIF, fccICCP, fccXMP:
+			if err := readMetadataChunk(chunkID, chunkData, md); err != nil {
+				return nil, image.Config{}, err
+			}
 
 		case fccVP8X:
 			if seenVP8X {
-				return nil, image.Config{}, errInvalidFormat
+				return nil, image.Config{}, ErrInvalidFormat
 			}
 			seenVP8X = true
 			if chunkLen != 10 {
-				return nil, image.Config{}, errInvalidFormat
+				return nil, image.Config{}, ErrInvalidFormat
 			}
 			if _, err := io.ReadFull(chunkData, buf[:10]); err != nil {
 				return nil, image.Config{}, err
@@ -134,6 +316,12 @@ func decode(r io.Reader, configOnly bool) (image.Image, image.Config, error) {
 			wantAlpha = (buf[0] & alphaBit) != 0
 			widthMinusOne = uint32(buf[4]) | uint32(buf[5])<<8 | uint32(buf[6])<<16
 			heightMinusOne = uint32(buf[7]) | uint32(buf[8])<<8 | uint32(buf[9])<<16
+			if !configOnly && (buf[0]&animationBit) != 0 {
+				return nil, image.Config{}, UnsupportedError("animation")
+			}
+			if !configOnly && tooLarge(int(widthMinusOne)+1, int(heightMinusOne)+1) {
+				return nil, image.Config{}, ErrLimitExceeded
+			}
 			if configOnly {
 				if wantAlpha {
 					return nil, image.Config{
@@ -152,14 +340,81 @@ func decode(r io.Reader, configOnly bool) (image.Image, image.Config, error) {
 	}
 }
 
-func readAlpha(chunkData io.Reader, widthMinusOne, heightMinusOne uint32, compression byte) (
+// Metadata holds the raw payloads of a WEBP image's metadata chunks. A field
+// is nil if the image does not have that chunk. Options.Metadata and
+// ReplaceMetadata write the chunks for its non-nil fields.
+type Metadata struct {
+	// ICCProfile is the ICCP chunk's ICC color profile.
+	ICCProfile []byte
+	// EXIF is the EXIF chunk's Exif metadata.
+	EXIF []byte
+	// XMP is the XMP chunk's XMP metadata.
+	XMP []byte
+}
+
+// readMetadata reads the chunks that follow the image data, up to the end of
+// the RIFF data, recording the metadata chunks in md. It does nothing if md is
+// nil.
+func readMetadata(riffReader *riff.Reader, md *Metadata) error {
+	if md == nil {
+		return nil
+	}
+	for {
+		chunkID, _, chunkData, err := riffReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := readMetadataChunk(chunkID, chunkData, md); err != nil {
+			return err
+		}
+	}
+}
+
+// readMetadataChunk records the payload of the chunk with the given ID in md,
+// if it is a metadata chunk and md is non-nil. Only the first chunk of each
+// kind is recorded.
+func readMetadataChunk(chunkID riff.FourCC, chunkData io.Reader, md *Metadata) error {
+	if md == nil {
+		return nil
+	}
+	var dst *[]byte
+	switch chunkID {
+	case fccICCP:
+		dst = &md.ICCProfile
+	case fccEXIF:
+		dst = &md.EXIF
+	case fccXMP:
+		dst = &md.XMP
+	default:
+		return nil
+	}
+	if *dst != nil {
+		return nil
+	}
+	b, err := io.ReadAll(chunkData)
+	if err != nil {
+		return err
+	}
+	if b == nil {
+		b = []byte{}
+	}
+	*dst = b
+	return nil
+}
+
+// readAlpha reads the alpha values of an ALPH chunk, re-using buf if its
+// capacity is large enough.
+func readAlpha(buf []byte, chunkData io.Reader, widthMinusOne, heightMinusOne uint32, compression byte) (
 	alpha []byte, alphaStride int, err error) {
 
 	switch compression {
 	case 0:
 		w := int(widthMinusOne) + 1
 		h := int(heightMinusOne) + 1
-		alpha = make([]byte, w*h)
+		alpha = makeAlpha(buf, w*h)
 		if _, err := io.ReadFull(chunkData, alpha); err != nil {
 			return nil, 0, err
 		}
@@ -169,13 +424,12 @@ func readAlpha(chunkData io.Reader, widthMinusOne, heightMinusOne uint32, compre
 		// Read the VP8L-compressed alpha values. First, synthesize a 5-byte VP8L header:
 		// a 1-byte magic number, a 14-bit widthMinusOne, a 14-bit heightMinusOne,
 		// a 1-bit (ignored, zero) alphaIsUsed and a 3-bit (zero) version.
-		// TODO(nigeltao): be more efficient than decoding an *image.NRGBA just to
-		// extract the green values to a separately allocated []byte. Fixing this
-		// will require changes to the vp8l package's API.
+		// The green values of the VP8L image are the alpha values of the
+		// outer NYCbCrA image.
 		if widthMinusOne > 0x3fff || heightMinusOne > 0x3fff {
-			return nil, 0, errors.New("webp: invalid format")
+			return nil, 0, ErrInvalidFormat
 		}
-		alphaImage, err := vp8l.Decode(io.MultiReader(
+		alpha, err := vp8l.DecodeGreen(buf, io.MultiReader(
 			bytes.NewReader([]byte{
 				0x2f, // VP8L magic number.
 				uint8(widthMinusOne),
@@ -188,16 +442,18 @@ func readAlpha(chunkData io.Reader, widthMinusOne, heightMinusOne uint32, compre
 		if err != nil {
 			return nil, 0, err
 		}
-		// The green values of the inner NRGBA image are the alpha values of the
-		// outer NYCbCrA image.
-		pix := alphaImage.(*image.NRGBA).Pix
-		alpha = make([]byte, len(pix)/4)
-		for i := range alpha {
-			alpha[i] = pix[4*i+1]
-		}
 		return alpha, int(widthMinusOne) + 1, nil
 	}
-	return nil, 0, errInvalidFormat
+	return nil, 0, ErrInvalidFormat
+}
+
+// makeAlpha returns a slice of n bytes, re-using buf if its capacity is large
+// enough.
+func makeAlpha(buf []byte, n int) []byte {
+	if cap(buf) >= n {
+		return buf[:n]
+	}
+	return make([]byte, n)
 }
 
 func unfilterAlpha(alpha []byte, alphaStride int, filter byte) {
@@ -256,21 +512,358 @@ func unfilterAlpha(alpha []byte, alphaStride int, filter byte) {
 }
 
 // Decode reads a WEBP image from r and returns it as an image.Image.
+//
+// If the data is not a valid WEBP image, the error matches ErrInvalidFormat
+// under errors.Is. If it is valid but uses an unsupported feature, such as
+// animation, the error is an UnsupportedError. Errors from reading r are
+// returned as is.
 func Decode(r io.Reader) (image.Image, error) {
-	m, _, err := decode(r, false)
+	m, _, err := decode(r, false, nil, nil, nil, nil)
 	if err != nil {
 		return nil, err
 	}
 	return m, err
 }
 
+// DecodeInto is like Decode, but it decodes into dst's pixel buffers, if their
+// capacity is large enough, instead of allocating new ones. dst is typically
+// the image returned by a previous call, so that decoding a series of images,
+// such as when generating thumbnails, allocates less memory than calling
+// Decode for each one. It may also be nil.
+//
+// Lossy images re-use the buffers of an *image.YCbCr or *image.NYCbCrA dst,
+// and lossless images those of an *image.NRGBA dst. The returned image is dst
+// itself if it has the type that Decode would return, and otherwise it shares
+// the buffers, if any, that it re-uses with dst. If DecodeInto returns an
+// error, the contents of those buffers are unspecified.
+func DecodeInto(dst image.Image, r io.Reader) (image.Image, error) {
+	m, _, err := decode(r, false, nil, dst, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// DecodeOptions are the parameters for DecodeWithOptions.
+type DecodeOptions struct {
+	// Crop, if non-empty, is the part of the image to decode, in the
+	// coordinates of the image, whose top-left is (0, 0). It is clipped to the
+	// image's bounds. For lossy images, the parts of the image that are below
+	// and to the right of Crop are not fully decoded.
+	Crop image.Rectangle
+
+	// The limits below protect services that decode untrusted images, such as
+	// thumbnailers, from images that would take too much time or memory to
+	// decode. Decoding an image that exceeds a limit fails with
+	// ErrLimitExceeded, before the costly work. Zero means no limit.
+
+	// MaxPixels is the largest number of pixels, width times height, of the
+	// image, as given by its VP8X, VP8 or VP8L header. The time and memory to
+	// decode an image grow with its number of pixels, before any cropping or
+	// scaling.
+	MaxPixels int
+	// MaxChunkSize is the largest size, in bytes, of each of the image's RIFF
+	// chunks. The time to decode an image also grows with the size of its
+	// compressed data, which a pathological image can pad out, such as with
+	// huge VP8 partitions.
+	MaxChunkSize int
+	// MaxPartitions is the largest number of DCT/WHT coefficient partitions,
+	// 1, 2, 4 or 8, of a lossy image's VP8 bitstream.
+	MaxPartitions int
+	// MaxFilterLevel is the largest loop filter level, from 1 to 63, of any of
+	// a lossy image's segments. A frame that is not loop filtered is always
+	// within the limit: zero means no limit, so loop filtering cannot be
+	// disallowed altogether.
+	//
+	// Together with MaxPixels, MaxPartitions and MaxFilterLevel bound the
+	// work to decode a small but valid lossy image whose headers ask for the
+	// most expensive decoding. None of the limits bound the work to decode a
+	// lossless image's entropy-coded data, other than by its size.
+	MaxFilterLevel int
+}
+
+// DecodeWithOptions is like Decode, but it crops the image, and limits the
+// work to decode it, as specified by opts. A nil *DecodeOptions is equivalent
+// to calling Decode.
+// It is typically used to make previews or thumbnails, without the cost of
+// fully decoding an image only to discard most of it. The
+// golang.org/x/image/webp/webpscale package also scales the cropped image.
+//
+// A cropped image is the SubImage of the decoded image, and its bounds are the
+// crop rectangle.
+func DecodeWithOptions(r io.Reader, opts *DecodeOptions) (image.Image, error) {
+	if opts == nil {
+		return Decode(r)
+	}
+	m, _, err := decode(r, false, nil, nil, opts, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !opts.Crop.Empty() {
+		m = m.(interface {
+			SubImage(image.Rectangle) image.Image
+		}).SubImage(opts.Crop)
+	}
+	return m, nil
+}
+
+// DecodeMetadata is like Decode, but it also returns the image's ICC profile,
+// Exif and XMP metadata, which are carried by the ICCP, EXIF and XMP chunks of
+// images in the extended WEBP format. Unlike Decode, it reads the data that
+// follows the image data, as that is where the EXIF and XMP chunks usually
+// are.
+func DecodeMetadata(r io.Reader) (image.Image, *Metadata, error) {
+	md := &Metadata{}
+	m, _, err := decode(r, false, md, nil, nil, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return m, md, nil
+}
+
+// DecodeRGBA is like Decode, but it returns the image as an *image.RGBA,
+// converting lossy images from Y'CbCr and premultiplying the alpha of images
+// that have an alpha channel.
+func DecodeRGBA(r io.Reader) (*image.RGBA, error) {
+	m, _, err := decode(r, false, nil, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	b := m.Bounds()
+	dst := image.NewRGBA(b)
+	switch m := m.(type) {
+	case *image.YCbCr:
+		ycbcr.ToRGBA(dst, b, m)
+	case *image.NYCbCrA:
+		ycbcr.NYCbCrAToRGBA(dst, b, m)
+	default:
+		draw.Draw(dst, b, m, b.Min, draw.Src)
+	}
+	return dst, nil
+}
+
+// DecodeGray is like Decode, but it returns the image as an *image.Gray. It
+// keeps the luma of lossy images and converts lossless images to gray,
+// dropping any chroma and alpha. For example, an OCR pipeline that only needs
+// the luma of document photographs can use it to hold two thirds as much pixel
+// data as the Y'CbCr image of a lossy image, and a quarter as much as
+// DecodeRGBA.
+func DecodeGray(r io.Reader) (*image.Gray, error) {
+	m, _, err := decode(r, false, nil, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return toGray(m), nil
+}
+
+// DecodeAutoGray is like Decode, but if the image is a lossy image without an
+// alpha channel whose chroma is neutral, that is, whose every Cb and Cr sample
+// is 128, it returns the image as an *image.Gray, as DecodeGray does. Such an
+// image has the same colors either way. Other images are returned as by
+// Decode.
+func DecodeAutoGray(r io.Reader) (image.Image, error) {
+	m, _, err := decode(r, false, nil, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return autoGray(m), nil
+}
+
+// autoGray returns m as an *image.Gray if it is a Y'CbCr image with neutral
+// chroma, and m otherwise.
+func autoGray(m image.Image) image.Image {
+	if m, ok := m.(*image.YCbCr); ok && m.SubsampleRatio == image.YCbCrSubsampleRatio420 {
+		// Check the chroma samples that the image's pixels use, but not the
+		// padding to the macroblock boundaries.
+		cw := (m.Rect.Max.X+1)/2 - m.Rect.Min.X/2
+		ch := (m.Rect.Max.Y+1)/2 - m.Rect.Min.Y/2
+		i0 := m.COffset(m.Rect.Min.X, m.Rect.Min.Y)
+		for y := 0; y < ch; y++ {
+			i := i0 + y*m.CStride
+			for _, c := range m.Cb[i : i+cw] {
+				if c != 0x80 {
+					return m
+				}
+			}
+			for _, c := range m.Cr[i : i+cw] {
+				if c != 0x80 {
+					return m
+				}
+			}
+		}
+		return toGray(m)
+	}
+	return m
+}
+
+// toGray returns m's luma, without any alpha, as a newly allocated
+// *image.Gray.
+func toGray(m image.Image) *image.Gray {
+	b := m.Bounds()
+	dst := image.NewGray(b)
+	switch m := m.(type) {
+	case *image.YCbCr:
+		copyLuma(dst, m)
+	case *image.NYCbCrA:
+		copyLuma(dst, &m.YCbCr)
+	case *image.NRGBA:
+		// Use the weights of color.GrayModel, on the non-premultiplied red,
+		// green and blue values.
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			src := m.Pix[m.PixOffset(b.Min.X, y):m.PixOffset(b.Max.X, y)]
+			d := dst.Pix[dst.PixOffset(b.Min.X, y):dst.PixOffset(b.Max.X, y)]
+			for i := range d {
+				s := src[4*i : 4*i+3 : 4*i+3]
+				r := uint32(s[0]) * 0x101
+				g := uint32(s[1]) * 0x101
+				b := uint32(s[2]) * 0x101
+				d[i] = uint8((19595*r + 38470*g + 7471*b + 1<<15) >> 24)
+			}
+		}
+	default:
+		draw.Draw(dst, b, m, b.Min, draw.Src)
+	}
+	return dst
+}
+
+// copyLuma copies src's Y plane to dst, which has the same bounds.
+func copyLuma(dst *image.Gray, src *image.YCbCr) {
+	b := src.Rect
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		i := src.YOffset(b.Min.X, y)
+		copy(dst.Pix[dst.PixOffset(b.Min.X, y):dst.PixOffset(b.Max.X, y)], src.Y[i:i+b.Dx()])
+	}
+}
+
 // DecodeConfig returns the color model and dimensions of a WEBP image without
-// decoding the entire image.
+// decoding the entire image. DecodeFeatures also reports which features, such
+// as animation, the image uses.
 func DecodeConfig(r io.Reader) (image.Config, error) {
-	_, c, err := decode(r, true)
+	_, c, err := decode(r, true, nil, nil, nil, nil)
 	return c, err
 }
 
+// Features describes a WEBP image's canvas and the features that it uses, as
+// given by its headers.
+type Features struct {
+	// Width and Height are the size of the canvas, which is the image's size
+	// for still images.
+	Width, Height int
+	// Extended is whether the image is in the extended format, which has a
+	// VP8X chunk. ICCProfile, EXIF, XMP and Animation are always false for
+	// images in the simple formats.
+	Extended bool
+	// Alpha is whether the image has an alpha channel. For images in the
+	// extended format, it is the VP8X chunk's alpha flag. For lossless images
+	// in the simple format, it is the VP8L header's alpha hint.
+	Alpha bool
+	// Animation is whether the image is animated.
+	Animation bool
+	// ICCProfile, EXIF and XMP are whether the image has the corresponding
+	// metadata chunks, according to the VP8X chunk's flags.
+	ICCProfile, EXIF, XMP bool
+	// Lossless is whether the image data is a lossless VP8L bitstream, as
+	// opposed to a lossy VP8 one. It is false for animated images, whose
+	// frames may each be either.
+	Lossless bool
+}
+
+// DecodeFeatures returns the canvas size and the features of a WEBP image,
+// reading only its headers. Unlike Decode, it also reports the features of
+// images that this package cannot decode, such as animated images.
+func DecodeFeatures(r io.Reader) (Features, error) {
+	er := &errReader{r: r}
+	f, err := decodeFeatures(er)
+	return f, er.wrap(err)
+}
+
+func decodeFeatures(r io.Reader) (Features, error) {
+	formType, riffReader, err := riff.NewReader(r)
+	if err != nil {
+		return Features{}, err
+	}
+	if formType != fccWEBP {
+		return Features{}, ErrInvalidFormat
+	}
+	var (
+		f   Features
+		buf [10]byte
+	)
+	for {
+		chunkID, chunkLen, chunkData, err := riffReader.Next()
+		if err == io.EOF {
+			err = ErrInvalidFormat
+		}
+		if err != nil {
+			return Features{}, err
+		}
+
+		switch chunkID {
+		case fccVP8:
+			if !f.Extended {
+				if int32(chunkLen) < 0 {
+					return Features{}, ErrInvalidFormat
+				}
+				d := vp8.NewDecoder()
+				d.Init(chunkData, int(chunkLen))
+				fh, err := d.DecodeFrameHeader()
+				if err != nil {
+					return Features{}, err
+				}
+				f.Width, f.Height = fh.Width, fh.Height
+			}
+			return f, nil
+
+		case fccVP8L:
+			f.Lossless = true
+			if !f.Extended {
+				// Read the 1-byte magic number, the 14-bit widthMinusOne and
+				// heightMinusOne, the 1-bit alphaIsUsed and the 3-bit version.
+				if _, err := io.ReadFull(chunkData, buf[:5]); err != nil {
+					if err == io.EOF || err == io.ErrUnexpectedEOF {
+						err = ErrInvalidFormat
+					}
+					return Features{}, err
+				}
+				bits := uint32(buf[1]) | uint32(buf[2])<<8 | uint32(buf[3])<<16 | uint32(buf[4])<<24
+				if buf[0] != 0x2f || bits>>29 != 0 {
+					return Features{}, ErrInvalidFormat
+				}
+				f.Width = int(bits&0x3fff) + 1
+				f.Height = int(bits>>14&0x3fff) + 1
+				f.Alpha = bits>>28&1 != 0
+			}
+			return f, nil
+
+		case fccVP8X:
+			if f.Extended || chunkLen != 10 {
+				return Features{}, ErrInvalidFormat
+			}
+			if _, err := io.ReadFull(chunkData, buf[:10]); err != nil {
+				return Features{}, err
+			}
+			const (
+				animationBit    = 1 << 1
+				xmpMetadataBit  = 1 << 2
+				exifMetadataBit = 1 << 3
+				alphaBit        = 1 << 4
+				iccProfileBit   = 1 << 5
+			)
+			f.Extended = true
+			f.Animation = buf[0]&animationBit != 0
+			f.XMP = buf[0]&xmpMetadataBit != 0
+			f.EXIF = buf[0]&exifMetadataBit != 0
+			f.Alpha = buf[0]&alphaBit != 0
+			f.ICCProfile = buf[0]&iccProfileBit != 0
+			f.Width = int(uint32(buf[4])|uint32(buf[5])<<8|uint32(buf[6])<<16) + 1
+			f.Height = int(uint32(buf[7])|uint32(buf[8])<<8|uint32(buf[9])<<16) + 1
+			if f.Animation {
+				return f, nil
+			}
+		}
+	}
+}
+
 func init() {
 	image.RegisterFormat("webp", "RIFF????WEBPVP8", Decode, DecodeConfig)
 }
@@ -6,11 +6,15 @@ package webp
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"image"
+	"image/color"
 	"image/png"
+	"io"
 	"io/ioutil"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -258,6 +262,171 @@ loop:
 // independent of the actual image size (0 pixels wide * 0 pixels high).
 //
 // This is based on golang.org/issue/10790.
+func TestDecodeRGBA(t *testing.T) {
+	testCases := []string{
+		"blue-purple-pink.lossy.webp",
+		"yellow_rose.lossy-with-alpha.webp",
+		"yellow_rose.lossless.webp",
+	}
+
+	for _, tc := range testCases {
+		data, err := ioutil.ReadFile("../testdata/" + tc)
+		if err != nil {
+			t.Errorf("%s: ReadFile: %v", tc, err)
+			continue
+		}
+		want, err := Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Errorf("%s: Decode: %v", tc, err)
+			continue
+		}
+		got, err := DecodeRGBA(bytes.NewReader(data))
+		if err != nil {
+			t.Errorf("%s: DecodeRGBA: %v", tc, err)
+			continue
+		}
+		if got.Rect != want.Bounds() {
+			t.Errorf("%s: bounds: got %v, want %v", tc, got.Rect, want.Bounds())
+			continue
+		}
+	loop:
+		for y := got.Rect.Min.Y; y < got.Rect.Max.Y; y++ {
+			for x := got.Rect.Min.X; x < got.Rect.Max.X; x++ {
+				if g, w := got.RGBAAt(x, y), color.RGBAModel.Convert(want.At(x, y)); g != w {
+					t.Errorf("%s: (%d, %d): got %v, want %v", tc, x, y, g, w)
+					break loop
+				}
+			}
+		}
+	}
+}
+
+func TestDecodeGray(t *testing.T) {
+	testCases := []string{
+		"blue-purple-pink.lossy.webp",
+		"yellow_rose.lossy-with-alpha.webp",
+		"blue-purple-pink.lossless.webp",
+		"yellow_rose.lossless.webp",
+	}
+
+	for _, tc := range testCases {
+		data, err := ioutil.ReadFile("../testdata/" + tc)
+		if err != nil {
+			t.Errorf("%s: ReadFile: %v", tc, err)
+			continue
+		}
+		want, err := Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Errorf("%s: Decode: %v", tc, err)
+			continue
+		}
+		got, err := DecodeGray(bytes.NewReader(data))
+		if err != nil {
+			t.Errorf("%s: DecodeGray: %v", tc, err)
+			continue
+		}
+		if got.Rect != want.Bounds() {
+			t.Errorf("%s: bounds: got %v, want %v", tc, got.Rect, want.Bounds())
+			continue
+		}
+	loop:
+		for y := got.Rect.Min.Y; y < got.Rect.Max.Y; y++ {
+			for x := got.Rect.Min.X; x < got.Rect.Max.X; x++ {
+				var w uint8
+				switch want := want.(type) {
+				case *image.YCbCr:
+					w = want.YCbCrAt(x, y).Y
+				case *image.NYCbCrA:
+					w = want.NYCbCrAAt(x, y).Y
+				case *image.NRGBA:
+					c := want.NRGBAAt(x, y)
+					c.A = 0xff
+					w = color.GrayModel.Convert(c).(color.Gray).Y
+				default:
+					t.Fatalf("%s: unexpected image type %T", tc, want)
+				}
+				if g := got.GrayAt(x, y).Y; g != w {
+					t.Errorf("%s: (%d, %d): got %#02x, want %#02x", tc, x, y, g, w)
+					break loop
+				}
+			}
+		}
+	}
+}
+
+func TestDecodeAutoGray(t *testing.T) {
+	// None of these images are gray.
+	testCases := []string{
+		"blue-purple-pink.lossy.webp",
+		"yellow_rose.lossy-with-alpha.webp",
+		"blue-purple-pink.lossless.webp",
+	}
+
+	for _, tc := range testCases {
+		data, err := ioutil.ReadFile("../testdata/" + tc)
+		if err != nil {
+			t.Errorf("%s: ReadFile: %v", tc, err)
+			continue
+		}
+		want, err := Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Errorf("%s: Decode: %v", tc, err)
+			continue
+		}
+		got, err := DecodeAutoGray(bytes.NewReader(data))
+		if err != nil {
+			t.Errorf("%s: DecodeAutoGray: %v", tc, err)
+			continue
+		}
+		if g, w := fmt.Sprintf("%T", got), fmt.Sprintf("%T", want); g != w {
+			t.Errorf("%s: got %s, want %s", tc, g, w)
+		}
+	}
+}
+
+func TestAutoGray(t *testing.T) {
+	// The image is not aligned to its macroblocks, and its padding has
+	// colorful chroma, as a VP8 frame's may.
+	full := image.NewYCbCr(image.Rect(0, 0, 32, 32), image.YCbCrSubsampleRatio420)
+	for i := range full.Y {
+		full.Y[i] = uint8(i)
+		full.Cb[i/4] = 0x10
+		full.Cr[i/4] = 0xf0
+	}
+	m := full.SubImage(image.Rect(0, 0, 19, 21)).(*image.YCbCr)
+	for y := 0; y < 11; y++ {
+		for x := 0; x < 10; x++ {
+			m.Cb[y*m.CStride+x] = 0x80
+			m.Cr[y*m.CStride+x] = 0x80
+		}
+	}
+
+	got, ok := autoGray(m).(*image.Gray)
+	if !ok {
+		t.Fatalf("neutral chroma: got %T, want *image.Gray", autoGray(m))
+	}
+	if got.Rect != m.Rect {
+		t.Fatalf("bounds: got %v, want %v", got.Rect, m.Rect)
+	}
+	for y := 0; y < m.Rect.Dy(); y++ {
+		for x := 0; x < m.Rect.Dx(); x++ {
+			if g, w := got.At(x, y), color.GrayModel.Convert(m.At(x, y)); g != w {
+				t.Fatalf("(%d, %d): got %v, want %v", x, y, g, w)
+			}
+		}
+	}
+
+	// Any other chroma keeps the image as is.
+	for _, p := range [][]uint8{m.Cb, m.Cr} {
+		i := 10*m.CStride + 9
+		p[i] = 0x81
+		if got := autoGray(m); got != image.Image(m) {
+			t.Errorf("colorful chroma: got %T, want the *image.YCbCr", got)
+		}
+		p[i] = 0x80
+	}
+}
+
 func TestDecodePartitionTooLarge(t *testing.T) {
 	data := "RIFF\xff\xff\xff\x7fWEBPVP8 " +
 		"\x78\x56\x34\x12" + // RIFF chunk length.
@@ -274,8 +443,323 @@ func TestDecodePartitionTooLarge(t *testing.T) {
 func TestDuplicateVP8X(t *testing.T) {
 	data := []byte{'R', 'I', 'F', 'F', 49, 0, 0, 0, 'W', 'E', 'B', 'P', 'V', 'P', '8', 'X', 10, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 'V', 'P', '8', 'X', 10, 0, 0, 0, 0x10, 0, 0, 0, 0, 0, 0, 0, 0, 0}
 	_, err := Decode(bytes.NewReader(data))
-	if err != errInvalidFormat {
-		t.Fatalf("unexpected error: want %q, got %q", errInvalidFormat, err)
+	if err != ErrInvalidFormat {
+		t.Fatalf("unexpected error: want %q, got %q", ErrInvalidFormat, err)
+	}
+}
+
+// riffWEBP returns a WEBP file made of the given chunks, each of which is a
+// FourCC followed by the chunk data.
+func riffWEBP(chunks ...string) string {
+	body := "WEBP"
+	for _, c := range chunks {
+		n := len(c) - 4
+		body += c[:4] + string([]byte{uint8(n), uint8(n >> 8), uint8(n >> 16), uint8(n >> 24)}) + c[4:]
+		if n%2 != 0 {
+			body += "\x00"
+		}
+	}
+	n := len(body)
+	return "RIFF" + string([]byte{uint8(n), uint8(n >> 8), uint8(n >> 16), uint8(n >> 24)}) + body
+}
+
+type failingReader struct {
+	r   io.Reader
+	n   int
+	err error
+}
+
+func (f *failingReader) Read(p []byte) (int, error) {
+	if f.n <= 0 {
+		return 0, f.err
+	}
+	if len(p) > f.n {
+		p = p[:f.n]
+	}
+	n, err := f.r.Read(p)
+	f.n -= n
+	return n, err
+}
+
+func TestDecodeErrors(t *testing.T) {
+	lossy, err := ioutil.ReadFile("../testdata/blue-purple-pink.lossy.webp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lossless, err := ioutil.ReadFile("../testdata/tux.lossless.webp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The VP8 and VP8L chunks, with their headers, follow the 12 byte RIFF
+	// header.
+	vp8Chunk, vp8lChunk := string(lossy[12:]), string(lossless[12:])
+	vp8lChunk = vp8lChunk[:4] + vp8lChunk[8:]
+	vp8Chunk = vp8Chunk[:4] + vp8Chunk[8:]
+	// vp8x returns a VP8X chunk for a 16x16 image with the given flags.
+	vp8x := func(flags byte) string {
+		return "VP8X" + string([]byte{flags, 0, 0, 0, 15, 0, 0, 15, 0, 0})
+	}
+	const (
+		animationBit = 1 << 1
+		alphaBit     = 1 << 4
+	)
+
+	testCases := []struct {
+		desc        string
+		data        string
+		invalid     bool
+		unsupported bool
+	}{
+		{"animated", riffWEBP(vp8x(animationBit), "ANIM"+strings.Repeat("\x00", 6)), false, true},
+		{"ANMF chunk", riffWEBP(vp8x(0), "ANMF"+strings.Repeat("\x00", 16)), false, true},
+		{"alpha flag without ALPH", riffWEBP(vp8x(alphaBit), vp8Chunk), false, true},
+		{"bad form type", "RIFF\x04\x00\x00\x00WEBQ", true, false},
+		{"no image", riffWEBP(vp8x(0)), true, false},
+		{"truncated VP8", riffWEBP(vp8Chunk[:len(vp8Chunk)/2]), true, false},
+		{"corrupt VP8L", riffWEBP("VP8L\x2f\xff\xff\xff\xff\xff\xff\xff"), true, false},
+		{"ALPH with VP8L", riffWEBP(vp8x(alphaBit), "ALPH\x00"+strings.Repeat("\x00", 256), vp8lChunk), true, false},
+	}
+	for _, tc := range testCases {
+		_, err := Decode(strings.NewReader(tc.data))
+		if err == nil {
+			t.Errorf("%s: got nil error", tc.desc)
+			continue
+		}
+		var ue UnsupportedError
+		if got := errors.Is(err, ErrInvalidFormat); got != tc.invalid {
+			t.Errorf("%s: %v: errors.Is(err, ErrInvalidFormat): got %t, want %t", tc.desc, err, got, tc.invalid)
+		}
+		if got := errors.As(err, &ue); got != tc.unsupported {
+			t.Errorf("%s: %v: errors.As(err, &UnsupportedError): got %t, want %t", tc.desc, err, got, tc.unsupported)
+		}
+	}
+
+	// DecodeConfig does not need to decode the frames of an animated image.
+	if _, err := DecodeConfig(strings.NewReader(riffWEBP(vp8x(animationBit), "ANIM"+strings.Repeat("\x00", 6)))); err != nil {
+		t.Errorf("DecodeConfig of animated image: %v", err)
+	}
+
+	// A VP8L image in an extended format file may have the alpha flag set.
+	if _, err := Decode(strings.NewReader(riffWEBP("VP8X"+string([]byte{alphaBit, 0, 0, 0, 0, 0, 0, 0, 0, 0}), vp8lChunk))); err != nil {
+		t.Errorf("VP8L with alpha flag: %v", err)
+	}
+
+	// Errors from reading are returned as is.
+	errRead := errors.New("read error")
+	_, err = Decode(&failingReader{r: bytes.NewReader(lossy), n: len(lossy) / 2, err: errRead})
+	if err != errRead {
+		t.Errorf("failing reader: got %v, want %v", err, errRead)
+	}
+}
+
+func TestDecodeAlphaFilters(t *testing.T) {
+	lossy, err := ioutil.ReadFile("../testdata/blue-purple-pink.lossy.webp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := DecodeConfig(bytes.NewReader(lossy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, h := c.Width, c.Height
+	vp8Chunk := string(lossy[12:])
+	vp8Chunk = vp8Chunk[:4] + vp8Chunk[8:]
+	const alphaBit = 1 << 4
+	vp8x := "VP8X" + string([]byte{alphaBit, 0, 0, 0,
+		uint8(w - 1), uint8((w - 1) >> 8), uint8((w - 1) >> 16),
+		uint8(h - 1), uint8((h - 1) >> 8), uint8((h - 1) >> 16),
+	})
+
+	want := make([]byte, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			want[y*w+x] = uint8(x*x + 3*y + x*y/5)
+		}
+	}
+	at := func(x, y int) int { return int(want[y*w+x]) }
+	// predict returns the filter's prediction of the alpha value at (x, y),
+	// as per the "Alpha Chunk" section of the WebP container specification.
+	predict := func(filter, x, y int) int {
+		switch {
+		case filter == 0 || x == 0 && y == 0:
+			return 0
+		case y == 0:
+			return at(x-1, y)
+		case x == 0:
+			return at(x, y-1)
+		case filter == 1:
+			return at(x-1, y)
+		case filter == 2:
+			return at(x, y-1)
+		}
+		g := at(x-1, y) + at(x, y-1) - at(x-1, y-1)
+		if g < 0 {
+			g = 0
+		} else if g > 255 {
+			g = 255
+		}
+		return g
+	}
+
+	for filter := 0; filter < 4; filter++ {
+		// The ALPH chunk's header byte has the filter and no compression.
+		alph := []byte{uint8(filter << 2)}
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				alph = append(alph, uint8(at(x, y)-predict(filter, x, y)))
+			}
+		}
+		m, err := Decode(strings.NewReader(riffWEBP(vp8x, "ALPH"+string(alph), vp8Chunk)))
+		if err != nil {
+			t.Errorf("filter %d: %v", filter, err)
+			continue
+		}
+		got, ok := m.(*image.NYCbCrA)
+		if !ok {
+			t.Errorf("filter %d: got %T, want *image.NYCbCrA", filter, m)
+			continue
+		}
+		if got.AStride != w || !bytes.Equal(got.A, want) {
+			t.Errorf("filter %d: alpha values differ", filter)
+		}
+	}
+}
+
+func TestDecodeMetadata(t *testing.T) {
+	lossy, err := ioutil.ReadFile("../testdata/blue-purple-pink.lossy.webp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lossless, err := ioutil.ReadFile("../testdata/tux.lossless.webp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	vp8Chunk, vp8lChunk := string(lossy[12:]), string(lossless[12:])
+	vp8Chunk = vp8Chunk[:4] + vp8Chunk[8:]
+	vp8lChunk = vp8lChunk[:4] + vp8lChunk[8:]
+	const (
+		xmpMetadataBit  = 1 << 2
+		exifMetadataBit = 1 << 3
+		iccProfileBit   = 1 << 5
+	)
+	vp8x := "VP8X" + string([]byte{iccProfileBit | exifMetadataBit | xmpMetadataBit, 0, 0, 0, 15, 0, 0, 15, 0, 0})
+
+	testCases := []struct {
+		desc string
+		data string
+		want Metadata
+	}{
+		{"none", string(lossy), Metadata{}},
+		{
+			"lossy",
+			riffWEBP(vp8x, "ICCPprofile", vp8Chunk, "EXIFMM\x00*exif", "XMP <x:xmpmeta/>"),
+			Metadata{ICCProfile: []byte("profile"), EXIF: []byte("MM\x00*exif"), XMP: []byte("<x:xmpmeta/>")},
+		},
+		{
+			"lossless",
+			riffWEBP(vp8x, vp8lChunk, "XMP odd", "EXIF", "XMP second"),
+			Metadata{EXIF: []byte{}, XMP: []byte("odd")},
+		},
+	}
+	for _, tc := range testCases {
+		m, md, err := DecodeMetadata(strings.NewReader(tc.data))
+		if err != nil {
+			t.Errorf("%s: %v", tc.desc, err)
+			continue
+		}
+		if m == nil {
+			t.Errorf("%s: got nil image", tc.desc)
+		}
+		if !bytes.Equal(md.ICCProfile, tc.want.ICCProfile) || (md.ICCProfile == nil) != (tc.want.ICCProfile == nil) ||
+			!bytes.Equal(md.EXIF, tc.want.EXIF) || (md.EXIF == nil) != (tc.want.EXIF == nil) ||
+			!bytes.Equal(md.XMP, tc.want.XMP) || (md.XMP == nil) != (tc.want.XMP == nil) {
+			t.Errorf("%s: got %q, want %q", tc.desc, *md, tc.want)
+		}
+		// Decode ignores the metadata.
+		if _, err := Decode(strings.NewReader(tc.data)); err != nil {
+			t.Errorf("%s: Decode: %v", tc.desc, err)
+		}
+	}
+}
+
+func TestDecodeInto(t *testing.T) {
+	var dst image.Image
+	for _, filename := range []string{
+		"blue-purple-pink-large.normal-filter.lossy",
+		"blue-purple-pink.lossy",
+		"yellow_rose.lossy-with-alpha",
+		"yellow_rose.lossy",
+		"blue-purple-pink-large.lossless",
+		"tux.lossless",
+		"video-001.lossy",
+		"yellow_rose.lossy-with-alpha",
+	} {
+		data, err := ioutil.ReadFile("../testdata/" + filename + ".webp")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("%s: Decode: %v", filename, err)
+		}
+		prev := dst
+		dst, err = DecodeInto(prev, bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("%s: DecodeInto: %v", filename, err)
+		}
+		if reflect.TypeOf(dst) != reflect.TypeOf(want) {
+			t.Fatalf("%s: got %T, want %T", filename, dst, want)
+		}
+		b := want.Bounds()
+		if dst.Bounds() != b {
+			t.Fatalf("%s: got bounds %v, want %v", filename, dst.Bounds(), b)
+		}
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				if got, want := dst.At(x, y), want.At(x, y); got != want {
+					t.Fatalf("%s: at (%d, %d): got %v, want %v", filename, x, y, got, want)
+				}
+			}
+		}
+	}
+
+	// Decoding an image into a larger one of the same type re-uses its
+	// buffers, and returns it.
+	for _, tc := range []struct {
+		large, small string
+	}{
+		{"blue-purple-pink-large.normal-filter.lossy", "blue-purple-pink.lossy"},
+		{"blue-purple-pink-large.lossless", "blue-purple-pink.lossless"},
+	} {
+		data, err := ioutil.ReadFile("../testdata/" + tc.large + ".webp")
+		if err != nil {
+			t.Fatal(err)
+		}
+		m, err := Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("%s: %v", tc.large, err)
+		}
+		pix := func(m image.Image) *byte {
+			switch m := m.(type) {
+			case *image.YCbCr:
+				return &m.Y[0]
+			case *image.NRGBA:
+				return &m.Pix[0]
+			}
+			return nil
+		}
+		p := pix(m)
+		data, err = ioutil.ReadFile("../testdata/" + tc.small + ".webp")
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := DecodeInto(m, bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("%s: %v", tc.small, err)
+		}
+		if got != m || pix(got) != p {
+			t.Errorf("%s: the buffers were not re-used", tc.small)
+		}
 	}
 }
 
@@ -300,3 +784,189 @@ func BenchmarkDecodeVP8NoFilter(b *testing.B)     { benchmarkDecode(b, "no-filte
 func BenchmarkDecodeVP8SimpleFilter(b *testing.B) { benchmarkDecode(b, "simple-filter.lossy") }
 func BenchmarkDecodeVP8NormalFilter(b *testing.B) { benchmarkDecode(b, "normal-filter.lossy") }
 func BenchmarkDecodeVP8L(b *testing.B)            { benchmarkDecode(b, "lossless") }
+
+func TestDecodeWithOptions(t *testing.T) {
+	crops := []image.Rectangle{
+		image.Rect(0, 0, 1, 1),
+		image.Rect(0, 0, 17, 33),
+		image.Rect(20, 30, 45, 47),
+		image.Rect(33, 0, 1000, 15),
+		image.Rect(-5, 60, 70, 1000),
+	}
+	for _, filename := range []string{
+		"blue-purple-pink-large.no-filter.lossy",
+		"blue-purple-pink-large.normal-filter.lossy",
+		"blue-purple-pink-large.simple-filter.lossy",
+		"video-001.lossy",
+		"yellow_rose.lossy-with-alpha",
+		"tux.lossless",
+	} {
+		data, err := ioutil.ReadFile("../testdata/" + filename + ".webp")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("%s: Decode: %v", filename, err)
+		}
+		for _, crop := range crops {
+			got, err := DecodeWithOptions(bytes.NewReader(data), &DecodeOptions{Crop: crop})
+			if err != nil {
+				t.Fatalf("%s: crop %v: %v", filename, crop, err)
+			}
+			b := crop.Intersect(want.Bounds())
+			if got.Bounds() != b {
+				t.Fatalf("%s: crop %v: got bounds %v, want %v", filename, crop, got.Bounds(), b)
+			}
+			for y := b.Min.Y; y < b.Max.Y; y++ {
+				for x := b.Min.X; x < b.Max.X; x++ {
+					if g, w := got.At(x, y), want.At(x, y); g != w {
+						t.Fatalf("%s: crop %v: at (%d, %d): got %v, want %v", filename, crop, x, y, g, w)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestDecodeWithOptionsLimits(t *testing.T) {
+	for _, filename := range []string{
+		"blue-purple-pink.lossy",
+		"yellow_rose.lossy-with-alpha",
+		"tux.lossless",
+	} {
+		data, err := ioutil.ReadFile("../testdata/" + filename + ".webp")
+		if err != nil {
+			t.Fatal(err)
+		}
+		c, err := DecodeConfig(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("%s: DecodeConfig: %v", filename, err)
+		}
+		// The image's largest chunk is smaller than the RIFF data.
+		pixels, maxChunk := c.Width*c.Height, len(data)-12
+
+		for _, o := range []struct {
+			opts    DecodeOptions
+			wantErr bool
+		}{
+			{DecodeOptions{MaxPixels: pixels, MaxChunkSize: maxChunk}, false},
+			{DecodeOptions{MaxPixels: pixels - 1}, true},
+			{DecodeOptions{MaxChunkSize: 16}, true},
+		} {
+			m, err := DecodeWithOptions(bytes.NewReader(data), &o.opts)
+			if !o.wantErr {
+				if err != nil {
+					t.Errorf("%s: %+v: %v", filename, o.opts, err)
+				} else if m.Bounds().Dx() != c.Width || m.Bounds().Dy() != c.Height {
+					t.Errorf("%s: %+v: got bounds %v", filename, o.opts, m.Bounds())
+				}
+				continue
+			}
+			if err != ErrLimitExceeded {
+				t.Errorf("%s: %+v: got %v, want ErrLimitExceeded", filename, o.opts, err)
+			}
+		}
+	}
+}
+
+func TestDecodeWithOptionsWorkLimits(t *testing.T) {
+	// The encoder uses two partitions for frames with more than 4096
+	// macroblocks.
+	buf := &bytes.Buffer{}
+	if err := Encode(buf, image.NewGray(image.Rect(0, 0, 1056, 1056)), nil); err != nil {
+		t.Fatal(err)
+	}
+	twoPartitions := buf.Bytes()
+	readFile := func(filename string) []byte {
+		data, err := ioutil.ReadFile("../testdata/" + filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+
+	for _, tc := range []struct {
+		desc    string
+		data    []byte
+		opts    DecodeOptions
+		wantErr bool
+	}{
+		{"two partitions", twoPartitions, DecodeOptions{MaxPartitions: 2}, false},
+		{"two partitions", twoPartitions, DecodeOptions{MaxPartitions: 1}, true},
+		// The image's segments' filter levels are 11, 7, 6 and 4.
+		{"filter level", readFile("yellow_rose.lossy-with-alpha.webp"), DecodeOptions{MaxFilterLevel: 11}, false},
+		{"filter level", readFile("yellow_rose.lossy-with-alpha.webp"), DecodeOptions{MaxFilterLevel: 10}, true},
+		{"no filter", readFile("blue-purple-pink-large.no-filter.lossy.webp"), DecodeOptions{MaxFilterLevel: 1}, false},
+		{"lossless", readFile("tux.lossless.webp"), DecodeOptions{MaxPartitions: 1, MaxFilterLevel: 1}, false},
+	} {
+		_, err := DecodeWithOptions(bytes.NewReader(tc.data), &tc.opts)
+		if !tc.wantErr {
+			if err != nil {
+				t.Errorf("%s: %+v: %v", tc.desc, tc.opts, err)
+			}
+		} else if err != ErrLimitExceeded {
+			t.Errorf("%s: %+v: got %v, want ErrLimitExceeded", tc.desc, tc.opts, err)
+		}
+	}
+}
+
+func TestDecodeFeatures(t *testing.T) {
+	testCases := []struct {
+		name string
+		data string
+		want Features
+	}{{
+		"animated",
+		riffWEBP("VP8X\x3e\x00\x00\x00\x3f\x01\x00\x1f\x00\x00", "ANIM"+strings.Repeat("\x00", 6)),
+		Features{Width: 320, Height: 32, Extended: true, Alpha: true, Animation: true, ICCProfile: true, EXIF: true, XMP: true},
+	}, {
+		"extended lossless",
+		riffWEBP("VP8X\x08\x00\x00\x00\x03\x00\x00\x04\x00\x00", "VP8L\x2f\x03\xc0\x00\x00"),
+		Features{Width: 4, Height: 5, Extended: true, EXIF: true, Lossless: true},
+	}, {
+		"simple lossless with alpha",
+		riffWEBP("VP8L\x2f\x03\x40\x01\x10"),
+		Features{Width: 4, Height: 6, Alpha: true, Lossless: true},
+	}}
+	for _, filename := range []string{
+		"blue-purple-pink.lossy",
+		"yellow_rose.lossy-with-alpha",
+	} {
+		data, err := ioutil.ReadFile("../testdata/" + filename + ".webp")
+		if err != nil {
+			t.Fatal(err)
+		}
+		c, err := DecodeConfig(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("%s: DecodeConfig: %v", filename, err)
+		}
+		withAlpha := c.ColorModel == color.NYCbCrAModel
+		testCases = append(testCases, struct {
+			name string
+			data string
+			want Features
+		}{filename, string(data), Features{Width: c.Width, Height: c.Height, Extended: withAlpha, Alpha: withAlpha}})
+	}
+
+	for _, tc := range testCases {
+		got, err := DecodeFeatures(strings.NewReader(tc.data))
+		if err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("%s: got %+v, want %+v", tc.name, got, tc.want)
+		}
+	}
+
+	for _, data := range []string{
+		riffWEBP("VP8L\x2f\x03"),
+		riffWEBP("VP8L\x2f\x03\x40\x01\x30"),
+		riffWEBP("VP8X\x10\x00\x00\x00\x3f\x01\x00\x1f\x00\x00"),
+	} {
+		if _, err := DecodeFeatures(strings.NewReader(data)); !errors.Is(err, ErrInvalidFormat) {
+			t.Errorf("%q: got %v, want ErrInvalidFormat", data, err)
+		}
+	}
+}
@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// Package webp implements a decoder for WEBP images.
+// Package webp implements a decoder and a lossy encoder for WEBP images.
 //
 // WEBP is defined at:
 // https://developers.google.com/speed/webp/docs/riff_container
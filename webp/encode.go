@@ -0,0 +1,388 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webp
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"io"
+
+	"golang.org/x/image/riff"
+	"golang.org/x/image/vp8"
+)
+
+// DefaultQuality is the default quality encoding parameter.
+const DefaultQuality = 75
+
+// Options are the encoding parameters. Quality ranges from 1 to 100 inclusive,
+// higher is better. A zero Quality means DefaultQuality.
+type Options struct {
+	Quality int
+	// Metadata, if non-nil, holds the ICC profile, Exif and XMP metadata to
+	// write, such as that returned by DecodeMetadata for the image being
+	// transcoded. Its nil fields are not written.
+	Metadata *Metadata
+}
+
+// Encode writes the image m to w in the lossy WEBP format with the given
+// options. Default parameters are used if a nil *Options is passed.
+//
+// The image is encoded as Y'CbCr with 4:2:0 chroma subsampling, converting it
+// if it is not already so. If m is not opaque, its alpha values are written
+// uncompressed, and its colors are written without premultiplying them, as
+// Decode returns them in an *image.NYCbCrA.
+//
+// The image must be between 1 and 16383 pixels wide and high.
+func Encode(w io.Writer, m image.Image, o *Options) error {
+	b := m.Bounds()
+	if b.Dx() <= 0 || b.Dy() <= 0 || b.Dx() > 0x3fff || b.Dy() > 0x3fff {
+		return errors.New("webp: invalid image size for encoding")
+	}
+	frame, alpha, err := encodeFrame(m, o)
+	if err != nil {
+		return err
+	}
+	var md *Metadata
+	if o != nil {
+		md = o.Metadata
+	}
+
+	buf := &bytes.Buffer{}
+	buf.Grow(12 + 8 + frame.Len() + 1)
+	buf.Write(fccRIFF[:])
+	buf.Write([]byte{0, 0, 0, 0}) // The RIFF chunk length is filled in below.
+	buf.Write(fccWEBP[:])
+	if flags := md.vp8xFlags(); alpha != nil || flags != 0 {
+		const alphaBit = 1 << 4
+		vp8x := [10]byte{0: flags}
+		if alpha != nil {
+			vp8x[0] |= alphaBit
+		}
+		putUint24(vp8x[4:], uint32(b.Dx()-1))
+		putUint24(vp8x[7:], uint32(b.Dy()-1))
+		writeChunk(buf, fccVP8X, vp8x[:])
+	}
+	md.writeICCP(buf)
+	writeFrameChunks(buf, frame, alpha)
+	md.writeEXIFAndXMP(buf)
+	return writeRIFF(w, buf)
+}
+
+// Disposal methods for the frames of an Animation.
+const (
+	// DisposalNone leaves the canvas as it is, before the next frame is
+	// rendered.
+	DisposalNone = 0
+	// DisposalBackground clears the frame's rectangle of the canvas to the
+	// background color, before the next frame is rendered.
+	DisposalBackground = 1
+)
+
+// Animation is an animated WEBP image, as written by EncodeAll.
+//
+// Each frame is drawn at its bounds on the canvas, whose top-left is (0, 0),
+// and alpha-blended with what the canvas already holds.
+type Animation struct {
+	// Image holds the successive frames. Each frame must be between 1 and
+	// 16383 pixels wide and high, and within the canvas.
+	Image []image.Image
+	// Delay holds the successive display durations, in milliseconds, of the
+	// frames. It must have the same length as Image.
+	Delay []int
+	// Disposal holds the successive disposal methods, such as DisposalNone.
+	// If it is nil, every frame's disposal method is DisposalNone. Otherwise,
+	// it must have the same length as Image.
+	Disposal []byte
+	// LoopCount is the number of times that the animation is played, with 0
+	// meaning forever. This is not the same as image/gif's LoopCount, for
+	// which 0 means forever, -1 means once, and n means n+1 times.
+	LoopCount int
+	// BackgroundColor is the canvas's background color. Viewers may ignore
+	// it. A nil BackgroundColor means transparent.
+	BackgroundColor color.Color
+	// Config's Width and Height are the canvas size. If either is zero, the
+	// canvas is the smallest one that holds every frame. Config's ColorModel
+	// is ignored.
+	Config image.Config
+}
+
+// EncodeAll writes the frames of a to w in the animated WEBP format, encoding
+// each frame as Encode does with the given options, and writing the options'
+// metadata, if any, once for the whole animation. Default parameters are used
+// if a nil *Options is passed.
+//
+// The format only allows frames to start at even coordinates, so a frame that
+// starts at an odd one is extended up or to the left by one transparent row
+// or column. With DisposalBackground, that row or column is also cleared.
+func EncodeAll(w io.Writer, a *Animation, o *Options) error {
+	if len(a.Image) == 0 {
+		return errors.New("webp: no frames to encode")
+	}
+	if len(a.Delay) != len(a.Image) {
+		return errors.New("webp: mismatched image and delay lengths")
+	}
+	if a.Disposal != nil && len(a.Disposal) != len(a.Image) {
+		return errors.New("webp: mismatched image and disposal lengths")
+	}
+	if a.LoopCount < 0 || a.LoopCount > 0xffff {
+		return errors.New("webp: invalid loop count")
+	}
+	canvas := image.Rect(0, 0, a.Config.Width, a.Config.Height)
+	if a.Config.Width <= 0 || a.Config.Height <= 0 {
+		canvas = image.Rectangle{}
+		for _, m := range a.Image {
+			canvas = canvas.Union(image.Rectangle{Max: m.Bounds().Max})
+		}
+	}
+	if canvas.Dx() > 1<<24 || canvas.Dy() > 1<<24 || uint64(canvas.Dx())*uint64(canvas.Dy()) > 1<<32-1 {
+		return errors.New("webp: invalid canvas size for encoding")
+	}
+
+	buf := &bytes.Buffer{}
+	buf.Write(fccRIFF[:])
+	buf.Write([]byte{0, 0, 0, 0}) // The RIFF chunk length is filled in below.
+	buf.Write(fccWEBP[:])
+	// The VP8X chunk's flags are filled in below, once it is known whether
+	// any frame has alpha values.
+	const (
+		animationBit = 1 << 1
+		alphaBit     = 1 << 4
+	)
+	var md *Metadata
+	if o != nil {
+		md = o.Metadata
+	}
+	vp8x := [10]byte{0: animationBit | md.vp8xFlags()}
+	putUint24(vp8x[4:], uint32(canvas.Dx()-1))
+	putUint24(vp8x[7:], uint32(canvas.Dy()-1))
+	writeChunk(buf, fccVP8X, vp8x[:])
+	flagsOffset := buf.Len() - len(vp8x)
+	md.writeICCP(buf)
+
+	var anim [6]byte
+	if a.BackgroundColor != nil {
+		c := color.NRGBAModel.Convert(a.BackgroundColor).(color.NRGBA)
+		anim[0], anim[1], anim[2], anim[3] = c.B, c.G, c.R, c.A
+	}
+	anim[4], anim[5] = uint8(a.LoopCount), uint8(a.LoopCount>>8)
+	writeChunk(buf, fccANIM, anim[:])
+
+	frameBuf := &bytes.Buffer{}
+	for i, m := range a.Image {
+		b := m.Bounds()
+		if b.Dx() <= 0 || b.Dy() <= 0 || b.Dx() > 0x3fff || b.Dy() > 0x3fff {
+			return errors.New("webp: invalid image size for encoding")
+		}
+		if !b.In(canvas) {
+			return errors.New("webp: frame is outside the canvas")
+		}
+		if a.Delay[i] < 0 || a.Delay[i] > 0xffffff {
+			return errors.New("webp: invalid delay")
+		}
+		disposal := byte(DisposalNone)
+		if a.Disposal != nil {
+			disposal = a.Disposal[i]
+			if disposal != DisposalNone && disposal != DisposalBackground {
+				return errors.New("webp: invalid disposal method")
+			}
+		}
+		if b.Min.X&1 != 0 || b.Min.Y&1 != 0 {
+			r := image.Rect(b.Min.X&^1, b.Min.Y&^1, b.Max.X, b.Max.Y)
+			if r.Dx() > 0x3fff || r.Dy() > 0x3fff {
+				return errors.New("webp: invalid image size for encoding")
+			}
+			dst := image.NewNRGBA(r)
+			draw.Draw(dst, b, m, b.Min, draw.Src)
+			m, b = dst, r
+		}
+		frame, alpha, err := encodeFrame(m, o)
+		if err != nil {
+			return err
+		}
+		if alpha != nil {
+			buf.Bytes()[flagsOffset] |= alphaBit
+		}
+
+		// The frame's header is its position, in units of two pixels, its
+		// size, its duration and its flags, whose blending bit is zero, for
+		// alpha-blending.
+		frameBuf.Reset()
+		var hdr [16]byte
+		putUint24(hdr[0:], uint32(b.Min.X/2))
+		putUint24(hdr[3:], uint32(b.Min.Y/2))
+		putUint24(hdr[6:], uint32(b.Dx()-1))
+		putUint24(hdr[9:], uint32(b.Dy()-1))
+		putUint24(hdr[12:], uint32(a.Delay[i]))
+		hdr[15] = disposal
+		frameBuf.Write(hdr[:])
+		writeFrameChunks(frameBuf, frame, alpha)
+		writeChunk(buf, fccANMF, frameBuf.Bytes())
+	}
+	md.writeEXIFAndXMP(buf)
+	return writeRIFF(w, buf)
+}
+
+// encodeFrame encodes m, which has a valid size, as a VP8 frame with the
+// given options, and returns that frame and m's alpha values, as returned by
+// toYCbCr.
+func encodeFrame(m image.Image, o *Options) (*bytes.Buffer, []byte, error) {
+	quality := DefaultQuality
+	if o != nil && o.Quality != 0 {
+		quality = o.Quality
+		if quality < 1 {
+			quality = 1
+		} else if quality > 100 {
+			quality = 100
+		}
+	}
+	// Map the quality linearly to VP8's quantizer index, which ranges from 0
+	// to 127 inclusive, lower is better.
+	quantIndex := (100 - quality) * 127 / 99
+
+	ycbcr, alpha := toYCbCr(m)
+	frame := &bytes.Buffer{}
+	if err := vp8.EncodeFrame(frame, ycbcr, quantIndex); err != nil {
+		return nil, nil, err
+	}
+	return frame, alpha, nil
+}
+
+// writeFrameChunks writes the ALPH chunk, if alpha is non-nil, and the VP8
+// chunk of an encoded frame.
+func writeFrameChunks(buf *bytes.Buffer, frame *bytes.Buffer, alpha []byte) {
+	if alpha != nil {
+		// The ALPH chunk's header byte is zero: no pre-processing, no
+		// filtering and no compression.
+		writeChunk(buf, fccALPH, append([]byte{0}, alpha...))
+	}
+	writeChunk(buf, fccVP8, frame.Bytes())
+}
+
+// writeRIFF fills in the RIFF chunk length of buf, which holds a whole WEBP
+// file, and writes it to w.
+func writeRIFF(w io.Writer, buf *bytes.Buffer) error {
+	data := buf.Bytes()
+	putUint32(data[4:8], uint32(len(data)-8))
+	_, err := w.Write(data)
+	return err
+}
+
+var fccRIFF = riff.FourCC{'R', 'I', 'F', 'F'}
+
+// writeChunk writes a RIFF chunk, padded to an even length.
+func writeChunk(buf *bytes.Buffer, id riff.FourCC, data []byte) {
+	var hdr [8]byte
+	copy(hdr[:4], id[:])
+	putUint32(hdr[4:], uint32(len(data)))
+	buf.Write(hdr[:])
+	buf.Write(data)
+	if len(data)&1 != 0 {
+		buf.WriteByte(0)
+	}
+}
+
+// putUint24 writes the 24-bit little-endian value u to b.
+func putUint24(b []byte, u uint32) {
+	b[0] = uint8(u)
+	b[1] = uint8(u >> 8)
+	b[2] = uint8(u >> 16)
+}
+
+// putUint32 writes the 32-bit little-endian value u to b.
+func putUint32(b []byte, u uint32) {
+	b[0] = uint8(u)
+	b[1] = uint8(u >> 8)
+	b[2] = uint8(u >> 16)
+	b[3] = uint8(u >> 24)
+}
+
+// toYCbCr returns m as a Y'CbCr image with 4:2:0 chroma subsampling, and its
+// non-premultiplied alpha values, one byte per pixel in row-major order, or
+// nil if m is opaque. Unless m already is such an image, the returned image's
+// origin is at (0, 0), and each chroma sample is the average of those of the
+// up to 2x2 pixels that it covers.
+func toYCbCr(m image.Image) (*image.YCbCr, []byte) {
+	b := m.Bounds()
+	switch m := m.(type) {
+	case *image.YCbCr:
+		if m.SubsampleRatio == image.YCbCrSubsampleRatio420 {
+			return m, nil
+		}
+	case *image.NYCbCrA:
+		if m.SubsampleRatio == image.YCbCrSubsampleRatio420 {
+			alpha := make([]byte, 0, b.Dx()*b.Dy())
+			opaque := true
+			for y := b.Min.Y; y < b.Max.Y; y++ {
+				i := m.AOffset(b.Min.X, y)
+				row := m.A[i : i+b.Dx()]
+				for _, a := range row {
+					opaque = opaque && a == 0xff
+				}
+				alpha = append(alpha, row...)
+			}
+			if opaque {
+				alpha = nil
+			}
+			return &m.YCbCr, alpha
+		}
+	}
+
+	dst := image.NewYCbCr(image.Rectangle{Max: b.Size()}, image.YCbCrSubsampleRatio420)
+	alpha := make([]byte, b.Dx()*b.Dy())
+	opaque := true
+	cbSum := make([]uint32, len(dst.Cb))
+	crSum := make([]uint32, len(dst.Cr))
+	count := make([]uint8, len(dst.Cb))
+	nrgba, _ := m.(*image.NRGBA)
+	rgba64, _ := m.(image.RGBA64Image)
+	for y, ai := b.Min.Y, 0; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x, ai = x+1, ai+1 {
+			var r, g, bb, a uint32
+			if nrgba != nil {
+				// Use the colors of translucent pixels as they are, without
+				// the loss of precision of premultiplying them.
+				i := nrgba.PixOffset(x, y)
+				s := nrgba.Pix[i : i+4 : i+4]
+				r, g, bb, a = uint32(s[0])*0x101, uint32(s[1])*0x101, uint32(s[2])*0x101, uint32(s[3])*0x101
+				if a != 0xffff {
+					opaque = false
+				}
+			} else if rgba64 != nil {
+				c := rgba64.RGBA64At(x, y)
+				r, g, bb, a = uint32(c.R), uint32(c.G), uint32(c.B), uint32(c.A)
+			} else {
+				r, g, bb, a = m.At(x, y).RGBA()
+			}
+			if nrgba == nil && a != 0xffff {
+				opaque = false
+				if a != 0 {
+					r = r * 0xffff / a
+					g = g * 0xffff / a
+					bb = bb * 0xffff / a
+				}
+			}
+			alpha[ai] = uint8(a >> 8)
+			yy, cb, cr := color.RGBToYCbCr(uint8(r>>8), uint8(g>>8), uint8(bb>>8))
+			dst.Y[dst.YOffset(x-b.Min.X, y-b.Min.Y)] = yy
+			ci := dst.COffset(x-b.Min.X, y-b.Min.Y)
+			cbSum[ci] += uint32(cb)
+			crSum[ci] += uint32(cr)
+			count[ci]++
+		}
+	}
+	for i, n := range count {
+		if n != 0 {
+			dst.Cb[i] = uint8((cbSum[i] + uint32(n)/2) / uint32(n))
+			dst.Cr[i] = uint8((crSum[i] + uint32(n)/2) / uint32(n))
+		}
+	}
+	if opaque {
+		alpha = nil
+	}
+	return dst, alpha
+}
@@ -0,0 +1,394 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webp
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/riff"
+)
+
+// rgbPSNR returns the peak signal-to-noise ratio, in decibels, of the
+// non-premultiplied 8-bit red, green and blue values of m1 compared to those
+// of m0. The two images have the same size, but may have different origins.
+func rgbPSNR(m0, m1 image.Image) float64 {
+	b0, b1 := m0.Bounds(), m1.Bounds()
+	sse, n := 0.0, 0
+	for y := 0; y < b0.Dy(); y++ {
+		for x := 0; x < b0.Dx(); x++ {
+			c0 := color.NRGBAModel.Convert(m0.At(b0.Min.X+x, b0.Min.Y+y)).(color.NRGBA)
+			c1 := color.NRGBAModel.Convert(m1.At(b1.Min.X+x, b1.Min.Y+y)).(color.NRGBA)
+			for _, d := range [3]float64{
+				float64(c0.R) - float64(c1.R),
+				float64(c0.G) - float64(c1.G),
+				float64(c0.B) - float64(c1.B),
+			} {
+				sse += d * d
+			}
+			n += 3
+		}
+	}
+	if sse == 0 {
+		return math.Inf(1)
+	}
+	return 10 * math.Log10(255*255*float64(n)/sse)
+}
+
+func TestEncode(t *testing.T) {
+	f, err := os.Open("../testdata/blue-purple-pink.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	src, err := png.Decode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prevSize, prevPSNR := 0, 0.0
+	for _, o := range []*Options{{Quality: 1}, {Quality: 50}, nil, {Quality: 100}} {
+		quality := DefaultQuality
+		if o != nil {
+			quality = o.Quality
+		}
+		buf := &bytes.Buffer{}
+		if err := Encode(buf, src, o); err != nil {
+			t.Fatalf("quality %d: %v", quality, err)
+		}
+		c, err := DecodeConfig(bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			t.Fatalf("quality %d: DecodeConfig: %v", quality, err)
+		}
+		if c.ColorModel != color.YCbCrModel || c.Width != src.Bounds().Dx() || c.Height != src.Bounds().Dy() {
+			t.Errorf("quality %d: DecodeConfig: got %v, %d, %d", quality, c.ColorModel, c.Width, c.Height)
+		}
+		m, err := Decode(bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			t.Fatalf("quality %d: Decode: %v", quality, err)
+		}
+		if m.Bounds() != src.Bounds() {
+			t.Fatalf("quality %d: bounds: got %v, want %v", quality, m.Bounds(), src.Bounds())
+		}
+		p := rgbPSNR(src, m)
+		if buf.Len() <= prevSize || p <= prevPSNR {
+			t.Errorf("quality %d: got %d bytes with PSNR %.2f, lower quality gave %d bytes with PSNR %.2f",
+				quality, buf.Len(), p, prevSize, prevPSNR)
+		}
+		prevSize, prevPSNR = buf.Len(), p
+	}
+	if prevPSNR < 35 {
+		t.Errorf("quality 100: got PSNR %.2f, want at least 35", prevPSNR)
+	}
+
+	// A zero Quality, such as when only Options.Metadata is set, means
+	// DefaultQuality.
+	want, got := &bytes.Buffer{}, &bytes.Buffer{}
+	if err := Encode(want, src, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := Encode(got, src, &Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got.Bytes(), want.Bytes()) {
+		t.Errorf("quality 0: output differs from the default quality's")
+	}
+}
+
+func TestEncodeAlpha(t *testing.T) {
+	// The image's origin is not (0, 0), and it is not a whole number of
+	// macroblocks or chroma samples.
+	src := image.NewNRGBA(image.Rect(3, 5, 40, 26))
+	for y := src.Rect.Min.Y; y < src.Rect.Max.Y; y++ {
+		for x := src.Rect.Min.X; x < src.Rect.Max.X; x++ {
+			src.SetNRGBA(x, y, color.NRGBA{uint8(2 * x), 0x80, uint8(3 * y), uint8(7*x + 11*y)})
+		}
+	}
+	buf := &bytes.Buffer{}
+	if err := Encode(buf, src, &Options{Quality: 100}); err != nil {
+		t.Fatal(err)
+	}
+	c, err := DecodeConfig(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("DecodeConfig: %v", err)
+	}
+	if c.ColorModel != color.NYCbCrAModel {
+		t.Errorf("DecodeConfig: got color model %v, want NYCbCrAModel", c.ColorModel)
+	}
+	m, err := Decode(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, ok := m.(*image.NYCbCrA)
+	if !ok {
+		t.Fatalf("got %T, want *image.NYCbCrA", m)
+	}
+	if got.Rect.Size() != src.Rect.Size() {
+		t.Fatalf("size: got %v, want %v", got.Rect.Size(), src.Rect.Size())
+	}
+	for y := 0; y < got.Rect.Dy(); y++ {
+		for x := 0; x < got.Rect.Dx(); x++ {
+			g := got.NYCbCrAAt(x, y).A
+			w := src.NRGBAAt(src.Rect.Min.X+x, src.Rect.Min.Y+y).A
+			if g != w {
+				t.Fatalf("alpha at (%d, %d): got %#02x, want %#02x", x, y, g, w)
+			}
+		}
+	}
+	// Compare the colors without their alpha, which would lose precision.
+	opaque := image.NewNRGBA(src.Rect)
+	copy(opaque.Pix, src.Pix)
+	for i := 3; i < len(opaque.Pix); i += 4 {
+		opaque.Pix[i] = 0xff
+	}
+	if p := rgbPSNR(opaque, &got.YCbCr); p < 35 {
+		t.Errorf("got PSNR %.2f, want at least 35", p)
+	}
+}
+
+func TestEncodeYCbCr(t *testing.T) {
+	data, err := os.ReadFile("../testdata/video-001.lossy.webp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	if err := Encode(buf, src, &Options{Quality: 90}); err != nil {
+		t.Fatal(err)
+	}
+	m, err := Decode(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := m.(*image.YCbCr); !ok {
+		t.Fatalf("got %T, want *image.YCbCr", m)
+	}
+	if p := rgbPSNR(src, m); p < 35 {
+		t.Errorf("got PSNR %.2f, want at least 35", p)
+	}
+}
+
+func TestEncodeInvalidSize(t *testing.T) {
+	for _, r := range []image.Rectangle{
+		image.Rect(0, 0, 0, 0),
+		image.Rect(0, 0, 0x4000, 1),
+	} {
+		if err := Encode(&bytes.Buffer{}, image.NewGray(r), nil); err == nil {
+			t.Errorf("%v: got nil error, want non-nil", r)
+		}
+	}
+}
+
+func TestEncodeAll(t *testing.T) {
+	frame := func(r image.Rectangle, alpha uint8) *image.NRGBA {
+		m := image.NewNRGBA(r)
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			for x := r.Min.X; x < r.Max.X; x++ {
+				m.SetNRGBA(x, y, color.NRGBA{uint8(4 * x), uint8(6 * y), 0x40, alpha})
+			}
+		}
+		return m
+	}
+	a := &Animation{
+		Image: []image.Image{
+			frame(image.Rect(0, 0, 40, 30), 0xff),
+			frame(image.Rect(3, 5, 20, 17), 0x80),
+			frame(image.Rect(10, 10, 40, 20), 0xff),
+		},
+		Delay:           []int{100, 200, 0x123456},
+		Disposal:        []byte{DisposalNone, DisposalBackground, DisposalNone},
+		LoopCount:       3,
+		BackgroundColor: color.NRGBA{0x11, 0x22, 0x33, 0x44},
+	}
+	buf := &bytes.Buffer{}
+	if err := EncodeAll(buf, a, &Options{Quality: 100}); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := DecodeConfig(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("DecodeConfig: %v", err)
+	}
+	if c.Width != 40 || c.Height != 30 {
+		t.Errorf("DecodeConfig: got %dx%d, want 40x30", c.Width, c.Height)
+	}
+
+	formType, r, err := riff.NewReader(bytes.NewReader(buf.Bytes()))
+	if err != nil || formType != fccWEBP {
+		t.Fatalf("riff.NewReader: got %q, %v", formType, err)
+	}
+	var chunks [][]byte
+	for {
+		id, _, data, err := r.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("Next: %v", err)
+		}
+		b, err := io.ReadAll(data)
+		if err != nil {
+			t.Fatalf("ReadAll: %v", err)
+		}
+		chunks = append(chunks, append(id[:], b...))
+	}
+	if len(chunks) != 5 {
+		t.Fatalf("got %d chunks, want 5", len(chunks))
+	}
+	if got, want := string(chunks[0]), "VP8X\x12\x00\x00\x00\x27\x00\x00\x1d\x00\x00"; got != want {
+		t.Errorf("VP8X: got %q, want %q", got, want)
+	}
+	if got, want := string(chunks[1]), "ANIM\x33\x22\x11\x44\x03\x00"; got != want {
+		t.Errorf("ANIM: got %q, want %q", got, want)
+	}
+	for i, want := range []struct {
+		hdr string
+		r   image.Rectangle
+	}{
+		{"\x00\x00\x00\x00\x00\x00\x27\x00\x00\x1d\x00\x00\x64\x00\x00\x00", image.Rect(0, 0, 40, 30)},
+		{"\x01\x00\x00\x02\x00\x00\x11\x00\x00\x0c\x00\x00\xc8\x00\x00\x01", image.Rect(2, 4, 20, 17)},
+		{"\x05\x00\x00\x05\x00\x00\x1d\x00\x00\x09\x00\x00\x56\x34\x12\x00", image.Rect(10, 10, 40, 20)},
+	} {
+		anmf := chunks[2+i]
+		if got := string(anmf[:4]); got != "ANMF" {
+			t.Fatalf("frame #%d: got chunk %q, want ANMF", i, got)
+		}
+		if got := string(anmf[4:20]); got != want.hdr {
+			t.Errorf("frame #%d: header: got %q, want %q", i, got, want.hdr)
+		}
+		// The frame's data are the chunks of a still image.
+		still := "RIFF\x00\x00\x00\x00WEBP" + string(anmf[20:])
+		if string(anmf[20:24]) == "ALPH" {
+			vp8x := string([]byte{
+				'V', 'P', '8', 'X', 10, 0, 0, 0, 0x10, 0, 0, 0,
+				uint8(want.r.Dx() - 1), 0, 0, uint8(want.r.Dy() - 1), 0, 0,
+			})
+			still = "RIFF\x00\x00\x00\x00WEBP" + vp8x + string(anmf[20:])
+		}
+		m, err := Decode(strings.NewReader(still[:4] + string([]byte{uint8(len(still) - 8), uint8((len(still) - 8) >> 8), 0, 0}) + still[8:]))
+		if err != nil {
+			t.Fatalf("frame #%d: Decode: %v", i, err)
+		}
+		if m.Bounds().Size() != want.r.Size() {
+			t.Fatalf("frame #%d: got size %v, want %v", i, m.Bounds().Size(), want.r.Size())
+		}
+		// Compare the colors of the frame, without any padding to even
+		// coordinates, and without their alpha.
+		b := a.Image[i].Bounds()
+		src := image.NewNRGBA(b)
+		draw.Draw(src, b, a.Image[i], b.Min, draw.Src)
+		for i := 3; i < len(src.Pix); i += 4 {
+			src.Pix[i] = 0xff
+		}
+		var opaque image.Image = m
+		if m, ok := m.(*image.NYCbCrA); ok {
+			opaque = &m.YCbCr
+		}
+		opaque = opaque.(interface {
+			SubImage(image.Rectangle) image.Image
+		}).SubImage(b.Sub(want.r.Min))
+		if p := rgbPSNR(src, opaque); p < 30 {
+			t.Errorf("frame #%d: got PSNR %.2f, want at least 30", i, p)
+		}
+	}
+}
+
+func TestEncodeAllErrors(t *testing.T) {
+	m := image.NewGray(image.Rect(0, 0, 8, 8))
+	for _, tc := range []struct {
+		name string
+		a    *Animation
+	}{
+		{"no frames", &Animation{}},
+		{"mismatched delays", &Animation{Image: []image.Image{m}, Delay: []int{1, 2}}},
+		{"mismatched disposals", &Animation{Image: []image.Image{m}, Delay: []int{1}, Disposal: []byte{}}},
+		{"invalid disposal", &Animation{Image: []image.Image{m}, Delay: []int{1}, Disposal: []byte{2}}},
+		{"invalid loop count", &Animation{Image: []image.Image{m}, Delay: []int{1}, LoopCount: 0x10000}},
+		{"invalid delay", &Animation{Image: []image.Image{m}, Delay: []int{-1}}},
+		{"outside the canvas", &Animation{Image: []image.Image{m}, Delay: []int{1}, Config: image.Config{Width: 4, Height: 4}}},
+	} {
+		if err := EncodeAll(&bytes.Buffer{}, tc.a, nil); err == nil {
+			t.Errorf("%s: got nil error, want non-nil", tc.name)
+		}
+	}
+}
+
+func TestEncodeMetadata(t *testing.T) {
+	md := &Metadata{
+		ICCProfile: []byte("profile"),
+		EXIF:       []byte("MM\x00*exif"),
+		XMP:        []byte("<x:xmpmeta/>"),
+	}
+	opaque := image.NewGray(image.Rect(0, 0, 20, 10))
+	translucent := image.NewNRGBA(image.Rect(0, 0, 20, 10))
+	for _, tc := range []struct {
+		desc      string
+		m         image.Image
+		md        *Metadata
+		wantIDs   string
+		wantAlpha bool
+	}{
+		{"opaque", opaque, md, "VP8X,ICCP,VP8 ,EXIF,XMP ", false},
+		{"translucent", translucent, md, "VP8X,ICCP,ALPH,VP8 ,EXIF,XMP ", true},
+		{"EXIF only", opaque, &Metadata{EXIF: []byte{}}, "VP8X,VP8 ,EXIF", false},
+		{"empty", opaque, &Metadata{}, "VP8 ", false},
+	} {
+		buf := &bytes.Buffer{}
+		if err := Encode(buf, tc.m, &Options{Quality: 90, Metadata: tc.md}); err != nil {
+			t.Errorf("%s: Encode: %v", tc.desc, err)
+			continue
+		}
+		if got := chunkIDs(t, buf.Bytes()); got != tc.wantIDs {
+			t.Errorf("%s: chunks: got %q, want %q", tc.desc, got, tc.wantIDs)
+		}
+		_, gotMD, err := DecodeMetadata(bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			t.Errorf("%s: DecodeMetadata: %v", tc.desc, err)
+			continue
+		}
+		if !reflect.DeepEqual(gotMD, tc.md) {
+			t.Errorf("%s: metadata: got %q, want %q", tc.desc, *gotMD, *tc.md)
+		}
+		f, err := DecodeFeatures(bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			t.Errorf("%s: DecodeFeatures: %v", tc.desc, err)
+			continue
+		}
+		if f.ICCProfile != (tc.md.ICCProfile != nil) || f.EXIF != (tc.md.EXIF != nil) ||
+			f.XMP != (tc.md.XMP != nil) || f.Alpha != tc.wantAlpha {
+			t.Errorf("%s: got features %+v", tc.desc, f)
+		}
+	}
+
+	a := &Animation{
+		Image: []image.Image{opaque, translucent},
+		Delay: []int{100, 100},
+	}
+	buf := &bytes.Buffer{}
+	if err := EncodeAll(buf, a, &Options{Quality: 90, Metadata: md}); err != nil {
+		t.Fatalf("EncodeAll: %v", err)
+	}
+	if got, want := chunkIDs(t, buf.Bytes()), "VP8X,ICCP,ANIM,ANMF,ANMF,EXIF,XMP "; got != want {
+		t.Errorf("EncodeAll: chunks: got %q, want %q", got, want)
+	}
+	f, err := DecodeFeatures(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("EncodeAll: DecodeFeatures: %v", err)
+	}
+	if !f.Animation || !f.ICCProfile || !f.EXIF || !f.XMP || !f.Alpha {
+		t.Errorf("EncodeAll: got features %+v", f)
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webp
+
+import (
+	"bytes"
+	"io"
+
+	"golang.org/x/image/riff"
+	"golang.org/x/image/vp8"
+	"golang.org/x/image/vp8l"
+)
+
+// The VP8X chunk's flags for the metadata chunks.
+const (
+	xmpMetadataBit  = 1 << 2
+	exifMetadataBit = 1 << 3
+	iccProfileBit   = 1 << 5
+)
+
+// vp8xFlags returns the VP8X chunk's flags for md's metadata chunks. md may
+// be nil.
+func (md *Metadata) vp8xFlags() byte {
+	if md == nil {
+		return 0
+	}
+	flags := byte(0)
+	if md.ICCProfile != nil {
+		flags |= iccProfileBit
+	}
+	if md.EXIF != nil {
+		flags |= exifMetadataBit
+	}
+	if md.XMP != nil {
+		flags |= xmpMetadataBit
+	}
+	return flags
+}
+
+// writeICCP writes md's ICCP chunk, if any, which must follow the VP8X chunk
+// and precede the image data. md may be nil.
+func (md *Metadata) writeICCP(buf *bytes.Buffer) {
+	if md != nil && md.ICCProfile != nil {
+		writeChunk(buf, fccICCP, md.ICCProfile)
+	}
+}
+
+// writeEXIFAndXMP writes md's EXIF and XMP chunks, if any, which follow the
+// image data. md may be nil.
+func (md *Metadata) writeEXIFAndXMP(buf *bytes.Buffer) {
+	if md == nil {
+		return
+	}
+	if md.EXIF != nil {
+		writeChunk(buf, fccEXIF, md.EXIF)
+	}
+	if md.XMP != nil {
+		writeChunk(buf, fccXMP, md.XMP)
+	}
+}
+
+// ReplaceMetadata writes to w the WEBP image read from r, with its ICCP, EXIF
+// and XMP chunks replaced by those for md's non-nil fields. A nil md removes
+// all of them. The image data and any other chunks are copied as they are,
+// without decoding them.
+//
+// Together with DecodeMetadata, it copies the metadata of one WEBP image to
+// another, such as a transcoded version of it, so that its ICC profile is
+// not lost. An image in the simple WEBP format is converted to the extended
+// format, if md has any metadata to add.
+func ReplaceMetadata(w io.Writer, r io.Reader, md *Metadata) error {
+	er := &errReader{r: r}
+	buf, err := replaceMetadata(er, md)
+	if err = er.wrap(err); err != nil {
+		return err
+	}
+	return writeRIFF(w, buf)
+}
+
+func replaceMetadata(r io.Reader, md *Metadata) (*bytes.Buffer, error) {
+	formType, riffReader, err := riff.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	if formType != fccWEBP {
+		return nil, ErrInvalidFormat
+	}
+
+	// Read the chunks, other than the metadata chunks, which are replaced,
+	// and the VP8X chunk, whose flags are updated.
+	type chunk struct {
+		id   riff.FourCC
+		data []byte
+	}
+	var (
+		chunks []chunk
+		vp8x   []byte
+	)
+	for {
+		chunkID, chunkLen, chunkData, err := riffReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch chunkID {
+		case fccICCP, fccEXIF, fccXMP:
+			continue
+		}
+		data, err := io.ReadAll(chunkData)
+		if err != nil {
+			return nil, err
+		}
+		if uint32(len(data)) != chunkLen {
+			return nil, ErrInvalidFormat
+		}
+		if chunkID == fccVP8X {
+			if vp8x != nil || chunkLen != 10 {
+				return nil, ErrInvalidFormat
+			}
+			vp8x = data
+			continue
+		}
+		chunks = append(chunks, chunk{chunkID, data})
+	}
+	if len(chunks) == 0 {
+		return nil, ErrInvalidFormat
+	}
+
+	flags := md.vp8xFlags()
+	if vp8x == nil && flags != 0 {
+		// Convert the simple format, whose only chunk is the image data, to
+		// the extended format, whose VP8X chunk holds the canvas size and
+		// whether the image has alpha values.
+		const alphaBit = 1 << 4
+		var w, h int
+		vp8x = make([]byte, 10)
+		switch chunks[0].id {
+		case fccVP8:
+			d := vp8.NewDecoder()
+			d.Init(bytes.NewReader(chunks[0].data), len(chunks[0].data))
+			fh, err := d.DecodeFrameHeader()
+			if err != nil {
+				return nil, err
+			}
+			w, h = fh.Width, fh.Height
+		case fccVP8L:
+			c, err := vp8l.DecodeExtendedConfig(bytes.NewReader(chunks[0].data))
+			if err != nil {
+				return nil, err
+			}
+			w, h = c.Width, c.Height
+			if c.HasAlpha {
+				vp8x[0] |= alphaBit
+			}
+		default:
+			return nil, ErrInvalidFormat
+		}
+		putUint24(vp8x[4:], uint32(w-1))
+		putUint24(vp8x[7:], uint32(h-1))
+	}
+
+	buf := &bytes.Buffer{}
+	buf.Write(fccRIFF[:])
+	buf.Write([]byte{0, 0, 0, 0}) // The RIFF chunk length is filled in by writeRIFF.
+	buf.Write(fccWEBP[:])
+	if vp8x != nil {
+		vp8x[0] = vp8x[0]&^(iccProfileBit|exifMetadataBit|xmpMetadataBit) | flags
+		writeChunk(buf, fccVP8X, vp8x)
+	}
+	md.writeICCP(buf)
+	for _, c := range chunks {
+		writeChunk(buf, c.id, c.data)
+	}
+	md.writeEXIFAndXMP(buf)
+	return buf, nil
+}
@@ -0,0 +1,129 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webp
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/image/riff"
+)
+
+// chunkIDs returns the comma-separated IDs of the chunks of the WEBP data.
+func chunkIDs(t *testing.T, data []byte) string {
+	t.Helper()
+	formType, r, err := riff.NewReader(bytes.NewReader(data))
+	if err != nil || formType != fccWEBP {
+		t.Fatalf("riff.NewReader: got %q, %v", formType, err)
+	}
+	var ids []string
+	for {
+		id, _, _, err := r.Next()
+		if err == io.EOF {
+			return strings.Join(ids, ",")
+		} else if err != nil {
+			t.Fatalf("Next: %v", err)
+		}
+		ids = append(ids, string(id[:]))
+	}
+}
+
+func TestReplaceMetadata(t *testing.T) {
+	md := &Metadata{
+		ICCProfile: []byte("profile"),
+		EXIF:       []byte("MM\x00*exif"),
+		XMP:        []byte("<x:xmpmeta/>"),
+	}
+	for _, tc := range []struct {
+		filename string
+		wantIDs  string
+	}{
+		{"blue-purple-pink.lossy", "VP8X,ICCP,VP8 ,EXIF,XMP "},
+		{"yellow_rose.lossy-with-alpha", "VP8X,ICCP,ALPH,VP8 ,EXIF,XMP "},
+		{"tux.lossless", "VP8X,ICCP,VP8L,EXIF,XMP "},
+		{"gopher-doc.8bpp.lossless", "VP8X,ICCP,VP8L,EXIF,XMP "},
+	} {
+		src, err := os.ReadFile("../testdata/" + tc.filename + ".webp")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := Decode(bytes.NewReader(src))
+		if err != nil {
+			t.Fatalf("%s: Decode: %v", tc.filename, err)
+		}
+		wantFeatures, err := DecodeFeatures(bytes.NewReader(src))
+		if err != nil {
+			t.Fatalf("%s: DecodeFeatures: %v", tc.filename, err)
+		}
+
+		// Add the metadata, then replace some of it, then remove it.
+		data := src
+		for i, md := range []*Metadata{md, {XMP: []byte("<y/>")}, nil} {
+			buf := &bytes.Buffer{}
+			if err := ReplaceMetadata(buf, bytes.NewReader(data), md); err != nil {
+				t.Fatalf("%s #%d: ReplaceMetadata: %v", tc.filename, i, err)
+			}
+			data = buf.Bytes()
+			if i == 0 {
+				if got := chunkIDs(t, data); got != tc.wantIDs {
+					t.Errorf("%s #%d: chunks: got %q, want %q", tc.filename, i, got, tc.wantIDs)
+				}
+			}
+
+			got, gotMD, err := DecodeMetadata(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("%s #%d: DecodeMetadata: %v", tc.filename, i, err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("%s #%d: images differ", tc.filename, i)
+			}
+			wantMD := md
+			if wantMD == nil {
+				wantMD = &Metadata{}
+			}
+			if !reflect.DeepEqual(gotMD, wantMD) {
+				t.Errorf("%s #%d: metadata: got %q, want %q", tc.filename, i, *gotMD, *wantMD)
+			}
+
+			f, err := DecodeFeatures(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("%s #%d: DecodeFeatures: %v", tc.filename, i, err)
+			}
+			if f.Width != wantFeatures.Width || f.Height != wantFeatures.Height || f.Alpha != wantFeatures.Alpha ||
+				f.ICCProfile != (wantMD.ICCProfile != nil) || f.EXIF != (wantMD.EXIF != nil) || f.XMP != (wantMD.XMP != nil) {
+				t.Errorf("%s #%d: got features %+v, source has %+v", tc.filename, i, f, wantFeatures)
+			}
+		}
+	}
+}
+
+func TestReplaceMetadataErrors(t *testing.T) {
+	src, err := os.ReadFile("../testdata/blue-purple-pink.lossy.webp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		desc string
+		data string
+	}{
+		{"not RIFF", "not a WEBP image"},
+		{"not WEBP", "RIFF\x04\x00\x00\x00WAVE"},
+		{"no chunks", "RIFF\x04\x00\x00\x00WEBP"},
+		{"truncated", string(src[:len(src)/2])},
+		{"unknown image chunk", riffWEBP("ABCDdata")},
+	} {
+		err := ReplaceMetadata(io.Discard, bytes.NewReader([]byte(tc.data)), &Metadata{XMP: []byte("<x/>")})
+		if err == nil {
+			t.Errorf("%s: got nil error", tc.desc)
+		} else if tc.desc != "not RIFF" && !errors.Is(err, ErrInvalidFormat) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("%s: got %v, want an invalid format error", tc.desc, err)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webp
+
+import (
+	"image"
+	"io"
+
+	"golang.org/x/image/vp8"
+)
+
+// A Partial is a WEBP image that is decoded a part at a time, from the top
+// down, such as for an image grid that shows the top of each image before
+// the rest of it is decoded.
+type Partial struct {
+	// d is the decoder of a lossy image. It is nil for a lossless image,
+	// which is decoded in full.
+	d           *vp8.Decoder
+	alpha       []byte
+	alphaStride int
+	// m is the image, once DecodeFrameRows has allocated it, and rows is the
+	// number of rows of pixels, from the top of m, that are decoded.
+	m    image.Image
+	rows int
+	er   *errReader
+	err  error
+}
+
+// DecodePartial reads a WEBP image from r, and decodes the top mbRows rows of
+// macroblocks, of 16 pixels each, of a lossy image. Continue decodes the rest
+// of the image, and Image returns the part of it that is decoded so far.
+//
+// DecodePartial reads all of the image's compressed data from r, and so it
+// is the CPU time to decode the pixels, rather than the time to read r, that
+// it spreads over the calls to Continue. A lossless image is decoded in full
+// by DecodePartial, as the VP8L format does not code it from the top down.
+// Errors are reported as for Decode, but errors in the data of a lossy
+// image's pixels, such as truncated data, may only be reported by Continue.
+func DecodePartial(r io.Reader, mbRows int) (*Partial, error) {
+	p := &Partial{}
+	m, _, err := decode(r, false, nil, nil, nil, p)
+	if err != nil {
+		return nil, err
+	}
+	if p.d == nil {
+		p.m, p.rows = m, m.Bounds().Dy()
+		return p, nil
+	}
+	if err := p.Continue(mbRows); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Continue decodes the next mbRows rows of macroblocks, of 16 pixels each, of
+// the image. It does nothing if the image is completely decoded. Once it
+// returns an error, later calls return the same error, and Image returns the
+// rows that were decoded before the error.
+func (p *Partial) Continue(mbRows int) error {
+	if p.err != nil || p.Done() {
+		return p.err
+	}
+	m, rows, err := p.d.DecodeFrameRows(mbRows)
+	if err != nil {
+		p.err = p.er.wrap(err)
+		return p.err
+	}
+	if p.m == nil {
+		if p.alpha != nil {
+			p.m = &image.NYCbCrA{
+				YCbCr:   *m,
+				A:       p.alpha,
+				AStride: p.alphaStride,
+			}
+		} else {
+			p.m = m
+		}
+	}
+	p.rows = rows
+	return nil
+}
+
+// Done returns whether the image is completely decoded.
+func (p *Partial) Done() bool {
+	return p.m != nil && p.rows == p.m.Bounds().Dy()
+}
+
+// Image returns the part of the image that is decoded so far, which is the
+// top rows of its pixels. Their values are final, as later calls to Continue
+// only decode the rows below them, into the same pixel buffers. Once Done
+// returns true, Image returns the whole image, as Decode would.
+func (p *Partial) Image() image.Image {
+	if p.Done() {
+		return p.m
+	}
+	b := p.m.Bounds()
+	return p.m.(interface {
+		SubImage(image.Rectangle) image.Image
+	}).SubImage(image.Rect(b.Min.X, b.Min.Y, b.Max.X, b.Min.Y+p.rows))
+}
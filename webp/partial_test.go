@@ -0,0 +1,109 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webp
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecodePartial(t *testing.T) {
+	for _, filename := range []string{
+		"blue-purple-pink-large.no-filter.lossy",
+		"blue-purple-pink-large.normal-filter.lossy",
+		"blue-purple-pink-large.simple-filter.lossy",
+		"video-001.lossy",
+		"yellow_rose.lossy-with-alpha",
+		"tux.lossless",
+	} {
+		data, err := os.ReadFile("../testdata/" + filename + ".webp")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("%s: Decode: %v", filename, err)
+		}
+		wb := want.Bounds()
+
+		for _, mbRows := range []int{1, 2, 100} {
+			p, err := DecodePartial(bytes.NewReader(data), mbRows)
+			if err != nil {
+				t.Fatalf("%s, mbRows=%d: DecodePartial: %v", filename, mbRows, err)
+			}
+			prevRows := -1
+			for calls := 0; ; calls++ {
+				got := p.Image()
+				if reflect.TypeOf(got) != reflect.TypeOf(want) {
+					t.Fatalf("%s, mbRows=%d: got %T, want %T", filename, mbRows, got, want)
+				}
+				b := got.Bounds()
+				if b.Min != wb.Min || b.Dx() != wb.Dx() || b.Dy() <= prevRows {
+					t.Fatalf("%s, mbRows=%d, call #%d: got bounds %v after %d rows", filename, mbRows, calls, b, prevRows)
+				}
+				for y := b.Min.Y; y < b.Max.Y; y++ {
+					for x := b.Min.X; x < b.Max.X; x++ {
+						if g, w := got.At(x, y), want.At(x, y); g != w {
+							t.Fatalf("%s, mbRows=%d, call #%d: at (%d, %d): got %v, want %v", filename, mbRows, calls, x, y, g, w)
+						}
+					}
+				}
+				prevRows = b.Dy()
+				if p.Done() {
+					break
+				}
+				if err := p.Continue(mbRows); err != nil {
+					t.Fatalf("%s, mbRows=%d: Continue: %v", filename, mbRows, err)
+				}
+			}
+			if got := p.Image(); !reflect.DeepEqual(got, want) {
+				t.Errorf("%s, mbRows=%d: the decoded image differs from Decode's", filename, mbRows)
+			}
+			if err := p.Continue(mbRows); err != nil {
+				t.Errorf("%s, mbRows=%d: Continue after Done: %v", filename, mbRows, err)
+			}
+		}
+	}
+}
+
+func TestDecodePartialErrors(t *testing.T) {
+	lossy, err := os.ReadFile("../testdata/video-001.lossy.webp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	vp8Chunk := string(lossy[12:])
+	vp8Chunk = vp8Chunk[:4] + vp8Chunk[8:]
+
+	// Truncated data is an error once the rows that it is missing are
+	// decoded, and later calls return the same error.
+	p, err := DecodePartial(strings.NewReader(riffWEBP(vp8Chunk[:len(vp8Chunk)/2])), 1)
+	if err != nil {
+		t.Fatalf("truncated VP8: DecodePartial: %v", err)
+	}
+	for err == nil && !p.Done() {
+		err = p.Continue(1)
+	}
+	if !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("truncated VP8: got %v, want %v", err, ErrInvalidFormat)
+	}
+	if err2 := p.Continue(1); err2 != err {
+		t.Errorf("truncated VP8: Continue after an error: got %v, want %v", err2, err)
+	}
+
+	// DecodePartial reads all of the compressed data, and so it returns the
+	// errors from reading.
+	errRead := errors.New("read error")
+	if _, err := DecodePartial(&failingReader{r: bytes.NewReader(lossy), n: len(lossy) / 2, err: errRead}, 1); err != errRead {
+		t.Errorf("failing reader: got %v, want %v", err, errRead)
+	}
+	var ue UnsupportedError
+	if _, err := DecodePartial(strings.NewReader(riffWEBP("ANMF"+strings.Repeat("\x00", 16))), 1); !errors.As(err, &ue) {
+		t.Errorf("ANMF chunk: got %v, want an UnsupportedError", err)
+	}
+}
@@ -0,0 +1,215 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package webpgif converts between image/gif's GIF animations and the webp
+// package's Animation, mapping their frames' delays and disposal methods, and
+// their loop counts.
+//
+// The webp package does not decode animated WEBP files, only encode them, so
+// converting a WEBP file to a GIF is not supported: ToGIF converts an
+// Animation that the caller has built.
+//
+// It is separate from the webp package so that programs that only decode WEBP
+// images do not depend on the image/gif package.
+package webpgif // import "golang.org/x/image/webp/webpgif"
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+
+	"golang.org/x/image/webp"
+)
+
+// FromGIF converts the frames, delays, disposal methods and loop count of g to
+// an Animation, to be written by webp.EncodeAll.
+//
+// GIF delays are in hundredths of a second, and WEBP delays in milliseconds.
+// The canvas background is transparent, as most GIF viewers render it,
+// regardless of g's BackgroundIndex.
+//
+// The WEBP format has no equivalent of gif.DisposalPrevious. A frame with that
+// disposal method is instead cleared with webp.DisposalBackground, and the
+// next frame is extended to also cover, and so restore, the canvas that it
+// cleared. This assumes that the frames' colors are either opaque or fully
+// transparent, as they are in GIF images.
+func FromGIF(g *gif.GIF) (*webp.Animation, error) {
+	if len(g.Delay) != len(g.Image) {
+		return nil, errors.New("webpgif: mismatched GIF image and delay lengths")
+	}
+	if g.Disposal != nil && len(g.Disposal) != len(g.Image) {
+		return nil, errors.New("webpgif: mismatched GIF image and disposal lengths")
+	}
+	a := &webp.Animation{
+		Image:    make([]image.Image, len(g.Image)),
+		Delay:    make([]int, len(g.Image)),
+		Disposal: make([]byte, len(g.Image)),
+		Config: image.Config{
+			Width:  g.Config.Width,
+			Height: g.Config.Height,
+		},
+	}
+	switch {
+	case g.LoopCount < 0:
+		a.LoopCount = 1
+	case g.LoopCount > 0:
+		a.LoopCount = g.LoopCount + 1
+		if a.LoopCount > 0xffff {
+			a.LoopCount = 0xffff
+		}
+	}
+
+	// canvas is the canvas as a GIF viewer renders it. It is only needed to
+	// restore the canvas after a frame with gif.DisposalPrevious, in which
+	// case restore is the part of the canvas that the next frame restores.
+	var (
+		canvas  *image.RGBA
+		restore image.Rectangle
+	)
+	for _, d := range g.Disposal {
+		if d == gif.DisposalPrevious {
+			r := image.Rect(0, 0, g.Config.Width, g.Config.Height)
+			for _, m := range g.Image {
+				r = r.Union(image.Rectangle{Max: m.Bounds().Max})
+			}
+			canvas = image.NewRGBA(r)
+			break
+		}
+	}
+
+	for i, m := range g.Image {
+		a.Delay[i] = 10 * g.Delay[i]
+		disposal := byte(0)
+		if g.Disposal != nil {
+			disposal = g.Disposal[i]
+		}
+		switch disposal {
+		case gif.DisposalBackground, gif.DisposalPrevious:
+			a.Disposal[i] = webp.DisposalBackground
+		default:
+			a.Disposal[i] = webp.DisposalNone
+		}
+
+		b := m.Bounds()
+		a.Image[i] = m
+		if !restore.Empty() {
+			r := b.Union(restore)
+			dst := image.NewRGBA(r)
+			draw.Draw(dst, r, canvas, r.Min, draw.Src)
+			draw.Draw(dst, b, m, b.Min, draw.Over)
+			a.Image[i] = dst
+			restore = image.Rectangle{}
+		}
+		if canvas == nil {
+			continue
+		}
+
+		var saved *image.RGBA
+		if disposal == gif.DisposalPrevious {
+			saved = image.NewRGBA(b)
+			draw.Draw(saved, b, canvas, b.Min, draw.Src)
+		}
+		draw.Draw(canvas, b, m, b.Min, draw.Over)
+		switch disposal {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, b, image.Transparent, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			draw.Draw(canvas, b, saved, b.Min, draw.Src)
+			restore = b
+		}
+	}
+	return a, nil
+}
+
+// ToGIF converts the frames, delays, disposal methods and loop count of a to
+// a GIF animation.
+//
+// Each frame is converted to an *image.Paletted with the palette p, without
+// dithering. A nil p means palette.WebSafe. If p has no fully transparent
+// color, color.Transparent is added to it, so p must have fewer than 256
+// colors if any frame has transparent pixels. GIF images have no partial
+// transparency, so pixels whose alpha is less than half are transparent, and
+// the others are opaque.
+//
+// WEBP delays are in milliseconds, and are rounded to the nearest hundredth
+// of a second.
+func ToGIF(a *webp.Animation, p color.Palette) (*gif.GIF, error) {
+	if len(a.Delay) != len(a.Image) {
+		return nil, errors.New("webpgif: mismatched image and delay lengths")
+	}
+	if a.Disposal != nil && len(a.Disposal) != len(a.Image) {
+		return nil, errors.New("webpgif: mismatched image and disposal lengths")
+	}
+	if p == nil {
+		p = palette.WebSafe
+	}
+	transparent := -1
+	for i, c := range p {
+		if _, _, _, alpha := c.RGBA(); alpha == 0 {
+			transparent = i
+			break
+		}
+	}
+	if transparent < 0 && len(p) < 256 {
+		p = append(p[:len(p):len(p)], color.Transparent)
+		transparent = len(p) - 1
+	}
+
+	canvas := image.Rect(0, 0, a.Config.Width, a.Config.Height)
+	if a.Config.Width <= 0 || a.Config.Height <= 0 {
+		canvas = image.Rectangle{}
+		for _, m := range a.Image {
+			canvas = canvas.Union(image.Rectangle{Max: m.Bounds().Max})
+		}
+	}
+	g := &gif.GIF{
+		Image:    make([]*image.Paletted, len(a.Image)),
+		Delay:    make([]int, len(a.Image)),
+		Disposal: make([]byte, len(a.Image)),
+		Config: image.Config{
+			ColorModel: p,
+			Width:      canvas.Dx(),
+			Height:     canvas.Dy(),
+		},
+	}
+	switch {
+	case a.LoopCount == 1:
+		g.LoopCount = -1
+	case a.LoopCount > 1:
+		g.LoopCount = a.LoopCount - 1
+	}
+
+	for i, m := range a.Image {
+		if a.Delay[i] < 0 {
+			return nil, errors.New("webpgif: invalid delay")
+		}
+		g.Delay[i] = (a.Delay[i] + 5) / 10
+		g.Disposal[i] = gif.DisposalNone
+		if a.Disposal != nil && a.Disposal[i] == webp.DisposalBackground {
+			g.Disposal[i] = gif.DisposalBackground
+		}
+
+		b := m.Bounds()
+		dst := image.NewPaletted(b, p)
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				c := color.NRGBAModel.Convert(m.At(x, y)).(color.NRGBA)
+				if c.A < 0x80 {
+					if transparent < 0 {
+						return nil, errors.New("webpgif: palette has no room for a transparent color")
+					}
+					dst.SetColorIndex(x, y, uint8(transparent))
+					continue
+				}
+				c.A = 0xff
+				dst.SetColorIndex(x, y, uint8(p.Index(c)))
+			}
+		}
+		g.Image[i] = dst
+	}
+	return g, nil
+}
@@ -0,0 +1,176 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webpgif
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"reflect"
+	"testing"
+
+	"golang.org/x/image/webp"
+)
+
+func TestFromGIF(t *testing.T) {
+	red := color.RGBA{0xff, 0x00, 0x00, 0xff}
+	blue := color.RGBA{0x00, 0x00, 0xff, 0xff}
+	palette := color.Palette{color.Transparent, red, blue}
+	frame := func(r image.Rectangle, index uint8) *image.Paletted {
+		m := image.NewPaletted(r, palette)
+		for i := range m.Pix {
+			m.Pix[i] = index
+		}
+		return m
+	}
+	g := &gif.GIF{
+		Image: []*image.Paletted{
+			frame(image.Rect(0, 0, 8, 8), 1),
+			frame(image.Rect(2, 2, 6, 6), 2),
+			frame(image.Rect(4, 0, 8, 2), 2),
+		},
+		Delay:     []int{10, 5, 0},
+		Disposal:  []byte{gif.DisposalNone, gif.DisposalPrevious, gif.DisposalBackground},
+		LoopCount: 2,
+		Config:    image.Config{Width: 8, Height: 8},
+	}
+	a, err := FromGIF(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{100, 50, 0}; !reflect.DeepEqual(a.Delay, want) {
+		t.Errorf("Delay: got %v, want %v", a.Delay, want)
+	}
+	if want := []byte{webp.DisposalNone, webp.DisposalBackground, webp.DisposalBackground}; !bytes.Equal(a.Disposal, want) {
+		t.Errorf("Disposal: got %v, want %v", a.Disposal, want)
+	}
+	if a.LoopCount != 3 {
+		t.Errorf("LoopCount: got %d, want 3", a.LoopCount)
+	}
+	if a.Config.Width != 8 || a.Config.Height != 8 {
+		t.Errorf("Config: got %dx%d, want 8x8", a.Config.Width, a.Config.Height)
+	}
+	if a.Image[0] != g.Image[0] || a.Image[1] != g.Image[1] {
+		t.Errorf("the first two frames were not passed through")
+	}
+
+	// The third frame also restores the red that the second one covered,
+	// which its WEBP disposal method clears.
+	m := a.Image[2]
+	if got, want := m.Bounds(), image.Rect(2, 0, 8, 6); got != want {
+		t.Fatalf("third frame: bounds: got %v, want %v", got, want)
+	}
+	for y := 0; y < 6; y++ {
+		for x := 2; x < 8; x++ {
+			want := red
+			if x >= 4 && y < 2 {
+				want = blue
+			}
+			if got := color.RGBAModel.Convert(m.At(x, y)); got != want {
+				t.Errorf("third frame: (%d, %d): got %v, want %v", x, y, got, want)
+			}
+		}
+	}
+
+	if err := webp.EncodeAll(&bytes.Buffer{}, a, nil); err != nil {
+		t.Errorf("EncodeAll: %v", err)
+	}
+
+	for _, tc := range []struct {
+		gifLoopCount, want int
+	}{
+		{0, 0},
+		{-1, 1},
+		{1 << 20, 0xffff},
+	} {
+		a, err := FromGIF(&gif.GIF{LoopCount: tc.gifLoopCount})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a.LoopCount != tc.want {
+			t.Errorf("GIF LoopCount %d: got %d, want %d", tc.gifLoopCount, a.LoopCount, tc.want)
+		}
+	}
+
+	g.Delay = g.Delay[:2]
+	if _, err := FromGIF(g); err == nil {
+		t.Errorf("mismatched lengths: got nil error, want non-nil")
+	}
+}
+
+func TestToGIF(t *testing.T) {
+	red := color.NRGBA{0xff, 0x00, 0x00, 0xff}
+	blue := color.NRGBA{0x00, 0x00, 0xff, 0xff}
+	p := color.Palette{red, blue}
+	m0 := image.NewNRGBA(image.Rect(0, 0, 4, 2))
+	for i := 0; i < 4; i++ {
+		m0.SetNRGBA(i, 0, red)
+		// Half-transparent blue is opaque, and less than that is transparent.
+		m0.SetNRGBA(i, 1, color.NRGBA{0x00, 0x00, 0xff, uint8(0x40 * i)})
+	}
+	m1 := image.NewNRGBA(image.Rect(2, 0, 4, 1))
+	m1.SetNRGBA(2, 0, blue)
+	a := &webp.Animation{
+		Image:     []image.Image{m0, m1},
+		Delay:     []int{104, 5},
+		Disposal:  []byte{webp.DisposalBackground, webp.DisposalNone},
+		LoopCount: 3,
+	}
+	g, err := ToGIF(a, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{10, 1}; !reflect.DeepEqual(g.Delay, want) {
+		t.Errorf("Delay: got %v, want %v", g.Delay, want)
+	}
+	if want := []byte{gif.DisposalBackground, gif.DisposalNone}; !bytes.Equal(g.Disposal, want) {
+		t.Errorf("Disposal: got %v, want %v", g.Disposal, want)
+	}
+	if g.LoopCount != 2 {
+		t.Errorf("LoopCount: got %d, want 2", g.LoopCount)
+	}
+	if g.Config.Width != 4 || g.Config.Height != 2 {
+		t.Errorf("Config: got %dx%d, want 4x2", g.Config.Width, g.Config.Height)
+	}
+	if len(p) != 2 {
+		t.Errorf("the caller's palette was modified")
+	}
+	if got, want := g.Image[0].Pix, []uint8{0, 0, 0, 0, 2, 2, 1, 1}; !bytes.Equal(got, want) {
+		t.Errorf("first frame: got %v, want %v", got, want)
+	}
+	if got, want := g.Image[1].Pix, []uint8{1, 2}; !bytes.Equal(got, want) {
+		t.Errorf("second frame: got %v, want %v", got, want)
+	}
+	if g.Image[1].Rect != m1.Rect {
+		t.Errorf("second frame: got bounds %v, want %v", g.Image[1].Rect, m1.Rect)
+	}
+	if err := gif.EncodeAll(&bytes.Buffer{}, g); err != nil {
+		t.Errorf("gif.EncodeAll: %v", err)
+	}
+
+	// Round-tripping through FromGIF keeps the delays, disposal methods and
+	// loop count.
+	b, err := FromGIF(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(b.Delay, []int{100, 10}) || !bytes.Equal(b.Disposal, a.Disposal) || b.LoopCount != a.LoopCount {
+		t.Errorf("round trip: got %v, %v, %d", b.Delay, b.Disposal, b.LoopCount)
+	}
+
+	// A full palette has no room for a transparent color.
+	full := make(color.Palette, 256)
+	for i := range full {
+		full[i] = color.Gray{uint8(i)}
+	}
+	if _, err := ToGIF(a, full); err == nil {
+		t.Errorf("full palette: got nil error, want non-nil")
+	}
+	a.Image = a.Image[:1]
+	if _, err := ToGIF(a, nil); err == nil {
+		t.Errorf("mismatched lengths: got nil error, want non-nil")
+	}
+}
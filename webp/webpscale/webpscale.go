@@ -0,0 +1,91 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package webpscale decodes WEBP images scaled to a given size, such as for
+// previews and thumbnails.
+//
+// It is separate from the webp package so that programs that only decode WEBP
+// images do not depend on the golang.org/x/image/draw package's scalers.
+package webpscale // import "golang.org/x/image/webp/webpscale"
+
+import (
+	"image"
+	"io"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/webp"
+)
+
+// Options are the parameters for Decode.
+type Options struct {
+	// DecodeOptions are the crop rectangle and decoding limits, as for
+	// webp.DecodeWithOptions. The image is cropped before it is scaled.
+	webp.DecodeOptions
+	// Width and Height, if positive, are the size to scale the decoded, and
+	// possibly cropped, image to. If only one of them is positive, the other
+	// one keeps the image's aspect ratio. If neither is, the image is not
+	// scaled.
+	Width, Height int
+	// Interpolator is the interpolator used for scaling. A nil Interpolator
+	// means draw.BiLinear, which averages all of the source pixels that each
+	// destination pixel covers when scaling down.
+	Interpolator draw.Interpolator
+}
+
+// Decode is like webp.DecodeWithOptions, but it also scales the decoded image
+// as specified by opts, as Scale does. A nil *Options is equivalent to calling
+// webp.Decode.
+func Decode(r io.Reader, opts *Options) (image.Image, error) {
+	if opts == nil {
+		return webp.Decode(r)
+	}
+	m, err := webp.DecodeWithOptions(r, &opts.DecodeOptions)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Width <= 0 && opts.Height <= 0 {
+		return m, nil
+	}
+	b := m.Bounds()
+	w, h := opts.Width, opts.Height
+	if b.Empty() {
+		return m, nil
+	} else if w <= 0 {
+		w = (h*b.Dx() + b.Dy()/2) / b.Dy()
+	} else if h <= 0 {
+		h = (w*b.Dy() + b.Dx()/2) / b.Dx()
+	}
+	if w < 1 {
+		w = 1
+	}
+	if h < 1 {
+		h = 1
+	}
+	q := opts.Interpolator
+	if q == nil {
+		q = draw.BiLinear
+	}
+	return Scale(m, w, h, q), nil
+}
+
+// Scale returns m, an image returned by the webp package's decoding functions
+// and possibly cropped, scaled to a newly allocated w×h image, whose bounds
+// are (0, 0) to (w, h).
+//
+// An opaque lossy image, an *image.YCbCr, is scaled to an *image.YCbCr with
+// the same subsample ratio, without converting it to RGBA. Other images are
+// scaled to an *image.RGBA. Its colors are premultiplied by alpha before they
+// are interpolated, so that the colors of transparent pixels do not bleed into
+// their neighbors.
+func Scale(m image.Image, w, h int, q draw.Interpolator) image.Image {
+	dr := image.Rect(0, 0, w, h)
+	if m, ok := m.(*image.YCbCr); ok {
+		dst := image.NewYCbCr(dr, m.SubsampleRatio)
+		draw.ScaleYCbCr(dst, dr, m, m.Rect, q)
+		return dst
+	}
+	dst := image.NewRGBA(dr)
+	q.Scale(dst, dr, m, m.Bounds(), draw.Src, nil)
+	return dst
+}
@@ -0,0 +1,95 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webpscale
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"os"
+	"reflect"
+	"testing"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/webp"
+)
+
+func TestDecode(t *testing.T) {
+	for _, tc := range []struct {
+		filename string
+		wantType image.Image
+	}{
+		{"blue-purple-pink-large.normal-filter.lossy", &image.YCbCr{}},
+		{"video-001.lossy", &image.YCbCr{}},
+		{"yellow_rose.lossy-with-alpha", &image.RGBA{}},
+		{"tux.lossless", &image.RGBA{}},
+	} {
+		data, err := os.ReadFile("../../testdata/" + tc.filename + ".webp")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := webp.Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("%s: Decode: %v", tc.filename, err)
+		}
+
+		// Scaling keeps the aspect ratio if only the width is given.
+		crop := image.Rect(8, 8, 48, 28)
+		opts := &Options{Width: 10}
+		opts.Crop = crop
+		got, err := Decode(bytes.NewReader(data), opts)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.filename, err)
+		}
+		if reflect.TypeOf(got) != reflect.TypeOf(tc.wantType) {
+			t.Fatalf("%s: got %T, want %T", tc.filename, got, tc.wantType)
+		}
+		if b := image.Rect(0, 0, 10, 5); got.Bounds() != b {
+			t.Fatalf("%s: got bounds %v, want %v", tc.filename, got.Bounds(), b)
+		}
+		sub := want.(interface {
+			SubImage(image.Rectangle) image.Image
+		}).SubImage(crop)
+		if w := Scale(sub, 10, 5, draw.BiLinear); !reflect.DeepEqual(got, w) {
+			t.Errorf("%s: got different pixels from scaling the decoded image", tc.filename)
+		}
+
+		// Without a size, the image is only cropped.
+		got, err = Decode(bytes.NewReader(data), &Options{DecodeOptions: webp.DecodeOptions{Crop: crop}})
+		if err != nil {
+			t.Fatalf("%s: crop only: %v", tc.filename, err)
+		}
+		if got.Bounds() != crop {
+			t.Errorf("%s: crop only: got bounds %v, want %v", tc.filename, got.Bounds(), crop)
+		}
+	}
+}
+
+func TestScaleAlpha(t *testing.T) {
+	// The left half is opaque blue, and the right half is transparent, with
+	// the Y'CbCr values of red. Scaling it down to a single pixel must not
+	// tint it red.
+	m := image.NewNYCbCrA(image.Rect(0, 0, 4, 2), image.YCbCrSubsampleRatio444)
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 4; x++ {
+			c := color.RGBA{0x00, 0x00, 0xff, 0xff}
+			if x >= 2 {
+				c = color.RGBA{0xff, 0x00, 0x00, 0x00}
+			}
+			yy, cb, cr := color.RGBToYCbCr(c.R, c.G, c.B)
+			m.Y[m.YOffset(x, y)] = yy
+			m.Cb[m.COffset(x, y)] = cb
+			m.Cr[m.COffset(x, y)] = cr
+			m.A[m.AOffset(x, y)] = c.A
+		}
+	}
+	for _, q := range []draw.Interpolator{draw.ApproxBiLinear, draw.BiLinear, draw.CatmullRom} {
+		got := Scale(m, 1, 1, q).(*image.RGBA)
+		c := got.RGBAAt(0, 0)
+		if c.R > 2 || c.A < 0x70 || c.A > 0x90 {
+			t.Errorf("%T: got %v, want premultiplied half-transparent blue", q, c)
+		}
+	}
+}